@@ -0,0 +1,131 @@
+// Copyright 2026 the P&AI authors. All rights reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"log/slog"
+	"os"
+	"time"
+
+	"github.com/p-n-ai/pai-bot/internal/agent"
+	"github.com/p-n-ai/pai-bot/internal/ai"
+	"github.com/p-n-ai/pai-bot/internal/curriculum"
+	"github.com/p-n-ai/pai-bot/internal/platform/airouter"
+	"github.com/p-n-ai/pai-bot/internal/platform/cache"
+	"github.com/p-n-ai/pai-bot/internal/platform/config"
+	"github.com/p-n-ai/pai-bot/internal/terminalchat"
+)
+
+func main() {
+	cfg, err := config.Load()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "load config: %v\n", err)
+		os.Exit(1)
+	}
+
+	var (
+		topicID   string
+		since     string
+		limit     int
+		apply     bool
+		aiGrading bool
+		rate      float64
+		maxCost   float64
+		asJSON    bool
+	)
+	flag.StringVar(&topicID, "topic", "", "only regrade results for this topic id")
+	flag.StringVar(&since, "since", "", "only regrade results completed on or after this date (YYYY-MM-DD)")
+	flag.IntVar(&limit, "limit", 0, "regrade at most this many results (0 = all)")
+	flag.BoolVar(&apply, "apply", cfg.QuizRegrade.Apply, "write the new grades and mastery; without it only the diff is reported")
+	flag.BoolVar(&aiGrading, "ai", cfg.QuizRegrade.AIGrading, "ask the grading model about free-text answers the rules reject")
+	flag.Float64Var(&rate, "rate", cfg.QuizRegrade.RatePerSecond, "AI grading calls per second at most (0 = unpaced)")
+	flag.Float64Var(&maxCost, "max-cost", cfg.QuizRegrade.MaxCostUSD, "stop AI grading once it has cost this many USD")
+	flag.BoolVar(&asJSON, "json", false, "print the report as JSON")
+	flag.Parse()
+
+	slog.SetDefault(slog.New(slog.NewTextHandler(os.Stderr, &slog.HandlerOptions{
+		Level: slog.LevelWarn,
+	})))
+
+	opts := agent.QuizRegradeOptions{
+		TopicID:       topicID,
+		Limit:         limit,
+		Apply:         apply,
+		AIGrading:     aiGrading,
+		RatePerSecond: rate,
+		MaxCostUSD:    maxCost,
+	}
+	if since != "" {
+		opts.Since, err = time.ParseInLocation(time.DateOnly, since, time.Local)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "-since: %v\n", err)
+			os.Exit(1)
+		}
+	}
+
+	ctx := context.Background()
+	state, cleanup, err := terminalchat.BuildState(ctx, cfg.Database, terminalchat.StateOptions{}, terminalchat.StateDeps{})
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "open database: %v\n", err)
+		os.Exit(1)
+	}
+	defer cleanup()
+	if state.DB == nil || state.TenantID == "" {
+		fmt.Fprintln(os.Stderr, "persistent postgres state is required for quiz regrade")
+		os.Exit(1)
+	}
+
+	loader, err := curriculum.NewLoader(cfg.CurriculumPath)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "load curriculum %s: %v\n", cfg.CurriculumPath, err)
+		os.Exit(1)
+	}
+
+	var router *ai.Router
+	if aiGrading {
+		if !cfg.HasAIProvider() {
+			fmt.Fprintln(os.Stderr, "-ai needs at least one AI provider configured")
+			os.Exit(1)
+		}
+		router = airouter.Setup(cfg.AI)
+	}
+
+	// Applied mastery changes drop the server's warm standby snapshots for
+	// the learners they touch.
+	var warmCache agent.WarmCache
+	if cfg.Cache.URL != "" {
+		c, err := cache.New(ctx, cfg.Cache.URL)
+		if err != nil {
+			slog.Warn("cache not connected; warm snapshots are left to expire", "error", err)
+		} else {
+			defer func() { _ = c.Close() }()
+			warmCache = c
+		}
+	}
+
+	regrader := agent.NewQuizRegrader(agent.QuizRegraderConfig{
+		Results:          agent.NewPostgresQuizResultHistory(state.DB.Pool, state.TenantID),
+		CurriculumLoader: loader,
+		Tracker:          state.Tracker,
+		AIRouter:         router,
+		WarmCache:        warmCache,
+		TenantID:         state.TenantID,
+	})
+	report, err := regrader.Run(ctx, opts)
+	if asJSON {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		_ = enc.Encode(report)
+	} else {
+		_ = report.WriteText(os.Stdout)
+	}
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "quiz regrade: %v\n", err)
+		os.Exit(1)
+	}
+}
@@ -0,0 +1,112 @@
+// Copyright 2026 the P&AI authors. All rights reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package main
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/p-n-ai/pai-bot/internal/agent"
+	"github.com/p-n-ai/pai-bot/internal/platform/config"
+	"github.com/p-n-ai/pai-bot/internal/platform/jobs"
+)
+
+// scheduledJob is a background job main registers when config enables it.
+// A nil run means its dependency is not configured, e.g. conversation
+// archiving with LEARN_CONVERSATION_ARCHIVE_DAYS unset.
+type scheduledJob struct {
+	name      string
+	cfg       config.JobConfig
+	singleton bool
+	jitter    time.Duration
+	timeout   time.Duration
+	run       func(ctx context.Context) error
+}
+
+func registerJobs(scheduler *jobs.Scheduler, scheduled []scheduledJob) error {
+	for _, job := range scheduled {
+		if !job.cfg.Enabled || job.run == nil {
+			continue
+		}
+		schedule, err := jobs.ParseSchedule(job.cfg.Schedule)
+		if err != nil {
+			return fmt.Errorf("job %s: %w", job.name, err)
+		}
+		if err := scheduler.Register(jobs.Job{
+			Name:      job.name,
+			Schedule:  schedule,
+			Jitter:    job.jitter,
+			Singleton: job.singleton,
+			Timeout:   job.timeout,
+			Run:       job.run,
+		}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// regradeQuizzes returns the quiz regrade job. It logs a summary of each
+// run; cmd/quiz-regrade prints the full diff.
+func regradeQuizzes(regrader *agent.QuizRegrader, cfg config.QuizRegradeConfig) func(ctx context.Context) error {
+	return func(ctx context.Context) error {
+		opts := agent.QuizRegradeOptions{
+			Apply:         cfg.Apply,
+			AIGrading:     cfg.AIGrading,
+			RatePerSecond: cfg.RatePerSecond,
+			MaxCostUSD:    cfg.MaxCostUSD,
+		}
+		if cfg.SinceDays > 0 {
+			opts.Since = time.Now().AddDate(0, 0, -cfg.SinceDays)
+		}
+		report, err := regrader.Run(ctx, opts)
+		slog.Info("quiz regrade finished",
+			"applied", report.Applied,
+			"scanned", report.Scanned,
+			"changed", report.Changed,
+			"mastery_changed", len(report.Mastery),
+			"skipped_questions", report.SkippedQuestions,
+			"ai_calls", report.AICalls,
+			"cost_usd", report.CostUSD,
+			"cost_cap_reached", report.CostCapReached,
+		)
+		return err
+	}
+}
+
+// scoreSessionSentiment returns the session sentiment job. Each run
+// rescores sessions with learner messages in the lookback window.
+func scoreSessionSentiment(analyzer *agent.SessionSentimentAnalyzer, cfg config.SentimentConfig) func(ctx context.Context) error {
+	return func(ctx context.Context) error {
+		lookback := time.Duration(cfg.LookbackHours) * time.Hour
+		if lookback <= 0 {
+			lookback = 24 * time.Hour
+		}
+		report, err := analyzer.Run(ctx, agent.SessionSentimentOptions{
+			Since:                time.Now().Add(-lookback),
+			FrustrationThreshold: cfg.FrustrationThreshold,
+			CheckIn:              cfg.CheckIn,
+			TeacherAlert:         cfg.TeacherAlert,
+		})
+		slog.Info("session sentiment scored",
+			"scored", report.Scored,
+			"spikes", report.Spikes,
+			"check_ins", report.CheckIns,
+			"teacher_alerts", report.TeacherAlerts,
+		)
+		return err
+	}
+}
+
+// malaysiaTime is the zone job schedules are written in, as for the agent's
+// own timers.
+func malaysiaTime() *time.Location {
+	loc, err := time.LoadLocation("Asia/Kuala_Lumpur")
+	if err != nil {
+		return time.FixedZone("MYT", 8*60*60)
+	}
+	return loc
+}
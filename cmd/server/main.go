@@ -15,19 +15,26 @@ import (
 	"syscall"
 	"time"
 
+	"github.com/nats-io/nats.go"
+
 	"github.com/p-n-ai/pai-bot/internal/adminapi"
 	"github.com/p-n-ai/pai-bot/internal/agent"
 	"github.com/p-n-ai/pai-bot/internal/auth"
 	"github.com/p-n-ai/pai-bot/internal/chat"
 	"github.com/p-n-ai/pai-bot/internal/curriculum"
+	"github.com/p-n-ai/pai-bot/internal/enginebus"
 	"github.com/p-n-ai/pai-bot/internal/focusedpage"
 	"github.com/p-n-ai/pai-bot/internal/focusedpagedelivery"
+	"github.com/p-n-ai/pai-bot/internal/i18n"
 	"github.com/p-n-ai/pai-bot/internal/platform/airouter"
 	"github.com/p-n-ai/pai-bot/internal/platform/cache"
 	"github.com/p-n-ai/pai-bot/internal/platform/config"
 	"github.com/p-n-ai/pai-bot/internal/platform/database"
 	"github.com/p-n-ai/pai-bot/internal/platform/featureflags"
+	"github.com/p-n-ai/pai-bot/internal/platform/jobs"
 	"github.com/p-n-ai/pai-bot/internal/platform/mailer"
+	"github.com/p-n-ai/pai-bot/internal/platform/msgcrypt"
+	"github.com/p-n-ai/pai-bot/internal/platform/secrets"
 	"github.com/p-n-ai/pai-bot/internal/platform/settings"
 	platformtenant "github.com/p-n-ai/pai-bot/internal/platform/tenant"
 	"github.com/p-n-ai/pai-bot/internal/progress"
@@ -48,6 +55,20 @@ func main() {
 
 	slog.SetDefault(slog.New(newLogHandler(cfg.Log)))
 
+	// Provider keys and the auth secret may come from Vault or secret files
+	// instead of the environment; they are reloaded on SIGHUP.
+	secretStore := secrets.FromConfig(cfg.Secrets)
+	bootSecrets, _, err := secretStore.Reload(context.Background())
+	if err != nil {
+		slog.Error("failed to load secrets", "error", err)
+		os.Exit(1)
+	}
+	bootSecrets.ApplyAI(&cfg.AI)
+	if secret, ok := bootSecrets[secrets.AuthSecret]; ok {
+		cfg.Auth.JWTSecret = secret
+	}
+	jwtSecret := auth.NewSigningSecret(cfg.Auth.JWTSecret)
+
 	if err := cfg.Validate(); err != nil {
 		slog.Error("invalid config", "error", err)
 		os.Exit(1)
@@ -72,7 +93,10 @@ func main() {
 		BuildHandler: func(ctx context.Context) (http.Handler, func(context.Context) error, error) {
 
 			// Initialize PostgreSQL-backed conversation store.
-			db, err := database.New(context.Background(), cfg.Database.URL, cfg.Database.MaxConns, cfg.Database.MinConns)
+			db, err := database.New(context.Background(), cfg.Database.URL, cfg.Database.MaxConns, cfg.Database.MinConns,
+				database.WithStatementCache(cfg.Database.StatementCache),
+				database.WithPreparedStatements(agent.HotStatements()...),
+			)
 			if err != nil {
 				slog.Error("failed to connect to database", "error", err)
 				os.Exit(1)
@@ -95,6 +119,10 @@ func main() {
 
 			// Initialize AI router with configured providers.
 			lastApplied := settings.MergeAI(cfg.AI, settingsStore.Current())
+			if err := airouter.Validate(lastApplied); err != nil {
+				slog.Error("invalid AI task model config", "error", err)
+				os.Exit(1)
+			}
 			router := airouter.Setup(lastApplied)
 			if !router.HasProvider() {
 				if cfg.Runtime.DevMode {
@@ -104,8 +132,10 @@ func main() {
 					os.Exit(1)
 				}
 			}
-			applySettings := func(st settings.Settings) {
-				// Applies run in commit order under the store's update lock, so a plain lastApplied variable is safe.
+			// Admin saves and secret reloads both re-apply the router; aiMu keeps
+			// cfg.AI and lastApplied consistent between them.
+			var aiMu sync.Mutex
+			reapplyAI := func(st settings.Settings) {
 				merged := settings.MergeAI(cfg.AI, st)
 				if merged == lastApplied {
 					return
@@ -113,6 +143,20 @@ func main() {
 				lastApplied = merged
 				airouter.Apply(router, merged)
 			}
+			applySettings := func(st settings.Settings) {
+				aiMu.Lock()
+				defer aiMu.Unlock()
+				reapplyAI(st)
+			}
+			applySecrets := func(v secrets.Values) {
+				if secret, ok := v[secrets.AuthSecret]; ok {
+					jwtSecret.Rotate(secret)
+				}
+				aiMu.Lock()
+				defer aiMu.Unlock()
+				v.ApplyAI(&cfg.AI)
+				reapplyAI(settingsStore.Current())
+			}
 
 			var warnFlagOverrides sync.Once
 			flagsProvider := func() featureflags.Features {
@@ -128,13 +172,23 @@ func main() {
 			}
 
 			// Initialize cache (warn if unavailable, don't fail).
+			var warmCache agent.WarmCache
+			// Singleton jobs lock through the cache; without one they run
+			// unguarded, which is only safe on a single replica.
+			var jobLocker jobs.Locker
 			if cfg.Cache.URL != "" {
 				c, err := cache.New(context.Background(), cfg.Cache.URL)
 				if err != nil {
 					slog.Warn("cache not connected", "error", err)
 				} else {
 					cleanup = append(cleanup, func() { _ = c.Close() })
+					warmCache = c
+					jobLocker = c
 					slog.Info("cache connected")
+					if seconds := cfg.AI.ResponseCacheSeconds; seconds > 0 {
+						router.SetResponseCache(c, time.Duration(seconds)*time.Second)
+						slog.Info("AI response cache enabled", "ttl_seconds", seconds)
+					}
 				}
 			} else {
 				slog.Warn("cache not configured, running without cache")
@@ -145,11 +199,34 @@ func main() {
 				slog.Error("failed to initialize conversation store", "error", err)
 				os.Exit(1)
 			}
+			messageKeys, err := msgcrypt.Parse(cfg.Database.MessageKeys, cfg.Database.MessageActiveKey)
+			if err != nil {
+				slog.Error("invalid LEARN_MESSAGE_ENCRYPTION_KEYS", "error", err)
+				os.Exit(1)
+			}
+			if messageKeys != nil {
+				store.SetMessageKeyring(messageKeys)
+				slog.Info("message encryption at rest enabled", "active_key", messageKeys.ActiveKeyID())
+			}
 			focusedPageStore := focusedpage.NewPostgresStore(db.Pool)
 			focusedPageCleanup, err := server.NewFocusedPageCleanupWorker(focusedPageStore, nil)
 			if err != nil {
 				return nil, nil, fmt.Errorf("initialize focused page cleanup: %w", err)
 			}
+			// Token budgets are checked from memory and flushed to Postgres
+			// in the background; a failed load leaves turns unlimited until
+			// the next sync.
+			tokenBudget := agent.NewCachedTokenBudget(agent.NewPostgresTokenBudgetStore(db.Pool))
+			if err := tokenBudget.Load(context.Background()); err != nil {
+				slog.Warn("token budgets unavailable at startup", "error", err)
+			}
+			var conversationArchive *server.ConversationArchiveWorker
+			if days := cfg.Runtime.ConversationArchiveDays; days > 0 {
+				conversationArchive, err = server.NewConversationArchiveWorker(store, time.Duration(days)*24*time.Hour, nil)
+				if err != nil {
+					return nil, nil, fmt.Errorf("initialize conversation archive: %w", err)
+				}
+			}
 			var focusedPageService *focusedpage.Service
 			var focusedPageHandler http.Handler
 			if strings.TrimSpace(cfg.FocusedPage.BaseURL) != "" {
@@ -175,6 +252,24 @@ func main() {
 				slog.Info("curriculum ready", "topics", len(topics))
 			}
 			retrievalService := server.NewBootstrapRetrievalService(loader)
+			var teachingNotes server.TeachingNoteCurriculum
+			if loader != nil {
+				saved, err := adminapi.New(db.Pool, store.TenantID()).ListTeachingNotes()
+				if err != nil {
+					slog.Warn("school teaching notes not loaded", "error", err)
+				}
+				teachingNotes = server.NewLoaderTeachingNotes(loader, store.TenantID(), saved)
+			}
+			messageTemplates := i18n.NewOverrides()
+			savedTemplates, err := adminapi.New(db.Pool, store.TenantID()).ListMessageTemplates()
+			if err != nil {
+				slog.Warn("message templates not loaded", "error", err)
+			}
+			templateOverrides := server.NewTenantMessageTemplates(messageTemplates, store.TenantID(), savedTemplates.Templates)
+			tenantSettings, err := adminapi.New(db.Pool, store.TenantID()).GetTenantSettings()
+			if err != nil {
+				slog.Warn("tenant settings not loaded", "error", err)
+			}
 
 			// Create agent engine with streaks and XP tracking.
 			eventLogger := agent.NewPostgresEventLogger(db.Pool)
@@ -182,8 +277,58 @@ func main() {
 			streakTracker := progress.NewMemoryStreakTracker()
 			xpTracker := progress.NewMemoryXPTracker()
 			goalStore := agent.NewPostgresGoalStore(db.Pool, store.TenantID())
+			badgeStore := agent.NewPostgresBadgeStore(db.Pool, store.TenantID())
+			referralStore := agent.NewPostgresReferralStore(db.Pool, store.TenantID())
 			challengeStore := agent.NewPostgresChallengeStore(db.Pool, store.TenantID())
 			groupStore := agent.NewPostgresGroupStore(db.Pool)
+			feedbackStore := agent.NewPostgresFeedbackStore(db.Pool, store.TenantID())
+			studyPlanStore := agent.NewPostgresStudyPlanStore(db.Pool, store.TenantID())
+			learnerMemoryStore := agent.NewPostgresLearnerMemoryStore(db.Pool, store.TenantID())
+			var subscriptionStore agent.SubscriptionStore
+			if cfg.Subscription.Enabled {
+				subscriptionStore = agent.NewPostgresSubscriptionStore(db.Pool, store.TenantID())
+			}
+			examCalendar, err := agent.ParseExamCalendar(cfg.Runtime.ExamCalendar)
+			if err != nil {
+				slog.Error("invalid LEARN_EXAM_CALENDAR", "error", err)
+				os.Exit(1)
+			}
+			replyLimits, err := agent.ParseReplyLengthLimits(cfg.Runtime.ReplyLengthLimits)
+			if err != nil {
+				slog.Error("invalid LEARN_REPLY_LENGTH_LIMITS", "error", err)
+				os.Exit(1)
+			}
+			latencySLOs, err := agent.ParseLatencySLOs(cfg.Runtime.LatencySLOs)
+			if err != nil {
+				slog.Error("invalid LEARN_LATENCY_SLOS", "error", err)
+				os.Exit(1)
+			}
+			var latencySLO *agent.LatencySLOMonitor
+			if len(latencySLOs) > 0 {
+				latencySLO = agent.NewLatencySLOMonitor(agent.LatencySLOMonitorConfig{
+					SLOs:          latencySLOs,
+					BurnRateAlert: cfg.Runtime.SLOBurnRateAlert,
+					Window:        time.Duration(cfg.Runtime.SLOWindowMinutes) * time.Minute,
+				})
+				if webhookURL := cfg.Runtime.SLOAlertWebhookURL; webhookURL != "" {
+					latencySLO.AddHook(agent.WebhookSLOAlertHook{URL: webhookURL, Client: &http.Client{Timeout: 10 * time.Second}})
+				}
+			}
+			notation, err := agent.ParseNotationConfig(cfg.Tenant.NotationRules)
+			if err != nil {
+				slog.Error("invalid LEARN_NOTATION_RULES", "error", err)
+				os.Exit(1)
+			}
+			contentFilterCfg, err := agent.LoadContentFilterConfig(cfg.Tenant.ContentFilterPolicy, cfg.Tenant.ContentFilterFile)
+			if err != nil {
+				slog.Error("invalid content filter config", "error", err)
+				os.Exit(1)
+			}
+			contentFilter, err := agent.NewContentFilter(contentFilterCfg)
+			if err != nil {
+				slog.Error("invalid content filter config", "error", err)
+				os.Exit(1)
+			}
 			engine := agent.NewEngine(agent.EngineConfig{
 				AIRouter:             router,
 				Store:                store,
@@ -195,18 +340,74 @@ func main() {
 				Streaks:              streakTracker,
 				XP:                   xpTracker,
 				Goals:                goalStore,
+				Badges:               badgeStore,
+				Referrals:            referralStore,
+				TelegramBotUsername:  cfg.Telegram.BotUsername,
 				Challenges:           challengeStore,
 				Groups:               groupStore,
 				TenantID:             store.TenantID(),
 				DevMode:              cfg.Runtime.DevMode,
 				FeatureFlags:         flagsProvider,
 				FocusedPages:         focusedPageService,
+				EditReanswerWindow:   time.Duration(cfg.Runtime.EditReanswerWindowSeconds) * time.Second,
+				Feedback:             feedbackStore,
+				FeedbackOperatorChat: cfg.Tenant.FeedbackOperatorChatID,
+				StudyPlans:           studyPlanStore,
+				ExamCalendar:         examCalendar,
+				ReplyLimits:          replyLimits,
+				Notation:             notation,
+				LearnerMemory:        learnerMemoryStore,
+				Access:               agent.NewPostgresAccessStore(db.Pool, store.TenantID()),
+				AccessGate: agent.AccessGateConfig{
+					Channels:    cfg.Tenant.AccessGateChannels,
+					InviteCodes: cfg.Tenant.AccessInviteCodes,
+					Operators:   cfg.Tenant.AccessOperatorIDs,
+				},
+				CannedAnswers:    agent.NewPostgresCannedAnswerStore(db.Pool, store.TenantID()),
+				ContentFilter:    contentFilter,
+				WarmCache:        warmCache,
+				MessageTemplates: messageTemplates,
+				Subscriptions:    subscriptionStore,
+				SubscriptionPlans: agent.SubscriptionPlans{
+					FreeDailyTokens:    cfg.Subscription.FreeDailyTokens,
+					PremiumDailyTokens: cfg.Subscription.PremiumDailyTokens,
+					CheckoutURL:        cfg.Subscription.CheckoutURL,
+					ManageURL:          cfg.Subscription.ManageURL,
+				},
+				Budget:              tokenBudget,
+				DailyProblems:       agent.NewPostgresDailyProblemStore(db.Pool, store.TenantID()),
+				LatencySLO:          latencySLO,
+				SLOAlertChat:        cfg.Runtime.SLOAlertChatID,
+				Reviews:             agent.NewPostgresReviewQueue(db.Pool, store.TenantID()),
+				QuizResults:         agent.NewPostgresQuizResultStore(db.Pool),
+				ReviewSamplePercent: cfg.Runtime.ReviewSamplePercent,
+				MetaFooter:          tenantSettings.MetaFooter,
 				FocusedPageEnabled: func(msg chat.InboundMessage) bool {
 					return focusedPageChannelEnabled(cfg.Runtime.DevMode, msg)
 				},
 			})
+			if err := engine.SetTenantNotation(store.TenantID(), tenantSettings.NotationRules); err != nil {
+				slog.Warn("invalid tenant notation rules; using LEARN_NOTATION_RULES", "error", err)
+			}
+
+			var turnResponder *enginebus.Responder
+			var natsConn *nats.Conn
+			if cfg.NATS.URL != "" {
+				natsConn, err = nats.Connect(cfg.NATS.URL, nats.Name("pai-bot"), nats.MaxReconnects(-1))
+				if err != nil {
+					slog.Warn("NATS not connected; engine turns are not served over NATS", "error", err)
+				} else {
+					cleanup = append(cleanup, natsConn.Close)
+					turnResponder, err = enginebus.NewResponder(engine, store.TenantID(), time.Duration(cfg.NATS.TurnTimeoutSeconds)*time.Second)
+					if err != nil {
+						return nil, nil, fmt.Errorf("initialize engine turn responder: %w", err)
+					}
+				}
+			}
 
 			gw := chat.NewGateway()
+			gw.SetDeliveryRecorder(store)
+			gw.SetDelayQueue(store)
 			if strings.TrimSpace(cfg.Telegram.BotToken) != "" {
 				tg, err := chat.NewTelegramChannel(cfg.Telegram.BotToken)
 				if err != nil {
@@ -253,7 +454,7 @@ func main() {
 			// WebSocket channel (always enabled — used by terminal-chat and embed web clients).
 			// Dev mode keeps first-message auth for terminal-chat; production embed mode
 			// requires origin checking and subprotocol JWT auth.
-			embedTokenManager := auth.NewTokenManager(cfg.Auth.JWTSecret, time.Hour)
+			embedTokenManager := auth.NewRotatingTokenManager(jwtSecret, time.Hour)
 			var wsChannel *chat.WSChannel
 			if cfg.Runtime.DevMode {
 				wsChannel = chat.NewWSChannel()
@@ -276,6 +477,7 @@ func main() {
 				}
 			}
 			engine.SetTurnDeliverer(server.NewGatewayTurnDeliverer(gw, store, focusedPageDeliveries))
+			engine.SetScheduledSender(gw)
 
 			// Start proactive scheduler (nudges for due reviews).
 			nudgeTracker := agent.NewPostgresNudgeTracker(db.Pool, store.TenantID())
@@ -297,17 +499,82 @@ func main() {
 			scheduler.SetWeeklyParentReportSource(server.NewWeeklyParentReportSource(adminapi.New(db.Pool, store.TenantID())))
 
 			scheduler.SetGroupStore(groupStore, store.TenantID())
+			scheduler.SetStudyPlans(studyPlanStore)
+			scheduler.SetRevisionMode(examCalendar, flagsProvider)
+			scheduler.SetContentFilter(contentFilter)
+			scheduler.SetMessageTemplates(messageTemplates)
+			scheduler.SetUserLifecycle(store)
+			scheduler.SetDailyProblems(engine)
+			scheduler.SetPresence(wsChannel)
+
+			budgetSyncSchedule := cfg.Jobs.TokenBudgetSync
+			if budgetSyncSchedule.Schedule == "" {
+				interval := time.Duration(cfg.AI.BudgetSyncSeconds) * time.Second
+				if interval <= 0 {
+					interval = agent.DefaultTokenBudgetSyncInterval
+				}
+				budgetSyncSchedule.Schedule = "@every " + interval.String()
+			}
+			var archiveConversations, refreshCurriculum func(context.Context) error
+			if conversationArchive != nil {
+				archiveConversations = conversationArchive.RunOnce
+			}
+			if loader != nil {
+				refreshCurriculum = func(context.Context) error {
+					changed, err := loader.Reload()
+					if changed {
+						slog.Info("curriculum reloaded", "version", loader.Version())
+					}
+					return err
+				}
+			}
+			quizRegrader := agent.NewQuizRegrader(agent.QuizRegraderConfig{
+				Results:          agent.NewPostgresQuizResultHistory(db.Pool, store.TenantID()),
+				CurriculumLoader: loader,
+				Tracker:          tracker,
+				AIRouter:         router,
+				WarmCache:        warmCache,
+				TenantID:         store.TenantID(),
+			})
+			sentimentAnalyzer := agent.NewSessionSentimentAnalyzer(agent.SessionSentimentConfig{
+				Store:            store,
+				Notifier:         server.NewGatewayNotifier(gw, store),
+				Groups:           groupStore,
+				Events:           eventLogger,
+				CurriculumLoader: loader,
+			})
+			jobScheduler := jobs.New(jobLocker, malaysiaTime(), nil)
+			if err := registerJobs(jobScheduler, []scheduledJob{
+				{name: "focused-page-cleanup", cfg: cfg.Jobs.FocusedPageCleanup, singleton: true, jitter: time.Minute, run: focusedPageCleanup.RunOnce},
+				{name: "conversation-archive", cfg: cfg.Jobs.ConversationArchive, singleton: true, jitter: 5 * time.Minute, run: archiveConversations},
+				{name: "token-budget-sync", cfg: budgetSyncSchedule, timeout: time.Minute, run: tokenBudget.Sync},
+				{name: "weekly-parent-reports", cfg: cfg.Jobs.WeeklyParentReports, singleton: true, timeout: time.Hour, run: func(ctx context.Context) error {
+					scheduler.SendWeeklyParentReports(ctx, time.Now())
+					return nil
+				}},
+				{name: "curriculum-refresh", cfg: cfg.Jobs.CurriculumRefresh, jitter: time.Minute, run: refreshCurriculum},
+				{name: "ai-model-refresh", cfg: cfg.Jobs.AIModelRefresh, jitter: 5 * time.Minute, timeout: time.Minute, run: router.RefreshModels},
+				{name: "quiz-regrade", cfg: cfg.Jobs.QuizRegrade, singleton: true, jitter: 5 * time.Minute, timeout: time.Hour, run: regradeQuizzes(quizRegrader, cfg.QuizRegrade)},
+				{name: "session-sentiment", cfg: cfg.Jobs.SessionSentiment, singleton: true, jitter: time.Minute, timeout: 10 * time.Minute, run: scoreSessionSentiment(sentimentAnalyzer, cfg.Sentiment)},
+			}); err != nil {
+				return nil, nil, fmt.Errorf("register background jobs: %w", err)
+			}
+			slog.Info("background jobs registered", "jobs", jobScheduler.Jobs())
 
 			// Scheduler runs in background; user list is empty initially — will be populated
 			// when we add user enumeration from the database.
 			go scheduler.Start(ctx, []string{})
+			go gw.RunDelayQueue(ctx, time.Minute)
+			go engine.RunWarmStandby(ctx)
 
 			// Start long-polling with message handler.
 			// Shared inbound message handler for all channels.
-			handleInbound := func(msg chat.InboundMessage) {
-				// Show typing indicator while processing.
-				if err := gw.SendTyping(ctx, msg.Channel, msg.UserID); err != nil {
-					slog.Warn("failed to send typing indicator", "error", err)
+			processInbound := func(msg chat.InboundMessage) {
+				// Show typing indicator while processing. Edits, deletes, and reactions are silent.
+				if msg.ExpectsReply() {
+					if err := gw.SendTyping(ctx, msg.Channel, msg.UserID); err != nil {
+						slog.Warn("failed to send typing indicator", "error", err)
+					}
 				}
 
 				_, err := engine.ProcessAndDeliver(ctx, msg)
@@ -315,6 +582,45 @@ func main() {
 					slog.Error("process or deliver turn failed", "error", err, "user_id", msg.UserID)
 				}
 			}
+			handleInbound := processInbound
+			var inboundPool *chat.InboundPool
+			var inboundSharder *enginebus.InboundSharder
+			if natsConn != nil && len(cfg.NATS.ShardChannels) > 0 {
+				// Pool workers only route sharded messages, so the sharder's
+				// per-user queues get the same cap and shed the same way;
+				// forwarded messages skip the pool and are bounded there.
+				inboundSharder, err = enginebus.NewInboundSharder(natsConn, enginebus.ShardConfig{
+					TenantID:   store.TenantID(),
+					Channels:   cfg.NATS.ShardChannels,
+					MaxPending: cfg.Runtime.InboundQueueSize,
+					Shed:       func(msg chat.InboundMessage) { inboundPool.Shed(msg) },
+				}, processInbound)
+				if err != nil {
+					return nil, nil, fmt.Errorf("initialize inbound sharding: %w", err)
+				}
+				handleInbound = inboundSharder.Handle
+			} else if len(cfg.NATS.ShardChannels) > 0 {
+				slog.Warn("inbound sharding disabled; LEARN_SHARD_CHANNELS needs a NATS connection")
+			}
+
+			// Every channel feeds a bounded worker pool; bursts beyond its
+			// queue are shed with an apology instead of piling up goroutines.
+			inboundPool, err = chat.NewInboundPool(chat.InboundPoolConfig{
+				Workers:   cfg.Runtime.InboundWorkers,
+				QueueSize: cfg.Runtime.InboundQueueSize,
+				ShedNotice: func(msg chat.InboundMessage) {
+					if err := gw.Send(ctx, chat.OutboundMessage{
+						Channel: msg.Channel,
+						UserID:  msg.UserID,
+						Text:    i18n.S(msg.Language, i18n.MsgServerBusy),
+					}); err != nil {
+						slog.Warn("failed to send busy notice", "channel", msg.Channel, "user_id", msg.UserID, "error", err)
+					}
+				},
+			}, handleInbound)
+			if err != nil {
+				return nil, nil, fmt.Errorf("initialize inbound worker pool: %w", err)
+			}
 
 			authService := auth.NewPostgresService(
 				db.Pool,
@@ -359,44 +665,110 @@ func main() {
 			apiHandler := server.NewHandlerWithAdminProvider(
 				server.NewTenantAdminDataSourceProvider(
 					func(tenantID string) server.AdminDataSource {
-						return adminapi.New(db.Pool, tenantID)
+						return adminapi.New(db.Pool, tenantID).WithMessageKeyring(messageKeys).
+							OnLearnerStateChanged(func(ctx context.Context, tenantID, studentID string) {
+								agent.ForgetWarmSnapshot(ctx, warmCache, tenantID, studentID)
+							})
 					},
 					func() server.AdminDataSource {
-						return adminapi.NewPlatform(db.Pool)
+						return adminapi.NewPlatform(db.Pool).WithMessageKeyring(messageKeys)
 					},
 					func(ctx context.Context) (string, error) {
 						return platformtenant.DefaultTenantID(ctx, db.Pool)
 					},
 				),
-				adminapi.NewPublic(db.Pool),
+				adminapi.NewPublic(db.Pool).WithMessageKeyring(messageKeys),
 				server.NewGatewaySender(gw),
 				retrievalService,
 				authService,
-				cfg.Auth.JWTSecret,
+				jwtSecret,
 				defaultAccessTokenTTL,
 				cfg.Email.BaseURL,
 				settingsStore,
 				applySettings,
 				cfg.Tenant.Mode == "multi",
+				engine,
+				teachingNotes,
+				templateOverrides,
+			)
+
+			var telegramWebAppHandler http.Handler
+			if strings.TrimSpace(cfg.Telegram.WebAppURL) != "" {
+				webAppHandler, err := server.NewTelegramWebAppHandler(
+					engine,
+					cfg.Telegram.BotToken,
+					cfg.Telegram.WebAppURL,
+					time.Duration(cfg.Telegram.WebAppInitDataMaxAgeSeconds)*time.Second,
+				)
+				if err != nil {
+					return nil, nil, fmt.Errorf("initialize telegram web app handler: %w", err)
+				}
+				telegramWebAppHandler = webAppHandler
+			}
+			var stripeWebhookHandler http.Handler
+			if subscriptionStore != nil && cfg.Subscription.StripeWebhookSecret != "" {
+				stripeHandler, err := server.NewStripeWebhookHandler(subscriptionStore, cfg.Subscription.StripeWebhookSecret)
+				if err != nil {
+					return nil, nil, fmt.Errorf("initialize stripe webhook handler: %w", err)
+				}
+				stripeWebhookHandler = stripeHandler
+			}
+			publicUsageHandler, err := server.NewPublicUsageHandler(
+				adminapi.NewPublic(db.Pool),
+				func(tenantID string) server.UsageStatsSource {
+					return adminapi.New(db.Pool, tenantID)
+				},
 			)
+			if err != nil {
+				return nil, nil, fmt.Errorf("initialize public usage handler: %w", err)
+			}
 
 			topMux := server.NewTopMux(server.TopMuxOptions{
-				APIHandler:         apiHandler,
-				WSChannel:          wsChannel,
-				EmbedConfigStore:   embedConfigStore,
-				WACloudChannel:     waCloudChannel,
-				WAMeowChannel:      waMeowChannel,
-				InboundHandler:     handleInbound,
-				AuthService:        authService,
-				JWTSecret:          cfg.Auth.JWTSecret,
-				AccessTokenTTL:     defaultAccessTokenTTL,
-				FocusedPageHandler: focusedPageHandler,
+				APIHandler:            apiHandler,
+				WSChannel:             wsChannel,
+				EmbedConfigStore:      embedConfigStore,
+				WACloudChannel:        waCloudChannel,
+				WAMeowChannel:         waMeowChannel,
+				InboundHandler:        gw.TrackInbound(inboundPool.Handle),
+				AuthService:           authService,
+				JWTSecret:             jwtSecret,
+				AccessTokenTTL:        defaultAccessTokenTTL,
+				FocusedPageHandler:    focusedPageHandler,
+				InboundPool:           inboundPool,
+				TelegramWebAppHandler: telegramWebAppHandler,
+				StripeWebhookHandler:  stripeWebhookHandler,
+				PublicUsageHandler:    publicUsageHandler,
+				LatencySLO:            latencySLO,
+				AIRouter:              router,
+				Gateway:               gw,
 			})
 
 			return http.Handler(topMux), func(ctx context.Context) error {
-				if err := gw.StartAll(ctx, handleInbound); err != nil {
+				if inboundSharder != nil {
+					if err := inboundSharder.Start(ctx); err != nil {
+						return err
+					}
+					cleanup = append(cleanup, inboundSharder.Close)
+				}
+				inboundPool.Start()
+				cleanup = append(cleanup, inboundPool.Close)
+				if err := gw.StartAll(ctx, inboundPool.Handle); err != nil {
 					return err
 				}
+				channelSupervisorDone := make(chan struct{})
+				go func() {
+					defer close(channelSupervisorDone)
+					gw.SuperviseChannels(ctx, 30*time.Second, chat.DefaultChannelRestartThreshold)
+				}()
+				cleanup = append(cleanup, func() { <-channelSupervisorDone })
+				if seconds := cfg.AI.HealthCheckSeconds; seconds > 0 {
+					healthCheckDone := make(chan struct{})
+					go func() {
+						defer close(healthCheckDone)
+						router.RunHealthChecks(ctx, time.Duration(seconds)*time.Second)
+					}()
+					cleanup = append(cleanup, func() { <-healthCheckDone })
+				}
 				if focusedPageDeliveries != nil {
 					workerCtx, cancelWorker := context.WithCancel(ctx)
 					workerDone := make(chan struct{})
@@ -409,12 +781,51 @@ func main() {
 						<-workerDone
 					})
 				}
-				focusedPageCleanupDone := make(chan struct{})
+				if cfg.Jobs.AIModelRefresh.Enabled {
+					// Load live catalogs now rather than at the first hourly
+					// run; until then providers serve their built-in ones.
+					modelsDone := make(chan struct{})
+					go func() {
+						defer close(modelsDone)
+						refreshCtx, cancel := context.WithTimeout(ctx, time.Minute)
+						defer cancel()
+						if err := router.RefreshModels(refreshCtx); err != nil {
+							slog.Warn("AI model catalogs not refreshed", "error", err)
+						}
+					}()
+					cleanup = append(cleanup, func() { <-modelsDone })
+				}
+				jobsDone := make(chan struct{})
+				go func() {
+					defer close(jobsDone)
+					jobScheduler.Run(ctx)
+				}()
+				cleanup = append(cleanup, func() {
+					<-jobsDone
+					// Flush the usage counted since the last sync so a clean
+					// shutdown loses none.
+					flushCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+					defer cancel()
+					if err := tokenBudget.Sync(flushCtx); err != nil {
+						slog.Warn("final token budget sync failed", "error", err)
+					}
+				})
+				if turnResponder != nil {
+					if err := turnResponder.Subscribe(ctx, natsConn); err != nil {
+						return err
+					}
+					cleanup = append(cleanup, func() {
+						if err := turnResponder.Close(); err != nil {
+							slog.Warn("failed to drain engine turn subscription", "error", err)
+						}
+					})
+				}
+				secretsDone := make(chan struct{})
 				go func() {
-					defer close(focusedPageCleanupDone)
-					focusedPageCleanup.Run(ctx)
+					defer close(secretsDone)
+					secretStore.Watch(ctx, time.Duration(cfg.Secrets.ReloadSeconds)*time.Second, applySecrets)
 				}()
-				cleanup = append(cleanup, func() { <-focusedPageCleanupDone })
+				cleanup = append(cleanup, func() { <-secretsDone })
 				slog.Info("P&AI Bot is running")
 				return nil
 			}, nil
@@ -44,6 +44,8 @@ func main() {
 	var historyJSONPath string
 	var dumpJSONPath string
 	var dumpTurnLimit int
+	var dryRun bool
+	var memorySnapshotPath string
 
 	flag.StringVar(&userID, "user-id", "terminal-user", "stable user id for the terminal session")
 	flag.StringVar(&language, "lang", "", "preferred language override (en, ms, zh)")
@@ -52,14 +54,21 @@ func main() {
 	flag.BoolVar(&multi, "multi", false, "multi-user mode: prefix lines with N: to switch users (e.g., 1:hello, 2:/challenge ABC)")
 	flag.IntVar(&userCount, "users", 2, "number of simulated users in multi-user mode")
 	flag.StringVar(&wsURL, "ws", "", "WebSocket server URL (e.g. ws://localhost:8080/ws/chat); when set, runs as pure WS client")
-	flag.StringVar(&oneShotMessage, "message", "", "send one WebSocket message and print one response; requires --ws")
+	flag.StringVar(&oneShotMessage, "message", "", "send one message and print one response; requires --ws or --dry-run")
 	flag.BoolVar(&verbose, "verbose", false, "show diagnostic warnings from curriculum loading and background checks")
 	flag.BoolVar(&progressSideEffects, "progress", false, "enable mastery, streak, and XP side effects in local terminal sessions")
 	flag.StringVar(&historyJSONPath, "history-json", "", "write local terminal conversation history to a JSON file when the session ends")
 	flag.StringVar(&dumpJSONPath, "dump-json", "", "write local terminal conversation history plus model-facing AI request/response traces to a JSON file when the session ends")
 	flag.IntVar(&dumpTurnLimit, "turn-limit", 0, "limit exported conversation turns and model calls to the latest N items; 0 exports everything")
+	flag.StringVar(&memorySnapshotPath, "memory-snapshot", "", "with --memory, load in-memory conversations from this JSON file on start and save them back periodically and on exit")
+	flag.BoolVar(&dryRun, "dry-run", false, "print the composed prompt, selected model, and estimated tokens for --message as JSON without calling a provider")
 	flag.Parse()
 
+	if dryRun && (wsURL != "" || strings.TrimSpace(oneShotMessage) == "") {
+		fmt.Fprintln(os.Stderr, "--dry-run requires --message and a local session (no --ws)")
+		os.Exit(1)
+	}
+
 	if wsURL != "" {
 		if oneShotMessage != "" {
 			if err := runWSClientOnce(wsURL, userID, oneShotMessage); err != nil {
@@ -106,9 +115,14 @@ func main() {
 		slog.Warn("curriculum not loaded", "path", cfg.CurriculumPath, "error", err)
 	}
 
+	if memorySnapshotPath != "" && !memory {
+		fmt.Fprintln(os.Stderr, "--memory-snapshot requires --memory")
+		os.Exit(1)
+	}
 	state, cleanup, err := terminalchat.BuildState(context.Background(), cfg.Database, terminalchat.StateOptions{
-		Memory:  memory,
-		Channel: channel,
+		Memory:       memory,
+		Channel:      channel,
+		SnapshotPath: memorySnapshotPath,
 	}, terminalchat.StateDeps{})
 	if err != nil {
 		fmt.Fprintf(os.Stderr, "build terminal chat state: %v\n", err)
@@ -135,12 +149,15 @@ func main() {
 	}
 	var goalStore agent.GoalStore
 	var challengeStore agent.ChallengeStore
+	var learnerMemoryStore agent.LearnerMemoryStore
 	if memory {
 		goalStore = agent.NewMemoryGoalStore()
 		challengeStore = agent.NewMemoryChallengeStore()
+		learnerMemoryStore = agent.NewMemoryLearnerMemoryStore()
 	} else {
 		goalStore = agent.NewPostgresGoalStoreForChannel(state.DB.Pool, state.TenantID, channel)
 		challengeStore = agent.NewPostgresChallengeStoreForChannel(state.DB.Pool, state.TenantID, channel)
+		learnerMemoryStore = agent.NewPostgresLearnerMemoryStoreForChannel(state.DB.Pool, state.TenantID, channel)
 	}
 
 	engineCfg := agent.EngineConfig{
@@ -151,6 +168,7 @@ func main() {
 		DisableMultiLanguage: cfg.Runtime.DisableMultiLanguage,
 		Goals:                goalStore,
 		Challenges:           challengeStore,
+		LearnerMemory:        learnerMemoryStore,
 		TenantID:             state.TenantID,
 		DevMode:              cfg.Runtime.DevMode,
 		FeatureFlags:         func() featureflags.Features { return cfg.FeatureFlags },
@@ -171,6 +189,18 @@ func main() {
 	}
 	engine := agent.NewEngine(engineCfg)
 
+	if dryRun {
+		if err := runDryRun(context.Background(), os.Stdout, engine, chat.InboundMessage{
+			Channel: channel,
+			UserID:  userID,
+			Text:    oneShotMessage,
+		}); err != nil {
+			fmt.Fprintf(os.Stderr, "dry run: %v\n", err)
+			os.Exit(1)
+		}
+		return
+	}
+
 	processor := terminalchat.Processor(engine)
 	var history *conversationHistory
 	if strings.TrimSpace(historyJSONPath) != "" || strings.TrimSpace(dumpJSONPath) != "" {
@@ -211,6 +241,16 @@ func main() {
 	}
 }
 
+func runDryRun(ctx context.Context, out io.Writer, engine *agent.Engine, msg chat.InboundMessage) error {
+	result, err := engine.DryRunMessage(ctx, msg)
+	if err != nil {
+		return err
+	}
+	enc := json.NewEncoder(out)
+	enc.SetIndent("", "  ")
+	return enc.Encode(result)
+}
+
 type conversationHistory struct {
 	mu         sync.Mutex             `json:"-"`
 	UserID     string                 `json:"user_id"`
@@ -0,0 +1,192 @@
+// Copyright 2026 the P&AI authors. All rights reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package adminapi
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+)
+
+// apiTokenPrefix marks P&AI API tokens so leaked ones are easy to spot in
+// logs and secret scanners.
+const apiTokenPrefix = "pai_"
+
+// apiTokenNameMaxLen bounds the label admins give a token.
+const apiTokenNameMaxLen = 100
+
+// APIToken is a read-only token a school gives its own dashboards to pull
+// the tenant's usage statistics. The token itself is never stored; Prefix
+// is enough to tell tokens apart.
+type APIToken struct {
+	ID         string     `json:"id"`
+	Name       string     `json:"name"`
+	Prefix     string     `json:"prefix"`
+	CreatedBy  string     `json:"created_by,omitempty"`
+	CreatedAt  time.Time  `json:"created_at"`
+	LastUsedAt *time.Time `json:"last_used_at,omitempty"`
+	RevokedAt  *time.Time `json:"revoked_at,omitempty"`
+}
+
+// CreatedAPIToken is a new token with its secret, which is shown only once.
+type CreatedAPIToken struct {
+	APIToken
+	Token string `json:"token"`
+}
+
+const apiTokenColumns = `id::text, name, token_prefix, COALESCE(created_by::text, ''), created_at, last_used_at, revoked_at`
+
+// ListAPITokens returns the tenant's API tokens, revoked ones included,
+// newest first.
+func (s *Service) ListAPITokens() ([]APIToken, error) {
+	if strings.TrimSpace(s.tenantID) == "" || s.allTenants {
+		return nil, fmt.Errorf("%w: tenant-scoped admin context is required", ErrInvalidArgument)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	rows, err := s.pool.Query(ctx, `
+		SELECT `+apiTokenColumns+`
+		FROM api_tokens
+		WHERE tenant_id = $1::uuid
+		ORDER BY created_at DESC, id`, s.tenantID)
+	if err != nil {
+		return nil, fmt.Errorf("list api tokens: %w", err)
+	}
+	tokens, err := pgx.CollectRows(rows, scanAPIToken)
+	if err != nil {
+		return nil, fmt.Errorf("list api tokens: %w", err)
+	}
+	if tokens == nil {
+		tokens = []APIToken{}
+	}
+	return tokens, nil
+}
+
+// CreateAPIToken issues a new token for the tenant. Only its hash is
+// stored, so the returned Token cannot be read back later.
+func (s *Service) CreateAPIToken(name, createdByUserID string) (CreatedAPIToken, error) {
+	if strings.TrimSpace(s.tenantID) == "" || s.allTenants {
+		return CreatedAPIToken{}, fmt.Errorf("%w: tenant-scoped admin context is required", ErrInvalidArgument)
+	}
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return CreatedAPIToken{}, fmt.Errorf("%w: name is required", ErrInvalidArgument)
+	}
+	if len([]rune(name)) > apiTokenNameMaxLen {
+		return CreatedAPIToken{}, fmt.Errorf("%w: name is longer than %d characters", ErrInvalidArgument, apiTokenNameMaxLen)
+	}
+	token, err := generateAPIToken()
+	if err != nil {
+		return CreatedAPIToken{}, fmt.Errorf("generate api token: %w", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	rows, err := s.pool.Query(ctx, `
+		INSERT INTO api_tokens (tenant_id, name, token_hash, token_prefix, created_by)
+		VALUES ($1::uuid, $2, $3, $4, NULLIF($5, '')::uuid)
+		RETURNING `+apiTokenColumns,
+		s.tenantID, name, hashAPIToken(token), apiTokenDisplayPrefix(token), createdByUserID)
+	if err != nil {
+		return CreatedAPIToken{}, fmt.Errorf("create api token: %w", err)
+	}
+	created, err := pgx.CollectExactlyOneRow(rows, scanAPIToken)
+	if err != nil {
+		return CreatedAPIToken{}, fmt.Errorf("create api token: %w", err)
+	}
+	return CreatedAPIToken{APIToken: created, Token: token}, nil
+}
+
+// RevokeAPIToken stops a token from authenticating. Revoking it again is a
+// no-op.
+func (s *Service) RevokeAPIToken(id string) error {
+	if strings.TrimSpace(s.tenantID) == "" || s.allTenants {
+		return fmt.Errorf("%w: tenant-scoped admin context is required", ErrInvalidArgument)
+	}
+	if !looksLikeUUID(id) {
+		return ErrNotFound
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	cmd, err := s.pool.Exec(ctx, `
+		UPDATE api_tokens
+		SET revoked_at = COALESCE(revoked_at, NOW())
+		WHERE tenant_id = $1::uuid AND id = $2::uuid`, s.tenantID, id)
+	if err != nil {
+		return fmt.Errorf("revoke api token: %w", err)
+	}
+	if cmd.RowsAffected() == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+// ResolveAPIToken returns the tenant a live token belongs to and records
+// its use. Unknown and revoked tokens return ErrNotFound. It runs without
+// tenant scope: the token is what picks the tenant.
+func (s *Service) ResolveAPIToken(ctx context.Context, token string) (string, error) {
+	token = strings.TrimSpace(token)
+	if !strings.HasPrefix(token, apiTokenPrefix) {
+		return "", ErrNotFound
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	var tenantID string
+	err := s.pool.QueryRow(ctx, `
+		UPDATE api_tokens
+		SET last_used_at = NOW()
+		WHERE token_hash = $1 AND revoked_at IS NULL
+		RETURNING tenant_id::text`, hashAPIToken(token)).Scan(&tenantID)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return "", ErrNotFound
+		}
+		return "", fmt.Errorf("resolve api token: %w", err)
+	}
+	return tenantID, nil
+}
+
+func scanAPIToken(row pgx.CollectableRow) (APIToken, error) {
+	var token APIToken
+	err := row.Scan(&token.ID, &token.Name, &token.Prefix, &token.CreatedBy, &token.CreatedAt, &token.LastUsedAt, &token.RevokedAt)
+	return token, err
+}
+
+func generateAPIToken() (string, error) {
+	buf := make([]byte, 32)
+	if _, err := rand.Read(buf); err != nil {
+		return "", err
+	}
+	return apiTokenPrefix + base64.RawURLEncoding.EncodeToString(buf), nil
+}
+
+func hashAPIToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
+
+// apiTokenDisplayPrefix keeps the marker and a few random characters: enough
+// to recognise a token, far too few to guess it.
+func apiTokenDisplayPrefix(token string) string {
+	const n = len(apiTokenPrefix) + 6
+	if len(token) <= n {
+		return token
+	}
+	return token[:n]
+}
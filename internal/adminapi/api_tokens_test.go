@@ -0,0 +1,59 @@
+// Copyright 2026 the P&AI authors. All rights reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package adminapi
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestAPITokensRequireTenantScope(t *testing.T) {
+	svc := &Service{allTenants: true}
+	if _, err := svc.ListAPITokens(); !errors.Is(err, ErrInvalidArgument) {
+		t.Fatalf("ListAPITokens() error = %v, want ErrInvalidArgument", err)
+	}
+	if _, err := svc.CreateAPIToken("Dashboard", ""); !errors.Is(err, ErrInvalidArgument) {
+		t.Fatalf("CreateAPIToken() error = %v, want ErrInvalidArgument", err)
+	}
+	if err := svc.RevokeAPIToken("00000000-0000-0000-0000-000000000001"); !errors.Is(err, ErrInvalidArgument) {
+		t.Fatalf("RevokeAPIToken() error = %v, want ErrInvalidArgument", err)
+	}
+}
+
+func TestCreateAPITokenRequiresName(t *testing.T) {
+	svc := &Service{tenantID: "00000000-0000-0000-0000-000000000001"}
+	for _, name := range []string{"  ", strings.Repeat("x", apiTokenNameMaxLen+1)} {
+		if _, err := svc.CreateAPIToken(name, ""); !errors.Is(err, ErrInvalidArgument) {
+			t.Fatalf("CreateAPIToken(%q) error = %v, want ErrInvalidArgument", name, err)
+		}
+	}
+}
+
+func TestResolveAPITokenRejectsForeignTokens(t *testing.T) {
+	// Rejected before any query: the service has no pool.
+	svc := &Service{allTenants: true}
+	if _, err := svc.ResolveAPIToken(context.Background(), "eyJhbGciOiJIUzI1NiJ9.jwt"); !errors.Is(err, ErrNotFound) {
+		t.Fatalf("ResolveAPIToken() error = %v, want ErrNotFound", err)
+	}
+}
+
+func TestGenerateAPIToken(t *testing.T) {
+	token, err := generateAPIToken()
+	if err != nil {
+		t.Fatalf("generateAPIToken() error = %v", err)
+	}
+	other, _ := generateAPIToken()
+	if !strings.HasPrefix(token, apiTokenPrefix) || token == other {
+		t.Fatalf("tokens = %q, %q", token, other)
+	}
+	prefix := apiTokenDisplayPrefix(token)
+	if prefix != token[:len(apiTokenPrefix)+6] {
+		t.Fatalf("apiTokenDisplayPrefix() = %q", prefix)
+	}
+	if hashAPIToken(token) == hashAPIToken(other) || len(hashAPIToken(token)) != 64 {
+		t.Fatalf("hashAPIToken() did not produce distinct sha256 hex digests")
+	}
+}
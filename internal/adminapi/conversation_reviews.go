@@ -0,0 +1,273 @@
+// Copyright 2026 the P&AI authors. All rights reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package adminapi
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+)
+
+// Review queue filters accepted by ListConversationReviews.
+const (
+	ReviewStatusPending  = "pending"
+	ReviewStatusReviewed = "reviewed"
+	ReviewStatusAll      = "all"
+)
+
+const (
+	defaultReviewListLimit = 50
+	maxReviewListLimit     = 200
+	// reviewExportLimit caps how many rated conversations one eval export
+	// reads, newest first.
+	reviewExportLimit = 200
+)
+
+// ConversationReview is one conversation in the human review queue. Reason
+// is "sampled" or "safety"; Rating is nil until a reviewer rates it.
+type ConversationReview struct {
+	ID             string     `json:"id"`
+	ConversationID string     `json:"conversation_id"`
+	StudentID      string     `json:"student_id"`
+	StudentName    string     `json:"student_name"`
+	Channel        string     `json:"channel"`
+	TopicID        string     `json:"topic_id,omitempty"`
+	Reason         string     `json:"reason"`
+	Rating         *int       `json:"rating,omitempty"`
+	Comment        string     `json:"comment,omitempty"`
+	ReviewedBy     string     `json:"reviewed_by,omitempty"`
+	ReviewedAt     *time.Time `json:"reviewed_at,omitempty"`
+	CreatedAt      time.Time  `json:"created_at"`
+}
+
+// EvalFixture is a conversation harness fixture built from rated reviews,
+// in the shape of internal/agent/testdata/ai_quality_conversations.yaml.
+type EvalFixture struct {
+	Version       int                `json:"version" yaml:"version"`
+	Conversations []EvalConversation `json:"conversations" yaml:"conversations"`
+}
+
+// EvalConversation replays a reviewed conversation's student turns.
+type EvalConversation struct {
+	ID     string     `json:"id" yaml:"id"`
+	Title  string     `json:"title" yaml:"title"`
+	Tags   []string   `json:"tags" yaml:"tags"`
+	Turns  []EvalTurn `json:"turns" yaml:"turns"`
+	Checks EvalChecks `json:"checks" yaml:"checks"`
+}
+
+type EvalTurn struct {
+	User string `json:"user" yaml:"user"`
+}
+
+// EvalChecks holds the baseline checks every exported case starts with;
+// curators add behavior checks when promoting a case into the fixture.
+type EvalChecks struct {
+	RequireNonEmptyReplies bool `json:"require_non_empty_replies" yaml:"require_non_empty_replies"`
+	ForbidFallbackMessage  bool `json:"forbid_fallback_message" yaml:"forbid_fallback_message"`
+}
+
+const conversationReviewColumns = `
+	r.id::text,
+	r.conversation_id::text,
+	COALESCE(NULLIF(u.external_id, ''), u.id::text),
+	u.name,
+	u.channel,
+	COALESCE(c.topic_id, ''),
+	r.reason,
+	r.rating,
+	r.comment,
+	COALESCE(r.reviewed_by::text, ''),
+	r.reviewed_at,
+	r.created_at`
+
+const conversationReviewJoins = `
+	FROM conversation_reviews r
+	JOIN conversations c ON c.id = r.conversation_id
+	JOIN users u ON u.id = c.user_id`
+
+// ListConversationReviews returns the review queue. Pending reviews list
+// safety flags first, then oldest first; reviewed ones list newest first.
+func (s *Service) ListConversationReviews(status string, limit int) ([]ConversationReview, error) {
+	status = strings.TrimSpace(status)
+	if status == "" {
+		status = ReviewStatusPending
+	}
+	var filter, order string
+	switch status {
+	case ReviewStatusPending:
+		filter = "AND r.reviewed_at IS NULL"
+		order = "(r.reason = 'safety') DESC, r.created_at"
+	case ReviewStatusReviewed:
+		filter = "AND r.reviewed_at IS NOT NULL"
+		order = "r.reviewed_at DESC"
+	case ReviewStatusAll:
+		order = "r.created_at DESC"
+	default:
+		return nil, fmt.Errorf("%w: status must be pending, reviewed or all", ErrInvalidArgument)
+	}
+	if limit <= 0 {
+		limit = defaultReviewListLimit
+	}
+	limit = min(limit, maxReviewListLimit)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	rows, err := s.pool.Query(ctx, fmt.Sprintf(`
+		SELECT %s
+		%s
+		WHERE %s %s
+		ORDER BY %s
+		LIMIT $2`,
+		conversationReviewColumns, conversationReviewJoins, s.tenantPredicate("r.tenant_id", 1), filter, order),
+		s.tenantArg(), limit)
+	if err != nil {
+		return nil, fmt.Errorf("list conversation reviews: %w", err)
+	}
+	reviews, err := collectConversationReviews(rows)
+	if err != nil {
+		return nil, fmt.Errorf("list conversation reviews: %w", err)
+	}
+	return reviews, nil
+}
+
+// RateConversationReview records a reviewer's 1-5 rating and comment.
+// Rating a review again replaces the earlier verdict.
+func (s *Service) RateConversationReview(reviewID string, rating int, comment, reviewerUserID string) (ConversationReview, error) {
+	reviewID = strings.TrimSpace(reviewID)
+	if !looksLikeUUID(reviewID) {
+		return ConversationReview{}, ErrNotFound
+	}
+	if rating < 1 || rating > 5 {
+		return ConversationReview{}, fmt.Errorf("%w: rating must be between 1 and 5", ErrInvalidArgument)
+	}
+	if !looksLikeUUID(reviewerUserID) {
+		reviewerUserID = ""
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	rows, err := s.pool.Query(ctx, fmt.Sprintf(`
+		WITH rated AS (
+			UPDATE conversation_reviews
+			SET rating = $3, comment = $4, reviewed_by = NULLIF($5, '')::uuid, reviewed_at = NOW()
+			WHERE %s AND id = $2::uuid
+			RETURNING *
+		)
+		SELECT %s
+		FROM rated r
+		JOIN conversations c ON c.id = r.conversation_id
+		JOIN users u ON u.id = c.user_id`,
+		s.tenantPredicate("tenant_id", 1), conversationReviewColumns),
+		s.tenantArg(), reviewID, rating, strings.TrimSpace(comment), reviewerUserID)
+	if err != nil {
+		return ConversationReview{}, fmt.Errorf("rate conversation review: %w", err)
+	}
+	reviews, err := collectConversationReviews(rows)
+	if err != nil {
+		return ConversationReview{}, fmt.Errorf("rate conversation review: %w", err)
+	}
+	if len(reviews) == 0 {
+		return ConversationReview{}, ErrNotFound
+	}
+	return reviews[0], nil
+}
+
+// ExportReviewEvalFixture turns the newest rated reviews into conversation
+// harness cases that replay each learner's turns. Tags carry the reason and
+// rating so the harness can select, say, only poorly rated sessions.
+func (s *Service) ExportReviewEvalFixture() (EvalFixture, error) {
+	reviews, err := s.ListConversationReviews(ReviewStatusReviewed, reviewExportLimit)
+	if err != nil {
+		return EvalFixture{}, err
+	}
+	fixture := EvalFixture{Version: 1, Conversations: []EvalConversation{}}
+	for _, review := range reviews {
+		transcript, err := s.GetConversationTranscript(review.ConversationID)
+		if errors.Is(err, ErrNotFound) {
+			continue
+		}
+		if err != nil {
+			return EvalFixture{}, fmt.Errorf("export review %s: %w", review.ID, err)
+		}
+		if evalCase, ok := reviewEvalConversation(review, transcript); ok {
+			fixture.Conversations = append(fixture.Conversations, evalCase)
+		}
+	}
+	return fixture, nil
+}
+
+// reviewEvalConversation builds a harness case from the learner's surviving
+// messages. It reports false when the learner said nothing to replay.
+func reviewEvalConversation(review ConversationReview, transcript ConversationTranscript) (EvalConversation, bool) {
+	var turns []EvalTurn
+	for _, entry := range transcript.Entries {
+		if entry.Kind != TranscriptEntryMessage || entry.Role != "student" || entry.DeletedAt != nil {
+			continue
+		}
+		if text := strings.TrimSpace(entry.Text); text != "" {
+			turns = append(turns, EvalTurn{User: text})
+		}
+	}
+	if len(turns) == 0 {
+		return EvalConversation{}, false
+	}
+	title := review.Comment
+	if title == "" {
+		title = "Reviewed conversation " + review.ConversationID
+	}
+	tags := []string{"review", review.Reason}
+	if review.Rating != nil {
+		tags = append(tags, fmt.Sprintf("rating-%d", *review.Rating))
+	}
+	if review.TopicID != "" {
+		tags = append(tags, review.TopicID)
+	}
+	return EvalConversation{
+		ID:     "review-" + review.ID,
+		Title:  title,
+		Tags:   tags,
+		Turns:  turns,
+		Checks: EvalChecks{RequireNonEmptyReplies: true, ForbidFallbackMessage: true},
+	}, true
+}
+
+func collectConversationReviews(rows pgx.Rows) ([]ConversationReview, error) {
+	defer rows.Close()
+	reviews := []ConversationReview{}
+	for rows.Next() {
+		var (
+			review ConversationReview
+			rating *int16
+		)
+		if err := rows.Scan(
+			&review.ID,
+			&review.ConversationID,
+			&review.StudentID,
+			&review.StudentName,
+			&review.Channel,
+			&review.TopicID,
+			&review.Reason,
+			&rating,
+			&review.Comment,
+			&review.ReviewedBy,
+			&review.ReviewedAt,
+			&review.CreatedAt,
+		); err != nil {
+			return nil, err
+		}
+		if rating != nil {
+			value := int(*rating)
+			review.Rating = &value
+		}
+		reviews = append(reviews, review)
+	}
+	return reviews, rows.Err()
+}
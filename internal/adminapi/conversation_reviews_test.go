@@ -0,0 +1,58 @@
+// Copyright 2026 the P&AI authors. All rights reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package adminapi
+
+import (
+	"errors"
+	"slices"
+	"testing"
+	"time"
+)
+
+func TestConversationReviewsRejectInvalidInput(t *testing.T) {
+	svc := &Service{tenantID: "tenant-abc"}
+	if _, err := svc.ListConversationReviews("flagged", 0); !errors.Is(err, ErrInvalidArgument) {
+		t.Fatalf("ListConversationReviews(flagged) error = %v, want ErrInvalidArgument", err)
+	}
+	reviewID := "7f1c8a52-1b2d-4c3e-9f4a-5b6c7d8e9f01"
+	for _, rating := range []int{0, 6} {
+		if _, err := svc.RateConversationReview(reviewID, rating, "", ""); !errors.Is(err, ErrInvalidArgument) {
+			t.Fatalf("RateConversationReview(rating %d) error = %v, want ErrInvalidArgument", rating, err)
+		}
+	}
+	if _, err := svc.RateConversationReview("42", 3, "", ""); !errors.Is(err, ErrNotFound) {
+		t.Fatalf("RateConversationReview(bad id) error = %v, want ErrNotFound", err)
+	}
+}
+
+func TestReviewEvalConversationReplaysSurvivingStudentTurns(t *testing.T) {
+	rating := 2
+	deleted := time.Date(2026, 10, 17, 9, 0, 0, 0, time.UTC)
+	review := ConversationReview{ID: "r1", ConversationID: "c1", Reason: "safety", Rating: &rating, TopicID: "F1-02", Comment: "Gave away the answer"}
+	transcript := ConversationTranscript{Entries: []TranscriptEntry{
+		{Kind: TranscriptEntryMessage, Role: "student", Text: "Solve 2x = 10"},
+		{Kind: TranscriptEntryMessage, Role: "assistant", Text: "x = 5"},
+		{Kind: TranscriptEntryEvent, EventType: "agent_turn_completed"},
+		{Kind: TranscriptEntryMessage, Role: "student", Text: "oops wrong chat", DeletedAt: &deleted},
+		{Kind: TranscriptEntryMessage, Role: "student", Text: " Why? "},
+	}}
+
+	got, ok := reviewEvalConversation(review, transcript)
+	if !ok {
+		t.Fatal("reviewEvalConversation() reported nothing to replay")
+	}
+	if len(got.Turns) != 2 || got.Turns[0].User != "Solve 2x = 10" || got.Turns[1].User != "Why?" {
+		t.Fatalf("turns = %+v, want the two surviving student messages", got.Turns)
+	}
+	if got.ID != "review-r1" || got.Title != "Gave away the answer" || !slices.Equal(got.Tags, []string{"review", "safety", "rating-2", "F1-02"}) {
+		t.Fatalf("case = %+v, want id, comment title and reason/rating/topic tags", got)
+	}
+	if !got.Checks.RequireNonEmptyReplies || !got.Checks.ForbidFallbackMessage {
+		t.Fatalf("checks = %+v, want the baseline checks", got.Checks)
+	}
+
+	if _, ok := reviewEvalConversation(review, ConversationTranscript{}); ok {
+		t.Fatal("reviewEvalConversation() with no student turns should be skipped")
+	}
+}
@@ -0,0 +1,211 @@
+// Copyright 2026 the P&AI authors. All rights reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package adminapi
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+)
+
+// dailyProblemHistoryDays is how far back ListDailyProblems looks; curated
+// problems scheduled ahead are always listed.
+const dailyProblemHistoryDays = 14
+
+// DailyProblem is one form's problem of the day. Source is "curated" when
+// saved here and "generated" when the bot picked one for a day left empty.
+type DailyProblem struct {
+	ID        string               `json:"id"`
+	TenantID  string               `json:"tenant_id"`
+	Form      string               `json:"form"`
+	Day       string               `json:"day"`
+	TopicID   string               `json:"topic_id"`
+	Question  DailyProblemQuestion `json:"question"`
+	Source    string               `json:"source"`
+	UpdatedAt time.Time            `json:"updated_at"`
+}
+
+// DailyProblemQuestion is the question learners answer. Options turn it into
+// multiple choice with Answer as the correct one.
+type DailyProblemQuestion struct {
+	Text       string   `json:"text"`
+	Answer     string   `json:"answer"`
+	AnswerType string   `json:"answer_type,omitempty"`
+	Working    string   `json:"working,omitempty"`
+	Options    []string `json:"options,omitempty"`
+	Hints      []string `json:"hints,omitempty"`
+}
+
+// storedDailyProblemQuestion mirrors the quiz question the bot keeps in
+// daily_problems.question, which has no JSON tags.
+type storedDailyProblemQuestion struct {
+	ID          string
+	Text        string
+	Difficulty  string
+	AnswerType  string
+	Answer      string
+	Working     string
+	Hints       []storedQuizHint
+	Distractors []storedQuizDistractor
+}
+
+type storedQuizHint struct {
+	Level int
+	Text  string
+}
+
+type storedQuizDistractor struct {
+	Value    string
+	Feedback string
+}
+
+const dailyProblemColumns = `id::text, tenant_id::text, form, publish_on::text, topic_id, question, source, updated_at`
+
+// ListDailyProblems returns the tenant's problems from the last two weeks
+// and everything scheduled ahead, newest first.
+func (s *Service) ListDailyProblems() ([]DailyProblem, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	rows, err := s.pool.Query(ctx, fmt.Sprintf(`
+		SELECT %s
+		FROM daily_problems
+		WHERE %s
+		  AND publish_on >= CURRENT_DATE - $2::int
+		ORDER BY publish_on DESC, form`,
+		dailyProblemColumns, s.tenantPredicate("tenant_id", 1)), s.tenantArg(), dailyProblemHistoryDays)
+	if err != nil {
+		return nil, fmt.Errorf("list daily problems: %w", err)
+	}
+	problems, err := collectDailyProblems(rows)
+	if err != nil {
+		return nil, fmt.Errorf("list daily problems: %w", err)
+	}
+	return problems, nil
+}
+
+// SaveDailyProblem curates form's problem for day, replacing any problem
+// already there. Learners who were sent the old one keep their attempts.
+func (s *Service) SaveDailyProblem(form, day, topicID string, question DailyProblemQuestion) (DailyProblem, error) {
+	if s.allTenants {
+		return DailyProblem{}, fmt.Errorf("%w: cannot save daily problems without tenant scope", ErrInvalidArgument)
+	}
+	form, topicID = strings.TrimSpace(form), strings.TrimSpace(topicID)
+	if form == "" || topicID == "" {
+		return DailyProblem{}, fmt.Errorf("%w: form and topic_id are required", ErrInvalidArgument)
+	}
+	if _, err := time.Parse("2006-01-02", day); err != nil {
+		return DailyProblem{}, fmt.Errorf("%w: day must be YYYY-MM-DD", ErrInvalidArgument)
+	}
+	stored, err := storeDailyProblemQuestion(question)
+	if err != nil {
+		return DailyProblem{}, err
+	}
+	raw, err := json.Marshal(stored)
+	if err != nil {
+		return DailyProblem{}, fmt.Errorf("encode daily problem: %w", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	rows, err := s.pool.Query(ctx, `
+		INSERT INTO daily_problems (tenant_id, form, publish_on, topic_id, question, source)
+		VALUES ($1::uuid, $2, $3::date, $4, $5::jsonb, 'curated')
+		ON CONFLICT (tenant_id, form, publish_on) DO UPDATE
+		SET topic_id = EXCLUDED.topic_id, question = EXCLUDED.question,
+		    source = 'curated', updated_at = NOW()
+		RETURNING `+dailyProblemColumns,
+		s.tenantID, form, day, topicID, raw)
+	if err != nil {
+		return DailyProblem{}, fmt.Errorf("save daily problem: %w", err)
+	}
+	problems, err := collectDailyProblems(rows)
+	if err != nil {
+		return DailyProblem{}, fmt.Errorf("save daily problem: %w", err)
+	}
+	if len(problems) != 1 {
+		return DailyProblem{}, fmt.Errorf("save daily problem: got %d rows", len(problems))
+	}
+	return problems[0], nil
+}
+
+// storeDailyProblemQuestion validates q and converts it to the bot's quiz
+// question shape.
+func storeDailyProblemQuestion(q DailyProblemQuestion) (storedDailyProblemQuestion, error) {
+	stored := storedDailyProblemQuestion{
+		ID:         "daily",
+		Text:       strings.TrimSpace(q.Text),
+		AnswerType: strings.TrimSpace(q.AnswerType),
+		Answer:     strings.TrimSpace(q.Answer),
+		Working:    strings.TrimSpace(q.Working),
+	}
+	if stored.Text == "" || stored.Answer == "" {
+		return storedDailyProblemQuestion{}, fmt.Errorf("%w: question text and answer are required", ErrInvalidArgument)
+	}
+	if len(q.Options) > 0 {
+		stored.AnswerType = "multiple_choice"
+		hasAnswer := false
+		for _, option := range q.Options {
+			option = strings.TrimSpace(option)
+			if option == "" {
+				continue
+			}
+			if option == stored.Answer {
+				hasAnswer = true
+				continue
+			}
+			stored.Distractors = append(stored.Distractors, storedQuizDistractor{Value: option})
+		}
+		if !hasAnswer {
+			return storedDailyProblemQuestion{}, fmt.Errorf("%w: options must include the answer", ErrInvalidArgument)
+		}
+	}
+	for i, hint := range q.Hints {
+		if hint = strings.TrimSpace(hint); hint != "" {
+			stored.Hints = append(stored.Hints, storedQuizHint{Level: i + 1, Text: hint})
+		}
+	}
+	return stored, nil
+}
+
+func collectDailyProblems(rows pgx.Rows) ([]DailyProblem, error) {
+	defer rows.Close()
+	problems := []DailyProblem{}
+	for rows.Next() {
+		var (
+			p   DailyProblem
+			raw []byte
+		)
+		if err := rows.Scan(&p.ID, &p.TenantID, &p.Form, &p.Day, &p.TopicID, &raw, &p.Source, &p.UpdatedAt); err != nil {
+			return nil, err
+		}
+		var stored storedDailyProblemQuestion
+		if err := json.Unmarshal(raw, &stored); err != nil {
+			return nil, fmt.Errorf("decode question: %w", err)
+		}
+		p.Question = DailyProblemQuestion{
+			Text:       stored.Text,
+			Answer:     stored.Answer,
+			AnswerType: stored.AnswerType,
+			Working:    stored.Working,
+		}
+		if len(stored.Distractors) > 0 {
+			p.Question.Options = []string{stored.Answer}
+			for _, d := range stored.Distractors {
+				p.Question.Options = append(p.Question.Options, d.Value)
+			}
+		}
+		for _, h := range stored.Hints {
+			p.Question.Hints = append(p.Question.Hints, h.Text)
+		}
+		p.UpdatedAt = p.UpdatedAt.UTC()
+		problems = append(problems, p)
+	}
+	return problems, rows.Err()
+}
@@ -0,0 +1,52 @@
+// Copyright 2026 the P&AI authors. All rights reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package adminapi
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestSaveDailyProblemRejectsInvalidInput(t *testing.T) {
+	question := DailyProblemQuestion{Text: "Solve 2x = 10.", Answer: "5"}
+	tests := []struct {
+		name     string
+		svc      *Service
+		form     string
+		day      string
+		topicID  string
+		question DailyProblemQuestion
+	}{
+		{name: "no tenant scope", svc: &Service{allTenants: true}, form: "1", day: "2026-10-20", topicID: "F1-02", question: question},
+		{name: "missing form", svc: &Service{tenantID: "tenant-abc"}, day: "2026-10-20", topicID: "F1-02", question: question},
+		{name: "bad day", svc: &Service{tenantID: "tenant-abc"}, form: "1", day: "20/10/2026", topicID: "F1-02", question: question},
+		{name: "missing answer", svc: &Service{tenantID: "tenant-abc"}, form: "1", day: "2026-10-20", topicID: "F1-02", question: DailyProblemQuestion{Text: "Solve 2x = 10."}},
+		{name: "answer not an option", svc: &Service{tenantID: "tenant-abc"}, form: "1", day: "2026-10-20", topicID: "F1-02", question: DailyProblemQuestion{Text: "Solve 2x = 10.", Answer: "5", Options: []string{"2", "10"}}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := tt.svc.SaveDailyProblem(tt.form, tt.day, tt.topicID, tt.question); !errors.Is(err, ErrInvalidArgument) {
+				t.Fatalf("SaveDailyProblem() error = %v, want ErrInvalidArgument", err)
+			}
+		})
+	}
+}
+
+func TestStoreDailyProblemQuestionBuildsMultipleChoice(t *testing.T) {
+	got, err := storeDailyProblemQuestion(DailyProblemQuestion{
+		Text:    "Solve 2x = 10.",
+		Answer:  "5",
+		Options: []string{"2", " 5 ", "10"},
+		Hints:   []string{"Divide both sides by 2."},
+	})
+	if err != nil {
+		t.Fatalf("storeDailyProblemQuestion() error = %v", err)
+	}
+	if got.AnswerType != "multiple_choice" || len(got.Distractors) != 2 || got.Distractors[0].Value != "2" {
+		t.Fatalf("question = %+v, want multiple choice with distractors 2 and 10", got)
+	}
+	if len(got.Hints) != 1 || got.Hints[0].Level != 1 {
+		t.Fatalf("hints = %+v, want one level-1 hint", got.Hints)
+	}
+}
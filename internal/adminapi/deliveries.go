@@ -0,0 +1,134 @@
+// Copyright 2026 the P&AI authors. All rights reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package adminapi
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+)
+
+const (
+	defaultDeliveryLimit = 50
+	maxDeliveryLimit     = 200
+	deliveryWindow       = 7 * 24 * time.Hour
+)
+
+// deliveryStatuses are the outcomes the gateway records per outbound message.
+var deliveryStatuses = []string{"sent", "failed", "rate_limited", "blocked"}
+
+// DeliveryReport summarizes outbound delivery over the last seven days.
+type DeliveryReport struct {
+	WindowDays int            `json:"window_days"`
+	Counts     map[string]int `json:"counts"`
+	// BlockedUsers counts users whose latest send was blocked; nudges to
+	// them are paused.
+	BlockedUsers int               `json:"blocked_users"`
+	Recent       []DeliveryAttempt `json:"recent"`
+}
+
+// DeliveryAttempt is one recorded outbound send.
+type DeliveryAttempt struct {
+	Channel     string    `json:"channel"`
+	StudentID   string    `json:"student_id"`
+	StudentName string    `json:"student_name,omitempty"`
+	Status      string    `json:"status"`
+	Error       string    `json:"error,omitempty"`
+	Timestamp   time.Time `json:"timestamp"`
+}
+
+// GetDeliveryReport returns delivery counts by status and the most recent
+// attempts. status filters the recent list and defaults to every
+// non-sent outcome.
+func (s *Service) GetDeliveryReport(status string, limit int) (DeliveryReport, error) {
+	status = strings.TrimSpace(status)
+	if status != "" && !slices.Contains(deliveryStatuses, status) {
+		return DeliveryReport{}, fmt.Errorf("%w: unknown delivery status %q", ErrInvalidArgument, status)
+	}
+	if limit <= 0 {
+		limit = defaultDeliveryLimit
+	}
+	limit = min(limit, maxDeliveryLimit)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	since := time.Now().Add(-deliveryWindow)
+	report := DeliveryReport{
+		WindowDays: int(deliveryWindow / (24 * time.Hour)),
+		Counts:     make(map[string]int, len(deliveryStatuses)),
+		Recent:     []DeliveryAttempt{},
+	}
+	for _, st := range deliveryStatuses {
+		report.Counts[st] = 0
+	}
+
+	rows, err := s.pool.Query(ctx, fmt.Sprintf(`
+		SELECT d.status, COUNT(*)
+		FROM message_deliveries d
+		WHERE %s AND d.created_at >= $2
+		GROUP BY d.status
+	`, s.tenantPredicate("d.tenant_id", 1)), s.tenantArg(), since)
+	if err != nil {
+		return DeliveryReport{}, fmt.Errorf("count deliveries: %w", err)
+	}
+	for rows.Next() {
+		var st string
+		var n int
+		if err := rows.Scan(&st, &n); err != nil {
+			rows.Close()
+			return DeliveryReport{}, fmt.Errorf("scan delivery count: %w", err)
+		}
+		report.Counts[st] = n
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return DeliveryReport{}, fmt.Errorf("iterate delivery counts: %w", err)
+	}
+
+	if err := s.pool.QueryRow(ctx, fmt.Sprintf(`
+		SELECT COUNT(*)
+		FROM (
+			SELECT DISTINCT ON (d.channel, d.external_id) d.status
+			FROM message_deliveries d
+			WHERE %s
+			ORDER BY d.channel, d.external_id, d.created_at DESC
+		) latest
+		WHERE latest.status = 'blocked'
+	`, s.tenantPredicate("d.tenant_id", 1)), s.tenantArg()).Scan(&report.BlockedUsers); err != nil {
+		return DeliveryReport{}, fmt.Errorf("count blocked users: %w", err)
+	}
+
+	rows, err = s.pool.Query(ctx, fmt.Sprintf(`
+		SELECT d.channel, d.external_id, COALESCE(u.name, ''), d.status, COALESCE(d.error, ''), d.created_at
+		FROM message_deliveries d
+		LEFT JOIN LATERAL (
+			SELECT name FROM users
+			WHERE tenant_id = d.tenant_id AND external_id = d.external_id
+			ORDER BY created_at ASC
+			LIMIT 1
+		) u ON TRUE
+		WHERE %s
+			AND (($2 = '' AND d.status <> 'sent') OR d.status = $2)
+		ORDER BY d.created_at DESC
+		LIMIT $3
+	`, s.tenantPredicate("d.tenant_id", 1)), s.tenantArg(), status, limit)
+	if err != nil {
+		return DeliveryReport{}, fmt.Errorf("list deliveries: %w", err)
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var a DeliveryAttempt
+		if err := rows.Scan(&a.Channel, &a.StudentID, &a.StudentName, &a.Status, &a.Error, &a.Timestamp); err != nil {
+			return DeliveryReport{}, fmt.Errorf("scan delivery: %w", err)
+		}
+		report.Recent = append(report.Recent, a)
+	}
+	if err := rows.Err(); err != nil {
+		return DeliveryReport{}, fmt.Errorf("iterate deliveries: %w", err)
+	}
+	return report, nil
+}
@@ -13,6 +13,8 @@ import (
 	"time"
 
 	"github.com/jackc/pgx/v5"
+
+	"github.com/p-n-ai/pai-bot/internal/progress"
 )
 
 // AdminGroup represents a group in the admin API.
@@ -29,6 +31,8 @@ type AdminGroup struct {
 	Closed      bool   `json:"closed"`
 	CreatedAt   string `json:"created_at"`
 	UpdatedAt   string `json:"updated_at"`
+
+	QuizLeaderboard bool `json:"quiz_leaderboard"`
 }
 
 // AdminGroupDetail is a group with its members.
@@ -64,6 +68,9 @@ type AdminUpdateGroupInput struct {
 	Subject     *string `json:"subject,omitempty"`
 	Cadence     *string `json:"cadence,omitempty"`
 	Closed      *bool   `json:"closed,omitempty"`
+
+	// QuizLeaderboard opts a class into the weekly quiz leaderboard.
+	QuizLeaderboard *bool `json:"quiz_leaderboard,omitempty"`
 }
 
 // AddMemberInput is the request body for adding a member to a group.
@@ -80,6 +87,19 @@ type AdminLeaderboardEntry struct {
 	Rank        int     `json:"rank"`
 }
 
+// ClassQuizLeaderboardEntry is a row of a class's weekly quiz leaderboard as
+// teachers see it: the student's real name alongside the nickname their
+// classmates see.
+type ClassQuizLeaderboardEntry struct {
+	UserID   string  `json:"user_id"`
+	Name     string  `json:"name"`
+	Nickname string  `json:"nickname,omitempty"`
+	QuizXP   int     `json:"quiz_xp"`
+	Accuracy float64 `json:"accuracy"`
+	Quizzes  int     `json:"quizzes"`
+	Rank     int     `json:"rank"`
+}
+
 const adminJoinCodeLen = 6
 const adminJoinCodeAlphabet = "ABCDEFGHJKLMNPQRSTUVWXYZ23456789"
 const adminLeaderboardLimit = 10
@@ -92,7 +112,7 @@ func (s *Service) ListGroups(groupType string) ([]AdminGroup, error) {
 		SELECT g.id::text, g.name, g.type, g.description, g.syllabus, g.subject, g.cadence,
 		       g.join_code, g.created_at, g.updated_at,
 		       COUNT(gm.id)::int,
-		       g.closed
+		       g.closed, g.quiz_leaderboard
 		FROM groups g
 		LEFT JOIN group_members gm ON gm.group_id = g.id
 		WHERE %s`, s.tenantPredicate("g.tenant_id", 1))
@@ -115,7 +135,7 @@ func (s *Service) ListGroups(groupType string) ([]AdminGroup, error) {
 		var g AdminGroup
 		var createdAt, updatedAt time.Time
 		if err := rows.Scan(&g.ID, &g.Name, &g.Type, &g.Description, &g.Syllabus, &g.Subject,
-			&g.Cadence, &g.JoinCode, &createdAt, &updatedAt, &g.MemberCount, &g.Closed); err != nil {
+			&g.Cadence, &g.JoinCode, &createdAt, &updatedAt, &g.MemberCount, &g.Closed, &g.QuizLeaderboard); err != nil {
 			return nil, fmt.Errorf("scan group: %w", err)
 		}
 		g.CreatedAt = createdAt.UTC().Format(time.RFC3339)
@@ -183,12 +203,12 @@ func (s *Service) GetGroupDetail(id string) (AdminGroupDetail, error) {
 		SELECT g.id::text, g.name, g.type, g.description, g.syllabus, g.subject, g.cadence,
 		       g.join_code, g.created_at, g.updated_at,
 		       (SELECT COUNT(*) FROM group_members gm WHERE gm.group_id = g.id)::int,
-		       g.closed
+		       g.closed, g.quiz_leaderboard
 		FROM groups g
 		WHERE g.id = $1::uuid AND %s`, s.tenantPredicate("g.tenant_id", 2)),
 		id, s.tenantArg(),
 	).Scan(&g.ID, &g.Name, &g.Type, &g.Description, &g.Syllabus, &g.Subject, &g.Cadence,
-		&g.JoinCode, &createdAt, &updatedAt, &g.MemberCount, &g.Closed)
+		&g.JoinCode, &createdAt, &updatedAt, &g.MemberCount, &g.Closed, &g.QuizLeaderboard)
 	if errors.Is(err, pgx.ErrNoRows) {
 		return AdminGroupDetail{}, fmt.Errorf("%w: group %s", ErrNotFound, id)
 	}
@@ -266,17 +286,23 @@ func (s *Service) UpdateGroup(id string, input AdminUpdateGroupInput) (AdminGrou
 	if input.Closed != nil {
 		setClauses = append(setClauses, fmt.Sprintf("closed = $%d", argIdx))
 		args = append(args, *input.Closed)
+		argIdx++
+	}
+	if input.QuizLeaderboard != nil {
+		// Study groups keep the mastery leaderboard; only classes opt in.
+		setClauses = append(setClauses, fmt.Sprintf("quiz_leaderboard = $%d AND type = 'class'", argIdx))
+		args = append(args, *input.QuizLeaderboard)
 	}
 
 	var g AdminGroup
 	var createdAt, updatedAt time.Time
 	err := s.pool.QueryRow(ctx, fmt.Sprintf(
 		`UPDATE groups SET %s WHERE id = $1::uuid AND %s
-		 RETURNING id::text, name, type, description, syllabus, subject, cadence, join_code, created_at, updated_at, closed`,
+		 RETURNING id::text, name, type, description, syllabus, subject, cadence, join_code, created_at, updated_at, closed, quiz_leaderboard`,
 		strings.Join(setClauses, ", "), s.tenantPredicate("tenant_id", 2)),
 		args...,
 	).Scan(&g.ID, &g.Name, &g.Type, &g.Description, &g.Syllabus, &g.Subject, &g.Cadence,
-		&g.JoinCode, &createdAt, &updatedAt, &g.Closed)
+		&g.JoinCode, &createdAt, &updatedAt, &g.Closed, &g.QuizLeaderboard)
 	if errors.Is(err, pgx.ErrNoRows) {
 		return AdminGroup{}, fmt.Errorf("%w: group %s", ErrNotFound, id)
 	}
@@ -429,7 +455,9 @@ func (s *Service) buildGroupLeaderboardQuery(groupID string) (string, []any) {
 }
 
 // GetGroupClassProgress returns class progress for a real group (by UUID).
-// This is the group-backed replacement for form-based class progress.
+// This is the group-backed replacement for form-based class progress. When
+// the class opted into the quiz leaderboard, the report includes this week's
+// standings.
 func (s *Service) GetGroupClassProgress(groupID string) (ClassProgress, error) {
 	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
 	defer cancel()
@@ -449,9 +477,85 @@ func (s *Service) GetGroupClassProgress(groupID string) (ClassProgress, error) {
 	if err != nil {
 		return ClassProgress{}, fmt.Errorf("query group class progress: %w", err)
 	}
-	defer rows.Close()
+	report, err := scanClassProgressRows(rows)
+	rows.Close()
+	if err != nil {
+		return ClassProgress{}, err
+	}
+
+	var quizLeaderboard bool
+	err = s.pool.QueryRow(ctx, fmt.Sprintf(
+		`SELECT quiz_leaderboard AND type = 'class' FROM groups WHERE id = $1::uuid AND %s`,
+		s.tenantPredicate("tenant_id", 2)),
+		groupID, s.tenantArg(),
+	).Scan(&quizLeaderboard)
+	if err != nil && !errors.Is(err, pgx.ErrNoRows) {
+		return ClassProgress{}, fmt.Errorf("get group quiz leaderboard setting: %w", err)
+	}
+	if !quizLeaderboard {
+		return report, nil
+	}
+
+	query, args := s.buildClassQuizLeaderboardQuery(groupID)
+	lbRows, err := s.pool.Query(ctx, query, args...)
+	if err != nil {
+		return ClassProgress{}, fmt.Errorf("query class quiz leaderboard: %w", err)
+	}
+	defer lbRows.Close()
+
+	report.QuizLeaderboard = make([]ClassQuizLeaderboardEntry, 0, adminLeaderboardLimit)
+	for lbRows.Next() {
+		var e ClassQuizLeaderboardEntry
+		if err := lbRows.Scan(&e.UserID, &e.Name, &e.Nickname, &e.QuizXP, &e.Accuracy, &e.Quizzes, &e.Rank); err != nil {
+			return ClassProgress{}, fmt.Errorf("scan class quiz leaderboard entry: %w", err)
+		}
+		report.QuizLeaderboard = append(report.QuizLeaderboard, e)
+	}
+	if err := lbRows.Err(); err != nil {
+		return ClassProgress{}, fmt.Errorf("iterate class quiz leaderboard: %w", err)
+	}
+	return report, nil
+}
 
-	return scanClassProgressRows(rows)
+// buildClassQuizLeaderboardQuery ranks a class the way students see it on
+// /leaderboard: XP from correct quiz answers over the last 7 days, ties
+// broken on accuracy, with opted-out students left off.
+func (s *Service) buildClassQuizLeaderboardQuery(groupID string) (string, []any) {
+	return fmt.Sprintf(`
+		WITH members AS (
+			SELECT gm.user_id, gm.tenant_id, u.name, COALESCE(pref.nickname, '') AS nickname
+			FROM group_members gm
+			JOIN users u ON u.id = gm.user_id AND u.role = 'student'
+			LEFT JOIN leaderboard_preferences pref ON pref.user_id = gm.user_id
+			WHERE gm.group_id = $1::uuid AND %s
+			  AND NOT COALESCE(pref.opted_out, false)
+		),
+		quizzes AS (
+			SELECT e.user_id,
+			       COUNT(*)::int AS quizzes,
+			       SUM(COALESCE((e.data->>'correct_answers')::int, 0))::int AS correct,
+			       SUM(COALESCE((e.data->>'total_questions')::int, 0))::int AS answered
+			FROM members m
+			JOIN events e
+			  ON e.user_id = m.user_id
+			 AND e.tenant_id = m.tenant_id
+			WHERE e.event_type = 'quiz_completed'
+			  AND e.created_at >= NOW() - INTERVAL '7 days'
+			GROUP BY e.user_id
+		),
+		scores AS (
+			SELECT m.user_id, m.name, m.nickname, q.quizzes,
+			       q.correct * $3::int AS quiz_xp,
+			       CASE WHEN q.answered > 0 THEN q.correct::float8 / q.answered ELSE 0 END AS accuracy
+			FROM members m
+			JOIN quizzes q ON q.user_id = m.user_id
+		)
+		SELECT user_id::text, name, nickname, quiz_xp, accuracy, quizzes,
+		       ROW_NUMBER() OVER (ORDER BY quiz_xp DESC, accuracy DESC) AS rank
+		FROM scores
+		ORDER BY rank
+		LIMIT %d`, s.tenantPredicate("gm.tenant_id", 2), adminLeaderboardLimit),
+		[]any{groupID, s.tenantArg(), progress.XPQuizCorrect}
 }
 
 func adminGenerateJoinCode() (string, error) {
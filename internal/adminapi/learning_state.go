@@ -0,0 +1,473 @@
+// Copyright 2026 the P&AI authors. All rights reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package adminapi
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+)
+
+// LearningStateVersion is the export format ImportLearningState accepts.
+const LearningStateVersion = 1
+
+// learningStateTimeout covers a whole export or import; an import writes
+// every row of the learner's state in one transaction.
+const learningStateTimeout = 15 * time.Second
+
+// LearningState is one learner's portable learning state: profile, mastery,
+// streak, XP, goals and study plan. It carries no tenant or user IDs, so it
+// can be imported into another tenant or deployment.
+type LearningState struct {
+	Version    int                     `json:"version"`
+	ExportedAt time.Time               `json:"exported_at"`
+	Learner    LearningStateLearner    `json:"learner"`
+	Progress   []LearningStateProgress `json:"progress"`
+	Streak     *LearningStateStreak    `json:"streak,omitempty"`
+	XP         []LearningStateXPEntry  `json:"xp"`
+	Goals      []LearningStateGoal     `json:"goals"`
+	StudyPlan  *LearningStateStudyPlan `json:"study_plan,omitempty"`
+}
+
+// LearningStateLearner is the learner's profile. Channel and ExternalID
+// identify the learner on import.
+type LearningStateLearner struct {
+	Name       string         `json:"name"`
+	ExternalID string         `json:"external_id"`
+	Channel    string         `json:"channel"`
+	Form       string         `json:"form,omitempty"`
+	Config     map[string]any `json:"config,omitempty"`
+}
+
+// LearningStateProgress is one topic's mastery and spaced-repetition state.
+type LearningStateProgress struct {
+	SyllabusID    string     `json:"syllabus_id"`
+	TopicID       string     `json:"topic_id"`
+	MasteryScore  float64    `json:"mastery_score"`
+	EaseFactor    float64    `json:"ease_factor"`
+	IntervalDays  int        `json:"interval_days"`
+	Repetitions   int        `json:"repetitions"`
+	NextReviewAt  *time.Time `json:"next_review_at,omitempty"`
+	LastStudiedAt *time.Time `json:"last_studied_at,omitempty"`
+}
+
+// LearningStateStreak is the learner's study streak. LastActiveDate is
+// YYYY-MM-DD.
+type LearningStateStreak struct {
+	Current        int    `json:"current"`
+	Longest        int    `json:"longest"`
+	LastActiveDate string `json:"last_active_date,omitempty"`
+}
+
+// LearningStateXPEntry is one xp_ledger award.
+type LearningStateXPEntry struct {
+	Source    string         `json:"source"`
+	Amount    int            `json:"amount"`
+	Metadata  map[string]any `json:"metadata,omitempty"`
+	CreatedAt time.Time      `json:"created_at"`
+}
+
+// LearningStateGoal is one learner goal, active or finished.
+type LearningStateGoal struct {
+	Summary        string     `json:"summary"`
+	TopicID        string     `json:"topic_id"`
+	TopicName      string     `json:"topic_name"`
+	SyllabusID     string     `json:"syllabus_id"`
+	TargetMastery  float64    `json:"target_mastery"`
+	CurrentMastery float64    `json:"current_mastery"`
+	Status         string     `json:"status"`
+	CreatedAt      time.Time  `json:"created_at"`
+	CompletedAt    *time.Time `json:"completed_at,omitempty"`
+}
+
+// LearningStateStudyPlan is the learner's exam revision plan. ExamDate is
+// YYYY-MM-DD; Weeks is stored as the bot wrote it.
+type LearningStateStudyPlan struct {
+	ExamDate  string           `json:"exam_date"`
+	Form      string           `json:"form,omitempty"`
+	Weeks     []map[string]any `json:"weeks"`
+	CreatedAt time.Time        `json:"created_at"`
+}
+
+// LearningStateImportResult summarizes an import.
+type LearningStateImportResult struct {
+	StudentID string `json:"student_id"`
+	Created   bool   `json:"created"`
+	Topics    int    `json:"topics"`
+	XPEntries int    `json:"xp_entries"`
+	Goals     int    `json:"goals"`
+	StudyPlan bool   `json:"study_plan"`
+}
+
+var learningStateGoalStatuses = []string{"active", "completed", "archived"}
+
+// ExportLearningState returns the learning state of the student with
+// studentID, their external ID or, failing that, their user ID.
+func (s *Service) ExportLearningState(studentID string) (LearningState, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), learningStateTimeout)
+	defer cancel()
+
+	state := LearningState{
+		Version:    LearningStateVersion,
+		ExportedAt: time.Now().UTC(),
+		Progress:   []LearningStateProgress{},
+		XP:         []LearningStateXPEntry{},
+		Goals:      []LearningStateGoal{},
+	}
+	var (
+		userID    string
+		rawConfig []byte
+	)
+	err := s.pool.QueryRow(ctx, fmt.Sprintf(`
+		SELECT u.id::text, u.name, COALESCE(u.external_id, ''), u.channel, COALESCE(u.form, ''),
+			COALESCE(u.config, '{}'::jsonb)
+		FROM users u
+		WHERE %s
+			AND u.role = 'student'
+			AND COALESCE(NULLIF(u.external_id, ''), u.id::text) = $2
+		LIMIT 1
+	`, s.tenantPredicate("u.tenant_id", 1)), s.tenantArg(), studentID).Scan(
+		&userID, &state.Learner.Name, &state.Learner.ExternalID, &state.Learner.Channel, &state.Learner.Form, &rawConfig,
+	)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return LearningState{}, ErrNotFound
+	}
+	if err != nil {
+		return LearningState{}, fmt.Errorf("query learner: %w", err)
+	}
+	if err := json.Unmarshal(rawConfig, &state.Learner.Config); err != nil {
+		return LearningState{}, fmt.Errorf("decode learner config: %w", err)
+	}
+	if state.Learner.ExternalID == "" {
+		// Web learners have no chat ID; their user ID keeps them addressable.
+		state.Learner.ExternalID = userID
+	}
+
+	rows, err := s.pool.Query(ctx, `
+		SELECT syllabus_id, topic_id, mastery_score, ease_factor, interval_days, repetitions,
+			next_review_at, last_studied_at
+		FROM learning_progress
+		WHERE user_id = $1::uuid
+		ORDER BY syllabus_id, topic_id`, userID)
+	if err != nil {
+		return LearningState{}, fmt.Errorf("query learner progress: %w", err)
+	}
+	state.Progress, err = pgx.CollectRows(rows, func(row pgx.CollectableRow) (LearningStateProgress, error) {
+		var p LearningStateProgress
+		err := row.Scan(&p.SyllabusID, &p.TopicID, &p.MasteryScore, &p.EaseFactor, &p.IntervalDays, &p.Repetitions, &p.NextReviewAt, &p.LastStudiedAt)
+		return p, err
+	})
+	if err != nil {
+		return LearningState{}, fmt.Errorf("scan learner progress: %w", err)
+	}
+
+	var (
+		streak     LearningStateStreak
+		lastActive *time.Time
+	)
+	err = s.pool.QueryRow(ctx, `
+		SELECT current_streak, longest_streak, last_active_date
+		FROM streaks
+		WHERE user_id = $1::uuid`, userID).Scan(&streak.Current, &streak.Longest, &lastActive)
+	switch {
+	case errors.Is(err, pgx.ErrNoRows):
+	case err != nil:
+		return LearningState{}, fmt.Errorf("query learner streak: %w", err)
+	default:
+		if lastActive != nil {
+			streak.LastActiveDate = lastActive.Format(time.DateOnly)
+		}
+		state.Streak = &streak
+	}
+
+	rows, err = s.pool.Query(ctx, `
+		SELECT source, amount, COALESCE(metadata, '{}'::jsonb), created_at
+		FROM xp_ledger
+		WHERE user_id = $1::uuid
+		ORDER BY created_at, id`, userID)
+	if err != nil {
+		return LearningState{}, fmt.Errorf("query learner xp: %w", err)
+	}
+	state.XP, err = pgx.CollectRows(rows, func(row pgx.CollectableRow) (LearningStateXPEntry, error) {
+		var (
+			entry LearningStateXPEntry
+			raw   []byte
+		)
+		if err := row.Scan(&entry.Source, &entry.Amount, &raw, &entry.CreatedAt); err != nil {
+			return entry, err
+		}
+		return entry, json.Unmarshal(raw, &entry.Metadata)
+	})
+	if err != nil {
+		return LearningState{}, fmt.Errorf("scan learner xp: %w", err)
+	}
+
+	rows, err = s.pool.Query(ctx, `
+		SELECT summary, topic_id, topic_name, syllabus_id, target_mastery, current_mastery, status,
+			created_at, completed_at
+		FROM goals
+		WHERE user_id = $1::uuid
+		ORDER BY created_at, id`, userID)
+	if err != nil {
+		return LearningState{}, fmt.Errorf("query learner goals: %w", err)
+	}
+	state.Goals, err = pgx.CollectRows(rows, func(row pgx.CollectableRow) (LearningStateGoal, error) {
+		var g LearningStateGoal
+		err := row.Scan(&g.Summary, &g.TopicID, &g.TopicName, &g.SyllabusID, &g.TargetMastery, &g.CurrentMastery, &g.Status, &g.CreatedAt, &g.CompletedAt)
+		return g, err
+	})
+	if err != nil {
+		return LearningState{}, fmt.Errorf("scan learner goals: %w", err)
+	}
+
+	var (
+		plan     LearningStateStudyPlan
+		examDate time.Time
+		rawWeeks []byte
+	)
+	err = s.pool.QueryRow(ctx, `
+		SELECT exam_date, form, weeks, created_at
+		FROM study_plans
+		WHERE user_id = $1::uuid`, userID).Scan(&examDate, &plan.Form, &rawWeeks, &plan.CreatedAt)
+	switch {
+	case errors.Is(err, pgx.ErrNoRows):
+	case err != nil:
+		return LearningState{}, fmt.Errorf("query learner study plan: %w", err)
+	default:
+		plan.ExamDate = examDate.Format(time.DateOnly)
+		if err := json.Unmarshal(rawWeeks, &plan.Weeks); err != nil {
+			return LearningState{}, fmt.Errorf("decode learner study plan: %w", err)
+		}
+		state.StudyPlan = &plan
+	}
+
+	return state, nil
+}
+
+// ImportLearningState writes state to the learner with the same channel and
+// external ID in the service's tenant, creating them if needed. The learner's
+// progress, streak, XP, goals and study plan are replaced by the imported
+// ones, so importing the same export twice is safe. Conversations are not
+// carried over.
+func (s *Service) ImportLearningState(state LearningState) (LearningStateImportResult, error) {
+	if s.allTenants {
+		return LearningStateImportResult{}, fmt.Errorf("%w: cannot import learning state without tenant scope", ErrInvalidArgument)
+	}
+	if err := validateLearningState(&state); err != nil {
+		return LearningStateImportResult{}, err
+	}
+	config, err := json.Marshal(state.Learner.Config)
+	if err != nil {
+		return LearningStateImportResult{}, fmt.Errorf("encode learner config: %w", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), learningStateTimeout)
+	defer cancel()
+
+	tx, err := s.pool.Begin(ctx)
+	if err != nil {
+		return LearningStateImportResult{}, fmt.Errorf("begin learning state import: %w", err)
+	}
+	defer func() {
+		_ = tx.Rollback(ctx)
+	}()
+
+	result := LearningStateImportResult{StudentID: state.Learner.ExternalID}
+	var userID string
+	err = tx.QueryRow(ctx, `
+		UPDATE users
+		SET name = $4, form = NULLIF($5, ''), config = COALESCE(config, '{}'::jsonb) || $6::jsonb, updated_at = NOW()
+		WHERE id = (
+			SELECT id FROM users
+			WHERE tenant_id = $1::uuid AND channel = $2 AND external_id = $3 AND role = 'student'
+			ORDER BY created_at ASC
+			LIMIT 1
+		)
+		RETURNING id::text`,
+		s.tenantID, state.Learner.Channel, state.Learner.ExternalID, state.Learner.Name, state.Learner.Form, config,
+	).Scan(&userID)
+	if errors.Is(err, pgx.ErrNoRows) {
+		result.Created = true
+		err = tx.QueryRow(ctx, `
+			INSERT INTO users (tenant_id, role, name, external_id, channel, form, config)
+			VALUES ($1::uuid, 'student', $4, $3, $2, NULLIF($5, ''), $6::jsonb)
+			RETURNING id::text`,
+			s.tenantID, state.Learner.Channel, state.Learner.ExternalID, state.Learner.Name, state.Learner.Form, config,
+		).Scan(&userID)
+	}
+	if err != nil {
+		return LearningStateImportResult{}, fmt.Errorf("upsert learner: %w", err)
+	}
+
+	for _, table := range []string{"learning_progress", "streaks", "xp_ledger", "goals", "study_plans"} {
+		if _, err := tx.Exec(ctx, "DELETE FROM "+table+" WHERE user_id = $1::uuid", userID); err != nil {
+			return LearningStateImportResult{}, fmt.Errorf("clear learner %s: %w", table, err)
+		}
+	}
+
+	for _, p := range state.Progress {
+		if _, err := tx.Exec(ctx, `
+			INSERT INTO learning_progress (user_id, tenant_id, syllabus_id, topic_id, mastery_score, ease_factor,
+				interval_days, repetitions, next_review_at, last_studied_at)
+			VALUES ($1::uuid, $2::uuid, $3, $4, $5, $6, $7, $8, $9, $10)`,
+			userID, s.tenantID, p.SyllabusID, p.TopicID, p.MasteryScore, p.EaseFactor, p.IntervalDays, p.Repetitions, p.NextReviewAt, p.LastStudiedAt,
+		); err != nil {
+			return LearningStateImportResult{}, fmt.Errorf("insert learner progress %s: %w", p.TopicID, err)
+		}
+	}
+	result.Topics = len(state.Progress)
+
+	if streak := state.Streak; streak != nil {
+		if _, err := tx.Exec(ctx, `
+			INSERT INTO streaks (user_id, tenant_id, current_streak, longest_streak, last_active_date)
+			VALUES ($1::uuid, $2::uuid, $3, $4, NULLIF($5, '')::date)`,
+			userID, s.tenantID, streak.Current, streak.Longest, streak.LastActiveDate,
+		); err != nil {
+			return LearningStateImportResult{}, fmt.Errorf("insert learner streak: %w", err)
+		}
+	}
+
+	for _, entry := range state.XP {
+		metadata, err := json.Marshal(entry.Metadata)
+		if err != nil {
+			return LearningStateImportResult{}, fmt.Errorf("encode xp metadata: %w", err)
+		}
+		if _, err := tx.Exec(ctx, `
+			INSERT INTO xp_ledger (user_id, tenant_id, source, amount, metadata, created_at)
+			VALUES ($1::uuid, $2::uuid, $3, $4, $5::jsonb, $6)`,
+			userID, s.tenantID, entry.Source, entry.Amount, metadata, entry.CreatedAt,
+		); err != nil {
+			return LearningStateImportResult{}, fmt.Errorf("insert learner xp: %w", err)
+		}
+	}
+	result.XPEntries = len(state.XP)
+
+	for _, g := range state.Goals {
+		if _, err := tx.Exec(ctx, `
+			INSERT INTO goals (user_id, tenant_id, summary, topic_id, topic_name, syllabus_id, target_mastery,
+				current_mastery, status, created_at, completed_at)
+			VALUES ($1::uuid, $2::uuid, $3, $4, $5, $6, $7, $8, $9, $10, $11)`,
+			userID, s.tenantID, g.Summary, g.TopicID, g.TopicName, g.SyllabusID, g.TargetMastery, g.CurrentMastery, g.Status, g.CreatedAt, g.CompletedAt,
+		); err != nil {
+			return LearningStateImportResult{}, fmt.Errorf("insert learner goal: %w", err)
+		}
+	}
+	result.Goals = len(state.Goals)
+
+	if plan := state.StudyPlan; plan != nil {
+		weeks, err := json.Marshal(plan.Weeks)
+		if err != nil {
+			return LearningStateImportResult{}, fmt.Errorf("encode study plan: %w", err)
+		}
+		if _, err := tx.Exec(ctx, `
+			INSERT INTO study_plans (user_id, tenant_id, exam_date, form, weeks, created_at)
+			VALUES ($1::uuid, $2::uuid, $3::date, $4, $5::jsonb, $6)`,
+			userID, s.tenantID, plan.ExamDate, plan.Form, weeks, plan.CreatedAt,
+		); err != nil {
+			return LearningStateImportResult{}, fmt.Errorf("insert learner study plan: %w", err)
+		}
+		result.StudyPlan = true
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return LearningStateImportResult{}, fmt.Errorf("commit learning state import: %w", err)
+	}
+	if s.onLearnerStateChanged != nil {
+		s.onLearnerStateChanged(ctx, s.tenantID, result.StudentID)
+	}
+	return result, nil
+}
+
+// OnLearnerStateChanged registers fn to run after an import rewrites a
+// learner's profile and progress, for dropping copies cached elsewhere such
+// as the engine's warm standby snapshot. It returns s for chaining onto a
+// constructor.
+func (s *Service) OnLearnerStateChanged(fn func(ctx context.Context, tenantID, studentID string)) *Service {
+	s.onLearnerStateChanged = fn
+	return s
+}
+
+// validateLearningState checks state against the database constraints
+// before anything is written, and fills in the name and timestamps a
+// hand-written state may leave out.
+func validateLearningState(state *LearningState) error {
+	if state.Version != LearningStateVersion {
+		return fmt.Errorf("%w: unsupported learning state version %d", ErrInvalidArgument, state.Version)
+	}
+	learner := &state.Learner
+	learner.ExternalID = strings.TrimSpace(learner.ExternalID)
+	learner.Channel = strings.TrimSpace(learner.Channel)
+	learner.Name = strings.TrimSpace(learner.Name)
+	if learner.ExternalID == "" || learner.Channel == "" {
+		return fmt.Errorf("%w: learner external_id and channel are required", ErrInvalidArgument)
+	}
+	if learner.Name == "" {
+		learner.Name = "Student " + learner.ExternalID
+	}
+	if learner.Config == nil {
+		learner.Config = map[string]any{}
+	}
+
+	now := time.Now().UTC()
+	seen := make(map[string]bool, len(state.Progress))
+	for _, p := range state.Progress {
+		if p.SyllabusID == "" || p.TopicID == "" {
+			return fmt.Errorf("%w: progress needs syllabus_id and topic_id", ErrInvalidArgument)
+		}
+		if p.MasteryScore < 0 || p.MasteryScore > 1 {
+			return fmt.Errorf("%w: mastery for %s must be between 0 and 1", ErrInvalidArgument, p.TopicID)
+		}
+		key := p.SyllabusID + "/" + p.TopicID
+		if seen[key] {
+			return fmt.Errorf("%w: duplicate progress for %s", ErrInvalidArgument, key)
+		}
+		seen[key] = true
+	}
+	if streak := state.Streak; streak != nil && streak.LastActiveDate != "" {
+		if _, err := time.Parse(time.DateOnly, streak.LastActiveDate); err != nil {
+			return fmt.Errorf("%w: streak last_active_date must be YYYY-MM-DD", ErrInvalidArgument)
+		}
+	}
+	for i := range state.XP {
+		if state.XP[i].Source == "" {
+			return fmt.Errorf("%w: xp entries need a source", ErrInvalidArgument)
+		}
+		if state.XP[i].CreatedAt.IsZero() {
+			state.XP[i].CreatedAt = now
+		}
+	}
+	for i := range state.Goals {
+		g := &state.Goals[i]
+		if g.Summary == "" || g.TopicID == "" {
+			return fmt.Errorf("%w: goals need a summary and topic_id", ErrInvalidArgument)
+		}
+		if g.TargetMastery < 0 || g.TargetMastery > 1 || g.CurrentMastery < 0 || g.CurrentMastery > 1 {
+			return fmt.Errorf("%w: goal mastery must be between 0 and 1", ErrInvalidArgument)
+		}
+		if !slices.Contains(learningStateGoalStatuses, g.Status) {
+			return fmt.Errorf("%w: goal status must be active, completed or archived", ErrInvalidArgument)
+		}
+		if g.CreatedAt.IsZero() {
+			g.CreatedAt = now
+		}
+	}
+	if plan := state.StudyPlan; plan != nil {
+		if _, err := time.Parse(time.DateOnly, plan.ExamDate); err != nil {
+			return fmt.Errorf("%w: study plan exam_date must be YYYY-MM-DD", ErrInvalidArgument)
+		}
+		if plan.Weeks == nil {
+			plan.Weeks = []map[string]any{}
+		}
+		if plan.CreatedAt.IsZero() {
+			plan.CreatedAt = now
+		}
+	}
+	return nil
+}
@@ -0,0 +1,66 @@
+// Copyright 2026 the P&AI authors. All rights reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package adminapi
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestImportLearningStateRejectsInvalidState(t *testing.T) {
+	valid := func() LearningState {
+		return LearningState{
+			Version: LearningStateVersion,
+			Learner: LearningStateLearner{ExternalID: "12345", Channel: "telegram"},
+		}
+	}
+	tests := []struct {
+		name   string
+		svc    *Service
+		mutate func(*LearningState)
+	}{
+		{name: "no tenant scope", svc: &Service{allTenants: true}, mutate: func(*LearningState) {}},
+		{name: "unknown version", svc: &Service{tenantID: "tenant-abc"}, mutate: func(s *LearningState) { s.Version = 2 }},
+		{name: "missing external id", svc: &Service{tenantID: "tenant-abc"}, mutate: func(s *LearningState) { s.Learner.ExternalID = " " }},
+		{name: "mastery out of range", svc: &Service{tenantID: "tenant-abc"}, mutate: func(s *LearningState) {
+			s.Progress = []LearningStateProgress{{SyllabusID: "kssm-f1", TopicID: "F1-02", MasteryScore: 1.5}}
+		}},
+		{name: "duplicate topic", svc: &Service{tenantID: "tenant-abc"}, mutate: func(s *LearningState) {
+			s.Progress = []LearningStateProgress{{SyllabusID: "kssm-f1", TopicID: "F1-02"}, {SyllabusID: "kssm-f1", TopicID: "F1-02"}}
+		}},
+		{name: "bad goal status", svc: &Service{tenantID: "tenant-abc"}, mutate: func(s *LearningState) {
+			s.Goals = []LearningStateGoal{{Summary: "Master equations", TopicID: "F1-02", TargetMastery: 0.8, Status: "paused"}}
+		}},
+		{name: "bad exam date", svc: &Service{tenantID: "tenant-abc"}, mutate: func(s *LearningState) {
+			s.StudyPlan = &LearningStateStudyPlan{ExamDate: "next month"}
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			state := valid()
+			tt.mutate(&state)
+			if _, err := tt.svc.ImportLearningState(state); !errors.Is(err, ErrInvalidArgument) {
+				t.Fatalf("ImportLearningState() error = %v, want ErrInvalidArgument", err)
+			}
+		})
+	}
+}
+
+func TestValidateLearningStateFillsDefaults(t *testing.T) {
+	state := LearningState{
+		Version:   LearningStateVersion,
+		Learner:   LearningStateLearner{ExternalID: " 12345 ", Channel: "telegram"},
+		XP:        []LearningStateXPEntry{{Source: "quiz", Amount: 40}},
+		StudyPlan: &LearningStateStudyPlan{ExamDate: "2026-11-02"},
+	}
+	if err := validateLearningState(&state); err != nil {
+		t.Fatalf("validateLearningState() error = %v", err)
+	}
+	if state.Learner.ExternalID != "12345" || state.Learner.Name != "Student 12345" || state.Learner.Config == nil {
+		t.Fatalf("learner = %+v, want trimmed ID, default name and empty config", state.Learner)
+	}
+	if state.XP[0].CreatedAt.IsZero() || state.StudyPlan.CreatedAt.IsZero() || state.StudyPlan.Weeks == nil {
+		t.Fatalf("xp = %+v, plan = %+v, want timestamps and weeks filled in", state.XP, state.StudyPlan)
+	}
+}
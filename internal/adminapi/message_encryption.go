@@ -0,0 +1,44 @@
+// Copyright 2026 the P&AI authors. All rights reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package adminapi
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/p-n-ai/pai-bot/internal/platform/msgcrypt"
+)
+
+// WithMessageKeyring lets the service read message content the conversation
+// store encrypted at rest. It returns s for chaining onto a constructor.
+func (s *Service) WithMessageKeyring(keys *msgcrypt.Keyring) *Service {
+	s.keys = keys
+	return s
+}
+
+// openMessage decrypts a message row's content and its attachments column,
+// which holds a sealed JSON string when keyID is set. tenantID and messageID
+// are the row's own, which the values were sealed for.
+func (s *Service) openMessage(tenantID, messageID, keyID, content string, attachments []byte) (string, []byte, error) {
+	if keyID == "" {
+		return content, attachments, nil
+	}
+	binding := msgcrypt.Binding(tenantID, messageID)
+	text, err := s.keys.Open(keyID, content, binding)
+	if err != nil {
+		return "", nil, err
+	}
+	if len(attachments) == 0 {
+		return text, nil, nil
+	}
+	var sealed string
+	if err := json.Unmarshal(attachments, &sealed); err != nil {
+		return "", nil, fmt.Errorf("decode sealed attachments: %w", err)
+	}
+	plain, err := s.keys.Open(keyID, sealed, binding)
+	if err != nil {
+		return "", nil, err
+	}
+	return text, []byte(plain), nil
+}
@@ -0,0 +1,150 @@
+// Copyright 2026 the P&AI authors. All rights reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package adminapi
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"strings"
+	"time"
+
+	"github.com/p-n-ai/pai-bot/internal/platform/msgcrypt"
+	"github.com/p-n-ai/pai-bot/internal/platform/textsearch"
+)
+
+const (
+	defaultMessageSearchLimit = 20
+	maxMessageSearchLimit     = 100
+	// sealedMessageSearchScan caps how many of the latest messages are
+	// decrypted per search when content is encrypted at rest; the full-text
+	// index only sees ciphertext then.
+	sealedMessageSearchScan = 5000
+)
+
+// MessageSearchHit is one message matching an admin history search.
+type MessageSearchHit struct {
+	ConversationID string    `json:"conversation_id"`
+	MessageID      string    `json:"message_id"`
+	StudentID      string    `json:"student_id"`
+	StudentName    string    `json:"student_name"`
+	Role           string    `json:"role"`
+	Text           string    `json:"text"`
+	Timestamp      time.Time `json:"timestamp"`
+}
+
+// SearchMessages full-text searches hot message history in the tenant, best
+// match first. studentID narrows the search to one student; archived
+// conversations are not searched.
+func (s *Service) SearchMessages(query, studentID string, limit int) ([]MessageSearchHit, error) {
+	tsQuery := textsearch.PrefixTSQuery(query)
+	if tsQuery == "" {
+		return nil, fmt.Errorf("%w: search query is required", ErrInvalidArgument)
+	}
+	if limit <= 0 {
+		limit = defaultMessageSearchLimit
+	}
+	limit = min(limit, maxMessageSearchLimit)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if s.keys != nil {
+		return s.searchSealedMessages(ctx, textsearch.Words(query), strings.TrimSpace(studentID), limit)
+	}
+
+	rows, err := s.pool.Query(ctx, fmt.Sprintf(`
+		SELECT
+			m.conversation_id::text,
+			m.id::text,
+			COALESCE(NULLIF(u.external_id, ''), u.id::text),
+			u.name,
+			CASE WHEN m.role = 'user' THEN 'student' ELSE m.role END,
+			m.content,
+			m.created_at
+		FROM messages m
+		JOIN conversations c ON c.id = m.conversation_id
+		JOIN users u ON u.id = c.user_id
+		WHERE %s
+			AND m.deleted_at IS NULL
+			AND m.search_vector @@ to_tsquery('simple', $2)
+			AND ($3 = '' OR COALESCE(NULLIF(u.external_id, ''), u.id::text) = $3)
+		ORDER BY ts_rank(m.search_vector, to_tsquery('simple', $2)) DESC, m.created_at DESC
+		LIMIT $4
+	`, s.tenantPredicate("m.tenant_id", 1)), s.tenantArg(), tsQuery, strings.TrimSpace(studentID), limit)
+	if err != nil {
+		return nil, fmt.Errorf("search messages: %w", err)
+	}
+	defer rows.Close()
+
+	hits := []MessageSearchHit{}
+	for rows.Next() {
+		var hit MessageSearchHit
+		if err := rows.Scan(&hit.ConversationID, &hit.MessageID, &hit.StudentID, &hit.StudentName, &hit.Role, &hit.Text, &hit.Timestamp); err != nil {
+			return nil, fmt.Errorf("scan message search hit: %w", err)
+		}
+		hits = append(hits, hit)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterate message search hits: %w", err)
+	}
+	return hits, nil
+}
+
+// searchSealedMessages decrypts the tenant's latest messages and keeps those
+// containing every query word, newest first.
+func (s *Service) searchSealedMessages(ctx context.Context, words []string, studentID string, limit int) ([]MessageSearchHit, error) {
+	rows, err := s.pool.Query(ctx, fmt.Sprintf(`
+		SELECT
+			m.conversation_id::text,
+			m.id::text,
+			COALESCE(NULLIF(u.external_id, ''), u.id::text),
+			u.name,
+			CASE WHEN m.role = 'user' THEN 'student' ELSE m.role END,
+			m.content,
+			m.created_at,
+			COALESCE(m.content_key_id, ''),
+			m.tenant_id::text
+		FROM messages m
+		JOIN conversations c ON c.id = m.conversation_id
+		JOIN users u ON u.id = c.user_id
+		WHERE %s
+			AND m.deleted_at IS NULL
+			AND ($2 = '' OR COALESCE(NULLIF(u.external_id, ''), u.id::text) = $2)
+		ORDER BY m.created_at DESC
+		LIMIT $3
+	`, s.tenantPredicate("m.tenant_id", 1)), s.tenantArg(), studentID, sealedMessageSearchScan)
+	if err != nil {
+		return nil, fmt.Errorf("search messages: %w", err)
+	}
+	defer rows.Close()
+
+	hits := []MessageSearchHit{}
+	scanned := 0
+	for rows.Next() {
+		scanned++
+		var hit MessageSearchHit
+		var keyID, tenantID string
+		if err := rows.Scan(&hit.ConversationID, &hit.MessageID, &hit.StudentID, &hit.StudentName, &hit.Role, &hit.Text, &hit.Timestamp, &keyID, &tenantID); err != nil {
+			return nil, fmt.Errorf("scan message search hit: %w", err)
+		}
+		if hit.Text, err = s.keys.Open(keyID, hit.Text, msgcrypt.Binding(tenantID, hit.MessageID)); err != nil {
+			return nil, fmt.Errorf("decrypt message %s: %w", hit.MessageID, err)
+		}
+		if !textsearch.ContainsAll(hit.Text, words) {
+			continue
+		}
+		hits = append(hits, hit)
+		if len(hits) == limit {
+			break
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterate message search hits: %w", err)
+	}
+	if scanned == sealedMessageSearchScan && len(hits) < limit {
+		slog.Warn("sealed admin message search hit its scan cap; older messages were not searched",
+			"student_id", studentID, "scan_limit", sealedMessageSearchScan, "hits", len(hits))
+	}
+	return hits, nil
+}
@@ -0,0 +1,16 @@
+// Copyright 2026 the P&AI authors. All rights reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package adminapi
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestSearchMessagesRejectsEmptyQuery(t *testing.T) {
+	svc := &Service{}
+	if _, err := svc.SearchMessages("  ?? ", "", 10); !errors.Is(err, ErrInvalidArgument) {
+		t.Fatalf("SearchMessages() error = %v, want ErrInvalidArgument", err)
+	}
+}
@@ -0,0 +1,141 @@
+// Copyright 2026 the P&AI authors. All rights reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package adminapi
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+
+	"github.com/p-n-ai/pai-bot/internal/i18n"
+)
+
+// MessageTemplate is a tenant's replacement copy for one outbound message in
+// one locale.
+type MessageTemplate struct {
+	TenantID  string    `json:"tenant_id"`
+	Key       string    `json:"key"`
+	Locale    string    `json:"locale"`
+	Body      string    `json:"body"`
+	UpdatedBy string    `json:"updated_by,omitempty"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// MessageTemplateKey describes a message a tenant may override.
+type MessageTemplateKey struct {
+	Key       string   `json:"key"`
+	Variables []string `json:"variables"`
+}
+
+// MessageTemplateView lists the overridable messages and the tenant's
+// current overrides.
+type MessageTemplateView struct {
+	Keys      []MessageTemplateKey `json:"keys"`
+	Templates []MessageTemplate    `json:"templates"`
+}
+
+const messageTemplateColumns = `tenant_id::text, key, locale, body, COALESCE(updated_by::text, ''), updated_at`
+
+// ListMessageTemplates returns the overridable messages and every override
+// saved in the tenant.
+func (s *Service) ListMessageTemplates() (MessageTemplateView, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	rows, err := s.pool.Query(ctx, fmt.Sprintf(`
+		SELECT %s
+		FROM message_templates
+		WHERE %s
+		ORDER BY tenant_id, key, locale`,
+		messageTemplateColumns, s.tenantPredicate("tenant_id", 1)), s.tenantArg())
+	if err != nil {
+		return MessageTemplateView{}, fmt.Errorf("list message templates: %w", err)
+	}
+	templates, err := collectMessageTemplates(rows)
+	if err != nil {
+		return MessageTemplateView{}, fmt.Errorf("list message templates: %w", err)
+	}
+	view := MessageTemplateView{Keys: []MessageTemplateKey{}, Templates: templates}
+	for _, key := range i18n.TemplateKeys() {
+		vars, _ := i18n.TemplateVars(key)
+		view.Keys = append(view.Keys, MessageTemplateKey{Key: string(key), Variables: vars})
+	}
+	return view, nil
+}
+
+// SaveMessageTemplate validates body and stores it as the tenant's copy of
+// key in locale, replacing any earlier override.
+func (s *Service) SaveMessageTemplate(key, locale, body, updatedByUserID string) (MessageTemplate, error) {
+	if s.allTenants {
+		return MessageTemplate{}, fmt.Errorf("%w: cannot save message templates without tenant scope", ErrInvalidArgument)
+	}
+	if err := i18n.ValidateTemplate(i18n.Key(key), locale, body); err != nil {
+		return MessageTemplate{}, fmt.Errorf("%w: %v", ErrInvalidArgument, err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	rows, err := s.pool.Query(ctx, `
+		INSERT INTO message_templates (tenant_id, key, locale, body, updated_by)
+		VALUES ($1::uuid, $2, $3, $4, NULLIF($5, '')::uuid)
+		ON CONFLICT (tenant_id, key, locale) DO UPDATE
+		SET body = EXCLUDED.body, updated_by = EXCLUDED.updated_by, updated_at = NOW()
+		RETURNING `+messageTemplateColumns,
+		s.tenantID, key, locale, body, updatedByUserID)
+	if err != nil {
+		return MessageTemplate{}, fmt.Errorf("save message template: %w", err)
+	}
+	templates, err := collectMessageTemplates(rows)
+	if err != nil {
+		return MessageTemplate{}, fmt.Errorf("save message template: %w", err)
+	}
+	if len(templates) != 1 {
+		return MessageTemplate{}, fmt.Errorf("save message template: got %d rows", len(templates))
+	}
+	return templates[0], nil
+}
+
+// DeleteMessageTemplate removes the tenant's copy of key in locale so the
+// built-in message is sent again, and returns the removed override.
+func (s *Service) DeleteMessageTemplate(key, locale string) (MessageTemplate, error) {
+	if s.allTenants {
+		return MessageTemplate{}, fmt.Errorf("%w: cannot delete message templates without tenant scope", ErrInvalidArgument)
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	rows, err := s.pool.Query(ctx, `
+		DELETE FROM message_templates
+		WHERE tenant_id = $1::uuid AND key = $2 AND locale = $3
+		RETURNING `+messageTemplateColumns,
+		s.tenantID, key, locale)
+	if err != nil {
+		return MessageTemplate{}, fmt.Errorf("delete message template: %w", err)
+	}
+	templates, err := collectMessageTemplates(rows)
+	if err != nil {
+		return MessageTemplate{}, fmt.Errorf("delete message template: %w", err)
+	}
+	if len(templates) == 0 {
+		return MessageTemplate{}, ErrNotFound
+	}
+	return templates[0], nil
+}
+
+func collectMessageTemplates(rows pgx.Rows) ([]MessageTemplate, error) {
+	defer rows.Close()
+	templates := []MessageTemplate{}
+	for rows.Next() {
+		var tmpl MessageTemplate
+		if err := rows.Scan(&tmpl.TenantID, &tmpl.Key, &tmpl.Locale, &tmpl.Body, &tmpl.UpdatedBy, &tmpl.UpdatedAt); err != nil {
+			return nil, err
+		}
+		tmpl.UpdatedAt = tmpl.UpdatedAt.UTC()
+		templates = append(templates, tmpl)
+	}
+	return templates, rows.Err()
+}
@@ -0,0 +1,39 @@
+// Copyright 2026 the P&AI authors. All rights reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package adminapi
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestSaveMessageTemplateRequiresTenantScope(t *testing.T) {
+	svc := &Service{allTenants: true}
+	if _, err := svc.SaveMessageTemplate("start_onboarding_form", "en", "Hi {{name}}!", ""); !errors.Is(err, ErrInvalidArgument) {
+		t.Fatalf("SaveMessageTemplate() error = %v, want ErrInvalidArgument", err)
+	}
+}
+
+func TestSaveMessageTemplateRejectsInvalidTemplates(t *testing.T) {
+	svc := &Service{tenantID: "tenant-abc"}
+	tests := []struct {
+		name   string
+		key    string
+		locale string
+		body   string
+	}{
+		{name: "not overridable", key: "technical_issue", locale: "en", body: "Oops."},
+		{name: "unknown locale", key: "start_onboarding_form", locale: "fr", body: "Salut {{name}}!"},
+		{name: "unknown variable", key: "start_onboarding_form", locale: "en", body: "Hi {{first_name}}!"},
+		{name: "malformed variable", key: "start_onboarding_form", locale: "en", body: "Hi {{name}!"},
+		{name: "blank body", key: "review_reminder", locale: "ms", body: "  "},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := svc.SaveMessageTemplate(tt.key, tt.locale, tt.body, ""); !errors.Is(err, ErrInvalidArgument) {
+				t.Fatalf("SaveMessageTemplate() error = %v, want ErrInvalidArgument", err)
+			}
+		})
+	}
+}
@@ -14,10 +14,13 @@ import (
 
 	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgxpool"
+
+	"github.com/p-n-ai/pai-bot/internal/apperr"
+	"github.com/p-n-ai/pai-bot/internal/platform/msgcrypt"
 )
 
-var ErrNotFound = errors.New("admin resource not found")
-var ErrInvalidArgument = errors.New("admin invalid argument")
+var ErrNotFound = apperr.UserInput(errors.New("admin resource not found"))
+var ErrInvalidArgument = apperr.UserInput(errors.New("admin invalid argument"))
 
 type Student struct {
 	ID         string    `json:"id"`
@@ -164,6 +167,36 @@ type NudgeRateSummary struct {
 	ResponseRate           float64 `json:"response_rate"`
 }
 
+// ChurnSummary counts learners lost to blocked or deactivated chats. Churned
+// and Reactivated cover the report window; Blocked and Inactive are current.
+type ChurnSummary struct {
+	ChurnedUsers     int `json:"churned_users"`
+	ReactivatedUsers int `json:"reactivated_users"`
+	BlockedUsers     int `json:"blocked_users"`
+	InactiveUsers    int `json:"inactive_users"`
+}
+
+// ReferralFunnel follows /invite links through the report window: learners
+// who asked for their link, new learners who started through one, and those
+// who went on to finish onboarding.
+type ReferralFunnel struct {
+	Sharers        int     `json:"sharers"`
+	Signups        int     `json:"signups"`
+	Activated      int     `json:"activated"`
+	ActivationRate float64 `json:"activation_rate"`
+}
+
+// DailyProblemParticipation counts problem of the day pushes through the
+// report window and how many were answered, first answers only.
+type DailyProblemParticipation struct {
+	Subscribers int     `json:"subscribers"`
+	Sent        int     `json:"sent"`
+	Answered    int     `json:"answered"`
+	Correct     int     `json:"correct"`
+	AnswerRate  float64 `json:"answer_rate"`
+	CorrectRate float64 `json:"correct_rate"`
+}
+
 type MetricsSummary struct {
 	WindowDays       int                     `json:"window_days"`
 	DailyActiveUsers []DailyActiveUsersPoint `json:"daily_active_users"`
@@ -186,14 +219,17 @@ type AnalyticsOverview struct {
 }
 
 type AnalyticsReport struct {
-	WindowDays       int                     `json:"window_days"`
-	GeneratedAt      time.Time               `json:"generated_at"`
-	Overview         AnalyticsOverview       `json:"overview"`
-	DailyActiveUsers []DailyActiveUsersPoint `json:"daily_active_users"`
-	Retention        []RetentionPoint        `json:"retention"`
-	NudgeRate        NudgeRateSummary        `json:"nudge_rate"`
-	AIUsage          AIUsageSummary          `json:"ai_usage"`
-	ABComparison     any                     `json:"ab_comparison"`
+	WindowDays       int                       `json:"window_days"`
+	GeneratedAt      time.Time                 `json:"generated_at"`
+	Overview         AnalyticsOverview         `json:"overview"`
+	DailyActiveUsers []DailyActiveUsersPoint   `json:"daily_active_users"`
+	Retention        []RetentionPoint          `json:"retention"`
+	NudgeRate        NudgeRateSummary          `json:"nudge_rate"`
+	Churn            ChurnSummary              `json:"churn"`
+	Referrals        ReferralFunnel            `json:"referrals"`
+	DailyProblems    DailyProblemParticipation `json:"daily_problems"`
+	AIUsage          AIUsageSummary            `json:"ai_usage"`
+	ABComparison     any                       `json:"ab_comparison"`
 }
 
 type ClassStudent struct {
@@ -205,6 +241,9 @@ type ClassStudent struct {
 type ClassProgress struct {
 	Students []ClassStudent `json:"students"`
 	TopicIDs []string       `json:"topic_ids"`
+
+	// QuizLeaderboard is set only for classes that opted into it.
+	QuizLeaderboard []ClassQuizLeaderboardEntry `json:"quiz_leaderboard,omitempty"`
 }
 
 type UserManagementSummary struct {
@@ -265,11 +304,20 @@ type StudentExportRow struct {
 	CreatedAt      time.Time
 }
 
+// MessageAttachment references non-text content stored with a message.
+type MessageAttachment struct {
+	Type      string `json:"type"`
+	Reference string `json:"reference"`
+	Caption   string `json:"caption,omitempty"`
+}
+
 type ConversationExportMessage struct {
-	MessageID string    `json:"message_id"`
-	Role      string    `json:"role"`
-	Content   string    `json:"content"`
-	CreatedAt time.Time `json:"created_at"`
+	MessageID   string              `json:"message_id"`
+	Role        string              `json:"role"`
+	Content     string              `json:"content"`
+	Kind        string              `json:"kind"`
+	Attachments []MessageAttachment `json:"attachments,omitempty"`
+	CreatedAt   time.Time           `json:"created_at"`
 }
 
 type ConversationExportRecord struct {
@@ -296,6 +344,10 @@ type Service struct {
 	pool       *pgxpool.Pool
 	tenantID   string
 	allTenants bool
+	keys       *msgcrypt.Keyring
+	// onLearnerStateChanged runs after an import rewrites a learner's
+	// state; see OnLearnerStateChanged.
+	onLearnerStateChanged func(ctx context.Context, tenantID, studentID string)
 }
 
 type tokenBudgetWindow struct {
@@ -342,6 +394,20 @@ func (s *Service) tenantArg() any {
 	return s.tenantID
 }
 
+// notSandboxUser keeps staff sandbox learners out of analytics; column holds
+// a users.id.
+func notSandboxUser(column string) string {
+	return fmt.Sprintf("NOT EXISTS (SELECT 1 FROM users sandbox_user WHERE sandbox_user.id = %s AND sandbox_user.sandbox)", column)
+}
+
+// notSandboxConversation is notSandboxUser for a conversations.id column.
+func notSandboxConversation(column string) string {
+	return fmt.Sprintf(`NOT EXISTS (
+			SELECT 1 FROM conversations sandbox_conv
+			JOIN users sandbox_user ON sandbox_user.id = sandbox_conv.user_id
+			WHERE sandbox_conv.id = %s AND sandbox_user.sandbox)`, column)
+}
+
 func (s *Service) GetClassProgress(classID string) (ClassProgress, error) {
 	// If classID looks like a UUID, use real group membership.
 	if looksLikeUUID(classID) {
@@ -520,7 +586,9 @@ func (s *Service) GetStudentConversations(studentID string) ([]StudentConversati
 			m.id::text,
 			m.created_at,
 			CASE WHEN m.role = 'user' THEN 'student' ELSE m.role END AS role,
-			m.content
+			m.content,
+			COALESCE(m.content_key_id, ''),
+			m.tenant_id::text
 		FROM messages m
 		JOIN conversations c ON c.id = m.conversation_id
 		JOIN users u ON u.id = c.user_id
@@ -538,9 +606,13 @@ func (s *Service) GetStudentConversations(studentID string) ([]StudentConversati
 	var conversations []StudentConversation
 	for rows.Next() {
 		var item StudentConversation
-		if err := rows.Scan(&item.ID, &item.Timestamp, &item.Role, &item.Text); err != nil {
+		var keyID, tenantID string
+		if err := rows.Scan(&item.ID, &item.Timestamp, &item.Role, &item.Text, &keyID, &tenantID); err != nil {
 			return nil, fmt.Errorf("scan student conversation: %w", err)
 		}
+		if item.Text, err = s.keys.Open(keyID, item.Text, msgcrypt.Binding(tenantID, item.ID)); err != nil {
+			return nil, fmt.Errorf("decrypt student conversation message %s: %w", item.ID, err)
+		}
 		conversations = append(conversations, item)
 	}
 	if err := rows.Err(); err != nil {
@@ -705,9 +777,10 @@ func (s *Service) GetAIUsage() (AIUsageSummary, error) {
 		WHERE %s
 			AND model IS NOT NULL
 			AND model <> ''
+			AND %s
 		GROUP BY model
 		ORDER BY COUNT(*) DESC, model ASC
-	`, s.tenantPredicate("tenant_id", 1)), s.tenantArg())
+	`, s.tenantPredicate("tenant_id", 1), notSandboxConversation("messages.conversation_id")), s.tenantArg())
 	if err != nil {
 		return AIUsageSummary{}, fmt.Errorf("query ai usage: %w", err)
 	}
@@ -741,9 +814,10 @@ func (s *Service) GetAIUsage() (AIUsageSummary, error) {
 			AND m.model IS NOT NULL
 			AND m.model <> ''
 			AND m.created_at >= NOW() - INTERVAL '7 day'
+			AND %s
 		GROUP BY usage_date
 		ORDER BY usage_date ASC
-	`, s.tenantPredicate("m.tenant_id", 1)), s.tenantArg())
+	`, s.tenantPredicate("m.tenant_id", 1), notSandboxConversation("m.conversation_id")), s.tenantArg())
 	if err != nil {
 		return AIUsageSummary{}, fmt.Errorf("query ai usage daily trend: %w", err)
 	}
@@ -772,12 +846,19 @@ func (s *Service) GetAIUsage() (AIUsageSummary, error) {
 			AND m.model IS NOT NULL
 			AND m.model <> ''
 			AND u.role = 'student'
+			AND NOT u.sandbox
 	`, s.tenantPredicate("m.tenant_id", 1)), s.tenantArg()).Scan(&activeLearners); err != nil {
 		return AIUsageSummary{}, fmt.Errorf("query ai usage active learners: %w", err)
 	}
 
 	finalizeAIUsageSummary(&summary, activeLearners)
 
+	costs, err := s.loadAICosts(ctx)
+	if err != nil {
+		return AIUsageSummary{}, err
+	}
+	applyAICosts(&summary, costs)
+
 	window, err := s.loadActiveTokenBudgetWindow(ctx)
 	if err != nil {
 		return AIUsageSummary{}, err
@@ -902,6 +983,18 @@ func (s *Service) GetAnalyticsReport() (AnalyticsReport, error) {
 	if err != nil {
 		return AnalyticsReport{}, err
 	}
+	churn, err := s.loadChurn(ctx, reportWindowDays)
+	if err != nil {
+		return AnalyticsReport{}, err
+	}
+	referrals, err := s.loadReferralFunnel(ctx, reportWindowDays)
+	if err != nil {
+		return AnalyticsReport{}, err
+	}
+	dailyProblems, err := s.loadDailyProblemParticipation(ctx, reportWindowDays)
+	if err != nil {
+		return AnalyticsReport{}, err
+	}
 	aiUsage, err := s.GetAIUsage()
 	if err != nil {
 		return AnalyticsReport{}, err
@@ -914,6 +1007,9 @@ func (s *Service) GetAnalyticsReport() (AnalyticsReport, error) {
 		DailyActiveUsers: daily,
 		Retention:        retention,
 		NudgeRate:        nudgeRate,
+		Churn:            churn,
+		Referrals:        referrals,
+		DailyProblems:    dailyProblems,
 		AIUsage:          aiUsage,
 		ABComparison:     nil,
 	}, nil
@@ -1122,12 +1218,17 @@ func (s *Service) ExportConversations() ([]ConversationExportRecord, error) {
 			m.id::text,
 			CASE WHEN m.role = 'user' THEN 'student' ELSE m.role END AS role,
 			COALESCE(m.content, ''),
-			m.created_at
+			COALESCE(m.content_kind, 'text'),
+			m.attachments,
+			m.created_at,
+			COALESCE(m.content_key_id, ''),
+			COALESCE(m.tenant_id::text, '')
 		FROM conversations c
 		JOIN users u ON u.id = c.user_id
 		LEFT JOIN messages m
 			ON m.conversation_id = c.id
 			AND m.role IN ('user', 'assistant')
+			AND m.deleted_at IS NULL
 		WHERE %s
 			AND u.role = 'student'
 		ORDER BY c.started_at ASC, c.id ASC, m.created_at ASC, m.id ASC
@@ -1149,7 +1250,11 @@ func (s *Service) ExportConversations() ([]ConversationExportRecord, error) {
 			messageID      *string
 			role           *string
 			content        *string
+			kind           string
+			attachments    []byte
 			messageAt      *time.Time
+			keyID          string
+			tenantID       string
 		)
 		if err := rows.Scan(
 			&conversationID,
@@ -1160,7 +1265,11 @@ func (s *Service) ExportConversations() ([]ConversationExportRecord, error) {
 			&messageID,
 			&role,
 			&content,
+			&kind,
+			&attachments,
 			&messageAt,
+			&keyID,
+			&tenantID,
 		); err != nil {
 			return nil, fmt.Errorf("scan conversation export: %w", err)
 		}
@@ -1179,12 +1288,23 @@ func (s *Service) ExportConversations() ([]ConversationExportRecord, error) {
 		}
 
 		if messageID != nil && role != nil && content != nil && messageAt != nil {
-			record.Messages = append(record.Messages, ConversationExportMessage{
+			text, attachments, err := s.openMessage(tenantID, *messageID, keyID, *content, attachments)
+			if err != nil {
+				return nil, fmt.Errorf("decrypt export message %s: %w", *messageID, err)
+			}
+			message := ConversationExportMessage{
 				MessageID: *messageID,
 				Role:      *role,
-				Content:   *content,
+				Content:   text,
+				Kind:      kind,
 				CreatedAt: *messageAt,
-			})
+			}
+			if len(attachments) > 0 {
+				if err := json.Unmarshal(attachments, &message.Attachments); err != nil {
+					return nil, fmt.Errorf("decode export message attachments: %w", err)
+				}
+			}
+			record.Messages = append(record.Messages, message)
 		}
 	}
 	if err := rows.Err(); err != nil {
@@ -1321,9 +1441,10 @@ func (s *Service) loadDailyActiveUsers(ctx context.Context, days int) ([]DailyAc
 		SELECT ds.activity_date, COUNT(DISTINCT a.user_id)
 		FROM day_series ds
 		LEFT JOIN activity a ON a.activity_date = ds.activity_date
+			AND %s
 		GROUP BY ds.activity_date
 		ORDER BY ds.activity_date ASC
-	`, s.tenantPredicate("e.tenant_id", 1), s.tenantPredicate("c.tenant_id", 1)), s.tenantArg(), days)
+	`, s.tenantPredicate("e.tenant_id", 1), s.tenantPredicate("c.tenant_id", 1), notSandboxUser("a.user_id")), s.tenantArg(), days)
 	if err != nil {
 		return nil, fmt.Errorf("query daily active users: %w", err)
 	}
@@ -1356,6 +1477,7 @@ func (s *Service) loadRetention(ctx context.Context) ([]RetentionPoint, error) {
 			FROM users u
 			WHERE %s
 				AND u.role = 'student'
+				AND NOT u.sandbox
 				AND u.created_at < DATE(NOW() AT TIME ZONE 'UTC') AT TIME ZONE 'UTC'
 		),
 		activity AS (
@@ -1423,6 +1545,7 @@ func (s *Service) loadNudgeRate(ctx context.Context, days int) (NudgeRateSummary
 			FROM nudge_log nl
 			WHERE %s
 				AND nl.sent_at >= NOW() - make_interval(days => $2::int)
+				AND %s
 		)
 		SELECT
 			COUNT(*) AS nudges_sent,
@@ -1447,7 +1570,7 @@ func (s *Service) loadNudgeRate(ctx context.Context, days int) (NudgeRateSummary
 				) responses
 			)) AS responses_within_24h
 		FROM nudges
-	`, s.tenantPredicate("nl.tenant_id", 1), s.tenantPredicate("e.tenant_id", 1), s.tenantPredicate("c.tenant_id", 1)), s.tenantArg(), days, s.tenantArg(), s.tenantArg()).Scan(&nudgesSent, &responses)
+	`, s.tenantPredicate("nl.tenant_id", 1), notSandboxUser("nl.user_id"), s.tenantPredicate("e.tenant_id", 1), s.tenantPredicate("c.tenant_id", 1)), s.tenantArg(), days, s.tenantArg(), s.tenantArg()).Scan(&nudgesSent, &responses)
 	if err != nil {
 		return NudgeRateSummary{}, fmt.Errorf("query nudge rate: %w", err)
 	}
@@ -1455,6 +1578,76 @@ func (s *Service) loadNudgeRate(ctx context.Context, days int) (NudgeRateSummary
 	return buildNudgeRateSummary(nudgesSent, responses), nil
 }
 
+func (s *Service) loadChurn(ctx context.Context, days int) (ChurnSummary, error) {
+	var churn ChurnSummary
+	err := s.pool.QueryRow(ctx, fmt.Sprintf(`
+		SELECT
+			(SELECT COUNT(DISTINCT e.user_id)
+			 FROM events e
+			 WHERE %s
+				AND e.event_type = 'user_churned'
+				AND e.created_at >= NOW() - make_interval(days => $2::int)
+				AND %s),
+			(SELECT COUNT(DISTINCT e.user_id)
+			 FROM events e
+			 WHERE %s
+				AND e.event_type = 'user_reactivated'
+				AND e.created_at >= NOW() - make_interval(days => $2::int)
+				AND %s),
+			(SELECT COUNT(*) FROM users u WHERE %s AND u.role = 'student' AND NOT u.sandbox AND u.lifecycle_state = 'blocked'),
+			(SELECT COUNT(*) FROM users u WHERE %s AND u.role = 'student' AND NOT u.sandbox AND u.lifecycle_state = 'inactive')
+	`, s.tenantPredicate("e.tenant_id", 1), notSandboxUser("e.user_id"), s.tenantPredicate("e.tenant_id", 1), notSandboxUser("e.user_id"), s.tenantPredicate("u.tenant_id", 1), s.tenantPredicate("u.tenant_id", 1)),
+		s.tenantArg(), days,
+	).Scan(&churn.ChurnedUsers, &churn.ReactivatedUsers, &churn.BlockedUsers, &churn.InactiveUsers)
+	if err != nil {
+		return ChurnSummary{}, fmt.Errorf("query churn: %w", err)
+	}
+	return churn, nil
+}
+
+func (s *Service) loadReferralFunnel(ctx context.Context, days int) (ReferralFunnel, error) {
+	var sharers, signups, activated int
+	err := s.pool.QueryRow(ctx, fmt.Sprintf(`
+		SELECT
+			COUNT(DISTINCT e.user_id) FILTER (WHERE e.event_type = 'referral_link_shared'),
+			COUNT(DISTINCT e.user_id) FILTER (WHERE e.event_type = 'referral_signup'),
+			COUNT(DISTINCT e.user_id) FILTER (WHERE e.event_type = 'referral_activated')
+		FROM events e
+		WHERE %s
+			AND e.event_type IN ('referral_link_shared', 'referral_signup', 'referral_activated')
+			AND e.created_at >= NOW() - make_interval(days => $2::int)
+			AND %s
+	`, s.tenantPredicate("e.tenant_id", 1), notSandboxUser("e.user_id")),
+		s.tenantArg(), days,
+	).Scan(&sharers, &signups, &activated)
+	if err != nil {
+		return ReferralFunnel{}, fmt.Errorf("query referral funnel: %w", err)
+	}
+	return buildReferralFunnel(sharers, signups, activated), nil
+}
+
+func (s *Service) loadDailyProblemParticipation(ctx context.Context, days int) (DailyProblemParticipation, error) {
+	var subscribers, sent, answered, correct int
+	err := s.pool.QueryRow(ctx, fmt.Sprintf(`
+		SELECT
+			(SELECT COUNT(*) FROM daily_problem_subscriptions ds WHERE %s AND %s),
+			COUNT(*) FILTER (WHERE e.event_type = 'daily_problem_sent'),
+			COUNT(*) FILTER (WHERE e.event_type = 'daily_problem_answered'),
+			COUNT(*) FILTER (WHERE e.event_type = 'daily_problem_answered' AND e.data->>'correct' = 'true')
+		FROM events e
+		WHERE %s
+			AND e.event_type IN ('daily_problem_sent', 'daily_problem_answered')
+			AND e.created_at >= NOW() - make_interval(days => $2::int)
+			AND %s
+	`, s.tenantPredicate("ds.tenant_id", 1), notSandboxUser("ds.user_id"), s.tenantPredicate("e.tenant_id", 1), notSandboxUser("e.user_id")),
+		s.tenantArg(), days,
+	).Scan(&subscribers, &sent, &answered, &correct)
+	if err != nil {
+		return DailyProblemParticipation{}, fmt.Errorf("query daily problem participation: %w", err)
+	}
+	return buildDailyProblemParticipation(subscribers, sent, answered, correct), nil
+}
+
 func (s *Service) loadStudentByExternalID(ctx context.Context, studentID string) (Student, string, error) {
 	var (
 		internalUserID string
@@ -1718,6 +1911,34 @@ func buildNudgeRateSummary(nudgesSent, responses int) NudgeRateSummary {
 	return summary
 }
 
+func buildReferralFunnel(sharers, signups, activated int) ReferralFunnel {
+	funnel := ReferralFunnel{
+		Sharers:   sharers,
+		Signups:   signups,
+		Activated: activated,
+	}
+	if signups > 0 {
+		funnel.ActivationRate = float64(activated) / float64(signups)
+	}
+	return funnel
+}
+
+func buildDailyProblemParticipation(subscribers, sent, answered, correct int) DailyProblemParticipation {
+	participation := DailyProblemParticipation{
+		Subscribers: subscribers,
+		Sent:        sent,
+		Answered:    answered,
+		Correct:     correct,
+	}
+	if sent > 0 {
+		participation.AnswerRate = float64(answered) / float64(sent)
+	}
+	if answered > 0 {
+		participation.CorrectRate = float64(correct) / float64(answered)
+	}
+	return participation
+}
+
 func buildAnalyticsOverview(daily []DailyActiveUsersPoint, retention []RetentionPoint, nudgeRate NudgeRateSummary, aiUsage AIUsageSummary) AnalyticsOverview {
 	overview := AnalyticsOverview{
 		NudgeResponseRate: nudgeRate.ResponseRate,
@@ -1831,6 +2052,71 @@ func finalizeAIUsageSummary(summary *AIUsageSummary, activeLearners int) {
 	}
 }
 
+// aiCosts sums the cost_usd that ai_response events carry. Events from before
+// costs were recorded, or from unpriced models, have none and are skipped.
+type aiCosts struct {
+	Priced bool
+	Month  float64
+	ByDay  map[string]float64
+}
+
+func (s *Service) loadAICosts(ctx context.Context) (aiCosts, error) {
+	rows, err := s.pool.Query(ctx, fmt.Sprintf(`
+		SELECT
+			DATE(e.created_at AT TIME ZONE 'UTC') AS usage_date,
+			COALESCE(SUM((e.data->>'cost_usd')::float8), 0) AS cost_usd
+		FROM events e
+		WHERE %s
+			AND e.event_type = 'ai_response'
+			AND e.data ? 'cost_usd'
+			AND %s
+			AND e.created_at >= LEAST(
+				DATE_TRUNC('month', NOW() AT TIME ZONE 'UTC'),
+				DATE(NOW() AT TIME ZONE 'UTC') - 6
+			) AT TIME ZONE 'UTC'
+		GROUP BY usage_date
+	`, s.tenantPredicate("e.tenant_id", 1), notSandboxUser("e.user_id")), s.tenantArg())
+	if err != nil {
+		return aiCosts{}, fmt.Errorf("query ai costs: %w", err)
+	}
+	defer rows.Close()
+
+	costs := aiCosts{ByDay: make(map[string]float64)}
+	monthStart := time.Now().UTC().Format("2006-01")
+	for rows.Next() {
+		var day time.Time
+		var cost float64
+		if err := rows.Scan(&day, &cost); err != nil {
+			return aiCosts{}, fmt.Errorf("scan ai costs: %w", err)
+		}
+		costs.Priced = true
+		date := day.UTC().Format("2006-01-02")
+		costs.ByDay[date] = cost
+		if strings.HasPrefix(date, monthStart) {
+			costs.Month += cost
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return aiCosts{}, fmt.Errorf("iterate ai costs: %w", err)
+	}
+	return costs, nil
+}
+
+// applyAICosts fills the summary's USD fields. They stay nil when no event
+// carried a cost, so the admin panel hides them rather than showing $0.
+func applyAICosts(summary *AIUsageSummary, costs aiCosts) {
+	if summary == nil || !costs.Priced {
+		return
+	}
+	month := costs.Month
+	summary.MonthlyCostUSD = &month
+	for i := range summary.DailyUsage {
+		if cost, ok := costs.ByDay[summary.DailyUsage[i].Date]; ok {
+			summary.DailyUsage[i].CostUSD = &cost
+		}
+	}
+}
+
 func applyTokenBudgetWindow(summary *AIUsageSummary, window *tokenBudgetWindow) {
 	if summary == nil || window == nil {
 		return
@@ -10,6 +10,8 @@ import (
 	"strings"
 	"testing"
 	"time"
+
+	"github.com/p-n-ai/pai-bot/internal/progress"
 )
 
 func TestFormFromClassID(t *testing.T) {
@@ -247,6 +249,32 @@ func TestBuildNudgeRateSummary(t *testing.T) {
 	}
 }
 
+func TestBuildReferralFunnel(t *testing.T) {
+	got := buildReferralFunnel(12, 8, 6)
+	if got.Sharers != 12 || got.Signups != 8 || got.Activated != 6 {
+		t.Fatalf("referral funnel = %#v, want sharers=12 signups=8 activated=6", got)
+	}
+	if got.ActivationRate != 0.75 {
+		t.Fatalf("activation rate = %v, want 0.75", got.ActivationRate)
+	}
+	if empty := buildReferralFunnel(3, 0, 0); empty.ActivationRate != 0 {
+		t.Fatalf("activation rate without signups = %v, want 0", empty.ActivationRate)
+	}
+}
+
+func TestBuildDailyProblemParticipation(t *testing.T) {
+	got := buildDailyProblemParticipation(20, 40, 10, 8)
+	if got.Subscribers != 20 || got.Sent != 40 || got.Answered != 10 || got.Correct != 8 {
+		t.Fatalf("participation = %#v, want subscribers=20 sent=40 answered=10 correct=8", got)
+	}
+	if got.AnswerRate != 0.25 || got.CorrectRate != 0.8 {
+		t.Fatalf("rates = %v, %v; want 0.25, 0.8", got.AnswerRate, got.CorrectRate)
+	}
+	if empty := buildDailyProblemParticipation(5, 0, 0, 0); empty.AnswerRate != 0 || empty.CorrectRate != 0 {
+		t.Fatalf("rates without sends = %v, %v; want 0", empty.AnswerRate, empty.CorrectRate)
+	}
+}
+
 func TestBuildAnalyticsOverview(t *testing.T) {
 	report := buildAnalyticsOverview(
 		[]DailyActiveUsersPoint{
@@ -331,6 +359,31 @@ func TestFinalizeAIUsageSummary(t *testing.T) {
 	}
 }
 
+func TestApplyAICosts(t *testing.T) {
+	summary := AIUsageSummary{
+		DailyUsage: []AIDailyUsagePoint{
+			{Date: "2026-03-10", Messages: 2, Tokens: 75},
+			{Date: "2026-03-11", Messages: 4, Tokens: 225},
+		},
+	}
+
+	applyAICosts(&summary, aiCosts{})
+	if summary.MonthlyCostUSD != nil || summary.DailyUsage[1].CostUSD != nil {
+		t.Fatalf("costs = %#v, want nil without priced events", summary)
+	}
+
+	applyAICosts(&summary, aiCosts{Priced: true, Month: 1.5, ByDay: map[string]float64{"2026-03-11": 0.25}})
+	if summary.MonthlyCostUSD == nil || *summary.MonthlyCostUSD != 1.5 {
+		t.Fatalf("MonthlyCostUSD = %v, want 1.5", summary.MonthlyCostUSD)
+	}
+	if summary.DailyUsage[0].CostUSD != nil {
+		t.Fatalf("DailyUsage[0].CostUSD = %v, want nil for a day without priced events", *summary.DailyUsage[0].CostUSD)
+	}
+	if got := summary.DailyUsage[1].CostUSD; got == nil || *got != 0.25 {
+		t.Fatalf("DailyUsage[1].CostUSD = %v, want 0.25", got)
+	}
+}
+
 func TestFinalizeAIUsageSummarySkipsPerStudentAverageWithoutLearners(t *testing.T) {
 	summary := AIUsageSummary{
 		TotalInputTokens:  20,
@@ -458,3 +511,28 @@ func TestNormalizeTokenBudgetWindowRequest(t *testing.T) {
 		})
 	}
 }
+
+func TestBuildClassQuizLeaderboardQueryHonorsPrivacy(t *testing.T) {
+	service := Service{tenantID: "11111111-1111-1111-1111-111111111111"}
+
+	query, args := service.buildClassQuizLeaderboardQuery("22222222-2222-2222-2222-222222222222")
+
+	if len(args) != 3 {
+		t.Fatalf("args len = %d, want 3", len(args))
+	}
+	if args[0] != "22222222-2222-2222-2222-222222222222" || args[1] != service.tenantID || args[2] != progress.XPQuizCorrect {
+		t.Fatalf("args = %#v, want group id, tenant id and quiz XP", args)
+	}
+	for _, want := range []string{
+		"LEFT JOIN leaderboard_preferences pref ON pref.user_id = gm.user_id",
+		"NOT COALESCE(pref.opted_out, false)",
+		"e.event_type = 'quiz_completed'",
+		"AND e.tenant_id = m.tenant_id",
+		"ORDER BY quiz_xp DESC, accuracy DESC",
+		"($2::uuid IS NULL OR gm.tenant_id = $2::uuid)",
+	} {
+		if !strings.Contains(query, want) {
+			t.Fatalf("query missing %q:\n%s", want, query)
+		}
+	}
+}
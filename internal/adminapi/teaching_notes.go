@@ -0,0 +1,146 @@
+// Copyright 2026 the P&AI authors. All rights reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package adminapi
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+
+	"github.com/p-n-ai/pai-bot/internal/curriculum"
+)
+
+// TeachingNote is one saved version of a tenant's school-specific notes and
+// worked examples for a curriculum topic.
+type TeachingNote struct {
+	TenantID  string                     `json:"tenant_id"`
+	TopicID   string                     `json:"topic_id"`
+	Version   int                        `json:"version"`
+	Notes     string                     `json:"notes"`
+	Examples  []curriculum.WorkedExample `json:"examples"`
+	CreatedBy string                     `json:"created_by,omitempty"`
+	CreatedAt time.Time                  `json:"created_at"`
+}
+
+// Overlay returns the note in the shape the curriculum loader layers onto
+// the OSS notes.
+func (n TeachingNote) Overlay() curriculum.TeachingNoteOverlay {
+	return curriculum.TeachingNoteOverlay{Notes: n.Notes, Examples: n.Examples}
+}
+
+const teachingNoteColumns = `tenant_id::text, topic_id, version, notes, examples, COALESCE(created_by::text, ''), created_at`
+
+// ListTeachingNotes returns the latest version of every topic's teaching
+// note in the tenant.
+func (s *Service) ListTeachingNotes() ([]TeachingNote, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	rows, err := s.pool.Query(ctx, fmt.Sprintf(`
+		SELECT DISTINCT ON (tenant_id, topic_id) %s
+		FROM teaching_note_versions
+		WHERE %s
+		ORDER BY tenant_id, topic_id, version DESC`,
+		teachingNoteColumns, s.tenantPredicate("tenant_id", 1)), s.tenantArg())
+	if err != nil {
+		return nil, fmt.Errorf("list teaching notes: %w", err)
+	}
+	notes, err := collectTeachingNotes(rows)
+	if err != nil {
+		return nil, fmt.Errorf("list teaching notes: %w", err)
+	}
+	return notes, nil
+}
+
+// GetTeachingNoteHistory returns every saved version of a topic's teaching
+// note, newest first.
+func (s *Service) GetTeachingNoteHistory(topicID string) ([]TeachingNote, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	rows, err := s.pool.Query(ctx, fmt.Sprintf(`
+		SELECT %s
+		FROM teaching_note_versions
+		WHERE %s AND topic_id = $2
+		ORDER BY tenant_id, version DESC`,
+		teachingNoteColumns, s.tenantPredicate("tenant_id", 1)), s.tenantArg(), topicID)
+	if err != nil {
+		return nil, fmt.Errorf("get teaching note history: %w", err)
+	}
+	notes, err := collectTeachingNotes(rows)
+	if err != nil {
+		return nil, fmt.Errorf("get teaching note history: %w", err)
+	}
+	if len(notes) == 0 {
+		return nil, ErrNotFound
+	}
+	return notes, nil
+}
+
+// SaveTeachingNote stores overlay as the next version of the topic's
+// teaching note. The caller validates overlay against the curriculum.
+func (s *Service) SaveTeachingNote(topicID string, overlay curriculum.TeachingNoteOverlay, createdByUserID string) (TeachingNote, error) {
+	if s.allTenants {
+		return TeachingNote{}, fmt.Errorf("%w: cannot save teaching notes without tenant scope", ErrInvalidArgument)
+	}
+	topicID = strings.TrimSpace(topicID)
+	if topicID == "" {
+		return TeachingNote{}, fmt.Errorf("%w: topic_id is required", ErrInvalidArgument)
+	}
+	examples := overlay.Examples
+	if examples == nil {
+		examples = []curriculum.WorkedExample{}
+	}
+	examplesJSON, err := json.Marshal(examples)
+	if err != nil {
+		return TeachingNote{}, fmt.Errorf("encode teaching note examples: %w", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	rows, err := s.pool.Query(ctx, `
+		INSERT INTO teaching_note_versions (tenant_id, topic_id, version, notes, examples, created_by)
+		SELECT $1::uuid, $2, COALESCE(MAX(version), 0) + 1, $3, $4::jsonb, NULLIF($5, '')::uuid
+		FROM teaching_note_versions
+		WHERE tenant_id = $1::uuid AND topic_id = $2
+		RETURNING `+teachingNoteColumns,
+		s.tenantID, topicID, overlay.Notes, string(examplesJSON), createdByUserID)
+	if err != nil {
+		return TeachingNote{}, fmt.Errorf("save teaching note: %w", err)
+	}
+	notes, err := collectTeachingNotes(rows)
+	if err != nil {
+		return TeachingNote{}, fmt.Errorf("save teaching note: %w", err)
+	}
+	if len(notes) != 1 {
+		return TeachingNote{}, fmt.Errorf("save teaching note: got %d rows", len(notes))
+	}
+	return notes[0], nil
+}
+
+func collectTeachingNotes(rows pgx.Rows) ([]TeachingNote, error) {
+	defer rows.Close()
+	notes := []TeachingNote{}
+	for rows.Next() {
+		var note TeachingNote
+		var examples []byte
+		if err := rows.Scan(&note.TenantID, &note.TopicID, &note.Version, &note.Notes, &examples, &note.CreatedBy, &note.CreatedAt); err != nil {
+			return nil, err
+		}
+		if err := json.Unmarshal(examples, &note.Examples); err != nil {
+			return nil, fmt.Errorf("decode examples for %s v%d: %w", note.TopicID, note.Version, err)
+		}
+		if note.Examples == nil {
+			note.Examples = []curriculum.WorkedExample{}
+		}
+		note.CreatedAt = note.CreatedAt.UTC()
+		notes = append(notes, note)
+	}
+	return notes, rows.Err()
+}
@@ -0,0 +1,19 @@
+// Copyright 2026 the P&AI authors. All rights reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package adminapi
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/p-n-ai/pai-bot/internal/curriculum"
+)
+
+func TestSaveTeachingNoteRequiresTenantScope(t *testing.T) {
+	svc := &Service{allTenants: true}
+	overlay := curriculum.TeachingNoteOverlay{Notes: "Use bus fares in word problems."}
+	if _, err := svc.SaveTeachingNote("F1-03", overlay, ""); !errors.Is(err, ErrInvalidArgument) {
+		t.Fatalf("SaveTeachingNote() error = %v, want ErrInvalidArgument", err)
+	}
+}
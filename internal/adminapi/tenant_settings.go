@@ -0,0 +1,95 @@
+// Copyright 2026 the P&AI authors. All rights reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package adminapi
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+)
+
+// TenantSettings are a school's switches for how the tutor behaves, kept in
+// tenants.config under "settings".
+type TenantSettings struct {
+	TenantID string `json:"tenant_id"`
+	// MetaFooter appends what the tutor did, a hint or a full solution, and
+	// the topic practised to each answer, for deployments where teachers
+	// review the chats. Normal student chats leave it off.
+	MetaFooter bool `json:"meta_footer"`
+	// NotationRules are the Malaysian notation conventions applied to this
+	// school's answers, as LEARN_NOTATION_RULES lists them. Empty keeps the
+	// deployment default; "none" turns every rule off.
+	NotationRules string `json:"notation_rules,omitempty"`
+}
+
+type tenantSettingsEnvelope struct {
+	Settings *TenantSettings `json:"settings,omitempty"`
+}
+
+// GetTenantSettings returns the tenant's settings, all off when none are saved.
+func (s *Service) GetTenantSettings() (TenantSettings, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	if strings.TrimSpace(s.tenantID) == "" {
+		return TenantSettings{}, fmt.Errorf("%w: tenant-scoped admin context is required", ErrInvalidArgument)
+	}
+
+	var rawConfig []byte
+	err := s.pool.QueryRow(ctx, `
+		SELECT COALESCE(config, '{}'::jsonb)
+		FROM tenants
+		WHERE id = $1::uuid
+	`, s.tenantID).Scan(&rawConfig)
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return TenantSettings{}, ErrNotFound
+		}
+		return TenantSettings{}, fmt.Errorf("query tenant settings: %w", err)
+	}
+
+	var envelope tenantSettingsEnvelope
+	if err := json.Unmarshal(rawConfig, &envelope); err != nil {
+		return TenantSettings{}, fmt.Errorf("decode tenant settings: %w", err)
+	}
+	settings := TenantSettings{}
+	if envelope.Settings != nil {
+		settings = *envelope.Settings
+	}
+	settings.TenantID = s.tenantID
+	return settings, nil
+}
+
+// UpdateTenantSettings replaces the tenant's settings, leaving the rest of
+// tenants.config as it is.
+func (s *Service) UpdateTenantSettings(settings TenantSettings) (TenantSettings, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	if strings.TrimSpace(s.tenantID) == "" {
+		return TenantSettings{}, fmt.Errorf("%w: tenant-scoped admin context is required", ErrInvalidArgument)
+	}
+
+	settings.TenantID = s.tenantID
+	raw, err := json.Marshal(settings)
+	if err != nil {
+		return TenantSettings{}, fmt.Errorf("encode tenant settings: %w", err)
+	}
+	cmd, err := s.pool.Exec(ctx, `
+		UPDATE tenants
+		SET config = COALESCE(config, '{}'::jsonb) || jsonb_build_object('settings', $2::jsonb - 'tenant_id')
+		WHERE id = $1::uuid
+	`, s.tenantID, string(raw))
+	if err != nil {
+		return TenantSettings{}, fmt.Errorf("update tenant settings: %w", err)
+	}
+	if cmd.RowsAffected() == 0 {
+		return TenantSettings{}, ErrNotFound
+	}
+	return settings, nil
+}
@@ -0,0 +1,19 @@
+// Copyright 2026 the P&AI authors. All rights reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package adminapi
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestTenantSettingsRequireTenantScope(t *testing.T) {
+	svc := &Service{allTenants: true}
+	if _, err := svc.GetTenantSettings(); !errors.Is(err, ErrInvalidArgument) {
+		t.Fatalf("GetTenantSettings() error = %v, want ErrInvalidArgument", err)
+	}
+	if _, err := svc.UpdateTenantSettings(TenantSettings{MetaFooter: true}); !errors.Is(err, ErrInvalidArgument) {
+		t.Fatalf("UpdateTenantSettings() error = %v, want ErrInvalidArgument", err)
+	}
+}
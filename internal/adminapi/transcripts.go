@@ -0,0 +1,347 @@
+// Copyright 2026 the P&AI authors. All rights reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package adminapi
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+
+	"github.com/p-n-ai/pai-bot/internal/platform/msgcrypt"
+)
+
+const (
+	TranscriptEntryMessage = "message"
+	TranscriptEntryEvent   = "event"
+)
+
+// TranscriptEntry is one message or turn event in a reviewed conversation.
+type TranscriptEntry struct {
+	Kind         string              `json:"kind"`
+	ID           string              `json:"id"`
+	Timestamp    time.Time           `json:"timestamp"`
+	Role         string              `json:"role,omitempty"`
+	Text         string              `json:"text,omitempty"`
+	OriginalText string              `json:"original_text,omitempty"`
+	EditedAt     *time.Time          `json:"edited_at,omitempty"`
+	DeletedAt    *time.Time          `json:"deleted_at,omitempty"`
+	ContentKind  string              `json:"content_kind,omitempty"`
+	Attachments  []MessageAttachment `json:"attachments,omitempty"`
+	Model        string              `json:"model,omitempty"`
+	InputTokens  int                 `json:"input_tokens,omitempty"`
+	OutputTokens int                 `json:"output_tokens,omitempty"`
+	EventType    string              `json:"event_type,omitempty"`
+	Data         map[string]any      `json:"data,omitempty"`
+	// Safety lists the safety decisions behind an assistant message.
+	Safety *SafetyTrace `json:"safety,omitempty"`
+}
+
+// SafetyTrace mirrors agent.SafetyTrace: the safety checks run for one turn.
+type SafetyTrace struct {
+	TurnID    string           `json:"turn_id,omitempty"`
+	Decisions []SafetyDecision `json:"decisions"`
+}
+
+// SafetyDecision is one safety check and its outcome.
+type SafetyDecision struct {
+	Check  string `json:"check"`
+	Action string `json:"action"`
+	Hits   int    `json:"hits,omitempty"`
+	Reason string `json:"reason,omitempty"`
+}
+
+// ConversationTranscript interleaves messages with the events fired during each turn.
+type ConversationTranscript struct {
+	ConversationID string     `json:"conversation_id"`
+	StudentID      string     `json:"student_id"`
+	StudentName    string     `json:"student_name"`
+	Channel        string     `json:"channel"`
+	TopicID        string     `json:"topic_id,omitempty"`
+	State          string     `json:"state"`
+	StartedAt      time.Time  `json:"started_at"`
+	EndedAt        *time.Time `json:"ended_at,omitempty"`
+	// Sentiment is the session's latest frustration and engagement score,
+	// absent until the session-sentiment job has scored it.
+	Sentiment *SessionSentiment `json:"sentiment,omitempty"`
+	Entries   []TranscriptEntry `json:"entries"`
+}
+
+// SessionSentiment mirrors agent.SessionSentiment: how a session is going
+// for the learner, both scores from 0 to 1.
+type SessionSentiment struct {
+	Frustration  float64    `json:"frustration"`
+	Engagement   float64    `json:"engagement"`
+	UserMessages int        `json:"user_messages"`
+	ScoredAt     time.Time  `json:"scored_at"`
+	SpikeAt      *time.Time `json:"spike_at,omitempty"`
+}
+
+func (s *Service) GetConversationTranscript(conversationID string) (ConversationTranscript, error) {
+	conversationID = strings.TrimSpace(conversationID)
+	if !looksLikeUUID(conversationID) {
+		return ConversationTranscript{}, ErrNotFound
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	transcript := ConversationTranscript{ConversationID: conversationID}
+	var archivedAt *time.Time
+	var sentiment []byte
+	err := s.pool.QueryRow(ctx, fmt.Sprintf(`
+		SELECT
+			COALESCE(NULLIF(u.external_id, ''), u.id::text),
+			u.name,
+			u.channel,
+			COALESCE(c.topic_id, ''),
+			c.state,
+			c.started_at,
+			c.ended_at,
+			c.archived_at,
+			c.metadata->'sentiment'
+		FROM conversations c
+		JOIN users u ON u.id = c.user_id
+		WHERE %s
+			AND c.id = $2::uuid
+	`, s.tenantPredicate("c.tenant_id", 1)), s.tenantArg(), conversationID).Scan(
+		&transcript.StudentID,
+		&transcript.StudentName,
+		&transcript.Channel,
+		&transcript.TopicID,
+		&transcript.State,
+		&transcript.StartedAt,
+		&transcript.EndedAt,
+		&archivedAt,
+		&sentiment,
+	)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return ConversationTranscript{}, ErrNotFound
+		}
+		return ConversationTranscript{}, fmt.Errorf("load transcript conversation: %w", err)
+	}
+	if len(sentiment) > 0 {
+		var parsed SessionSentiment
+		if err := json.Unmarshal(sentiment, &parsed); err == nil {
+			transcript.Sentiment = &parsed
+		}
+	}
+
+	load := s.loadTranscriptMessages
+	if archivedAt != nil {
+		load = s.loadArchivedTranscriptMessages
+	}
+	messages, err := load(ctx, conversationID)
+	if err != nil {
+		return ConversationTranscript{}, err
+	}
+	events, err := s.loadTranscriptEvents(ctx, conversationID)
+	if err != nil {
+		return ConversationTranscript{}, err
+	}
+	transcript.Entries = mergeTranscriptEntries(messages, events)
+	return transcript, nil
+}
+
+func (s *Service) loadTranscriptMessages(ctx context.Context, conversationID string) ([]TranscriptEntry, error) {
+	rows, err := s.pool.Query(ctx, fmt.Sprintf(`
+		SELECT
+			m.id::text,
+			m.created_at,
+			CASE WHEN m.role = 'user' THEN 'student' ELSE m.role END,
+			m.content,
+			COALESCE(m.original_content, ''),
+			m.edited_at,
+			m.deleted_at,
+			m.content_kind,
+			m.attachments,
+			COALESCE(m.model, ''),
+			COALESCE(m.input_tokens, 0),
+			COALESCE(m.output_tokens, 0),
+			m.safety,
+			COALESCE(m.content_key_id, ''),
+			m.tenant_id::text
+		FROM messages m
+		WHERE %s
+			AND m.conversation_id = $2::uuid
+		ORDER BY m.created_at ASC
+	`, s.tenantPredicate("m.tenant_id", 1)), s.tenantArg(), conversationID)
+	if err != nil {
+		return nil, fmt.Errorf("query transcript messages: %w", err)
+	}
+	defer rows.Close()
+
+	var entries []TranscriptEntry
+	for rows.Next() {
+		entry := TranscriptEntry{Kind: TranscriptEntryMessage}
+		var attachments, safety []byte
+		var keyID, tenantID string
+		if err := rows.Scan(&entry.ID, &entry.Timestamp, &entry.Role, &entry.Text, &entry.OriginalText, &entry.EditedAt, &entry.DeletedAt, &entry.ContentKind, &attachments, &entry.Model, &entry.InputTokens, &entry.OutputTokens, &safety, &keyID, &tenantID); err != nil {
+			return nil, fmt.Errorf("scan transcript message: %w", err)
+		}
+		if entry.Text, attachments, err = s.openMessage(tenantID, entry.ID, keyID, entry.Text, attachments); err != nil {
+			return nil, fmt.Errorf("decrypt transcript message %s: %w", entry.ID, err)
+		}
+		if entry.OriginalText != "" {
+			if entry.OriginalText, err = s.keys.Open(keyID, entry.OriginalText, msgcrypt.Binding(tenantID, entry.ID)); err != nil {
+				return nil, fmt.Errorf("decrypt transcript message %s: %w", entry.ID, err)
+			}
+		}
+		if len(attachments) > 0 {
+			if err := json.Unmarshal(attachments, &entry.Attachments); err != nil {
+				return nil, fmt.Errorf("decode transcript message attachments: %w", err)
+			}
+		}
+		if len(safety) > 0 {
+			entry.Safety = &SafetyTrace{}
+			if err := json.Unmarshal(safety, entry.Safety); err != nil {
+				return nil, fmt.Errorf("decode transcript message safety trace: %w", err)
+			}
+		}
+		entries = append(entries, entry)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterate transcript messages: %w", err)
+	}
+	return entries, nil
+}
+
+// archivedTranscriptMessage mirrors the fields of agent.StoredMessage that
+// the conversation archive payload carries.
+type archivedTranscriptMessage struct {
+	ID              string              `json:"id"`
+	Role            string              `json:"role"`
+	Content         string              `json:"content"`
+	Kind            string              `json:"kind"`
+	Attachments     []MessageAttachment `json:"attachments"`
+	Model           string              `json:"model"`
+	InputTokens     int                 `json:"input_tokens"`
+	OutputTokens    int                 `json:"output_tokens"`
+	CreatedAt       time.Time           `json:"created_at"`
+	OriginalContent string              `json:"original_content"`
+	EditedAt        *time.Time          `json:"edited_at"`
+	DeletedAt       *time.Time          `json:"deleted_at"`
+	Safety          *SafetyTrace        `json:"safety"`
+}
+
+// loadArchivedTranscriptMessages reads messages from the gzip-compressed
+// conversation_archives payload written by the archive worker.
+func (s *Service) loadArchivedTranscriptMessages(ctx context.Context, conversationID string) ([]TranscriptEntry, error) {
+	var payload []byte
+	var keyID, tenantID, archivedID string
+	err := s.pool.QueryRow(ctx, fmt.Sprintf(`
+		SELECT a.payload, COALESCE(a.payload_key_id, ''), a.tenant_id::text, a.conversation_id::text
+		FROM conversation_archives a
+		WHERE %s
+			AND a.conversation_id = $2::uuid
+	`, s.tenantPredicate("a.tenant_id", 1)), s.tenantArg(), conversationID).Scan(&payload, &keyID, &tenantID, &archivedID)
+	if err != nil {
+		return nil, fmt.Errorf("load conversation archive: %w", err)
+	}
+	opened, err := s.keys.Open(keyID, string(payload), msgcrypt.Binding(tenantID, archivedID))
+	if err != nil {
+		return nil, fmt.Errorf("decrypt conversation archive: %w", err)
+	}
+	return decodeArchivedTranscript([]byte(opened))
+}
+
+func decodeArchivedTranscript(payload []byte) ([]TranscriptEntry, error) {
+	zr, err := gzip.NewReader(bytes.NewReader(payload))
+	if err != nil {
+		return nil, fmt.Errorf("decompress conversation archive: %w", err)
+	}
+	defer func() { _ = zr.Close() }()
+	var archive struct {
+		Messages []archivedTranscriptMessage `json:"messages"`
+	}
+	if err := json.NewDecoder(zr).Decode(&archive); err != nil {
+		return nil, fmt.Errorf("decode conversation archive: %w", err)
+	}
+
+	entries := make([]TranscriptEntry, 0, len(archive.Messages))
+	for _, msg := range archive.Messages {
+		role := msg.Role
+		if role == "user" {
+			role = "student"
+		}
+		kind := msg.Kind
+		if kind == "" {
+			kind = "text"
+		}
+		entries = append(entries, TranscriptEntry{
+			Kind:         TranscriptEntryMessage,
+			ID:           msg.ID,
+			Timestamp:    msg.CreatedAt,
+			Role:         role,
+			Text:         msg.Content,
+			OriginalText: msg.OriginalContent,
+			EditedAt:     msg.EditedAt,
+			DeletedAt:    msg.DeletedAt,
+			ContentKind:  kind,
+			Attachments:  msg.Attachments,
+			Model:        msg.Model,
+			InputTokens:  msg.InputTokens,
+			OutputTokens: msg.OutputTokens,
+			Safety:       msg.Safety,
+		})
+	}
+	return entries, nil
+}
+
+func (s *Service) loadTranscriptEvents(ctx context.Context, conversationID string) ([]TranscriptEntry, error) {
+	rows, err := s.pool.Query(ctx, fmt.Sprintf(`
+		SELECT e.id::text, e.created_at, e.event_type, COALESCE(e.data, '{}'::jsonb)
+		FROM events e
+		WHERE %s
+			AND e.conversation_id = $2::uuid
+		ORDER BY e.created_at ASC
+	`, s.tenantPredicate("e.tenant_id", 1)), s.tenantArg(), conversationID)
+	if err != nil {
+		return nil, fmt.Errorf("query transcript events: %w", err)
+	}
+	defer rows.Close()
+
+	var entries []TranscriptEntry
+	for rows.Next() {
+		entry := TranscriptEntry{Kind: TranscriptEntryEvent}
+		var raw []byte
+		if err := rows.Scan(&entry.ID, &entry.Timestamp, &entry.EventType, &raw); err != nil {
+			return nil, fmt.Errorf("scan transcript event: %w", err)
+		}
+		if len(raw) > 0 {
+			if err := json.Unmarshal(raw, &entry.Data); err != nil {
+				return nil, fmt.Errorf("decode transcript event data: %w", err)
+			}
+		}
+		entries = append(entries, entry)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterate transcript events: %w", err)
+	}
+	return entries, nil
+}
+
+// mergeTranscriptEntries orders messages and events on one timeline. Events
+// are logged asynchronously after the message they describe, so on equal
+// timestamps the message sorts first.
+func mergeTranscriptEntries(messages, events []TranscriptEntry) []TranscriptEntry {
+	entries := make([]TranscriptEntry, 0, len(messages)+len(events))
+	entries = append(entries, messages...)
+	entries = append(entries, events...)
+	sort.SliceStable(entries, func(i, j int) bool {
+		if !entries[i].Timestamp.Equal(entries[j].Timestamp) {
+			return entries[i].Timestamp.Before(entries[j].Timestamp)
+		}
+		return entries[i].Kind == TranscriptEntryMessage && entries[j].Kind != TranscriptEntryMessage
+	})
+	return entries
+}
@@ -0,0 +1,94 @@
+// Copyright 2026 the P&AI authors. All rights reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package adminapi
+
+import (
+	"bytes"
+	"compress/gzip"
+	"encoding/json"
+	"testing"
+	"time"
+)
+
+func TestMergeTranscriptEntriesInterleavesByTime(t *testing.T) {
+	base := time.Date(2026, 3, 9, 11, 20, 0, 0, time.UTC)
+	messages := []TranscriptEntry{
+		{Kind: TranscriptEntryMessage, ID: "m1", Timestamp: base, Role: "student"},
+		{Kind: TranscriptEntryMessage, ID: "m2", Timestamp: base.Add(3 * time.Second), Role: "assistant"},
+	}
+	events := []TranscriptEntry{
+		{Kind: TranscriptEntryEvent, ID: "e1", Timestamp: base, EventType: "message_sent"},
+		{Kind: TranscriptEntryEvent, ID: "e2", Timestamp: base.Add(3 * time.Second), EventType: "agent_turn_completed"},
+		{Kind: TranscriptEntryEvent, ID: "e0", Timestamp: base.Add(-time.Second), EventType: "session_started"},
+	}
+
+	got := mergeTranscriptEntries(messages, events)
+
+	want := []string{"e0", "m1", "e1", "m2", "e2"}
+	if len(got) != len(want) {
+		t.Fatalf("entries = %d, want %d", len(got), len(want))
+	}
+	for i, id := range want {
+		if got[i].ID != id {
+			t.Fatalf("entries[%d].ID = %q, want %q", i, got[i].ID, id)
+		}
+	}
+}
+
+func TestMergeTranscriptEntriesEncodesEmptyList(t *testing.T) {
+	payload, err := json.Marshal(ConversationTranscript{Entries: mergeTranscriptEntries(nil, nil)})
+	if err != nil {
+		t.Fatalf("json.Marshal() error = %v", err)
+	}
+	var decoded map[string]any
+	if err := json.Unmarshal(payload, &decoded); err != nil {
+		t.Fatalf("json.Unmarshal() error = %v", err)
+	}
+	if _, ok := decoded["entries"].([]any); !ok {
+		t.Fatalf("entries = %#v, want empty array", decoded["entries"])
+	}
+}
+
+func TestDecodeArchivedTranscriptMapsStoredMessages(t *testing.T) {
+	createdAt := time.Date(2026, 3, 9, 11, 20, 0, 0, time.UTC)
+	raw, err := json.Marshal(map[string]any{
+		"summary": "ignored here",
+		"messages": []map[string]any{
+			{"id": "m1", "role": "user", "content": "2x = 6", "created_at": createdAt},
+			{"id": "m2", "role": "assistant", "content": "x = 3", "kind": "quiz", "model": "gpt-5.4", "output_tokens": 4, "created_at": createdAt.Add(time.Second),
+				"safety": map[string]any{"turn_id": "t1", "decisions": []map[string]any{{"check": "content_filter", "action": "masked", "hits": 1}}}},
+		},
+	})
+	if err != nil {
+		t.Fatalf("json.Marshal() error = %v", err)
+	}
+	var buf bytes.Buffer
+	zw := gzip.NewWriter(&buf)
+	if _, err := zw.Write(raw); err != nil {
+		t.Fatalf("gzip write error = %v", err)
+	}
+	if err := zw.Close(); err != nil {
+		t.Fatalf("gzip close error = %v", err)
+	}
+
+	entries, err := decodeArchivedTranscript(buf.Bytes())
+	if err != nil {
+		t.Fatalf("decodeArchivedTranscript() error = %v", err)
+	}
+	if len(entries) != 2 {
+		t.Fatalf("entries = %d, want 2", len(entries))
+	}
+	if entries[0].Role != "student" || entries[0].ContentKind != "text" || !entries[0].Timestamp.Equal(createdAt) {
+		t.Fatalf("entries[0] = %+v, want student text message", entries[0])
+	}
+	if entries[1].ContentKind != "quiz" || entries[1].Model != "gpt-5.4" || entries[1].OutputTokens != 4 {
+		t.Fatalf("entries[1] = %+v, want archived assistant metadata", entries[1])
+	}
+	if safety := entries[1].Safety; safety == nil || safety.TurnID != "t1" || len(safety.Decisions) != 1 || safety.Decisions[0].Hits != 1 {
+		t.Fatalf("entries[1].Safety = %+v, want the archived safety trace", safety)
+	}
+	if entries[0].Safety != nil {
+		t.Errorf("entries[0].Safety = %+v, want none on the student message", entries[0].Safety)
+	}
+}
@@ -0,0 +1,124 @@
+// Copyright 2026 the P&AI authors. All rights reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package adminapi
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+)
+
+const (
+	// usageStatsDefaultWindow is the period reported when the caller gives
+	// no start date.
+	usageStatsDefaultWindow = 30 * 24 * time.Hour
+	// usageStatsMaxWindow keeps a single request to about a school year.
+	usageStatsMaxWindow = 366 * 24 * time.Hour
+	usageStatsTopTopics = 5
+)
+
+// UsageStats is a tenant's aggregate usage over [From, To). It carries no
+// learner identities, so schools can share it with their own dashboards.
+type UsageStats struct {
+	TenantID string    `json:"tenant_id"`
+	From     time.Time `json:"from"`
+	To       time.Time `json:"to"`
+	// ActiveStudents counts students who sent at least one message.
+	ActiveStudents int          `json:"active_students"`
+	Messages       int          `json:"messages"`
+	InputTokens    int64        `json:"input_tokens"`
+	OutputTokens   int64        `json:"output_tokens"`
+	TotalTokens    int64        `json:"total_tokens"`
+	TopTopics      []TopicUsage `json:"top_topics"`
+}
+
+// TopicUsage is the activity in conversations about one curriculum topic.
+type TopicUsage struct {
+	TopicID  string `json:"topic_id"`
+	Messages int    `json:"messages"`
+	Students int    `json:"students"`
+}
+
+// GetUsageStats returns the tenant's usage between from and to. A zero to
+// means now and a zero from means 30 days before to. Sandbox learners are
+// left out.
+func (s *Service) GetUsageStats(from, to time.Time) (UsageStats, error) {
+	if strings.TrimSpace(s.tenantID) == "" || s.allTenants {
+		return UsageStats{}, fmt.Errorf("%w: tenant-scoped admin context is required", ErrInvalidArgument)
+	}
+	from, to, err := normalizeUsageWindow(from, to, time.Now())
+	if err != nil {
+		return UsageStats{}, err
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	stats := UsageStats{TenantID: s.tenantID, From: from, To: to, TopTopics: []TopicUsage{}}
+	if err := s.pool.QueryRow(ctx, `
+		SELECT
+			COUNT(DISTINCT c.user_id) FILTER (WHERE m.role = 'user' AND u.role = 'student'),
+			COUNT(*),
+			COALESCE(SUM(m.input_tokens), 0),
+			COALESCE(SUM(m.output_tokens), 0)
+		FROM messages m
+		JOIN conversations c ON c.id = m.conversation_id
+		JOIN users u ON u.id = c.user_id
+		WHERE m.tenant_id = $1::uuid
+			AND m.created_at >= $2 AND m.created_at < $3
+			AND m.role <> 'system'
+			AND NOT u.sandbox
+	`, s.tenantID, from, to).Scan(&stats.ActiveStudents, &stats.Messages, &stats.InputTokens, &stats.OutputTokens); err != nil {
+		return UsageStats{}, fmt.Errorf("query usage stats: %w", err)
+	}
+	stats.TotalTokens = stats.InputTokens + stats.OutputTokens
+
+	rows, err := s.pool.Query(ctx, `
+		SELECT c.topic_id, COUNT(*), COUNT(DISTINCT c.user_id)
+		FROM messages m
+		JOIN conversations c ON c.id = m.conversation_id
+		JOIN users u ON u.id = c.user_id
+		WHERE m.tenant_id = $1::uuid
+			AND m.created_at >= $2 AND m.created_at < $3
+			AND m.role <> 'system'
+			AND NOT u.sandbox
+			AND COALESCE(c.topic_id, '') <> ''
+		GROUP BY c.topic_id
+		ORDER BY COUNT(*) DESC, c.topic_id
+		LIMIT $4
+	`, s.tenantID, from, to, usageStatsTopTopics)
+	if err != nil {
+		return UsageStats{}, fmt.Errorf("query usage top topics: %w", err)
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var topic TopicUsage
+		if err := rows.Scan(&topic.TopicID, &topic.Messages, &topic.Students); err != nil {
+			return UsageStats{}, fmt.Errorf("scan usage top topics: %w", err)
+		}
+		stats.TopTopics = append(stats.TopTopics, topic)
+	}
+	if err := rows.Err(); err != nil {
+		return UsageStats{}, fmt.Errorf("iterate usage top topics: %w", err)
+	}
+	return stats, nil
+}
+
+func normalizeUsageWindow(from, to, now time.Time) (time.Time, time.Time, error) {
+	if to.IsZero() {
+		to = now
+	}
+	if from.IsZero() {
+		from = to.Add(-usageStatsDefaultWindow)
+	}
+	from, to = from.UTC(), to.UTC()
+	if !from.Before(to) {
+		return time.Time{}, time.Time{}, fmt.Errorf("%w: from must be before to", ErrInvalidArgument)
+	}
+	if to.Sub(from) > usageStatsMaxWindow {
+		return time.Time{}, time.Time{}, fmt.Errorf("%w: usage window is longer than 366 days", ErrInvalidArgument)
+	}
+	return from, to, nil
+}
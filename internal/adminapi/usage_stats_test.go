@@ -0,0 +1,41 @@
+// Copyright 2026 the P&AI authors. All rights reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package adminapi
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestGetUsageStatsRequiresTenantScope(t *testing.T) {
+	svc := &Service{allTenants: true}
+	if _, err := svc.GetUsageStats(time.Time{}, time.Time{}); !errors.Is(err, ErrInvalidArgument) {
+		t.Fatalf("GetUsageStats() error = %v, want ErrInvalidArgument", err)
+	}
+}
+
+func TestNormalizeUsageWindow(t *testing.T) {
+	now := time.Date(2026, 10, 17, 9, 0, 0, 0, time.UTC)
+
+	from, to, err := normalizeUsageWindow(time.Time{}, time.Time{}, now)
+	if err != nil || !to.Equal(now) || !from.Equal(now.AddDate(0, 0, -30)) {
+		t.Fatalf("default window = %v..%v, %v", from, to, err)
+	}
+
+	start := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	if from, _, err := normalizeUsageWindow(start, time.Time{}, now); err != nil || !from.Equal(start) {
+		t.Fatalf("from only = %v, %v", from, err)
+	}
+
+	for name, window := range map[string][2]time.Time{
+		"from after to": {now, start},
+		"empty":         {now, now},
+		"too long":      {start.AddDate(-1, 0, 0), now},
+	} {
+		if _, _, err := normalizeUsageWindow(window[0], window[1], now); !errors.Is(err, ErrInvalidArgument) {
+			t.Fatalf("%s: error = %v, want ErrInvalidArgument", name, err)
+		}
+	}
+}
@@ -0,0 +1,408 @@
+// Copyright 2026 the P&AI authors. All rights reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package agent
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"slices"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"github.com/p-n-ai/pai-bot/internal/chat"
+	"github.com/p-n-ai/pai-bot/internal/i18n"
+)
+
+const (
+	// accessOperatorChannel is where operators run /approve and receive new
+	// request alerts; operator IDs are Telegram user IDs.
+	accessOperatorChannel = "telegram"
+	// accessPendingListLimit keeps /pending to one chat message.
+	accessPendingListLimit = 20
+)
+
+// AccessStatus is where a learner stands with the access gate.
+type AccessStatus string
+
+const (
+	AccessPending  AccessStatus = "pending"
+	AccessApproved AccessStatus = "approved"
+	AccessRevoked  AccessStatus = "revoked"
+)
+
+// Ways a learner can be let in.
+const (
+	AccessViaOperator   = "operator"
+	AccessViaInviteCode = "invite_code"
+)
+
+// AccessRequest is one learner's standing with the access gate.
+type AccessRequest struct {
+	Channel     string
+	UserID      string
+	DisplayName string
+	Status      AccessStatus
+	GrantedVia  string
+	DecidedBy   string
+	RequestedAt time.Time
+	DecidedAt   *time.Time
+}
+
+// AccessStore persists access requests and decisions. Learners are keyed by
+// channel and external ID since they may not have a user record yet.
+type AccessStore interface {
+	GetAccessRequest(channel, userID string) (*AccessRequest, bool, error)
+	// RequestAccess records a pending request and reports whether it is new.
+	// An existing request keeps its status.
+	RequestAccess(channel, userID, displayName string) (bool, error)
+	SetAccessStatus(channel, userID string, status AccessStatus, via, decidedBy string) error
+	ListAccessRequests(status AccessStatus, limit int) ([]AccessRequest, error)
+}
+
+// AccessGateConfig turns on the access gate for a closed pilot.
+type AccessGateConfig struct {
+	// Channels lists the chat channels that require approval. Empty disables
+	// the gate.
+	Channels []string
+	// InviteCodes approve a learner who sends one, as "/start CODE" or on its
+	// own. Matching ignores case.
+	InviteCodes []string
+	// Operators are Telegram user IDs allowed to /approve, /revoke and
+	// /pending, and to edit canned answers with /faq. They are never gated
+	// and are alerted to new requests.
+	Operators []string
+}
+
+// MemoryAccessStore is an in-memory AccessStore.
+type MemoryAccessStore struct {
+	mu       sync.RWMutex
+	requests map[string]AccessRequest
+}
+
+func NewMemoryAccessStore() *MemoryAccessStore {
+	return &MemoryAccessStore{requests: make(map[string]AccessRequest)}
+}
+
+func accessKey(channel, userID string) string {
+	return channel + ":" + userID
+}
+
+func (s *MemoryAccessStore) GetAccessRequest(channel, userID string) (*AccessRequest, bool, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	req, ok := s.requests[accessKey(channel, userID)]
+	if !ok {
+		return nil, false, nil
+	}
+	return &req, true, nil
+}
+
+func (s *MemoryAccessStore) RequestAccess(channel, userID, displayName string) (bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	key := accessKey(channel, userID)
+	if _, ok := s.requests[key]; ok {
+		return false, nil
+	}
+	s.requests[key] = AccessRequest{
+		Channel:     channel,
+		UserID:      userID,
+		DisplayName: displayName,
+		Status:      AccessPending,
+		RequestedAt: time.Now(),
+	}
+	return true, nil
+}
+
+func (s *MemoryAccessStore) SetAccessStatus(channel, userID string, status AccessStatus, via, decidedBy string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	key := accessKey(channel, userID)
+	now := time.Now()
+	req, ok := s.requests[key]
+	if !ok {
+		req = AccessRequest{Channel: channel, UserID: userID, RequestedAt: now}
+	}
+	req.Status = status
+	req.GrantedVia = via
+	req.DecidedBy = decidedBy
+	req.DecidedAt = &now
+	s.requests[key] = req
+	return nil
+}
+
+func (s *MemoryAccessStore) ListAccessRequests(status AccessStatus, limit int) ([]AccessRequest, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	var out []AccessRequest
+	for _, req := range s.requests {
+		if req.Status == status {
+			out = append(out, req)
+		}
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].RequestedAt.Before(out[j].RequestedAt) })
+	if limit > 0 && len(out) > limit {
+		out = out[:limit]
+	}
+	return out, nil
+}
+
+// PostgresAccessStore persists access requests in PostgreSQL.
+type PostgresAccessStore struct {
+	pool     *pgxpool.Pool
+	tenantID string
+}
+
+func NewPostgresAccessStore(pool *pgxpool.Pool, tenantID string) *PostgresAccessStore {
+	return &PostgresAccessStore{pool: pool, tenantID: tenantID}
+}
+
+func (s *PostgresAccessStore) GetAccessRequest(channel, userID string) (*AccessRequest, bool, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), dbTimeout)
+	defer cancel()
+
+	req := AccessRequest{Channel: channel, UserID: userID}
+	var status string
+	err := s.pool.QueryRow(ctx,
+		`SELECT display_name, status, granted_via, decided_by, requested_at, decided_at
+		 FROM access_requests
+		 WHERE tenant_id = $1::uuid AND channel = $2 AND external_id = $3`,
+		s.tenantID, channel, userID,
+	).Scan(&req.DisplayName, &status, &req.GrantedVia, &req.DecidedBy, &req.RequestedAt, &req.DecidedAt)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, false, nil
+	}
+	if err != nil {
+		return nil, false, fmt.Errorf("get access request: %w", err)
+	}
+	req.Status = AccessStatus(status)
+	return &req, true, nil
+}
+
+func (s *PostgresAccessStore) RequestAccess(channel, userID, displayName string) (bool, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), dbTimeout)
+	defer cancel()
+
+	cmd, err := s.pool.Exec(ctx,
+		`INSERT INTO access_requests (tenant_id, channel, external_id, display_name)
+		 VALUES ($1::uuid, $2, $3, $4)
+		 ON CONFLICT (tenant_id, channel, external_id) DO NOTHING`,
+		s.tenantID, channel, userID, displayName,
+	)
+	if err != nil {
+		return false, fmt.Errorf("request access: %w", err)
+	}
+	return cmd.RowsAffected() == 1, nil
+}
+
+func (s *PostgresAccessStore) SetAccessStatus(channel, userID string, status AccessStatus, via, decidedBy string) error {
+	ctx, cancel := context.WithTimeout(context.Background(), dbTimeout)
+	defer cancel()
+
+	if _, err := s.pool.Exec(ctx,
+		`INSERT INTO access_requests (tenant_id, channel, external_id, status, granted_via, decided_by, decided_at)
+		 VALUES ($1::uuid, $2, $3, $4, $5, $6, NOW())
+		 ON CONFLICT (tenant_id, channel, external_id) DO UPDATE
+		 SET status = EXCLUDED.status,
+		     granted_via = EXCLUDED.granted_via,
+		     decided_by = EXCLUDED.decided_by,
+		     decided_at = EXCLUDED.decided_at`,
+		s.tenantID, channel, userID, string(status), via, decidedBy,
+	); err != nil {
+		return fmt.Errorf("set access status: %w", err)
+	}
+	return nil
+}
+
+func (s *PostgresAccessStore) ListAccessRequests(status AccessStatus, limit int) ([]AccessRequest, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), dbTimeout)
+	defer cancel()
+
+	rows, err := s.pool.Query(ctx,
+		`SELECT channel, external_id, display_name, status, granted_via, decided_by, requested_at, decided_at
+		 FROM access_requests
+		 WHERE tenant_id = $1::uuid AND status = $2
+		 ORDER BY requested_at ASC
+		 LIMIT $3`,
+		s.tenantID, string(status), limit,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("list access requests: %w", err)
+	}
+	defer rows.Close()
+
+	var out []AccessRequest
+	for rows.Next() {
+		var req AccessRequest
+		var st string
+		if err := rows.Scan(&req.Channel, &req.UserID, &req.DisplayName, &st, &req.GrantedVia, &req.DecidedBy, &req.RequestedAt, &req.DecidedAt); err != nil {
+			return nil, fmt.Errorf("scan access request: %w", err)
+		}
+		req.Status = AccessStatus(st)
+		out = append(out, req)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterate access requests: %w", err)
+	}
+	return out, nil
+}
+
+func (e *Engine) accessGated(msg chat.InboundMessage) bool {
+	return e.access != nil && slices.Contains(e.accessGate.Channels, msg.Channel) && !e.isAccessOperator(msg)
+}
+
+func (e *Engine) isAccessOperator(msg chat.InboundMessage) bool {
+	return msg.Channel == accessOperatorChannel && slices.Contains(e.accessGate.Operators, msg.UserID)
+}
+
+// checkAccess answers messages from learners the gate has not let in. It
+// returns handled=false when msg may be processed normally.
+func (e *Engine) checkAccess(msg chat.InboundMessage) (string, bool) {
+	if !e.accessGated(msg) {
+		return "", false
+	}
+	locale := e.messageLocale(msg, nil)
+	req, found, err := e.access.GetAccessRequest(msg.Channel, msg.UserID)
+	if err != nil {
+		// Fail closed: a pilot gate that opens on database errors is no gate.
+		slog.Error("failed to check access", "channel", msg.Channel, "user_id", msg.UserID, "error", err)
+		if !msg.ExpectsReply() {
+			return "", true
+		}
+		return i18n.S(locale, i18n.MsgTechnicalIssue), true
+	}
+	if found && req.Status == AccessApproved {
+		return "", false
+	}
+	if !msg.ExpectsReply() {
+		return "", true
+	}
+	if (!found || req.Status == AccessPending) && e.matchesInviteCode(msg.Text) {
+		if err := e.access.SetAccessStatus(msg.Channel, msg.UserID, AccessApproved, AccessViaInviteCode, ""); err != nil {
+			slog.Error("failed to approve invite code", "channel", msg.Channel, "user_id", msg.UserID, "error", err)
+			return i18n.S(locale, i18n.MsgTechnicalIssue), true
+		}
+		slog.Info("access granted", "channel", msg.Channel, "user_id", msg.UserID, "via", AccessViaInviteCode)
+		welcome, err := e.handleStart(msg.UserID, msg)
+		if err != nil {
+			slog.Error("failed to start onboarding after invite code", "user_id", msg.UserID, "error", err)
+			return i18n.S(locale, i18n.MsgAccessGranted), true
+		}
+		return i18n.S(locale, i18n.MsgAccessGranted) + "\n\n" + welcome, true
+	}
+	if found {
+		return i18n.S(locale, i18n.MsgAccessPending), true
+	}
+
+	created, err := e.access.RequestAccess(msg.Channel, msg.UserID, preferredIncomingName(msg))
+	if err != nil {
+		slog.Error("failed to record access request", "channel", msg.Channel, "user_id", msg.UserID, "error", err)
+		return i18n.S(locale, i18n.MsgTechnicalIssue), true
+	}
+	if created {
+		e.alertAccessOperators(msg)
+	}
+	return i18n.S(locale, i18n.MsgAccessRequested), true
+}
+
+// matchesInviteCode accepts "/start CODE" or the code on its own.
+func (e *Engine) matchesInviteCode(text string) bool {
+	fields := strings.Fields(text)
+	if len(fields) == 2 && fields[0] == "/start" {
+		fields = fields[1:]
+	}
+	if len(fields) != 1 {
+		return false
+	}
+	for _, code := range e.accessGate.InviteCodes {
+		if code != "" && strings.EqualFold(fields[0], code) {
+			return true
+		}
+	}
+	return false
+}
+
+func (e *Engine) alertAccessOperators(msg chat.InboundMessage) {
+	name := preferredIncomingName(msg)
+	if name == "" {
+		name = "(no name)"
+	}
+	text := fmt.Sprintf("Access request from %s on %s.\nApprove with /approve %s", name, msg.Channel, accessTarget(msg.Channel, msg.UserID))
+	for _, op := range e.accessGate.Operators {
+		e.notifier.Notify(context.Background(), accessOperatorChannel, op, text)
+	}
+}
+
+// accessTarget names a learner in operator commands. Telegram learners are
+// written by ID alone.
+func accessTarget(channel, userID string) string {
+	if channel == accessOperatorChannel {
+		return userID
+	}
+	return channel + ":" + userID
+}
+
+func parseAccessTarget(arg string) (string, string) {
+	if channel, userID, ok := strings.Cut(arg, ":"); ok && channel != "" && userID != "" {
+		return channel, userID
+	}
+	return accessOperatorChannel, arg
+}
+
+// handleAccessCommand runs /approve, /revoke and /pending for operators.
+func (e *Engine) handleAccessCommand(ctx context.Context, msg chat.InboundMessage, cmd string, args []string) (string, error) {
+	if e.access == nil || !e.isAccessOperator(msg) {
+		return i18n.S(e.messageLocale(msg, nil), i18n.MsgUnknownCommand, cmd), nil
+	}
+	if cmd == "/pending" {
+		pending, err := e.access.ListAccessRequests(AccessPending, accessPendingListLimit)
+		if err != nil {
+			slog.Error("failed to list access requests", "error", err)
+			return "Could not load pending requests.", nil
+		}
+		if len(pending) == 0 {
+			return "No pending access requests.", nil
+		}
+		var b strings.Builder
+		b.WriteString("Pending access requests:")
+		for _, req := range pending {
+			name := req.DisplayName
+			if name == "" {
+				name = "(no name)"
+			}
+			fmt.Fprintf(&b, "\n%s · %s · %s", accessTarget(req.Channel, req.UserID), name, req.RequestedAt.Format("2006-01-02"))
+		}
+		return b.String(), nil
+	}
+
+	if len(args) != 1 {
+		return fmt.Sprintf("Usage: %s <user_id> or %s <channel>:<user_id>", cmd, cmd), nil
+	}
+	channel, userID := parseAccessTarget(args[0])
+	status := AccessApproved
+	via := AccessViaOperator
+	if cmd == "/revoke" {
+		status = AccessRevoked
+		via = ""
+	}
+	if err := e.access.SetAccessStatus(channel, userID, status, via, msg.UserID); err != nil {
+		slog.Error("failed to set access status", "channel", channel, "user_id", userID, "status", status, "error", err)
+		return "Could not update access.", nil
+	}
+	slog.Info("access updated", "channel", channel, "user_id", userID, "status", status, "operator", msg.UserID)
+	target := accessTarget(channel, userID)
+	if status == AccessRevoked {
+		return fmt.Sprintf("Access revoked for %s.", target), nil
+	}
+	locale := e.resolveUserLocale(userID)
+	e.notifier.Notify(ctx, channel, userID, i18n.S(locale, i18n.MsgAccessApproved))
+	return fmt.Sprintf("Access approved for %s.", target), nil
+}
@@ -0,0 +1,131 @@
+// Copyright 2026 the P&AI authors. All rights reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package agent_test
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/p-n-ai/pai-bot/internal/agent"
+	"github.com/p-n-ai/pai-bot/internal/ai"
+	"github.com/p-n-ai/pai-bot/internal/chat"
+	"github.com/p-n-ai/pai-bot/internal/i18n"
+)
+
+const accessOperatorID = "9001"
+
+func newGatedEngine(t *testing.T) (*agent.Engine, *agent.MemoryAccessStore, *capturingNotifier, *ai.MockProvider) {
+	t.Helper()
+	mockAI := ai.NewMockProvider("Jawapan tutor.")
+	access := agent.NewMemoryAccessStore()
+	notifier := &capturingNotifier{}
+	engine := agent.NewEngine(agent.EngineConfig{
+		AIRouter: mockRouter(mockAI),
+		Notifier: notifier,
+		Access:   access,
+		AccessGate: agent.AccessGateConfig{
+			Channels:    []string{"telegram"},
+			InviteCodes: []string{"PILOT-KL"},
+			Operators:   []string{accessOperatorID},
+		},
+	})
+	return engine, access, notifier, mockAI
+}
+
+func sendAs(t *testing.T, engine *agent.Engine, channel, userID, text string) string {
+	t.Helper()
+	resp, err := engine.ProcessMessage(context.Background(), chat.InboundMessage{
+		Channel: channel, UserID: userID, Text: text, FirstName: "Aina", Language: "en",
+	})
+	if err != nil {
+		t.Fatalf("ProcessMessage(%q) error = %v", text, err)
+	}
+	return resp
+}
+
+func TestAccessGateRecordsRequestAndAlertsOperators(t *testing.T) {
+	engine, access, notifier, mockAI := newGatedEngine(t)
+
+	if got := sendAs(t, engine, "telegram", "42", "What is a fraction?"); got != i18n.S("en", i18n.MsgAccessRequested) {
+		t.Fatalf("first reply = %q, want the access-requested message", got)
+	}
+	if got := sendAs(t, engine, "telegram", "42", "hello?"); got != i18n.S("en", i18n.MsgAccessPending) {
+		t.Fatalf("second reply = %q, want the pending message", got)
+	}
+	if mockAI.LastRequest != nil {
+		t.Error("gated learner reached the model")
+	}
+
+	req, found, _ := access.GetAccessRequest("telegram", "42")
+	if !found || req.Status != agent.AccessPending || req.DisplayName != "Aina" {
+		t.Fatalf("access request = %+v, %v", req, found)
+	}
+	if len(notifier.sent) != 1 {
+		t.Fatalf("operator alerts = %d, want 1 for the first request only", len(notifier.sent))
+	}
+	alert := notifier.sent[0]
+	if alert.channel != "telegram" || alert.userID != accessOperatorID || !strings.Contains(alert.text, "/approve 42") {
+		t.Errorf("operator alert = %+v", alert)
+	}
+}
+
+func TestAccessGateOperatorApprovesAndRevokes(t *testing.T) {
+	engine, access, notifier, _ := newGatedEngine(t)
+	sendAs(t, engine, "telegram", "42", "hi")
+
+	// A learner on an open channel cannot use operator commands.
+	if got := sendAs(t, engine, "websocket", "77", "/approve 42"); !strings.Contains(got, "Unknown command") {
+		t.Fatalf("non-operator /approve reply = %q, want unknown command", got)
+	}
+	if got := sendAs(t, engine, "telegram", accessOperatorID, "/pending"); !strings.Contains(got, "42 · Aina") {
+		t.Fatalf("/pending = %q, want the waiting learner", got)
+	}
+	if got := sendAs(t, engine, "telegram", accessOperatorID, "/approve 42"); got != "Access approved for 42." {
+		t.Fatalf("/approve reply = %q", got)
+	}
+	req, _, _ := access.GetAccessRequest("telegram", "42")
+	if req.Status != agent.AccessApproved || req.GrantedVia != agent.AccessViaOperator || req.DecidedBy != accessOperatorID {
+		t.Fatalf("access request after approve = %+v", req)
+	}
+	last := notifier.sent[len(notifier.sent)-1]
+	if last.userID != "42" || !strings.Contains(last.text, "/start") {
+		t.Errorf("approval notice = %+v, want a message to the learner", last)
+	}
+	if got := sendAs(t, engine, "telegram", "42", "What is a fraction?"); got == i18n.S("en", i18n.MsgAccessPending) {
+		t.Fatal("approved learner is still gated")
+	}
+
+	sendAs(t, engine, "telegram", accessOperatorID, "/revoke 42")
+	if got := sendAs(t, engine, "telegram", "42", "again"); got != i18n.S("en", i18n.MsgAccessPending) {
+		t.Fatalf("revoked learner reply = %q, want the pending message", got)
+	}
+	if got := sendAs(t, engine, "telegram", "42", "PILOT-KL"); got != i18n.S("en", i18n.MsgAccessPending) {
+		t.Fatalf("revoked learner with invite code reply = %q, want still gated", got)
+	}
+}
+
+func TestAccessGateInviteCode(t *testing.T) {
+	engine, access, _, _ := newGatedEngine(t)
+
+	got := sendAs(t, engine, "telegram", "55", "/start pilot-kl")
+	if !strings.HasPrefix(got, i18n.S("en", i18n.MsgAccessGranted)) {
+		t.Fatalf("invite code reply = %q, want the granted message first", got)
+	}
+	req, _, _ := access.GetAccessRequest("telegram", "55")
+	if req.Status != agent.AccessApproved || req.GrantedVia != agent.AccessViaInviteCode {
+		t.Fatalf("access request = %+v, want approved via invite code", req)
+	}
+}
+
+func TestAccessGateLeavesOtherChannelsOpen(t *testing.T) {
+	engine, _, _, mockAI := newGatedEngine(t)
+
+	if got := sendAs(t, engine, "websocket", "42", "What is a fraction?"); got != "Jawapan tutor." {
+		t.Fatalf("ungated channel reply = %q, want the tutor answer", got)
+	}
+	if mockAI.LastRequest == nil {
+		t.Error("ungated channel did not reach the model")
+	}
+}
@@ -0,0 +1,129 @@
+// Copyright 2026 the P&AI authors. All rights reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package agent
+
+import (
+	"context"
+	"log/slog"
+	"regexp"
+	"time"
+
+	"github.com/p-n-ai/pai-bot/internal/chat"
+	"github.com/p-n-ai/pai-bot/internal/i18n"
+	"github.com/p-n-ai/pai-bot/internal/platform/featureflags"
+)
+
+const (
+	// backgroundTurnWorkers bounds heavy turns running after their
+	// acknowledgement; further heavy requests are answered inline.
+	backgroundTurnWorkers = 4
+	// backgroundTurnTimeout caps one background turn, model retries included.
+	backgroundTurnTimeout = 3 * time.Minute
+)
+
+// worksheetRequestPattern matches requests for a whole worksheet or paper,
+// which take long enough to generate that the turn should not stay open.
+var worksheetRequestPattern = regexp.MustCompile(`(?i)\b(worksheets?|lembaran kerja|kertas latihan|practice (paper|set)|set latihan)\b|练习卷|工作纸`)
+
+// backgroundTurns runs acknowledged heavy turns on a fixed number of slots.
+type backgroundTurns struct {
+	slots chan struct{}
+}
+
+func newBackgroundTurns(workers int) *backgroundTurns {
+	return &backgroundTurns{slots: make(chan struct{}, workers)}
+}
+
+// tryAcquire claims a slot without waiting.
+func (b *backgroundTurns) tryAcquire() bool {
+	select {
+	case b.slots <- struct{}{}:
+		return true
+	default:
+		return false
+	}
+}
+
+func (b *backgroundTurns) release() {
+	<-b.slots
+}
+
+// isHeavyTurn reports whether msg asks for work slow enough to answer as a
+// follow-up: analysing an attached image or generating a worksheet.
+func isHeavyTurn(msg chat.InboundMessage) bool {
+	if msg.HasImage && msg.ImageDataURL != "" {
+		return true
+	}
+	return worksheetRequestPattern.MatchString(msg.Text)
+}
+
+// maybeStartBackgroundTurn acknowledges a heavy teaching request straight
+// away and runs the turn on a background worker, delivering the answer as a
+// follow-up message when it is ready. It declines, so the turn runs inline,
+// when the feature is off, the channel cannot push a later message, no turn
+// deliverer is installed, or every worker is busy.
+func (e *Engine) maybeStartBackgroundTurn(ctx context.Context, msg chat.InboundMessage, conv *Conversation, responsePrefix string) (string, bool) {
+	if !e.featureFlags().Enabled(featureflags.BackgroundTurns) || !isHeavyTurn(msg) {
+		return "", false
+	}
+	if msg.Channel != "telegram" && msg.Channel != "whatsapp" {
+		return "", false
+	}
+	if e.turnDeliverer == nil {
+		return "", false
+	}
+	if !e.backgroundTurns.tryAcquire() {
+		slog.Info("background turn workers busy, answering inline", "channel", msg.Channel, "user_id", msg.UserID)
+		return "", false
+	}
+
+	e.logEventAsync(Event{
+		ConversationID: conv.ID,
+		UserID:         msg.UserID,
+		EventType:      "background_turn_started",
+		Data: map[string]any{
+			"channel":   msg.Channel,
+			"has_image": msg.HasImage,
+		},
+	})
+	jobCtx := context.WithoutCancel(ctx)
+	go func() {
+		defer e.backgroundTurns.release()
+		e.runBackgroundTurn(jobCtx, msg, conv, responsePrefix)
+	}()
+	return i18n.S(e.messageLocale(msg, conv), i18n.MsgWorkingOnIt), true
+}
+
+// runBackgroundTurn runs the teaching turn under the learner's turn lock, so
+// messages sent meanwhile are answered after it, and delivers the result.
+func (e *Engine) runBackgroundTurn(ctx context.Context, msg chat.InboundMessage, conv *Conversation, responsePrefix string) {
+	ctx, cancel := context.WithTimeout(ctx, backgroundTurnTimeout)
+	defer cancel()
+	unlock := e.turnLocks.lock(msg.Channel + "\x00" + msg.UserID)
+	defer unlock()
+
+	startedAt := time.Now()
+	result := TurnResult{}
+	text, err := e.runTeachingTurn(ctx, msg, conv, responsePrefix, &result)
+	if err != nil {
+		text = e.errorReply(e.messageLocale(msg, conv), msg.UserID, conv.ID, "background_turn", err)
+	}
+	result.Text = e.screenReply(msg, text)
+
+	status := "delivered"
+	if err := e.turnDeliverer.DeliverTurn(ctx, msg, result); err != nil {
+		slog.Error("background turn delivery failed", "channel", msg.Channel, "user_id", msg.UserID, "error", err)
+		status = "delivery_failed"
+	}
+	e.logEventAsync(Event{
+		ConversationID: conv.ID,
+		UserID:         msg.UserID,
+		EventType:      "background_turn_completed",
+		Data: map[string]any{
+			"channel":    msg.Channel,
+			"status":     status,
+			"latency_ms": time.Since(startedAt).Milliseconds(),
+		},
+	})
+}
@@ -0,0 +1,95 @@
+// Copyright 2026 the P&AI authors. All rights reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package agent_test
+
+import (
+	"context"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/p-n-ai/pai-bot/internal/agent"
+	"github.com/p-n-ai/pai-bot/internal/ai"
+	"github.com/p-n-ai/pai-bot/internal/chat"
+	"github.com/p-n-ai/pai-bot/internal/platform/featureflags"
+)
+
+type capturingTurnDeliverer struct {
+	mu      sync.Mutex
+	results []agent.TurnResult
+}
+
+func (d *capturingTurnDeliverer) DeliverTurn(_ context.Context, _ chat.InboundMessage, result agent.TurnResult) error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.results = append(d.results, result)
+	return nil
+}
+
+func (d *capturingTurnDeliverer) waitFor(t *testing.T, n int) []agent.TurnResult {
+	t.Helper()
+	deadline := time.Now().Add(2 * time.Second)
+	for time.Now().Before(deadline) {
+		d.mu.Lock()
+		results := append([]agent.TurnResult(nil), d.results...)
+		d.mu.Unlock()
+		if len(results) >= n {
+			return results
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+	t.Fatalf("delivered turns did not reach %d", n)
+	return nil
+}
+
+func newBackgroundTurnEngine(t *testing.T, flags string) (*agent.Engine, *capturingTurnDeliverer) {
+	t.Helper()
+	features, err := featureflags.Parse(flags)
+	if err != nil {
+		t.Fatal(err)
+	}
+	deliverer := &capturingTurnDeliverer{}
+	engine := agent.NewEngine(agent.EngineConfig{
+		AIRouter:      mockRouter(ai.NewMockProvider("1. Solve 2x + 3 = 7.\n2. Solve 5x = 20.")),
+		Store:         agent.NewMemoryStore(),
+		FeatureFlags:  func() featureflags.Features { return features },
+		TurnDeliverer: deliverer,
+	})
+	return engine, deliverer
+}
+
+func TestWorksheetRequestIsAcknowledgedAndAnsweredAsFollowUp(t *testing.T) {
+	engine, deliverer := newBackgroundTurnEngine(t, "background_turns")
+
+	ack := sendAs(t, engine, "telegram", "42", "Can you make me a worksheet on linear equations?")
+	if !strings.Contains(ack, "Working on it") {
+		t.Fatalf("reply = %q, want an immediate acknowledgement", ack)
+	}
+	results := deliverer.waitFor(t, 1)
+	if !strings.Contains(results[0].Text, "Solve 2x + 3 = 7") {
+		t.Fatalf("follow-up = %q, want the generated worksheet", results[0].Text)
+	}
+}
+
+func TestBackgroundTurnsAnswerInlineWhenOffOrNotHeavy(t *testing.T) {
+	engine, deliverer := newBackgroundTurnEngine(t, "")
+	if reply := sendAs(t, engine, "telegram", "42", "Make me a worksheet on linear equations"); !strings.Contains(reply, "Solve 2x + 3 = 7") {
+		t.Fatalf("reply with flag off = %q, want the answer inline", reply)
+	}
+
+	engine, deliverer = newBackgroundTurnEngine(t, "background_turns")
+	if reply := sendAs(t, engine, "telegram", "42", "How do I solve 2x + 3 = 7?"); !strings.Contains(reply, "Solve 2x + 3 = 7") {
+		t.Fatalf("reply to a light question = %q, want the answer inline", reply)
+	}
+	if reply := sendAs(t, engine, "websocket", "43", "Make me a worksheet on linear equations"); !strings.Contains(reply, "Solve 2x + 3 = 7") {
+		t.Fatalf("websocket reply = %q, want the answer inline", reply)
+	}
+	time.Sleep(20 * time.Millisecond)
+	deliverer.mu.Lock()
+	defer deliverer.mu.Unlock()
+	if len(deliverer.results) != 0 {
+		t.Fatalf("follow-ups = %+v, want none", deliverer.results)
+	}
+}
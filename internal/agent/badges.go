@@ -0,0 +1,275 @@
+// Copyright 2026 the P&AI authors. All rights reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package agent
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"github.com/p-n-ai/pai-bot/internal/i18n"
+)
+
+// BadgeID names an achievement badge.
+type BadgeID string
+
+const (
+	BadgeFirstQuiz     BadgeID = "first_quiz"
+	BadgeStreak7       BadgeID = "streak_7"
+	BadgeTopicMastered BadgeID = "topic_mastered"
+	BadgeProblems100   BadgeID = "problems_100"
+)
+
+// badgeOrder is the order /progress lists earned badges in.
+var badgeOrder = []BadgeID{BadgeFirstQuiz, BadgeTopicMastered, BadgeStreak7, BadgeProblems100}
+
+var badgeNames = map[BadgeID]i18n.Key{
+	BadgeFirstQuiz:     i18n.MsgBadgeFirstQuiz,
+	BadgeStreak7:       i18n.MsgBadgeStreak7,
+	BadgeTopicMastered: i18n.MsgBadgeTopicMastered,
+	BadgeProblems100:   i18n.MsgBadgeProblems100,
+}
+
+const (
+	// problemsSolvedCounter tallies correct quiz answers toward BadgeProblems100.
+	problemsSolvedCounter = "problems_solved"
+	problemsBadgeTarget   = 100
+	streakBadgeDays       = 7
+)
+
+// EarnedBadge is a badge a learner holds.
+type EarnedBadge struct {
+	ID       BadgeID
+	EarnedAt time.Time
+}
+
+// BadgeStore persists earned badges and the running counts that count-based
+// badges are evaluated against.
+type BadgeStore interface {
+	// AwardBadge records badge for the user and reports whether it is new.
+	AwardBadge(userID string, badge BadgeID) (bool, error)
+	ListBadges(userID string) ([]EarnedBadge, error)
+	// IncrementBadgeCounter adds one to the user's counter and returns the
+	// new total.
+	IncrementBadgeCounter(userID, counter string) (int, error)
+}
+
+// MemoryBadgeStore is an in-memory BadgeStore.
+type MemoryBadgeStore struct {
+	mu       sync.Mutex
+	badges   map[string][]EarnedBadge
+	counters map[string]map[string]int
+}
+
+func NewMemoryBadgeStore() *MemoryBadgeStore {
+	return &MemoryBadgeStore{
+		badges:   make(map[string][]EarnedBadge),
+		counters: make(map[string]map[string]int),
+	}
+}
+
+func (s *MemoryBadgeStore) AwardBadge(userID string, badge BadgeID) (bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, earned := range s.badges[userID] {
+		if earned.ID == badge {
+			return false, nil
+		}
+	}
+	s.badges[userID] = append(s.badges[userID], EarnedBadge{ID: badge, EarnedAt: time.Now()})
+	return true, nil
+}
+
+func (s *MemoryBadgeStore) ListBadges(userID string) ([]EarnedBadge, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]EarnedBadge(nil), s.badges[userID]...), nil
+}
+
+func (s *MemoryBadgeStore) IncrementBadgeCounter(userID, counter string) (int, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.counters[userID] == nil {
+		s.counters[userID] = make(map[string]int)
+	}
+	s.counters[userID][counter]++
+	return s.counters[userID][counter], nil
+}
+
+// PostgresBadgeStore persists badges in PostgreSQL.
+type PostgresBadgeStore struct {
+	pool     *pgxpool.Pool
+	tenantID string
+}
+
+func NewPostgresBadgeStore(pool *pgxpool.Pool, tenantID string) *PostgresBadgeStore {
+	return &PostgresBadgeStore{pool: pool, tenantID: tenantID}
+}
+
+const badgeUserSQL = `SELECT id FROM users
+	WHERE tenant_id = $1::uuid AND external_id = $2
+	ORDER BY created_at ASC
+	LIMIT 1`
+
+func (s *PostgresBadgeStore) AwardBadge(externalID string, badge BadgeID) (bool, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), dbTimeout)
+	defer cancel()
+
+	tag, err := s.pool.Exec(ctx,
+		`INSERT INTO user_badges (user_id, tenant_id, badge_id)
+		 SELECT u.id, $1::uuid, $3 FROM (`+badgeUserSQL+`) u
+		 ON CONFLICT (user_id, badge_id) DO NOTHING`,
+		s.tenantID, externalID, string(badge),
+	)
+	if err != nil {
+		return false, fmt.Errorf("award badge: %w", err)
+	}
+	return tag.RowsAffected() == 1, nil
+}
+
+func (s *PostgresBadgeStore) ListBadges(externalID string) ([]EarnedBadge, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), dbTimeout)
+	defer cancel()
+
+	rows, err := s.pool.Query(ctx,
+		`SELECT b.badge_id, b.earned_at
+		 FROM user_badges b
+		 WHERE b.user_id = (`+badgeUserSQL+`)
+		 ORDER BY b.earned_at ASC`,
+		s.tenantID, externalID,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("list badges: %w", err)
+	}
+	defer rows.Close()
+
+	var badges []EarnedBadge
+	for rows.Next() {
+		var badge EarnedBadge
+		if err := rows.Scan(&badge.ID, &badge.EarnedAt); err != nil {
+			return nil, fmt.Errorf("scan badge: %w", err)
+		}
+		badges = append(badges, badge)
+	}
+	return badges, rows.Err()
+}
+
+func (s *PostgresBadgeStore) IncrementBadgeCounter(externalID, counter string) (int, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), dbTimeout)
+	defer cancel()
+
+	var value int
+	err := s.pool.QueryRow(ctx,
+		`INSERT INTO user_badge_counters (user_id, tenant_id, counter, value)
+		 SELECT u.id, $1::uuid, $3, 1 FROM (`+badgeUserSQL+`) u
+		 ON CONFLICT (user_id, counter) DO UPDATE
+		 SET value = user_badge_counters.value + 1, updated_at = NOW()
+		 RETURNING value`,
+		s.tenantID, externalID, counter,
+	).Scan(&value)
+	if err != nil {
+		return 0, fmt.Errorf("increment badge counter: %w", err)
+	}
+	return value, nil
+}
+
+// badgesForEvent reports the badges event earns. Count-based badges bump
+// their counter here, so each event must be evaluated once.
+func (e *Engine) badgesForEvent(event Event) []BadgeID {
+	switch event.EventType {
+	case "quiz_completed":
+		return []BadgeID{BadgeFirstQuiz}
+	case "topic_mastered":
+		return []BadgeID{BadgeTopicMastered}
+	case "streak_milestone":
+		if days, _ := event.Data["streak_days"].(int); days >= streakBadgeDays {
+			return []BadgeID{BadgeStreak7}
+		}
+	case "quiz_answer_correct":
+		solved, err := e.badges.IncrementBadgeCounter(event.UserID, problemsSolvedCounter)
+		if err != nil {
+			slog.Warn("failed to count solved problem", "user_id", event.UserID, "error", err)
+			return nil
+		}
+		if solved >= problemsBadgeTarget {
+			return []BadgeID{BadgeProblems100}
+		}
+	}
+	return nil
+}
+
+// evaluateBadges awards the badges event earns and queues an announcement for
+// each new one, shown ahead of the learner's next reply.
+func (e *Engine) evaluateBadges(event Event) {
+	if e.badges == nil || event.UserID == "" {
+		return
+	}
+	for _, badge := range e.badgesForEvent(event) {
+		awarded, err := e.badges.AwardBadge(event.UserID, badge)
+		if err != nil {
+			slog.Warn("failed to award badge", "user_id", event.UserID, "badge", badge, "error", err)
+			continue
+		}
+		if !awarded {
+			continue
+		}
+		if e.milestones != nil {
+			locale := e.resolveUserLocale(event.UserID)
+			e.milestones.add(event.UserID, i18n.S(locale, i18n.MsgBadgeEarned, i18n.S(locale, badgeNames[badge])))
+		}
+		if event.ConversationID != "" {
+			e.logEventAsync(Event{
+				ConversationID: event.ConversationID,
+				UserID:         event.UserID,
+				EventType:      "badge_earned",
+				Data: map[string]any{
+					"badge": string(badge),
+				},
+			})
+		}
+	}
+}
+
+// evaluateTopicMasteredBadge evaluates a mastery crossing. Mastery comes from
+// progress tracking rather than a conversation turn, so the signal is
+// evaluated directly instead of being logged as an event.
+func (e *Engine) evaluateTopicMasteredBadge(userID, topicID string) {
+	e.evaluateBadges(Event{
+		UserID:    userID,
+		EventType: "topic_mastered",
+		Data:      map[string]any{"topic_id": topicID},
+	})
+}
+
+// appendBadgesToProgressReport lists the learner's earned badges under the
+// /progress report.
+func (e *Engine) appendBadgesToProgressReport(userID, locale, report string) string {
+	if e.badges == nil {
+		return report
+	}
+	earned, err := e.badges.ListBadges(userID)
+	if err != nil {
+		slog.Warn("failed to list badges", "user_id", userID, "error", err)
+		return report
+	}
+	if len(earned) == 0 {
+		return report
+	}
+	held := make(map[BadgeID]bool, len(earned))
+	for _, badge := range earned {
+		held[badge.ID] = true
+	}
+	var names []string
+	for _, badge := range badgeOrder {
+		if held[badge] {
+			names = append(names, i18n.S(locale, badgeNames[badge]))
+		}
+	}
+	return strings.TrimSpace(report) + "\n\n" + i18n.S(locale, i18n.MsgBadgesHeader) + "\n" + strings.Join(names, "\n")
+}
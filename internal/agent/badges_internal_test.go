@@ -0,0 +1,78 @@
+// Copyright 2026 the P&AI authors. All rights reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package agent
+
+import (
+	"strings"
+	"testing"
+)
+
+func newBadgeTestEngine(t *testing.T) (*Engine, *MemoryBadgeStore) {
+	t.Helper()
+	store := NewMemoryStore()
+	badges := NewMemoryBadgeStore()
+	engine := NewEngine(EngineConfig{Store: store, Badges: badges})
+	if err := store.SetUserPreferredLanguage("badge-user", "en"); err != nil {
+		t.Fatalf("SetUserPreferredLanguage() error = %v", err)
+	}
+	return engine, badges
+}
+
+func heldBadges(t *testing.T, badges *MemoryBadgeStore) []BadgeID {
+	t.Helper()
+	earned, err := badges.ListBadges("badge-user")
+	if err != nil {
+		t.Fatalf("ListBadges() error = %v", err)
+	}
+	ids := make([]BadgeID, len(earned))
+	for i, badge := range earned {
+		ids[i] = badge.ID
+	}
+	return ids
+}
+
+func TestEvaluateBadges_AwardsOnceAndAnnounces(t *testing.T) {
+	engine, badges := newBadgeTestEngine(t)
+	quiz := Event{UserID: "badge-user", EventType: "quiz_completed"}
+
+	engine.evaluateBadges(quiz)
+	engine.evaluateBadges(quiz)
+
+	if got := heldBadges(t, badges); len(got) != 1 || got[0] != BadgeFirstQuiz {
+		t.Fatalf("badges = %v, want only first_quiz", got)
+	}
+	announced := engine.milestones.drain("badge-user")
+	if len(announced) != 1 || !strings.Contains(announced[0], "First Quiz") {
+		t.Fatalf("announcements = %q, want one First Quiz announcement", announced)
+	}
+}
+
+func TestEvaluateBadges_StreakNeedsSevenDays(t *testing.T) {
+	engine, badges := newBadgeTestEngine(t)
+
+	engine.evaluateBadges(Event{UserID: "badge-user", EventType: "streak_milestone", Data: map[string]any{"streak_days": 3}})
+	if got := heldBadges(t, badges); len(got) != 0 {
+		t.Fatalf("badges after 3 days = %v, want none", got)
+	}
+	engine.evaluateBadges(Event{UserID: "badge-user", EventType: "streak_milestone", Data: map[string]any{"streak_days": 7}})
+	if got := heldBadges(t, badges); len(got) != 1 || got[0] != BadgeStreak7 {
+		t.Fatalf("badges after 7 days = %v, want streak_7", got)
+	}
+}
+
+func TestEvaluateBadges_HundredProblemsCountsCorrectAnswers(t *testing.T) {
+	engine, badges := newBadgeTestEngine(t)
+	answer := Event{UserID: "badge-user", EventType: "quiz_answer_correct"}
+
+	for range problemsBadgeTarget - 1 {
+		engine.evaluateBadges(answer)
+	}
+	if got := heldBadges(t, badges); len(got) != 0 {
+		t.Fatalf("badges after 99 answers = %v, want none", got)
+	}
+	engine.evaluateBadges(answer)
+	if got := heldBadges(t, badges); len(got) != 1 || got[0] != BadgeProblems100 {
+		t.Fatalf("badges after 100 answers = %v, want problems_100", got)
+	}
+}
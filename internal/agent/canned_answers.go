@@ -0,0 +1,413 @@
+// Copyright 2026 the P&AI authors. All rights reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package agent
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"maps"
+	"regexp"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+	"unicode"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"github.com/p-n-ai/pai-bot/internal/chat"
+	"github.com/p-n-ai/pai-bot/internal/i18n"
+)
+
+const (
+	// cannedAnswerCacheTTL bounds how long another replica's /faq edit takes
+	// to reach this one. Local edits clear the cache straight away.
+	cannedAnswerCacheTTL = time.Minute
+	// cannedAnswerMinPhraseLen stops one-word phrases like "hi" from
+	// swallowing real questions.
+	cannedAnswerMinPhraseLen = 4
+)
+
+var cannedAnswerKeyPattern = regexp.MustCompile(`^[a-z0-9][a-z0-9_-]{0,39}$`)
+
+// CannedAnswer is an operator-written reply served instead of a model turn
+// when a learner's message contains one of its phrases.
+type CannedAnswer struct {
+	Key     string
+	Phrases []string
+	// Answers maps a locale (ms, en, zh) to the exact reply text.
+	Answers   map[string]string
+	UpdatedBy string
+	UpdatedAt time.Time
+}
+
+// answerFor returns the reply for locale, falling back to the default locale,
+// then English, then any answer at all.
+func (a CannedAnswer) answerFor(locale string) string {
+	for _, loc := range []string{locale, i18n.DefaultLocale, "en"} {
+		if text := a.Answers[loc]; text != "" {
+			return text
+		}
+	}
+	for _, loc := range slices.Sorted(maps.Keys(a.Answers)) {
+		if text := a.Answers[loc]; text != "" {
+			return text
+		}
+	}
+	return ""
+}
+
+// CannedAnswerStore persists a tenant's canned answers.
+type CannedAnswerStore interface {
+	ListCannedAnswers() ([]CannedAnswer, error)
+	// PutCannedAnswer creates or replaces the answer with the same key.
+	PutCannedAnswer(answer CannedAnswer) error
+	// DeleteCannedAnswer reports whether an answer was removed.
+	DeleteCannedAnswer(key string) (bool, error)
+}
+
+// MemoryCannedAnswerStore is an in-memory CannedAnswerStore.
+type MemoryCannedAnswerStore struct {
+	mu      sync.RWMutex
+	answers map[string]CannedAnswer
+}
+
+func NewMemoryCannedAnswerStore() *MemoryCannedAnswerStore {
+	return &MemoryCannedAnswerStore{answers: make(map[string]CannedAnswer)}
+}
+
+func (s *MemoryCannedAnswerStore) ListCannedAnswers() ([]CannedAnswer, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	out := make([]CannedAnswer, 0, len(s.answers))
+	for _, key := range slices.Sorted(maps.Keys(s.answers)) {
+		out = append(out, cloneCannedAnswer(s.answers[key]))
+	}
+	return out, nil
+}
+
+func (s *MemoryCannedAnswerStore) PutCannedAnswer(answer CannedAnswer) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	answer = cloneCannedAnswer(answer)
+	answer.UpdatedAt = time.Now()
+	s.answers[answer.Key] = answer
+	return nil
+}
+
+func (s *MemoryCannedAnswerStore) DeleteCannedAnswer(key string) (bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	_, ok := s.answers[key]
+	delete(s.answers, key)
+	return ok, nil
+}
+
+func cloneCannedAnswer(a CannedAnswer) CannedAnswer {
+	a.Phrases = slices.Clone(a.Phrases)
+	a.Answers = maps.Clone(a.Answers)
+	return a
+}
+
+// PostgresCannedAnswerStore persists canned answers in PostgreSQL.
+type PostgresCannedAnswerStore struct {
+	pool     *pgxpool.Pool
+	tenantID string
+}
+
+func NewPostgresCannedAnswerStore(pool *pgxpool.Pool, tenantID string) *PostgresCannedAnswerStore {
+	return &PostgresCannedAnswerStore{pool: pool, tenantID: tenantID}
+}
+
+func (s *PostgresCannedAnswerStore) ListCannedAnswers() ([]CannedAnswer, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), dbTimeout)
+	defer cancel()
+
+	rows, err := s.pool.Query(ctx,
+		`SELECT key, phrases, answers, updated_by, updated_at
+		 FROM canned_answers
+		 WHERE tenant_id = $1::uuid
+		 ORDER BY key`,
+		s.tenantID,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("list canned answers: %w", err)
+	}
+	defer rows.Close()
+
+	var out []CannedAnswer
+	for rows.Next() {
+		var a CannedAnswer
+		if err := rows.Scan(&a.Key, &a.Phrases, &a.Answers, &a.UpdatedBy, &a.UpdatedAt); err != nil {
+			return nil, fmt.Errorf("scan canned answer: %w", err)
+		}
+		out = append(out, a)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterate canned answers: %w", err)
+	}
+	return out, nil
+}
+
+func (s *PostgresCannedAnswerStore) PutCannedAnswer(answer CannedAnswer) error {
+	ctx, cancel := context.WithTimeout(context.Background(), dbTimeout)
+	defer cancel()
+
+	phrases := answer.Phrases
+	if phrases == nil {
+		phrases = []string{}
+	}
+	answers := answer.Answers
+	if answers == nil {
+		answers = map[string]string{}
+	}
+	if _, err := s.pool.Exec(ctx,
+		`INSERT INTO canned_answers (tenant_id, key, phrases, answers, updated_by)
+		 VALUES ($1::uuid, $2, $3, $4, $5)
+		 ON CONFLICT (tenant_id, key) DO UPDATE
+		 SET phrases = EXCLUDED.phrases,
+		     answers = EXCLUDED.answers,
+		     updated_by = EXCLUDED.updated_by,
+		     updated_at = NOW()`,
+		s.tenantID, answer.Key, phrases, answers, answer.UpdatedBy,
+	); err != nil {
+		return fmt.Errorf("put canned answer: %w", err)
+	}
+	return nil
+}
+
+func (s *PostgresCannedAnswerStore) DeleteCannedAnswer(key string) (bool, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), dbTimeout)
+	defer cancel()
+
+	cmd, err := s.pool.Exec(ctx,
+		`DELETE FROM canned_answers WHERE tenant_id = $1::uuid AND key = $2`,
+		s.tenantID, key,
+	)
+	if err != nil {
+		return false, fmt.Errorf("delete canned answer: %w", err)
+	}
+	return cmd.RowsAffected() > 0, nil
+}
+
+// cannedAnswerCache keeps the answer list in memory so matching does not
+// query the store on every message.
+type cannedAnswerCache struct {
+	mu       sync.Mutex
+	answers  []CannedAnswer
+	loadedAt time.Time
+}
+
+func (c *cannedAnswerCache) get(store CannedAnswerStore) ([]CannedAnswer, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if !c.loadedAt.IsZero() && time.Since(c.loadedAt) < cannedAnswerCacheTTL {
+		return c.answers, nil
+	}
+	answers, err := store.ListCannedAnswers()
+	if err != nil {
+		return nil, err
+	}
+	c.answers, c.loadedAt = answers, time.Now()
+	return answers, nil
+}
+
+func (c *cannedAnswerCache) invalidate() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.loadedAt = time.Time{}
+}
+
+// normalizeCannedText lowercases text and reduces it to space-separated
+// words, padded so phrase matching can require whole words.
+func normalizeCannedText(text string) string {
+	fields := strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+	if len(fields) == 0 {
+		return ""
+	}
+	return " " + strings.Join(fields, " ") + " "
+}
+
+// matchCannedAnswer returns the answer whose phrase appears in text. When
+// several match, the longest phrase wins so specific entries beat general
+// ones.
+func matchCannedAnswer(answers []CannedAnswer, text string) (CannedAnswer, bool) {
+	normalized := normalizeCannedText(text)
+	if normalized == "" {
+		return CannedAnswer{}, false
+	}
+	var best CannedAnswer
+	bestLen := 0
+	for _, a := range answers {
+		if len(a.Answers) == 0 {
+			continue
+		}
+		for _, phrase := range a.Phrases {
+			p := normalizeCannedText(phrase)
+			if len(p) > bestLen && strings.Contains(normalized, p) {
+				best, bestLen = a, len(p)
+			}
+		}
+	}
+	return best, bestLen > 0
+}
+
+// maybeHandleCannedAnswer serves an operator-written reply without calling
+// the model.
+func (e *Engine) maybeHandleCannedAnswer(msg chat.InboundMessage, conv *Conversation) (string, bool) {
+	if e.cannedAnswers == nil || strings.TrimSpace(msg.Text) == "" {
+		return "", false
+	}
+	answers, err := e.cannedAnswerCache.get(e.cannedAnswers)
+	if err != nil {
+		slog.Error("failed to load canned answers", "error", err)
+		return "", false
+	}
+	match, ok := matchCannedAnswer(answers, msg.Text)
+	if !ok {
+		return "", false
+	}
+	locale := e.messageLocale(msg, conv)
+	response := e.recordDeterministicTutorReply(msg, conv, match.answerFor(locale), "canned_answer_served", map[string]any{
+		"channel": msg.Channel,
+		"key":     match.Key,
+		"locale":  locale,
+	})
+	return response, true
+}
+
+// handleCannedAnswerCommand lets operators manage canned answers:
+//
+//	/faq                              list answers
+//	/faq add <key> <phrase>           add a trigger phrase
+//	/faq answer <key> <ms|en|zh> <text>
+//	/faq remove <key>
+func (e *Engine) handleCannedAnswerCommand(msg chat.InboundMessage, args []string) (string, error) {
+	if e.cannedAnswers == nil || !e.isAccessOperator(msg) {
+		return i18n.S(e.messageLocale(msg, nil), i18n.MsgUnknownCommand, "/faq"), nil
+	}
+	const usage = "Usage:\n/faq\n/faq add <key> <phrase>\n/faq answer <key> <ms|en|zh> <text>\n/faq remove <key>"
+	if len(args) == 0 {
+		return e.listCannedAnswers(), nil
+	}
+	if len(args) < 2 {
+		return usage, nil
+	}
+	sub, key := args[0], strings.ToLower(args[1])
+	if !cannedAnswerKeyPattern.MatchString(key) {
+		return "Keys are lowercase letters, digits, - or _, up to 40 characters.", nil
+	}
+
+	if sub == "remove" {
+		removed, err := e.cannedAnswers.DeleteCannedAnswer(key)
+		if err != nil {
+			slog.Error("failed to delete canned answer", "key", key, "error", err)
+			return "Could not remove the canned answer.", nil
+		}
+		e.cannedAnswerCache.invalidate()
+		if !removed {
+			return fmt.Sprintf("No canned answer named %s.", key), nil
+		}
+		return fmt.Sprintf("Removed canned answer %s.", key), nil
+	}
+
+	current, err := e.findCannedAnswer(key)
+	if err != nil {
+		slog.Error("failed to load canned answer", "key", key, "error", err)
+		return "Could not load canned answers.", nil
+	}
+	var reply string
+	switch sub {
+	case "add":
+		phrase := strings.Join(args[2:], " ")
+		if len(strings.TrimSpace(normalizeCannedText(phrase))) < cannedAnswerMinPhraseLen {
+			return fmt.Sprintf("Phrases need at least %d letters.", cannedAnswerMinPhraseLen), nil
+		}
+		if !slices.Contains(current.Phrases, phrase) {
+			current.Phrases = append(current.Phrases, phrase)
+		}
+		reply = fmt.Sprintf("%s now matches %q.", key, phrase)
+	case "answer":
+		if len(args) < 4 {
+			return usage, nil
+		}
+		locale := i18n.NormalizeLocale(args[2])
+		if locale == "" {
+			return "Language must be ms, en or zh.", nil
+		}
+		// Keep the operator's line breaks: take the text after the first
+		// four words of the raw message rather than rejoining fields.
+		text := commandRemainder(msg.Text, 4)
+		if current.Answers == nil {
+			current.Answers = make(map[string]string)
+		}
+		current.Answers[locale] = text
+		reply = fmt.Sprintf("Saved the %s answer for %s.", i18n.LocaleDisplayName(locale), key)
+	default:
+		return usage, nil
+	}
+
+	current.UpdatedBy = msg.UserID
+	if err := e.cannedAnswers.PutCannedAnswer(current); err != nil {
+		slog.Error("failed to save canned answer", "key", key, "error", err)
+		return "Could not save the canned answer.", nil
+	}
+	e.cannedAnswerCache.invalidate()
+	slog.Info("canned answer updated", "key", key, "operator", msg.UserID)
+	if len(current.Answers) == 0 {
+		reply += fmt.Sprintf("\nIt is not served until it has an answer: /faq answer %s <ms|en|zh> <text>", key)
+	}
+	return reply, nil
+}
+
+func (e *Engine) findCannedAnswer(key string) (CannedAnswer, error) {
+	answers, err := e.cannedAnswers.ListCannedAnswers()
+	if err != nil {
+		return CannedAnswer{}, err
+	}
+	for _, a := range answers {
+		if a.Key == key {
+			return cloneCannedAnswer(a), nil
+		}
+	}
+	return CannedAnswer{Key: key}, nil
+}
+
+func (e *Engine) listCannedAnswers() string {
+	answers, err := e.cannedAnswers.ListCannedAnswers()
+	if err != nil {
+		slog.Error("failed to list canned answers", "error", err)
+		return "Could not load canned answers."
+	}
+	if len(answers) == 0 {
+		return "No canned answers yet. Start with /faq add <key> <phrase>."
+	}
+	var b strings.Builder
+	b.WriteString("Canned answers:")
+	for _, a := range answers {
+		langs := strings.Join(slices.Sorted(maps.Keys(a.Answers)), ", ")
+		if langs == "" {
+			langs = "no answer yet"
+		}
+		fmt.Fprintf(&b, "\n%s · %s · %s", a.Key, strings.Join(a.Phrases, " | "), langs)
+	}
+	return b.String()
+}
+
+// commandRemainder returns text after its first n whitespace-separated
+// words, with the rest of the formatting intact.
+func commandRemainder(text string, n int) string {
+	rest := strings.TrimSpace(text)
+	for range n {
+		i := strings.IndexFunc(rest, unicode.IsSpace)
+		if i < 0 {
+			return ""
+		}
+		rest = strings.TrimLeftFunc(rest[i:], unicode.IsSpace)
+	}
+	return rest
+}
@@ -0,0 +1,101 @@
+// Copyright 2026 the P&AI authors. All rights reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package agent_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/p-n-ai/pai-bot/internal/agent"
+	"github.com/p-n-ai/pai-bot/internal/ai"
+)
+
+func newCannedAnswerEngine(t *testing.T) (*agent.Engine, *agent.MemoryCannedAnswerStore, *ai.MockProvider) {
+	t.Helper()
+	mockAI := ai.NewMockProvider("Jawapan tutor.")
+	canned := agent.NewMemoryCannedAnswerStore()
+	engine := agent.NewEngine(agent.EngineConfig{
+		AIRouter:      mockRouter(mockAI),
+		Notifier:      &capturingNotifier{},
+		Access:        agent.NewMemoryAccessStore(),
+		AccessGate:    agent.AccessGateConfig{Operators: []string{accessOperatorID}},
+		CannedAnswers: canned,
+	})
+	return engine, canned, mockAI
+}
+
+func TestCannedAnswerServedWithoutModel(t *testing.T) {
+	engine, canned, mockAI := newCannedAnswerEngine(t)
+	if err := canned.PutCannedAnswer(agent.CannedAnswer{
+		Key:     "pricing",
+		Phrases: []string{"is this free", "berapa harga"},
+		Answers: map[string]string{
+			"en": "Yes, P&AI is free for pilot schools.",
+			"ms": "Ya, P&AI percuma untuk sekolah perintis.",
+		},
+	}); err != nil {
+		t.Fatalf("PutCannedAnswer() error = %v", err)
+	}
+
+	if got := sendAs(t, engine, "websocket", "42", "Hi, is this FREE?"); got != "Yes, P&AI is free for pilot schools." {
+		t.Fatalf("reply = %q, want the English canned answer", got)
+	}
+	if mockAI.LastRequest != nil {
+		t.Error("canned answer still called the model")
+	}
+	// Phrases match whole words only.
+	if got := sendAs(t, engine, "websocket", "42", "this freeform question about fractions"); got != "Jawapan tutor." {
+		t.Fatalf("partial-word reply = %q, want the tutor answer", got)
+	}
+}
+
+func TestCannedAnswerLongestPhraseWins(t *testing.T) {
+	engine, canned, _ := newCannedAnswerEngine(t)
+	_ = canned.PutCannedAnswer(agent.CannedAnswer{
+		Key: "reset", Phrases: []string{"reset"}, Answers: map[string]string{"en": "general reset"},
+	})
+	_ = canned.PutCannedAnswer(agent.CannedAnswer{
+		Key: "reset-password", Phrases: []string{"reset my password"}, Answers: map[string]string{"en": "password reset"},
+	})
+
+	if got := sendAs(t, engine, "websocket", "42", "how do I reset my password"); got != "password reset" {
+		t.Fatalf("reply = %q, want the more specific answer", got)
+	}
+}
+
+func TestFAQCommandManagesAnswers(t *testing.T) {
+	engine, canned, _ := newCannedAnswerEngine(t)
+
+	if got := sendAs(t, engine, "telegram", "77", "/faq"); !strings.Contains(got, "Unknown command") {
+		t.Fatalf("non-operator /faq = %q, want unknown command", got)
+	}
+	if got := sendAs(t, engine, "telegram", accessOperatorID, "/faq add pricing is this free"); !strings.Contains(got, "not served until it has an answer") {
+		t.Fatalf("/faq add = %q, want a reminder to add an answer", got)
+	}
+	if got := sendAs(t, engine, "telegram", accessOperatorID, "/faq add pricing hi"); !strings.Contains(got, "at least") {
+		t.Fatalf("/faq add short phrase = %q, want it rejected", got)
+	}
+	sendAs(t, engine, "telegram", accessOperatorID, "/faq answer pricing ms Ya, percuma.\nTiada bayaran tersembunyi.")
+
+	answers, _ := canned.ListCannedAnswers()
+	if len(answers) != 1 {
+		t.Fatalf("answers = %+v, want one entry", answers)
+	}
+	got := answers[0]
+	if got.Key != "pricing" || len(got.Phrases) != 1 || got.Answers["ms"] != "Ya, percuma.\nTiada bayaran tersembunyi." || got.UpdatedBy != accessOperatorID {
+		t.Fatalf("stored answer = %+v", got)
+	}
+	// English learners fall back to the Malay answer when no English one exists.
+	if reply := sendAs(t, engine, "websocket", "42", "is this free?"); reply != got.Answers["ms"] {
+		t.Fatalf("learner reply = %q, want the new canned answer", reply)
+	}
+	if list := sendAs(t, engine, "telegram", accessOperatorID, "/faq"); !strings.Contains(list, "pricing · is this free · ms") {
+		t.Fatalf("/faq list = %q", list)
+	}
+
+	sendAs(t, engine, "telegram", accessOperatorID, "/faq remove pricing")
+	if reply := sendAs(t, engine, "websocket", "42", "is this free?"); reply != "Jawapan tutor." {
+		t.Fatalf("reply after remove = %q, want the tutor answer", reply)
+	}
+}
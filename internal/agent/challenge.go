@@ -16,6 +16,8 @@ import (
 	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgconn"
 	"github.com/jackc/pgx/v5/pgxpool"
+
+	"github.com/p-n-ai/pai-bot/internal/apperr"
 )
 
 const (
@@ -42,12 +44,12 @@ const (
 )
 
 var (
-	ErrChallengeNotFound            = errors.New("challenge not found")
-	ErrChallengeNotJoinable         = errors.New("challenge not joinable")
-	ErrChallengeSelfJoin            = errors.New("challenge self join")
-	ErrChallengeSearchAlreadyActive = errors.New("challenge search already active")
-	ErrChallengeAlreadyActive       = errors.New("challenge already active")
-	ErrChallengeAcceptNotAvailable  = errors.New("challenge accept not available")
+	ErrChallengeNotFound            = apperr.UserInput(errors.New("challenge not found"))
+	ErrChallengeNotJoinable         = apperr.UserInput(errors.New("challenge not joinable"))
+	ErrChallengeSelfJoin            = apperr.UserInput(errors.New("challenge self join"))
+	ErrChallengeSearchAlreadyActive = apperr.UserInput(errors.New("challenge search already active"))
+	ErrChallengeAlreadyActive       = apperr.UserInput(errors.New("challenge already active"))
+	ErrChallengeAcceptNotAvailable  = apperr.UserInput(errors.New("challenge accept not available"))
 )
 
 // Challenge represents a peer challenge.
@@ -0,0 +1,246 @@
+// Copyright 2026 the P&AI authors. All rights reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package agent
+
+import (
+	"context"
+	"crypto/rand"
+	"errors"
+	"fmt"
+	"log/slog"
+	"math/big"
+	"strings"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+
+	"github.com/p-n-ai/pai-bot/internal/apperr"
+	"github.com/p-n-ai/pai-bot/internal/chat"
+	"github.com/p-n-ai/pai-bot/internal/i18n"
+)
+
+// linkCodeTTL is how long a /link code stays redeemable. It only has to
+// survive the learner switching apps.
+const linkCodeTTL = 10 * time.Minute
+
+var (
+	// ErrLinkCodeInvalid means the code does not exist or has expired. Like
+	// ErrLinkOwnCode, it carries the reply the learner sees.
+	ErrLinkCodeInvalid = apperr.WithMessage(apperr.UserInput(errors.New("link code invalid or expired")), i18n.MsgLinkInvalid)
+	// ErrLinkOwnCode means the learner redeemed a code from the same chat.
+	ErrLinkOwnCode = apperr.WithMessage(apperr.UserInput(errors.New("link code belongs to the same chat")), i18n.MsgLinkSameChat)
+)
+
+// ChannelLinker is implemented by conversation stores that can link one
+// learner's chat identities, so GetActiveConversation resolves the same
+// canonical conversation from any linked channel.
+type ChannelLinker interface {
+	CreateLinkCode(userID string, expiresAt time.Time) (string, error)
+	// RedeemLinkCode links userID with the owner of code. Codes are single
+	// use.
+	RedeemLinkCode(code, userID string, now time.Time) error
+}
+
+// handleLinkCommand answers /link with a fresh code and /link CODE by
+// linking this chat to the one that issued the code.
+func (e *Engine) handleLinkCommand(msg chat.InboundMessage, args []string) (string, error) {
+	locale := e.messageLocale(msg, nil)
+	linker, ok := e.store.(ChannelLinker)
+	if !ok {
+		return i18n.S(locale, i18n.MsgLinkUnavailable), nil
+	}
+
+	if len(args) == 0 {
+		code, err := linker.CreateLinkCode(msg.UserID, time.Now().Add(linkCodeTTL))
+		if err != nil {
+			slog.Error("failed to create link code", "user_id", msg.UserID, "error", err)
+			return i18n.S(locale, i18n.MsgTechnicalIssue), nil
+		}
+		return i18n.S(locale, i18n.MsgLinkCode, code, code, int(linkCodeTTL/time.Minute)), nil
+	}
+
+	if err := linker.RedeemLinkCode(strings.TrimSpace(args[0]), msg.UserID, time.Now()); err != nil {
+		return e.errorReply(locale, msg.UserID, "", "redeem_link_code", err), nil
+	}
+
+	conv, hasConv := e.store.GetActiveConversation(msg.UserID)
+	e.logEventAsync(Event{
+		UserID:    msg.UserID,
+		EventType: "channels_linked",
+		Data: map[string]any{
+			"channel":          msg.Channel,
+			"has_conversation": hasConv,
+		},
+	})
+	if hasConv && conv.TopicID != "" {
+		return i18n.S(locale, i18n.MsgLinkDoneTopic, e.lookupTopicName(conv.TopicID)), nil
+	}
+	return i18n.S(locale, i18n.MsgLinkDone), nil
+}
+
+// newLinkCode returns a six-digit code, short enough to retype on a phone.
+func newLinkCode() (string, error) {
+	n, err := rand.Int(rand.Reader, big.NewInt(1_000_000))
+	if err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("%06d", n.Int64()), nil
+}
+
+type memoryLinkCode struct {
+	userID    string
+	expiresAt time.Time
+}
+
+func (s *MemoryStore) CreateLinkCode(userID string, expiresAt time.Time) (string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for {
+		code, err := newLinkCode()
+		if err != nil {
+			return "", fmt.Errorf("generate link code: %w", err)
+		}
+		if _, taken := s.linkCodes[code]; !taken {
+			s.linkCodes[code] = memoryLinkCode{userID: userID, expiresAt: expiresAt}
+			return code, nil
+		}
+	}
+}
+
+func (s *MemoryStore) RedeemLinkCode(code, userID string, now time.Time) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	link, ok := s.linkCodes[code]
+	if !ok || !now.Before(link.expiresAt) {
+		return ErrLinkCodeInvalid
+	}
+	if link.userID == userID {
+		return ErrLinkOwnCode
+	}
+	delete(s.linkCodes, code)
+
+	// The code owner's group absorbs the redeemer's.
+	canonical := s.canonicalUserLocked(link.userID)
+	merged := s.canonicalUserLocked(userID)
+	for member, c := range s.userLinks {
+		if c == merged {
+			s.userLinks[member] = canonical
+		}
+	}
+	s.userLinks[link.userID] = canonical
+	s.userLinks[merged] = canonical
+	s.userLinks[userID] = canonical
+	return nil
+}
+
+func (s *MemoryStore) canonicalUserLocked(userID string) string {
+	if c, ok := s.userLinks[userID]; ok {
+		return c
+	}
+	return userID
+}
+
+// sameLinkGroupLocked reports whether other is userID or linked to it.
+func (s *MemoryStore) sameLinkGroupLocked(userID, other string) bool {
+	return userID == other || s.canonicalUserLocked(userID) == s.canonicalUserLocked(other)
+}
+
+func (s *PostgresStore) CreateLinkCode(userID string, expiresAt time.Time) (string, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), dbTimeout)
+	defer cancel()
+
+	userUUID, err := s.resolveOrCreateUser(ctx, userID)
+	if err != nil {
+		return "", err
+	}
+	for attempt := 0; attempt < 5; attempt++ {
+		code, err := newLinkCode()
+		if err != nil {
+			return "", fmt.Errorf("generate link code: %w", err)
+		}
+		cmd, err := s.pool.Exec(ctx,
+			`INSERT INTO user_link_codes (code, tenant_id, user_id, expires_at)
+			 VALUES ($1, $2::uuid, $3::uuid, $4)
+			 ON CONFLICT (code) DO UPDATE
+			 SET tenant_id = EXCLUDED.tenant_id, user_id = EXCLUDED.user_id,
+			     expires_at = EXCLUDED.expires_at, created_at = NOW()
+			 WHERE user_link_codes.expires_at <= NOW()`,
+			code, s.tenantID, userUUID, expiresAt,
+		)
+		if err != nil {
+			return "", fmt.Errorf("insert link code: %w", err)
+		}
+		if cmd.RowsAffected() == 1 {
+			return code, nil
+		}
+	}
+	return "", errors.New("insert link code: no free code")
+}
+
+func (s *PostgresStore) RedeemLinkCode(code, userID string, now time.Time) error {
+	ctx, cancel := context.WithTimeout(context.Background(), dbTimeout)
+	defer cancel()
+
+	userUUID, err := s.resolveOrCreateUser(ctx, userID)
+	if err != nil {
+		return err
+	}
+
+	tx, err := s.pool.BeginTx(ctx, pgx.TxOptions{})
+	if err != nil {
+		return fmt.Errorf("begin link tx: %w", err)
+	}
+	defer func() { _ = tx.Rollback(ctx) }()
+
+	var ownerUUID string
+	err = tx.QueryRow(ctx,
+		`SELECT user_id::text
+		 FROM user_link_codes
+		 WHERE code = $1 AND tenant_id = $2::uuid AND expires_at > $3
+		 FOR UPDATE`,
+		code, s.tenantID, now,
+	).Scan(&ownerUUID)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return ErrLinkCodeInvalid
+	}
+	if err != nil {
+		return fmt.Errorf("lookup link code: %w", err)
+	}
+	if ownerUUID == userUUID {
+		return ErrLinkOwnCode
+	}
+	if _, err := tx.Exec(ctx, `DELETE FROM user_link_codes WHERE code = $1`, code); err != nil {
+		return fmt.Errorf("consume link code: %w", err)
+	}
+
+	// The code owner's group absorbs the redeemer's. Every member, the
+	// canonical user included, gets a row so a group is one lookup.
+	var canonical, merged string
+	err = tx.QueryRow(ctx,
+		`SELECT COALESCE((SELECT canonical_user_id FROM user_links WHERE user_id = $1::uuid), $1::uuid)::text,
+		        COALESCE((SELECT canonical_user_id FROM user_links WHERE user_id = $2::uuid), $2::uuid)::text`,
+		ownerUUID, userUUID,
+	).Scan(&canonical, &merged)
+	if err != nil {
+		return fmt.Errorf("lookup link groups: %w", err)
+	}
+	if _, err := tx.Exec(ctx,
+		`UPDATE user_links SET canonical_user_id = $1::uuid, linked_at = NOW()
+		 WHERE canonical_user_id = $2::uuid`,
+		canonical, merged,
+	); err != nil {
+		return fmt.Errorf("merge link groups: %w", err)
+	}
+	if _, err := tx.Exec(ctx,
+		`INSERT INTO user_links (user_id, canonical_user_id, tenant_id)
+		 SELECT DISTINCT u, $1::uuid, $4::uuid FROM unnest(ARRAY[$1::uuid, $2::uuid, $3::uuid]) AS u
+		 ON CONFLICT (user_id) DO UPDATE SET canonical_user_id = EXCLUDED.canonical_user_id, linked_at = NOW()`,
+		canonical, ownerUUID, userUUID, s.tenantID,
+	); err != nil {
+		return fmt.Errorf("insert user links: %w", err)
+	}
+	return tx.Commit(ctx)
+}
@@ -0,0 +1,77 @@
+// Copyright 2026 the P&AI authors. All rights reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package agent_test
+
+import (
+	"errors"
+	"regexp"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/p-n-ai/pai-bot/internal/agent"
+)
+
+func TestEngine_LinkCarriesConversationAcrossChannels(t *testing.T) {
+	store := agent.NewMemoryStore()
+	_ = store.SetUserForm("web-guest", "1")
+	_ = store.SetUserForm("tg-42", "1")
+	webConv, _ := store.CreateConversation(agent.Conversation{UserID: "web-guest", State: "teaching", TopicID: "F1-02"})
+	_, _ = store.AddMessage(webConv, agent.StoredMessage{Role: "user", Content: "Macam mana nak selesaikan 2x + 3 = 7?"})
+
+	engine := agent.NewEngine(agent.EngineConfig{
+		Store:            store,
+		CurriculumLoader: createTestCurriculumLoader(t),
+	})
+
+	resp := sendAs(t, engine, "websocket", "web-guest", "/link")
+	code := regexp.MustCompile(`\b\d{6}\b`).FindString(resp)
+	if code == "" {
+		t.Fatalf("/link = %q, want a six-digit code", resp)
+	}
+
+	if resp := sendAs(t, engine, "websocket", "web-guest", "/link "+code); !strings.Contains(resp, "other chat app") {
+		t.Fatalf("own code = %q, want same-chat hint", resp)
+	}
+	resp = sendAs(t, engine, "telegram", "tg-42", "/link "+code)
+	if !strings.Contains(resp, "Linear Equations") {
+		t.Fatalf("redeem = %q, want the carried-over topic", resp)
+	}
+
+	conv, ok := store.GetActiveConversation("tg-42")
+	if !ok || conv.ID != webConv || conv.TopicID != "F1-02" {
+		t.Fatalf("GetActiveConversation(tg-42) = %+v, %v; want web conversation %s", conv, ok, webConv)
+	}
+
+	if resp := sendAs(t, engine, "telegram", "tg-42", "/link "+code); !strings.Contains(resp, "not valid") {
+		t.Fatalf("reused code = %q, want invalid-code reply", resp)
+	}
+}
+
+func TestMemoryStore_LinkPicksMostRecentConversation(t *testing.T) {
+	store := agent.NewMemoryStore()
+	now := time.Now()
+	older, _ := store.CreateConversation(agent.Conversation{UserID: "tg-1", State: "teaching"})
+	_, _ = store.AddMessage(older, agent.StoredMessage{Role: "user", Content: "hi", CreatedAt: now.Add(-time.Hour)})
+	newer, _ := store.CreateConversation(agent.Conversation{UserID: "web-1", State: "teaching"})
+	_, _ = store.AddMessage(newer, agent.StoredMessage{Role: "user", Content: "hello", CreatedAt: now})
+
+	code, err := store.CreateLinkCode("tg-1", now.Add(time.Minute))
+	if err != nil {
+		t.Fatalf("CreateLinkCode() error = %v", err)
+	}
+	if err := store.RedeemLinkCode(code, "web-1", now.Add(2*time.Minute)); !errors.Is(err, agent.ErrLinkCodeInvalid) {
+		t.Fatalf("RedeemLinkCode(expired) error = %v, want ErrLinkCodeInvalid", err)
+	}
+	code, _ = store.CreateLinkCode("tg-1", now.Add(time.Minute))
+	if err := store.RedeemLinkCode(code, "web-1", now); err != nil {
+		t.Fatalf("RedeemLinkCode() error = %v", err)
+	}
+
+	for _, user := range []string{"tg-1", "web-1"} {
+		if conv, ok := store.GetActiveConversation(user); !ok || conv.ID != newer {
+			t.Fatalf("GetActiveConversation(%s) = %+v, want most recent conversation %s", user, conv, newer)
+		}
+	}
+}
@@ -0,0 +1,83 @@
+// Copyright 2026 the P&AI authors. All rights reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package agent
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"github.com/p-n-ai/pai-bot/internal/chat"
+	"github.com/p-n-ai/pai-bot/internal/curriculum"
+	"github.com/p-n-ai/pai-bot/internal/i18n"
+	"github.com/p-n-ai/pai-bot/internal/platform/featureflags"
+)
+
+// checkInDelay is how long after a teaching turn the check-in goes out.
+const checkInDelay = 24 * time.Hour
+
+// ScheduledSender queues messages for later delivery. *chat.Gateway
+// implements it on top of its delay queue.
+type ScheduledSender interface {
+	SendAt(ctx context.Context, msg chat.OutboundMessage, at time.Time) (string, error)
+	CancelScheduled(ctx context.Context, channel, userID string) (int, error)
+}
+
+// SetScheduledSender installs the delay queue once the chat gateway exists.
+func (e *Engine) SetScheduledSender(s ScheduledSender) {
+	e.scheduledSends = s
+}
+
+// cancelScheduledSends drops the learner's queued check-ins and deferred
+// nudges: they came back on their own, so the reminders are moot.
+func (e *Engine) cancelScheduledSends(ctx context.Context, msg chat.InboundMessage) {
+	if e.scheduledSends == nil {
+		return
+	}
+	dropped, err := e.scheduledSends.CancelScheduled(ctx, msg.Channel, msg.UserID)
+	if err != nil {
+		slog.Warn("failed to cancel scheduled sends", "channel", msg.Channel, "user_id", msg.UserID, "error", err)
+		return
+	}
+	if dropped > 0 {
+		slog.Info("scheduled sends cancelled by learner activity", "channel", msg.Channel, "user_id", msg.UserID, "dropped", dropped)
+	}
+}
+
+// scheduleCheckIn queues a next-day "shall we continue?" message after a
+// teaching turn. The learner's next message cancels it, so only a learner
+// who goes quiet for a day receives it.
+func (e *Engine) scheduleCheckIn(ctx context.Context, msg chat.InboundMessage, conv *Conversation, topic *curriculum.Topic) {
+	if e.scheduledSends == nil || !e.featureFlags().Enabled(featureflags.CheckInFollowUps) {
+		return
+	}
+	// Websocket sessions are gone by tomorrow; only push channels can
+	// deliver a check-in.
+	if (msg.Channel != "telegram" && msg.Channel != "whatsapp") || IsSandboxUser(msg.UserID) {
+		return
+	}
+	locale := e.messageLocale(msg, conv)
+	text := i18n.S(locale, i18n.MsgCheckIn)
+	if topic != nil && topic.Name != "" {
+		text = i18n.S(locale, i18n.MsgCheckInTopic, topic.Name, topic.Name)
+	}
+	at := checkInTime(time.Now())
+	if _, err := e.scheduledSends.SendAt(ctx, chat.OutboundMessage{
+		Channel: msg.Channel,
+		UserID:  msg.UserID,
+		Text:    text,
+	}, at); err != nil {
+		slog.Warn("failed to schedule check-in", "channel", msg.Channel, "user_id", msg.UserID, "error", err)
+	}
+}
+
+// checkInTime is a day after now, pushed to the end of quiet hours when it
+// would land inside them.
+func checkInTime(now time.Time) time.Time {
+	at := now.Add(checkInDelay)
+	if IsQuietHours(at) {
+		return quietHoursEndAfter(at)
+	}
+	return at
+}
@@ -0,0 +1,92 @@
+// Copyright 2026 the P&AI authors. All rights reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package agent_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/p-n-ai/pai-bot/internal/agent"
+	"github.com/p-n-ai/pai-bot/internal/ai"
+	"github.com/p-n-ai/pai-bot/internal/chat"
+	"github.com/p-n-ai/pai-bot/internal/platform/featureflags"
+)
+
+func newCheckInEngine(t *testing.T, flags string) (*agent.Engine, *chat.Gateway) {
+	t.Helper()
+	features, err := featureflags.Parse(flags)
+	if err != nil {
+		t.Fatal(err)
+	}
+	gw := chat.NewGateway()
+	gw.Register("telegram", &chat.MockChannel{})
+	gw.SetDelayQueue(chat.NewMemoryDelayQueue())
+	engine := agent.NewEngine(agent.EngineConfig{
+		AIRouter:       mockRouter(ai.NewMockProvider("Add 3 to both sides.")),
+		Store:          agent.NewMemoryStore(),
+		FeatureFlags:   func() featureflags.Features { return features },
+		ScheduledSends: gw,
+	})
+	return engine, gw
+}
+
+func TestCheckInQueuedForTomorrowAndReplacedOnReturn(t *testing.T) {
+	engine, gw := newCheckInEngine(t, "check_in_follow_ups")
+	ctx := context.Background()
+
+	before := time.Now()
+	sendAs(t, engine, "telegram", "42", "How do I solve x - 3 = 5?")
+	pending, err := gw.PendingSends(ctx, "telegram", "42")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(pending) != 1 {
+		t.Fatalf("pending = %+v, want one check-in", pending)
+	}
+	first := pending[0]
+	if first.SendAt.Before(before.Add(24*time.Hour)) || agent.IsQuietHours(first.SendAt) {
+		t.Fatalf("check-in at %v, want a day later outside quiet hours", first.SendAt)
+	}
+	if first.Message.Text == "" {
+		t.Fatal("check-in has no text")
+	}
+
+	// Coming back drops the queued check-in; the new turn queues a fresh one.
+	sendAs(t, engine, "telegram", "42", "And 2x = 10?")
+	pending, _ = gw.PendingSends(ctx, "telegram", "42")
+	if len(pending) != 1 || pending[0].ID == first.ID {
+		t.Fatalf("pending = %+v, want one check-in replacing %s", pending, first.ID)
+	}
+}
+
+func TestCheckInSkipsWebsocketAndDisabledFlag(t *testing.T) {
+	ctx := context.Background()
+
+	engine, gw := newCheckInEngine(t, "check_in_follow_ups")
+	sendAs(t, engine, "websocket", "42", "How do I solve x - 3 = 5?")
+	if pending, _ := gw.PendingSends(ctx, "websocket", "42"); len(pending) != 0 {
+		t.Fatalf("websocket pending = %+v, want none", pending)
+	}
+
+	engine, gw = newCheckInEngine(t, "")
+	sendAs(t, engine, "telegram", "42", "How do I solve x - 3 = 5?")
+	if pending, _ := gw.PendingSends(ctx, "telegram", "42"); len(pending) != 0 {
+		t.Fatalf("pending with flag off = %+v, want none", pending)
+	}
+}
+
+func TestLearnerActivityCancelsQueuedNudge(t *testing.T) {
+	engine, gw := newCheckInEngine(t, "")
+	ctx := context.Background()
+	if _, err := gw.SendAt(ctx, chat.OutboundMessage{Channel: "telegram", UserID: "42", Text: "Time to review!"}, time.Now().Add(time.Hour)); err != nil {
+		t.Fatal(err)
+	}
+
+	sendAs(t, engine, "telegram", "42", "/help")
+
+	if pending, _ := gw.PendingSends(ctx, "telegram", "42"); len(pending) != 0 {
+		t.Fatalf("pending = %+v, want the nudge cancelled", pending)
+	}
+}
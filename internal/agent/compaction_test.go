@@ -0,0 +1,126 @@
+// Copyright 2026 the P&AI authors. All rights reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package agent
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/p-n-ai/pai-bot/internal/ai"
+)
+
+type summarySequenceProvider struct {
+	mu        sync.Mutex
+	responses []string
+	requests  []ai.CompletionRequest
+}
+
+func (p *summarySequenceProvider) Complete(_ context.Context, req ai.CompletionRequest) (ai.CompletionResponse, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.requests = append(p.requests, req)
+	if len(p.responses) == 0 {
+		return ai.CompletionResponse{}, errors.New("no scripted response")
+	}
+	content := p.responses[0]
+	p.responses = p.responses[1:]
+	return ai.CompletionResponse{Content: content, Model: "mock"}, nil
+}
+
+func (p *summarySequenceProvider) StreamComplete(context.Context, ai.CompletionRequest) (<-chan ai.StreamChunk, error) {
+	return nil, errors.New("not implemented")
+}
+
+func (p *summarySequenceProvider) Models() []ai.ModelInfo { return nil }
+
+func (p *summarySequenceProvider) HealthCheck(context.Context) error { return nil }
+
+func TestValidateSummary(t *testing.T) {
+	tests := []struct {
+		name     string
+		summary  string
+		wantLang string
+		keywords []string
+		wantErr  string
+	}{
+		{name: "valid", summary: "The student discussed linear equations and understood balancing.", wantLang: "en", keywords: []string{"linear"}},
+		{name: "too short", summary: "ok", wantErr: "too short"},
+		{name: "too long", summary: strings.Repeat("word ", maxSummaryWords+1), wantErr: "words"},
+		{name: "language drift", summary: "Pelajar faham persamaan linear dan keliru tentang tanda.", wantLang: "en", wantErr: "language"},
+		{name: "topic dropped", summary: "The student asked several questions and understood most of them.", keywords: []string{"linear", "equations"}, wantErr: "topic"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := validateSummary(tt.summary, tt.wantLang, tt.keywords)
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Fatalf("validateSummary() error = %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Fatalf("validateSummary() error = %v, want %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestResummarizeRetriesOnceAfterRejectedSummary(t *testing.T) {
+	provider := &summarySequenceProvider{responses: []string{
+		"Pelajar faham dan keliru tentang soalan.",
+		"The student practised solving problems and understood the steps.",
+	}}
+	router := ai.NewRouterWithConfig(ai.RouterConfig{RetryBackoff: []time.Duration{time.Millisecond}})
+	router.Register("mock", provider)
+	store := NewMemoryStore()
+	engine := NewEngine(EngineConfig{AIRouter: router, Store: store, KeepRecent: 2})
+
+	convID, err := store.CreateConversation(Conversation{UserID: "u1", State: "teaching"})
+	if err != nil {
+		t.Fatalf("CreateConversation() error = %v", err)
+	}
+	for _, msg := range []StoredMessage{
+		{Role: "user", Content: "how do i solve this, please explain"},
+		{Role: "assistant", Content: "Start by isolating x."},
+		{Role: "user", Content: "what is the next step?"},
+		{Role: "assistant", Content: "Divide both sides."},
+	} {
+		if _, err := store.AddMessage(convID, msg); err != nil {
+			t.Fatalf("AddMessage() error = %v", err)
+		}
+	}
+
+	summary, err := engine.Resummarize(context.Background(), convID)
+	if err != nil {
+		t.Fatalf("Resummarize() error = %v", err)
+	}
+	if !strings.HasPrefix(summary, "The student practised") {
+		t.Fatalf("summary = %q, want retried English summary", summary)
+	}
+	if len(provider.requests) != 2 {
+		t.Fatalf("summary calls = %d, want 2", len(provider.requests))
+	}
+	if !strings.Contains(provider.requests[1].Messages[0].Content, "previous summary was rejected") {
+		t.Fatalf("retry prompt = %q, want rejection feedback", provider.requests[1].Messages[0].Content)
+	}
+
+	conv, _ := store.GetConversation(convID)
+	if conv.Summary != summary || conv.CompactedAt != 2 {
+		t.Fatalf("stored summary = %q compacted_at = %d, want new summary at 2", conv.Summary, conv.CompactedAt)
+	}
+}
+
+func TestResummarizeNothingToSummarize(t *testing.T) {
+	store := NewMemoryStore()
+	engine := NewEngine(EngineConfig{AIRouter: ai.NewRouter(), Store: store, KeepRecent: 6})
+	convID, _ := store.CreateConversation(Conversation{UserID: "u1", State: "teaching"})
+
+	if _, err := engine.Resummarize(context.Background(), convID); !errors.Is(err, ErrNothingToSummarize) {
+		t.Fatalf("Resummarize() error = %v, want ErrNothingToSummarize", err)
+	}
+}
@@ -0,0 +1,184 @@
+// Copyright 2026 the P&AI authors. All rights reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package agent
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/p-n-ai/pai-bot/internal/chat"
+	"github.com/p-n-ai/pai-bot/internal/curriculum"
+)
+
+// teachingStrategy is the approach the tutor switches to when a learner keeps
+// saying they do not understand.
+type teachingStrategy string
+
+const (
+	strategySimplerNumbers teachingStrategy = "simpler_numbers"
+	strategyVisual         teachingStrategy = "visual"
+	strategyWorkedExample  teachingStrategy = "worked_example"
+)
+
+// confusionStrategies is the order strategies are tried in while the learner
+// stays confused.
+var confusionStrategies = []teachingStrategy{strategySimplerNumbers, strategyVisual, strategyWorkedExample}
+
+const (
+	// confusionSwitchThreshold is how many confusion signals on one topic
+	// trigger the first strategy switch.
+	confusionSwitchThreshold = 2
+	// confusionWindow is how many of the learner's latest messages are
+	// searched for signals.
+	confusionWindow = 6
+)
+
+var (
+	confusionSignalMarkers = []string{
+		"tak faham",
+		"x faham",
+		"tak paham",
+		"tidak faham",
+		"tak fhm",
+		"keliru",
+		"blur",
+		"don't get",
+		"dont get",
+		"don't understand",
+		"dont understand",
+		"do not understand",
+		"confused",
+		"i'm lost",
+		"im lost",
+	}
+	// Chinese has no word boundaries, so these match as plain substrings.
+	confusionSignalSubstrings = []string{"不懂", "不明白", "听不懂", "看不懂"}
+)
+
+func isConfusionSignal(text string) bool {
+	if containsMarker(text, confusionSignalMarkers) {
+		return true
+	}
+	for _, marker := range confusionSignalSubstrings {
+		if strings.Contains(text, marker) {
+			return true
+		}
+	}
+	return false
+}
+
+// confusionSignals counts confusion signals among the learner's latest
+// messages, the current one included. A message that moves the conversation
+// to another topic starts the count again.
+func confusionSignals(conv *Conversation, topicChanged bool) int {
+	if conv == nil || topicChanged {
+		return 0
+	}
+	signals, seen := 0, 0
+	for i := len(conv.Messages) - 1; i >= 0 && seen < confusionWindow; i-- {
+		m := conv.Messages[i]
+		if m.Role != "user" || !m.Visible() {
+			continue
+		}
+		seen++
+		if isConfusionSignal(m.Content) {
+			signals++
+		}
+	}
+	return signals
+}
+
+// confusionStrategy picks the strategy for a turn, or "" while the learner
+// has not signalled confusion often enough. Each further signal moves on to
+// the next strategy.
+func confusionStrategy(conv *Conversation, currentText string, topicChanged bool) (teachingStrategy, int) {
+	if !isConfusionSignal(currentText) {
+		return "", 0
+	}
+	signals := confusionSignals(conv, topicChanged)
+	if signals < confusionSwitchThreshold {
+		return "", signals
+	}
+	return confusionStrategies[(signals-confusionSwitchThreshold)%len(confusionStrategies)], signals
+}
+
+// strategySwitchInstruction tells the tutor how to re-teach with strategy.
+func (e *Engine) strategySwitchInstruction(strategy teachingStrategy, channel, topicID string) string {
+	const prefix = "The student has said more than once that they still do not understand this topic. Stop repeating the previous explanation and switch strategy. "
+	switch strategy {
+	case strategySimplerNumbers:
+		return prefix + "Re-teach the same idea with small, friendly whole numbers first, then map it back to the original problem. Keep it to one step and one check question."
+	case strategyVisual:
+		if chat.SupportsPhotos(channel) {
+			return prefix + "Show it visually: call plot_graph when the idea can be drawn as y = f(x), otherwise draw a small text diagram or number line. Explain the picture in a few lines and end with one check question."
+		}
+		return prefix + "Show it visually with a small text diagram or number line. Explain the picture in a few lines and end with one check question."
+	case strategyWorkedExample:
+		if example, ok := e.strategyWorkedExample(topicID); ok {
+			return prefix + fmt.Sprintf("Walk through this worked example from the curriculum one step at a time, then ask the student to try the first step of their own problem.\nExample: %s\nWorking: %s\nAnswer: %s",
+				example.Text, example.Answer.Working, example.Answer.Value)
+		}
+		return prefix + "Walk through one short worked example from the teaching notes one step at a time, then ask the student to try the first step of their own problem."
+	}
+	return ""
+}
+
+// strategyWorkedExample picks the easiest assessment question on the topic
+// that comes with its working.
+func (e *Engine) strategyWorkedExample(topicID string) (curriculum.AssessmentQuestion, bool) {
+	if e.curriculumLoader == nil || topicID == "" {
+		return curriculum.AssessmentQuestion{}, false
+	}
+	assessment, ok := e.curriculumLoader.GetAssessment(topicID)
+	if !ok {
+		return curriculum.AssessmentQuestion{}, false
+	}
+	var picked curriculum.AssessmentQuestion
+	found := false
+	for _, q := range assessment.Questions {
+		if strings.TrimSpace(q.Answer.Working) == "" {
+			continue
+		}
+		if !found || (q.Difficulty == "easy" && picked.Difficulty != "easy") {
+			picked, found = q, true
+		}
+	}
+	return picked, found
+}
+
+// appendStrategySwitchPacket adds the strategy switch instruction for a
+// confused learner and logs the switch for evaluation.
+func (e *Engine) appendStrategySwitchPacket(packets []contextPacket, turn *agentTurn, topicChanged bool) []contextPacket {
+	strategy, signals := confusionStrategy(turn.Conversation, turn.InputText, topicChanged)
+	if strategy == "" {
+		return packets
+	}
+	// Short replies like "tak faham" match no topic; fall back to the
+	// conversation's.
+	topicID := turn.Conversation.TopicID
+	if turn.Topic != nil {
+		topicID = turn.Topic.ID
+	}
+	instruction := e.strategySwitchInstruction(strategy, turn.Channel, topicID)
+	e.logEventAsync(Event{
+		ConversationID: turn.ConversationID,
+		UserID:         turn.UserID,
+		EventType:      "strategy_switch",
+		Data: map[string]any{
+			"channel":           turn.Channel,
+			"turn_id":           turn.ID,
+			"topic_id":          topicID,
+			"strategy":          string(strategy),
+			"confusion_signals": signals,
+		},
+	})
+	return append(packets, newContextPacket(contextPacket{
+		ID:       "strategy.switch",
+		Kind:     contextKindControlInstruction,
+		Trust:    contextTrustSystemOwned,
+		Source:   "strategy_switch",
+		Data:     instruction,
+		RenderAs: contextRenderSystemInstruction,
+	}))
+}
@@ -0,0 +1,62 @@
+// Copyright 2026 the P&AI authors. All rights reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package agent_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/p-n-ai/pai-bot/internal/agent"
+	"github.com/p-n-ai/pai-bot/internal/ai"
+)
+
+func strategySwitchPrompt(mockAI *ai.MockProvider) string {
+	for _, m := range mockAI.LastRequest.Messages {
+		if m.Role == "system" && strings.Contains(m.Content, "switch strategy") {
+			return m.Content
+		}
+	}
+	return ""
+}
+
+func TestRepeatedConfusionSwitchesTeachingStrategy(t *testing.T) {
+	mockAI := ai.NewMockProvider("AI response")
+	events := agent.NewMemoryEventLogger()
+	engine := agent.NewEngine(agent.EngineConfig{
+		AIRouter:         mockRouter(mockAI),
+		Store:            agent.NewMemoryStore(),
+		EventLogger:      events,
+		CurriculumLoader: createTestCurriculumLoader(t),
+	})
+
+	sendAs(t, engine, "telegram", "aina", "explain linear equations")
+	sendAs(t, engine, "telegram", "aina", "tak faham")
+	if got := strategySwitchPrompt(mockAI); got != "" {
+		t.Fatalf("first confusion switched strategy: %q", got)
+	}
+
+	sendAs(t, engine, "telegram", "aina", "still don't get it")
+	if got := strategySwitchPrompt(mockAI); !strings.Contains(got, "small, friendly whole numbers") {
+		t.Fatalf("second confusion prompt = %q, want the simpler numbers strategy", got)
+	}
+	event := waitForEvent(t, events, "strategy_switch")
+	if event.Data["strategy"] != "simpler_numbers" || event.Data["confusion_signals"] != 2 {
+		t.Fatalf("strategy_switch data = %#v", event.Data)
+	}
+
+	sendAs(t, engine, "telegram", "aina", "I'm confused")
+	if got := strategySwitchPrompt(mockAI); !strings.Contains(got, "plot_graph") {
+		t.Fatalf("third confusion prompt = %q, want the visual strategy", got)
+	}
+
+	sendAs(t, engine, "telegram", "aina", "masih tak faham")
+	if got := strategySwitchPrompt(mockAI); !strings.Contains(got, "x = 7 - 3") {
+		t.Fatalf("fourth confusion prompt = %q, want the curriculum worked example", got)
+	}
+
+	sendAs(t, engine, "telegram", "aina", "ok what about 2x = 10")
+	if got := strategySwitchPrompt(mockAI); got != "" {
+		t.Fatalf("message without confusion switched strategy: %q", got)
+	}
+}
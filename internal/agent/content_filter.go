@@ -0,0 +1,278 @@
+// Copyright 2026 the P&AI authors. All rights reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package agent
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"os"
+	"regexp"
+	"slices"
+	"strings"
+	"unicode"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/p-n-ai/pai-bot/internal/ai"
+	"github.com/p-n-ai/pai-bot/internal/chat"
+)
+
+// ContentFilterPolicy says what happens when a reply contains blocked words.
+type ContentFilterPolicy string
+
+const (
+	// ContentFilterOff sends replies unchanged.
+	ContentFilterOff ContentFilterPolicy = "off"
+	// ContentFilterReplace masks blocked words with contentFilterMask.
+	ContentFilterReplace ContentFilterPolicy = "replace"
+	// ContentFilterRegenerate asks the model once for a clean rewrite of a
+	// teaching answer, then masks anything left.
+	ContentFilterRegenerate ContentFilterPolicy = "regenerate"
+)
+
+const contentFilterMask = "***"
+
+const contentFilterRewritePrompt = "Your previous reply used language that is not allowed with school students. " +
+	"Rewrite the same reply with school-appropriate wording only. Keep the maths and the language unchanged."
+
+// defaultBlockedWords are never sent to students whatever the tenant adds.
+// Words are matched whole and case-insensitively; Chinese entries match
+// anywhere since the script has no word breaks.
+var defaultBlockedWords = map[string][]string{
+	"en": {
+		"fuck", "fucking", "fucked", "motherfucker", "shit", "bullshit", "bitch",
+		"bastard", "asshole", "dick", "cunt", "slut", "whore", "wanker",
+		"retard", "retarded",
+	},
+	"ms": {
+		"sial", "celaka", "keparat", "pukimak", "puki", "pantat", "lancau",
+		"butoh", "haram jadah",
+	},
+	"zh": {
+		"他妈的", "操你", "傻逼", "混蛋", "王八蛋", "狗屎", "婊子",
+	},
+}
+
+// defaultBlockedPatterns catch phrasing that is unacceptable even when each
+// word is harmless.
+var defaultBlockedPatterns = []string{
+	`(?i)\bkill\s+(?:your\s*self|urself)\b`,
+	`(?i)\bgo\s+die\b`,
+	`(?i)\bpergi\s+mampus\b`,
+	`(?i)\bshut\s+up\b`,
+}
+
+// ContentFilterConfig lists what the output filter blocks on top of the
+// built-in lists.
+type ContentFilterConfig struct {
+	Policy ContentFilterPolicy `yaml:"-"`
+	// Words maps a locale to extra blocked words or phrases.
+	Words map[string][]string `yaml:"words"`
+	// Patterns are extra regular expressions; a match is masked whole.
+	Patterns []string `yaml:"patterns"`
+}
+
+// LoadContentFilterConfig parses the policy and reads extra words and
+// patterns from the YAML file at path. An empty path uses the built-in
+// lists only.
+func LoadContentFilterConfig(policy, path string) (ContentFilterConfig, error) {
+	var cfg ContentFilterConfig
+	if path != "" {
+		b, err := os.ReadFile(path)
+		if err != nil {
+			return ContentFilterConfig{}, fmt.Errorf("read content filter: %w", err)
+		}
+		if err := yaml.Unmarshal(b, &cfg); err != nil {
+			return ContentFilterConfig{}, fmt.Errorf("decode content filter %s: %w", path, err)
+		}
+	}
+	switch p := ContentFilterPolicy(strings.ToLower(strings.TrimSpace(policy))); p {
+	case "", ContentFilterReplace:
+		cfg.Policy = ContentFilterReplace
+	case ContentFilterOff, ContentFilterRegenerate:
+		cfg.Policy = p
+	default:
+		return ContentFilterConfig{}, fmt.Errorf("unknown content filter policy %q: want replace, regenerate, or off", policy)
+	}
+	return cfg, nil
+}
+
+// ContentFilter screens replies for words schools do not allow. It is
+// deterministic and independent of any model-side moderation. A nil
+// *ContentFilter passes text through.
+type ContentFilter struct {
+	policy  ContentFilterPolicy
+	blocked []*regexp.Regexp
+}
+
+// NewContentFilter compiles cfg with the built-in lists. It returns nil when
+// the policy is off.
+func NewContentFilter(cfg ContentFilterConfig) (*ContentFilter, error) {
+	if cfg.Policy == ContentFilterOff {
+		return nil, nil
+	}
+	if cfg.Policy == "" {
+		cfg.Policy = ContentFilterReplace
+	}
+	var words []string
+	for _, list := range []map[string][]string{defaultBlockedWords, cfg.Words} {
+		for _, localeWords := range list {
+			words = append(words, localeWords...)
+		}
+	}
+	f := &ContentFilter{policy: cfg.Policy}
+	if re := compileBlockedWords(words); re != nil {
+		f.blocked = append(f.blocked, re)
+	}
+	for _, pattern := range slices.Concat(defaultBlockedPatterns, cfg.Patterns) {
+		re, err := regexp.Compile(pattern)
+		if err != nil {
+			return nil, fmt.Errorf("content filter pattern %q: %w", pattern, err)
+		}
+		f.blocked = append(f.blocked, re)
+	}
+	return f, nil
+}
+
+// compileBlockedWords builds one case-insensitive alternation, longest word
+// first so "motherfucker" is masked whole rather than around "fuck".
+func compileBlockedWords(words []string) *regexp.Regexp {
+	seen := make(map[string]bool, len(words))
+	var alts []string
+	for _, w := range words {
+		w = strings.ToLower(strings.Join(strings.Fields(w), " "))
+		if w == "" || seen[w] {
+			continue
+		}
+		seen[w] = true
+		alts = append(alts, w)
+	}
+	if len(alts) == 0 {
+		return nil
+	}
+	slices.SortFunc(alts, func(a, b string) int {
+		if len(a) != len(b) {
+			return len(b) - len(a)
+		}
+		return strings.Compare(a, b)
+	})
+	for i, w := range alts {
+		quoted := strings.ReplaceAll(regexp.QuoteMeta(w), " ", `\s+`)
+		if strings.IndexFunc(w, func(r rune) bool { return unicode.Is(unicode.Han, r) }) >= 0 {
+			alts[i] = quoted
+		} else {
+			alts[i] = `\b` + quoted + `\b`
+		}
+	}
+	return regexp.MustCompile(`(?i)(?:` + strings.Join(alts, "|") + `)`)
+}
+
+// Policy reports the configured policy, or off for a nil filter.
+func (f *ContentFilter) Policy() ContentFilterPolicy {
+	if f == nil {
+		return ContentFilterOff
+	}
+	return f.policy
+}
+
+// Clean masks every blocked word and pattern in text and reports how many
+// were found.
+func (f *ContentFilter) Clean(text string) (string, int) {
+	if f == nil || text == "" {
+		return text, 0
+	}
+	hits := 0
+	for _, re := range f.blocked {
+		text = re.ReplaceAllStringFunc(text, func(string) string {
+			hits++
+			return contentFilterMask
+		})
+	}
+	return text, hits
+}
+
+// Blocked reports whether text contains anything the filter would mask.
+func (f *ContentFilter) Blocked(text string) bool {
+	if f == nil {
+		return false
+	}
+	for _, re := range f.blocked {
+		if re.MatchString(text) {
+			return true
+		}
+	}
+	return false
+}
+
+// screenTeachingReply applies the content filter to a finished teaching
+// answer. Under the regenerate policy a blocked answer is rewritten once by
+// the model; whatever is still blocked afterwards is masked.
+func (e *Engine) screenTeachingReply(ctx context.Context, turn *agentTurn, messages []ai.Message, model string, msg chat.InboundMessage, conv *Conversation, resp teachingCompletion, content string) (teachingCompletion, string) {
+	if e.contentFilter == nil {
+		return resp, content
+	}
+	if !e.contentFilter.Blocked(content) {
+		turn.Safety.add(SafetyDecision{Check: SafetyCheckContentFilter, Action: SafetyActionPassed})
+		return resp, content
+	}
+	regenerated := false
+	if e.contentFilter.Policy() == ContentFilterRegenerate {
+		retry := append(slices.Clip(messages),
+			ai.Message{Role: "assistant", Content: resp.Content},
+			ai.Message{Role: "user", Content: contentFilterRewritePrompt},
+		)
+		rewritten, err := e.completeTextTeachingTurn(ctx, turn, retry, model)
+		if err != nil {
+			slog.Warn("content filter rewrite failed; masking original", "error", err)
+		} else {
+			regenerated = true
+			rewritten.InputTokens += resp.InputTokens
+			rewritten.OutputTokens += resp.OutputTokens
+			rewritten.CostUSD += resp.CostUSD
+			resp = rewritten
+			content = e.finishTeachingReply(rewritten.Content, msg, conv)
+		}
+	}
+	content, masked := e.contentFilter.Clean(content)
+	if regenerated {
+		turn.Safety.add(SafetyDecision{Check: SafetyCheckContentFilter, Action: SafetyActionRegenerated})
+	}
+	if masked > 0 {
+		turn.Safety.add(SafetyDecision{Check: SafetyCheckContentFilter, Action: SafetyActionMasked, Hits: masked})
+	}
+	e.logEventAsync(Event{
+		ConversationID: turn.ConversationID,
+		UserID:         turn.UserID,
+		EventType:      "content_filtered",
+		Data: map[string]any{
+			"channel":     turn.Channel,
+			"source":      "teaching",
+			"policy":      string(e.contentFilter.Policy()),
+			"regenerated": regenerated,
+			"masked":      masked,
+		},
+	})
+	return resp, content
+}
+
+// screenReply is the last check on every reply, covering answers that never
+// went through a teaching turn.
+func (e *Engine) screenReply(msg chat.InboundMessage, text string) string {
+	cleaned, masked := e.contentFilter.Clean(text)
+	if masked > 0 {
+		slog.Warn("content filter masked a reply", "channel", msg.Channel, "user_id", msg.UserID, "masked", masked)
+		e.logEventAsync(Event{
+			UserID:    msg.UserID,
+			EventType: "content_filtered",
+			Data: map[string]any{
+				"channel": msg.Channel,
+				"source":  "reply",
+				"policy":  string(e.contentFilter.Policy()),
+				"masked":  masked,
+			},
+		})
+	}
+	return cleaned
+}
@@ -0,0 +1,175 @@
+// Copyright 2026 the P&AI authors. All rights reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package agent_test
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/p-n-ai/pai-bot/internal/agent"
+	"github.com/p-n-ai/pai-bot/internal/ai"
+)
+
+// scriptedReplyProvider returns its responses in order and then keeps
+// repeating the last one.
+type scriptedReplyProvider struct {
+	mu        sync.Mutex
+	responses []string
+	requests  []ai.CompletionRequest
+}
+
+func (p *scriptedReplyProvider) Complete(_ context.Context, req ai.CompletionRequest) (ai.CompletionResponse, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.requests = append(p.requests, req)
+	content := p.responses[0]
+	if len(p.responses) > 1 {
+		p.responses = p.responses[1:]
+	}
+	return ai.CompletionResponse{Content: content, Model: "mock", InputTokens: 10, OutputTokens: 5}, nil
+}
+
+func (p *scriptedReplyProvider) StreamComplete(context.Context, ai.CompletionRequest) (<-chan ai.StreamChunk, error) {
+	return nil, errors.New("not implemented")
+}
+
+func (p *scriptedReplyProvider) Models() []ai.ModelInfo { return nil }
+
+func (p *scriptedReplyProvider) HealthCheck(context.Context) error { return nil }
+
+func newContentFilter(t *testing.T, cfg agent.ContentFilterConfig) *agent.ContentFilter {
+	t.Helper()
+	f, err := agent.NewContentFilter(cfg)
+	if err != nil {
+		t.Fatalf("NewContentFilter() error = %v", err)
+	}
+	return f
+}
+
+func TestContentFilterClean(t *testing.T) {
+	f := newContentFilter(t, agent.ContentFilterConfig{
+		Words:    map[string][]string{"ms": {"bodoh"}},
+		Patterns: []string{`(?i)\bhate\s+you\b`},
+	})
+	tests := []struct {
+		in, want string
+		hits     int
+	}{
+		{"What the FUCK is x?", "What the *** is x?", 1},
+		{"You motherfucker.", "You ***.", 1},
+		{"Jangan jadi Bodoh, cuba lagi.", "Jangan jadi ***, cuba lagi.", 1},
+		{"这是他妈的答案", "这是***答案", 1},
+		{"I hate   you", "I ***", 1},
+		{"Kill yourself", "***", 1},
+		// Whole words only: these contain blocked words inside other words.
+		{"Scunthorpe shitake Dickens sosial", "Scunthorpe shitake Dickens sosial", 0},
+		{"Solve 2x + 3 = 7.", "Solve 2x + 3 = 7.", 0},
+	}
+	for _, tt := range tests {
+		got, hits := f.Clean(tt.in)
+		if got != tt.want || hits != tt.hits {
+			t.Errorf("Clean(%q) = %q, %d; want %q, %d", tt.in, got, hits, tt.want, tt.hits)
+		}
+	}
+
+	var off *agent.ContentFilter
+	if got, hits := off.Clean("shit"); got != "shit" || hits != 0 {
+		t.Errorf("nil filter Clean() = %q, %d; want text unchanged", got, hits)
+	}
+}
+
+func TestLoadContentFilterConfig(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "filter.yaml")
+	if err := os.WriteFile(path, []byte("words:\n  en: [dumb]\npatterns:\n  - '(?i)loser'\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	cfg, err := agent.LoadContentFilterConfig("Regenerate", path)
+	if err != nil {
+		t.Fatalf("LoadContentFilterConfig() error = %v", err)
+	}
+	if cfg.Policy != agent.ContentFilterRegenerate || len(cfg.Words["en"]) != 1 || len(cfg.Patterns) != 1 {
+		t.Fatalf("config = %+v", cfg)
+	}
+	if got, _ := newContentFilter(t, cfg).Clean("dumb loser"); got != "*** ***" {
+		t.Errorf("Clean() = %q, want both extra entries masked", got)
+	}
+
+	if cfg, err := agent.LoadContentFilterConfig("", ""); err != nil || cfg.Policy != agent.ContentFilterReplace {
+		t.Errorf("default config = %+v, %v; want replace", cfg, err)
+	}
+	if f, err := agent.NewContentFilter(agent.ContentFilterConfig{Policy: agent.ContentFilterOff}); f != nil || err != nil {
+		t.Errorf("off policy = %v, %v; want nil filter", f, err)
+	}
+	if _, err := agent.LoadContentFilterConfig("block", ""); err == nil {
+		t.Error("unknown policy accepted")
+	}
+	if _, err := agent.NewContentFilter(agent.ContentFilterConfig{Patterns: []string{"("}}); err == nil {
+		t.Error("invalid pattern accepted")
+	}
+}
+
+func TestContentFilterReplaceMasksTutorAnswer(t *testing.T) {
+	store := agent.NewMemoryStore()
+	provider := &scriptedReplyProvider{responses: []string{"That shit is wrong. Try 2x = 4 again."}}
+	engine := agent.NewEngine(agent.EngineConfig{
+		AIRouter:      mockRouter(provider),
+		Store:         store,
+		ContentFilter: newContentFilter(t, agent.ContentFilterConfig{Policy: agent.ContentFilterReplace}),
+	})
+
+	got := sendAs(t, engine, "websocket", "42", "Is 2x = 5 right?")
+	if strings.Contains(got, "shit") || !strings.Contains(got, "***") {
+		t.Fatalf("reply = %q, want the blocked word masked", got)
+	}
+	conv, _ := store.GetActiveConversation("42")
+	last := conv.Messages[len(conv.Messages)-1]
+	if last.Role != "assistant" || strings.Contains(last.Content, "shit") {
+		t.Errorf("stored reply = %+v, want the masked text", last)
+	}
+}
+
+func TestContentFilterRegenerateRewritesTutorAnswer(t *testing.T) {
+	provider := &scriptedReplyProvider{responses: []string{"Damn right, that's bullshit.", "Not quite. Try dividing both sides by 2."}}
+	engine := agent.NewEngine(agent.EngineConfig{
+		AIRouter:      mockRouter(provider),
+		ContentFilter: newContentFilter(t, agent.ContentFilterConfig{Policy: agent.ContentFilterRegenerate}),
+	})
+
+	if got := sendAs(t, engine, "websocket", "42", "Is x = 4 when 2x = 4?"); got != "Not quite. Try dividing both sides by 2." {
+		t.Fatalf("reply = %q, want the rewritten answer", got)
+	}
+	provider.mu.Lock()
+	defer provider.mu.Unlock()
+	if len(provider.requests) < 2 {
+		t.Fatalf("model calls = %d, want a rewrite request", len(provider.requests))
+	}
+	retry := provider.requests[1].Messages
+	if draft := retry[len(retry)-2]; draft.Role != "assistant" || !strings.Contains(draft.Content, "bullshit") {
+		t.Errorf("rewrite request draft = %+v, want the blocked answer", draft)
+	}
+	if ask := retry[len(retry)-1]; ask.Role != "user" || !strings.Contains(ask.Content, "school-appropriate") {
+		t.Errorf("rewrite request instruction = %+v", ask)
+	}
+}
+
+func TestContentFilterScreensDeterministicReplies(t *testing.T) {
+	canned := agent.NewMemoryCannedAnswerStore()
+	_ = canned.PutCannedAnswer(agent.CannedAnswer{
+		Key: "pricing", Phrases: []string{"is this free"}, Answers: map[string]string{"en": "Hell yes, free as shit."},
+	})
+	engine := agent.NewEngine(agent.EngineConfig{
+		AIRouter:      mockRouter(ai.NewMockProvider("unused")),
+		CannedAnswers: canned,
+		ContentFilter: newContentFilter(t, agent.ContentFilterConfig{}),
+	})
+
+	if got := sendAs(t, engine, "websocket", "42", "is this free"); got != "Hell yes, free as ***." {
+		t.Fatalf("reply = %q, want the canned answer masked", got)
+	}
+}
@@ -22,26 +22,14 @@ const (
 // loadContextPackets gathers selected learner/runtime state for one tutor turn.
 // It returns trust-labeled packets directly, so prompt rendering and tracing do
 // not need a second context representation.
-func (e *Engine) loadContextPackets(_ context.Context, turn *agentTurn, msg chat.InboundMessage, conv *Conversation, topic *curriculum.Topic, teachingNotes string) []contextPacket {
+func (e *Engine) loadContextPackets(ctx context.Context, turn *agentTurn, msg chat.InboundMessage, conv *Conversation, topic *curriculum.Topic, teachingNotes string) []contextPacket {
 	var packets []contextPacket
 
-	profile := learnerProfile{}
-	if name, ok := e.store.GetUserName(msg.UserID); ok && name != "" {
-		profile.Name = name
+	snap, warm := e.warm.snapshot(msg.UserID)
+	if !warm {
+		snap = e.readLearnerSnapshot(msg.UserID, false)
 	}
-	if form, ok := e.store.GetUserForm(msg.UserID); ok && form != "" {
-		profile.Form = form
-	}
-	if lang, ok := e.store.GetUserPreferredLanguage(msg.UserID); ok && lang != "" {
-		profile.Language = lang
-	}
-	if intensity, ok := e.store.GetUserPreferredQuizIntensity(msg.UserID); ok && intensity != "" {
-		profile.QuizIntensity = intensity
-	}
-	if group, ok := e.store.GetUserABGroup(msg.UserID); ok && group != "" {
-		profile.ABGroup = group
-	}
-	packets = appendProfilePackets(packets, profile)
+	packets = appendProfilePackets(packets, snap.Profile)
 
 	if conv != nil {
 		packets = append(packets, newContextPacket(contextPacket{
@@ -86,31 +74,31 @@ func (e *Engine) loadContextPackets(_ context.Context, turn *agentTurn, msg chat
 	}
 
 	if e.tracker != nil {
-		if items, err := e.tracker.GetAllProgress(msg.UserID); err == nil {
-			selected := selectTurnProgress(items, topic, maxTurnProgressItems)
-			if len(selected) > 0 {
-				packets = append(packets, newContextPacket(contextPacket{
-					ID:       "progress.mastery",
-					Kind:     contextKindProgress,
-					Trust:    contextTrustSystemOwned,
-					Source:   "progress",
-					Data:     selected,
-					RenderAs: contextRenderSystemData,
-				}))
-			}
+		if selected := selectTurnProgress(snap.Progress, topic, maxTurnProgressItems); len(selected) > 0 {
+			packets = append(packets, newContextPacket(contextPacket{
+				ID:       "progress.mastery",
+				Kind:     contextKindProgress,
+				Trust:    contextTrustSystemOwned,
+				Source:   "progress",
+				Data:     selected,
+				RenderAs: contextRenderSystemData,
+			}))
 		}
-		if due, err := e.tracker.GetDueReviews(msg.UserID); err == nil {
-			selected := capProgressItems(sortDueReviews(due), maxTurnDueReviews)
-			if len(selected) > 0 {
-				packets = append(packets, newContextPacket(contextPacket{
-					ID:       "progress.due_reviews",
-					Kind:     contextKindProgress,
-					Trust:    contextTrustSystemOwned,
-					Source:   "due_reviews",
-					Data:     selected,
-					RenderAs: contextRenderSystemData,
-				}))
-			}
+		var due []progress.ProgressItem
+		if warm {
+			due = snap.dueReviews(time.Now())
+		} else if items, err := e.tracker.GetDueReviews(msg.UserID); err == nil {
+			due = items
+		}
+		if selected := capProgressItems(sortDueReviews(due), maxTurnDueReviews); len(selected) > 0 {
+			packets = append(packets, newContextPacket(contextPacket{
+				ID:       "progress.due_reviews",
+				Kind:     contextKindProgress,
+				Trust:    contextTrustSystemOwned,
+				Source:   "due_reviews",
+				Data:     selected,
+				RenderAs: contextRenderSystemData,
+			}))
 		}
 	}
 
@@ -149,6 +137,8 @@ func (e *Engine) loadContextPackets(_ context.Context, turn *agentTurn, msg chat
 		}
 	}
 
+	packets = e.appendLearnerMemoryPacket(ctx, packets, msg.UserID, turn.UserContent)
+
 	if turn.HasReply && turn.ReplyText != "" {
 		packets = append(packets, newContextPacket(contextPacket{
 			ID:       "current.reply_to",
@@ -0,0 +1,187 @@
+// Copyright 2026 the P&AI authors. All rights reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package agent
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+
+	"github.com/p-n-ai/pai-bot/internal/platform/msgcrypt"
+)
+
+// conversationArchive is the cold-storage payload for one conversation: its
+// messages plus the compaction summary that was moved out of metadata.
+type conversationArchive struct {
+	Summary     string          `json:"summary,omitempty"`
+	CompactedAt int             `json:"compacted_at,omitempty"`
+	Messages    []StoredMessage `json:"messages"`
+}
+
+func encodeConversationArchive(archive conversationArchive) ([]byte, error) {
+	raw, err := json.Marshal(archive)
+	if err != nil {
+		return nil, fmt.Errorf("encode conversation archive: %w", err)
+	}
+	var buf bytes.Buffer
+	zw := gzip.NewWriter(&buf)
+	if _, err := zw.Write(raw); err != nil {
+		return nil, fmt.Errorf("compress conversation archive: %w", err)
+	}
+	if err := zw.Close(); err != nil {
+		return nil, fmt.Errorf("compress conversation archive: %w", err)
+	}
+	return buf.Bytes(), nil
+}
+
+func decodeConversationArchive(payload []byte) (conversationArchive, error) {
+	zr, err := gzip.NewReader(bytes.NewReader(payload))
+	if err != nil {
+		return conversationArchive{}, fmt.Errorf("decompress conversation archive: %w", err)
+	}
+	defer func() { _ = zr.Close() }()
+	raw, err := io.ReadAll(zr)
+	if err != nil {
+		return conversationArchive{}, fmt.Errorf("decompress conversation archive: %w", err)
+	}
+	var archive conversationArchive
+	if err := json.Unmarshal(raw, &archive); err != nil {
+		return conversationArchive{}, fmt.Errorf("decode conversation archive: %w", err)
+	}
+	return archive, nil
+}
+
+// ArchiveEndedConversations moves up to limit conversations, across all
+// tenants, that ended before cutoff into conversation_archives. Each one is
+// archived in its own transaction; it returns how many were moved.
+func (s *PostgresStore) ArchiveEndedConversations(ctx context.Context, cutoff time.Time, limit int) (int, error) {
+	rows, err := s.pool.Query(ctx,
+		`SELECT id::text
+		 FROM conversations
+		 WHERE archived_at IS NULL
+		   AND ended_at IS NOT NULL
+		   AND ended_at < $1
+		 ORDER BY ended_at ASC
+		 LIMIT $2`,
+		cutoff,
+		limit,
+	)
+	if err != nil {
+		return 0, fmt.Errorf("query archivable conversations: %w", err)
+	}
+	ids, err := pgx.CollectRows(rows, pgx.RowTo[string])
+	if err != nil {
+		return 0, fmt.Errorf("scan archivable conversations: %w", err)
+	}
+
+	archived := 0
+	for _, id := range ids {
+		if err := s.archiveConversation(ctx, id); err != nil {
+			return archived, err
+		}
+		archived++
+	}
+	return archived, nil
+}
+
+func (s *PostgresStore) archiveConversation(ctx context.Context, id string) error {
+	tx, err := s.pool.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("begin archive %s: %w", id, err)
+	}
+	defer func() { _ = tx.Rollback(ctx) }()
+
+	var tenantID string
+	var metadataBytes []byte
+	err = tx.QueryRow(ctx,
+		`SELECT tenant_id::text, metadata
+		 FROM conversations
+		 WHERE id = $1::uuid AND archived_at IS NULL
+		 FOR UPDATE`,
+		id,
+	).Scan(&tenantID, &metadataBytes)
+	if errors.Is(err, pgx.ErrNoRows) {
+		// Archived by another worker since the batch was selected.
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("lock conversation %s: %w", id, err)
+	}
+
+	messages, err := queryConversationMessages(ctx, tx, s.keys, id)
+	if err != nil {
+		return err
+	}
+	metadata := parseConversationMetadata(metadataBytes)
+	payload, err := encodeConversationArchive(conversationArchive{
+		Summary:     metadata.Summary,
+		CompactedAt: metadata.CompactedAt,
+		Messages:    messages,
+	})
+	if err != nil {
+		return err
+	}
+	// The payload holds decrypted messages, so it is sealed as a whole,
+	// bound to the conversation.
+	sealed, keyID, err := s.keys.Seal(string(payload), msgcrypt.Binding(tenantID, id))
+	if err != nil {
+		return fmt.Errorf("encrypt conversation archive %s: %w", id, err)
+	}
+
+	if _, err := tx.Exec(ctx,
+		`INSERT INTO conversation_archives (conversation_id, tenant_id, message_count, payload, payload_key_id)
+		 VALUES ($1::uuid, $2::uuid, $3, $4, $5)`,
+		id, tenantID, len(messages), []byte(sealed), nullIfEmpty(keyID),
+	); err != nil {
+		return fmt.Errorf("insert conversation archive %s: %w", id, err)
+	}
+	if _, err := tx.Exec(ctx, `DELETE FROM messages WHERE conversation_id = $1::uuid`, id); err != nil {
+		return fmt.Errorf("delete archived messages %s: %w", id, err)
+	}
+	if _, err := tx.Exec(ctx,
+		`UPDATE conversations
+		 SET archived_at = NOW(),
+		     metadata = COALESCE(metadata, '{}'::jsonb) - 'summary' - 'compacted_at'
+		 WHERE id = $1::uuid`,
+		id,
+	); err != nil {
+		return fmt.Errorf("mark conversation archived %s: %w", id, err)
+	}
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("commit archive %s: %w", id, err)
+	}
+	return nil
+}
+
+// loadArchivedMessages fills conv from its cold-storage payload.
+func (s *PostgresStore) loadArchivedMessages(ctx context.Context, conv *Conversation) error {
+	var payload []byte
+	var keyID, tenantID string
+	err := s.pool.QueryRow(ctx,
+		`SELECT payload, COALESCE(payload_key_id, ''), tenant_id::text FROM conversation_archives WHERE conversation_id = $1::uuid`,
+		conv.ID,
+	).Scan(&payload, &keyID, &tenantID)
+	if err != nil {
+		return fmt.Errorf("load conversation archive %s: %w", conv.ID, err)
+	}
+	opened, err := s.keys.Open(keyID, string(payload), msgcrypt.Binding(tenantID, conv.ID))
+	if err != nil {
+		return fmt.Errorf("decrypt conversation archive %s: %w", conv.ID, err)
+	}
+	archive, err := decodeConversationArchive([]byte(opened))
+	if err != nil {
+		return err
+	}
+	conv.Summary = archive.Summary
+	conv.CompactedAt = archive.CompactedAt
+	conv.Messages = append(conv.Messages, archive.Messages...)
+	return nil
+}
@@ -0,0 +1,45 @@
+// Copyright 2026 the P&AI authors. All rights reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package agent
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestConversationArchiveRoundTripCompresses(t *testing.T) {
+	archive := conversationArchive{
+		Summary:     "Practised linear equations.",
+		CompactedAt: 2,
+		Messages: []StoredMessage{
+			{ID: "m1", Role: "user", Content: strings.Repeat("solve 2x + 3 = 7 ", 50)},
+			{ID: "m2", Role: "assistant", Content: strings.Repeat("subtract 3 from both sides ", 50), Model: "gpt-5.4"},
+		},
+	}
+
+	payload, err := encodeConversationArchive(archive)
+	if err != nil {
+		t.Fatalf("encodeConversationArchive() error = %v", err)
+	}
+	if rawLen := len(archive.Messages[0].Content) + len(archive.Messages[1].Content); len(payload) >= rawLen {
+		t.Fatalf("payload = %d bytes, want smaller than %d raw bytes", len(payload), rawLen)
+	}
+
+	got, err := decodeConversationArchive(payload)
+	if err != nil {
+		t.Fatalf("decodeConversationArchive() error = %v", err)
+	}
+	if got.Summary != archive.Summary || got.CompactedAt != 2 || len(got.Messages) != 2 {
+		t.Fatalf("decoded archive = %+v", got)
+	}
+	if got.Messages[1].Model != "gpt-5.4" || got.Messages[0].Content != archive.Messages[0].Content {
+		t.Fatalf("decoded messages = %+v", got.Messages)
+	}
+}
+
+func TestDecodeConversationArchiveRejectsUncompressedPayload(t *testing.T) {
+	if _, err := decodeConversationArchive([]byte(`{"messages":[]}`)); err == nil {
+		t.Fatal("decodeConversationArchive() error = nil, want decompress error")
+	}
+}
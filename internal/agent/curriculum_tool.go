@@ -9,6 +9,7 @@ import (
 	"time"
 
 	"github.com/p-n-ai/pai-bot/internal/agentcore"
+	"github.com/p-n-ai/pai-bot/internal/chat"
 	"github.com/p-n-ai/pai-bot/internal/curriculum"
 	"github.com/p-n-ai/pai-bot/internal/llm"
 )
@@ -60,9 +61,13 @@ func (t curriculumLookupTool) Execute(_ context.Context, call llm.ToolCall) (llm
 	}, nil
 }
 
-func (e *Engine) teachingTools() []agentcore.Tool {
-	if e.curriculumLoader == nil {
-		return nil
+func (e *Engine) teachingTools(channel string) []agentcore.Tool {
+	tools := []agentcore.Tool{stepCheckTool{}}
+	if chat.SupportsPhotos(channel) {
+		tools = append(tools, &plotGraphTool{})
 	}
-	return []agentcore.Tool{curriculumLookupTool{loader: e.curriculumLoader}}
+	if e.curriculumLoader != nil {
+		tools = append(tools, curriculumLookupTool{loader: e.curriculumLoader})
+	}
+	return tools
 }
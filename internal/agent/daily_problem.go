@@ -0,0 +1,688 @@
+// Copyright 2026 the P&AI authors. All rights reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package agent
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"hash/fnv"
+	"log/slog"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"github.com/p-n-ai/pai-bot/internal/chat"
+	"github.com/p-n-ai/pai-bot/internal/curriculum"
+	"github.com/p-n-ai/pai-bot/internal/i18n"
+)
+
+// Daily problem sources. Operators curate problems ahead of time; a form
+// with none for the day gets one generated from its curriculum.
+const (
+	DailyProblemCurated   = "curated"
+	DailyProblemGenerated = "generated"
+)
+
+// dailyProblemHour is when the problem of the day goes out, just after quiet
+// hours end.
+const dailyProblemHour = 8 // 8:00 AM MYT
+
+// dailyProblemStartChoice is the button on a pushed problem that opens it as
+// a one-question quiz.
+const dailyProblemStartChoice = "daily:start"
+
+// DailyProblem is one form's problem of the day.
+type DailyProblem struct {
+	ID       string
+	Form     string
+	Day      string // YYYY-MM-DD in MYT
+	TopicID  string
+	Question QuizQuestion
+	Source   string
+}
+
+// DailyProblemSubscriber is a learner who opted in to the problem of the day
+// on Channel.
+type DailyProblemSubscriber struct {
+	UserID  string
+	Channel string
+}
+
+// DailyProblemStore keeps each form's daily problems, learners' opt-ins, and
+// who was sent and answered which problem.
+type DailyProblemStore interface {
+	// GetDailyProblem returns nil when form has no problem on day.
+	GetDailyProblem(form, day string) (*DailyProblem, error)
+	// CreateDailyProblem stores p unless form already has a problem on that
+	// day, and returns the stored problem either way.
+	CreateDailyProblem(p DailyProblem) (DailyProblem, error)
+	SetDailyProblemSubscription(userID, channel string, subscribed bool) error
+	DailyProblemSubscribed(userID string) (bool, error)
+	ListDailyProblemSubscribers() ([]DailyProblemSubscriber, error)
+	// ClaimDailyProblemSend records that problemID goes to userID and reports
+	// false when it already went, or the learner already answered it, so a
+	// restart does not push it twice.
+	ClaimDailyProblemSend(problemID, userID string) (bool, error)
+	// RecordDailyProblemAnswer keeps the learner's first answer and reports
+	// whether this was it.
+	RecordDailyProblemAnswer(problemID, userID string, correct bool) (bool, error)
+	// DailyProblemAnswer returns the learner's first answer, if any.
+	DailyProblemAnswer(problemID, userID string) (answered, correct bool, err error)
+}
+
+// DailyProblemDelivery is a problem of the day ready to push to a learner.
+type DailyProblemDelivery struct {
+	UserID    string
+	Channel   string
+	ProblemID string
+	Form      string
+	TopicID   string
+	Text      string
+}
+
+// DailyProblemSource is what the scheduler pushes each morning. Engine
+// implements it.
+type DailyProblemSource interface {
+	// DailyProblemDeliveries claims and renders today's problem for every
+	// opted-in learner not yet sent it.
+	DailyProblemDeliveries(ctx context.Context, now time.Time) ([]DailyProblemDelivery, error)
+	DailyProblemDelivered(d DailyProblemDelivery)
+}
+
+type memoryDailyProblemAnswer struct {
+	correct bool
+}
+
+// MemoryDailyProblemStore is an in-memory DailyProblemStore.
+type MemoryDailyProblemStore struct {
+	mu          sync.RWMutex
+	problems    map[string]DailyProblem // form + day
+	subscribers map[string]string       // user → channel
+	sent        map[string]bool         // problem + user
+	answers     map[string]memoryDailyProblemAnswer
+}
+
+func NewMemoryDailyProblemStore() *MemoryDailyProblemStore {
+	return &MemoryDailyProblemStore{
+		problems:    make(map[string]DailyProblem),
+		subscribers: make(map[string]string),
+		sent:        make(map[string]bool),
+		answers:     make(map[string]memoryDailyProblemAnswer),
+	}
+}
+
+func (s *MemoryDailyProblemStore) GetDailyProblem(form, day string) (*DailyProblem, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	p, ok := s.problems[form+"\x00"+day]
+	if !ok {
+		return nil, nil
+	}
+	return &p, nil
+}
+
+func (s *MemoryDailyProblemStore) CreateDailyProblem(p DailyProblem) (DailyProblem, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	key := p.Form + "\x00" + p.Day
+	if existing, ok := s.problems[key]; ok {
+		return existing, nil
+	}
+	if p.ID == "" {
+		p.ID = generateID()
+	}
+	s.problems[key] = p
+	return p, nil
+}
+
+func (s *MemoryDailyProblemStore) SetDailyProblemSubscription(userID, channel string, subscribed bool) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if subscribed {
+		s.subscribers[userID] = channel
+	} else {
+		delete(s.subscribers, userID)
+	}
+	return nil
+}
+
+func (s *MemoryDailyProblemStore) DailyProblemSubscribed(userID string) (bool, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	_, ok := s.subscribers[userID]
+	return ok, nil
+}
+
+func (s *MemoryDailyProblemStore) ListDailyProblemSubscribers() ([]DailyProblemSubscriber, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	subs := make([]DailyProblemSubscriber, 0, len(s.subscribers))
+	for userID, channel := range s.subscribers {
+		subs = append(subs, DailyProblemSubscriber{UserID: userID, Channel: channel})
+	}
+	return subs, nil
+}
+
+func (s *MemoryDailyProblemStore) ClaimDailyProblemSend(problemID, userID string) (bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	key := problemID + "\x00" + userID
+	if _, answered := s.answers[key]; answered || s.sent[key] {
+		return false, nil
+	}
+	s.sent[key] = true
+	return true, nil
+}
+
+func (s *MemoryDailyProblemStore) RecordDailyProblemAnswer(problemID, userID string, correct bool) (bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	key := problemID + "\x00" + userID
+	if _, ok := s.answers[key]; ok {
+		return false, nil
+	}
+	s.answers[key] = memoryDailyProblemAnswer{correct: correct}
+	return true, nil
+}
+
+func (s *MemoryDailyProblemStore) DailyProblemAnswer(problemID, userID string) (bool, bool, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	answer, ok := s.answers[problemID+"\x00"+userID]
+	return ok, answer.correct, nil
+}
+
+// PostgresDailyProblemStore keeps daily problems in PostgreSQL for one tenant.
+type PostgresDailyProblemStore struct {
+	pool     *pgxpool.Pool
+	tenantID string
+}
+
+func NewPostgresDailyProblemStore(pool *pgxpool.Pool, tenantID string) *PostgresDailyProblemStore {
+	return &PostgresDailyProblemStore{pool: pool, tenantID: tenantID}
+}
+
+func (s *PostgresDailyProblemStore) GetDailyProblem(form, day string) (*DailyProblem, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), dbTimeout)
+	defer cancel()
+
+	p, err := scanDailyProblem(s.pool.QueryRow(ctx,
+		`SELECT id::text, form, publish_on::text, topic_id, question, source
+		 FROM daily_problems
+		 WHERE tenant_id = $1::uuid AND form = $2 AND publish_on = $3::date`,
+		s.tenantID, form, day,
+	))
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("get daily problem: %w", err)
+	}
+	return &p, nil
+}
+
+func (s *PostgresDailyProblemStore) CreateDailyProblem(p DailyProblem) (DailyProblem, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), dbTimeout)
+	defer cancel()
+
+	question, err := json.Marshal(p.Question)
+	if err != nil {
+		return DailyProblem{}, fmt.Errorf("encode daily problem: %w", err)
+	}
+	if _, err := s.pool.Exec(ctx,
+		`INSERT INTO daily_problems (tenant_id, form, publish_on, topic_id, question, source)
+		 VALUES ($1::uuid, $2, $3::date, $4, $5::jsonb, $6)
+		 ON CONFLICT (tenant_id, form, publish_on) DO NOTHING`,
+		s.tenantID, p.Form, p.Day, p.TopicID, question, p.Source,
+	); err != nil {
+		return DailyProblem{}, fmt.Errorf("create daily problem: %w", err)
+	}
+	stored, err := s.GetDailyProblem(p.Form, p.Day)
+	if err != nil {
+		return DailyProblem{}, err
+	}
+	if stored == nil {
+		return DailyProblem{}, fmt.Errorf("create daily problem: not found after insert")
+	}
+	return *stored, nil
+}
+
+func scanDailyProblem(row pgx.Row) (DailyProblem, error) {
+	var (
+		p        DailyProblem
+		question []byte
+	)
+	if err := row.Scan(&p.ID, &p.Form, &p.Day, &p.TopicID, &question, &p.Source); err != nil {
+		return DailyProblem{}, err
+	}
+	if err := json.Unmarshal(question, &p.Question); err != nil {
+		return DailyProblem{}, fmt.Errorf("decode question: %w", err)
+	}
+	return p, nil
+}
+
+func (s *PostgresDailyProblemStore) SetDailyProblemSubscription(externalID, channel string, subscribed bool) error {
+	ctx, cancel := context.WithTimeout(context.Background(), dbTimeout)
+	defer cancel()
+
+	query := `DELETE FROM daily_problem_subscriptions
+		 WHERE tenant_id = $1::uuid AND user_id IN (` + badgeUserSQL + `)`
+	args := []any{s.tenantID, externalID}
+	if subscribed {
+		query = `INSERT INTO daily_problem_subscriptions (user_id, tenant_id, channel)
+		 SELECT u.id, $1::uuid, $3 FROM (` + badgeUserSQL + `) u
+		 ON CONFLICT (user_id) DO UPDATE SET channel = EXCLUDED.channel`
+		args = append(args, channel)
+	}
+	if _, err := s.pool.Exec(ctx, query, args...); err != nil {
+		return fmt.Errorf("set daily problem subscription: %w", err)
+	}
+	return nil
+}
+
+func (s *PostgresDailyProblemStore) DailyProblemSubscribed(externalID string) (bool, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), dbTimeout)
+	defer cancel()
+
+	var subscribed bool
+	if err := s.pool.QueryRow(ctx,
+		`SELECT EXISTS (
+			SELECT 1 FROM daily_problem_subscriptions
+			WHERE tenant_id = $1::uuid AND user_id IN (`+badgeUserSQL+`)
+		)`,
+		s.tenantID, externalID,
+	).Scan(&subscribed); err != nil {
+		return false, fmt.Errorf("check daily problem subscription: %w", err)
+	}
+	return subscribed, nil
+}
+
+func (s *PostgresDailyProblemStore) ListDailyProblemSubscribers() ([]DailyProblemSubscriber, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), dbTimeout)
+	defer cancel()
+
+	rows, err := s.pool.Query(ctx,
+		`SELECT u.external_id, s.channel
+		 FROM daily_problem_subscriptions s
+		 JOIN users u ON u.id = s.user_id
+		 WHERE s.tenant_id = $1::uuid
+		 ORDER BY s.created_at`,
+		s.tenantID,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("list daily problem subscribers: %w", err)
+	}
+	defer rows.Close()
+
+	var subs []DailyProblemSubscriber
+	for rows.Next() {
+		var sub DailyProblemSubscriber
+		if err := rows.Scan(&sub.UserID, &sub.Channel); err != nil {
+			return nil, fmt.Errorf("scan daily problem subscriber: %w", err)
+		}
+		subs = append(subs, sub)
+	}
+	return subs, rows.Err()
+}
+
+func (s *PostgresDailyProblemStore) ClaimDailyProblemSend(problemID, externalID string) (bool, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), dbTimeout)
+	defer cancel()
+
+	tag, err := s.pool.Exec(ctx,
+		`INSERT INTO daily_problem_attempts (problem_id, user_id, sent_at)
+		 SELECT $3::uuid, u.id, NOW() FROM (`+badgeUserSQL+`) u
+		 ON CONFLICT (problem_id, user_id) DO NOTHING`,
+		s.tenantID, externalID, problemID,
+	)
+	if err != nil {
+		return false, fmt.Errorf("claim daily problem send: %w", err)
+	}
+	return tag.RowsAffected() > 0, nil
+}
+
+func (s *PostgresDailyProblemStore) RecordDailyProblemAnswer(problemID, externalID string, correct bool) (bool, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), dbTimeout)
+	defer cancel()
+
+	tag, err := s.pool.Exec(ctx,
+		`INSERT INTO daily_problem_attempts (problem_id, user_id, answered_at, correct)
+		 SELECT $3::uuid, u.id, NOW(), $4 FROM (`+badgeUserSQL+`) u
+		 ON CONFLICT (problem_id, user_id) DO UPDATE
+		 SET answered_at = EXCLUDED.answered_at, correct = EXCLUDED.correct
+		 WHERE daily_problem_attempts.answered_at IS NULL`,
+		s.tenantID, externalID, problemID, correct,
+	)
+	if err != nil {
+		return false, fmt.Errorf("record daily problem answer: %w", err)
+	}
+	return tag.RowsAffected() > 0, nil
+}
+
+func (s *PostgresDailyProblemStore) DailyProblemAnswer(problemID, externalID string) (bool, bool, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), dbTimeout)
+	defer cancel()
+
+	var correct *bool
+	err := s.pool.QueryRow(ctx,
+		`SELECT a.correct
+		 FROM daily_problem_attempts a
+		 WHERE a.problem_id = $3::uuid
+		   AND a.answered_at IS NOT NULL
+		   AND a.user_id IN (`+badgeUserSQL+`)`,
+		s.tenantID, externalID, problemID,
+	).Scan(&correct)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return false, false, nil
+	}
+	if err != nil {
+		return false, false, fmt.Errorf("load daily problem answer: %w", err)
+	}
+	return true, correct != nil && *correct, nil
+}
+
+// dailyProblem returns form's problem for day, generating and storing one
+// when none was curated.
+func (e *Engine) dailyProblem(ctx context.Context, form, day string) (*DailyProblem, error) {
+	p, err := e.dailyProblems.GetDailyProblem(form, day)
+	if err != nil || p != nil {
+		return p, err
+	}
+	generated, ok := e.generateDailyProblem(ctx, form, day)
+	if !ok {
+		return nil, nil
+	}
+	stored, err := e.dailyProblems.CreateDailyProblem(generated)
+	if err != nil {
+		return nil, err
+	}
+	return &stored, nil
+}
+
+// generateDailyProblem picks a topic from form's syllabus, stable for the
+// day, and asks the AI for a fresh question in the style of its assessment.
+// Without a provider it uses one of the assessment's own questions.
+func (e *Engine) generateDailyProblem(ctx context.Context, form, day string) (DailyProblem, bool) {
+	if e.curriculumLoader == nil {
+		return DailyProblem{}, false
+	}
+	var topics []curriculum.Topic
+	for _, topic := range e.studyPlanTopics(form) {
+		if assessment, ok := e.curriculumLoader.GetAssessment(topic.ID); ok && len(assessment.Questions) > 0 {
+			topics = append(topics, topic)
+		}
+	}
+	if len(topics) == 0 {
+		return DailyProblem{}, false
+	}
+	e.sortTopicsBySyllabus(topics)
+	seed := dailyProblemSeed(form, day)
+	topic := topics[seed%uint32(len(topics))]
+
+	session := NewQuizSession("", topic.ID, nil)
+	questions := e.generateQuizQuestions(ctx, session, topic, 1)
+	if len(questions) == 0 {
+		assessment, _ := e.curriculumLoader.GetAssessment(topic.ID)
+		static := questionsFromAssessment(assessment)
+		questions = static[seed%uint32(len(static)):]
+	}
+	return DailyProblem{
+		Form:     form,
+		Day:      day,
+		TopicID:  topic.ID,
+		Question: questions[0],
+		Source:   DailyProblemGenerated,
+	}, true
+}
+
+func dailyProblemSeed(form, day string) uint32 {
+	h := fnv.New32a()
+	_, _ = h.Write([]byte(form + "\x00" + day))
+	return h.Sum32()
+}
+
+// handleDailyCommand runs /daily: "on" and "off" change the learner's opt-in,
+// and a bare /daily opens today's problem as a one-question quiz.
+func (e *Engine) handleDailyCommand(ctx context.Context, msg chat.InboundMessage, args []string) (string, error) {
+	locale := e.messageLocale(msg, nil)
+	if e.dailyProblems == nil {
+		return i18n.S(locale, i18n.MsgUnknownCommand, "/daily"), nil
+	}
+	if len(args) > 0 {
+		switch strings.ToLower(args[0]) {
+		case "on", "off":
+			subscribed := strings.EqualFold(args[0], "on")
+			if err := e.dailyProblems.SetDailyProblemSubscription(msg.UserID, msg.Channel, subscribed); err != nil {
+				slog.Error("failed to update daily problem subscription", "user_id", msg.UserID, "error", err)
+				return i18n.S(locale, i18n.MsgTechnicalIssue), nil
+			}
+			e.logEventAsync(Event{
+				UserID:    msg.UserID,
+				EventType: "daily_problem_subscription",
+				Data: map[string]any{
+					"channel":    msg.Channel,
+					"subscribed": subscribed,
+				},
+			})
+			if subscribed {
+				return i18n.S(locale, i18n.MsgDailyProblemOn), nil
+			}
+			return i18n.S(locale, i18n.MsgDailyProblemOff), nil
+		}
+	}
+
+	form, _ := e.store.GetUserForm(msg.UserID)
+	if form == "" {
+		return i18n.S(locale, i18n.MsgDailyProblemNoForm), nil
+	}
+	problem, err := e.dailyProblem(ctx, form, usageDay(time.Now()))
+	if err != nil {
+		slog.Error("failed to load daily problem", "user_id", msg.UserID, "form", form, "error", err)
+		return i18n.S(locale, i18n.MsgTechnicalIssue), nil
+	}
+	if problem == nil {
+		return i18n.S(locale, i18n.MsgDailyProblemUnavailable), nil
+	}
+	if _, correct, err := e.dailyProblems.DailyProblemAnswer(problem.ID, msg.UserID); err != nil {
+		slog.Warn("failed to load daily problem answer", "user_id", msg.UserID, "error", err)
+	} else if correct {
+		return i18n.S(locale, i18n.MsgDailyProblemAlreadySolved), nil
+	}
+
+	conv, err := e.getOrCreateConversation(msg.UserID)
+	if err != nil {
+		slog.Error("failed to get conversation for /daily", "user_id", msg.UserID, "error", err)
+		return i18n.S(locale, i18n.MsgTechnicalIssue), nil
+	}
+	session := NewQuizSession(msg.UserID, problem.TopicID, nil)
+	session.AppendQuestions([]QuizQuestion{problem.Question})
+	if err := e.store.UpdateConversationQuizState(conv.ID, conversationStateQuizActive, ConversationQuizState{
+		TopicID:            problem.TopicID,
+		RunState:           defaultQuizRunState(),
+		GeneratedQuestions: session.Questions,
+		DailyProblemID:     problem.ID,
+	}); err != nil {
+		slog.Error("failed to persist daily problem quiz state", "conversation_id", conv.ID, "error", err)
+		return i18n.S(locale, i18n.MsgTechnicalIssue), nil
+	}
+
+	response := i18n.S(locale, i18n.MsgDailyProblemHeader) + "\n" +
+		renderQuizQuestion(e.lookupTopicName(problem.TopicID), session, problem.Question)
+	if _, err := e.store.AddMessage(conv.ID, quizQuestionMessage(response, problem.TopicID, problem.Question)); err != nil {
+		slog.Error("failed to store daily problem prompt", "conversation_id", conv.ID, "error", err)
+	}
+	if subscribed, err := e.dailyProblems.DailyProblemSubscribed(msg.UserID); err == nil && !subscribed {
+		response += "\n\n" + i18n.S(locale, i18n.MsgDailyProblemSubscribeHint)
+	}
+	return response, nil
+}
+
+// recordDailyProblemAnswer keeps the learner's first answer to a daily
+// problem for participation stats. Retries still run through the quiz.
+func (e *Engine) recordDailyProblemAnswer(msg chat.InboundMessage, conv *Conversation, state ConversationQuizState, correct bool) {
+	if e.dailyProblems == nil {
+		return
+	}
+	first, err := e.dailyProblems.RecordDailyProblemAnswer(state.DailyProblemID, msg.UserID, correct)
+	if err != nil {
+		slog.Warn("failed to record daily problem answer", "user_id", msg.UserID, "problem_id", state.DailyProblemID, "error", err)
+		return
+	}
+	if !first {
+		return
+	}
+	e.logEventAsync(Event{
+		ConversationID: conv.ID,
+		UserID:         msg.UserID,
+		EventType:      "daily_problem_answered",
+		Data: map[string]any{
+			"problem_id":       state.DailyProblemID,
+			"topic_id":         state.TopicID,
+			"correct":          correct,
+			"answer_transport": quizInputSource(msg),
+		},
+	})
+}
+
+func (e *Engine) DailyProblemDeliveries(ctx context.Context, now time.Time) ([]DailyProblemDelivery, error) {
+	if e.dailyProblems == nil {
+		return nil, nil
+	}
+	subs, err := e.dailyProblems.ListDailyProblemSubscribers()
+	if err != nil {
+		return nil, err
+	}
+	day := usageDay(now)
+	problems := make(map[string]*DailyProblem)
+	var deliveries []DailyProblemDelivery
+	for _, sub := range subs {
+		form, _ := e.store.GetUserForm(sub.UserID)
+		if form == "" {
+			continue
+		}
+		problem, seen := problems[form]
+		if !seen {
+			problem, err = e.dailyProblem(ctx, form, day)
+			if err != nil {
+				slog.Error("failed to load daily problem", "form", form, "day", day, "error", err)
+			}
+			problems[form] = problem
+		}
+		if problem == nil {
+			continue
+		}
+		claimed, err := e.dailyProblems.ClaimDailyProblemSend(problem.ID, sub.UserID)
+		if err != nil {
+			slog.Warn("failed to claim daily problem send", "user_id", sub.UserID, "problem_id", problem.ID, "error", err)
+			continue
+		}
+		if !claimed {
+			continue
+		}
+		locale := e.messageLocale(chat.InboundMessage{UserID: sub.UserID}, nil)
+		deliveries = append(deliveries, DailyProblemDelivery{
+			UserID:    sub.UserID,
+			Channel:   sub.Channel,
+			ProblemID: problem.ID,
+			Form:      form,
+			TopicID:   problem.TopicID,
+			Text:      e.renderDailyProblemPush(locale, problem),
+		})
+	}
+	return deliveries, nil
+}
+
+// renderDailyProblemPush shows the problem with a button that opens it as a
+// quiz. Channels without buttons are told to send /daily instead.
+func (e *Engine) renderDailyProblemPush(locale string, problem *DailyProblem) string {
+	var b strings.Builder
+	b.WriteString(i18n.S(locale, i18n.MsgDailyProblemHeader))
+	if name := e.lookupTopicName(problem.TopicID); name != "" {
+		b.WriteString("\n")
+		b.WriteString(name)
+	}
+	b.WriteString("\n\n")
+	b.WriteString(problem.Question.Text)
+	if options := quizOptions(problem.Question); len(options) > 0 {
+		b.WriteString("\n")
+		for _, option := range options {
+			b.WriteString("\n- ")
+			b.WriteString(option)
+		}
+	}
+	b.WriteString("\n\n")
+	b.WriteString(i18n.S(locale, i18n.MsgDailyProblemPushHint))
+	b.WriteString("\n")
+	b.WriteString(chat.ChoiceActionCode(dailyProblemStartChoice, i18n.S(locale, i18n.MsgDailyProblemSolve)))
+	return b.String()
+}
+
+func (e *Engine) DailyProblemDelivered(d DailyProblemDelivery) {
+	e.logEventAsync(Event{
+		UserID:    d.UserID,
+		EventType: "daily_problem_sent",
+		Data: map[string]any{
+			"channel":    d.Channel,
+			"problem_id": d.ProblemID,
+			"form":       d.Form,
+			"topic_id":   d.TopicID,
+		},
+	})
+}
+
+// SetDailyProblems turns on the morning problem of the day push.
+func (s *Scheduler) SetDailyProblems(source DailyProblemSource) {
+	s.dailyProblems = source
+}
+
+func (s *Scheduler) runDailyProblemTimer(ctx context.Context) {
+	for {
+		delay := timeUntilNext(dailyProblemHour, 0)
+		s.logger.Info("daily problem scheduled", "fires_in", delay.Round(time.Second))
+
+		timer := time.NewTimer(delay)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return
+		case now := <-timer.C:
+			s.SendDailyProblems(ctx, now)
+		}
+	}
+}
+
+// SendDailyProblems pushes today's problem to every opted-in learner who has
+// not had it yet.
+func (s *Scheduler) SendDailyProblems(ctx context.Context, now time.Time) {
+	if s.dailyProblems == nil {
+		return
+	}
+	deliveries, err := s.dailyProblems.DailyProblemDeliveries(ctx, now)
+	if err != nil {
+		s.logger.Error("failed to load daily problems", "error", err)
+		return
+	}
+	for _, d := range deliveries {
+		if s.lifecycle != nil {
+			if state, ok := s.lifecycle.UserLifecycleState(d.UserID); ok && state != UserActive {
+				continue
+			}
+		}
+		out, ok := chat.RenderTurn(chat.InboundMessage{Channel: d.Channel, UserID: d.UserID}, d.Text, "", chat.TelegramInlineKeyboardContext{})
+		if !ok {
+			continue
+		}
+		if err := s.gateway.Send(ctx, out); err != nil {
+			s.logger.Error("failed to send daily problem", "user_id", d.UserID, "error", err)
+			continue
+		}
+		s.dailyProblems.DailyProblemDelivered(d)
+	}
+	s.logger.Info("daily problems sent", "count", len(deliveries))
+}
@@ -0,0 +1,146 @@
+// Copyright 2026 the P&AI authors. All rights reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package agent_test
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/p-n-ai/pai-bot/internal/agent"
+	"github.com/p-n-ai/pai-bot/internal/ai"
+	"github.com/p-n-ai/pai-bot/internal/chat"
+	"github.com/p-n-ai/pai-bot/internal/i18n"
+)
+
+func newDailyProblemEngine(t *testing.T) (*agent.Engine, *agent.MemoryDailyProblemStore) {
+	t.Helper()
+	problems := agent.NewMemoryDailyProblemStore()
+	engine := agent.NewEngine(agent.EngineConfig{
+		AIRouter:         mockRouter(ai.NewMockProvider("AI response")),
+		Store:            agent.NewMemoryStore(),
+		CurriculumLoader: createTestCurriculumLoader(t),
+		DailyProblems:    problems,
+	})
+	return engine, problems
+}
+
+func onboardToForm1(t *testing.T, engine *agent.Engine, userID string) {
+	t.Helper()
+	sendAs(t, engine, "telegram", userID, "/start")
+	if _, err := engine.ProcessMessage(context.Background(), chat.InboundMessage{
+		Channel: "telegram", UserID: userID, Text: "form:1", CallbackQueryID: "cb-form",
+	}); err != nil {
+		t.Fatalf("ProcessMessage(form:1) error = %v", err)
+	}
+}
+
+func curateDailyProblem(t *testing.T, problems *agent.MemoryDailyProblemStore) agent.DailyProblem {
+	t.Helper()
+	p, err := problems.CreateDailyProblem(agent.DailyProblem{
+		Form:    "1",
+		Day:     time.Now().In(time.FixedZone("MYT", 8*3600)).Format("2006-01-02"),
+		TopicID: "F1-02",
+		Question: agent.QuizQuestion{
+			ID:         "daily",
+			Text:       "Solve 2x = 10.",
+			AnswerType: "exact",
+			Answer:     "5",
+		},
+		Source: agent.DailyProblemCurated,
+	})
+	if err != nil {
+		t.Fatalf("CreateDailyProblem() error = %v", err)
+	}
+	return p
+}
+
+func TestDailyProblemIsGradedThroughQuizAndFirstAnswerCounts(t *testing.T) {
+	engine, problems := newDailyProblemEngine(t)
+	onboardToForm1(t, engine, "aina")
+	p := curateDailyProblem(t, problems)
+
+	got := sendAs(t, engine, "telegram", "aina", "/daily")
+	if !strings.Contains(got, "Solve 2x = 10.") || !strings.Contains(got, i18n.S("en", i18n.MsgDailyProblemSubscribeHint)) {
+		t.Fatalf("/daily = %q, want the problem and a subscribe hint", got)
+	}
+
+	sendAs(t, engine, "telegram", "aina", "4")
+	if answered, correct, _ := problems.DailyProblemAnswer(p.ID, "aina"); !answered || correct {
+		t.Fatalf("answer = %v, %v; want the first, wrong answer kept", answered, correct)
+	}
+
+	got = sendAs(t, engine, "telegram", "aina", "5")
+	if !strings.Contains(got, i18n.S("en", i18n.MsgDailyProblemSolved)) {
+		t.Fatalf("answer reply = %q, want the problem marked solved", got)
+	}
+	if _, correct, _ := problems.DailyProblemAnswer(p.ID, "aina"); correct {
+		t.Fatal("retry overwrote the first answer")
+	}
+}
+
+func TestDailyProblemSolvedTodayIsNotRepeated(t *testing.T) {
+	engine, problems := newDailyProblemEngine(t)
+	onboardToForm1(t, engine, "aina")
+	curateDailyProblem(t, problems)
+
+	sendAs(t, engine, "telegram", "aina", "/daily")
+	sendAs(t, engine, "telegram", "aina", "5")
+	if got, want := sendAs(t, engine, "telegram", "aina", "/daily"), i18n.S("en", i18n.MsgDailyProblemAlreadySolved); got != want {
+		t.Fatalf("/daily = %q, want %q", got, want)
+	}
+}
+
+func TestDailyProblemGeneratedWhenNoneCurated(t *testing.T) {
+	engine, problems := newDailyProblemEngine(t)
+	onboardToForm1(t, engine, "aina")
+
+	sendAs(t, engine, "telegram", "aina", "/daily")
+	day := time.Now().In(time.FixedZone("MYT", 8*3600)).Format("2006-01-02")
+	p, err := problems.GetDailyProblem("1", day)
+	if err != nil || p == nil {
+		t.Fatalf("GetDailyProblem() = %v, %v; want a generated problem", p, err)
+	}
+	if p.Source != agent.DailyProblemGenerated || p.TopicID != "F1-02" || p.Question.Text == "" {
+		t.Fatalf("problem = %+v, want one generated from F1-02", p)
+	}
+}
+
+func TestDailyProblemDeliveriesGoToSubscribersOnce(t *testing.T) {
+	engine, problems := newDailyProblemEngine(t)
+	onboardToForm1(t, engine, "aina")
+	onboardToForm1(t, engine, "badri")
+	curateDailyProblem(t, problems)
+
+	if got, want := sendAs(t, engine, "telegram", "aina", "/daily on"), i18n.S("en", i18n.MsgDailyProblemOn); got != want {
+		t.Fatalf("/daily on = %q, want %q", got, want)
+	}
+
+	deliveries, err := engine.DailyProblemDeliveries(context.Background(), time.Now())
+	if err != nil {
+		t.Fatalf("DailyProblemDeliveries() error = %v", err)
+	}
+	if len(deliveries) != 1 || deliveries[0].UserID != "aina" || deliveries[0].Channel != "telegram" {
+		t.Fatalf("deliveries = %+v, want one for aina on telegram", deliveries)
+	}
+	if !strings.Contains(deliveries[0].Text, "Solve 2x = 10.") || !strings.Contains(deliveries[0].Text, "daily:start") {
+		t.Fatalf("delivery text = %q, want the problem and a solve button", deliveries[0].Text)
+	}
+	if again, _ := engine.DailyProblemDeliveries(context.Background(), time.Now()); len(again) != 0 {
+		t.Fatalf("second run deliveries = %+v, want none", again)
+	}
+
+	got, err := engine.ProcessMessage(context.Background(), chat.InboundMessage{
+		Channel: "telegram", UserID: "aina", Text: "daily:start", CallbackQueryID: "cb-daily",
+	})
+	if err != nil || !strings.Contains(got, "Solve 2x = 10.") {
+		t.Fatalf("daily:start = %q, %v; want the problem opened", got, err)
+	}
+
+	sendAs(t, engine, "telegram", "aina", "/daily off")
+	if subscribed, _ := problems.DailyProblemSubscribed("aina"); subscribed {
+		t.Fatal("still subscribed after /daily off")
+	}
+}
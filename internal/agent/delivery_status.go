@@ -0,0 +1,150 @@
+// Copyright 2026 the P&AI authors. All rights reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package agent
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/jackc/pgx/v5"
+
+	"github.com/p-n-ai/pai-bot/internal/chat"
+)
+
+// RecordDelivery stores the outcome and moves the learner's lifecycle state.
+// A blocked send pauses nudges until a later send gets through.
+func (s *MemoryStore) RecordDelivery(rec chat.DeliveryRecord) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.deliveries = append(s.deliveries, rec)
+
+	current, known := s.userState[rec.UserID]
+	if !known {
+		current = UserActive
+	}
+	next, eventType, ok := lifecycleTransition(current, rec)
+	if !ok {
+		return nil
+	}
+	s.userState[rec.UserID] = next
+	s.lifecycleEvents = append(s.lifecycleEvents, Event{
+		UserID:    rec.UserID,
+		EventType: eventType,
+		Data:      lifecycleEventData(rec, current, next),
+		CreatedAt: rec.At,
+	})
+	return nil
+}
+
+func (s *MemoryStore) UserLifecycleState(userID string) (UserState, bool) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	state, ok := s.userState[userID]
+	return state, ok
+}
+
+// Deliveries returns every recorded delivery, oldest first.
+func (s *MemoryStore) Deliveries() []chat.DeliveryRecord {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return append([]chat.DeliveryRecord(nil), s.deliveries...)
+}
+
+// LifecycleEvents returns the churn and reactivation events recorded so far.
+func (s *MemoryStore) LifecycleEvents() []Event {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return append([]Event(nil), s.lifecycleEvents...)
+}
+
+func (s *PostgresStore) RecordDelivery(rec chat.DeliveryRecord) error {
+	ctx, cancel := context.WithTimeout(context.Background(), dbTimeout)
+	defer cancel()
+
+	tx, err := s.pool.BeginTx(ctx, pgx.TxOptions{})
+	if err != nil {
+		return fmt.Errorf("begin delivery tx: %w", err)
+	}
+	defer func() { _ = tx.Rollback(ctx) }()
+
+	if _, err := tx.Exec(ctx,
+		`INSERT INTO message_deliveries (tenant_id, channel, external_id, status, error, created_at)
+		 VALUES ($1::uuid, $2, $3, $4, NULLIF($5, ''), $6)`,
+		s.tenantID, rec.Channel, rec.UserID, string(rec.Status), rec.Error, rec.At,
+	); err != nil {
+		return fmt.Errorf("insert message delivery: %w", err)
+	}
+
+	// Only sent and blocked outcomes can move the state, so skip the user
+	// lookup for the rest.
+	if rec.Status != chat.DeliverySent && rec.Status != chat.DeliveryBlocked {
+		return tx.Commit(ctx)
+	}
+
+	var userUUID, current string
+	err = tx.QueryRow(ctx,
+		`SELECT id::text, lifecycle_state
+		 FROM users
+		 WHERE tenant_id = $1::uuid AND channel = $2 AND external_id = $3
+		 ORDER BY created_at ASC
+		 LIMIT 1
+		 FOR UPDATE`,
+		s.tenantID, rec.Channel, rec.UserID,
+	).Scan(&userUUID, &current)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return tx.Commit(ctx)
+	}
+	if err != nil {
+		return fmt.Errorf("lookup user lifecycle: %w", err)
+	}
+
+	next, eventType, ok := lifecycleTransition(UserState(current), rec)
+	if !ok {
+		return tx.Commit(ctx)
+	}
+	if _, err := tx.Exec(ctx,
+		`UPDATE users SET lifecycle_state = $2, lifecycle_changed_at = $3, updated_at = NOW()
+		 WHERE id = $1::uuid`,
+		userUUID, string(next), rec.At,
+	); err != nil {
+		return fmt.Errorf("update user lifecycle: %w", err)
+	}
+	data, err := json.Marshal(lifecycleEventData(rec, UserState(current), next))
+	if err != nil {
+		return fmt.Errorf("marshal lifecycle event: %w", err)
+	}
+	if _, err := tx.Exec(ctx,
+		`INSERT INTO events (tenant_id, user_id, event_type, data, created_at)
+		 VALUES ($1::uuid, $2::uuid, $3, $4::jsonb, $5)`,
+		s.tenantID, userUUID, eventType, string(data), rec.At,
+	); err != nil {
+		return fmt.Errorf("insert lifecycle event: %w", err)
+	}
+	return tx.Commit(ctx)
+}
+
+func (s *PostgresStore) UserLifecycleState(externalID string) (UserState, bool) {
+	ctx, cancel := context.WithTimeout(context.Background(), dbTimeout)
+	defer cancel()
+
+	var state string
+	err := s.pool.QueryRow(ctx,
+		`SELECT lifecycle_state
+		 FROM users
+		 WHERE tenant_id = $1::uuid
+		   AND channel = $2
+		   AND external_id = $3
+		 ORDER BY created_at ASC
+		 LIMIT 1`,
+		s.tenantID,
+		s.channel,
+		externalID,
+	).Scan(&state)
+	if err != nil {
+		return "", false
+	}
+	return UserState(state), true
+}
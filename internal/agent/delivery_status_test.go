@@ -0,0 +1,96 @@
+// Copyright 2026 the P&AI authors. All rights reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package agent_test
+
+import (
+	"context"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/p-n-ai/pai-bot/internal/agent"
+	"github.com/p-n-ai/pai-bot/internal/chat"
+	"github.com/p-n-ai/pai-bot/internal/progress"
+)
+
+func TestScheduler_PausesNudgesForBlockedUser(t *testing.T) {
+	tracker := progress.NewMemoryTracker()
+	_ = tracker.SetMastery("blocked-user", "malaysia-kssm", "F1-02", 0.4)
+	store := agent.NewMemoryStore()
+	mockCh := &chat.MockChannel{}
+	gw := chat.NewGateway()
+	gw.Register("telegram", mockCh)
+	gw.SetDeliveryRecorder(store)
+
+	loc, _ := time.LoadLocation("Asia/Kuala_Lumpur")
+	y, m, d := time.Now().In(loc).Date()
+	now := time.Date(y, m, d, 10, 0, 0, 0, loc)
+
+	scheduler := agent.NewScheduler(
+		agent.SchedulerConfig{CheckInterval: time.Second, MaxNudgesPerDay: 3},
+		tracker, nil, nil, nil,
+		agent.NewMemoryNudgeTracker(), gw, nil, nil,
+	)
+	scheduler.SetUserLifecycle(store)
+	ctx := context.Background()
+
+	_ = store.RecordDelivery(chat.DeliveryRecord{Channel: "telegram", UserID: "blocked-user", Status: chat.DeliveryBlocked, At: now})
+	if err := scheduler.CheckUserForNudge(ctx, "blocked-user", now); err != nil {
+		t.Fatalf("CheckUserForNudge() error = %v", err)
+	}
+	if len(mockCh.SentMessages) != 0 {
+		t.Fatalf("nudges sent = %d, want none to a blocked user", len(mockCh.SentMessages))
+	}
+
+	// A later successful send, e.g. a reply after the user unblocks, lifts the pause.
+	_ = store.RecordDelivery(chat.DeliveryRecord{Channel: "telegram", UserID: "blocked-user", Status: chat.DeliverySent, At: now})
+	if err := scheduler.CheckUserForNudge(ctx, "blocked-user", now); err != nil {
+		t.Fatalf("CheckUserForNudge() error = %v", err)
+	}
+	if len(mockCh.SentMessages) != 1 {
+		t.Fatalf("nudges sent = %d, want 1 after the pause lifts", len(mockCh.SentMessages))
+	}
+	if got := store.Deliveries(); len(got) != 3 || got[2].Status != chat.DeliverySent {
+		t.Fatalf("deliveries = %+v, want the nudge recorded as sent", got)
+	}
+}
+
+func TestMemoryStore_DeliveryMovesUserLifecycle(t *testing.T) {
+	store := agent.NewMemoryStore()
+	now := time.Now()
+	record := func(status chat.DeliveryStatus, errText string) {
+		t.Helper()
+		if err := store.RecordDelivery(chat.DeliveryRecord{Channel: "telegram", UserID: "u1", Status: status, Error: errText, At: now}); err != nil {
+			t.Fatalf("RecordDelivery() error = %v", err)
+		}
+	}
+
+	record(chat.DeliverySent, "")
+	if state, ok := store.UserLifecycleState("u1"); ok {
+		t.Fatalf("state after first send = %q, want untracked", state)
+	}
+	record(chat.DeliveryBlocked, "telegram API error 403: Forbidden: bot was blocked by the user")
+	record(chat.DeliveryBlocked, "telegram API error 403: Forbidden: bot was blocked by the user")
+	if state, _ := store.UserLifecycleState("u1"); state != agent.UserBlocked {
+		t.Fatalf("state = %q, want %q", state, agent.UserBlocked)
+	}
+	record(chat.DeliveryFailed, "timeout")
+	record(chat.DeliveryBlocked, "telegram API error 403: Forbidden: user is deactivated")
+	if state, _ := store.UserLifecycleState("u1"); state != agent.UserInactive {
+		t.Fatalf("state = %q, want %q", state, agent.UserInactive)
+	}
+	record(chat.DeliverySent, "")
+	if state, _ := store.UserLifecycleState("u1"); state != agent.UserActive {
+		t.Fatalf("state = %q, want %q", state, agent.UserActive)
+	}
+
+	var got []string
+	for _, ev := range store.LifecycleEvents() {
+		got = append(got, ev.EventType+":"+ev.Data["to"].(string))
+	}
+	want := []string{"user_churned:blocked", "user_churned:inactive", "user_reactivated:active"}
+	if !slices.Equal(got, want) {
+		t.Fatalf("lifecycle events = %v, want %v", got, want)
+	}
+}
@@ -0,0 +1,113 @@
+// Copyright 2026 the P&AI authors. All rights reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package agent
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/p-n-ai/pai-bot/internal/ai"
+	"github.com/p-n-ai/pai-bot/internal/chat"
+)
+
+// DryRunResult is the model request a teaching turn would send, composed
+// without calling a provider.
+type DryRunResult struct {
+	Provider             string         `json:"provider,omitempty"`
+	Model                string         `json:"model,omitempty"`
+	Task                 ai.TaskType    `json:"task"`
+	MaxTokens            int            `json:"max_tokens"`
+	EstimatedInputTokens int            `json:"estimated_input_tokens"`
+	TopicID              string         `json:"topic_id,omitempty"`
+	ContextSources       []string       `json:"context_sources,omitempty"`
+	Budget               map[string]any `json:"budget,omitempty"`
+	Blocked              bool           `json:"blocked,omitempty"`
+	BlockMessage         string         `json:"block_message,omitempty"`
+	Messages             []ai.Message   `json:"messages"`
+}
+
+// DryRunMessage composes the prompt a teaching turn would send for msg and
+// reports the model the router would pick. It is a debugging aid: nothing is
+// stored, no conversation is created, and no provider is called. Commands and
+// onboarding flows are not simulated.
+func (e *Engine) DryRunMessage(ctx context.Context, msg chat.InboundMessage) (*DryRunResult, error) {
+	conv, found := e.store.GetActiveConversation(msg.UserID)
+	if !found {
+		conv = &Conversation{UserID: msg.UserID, State: "teaching"}
+	}
+	userContent := msg.Text
+	if msg.HasImage && userContent == "" {
+		userContent = "Please help me with the attached image."
+	}
+	turn := &agentTurn{
+		ID:             generateID(),
+		UserID:         msg.UserID,
+		ConversationID: conv.ID,
+		Channel:        msg.Channel,
+		Language:       msg.Language,
+		Route:          agentTurnRouteTeaching,
+		TaskType:       ai.TaskTeaching,
+		MaxTokens:      e.replyLimits.forChannel(msg.Channel).maxTokens(e.aiRouter.GenerationDefaults(e.tenantID, ai.TaskTeaching).MaxTokens),
+		InputText:      msg.Text,
+		UserContent:    userContent,
+		HasImage:       msg.HasImage,
+		HasReply:       msg.ReplyToText != "",
+		ReplyText:      msg.ReplyToText,
+		ImageDataURL:   msg.ImageDataURL,
+	}
+
+	// The live turn stores the user message before composing, so the prompt
+	// history includes it; mirror that on a copy.
+	preview := *conv
+	preview.Messages = append(append([]StoredMessage(nil), conv.Messages...), inboundStoredMessage(msg, userContent))
+	topic, notes, switched := e.resolveTurnTopic(msg, &preview)
+	if switched {
+		preview.TopicID = topic.ID
+	}
+	turn.Conversation = &preview
+	turn.Topic = topic
+	turn.TeachingNotes = notes
+	turn.Packets = e.loadContextPackets(ctx, turn, msg, &preview, topic, notes)
+
+	result := &DryRunResult{
+		Task:      turn.TaskType,
+		MaxTokens: turn.MaxTokens,
+		TopicID:   preview.TopicID,
+	}
+	if e.turnHooksEnabled() {
+		hookResult, err := e.runTurnHooks(ctx, turn)
+		if err != nil {
+			return nil, fmt.Errorf("dry run: %w", err)
+		}
+		turn.Packets = hookResult.Packets
+		if hookResult.Blocked {
+			result.Blocked = true
+			result.BlockMessage = hookResult.BlockMessage
+			return result, nil
+		}
+	}
+
+	result.Messages = e.buildPromptMessagesFromTurn(turn)
+	for _, packet := range turn.Packets {
+		result.ContextSources = append(result.ContextSources, packet.ID)
+	}
+	result.Budget = turn.Prompt.Budget.eventData()
+	result.EstimatedInputTokens = turn.Prompt.Budget.total()
+	if result.EstimatedInputTokens == 0 {
+		result.EstimatedInputTokens = estimateMessageTokens(result.Messages)
+	}
+
+	reqModel := ""
+	if msg.ImageDataURL != "" {
+		reqModel = "gpt-4o"
+	}
+	result.Model = reqModel
+	if e.aiRouter != nil {
+		if provider, model, ok := e.aiRouter.SelectModel(ai.TaskTeaching, reqModel); ok {
+			result.Provider = provider
+			result.Model = model
+		}
+	}
+	return result, nil
+}
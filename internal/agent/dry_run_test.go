@@ -0,0 +1,88 @@
+// Copyright 2026 the P&AI authors. All rights reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package agent_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/p-n-ai/pai-bot/internal/agent"
+	"github.com/p-n-ai/pai-bot/internal/ai"
+	"github.com/p-n-ai/pai-bot/internal/chat"
+)
+
+func TestDryRunMessageComposesPromptWithoutSideEffects(t *testing.T) {
+	mockAI := ai.NewMockProvider("should not be called")
+	store := agent.NewMemoryStore()
+	engine := agent.NewEngine(agent.EngineConfig{
+		AIRouter: mockRouter(mockAI),
+		Store:    store,
+	})
+
+	result, err := engine.DryRunMessage(context.Background(), chat.InboundMessage{
+		Channel: "telegram",
+		UserID:  "dry-run-user",
+		Text:    "Apa itu persamaan linear?",
+	})
+	if err != nil {
+		t.Fatalf("DryRunMessage() error = %v", err)
+	}
+	if mockAI.LastRequest != nil {
+		t.Fatal("DryRunMessage() called the provider")
+	}
+	if _, found := store.GetActiveConversation("dry-run-user"); found {
+		t.Fatal("DryRunMessage() created a conversation")
+	}
+	if result.Provider != "mock" || result.Task != ai.TaskTeaching {
+		t.Fatalf("result provider/task = %q/%q, want mock/teaching", result.Provider, result.Task)
+	}
+	if len(result.Messages) < 2 || result.Messages[0].Role != "system" {
+		t.Fatalf("result messages = %+v, want system prompt first", result.Messages)
+	}
+	last := result.Messages[len(result.Messages)-1]
+	if last.Role != "user" || last.Content != "Apa itu persamaan linear?" {
+		t.Fatalf("last message = %+v, want the inbound user message", last)
+	}
+	if result.EstimatedInputTokens <= 0 || result.MaxTokens <= 0 {
+		t.Fatalf("token estimates = %d in / %d max, want positive", result.EstimatedInputTokens, result.MaxTokens)
+	}
+}
+
+func TestDryRunMessageLeavesExistingConversationUntouched(t *testing.T) {
+	mockAI := ai.NewMockProvider("should not be called")
+	store := agent.NewMemoryStore()
+	convID, err := store.CreateConversation(agent.Conversation{UserID: "dry-run-user", State: "teaching"})
+	if err != nil {
+		t.Fatalf("CreateConversation() error = %v", err)
+	}
+	if _, err := store.AddMessage(convID, agent.StoredMessage{Role: "user", Content: "hello"}); err != nil {
+		t.Fatalf("AddMessage() error = %v", err)
+	}
+	engine := agent.NewEngine(agent.EngineConfig{
+		AIRouter: mockRouter(mockAI),
+		Store:    store,
+	})
+
+	result, err := engine.DryRunMessage(context.Background(), chat.InboundMessage{
+		Channel: "telegram",
+		UserID:  "dry-run-user",
+		Text:    "What is 2x + 3 = 7?",
+	})
+	if err != nil {
+		t.Fatalf("DryRunMessage() error = %v", err)
+	}
+	conv, err := store.GetConversation(convID)
+	if err != nil {
+		t.Fatalf("GetConversation() error = %v", err)
+	}
+	if len(conv.Messages) != 1 {
+		t.Fatalf("stored messages = %d, want 1", len(conv.Messages))
+	}
+	if mockAI.LastRequest != nil {
+		t.Fatal("DryRunMessage() called the provider")
+	}
+	if len(result.Messages) < 3 {
+		t.Fatalf("result messages = %+v, want history plus the new message", result.Messages)
+	}
+}
@@ -0,0 +1,46 @@
+// Copyright 2026 the P&AI authors. All rights reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package agent
+
+import (
+	"context"
+	"log/slog"
+
+	"github.com/p-n-ai/pai-bot/internal/apperr"
+)
+
+// errorReply handles a failure that ends a learner's request. It logs err
+// at a level matching its class, records it as the class's analytics event
+// tagged with op when there is a conversation to attach it to, and returns
+// what the learner is told: the message err carries, or else its class's
+// catalog message.
+func (e *Engine) errorReply(locale, userID, conversationID, op string, err error) string {
+	class := apperr.ClassOf(err)
+	level := slog.LevelError
+	switch class {
+	case apperr.ClassUserInput, apperr.ClassSafety:
+		level = slog.LevelInfo
+	case apperr.ClassTransient:
+		level = slog.LevelWarn
+	}
+	slog.Log(context.Background(), level, "request failed",
+		"op", op,
+		"class", class,
+		"user_id", userID,
+		"conversation_id", conversationID,
+		"error", err,
+	)
+	if conversationID != "" {
+		e.logEventAsync(Event{
+			ConversationID: conversationID,
+			UserID:         userID,
+			EventType:      apperr.EventType(err),
+			Data: map[string]any{
+				"op":    op,
+				"class": string(class),
+			},
+		})
+	}
+	return apperr.UserMessage(locale, err)
+}
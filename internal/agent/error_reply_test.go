@@ -0,0 +1,66 @@
+// Copyright 2026 the P&AI authors. All rights reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package agent_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+
+	"github.com/p-n-ai/pai-bot/internal/agent"
+	"github.com/p-n-ai/pai-bot/internal/ai"
+	"github.com/p-n-ai/pai-bot/internal/apperr"
+	"github.com/p-n-ai/pai-bot/internal/chat"
+	"github.com/p-n-ai/pai-bot/internal/i18n"
+)
+
+func TestEngine_AIFailureRepliesAndLogsByErrorClass(t *testing.T) {
+	tests := []struct {
+		name      string
+		err       error
+		wantReply i18n.Key
+		wantEvent string
+	}{
+		{
+			name:      "rate limited",
+			err:       apperr.HTTPStatus(http.StatusTooManyRequests, errors.New("slow down")),
+			wantReply: i18n.MsgServerBusy,
+			wantEvent: "error_transient",
+		},
+		{
+			name:      "rejected key",
+			err:       apperr.HTTPStatus(http.StatusUnauthorized, errors.New("bad key")),
+			wantReply: i18n.MsgTechnicalIssue,
+			wantEvent: "error_dependency",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			events := agent.NewMemoryEventLogger()
+			engine := agent.NewEngine(agent.EngineConfig{
+				AIRouter:    mockRouter(&ai.MockProvider{Err: tt.err}),
+				EventLogger: events,
+				Store:       agent.NewMemoryStore(),
+			})
+
+			reply, err := engine.ProcessMessage(context.Background(), chat.InboundMessage{
+				Channel:  "telegram",
+				UserID:   "err-user",
+				Text:     "Explain linear equations",
+				Language: "en",
+			})
+			if err != nil {
+				t.Fatalf("ProcessMessage() error = %v", err)
+			}
+			if want := i18n.S("en", tt.wantReply); reply != want {
+				t.Fatalf("reply = %q, want %q", reply, want)
+			}
+			event := waitForEvent(t, events, tt.wantEvent)
+			if event.Data["op"] != "ai_completion" || event.ConversationID == "" {
+				t.Fatalf("%s event = %#v", tt.wantEvent, event)
+			}
+		})
+	}
+}
@@ -68,6 +68,29 @@ func (l *MemoryEventLogger) Events() []Event {
 	return append([]Event{}, l.events...)
 }
 
+const insertEventSQL = `INSERT INTO events (tenant_id, user_id, conversation_id, event_type, data, created_at)
+	 SELECT c.tenant_id, c.user_id, c.id, $2, $3::jsonb, $4
+	 FROM conversations c
+	 WHERE c.id = $1::uuid`
+
+// insertEventArgs returns the parameters for insertEventSQL.
+func insertEventArgs(event Event) ([]any, error) {
+	payload := event.Data
+	if payload == nil {
+		payload = map[string]any{}
+	}
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return nil, fmt.Errorf("marshal event data: %w", err)
+	}
+
+	createdAt := event.CreatedAt
+	if createdAt.IsZero() {
+		createdAt = time.Now()
+	}
+	return []any{event.ConversationID, event.EventType, string(data), createdAt}, nil
+}
+
 // PostgresEventLogger inserts events into the events table.
 type PostgresEventLogger struct {
 	pool *pgxpool.Pool
@@ -88,33 +111,15 @@ func (l *PostgresEventLogger) LogEvent(event Event) error {
 		return fmt.Errorf("conversation_id is required")
 	}
 
-	payload := event.Data
-	if payload == nil {
-		payload = map[string]any{}
-	}
-	data, err := json.Marshal(payload)
+	args, err := insertEventArgs(event)
 	if err != nil {
-		return fmt.Errorf("marshal event data: %w", err)
-	}
-
-	createdAt := event.CreatedAt
-	if createdAt.IsZero() {
-		createdAt = time.Now()
+		return err
 	}
 
 	ctx, cancel := context.WithTimeout(context.Background(), dbTimeout)
 	defer cancel()
 
-	cmd, err := l.pool.Exec(ctx,
-		`INSERT INTO events (tenant_id, user_id, event_type, data, created_at)
-		 SELECT c.tenant_id, c.user_id, $2, $3::jsonb, $4
-		 FROM conversations c
-		 WHERE c.id = $1::uuid`,
-		event.ConversationID,
-		event.EventType,
-		string(data),
-		createdAt,
-	)
+	cmd, err := l.pool.Exec(ctx, insertEventSQL, args...)
 	if err != nil {
 		return fmt.Errorf("insert event: %w", err)
 	}
@@ -0,0 +1,152 @@
+// Copyright 2026 the P&AI authors. All rights reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package agent
+
+import (
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/p-n-ai/pai-bot/internal/i18n"
+	"github.com/p-n-ai/pai-bot/internal/platform/featureflags"
+	"github.com/p-n-ai/pai-bot/internal/progress"
+)
+
+// revisionModeWindow is how long before an exam revision mode kicks in.
+const revisionModeWindow = 8 * 7 * 24 * time.Hour
+
+// ExamSitting is one national exam date and the forms that sit it.
+type ExamSitting struct {
+	Exam  string
+	Forms []string // empty means every form
+	Date  time.Time
+}
+
+// ExamCalendar lists upcoming exam sittings, e.g. SPM for Forms 4-5 and the
+// PT3-style lower-secondary assessment for Forms 1-3.
+type ExamCalendar []ExamSitting
+
+// ParseExamCalendar parses "EXAM:forms:date" entries separated by semicolons,
+// e.g. "PT3:1,2,3:2026-10-12;SPM:4,5:2026-11-03". Dates are in MYT.
+func ParseExamCalendar(raw string) (ExamCalendar, error) {
+	loc := examLocation()
+	var calendar ExamCalendar
+	for _, entry := range strings.Split(raw, ";") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+		parts := strings.Split(entry, ":")
+		if len(parts) != 3 || strings.TrimSpace(parts[0]) == "" {
+			return nil, fmt.Errorf("invalid exam calendar entry %q: want EXAM:forms:YYYY-MM-DD", entry)
+		}
+		date, err := time.ParseInLocation("2006-01-02", strings.TrimSpace(parts[2]), loc)
+		if err != nil {
+			return nil, fmt.Errorf("invalid exam date in %q: %w", entry, err)
+		}
+		var forms []string
+		for _, form := range strings.Split(parts[1], ",") {
+			if form = strings.TrimSpace(form); form != "" {
+				forms = append(forms, form)
+			}
+		}
+		calendar = append(calendar, ExamSitting{Exam: strings.TrimSpace(parts[0]), Forms: forms, Date: date})
+	}
+	sort.SliceStable(calendar, func(i, j int) bool { return calendar[i].Date.Before(calendar[j].Date) })
+	return calendar, nil
+}
+
+// NextFor returns the earliest sitting on or after today for the form.
+func (c ExamCalendar) NextFor(form string, now time.Time) (ExamSitting, bool) {
+	for _, sitting := range c {
+		if now.After(sitting.Date.AddDate(0, 0, 1)) {
+			continue
+		}
+		if len(sitting.Forms) == 0 || slices.Contains(sitting.Forms, form) {
+			return sitting, true
+		}
+	}
+	return ExamSitting{}, false
+}
+
+// DaysUntil returns the calendar days in MYT from now's date to the
+// sitting's: 1 the day before, 0 on the day.
+func (s ExamSitting) DaysUntil(now time.Time) int {
+	loc := examLocation()
+	day := func(t time.Time) time.Time {
+		y, m, d := t.In(loc).Date()
+		return time.Date(y, m, d, 0, 0, 0, 0, time.UTC)
+	}
+	return max(int(day(s.Date).Sub(day(now)).Hours()/24), 0)
+}
+
+// examLocation is the zone exam dates are written and counted in.
+func examLocation() *time.Location {
+	loc, err := time.LoadLocation("Asia/Kuala_Lumpur")
+	if err != nil {
+		return time.FixedZone("MYT", 8*60*60)
+	}
+	return loc
+}
+
+// InRevisionWindow reports whether now is close enough to the exam for
+// revision mode.
+func (s ExamSitting) InRevisionWindow(now time.Time) bool {
+	return !now.After(s.Date.AddDate(0, 0, 1)) && s.Date.Sub(now) <= revisionModeWindow
+}
+
+// upcomingExam returns the learner's next exam when revision mode is enabled
+// for the tenant.
+func (e *Engine) upcomingExam(userID string, now time.Time) (ExamSitting, bool) {
+	if len(e.examCalendar) == 0 || !e.featureFlags().Enabled(featureflags.RevisionMode) {
+		return ExamSitting{}, false
+	}
+	form, _ := e.store.GetUserForm(userID)
+	return e.examCalendar.NextFor(form, now)
+}
+
+// revisionExam returns the exam the learner is revising for, if any.
+func (e *Engine) revisionExam(userID string, now time.Time) (ExamSitting, bool) {
+	sitting, ok := e.upcomingExam(userID, now)
+	if !ok || !sitting.InRevisionWindow(now) {
+		return ExamSitting{}, false
+	}
+	return sitting, true
+}
+
+func (e *Engine) appendExamCountdown(userID, locale, report string) string {
+	now := e.now()
+	sitting, ok := e.upcomingExam(userID, now)
+	if !ok {
+		return report
+	}
+	line := i18n.S(locale, i18n.MsgExamCountdown, sitting.Exam, sitting.DaysUntil(now))
+	if sitting.InRevisionWindow(now) {
+		line += "\n" + i18n.S(locale, i18n.MsgRevisionModeOn)
+	}
+	return strings.TrimRight(report, "\n") + "\n\n" + line
+}
+
+// revisionDue reports whether an item is due under revision mode, which
+// halves the remaining spaced-repetition interval for topics not yet mastered.
+func revisionDue(item progress.ProgressItem, now time.Time) bool {
+	if !item.NextReviewAt.After(now) {
+		return true
+	}
+	if item.MasteryScore >= defaultGoalTargetMastery || item.LastStudied.IsZero() {
+		return false
+	}
+	half := item.NextReviewAt.Sub(item.LastStudied) / 2
+	return !item.LastStudied.Add(half).After(now)
+}
+
+// now returns the engine clock's time.
+func (e *Engine) now() time.Time {
+	if e.clock != nil {
+		return e.clock()
+	}
+	return time.Now()
+}
@@ -0,0 +1,80 @@
+// Copyright 2026 the P&AI authors. All rights reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package agent
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/p-n-ai/pai-bot/internal/progress"
+)
+
+func TestParseExamCalendarPicksNextSittingForForm(t *testing.T) {
+	calendar, err := ParseExamCalendar("SPM:4,5:2026-11-03; PT3:1,2,3:2026-10-12")
+	if err != nil {
+		t.Fatalf("ParseExamCalendar() error = %v", err)
+	}
+	now := time.Date(2026, 9, 1, 10, 0, 0, 0, time.UTC)
+
+	sitting, ok := calendar.NextFor("2", now)
+	if !ok || sitting.Exam != "PT3" {
+		t.Fatalf("NextFor(2) = %#v, %v, want PT3", sitting, ok)
+	}
+	if sitting, ok := calendar.NextFor("5", now); !ok || sitting.Exam != "SPM" {
+		t.Fatalf("NextFor(5) = %#v, %v, want SPM", sitting, ok)
+	}
+	if _, ok := calendar.NextFor("2", time.Date(2026, 10, 20, 0, 0, 0, 0, time.UTC)); ok {
+		t.Fatal("NextFor(2) after the sitting should find nothing")
+	}
+	if !sitting.InRevisionWindow(now) {
+		t.Fatal("six weeks before the exam should be inside the revision window")
+	}
+	if sitting.InRevisionWindow(time.Date(2026, 6, 1, 0, 0, 0, 0, time.UTC)) {
+		t.Fatal("four months before the exam should be outside the revision window")
+	}
+}
+
+func TestParseExamCalendarRejectsMalformedEntry(t *testing.T) {
+	if _, err := ParseExamCalendar("SPM:2026-11-03"); err == nil || !strings.Contains(err.Error(), "EXAM:forms") {
+		t.Fatalf("ParseExamCalendar() error = %v, want format error", err)
+	}
+}
+
+func TestRevisionDueHalvesIntervalForWeakTopics(t *testing.T) {
+	studied := time.Date(2026, 10, 1, 10, 0, 0, 0, time.UTC)
+	item := progress.ProgressItem{
+		MasteryScore: 0.4,
+		LastStudied:  studied,
+		NextReviewAt: studied.AddDate(0, 0, 6),
+	}
+	if revisionDue(item, studied.AddDate(0, 0, 2)) {
+		t.Fatal("day 2 of a 6-day interval should not be due yet")
+	}
+	if !revisionDue(item, studied.AddDate(0, 0, 3)) {
+		t.Fatal("halfway through the interval should be due in revision mode")
+	}
+	item.MasteryScore = 0.9
+	if revisionDue(item, studied.AddDate(0, 0, 3)) {
+		t.Fatal("mastered topics keep their normal interval")
+	}
+}
+
+func TestExamSittingDaysUntilCountsMalaysianCalendarDays(t *testing.T) {
+	calendar, _ := ParseExamCalendar("SPM:4,5:2026-11-03")
+	sitting := calendar[0]
+	for _, tc := range []struct {
+		now  time.Time
+		want int
+	}{
+		{time.Date(2026, 11, 1, 15, 59, 0, 0, time.UTC), 2}, // 23:59 MYT on 1 Nov
+		{time.Date(2026, 11, 1, 16, 0, 0, 0, time.UTC), 1},  // midnight MYT on 2 Nov
+		{time.Date(2026, 11, 3, 1, 0, 0, 0, time.UTC), 0},
+		{time.Date(2026, 11, 5, 1, 0, 0, 0, time.UTC), 0},
+	} {
+		if got := sitting.DaysUntil(tc.now); got != tc.want {
+			t.Errorf("DaysUntil(%v) = %d, want %d", tc.now, got, tc.want)
+		}
+	}
+}
@@ -0,0 +1,172 @@
+// Copyright 2026 the P&AI authors. All rights reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package agent
+
+import (
+	"context"
+	"strings"
+
+	"github.com/p-n-ai/pai-bot/internal/chat"
+	"github.com/p-n-ai/pai-bot/internal/i18n"
+)
+
+// explainStrategy is how /again re-explains the last answer.
+type explainStrategy string
+
+const (
+	explainAnalogy  explainStrategy = "analogy"
+	explainSteps    explainStrategy = "steps"
+	explainLanguage explainStrategy = "language"
+)
+
+// explainStrategies is the order /again tries strategies in when the learner
+// does not name one.
+var explainStrategies = []explainStrategy{explainAnalogy, explainSteps, explainLanguage}
+
+// Inline-button data for /again. Telegram caps callback data at 64 bytes, so
+// the preference button carries only the strategy.
+const (
+	againChoice       = "again"
+	againPreferChoice = "again:prefer:"
+)
+
+func parseExplainStrategy(s string) (explainStrategy, bool) {
+	for _, strategy := range explainStrategies {
+		if strings.EqualFold(s, string(strategy)) {
+			return strategy, true
+		}
+	}
+	return "", false
+}
+
+// request is the instruction sent in place of the learner's message. It is
+// never stored, so the question appears once in history.
+func (s explainStrategy) request(locale string) string {
+	const prefix = "I didn't quite get your last explanation. Explain the same idea again in a different way, without repeating it word for word. "
+	switch s {
+	case explainAnalogy:
+		return prefix + "Use a new everyday analogy a Malaysian secondary student would know."
+	case explainSteps:
+		return prefix + "Break it into smaller numbered steps and show every bit of working."
+	case explainLanguage:
+		return prefix + "This time explain it in " + alternateExplainLanguage(locale) + "."
+	}
+	return prefix
+}
+
+// alternateExplainLanguage is the language the "language" strategy switches
+// to: Bahasa Melayu, or English for learners already using it.
+func alternateExplainLanguage(locale string) string {
+	if locale == "ms" {
+		return "English"
+	}
+	return "Bahasa Melayu"
+}
+
+// handleAgainCommand runs /again: "/again [analogy|steps|language]" re-explains
+// the last answer, and "/again prefer <strategy>" records that a re-explanation
+// helped.
+func (e *Engine) handleAgainCommand(ctx context.Context, msg chat.InboundMessage, args []string) (string, error) {
+	conv, ok := e.store.GetActiveConversation(msg.UserID)
+	locale := e.messageLocale(msg, conv)
+	if !ok {
+		return i18n.S(locale, i18n.MsgAgainNothing), nil
+	}
+	if len(args) == 2 && strings.EqualFold(args[0], "prefer") {
+		return e.recordExplanationPreference(msg, conv, locale, args[1]), nil
+	}
+	if conv.State != conversationStateTeaching {
+		return i18n.S(locale, i18n.MsgAgainNothing), nil
+	}
+	question, answer, variants, found := lastExplainedExchange(conv)
+	if !found {
+		return i18n.S(locale, i18n.MsgAgainNothing), nil
+	}
+
+	strategy := explainStrategies[variants%len(explainStrategies)]
+	if len(args) > 0 {
+		named, ok := parseExplainStrategy(args[0])
+		if !ok {
+			return i18n.S(locale, i18n.MsgAgainUsage), nil
+		}
+		strategy = named
+	}
+
+	retry := msg
+	retry.Text = question.Content
+	retry.CallbackQueryID = ""
+	var result TurnResult
+	reply, err := e.runTeachingTurnFor(ctx, retry, conv, "", &result, teachingTurnOptions{
+		ExistingUserMessageID: question.ID,
+		Strategy:              strategy,
+	})
+	if err != nil || result.AssistantMessageID == "" {
+		return reply, err
+	}
+	e.logEventAsync(Event{
+		ConversationID: conv.ID,
+		UserID:         msg.UserID,
+		EventType:      "explanation_regenerated",
+		Data: map[string]any{
+			"channel":             msg.Channel,
+			"strategy":            string(strategy),
+			"variant":             variants + 1,
+			"question_message_id": question.ID,
+			"replaced_message_id": answer.ID,
+			"variant_message_id":  result.AssistantMessageID,
+		},
+	})
+	return reply + "\n" +
+		chat.ChoiceActionCode(againPreferChoice+string(strategy), i18n.S(locale, i18n.MsgAgainClearer)) +
+		chat.ChoiceActionCode(againChoice, i18n.S(locale, i18n.MsgAgainAnotherWay)), nil
+}
+
+// lastExplainedExchange finds the learner's latest question and the latest
+// answer to it. variants counts the re-explanations already given.
+func lastExplainedExchange(conv *Conversation) (question, answer StoredMessage, variants int, found bool) {
+	answers := 0
+	for i := len(conv.Messages) - 1; i >= 0; i-- {
+		m := conv.Messages[i]
+		if !m.Visible() {
+			continue
+		}
+		switch m.Role {
+		case "assistant":
+			if answers == 0 {
+				answer = m
+			}
+			answers++
+		case "user":
+			if answers == 0 {
+				return StoredMessage{}, StoredMessage{}, 0, false
+			}
+			return m, answer, answers - 1, true
+		}
+	}
+	return StoredMessage{}, StoredMessage{}, 0, false
+}
+
+// recordExplanationPreference logs that the learner found a re-explanation
+// clearer than the answers before it.
+func (e *Engine) recordExplanationPreference(msg chat.InboundMessage, conv *Conversation, locale, raw string) string {
+	strategy, ok := parseExplainStrategy(raw)
+	if !ok {
+		return i18n.S(locale, i18n.MsgAgainUsage)
+	}
+	data := map[string]any{
+		"channel":  msg.Channel,
+		"strategy": string(strategy),
+	}
+	if _, answer, variants, found := lastExplainedExchange(conv); found {
+		data["variant"] = variants
+		data["preferred_message_id"] = answer.ID
+	}
+	e.logEventAsync(Event{
+		ConversationID: conv.ID,
+		UserID:         msg.UserID,
+		EventType:      "explanation_preferred",
+		Data:           data,
+	})
+	return i18n.S(locale, i18n.MsgAgainThanks)
+}
@@ -0,0 +1,115 @@
+// Copyright 2026 the P&AI authors. All rights reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package agent_test
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/p-n-ai/pai-bot/internal/agent"
+	"github.com/p-n-ai/pai-bot/internal/ai"
+	"github.com/p-n-ai/pai-bot/internal/chat"
+	"github.com/p-n-ai/pai-bot/internal/i18n"
+)
+
+func TestAgainReexplainsWithoutDuplicatingTheQuestion(t *testing.T) {
+	mockAI := ai.NewMockProvider("Think of x as a box.")
+	store := agent.NewMemoryStore()
+	engine := agent.NewEngine(agent.EngineConfig{
+		AIRouter: mockRouter(mockAI),
+		Store:    store,
+	})
+
+	sendAs(t, engine, "telegram", "aina", "what is x in x + 3 = 7")
+	got := sendAs(t, engine, "telegram", "aina", "/again")
+	if !strings.HasPrefix(got, "Think of x as a box.") || !strings.Contains(got, "again:prefer:analogy") || !strings.Contains(got, "[[PAI_CHOICE:again|") {
+		t.Fatalf("/again = %q, want the new explanation with preference buttons", got)
+	}
+
+	msgs := mockAI.LastRequest.Messages
+	last := msgs[len(msgs)-1]
+	if last.Role != "user" || !strings.Contains(last.Content, "analogy") {
+		t.Fatalf("last prompt message = %#v, want the analogy request", last)
+	}
+	var sawQuestion, sawAnswer bool
+	for _, m := range msgs[:len(msgs)-1] {
+		sawQuestion = sawQuestion || m.Role == "user" && m.Content == "what is x in x + 3 = 7"
+		sawAnswer = sawAnswer || m.Role == "assistant" && m.Content == "Think of x as a box."
+	}
+	if !sawQuestion || !sawAnswer {
+		t.Fatalf("prompt = %#v, want the question and the earlier answer in history", msgs)
+	}
+
+	conv, _ := store.GetActiveConversation("aina")
+	var users, answers int
+	for _, m := range conv.Messages {
+		switch m.Role {
+		case "user":
+			users++
+		case "assistant":
+			answers++
+		}
+	}
+	if users != 1 || answers != 2 {
+		t.Fatalf("history has %d user and %d assistant messages, want 1 and 2", users, answers)
+	}
+}
+
+func TestAgainCyclesStrategiesAndAcceptsANamedOne(t *testing.T) {
+	mockAI := ai.NewMockProvider("AI response")
+	engine := agent.NewEngine(agent.EngineConfig{
+		AIRouter: mockRouter(mockAI),
+		Store:    agent.NewMemoryStore(),
+	})
+
+	sendAs(t, engine, "telegram", "aina", "explain fractions")
+	for _, want := range []string{"analogy", "numbered steps", "Bahasa Melayu"} {
+		resp, err := engine.ProcessMessage(context.Background(), chat.InboundMessage{
+			Channel: "telegram", UserID: "aina", Text: "again", CallbackQueryID: "cb-again", Language: "en",
+		})
+		if err != nil {
+			t.Fatalf("again callback error = %v", err)
+		}
+		if resp == "" {
+			t.Fatal("again callback returned no reply")
+		}
+		msgs := mockAI.LastRequest.Messages
+		if last := msgs[len(msgs)-1].Content; !strings.Contains(last, want) {
+			t.Fatalf("request = %q, want it to mention %q", last, want)
+		}
+	}
+
+	sendAs(t, engine, "telegram", "aina", "/again steps")
+	msgs := mockAI.LastRequest.Messages
+	if last := msgs[len(msgs)-1].Content; !strings.Contains(last, "numbered steps") {
+		t.Fatalf("request = %q, want the named strategy", last)
+	}
+	if got, want := sendAs(t, engine, "telegram", "aina", "/again poem"), i18n.S("en", i18n.MsgAgainUsage); got != want {
+		t.Fatalf("/again poem = %q, want %q", got, want)
+	}
+}
+
+func TestAgainWithoutAnAnswerAndPreference(t *testing.T) {
+	engine := agent.NewEngine(agent.EngineConfig{
+		AIRouter: mockRouter(ai.NewMockProvider("AI response")),
+		Store:    agent.NewMemoryStore(),
+	})
+
+	if got, want := sendAs(t, engine, "telegram", "new", "/again"), i18n.S("en", i18n.MsgAgainNothing); got != want {
+		t.Fatalf("/again = %q, want %q", got, want)
+	}
+
+	sendAs(t, engine, "telegram", "aina", "explain fractions")
+	sendAs(t, engine, "telegram", "aina", "/again")
+	got, err := engine.ProcessMessage(context.Background(), chat.InboundMessage{
+		Channel: "telegram", UserID: "aina", Text: "again:prefer:analogy", CallbackQueryID: "cb-prefer", Language: "en",
+	})
+	if err != nil {
+		t.Fatalf("prefer callback error = %v", err)
+	}
+	if want := i18n.S("en", i18n.MsgAgainThanks); got != want {
+		t.Fatalf("prefer reply = %q, want %q", got, want)
+	}
+}
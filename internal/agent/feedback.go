@@ -0,0 +1,278 @@
+// Copyright 2026 the P&AI authors. All rights reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package agent
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"github.com/p-n-ai/pai-bot/internal/chat"
+	"github.com/p-n-ai/pai-bot/internal/i18n"
+)
+
+const (
+	feedbackPendingState = "feedback_pending"
+	// feedbackContextMessages is how many recent turns are kept with a report
+	// so reviewers can see what the bot actually said.
+	feedbackContextMessages = 6
+	feedbackSnippetLimit    = 500
+	// feedbackOperatorChannel is where operator chats live; tenants configure
+	// a Telegram group or chat ID.
+	feedbackOperatorChannel = "telegram"
+)
+
+// Feedback is a learner's free-text report about the tutor, stored with the
+// conversation it refers to.
+type Feedback struct {
+	ID             string
+	UserID         string
+	ConversationID string
+	// MessageID is the latest assistant answer when the report was sent, the
+	// answer a "this was explained wrong" report most likely refers to.
+	MessageID string
+	Text      string
+	Context   []FeedbackContextMessage
+	CreatedAt time.Time
+}
+
+// FeedbackContextMessage is one recent turn captured with a feedback report.
+type FeedbackContextMessage struct {
+	Role    string `json:"role"`
+	Content string `json:"content"`
+}
+
+// FeedbackInput captures a new feedback report.
+type FeedbackInput struct {
+	ConversationID string
+	MessageID      string
+	Text           string
+	Context        []FeedbackContextMessage
+}
+
+// FeedbackStore persists learner feedback reports.
+type FeedbackStore interface {
+	AddFeedback(userID string, input FeedbackInput) (*Feedback, error)
+}
+
+// MemoryFeedbackStore is an in-memory FeedbackStore.
+type MemoryFeedbackStore struct {
+	mu       sync.RWMutex
+	feedback []*Feedback
+}
+
+func NewMemoryFeedbackStore() *MemoryFeedbackStore {
+	return &MemoryFeedbackStore{}
+}
+
+func (s *MemoryFeedbackStore) AddFeedback(userID string, input FeedbackInput) (*Feedback, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	fb := &Feedback{
+		ID:             generateID(),
+		UserID:         userID,
+		ConversationID: input.ConversationID,
+		MessageID:      input.MessageID,
+		Text:           input.Text,
+		Context:        append([]FeedbackContextMessage(nil), input.Context...),
+		CreatedAt:      time.Now(),
+	}
+	s.feedback = append(s.feedback, fb)
+	copied := *fb
+	return &copied, nil
+}
+
+// ListFeedback returns the reports a user has sent, oldest first.
+func (s *MemoryFeedbackStore) ListFeedback(userID string) []Feedback {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	var out []Feedback
+	for _, fb := range s.feedback {
+		if fb.UserID == userID {
+			out = append(out, *fb)
+		}
+	}
+	return out
+}
+
+// PostgresFeedbackStore persists feedback in PostgreSQL.
+type PostgresFeedbackStore struct {
+	pool     *pgxpool.Pool
+	tenantID string
+	channel  string
+}
+
+func NewPostgresFeedbackStore(pool *pgxpool.Pool, tenantID string) *PostgresFeedbackStore {
+	return &PostgresFeedbackStore{pool: pool, tenantID: tenantID, channel: defaultChannel}
+}
+
+func (s *PostgresFeedbackStore) AddFeedback(externalID string, input FeedbackInput) (*Feedback, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), dbTimeout)
+	defer cancel()
+
+	contextJSON, err := json.Marshal(input.Context)
+	if err != nil {
+		return nil, fmt.Errorf("marshal feedback context: %w", err)
+	}
+
+	fb := &Feedback{
+		UserID:         externalID,
+		ConversationID: input.ConversationID,
+		MessageID:      input.MessageID,
+		Text:           input.Text,
+		Context:        input.Context,
+	}
+	err = s.pool.QueryRow(ctx,
+		`INSERT INTO feedback (tenant_id, user_id, conversation_id, message_id, text, context)
+		 VALUES (
+		   $1::uuid,
+		   (
+		     SELECT id FROM users
+		     WHERE tenant_id = $1::uuid AND channel = $2 AND external_id = $3
+		     ORDER BY created_at ASC
+		     LIMIT 1
+		   ),
+		   NULLIF($4, '')::uuid, NULLIF($5, '')::uuid, $6, $7
+		 )
+		 RETURNING id::text, created_at`,
+		s.tenantID,
+		s.channel,
+		externalID,
+		input.ConversationID,
+		input.MessageID,
+		input.Text,
+		contextJSON,
+	).Scan(&fb.ID, &fb.CreatedAt)
+	if err != nil {
+		return nil, fmt.Errorf("insert feedback: %w", err)
+	}
+	return fb, nil
+}
+
+// handleFeedbackCommand handles "/feedback [text]". Without text it asks for
+// the report and captures the learner's next message.
+func (e *Engine) handleFeedbackCommand(ctx context.Context, msg chat.InboundMessage) (string, error) {
+	conv, err := e.getOrCreateConversation(msg.UserID)
+	if err != nil {
+		slog.Error("failed to get conversation for /feedback", "user_id", msg.UserID, "error", err)
+		return i18n.S(e.messageLocale(msg, nil), i18n.MsgTechnicalIssue), nil
+	}
+	locale := e.messageLocale(msg, conv)
+
+	text := strings.TrimSpace(strings.TrimPrefix(strings.TrimSpace(msg.Text), strings.Fields(msg.Text)[0]))
+	if text != "" {
+		return e.submitFeedback(ctx, msg, conv, text), nil
+	}
+	// Only park plain teaching conversations; quiz and onboarding states own
+	// the next message, so there the report has to come inline.
+	if conv.State != "teaching" {
+		return i18n.S(locale, i18n.MsgFeedbackUsage), nil
+	}
+	if err := e.store.UpdateConversationState(conv.ID, feedbackPendingState); err != nil {
+		slog.Error("failed to set feedback state", "conversation_id", conv.ID, "error", err)
+		return i18n.S(locale, i18n.MsgTechnicalIssue), nil
+	}
+	return i18n.S(locale, i18n.MsgFeedbackPrompt), nil
+}
+
+// handleFeedbackReply records the message that follows a bare /feedback.
+func (e *Engine) handleFeedbackReply(ctx context.Context, msg chat.InboundMessage, conv *Conversation) string {
+	text := strings.TrimSpace(msg.Text)
+	if text == "" {
+		return i18n.S(e.messageLocale(msg, conv), i18n.MsgFeedbackPrompt)
+	}
+	if err := e.store.UpdateConversationState(conv.ID, "teaching"); err != nil {
+		slog.Error("failed to restore conversation state after feedback", "conversation_id", conv.ID, "error", err)
+	}
+	return e.submitFeedback(ctx, msg, conv, text)
+}
+
+func (e *Engine) submitFeedback(ctx context.Context, msg chat.InboundMessage, conv *Conversation, text string) string {
+	locale := e.messageLocale(msg, conv)
+	input := feedbackInputFromConversation(conv, text)
+	fb, err := e.feedback.AddFeedback(msg.UserID, input)
+	if err != nil {
+		slog.Error("failed to store feedback", "user_id", msg.UserID, "conversation_id", conv.ID, "error", err)
+		return i18n.S(locale, i18n.MsgTechnicalIssue)
+	}
+
+	forwarded := e.forwardFeedback(ctx, msg, fb)
+	e.logEventAsync(Event{
+		ConversationID: conv.ID,
+		UserID:         msg.UserID,
+		EventType:      "feedback_submitted",
+		Data: map[string]any{
+			"feedback_id": fb.ID,
+			"message_id":  fb.MessageID,
+			"channel":     msg.Channel,
+			"text_len":    len(text),
+			"forwarded":   forwarded,
+		},
+	})
+	return i18n.S(locale, i18n.MsgFeedbackThanks)
+}
+
+// forwardFeedback posts the report to the tenant operator chat, when one is
+// configured, so a person sees complaints without polling the database.
+func (e *Engine) forwardFeedback(ctx context.Context, msg chat.InboundMessage, fb *Feedback) bool {
+	if e.feedbackOperatorChatID == "" {
+		return false
+	}
+	var b strings.Builder
+	fmt.Fprintf(&b, "Feedback from %s (%s)\n", msg.UserID, msg.Channel)
+	fmt.Fprintf(&b, "Conversation: %s\n\n", fb.ConversationID)
+	b.WriteString(fb.Text)
+	for i := len(fb.Context) - 1; i >= 0; i-- {
+		if fb.Context[i].Role == "assistant" {
+			b.WriteString("\n\nLast answer:\n")
+			b.WriteString(fb.Context[i].Content)
+			break
+		}
+	}
+	e.notifier.Notify(ctx, feedbackOperatorChannel, e.feedbackOperatorChatID, b.String())
+	return true
+}
+
+// feedbackInputFromConversation captures the recent visible turns and the
+// latest assistant answer alongside the report text.
+func feedbackInputFromConversation(conv *Conversation, text string) FeedbackInput {
+	input := FeedbackInput{ConversationID: conv.ID, Text: text}
+	for i := len(conv.Messages) - 1; i >= 0 && len(input.Context) < feedbackContextMessages; i-- {
+		m := conv.Messages[i]
+		if (m.Role != "user" && m.Role != "assistant") || !m.Visible() {
+			continue
+		}
+		content := sanitizeControlContent(m.Content)
+		if content == "" {
+			continue
+		}
+		if m.Role == "assistant" && input.MessageID == "" {
+			input.MessageID = m.ID
+		}
+		input.Context = append(input.Context, FeedbackContextMessage{
+			Role:    m.Role,
+			Content: truncateFeedbackSnippet(content),
+		})
+	}
+	for i, j := 0, len(input.Context)-1; i < j; i, j = i+1, j-1 {
+		input.Context[i], input.Context[j] = input.Context[j], input.Context[i]
+	}
+	return input
+}
+
+func truncateFeedbackSnippet(s string) string {
+	runes := []rune(s)
+	if len(runes) <= feedbackSnippetLimit {
+		return s
+	}
+	return string(runes[:feedbackSnippetLimit]) + "…"
+}
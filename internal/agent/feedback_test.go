@@ -0,0 +1,135 @@
+// Copyright 2026 the P&AI authors. All rights reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package agent_test
+
+import (
+	"context"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/p-n-ai/pai-bot/internal/agent"
+	"github.com/p-n-ai/pai-bot/internal/ai"
+	"github.com/p-n-ai/pai-bot/internal/chat"
+)
+
+type capturedNotification struct {
+	channel, userID, text string
+}
+
+type capturingNotifier struct {
+	mu   sync.Mutex
+	sent []capturedNotification
+}
+
+func (n *capturingNotifier) Notify(_ context.Context, channel, userID, text string) {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	n.sent = append(n.sent, capturedNotification{channel: channel, userID: userID, text: text})
+}
+
+func waitForEvent(t *testing.T, logger *agent.MemoryEventLogger, eventType string) agent.Event {
+	t.Helper()
+	deadline := time.Now().Add(time.Second)
+	for time.Now().Before(deadline) {
+		for _, event := range logger.Events() {
+			if event.EventType == eventType {
+				return event
+			}
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+	t.Fatalf("no %s event logged", eventType)
+	return agent.Event{}
+}
+
+func TestEngine_FeedbackCommandStoresContextAndForwards(t *testing.T) {
+	store := agent.NewMemoryStore()
+	feedback := agent.NewMemoryFeedbackStore()
+	eventLogger := agent.NewMemoryEventLogger()
+	notifier := &capturingNotifier{}
+	engine := agent.NewEngine(agent.EngineConfig{
+		AIRouter:             mockRouter(ai.NewMockProvider("Subtract 3 from both sides, so x = 4.")),
+		Store:                store,
+		EventLogger:          eventLogger,
+		Feedback:             feedback,
+		Notifier:             notifier,
+		FeedbackOperatorChat: "-100777",
+	})
+	ctx := context.Background()
+
+	result, err := engine.ProcessTurn(ctx, chat.InboundMessage{Channel: "telegram", UserID: "fb-user", Text: "solve x + 3 = 9"})
+	if err != nil {
+		t.Fatalf("ProcessTurn() error = %v", err)
+	}
+	resp, err := engine.ProcessMessage(ctx, chat.InboundMessage{Channel: "telegram", UserID: "fb-user", Text: "/feedback 9 - 3 is 6, not 4"})
+	if err != nil {
+		t.Fatalf("ProcessMessage(/feedback) error = %v", err)
+	}
+	if !strings.Contains(resp, "Terima kasih") {
+		t.Fatalf("response = %q, want thanks", resp)
+	}
+
+	reports := feedback.ListFeedback("fb-user")
+	if len(reports) != 1 {
+		t.Fatalf("feedback reports = %d, want 1", len(reports))
+	}
+	report := reports[0]
+	if report.Text != "9 - 3 is 6, not 4" || report.ConversationID != result.ConversationID || report.MessageID != result.AssistantMessageID {
+		t.Fatalf("report = %#v, want text linked to the last answer", report)
+	}
+	if len(report.Context) != 2 || report.Context[0].Role != "user" || report.Context[1].Role != "assistant" {
+		t.Fatalf("report context = %#v, want question then answer", report.Context)
+	}
+
+	if len(notifier.sent) != 1 {
+		t.Fatalf("operator notifications = %d, want 1", len(notifier.sent))
+	}
+	forwarded := notifier.sent[0]
+	if forwarded.userID != "-100777" || !strings.Contains(forwarded.text, "9 - 3 is 6, not 4") || !strings.Contains(forwarded.text, "x = 4") {
+		t.Fatalf("forwarded = %#v, want report and last answer sent to operator chat", forwarded)
+	}
+
+	event := waitForEvent(t, eventLogger, "feedback_submitted")
+	if event.Data["feedback_id"] != report.ID || event.Data["forwarded"] != true {
+		t.Fatalf("event data = %#v, want stored and forwarded report", event.Data)
+	}
+}
+
+func TestEngine_BareFeedbackCommandCapturesNextMessage(t *testing.T) {
+	mockAI := ai.NewMockProvider("answer")
+	store := agent.NewMemoryStore()
+	feedback := agent.NewMemoryFeedbackStore()
+	engine := agent.NewEngine(agent.EngineConfig{
+		AIRouter: mockRouter(mockAI),
+		Store:    store,
+		Feedback: feedback,
+	})
+	ctx := context.Background()
+
+	if _, err := engine.ProcessMessage(ctx, chat.InboundMessage{Channel: "telegram", UserID: "fb-user", Text: "explain ratios"}); err != nil {
+		t.Fatalf("ProcessMessage() error = %v", err)
+	}
+	resp, err := engine.ProcessMessage(ctx, chat.InboundMessage{Channel: "telegram", UserID: "fb-user", Text: "/feedback"})
+	if err != nil || !strings.Contains(resp, "Apa yang tidak kena") {
+		t.Fatalf("ProcessMessage(/feedback) = %q, %v, want prompt", resp, err)
+	}
+
+	mockAI.LastRequest = nil
+	if _, err := engine.ProcessMessage(ctx, chat.InboundMessage{Channel: "telegram", UserID: "fb-user", Text: "the example was confusing"}); err != nil {
+		t.Fatalf("ProcessMessage(report) error = %v", err)
+	}
+	if mockAI.LastRequest != nil {
+		t.Fatal("feedback reply should not be answered by the tutor")
+	}
+	reports := feedback.ListFeedback("fb-user")
+	if len(reports) != 1 || reports[0].Text != "the example was confusing" {
+		t.Fatalf("reports = %#v, want the follow-up message stored", reports)
+	}
+	conv, _ := store.GetActiveConversation("fb-user")
+	if conv.State != "teaching" {
+		t.Fatalf("state = %q, want teaching after feedback", conv.State)
+	}
+}
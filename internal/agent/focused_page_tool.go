@@ -26,11 +26,11 @@ func (e *Engine) completeTeachingTurn(ctx context.Context, turn *agentTurn, mess
 		UserID:  turn.UserID,
 	})
 	if focusedConfigured && !e.aiRouter.HasNativeProvider() {
-		completion, err := e.completeTextTeachingTurn(ctx, messages, model)
+		completion, err := e.completeTextTeachingTurn(ctx, turn, messages, model)
 		return completion, nil, err
 	}
 	if !focusedConfigured && !e.featureFlags().Enabled(featureflags.AgentCore) {
-		completion, err := e.completeTextTeachingTurn(ctx, messages, model)
+		completion, err := e.completeTextTeachingTurn(ctx, turn, messages, model)
 		return completion, nil, err
 	}
 	if !focusedConfigured {
@@ -51,7 +51,7 @@ func (e *Engine) completeTeachingTurn(ctx context.Context, turn *agentTurn, mess
 	}
 	tools := []agentcore.Tool{tool}
 	if e.featureFlags().Enabled(featureflags.AgentCore) {
-		tools = append(e.teachingTools(), tools...)
+		tools = append(e.teachingTools(turn.Channel), tools...)
 	}
 	completion, err := e.completeNativeTeachingTurnWithTools(ctx, turn, model, tools)
 	if err != nil {
@@ -63,11 +63,18 @@ func (e *Engine) completeTeachingTurn(ctx context.Context, turn *agentTurn, mess
 	return completion, tool.artifact, nil
 }
 
-func (e *Engine) completeTextTeachingTurn(ctx context.Context, messages []ai.Message, model string) (teachingCompletion, error) {
-	response, err := e.aiRouter.Complete(ctx, ai.CompletionRequest{Messages: messages, Model: model, Task: ai.TaskTeaching, MaxTokens: 1024})
+func (e *Engine) completeTextTeachingTurn(ctx context.Context, turn *agentTurn, messages []ai.Message, model string) (teachingCompletion, error) {
+	if e.featureFlags().Enabled(featureflags.StreamedTeaching) {
+		return e.streamTextTeachingTurn(ctx, turn, messages, model)
+	}
+	response, err := e.aiRouter.Complete(ctx, ai.CompletionRequest{
+		RequestMetadata: e.requestMetadata(turn.UserID, turn.ConversationID, turn.ID),
+		Messages:        messages, Model: model, Task: ai.TaskTeaching, MaxTokens: turn.MaxTokens,
+	})
 	return teachingCompletion{
 		Content: response.Content, Model: response.Model,
 		InputTokens: response.InputTokens, OutputTokens: response.OutputTokens,
+		CostUSD: response.CostUSD, Truncated: response.Truncated,
 	}, err
 }
 
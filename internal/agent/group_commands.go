@@ -8,6 +8,8 @@ import (
 	"errors"
 	"fmt"
 	"strings"
+	"unicode"
+	"unicode/utf8"
 
 	"github.com/p-n-ai/pai-bot/internal/chat"
 	"github.com/p-n-ai/pai-bot/internal/i18n"
@@ -84,8 +86,10 @@ func (e *Engine) handleJoinGroupCommand(_ context.Context, msg chat.InboundMessa
 	return i18n.S(locale, i18n.MsgGroupJoined, g.Name), nil
 }
 
-// handleLeaderboardCommand handles "/leaderboard [code]".
-// Without args, shows leaderboard for the most recently joined group.
+// handleLeaderboardCommand handles "/leaderboard [code]" and the privacy
+// subcommands "/leaderboard nickname [name]", "/leaderboard hide" and
+// "/leaderboard show". Without args, shows leaderboard for the most recently
+// joined group.
 func (e *Engine) handleLeaderboardCommand(_ context.Context, msg chat.InboundMessage, args []string) (string, error) {
 	locale := e.messageLocale(msg, nil)
 
@@ -97,6 +101,15 @@ func (e *Engine) handleLeaderboardCommand(_ context.Context, msg chat.InboundMes
 		return i18n.S(locale, i18n.MsgGroupUserNotFound), nil
 	}
 
+	if len(args) > 0 {
+		switch strings.ToLower(args[0]) {
+		case "nickname", "nick":
+			return e.setLeaderboardNickname(userUUID, strings.Join(args[1:], " "), locale)
+		case "hide", "show":
+			return e.setLeaderboardOptOut(userUUID, strings.EqualFold(args[0], "hide"), locale)
+		}
+	}
+
 	var g *Group
 
 	// Always resolve from the user's own groups to prevent cross-tenant/non-member leaks.
@@ -123,14 +136,92 @@ func (e *Engine) handleLeaderboardCommand(_ context.Context, msg chat.InboundMes
 		g = &userGroups[0] // most recently joined
 	}
 
-	entries, err := e.groups.GetWeeklyLeaderboard(g.ID, 10)
+	board, err := groupLeaderboard(e.groups, *g, locale)
 	if err != nil {
-		return "", fmt.Errorf("get leaderboard: %w", err)
+		return "", err
 	}
-	if len(entries) == 0 {
+	if board == "" {
 		return i18n.S(locale, i18n.MsgLeaderboardEmpty, g.Name), nil
 	}
+	return board, nil
+}
+
+// setLeaderboardNickname sets the name leaderboards show for the user, or
+// clears it when nickname is empty.
+func (e *Engine) setLeaderboardNickname(userUUID, nickname, locale string) (string, error) {
+	nickname = strings.TrimSpace(nickname)
+	if nickname != "" && !validLeaderboardNickname(nickname) {
+		return i18n.S(locale, i18n.MsgLeaderboardNickInvalid), nil
+	}
+	privacy, err := e.groups.GetLeaderboardPrivacy(userUUID)
+	if err != nil {
+		return "", fmt.Errorf("get leaderboard privacy: %w", err)
+	}
+	privacy.Nickname = nickname
+	if err := e.groups.SetLeaderboardPrivacy(userUUID, e.tenantID, privacy); err != nil {
+		return "", fmt.Errorf("set leaderboard nickname: %w", err)
+	}
+	if nickname == "" {
+		return i18n.S(locale, i18n.MsgLeaderboardNickCleared), nil
+	}
+	return i18n.S(locale, i18n.MsgLeaderboardNickSet, nickname), nil
+}
+
+// setLeaderboardOptOut hides the user from, or returns them to, every
+// leaderboard their groups show.
+func (e *Engine) setLeaderboardOptOut(userUUID string, optOut bool, locale string) (string, error) {
+	privacy, err := e.groups.GetLeaderboardPrivacy(userUUID)
+	if err != nil {
+		return "", fmt.Errorf("get leaderboard privacy: %w", err)
+	}
+	privacy.OptedOut = optOut
+	if err := e.groups.SetLeaderboardPrivacy(userUUID, e.tenantID, privacy); err != nil {
+		return "", fmt.Errorf("set leaderboard opt-out: %w", err)
+	}
+	if optOut {
+		return i18n.S(locale, i18n.MsgLeaderboardHidden), nil
+	}
+	return i18n.S(locale, i18n.MsgLeaderboardShown), nil
+}
 
+// validLeaderboardNickname reports whether name is 2-20 letters, digits,
+// spaces, underscores or hyphens. Markdown characters are rejected so a
+// nickname cannot break the leaderboard's formatting.
+func validLeaderboardNickname(name string) bool {
+	n := utf8.RuneCountInString(name)
+	if n < 2 || n > 20 {
+		return false
+	}
+	for _, r := range name {
+		if !unicode.IsLetter(r) && !unicode.IsDigit(r) && r != ' ' && r != '_' && r != '-' {
+			return false
+		}
+	}
+	return true
+}
+
+// groupLeaderboard renders g's weekly leaderboard: the quiz leaderboard for a
+// class that opted in, the mastery leaderboard otherwise. It returns "" when
+// nobody has placed yet.
+func groupLeaderboard(groups GroupStore, g Group, locale string) (string, error) {
+	if g.Type == "class" && g.QuizLeaderboard {
+		entries, err := groups.GetWeeklyQuizLeaderboard(g.ID, 10)
+		if err != nil {
+			return "", fmt.Errorf("get quiz leaderboard: %w", err)
+		}
+		if len(entries) == 0 {
+			return "", nil
+		}
+		return formatQuizLeaderboard(g.Name, entries, locale), nil
+	}
+
+	entries, err := groups.GetWeeklyLeaderboard(g.ID, 10)
+	if err != nil {
+		return "", fmt.Errorf("get leaderboard: %w", err)
+	}
+	if len(entries) == 0 {
+		return "", nil
+	}
 	return formatLeaderboard(g.Name, entries, locale), nil
 }
 
@@ -175,3 +266,22 @@ func formatLeaderboard(groupName string, entries []LeaderboardEntry, locale stri
 
 	return b.String()
 }
+
+func formatQuizLeaderboard(groupName string, entries []QuizLeaderboardEntry, locale string) string {
+	var b strings.Builder
+	b.WriteString(i18n.S(locale, i18n.MsgQuizLeaderboardHeader, groupName))
+	b.WriteString("\n\n")
+
+	medals := []string{"🥇", "🥈", "🥉"}
+	for _, e := range entries {
+		prefix := fmt.Sprintf("%d.", e.Rank)
+		if e.Rank <= 3 {
+			prefix = medals[e.Rank-1]
+		}
+		fmt.Fprintf(&b, "%s %s — %d XP · %.0f%%\n", prefix, e.DisplayName, e.QuizXP, e.Accuracy*100)
+	}
+
+	b.WriteString("\n")
+	b.WriteString(i18n.S(locale, i18n.MsgLeaderboardPrivacyHint))
+	return b.String()
+}
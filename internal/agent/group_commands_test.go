@@ -7,6 +7,7 @@ import (
 	"context"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/p-n-ai/pai-bot/internal/agent"
 	"github.com/p-n-ai/pai-bot/internal/chat"
@@ -226,3 +227,92 @@ func TestEngine_LeaderboardCommand_NonMemberCodeDenied(t *testing.T) {
 		t.Fatalf("response = %q, non-member should not see group leaderboard", resp)
 	}
 }
+
+func TestEngine_LeaderboardCommand_ClassQuizBoard(t *testing.T) {
+	groupStore := agent.NewMemoryGroupStore()
+	store := agent.NewMemoryStore()
+	_ = store.SetUserName("quiz-a", "Aina")
+	_ = store.SetUserForm("quiz-a", "Form 1")
+
+	engine := agent.NewEngine(agent.EngineConfig{
+		Store:    store,
+		Groups:   groupStore,
+		TenantID: "test-tenant",
+	})
+
+	g, _ := groupStore.CreateGroup("test-tenant", "5 Bestari", "class", "", "", "", "", "")
+	optIn := true
+	if _, err := groupStore.UpdateGroup(g.ID, agent.UpdateGroupInput{QuizLeaderboard: &optIn}); err != nil {
+		t.Fatalf("UpdateGroup() error = %v", err)
+	}
+	for _, id := range []string{"quiz-a", "quiz-b", "quiz-c"} {
+		_ = groupStore.JoinGroup(g.ID, id, "test-tenant", "member")
+	}
+	now := time.Now()
+	groupStore.RecordQuizResult("quiz-a", 4, 5, now)
+	groupStore.RecordQuizResult("quiz-b", 5, 5, now)
+	groupStore.RecordQuizResult("quiz-c", 5, 5, now)
+	_ = groupStore.SetLeaderboardPrivacy("quiz-c", "test-tenant", agent.LeaderboardPrivacy{OptedOut: true})
+
+	send := func(text string) string {
+		t.Helper()
+		resp, err := engine.ProcessMessage(context.Background(), chat.InboundMessage{
+			Channel: "telegram",
+			UserID:  "quiz-a",
+			Text:    text,
+		})
+		if err != nil {
+			t.Fatalf("ProcessMessage(%q) error = %v", text, err)
+		}
+		return resp
+	}
+
+	if resp := send("/leaderboard nickname Algebra Ace"); !strings.Contains(resp, "Algebra Ace") {
+		t.Fatalf("nickname response = %q, want the nickname confirmed", resp)
+	}
+
+	resp := send("/leaderboard")
+	if !strings.Contains(resp, "🥇 User quiz-b — 100 XP · 100%") || !strings.Contains(resp, "🥈 Algebra Ace — 80 XP · 80%") {
+		t.Fatalf("response = %q, want ranked quiz rows with the nickname", resp)
+	}
+	if strings.Contains(resp, "quiz-c") {
+		t.Fatalf("response = %q, opted-out member should be hidden", resp)
+	}
+
+	send("/leaderboard hide")
+	if resp := send("/leaderboard"); strings.Contains(resp, "Algebra Ace") {
+		t.Fatalf("response = %q, want the learner hidden after opting out", resp)
+	}
+	send("/leaderboard show")
+	if resp := send("/leaderboard"); !strings.Contains(resp, "Algebra Ace") {
+		t.Fatalf("response = %q, want the learner back after opting in", resp)
+	}
+}
+
+func TestEngine_LeaderboardCommand_RejectsInvalidNickname(t *testing.T) {
+	groupStore := agent.NewMemoryGroupStore()
+	store := agent.NewMemoryStore()
+	_ = store.SetUserName("quiz-nick", "Badrul")
+	_ = store.SetUserForm("quiz-nick", "Form 1")
+
+	engine := agent.NewEngine(agent.EngineConfig{
+		Store:    store,
+		Groups:   groupStore,
+		TenantID: "test-tenant",
+	})
+
+	resp, err := engine.ProcessMessage(context.Background(), chat.InboundMessage{
+		Channel: "telegram",
+		UserID:  "quiz-nick",
+		Text:    "/leaderboard nickname *boss*",
+	})
+	if err != nil {
+		t.Fatalf("error = %v", err)
+	}
+	if !strings.Contains(resp, "2–20") {
+		t.Fatalf("response = %q, want the nickname rules", resp)
+	}
+	if privacy, _ := groupStore.GetLeaderboardPrivacy("quiz-nick"); privacy.Nickname != "" {
+		t.Fatalf("nickname = %q, want none saved", privacy.Nickname)
+	}
+}
@@ -6,10 +6,12 @@ package agent
 import (
 	"errors"
 	"time"
+
+	"github.com/p-n-ai/pai-bot/internal/apperr"
 )
 
 // ErrGroupClosed is returned when attempting to join a closed group.
-var ErrGroupClosed = errors.New("group is closed for new members")
+var ErrGroupClosed = apperr.UserInput(errors.New("group is closed for new members"))
 
 // Group represents a grouping entity. A "class" is a group with Type="class".
 type Group struct {
@@ -27,6 +29,10 @@ type Group struct {
 	Closed      bool      `json:"closed"`
 	CreatedAt   time.Time `json:"created_at"`
 	UpdatedAt   time.Time `json:"updated_at"`
+
+	// QuizLeaderboard opts a class into the weekly quiz leaderboard. It has
+	// no effect on study groups.
+	QuizLeaderboard bool `json:"quiz_leaderboard"`
 }
 
 // GroupMember represents a user's membership in a group.
@@ -44,6 +50,7 @@ type GroupMemberDelivery struct {
 	ExternalID string // external chat ID for gateway.Send
 	Channel    string // "telegram", "whatsapp"
 	UserName   string
+	Role       string // "member", "leader", "teacher"
 }
 
 // LeaderboardEntry represents a single row in the weekly leaderboard.
@@ -54,6 +61,24 @@ type LeaderboardEntry struct {
 	Rank        int     `json:"rank"`
 }
 
+// QuizLeaderboardEntry represents a single row in a class's weekly quiz
+// leaderboard. DisplayName is the learner's nickname when they set one.
+type QuizLeaderboardEntry struct {
+	UserID      string  `json:"user_id"`
+	DisplayName string  `json:"display_name"`
+	QuizXP      int     `json:"quiz_xp"`  // XP from correct quiz answers over 7 days
+	Accuracy    float64 `json:"accuracy"` // correct / answered over the same window
+	Quizzes     int     `json:"quizzes"`
+	Rank        int     `json:"rank"`
+}
+
+// LeaderboardPrivacy is how a learner appears on leaderboards. OptedOut
+// learners are left off every leaderboard their groups show.
+type LeaderboardPrivacy struct {
+	Nickname string `json:"nickname"`
+	OptedOut bool   `json:"opted_out"`
+}
+
 // UpdateGroupInput contains optional fields for updating a group.
 type UpdateGroupInput struct {
 	Name        *string `json:"name,omitempty"`
@@ -62,6 +87,9 @@ type UpdateGroupInput struct {
 	Subject     *string `json:"subject,omitempty"`
 	Cadence     *string `json:"cadence,omitempty"`
 	Closed      *bool   `json:"closed,omitempty"`
+
+	// QuizLeaderboard is honored for classes only.
+	QuizLeaderboard *bool `json:"quiz_leaderboard,omitempty"`
 }
 
 // GroupStore persists group and membership data.
@@ -86,4 +114,10 @@ type GroupStore interface {
 
 	// Leaderboard — uses mastery_snapshots for 7-day delta
 	GetWeeklyLeaderboard(groupID string, limit int) ([]LeaderboardEntry, error)
+	// Quiz leaderboard — quiz_completed events over the last 7 days
+	GetWeeklyQuizLeaderboard(groupID string, limit int) ([]QuizLeaderboardEntry, error)
+
+	// Leaderboard privacy — one setting per learner, across all their groups
+	GetLeaderboardPrivacy(userID string) (LeaderboardPrivacy, error)
+	SetLeaderboardPrivacy(userID, tenantID string, privacy LeaderboardPrivacy) error
 }
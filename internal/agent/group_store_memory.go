@@ -4,17 +4,30 @@
 package agent
 
 import (
+	"cmp"
 	"fmt"
+	"slices"
 	"sync"
 	"time"
+
+	"github.com/p-n-ai/pai-bot/internal/progress"
 )
 
 // MemoryGroupStore is an in-memory implementation of GroupStore for testing.
 type MemoryGroupStore struct {
-	groups  map[string]*Group
-	members map[string][]groupMemberEntry // groupID -> members
-	mu      sync.RWMutex
-	counter int
+	groups      map[string]*Group
+	members     map[string][]groupMemberEntry // groupID -> members
+	privacy     map[string]LeaderboardPrivacy // userID -> leaderboard privacy
+	quizResults []memoryQuizResult
+	mu          sync.RWMutex
+	counter     int
+}
+
+type memoryQuizResult struct {
+	UserID  string
+	Correct int
+	Total   int
+	At      time.Time
 }
 
 type groupMemberEntry struct {
@@ -29,6 +42,7 @@ func NewMemoryGroupStore() *MemoryGroupStore {
 	return &MemoryGroupStore{
 		groups:  make(map[string]*Group),
 		members: make(map[string][]groupMemberEntry),
+		privacy: make(map[string]LeaderboardPrivacy),
 	}
 }
 
@@ -109,6 +123,9 @@ func (s *MemoryGroupStore) UpdateGroup(id string, input UpdateGroupInput) (*Grou
 	if input.Closed != nil {
 		g.Closed = *input.Closed
 	}
+	if input.QuizLeaderboard != nil {
+		g.QuizLeaderboard = *input.QuizLeaderboard
+	}
 	g.UpdatedAt = time.Now()
 	cp := *g
 	return &cp, nil
@@ -224,6 +241,7 @@ func (s *MemoryGroupStore) GetGroupMembersWithChannel(groupID string) ([]GroupMe
 			ExternalID: m.UserID, // in memory store, userID doubles as externalID
 			Channel:    "telegram",
 			UserName:   "User " + m.UserID,
+			Role:       m.Role,
 		})
 	}
 	return result, nil
@@ -235,3 +253,74 @@ func (s *MemoryGroupStore) GetWeeklyLeaderboard(groupID string, limit int) ([]Le
 	// In-memory: return empty leaderboard (no mastery snapshots to compare)
 	return nil, nil
 }
+
+// RecordQuizResult stands in for the quiz_completed events the Postgres store
+// ranks on.
+func (s *MemoryGroupStore) RecordQuizResult(userID string, correct, total int, at time.Time) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.quizResults = append(s.quizResults, memoryQuizResult{UserID: userID, Correct: correct, Total: total, At: at})
+}
+
+func (s *MemoryGroupStore) GetWeeklyQuizLeaderboard(groupID string, limit int) ([]QuizLeaderboardEntry, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	if limit <= 0 {
+		limit = 10
+	}
+	since := time.Now().Add(-7 * 24 * time.Hour)
+	var entries []QuizLeaderboardEntry
+	for _, m := range s.members[groupID] {
+		if m.Role == "teacher" || s.privacy[m.UserID].OptedOut {
+			continue
+		}
+		var correct, answered, quizzes int
+		for _, r := range s.quizResults {
+			if r.UserID == m.UserID && !r.At.Before(since) {
+				correct += r.Correct
+				answered += r.Total
+				quizzes++
+			}
+		}
+		if quizzes == 0 {
+			continue
+		}
+		entry := QuizLeaderboardEntry{
+			UserID:      m.UserID,
+			DisplayName: "User " + m.UserID,
+			QuizXP:      correct * progress.XPQuizCorrect,
+			Quizzes:     quizzes,
+		}
+		if nickname := s.privacy[m.UserID].Nickname; nickname != "" {
+			entry.DisplayName = nickname
+		}
+		if answered > 0 {
+			entry.Accuracy = float64(correct) / float64(answered)
+		}
+		entries = append(entries, entry)
+	}
+	slices.SortStableFunc(entries, func(a, b QuizLeaderboardEntry) int {
+		return cmp.Or(cmp.Compare(b.QuizXP, a.QuizXP), cmp.Compare(b.Accuracy, a.Accuracy))
+	})
+	if len(entries) > limit {
+		entries = entries[:limit]
+	}
+	for i := range entries {
+		entries[i].Rank = i + 1
+	}
+	return entries, nil
+}
+
+func (s *MemoryGroupStore) GetLeaderboardPrivacy(userID string) (LeaderboardPrivacy, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.privacy[userID], nil
+}
+
+func (s *MemoryGroupStore) SetLeaderboardPrivacy(userID, _ string, privacy LeaderboardPrivacy) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.privacy[userID] = privacy
+	return nil
+}
@@ -13,6 +13,8 @@ import (
 
 	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgxpool"
+
+	"github.com/p-n-ai/pai-bot/internal/progress"
 )
 
 const joinCodeLen = 6
@@ -63,7 +65,7 @@ func (s *PostgresGroupStore) GetGroupByID(id string) (*Group, error) {
 		SELECT g.id::text, g.tenant_id::text, g.name, g.type, g.description, g.syllabus, g.subject, g.cadence,
 		       g.join_code, COALESCE(g.created_by::text, ''), g.created_at, g.updated_at,
 		       (SELECT COUNT(*) FROM group_members gm WHERE gm.group_id = g.id)::int,
-		       g.closed
+		       g.closed, g.quiz_leaderboard
 		FROM groups g WHERE g.id = $1::uuid`, id)
 }
 
@@ -74,7 +76,7 @@ func (s *PostgresGroupStore) GetGroupByJoinCode(code string) (*Group, error) {
 		SELECT g.id::text, g.tenant_id::text, g.name, g.type, g.description, g.syllabus, g.subject, g.cadence,
 		       g.join_code, COALESCE(g.created_by::text, ''), g.created_at, g.updated_at,
 		       (SELECT COUNT(*) FROM group_members gm WHERE gm.group_id = g.id)::int,
-		       g.closed
+		       g.closed, g.quiz_leaderboard
 		FROM groups g WHERE g.join_code = $1`, strings.ToUpper(strings.TrimSpace(code)))
 }
 
@@ -82,7 +84,7 @@ func (s *PostgresGroupStore) scanGroup(ctx context.Context, query string, args .
 	g := &Group{}
 	err := s.pool.QueryRow(ctx, query, args...).Scan(
 		&g.ID, &g.TenantID, &g.Name, &g.Type, &g.Description, &g.Syllabus, &g.Subject, &g.Cadence,
-		&g.JoinCode, &g.CreatedBy, &g.CreatedAt, &g.UpdatedAt, &g.MemberCount, &g.Closed,
+		&g.JoinCode, &g.CreatedBy, &g.CreatedAt, &g.UpdatedAt, &g.MemberCount, &g.Closed, &g.QuizLeaderboard,
 	)
 	if errors.Is(err, pgx.ErrNoRows) {
 		return nil, nil
@@ -129,19 +131,24 @@ func (s *PostgresGroupStore) UpdateGroup(id string, input UpdateGroupInput) (*Gr
 	if input.Closed != nil {
 		setClauses = append(setClauses, fmt.Sprintf("closed = $%d", argIdx))
 		args = append(args, *input.Closed)
+		argIdx++
+	}
+	if input.QuizLeaderboard != nil {
+		setClauses = append(setClauses, fmt.Sprintf("quiz_leaderboard = $%d", argIdx))
+		args = append(args, *input.QuizLeaderboard)
 	}
 
 	query := fmt.Sprintf(
 		`UPDATE groups SET %s WHERE id = $1::uuid
 		 RETURNING id::text, tenant_id::text, name, type, description, syllabus, subject, cadence,
-		           join_code, COALESCE(created_by::text, ''), created_at, updated_at, closed`,
+		           join_code, COALESCE(created_by::text, ''), created_at, updated_at, closed, quiz_leaderboard`,
 		strings.Join(setClauses, ", "),
 	)
 
 	g := &Group{}
 	err := s.pool.QueryRow(ctx, query, args...).Scan(
 		&g.ID, &g.TenantID, &g.Name, &g.Type, &g.Description, &g.Syllabus, &g.Subject, &g.Cadence,
-		&g.JoinCode, &g.CreatedBy, &g.CreatedAt, &g.UpdatedAt, &g.Closed,
+		&g.JoinCode, &g.CreatedBy, &g.CreatedAt, &g.UpdatedAt, &g.Closed, &g.QuizLeaderboard,
 	)
 	if errors.Is(err, pgx.ErrNoRows) {
 		return nil, nil
@@ -245,7 +252,7 @@ func (s *PostgresGroupStore) GetUserGroups(userID string) ([]Group, error) {
 		SELECT g.id::text, g.tenant_id::text, g.name, g.type, g.description, g.syllabus, g.subject, g.cadence,
 		       g.join_code, COALESCE(g.created_by::text, ''), g.created_at, g.updated_at,
 		       COUNT(gm_count.id)::int,
-		       g.closed
+		       g.closed, g.quiz_leaderboard
 		FROM groups g
 		JOIN group_members user_membership ON user_membership.group_id = g.id
 		LEFT JOIN group_members gm_count ON gm_count.group_id = g.id
@@ -263,7 +270,7 @@ func (s *PostgresGroupStore) GetUserGroups(userID string) ([]Group, error) {
 	for rows.Next() {
 		var g Group
 		if err := rows.Scan(&g.ID, &g.TenantID, &g.Name, &g.Type, &g.Description, &g.Syllabus, &g.Subject,
-			&g.Cadence, &g.JoinCode, &g.CreatedBy, &g.CreatedAt, &g.UpdatedAt, &g.MemberCount, &g.Closed); err != nil {
+			&g.Cadence, &g.JoinCode, &g.CreatedBy, &g.CreatedAt, &g.UpdatedAt, &g.MemberCount, &g.Closed, &g.QuizLeaderboard); err != nil {
 			return nil, fmt.Errorf("scan user group: %w", err)
 		}
 		groups = append(groups, g)
@@ -279,7 +286,7 @@ func (s *PostgresGroupStore) ListGroups(tenantID, groupType string) ([]Group, er
 		SELECT g.id::text, g.tenant_id::text, g.name, g.type, g.description, g.syllabus, g.subject, g.cadence,
 		       g.join_code, COALESCE(g.created_by::text, ''), g.created_at, g.updated_at,
 		       COUNT(gm.id)::int,
-		       g.closed
+		       g.closed, g.quiz_leaderboard
 		FROM groups g
 		LEFT JOIN group_members gm ON gm.group_id = g.id
 		WHERE g.tenant_id = $1::uuid`
@@ -301,7 +308,7 @@ func (s *PostgresGroupStore) ListGroups(tenantID, groupType string) ([]Group, er
 	for rows.Next() {
 		var g Group
 		if err := rows.Scan(&g.ID, &g.TenantID, &g.Name, &g.Type, &g.Description, &g.Syllabus, &g.Subject,
-			&g.Cadence, &g.JoinCode, &g.CreatedBy, &g.CreatedAt, &g.UpdatedAt, &g.MemberCount, &g.Closed); err != nil {
+			&g.Cadence, &g.JoinCode, &g.CreatedBy, &g.CreatedAt, &g.UpdatedAt, &g.MemberCount, &g.Closed, &g.QuizLeaderboard); err != nil {
 			return nil, fmt.Errorf("scan group: %w", err)
 		}
 		groups = append(groups, g)
@@ -314,7 +321,7 @@ func (s *PostgresGroupStore) GetGroupMembersWithChannel(groupID string) ([]Group
 	defer cancel()
 
 	rows, err := s.pool.Query(ctx, `
-		SELECT u.external_id, u.channel, u.name
+		SELECT u.external_id, u.channel, u.name, gm.role
 		FROM group_members gm
 		JOIN users u ON u.id = gm.user_id
 		WHERE gm.group_id = $1::uuid
@@ -330,7 +337,7 @@ func (s *PostgresGroupStore) GetGroupMembersWithChannel(groupID string) ([]Group
 	var members []GroupMemberDelivery
 	for rows.Next() {
 		var m GroupMemberDelivery
-		if err := rows.Scan(&m.ExternalID, &m.Channel, &m.UserName); err != nil {
+		if err := rows.Scan(&m.ExternalID, &m.Channel, &m.UserName, &m.Role); err != nil {
 			return nil, fmt.Errorf("scan group member delivery: %w", err)
 		}
 		members = append(members, m)
@@ -370,7 +377,9 @@ func buildWeeklyLeaderboardQuery(groupID string, limit int) (string, []any) {
 			SELECT gm.user_id, gm.tenant_id
 			FROM group_members gm
 			JOIN users u ON u.id = gm.user_id AND u.role = 'student'
+			LEFT JOIN leaderboard_preferences pref ON pref.user_id = gm.user_id
 			WHERE gm.group_id = $1::uuid
+			  AND NOT COALESCE(pref.opted_out, false)
 		),
 		current_scores AS (
 			SELECT m.user_id, lp.topic_id, lp.mastery_score
@@ -401,14 +410,117 @@ func buildWeeklyLeaderboardQuery(groupID string, limit int) (string, []any) {
 			LEFT JOIN baseline_scores bs ON bs.user_id = cs.user_id AND bs.topic_id = cs.topic_id
 			GROUP BY cs.user_id
 		)
-		SELECT g.user_id::text, u.name, g.avg_gain,
+		SELECT g.user_id::text, COALESCE(NULLIF(pref.nickname, ''), u.name), g.avg_gain,
 		       ROW_NUMBER() OVER (ORDER BY g.avg_gain DESC) AS rank
 		FROM gains g
 		JOIN users u ON u.id = g.user_id
+		LEFT JOIN leaderboard_preferences pref ON pref.user_id = g.user_id
 		ORDER BY g.avg_gain DESC
 		LIMIT $2`, []any{groupID, limit}
 }
 
+func (s *PostgresGroupStore) GetWeeklyQuizLeaderboard(groupID string, limit int) ([]QuizLeaderboardEntry, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), dbTimeout)
+	defer cancel()
+
+	if limit <= 0 {
+		limit = 10
+	}
+
+	query, args := buildWeeklyQuizLeaderboardQuery(groupID, limit)
+	rows, err := s.pool.Query(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("query weekly quiz leaderboard: %w", err)
+	}
+	defer rows.Close()
+
+	entries := make([]QuizLeaderboardEntry, 0, limit)
+	for rows.Next() {
+		var e QuizLeaderboardEntry
+		if err := rows.Scan(&e.UserID, &e.DisplayName, &e.QuizXP, &e.Accuracy, &e.Quizzes, &e.Rank); err != nil {
+			return nil, fmt.Errorf("scan quiz leaderboard entry: %w", err)
+		}
+		entries = append(entries, e)
+	}
+	return entries, rows.Err()
+}
+
+// buildWeeklyQuizLeaderboardQuery ranks a group's students by the XP their
+// correct quiz answers earned over the last 7 days, breaking ties on
+// accuracy. Students who opted out are left off entirely.
+func buildWeeklyQuizLeaderboardQuery(groupID string, limit int) (string, []any) {
+	return `
+		WITH members AS (
+			SELECT gm.user_id, gm.tenant_id,
+			       COALESCE(NULLIF(pref.nickname, ''), u.name) AS display_name
+			FROM group_members gm
+			JOIN users u ON u.id = gm.user_id AND u.role = 'student'
+			LEFT JOIN leaderboard_preferences pref ON pref.user_id = gm.user_id
+			WHERE gm.group_id = $1::uuid
+			  AND NOT COALESCE(pref.opted_out, false)
+		),
+		quizzes AS (
+			SELECT e.user_id,
+			       COUNT(*)::int AS quizzes,
+			       SUM(COALESCE((e.data->>'correct_answers')::int, 0))::int AS correct,
+			       SUM(COALESCE((e.data->>'total_questions')::int, 0))::int AS answered
+			FROM members m
+			JOIN events e
+			  ON e.user_id = m.user_id
+			 AND e.tenant_id = m.tenant_id
+			WHERE e.event_type = 'quiz_completed'
+			  AND e.created_at >= NOW() - INTERVAL '7 days'
+			GROUP BY e.user_id
+		),
+		scores AS (
+			SELECT m.user_id, m.display_name, q.quizzes,
+			       q.correct * $3::int AS quiz_xp,
+			       CASE WHEN q.answered > 0 THEN q.correct::float8 / q.answered ELSE 0 END AS accuracy
+			FROM members m
+			JOIN quizzes q ON q.user_id = m.user_id
+		)
+		SELECT user_id::text, display_name, quiz_xp, accuracy, quizzes,
+		       ROW_NUMBER() OVER (ORDER BY quiz_xp DESC, accuracy DESC) AS rank
+		FROM scores
+		ORDER BY rank
+		LIMIT $2`, []any{groupID, limit, progress.XPQuizCorrect}
+}
+
+func (s *PostgresGroupStore) GetLeaderboardPrivacy(userID string) (LeaderboardPrivacy, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), dbTimeout)
+	defer cancel()
+
+	var privacy LeaderboardPrivacy
+	err := s.pool.QueryRow(ctx,
+		`SELECT nickname, opted_out FROM leaderboard_preferences WHERE user_id = $1::uuid`,
+		userID,
+	).Scan(&privacy.Nickname, &privacy.OptedOut)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return LeaderboardPrivacy{}, nil
+	}
+	if err != nil {
+		return LeaderboardPrivacy{}, fmt.Errorf("get leaderboard privacy: %w", err)
+	}
+	return privacy, nil
+}
+
+func (s *PostgresGroupStore) SetLeaderboardPrivacy(userID, tenantID string, privacy LeaderboardPrivacy) error {
+	ctx, cancel := context.WithTimeout(context.Background(), dbTimeout)
+	defer cancel()
+
+	_, err := s.pool.Exec(ctx,
+		`INSERT INTO leaderboard_preferences (user_id, tenant_id, nickname, opted_out)
+		 VALUES ($1::uuid, $2::uuid, $3, $4)
+		 ON CONFLICT (user_id) DO UPDATE
+		 SET nickname = EXCLUDED.nickname, opted_out = EXCLUDED.opted_out, updated_at = NOW()`,
+		userID, tenantID, privacy.Nickname, privacy.OptedOut,
+	)
+	if err != nil {
+		return fmt.Errorf("set leaderboard privacy: %w", err)
+	}
+	return nil
+}
+
 const joinCodeAlphabet = "ABCDEFGHJKLMNPQRSTUVWXYZ23456789" // no I/O/0/1 to avoid confusion
 
 func generateJoinCode() (string, error) {
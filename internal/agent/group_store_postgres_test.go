@@ -6,6 +6,8 @@ package agent
 import (
 	"strings"
 	"testing"
+
+	"github.com/p-n-ai/pai-bot/internal/progress"
 )
 
 func TestBuildWeeklyLeaderboardQueryUsesJoinedMemberSet(t *testing.T) {
@@ -34,3 +36,26 @@ func TestBuildWeeklyLeaderboardQueryUsesJoinedMemberSet(t *testing.T) {
 		}
 	}
 }
+
+func TestBuildWeeklyQuizLeaderboardQuerySkipsOptedOutMembers(t *testing.T) {
+	query, args := buildWeeklyQuizLeaderboardQuery("33333333-3333-3333-3333-333333333333", 5)
+
+	if len(args) != 3 {
+		t.Fatalf("args len = %d, want 3", len(args))
+	}
+	if args[0] != "33333333-3333-3333-3333-333333333333" || args[1] != 5 || args[2] != progress.XPQuizCorrect {
+		t.Fatalf("args = %#v, want group id, limit and quiz XP", args)
+	}
+	for _, want := range []string{
+		"COALESCE(NULLIF(pref.nickname, ''), u.name) AS display_name",
+		"NOT COALESCE(pref.opted_out, false)",
+		"e.event_type = 'quiz_completed'",
+		"AND e.tenant_id = m.tenant_id",
+		"ORDER BY quiz_xp DESC, accuracy DESC",
+		"LIMIT $2",
+	} {
+		if !strings.Contains(query, want) {
+			t.Fatalf("query missing %q:\n%s", want, query)
+		}
+	}
+}
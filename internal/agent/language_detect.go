@@ -0,0 +1,122 @@
+// Copyright 2026 the P&AI authors. All rights reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package agent
+
+import (
+	"log/slog"
+	"strings"
+	"sync"
+	"unicode"
+
+	"github.com/p-n-ai/pai-bot/internal/chat"
+)
+
+const (
+	// languageSwitchStreak is how many consecutive messages in one language
+	// it takes to change the preference, so a single English question from a
+	// BM learner does not flip their whole profile.
+	languageSwitchStreak = 2
+	// minDetectableHan is the fewest Han characters that mark a message as
+	// Chinese; shorter snippets are usually names or copied symbols.
+	minDetectableHan = 2
+)
+
+// detectMessageLanguage guesses whether text is Bahasa Melayu, English, or
+// Chinese. It returns "" when the message is too short or mixed to tell.
+func detectMessageLanguage(text string) string {
+	var han, letters int
+	for _, r := range text {
+		switch {
+		case unicode.Is(unicode.Han, r):
+			han++
+		case unicode.IsLetter(r):
+			letters++
+		}
+	}
+	// Han characters carry a word each, so weigh them against Latin words
+	// rather than letters.
+	if han >= minDetectableHan && han*4 >= letters {
+		return "zh"
+	}
+	return detectLatestMessageLanguage(text)
+}
+
+type languageStreak struct {
+	lang  string
+	count int
+}
+
+// detectedLanguages tracks, per user, consecutive messages detected in a
+// language other than their stored preference.
+type detectedLanguages struct {
+	mu      sync.Mutex
+	streaks map[string]languageStreak
+}
+
+func newDetectedLanguages() *detectedLanguages {
+	return &detectedLanguages{streaks: make(map[string]languageStreak)}
+}
+
+// observe records a detected language and reports whether it has now been
+// seen often enough in a row to adopt.
+func (d *detectedLanguages) observe(userID, lang string) bool {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	streak := d.streaks[userID]
+	if streak.lang != lang {
+		streak = languageStreak{lang: lang}
+	}
+	streak.count++
+	if streak.count >= languageSwitchStreak {
+		delete(d.streaks, userID)
+		return true
+	}
+	d.streaks[userID] = streak
+	return false
+}
+
+func (d *detectedLanguages) reset(userID string) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	delete(d.streaks, userID)
+}
+
+// maybeAdoptDetectedLanguage updates the learner's preferred language once
+// they write several messages in a row in another language. The stored
+// preference then drives both the i18n catalog and the prompt instruction.
+func (e *Engine) maybeAdoptDetectedLanguage(msg chat.InboundMessage, conv *Conversation) {
+	if e.disableMultiLanguage || msg.UserID == "" {
+		return
+	}
+	lang := detectMessageLanguage(strings.TrimSpace(msg.Text))
+	if lang == "" {
+		return
+	}
+	current, hasPreference := e.store.GetUserPreferredLanguage(msg.UserID)
+	if hasPreference && current == lang {
+		e.detectedLanguages.reset(msg.UserID)
+		return
+	}
+	if !e.detectedLanguages.observe(msg.UserID, lang) {
+		return
+	}
+	if err := e.setUserPreferredLanguage(msg.UserID, lang); err != nil {
+		slog.Error("failed to persist detected language", "user_id", msg.UserID, "error", err)
+		return
+	}
+	conversationID := ""
+	if conv != nil {
+		conversationID = conv.ID
+	}
+	e.logEventAsync(Event{
+		ConversationID: conversationID,
+		UserID:         msg.UserID,
+		EventType:      "language_changed",
+		Data: map[string]any{
+			"preferred_language": lang,
+			"previous_language":  current,
+			"source":             "auto_detect",
+		},
+	})
+}
@@ -0,0 +1,39 @@
+// Copyright 2026 the P&AI authors. All rights reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package agent
+
+import "testing"
+
+func TestDetectMessageLanguage(t *testing.T) {
+	tests := []struct {
+		text string
+		want string
+	}{
+		{"how do I solve this equation?", "en"},
+		{"saya tak faham langkah pertama", "ms"},
+		{"这个方程怎么解？", "zh"},
+		{"x + 3 = 9 怎么做", "zh"},
+		{"ok", ""},
+		{"2x = 8", ""},
+		{"Ahmad 李", ""},
+	}
+	for _, tt := range tests {
+		if got := detectMessageLanguage(tt.text); got != tt.want {
+			t.Errorf("detectMessageLanguage(%q) = %q, want %q", tt.text, got, tt.want)
+		}
+	}
+}
+
+func TestDetectedLanguagesRequiresConsecutiveStreak(t *testing.T) {
+	d := newDetectedLanguages()
+	if d.observe("u", "en") {
+		t.Fatal("first detection should not adopt")
+	}
+	if d.observe("u", "zh") {
+		t.Fatal("a different language should restart the streak")
+	}
+	if !d.observe("u", "zh") {
+		t.Fatal("second consecutive detection should adopt")
+	}
+}
@@ -0,0 +1,58 @@
+// Copyright 2026 the P&AI authors. All rights reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package agent_test
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/p-n-ai/pai-bot/internal/agent"
+	"github.com/p-n-ai/pai-bot/internal/ai"
+	"github.com/p-n-ai/pai-bot/internal/chat"
+)
+
+func TestEngine_AdoptsDetectedLanguageAfterStreak(t *testing.T) {
+	mockAI := ai.NewMockProvider("answer")
+	store := agent.NewMemoryStore()
+	_ = store.SetUserPreferredLanguage("lang-user", "ms")
+	eventLogger := agent.NewMemoryEventLogger()
+	engine := agent.NewEngine(agent.EngineConfig{
+		AIRouter:    mockRouter(mockAI),
+		Store:       store,
+		EventLogger: eventLogger,
+	})
+	ctx := context.Background()
+	send := func(text string) {
+		t.Helper()
+		if _, err := engine.ProcessMessage(ctx, chat.InboundMessage{Channel: "telegram", UserID: "lang-user", Text: text}); err != nil {
+			t.Fatalf("ProcessMessage(%q) error = %v", text, err)
+		}
+	}
+
+	send("这个方程怎么解？")
+	if lang, _ := store.GetUserPreferredLanguage("lang-user"); lang != "ms" {
+		t.Fatalf("preferred language = %q after one message, want ms", lang)
+	}
+	if !strings.Contains(mockAI.LastRequest.Messages[0].Content, "Reply mainly in Chinese") {
+		t.Fatal("system prompt should follow the latest message's language")
+	}
+
+	send("为什么要两边减三？")
+	if lang, _ := store.GetUserPreferredLanguage("lang-user"); lang != "zh" {
+		t.Fatalf("preferred language = %q, want zh after two Chinese messages", lang)
+	}
+	if !strings.Contains(mockAI.LastRequest.Messages[0].Content, "Preferred language setting: Chinese") {
+		t.Fatal("system prompt should use the adopted preference")
+	}
+	event := waitForEvent(t, eventLogger, "language_changed")
+	if event.Data["source"] != "auto_detect" || event.Data["previous_language"] != "ms" {
+		t.Fatalf("event data = %#v, want auto_detect from ms", event.Data)
+	}
+
+	resp, _ := engine.ProcessMessage(ctx, chat.InboundMessage{Channel: "telegram", UserID: "lang-user", Text: "/more"})
+	if resp != "没有更多内容了。" {
+		t.Fatalf("/more = %q, want Chinese catalog message", resp)
+	}
+}
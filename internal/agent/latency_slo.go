@@ -0,0 +1,349 @@
+// Copyright 2026 the P&AI authors. All rights reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package agent
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	defaultSLOBurnRateAlert = 2.0
+	defaultSLOWindow        = time.Hour
+	// defaultSLOMinSamples keeps a few slow replies on a quiet channel from
+	// paging anyone.
+	defaultSLOMinSamples = 20
+	sloAlertTimeout      = 10 * time.Second
+	// sloOperatorChannel is where the operator alert chat lives.
+	sloOperatorChannel = "telegram"
+)
+
+// LatencySLO is a channel's response time objective: Objective of turns
+// answer within Target, e.g. 0.95 within 8s for a p95 of 8s.
+type LatencySLO struct {
+	Target    time.Duration
+	Objective float64
+}
+
+// LatencySLOs maps a channel name to its objective.
+type LatencySLOs map[string]LatencySLO
+
+// ParseLatencySLOs reads "channel=target_ms:objective" pairs separated by
+// commas, e.g. "telegram=8000:0.95,whatsapp=10000:0.9".
+func ParseLatencySLOs(raw string) (LatencySLOs, error) {
+	slos := LatencySLOs{}
+	for _, entry := range strings.Split(raw, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+		channel, values, ok := strings.Cut(entry, "=")
+		channel = strings.ToLower(strings.TrimSpace(channel))
+		target, objective, okValues := strings.Cut(values, ":")
+		if !ok || !okValues || channel == "" {
+			return nil, fmt.Errorf("invalid latency SLO entry %q: want channel=target_ms:objective", entry)
+		}
+		targetMS, err := strconv.Atoi(strings.TrimSpace(target))
+		if err != nil || targetMS <= 0 {
+			return nil, fmt.Errorf("invalid target in %q", entry)
+		}
+		ratio, err := strconv.ParseFloat(strings.TrimSpace(objective), 64)
+		if err != nil || ratio <= 0 || ratio >= 1 {
+			return nil, fmt.Errorf("invalid objective in %q: want a ratio between 0 and 1", entry)
+		}
+		slos[channel] = LatencySLO{Target: time.Duration(targetMS) * time.Millisecond, Objective: ratio}
+	}
+	return slos, nil
+}
+
+// SLOCompliance is how a channel is doing against its objective over the
+// monitor's window. BurnRate is the share of slow turns divided by the share
+// the objective allows; above 1 the error budget runs out early.
+type SLOCompliance struct {
+	Channel    string
+	Target     time.Duration
+	Objective  float64
+	Samples    int
+	Slow       int
+	Compliance float64
+	P95        time.Duration
+	BurnRate   float64
+	Window     time.Duration
+}
+
+// jsonView renders durations in milliseconds for webhooks and the admin API.
+func (c SLOCompliance) jsonView() map[string]any {
+	return map[string]any{
+		"channel":        c.Channel,
+		"target_ms":      c.Target.Milliseconds(),
+		"objective":      c.Objective,
+		"samples":        c.Samples,
+		"slow":           c.Slow,
+		"compliance":     c.Compliance,
+		"p95_ms":         c.P95.Milliseconds(),
+		"burn_rate":      c.BurnRate,
+		"window_minutes": c.Window.Minutes(),
+	}
+}
+
+// SLOAlertHook is told when a channel burns its error budget too fast.
+type SLOAlertHook interface {
+	AlertSLO(ctx context.Context, alert SLOCompliance) error
+}
+
+// LatencySLOMonitorConfig configures a LatencySLOMonitor. Zero values use
+// the defaults: a 2x burn rate over one hour with at least 20 turns.
+type LatencySLOMonitorConfig struct {
+	SLOs          LatencySLOs
+	BurnRateAlert float64
+	Window        time.Duration
+	MinSamples    int
+	Hooks         []SLOAlertHook
+}
+
+type latencySample struct {
+	at   time.Time
+	slow bool
+	took time.Duration
+}
+
+// LatencySLOMonitor keeps the response times of recent turns per channel and
+// alerts its hooks when a channel's burn rate crosses the threshold. A
+// channel alerts at most once per window.
+type LatencySLOMonitor struct {
+	slos          LatencySLOs
+	burnRateAlert float64
+	window        time.Duration
+	minSamples    int
+	now           func() time.Time
+
+	mu        sync.Mutex
+	hooks     []SLOAlertHook
+	samples   map[string][]latencySample
+	lastAlert map[string]time.Time
+}
+
+func NewLatencySLOMonitor(cfg LatencySLOMonitorConfig) *LatencySLOMonitor {
+	m := &LatencySLOMonitor{
+		slos:          cfg.SLOs,
+		burnRateAlert: cfg.BurnRateAlert,
+		window:        cfg.Window,
+		minSamples:    cfg.MinSamples,
+		now:           time.Now,
+		hooks:         append([]SLOAlertHook(nil), cfg.Hooks...),
+		samples:       make(map[string][]latencySample),
+		lastAlert:     make(map[string]time.Time),
+	}
+	if m.burnRateAlert <= 0 {
+		m.burnRateAlert = defaultSLOBurnRateAlert
+	}
+	if m.window <= 0 {
+		m.window = defaultSLOWindow
+	}
+	if m.minSamples <= 0 {
+		m.minSamples = defaultSLOMinSamples
+	}
+	return m
+}
+
+// AddHook registers another alert hook.
+func (m *LatencySLOMonitor) AddHook(hook SLOAlertHook) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.hooks = append(m.hooks, hook)
+}
+
+// Record adds one turn's response time. Channels without an objective are
+// ignored.
+func (m *LatencySLOMonitor) Record(channel string, took time.Duration) {
+	channel = strings.ToLower(channel)
+	slo, ok := m.slos[channel]
+	if !ok {
+		return
+	}
+	now := m.now()
+
+	m.mu.Lock()
+	samples := append(m.pruneLocked(channel, now), latencySample{at: now, slow: took > slo.Target, took: took})
+	m.samples[channel] = samples
+	report := complianceOf(channel, slo, samples, m.window)
+	var hooks []SLOAlertHook
+	if report.Samples >= m.minSamples && report.BurnRate >= m.burnRateAlert && now.Sub(m.lastAlert[channel]) >= m.window {
+		m.lastAlert[channel] = now
+		hooks = slices.Clone(m.hooks)
+	}
+	m.mu.Unlock()
+
+	if hooks == nil {
+		return
+	}
+	slog.Warn("latency SLO burn rate exceeded",
+		"channel", channel,
+		"burn_rate", report.BurnRate,
+		"compliance", report.Compliance,
+		"p95_ms", report.P95.Milliseconds(),
+		"samples", report.Samples,
+	)
+	go fireSLOAlert(hooks, report)
+}
+
+// Compliance reports every channel with an objective, sorted by name.
+func (m *LatencySLOMonitor) Compliance() []SLOCompliance {
+	now := m.now()
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	channels := make([]string, 0, len(m.slos))
+	for channel := range m.slos {
+		channels = append(channels, channel)
+	}
+	slices.Sort(channels)
+	reports := make([]SLOCompliance, 0, len(channels))
+	for _, channel := range channels {
+		reports = append(reports, complianceOf(channel, m.slos[channel], m.pruneLocked(channel, now), m.window))
+	}
+	return reports
+}
+
+// StatsHandler serves Compliance as JSON.
+func (m *LatencySLOMonitor) StatsHandler() http.Handler {
+	return http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+		reports := m.Compliance()
+		channels := make([]map[string]any, 0, len(reports))
+		for _, report := range reports {
+			channels = append(channels, report.jsonView())
+		}
+		rw.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(rw).Encode(map[string]any{
+			"burn_rate_alert": m.burnRateAlert,
+			"channels":        channels,
+		})
+	})
+}
+
+// pruneLocked drops samples older than the window.
+func (m *LatencySLOMonitor) pruneLocked(channel string, now time.Time) []latencySample {
+	samples := m.samples[channel]
+	cutoff := now.Add(-m.window)
+	drop := 0
+	for drop < len(samples) && samples[drop].at.Before(cutoff) {
+		drop++
+	}
+	if drop > 0 {
+		samples = slices.Delete(samples, 0, drop)
+		m.samples[channel] = samples
+	}
+	return samples
+}
+
+func complianceOf(channel string, slo LatencySLO, samples []latencySample, window time.Duration) SLOCompliance {
+	report := SLOCompliance{
+		Channel:    channel,
+		Target:     slo.Target,
+		Objective:  slo.Objective,
+		Samples:    len(samples),
+		Compliance: 1,
+		Window:     window,
+	}
+	if len(samples) == 0 {
+		return report
+	}
+	took := make([]time.Duration, len(samples))
+	for i, s := range samples {
+		took[i] = s.took
+		if s.slow {
+			report.Slow++
+		}
+	}
+	slices.Sort(took)
+	report.P95 = took[(len(took)*95+99)/100-1]
+	slowShare := float64(report.Slow) / float64(len(samples))
+	report.Compliance = 1 - slowShare
+	report.BurnRate = slowShare / (1 - slo.Objective)
+	return report
+}
+
+func fireSLOAlert(hooks []SLOAlertHook, report SLOCompliance) {
+	ctx, cancel := context.WithTimeout(context.Background(), sloAlertTimeout)
+	defer cancel()
+	for _, hook := range hooks {
+		if err := hook.AlertSLO(ctx, report); err != nil {
+			slog.Warn("latency SLO alert failed", "channel", report.Channel, "error", err)
+		}
+	}
+}
+
+// formatSLOAlert is the operator-facing alert text.
+func formatSLOAlert(alert SLOCompliance) string {
+	return fmt.Sprintf("Latency SLO alert: %s\n%.1f%% of %d turns in the last %s answered within %s (objective %.1f%%). p95 is %s; burn rate %.1fx.",
+		alert.Channel,
+		alert.Compliance*100,
+		alert.Samples,
+		alert.Window,
+		alert.Target,
+		alert.Objective*100,
+		alert.P95.Round(100*time.Millisecond),
+		alert.BurnRate,
+	)
+}
+
+// WebhookSLOAlertHook POSTs each alert as JSON to URL.
+type WebhookSLOAlertHook struct {
+	URL    string
+	Client *http.Client
+}
+
+func (h WebhookSLOAlertHook) AlertSLO(ctx context.Context, alert SLOCompliance) error {
+	payload := alert.jsonView()
+	payload["text"] = formatSLOAlert(alert)
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return fmt.Errorf("encode SLO alert: %w", err)
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, h.URL, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("build SLO alert request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	client := h.Client
+	if client == nil {
+		client = http.DefaultClient
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return fmt.Errorf("send SLO alert: %w", err)
+	}
+	_ = resp.Body.Close()
+	if resp.StatusCode >= 300 {
+		return fmt.Errorf("send SLO alert: webhook returned %s", resp.Status)
+	}
+	return nil
+}
+
+// operatorSLOAlertHook messages the operator chat through the engine's
+// notifier, which is installed after the engine is built.
+type operatorSLOAlertHook struct {
+	engine *Engine
+	chatID string
+}
+
+func (h operatorSLOAlertHook) AlertSLO(ctx context.Context, alert SLOCompliance) error {
+	h.engine.notifier.Notify(ctx, sloOperatorChannel, h.chatID, formatSLOAlert(alert))
+	return nil
+}
+
+// recordTurnLatency feeds a turn's response time to the latency SLO monitor.
+func (e *Engine) recordTurnLatency(channel string, took time.Duration) {
+	if e.latencySLO != nil {
+		e.latencySLO.Record(channel, took)
+	}
+}
@@ -0,0 +1,111 @@
+// Copyright 2026 the P&AI authors. All rights reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package agent
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+type recordingSLOHook struct {
+	alerts chan SLOCompliance
+}
+
+func (h recordingSLOHook) AlertSLO(_ context.Context, alert SLOCompliance) error {
+	h.alerts <- alert
+	return nil
+}
+
+func TestParseLatencySLOs(t *testing.T) {
+	slos, err := ParseLatencySLOs(" Telegram=8000:0.95, whatsapp=10000:0.9 ")
+	if err != nil {
+		t.Fatalf("ParseLatencySLOs() error = %v", err)
+	}
+	if got := slos["telegram"]; got.Target != 8*time.Second || got.Objective != 0.95 {
+		t.Errorf("telegram = %+v, want 8s at 0.95", got)
+	}
+	if got := slos["whatsapp"]; got.Target != 10*time.Second || got.Objective != 0.9 {
+		t.Errorf("whatsapp = %+v, want 10s at 0.9", got)
+	}
+	for _, raw := range []string{"telegram", "telegram=8000", "telegram=0:0.95", "telegram=8000:1", "telegram=8000:95"} {
+		if _, err := ParseLatencySLOs(raw); err == nil {
+			t.Errorf("ParseLatencySLOs(%q) error = nil, want error", raw)
+		}
+	}
+}
+
+func TestLatencySLOMonitorAlertsOncePerWindowWhenBurnRateIsHigh(t *testing.T) {
+	hook := recordingSLOHook{alerts: make(chan SLOCompliance, 4)}
+	monitor := NewLatencySLOMonitor(LatencySLOMonitorConfig{
+		SLOs:       LatencySLOs{"telegram": {Target: 8 * time.Second, Objective: 0.9}},
+		Window:     time.Hour,
+		MinSamples: 10,
+		Hooks:      []SLOAlertHook{hook},
+	})
+	now := time.Date(2026, 10, 17, 9, 0, 0, 0, time.UTC)
+	monitor.now = func() time.Time { return now }
+
+	// 8 fast and 2 slow turns: 80% compliance burns a 90% objective at 2x.
+	for i := 0; i < 8; i++ {
+		monitor.Record("telegram", time.Second)
+	}
+	monitor.Record("websocket", time.Minute) // no objective; ignored
+	monitor.Record("telegram", 12*time.Second)
+	select {
+	case alert := <-hook.alerts:
+		t.Fatalf("alerted below the minimum sample count: %+v", alert)
+	case <-time.After(20 * time.Millisecond):
+	}
+	monitor.Record("Telegram", 15*time.Second)
+
+	var alert SLOCompliance
+	select {
+	case alert = <-hook.alerts:
+	case <-time.After(time.Second):
+		t.Fatal("no alert after the burn rate crossed 2x")
+	}
+	if alert.Channel != "telegram" || alert.Samples != 10 || alert.Slow != 2 || alert.BurnRate < 1.99 || alert.P95 != 15*time.Second {
+		t.Fatalf("alert = %+v", alert)
+	}
+
+	monitor.Record("telegram", 20*time.Second)
+	select {
+	case again := <-hook.alerts:
+		t.Fatalf("alerted twice in one window: %+v", again)
+	case <-time.After(20 * time.Millisecond):
+	}
+
+	// An hour later the old turns have left the window.
+	now = now.Add(time.Hour + time.Minute)
+	reports := monitor.Compliance()
+	if len(reports) != 1 || reports[0].Samples != 0 || reports[0].Compliance != 1 {
+		t.Fatalf("Compliance() after the window = %+v, want no samples", reports)
+	}
+}
+
+func TestWebhookSLOAlertHookPostsJSON(t *testing.T) {
+	var got map[string]any
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost || r.Header.Get("Content-Type") != "application/json" {
+			t.Errorf("request = %s %s", r.Method, r.Header.Get("Content-Type"))
+		}
+		_ = json.NewDecoder(r.Body).Decode(&got)
+	}))
+	defer srv.Close()
+
+	err := WebhookSLOAlertHook{URL: srv.URL}.AlertSLO(context.Background(), SLOCompliance{
+		Channel: "telegram", Target: 8 * time.Second, Objective: 0.95, Samples: 40, Slow: 6,
+		Compliance: 0.85, P95: 11 * time.Second, BurnRate: 3, Window: time.Hour,
+	})
+	if err != nil {
+		t.Fatalf("AlertSLO() error = %v", err)
+	}
+	if got["channel"] != "telegram" || got["target_ms"] != float64(8000) || got["p95_ms"] != float64(11000) || got["burn_rate"] != float64(3) || got["text"] == "" {
+		t.Fatalf("webhook body = %v", got)
+	}
+}
@@ -0,0 +1,49 @@
+// Copyright 2026 the P&AI authors. All rights reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package agent_test
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/p-n-ai/pai-bot/internal/agent"
+	"github.com/p-n-ai/pai-bot/internal/ai"
+)
+
+func TestSlowTurnsAlertTheOperatorChat(t *testing.T) {
+	notifier := &capturingNotifier{}
+	monitor := agent.NewLatencySLOMonitor(agent.LatencySLOMonitorConfig{
+		SLOs:       agent.LatencySLOs{"telegram": {Target: time.Nanosecond, Objective: 0.95}},
+		MinSamples: 2,
+	})
+	engine := agent.NewEngine(agent.EngineConfig{
+		AIRouter:     mockRouter(ai.NewMockProvider("AI response")),
+		Store:        agent.NewMemoryStore(),
+		Notifier:     notifier,
+		LatencySLO:   monitor,
+		SLOAlertChat: "ops-chat",
+	})
+
+	sendAs(t, engine, "telegram", "aina", "what is a fraction?")
+	sendAs(t, engine, "websocket", "aina", "what is a decimal?")
+	sendAs(t, engine, "telegram", "aina", "and a percentage?")
+
+	deadline := time.Now().Add(time.Second)
+	for time.Now().Before(deadline) {
+		notifier.mu.Lock()
+		sent := append([]capturedNotification(nil), notifier.sent...)
+		notifier.mu.Unlock()
+		for _, n := range sent {
+			if n.userID == "ops-chat" {
+				if n.channel != "telegram" || !strings.Contains(n.text, "Latency SLO alert: telegram") || !strings.Contains(n.text, "of 2 turns") {
+					t.Fatalf("alert = %+v", n)
+				}
+				return
+			}
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+	t.Fatal("operator chat was not alerted")
+}
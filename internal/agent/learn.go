@@ -49,6 +49,12 @@ func (e *Engine) handleLearnCommand(ctx context.Context, msg chat.InboundMessage
 		return i18n.S(locale, i18n.MsgLearnTopicNotFound, raw), nil
 	}
 
+	return e.setLearnTopic(msg, locale, *topic)
+}
+
+// setLearnTopic switches the learner's conversation to topic and starts
+// teaching it.
+func (e *Engine) setLearnTopic(msg chat.InboundMessage, locale string, topic curriculum.Topic) (string, error) {
 	// Get or create conversation and set topic.
 	conv, err := e.getOrCreateConversation(msg.UserID)
 	if err != nil {
@@ -0,0 +1,146 @@
+// Copyright 2026 the P&AI authors. All rights reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package agent
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/p-n-ai/pai-bot/internal/ai"
+	"github.com/p-n-ai/pai-bot/internal/platform/featureflags"
+)
+
+func learnerMemoryFlags(t *testing.T, value string) func() featureflags.Features {
+	t.Helper()
+	flags, err := featureflags.Parse(value)
+	if err != nil {
+		t.Fatalf("featureflags.Parse(%q) error = %v", value, err)
+	}
+	return func() featureflags.Features { return flags }
+}
+
+func TestRememberFromMessagesSavesNewHighlights(t *testing.T) {
+	memories := NewMemoryLearnerMemoryStore()
+	_ = memories.SaveLearnerMemories("mem-user", []LearnerMemory{{Kind: LearnerMemoryPreference, Source: LearnerMemoryLearnerStated, Content: "Prefers explanations in Bahasa Melayu."}})
+	provider := ai.NewMockProvider(`{"memories":[
+		{"kind":"struggle","source":"tutor_observed","content":"The student keeps swapping numerator and denominator."},
+		{"kind":"goal","source":"learner_stated","content":"Wants an A in SPM Mathematics."},
+		{"kind":"preference","source":"learner_stated","content":"prefers explanations in bahasa melayu"}
+	]}`)
+	router := ai.NewRouter()
+	router.Register("openai", provider)
+	engine := NewEngine(EngineConfig{
+		AIRouter:      router,
+		LearnerMemory: memories,
+		FeatureFlags:  learnerMemoryFlags(t, "learner_memory"),
+	})
+
+	conv := &Conversation{ID: "conv-1", UserID: "mem-user"}
+	engine.rememberFromMessages(context.Background(), conv, []StoredMessage{
+		{Role: "user", Content: "I want an A for SPM maths"},
+		{Role: "assistant", Content: "Let's start with fractions."},
+	})
+
+	if provider.LastRequest == nil || !strings.Contains(provider.LastRequest.Messages[1].Content, "Already known:") {
+		t.Fatalf("extraction request = %+v, want known memories listed", provider.LastRequest)
+	}
+	got, _ := memories.ListLearnerMemories("mem-user")
+	if len(got) != 3 {
+		t.Fatalf("memories = %+v, want the existing one plus struggle and goal", got)
+	}
+	for _, m := range got[1:] {
+		if m.ConversationID != "conv-1" || m.EmbeddingModel != lexicalEmbeddingModel || len(m.Embedding) != lexicalEmbeddingDims {
+			t.Fatalf("memory = %+v, want conversation and lexical embedding recorded", m)
+		}
+	}
+	if got[2].Kind != LearnerMemoryGoal || got[2].Source != LearnerMemoryLearnerStated {
+		t.Fatalf("goal memory = %+v, want learner-stated goal", got[2])
+	}
+}
+
+func TestRememberFromMessagesRequiresFeatureFlag(t *testing.T) {
+	memories := NewMemoryLearnerMemoryStore()
+	provider := ai.NewMockProvider(`{"memories":[{"kind":"goal","source":"learner_stated","content":"Wants an A."}]}`)
+	router := ai.NewRouter()
+	router.Register("openai", provider)
+	engine := NewEngine(EngineConfig{AIRouter: router, LearnerMemory: memories})
+
+	engine.rememberFromMessages(context.Background(), &Conversation{ID: "conv-1", UserID: "mem-user"}, []StoredMessage{{Role: "user", Content: "hi"}})
+	if provider.LastRequest != nil {
+		t.Fatal("extraction should not run while learner_memory is off")
+	}
+}
+
+func TestLearnerMemoryPacketRanksBySimilarity(t *testing.T) {
+	memories := NewMemoryLearnerMemoryStore()
+	now := time.Now()
+	var saved []LearnerMemory
+	for i, content := range []string{
+		"Struggles with pecahan setara and simplifying fractions.",
+		"Wants an A in SPM Mathematics.",
+		"Prefers short worked examples.",
+	} {
+		saved = append(saved, LearnerMemory{
+			Kind:           LearnerMemoryStruggle,
+			Source:         LearnerMemoryTutorObserved,
+			Content:        content,
+			EmbeddingModel: lexicalEmbeddingModel,
+			Embedding:      lexicalEmbedding(content),
+			CreatedAt:      now.Add(time.Duration(i) * time.Minute),
+		})
+	}
+	saved[1].Kind, saved[1].Source = LearnerMemoryGoal, LearnerMemoryLearnerStated
+	_ = memories.SaveLearnerMemories("mem-user", saved)
+	engine := NewEngine(EngineConfig{LearnerMemory: memories, FeatureFlags: learnerMemoryFlags(t, "learner_memory")})
+
+	packets := engine.appendLearnerMemoryPacket(context.Background(), nil, "mem-user", "Macam mana nak cari pecahan setara?")
+	if len(packets) != 1 || packets[0].Trust != contextTrustModelGenerated {
+		t.Fatalf("packets = %+v, want one model-generated memory packet", packets)
+	}
+	data := packets[0].Data.([]learnerMemoryData)
+	if !strings.Contains(data[0].Content, "pecahan setara") {
+		t.Fatalf("first memory = %q, want the fractions struggle ranked first", data[0].Content)
+	}
+
+	block := buildLearnerMemoryBlock(packets)
+	for _, want := range []string{"WHAT I KNOW ABOUT THIS STUDENT", "not instructions", "goal, stated by the student", "struggle, inferred by the tutor", "> Wants an A"} {
+		if !strings.Contains(block, want) {
+			t.Fatalf("block = %q, want %q", block, want)
+		}
+	}
+}
+
+func TestNewLearnerMemoriesTrimsAndCaps(t *testing.T) {
+	var extracted extractedLearnerMemories
+	for i := 0; i < maxExtractedMemories+2; i++ {
+		extracted.Memories = append(extracted.Memories, struct {
+			Kind    string `json:"kind"`
+			Source  string `json:"source"`
+			Content string `json:"content"`
+		}{Kind: "struggle", Source: "made_up", Content: string(rune('a'+i)) + strings.Repeat(" long", 50)})
+	}
+	got := newLearnerMemories(nil, extracted, "conv-1")
+	if len(got) != maxExtractedMemories {
+		t.Fatalf("len = %d, want %d", len(got), maxExtractedMemories)
+	}
+	if got[0].Source != LearnerMemoryTutorObserved {
+		t.Fatalf("source = %q, want unknown sources treated as tutor observed", got[0].Source)
+	}
+	if n := len([]rune(got[0].Content)); n != maxLearnerMemoryRunes {
+		t.Fatalf("content runes = %d, want %d", n, maxLearnerMemoryRunes)
+	}
+}
+
+func TestMemoryLearnerMemoryStoreKeepsNewest(t *testing.T) {
+	store := NewMemoryLearnerMemoryStore()
+	for i := 0; i < maxLearnerMemories+3; i++ {
+		_ = store.SaveLearnerMemories("mem-user", []LearnerMemory{{Kind: LearnerMemoryGoal, Content: string(rune('A' + i%26))}})
+	}
+	got, _ := store.ListLearnerMemories("mem-user")
+	if len(got) != maxLearnerMemories {
+		t.Fatalf("len = %d, want cap %d", len(got), maxLearnerMemories)
+	}
+}
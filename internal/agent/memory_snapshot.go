@@ -0,0 +1,138 @@
+// Copyright 2026 the P&AI authors. All rights reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package agent
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// memoryStoreSnapshotVersion is bumped when the snapshot layout changes in a
+// way older files cannot be read into.
+const memoryStoreSnapshotVersion = 1
+
+type memoryStoreSnapshot struct {
+	Version       int                      `json:"version"`
+	SavedAt       time.Time                `json:"saved_at"`
+	Conversations map[string]*Conversation `json:"conversations"`
+	UserName      map[string]string        `json:"user_name,omitempty"`
+	UserForm      map[string]string        `json:"user_form,omitempty"`
+	UserLang      map[string]string        `json:"user_lang,omitempty"`
+	UserQuizLevel map[string]string        `json:"user_quiz_level,omitempty"`
+	UserABGroup   map[string]string        `json:"user_ab_group,omitempty"`
+}
+
+// SaveSnapshot writes the store's conversations and learner settings to path
+// as JSON. The file is replaced atomically and readable only by its owner,
+// since it holds learner messages.
+func (s *MemoryStore) SaveSnapshot(path string) error {
+	s.mu.RLock()
+	b, err := json.Marshal(memoryStoreSnapshot{
+		Version:       memoryStoreSnapshotVersion,
+		SavedAt:       time.Now(),
+		Conversations: s.conversations,
+		UserName:      s.userName,
+		UserForm:      s.userForm,
+		UserLang:      s.userLang,
+		UserQuizLevel: s.userQuizLevel,
+		UserABGroup:   s.userABGroup,
+	})
+	s.mu.RUnlock()
+	if err != nil {
+		return fmt.Errorf("encode memory store snapshot: %w", err)
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(path), filepath.Base(path)+".*.tmp")
+	if err != nil {
+		return fmt.Errorf("create memory store snapshot: %w", err)
+	}
+	defer func() { _ = os.Remove(tmp.Name()) }()
+	if _, err := tmp.Write(b); err != nil {
+		_ = tmp.Close()
+		return fmt.Errorf("write memory store snapshot: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("write memory store snapshot: %w", err)
+	}
+	if err := os.Chmod(tmp.Name(), 0o600); err != nil {
+		return fmt.Errorf("write memory store snapshot: %w", err)
+	}
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		return fmt.Errorf("replace memory store snapshot: %w", err)
+	}
+	return nil
+}
+
+// LoadSnapshot replaces the store's contents with a snapshot written by
+// SaveSnapshot. A missing file is not an error, so the first start with a
+// fresh path begins empty.
+func (s *MemoryStore) LoadSnapshot(path string) error {
+	b, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("read memory store snapshot: %w", err)
+	}
+	var snap memoryStoreSnapshot
+	if err := json.Unmarshal(b, &snap); err != nil {
+		return fmt.Errorf("decode memory store snapshot: %w", err)
+	}
+	if snap.Version != memoryStoreSnapshotVersion {
+		return fmt.Errorf("memory store snapshot version %d: want %d", snap.Version, memoryStoreSnapshotVersion)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.conversations = orEmpty(snap.Conversations)
+	for id, conv := range s.conversations {
+		if conv == nil {
+			delete(s.conversations, id)
+			continue
+		}
+		conv.ID = id
+		if conv.Messages == nil {
+			conv.Messages = []StoredMessage{}
+		}
+	}
+	s.userName = orEmpty(snap.UserName)
+	s.userForm = orEmpty(snap.UserForm)
+	s.userLang = orEmpty(snap.UserLang)
+	s.userQuizLevel = orEmpty(snap.UserQuizLevel)
+	s.userABGroup = orEmpty(snap.UserABGroup)
+	return nil
+}
+
+// RunSnapshots saves the store to path every interval until ctx is done, then
+// saves once more so a clean shutdown keeps the latest turns.
+func (s *MemoryStore) RunSnapshots(ctx context.Context, path string, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			if err := s.SaveSnapshot(path); err != nil {
+				slog.Error("final memory store snapshot failed", "path", path, "error", err)
+			}
+			return
+		case <-ticker.C:
+			if err := s.SaveSnapshot(path); err != nil {
+				slog.Warn("memory store snapshot failed", "path", path, "error", err)
+			}
+		}
+	}
+}
+
+func orEmpty[K comparable, V any](m map[K]V) map[K]V {
+	if m == nil {
+		return make(map[K]V)
+	}
+	return m
+}
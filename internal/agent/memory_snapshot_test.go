@@ -0,0 +1,79 @@
+// Copyright 2026 the P&AI authors. All rights reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package agent_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/p-n-ai/pai-bot/internal/agent"
+)
+
+func TestMemoryStoreSnapshotRoundTrip(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "memory.json")
+	store := agent.NewMemoryStore()
+	convID, err := store.CreateConversation(agent.Conversation{UserID: "u1", State: "teaching", TopicID: "F1-01"})
+	if err != nil {
+		t.Fatalf("CreateConversation() error = %v", err)
+	}
+	if _, err := store.AddMessage(convID, agent.StoredMessage{Role: "user", Content: "apa itu algebra"}); err != nil {
+		t.Fatalf("AddMessage() error = %v", err)
+	}
+	if err := store.SetUserPreferredLanguage("u1", "ms"); err != nil {
+		t.Fatalf("SetUserPreferredLanguage() error = %v", err)
+	}
+	if err := store.SetUserForm("u1", "2"); err != nil {
+		t.Fatalf("SetUserForm() error = %v", err)
+	}
+
+	if err := store.SaveSnapshot(path); err != nil {
+		t.Fatalf("SaveSnapshot() error = %v", err)
+	}
+	info, err := os.Stat(path)
+	if err != nil {
+		t.Fatalf("Stat() error = %v", err)
+	}
+	if perm := info.Mode().Perm(); perm != 0o600 {
+		t.Fatalf("snapshot permissions = %o, want 600", perm)
+	}
+
+	restored := agent.NewMemoryStore()
+	if err := restored.LoadSnapshot(path); err != nil {
+		t.Fatalf("LoadSnapshot() error = %v", err)
+	}
+	conv, err := restored.GetConversation(convID)
+	if err != nil {
+		t.Fatalf("GetConversation() error = %v", err)
+	}
+	if conv.TopicID != "F1-01" || len(conv.Messages) != 1 || conv.Messages[0].Content != "apa itu algebra" {
+		t.Fatalf("restored conversation = %+v", conv)
+	}
+	if lang, _ := restored.GetUserPreferredLanguage("u1"); lang != "ms" {
+		t.Fatalf("restored language = %q, want ms", lang)
+	}
+	if form, _ := restored.GetUserForm("u1"); form != "2" {
+		t.Fatalf("restored form = %q, want 2", form)
+	}
+}
+
+func TestMemoryStoreLoadSnapshotMissingFileStartsEmpty(t *testing.T) {
+	store := agent.NewMemoryStore()
+	if err := store.LoadSnapshot(filepath.Join(t.TempDir(), "missing.json")); err != nil {
+		t.Fatalf("LoadSnapshot() error = %v", err)
+	}
+	if _, found := store.GetActiveConversation("u1"); found {
+		t.Fatal("expected an empty store")
+	}
+}
+
+func TestMemoryStoreLoadSnapshotRejectsCorruptFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "memory.json")
+	if err := os.WriteFile(path, []byte("{not json"), 0o600); err != nil {
+		t.Fatalf("WriteFile() error = %v", err)
+	}
+	if err := agent.NewMemoryStore().LoadSnapshot(path); err == nil {
+		t.Fatal("LoadSnapshot() error = nil, want decode error")
+	}
+}
@@ -0,0 +1,160 @@
+// Copyright 2026 the P&AI authors. All rights reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package agent
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+)
+
+// MessageBatchWriter is implemented by stores that can append several
+// messages, plus analytics events for the same conversation, in one round
+// trip. Teaching turns use it to write the learner's message and the reply
+// together once the reply is ready.
+type MessageBatchWriter interface {
+	AddMessages(conversationID string, msgs []StoredMessage, events []Event) ([]string, error)
+}
+
+// AddMessages inserts msgs and events in one pgx batch. The batch runs as a
+// single implicit transaction, so either every row is written or none is.
+func (s *PostgresStore) AddMessages(conversationID string, msgs []StoredMessage, events []Event) ([]string, error) {
+	if len(msgs) == 0 && len(events) == 0 {
+		return nil, nil
+	}
+	batch := &pgx.Batch{}
+	for _, msg := range msgs {
+		args, err := insertMessageArgs(s.keys, s.tenantID, conversationID, msg)
+		if err != nil {
+			return nil, err
+		}
+		batch.Queue(insertMessageSQL, args...)
+	}
+	for _, event := range events {
+		if event.EventType == "" {
+			return nil, fmt.Errorf("event_type is required")
+		}
+		event.ConversationID = conversationID
+		args, err := insertEventArgs(event)
+		if err != nil {
+			return nil, err
+		}
+		batch.Queue(insertEventSQL, args...)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), dbTimeout)
+	defer cancel()
+	results := s.pool.SendBatch(ctx, batch)
+	defer func() { _ = results.Close() }()
+
+	ids := make([]string, 0, len(msgs))
+	for range msgs {
+		var id string
+		if err := results.QueryRow().Scan(&id); err != nil {
+			if errors.Is(err, pgx.ErrNoRows) {
+				return nil, fmt.Errorf("conversation not found: %s", conversationID)
+			}
+			return nil, fmt.Errorf("insert message: %w", err)
+		}
+		ids = append(ids, id)
+	}
+	for range events {
+		if _, err := results.Exec(); err != nil {
+			return nil, fmt.Errorf("insert event: %w", err)
+		}
+	}
+	if err := results.Close(); err != nil {
+		return nil, fmt.Errorf("commit message batch: %w", err)
+	}
+	return ids, nil
+}
+
+// pendingUserMessage is a learner message held back so it can be written in
+// the same batch as the reply.
+type pendingUserMessage struct {
+	conversationID string
+	msg            StoredMessage
+	event          Event
+	written        bool
+	// id is set once the message is written as part of a batch.
+	id string
+}
+
+// deferUserMessage returns the learner's message for batching when the store
+// supports it. Otherwise it is written now and nil is returned.
+func (e *Engine) deferUserMessage(conversationID string, msg StoredMessage, event Event) (*pendingUserMessage, string) {
+	if _, ok := e.store.(MessageBatchWriter); ok {
+		// Stamp arrival time so the row sorts before the reply.
+		msg.CreatedAt = time.Now()
+		return &pendingUserMessage{conversationID: conversationID, msg: msg, event: event}, ""
+	}
+	id, err := e.store.AddMessage(conversationID, msg)
+	if err != nil {
+		slog.Error("failed to store user message", "error", err)
+	}
+	e.logEventAsync(event)
+	return nil, id
+}
+
+// flush writes a pending message on its own, for turns that end without a
+// reply to batch it with.
+func (p *pendingUserMessage) flush(e *Engine) {
+	if p == nil || p.written {
+		return
+	}
+	p.written = true
+	if _, err := e.store.AddMessage(p.conversationID, p.msg); err != nil {
+		slog.Error("failed to store user message", "error", err)
+	}
+	e.logEventAsync(p.event)
+}
+
+// addReply stores the assistant reply, together with any pending learner
+// message and events, and returns the reply's ID. Events join the batch only
+// when the engine logs events to Postgres; otherwise they go to the event
+// logger as usual.
+func (e *Engine) addReply(conversationID string, pending *pendingUserMessage, reply StoredMessage, events ...Event) (string, error) {
+	writer, ok := e.store.(MessageBatchWriter)
+	if !ok || pending == nil || pending.written {
+		id, err := e.store.AddMessage(conversationID, reply)
+		for _, event := range events {
+			e.logEventAsync(event)
+		}
+		return id, err
+	}
+	pending.written = true
+
+	msgs := []StoredMessage{pending.msg, reply}
+	events = append([]Event{pending.event}, events...)
+	var batched []Event
+	if _, pgEvents := e.eventLogger.(*PostgresEventLogger); pgEvents {
+		for _, event := range events {
+			batched = append(batched, e.withABGroup(event))
+		}
+	}
+	ids, err := writer.AddMessages(conversationID, msgs, batched)
+	if err != nil {
+		// Nothing was written; fall back to one insert per row so the
+		// learner's message is not lost with the batch.
+		slog.Warn("message batch failed; writing individually", "conversation_id", conversationID, "error", err)
+		if pending.id, err = e.store.AddMessage(conversationID, pending.msg); err != nil {
+			slog.Error("failed to store user message", "error", err)
+		}
+		for _, event := range events {
+			e.logEventAsync(event)
+		}
+		return e.store.AddMessage(conversationID, reply)
+	}
+	if batched == nil {
+		for _, event := range events {
+			e.logEventAsync(event)
+		}
+	}
+	pending.id = ids[0]
+	return ids[1], nil
+}
@@ -0,0 +1,132 @@
+// Copyright 2026 the P&AI authors. All rights reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package agent_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/p-n-ai/pai-bot/internal/agent"
+	"github.com/p-n-ai/pai-bot/internal/ai"
+	"github.com/p-n-ai/pai-bot/internal/chat"
+)
+
+// batchStore records how a turn's messages reach the store.
+type batchStore struct {
+	*agent.MemoryStore
+	batchErr error
+	batches  [][]agent.StoredMessage
+	singles  []agent.StoredMessage
+}
+
+func newBatchStore() *batchStore {
+	return &batchStore{MemoryStore: agent.NewMemoryStore()}
+}
+
+// UserExists skips onboarding, which the engine only auto-starts for
+// non-memory stores.
+func (s *batchStore) UserExists(string) bool { return true }
+
+func (s *batchStore) AddMessage(conversationID string, msg agent.StoredMessage) (string, error) {
+	s.singles = append(s.singles, msg)
+	return s.MemoryStore.AddMessage(conversationID, msg)
+}
+
+func (s *batchStore) AddMessages(conversationID string, msgs []agent.StoredMessage, _ []agent.Event) ([]string, error) {
+	if s.batchErr != nil {
+		return nil, s.batchErr
+	}
+	s.batches = append(s.batches, msgs)
+	ids := make([]string, 0, len(msgs))
+	for _, msg := range msgs {
+		id, err := s.MemoryStore.AddMessage(conversationID, msg)
+		if err != nil {
+			return nil, err
+		}
+		ids = append(ids, id)
+	}
+	return ids, nil
+}
+
+func TestTeachingTurnBatchesUserAndReply(t *testing.T) {
+	const question = "Apa itu pecahan setara?"
+	mockAI := ai.NewMockProvider("Pecahan setara mewakili nilai yang sama.")
+	store := newBatchStore()
+	engine := agent.NewEngine(agent.EngineConfig{AIRouter: mockRouter(mockAI), Store: store})
+
+	if _, err := engine.ProcessMessage(context.Background(), chat.InboundMessage{
+		Channel: "telegram", UserID: "u1", Text: question, MessageID: "41",
+	}); err != nil {
+		t.Fatalf("ProcessMessage() error = %v", err)
+	}
+
+	if len(store.batches) != 1 || len(store.singles) != 0 {
+		t.Fatalf("batches = %d, single writes = %d; want one batch and no single writes", len(store.batches), len(store.singles))
+	}
+	if got := countMessagesContaining(mockAI.LastRequest.Messages, "user", question); got != 1 {
+		t.Errorf("current learner message count in prompt = %d, want 1", got)
+	}
+	conv, _ := store.GetActiveConversation("u1")
+	if len(conv.Messages) != 2 {
+		t.Fatalf("stored message count = %d, want 2", len(conv.Messages))
+	}
+	user, reply := conv.Messages[0], conv.Messages[1]
+	if user.Role != "user" || user.Content != question || user.ExternalID != "41" {
+		t.Errorf("stored learner message = %#v", user)
+	}
+	if reply.Role != "assistant" || reply.CreatedAt.Before(user.CreatedAt) {
+		t.Errorf("stored reply = %#v, want assistant written after the learner message", reply)
+	}
+}
+
+func TestTeachingTurnWritesUserMessageWhenModelFails(t *testing.T) {
+	store := newBatchStore()
+	engine := agent.NewEngine(agent.EngineConfig{
+		AIRouter: mockRouter(&flakyProvider{failuresBeforeSuccess: 100}),
+		Store:    store,
+	})
+
+	if _, err := engine.ProcessMessage(context.Background(), chat.InboundMessage{
+		Channel: "telegram", UserID: "u1", Text: "Terangkan indeks",
+	}); err != nil {
+		t.Fatalf("ProcessMessage() error = %v", err)
+	}
+
+	if len(store.batches) != 0 {
+		t.Errorf("batches = %d, want none without a reply", len(store.batches))
+	}
+	conv, _ := store.GetActiveConversation("u1")
+	if len(conv.Messages) != 1 || conv.Messages[0].Content != "Terangkan indeks" {
+		t.Fatalf("stored messages = %#v, want only the learner message", conv.Messages)
+	}
+}
+
+func TestTeachingTurnFallsBackWhenBatchFails(t *testing.T) {
+	store := newBatchStore()
+	store.batchErr = errors.New("connection reset")
+	events := agent.NewMemoryEventLogger()
+	engine := agent.NewEngine(agent.EngineConfig{
+		AIRouter:    mockRouter(ai.NewMockProvider("Jawapan")),
+		Store:       store,
+		EventLogger: events,
+	})
+
+	result, err := engine.ProcessTurn(context.Background(), chat.InboundMessage{
+		Channel: "telegram", UserID: "u1", Text: "Soalan",
+	})
+	if err != nil {
+		t.Fatalf("ProcessTurn() error = %v", err)
+	}
+
+	conv, _ := store.GetActiveConversation("u1")
+	if len(conv.Messages) != 2 || conv.Messages[0].Role != "user" || conv.Messages[1].Role != "assistant" {
+		t.Fatalf("stored messages = %#v, want learner message then reply", conv.Messages)
+	}
+	if result.AssistantMessageID != conv.Messages[1].ID {
+		t.Errorf("AssistantMessageID = %q, want %q", result.AssistantMessageID, conv.Messages[1].ID)
+	}
+	waitForEvent(t, events, "message_sent")
+	waitForEvent(t, events, "ai_response")
+}
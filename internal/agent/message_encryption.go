@@ -0,0 +1,172 @@
+// Copyright 2026 the P&AI authors. All rights reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package agent
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/jackc/pgx/v5"
+
+	"github.com/p-n-ai/pai-bot/internal/platform/msgcrypt"
+)
+
+// SetMessageKeyring turns on at-rest encryption: message content, edit
+// history and attachments written from now on are sealed with the active
+// key, and rows sealed with any key in keys can be read back. Rows written
+// before encryption was turned on stay readable as plaintext. A nil keyring
+// turns encryption off for new rows.
+func (s *PostgresStore) SetMessageKeyring(keys *msgcrypt.Keyring) {
+	s.keys = keys
+}
+
+// newMessageID returns a random UUID in the text form Postgres reads back, so
+// a message can be sealed for its ID before the row is written.
+func newMessageID() string {
+	b := make([]byte, 16)
+	_, _ = rand.Read(b)
+	b[6] = b[6]&0x0f | 0x40
+	b[8] = b[8]&0x3f | 0x80
+	return fmt.Sprintf("%x-%x-%x-%x-%x", b[0:4], b[4:6], b[6:8], b[8:10], b[10:])
+}
+
+// sealMessage seals a message's content and encoded attachments for the row
+// binding names. Sealed attachments are stored as a JSON string so the column
+// stays valid jsonb.
+func sealMessage(keys *msgcrypt.Keyring, binding []byte, content string, attachments []byte) (string, []byte, string, error) {
+	if keys == nil {
+		return content, attachments, "", nil
+	}
+	sealed, keyID, err := keys.Seal(content, binding)
+	if err != nil {
+		return "", nil, "", fmt.Errorf("encrypt message content: %w", err)
+	}
+	if len(attachments) == 0 {
+		return sealed, nil, keyID, nil
+	}
+	sealedAttachments, _, err := keys.Seal(string(attachments), binding)
+	if err != nil {
+		return "", nil, "", fmt.Errorf("encrypt message attachments: %w", err)
+	}
+	encoded, err := json.Marshal(sealedAttachments)
+	if err != nil {
+		return "", nil, "", fmt.Errorf("encode message attachments: %w", err)
+	}
+	return sealed, encoded, keyID, nil
+}
+
+// openMessage decrypts a scanned row of tenantID in place: msg's content and
+// original content, and attachments back to their plaintext JSON.
+func openMessage(keys *msgcrypt.Keyring, tenantID, keyID string, msg *StoredMessage, attachments *[]byte) error {
+	if keyID == "" {
+		return nil
+	}
+	binding := msgcrypt.Binding(tenantID, msg.ID)
+	content, err := keys.Open(keyID, msg.Content, binding)
+	if err != nil {
+		return fmt.Errorf("decrypt message %s: %w", msg.ID, err)
+	}
+	msg.Content = content
+	if msg.OriginalContent != "" {
+		original, err := keys.Open(keyID, msg.OriginalContent, binding)
+		if err != nil {
+			return fmt.Errorf("decrypt message %s: %w", msg.ID, err)
+		}
+		msg.OriginalContent = original
+	}
+	if len(*attachments) == 0 {
+		return nil
+	}
+	plain, err := openSealedJSON(keys, keyID, binding, *attachments)
+	if err != nil {
+		return fmt.Errorf("decrypt message %s attachments: %w", msg.ID, err)
+	}
+	*attachments = plain
+	return nil
+}
+
+// openSealedJSON opens a jsonb column holding a sealed JSON string.
+func openSealedJSON(keys *msgcrypt.Keyring, keyID string, binding, column []byte) ([]byte, error) {
+	var sealed string
+	if err := json.Unmarshal(column, &sealed); err != nil {
+		return nil, err
+	}
+	plain, err := keys.Open(keyID, sealed, binding)
+	if err != nil {
+		return nil, err
+	}
+	return []byte(plain), nil
+}
+
+// editSealedMessage is EditMessage for encrypted stores. The row is read and
+// resealed under the active key, so an edit also rotates the row's key.
+func (s *PostgresStore) editSealedMessage(ctx context.Context, conversationID, messageID, content string) error {
+	tx, err := s.pool.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("edit message: %w", err)
+	}
+	defer func() { _ = tx.Rollback(ctx) }()
+
+	msg := StoredMessage{ID: messageID}
+	var attachments []byte
+	var keyID, tenantID string
+	err = tx.QueryRow(ctx,
+		`SELECT content, COALESCE(original_content, ''), attachments, COALESCE(content_key_id, ''), tenant_id::text
+		 FROM messages
+		 WHERE id = $2::uuid
+		   AND conversation_id = $1::uuid
+		   AND deleted_at IS NULL
+		 FOR UPDATE`,
+		conversationID,
+		messageID,
+	).Scan(&msg.Content, &msg.OriginalContent, &attachments, &keyID, &tenantID)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return fmt.Errorf("message not found: %s", messageID)
+	}
+	if err != nil {
+		return fmt.Errorf("edit message: %w", err)
+	}
+	if err := openMessage(s.keys, tenantID, keyID, &msg, &attachments); err != nil {
+		return err
+	}
+	binding := msgcrypt.Binding(tenantID, messageID)
+	original := msg.OriginalContent
+	if original == "" {
+		original = msg.Content
+	}
+
+	sealedContent, sealedAttachments, newKeyID, err := sealMessage(s.keys, binding, content, attachments)
+	if err != nil {
+		return err
+	}
+	sealedOriginal, _, err := s.keys.Seal(original, binding)
+	if err != nil {
+		return fmt.Errorf("encrypt message content: %w", err)
+	}
+	if _, err := tx.Exec(ctx,
+		`UPDATE messages
+		 SET original_content = $3,
+		     content = $4,
+		     attachments = $5::jsonb,
+		     content_key_id = $6,
+		     edited_at = NOW()
+		 WHERE id = $2::uuid
+		   AND conversation_id = $1::uuid`,
+		conversationID,
+		messageID,
+		sealedOriginal,
+		sealedContent,
+		sealedAttachments,
+		newKeyID,
+	); err != nil {
+		return fmt.Errorf("edit message: %w", err)
+	}
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("edit message: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,101 @@
+// Copyright 2026 the P&AI authors. All rights reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+//go:build integration
+// +build integration
+
+package agent
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/p-n-ai/pai-bot/internal/platform/msgcrypt"
+)
+
+func TestPostgresStore_EncryptsMessagesAtRestAcrossKeyRotation(t *testing.T) {
+	ctx := context.Background()
+	pool, _ := startSchedulerPostgres(t, ctx)
+
+	store, err := NewPostgresStore(ctx, pool)
+	if err != nil {
+		t.Fatalf("NewPostgresStore() error = %v", err)
+	}
+	convID, err := store.CreateConversation(Conversation{UserID: "store-encrypted-user", State: "teaching"})
+	if err != nil {
+		t.Fatalf("CreateConversation() error = %v", err)
+	}
+	if _, err := store.AddMessage(convID, StoredMessage{Role: "user", Content: "written before encryption"}); err != nil {
+		t.Fatalf("AddMessage(plaintext) error = %v", err)
+	}
+
+	store.SetMessageKeyring(testKeyring(t, "k1=AQEBAQEBAQEBAQEBAQEBAQEBAQEBAQEBAQEBAQEBAQE=", ""))
+	msgID, err := store.AddMessage(convID, StoredMessage{
+		Role:        "user",
+		Content:     "my pecahan homework",
+		Kind:        MessageKindImage,
+		Attachments: []MessageAttachment{{Type: "image", Reference: "photo-ref", Caption: "page 12"}},
+	})
+	if err != nil {
+		t.Fatalf("AddMessage(sealed) error = %v", err)
+	}
+
+	var rawContent, rawAttachments, keyID string
+	if err := pool.QueryRow(ctx,
+		`SELECT content, attachments::text, content_key_id FROM messages WHERE id = $1::uuid`, msgID,
+	).Scan(&rawContent, &rawAttachments, &keyID); err != nil {
+		t.Fatalf("read raw row: %v", err)
+	}
+	if keyID != "k1" || strings.Contains(rawContent, "pecahan") || strings.Contains(rawAttachments, "photo-ref") {
+		t.Fatalf("raw row = %q / %q under %q, want ciphertext under k1", rawContent, rawAttachments, keyID)
+	}
+
+	// Rotate: k2 seals new writes, k1 still opens old rows.
+	store.SetMessageKeyring(testKeyring(t, "k1=AQEBAQEBAQEBAQEBAQEBAQEBAQEBAQEBAQEBAQEBAQE=,k2=AgICAgICAgICAgICAgICAgICAgICAgICAgICAgICAgI=", "k2"))
+	conv, err := store.GetConversation(convID)
+	if err != nil {
+		t.Fatalf("GetConversation() error = %v", err)
+	}
+	if len(conv.Messages) != 2 || conv.Messages[0].Content != "written before encryption" || conv.Messages[1].Content != "my pecahan homework" {
+		t.Fatalf("messages = %+v, want both decrypted", conv.Messages)
+	}
+	if got := conv.Messages[1].Attachments; len(got) != 1 || got[0].Reference != "photo-ref" {
+		t.Fatalf("attachments = %+v, want the photo reference", got)
+	}
+
+	if err := store.EditMessage(convID, msgID, "my pecahan homework, page 12"); err != nil {
+		t.Fatalf("EditMessage() error = %v", err)
+	}
+	if err := pool.QueryRow(ctx, `SELECT content_key_id FROM messages WHERE id = $1::uuid`, msgID).Scan(&keyID); err != nil || keyID != "k2" {
+		t.Fatalf("edited row key = %q, %v; want k2", keyID, err)
+	}
+	results, err := store.SearchMessages("store-encrypted-user", "pecahan", time.Time{}, 5)
+	if err != nil || len(results) != 1 || results[0].Content != "my pecahan homework, page 12" {
+		t.Fatalf("SearchMessages() = %+v, %v; want the edited message", results, err)
+	}
+
+	if err := store.EndConversation(convID); err != nil {
+		t.Fatalf("EndConversation() error = %v", err)
+	}
+	if _, err := store.ArchiveEndedConversations(ctx, time.Now().Add(time.Minute), 10); err != nil {
+		t.Fatalf("ArchiveEndedConversations() error = %v", err)
+	}
+	conv, err = store.GetConversation(convID)
+	if err != nil {
+		t.Fatalf("GetConversation(archived) error = %v", err)
+	}
+	if len(conv.Messages) != 2 || conv.Messages[1].OriginalContent != "my pecahan homework" {
+		t.Fatalf("archived messages = %+v, want the edit history restored", conv.Messages)
+	}
+}
+
+func testKeyring(t *testing.T, raw, active string) *msgcrypt.Keyring {
+	t.Helper()
+	keys, err := msgcrypt.Parse(raw, active)
+	if err != nil {
+		t.Fatalf("msgcrypt.Parse() error = %v", err)
+	}
+	return keys
+}
@@ -0,0 +1,60 @@
+// Copyright 2026 the P&AI authors. All rights reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package agent
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/p-n-ai/pai-bot/internal/platform/msgcrypt"
+)
+
+func TestSealMessageRoundTripsContentAndAttachments(t *testing.T) {
+	keys, err := msgcrypt.Parse("k1=AQEBAQEBAQEBAQEBAQEBAQEBAQEBAQEBAQEBAQEBAQE=", "")
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+	attachments, _ := json.Marshal([]MessageAttachment{{Type: "image", Reference: "photo-ref"}})
+
+	id := newMessageID()
+	content, sealedAttachments, keyID, err := sealMessage(keys, msgcrypt.Binding("tenant-1", id), "nama saya Aina", attachments)
+	if err != nil {
+		t.Fatalf("sealMessage() error = %v", err)
+	}
+	if keyID != "k1" || strings.Contains(content, "Aina") || strings.Contains(string(sealedAttachments), "photo-ref") {
+		t.Fatalf("sealed = %q / %s under %q, want ciphertext", content, sealedAttachments, keyID)
+	}
+	if !json.Valid(sealedAttachments) {
+		t.Fatalf("sealed attachments %s are not valid JSON for the jsonb column", sealedAttachments)
+	}
+
+	moved := StoredMessage{ID: newMessageID(), Content: content}
+	movedAttachments := sealedAttachments
+	if err := openMessage(keys, "tenant-1", keyID, &moved, &movedAttachments); err == nil {
+		t.Fatal("openMessage() opened content copied into another message row")
+	}
+	msg := StoredMessage{ID: id, Content: content}
+	if err := openMessage(keys, "tenant-2", keyID, &msg, &sealedAttachments); err == nil {
+		t.Fatal("openMessage() opened content under another tenant")
+	}
+	if err := openMessage(keys, "tenant-1", keyID, &msg, &sealedAttachments); err != nil {
+		t.Fatalf("openMessage() error = %v", err)
+	}
+	if msg.Content != "nama saya Aina" || string(sealedAttachments) != string(attachments) {
+		t.Fatalf("opened = %q / %s", msg.Content, sealedAttachments)
+	}
+
+	plain, plainAttachments, keyID, err := sealMessage(nil, nil, "hello", attachments)
+	if err != nil || plain != "hello" || keyID != "" || string(plainAttachments) != string(attachments) {
+		t.Fatalf("sealMessage(nil keys) = %q, %s, %q, %v; want plaintext", plain, plainAttachments, keyID, err)
+	}
+}
+
+func TestNewMessageIDIsCanonicalUUID(t *testing.T) {
+	id := newMessageID()
+	if len(id) != 36 || id[8] != '-' || id[13] != '-' || id[14] != '4' || id[18] != '-' || id[23] != '-' || strings.ToLower(id) != id {
+		t.Fatalf("newMessageID() = %q, want a lowercase version 4 UUID as Postgres prints it", id)
+	}
+}
@@ -0,0 +1,121 @@
+// Copyright 2026 the P&AI authors. All rights reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package agent
+
+import (
+	"context"
+	"log/slog"
+	"strings"
+	"time"
+
+	"github.com/p-n-ai/pai-bot/internal/chat"
+)
+
+// applyMessageRevision mirrors a channel edit or delete onto the stored copy of
+// the learner's message. Only the active conversation is searched; edits to
+// messages already folded into the summary change history but not the summary.
+// An edit of the latest question inside the re-answer window returns a revised
+// answer; every other revision returns no reply.
+func (e *Engine) applyMessageRevision(ctx context.Context, msg chat.InboundMessage, result *TurnResult) (string, error) {
+	conv, ok := e.store.GetActiveConversation(msg.UserID)
+	if !ok {
+		return "", nil
+	}
+
+	for _, externalID := range msg.DeletedMessageIDs {
+		stored, _, found := findMessageByExternalID(conv, externalID)
+		if !found || !stored.Visible() {
+			continue
+		}
+		if err := e.store.DeleteMessage(conv.ID, stored.ID); err != nil {
+			slog.Warn("failed to apply message delete", "conversation_id", conv.ID, "message_id", stored.ID, "error", err)
+			continue
+		}
+		e.logEventAsync(Event{
+			ConversationID: conv.ID,
+			UserID:         msg.UserID,
+			EventType:      "message_deleted",
+			Data: map[string]any{
+				"channel":    msg.Channel,
+				"message_id": stored.ID,
+			},
+		})
+	}
+
+	if !msg.Edited {
+		return "", nil
+	}
+	content := strings.TrimSpace(msg.Text)
+	stored, index, found := findMessageByExternalID(conv, msg.MessageID)
+	if !found || !stored.Visible() || content == "" || stored.Content == content {
+		return "", nil
+	}
+	if err := e.store.EditMessage(conv.ID, stored.ID, content); err != nil {
+		slog.Warn("failed to apply message edit", "conversation_id", conv.ID, "message_id", stored.ID, "error", err)
+		return "", nil
+	}
+
+	reanswer := e.shouldReanswerEdit(conv, stored, index)
+	e.logEventAsync(Event{
+		ConversationID: conv.ID,
+		UserID:         msg.UserID,
+		EventType:      "message_edited",
+		Data: map[string]any{
+			"channel":    msg.Channel,
+			"message_id": stored.ID,
+			"reanswered": reanswer,
+		},
+	})
+	if !reanswer {
+		return "", nil
+	}
+
+	// The earlier answer replied to the old wording; drop it from history so
+	// the revised answer is not prompted with a stale exchange.
+	for _, later := range conv.Messages[index+1:] {
+		if later.Role == "assistant" && later.Visible() {
+			if err := e.store.DeleteMessage(conv.ID, later.ID); err != nil {
+				slog.Warn("failed to retire superseded answer", "conversation_id", conv.ID, "message_id", later.ID, "error", err)
+			}
+		}
+	}
+
+	revised := msg
+	revised.Edited = false
+	revised.Text = content
+	conv, err := e.store.GetConversation(conv.ID)
+	if err != nil {
+		return "", err
+	}
+	return e.runTeachingTurnFor(ctx, revised, conv, "", result, teachingTurnOptions{ExistingUserMessageID: stored.ID})
+}
+
+// shouldReanswerEdit allows a revised answer only for the newest learner
+// message in a plain teaching conversation, edited soon after it was sent.
+func (e *Engine) shouldReanswerEdit(conv *Conversation, stored StoredMessage, index int) bool {
+	if e.editReanswerWindow <= 0 || stored.Role != "user" || conv.State != conversationStateTeaching {
+		return false
+	}
+	if time.Since(stored.CreatedAt) > e.editReanswerWindow {
+		return false
+	}
+	for _, later := range conv.Messages[index+1:] {
+		if later.Role == "user" && later.Visible() {
+			return false
+		}
+	}
+	return true
+}
+
+func findMessageByExternalID(conv *Conversation, externalID string) (StoredMessage, int, bool) {
+	if conv == nil || externalID == "" {
+		return StoredMessage{}, -1, false
+	}
+	for i := len(conv.Messages) - 1; i >= 0; i-- {
+		if conv.Messages[i].ExternalID == externalID {
+			return conv.Messages[i], i, true
+		}
+	}
+	return StoredMessage{}, -1, false
+}
@@ -0,0 +1,267 @@
+// Copyright 2026 the P&AI authors. All rights reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package agent
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"sort"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/p-n-ai/pai-bot/internal/chat"
+	"github.com/p-n-ai/pai-bot/internal/i18n"
+	"github.com/p-n-ai/pai-bot/internal/platform/msgcrypt"
+	"github.com/p-n-ai/pai-bot/internal/platform/textsearch"
+)
+
+const (
+	// searchResultLimit keeps /search replies short enough for one chat bubble.
+	searchResultLimit = 5
+	// searchSnippetRunes is how much of a matching message a result shows.
+	searchSnippetRunes = 120
+	// searchRecentWindow is how far back "last week" style phrases look. Two
+	// weeks covers the previous calendar week from any day of this one.
+	searchRecentWindow = 14 * 24 * time.Hour
+	// sealedSearchScanLimit caps how many of a learner's latest messages an
+	// encrypted store decrypts per search, since the full-text index only
+	// sees ciphertext.
+	sealedSearchScanLimit = 1000
+)
+
+// searchRecentPhrases narrow /search to recent messages when a learner adds
+// them to the query.
+var searchRecentPhrases = []string{"last week", "minggu lepas", "minggu lalu", "上周", "上星期"}
+
+// MessageSearchResult is one message matching a history search.
+type MessageSearchResult struct {
+	ConversationID string
+	MessageID      string
+	Role           string
+	Content        string
+	CreatedAt      time.Time
+}
+
+// MessageSearcher is implemented by conversation stores that can search a
+// learner's own message history.
+type MessageSearcher interface {
+	SearchMessages(userID, query string, since time.Time, limit int) ([]MessageSearchResult, error)
+}
+
+// SearchMessages matches messages containing every query word, newest first.
+func (s *MemoryStore) SearchMessages(userID, query string, since time.Time, limit int) ([]MessageSearchResult, error) {
+	words := textsearch.Words(query)
+	if len(words) == 0 {
+		return nil, nil
+	}
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	var results []MessageSearchResult
+	for _, conv := range s.conversations {
+		if conv.UserID != userID {
+			continue
+		}
+		for _, m := range conv.Messages {
+			if !m.Visible() || m.CreatedAt.Before(since) || !textsearch.ContainsAll(m.Content, words) {
+				continue
+			}
+			results = append(results, MessageSearchResult{
+				ConversationID: conv.ID,
+				MessageID:      m.ID,
+				Role:           m.Role,
+				Content:        m.Content,
+				CreatedAt:      m.CreatedAt,
+			})
+		}
+	}
+	sort.SliceStable(results, func(i, j int) bool { return results[i].CreatedAt.After(results[j].CreatedAt) })
+	if limit > 0 && len(results) > limit {
+		results = results[:limit]
+	}
+	return results, nil
+}
+
+// SearchMessages uses the messages.search_vector index. Each query word
+// matches as a prefix so "pecahan" also finds "pecahannya".
+func (s *PostgresStore) SearchMessages(userID, query string, since time.Time, limit int) ([]MessageSearchResult, error) {
+	tsQuery := textsearch.PrefixTSQuery(query)
+	if tsQuery == "" {
+		return nil, nil
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), dbTimeout)
+	defer cancel()
+	if s.keys != nil {
+		return s.searchSealedMessages(ctx, userID, textsearch.Words(query), since, limit)
+	}
+
+	rows, err := s.pool.Query(ctx,
+		`SELECT m.conversation_id::text, m.id::text, m.role, m.content, m.created_at
+		 FROM messages m
+		 JOIN conversations c ON c.id = m.conversation_id
+		 JOIN users u ON u.id = c.user_id
+		 WHERE u.external_id = $1
+		   AND u.channel = $2
+		   AND c.tenant_id = $3::uuid
+		   AND m.deleted_at IS NULL
+		   AND m.created_at >= $4
+		   AND m.search_vector @@ to_tsquery('simple', $5)
+		 ORDER BY ts_rank(m.search_vector, to_tsquery('simple', $5)) DESC, m.created_at DESC
+		 LIMIT $6`,
+		userID, s.channel, s.tenantID, since, tsQuery, limit,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("search messages: %w", err)
+	}
+	defer rows.Close()
+
+	var results []MessageSearchResult
+	for rows.Next() {
+		var r MessageSearchResult
+		if err := rows.Scan(&r.ConversationID, &r.MessageID, &r.Role, &r.Content, &r.CreatedAt); err != nil {
+			return nil, fmt.Errorf("scan message search result: %w", err)
+		}
+		results = append(results, r)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterate message search results: %w", err)
+	}
+	return results, nil
+}
+
+// searchSealedMessages decrypts the learner's latest messages and matches
+// them the way MemoryStore does.
+func (s *PostgresStore) searchSealedMessages(ctx context.Context, userID string, words []string, since time.Time, limit int) ([]MessageSearchResult, error) {
+	rows, err := s.pool.Query(ctx,
+		`SELECT m.conversation_id::text, m.id::text, m.role, m.content, m.created_at, COALESCE(m.content_key_id, '')
+		 FROM messages m
+		 JOIN conversations c ON c.id = m.conversation_id
+		 JOIN users u ON u.id = c.user_id
+		 WHERE u.external_id = $1
+		   AND u.channel = $2
+		   AND c.tenant_id = $3::uuid
+		   AND m.deleted_at IS NULL
+		   AND m.created_at >= $4
+		 ORDER BY m.created_at DESC
+		 LIMIT $5`,
+		userID, s.channel, s.tenantID, since, sealedSearchScanLimit,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("search messages: %w", err)
+	}
+	defer rows.Close()
+
+	var results []MessageSearchResult
+	scanned := 0
+	for rows.Next() {
+		scanned++
+		var r MessageSearchResult
+		var keyID string
+		if err := rows.Scan(&r.ConversationID, &r.MessageID, &r.Role, &r.Content, &r.CreatedAt, &keyID); err != nil {
+			return nil, fmt.Errorf("scan message search result: %w", err)
+		}
+		if r.Content, err = s.keys.Open(keyID, r.Content, msgcrypt.Binding(s.tenantID, r.MessageID)); err != nil {
+			return nil, fmt.Errorf("decrypt message %s: %w", r.MessageID, err)
+		}
+		if !textsearch.ContainsAll(r.Content, words) {
+			continue
+		}
+		results = append(results, r)
+		if limit > 0 && len(results) == limit {
+			break
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterate message search results: %w", err)
+	}
+	if scanned == sealedSearchScanLimit && (limit <= 0 || len(results) < limit) {
+		slog.Warn("sealed message search hit its scan cap; older messages were not searched",
+			"user_id", userID, "scan_limit", sealedSearchScanLimit, "results", len(results))
+	}
+	return results, nil
+}
+
+// searchSnippet trims content to a window around the first query word.
+func searchSnippet(content string, words []string) string {
+	content = strings.Join(strings.Fields(content), " ")
+	runes := []rune(content)
+	if len(runes) <= searchSnippetRunes {
+		return content
+	}
+	start := 0
+	lower := strings.ToLower(content)
+	for _, w := range words {
+		if i := strings.Index(lower, w); i >= 0 {
+			start = utf8.RuneCountInString(lower[:i]) - searchSnippetRunes/4
+			break
+		}
+	}
+	start = max(0, min(start, len(runes)-searchSnippetRunes))
+	snippet := string(runes[start : start+searchSnippetRunes])
+	if start > 0 {
+		snippet = "…" + snippet
+	}
+	if start+searchSnippetRunes < len(runes) {
+		snippet += "…"
+	}
+	return snippet
+}
+
+// parseSearchQuery strips a recent-time phrase from a /search query and
+// returns the remaining words with the earliest message time to include.
+func parseSearchQuery(raw string, now time.Time) (string, time.Time) {
+	query := strings.ToLower(strings.TrimSpace(raw))
+	for _, phrase := range searchRecentPhrases {
+		if i := strings.Index(query, phrase); i >= 0 {
+			query = strings.TrimSpace(query[:i] + query[i+len(phrase):])
+			return query, now.Add(-searchRecentWindow)
+		}
+	}
+	return query, time.Time{}
+}
+
+// handleSearchCommand answers /search <words> from the learner's own history.
+func (e *Engine) handleSearchCommand(msg chat.InboundMessage, args []string) (string, error) {
+	locale := e.messageLocale(msg, nil)
+	searcher, ok := e.store.(MessageSearcher)
+	if !ok {
+		return i18n.S(locale, i18n.MsgSearchUnavailable), nil
+	}
+	query, since := parseSearchQuery(strings.Join(args, " "), time.Now())
+	words := textsearch.Words(query)
+	if len(words) == 0 {
+		return i18n.S(locale, i18n.MsgSearchUsage), nil
+	}
+
+	results, err := searcher.SearchMessages(msg.UserID, query, since, searchResultLimit)
+	if err != nil {
+		slog.Error("failed to search messages", "user_id", msg.UserID, "error", err)
+		return i18n.S(locale, i18n.MsgTechnicalIssue), nil
+	}
+	e.logEventAsync(Event{
+		UserID:    msg.UserID,
+		EventType: "history_searched",
+		Data: map[string]any{
+			"word_count":   len(words),
+			"result_count": len(results),
+			"recent_only":  !since.IsZero(),
+		},
+	})
+	if len(results) == 0 {
+		return i18n.S(locale, i18n.MsgSearchNoResults, query), nil
+	}
+
+	var b strings.Builder
+	b.WriteString(i18n.S(locale, i18n.MsgSearchResultsHeader, query))
+	for _, r := range results {
+		label := i18n.S(locale, i18n.MsgSearchRoleTutor)
+		if r.Role == "user" {
+			label = i18n.S(locale, i18n.MsgSearchRoleLearner)
+		}
+		fmt.Fprintf(&b, "\n\n%s · %s\n%s", r.CreatedAt.Format("2006-01-02"), label, searchSnippet(r.Content, words))
+	}
+	return b.String(), nil
+}
@@ -0,0 +1,37 @@
+// Copyright 2026 the P&AI authors. All rights reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package agent
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestParseSearchQuery(t *testing.T) {
+	now := time.Date(2026, 10, 17, 9, 0, 0, 0, time.UTC)
+
+	query, since := parseSearchQuery("Fractions LAST WEEK", now)
+	if query != "fractions" || !since.Equal(now.Add(-searchRecentWindow)) {
+		t.Fatalf("parseSearchQuery() = %q, %v, want fractions since two weeks ago", query, since)
+	}
+	query, since = parseSearchQuery("pecahan", now)
+	if query != "pecahan" || !since.IsZero() {
+		t.Fatalf("parseSearchQuery() = %q, %v, want no time bound", query, since)
+	}
+}
+
+func TestSearchSnippetCentersOnMatch(t *testing.T) {
+	content := strings.Repeat("lorem ", 40) + "pecahan setara" + strings.Repeat(" ipsum", 40)
+	snippet := searchSnippet(content, []string{"pecahan"})
+	if !strings.Contains(snippet, "pecahan setara") {
+		t.Fatalf("snippet = %q, want the matching words", snippet)
+	}
+	if !strings.HasPrefix(snippet, "…") || !strings.HasSuffix(snippet, "…") {
+		t.Fatalf("snippet = %q, want ellipses on both trimmed ends", snippet)
+	}
+	if short := searchSnippet("pecahan  4/8", []string{"pecahan"}); short != "pecahan 4/8" {
+		t.Fatalf("short snippet = %q, want whitespace-collapsed content", short)
+	}
+}
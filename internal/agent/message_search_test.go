@@ -0,0 +1,58 @@
+// Copyright 2026 the P&AI authors. All rights reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package agent_test
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/p-n-ai/pai-bot/internal/agent"
+	"github.com/p-n-ai/pai-bot/internal/chat"
+)
+
+func TestEngine_SearchCommandFindsOwnHistory(t *testing.T) {
+	store := agent.NewMemoryStore()
+	_ = store.SetUserForm("search-user", "1")
+	now := time.Now()
+	convID, _ := store.CreateConversation(agent.Conversation{UserID: "search-user", State: "teaching"})
+	_, _ = store.AddMessage(convID, agent.StoredMessage{Role: "user", Content: "Macam mana nak ringkaskan pecahan 4/8?", CreatedAt: now.Add(-30 * 24 * time.Hour)})
+	_, _ = store.AddMessage(convID, agent.StoredMessage{Role: "assistant", Content: "Bahagi pengangka dan penyebut dengan 4, jadi pecahan 4/8 = 1/2.", CreatedAt: now.Add(-2 * 24 * time.Hour)})
+	_, _ = store.AddMessage(convID, agent.StoredMessage{Role: "user", Content: "Apa itu kecerunan?", CreatedAt: now.Add(-24 * time.Hour)})
+	otherID, _ := store.CreateConversation(agent.Conversation{UserID: "other-user", State: "teaching"})
+	_, _ = store.AddMessage(otherID, agent.StoredMessage{Role: "user", Content: "pecahan setara", CreatedAt: now})
+
+	engine := agent.NewEngine(agent.EngineConfig{Store: store})
+	ctx := context.Background()
+
+	resp, err := engine.ProcessMessage(ctx, chat.InboundMessage{Channel: "telegram", UserID: "search-user", Text: "/search pecahan"})
+	if err != nil {
+		t.Fatalf("ProcessMessage(/search) error = %v", err)
+	}
+	if !strings.Contains(resp, "ringkaskan pecahan") || !strings.Contains(resp, "1/2") {
+		t.Fatalf("response = %q, want both matching messages", resp)
+	}
+	if strings.Contains(resp, "kecerunan") || strings.Contains(resp, "setara") {
+		t.Fatalf("response = %q, want only this learner's matching messages", resp)
+	}
+	if strings.Index(resp, "1/2") > strings.Index(resp, "ringkaskan") {
+		t.Fatalf("response = %q, want newest match first", resp)
+	}
+
+	resp, _ = engine.ProcessMessage(ctx, chat.InboundMessage{Channel: "telegram", UserID: "search-user", Text: "/search pecahan minggu lepas"})
+	if strings.Contains(resp, "ringkaskan") || !strings.Contains(resp, "1/2") {
+		t.Fatalf("recent search = %q, want only the message from this week", resp)
+	}
+
+	resp, _ = engine.ProcessMessage(ctx, chat.InboundMessage{Channel: "telegram", UserID: "search-user", Text: "/search trigonometri"})
+	if !strings.Contains(resp, "trigonometri") || strings.Contains(resp, "1/2") {
+		t.Fatalf("no-result search = %q, want no-results message naming the query", resp)
+	}
+
+	resp, _ = engine.ProcessMessage(ctx, chat.InboundMessage{Channel: "telegram", UserID: "search-user", Text: "/search"})
+	if !strings.Contains(resp, "/search") {
+		t.Fatalf("empty search = %q, want usage hint", resp)
+	}
+}
@@ -0,0 +1,81 @@
+// Copyright 2026 the P&AI authors. All rights reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package agent_test
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/p-n-ai/pai-bot/internal/agent"
+	"github.com/p-n-ai/pai-bot/internal/ai"
+	"github.com/p-n-ai/pai-bot/internal/chat"
+	"github.com/p-n-ai/pai-bot/internal/i18n"
+	"github.com/p-n-ai/pai-bot/internal/progress"
+)
+
+func TestEngine_StartCommand_UsesTenantWelcome(t *testing.T) {
+	templates := i18n.NewOverrides()
+	engine := agent.NewEngine(agent.EngineConfig{
+		AIRouter:             mockRouter(ai.NewMockProvider("")),
+		DisableMultiLanguage: true,
+		MessageTemplates:     templates,
+	})
+	start := func(userID string) string {
+		t.Helper()
+		resp, err := engine.ProcessMessage(context.Background(), chat.InboundMessage{
+			Channel:   "telegram",
+			UserID:    userID,
+			Text:      "/start",
+			FirstName: "Ali",
+			Language:  "en",
+		})
+		if err != nil {
+			t.Fatalf("ProcessMessage() error = %v", err)
+		}
+		return resp
+	}
+
+	if resp := start("u1"); !strings.HasPrefix(resp, "Hi Ali!") {
+		t.Fatalf("welcome without override = %q, want the built-in copy", resp)
+	}
+
+	// Saved through the admin API while the bot is running.
+	templates.Set(i18n.MsgStartOnboardingForm, "en", "Welcome to SMK Damansara, {{name}}! Which form are you in?")
+	if resp := chat.StripActionCodes(start("u2")); resp != "Welcome to SMK Damansara, Ali! Which form are you in?" {
+		t.Fatalf("welcome with override = %q, want the tenant copy", resp)
+	}
+}
+
+func TestScheduler_ReviewReminderUsesTenantTemplate(t *testing.T) {
+	tracker := progress.NewMemoryTracker()
+	_ = tracker.SetMastery("u1", "malaysia-kssm", "F1-02", 0.4)
+	mockCh := &chat.MockChannel{}
+	gw := chat.NewGateway()
+	gw.Register("telegram", mockCh)
+
+	loc, _ := time.LoadLocation("Asia/Kuala_Lumpur")
+	y, m, d := time.Now().In(loc).Date()
+	now := time.Date(y, m, d, 10, 0, 0, 0, loc)
+
+	templates := i18n.NewOverrides()
+	templates.Set(i18n.MsgReviewReminder, "ms", "Masa ulang kaji {{topic}} ({{mastery}})! Balas untuk mula.")
+	scheduler := agent.NewScheduler(
+		agent.SchedulerConfig{CheckInterval: time.Second, MaxNudgesPerDay: 3},
+		tracker, nil, nil, nil,
+		agent.NewMemoryNudgeTracker(), gw, nil, nil,
+	)
+	scheduler.SetMessageTemplates(templates)
+
+	if err := scheduler.CheckUserForNudge(context.Background(), "u1", now); err != nil {
+		t.Fatalf("CheckUserForNudge() error = %v", err)
+	}
+	if len(mockCh.SentMessages) != 1 {
+		t.Fatalf("nudges sent = %d, want 1", len(mockCh.SentMessages))
+	}
+	if got := mockCh.SentMessages[0].Text; got != "Masa ulang kaji F1-02 (40%)! Balas untuk mula." {
+		t.Fatalf("nudge = %q, want the tenant reminder", got)
+	}
+}
@@ -0,0 +1,69 @@
+// Copyright 2026 the P&AI authors. All rights reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package agent
+
+import (
+	"strings"
+
+	"github.com/p-n-ai/pai-bot/internal/curriculum"
+	"github.com/p-n-ai/pai-bot/internal/i18n"
+)
+
+// Tutor moves reported in the meta footer and on ai_response events.
+const (
+	TutorMoveHint         = "hint"
+	TutorMoveCheck        = "check"
+	TutorMoveFullSolution = "full_solution"
+)
+
+// classifyTutorMove reports what a teaching reply did for the learner's
+// request: worked the problem through, checked their attempt, or guided them
+// with a hint and left the answer to them.
+func classifyTutorMove(request, reply string) string {
+	if containsDetectableFinalAnswer(reply) && !latestRequestForbidsAnswerDump(request) {
+		return TutorMoveFullSolution
+	}
+	lower := strings.ToLower(request)
+	if containsMarker(lower, checkOnlyMarkers) || containsMarker(lower, checkRequestMarkers) {
+		return TutorMoveCheck
+	}
+	return TutorMoveHint
+}
+
+// SetTenantMetaFooter turns the meta footer on or off for tenantID. The
+// engine serves one tenant, so a change for any other tenant is ignored.
+func (e *Engine) SetTenantMetaFooter(tenantID string, enabled bool) {
+	if tenantID != e.tenantID {
+		return
+	}
+	e.metaFooter.Store(enabled)
+}
+
+// metaFooterFor returns the footer appended to a teaching reply when the
+// tenant has it on: the tutor's move and the topic practised. It is added to
+// the sent reply only, never to stored history, so the model does not learn
+// to write it and exports stay clean.
+func (e *Engine) metaFooterFor(locale, move string, topic *curriculum.Topic) string {
+	if !e.metaFooter.Load() {
+		return ""
+	}
+	var key i18n.Key
+	switch move {
+	case TutorMoveFullSolution:
+		key = i18n.MsgMetaFooterFullSolution
+	case TutorMoveCheck:
+		key = i18n.MsgMetaFooterCheck
+	default:
+		key = i18n.MsgMetaFooterHint
+	}
+	footer := i18n.S(locale, key)
+	if topic != nil && strings.TrimSpace(topic.Name) != "" {
+		objective := topic.Name
+		if ref := strings.TrimSpace(topic.OfficialRef); ref != "" {
+			objective = ref + " " + objective
+		}
+		footer += " · " + i18n.S(locale, i18n.MsgMetaFooterObjective, objective)
+	}
+	return "— " + footer
+}
@@ -0,0 +1,45 @@
+// Copyright 2026 the P&AI authors. All rights reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package agent_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/p-n-ai/pai-bot/internal/agent"
+)
+
+func TestEngine_MetaFooterReportsTheTutorMove(t *testing.T) {
+	store := agent.NewMemoryStore()
+	provider := &scriptedReplyProvider{responses: []string{
+		"Try dividing both sides by 2 first. What do you get?",
+		"Divide both sides by 2, so x = 2.",
+		"Try dividing both sides by 2 first. What do you get?",
+	}}
+	engine := agent.NewEngine(agent.EngineConfig{
+		AIRouter:   mockRouter(provider),
+		Store:      store,
+		MetaFooter: true,
+	})
+
+	got := sendAs(t, engine, "websocket", "42", "How do I solve 2x = 4? Hint only please")
+	if !strings.HasSuffix(got, "— ℹ️ Hint given, answer not revealed") {
+		t.Fatalf("reply = %q, want the hint footer", got)
+	}
+	conv, _ := store.GetActiveConversation("42")
+	if last := conv.Messages[len(conv.Messages)-1]; strings.Contains(last.Content, "ℹ️") {
+		t.Fatalf("stored reply = %q, want it without the footer", last.Content)
+	}
+
+	got = sendAs(t, engine, "websocket", "42", "Show the full solution for 2x = 4")
+	if !strings.HasSuffix(got, "— ℹ️ Full solution shown") {
+		t.Fatalf("reply = %q, want the full solution footer", got)
+	}
+
+	engine.SetTenantMetaFooter("", false)
+	got = sendAs(t, engine, "websocket", "42", "How do I solve 3x = 6?")
+	if strings.Contains(got, "ℹ️") {
+		t.Fatalf("reply = %q, want no footer once turned off", got)
+	}
+}
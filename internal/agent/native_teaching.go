@@ -11,6 +11,7 @@ import (
 	"github.com/p-n-ai/pai-bot/internal/agentcore"
 	"github.com/p-n-ai/pai-bot/internal/ai"
 	"github.com/p-n-ai/pai-bot/internal/llm"
+	"github.com/p-n-ai/pai-bot/internal/plot"
 )
 
 type teachingCompletion struct {
@@ -18,10 +19,28 @@ type teachingCompletion struct {
 	Model        string
 	InputTokens  int
 	OutputTokens int
+	CostUSD      float64
+	// Graph is the image a plot tool drew during the turn, if any.
+	Graph *plot.Graph
+	// Truncated reports that the reply hit the output token limit.
+	Truncated bool
+}
+
+// requestMetadata attributes an AI call to the tenant and a hashed learner ID
+// so provider-side abuse reports can be traced without sharing raw user IDs.
+// It also carries the learner's subscription tier for routing.
+func (e *Engine) requestMetadata(userID, conversationID, turnID string) ai.RequestMetadata {
+	return ai.RequestMetadata{
+		Tenant:         e.tenantID,
+		UserHash:       ai.HashUser(e.tenantID, userID),
+		ConversationID: conversationID,
+		TurnID:         turnID,
+		Tier:           string(e.subscriptionTier(userID)),
+	}
 }
 
 func (e *Engine) completeNativeTeachingTurn(ctx context.Context, turn *agentTurn, modelID string) (teachingCompletion, error) {
-	return e.completeNativeTeachingTurnWithTools(ctx, turn, modelID, e.teachingTools())
+	return e.completeNativeTeachingTurnWithTools(ctx, turn, modelID, e.teachingTools(turn.Channel))
 }
 
 func (e *Engine) completeNativeTeachingTurnWithTools(ctx context.Context, turn *agentTurn, modelID string, tools []agentcore.Tool) (teachingCompletion, error) {
@@ -29,10 +48,14 @@ func (e *Engine) completeNativeTeachingTurnWithTools(ctx context.Context, turn *
 	if err != nil {
 		return teachingCompletion{}, err
 	}
-	model := ai.NewNativeModel(e.aiRouter, ai.NativeModelConfig{Task: ai.TaskTeaching, Model: modelID})
+	model := ai.NewNativeModel(e.aiRouter, ai.NativeModelConfig{
+		Task:     ai.TaskTeaching,
+		Model:    modelID,
+		Metadata: e.requestMetadata(turn.UserID, turn.ConversationID, turn.ID),
+	})
 	result, err := agentcore.Run(ctx, model, nativeContext, tools, agentcore.Config{
 		MaxModelCalls:  agentcore.DefaultMaxModelCalls,
-		StreamOptions:  &llm.StreamOptions{MaxTokens: 1024},
+		StreamOptions:  &llm.StreamOptions{MaxTokens: turn.MaxTokens},
 		RunID:          turn.ID,
 		ConversationID: turn.ConversationID,
 	})
@@ -57,8 +80,11 @@ func (e *Engine) completeNativeTeachingTurnWithTools(ctx context.Context, turn *
 		}
 		completion.InputTokens += assistant.Usage.Input + assistant.Usage.CacheRead + assistant.Usage.CacheWrite
 		completion.OutputTokens += assistant.Usage.Output
+		completion.CostUSD += assistant.Usage.Cost.Total
 	}
 	completion.Content = content.String()
+	completion.Truncated = result.Final.StopReason == llm.StopReasonLength
+	completion.Graph = plottedGraph(tools)
 	completion.Model = result.Final.ResponseModel
 	if completion.Model == "" {
 		completion.Model = result.Final.Model
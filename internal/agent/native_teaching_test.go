@@ -143,7 +143,7 @@ func TestEngineAgentCoreRunsCurriculumToolAndPersistsOnlyFinalText(t *testing.T)
 	if len(provider.contexts) != 2 {
 		t.Fatalf("native calls = %d, want 2", len(provider.contexts))
 	}
-	if provider.contexts[0].SystemPrompt == "" || len(provider.contexts[0].Tools) != 1 {
+	if provider.contexts[0].SystemPrompt == "" || len(provider.contexts[0].Tools) != 3 {
 		t.Fatalf("initial native context = %#v", provider.contexts[0])
 	}
 	for _, message := range provider.contexts[0].Messages {
@@ -228,3 +228,27 @@ func TestEngineSerializesTeachingTurnsPerConversation(t *testing.T) {
 		t.Fatalf("provider max concurrency = %d calls = %d, want 1 and 2", provider.max, provider.calls)
 	}
 }
+
+func TestEngineAttributesTeachingRequestsWithoutRawUserID(t *testing.T) {
+	mock := ai.NewMockProvider("Let's start with fractions.")
+	engine := agent.NewEngine(agent.EngineConfig{
+		AIRouter: mockRouter(mock),
+		Store:    agent.NewMemoryStore(),
+		TenantID: "tenant-1",
+	})
+
+	result, err := engine.ProcessTurn(context.Background(), chat.InboundMessage{Channel: "telegram", UserID: "user-1", Text: "teach me fractions"})
+	if err != nil {
+		t.Fatalf("ProcessTurn() error = %v", err)
+	}
+	if mock.LastRequest == nil {
+		t.Fatal("expected a teaching request")
+	}
+	metadata := mock.LastRequest.RequestMetadata
+	if metadata.Tenant != "tenant-1" || metadata.UserHash != ai.HashUser("tenant-1", "user-1") {
+		t.Fatalf("metadata = %#v, want tenant and hashed user", metadata)
+	}
+	if metadata.ConversationID != result.ConversationID || metadata.TurnID == "" {
+		t.Fatalf("metadata = %#v, want conversation %q and a turn ID", metadata, result.ConversationID)
+	}
+}
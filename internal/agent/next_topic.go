@@ -0,0 +1,193 @@
+// Copyright 2026 the P&AI authors. All rights reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package agent
+
+import (
+	"cmp"
+	"log/slog"
+	"slices"
+	"time"
+
+	"github.com/p-n-ai/pai-bot/internal/chat"
+	"github.com/p-n-ai/pai-bot/internal/curriculum"
+	"github.com/p-n-ai/pai-bot/internal/i18n"
+	"github.com/p-n-ai/pai-bot/internal/progress"
+)
+
+// TopicRecommendationReason says why NextBest picked a topic.
+type TopicRecommendationReason string
+
+const (
+	// RecommendContinue is a started topic that is not yet mastered.
+	RecommendContinue TopicRecommendationReason = "continue"
+	// RecommendNext is the first untouched topic, in syllabus order, whose
+	// prerequisites are mastered.
+	RecommendNext TopicRecommendationReason = "next"
+	// RecommendPrerequisite is an unmastered prerequisite holding back the
+	// next topic in the syllabus.
+	RecommendPrerequisite TopicRecommendationReason = "prerequisite"
+)
+
+// TopicRecommendation is the topic a learner should study next.
+type TopicRecommendation struct {
+	Topic   curriculum.Topic
+	Reason  TopicRecommendationReason
+	Mastery float64
+	// Unlocks is the topic a RecommendPrerequisite pick is holding back.
+	Unlocks *curriculum.Topic
+}
+
+// NextBest recommends what userID should learn next from the topics for
+// their form. A started, unmastered topic comes first, most recently studied
+// first, so learners finish what they began. Otherwise it picks the first
+// untouched topic in syllabus order whose prerequisites are mastered, and
+// failing that the prerequisite blocking the earliest locked topic.
+func (e *Engine) NextBest(userID string) (TopicRecommendation, bool) {
+	if e.curriculumLoader == nil {
+		return TopicRecommendation{}, false
+	}
+	form, _ := e.store.GetUserForm(userID)
+	topics := e.studyPlanTopics(form)
+	e.sortTopicsBySyllabus(topics)
+
+	studied := make(map[string]progress.ProgressItem)
+	if e.tracker != nil {
+		items, err := e.tracker.GetAllProgress(userID)
+		if err != nil {
+			slog.Warn("failed to load progress for next topic", "user_id", userID, "error", err)
+		}
+		for _, item := range items {
+			studied[item.TopicID] = item
+		}
+	}
+
+	var (
+		continueWith *curriculum.Topic
+		lastStudied  time.Time
+		next         *curriculum.Topic
+		blocked      *curriculum.Topic
+		blockedBy    string
+	)
+	for i := range topics {
+		topic := &topics[i]
+		item := studied[topic.ID]
+		if progress.IsMastered(item.MasteryScore) {
+			continue
+		}
+		if unmet := e.unmetPrerequisites(*topic, studied); len(unmet) > 0 {
+			if blocked == nil {
+				blocked, blockedBy = topic, unmet[0]
+			}
+			continue
+		}
+		if item.MasteryScore > 0 {
+			if continueWith == nil || item.LastStudied.After(lastStudied) {
+				continueWith, lastStudied = topic, item.LastStudied
+			}
+			continue
+		}
+		if next == nil {
+			next = topic
+		}
+	}
+
+	switch {
+	case continueWith != nil:
+		return TopicRecommendation{Topic: *continueWith, Reason: RecommendContinue, Mastery: studied[continueWith.ID].MasteryScore}, true
+	case next != nil:
+		return TopicRecommendation{Topic: *next, Reason: RecommendNext}, true
+	case blocked != nil:
+		prereq, _ := e.curriculumLoader.GetTopic(blockedBy)
+		return TopicRecommendation{Topic: prereq, Reason: RecommendPrerequisite, Mastery: studied[blockedBy].MasteryScore, Unlocks: blocked}, true
+	}
+	return TopicRecommendation{}, false
+}
+
+// unmetPrerequisites lists the loaded required prerequisites of topic that
+// the learner has not yet mastered to the unlock threshold.
+func (e *Engine) unmetPrerequisites(topic curriculum.Topic, studied map[string]progress.ProgressItem) []string {
+	var unmet []string
+	for _, id := range topic.Prerequisites.Required {
+		if _, ok := e.curriculumLoader.GetTopic(id); !ok {
+			continue
+		}
+		if studied[id].MasteryScore < curriculum.UnlockMasteryThreshold {
+			unmet = append(unmet, id)
+		}
+	}
+	return unmet
+}
+
+// sortTopicsBySyllabus orders topics by form, then subject, then their
+// position in the subject's topic list. Topics a subject does not list sort
+// after the listed ones by ID.
+func (e *Engine) sortTopicsBySyllabus(topics []curriculum.Topic) {
+	type position struct {
+		form, subject string
+		index         int
+	}
+	positions := make(map[string]position, len(topics))
+	for _, topic := range topics {
+		subject, _ := e.curriculumLoader.GetSubject(topic.SubjectID)
+		index := slices.Index(subject.Topics, topic.ID)
+		if index < 0 {
+			index = len(subject.Topics)
+		}
+		positions[topic.ID] = position{form: inferTopicForm(topic, subject), subject: topic.SubjectID, index: index}
+	}
+	slices.SortStableFunc(topics, func(a, b curriculum.Topic) int {
+		pa, pb := positions[a.ID], positions[b.ID]
+		return cmp.Or(
+			cmp.Compare(pa.form, pb.form),
+			cmp.Compare(pa.subject, pb.subject),
+			cmp.Compare(pa.index, pb.index),
+			cmp.Compare(a.ID, b.ID),
+		)
+	})
+}
+
+func (e *Engine) handleNextCommand(msg chat.InboundMessage) (string, error) {
+	locale := e.messageLocale(msg, nil)
+	rec, ok := e.NextBest(msg.UserID)
+	if !ok {
+		return i18n.S(locale, i18n.MsgNextAllDone), nil
+	}
+	e.logEventAsync(Event{
+		UserID:    msg.UserID,
+		EventType: "next_topic_recommended",
+		Data: map[string]any{
+			"topic_id": rec.Topic.ID,
+			"reason":   string(rec.Reason),
+			"source":   "command",
+		},
+	})
+	switch rec.Reason {
+	case RecommendContinue:
+		return i18n.S(locale, i18n.MsgNextContinue, rec.Topic.Name, int(rec.Mastery*100), rec.Topic.Name), nil
+	case RecommendPrerequisite:
+		return i18n.S(locale, i18n.MsgNextPrerequisite, rec.Unlocks.Name, rec.Topic.Name, rec.Topic.Name), nil
+	default:
+		return i18n.S(locale, i18n.MsgNextStart, rec.Topic.Name, rec.Topic.Name), nil
+	}
+}
+
+// sessionEndNextTopic returns a one-line pointer to the next topic for the
+// close of a session, or "" when there is nothing new to suggest beyond the
+// topic just finished.
+func (e *Engine) sessionEndNextTopic(userID, locale, finishedTopicID string) string {
+	rec, ok := e.NextBest(userID)
+	if !ok || rec.Topic.ID == finishedTopicID {
+		return ""
+	}
+	e.logEventAsync(Event{
+		UserID:    userID,
+		EventType: "next_topic_recommended",
+		Data: map[string]any{
+			"topic_id": rec.Topic.ID,
+			"reason":   string(rec.Reason),
+			"source":   "session_end",
+		},
+	})
+	return i18n.S(locale, i18n.MsgNextSessionEnd, rec.Topic.Name)
+}
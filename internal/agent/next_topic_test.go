@@ -0,0 +1,144 @@
+// Copyright 2026 the P&AI authors. All rights reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package agent_test
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/p-n-ai/pai-bot/internal/agent"
+	"github.com/p-n-ai/pai-bot/internal/ai"
+	"github.com/p-n-ai/pai-bot/internal/curriculum"
+	"github.com/p-n-ai/pai-bot/internal/progress"
+)
+
+// createNextTopicLoader builds a Form 1 subject whose syllabus order
+// (F1-03, F1-01, F1-02) differs from ID order. F1-02 requires F1-01.
+func createNextTopicLoader(t *testing.T) *curriculum.Loader {
+	t.Helper()
+	dir := t.TempDir()
+	// No topic here has word-problem templates, so a quiz ends after its
+	// assessment questions.
+	files := map[string]string{
+		"subject.yaml": `id: algebra-f1
+name: Matematik Tingkatan 1
+grade_id: tingkatan-1
+topics: [F1-03, F1-01, F1-02]
+`,
+		"F1-01.yaml":        "id: F1-01\nname: Algebraic Expressions\nsubject_id: algebra-f1\nsyllabus_id: kssm-f1\n",
+		"F1-02.yaml":        "id: F1-02\nname: Simple Equations\nsubject_id: algebra-f1\nsyllabus_id: kssm-f1\nprerequisites:\n  required: [F1-01]\n",
+		"F1-02.teaching.md": "# Simple Equations\nBalance both sides of the equation.\n",
+		"F1-03.yaml":        "id: F1-03\nname: Integers\nsubject_id: algebra-f1\nsyllabus_id: kssm-f1\n",
+		"F1-02.assessments.yaml": `topic_id: F1-02
+questions:
+  - id: Q1
+    text: "Solve x + 2 = 6. Reply with the number only."
+    difficulty: easy
+    answer:
+      type: exact
+      value: "4"
+`,
+	}
+	for name, body := range files {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(body), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	loader, err := curriculum.NewLoader(dir)
+	if err != nil {
+		t.Fatalf("NewLoader() error = %v", err)
+	}
+	return loader
+}
+
+func TestNextBestFollowsSyllabusMasteryAndPrerequisites(t *testing.T) {
+	tests := []struct {
+		name       string
+		mastery    map[string]float64
+		wantTopic  string
+		wantReason agent.TopicRecommendationReason
+		wantReply  string
+	}{
+		{
+			name:       "new learner starts at the top of the syllabus",
+			wantTopic:  "F1-03",
+			wantReason: agent.RecommendNext,
+			wantReply:  "Next up: Integers.\nSend /learn Integers to start.",
+		},
+		{
+			name:       "started topic comes before untouched ones",
+			mastery:    map[string]float64{"F1-03": 0.9, "F1-01": 0.3},
+			wantTopic:  "F1-01",
+			wantReason: agent.RecommendContinue,
+			wantReply:  "Keep going with Algebraic Expressions — you are at 30% mastery.",
+		},
+		{
+			name:       "mastered prerequisite unlocks the next topic",
+			mastery:    map[string]float64{"F1-03": 0.9, "F1-01": 0.9},
+			wantTopic:  "F1-02",
+			wantReason: agent.RecommendNext,
+			wantReply:  "Next up: Simple Equations.",
+		},
+		{
+			name:       "prerequisite below the unlock bar is revisited",
+			mastery:    map[string]float64{"F1-03": 0.9, "F1-01": 0.77},
+			wantTopic:  "F1-01",
+			wantReason: agent.RecommendPrerequisite,
+			wantReply:  "Before Simple Equations, let's firm up Algebraic Expressions.",
+		},
+		{
+			name:      "everything mastered",
+			mastery:   map[string]float64{"F1-03": 0.9, "F1-01": 0.9, "F1-02": 0.9},
+			wantReply: "You have mastered every topic for your form!",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			store := agent.NewMemoryStore()
+			_ = store.SetUserForm("42", "1")
+			_ = store.SetUserPreferredLanguage("42", "en")
+			tracker := progress.NewMemoryTracker()
+			for topicID, score := range tt.mastery {
+				_ = tracker.UpdateMastery("42", "kssm-f1", topicID, score)
+			}
+			engine := agent.NewEngine(agent.EngineConfig{
+				AIRouter:         mockRouter(ai.NewMockProvider("unused")),
+				Store:            store,
+				CurriculumLoader: createNextTopicLoader(t),
+				Tracker:          tracker,
+			})
+
+			rec, ok := engine.NextBest("42")
+			if ok != (tt.wantTopic != "") || rec.Topic.ID != tt.wantTopic || rec.Reason != tt.wantReason {
+				t.Fatalf("NextBest() = %s/%s, %v; want %s/%s", rec.Topic.ID, rec.Reason, ok, tt.wantTopic, tt.wantReason)
+			}
+			if got := sendAs(t, engine, "telegram", "42", "/next"); !strings.Contains(got, tt.wantReply) {
+				t.Errorf("/next = %q, want %q", got, tt.wantReply)
+			}
+		})
+	}
+}
+
+func TestQuizCompletionSuggestsNextTopic(t *testing.T) {
+	store := agent.NewMemoryStore()
+	_ = store.SetUserForm("42", "1")
+	_ = store.SetUserPreferredLanguage("42", "en")
+	_ = store.SetUserPreferredQuizIntensity("42", "mixed")
+	tracker := progress.NewMemoryTracker()
+	_ = tracker.UpdateMastery("42", "kssm-f1", "F1-01", 0.2)
+	engine := agent.NewEngine(agent.EngineConfig{
+		AIRouter:         mockRouter(ai.NewMockProvider("unused")),
+		Store:            store,
+		CurriculumLoader: createNextTopicLoader(t),
+		Tracker:          tracker,
+	})
+
+	sendAs(t, engine, "telegram", "42", "quiz me on simple equations")
+	got := sendAs(t, engine, "telegram", "42", "4")
+	if !strings.Contains(got, "Quiz complete") || !strings.HasSuffix(got, "Next time, let's tackle Algebraic Expressions.") {
+		t.Fatalf("quiz completion = %q, want a pointer to the next topic", got)
+	}
+}
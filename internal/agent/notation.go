@@ -0,0 +1,154 @@
+// Copyright 2026 the P&AI authors. All rights reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package agent
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// NotationConfig selects which Malaysian notation conventions are enforced on
+// tutor answers. The zero value leaves answers untouched.
+type NotationConfig struct {
+	// Currency writes amounts as "RM12.50": no space, two decimal places.
+	Currency bool
+	// DecimalPoint rewrites decimal commas ("3,5 cm") to decimal points
+	// where a currency, unit or operator shows the number is a decimal.
+	DecimalPoint bool
+	// MalayTerms uses KSSM operation words (tambah, tolak, darab, bahagi) in
+	// Bahasa Melayu answers.
+	MalayTerms bool
+}
+
+// ParseNotationConfig parses a comma-separated list of rules: "currency",
+// "decimal", "terms", or "all". Empty or "none" disables every rule.
+func ParseNotationConfig(raw string) (NotationConfig, error) {
+	var cfg NotationConfig
+	for _, rule := range strings.Split(raw, ",") {
+		switch strings.ToLower(strings.TrimSpace(rule)) {
+		case "", "none":
+		case "all":
+			cfg = NotationConfig{Currency: true, DecimalPoint: true, MalayTerms: true}
+		case "currency":
+			cfg.Currency = true
+		case "decimal":
+			cfg.DecimalPoint = true
+		case "terms":
+			cfg.MalayTerms = true
+		default:
+			return NotationConfig{}, fmt.Errorf("unknown notation rule %q: want currency, decimal, terms, or all", strings.TrimSpace(rule))
+		}
+	}
+	return cfg, nil
+}
+
+// SetTenantNotation applies a tenant's notation rules, in ParseNotationConfig
+// form, to later answers. Empty rules restore the deployment default, so a
+// tenant turns every rule off with "none". The engine serves one tenant, so a
+// change for any other tenant is ignored.
+func (e *Engine) SetTenantNotation(tenantID, rules string) error {
+	if tenantID != e.tenantID {
+		return nil
+	}
+	cfg := e.notationDefault
+	if strings.TrimSpace(rules) != "" {
+		parsed, err := ParseNotationConfig(rules)
+		if err != nil {
+			return err
+		}
+		cfg = parsed
+	}
+	e.notation.Store(&cfg)
+	return nil
+}
+
+var (
+	currencyPattern     = regexp.MustCompile(`(?i)\b(?:RM|MYR)\s*(\d+(?:,\d{3})*)(?:\.(\d+))?\b`)
+	decimalCommaPattern = regexp.MustCompile(`(\d+),(\d{1,2})\b`)
+	// decimalBeforePattern and decimalAfterPattern match what may sit either
+	// side of "3,5" for it to read as a decimal rather than a list: a
+	// currency before it, a unit after it, or an arithmetic operator.
+	decimalBeforePattern = regexp.MustCompile(`(?i)(?:\b(?:RM|MYR)|[×÷*/+\-]|\bx)\s*$`)
+	decimalAfterPattern  = regexp.MustCompile(`(?i)^\s*(?:[%°×÷*/+\-]|(?:x|mm|cm|km|m|mg|kg|g|ml|l|s|min|saat|minit|jam|h)\b)`)
+	malayTimesPattern    = regexp.MustCompile(`(\d)\s+kali\s+(\d)`)
+	malayTermsReplacer   = strings.NewReplacer(
+		" divided by ", " bahagi ",
+		" multiplied by ", " darab ",
+		" times ", " darab ",
+		" minus ", " tolak ",
+		" plus ", " tambah ",
+	)
+)
+
+// localizeNotation applies the enabled conventions to an answer that has
+// already been through normalizeEquationFormatting.
+func localizeNotation(content, locale string, cfg NotationConfig) string {
+	if cfg.DecimalPoint {
+		content = replaceDecimalCommas(content)
+	}
+	if cfg.Currency {
+		content = currencyPattern.ReplaceAllStringFunc(content, formatRinggit)
+	}
+	if cfg.MalayTerms && locale == "ms" {
+		content = malayTermsReplacer.Replace(content)
+		content = malayTimesPattern.ReplaceAllString(content, "$1 darab $2")
+	}
+	return content
+}
+
+func formatRinggit(match string) string {
+	parts := currencyPattern.FindStringSubmatch(match)
+	amount, cents := parts[1], parts[2]
+	if cents == "" {
+		return "RM" + amount
+	}
+	// Pad "RM5.5" to "RM5.50"; longer precision is left alone rather than
+	// silently rounding money.
+	if len(cents) == 1 {
+		cents += "0"
+	}
+	return "RM" + amount + "." + cents
+}
+
+// replaceDecimalCommas rewrites "3,5 cm", "RM4,5" and "3 x 2,5" to decimal
+// points. A bare "3,5" could as well be the list "3 and 5" ("x = 1,2"), so
+// only a number with a currency, unit or arithmetic operator beside it is
+// rewritten; lists, coordinates "(3,5)" and thousands separators "1,250" are
+// left alone.
+func replaceDecimalCommas(content string) string {
+	matches := decimalCommaPattern.FindAllStringSubmatchIndex(content, -1)
+	if len(matches) == 0 {
+		return content
+	}
+	var b strings.Builder
+	last := 0
+	for _, m := range matches {
+		start, end := m[0], m[1]
+		if !decimalContext(content, start, end) || partOfNumberList(content, start, end) {
+			continue
+		}
+		b.WriteString(content[last:start])
+		b.WriteString(content[m[2]:m[3]])
+		b.WriteByte('.')
+		b.WriteString(content[m[4]:m[5]])
+		last = end
+	}
+	b.WriteString(content[last:])
+	return b.String()
+}
+
+func decimalContext(content string, start, end int) bool {
+	return decimalBeforePattern.MatchString(content[:start]) || decimalAfterPattern.MatchString(content[end:])
+}
+
+func partOfNumberList(content string, start, end int) bool {
+	before := strings.TrimRight(content[:start], " ")
+	after := strings.TrimLeft(content[end:], " ")
+	if strings.HasSuffix(before, "(") || strings.HasPrefix(after, ")") {
+		return true
+	}
+	nextIsDigit := len(after) > 1 && after[1] >= '0' && after[1] <= '9'
+	return strings.HasSuffix(before, ",") || (strings.HasPrefix(after, ",") && nextIsDigit)
+}
@@ -0,0 +1,55 @@
+// Copyright 2026 the P&AI authors. All rights reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package agent
+
+import "testing"
+
+func TestParseNotationConfig(t *testing.T) {
+	cfg, err := ParseNotationConfig(" currency, terms ")
+	if err != nil {
+		t.Fatalf("ParseNotationConfig() error = %v", err)
+	}
+	if cfg != (NotationConfig{Currency: true, MalayTerms: true}) {
+		t.Fatalf("cfg = %#v", cfg)
+	}
+	if cfg, _ := ParseNotationConfig("all"); !cfg.Currency || !cfg.DecimalPoint || !cfg.MalayTerms {
+		t.Fatalf("all = %#v, want every rule", cfg)
+	}
+	if cfg, err := ParseNotationConfig("none"); err != nil || cfg != (NotationConfig{}) {
+		t.Fatalf("none = %#v, %v; want every rule off", cfg, err)
+	}
+	if _, err := ParseNotationConfig("currency,fahrenheit"); err == nil {
+		t.Fatal("unknown rule should fail")
+	}
+}
+
+func TestLocalizeNotation(t *testing.T) {
+	all := NotationConfig{Currency: true, DecimalPoint: true, MalayTerms: true}
+	tests := []struct {
+		name   string
+		in     string
+		locale string
+		cfg    NotationConfig
+		want   string
+	}{
+		{"currency spacing and cents", "Tambang ialah RM 5.5 dan myr 1,250.", "ms", all, "Tambang ialah RM5.50 dan RM1,250."},
+		{"decimal comma", "Panjang sisi ialah 3,5 cm.", "ms", all, "Panjang sisi ialah 3.5 cm."},
+		{"keeps lists and coordinates", "Nombor 1,2,3 dan titik (3,5) serta 1,250 orang.", "en", all, "Nombor 1,2,3 dan titik (3,5) serta 1,250 orang."},
+		{"decimal comma in ringgit", "Harga RM4,5 sahaja", "ms", all, "Harga RM4.50 sahaja"},
+		{"decimal comma before a percent", "Kadar faedah ialah 3,75%.", "ms", all, "Kadar faedah ialah 3.75%."},
+		{"keeps two-item lists", "So x = 1,2 and the roots are 2,5 and 7.", "en", all, "So x = 1,2 and the roots are 2,5 and 7."},
+		{"keeps a list ending in a unit", "Sides of 1,2,3 cm", "en", all, "Sides of 1,2,3 cm"},
+		{"decimal comma beside an operator", "Luas = 4 x 2,5 = 10", "ms", all, "Luas = 4 x 2.5 = 10"},
+		{"malay terms", "Jadi 9 minus 3 ialah 6, dan 2 kali 3 ialah 6.", "ms", all, "Jadi 9 tolak 3 ialah 6, dan 2 darab 3 ialah 6."},
+		{"english answers keep english terms", "So 9 minus 3 is 6.", "en", all, "So 9 minus 3 is 6."},
+		{"disabled", "RM 5.5 and 3,5", "ms", NotationConfig{}, "RM 5.5 and 3,5"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := localizeNotation(tt.in, tt.locale, tt.cfg); got != tt.want {
+				t.Fatalf("localizeNotation(%q) = %q, want %q", tt.in, got, tt.want)
+			}
+		})
+	}
+}
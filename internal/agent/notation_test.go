@@ -0,0 +1,68 @@
+// Copyright 2026 the P&AI authors. All rights reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package agent_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/p-n-ai/pai-bot/internal/agent"
+	"github.com/p-n-ai/pai-bot/internal/ai"
+	"github.com/p-n-ai/pai-bot/internal/chat"
+)
+
+func TestEngine_AppliesNotationAfterEquationNormalizer(t *testing.T) {
+	engine := agent.NewEngine(agent.EngineConfig{
+		AIRouter: mockRouter(ai.NewMockProvider(`Jumlah kos ialah \(3 \times 2,5\) = RM 7.5`)),
+		Store:    agent.NewMemoryStore(),
+		Notation: agent.NotationConfig{Currency: true, DecimalPoint: true, MalayTerms: true},
+	})
+
+	resp, err := engine.ProcessMessage(context.Background(), chat.InboundMessage{Channel: "telegram", UserID: "notation-user", Text: "berapa kos teksi?", Language: "ms"})
+	if err != nil {
+		t.Fatalf("ProcessMessage() error = %v", err)
+	}
+	if resp != "Jumlah kos ialah 3 x 2.5 = RM7.50" {
+		t.Fatalf("response = %q, want localized plain notation", resp)
+	}
+}
+
+func TestEngine_TenantNotationOverridesTheDefault(t *testing.T) {
+	engine := agent.NewEngine(agent.EngineConfig{
+		AIRouter: mockRouter(ai.NewMockProvider(`Jumlah kos ialah RM 7.5`)),
+		Store:    agent.NewMemoryStore(),
+		TenantID: "tenant-1",
+		Notation: agent.NotationConfig{Currency: true},
+	})
+	ask := func() string {
+		t.Helper()
+		resp, err := engine.ProcessMessage(context.Background(), chat.InboundMessage{Channel: "telegram", UserID: "notation-user", Text: "berapa kos teksi?", Language: "ms"})
+		if err != nil {
+			t.Fatalf("ProcessMessage() error = %v", err)
+		}
+		return resp
+	}
+
+	if err := engine.SetTenantNotation("tenant-1", "none"); err != nil {
+		t.Fatalf("SetTenantNotation() error = %v", err)
+	}
+	if got := ask(); got != "Jumlah kos ialah RM 7.5" {
+		t.Fatalf("response = %q, want the tenant's rules off", got)
+	}
+	if err := engine.SetTenantNotation("tenant-2", "all"); err != nil {
+		t.Fatalf("SetTenantNotation() for another tenant error = %v", err)
+	}
+	if err := engine.SetTenantNotation("tenant-1", "fahrenheit"); err == nil {
+		t.Fatal("SetTenantNotation() accepted an unknown rule")
+	}
+	if got := ask(); got != "Jumlah kos ialah RM 7.5" {
+		t.Fatalf("response = %q, want other tenants and bad rules ignored", got)
+	}
+	if err := engine.SetTenantNotation("tenant-1", ""); err != nil {
+		t.Fatalf("SetTenantNotation() error = %v", err)
+	}
+	if got := ask(); got != "Jumlah kos ialah RM7.50" {
+		t.Fatalf("response = %q, want the deployment default back", got)
+	}
+}
@@ -0,0 +1,193 @@
+// Copyright 2026 the P&AI authors. All rights reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package agent
+
+import (
+	"fmt"
+	"log/slog"
+	"strconv"
+	"strings"
+
+	"github.com/p-n-ai/pai-bot/internal/chat"
+	"github.com/p-n-ai/pai-bot/internal/curriculum"
+	"github.com/p-n-ai/pai-bot/internal/i18n"
+)
+
+// onboardingTopicState is the last onboarding step: the learner has picked a
+// form and is offered a first topic.
+const onboardingTopicState = "onboarding_topic"
+
+const (
+	onboardingTopicChoicePrefix = "topic:"
+	onboardingSkipChoice        = "onboarding:skip"
+	maxOnboardingTopics         = 4
+)
+
+// onboardingLanguageChoices renders the language step's buttons.
+func onboardingLanguageChoices() string {
+	return "\n" + chat.ChoiceActionCode("lang:en", "English") +
+		chat.ChoiceActionCode("lang:ms", "BM") +
+		chat.ChoiceActionCode("lang:zh", "中文")
+}
+
+// onboardingFormChoices renders the form step's buttons.
+func onboardingFormChoices(lang string) string {
+	var b strings.Builder
+	b.WriteString("\n")
+	for form := 1; form <= 3; form++ {
+		b.WriteString(chat.ChoiceActionCode(fmt.Sprintf("form:%d", form), i18n.S(lang, i18n.MsgOnboardingFormChoice, form)))
+	}
+	return b.String()
+}
+
+// onboardingTopics returns the first topics of form's syllabus, which the
+// topic step offers as starting points.
+func (e *Engine) onboardingTopics(form int) []curriculum.Topic {
+	topics := e.studyPlanTopics(strconv.Itoa(form))
+	if len(topics) == 0 {
+		return nil
+	}
+	e.sortTopicsBySyllabus(topics)
+	if len(topics) > maxOnboardingTopics {
+		topics = topics[:maxOnboardingTopics]
+	}
+	return topics
+}
+
+// onboardingTopicPrompt asks for a first topic. It returns the text to store
+// in history and the same text with the topic buttons appended.
+func onboardingTopicPrompt(lang string, form int, topics []curriculum.Topic) (string, string) {
+	lines := make([]string, len(topics))
+	for i, topic := range topics {
+		lines[i] = fmt.Sprintf("%d. %s", i+1, topic.Name)
+	}
+	text := i18n.S(lang, i18n.MsgOnboardingTopicPrompt, form, strings.Join(lines, "\n"))
+
+	var b strings.Builder
+	b.WriteString(text)
+	b.WriteString("\n")
+	for _, topic := range topics {
+		b.WriteString(chat.ChoiceActionCode(onboardingTopicChoicePrefix+topic.ID, topic.Name))
+	}
+	b.WriteString(chat.ChoiceActionCode(onboardingSkipChoice, i18n.S(lang, i18n.MsgOnboardingTopicSkip)))
+	return text, b.String()
+}
+
+// parseOnboardingTopicChoice matches a reply to the topic prompt: a button
+// tap, the topic's number in the list, or its name.
+func parseOnboardingTopicChoice(text string, topics []curriculum.Topic) (curriculum.Topic, bool) {
+	trimmed := strings.TrimSpace(text)
+	if id, ok := strings.CutPrefix(trimmed, onboardingTopicChoicePrefix); ok {
+		for _, topic := range topics {
+			if topic.ID == id {
+				return topic, true
+			}
+		}
+		return curriculum.Topic{}, false
+	}
+	if n, err := strconv.Atoi(trimmed); err == nil && n >= 1 && n <= len(topics) {
+		return topics[n-1], true
+	}
+	for _, topic := range topics {
+		if strings.EqualFold(trimmed, topic.Name) {
+			return topic, true
+		}
+	}
+	return curriculum.Topic{}, false
+}
+
+// handleOnboardingTopic handles a reply to the topic prompt. It reports false
+// when the learner ignored the prompt and asked something else instead; the
+// wizard is then finished and the message should be handled as a normal turn.
+func (e *Engine) handleOnboardingTopic(msg chat.InboundMessage, conv *Conversation) (string, bool) {
+	lang := e.messageLocale(msg, conv)
+	form := 0
+	if raw, ok := e.store.GetUserForm(msg.UserID); ok {
+		form, _ = strconv.Atoi(raw)
+	}
+	topics := e.onboardingTopics(form)
+
+	skipped := strings.EqualFold(strings.TrimSpace(msg.Text), onboardingSkipChoice)
+	topic, picked := parseOnboardingTopicChoice(msg.Text, topics)
+	if !skipped && !picked {
+		if err := e.store.UpdateConversationState(conv.ID, "teaching"); err != nil {
+			slog.Error("failed to finish onboarding", "conversation_id", conv.ID, "error", err)
+		}
+		conv.State = "teaching"
+		e.logOnboardingTopicSkipped(conv, msg.UserID, "free_text")
+		e.logOnboardingCompleted(conv, msg.UserID, form, lang, "")
+		return "", false
+	}
+
+	if _, err := e.store.AddMessage(conv.ID, StoredMessage{
+		Role:    "user",
+		Content: msg.Text,
+	}); err != nil {
+		slog.Error("failed to store onboarding user message", "error", err)
+	}
+
+	response := i18n.S(lang, i18n.MsgOnboardingCompleted, form)
+	if picked {
+		if err := e.store.UpdateConversationTopicID(conv.ID, topic.ID); err != nil {
+			slog.Error("failed to set onboarding topic", "conversation_id", conv.ID, "topic_id", topic.ID, "error", err)
+			return i18n.S(lang, i18n.MsgTechnicalIssue), true
+		}
+		response = i18n.S(lang, i18n.MsgLearnTopicSet, topic.Name)
+	}
+	if err := e.store.UpdateConversationState(conv.ID, "teaching"); err != nil {
+		slog.Error("failed to update conversation state", "conversation_id", conv.ID, "error", err)
+		return i18n.S(lang, i18n.MsgTechnicalIssue), true
+	}
+	if _, err := e.store.AddMessage(conv.ID, StoredMessage{
+		Role:    "assistant",
+		Content: response,
+	}); err != nil {
+		slog.Error("failed to store onboarding assistant message", "error", err)
+	}
+
+	if picked {
+		e.logEventAsync(Event{
+			ConversationID: conv.ID,
+			UserID:         msg.UserID,
+			EventType:      "onboarding_topic_selected",
+			Data: map[string]any{
+				"topic_id":   topic.ID,
+				"topic_name": topic.Name,
+			},
+		})
+	} else {
+		e.logOnboardingTopicSkipped(conv, msg.UserID, "button")
+	}
+	e.logOnboardingCompleted(conv, msg.UserID, form, lang, topic.ID)
+	return response, true
+}
+
+func (e *Engine) logOnboardingTopicSkipped(conv *Conversation, userID, source string) {
+	e.logEventAsync(Event{
+		ConversationID: conv.ID,
+		UserID:         userID,
+		EventType:      "onboarding_topic_skipped",
+		Data: map[string]any{
+			"source": source,
+		},
+	})
+}
+
+// logOnboardingCompleted records the end of the onboarding funnel. topicID is
+// empty when the learner finished without picking a first topic.
+func (e *Engine) logOnboardingCompleted(conv *Conversation, userID string, form int, lang, topicID string) {
+	data := map[string]any{
+		"selected_form":      form,
+		"preferred_language": lang,
+	}
+	if topicID != "" {
+		data["first_topic_id"] = topicID
+	}
+	e.logEventAsync(Event{
+		ConversationID: conv.ID,
+		UserID:         userID,
+		EventType:      "onboarding_completed",
+		Data:           data,
+	})
+}
@@ -0,0 +1,159 @@
+// Copyright 2026 the P&AI authors. All rights reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package agent_test
+
+import (
+	"context"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/p-n-ai/pai-bot/internal/agent"
+	"github.com/p-n-ai/pai-bot/internal/ai"
+	"github.com/p-n-ai/pai-bot/internal/chat"
+)
+
+func TestEngine_OnboardingWizard_ButtonsThroughFirstTopic(t *testing.T) {
+	mockAI := ai.NewMockProvider("AI teaching response")
+	store := agent.NewMemoryStore()
+	eventLogger := agent.NewMemoryEventLogger()
+	engine := agent.NewEngine(agent.EngineConfig{
+		AIRouter:         mockRouter(mockAI),
+		Store:            store,
+		EventLogger:      eventLogger,
+		CurriculumLoader: createTestCurriculumLoader(t),
+	})
+	tap := func(data string) string {
+		t.Helper()
+		resp, err := engine.ProcessMessage(context.Background(), chat.InboundMessage{
+			Channel:         "telegram",
+			UserID:          "wizard-user",
+			Text:            data,
+			CallbackQueryID: "cb-" + data,
+		})
+		if err != nil {
+			t.Fatalf("ProcessMessage(%q) error = %v", data, err)
+		}
+		return resp
+	}
+
+	resp := tap("/start")
+	if got := chat.BuildTelegramInlineKeyboard(resp); len(got) != 1 || got[0][0].CallbackData != "lang:en" {
+		t.Fatalf("language step keyboard = %#v, want language buttons", got)
+	}
+
+	resp = tap("lang:en")
+	keyboard := chat.BuildTelegramInlineKeyboard(resp)
+	if len(keyboard) != 1 || len(keyboard[0]) != 3 || keyboard[0][0] != (chat.InlineButton{Text: "Form 1", CallbackData: "form:1"}) {
+		t.Fatalf("form step keyboard = %#v, want Form 1-3 buttons", keyboard)
+	}
+
+	resp = tap("form:1")
+	if !strings.Contains(chat.StripActionCodes(resp), "1. Linear Equations") {
+		t.Fatalf("topic step = %q, want the numbered topic list", resp)
+	}
+	keyboard = chat.BuildTelegramInlineKeyboard(resp)
+	want := []chat.InlineButton{{Text: "Linear Equations", CallbackData: "topic:F1-02"}}
+	if len(keyboard) != 2 || !slices.Equal(keyboard[0], want) || keyboard[1][0].CallbackData != "onboarding:skip" {
+		t.Fatalf("topic step keyboard = %#v, want topic then skip", keyboard)
+	}
+	if form, _ := store.GetUserForm("wizard-user"); form != "1" {
+		t.Fatalf("GetUserForm() = %q, want 1", form)
+	}
+
+	resp = tap("topic:F1-02")
+	if !strings.Contains(resp, "Linear Equations") {
+		t.Fatalf("topic pick response = %q, want the topic confirmed", resp)
+	}
+	conv, _ := store.GetActiveConversation("wizard-user")
+	if conv.State != "teaching" || conv.TopicID != "F1-02" {
+		t.Fatalf("conversation = state %q topic %q, want teaching on F1-02", conv.State, conv.TopicID)
+	}
+	if mockAI.LastRequest != nil {
+		t.Fatal("AI should not be called during the wizard")
+	}
+
+	funnel := []string{
+		"onboarding_started",
+		"onboarding_language_selected",
+		"onboarding_form_selected",
+		"onboarding_topic_selected",
+		"onboarding_completed",
+	}
+	var got []string
+	deadline := time.Now().Add(500 * time.Millisecond)
+	for time.Now().Before(deadline) {
+		got = got[:0]
+		for _, event := range eventLogger.Events() {
+			if strings.HasPrefix(event.EventType, "onboarding_") {
+				got = append(got, event.EventType)
+			}
+		}
+		if len(got) == len(funnel) {
+			break
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+	slices.Sort(got)
+	slices.Sort(funnel)
+	if !slices.Equal(got, funnel) {
+		t.Fatalf("onboarding events = %v, want %v", got, funnel)
+	}
+}
+
+func TestEngine_OnboardingWizard_FreeTextSkipsTopicAndTeaches(t *testing.T) {
+	mockAI := ai.NewMockProvider("AI teaching response")
+	store := agent.NewMemoryStore()
+	engine := agent.NewEngine(agent.EngineConfig{
+		AIRouter:             mockRouter(mockAI),
+		Store:                store,
+		CurriculumLoader:     createTestCurriculumLoader(t),
+		DisableMultiLanguage: true,
+	})
+	send := func(text string) string {
+		t.Helper()
+		resp, err := engine.ProcessMessage(context.Background(), chat.InboundMessage{
+			Channel: "telegram", UserID: "wizard-free", Text: text,
+		})
+		if err != nil {
+			t.Fatalf("ProcessMessage(%q) error = %v", text, err)
+		}
+		return resp
+	}
+
+	send("/start")
+	send("Tingkatan 1")
+	if conv, _ := store.GetActiveConversation("wizard-free"); conv.State != "onboarding_topic" {
+		t.Fatalf("state after form = %q, want onboarding_topic", conv.State)
+	}
+
+	if resp := send("Macam mana nak selesaikan 2x + 3 = 7?"); resp != "AI teaching response" {
+		t.Fatalf("free-text reply = %q, want a normal teaching turn", resp)
+	}
+	if conv, _ := store.GetActiveConversation("wizard-free"); conv.State != "teaching" {
+		t.Fatalf("state after free text = %q, want teaching", conv.State)
+	}
+}
+
+func TestEngine_OnboardingWizard_NumberPicksListedTopic(t *testing.T) {
+	store := agent.NewMemoryStore()
+	engine := agent.NewEngine(agent.EngineConfig{
+		AIRouter:             mockRouter(ai.NewMockProvider("")),
+		Store:                store,
+		CurriculumLoader:     createTestCurriculumLoader(t),
+		DisableMultiLanguage: true,
+	})
+	for _, text := range []string{"/start", "form:1", "1"} {
+		if _, err := engine.ProcessMessage(context.Background(), chat.InboundMessage{
+			Channel: "websocket", UserID: "wizard-num", Text: text,
+		}); err != nil {
+			t.Fatalf("ProcessMessage(%q) error = %v", text, err)
+		}
+	}
+	conv, _ := store.GetActiveConversation("wizard-num")
+	if conv.State != "teaching" || conv.TopicID != "F1-02" {
+		t.Fatalf("conversation = state %q topic %q, want teaching on F1-02", conv.State, conv.TopicID)
+	}
+}
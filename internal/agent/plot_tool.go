@@ -0,0 +1,71 @@
+// Copyright 2026 the P&AI authors. All rights reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package agent
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/p-n-ai/pai-bot/internal/agentcore"
+	"github.com/p-n-ai/pai-bot/internal/llm"
+	"github.com/p-n-ai/pai-bot/internal/plot"
+)
+
+const plotGraphToolName = "plot_graph"
+
+// plotGraphTool renders y = f(x) to an image sent with the tutor's reply.
+// A turn sends at most one graph; a later call replaces an earlier one.
+type plotGraphTool struct {
+	graph *plot.Graph
+}
+
+func (*plotGraphTool) Definition() llm.Tool {
+	return llm.Tool{
+		Name:        plotGraphToolName,
+		Description: "Draw the graph of y = f(x) for a polynomial in x (linear, quadratic, cubic) and send it to the student as an image with your reply. Intercepts are marked. Use it when a graph would explain the idea better than words, then refer to the picture in your reply.",
+		Parameters: json.RawMessage(`{
+			"type":"object",
+			"properties":{
+				"function":{"type":"string","minLength":1,"description":"The function in x, e.g. y = x^2 - 2x - 3."},
+				"x_min":{"type":"number","description":"Left edge of the graph. Defaults to -5."},
+				"x_max":{"type":"number","description":"Right edge of the graph. Defaults to 5."}
+			},
+			"required":["function"],
+			"additionalProperties":false
+		}`),
+	}
+}
+
+func (t *plotGraphTool) Execute(_ context.Context, call llm.ToolCall) (llm.ToolResultMessage, error) {
+	function, _ := call.Arguments["function"].(string)
+	xMin, _ := call.Arguments["x_min"].(float64)
+	xMax, _ := call.Arguments["x_max"].(float64)
+	graph, err := plot.Render(function, xMin, xMax)
+	if err != nil {
+		return llm.ToolResultMessage{
+			Content:   []llm.UserContent{llm.TextContent{Text: err.Error()}},
+			IsError:   true,
+			Timestamp: time.Now(),
+		}, nil
+	}
+	t.graph = &graph
+	return llm.ToolResultMessage{
+		Content: []llm.UserContent{llm.TextContent{Text: fmt.Sprintf(
+			"The graph of y = %s for %g ≤ x ≤ %g will be sent as an image just before your reply. Intercepts are marked with red dots.",
+			graph.Function, graph.XMin, graph.XMax)}},
+		Timestamp: time.Now(),
+	}, nil
+}
+
+// plottedGraph returns the graph a plot tool among tools drew, if any.
+func plottedGraph(tools []agentcore.Tool) *plot.Graph {
+	for _, tool := range tools {
+		if plotter, ok := tool.(*plotGraphTool); ok && plotter.graph != nil {
+			return plotter.graph
+		}
+	}
+	return nil
+}
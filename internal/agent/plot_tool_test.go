@@ -0,0 +1,45 @@
+// Copyright 2026 the P&AI authors. All rights reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package agent
+
+import (
+	"bytes"
+	"context"
+	"testing"
+
+	"github.com/p-n-ai/pai-bot/internal/agentcore"
+	"github.com/p-n-ai/pai-bot/internal/llm"
+)
+
+func TestPlotGraphToolKeepsRenderedGraph(t *testing.T) {
+	tool := &plotGraphTool{}
+	result, err := tool.Execute(context.Background(), llm.ToolCall{
+		Name:      plotGraphToolName,
+		Arguments: map[string]any{"function": "y = x^2 - 4", "x_min": -3.0, "x_max": 3.0},
+	})
+	if err != nil || result.IsError {
+		t.Fatalf("Execute() = %#v, %v", result, err)
+	}
+	graph := plottedGraph([]agentcore.Tool{stepCheckTool{}, tool})
+	if graph == nil || graph.Function != "x^2 - 4" || graph.XMin != -3 || graph.XMax != 3 {
+		t.Fatalf("plotted graph = %+v", graph)
+	}
+	if !bytes.HasPrefix(graph.PNG, []byte("\x89PNG")) {
+		t.Fatal("graph is not a PNG")
+	}
+}
+
+func TestPlotGraphToolRejectsOtherVariables(t *testing.T) {
+	tool := &plotGraphTool{}
+	result, err := tool.Execute(context.Background(), llm.ToolCall{
+		Name:      plotGraphToolName,
+		Arguments: map[string]any{"function": "y = 2t + 1"},
+	})
+	if err != nil || !result.IsError {
+		t.Fatalf("Execute() = %#v, %v; want a tool error", result, err)
+	}
+	if plottedGraph([]agentcore.Tool{tool}) != nil {
+		t.Fatal("failed plot should not be sent")
+	}
+}
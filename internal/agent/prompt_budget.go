@@ -0,0 +1,54 @@
+// Copyright 2026 the P&AI authors. All rights reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package agent
+
+import "github.com/p-n-ai/pai-bot/internal/ai"
+
+// imagePromptTokens approximates what one attached image costs in input
+// tokens. Providers bill images by resolution; this is the common figure for a
+// phone photo at high detail and only needs to be in the right ballpark.
+const imagePromptTokens = 765
+
+// promptBudget is a rough per-section token breakdown of a composed teaching
+// prompt, so agent_turn_completed shows what is filling the context window.
+type promptBudget struct {
+	System        int
+	TeachingNotes int
+	Summary       int
+	Memory        int
+	History       int
+	LearnerInput  int
+	Image         int
+}
+
+func (b promptBudget) total() int {
+	return b.System + b.TeachingNotes + b.Summary + b.Memory + b.History + b.LearnerInput + b.Image
+}
+
+func (b promptBudget) eventData() map[string]any {
+	return map[string]any{
+		"system_tokens":         b.System,
+		"teaching_notes_tokens": b.TeachingNotes,
+		"summary_tokens":        b.Summary,
+		"memory_tokens":         b.Memory,
+		"history_tokens":        b.History,
+		"learner_input_tokens":  b.LearnerInput,
+		"image_tokens":          b.Image,
+		"total_tokens":          b.total(),
+	}
+}
+
+// estimateTextTokens uses the same 4-characters-per-token rule as
+// estimateTokens.
+func estimateTextTokens(s string) int {
+	return len(s) / 4
+}
+
+func estimateMessageTokens(messages []ai.Message) int {
+	total := 0
+	for _, m := range messages {
+		total += estimateTextTokens(m.Content)
+	}
+	return total
+}
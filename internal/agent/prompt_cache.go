@@ -0,0 +1,121 @@
+// Copyright 2026 the P&AI authors. All rights reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package agent
+
+import (
+	"sync"
+	"time"
+
+	"github.com/p-n-ai/pai-bot/internal/chat"
+	"github.com/p-n-ai/pai-bot/internal/curriculum"
+)
+
+const (
+	// systemPromptCacheTTL bounds how long a profile edit made outside the
+	// engine, e.g. from the admin panel, can go unseen.
+	systemPromptCacheTTL = 30 * time.Minute
+	// maxSystemPromptCacheEntries caps memory; a full cache is dropped whole.
+	maxSystemPromptCacheEntries = 4096
+)
+
+// systemPromptKey identifies the static part of a learner's system prompt.
+// convUserID differs from userID when the chat is linked to another channel.
+type systemPromptKey struct {
+	userID            string
+	convUserID        string
+	topicID           string
+	curriculumVersion uint64
+}
+
+// systemPromptParts are the sections of the system prompt that only change
+// with the learner's profile or the curriculum.
+type systemPromptParts struct {
+	language string
+	topic    string
+	// notes are the teaching notes topic was rendered from. Retrieval can
+	// pick different note sections per message, so a mismatch re-renders.
+	notes   string
+	builtAt time.Time
+}
+
+// systemPromptCache keeps each learner's composed static prompt sections
+// between turns, so a turn only renders the parts that depend on the latest
+// message and current mastery.
+type systemPromptCache struct {
+	mu      sync.Mutex
+	entries map[systemPromptKey]systemPromptParts
+}
+
+func newSystemPromptCache() *systemPromptCache {
+	return &systemPromptCache{entries: make(map[systemPromptKey]systemPromptParts)}
+}
+
+func (c *systemPromptCache) get(key systemPromptKey, now time.Time) (systemPromptParts, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	parts, ok := c.entries[key]
+	if !ok || now.Sub(parts.builtAt) > systemPromptCacheTTL {
+		return systemPromptParts{}, false
+	}
+	return parts, true
+}
+
+func (c *systemPromptCache) put(key systemPromptKey, parts systemPromptParts) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if len(c.entries) >= maxSystemPromptCacheEntries {
+		clear(c.entries)
+	}
+	c.entries[key] = parts
+}
+
+// forgetUser drops every entry that renders userID's profile.
+func (c *systemPromptCache) forgetUser(userID string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for key := range c.entries {
+		if key.userID == userID || key.convUserID == userID {
+			delete(c.entries, key)
+		}
+	}
+}
+
+// cachedSystemPrompt returns the static system prompt sections for this
+// learner and topic, rendering them on a miss.
+func (e *Engine) cachedSystemPrompt(msg chat.InboundMessage, conv *Conversation, topic *curriculum.Topic, teachingNotes string) systemPromptParts {
+	key := systemPromptKey{userID: msg.UserID}
+	if conv != nil {
+		key.convUserID = conv.UserID
+	}
+	if topic != nil {
+		key.topicID = topic.ID
+	}
+	if e.curriculumLoader != nil {
+		key.curriculumVersion = e.curriculumLoader.Version()
+	}
+
+	now := time.Now()
+	parts, ok := e.promptCache.get(key, now)
+	if ok && parts.notes == teachingNotes {
+		return parts
+	}
+	if !ok {
+		parts = systemPromptParts{
+			language: e.preferredLanguageInstruction(msg, conv),
+			builtAt:  now,
+		}
+	}
+	parts.topic = topicPromptBlock(topic, teachingNotes)
+	parts.notes = teachingNotes
+	e.promptCache.put(key, parts)
+	return parts
+}
+
+// setUserPreferredLanguage stores a language change and drops the learner's
+// cached prompt and warm snapshot, which both carry the preference.
+func (e *Engine) setUserPreferredLanguage(userID, lang string) error {
+	e.promptCache.forgetUser(userID)
+	e.warm.release(userID)
+	return e.store.SetUserPreferredLanguage(userID, lang)
+}
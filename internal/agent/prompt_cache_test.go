@@ -0,0 +1,51 @@
+// Copyright 2026 the P&AI authors. All rights reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package agent_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/p-n-ai/pai-bot/internal/agent"
+	"github.com/p-n-ai/pai-bot/internal/ai"
+	"github.com/p-n-ai/pai-bot/internal/curriculum"
+)
+
+func TestEngine_CachedSystemPromptFollowsProfileAndCurriculum(t *testing.T) {
+	mockAI := ai.NewMockProvider("ok")
+	store := agent.NewMemoryStore()
+	_ = store.SetUserForm("cache-user", "1")
+	_ = store.SetUserPreferredLanguage("cache-user", "en")
+	loader := createTestCurriculumLoader(t)
+	engine := agent.NewEngine(agent.EngineConfig{
+		AIRouter:         mockRouter(mockAI),
+		Store:            store,
+		CurriculumLoader: loader,
+	})
+	_, _ = store.CreateConversation(agent.Conversation{UserID: "cache-user", State: "teaching", TopicID: "F1-02"})
+
+	systemPrompt := func(text string) string {
+		t.Helper()
+		sendAs(t, engine, "telegram", "cache-user", text)
+		return mockAI.LastRequest.Messages[0].Content
+	}
+
+	first := systemPrompt("solve 2x + 3 = 7")
+	if !strings.Contains(first, "Preferred language setting: English") || !strings.Contains(first, "Linear Equations") {
+		t.Fatalf("first prompt missing profile or topic:\n%s", first)
+	}
+	if again := systemPrompt("solve 2x + 3 = 7"); again != first {
+		t.Fatal("repeated turn should render the same prompt")
+	}
+
+	sendAs(t, engine, "telegram", "cache-user", "/language ms")
+	if got := systemPrompt("solve 2x + 3 = 7"); !strings.Contains(got, "Preferred language setting: Bahasa Melayu") {
+		t.Fatalf("prompt after /language ms kept the old preference:\n%s", got)
+	}
+
+	loader.SetTeachingNoteOverlay("F1-02", curriculum.TeachingNoteOverlay{Notes: "Use the balance-scale picture first."})
+	if got := systemPrompt("ok"); !strings.Contains(got, "balance-scale picture") {
+		t.Fatalf("prompt after overlay edit missing school notes:\n%s", got)
+	}
+}
@@ -0,0 +1,70 @@
+// Copyright 2026 the P&AI authors. All rights reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package agent
+
+import (
+	"runtime/debug"
+	"sync"
+)
+
+// PromptChange is one entry in the tutor prompt changelog.
+type PromptChange struct {
+	Version string `json:"version"`
+	Summary string `json:"summary"`
+}
+
+// promptChangelog lists tutor prompt versions, newest first. Add an entry
+// whenever a change to the system prompt, its context blocks or the
+// instruction rules could move answer quality, so dashboards segmenting
+// ai_response events by prompt_version see the change.
+var promptChangelog = []PromptChange{
+	{Version: "2026-10-17.1", Summary: "Baseline: tutor persona and behavior rules, teaching notes, learner memory, context trust rules, image instructions."},
+}
+
+// PromptVersion is the tutor prompt version stamped on ai_response events.
+var PromptVersion = promptChangelog[0].Version
+
+// PromptChangelog returns the prompt changelog, newest first.
+func PromptChangelog() []PromptChange {
+	return append([]PromptChange(nil), promptChangelog...)
+}
+
+// buildVersion is set at link time, e.g.
+//
+//	go build -ldflags "-X github.com/p-n-ai/pai-bot/internal/agent.buildVersion=$(git rev-parse --short HEAD)"
+//
+// Docker builds pass it in since the build context has no .git.
+var buildVersion string
+
+// EngineBuild identifies the running engine build: the link-time version,
+// else the VCS revision Go stamped into the binary, else "devel".
+var EngineBuild = sync.OnceValue(func() string {
+	if buildVersion != "" {
+		return buildVersion
+	}
+	info, ok := debug.ReadBuildInfo()
+	if !ok {
+		return "devel"
+	}
+	var revision string
+	var modified bool
+	for _, setting := range info.Settings {
+		switch setting.Key {
+		case "vcs.revision":
+			revision = setting.Value
+		case "vcs.modified":
+			modified = setting.Value == "true"
+		}
+	}
+	if revision == "" {
+		return "devel"
+	}
+	if len(revision) > 12 {
+		revision = revision[:12]
+	}
+	if modified {
+		revision += "-dirty"
+	}
+	return revision
+})
@@ -0,0 +1,50 @@
+// Copyright 2026 the P&AI authors. All rights reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package agent_test
+
+import (
+	"testing"
+
+	"github.com/p-n-ai/pai-bot/internal/agent"
+	"github.com/p-n-ai/pai-bot/internal/ai"
+)
+
+func TestPromptChangelogIsNewestFirstAndUnique(t *testing.T) {
+	changelog := agent.PromptChangelog()
+	if len(changelog) == 0 || changelog[0].Version != agent.PromptVersion {
+		t.Fatalf("changelog = %+v, want PromptVersion %q first", changelog, agent.PromptVersion)
+	}
+	seen := map[string]bool{}
+	for i, change := range changelog {
+		if change.Version == "" || change.Summary == "" {
+			t.Errorf("changelog[%d] = %+v, want a version and summary", i, change)
+		}
+		if seen[change.Version] {
+			t.Errorf("changelog version %q listed twice", change.Version)
+		}
+		seen[change.Version] = true
+		if i > 0 && change.Version >= changelog[i-1].Version {
+			t.Errorf("changelog[%d] %q is not older than %q", i, change.Version, changelog[i-1].Version)
+		}
+	}
+}
+
+func TestAIResponseEventCarriesPromptVersionAndBuild(t *testing.T) {
+	logger := agent.NewMemoryEventLogger()
+	engine := agent.NewEngine(agent.EngineConfig{
+		AIRouter:    mockRouter(ai.NewMockProvider("Add 3 to both sides.")),
+		Store:       agent.NewMemoryStore(),
+		EventLogger: logger,
+	})
+
+	sendAs(t, engine, "telegram", "42", "How do I solve x - 3 = 5?")
+
+	event := waitForEvent(t, logger, "ai_response")
+	if event.Data["prompt_version"] != agent.PromptVersion {
+		t.Errorf("prompt_version = %v, want %q", event.Data["prompt_version"], agent.PromptVersion)
+	}
+	if build, _ := event.Data["engine_build"].(string); build == "" || build != agent.EngineBuild() {
+		t.Errorf("engine_build = %v, want %q", event.Data["engine_build"], agent.EngineBuild())
+	}
+}
@@ -4,8 +4,10 @@
 package agent
 
 import (
+	"math"
 	"regexp"
 	"sort"
+	"strconv"
 	"strings"
 
 	"github.com/p-n-ai/pai-bot/internal/curriculum"
@@ -66,6 +68,12 @@ type QuizSession struct {
 	Questions      []QuizQuestion
 	CurrentIndex   int
 	CorrectAnswers int
+	// Difficulty is the adapted level new questions are generated at; see
+	// adaptDifficulty. HitStreak and MissStreak count consecutive correct
+	// and wrong attempts toward the next change.
+	Difficulty string
+	HitStreak  int
+	MissStreak int
 }
 
 // NewQuizSession creates a new in-memory quiz session.
@@ -161,6 +169,7 @@ var (
 	structuredAssignmentPattern     = regexp.MustCompile(`(?i)^\s*([a-z])\s*=\s*`)
 	structuredAssignmentListPattern = regexp.MustCompile(`(?i)\b[a-z]\s*=\s*[^,;\n]+,\s*[a-z]\s*=`)
 	structuredNamedFieldPattern     = regexp.MustCompile(`(?i)^\s*(base|index|gradient|y[\s-]?intercept|intercept)\s*(?:is|=|:)?\s*`)
+	quizNumberPattern               = regexp.MustCompile(`-?\d+(?:\.\d+)?|-?\.\d+`)
 )
 
 type structuredQuizFragment struct {
@@ -188,6 +197,8 @@ func gradeQuizAnswer(question QuizQuestion, answer string) bool {
 		return actual == expected
 	case "multiple_choice":
 		return actual == expected
+	case "numeric":
+		return gradeNumericQuizAnswer(question.Answer, answer)
 	case "exact":
 		if gradeStructuredQuizAnswer(question.Answer, answer) {
 			return true
@@ -198,6 +209,29 @@ func gradeQuizAnswer(question QuizQuestion, answer string) bool {
 	}
 }
 
+// gradeNumericQuizAnswer accepts any answer holding the expected number,
+// with or without its unit, e.g. "RM 2.90", "2.9" or "x = 2.90".
+func gradeNumericQuizAnswer(expected, answer string) bool {
+	want, ok := soleQuizNumber(expected)
+	if !ok {
+		return false
+	}
+	got, ok := soleQuizNumber(answer)
+	return ok && math.Abs(got-want) < 1e-9
+}
+
+// soleQuizNumber returns the only number in value; thousands separators
+// are ignored.
+func soleQuizNumber(value string) (float64, bool) {
+	value = strings.NewReplacer(",", "", "−", "-", "–", "-").Replace(value)
+	matches := quizNumberPattern.FindAllString(value, -1)
+	if len(matches) != 1 {
+		return 0, false
+	}
+	n, err := strconv.ParseFloat(matches[0], 64)
+	return n, err == nil
+}
+
 func matchingDistractorFeedback(question QuizQuestion, answer string) string {
 	actual := normalizeQuizAnswer(answer)
 	for _, distractor := range question.Distractors {
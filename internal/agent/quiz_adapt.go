@@ -0,0 +1,98 @@
+// Copyright 2026 the P&AI authors. All rights reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package agent
+
+import "slices"
+
+const (
+	// quizEaseAfterMisses is how many wrong attempts in a row step the
+	// generated question difficulty down a level.
+	quizEaseAfterMisses = 2
+	// quizHardenAfterHits is how many correct answers in a row step it up.
+	quizHardenAfterHits = 3
+)
+
+// quizDifficultyOrder ranks the normalized difficulty labels.
+var quizDifficultyOrder = []string{"easy", "medium", "hard"}
+
+// quizDifficultyLevels returns the difficulty levels the topic's assessment
+// questions actually use, easiest first. A topic without difficulty metadata
+// has no levels and is not adapted.
+func quizDifficultyLevels(questions []QuizQuestion) []string {
+	var levels []string
+	for _, level := range quizDifficultyOrder {
+		if slices.ContainsFunc(questions, func(q QuizQuestion) bool {
+			return normalizeQuizIntensity(q.Difficulty) == level
+		}) {
+			levels = append(levels, level)
+		}
+	}
+	return levels
+}
+
+// startingQuizDifficulty picks the first level to generate at: the
+// learner's chosen intensity when the topic has it, otherwise the middle
+// of the topic's levels.
+func startingQuizDifficulty(levels []string, intensity string) string {
+	if len(levels) == 0 {
+		return ""
+	}
+	if normalized := normalizeQuizIntensity(intensity); slices.Contains(levels, normalized) {
+		return normalized
+	}
+	return levels[(len(levels)-1)/2]
+}
+
+// adaptDifficulty records one graded attempt and moves Difficulty a level
+// down after quizEaseAfterMisses misses in a row, or up after
+// quizHardenAfterHits hits in a row. It reports whether the level changed.
+func (s *QuizSession) adaptDifficulty(levels []string, correct bool) bool {
+	if correct {
+		s.HitStreak++
+		s.MissStreak = 0
+	} else {
+		s.MissStreak++
+		s.HitStreak = 0
+	}
+	current := slices.Index(levels, s.Difficulty)
+	if current < 0 {
+		return false
+	}
+	next := current
+	switch {
+	case s.MissStreak >= quizEaseAfterMisses && current > 0:
+		next = current - 1
+	case s.HitStreak >= quizHardenAfterHits && current < len(levels)-1:
+		next = current + 1
+	default:
+		return false
+	}
+	s.Difficulty = levels[next]
+	s.HitStreak, s.MissStreak = 0, 0
+	return true
+}
+
+// dropStaleGeneratedQuestions discards generated questions the learner has
+// not been shown yet once the difficulty has moved, so the next batch is
+// generated at the new level. staticCount is how many of the session's
+// questions came from the assessment file; a missed current question stays
+// on screen and is kept.
+func (s *QuizSession) dropStaleGeneratedQuestions(staticCount int, currentShown bool) {
+	keep := s.CurrentIndex
+	if currentShown {
+		keep++
+	}
+	keep = max(keep, staticCount)
+	if keep < len(s.Questions) {
+		s.Questions = s.Questions[:keep]
+	}
+}
+
+// generationDifficulty is the difficulty new questions are generated at.
+func (s *QuizSession) generationDifficulty() string {
+	if s.Difficulty != "" {
+		return s.Difficulty
+	}
+	return s.Intensity
+}
@@ -0,0 +1,69 @@
+// Copyright 2026 the P&AI authors. All rights reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package agent
+
+import "testing"
+
+func TestQuizSessionAdaptDifficulty(t *testing.T) {
+	levels := []string{"easy", "medium", "hard"}
+	tests := []struct {
+		name    string
+		start   string
+		answers []bool
+		want    string
+		changed int
+	}{
+		{name: "two misses step down", start: "medium", answers: []bool{false, false}, want: "easy", changed: 1},
+		{name: "three hits step up", start: "medium", answers: []bool{true, true, true}, want: "hard", changed: 1},
+		{name: "a hit resets the miss streak", start: "medium", answers: []bool{false, true, false}, want: "medium"},
+		{name: "two hits are not enough", start: "easy", answers: []bool{true, true}, want: "easy"},
+		{name: "no level below the easiest", start: "easy", answers: []bool{false, false, false, false}, want: "easy"},
+		{name: "streak restarts after a change", start: "hard", answers: []bool{false, false, false}, want: "medium", changed: 1},
+		{name: "unknown level is left alone", start: "", answers: []bool{false, false}, want: ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			session := &QuizSession{Difficulty: tt.start}
+			changed := 0
+			for _, correct := range tt.answers {
+				if session.adaptDifficulty(levels, correct) {
+					changed++
+				}
+			}
+			if session.Difficulty != tt.want || changed != tt.changed {
+				t.Fatalf("Difficulty = %q after %d changes, want %q after %d", session.Difficulty, changed, tt.want, tt.changed)
+			}
+		})
+	}
+}
+
+func TestQuizDifficultyLevelsFromAssessmentMetadata(t *testing.T) {
+	levels := quizDifficultyLevels([]QuizQuestion{{Difficulty: "sukar"}, {Difficulty: "easy"}, {Difficulty: ""}})
+	if len(levels) != 2 || levels[0] != "easy" || levels[1] != "hard" {
+		t.Fatalf("quizDifficultyLevels() = %v, want [easy hard]", levels)
+	}
+	if got := startingQuizDifficulty(levels, "mixed"); got != "easy" {
+		t.Fatalf("startingQuizDifficulty(mixed) = %q, want easy", got)
+	}
+	if got := startingQuizDifficulty(levels, "hard"); got != "hard" {
+		t.Fatalf("startingQuizDifficulty(hard) = %q, want hard", got)
+	}
+	if got := startingQuizDifficulty(nil, "hard"); got != "" {
+		t.Fatalf("startingQuizDifficulty() without levels = %q, want empty", got)
+	}
+}
+
+func TestQuizSessionDropStaleGeneratedQuestions(t *testing.T) {
+	session := NewQuizSession("u", "F1-01", []QuizQuestion{{ID: "S1"}, {ID: "G1"}, {ID: "G2"}, {ID: "G3"}})
+	session.CurrentIndex = 2
+
+	session.dropStaleGeneratedQuestions(1, true)
+	if len(session.Questions) != 3 {
+		t.Fatalf("kept %d questions, want the missed G2 kept", len(session.Questions))
+	}
+	session.dropStaleGeneratedQuestions(1, false)
+	if len(session.Questions) != 2 {
+		t.Fatalf("kept %d questions, want unseen generated questions dropped", len(session.Questions))
+	}
+}
@@ -24,6 +24,8 @@ type quizGenerateInput struct {
 	N             int
 	TeachingNotes string
 	AllQuestions  []QuizQuestion
+	// PastPaperExam asks for questions in that exam's past-paper style, e.g. "SPM".
+	PastPaperExam string
 }
 
 // quizQuestionGenerator generates quiz questions using an AI router.
@@ -48,6 +50,7 @@ func (g *quizQuestionGenerator) Generate(ctx context.Context, input quizGenerate
 		Intensity:     input.Intensity,
 		TeachingNotes: input.TeachingNotes,
 		Exemplars:     exemplars,
+		PastPaperExam: input.PastPaperExam,
 	})
 	resp, err := g.aiRouter.Complete(ctx, ai.CompletionRequest{
 		Task:        ai.TaskGrading,
@@ -99,10 +102,15 @@ type examMimicryPromptInput struct {
 	Intensity     string
 	TeachingNotes string
 	Exemplars     []QuizQuestion
+	PastPaperExam string
 }
 
 func buildExamMimicryPrompt(input examMimicryPromptInput) string {
 	exemplarJSON, _ := json.MarshalIndent(input.Exemplars, "", "  ")
+	pastPaper := ""
+	if input.PastPaperExam != "" {
+		pastPaper = fmt.Sprintf("\n- Write them as %s past-year paper questions: exam-paper phrasing, marks shown in brackets, and multi-part (a)/(b) structure where the topic allows", input.PastPaperExam)
+	}
 	return fmt.Sprintf(`You are a KSSM Mathematics exam question writer for Malaysian secondary students.
 
 Generate %d new questions for:
@@ -121,11 +129,11 @@ Requirements:
 - Each question must have: text, answer (type + value + working), difficulty, hints (2 levels), and distractors (for multiple_choice)
 - Use Bahasa Melayu or English matching the exemplar language
 - Include LaTeX math notation where appropriate
-- Do not duplicate any of the example questions
+- Do not duplicate any of the example questions%s
 
 Return a JSON array of questions.`,
 		input.N, input.TopicName, input.TopicID, input.SyllabusID,
-		input.Intensity, input.TeachingNotes, string(exemplarJSON))
+		input.Intensity, input.TeachingNotes, string(exemplarJSON), pastPaper)
 }
 
 type generatedQuestionJSON struct {
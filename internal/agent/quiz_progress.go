@@ -33,31 +33,13 @@ func (e *Engine) recordQuizOutcomeAsync(userID, topicID, transport string, quest
 				slog.Warn("failed to update quiz mastery", "user_id", userID, "topic_id", topicID, "error", err)
 			} else {
 				e.syncGoalProgress(userID, syllabusID, topicID)
-				if e.xp != nil {
+				if e.xp != nil || e.badges != nil {
 					masteryAfter, err := e.tracker.GetMastery(userID, syllabusID, topicID)
 					if err != nil {
 						slog.Warn("failed to read quiz mastery after update", "user_id", userID, "topic_id", topicID, "error", err)
 					} else if !progress.IsMastered(masteryBefore) && progress.IsMastered(masteryAfter) {
-						if err := e.xp.Award(userID, progress.XPSourceMastery, progress.XPMasteryUp, map[string]any{
-							"topic_id":     topicID,
-							"syllabus_id":  syllabusID,
-							"question_id":  question.ID,
-							"difficulty":   question.Difficulty,
-							"from_quiz":    true,
-							"quiz_correct": correct,
-						}); err != nil {
-							slog.Warn("failed to award mastery xp from quiz", "user_id", userID, "topic_id", topicID, "error", err)
-						}
-						if e.milestones != nil && e.userABGroup(userID) == ABGroupA {
-							topicName := topicID
-							if e.curriculumLoader != nil {
-								if t, ok := e.curriculumLoader.GetTopic(topicID); ok {
-									topicName = t.Name
-								}
-							}
-							locale := e.resolveUserLocale(userID)
-							e.milestones.add(userID, FormatTopicMasteredCelebration(locale, topicName, progress.XPMasteryUp))
-						}
+						e.evaluateTopicMasteredBadge(userID, topicID)
+						e.awardQuizMasteryXP(userID, syllabusID, topicID, question, correct)
 					}
 				}
 			}
@@ -78,6 +60,34 @@ func (e *Engine) recordQuizOutcomeAsync(userID, topicID, transport string, quest
 	}()
 }
 
+// awardQuizMasteryXP awards the mastery XP and celebration for a topic a quiz
+// answer just took over the mastery threshold.
+func (e *Engine) awardQuizMasteryXP(userID, syllabusID, topicID string, question QuizQuestion, correct bool) {
+	if e.xp == nil {
+		return
+	}
+	if err := e.xp.Award(userID, progress.XPSourceMastery, progress.XPMasteryUp, map[string]any{
+		"topic_id":     topicID,
+		"syllabus_id":  syllabusID,
+		"question_id":  question.ID,
+		"difficulty":   question.Difficulty,
+		"from_quiz":    true,
+		"quiz_correct": correct,
+	}); err != nil {
+		slog.Warn("failed to award mastery xp from quiz", "user_id", userID, "topic_id", topicID, "error", err)
+	}
+	if e.milestones != nil && e.userABGroup(userID) == ABGroupA {
+		topicName := topicID
+		if e.curriculumLoader != nil {
+			if t, ok := e.curriculumLoader.GetTopic(topicID); ok {
+				topicName = t.Name
+			}
+		}
+		locale := e.resolveUserLocale(userID)
+		e.milestones.add(userID, FormatTopicMasteredCelebration(locale, topicName, progress.XPMasteryUp))
+	}
+}
+
 func quizMasterySignal(question QuizQuestion, correct bool) float64 {
 	difficulty := normalizeQuizIntensity(question.Difficulty)
 	if correct {
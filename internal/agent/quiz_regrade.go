@@ -0,0 +1,473 @@
+// Copyright 2026 the P&AI authors. All rights reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package agent
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"strings"
+	"time"
+
+	"github.com/p-n-ai/pai-bot/internal/ai"
+	"github.com/p-n-ai/pai-bot/internal/curriculum"
+	"github.com/p-n-ai/pai-bot/internal/progress"
+)
+
+// quizRegradeBatchSize is how many stored results a regrade reads at a time.
+const quizRegradeBatchSize = 200
+
+var errRegradeCostCap = errors.New("quiz regrade: AI grading needs a positive cost cap")
+
+// QuizResultFilter selects stored quiz results, oldest first. AfterID and
+// AfterCompletedAt page past the last result already read.
+type QuizResultFilter struct {
+	TopicID          string
+	Since            time.Time
+	AfterCompletedAt time.Time
+	AfterID          string
+	Limit            int
+}
+
+// QuizResultHistory reads and rewrites stored quiz results for re-grading.
+type QuizResultHistory interface {
+	ListQuizResults(ctx context.Context, filter QuizResultFilter) ([]QuizResult, error)
+	UpdateQuizResult(ctx context.Context, result QuizResult) error
+}
+
+// QuizRegraderConfig holds what a QuizRegrader reads and writes. Tracker
+// and AIRouter are optional: without a tracker mastery is left alone, and
+// without a router only the rule-based grader runs.
+type QuizRegraderConfig struct {
+	Results          QuizResultHistory
+	CurriculumLoader *curriculum.Loader
+	Tracker          progress.Tracker
+	AIRouter         *ai.Router
+	// WarmCache, with the results' TenantID, is where the engine keeps
+	// warm standby snapshots; learners whose mastery moves have theirs
+	// dropped. Nil skips that.
+	WarmCache WarmCache
+	TenantID  string
+}
+
+// QuizRegradeOptions scopes one regrade run. Nothing is written unless
+// Apply is set. AIGrading asks the grading model about free-text answers
+// the rules reject, at most RatePerSecond calls a second (0 is unpaced),
+// and stops asking once the calls have cost MaxCostUSD.
+type QuizRegradeOptions struct {
+	TopicID       string
+	Since         time.Time
+	Limit         int
+	Apply         bool
+	AIGrading     bool
+	RatePerSecond float64
+	MaxCostUSD    float64
+}
+
+// QuizRegradeReport is what a regrade run changed, or would change on a
+// dry run.
+type QuizRegradeReport struct {
+	Applied bool `json:"applied"`
+	Scanned int  `json:"scanned"`
+	Changed int  `json:"changed"`
+	// SkippedQuestions counts answers to questions no longer in the
+	// curriculum, such as generated ones, which keep their old grade.
+	SkippedQuestions int                  `json:"skipped_questions"`
+	AICalls          int                  `json:"ai_calls"`
+	CostUSD          float64              `json:"cost_usd"`
+	CostCapReached   bool                 `json:"cost_cap_reached"`
+	Results          []QuizRegradeDiff    `json:"results"`
+	Mastery          []MasteryRegradeDiff `json:"mastery"`
+}
+
+// QuizRegradeDiff is one stored result whose grade changed.
+type QuizRegradeDiff struct {
+	ResultID       string                    `json:"result_id"`
+	UserID         string                    `json:"user_id"`
+	TopicID        string                    `json:"topic_id"`
+	CompletedAt    time.Time                 `json:"completed_at"`
+	OldCorrect     int                       `json:"old_correct"`
+	NewCorrect     int                       `json:"new_correct"`
+	OldScore       float64                   `json:"old_score"`
+	NewScore       float64                   `json:"new_score"`
+	TotalQuestions int                       `json:"total_questions"`
+	Questions      []QuizQuestionRegradeDiff `json:"questions"`
+}
+
+// QuizQuestionRegradeDiff is one question whose grade changed.
+type QuizQuestionRegradeDiff struct {
+	Index      int     `json:"index"`
+	QuestionID string  `json:"question_id"`
+	OldCorrect bool    `json:"old_correct"`
+	NewCorrect bool    `json:"new_correct"`
+	OldScore   float64 `json:"old_score"`
+	NewScore   float64 `json:"new_score"`
+}
+
+// MasteryRegradeDiff is one learner's topic mastery before and after.
+type MasteryRegradeDiff struct {
+	UserID     string  `json:"user_id"`
+	SyllabusID string  `json:"syllabus_id"`
+	TopicID    string  `json:"topic_id"`
+	Old        float64 `json:"old"`
+	New        float64 `json:"new"`
+}
+
+// QuizRegrader re-grades stored quiz results against the current
+// curriculum answers and grading rules, and moves mastery to match.
+type QuizRegrader struct {
+	results   QuizResultHistory
+	loader    *curriculum.Loader
+	tracker   progress.Tracker
+	aiRouter  *ai.Router
+	warmCache WarmCache
+	tenantID  string
+}
+
+func NewQuizRegrader(cfg QuizRegraderConfig) *QuizRegrader {
+	return &QuizRegrader{
+		results:   cfg.Results,
+		loader:    cfg.CurriculumLoader,
+		tracker:   cfg.Tracker,
+		aiRouter:  cfg.AIRouter,
+		warmCache: cfg.WarmCache,
+		tenantID:  cfg.TenantID,
+	}
+}
+
+// quizMasteryKey is one learner's topic.
+type quizMasteryKey struct {
+	userID  string
+	topicID string
+}
+
+// quizMasteryReplay holds the mastery signals a learner's results gave
+// under the stored and the new grades, in the order they were answered.
+type quizMasteryReplay struct {
+	old, new []float64
+	changed  bool
+}
+
+// regradeRun is the state of one Run.
+type regradeRun struct {
+	*QuizRegrader
+	opts      QuizRegradeOptions
+	report    QuizRegradeReport
+	questions map[string]map[string]QuizQuestion
+	mastery   map[quizMasteryKey]*quizMasteryReplay
+	order     []quizMasteryKey
+	pace      *time.Ticker
+}
+
+// Run re-grades the results opts selects and reports the differences,
+// writing them only when opts.Apply is set.
+func (r *QuizRegrader) Run(ctx context.Context, opts QuizRegradeOptions) (QuizRegradeReport, error) {
+	if opts.AIGrading && r.aiRouter != nil && opts.MaxCostUSD <= 0 {
+		return QuizRegradeReport{}, errRegradeCostCap
+	}
+	run := &regradeRun{
+		QuizRegrader: r,
+		opts:         opts,
+		report:       QuizRegradeReport{Applied: opts.Apply, Results: []QuizRegradeDiff{}, Mastery: []MasteryRegradeDiff{}},
+		questions:    make(map[string]map[string]QuizQuestion),
+		mastery:      make(map[quizMasteryKey]*quizMasteryReplay),
+	}
+	if opts.AIGrading && opts.RatePerSecond > 0 {
+		run.pace = time.NewTicker(time.Duration(float64(time.Second) / opts.RatePerSecond))
+		defer run.pace.Stop()
+	}
+
+	filter := QuizResultFilter{TopicID: opts.TopicID, Since: opts.Since}
+	for {
+		filter.Limit = quizRegradeBatchSize
+		if opts.Limit > 0 {
+			filter.Limit = min(filter.Limit, opts.Limit-run.report.Scanned)
+		}
+		if filter.Limit <= 0 {
+			break
+		}
+		batch, err := r.results.ListQuizResults(ctx, filter)
+		if err != nil {
+			return run.report, fmt.Errorf("list quiz results: %w", err)
+		}
+		for _, result := range batch {
+			if err := run.regrade(ctx, result); err != nil {
+				return run.report, err
+			}
+		}
+		if len(batch) < filter.Limit {
+			break
+		}
+		last := batch[len(batch)-1]
+		filter.AfterCompletedAt, filter.AfterID = last.CompletedAt, last.ID
+	}
+
+	if err := run.recomputeMastery(ctx); err != nil {
+		return run.report, err
+	}
+	return run.report, nil
+}
+
+// regrade grades one stored result again and records the difference.
+func (run *regradeRun) regrade(ctx context.Context, result QuizResult) error {
+	run.report.Scanned++
+	current := run.topicQuestions(result.TopicID)
+	key := quizMasteryKey{userID: result.UserID, topicID: result.TopicID}
+	replay, ok := run.mastery[key]
+	if !ok {
+		replay = &quizMasteryReplay{}
+		run.mastery[key] = replay
+		run.order = append(run.order, key)
+	}
+
+	updated := result
+	updated.Questions = make([]QuizResultQuestion, len(result.Questions))
+	diff := QuizRegradeDiff{
+		ResultID:       result.ID,
+		UserID:         result.UserID,
+		TopicID:        result.TopicID,
+		CompletedAt:    result.CompletedAt,
+		OldCorrect:     result.CorrectAnswers,
+		OldScore:       result.Score,
+		TotalQuestions: result.TotalQuestions,
+	}
+	for i, stored := range result.Questions {
+		regraded := stored
+		question, found := current[stored.QuestionID]
+		if !found || stored.QuestionID == "" {
+			run.report.SkippedQuestions++
+		} else {
+			regraded.Correct, regraded.Score = false, 0
+			for n, answer := range stored.Answers {
+				correct, err := run.grade(ctx, question, answer)
+				if err != nil {
+					return err
+				}
+				if correct {
+					regraded.Correct = true
+					regraded.Score = 1 / float64(n+1)
+					break
+				}
+			}
+		}
+		updated.Questions[i] = regraded
+		replay.old = append(replay.old, storedMasterySignals(stored)...)
+		replay.new = append(replay.new, storedMasterySignals(regraded)...)
+		if regraded.Correct != stored.Correct || regraded.Score != stored.Score {
+			diff.Questions = append(diff.Questions, QuizQuestionRegradeDiff{
+				Index:      stored.Index,
+				QuestionID: stored.QuestionID,
+				OldCorrect: stored.Correct,
+				NewCorrect: regraded.Correct,
+				OldScore:   stored.Score,
+				NewScore:   regraded.Score,
+			})
+		}
+	}
+	if len(diff.Questions) == 0 {
+		return nil
+	}
+
+	updated.CorrectAnswers, updated.Score = 0, 0
+	for _, question := range updated.Questions {
+		if question.Correct {
+			updated.CorrectAnswers++
+		}
+		updated.Score += question.Score
+	}
+	if updated.TotalQuestions > 0 {
+		updated.Score /= float64(updated.TotalQuestions)
+	}
+	diff.NewCorrect, diff.NewScore = updated.CorrectAnswers, updated.Score
+	replay.changed = true
+	run.report.Changed++
+	run.report.Results = append(run.report.Results, diff)
+
+	if run.opts.Apply {
+		if err := run.results.UpdateQuizResult(ctx, updated); err != nil {
+			return fmt.Errorf("update quiz result %s: %w", result.ID, err)
+		}
+	}
+	return nil
+}
+
+// grade applies the rule-based grader, then the grading model for a
+// free-text answer the rules reject while the cost cap allows.
+func (run *regradeRun) grade(ctx context.Context, question QuizQuestion, answer string) (bool, error) {
+	if gradeQuizAnswer(question, answer) {
+		return true, nil
+	}
+	if !run.opts.AIGrading || run.aiRouter == nil || question.AnswerType != "free_text" || strings.TrimSpace(answer) == "" {
+		return false, nil
+	}
+	if run.report.CostUSD >= run.opts.MaxCostUSD {
+		run.report.CostCapReached = true
+		return false, nil
+	}
+	if run.pace != nil {
+		select {
+		case <-ctx.Done():
+			return false, ctx.Err()
+		case <-run.pace.C:
+		}
+	}
+
+	resp, err := run.aiRouter.Complete(ctx, ai.CompletionRequest{
+		Task:      ai.TaskGrading,
+		MaxTokens: 4,
+		Messages: []ai.Message{
+			{Role: "system", Content: "You grade secondary school maths quiz answers. Reply with only YES if the student's answer means the same as the expected answer, otherwise NO."},
+			{Role: "user", Content: fmt.Sprintf("Question: %s\nExpected answer: %s\nStudent answer: %s", question.Text, question.Answer, answer)},
+		},
+	})
+	if err != nil {
+		return false, fmt.Errorf("grade answer to %s: %w", question.ID, err)
+	}
+	run.report.AICalls++
+	run.report.CostUSD += resp.CostUSD
+	return strings.HasPrefix(strings.ToUpper(strings.TrimSpace(resp.Content)), "YES"), nil
+}
+
+// topicQuestions returns a topic's curriculum questions by ID.
+func (run *regradeRun) topicQuestions(topicID string) map[string]QuizQuestion {
+	if questions, ok := run.questions[topicID]; ok {
+		return questions
+	}
+	questions := make(map[string]QuizQuestion)
+	if run.loader != nil {
+		if assessment, ok := run.loader.GetAssessment(topicID); ok {
+			for _, question := range questionsFromAssessment(assessment) {
+				questions[question.ID] = question
+			}
+		}
+	}
+	run.questions[topicID] = questions
+	return questions
+}
+
+// recomputeMastery moves each affected learner's topic mastery by the
+// difference the new grades make when their answers are replayed.
+func (run *regradeRun) recomputeMastery(ctx context.Context) error {
+	if run.tracker == nil {
+		return nil
+	}
+	setter, canSet := run.tracker.(interface {
+		SetMastery(userID, syllabusID, topicID string, score float64) error
+	})
+	for _, key := range run.order {
+		replay := run.mastery[key]
+		if !replay.changed {
+			continue
+		}
+		shift := replayMastery(replay.new) - replayMastery(replay.old)
+		if shift == 0 {
+			continue
+		}
+		syllabusID := "default"
+		if run.loader != nil {
+			if topic, ok := run.loader.GetTopic(key.topicID); ok && topic.SyllabusID != "" {
+				syllabusID = topic.SyllabusID
+			}
+		}
+		current, err := run.tracker.GetMastery(key.userID, syllabusID, key.topicID)
+		if err != nil {
+			return fmt.Errorf("read mastery for %s/%s: %w", key.userID, key.topicID, err)
+		}
+		score := math.Min(1, math.Max(0, current+shift))
+		run.report.Mastery = append(run.report.Mastery, MasteryRegradeDiff{
+			UserID:     key.userID,
+			SyllabusID: syllabusID,
+			TopicID:    key.topicID,
+			Old:        current,
+			New:        score,
+		})
+		if !run.opts.Apply {
+			continue
+		}
+		if !canSet {
+			return fmt.Errorf("quiz regrade: tracker cannot set mastery")
+		}
+		if err := setter.SetMastery(key.userID, syllabusID, key.topicID, score); err != nil {
+			return fmt.Errorf("set mastery for %s/%s: %w", key.userID, key.topicID, err)
+		}
+		ForgetWarmSnapshot(ctx, run.warmCache, run.tenantID, key.userID)
+	}
+	return nil
+}
+
+// storedMasterySignals is the mastery signal each attempt at a question
+// gave: a miss for every answer before the one graded correct.
+func storedMasterySignals(question QuizResultQuestion) []float64 {
+	attempts := len(question.Answers)
+	if question.Correct && question.Score > 0 {
+		attempts = int(math.Round(1 / question.Score))
+	}
+	signals := make([]float64, 0, attempts)
+	asked := QuizQuestion{Difficulty: question.Difficulty}
+	for n := range attempts {
+		signals = append(signals, quizMasterySignal(asked, question.Correct && n == attempts-1))
+	}
+	return signals
+}
+
+// replayMastery is the score progress.Tracker.UpdateMastery reaches from
+// no progress after the given signals.
+func replayMastery(signals []float64) float64 {
+	score := 0.0
+	for i, signal := range signals {
+		if i == 0 {
+			score = signal
+			continue
+		}
+		score = score*0.7 + signal*0.3
+	}
+	return score
+}
+
+// WriteText writes the report for a terminal.
+func (r QuizRegradeReport) WriteText(w io.Writer) error {
+	mode := "dry run, nothing written"
+	if r.Applied {
+		mode = "applied"
+	}
+	var b strings.Builder
+	fmt.Fprintf(&b, "Quiz regrade (%s)\n", mode)
+	fmt.Fprintf(&b, "Results scanned: %d, changed: %d, questions skipped: %d\n", r.Scanned, r.Changed, r.SkippedQuestions)
+	if r.AICalls > 0 || r.CostCapReached {
+		fmt.Fprintf(&b, "AI grading calls: %d, cost: $%.4f", r.AICalls, r.CostUSD)
+		if r.CostCapReached {
+			b.WriteString(" (cost cap reached)")
+		}
+		b.WriteString("\n")
+	}
+	for _, diff := range r.Results {
+		fmt.Fprintf(&b, "\n%s %s %s %s: %d/%d (%.2f) -> %d/%d (%.2f)\n",
+			diff.ResultID, diff.UserID, diff.TopicID, diff.CompletedAt.Format(time.DateOnly),
+			diff.OldCorrect, diff.TotalQuestions, diff.OldScore,
+			diff.NewCorrect, diff.TotalQuestions, diff.NewScore)
+		for _, question := range diff.Questions {
+			fmt.Fprintf(&b, "  Q%d %s: %s (%.2f) -> %s (%.2f)\n",
+				question.Index+1, question.QuestionID,
+				correctLabel(question.OldCorrect), question.OldScore,
+				correctLabel(question.NewCorrect), question.NewScore)
+		}
+	}
+	if len(r.Mastery) > 0 {
+		b.WriteString("\nMastery\n")
+		for _, diff := range r.Mastery {
+			fmt.Fprintf(&b, "  %s %s/%s: %.2f -> %.2f\n", diff.UserID, diff.SyllabusID, diff.TopicID, diff.Old, diff.New)
+		}
+	}
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+func correctLabel(correct bool) string {
+	if correct {
+		return "correct"
+	}
+	return "wrong"
+}
@@ -0,0 +1,218 @@
+// Copyright 2026 the P&AI authors. All rights reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package agent_test
+
+import (
+	"bytes"
+	"context"
+	"math"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/p-n-ai/pai-bot/internal/agent"
+	"github.com/p-n-ai/pai-bot/internal/ai"
+	"github.com/p-n-ai/pai-bot/internal/curriculum"
+	"github.com/p-n-ai/pai-bot/internal/progress"
+)
+
+func createRegradeLoader(t *testing.T) *curriculum.Loader {
+	t.Helper()
+	dir := t.TempDir()
+	topicsDir := filepath.Join(dir, "curricula", "malaysia", "kssm", "topics", "algebra")
+	if err := os.MkdirAll(topicsDir, 0o755); err != nil {
+		t.Fatalf("MkdirAll() error = %v", err)
+	}
+	files := map[string]string{
+		"01-linear-equations.yaml": `id: F1-02
+name: Linear Equations
+subject_id: math
+syllabus_id: kssm-f1
+difficulty: beginner
+learning_objectives:
+  - id: LO1
+    text: Solve linear equations in one variable
+    bloom: apply
+`,
+		"01-linear-equations.assessments.yaml": `topic_id: F1-02
+provenance: human
+questions:
+  - id: Q1
+    text: "A pen costs RM 2.90. How much is it?"
+    difficulty: easy
+    learning_objective: LO1
+    answer:
+      type: numeric
+      value: "2.90"
+    marks: 1
+  - id: Q2
+    text: "What does it mean to solve an equation?"
+    difficulty: medium
+    learning_objective: LO1
+    answer:
+      type: free_text
+      value: "find the value of the unknown"
+    marks: 1
+`,
+	}
+	for name, data := range files {
+		if err := os.WriteFile(filepath.Join(topicsDir, name), []byte(data), 0o644); err != nil {
+			t.Fatalf("WriteFile(%s) error = %v", name, err)
+		}
+	}
+	loader, err := curriculum.NewLoader(dir)
+	if err != nil {
+		t.Fatalf("NewLoader() error = %v", err)
+	}
+	return loader
+}
+
+// seedRegradeResult stores a quiz graded by older rules: "RM 2.90" was
+// marked wrong before numeric answers accepted units.
+func seedRegradeResult(t *testing.T, store *agent.MemoryQuizResultStore) {
+	t.Helper()
+	err := store.SaveQuizResult(agent.QuizResult{
+		ConversationID: "conv-1",
+		UserID:         "learner-1",
+		TopicID:        "F1-02",
+		Questions: []agent.QuizResultQuestion{
+			{Index: 0, QuestionID: "Q1", Difficulty: "easy", Answers: []string{"RM 2.90"}},
+			{Index: 1, QuestionID: "gen-1-ab", Difficulty: "easy", Answers: []string{"7"}, Correct: true, Score: 1},
+		},
+		CorrectAnswers: 1,
+		TotalQuestions: 2,
+		Score:          0.5,
+		CompletedAt:    time.Date(2026, 9, 1, 10, 0, 0, 0, time.UTC),
+	})
+	if err != nil {
+		t.Fatalf("SaveQuizResult() error = %v", err)
+	}
+}
+
+func TestQuizRegrader_DryRunReportsWithoutWriting(t *testing.T) {
+	store := agent.NewMemoryQuizResultStore()
+	seedRegradeResult(t, store)
+	tracker := progress.NewMemoryTracker()
+	_ = tracker.SetMastery("learner-1", "kssm-f1", "F1-02", 0.4)
+
+	regrader := agent.NewQuizRegrader(agent.QuizRegraderConfig{
+		Results:          store,
+		CurriculumLoader: createRegradeLoader(t),
+		Tracker:          tracker,
+	})
+	report, err := regrader.Run(context.Background(), agent.QuizRegradeOptions{})
+	if err != nil {
+		t.Fatalf("Run() error = %v", err)
+	}
+	if report.Applied || report.Scanned != 1 || report.Changed != 1 || report.SkippedQuestions != 1 {
+		t.Fatalf("report = %+v", report)
+	}
+	diff := report.Results[0]
+	if diff.OldCorrect != 1 || diff.NewCorrect != 2 || diff.NewScore != 1 || len(diff.Questions) != 1 || diff.Questions[0].QuestionID != "Q1" {
+		t.Fatalf("diff = %+v", diff)
+	}
+	if len(report.Mastery) != 1 || report.Mastery[0].Old != 0.4 || report.Mastery[0].New <= 0.4 {
+		t.Fatalf("mastery = %+v", report.Mastery)
+	}
+
+	if got := store.Results()[0]; got.CorrectAnswers != 1 || got.Questions[0].Correct {
+		t.Fatalf("dry run rewrote the result: %+v", got)
+	}
+	if got, _ := tracker.GetMastery("learner-1", "kssm-f1", "F1-02"); got != 0.4 {
+		t.Fatalf("dry run changed mastery to %v", got)
+	}
+
+	var out bytes.Buffer
+	if err := report.WriteText(&out); err != nil {
+		t.Fatalf("WriteText() error = %v", err)
+	}
+	for _, want := range []string{"dry run", "1/2 (0.50) -> 2/2 (1.00)", "Q1 Q1: wrong (0.00) -> correct (1.00)", "learner-1 kssm-f1/F1-02: 0.40 ->"} {
+		if !strings.Contains(out.String(), want) {
+			t.Fatalf("report text missing %q:\n%s", want, out.String())
+		}
+	}
+}
+
+func TestQuizRegrader_ApplyRewritesResultsAndMastery(t *testing.T) {
+	store := agent.NewMemoryQuizResultStore()
+	seedRegradeResult(t, store)
+	tracker := progress.NewMemoryTracker()
+	_ = tracker.SetMastery("learner-1", "kssm-f1", "F1-02", 0.4)
+	warm := newMapWarmCache()
+	_ = warm.Set(context.Background(), "warm:tenant-1:learner-1", []byte(`{}`), time.Hour)
+	_ = warm.Set(context.Background(), "warm:tenant-1:learner-2", []byte(`{}`), time.Hour)
+
+	regrader := agent.NewQuizRegrader(agent.QuizRegraderConfig{
+		Results:          store,
+		CurriculumLoader: createRegradeLoader(t),
+		Tracker:          tracker,
+		WarmCache:        warm,
+		TenantID:         "tenant-1",
+	})
+	report, err := regrader.Run(context.Background(), agent.QuizRegradeOptions{Apply: true})
+	if err != nil {
+		t.Fatalf("Run() error = %v", err)
+	}
+	if _, kept := warm.values["warm:tenant-1:learner-1"]; kept || warm.len() != 1 {
+		t.Fatalf("warm snapshots = %v, want only the regraded learner's dropped", warm.values)
+	}
+
+	got := store.Results()[0]
+	if got.CorrectAnswers != 2 || got.Score != 1 || !got.Questions[0].Correct || got.Questions[0].Score != 1 {
+		t.Fatalf("result = %+v", got)
+	}
+	// The miss became an easy hit: 0.15 -> 0.65, then 0.65 seeds the blend.
+	mastery, _ := tracker.GetMastery("learner-1", "kssm-f1", "F1-02")
+	want := 0.4 + (0.65*0.7 + 0.65*0.3) - (0.15*0.7 + 0.65*0.3)
+	if math.Abs(mastery-want) > 1e-9 || report.Mastery[0].New != mastery {
+		t.Fatalf("mastery = %v, want %v", mastery, want)
+	}
+
+	again, err := regrader.Run(context.Background(), agent.QuizRegradeOptions{Apply: true})
+	if err != nil {
+		t.Fatalf("second Run() error = %v", err)
+	}
+	if again.Changed != 0 || len(again.Mastery) != 0 {
+		t.Fatalf("second run should find nothing to change: %+v", again)
+	}
+}
+
+func TestQuizRegrader_AIGradesRejectedFreeText(t *testing.T) {
+	store := agent.NewMemoryQuizResultStore()
+	if err := store.SaveQuizResult(agent.QuizResult{
+		ConversationID: "conv-2",
+		UserID:         "learner-2",
+		TopicID:        "F1-02",
+		Questions: []agent.QuizResultQuestion{
+			{Index: 0, QuestionID: "Q2", Difficulty: "medium", Answers: []string{"work out what x is", "no idea"}},
+		},
+		TotalQuestions: 1,
+		CompletedAt:    time.Now(),
+	}); err != nil {
+		t.Fatalf("SaveQuizResult() error = %v", err)
+	}
+	provider := ai.NewMockProvider("NO").Queue("YES")
+	regrader := agent.NewQuizRegrader(agent.QuizRegraderConfig{
+		Results:          store,
+		CurriculumLoader: createRegradeLoader(t),
+		AIRouter:         mockRouter(provider),
+	})
+
+	if _, err := regrader.Run(context.Background(), agent.QuizRegradeOptions{AIGrading: true}); err == nil {
+		t.Fatal("AI grading without a cost cap should fail")
+	}
+
+	report, err := regrader.Run(context.Background(), agent.QuizRegradeOptions{AIGrading: true, MaxCostUSD: 1, RatePerSecond: 1000})
+	if err != nil {
+		t.Fatalf("Run() error = %v", err)
+	}
+	if report.AICalls != 1 || report.Changed != 1 || report.Results[0].Questions[0].NewScore != 1 {
+		t.Fatalf("report = %+v", report)
+	}
+	if req := provider.LastRequest; req == nil || req.Task != ai.TaskGrading {
+		t.Fatalf("grading request = %+v", req)
+	}
+}
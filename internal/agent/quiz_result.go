@@ -0,0 +1,345 @@
+// Copyright 2026 the P&AI authors. All rights reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package agent
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// QuizResultSchemaVersion is carried on quiz_completed events so consumers
+// can tell the typed "result" payload apart from older free-form events.
+const QuizResultSchemaVersion = 1
+
+// QuizAnswerRecord is one answer attempt in a running quiz. The question's
+// ID and objective are copied in so the record survives later changes to the
+// generated question list.
+type QuizAnswerRecord struct {
+	QuestionIndex     int    `json:"question_index"`
+	QuestionID        string `json:"question_id,omitempty"`
+	LearningObjective string `json:"learning_objective,omitempty"`
+	Difficulty        string `json:"difficulty,omitempty"`
+	Answer            string `json:"answer"`
+	Correct           bool   `json:"correct"`
+}
+
+// QuizResult is the outcome of a completed quiz.
+type QuizResult struct {
+	// ID is set on results read back from a store.
+	ID             string               `json:"id,omitempty"`
+	ConversationID string               `json:"conversation_id"`
+	UserID         string               `json:"user_id"`
+	TopicID        string               `json:"topic_id"`
+	Intensity      string               `json:"intensity,omitempty"`
+	Difficulty     string               `json:"difficulty,omitempty"`
+	DailyProblemID string               `json:"daily_problem_id,omitempty"`
+	Questions      []QuizResultQuestion `json:"questions"`
+	CorrectAnswers int                  `json:"correct_answers"`
+	TotalQuestions int                  `json:"total_questions"`
+	// Score is the mean of the question scores, from 0 to 1.
+	Score        float64   `json:"score"`
+	ObjectiveIDs []string  `json:"objective_ids"`
+	CompletedAt  time.Time `json:"completed_at"`
+}
+
+// QuizResultQuestion is one question's answers and score. Score is 1 when
+// the first answer was right and 1/n when it took n attempts.
+type QuizResultQuestion struct {
+	Index             int      `json:"index"`
+	QuestionID        string   `json:"question_id,omitempty"`
+	LearningObjective string   `json:"learning_objective,omitempty"`
+	Difficulty        string   `json:"difficulty,omitempty"`
+	Answers           []string `json:"answers"`
+	Correct           bool     `json:"correct"`
+	Score             float64  `json:"score"`
+}
+
+// newQuizResult groups answers by question in the order asked.
+func newQuizResult(conversationID, userID string, state ConversationQuizState, difficulty string, answers []QuizAnswerRecord, completedAt time.Time) QuizResult {
+	result := QuizResult{
+		ConversationID: conversationID,
+		UserID:         userID,
+		TopicID:        state.TopicID,
+		Intensity:      state.Intensity,
+		Difficulty:     difficulty,
+		DailyProblemID: state.DailyProblemID,
+		Questions:      []QuizResultQuestion{},
+		ObjectiveIDs:   []string{},
+		CompletedAt:    completedAt,
+	}
+	byIndex := make(map[int]int)
+	for _, answer := range answers {
+		pos, ok := byIndex[answer.QuestionIndex]
+		if !ok {
+			pos = len(result.Questions)
+			byIndex[answer.QuestionIndex] = pos
+			result.Questions = append(result.Questions, QuizResultQuestion{
+				Index:             answer.QuestionIndex,
+				QuestionID:        answer.QuestionID,
+				LearningObjective: answer.LearningObjective,
+				Difficulty:        answer.Difficulty,
+			})
+			if answer.LearningObjective != "" && !slices.Contains(result.ObjectiveIDs, answer.LearningObjective) {
+				result.ObjectiveIDs = append(result.ObjectiveIDs, answer.LearningObjective)
+			}
+		}
+		question := &result.Questions[pos]
+		question.Answers = append(question.Answers, answer.Answer)
+		if answer.Correct && !question.Correct {
+			question.Correct = true
+			question.Score = 1 / float64(len(question.Answers))
+		}
+	}
+
+	total := 0.0
+	for _, question := range result.Questions {
+		if question.Correct {
+			result.CorrectAnswers++
+		}
+		total += question.Score
+	}
+	result.TotalQuestions = len(result.Questions)
+	if result.TotalQuestions > 0 {
+		result.Score = total / float64(result.TotalQuestions)
+	}
+	return result
+}
+
+// event is the quiz_completed event for r. The top-level counts stay for
+// existing dashboards; "result" carries the typed QuizResult.
+func (r QuizResult) event() Event {
+	return Event{
+		ConversationID: r.ConversationID,
+		UserID:         r.UserID,
+		EventType:      "quiz_completed",
+		Data: map[string]any{
+			"topic_id":        r.TopicID,
+			"correct_answers": r.CorrectAnswers,
+			"total_questions": r.TotalQuestions,
+			"schema_version":  QuizResultSchemaVersion,
+			"result":          r,
+		},
+		CreatedAt: r.CompletedAt,
+	}
+}
+
+// QuizResultStore keeps completed quiz results.
+type QuizResultStore interface {
+	SaveQuizResult(result QuizResult) error
+}
+
+// saveQuizResultAsync records a completed quiz and emits its event.
+func (e *Engine) saveQuizResultAsync(result QuizResult) {
+	e.logEventAsync(result.event())
+	if e.quizResults == nil || IsSandboxUser(result.UserID) {
+		return
+	}
+	go func() {
+		if err := e.quizResults.SaveQuizResult(result); err != nil {
+			slog.Warn("failed to save quiz result",
+				"conversation_id", result.ConversationID,
+				"user_id", result.UserID,
+				"topic_id", result.TopicID,
+				"error", err,
+			)
+		}
+	}()
+}
+
+// MemoryQuizResultStore is an in-memory QuizResultStore.
+type MemoryQuizResultStore struct {
+	mu      sync.Mutex
+	results []QuizResult
+}
+
+func NewMemoryQuizResultStore() *MemoryQuizResultStore {
+	return &MemoryQuizResultStore{}
+}
+
+func (s *MemoryQuizResultStore) SaveQuizResult(result QuizResult) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if result.ID == "" {
+		result.ID = generateID()
+	}
+	s.results = append(s.results, result)
+	return nil
+}
+
+func (s *MemoryQuizResultStore) ListQuizResults(_ context.Context, filter QuizResultFilter) ([]QuizResult, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	var out []QuizResult
+	for _, result := range s.results {
+		if filter.TopicID != "" && result.TopicID != filter.TopicID {
+			continue
+		}
+		if !filter.Since.IsZero() && result.CompletedAt.Before(filter.Since) {
+			continue
+		}
+		if filter.AfterID != "" && quizResultCompare(result, filter.AfterCompletedAt, filter.AfterID) <= 0 {
+			continue
+		}
+		out = append(out, result)
+	}
+	slices.SortFunc(out, func(a, b QuizResult) int {
+		return quizResultCompare(a, b.CompletedAt, b.ID)
+	})
+	if filter.Limit > 0 && len(out) > filter.Limit {
+		out = out[:filter.Limit]
+	}
+	return out, nil
+}
+
+func (s *MemoryQuizResultStore) UpdateQuizResult(_ context.Context, result QuizResult) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for i := range s.results {
+		if s.results[i].ID == result.ID {
+			s.results[i] = result
+			return nil
+		}
+	}
+	return fmt.Errorf("quiz result not found: %s", result.ID)
+}
+
+// quizResultCompare orders results by completion time, then ID.
+func quizResultCompare(result QuizResult, completedAt time.Time, id string) int {
+	if c := result.CompletedAt.Compare(completedAt); c != 0 {
+		return c
+	}
+	return strings.Compare(result.ID, id)
+}
+
+// Results returns the saved results in order.
+func (s *MemoryQuizResultStore) Results() []QuizResult {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]QuizResult(nil), s.results...)
+}
+
+// PostgresQuizResultStore writes results to quiz_results, taking the tenant
+// and learner from the conversation. Reading results back for re-grading
+// needs the tenant; see NewPostgresQuizResultHistory.
+type PostgresQuizResultStore struct {
+	pool     *pgxpool.Pool
+	tenantID string
+}
+
+func NewPostgresQuizResultStore(pool *pgxpool.Pool) *PostgresQuizResultStore {
+	return &PostgresQuizResultStore{pool: pool}
+}
+
+// NewPostgresQuizResultHistory returns a store that also lists and updates
+// tenantID's results.
+func NewPostgresQuizResultHistory(pool *pgxpool.Pool, tenantID string) *PostgresQuizResultStore {
+	return &PostgresQuizResultStore{pool: pool, tenantID: tenantID}
+}
+
+func (s *PostgresQuizResultStore) SaveQuizResult(result QuizResult) error {
+	questions, err := json.Marshal(result.Questions)
+	if err != nil {
+		return fmt.Errorf("marshal quiz result questions: %w", err)
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), dbTimeout)
+	defer cancel()
+
+	cmd, err := s.pool.Exec(ctx,
+		`INSERT INTO quiz_results (tenant_id, user_id, conversation_id, topic_id, intensity, difficulty,
+		     daily_problem_id, correct_answers, total_questions, score, objective_ids, questions, completed_at)
+		 SELECT c.tenant_id, c.user_id, c.id, $2, $3, $4, $5, $6, $7, $8, $9, $10::jsonb, $11
+		 FROM conversations c
+		 WHERE c.id = $1::uuid`,
+		result.ConversationID, result.TopicID, result.Intensity, result.Difficulty, result.DailyProblemID,
+		result.CorrectAnswers, result.TotalQuestions, result.Score, result.ObjectiveIDs, string(questions), result.CompletedAt,
+	)
+	if err != nil {
+		return fmt.Errorf("insert quiz result: %w", err)
+	}
+	if cmd.RowsAffected() == 0 {
+		return fmt.Errorf("conversation not found: %s", result.ConversationID)
+	}
+	return nil
+}
+
+// ListQuizResults reads the tenant's stored results, with the learner's
+// external ID as UserID.
+func (s *PostgresQuizResultStore) ListQuizResults(ctx context.Context, filter QuizResultFilter) ([]QuizResult, error) {
+	if s.tenantID == "" {
+		return nil, fmt.Errorf("quiz result store has no tenant")
+	}
+	limit := filter.Limit
+	if limit <= 0 {
+		limit = quizRegradeBatchSize
+	}
+	var since, after *time.Time
+	if !filter.Since.IsZero() {
+		since = &filter.Since
+	}
+	if filter.AfterID != "" {
+		after = &filter.AfterCompletedAt
+	}
+	rows, err := s.pool.Query(ctx,
+		`SELECT q.id::text, q.conversation_id::text, u.external_id, q.topic_id, q.intensity, q.difficulty,
+		        q.daily_problem_id, q.correct_answers, q.total_questions, q.score, q.objective_ids, q.questions, q.completed_at
+		 FROM quiz_results q
+		 JOIN users u ON u.id = q.user_id
+		 WHERE q.tenant_id = $1::uuid
+		   AND ($2 = '' OR q.topic_id = $2)
+		   AND ($3::timestamptz IS NULL OR q.completed_at >= $3)
+		   AND ($4::timestamptz IS NULL OR (q.completed_at, q.id::text) > ($4, $5))
+		 ORDER BY q.completed_at, q.id::text
+		 LIMIT $6`,
+		s.tenantID, filter.TopicID, since, after, filter.AfterID, limit,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("list quiz results: %w", err)
+	}
+	defer rows.Close()
+
+	var out []QuizResult
+	for rows.Next() {
+		var result QuizResult
+		var questions []byte
+		if err := rows.Scan(&result.ID, &result.ConversationID, &result.UserID, &result.TopicID, &result.Intensity,
+			&result.Difficulty, &result.DailyProblemID, &result.CorrectAnswers, &result.TotalQuestions, &result.Score,
+			&result.ObjectiveIDs, &questions, &result.CompletedAt); err != nil {
+			return nil, fmt.Errorf("scan quiz result: %w", err)
+		}
+		if err := json.Unmarshal(questions, &result.Questions); err != nil {
+			return nil, fmt.Errorf("decode quiz result %s questions: %w", result.ID, err)
+		}
+		out = append(out, result)
+	}
+	return out, rows.Err()
+}
+
+// UpdateQuizResult rewrites a stored result's grades.
+func (s *PostgresQuizResultStore) UpdateQuizResult(ctx context.Context, result QuizResult) error {
+	questions, err := json.Marshal(result.Questions)
+	if err != nil {
+		return fmt.Errorf("marshal quiz result questions: %w", err)
+	}
+	cmd, err := s.pool.Exec(ctx,
+		`UPDATE quiz_results
+		 SET correct_answers = $3, total_questions = $4, score = $5, questions = $6::jsonb
+		 WHERE id = $1::uuid AND tenant_id = $2::uuid`,
+		result.ID, s.tenantID, result.CorrectAnswers, result.TotalQuestions, result.Score, string(questions),
+	)
+	if err != nil {
+		return fmt.Errorf("update quiz result: %w", err)
+	}
+	if cmd.RowsAffected() == 0 {
+		return fmt.Errorf("quiz result not found: %s", result.ID)
+	}
+	return nil
+}
@@ -9,8 +9,10 @@ import (
 	"log/slog"
 	"sort"
 	"strings"
+	"time"
 
 	"github.com/p-n-ai/pai-bot/internal/chat"
+	"github.com/p-n-ai/pai-bot/internal/curriculum"
 	"github.com/p-n-ai/pai-bot/internal/i18n"
 )
 
@@ -120,15 +122,18 @@ func (e *Engine) startQuizWithIntensity(msg chat.InboundMessage, conv *Conversat
 		}
 	}
 
-	questions := filterQuizQuestionsByIntensity(questionsFromAssessment(assessment), intensity)
+	allQuestions := questionsFromAssessment(assessment)
+	questions := filterQuizQuestionsByIntensity(allQuestions, intensity)
 	session := NewQuizSession(msg.UserID, topicID, questions)
 	session.Intensity = normalizeQuizIntensity(intensity)
+	session.Difficulty = startingQuizDifficulty(quizDifficultyLevels(allQuestions), intensity)
 	if err := e.store.UpdateConversationQuizState(conv.ID, conversationStateQuizActive, ConversationQuizState{
 		TopicID:        topicID,
 		Intensity:      session.Intensity,
 		CurrentIndex:   session.CurrentIndex,
 		CorrectAnswers: session.CorrectAnswers,
 		RunState:       defaultQuizRunState(),
+		Difficulty:     session.Difficulty,
 	}); err != nil {
 		slog.Error("failed to persist quiz state", "conversation_id", conv.ID, "error", err)
 		return i18n.S(e.messageLocale(msg, conv), i18n.MsgTechnicalIssue)
@@ -136,10 +141,7 @@ func (e *Engine) startQuizWithIntensity(msg chat.InboundMessage, conv *Conversat
 
 	question, _ := session.NextQuestion()
 	response := renderQuizQuestion(e.lookupTopicName(topicID), session, question)
-	if _, err := e.store.AddMessage(conv.ID, StoredMessage{
-		Role:    "assistant",
-		Content: response,
-	}); err != nil {
+	if _, err := e.store.AddMessage(conv.ID, quizQuestionMessage(response, topicID, question)); err != nil {
 		slog.Error("failed to store quiz prompt", "conversation_id", conv.ID, "error", err)
 	}
 	e.logEventAsync(Event{
@@ -192,8 +194,8 @@ func (e *Engine) handleQuizIntensitySelection(msg chat.InboundMessage, conv *Con
 	return e.startQuizWithIntensity(msg, conv, topicID, intensity, false)
 }
 
-func (e *Engine) maybeGenerateQuizQuestions(ctx context.Context, session *QuizSession) {
-	if e.aiRouter == nil || !e.aiRouter.HasProvider() || e.curriculumLoader == nil {
+func (e *Engine) maybeGenerateQuizQuestions(ctx context.Context, session *QuizSession, locale string) {
+	if e.curriculumLoader == nil {
 		return
 	}
 	remaining := QuizMaxQuestions - len(session.Questions)
@@ -208,42 +210,98 @@ func (e *Engine) maybeGenerateQuizQuestions(ctx context.Context, session *QuizSe
 	if !ok {
 		return
 	}
+	// Template word problems carry numbers checked against their own story;
+	// the AI writes the rest of the batch.
+	difficulty := session.generationDifficulty()
+	questions := wordProblemQuestions(topic.Name, difficulty, locale, min(quizWordProblemsPerBatch, n))
+	if generated := e.generateQuizQuestions(ctx, session, topic, n-len(questions)); len(generated) > 0 {
+		questions = append(questions, generated...)
+	}
+	questions = append(questions, wordProblemQuestions(topic.Name, difficulty, locale, n-len(questions))...)
+	session.AppendQuestions(questions)
+}
+
+// generateQuizQuestions asks the AI for n questions in the style of the
+// topic's assessment. It returns nil when no provider is configured or
+// generation fails.
+func (e *Engine) generateQuizQuestions(ctx context.Context, session *QuizSession, topic curriculum.Topic, n int) []QuizQuestion {
+	if n <= 0 || e.aiRouter == nil || !e.aiRouter.HasProvider() {
+		return nil
+	}
 	teachingNotes, _ := e.curriculumLoader.GetTeachingNotes(session.TopicID)
 	assessment, ok := e.curriculumLoader.GetAssessment(session.TopicID)
 	if !ok {
-		return
+		return nil
 	}
 	allStatic := questionsFromAssessment(assessment)
 
-	gen := quizQuestionGenerator{aiRouter: e.aiRouter}
-	questions, err := gen.Generate(ctx, quizGenerateInput{
+	input := quizGenerateInput{
 		TopicID:       session.TopicID,
 		TopicName:     topic.Name,
 		SyllabusID:    topic.SyllabusID,
-		Intensity:     session.Intensity,
+		Intensity:     session.generationDifficulty(),
 		N:             n,
 		TeachingNotes: teachingNotes,
 		AllQuestions:  allStatic,
-	})
+	}
+	if sitting, ok := e.revisionExam(session.UserID, time.Now()); ok {
+		input.PastPaperExam = sitting.Exam
+	}
+	gen := quizQuestionGenerator{aiRouter: e.aiRouter}
+	questions, err := gen.Generate(ctx, input)
 	if err != nil {
 		slog.Warn("quiz question generation failed", "topic_id", session.TopicID, "error", err)
-		return
+		return nil
 	}
-	session.AppendQuestions(questions)
+	return questions
+}
+
+// quizSessionFromState rebuilds the session a conversation's persisted quiz
+// state describes. It fails when the topic's assessment is no longer loaded.
+func (e *Engine) quizSessionFromState(userID string, state ConversationQuizState) (*QuizSession, bool) {
+	var questions []QuizQuestion
+	if state.DailyProblemID == "" {
+		if e.curriculumLoader == nil {
+			return nil, false
+		}
+		assessment, ok := e.curriculumLoader.GetAssessment(state.TopicID)
+		if !ok {
+			return nil, false
+		}
+		questions = filterQuizQuestionsByIntensity(questionsFromAssessment(assessment), state.Intensity)
+	}
+	session := NewQuizSession(userID, state.TopicID, questions)
+	session.Intensity = state.Intensity
+	session.CurrentIndex = state.CurrentIndex
+	session.CorrectAnswers = state.CorrectAnswers
+	session.Difficulty = state.Difficulty
+	session.HitStreak = state.HitStreak
+	session.MissStreak = state.MissStreak
+	if len(state.GeneratedQuestions) > 0 {
+		session.AppendQuestions(state.GeneratedQuestions)
+	}
+	return session, true
 }
 
 func (e *Engine) handleActiveQuizTurn(ctx context.Context, msg chat.InboundMessage, conv *Conversation, state ConversationQuizState) (string, bool) {
-	assessment, ok := e.curriculumLoader.GetAssessment(state.TopicID)
-	if !ok {
-		_ = e.store.ClearConversationQuizState(conv.ID, conversationStateTeaching)
-		return quizUnavailableText(e.messageLocale(msg, conv)), true
+	var assessmentQuestions []QuizQuestion
+	if state.DailyProblemID == "" {
+		assessment, ok := e.curriculumLoader.GetAssessment(state.TopicID)
+		if !ok {
+			_ = e.store.ClearConversationQuizState(conv.ID, conversationStateTeaching)
+			return quizUnavailableText(e.messageLocale(msg, conv)), true
+		}
+		assessmentQuestions = questionsFromAssessment(assessment)
 	}
 
-	questions := filterQuizQuestionsByIntensity(questionsFromAssessment(assessment), state.Intensity)
+	questions := filterQuizQuestionsByIntensity(assessmentQuestions, state.Intensity)
 	session := NewQuizSession(msg.UserID, state.TopicID, questions)
 	session.Intensity = state.Intensity
 	session.CurrentIndex = state.CurrentIndex
 	session.CorrectAnswers = state.CorrectAnswers
+	session.Difficulty = state.Difficulty
+	session.HitStreak = state.HitStreak
+	session.MissStreak = state.MissStreak
 	if len(state.GeneratedQuestions) > 0 {
 		session.AppendQuestions(state.GeneratedQuestions)
 	}
@@ -284,7 +342,7 @@ func (e *Engine) handleActiveQuizTurn(ctx context.Context, msg chat.InboundMessa
 		return response, true
 	case quizTurnActionRepeat, quizTurnActionShowQuestion:
 		response := renderQuizQuestion(e.lookupTopicName(state.TopicID), session, question)
-		if _, err := e.store.AddMessage(conv.ID, StoredMessage{Role: "assistant", Content: response}); err != nil {
+		if _, err := e.store.AddMessage(conv.ID, quizQuestionMessage(response, state.TopicID, question)); err != nil {
 			slog.Error("failed to store quiz repeat response", "conversation_id", conv.ID, "error", err)
 		}
 		return response, true
@@ -313,8 +371,53 @@ func (e *Engine) handleActiveQuizTurn(ctx context.Context, msg chat.InboundMessa
 	}
 
 	result := session.SubmitAnswer(answerText)
+	answers := append(append([]QuizAnswerRecord(nil), state.Answers...), QuizAnswerRecord{
+		QuestionIndex:     state.CurrentIndex,
+		QuestionID:        question.ID,
+		LearningObjective: question.LearningObjective,
+		Difficulty:        question.Difficulty,
+		Answer:            answerText,
+		Correct:           result.Correct,
+	})
 	e.recordQuizOutcomeAsync(msg.UserID, state.TopicID, quizInputSource(msg), question, result.Correct)
+	if state.DailyProblemID != "" {
+		e.recordDailyProblemAnswer(msg, conv, state, result.Correct)
+	}
+	staticCount := len(questions)
+	previousDifficulty := session.Difficulty
+	if state.DailyProblemID == "" && session.adaptDifficulty(quizDifficultyLevels(assessmentQuestions), result.Correct) {
+		session.dropStaleGeneratedQuestions(staticCount, !result.Correct)
+		e.logEventAsync(Event{
+			ConversationID: conv.ID,
+			UserID:         msg.UserID,
+			EventType:      "quiz_difficulty_adapted",
+			Data: map[string]any{
+				"topic_id":       state.TopicID,
+				"question_index": state.CurrentIndex,
+				"from":           previousDifficulty,
+				"to":             session.Difficulty,
+			},
+		})
+	}
+	nextState := ConversationQuizState{
+		TopicID:        state.TopicID,
+		Intensity:      state.Intensity,
+		CurrentIndex:   session.CurrentIndex,
+		CorrectAnswers: session.CorrectAnswers,
+		RunState:       defaultQuizRunState(),
+		Difficulty:     session.Difficulty,
+		HitStreak:      session.HitStreak,
+		MissStreak:     session.MissStreak,
+		DailyProblemID: state.DailyProblemID,
+		Answers:        answers,
+	}
 	if !result.Correct {
+		if len(session.Questions) > staticCount {
+			nextState.GeneratedQuestions = session.Questions[staticCount:]
+		}
+		if err := e.store.UpdateConversationQuizState(conv.ID, conversationStateQuizActive, nextState); err != nil {
+			slog.Error("failed to update quiz state", "conversation_id", conv.ID, "error", err)
+		}
 		response := renderQuizRetry(e.messageLocale(msg, conv), result)
 		if _, err := e.store.AddMessage(conv.ID, StoredMessage{
 			Role:    "assistant",
@@ -334,19 +437,21 @@ func (e *Engine) handleActiveQuizTurn(ctx context.Context, msg chat.InboundMessa
 		})
 		return response, true
 	}
+	e.logEventAsync(Event{
+		ConversationID: conv.ID,
+		UserID:         msg.UserID,
+		EventType:      "quiz_answer_correct",
+		Data: map[string]any{
+			"topic_id":         state.TopicID,
+			"question_index":   state.CurrentIndex,
+			"answer_transport": quizInputSource(msg),
+		},
+	})
 
-	if session.IsComplete() && len(session.Questions) < QuizMaxQuestions {
-		e.maybeGenerateQuizQuestions(ctx, session)
+	if session.IsComplete() && state.DailyProblemID == "" && len(session.Questions) < QuizMaxQuestions {
+		e.maybeGenerateQuizQuestions(ctx, session, e.messageLocale(msg, conv))
 	}
 
-	nextState := ConversationQuizState{
-		TopicID:        state.TopicID,
-		Intensity:      state.Intensity,
-		CurrentIndex:   session.CurrentIndex,
-		CorrectAnswers: session.CorrectAnswers,
-		RunState:       defaultQuizRunState(),
-	}
-	staticCount := len(filterQuizQuestionsByIntensity(questionsFromAssessment(assessment), state.Intensity))
 	if len(session.Questions) > staticCount {
 		nextState.GeneratedQuestions = session.Questions[staticCount:]
 	}
@@ -356,17 +461,15 @@ func (e *Engine) handleActiveQuizTurn(ctx context.Context, msg chat.InboundMessa
 		if err := e.store.ClearConversationQuizState(conv.ID, conversationStateTeaching); err != nil {
 			slog.Error("failed to restore teaching state after quiz", "conversation_id", conv.ID, "error", err)
 		}
-		response = renderQuizCompletion(e.messageLocale(msg, conv), result, session.Summary())
-		e.logEventAsync(Event{
-			ConversationID: conv.ID,
-			UserID:         msg.UserID,
-			EventType:      "quiz_completed",
-			Data: map[string]any{
-				"topic_id":        state.TopicID,
-				"correct_answers": session.CorrectAnswers,
-				"total_questions": len(session.Questions),
-			},
-		})
+		locale := e.messageLocale(msg, conv)
+		response = renderQuizCompletion(locale, result, session.Summary())
+		if state.DailyProblemID != "" {
+			response = result.Feedback + "\n\n" + i18n.S(locale, i18n.MsgDailyProblemSolved)
+		}
+		if next := e.sessionEndNextTopic(msg.UserID, locale, state.TopicID); next != "" {
+			response += "\n\n" + next
+		}
+		e.saveQuizResultAsync(newQuizResult(conv.ID, msg.UserID, state, session.Difficulty, answers, time.Now()))
 	} else {
 		if err := e.store.UpdateConversationQuizState(conv.ID, conversationStateQuizActive, nextState); err != nil {
 			slog.Error("failed to update quiz state", "conversation_id", conv.ID, "error", err)
@@ -412,20 +515,11 @@ func (e *Engine) handlePausedQuizTurn(ctx context.Context, msg chat.InboundMessa
 }
 
 func (e *Engine) resumePausedQuizTurn(_ context.Context, msg chat.InboundMessage, conv *Conversation, state ConversationQuizState, action quizTurnAction) string {
-	assessment, ok := e.curriculumLoader.GetAssessment(state.TopicID)
+	session, ok := e.quizSessionFromState(msg.UserID, state)
 	if !ok {
 		_ = e.store.ClearConversationQuizState(conv.ID, conversationStateTeaching)
 		return quizUnavailableText(e.messageLocale(msg, conv))
 	}
-
-	questions := filterQuizQuestionsByIntensity(questionsFromAssessment(assessment), state.Intensity)
-	session := NewQuizSession(msg.UserID, state.TopicID, questions)
-	session.Intensity = state.Intensity
-	session.CurrentIndex = state.CurrentIndex
-	session.CorrectAnswers = state.CorrectAnswers
-	if len(state.GeneratedQuestions) > 0 {
-		session.AppendQuestions(state.GeneratedQuestions)
-	}
 	question, hasQuestion := session.NextQuestion()
 	if !hasQuestion {
 		_ = e.store.ClearConversationQuizState(conv.ID, conversationStateTeaching)
@@ -632,6 +726,20 @@ func containsAny(text string, parts ...string) bool {
 	return false
 }
 
+// quizQuestionMessage stores a rendered question with a reference back to the
+// assessment item so history and exports can tell which question was asked.
+func quizQuestionMessage(response, topicID string, question QuizQuestion) StoredMessage {
+	return StoredMessage{
+		Role:    "assistant",
+		Content: response,
+		Kind:    MessageKindQuiz,
+		Attachments: []MessageAttachment{{
+			Type:      string(MessageKindQuiz),
+			Reference: topicID + "/" + question.ID,
+		}},
+	}
+}
+
 func renderQuizQuestion(topicName string, session *QuizSession, question QuizQuestion) string {
 	var builder strings.Builder
 	if topicName != "" {
@@ -5,6 +5,7 @@ package agent_test
 
 import (
 	"context"
+	"strings"
 	"testing"
 	"time"
 
@@ -918,3 +919,133 @@ func TestEngine_ProcessMessage_DontGetItPausesQuizForTeaching(t *testing.T) {
 		t.Fatalf("QuizState = %#v, want paused teaching detour", conv.QuizState)
 	}
 }
+
+func TestEngine_ProcessMessage_QuizEasesDifficultyAfterTwoMisses(t *testing.T) {
+	store := agent.NewMemoryStore()
+	if err := store.SetUserPreferredQuizIntensity("quiz-adapt", "mixed"); err != nil {
+		t.Fatalf("SetUserPreferredQuizIntensity() error = %v", err)
+	}
+	engine := agent.NewEngine(agent.EngineConfig{
+		AIRouter:         mockRouter(ai.NewMockProvider("should-not-be-used")),
+		Store:            store,
+		CurriculumLoader: createTestCurriculumLoader(t),
+	})
+
+	for _, text := range []string{"quiz me on linear equations", "10", "11"} {
+		if _, err := engine.ProcessMessage(context.Background(), chat.InboundMessage{Channel: "telegram", UserID: "quiz-adapt", Text: text}); err != nil {
+			t.Fatalf("ProcessMessage(%q) error = %v", text, err)
+		}
+		conv, _ := store.GetActiveConversation("quiz-adapt")
+		if text == "10" && (conv.QuizState.Difficulty != "medium" || conv.QuizState.MissStreak != 1) {
+			t.Fatalf("after one miss QuizState = %#v, want medium with a miss streak of 1", conv.QuizState)
+		}
+	}
+
+	conv, _ := store.GetActiveConversation("quiz-adapt")
+	if conv.QuizState == nil || conv.QuizState.Difficulty != "easy" || conv.QuizState.MissStreak != 0 {
+		t.Fatalf("QuizState = %#v, want difficulty eased to easy", conv.QuizState)
+	}
+}
+
+func TestEngine_ProcessMessage_QuizTopsUpWithWordProblemsWithoutAI(t *testing.T) {
+	store := agent.NewMemoryStore()
+	if err := store.SetUserPreferredQuizIntensity("quiz-words", "easy"); err != nil {
+		t.Fatalf("SetUserPreferredQuizIntensity() error = %v", err)
+	}
+	engine := agent.NewEngine(agent.EngineConfig{
+		AIRouter:         mockRouter(ai.NewMockProvider("not json")),
+		Store:            store,
+		CurriculumLoader: createTestCurriculumLoader(t),
+	})
+
+	for _, text := range []string{"quiz me on linear equations", "4"} {
+		if _, err := engine.ProcessMessage(context.Background(), chat.InboundMessage{Channel: "telegram", UserID: "quiz-words", Text: text, Language: "en"}); err != nil {
+			t.Fatalf("ProcessMessage(%q) error = %v", text, err)
+		}
+	}
+	conv, _ := store.GetActiveConversation("quiz-words")
+	if conv.QuizState == nil || len(conv.QuizState.GeneratedQuestions) != 3 {
+		t.Fatalf("QuizState = %#v, want three word problems queued", conv.QuizState)
+	}
+	next := conv.QuizState.GeneratedQuestions[0]
+	if !strings.HasPrefix(next.ID, "word-linear_price-") || next.Difficulty != "easy" {
+		t.Fatalf("next question = %+v, want an easy linear_price word problem", next)
+	}
+
+	resp, err := engine.ProcessMessage(context.Background(), chat.InboundMessage{Channel: "telegram", UserID: "quiz-words", Text: "RM " + next.Answer, Language: "en"})
+	if err != nil {
+		t.Fatalf("ProcessMessage(answer) error = %v", err)
+	}
+	if !strings.Contains(resp, "Question 3/4") {
+		t.Fatalf("response = %q, want the word problem accepted", resp)
+	}
+}
+
+func TestEngine_ProcessMessage_CompletedQuizSavesTypedResult(t *testing.T) {
+	mockAI := ai.NewMockProvider("should-not-be-used")
+	store := agent.NewMemoryStore()
+	results := agent.NewMemoryQuizResultStore()
+	events := agent.NewMemoryEventLogger()
+	if err := store.SetUserPreferredQuizIntensity("quiz-result-user", "mixed"); err != nil {
+		t.Fatalf("SetUserPreferredQuizIntensity() error = %v", err)
+	}
+	engine := agent.NewEngine(agent.EngineConfig{
+		AIRouter:         mockRouter(mockAI),
+		Store:            store,
+		EventLogger:      events,
+		CurriculumLoader: createTestCurriculumLoader(t),
+		QuizResults:      results,
+	})
+
+	send := func(text string) {
+		t.Helper()
+		if _, err := engine.ProcessMessage(context.Background(), chat.InboundMessage{
+			Channel: "telegram",
+			UserID:  "quiz-result-user",
+			Text:    text,
+		}); err != nil {
+			t.Fatalf("ProcessMessage(%q) error = %v", text, err)
+		}
+	}
+	for _, text := range []string{"give me a quiz on linear equations", "10", "4", "4+3=7", "19"} {
+		send(text)
+	}
+	// The quiz tops up with word problems; answer them from the stored state.
+	for range 10 {
+		conv, ok := store.GetActiveConversation("quiz-result-user")
+		if !ok || conv.QuizState == nil {
+			break
+		}
+		send(conv.QuizState.GeneratedQuestions[conv.QuizState.CurrentIndex-3].Answer)
+	}
+	time.Sleep(100 * time.Millisecond)
+
+	saved := results.Results()
+	if len(saved) != 1 {
+		t.Fatalf("saved results = %d, want 1", len(saved))
+	}
+	result := saved[0]
+	if result.TopicID != "F1-02" || result.TotalQuestions < 3 || result.CorrectAnswers != result.TotalQuestions {
+		t.Fatalf("result = %+v, want every F1-02 question answered", result)
+	}
+	first := result.Questions[0]
+	if first.QuestionID != "Q1" || first.LearningObjective != "LO1" || len(first.Answers) != 2 || first.Score != 0.5 {
+		t.Fatalf("first question = %+v, want Q1/LO1 answered twice for half credit", first)
+	}
+	if want := (float64(result.TotalQuestions) - 0.5) / float64(result.TotalQuestions); result.Score != want || result.ObjectiveIDs[0] != "LO1" {
+		t.Fatalf("score = %v, objectives = %v; want %v starting with LO1", result.Score, result.ObjectiveIDs, want)
+	}
+
+	var completed *agent.Event
+	for _, event := range events.Events() {
+		if event.EventType == "quiz_completed" {
+			completed = &event
+		}
+	}
+	if completed == nil {
+		t.Fatal("expected quiz_completed event")
+	}
+	if typed, ok := completed.Data["result"].(agent.QuizResult); !ok || typed.Score != result.Score || completed.Data["schema_version"] != agent.QuizResultSchemaVersion {
+		t.Fatalf("quiz_completed data = %#v, want the typed result", completed.Data)
+	}
+}
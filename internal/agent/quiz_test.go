@@ -156,6 +156,20 @@ func TestQuizSession_SubmitAnswer_StructuredAnswersAllowEquivalentFormatting(t *
 	}
 }
 
+func TestGradeQuizAnswer_NumericIgnoresUnitsAndTrailingZeros(t *testing.T) {
+	question := QuizQuestion{AnswerType: "numeric", Answer: "2.90"}
+	for _, answer := range []string{"2.90", "2.9", "RM2.90", "RM 2.9", "x = 2.90"} {
+		if !gradeQuizAnswer(question, answer) {
+			t.Errorf("gradeQuizAnswer(%q) = false, want true", answer)
+		}
+	}
+	for _, answer := range []string{"2.09", "29", "2.90 or 3", "about RM3"} {
+		if gradeQuizAnswer(question, answer) {
+			t.Errorf("gradeQuizAnswer(%q) = true, want false", answer)
+		}
+	}
+}
+
 func TestQuizSession_SubmitAnswer_StructuredAnswersRejectContradictoryExtraParts(t *testing.T) {
 	session := NewQuizSession("user-1", "F3-09", []QuizQuestion{
 		{
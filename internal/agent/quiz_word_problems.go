@@ -0,0 +1,50 @@
+// Copyright 2026 the P&AI authors. All rights reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package agent
+
+import (
+	"crypto/rand"
+	"fmt"
+	mathrand "math/rand/v2"
+
+	"github.com/p-n-ai/pai-bot/internal/wordproblem"
+)
+
+// quizWordProblemsPerBatch is how many questions in each generated batch come
+// from word-problem templates when the AI can write the rest. Templates fill
+// the whole batch when it cannot.
+const quizWordProblemsPerBatch = 1
+
+// wordProblemQuestions draws up to n template word problems for the topic,
+// numbered and graded numerically so "RM2.90" and "2.9" both pass.
+func wordProblemQuestions(topicName, difficulty, locale string, n int) []QuizQuestion {
+	if n <= 0 {
+		return nil
+	}
+	switch difficulty = normalizeQuizIntensity(difficulty); difficulty {
+	case "easy", "medium", "hard":
+	default:
+		difficulty = "medium"
+	}
+	rng := mathrand.New(mathrand.NewPCG(mathrand.Uint64(), mathrand.Uint64()))
+	problems := wordproblem.Generate(rng, topicName, difficulty, locale, n)
+	questions := make([]QuizQuestion, 0, len(problems))
+	for i, p := range problems {
+		b := make([]byte, 4)
+		_, _ = rand.Read(b)
+		questions = append(questions, QuizQuestion{
+			ID:         fmt.Sprintf("word-%s-%d-%x", p.Template, i+1, b),
+			Text:       p.Text,
+			Difficulty: difficulty,
+			AnswerType: "numeric",
+			Answer:     p.Answer,
+			Working:    p.Working,
+			Hints: []QuizHint{
+				{Level: 1, Text: p.Hints[0]},
+				{Level: 2, Text: p.Hints[1]},
+			},
+		})
+	}
+	return questions
+}
@@ -0,0 +1,30 @@
+// Copyright 2026 the P&AI authors. All rights reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package agent
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestWordProblemQuestionsGradeTheirOwnAnswers(t *testing.T) {
+	questions := wordProblemQuestions("Linear Equations", "mixed", "en", 2)
+	if len(questions) != 2 {
+		t.Fatalf("questions = %d, want 2", len(questions))
+	}
+	for _, q := range questions {
+		if !strings.HasPrefix(q.ID, "word-linear_price-") || q.AnswerType != "numeric" || q.Difficulty != "medium" || len(q.Hints) != 2 {
+			t.Fatalf("question = %+v", q)
+		}
+		if !gradeQuizAnswer(q, "RM"+q.Answer) {
+			t.Fatalf("RM%s should be accepted for %q", q.Answer, q.Text)
+		}
+	}
+}
+
+func TestWordProblemQuestionsSkipTopicsWithoutTemplates(t *testing.T) {
+	if got := wordProblemQuestions("Lines and Angles", "easy", "ms", 3); len(got) != 0 {
+		t.Fatalf("questions = %+v, want none", got)
+	}
+}
@@ -0,0 +1,59 @@
+// Copyright 2026 the P&AI authors. All rights reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package agent
+
+import (
+	"github.com/p-n-ai/pai-bot/internal/chat"
+)
+
+// Reaction ratings sit at the ends of the 1-5 answer_rating_submitted scale so
+// reaction feedback averages alongside explicit ratings.
+const (
+	reactionRatingUp   = 5
+	reactionRatingDown = 1
+)
+
+// recordReactionFeedback turns a thumbs reaction on a bot answer into an
+// answer_rating_submitted event for the stored assistant message. Reactions on
+// messages that were never linked to a stored answer are dropped.
+func (e *Engine) recordReactionFeedback(msg chat.InboundMessage) {
+	rating := 0
+	switch msg.Reaction {
+	case chat.ReactionThumbsUp:
+		rating = reactionRatingUp
+	case chat.ReactionThumbsDown:
+		rating = reactionRatingDown
+	default:
+		return
+	}
+
+	conv, ok := e.store.GetActiveConversation(msg.UserID)
+	if !ok {
+		return
+	}
+	stored, _, found := findMessageByExternalID(conv, msg.MessageID)
+	if !found || stored.Role != "assistant" {
+		return
+	}
+
+	data := map[string]any{
+		"rating":           rating,
+		"rated_message_id": stored.ID,
+		"source":           "reaction",
+		"reaction":         msg.Reaction,
+		"channel":          msg.Channel,
+	}
+	if stored.Model != "" {
+		data["model"] = stored.Model
+	}
+	if group, ok := e.store.GetUserABGroup(msg.UserID); ok {
+		data["ab_group"] = group
+	}
+	e.logEventAsync(Event{
+		ConversationID: conv.ID,
+		UserID:         msg.UserID,
+		EventType:      "answer_rating_submitted",
+		Data:           data,
+	})
+}
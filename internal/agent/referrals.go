@@ -0,0 +1,375 @@
+// Copyright 2026 the P&AI authors. All rights reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package agent
+
+import (
+	"context"
+	"crypto/rand"
+	"errors"
+	"fmt"
+	"log/slog"
+	"sync"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"github.com/p-n-ai/pai-bot/internal/apperr"
+	"github.com/p-n-ai/pai-bot/internal/chat"
+	"github.com/p-n-ai/pai-bot/internal/i18n"
+	"github.com/p-n-ai/pai-bot/internal/progress"
+)
+
+// referralPayloadPrefix marks a /start payload as a referral code, as in the
+// deep link t.me/<bot>?start=ref_<code>.
+const referralPayloadPrefix = "ref_"
+
+// referralCodeAlphabet avoids characters that are easy to misread, and fits
+// Telegram's start parameter charset.
+const (
+	referralCodeAlphabet = "abcdefghjkmnpqrstuvwxyz23456789"
+	referralCodeLength   = 8
+)
+
+var (
+	// ErrReferralCodeInvalid means no learner owns the code.
+	ErrReferralCodeInvalid = apperr.UserInput(errors.New("referral code not found"))
+	// ErrSelfReferral means a learner followed their own invite link.
+	ErrSelfReferral = apperr.UserInput(errors.New("learner used their own referral code"))
+)
+
+// ReferralStats counts the learners one learner has invited.
+type ReferralStats struct {
+	// Joined is how many new learners started through the learner's link.
+	Joined int
+	// Activated is how many of them finished onboarding.
+	Activated int
+}
+
+// ReferralStore persists referral codes and who invited whom. A learner is
+// attributed to at most one referrer.
+type ReferralStore interface {
+	// ReferralCode returns the learner's code, creating it on first use.
+	ReferralCode(userID string) (string, error)
+	// RecordReferral attributes referredID to the owner of code and returns
+	// the owner. created is false when referredID was already attributed.
+	RecordReferral(code, referredID string) (referrerID string, created bool, err error)
+	// ActivateReferral marks referredID's referral activated and returns the
+	// referrer. activated is false when there is no referral left to
+	// activate.
+	ActivateReferral(referredID string) (referrerID string, activated bool, err error)
+	ReferralStats(referrerID string) (ReferralStats, error)
+}
+
+func newReferralCode() (string, error) {
+	buf := make([]byte, referralCodeLength)
+	if _, err := rand.Read(buf); err != nil {
+		return "", err
+	}
+	for i, b := range buf {
+		buf[i] = referralCodeAlphabet[int(b)%len(referralCodeAlphabet)]
+	}
+	return string(buf), nil
+}
+
+type memoryReferral struct {
+	referrerID string
+	activated  bool
+}
+
+// MemoryReferralStore is an in-memory ReferralStore.
+type MemoryReferralStore struct {
+	mu         sync.Mutex
+	codes      map[string]string // user ID -> code
+	owners     map[string]string // code -> user ID
+	referredBy map[string]*memoryReferral
+}
+
+func NewMemoryReferralStore() *MemoryReferralStore {
+	return &MemoryReferralStore{
+		codes:      make(map[string]string),
+		owners:     make(map[string]string),
+		referredBy: make(map[string]*memoryReferral),
+	}
+}
+
+func (s *MemoryReferralStore) ReferralCode(userID string) (string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if code, ok := s.codes[userID]; ok {
+		return code, nil
+	}
+	for {
+		code, err := newReferralCode()
+		if err != nil {
+			return "", fmt.Errorf("generate referral code: %w", err)
+		}
+		if _, taken := s.owners[code]; !taken {
+			s.codes[userID] = code
+			s.owners[code] = userID
+			return code, nil
+		}
+	}
+}
+
+func (s *MemoryReferralStore) RecordReferral(code, referredID string) (string, bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	referrerID, ok := s.owners[code]
+	if !ok {
+		return "", false, ErrReferralCodeInvalid
+	}
+	if referrerID == referredID {
+		return "", false, ErrSelfReferral
+	}
+	if _, exists := s.referredBy[referredID]; exists {
+		return referrerID, false, nil
+	}
+	s.referredBy[referredID] = &memoryReferral{referrerID: referrerID}
+	return referrerID, true, nil
+}
+
+func (s *MemoryReferralStore) ActivateReferral(referredID string) (string, bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	referral, ok := s.referredBy[referredID]
+	if !ok || referral.activated {
+		return "", false, nil
+	}
+	referral.activated = true
+	return referral.referrerID, true, nil
+}
+
+func (s *MemoryReferralStore) ReferralStats(referrerID string) (ReferralStats, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	var stats ReferralStats
+	for _, referral := range s.referredBy {
+		if referral.referrerID != referrerID {
+			continue
+		}
+		stats.Joined++
+		if referral.activated {
+			stats.Activated++
+		}
+	}
+	return stats, nil
+}
+
+// PostgresReferralStore persists referrals in PostgreSQL.
+type PostgresReferralStore struct {
+	pool     *pgxpool.Pool
+	tenantID string
+}
+
+func NewPostgresReferralStore(pool *pgxpool.Pool, tenantID string) *PostgresReferralStore {
+	return &PostgresReferralStore{pool: pool, tenantID: tenantID}
+}
+
+func (s *PostgresReferralStore) ReferralCode(externalID string) (string, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), dbTimeout)
+	defer cancel()
+
+	for attempt := 0; attempt < 5; attempt++ {
+		candidate, err := newReferralCode()
+		if err != nil {
+			return "", fmt.Errorf("generate referral code: %w", err)
+		}
+		// A clash with another learner's code inserts nothing, and the
+		// select below then finds no code for this learner.
+		if _, err := s.pool.Exec(ctx,
+			`INSERT INTO referral_codes (user_id, tenant_id, code)
+			 SELECT u.id, $1::uuid, $3 FROM (`+badgeUserSQL+`) u
+			 ON CONFLICT DO NOTHING`,
+			s.tenantID, externalID, candidate,
+		); err != nil {
+			return "", fmt.Errorf("insert referral code: %w", err)
+		}
+		var code string
+		err = s.pool.QueryRow(ctx,
+			`SELECT code FROM referral_codes WHERE user_id = (`+badgeUserSQL+`)`,
+			s.tenantID, externalID,
+		).Scan(&code)
+		if errors.Is(err, pgx.ErrNoRows) {
+			continue
+		}
+		if err != nil {
+			return "", fmt.Errorf("load referral code: %w", err)
+		}
+		return code, nil
+	}
+	return "", errors.New("insert referral code: no free code")
+}
+
+func (s *PostgresReferralStore) RecordReferral(code, referredExternalID string) (string, bool, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), dbTimeout)
+	defer cancel()
+
+	var referrerID string
+	err := s.pool.QueryRow(ctx,
+		`SELECT u.external_id
+		 FROM referral_codes rc
+		 JOIN users u ON u.id = rc.user_id
+		 WHERE rc.tenant_id = $1::uuid AND rc.code = $2`,
+		s.tenantID, code,
+	).Scan(&referrerID)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return "", false, ErrReferralCodeInvalid
+	}
+	if err != nil {
+		return "", false, fmt.Errorf("load referral code owner: %w", err)
+	}
+	if referrerID == referredExternalID {
+		return "", false, ErrSelfReferral
+	}
+
+	tag, err := s.pool.Exec(ctx,
+		`INSERT INTO referrals (referred_user_id, referrer_user_id, tenant_id)
+		 SELECT u.id, rc.user_id, $1::uuid
+		 FROM (`+badgeUserSQL+`) u, referral_codes rc
+		 WHERE rc.tenant_id = $1::uuid AND rc.code = $3
+		 ON CONFLICT (referred_user_id) DO NOTHING`,
+		s.tenantID, referredExternalID, code,
+	)
+	if err != nil {
+		return "", false, fmt.Errorf("record referral: %w", err)
+	}
+	return referrerID, tag.RowsAffected() == 1, nil
+}
+
+func (s *PostgresReferralStore) ActivateReferral(referredExternalID string) (string, bool, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), dbTimeout)
+	defer cancel()
+
+	var referrerID string
+	err := s.pool.QueryRow(ctx,
+		`UPDATE referrals r
+		 SET activated_at = NOW()
+		 FROM users u
+		 WHERE r.referred_user_id = (`+badgeUserSQL+`)
+		   AND r.activated_at IS NULL
+		   AND u.id = r.referrer_user_id
+		 RETURNING u.external_id`,
+		s.tenantID, referredExternalID,
+	).Scan(&referrerID)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return "", false, nil
+	}
+	if err != nil {
+		return "", false, fmt.Errorf("activate referral: %w", err)
+	}
+	return referrerID, true, nil
+}
+
+func (s *PostgresReferralStore) ReferralStats(referrerExternalID string) (ReferralStats, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), dbTimeout)
+	defer cancel()
+
+	var stats ReferralStats
+	err := s.pool.QueryRow(ctx,
+		`SELECT COUNT(*), COUNT(activated_at)
+		 FROM referrals
+		 WHERE referrer_user_id = (`+badgeUserSQL+`)`,
+		s.tenantID, referrerExternalID,
+	).Scan(&stats.Joined, &stats.Activated)
+	if err != nil {
+		return ReferralStats{}, fmt.Errorf("count referrals: %w", err)
+	}
+	return stats, nil
+}
+
+// handleInviteCommand answers /invite with the learner's referral deep link
+// and how many friends it has brought in.
+func (e *Engine) handleInviteCommand(msg chat.InboundMessage) (string, error) {
+	locale := e.messageLocale(msg, nil)
+	if e.referrals == nil || e.telegramBotUsername == "" {
+		return i18n.S(locale, i18n.MsgInviteUnavailable), nil
+	}
+	code, err := e.referrals.ReferralCode(msg.UserID)
+	if err != nil {
+		slog.Error("failed to get referral code", "user_id", msg.UserID, "error", err)
+		return i18n.S(locale, i18n.MsgTechnicalIssue), nil
+	}
+	e.logEventAsync(Event{
+		UserID:    msg.UserID,
+		EventType: "referral_link_shared",
+		Data: map[string]any{
+			"channel": msg.Channel,
+		},
+	})
+
+	link := fmt.Sprintf("https://t.me/%s?start=%s%s", e.telegramBotUsername, referralPayloadPrefix, code)
+	reply := i18n.S(locale, i18n.MsgInviteLink, link, progress.XPReferral)
+	stats, err := e.referrals.ReferralStats(msg.UserID)
+	if err != nil {
+		slog.Warn("failed to count referrals", "user_id", msg.UserID, "error", err)
+	} else if stats.Joined > 0 {
+		reply += "\n\n" + i18n.S(locale, i18n.MsgInviteStats, stats.Joined, stats.Activated)
+	}
+	return reply, nil
+}
+
+// attributeReferral credits a new learner to the learner whose invite code
+// they followed, and returns the onboarding reply with a note about the
+// welcome bonus.
+func (e *Engine) attributeReferral(msg chat.InboundMessage, code, reply string) string {
+	if e.referrals == nil {
+		return reply
+	}
+	referrerID, created, err := e.referrals.RecordReferral(code, msg.UserID)
+	switch {
+	case errors.Is(err, ErrReferralCodeInvalid), errors.Is(err, ErrSelfReferral):
+		slog.Info("ignoring referral payload", "user_id", msg.UserID, "reason", err)
+		return reply
+	case err != nil:
+		slog.Error("failed to record referral", "user_id", msg.UserID, "error", err)
+		return reply
+	case !created:
+		return reply
+	}
+	slog.Info("referral recorded", "user_id", msg.UserID, "referrer_id", referrerID)
+	e.logEventAsync(Event{
+		UserID:    msg.UserID,
+		EventType: "referral_signup",
+		Data: map[string]any{
+			"channel": msg.Channel,
+		},
+	})
+	locale := e.messageLocale(msg, nil)
+	return i18n.S(locale, i18n.MsgReferralWelcome, progress.XPReferralWelcome) + "\n\n" + reply
+}
+
+// evaluateReferral rewards both learners once an invited learner finishes
+// onboarding. Rewarding then rather than at sign-up keeps a bare /start from
+// earning XP.
+func (e *Engine) evaluateReferral(event Event) {
+	if e.referrals == nil || event.EventType != "onboarding_completed" || event.UserID == "" {
+		return
+	}
+	referrerID, activated, err := e.referrals.ActivateReferral(event.UserID)
+	if err != nil {
+		slog.Warn("failed to activate referral", "user_id", event.UserID, "error", err)
+		return
+	}
+	if !activated {
+		return
+	}
+	if e.xp != nil {
+		if err := e.xp.Award(referrerID, progress.XPSourceReferral, progress.XPReferral, map[string]any{
+			"referred_user_id": event.UserID,
+		}); err != nil {
+			slog.Warn("failed to award referral XP", "user_id", referrerID, "error", err)
+		}
+		if err := e.xp.Award(event.UserID, progress.XPSourceReferral, progress.XPReferralWelcome, nil); err != nil {
+			slog.Warn("failed to award referral welcome XP", "user_id", event.UserID, "error", err)
+		}
+	}
+	if e.milestones != nil {
+		e.milestones.add(referrerID, i18n.S(e.resolveUserLocale(referrerID), i18n.MsgReferralFriendDone, progress.XPReferral))
+	}
+	e.logEventAsync(Event{
+		ConversationID: event.ConversationID,
+		UserID:         event.UserID,
+		EventType:      "referral_activated",
+	})
+}
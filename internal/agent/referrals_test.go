@@ -0,0 +1,147 @@
+// Copyright 2026 the P&AI authors. All rights reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package agent_test
+
+import (
+	"context"
+	"errors"
+	"regexp"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/p-n-ai/pai-bot/internal/agent"
+	"github.com/p-n-ai/pai-bot/internal/ai"
+	"github.com/p-n-ai/pai-bot/internal/chat"
+	"github.com/p-n-ai/pai-bot/internal/i18n"
+	"github.com/p-n-ai/pai-bot/internal/progress"
+)
+
+var inviteLinkPattern = regexp.MustCompile(`https://t\.me/pai_test_bot\?start=(ref_[a-z0-9]+)`)
+
+func TestInviteLinkAttributesSignupAndRewardsOnOnboarding(t *testing.T) {
+	referrals := agent.NewMemoryReferralStore()
+	xp := progress.NewMemoryXPTracker()
+	engine := agent.NewEngine(agent.EngineConfig{
+		AIRouter:            mockRouter(ai.NewMockProvider("AI response")),
+		Store:               agent.NewMemoryStore(),
+		CurriculumLoader:    createTestCurriculumLoader(t),
+		XP:                  xp,
+		Referrals:           referrals,
+		TelegramBotUsername: "@pai_test_bot",
+	})
+
+	invite := sendAs(t, engine, "telegram", "inviter", "/invite")
+	match := inviteLinkPattern.FindStringSubmatch(invite)
+	if match == nil {
+		t.Fatalf("/invite = %q, want a t.me deep link", invite)
+	}
+	payload := match[1]
+
+	welcome := sendAs(t, engine, "telegram", "friend", "/start "+payload)
+	if want := i18n.S("en", i18n.MsgReferralWelcome, progress.XPReferralWelcome); !strings.HasPrefix(welcome, want) {
+		t.Fatalf("/start %s = %q, want the referral welcome first", payload, welcome)
+	}
+	if stats, _ := referrals.ReferralStats("inviter"); stats != (agent.ReferralStats{Joined: 1}) {
+		t.Fatalf("stats after signup = %+v, want one joined", stats)
+	}
+
+	for _, data := range []string{"form:1", "onboarding:skip"} {
+		if _, err := engine.ProcessMessage(context.Background(), chat.InboundMessage{
+			Channel: "telegram", UserID: "friend", Text: data, CallbackQueryID: "cb-" + data,
+		}); err != nil {
+			t.Fatalf("ProcessMessage(%q) error = %v", data, err)
+		}
+	}
+
+	deadline := time.Now().Add(500 * time.Millisecond)
+	for time.Now().Before(deadline) {
+		if total, _ := xp.GetTotal("inviter"); total == progress.XPReferral {
+			break
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+	if total, _ := xp.GetTotal("inviter"); total != progress.XPReferral {
+		t.Fatalf("inviter XP = %d, want %d", total, progress.XPReferral)
+	}
+	if total, _ := xp.GetTotal("friend"); total != progress.XPReferralWelcome {
+		t.Fatalf("friend XP = %d, want %d", total, progress.XPReferralWelcome)
+	}
+
+	again := sendAs(t, engine, "telegram", "inviter", "/invite")
+	if !strings.Contains(again, i18n.S("en", i18n.MsgInviteStats, 1, 1)) {
+		t.Errorf("/invite = %q, want the joined and finished counts", again)
+	}
+	// The inviter never onboarded, so the announcement uses the default
+	// locale.
+	if !strings.HasPrefix(again, "🎉") {
+		t.Errorf("/invite = %q, want the referral reward announced first", again)
+	}
+}
+
+func TestStartPayloadIgnoredForExistingLearnersAndOwnCode(t *testing.T) {
+	referrals := agent.NewMemoryReferralStore()
+	engine := agent.NewEngine(agent.EngineConfig{
+		AIRouter:            mockRouter(ai.NewMockProvider("AI response")),
+		Store:               agent.NewMemoryStore(),
+		Referrals:           referrals,
+		TelegramBotUsername: "pai_test_bot",
+	})
+	code, _ := referrals.ReferralCode("inviter")
+
+	sendAs(t, engine, "telegram", "regular", "/start")
+	if got := sendAs(t, engine, "telegram", "regular", "/start ref_"+code); strings.Contains(got, "friend invited") {
+		t.Fatalf("existing learner /start = %q, want no referral", got)
+	}
+	if got := sendAs(t, engine, "telegram", "inviter", "/start ref_"+code); strings.Contains(got, "friend invited") {
+		t.Fatalf("own code /start = %q, want no referral", got)
+	}
+	if stats, _ := referrals.ReferralStats("inviter"); stats.Joined != 0 {
+		t.Fatalf("stats = %+v, want no referrals", stats)
+	}
+}
+
+func TestInviteUnavailableWithoutBotUsername(t *testing.T) {
+	engine := agent.NewEngine(agent.EngineConfig{
+		AIRouter:  mockRouter(ai.NewMockProvider("AI response")),
+		Referrals: agent.NewMemoryReferralStore(),
+	})
+	if got := sendAs(t, engine, "telegram", "42", "/invite"); got != i18n.S("en", i18n.MsgInviteUnavailable) {
+		t.Fatalf("/invite = %q, want the unavailable message", got)
+	}
+}
+
+func TestMemoryReferralStore(t *testing.T) {
+	store := agent.NewMemoryReferralStore()
+	code, err := store.ReferralCode("a")
+	if err != nil {
+		t.Fatalf("ReferralCode() error = %v", err)
+	}
+	if again, _ := store.ReferralCode("a"); again != code {
+		t.Fatalf("ReferralCode() = %q then %q, want a stable code", code, again)
+	}
+
+	if _, _, err := store.RecordReferral("missing", "b"); !errors.Is(err, agent.ErrReferralCodeInvalid) {
+		t.Fatalf("RecordReferral(missing) error = %v, want ErrReferralCodeInvalid", err)
+	}
+	if _, _, err := store.RecordReferral(code, "a"); !errors.Is(err, agent.ErrSelfReferral) {
+		t.Fatalf("RecordReferral(own code) error = %v, want ErrSelfReferral", err)
+	}
+	if referrer, created, _ := store.RecordReferral(code, "b"); referrer != "a" || !created {
+		t.Fatalf("RecordReferral() = %q, %v; want a, true", referrer, created)
+	}
+	if _, created, _ := store.RecordReferral(code, "b"); created {
+		t.Fatal("RecordReferral() attributed the same learner twice")
+	}
+
+	if referrer, activated, _ := store.ActivateReferral("b"); referrer != "a" || !activated {
+		t.Fatalf("ActivateReferral() = %q, %v; want a, true", referrer, activated)
+	}
+	if _, activated, _ := store.ActivateReferral("b"); activated {
+		t.Fatal("ActivateReferral() activated the same referral twice")
+	}
+	if _, activated, _ := store.ActivateReferral("c"); activated {
+		t.Fatal("ActivateReferral() activated a learner nobody invited")
+	}
+}
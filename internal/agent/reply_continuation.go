@@ -0,0 +1,97 @@
+// Copyright 2026 the P&AI authors. All rights reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package agent
+
+import (
+	"context"
+	"log/slog"
+	"slices"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/p-n-ai/pai-bot/internal/ai"
+)
+
+// maxReplyContinuations bounds the extra calls made to finish a reply that
+// hit the output token limit.
+const maxReplyContinuations = 2
+
+const replyContinuationPrompt = "Your previous reply was cut off. Continue it from exactly where it stopped, " +
+	"mid-sentence or mid-equation if needed. Do not repeat anything already written and do not start over."
+
+// continueTruncatedReply asks the model to finish a reply that stopped at the
+// token limit and stitches the parts together, so a learner never gets an
+// answer that ends mid-equation. If a continuation fails, the reply so far
+// is kept. A reply cut by the channel's hard token limit is left alone: that
+// cut is deliberate, and the learner can ask for the rest with /more.
+func (e *Engine) continueTruncatedReply(ctx context.Context, turn *agentTurn, messages []ai.Message, model string, resp teachingCompletion) teachingCompletion {
+	if !resp.Truncated || e.replyLimits.forChannel(turn.Channel).HardTokens > 0 {
+		return resp
+	}
+	calls := 0
+	for resp.Truncated && calls < maxReplyContinuations {
+		calls++
+		retry := append(slices.Clip(messages),
+			ai.Message{Role: "assistant", Content: resp.Content},
+			ai.Message{Role: "user", Content: replyContinuationPrompt},
+		)
+		next, err := e.completeTextTeachingTurn(ctx, turn, retry, model)
+		if err != nil {
+			slog.Warn("reply continuation failed; sending partial reply", "error", err)
+			break
+		}
+		resp.InputTokens += next.InputTokens
+		resp.OutputTokens += next.OutputTokens
+		resp.CostUSD += next.CostUSD
+		resp.Truncated = next.Truncated
+		if strings.TrimSpace(next.Content) == "" {
+			break
+		}
+		resp.Content = stitchContinuation(resp.Content, next.Content)
+	}
+	e.logEventAsync(Event{
+		ConversationID: turn.ConversationID,
+		UserID:         turn.UserID,
+		EventType:      "reply_continued",
+		Data: map[string]any{
+			"channel":       turn.Channel,
+			"continuations": calls,
+			"complete":      !resp.Truncated,
+		},
+	})
+	return resp
+}
+
+// stitchContinuation joins a cut-off reply and its continuation. Models
+// sometimes restate the last few words, so an overlap between the tail of
+// partial and the head of next is dropped.
+func stitchContinuation(partial, next string) string {
+	next = strings.TrimLeft(next, "\n")
+	if n := continuationOverlap(partial, next); n > 0 {
+		next = next[n:]
+	}
+	if next == "" {
+		return partial
+	}
+	last, _ := utf8.DecodeLastRuneInString(partial)
+	first, _ := utf8.DecodeRuneInString(next)
+	if strings.ContainsRune(".!?:;,", last) && !unicode.IsSpace(first) && !unicode.IsPunct(first) {
+		return partial + " " + next
+	}
+	return partial + next
+}
+
+// continuationOverlap returns the length of the longest prefix of next that
+// repeats the end of partial. Short overlaps are ignored as coincidence.
+func continuationOverlap(partial, next string) int {
+	const minOverlap, maxOverlap = 8, 120
+	limit := min(len(partial), len(next), maxOverlap)
+	for n := limit; n >= minOverlap; n-- {
+		if strings.HasSuffix(partial, next[:n]) {
+			return n
+		}
+	}
+	return 0
+}
@@ -0,0 +1,24 @@
+// Copyright 2026 the P&AI authors. All rights reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package agent
+
+import "testing"
+
+func TestStitchContinuation(t *testing.T) {
+	tests := []struct {
+		partial, next, want string
+	}{
+		{"2x + 3 = 7 -", " 3", "2x + 3 = 7 - 3"},
+		{"so x = 4", "2. Next", "so x = 42. Next"},
+		{"So x = 2.", "Now check it.", "So x = 2. Now check it."},
+		{"First, subtract 3 from both", "subtract 3 from both sides.", "First, subtract 3 from both sides."},
+		{"Answer:", "\n\nx = 2", "Answer: x = 2"},
+		{"x = 2", "", "x = 2"},
+	}
+	for _, tt := range tests {
+		if got := stitchContinuation(tt.partial, tt.next); got != tt.want {
+			t.Errorf("stitchContinuation(%q, %q) = %q, want %q", tt.partial, tt.next, got, tt.want)
+		}
+	}
+}
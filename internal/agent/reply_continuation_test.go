@@ -0,0 +1,105 @@
+// Copyright 2026 the P&AI authors. All rights reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package agent_test
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/p-n-ai/pai-bot/internal/agent"
+	"github.com/p-n-ai/pai-bot/internal/ai"
+)
+
+// truncatingProvider returns its parts in order; every part but the last is
+// marked as cut off at the token limit.
+type truncatingProvider struct {
+	mu       sync.Mutex
+	parts    []string
+	failRest bool
+	requests []ai.CompletionRequest
+}
+
+func (p *truncatingProvider) Complete(_ context.Context, req ai.CompletionRequest) (ai.CompletionResponse, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.requests = append(p.requests, req)
+	if len(p.requests) > 1 && p.failRest {
+		return ai.CompletionResponse{}, errors.New("provider down")
+	}
+	content := p.parts[0]
+	truncated := len(p.parts) > 1
+	if truncated {
+		p.parts = p.parts[1:]
+	}
+	return ai.CompletionResponse{Content: content, Model: "mock", InputTokens: 10, OutputTokens: 5, Truncated: truncated}, nil
+}
+
+func (p *truncatingProvider) StreamComplete(context.Context, ai.CompletionRequest) (<-chan ai.StreamChunk, error) {
+	return nil, errors.New("not implemented")
+}
+
+func (p *truncatingProvider) Models() []ai.ModelInfo { return nil }
+
+func (p *truncatingProvider) HealthCheck(context.Context) error { return nil }
+
+func TestTruncatedReplyIsContinuedBeforeDelivery(t *testing.T) {
+	store := agent.NewMemoryStore()
+	provider := &truncatingProvider{parts: []string{
+		"Subtract 3 from both sides: 2x + 3 - 3 = 7 -",
+		" 3, so 2x = 4. Then divide by 2.",
+	}}
+	engine := agent.NewEngine(agent.EngineConfig{AIRouter: mockRouter(provider), Store: store})
+
+	got := sendAs(t, engine, "websocket", "42", "Solve 2x + 3 = 7")
+	want := "Subtract 3 from both sides: 2x + 3 - 3 = 7 - 3, so 2x = 4. Then divide by 2."
+	if got != want {
+		t.Fatalf("reply = %q, want %q", got, want)
+	}
+
+	provider.mu.Lock()
+	defer provider.mu.Unlock()
+	if len(provider.requests) != 2 {
+		t.Fatalf("model calls = %d, want 2", len(provider.requests))
+	}
+	retry := provider.requests[1].Messages
+	if partial := retry[len(retry)-2]; partial.Role != "assistant" || !strings.HasSuffix(partial.Content, "= 7 -") {
+		t.Errorf("continuation partial = %+v, want the cut-off reply", partial)
+	}
+	if ask := retry[len(retry)-1]; ask.Role != "user" || !strings.Contains(ask.Content, "Continue") {
+		t.Errorf("continuation instruction = %+v", ask)
+	}
+
+	conv, _ := store.GetActiveConversation("42")
+	last := conv.Messages[len(conv.Messages)-1]
+	if last.Content != want || last.OutputTokens != 10 {
+		t.Errorf("stored reply = %q (%d output tokens), want the stitched reply and summed tokens", last.Content, last.OutputTokens)
+	}
+}
+
+func TestTruncatedReplyContinuationIsBounded(t *testing.T) {
+	provider := &truncatingProvider{parts: []string{"Step 1: x =", " 2. Step 2:", " y =", " 3. Step 3:", " done."}}
+	engine := agent.NewEngine(agent.EngineConfig{AIRouter: mockRouter(provider)})
+
+	got := sendAs(t, engine, "websocket", "42", "Solve the system")
+	if got != "Step 1: x = 2. Step 2: y =" {
+		t.Fatalf("reply = %q, want the first three parts only", got)
+	}
+	provider.mu.Lock()
+	defer provider.mu.Unlock()
+	if len(provider.requests) != 3 {
+		t.Errorf("model calls = %d, want 1 plus 2 continuations", len(provider.requests))
+	}
+}
+
+func TestTruncatedReplyKeptWhenContinuationFails(t *testing.T) {
+	provider := &truncatingProvider{parts: []string{"Divide both sides by 2 to get x =", " 2."}, failRest: true}
+	engine := agent.NewEngine(agent.EngineConfig{AIRouter: mockRouter(provider)})
+
+	if got := sendAs(t, engine, "websocket", "42", "Solve 2x = 4"); got != "Divide both sides by 2 to get x =" {
+		t.Fatalf("reply = %q, want the partial reply", got)
+	}
+}
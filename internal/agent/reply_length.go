@@ -0,0 +1,215 @@
+// Copyright 2026 the P&AI authors. All rights reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package agent
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+	"unicode"
+
+	"github.com/p-n-ai/pai-bot/internal/chat"
+	"github.com/p-n-ai/pai-bot/internal/i18n"
+)
+
+// moreContinuationPrompt asks the tutor to pick up an answer the hard token
+// limit cut off.
+const moreContinuationPrompt = "Continue your previous answer exactly where it stopped. Do not repeat what you already said."
+
+// ReplyLengthLimit bounds tutor answers on one channel. SoftChars splits a
+// longer answer and holds the rest for /more; HardTokens stops generation.
+// Zero disables either limit. While a teaching reply streams only HardTokens
+// applies; SoftChars splits the assembled reply once the stream ends.
+type ReplyLengthLimit struct {
+	SoftChars  int
+	HardTokens int
+}
+
+// ReplyLengthLimits maps a channel name to its reply limits.
+type ReplyLengthLimits map[string]ReplyLengthLimit
+
+// ParseReplyLengthLimits parses "channel=soft:hard" entries separated by
+// commas, e.g. "telegram=1200:800,whatsapp=700:500". Soft limits are
+// characters; hard limits are output tokens.
+func ParseReplyLengthLimits(raw string) (ReplyLengthLimits, error) {
+	limits := ReplyLengthLimits{}
+	for _, entry := range strings.Split(raw, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+		channel, values, ok := strings.Cut(entry, "=")
+		channel = strings.ToLower(strings.TrimSpace(channel))
+		soft, hard, okValues := strings.Cut(values, ":")
+		if !ok || !okValues || channel == "" {
+			return nil, fmt.Errorf("invalid reply length entry %q: want channel=soft:hard", entry)
+		}
+		softChars, err := strconv.Atoi(strings.TrimSpace(soft))
+		if err != nil || softChars < 0 {
+			return nil, fmt.Errorf("invalid soft limit in %q", entry)
+		}
+		hardTokens, err := strconv.Atoi(strings.TrimSpace(hard))
+		if err != nil || hardTokens < 0 {
+			return nil, fmt.Errorf("invalid hard limit in %q", entry)
+		}
+		limits[channel] = ReplyLengthLimit{SoftChars: softChars, HardTokens: hardTokens}
+	}
+	return limits, nil
+}
+
+func (l ReplyLengthLimits) forChannel(channel string) ReplyLengthLimit {
+	return l[strings.ToLower(channel)]
+}
+
+// maxTokens returns the channel's hard limit, or taskDefault, the teaching
+// task's configured cap, when the channel has none.
+func (l ReplyLengthLimit) maxTokens(taskDefault int) int {
+	if l.HardTokens > 0 {
+		return l.HardTokens
+	}
+	return taskDefault
+}
+
+// pendingReplyTTL is how long /more can still fetch the rest of a reply.
+// Older entries are dropped so learners who never ask for more do not pin
+// their text in memory.
+const pendingReplyTTL = 30 * time.Minute
+
+// pendingReply is what /more will send next: either held-back text or a
+// request to let the model continue a hard-truncated answer.
+type pendingReply struct {
+	rest   string
+	resume bool
+	at     time.Time
+}
+
+type pendingReplies struct {
+	mu      sync.Mutex
+	pending map[string]pendingReply
+	now     func() time.Time
+}
+
+func newPendingReplies() *pendingReplies {
+	return &pendingReplies{pending: make(map[string]pendingReply), now: time.Now}
+}
+
+// set stores reply for userID and drops every expired entry, which keeps the
+// map bounded by the learners active within pendingReplyTTL.
+func (p *pendingReplies) set(userID string, reply pendingReply) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	now := p.now()
+	for id, r := range p.pending {
+		if now.Sub(r.at) > pendingReplyTTL {
+			delete(p.pending, id)
+		}
+	}
+	if reply.rest == "" && !reply.resume {
+		delete(p.pending, userID)
+		return
+	}
+	reply.at = now
+	p.pending[userID] = reply
+}
+
+// take removes and returns userID's pending reply unless it has expired.
+func (p *pendingReplies) take(userID string) (pendingReply, bool) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	reply, ok := p.pending[userID]
+	delete(p.pending, userID)
+	if ok && p.now().Sub(reply.at) > pendingReplyTTL {
+		return pendingReply{}, false
+	}
+	return reply, ok
+}
+
+// limitReply applies the channel's soft limit to the finished content, after
+// any stream has ended, and remembers what /more should send. truncated
+// reports that the model stopped at its token limit; with a channel hard limit
+// that cut is left for /more to continue, since continueTruncatedReply skips
+// it. It returns the text to show now and whether more is pending.
+func (e *Engine) limitReply(userID, channel, content string, truncated bool) (string, bool) {
+	limit := e.replyLimits.forChannel(channel)
+	head, rest := splitReply(content, limit.SoftChars)
+	truncated = truncated && limit.HardTokens > 0
+	e.pendingReplies.set(userID, pendingReply{rest: rest, resume: truncated})
+	return head, rest != "" || truncated
+}
+
+// splitReply cuts content at the last paragraph, sentence, or word break
+// within limit runes. It returns content unchanged when it fits.
+func splitReply(content string, limit int) (string, string) {
+	runes := []rune(content)
+	if limit <= 0 || len(runes) <= limit {
+		return content, ""
+	}
+	window := string(runes[:limit])
+	cut := strings.LastIndex(window, "\n\n")
+	if cut < len(window)/3 {
+		cut = lastSentenceEnd(window)
+	}
+	if cut < len(window)/3 {
+		cut = strings.LastIndexFunc(window, unicode.IsSpace)
+	}
+	if cut <= 0 {
+		cut = len(window)
+	}
+	return strings.TrimSpace(content[:cut]), strings.TrimSpace(content[cut:])
+}
+
+func lastSentenceEnd(s string) int {
+	best := -1
+	for _, mark := range []string{". ", "! ", "? ", ".\n", "!\n", "?\n", "。"} {
+		if i := strings.LastIndex(s, mark); i >= 0 && i+len(mark) > best {
+			best = i + len(mark)
+		}
+	}
+	return best
+}
+
+// handleMoreCommand sends the rest of a reply that was cut for length.
+func (e *Engine) handleMoreCommand(ctx context.Context, msg chat.InboundMessage) (string, error) {
+	conv, err := e.getOrCreateConversation(msg.UserID)
+	if err != nil {
+		slog.Error("failed to get conversation for /more", "user_id", msg.UserID, "error", err)
+		return i18n.S(e.messageLocale(msg, nil), i18n.MsgTechnicalIssue), nil
+	}
+	locale := e.messageLocale(msg, conv)
+	pending, ok := e.pendingReplies.take(msg.UserID)
+	if !ok {
+		return i18n.S(locale, i18n.MsgMoreNothing), nil
+	}
+	if pending.rest == "" {
+		// The instruction is only sent to the model; history keeps the
+		// question and the partial answer it continues.
+		question, _, _, found := lastExplainedExchange(conv)
+		if !found {
+			return i18n.S(locale, i18n.MsgMoreNothing), nil
+		}
+		continuation := msg
+		continuation.Text = question.Content
+		return e.runTeachingTurnFor(ctx, continuation, conv, "", nil, teachingTurnOptions{
+			ExistingUserMessageID: question.ID,
+			Continue:              true,
+		})
+	}
+
+	limit := e.replyLimits.forChannel(msg.Channel)
+	head, rest := splitReply(pending.rest, limit.SoftChars)
+	if rest != "" || pending.resume {
+		e.pendingReplies.set(msg.UserID, pendingReply{rest: rest, resume: pending.resume})
+	}
+	if _, err := e.store.AddMessage(conv.ID, StoredMessage{Role: "assistant", Content: head}); err != nil {
+		slog.Error("failed to store /more reply", "conversation_id", conv.ID, "error", err)
+	}
+	if rest != "" || pending.resume {
+		head += "\n\n" + i18n.S(locale, i18n.MsgReplyMoreHint)
+	}
+	return head, nil
+}
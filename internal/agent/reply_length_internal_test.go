@@ -0,0 +1,78 @@
+// Copyright 2026 the P&AI authors. All rights reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package agent
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestParseReplyLengthLimits(t *testing.T) {
+	limits, err := ParseReplyLengthLimits(" Telegram=1200:800, whatsapp=0:500 ")
+	if err != nil {
+		t.Fatalf("ParseReplyLengthLimits() error = %v", err)
+	}
+	if got := limits.forChannel("telegram"); got != (ReplyLengthLimit{SoftChars: 1200, HardTokens: 800}) {
+		t.Fatalf("telegram = %#v", got)
+	}
+	if got := limits.forChannel("whatsapp").maxTokens(1024); got != 500 {
+		t.Fatalf("whatsapp max tokens = %d, want 500", got)
+	}
+	if got := limits.forChannel("websocket").maxTokens(1024); got != 1024 {
+		t.Fatalf("unconfigured max tokens = %d, want the task default", got)
+	}
+
+	for _, raw := range []string{"telegram", "telegram=12", "telegram=a:1", "=1:1", "telegram=-1:0"} {
+		if _, err := ParseReplyLengthLimits(raw); err == nil {
+			t.Errorf("ParseReplyLengthLimits(%q) error = nil, want error", raw)
+		}
+	}
+}
+
+func TestSplitReplyPrefersParagraphThenSentenceBreaks(t *testing.T) {
+	content := "First paragraph explains the idea.\n\nSecond paragraph works an example in detail."
+	head, rest := splitReply(content, 50)
+	if head != "First paragraph explains the idea." || !strings.HasPrefix(rest, "Second paragraph") {
+		t.Fatalf("split = %q | %q, want paragraph break", head, rest)
+	}
+
+	head, rest = splitReply("One sentence here. Another sentence follows and keeps going on.", 40)
+	if head != "One sentence here." || rest != "Another sentence follows and keeps going on." {
+		t.Fatalf("split = %q | %q, want sentence break", head, rest)
+	}
+
+	if head, rest := splitReply("short", 40); head != "short" || rest != "" {
+		t.Fatalf("split = %q | %q, want unchanged", head, rest)
+	}
+	if head, rest := splitReply("ungkapan algebra", 0); head != "ungkapan algebra" || rest != "" {
+		t.Fatalf("split = %q | %q, want unchanged with no limit", head, rest)
+	}
+}
+
+func TestPendingRepliesExpire(t *testing.T) {
+	now := time.Date(2026, 10, 17, 9, 0, 0, 0, time.UTC)
+	p := newPendingReplies()
+	p.now = func() time.Time { return now }
+
+	p.set("stale", pendingReply{rest: "old rest"})
+	p.set("fresh", pendingReply{rest: "new rest"})
+	now = now.Add(pendingReplyTTL + time.Minute)
+	if _, ok := p.take("stale"); ok {
+		t.Fatal("take(stale) ok = true, want an expired reply dropped")
+	}
+
+	p.set("fresh", pendingReply{rest: "new rest"})
+	p.set("other", pendingReply{rest: "x"})
+	now = now.Add(time.Minute)
+	if reply, ok := p.take("fresh"); !ok || reply.rest != "new rest" {
+		t.Fatalf("take(fresh) = %#v, %v; want the reply within the window", reply, ok)
+	}
+
+	p.pending["abandoned"] = pendingReply{rest: "y", at: now.Add(-2 * pendingReplyTTL)}
+	p.set("other", pendingReply{rest: "z"})
+	if _, ok := p.pending["abandoned"]; ok {
+		t.Fatal("set() kept an expired entry, want it pruned")
+	}
+}
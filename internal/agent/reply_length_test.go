@@ -0,0 +1,105 @@
+// Copyright 2026 the P&AI authors. All rights reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package agent_test
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/p-n-ai/pai-bot/internal/agent"
+	"github.com/p-n-ai/pai-bot/internal/ai"
+	"github.com/p-n-ai/pai-bot/internal/chat"
+)
+
+func TestEngine_MoreSendsRestOfLongReply(t *testing.T) {
+	mockAI := ai.NewMockProvider("Step one: move 3 to the other side.\n\nStep two: divide both sides by 2 to get x.")
+	engine := agent.NewEngine(agent.EngineConfig{
+		AIRouter:    mockRouter(mockAI),
+		Store:       agent.NewMemoryStore(),
+		ReplyLimits: agent.ReplyLengthLimits{"telegram": {SoftChars: 50, HardTokens: 600}},
+	})
+	ctx := context.Background()
+	msg := chat.InboundMessage{Channel: "telegram", UserID: "more-user", Text: "solve 2x + 3 = 9", Language: "en"}
+
+	resp, err := engine.ProcessMessage(ctx, msg)
+	if err != nil {
+		t.Fatalf("ProcessMessage() error = %v", err)
+	}
+	if !strings.Contains(resp, "Step one") || strings.Contains(resp, "Step two") || !strings.Contains(resp, "/more") {
+		t.Fatalf("response = %q, want first part with /more hint", resp)
+	}
+	if mockAI.LastRequest.MaxTokens != 600 {
+		t.Fatalf("max tokens = %d, want channel hard limit", mockAI.LastRequest.MaxTokens)
+	}
+
+	msg.Text = "/more"
+	resp, _ = engine.ProcessMessage(ctx, msg)
+	if !strings.HasPrefix(resp, "Step two") || strings.Contains(resp, "/more") {
+		t.Fatalf("/more = %q, want the rest without another hint", resp)
+	}
+	resp, _ = engine.ProcessMessage(ctx, msg)
+	if !strings.Contains(resp, "nothing more") {
+		t.Fatalf("/more = %q, want nothing pending", resp)
+	}
+}
+
+func TestEngine_MoreContinuesHardTruncatedReply(t *testing.T) {
+	mockAI := ai.NewMockProvider("This answer ran into the token cap")
+	mockAI.Truncated = true
+	store := agent.NewMemoryStore()
+	engine := agent.NewEngine(agent.EngineConfig{
+		AIRouter:    mockRouter(mockAI),
+		Store:       store,
+		ReplyLimits: agent.ReplyLengthLimits{"telegram": {HardTokens: 10}},
+	})
+	ctx := context.Background()
+	msg := chat.InboundMessage{Channel: "telegram", UserID: "more-user", Text: "explain ratios", Language: "en"}
+
+	resp, _ := engine.ProcessMessage(ctx, msg)
+	if !strings.Contains(resp, "/more") {
+		t.Fatalf("response = %q, want /more hint after hitting the hard limit", resp)
+	}
+
+	if calls := mockAI.Calls(); calls != 1 {
+		t.Fatalf("calls = %d, want no auto-continuation past the channel's hard limit", calls)
+	}
+
+	msg.Text = "/more"
+	if _, err := engine.ProcessMessage(ctx, msg); err != nil {
+		t.Fatalf("ProcessMessage(/more) error = %v", err)
+	}
+	prompt := mockAI.LastRequest.Messages
+	last := prompt[len(prompt)-1]
+	if !strings.Contains(last.Content, "Continue your previous answer") {
+		t.Fatalf("last prompt message = %q, want continuation request", last.Content)
+	}
+	if partial := prompt[len(prompt)-2]; partial.Role != "assistant" || partial.Content != "This answer ran into the token cap" {
+		t.Fatalf("message before the request = %+v, want the partial answer", partial)
+	}
+
+	conv, _ := store.GetActiveConversation("more-user")
+	for _, m := range conv.Messages {
+		if m.Role == "user" && m.Content != "explain ratios" {
+			t.Fatalf("stored user message %q, want only the learner's question", m.Content)
+		}
+	}
+}
+
+func TestEngine_NoMoreHintForCompleteReplyAtHardLimit(t *testing.T) {
+	// The mock reports one output token per byte, so this finished answer
+	// uses more than the hard limit without being cut off.
+	mockAI := ai.NewMockProvider("A ratio compares two quantities.")
+	engine := agent.NewEngine(agent.EngineConfig{
+		AIRouter:    mockRouter(mockAI),
+		Store:       agent.NewMemoryStore(),
+		ReplyLimits: agent.ReplyLengthLimits{"telegram": {HardTokens: 10}},
+	})
+	msg := chat.InboundMessage{Channel: "telegram", UserID: "more-user", Text: "explain ratios", Language: "en"}
+
+	resp, _ := engine.ProcessMessage(context.Background(), msg)
+	if strings.Contains(resp, "/more") {
+		t.Fatalf("response = %q, want no /more hint for a complete reply", resp)
+	}
+}
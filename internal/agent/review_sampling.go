@@ -0,0 +1,112 @@
+// Copyright 2026 the P&AI authors. All rights reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package agent
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"math/rand/v2"
+	"sync"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// Reasons a conversation lands in the review queue.
+const (
+	ReviewReasonSampled = "sampled"
+	ReviewReasonSafety  = "safety"
+)
+
+// ReviewQueue collects conversations for human quality review. Queueing the
+// same conversation again is a no-op, except that a safety flag overrides a
+// random sample so reviewers can triage it first.
+type ReviewQueue interface {
+	QueueForReview(conversationID, reason string) error
+}
+
+// evaluateReviewSample queues a random ReviewSamplePercent of new sessions
+// and every conversation with a turn the safety checks did more than pass.
+func (e *Engine) evaluateReviewSample(event Event) {
+	if e.reviews == nil || event.ConversationID == "" || IsSandboxUser(event.UserID) {
+		return
+	}
+	var reason string
+	switch event.EventType {
+	case "session_started":
+		if e.reviewSamplePercent <= 0 || rand.Float64()*100 >= e.reviewSamplePercent {
+			return
+		}
+		reason = ReviewReasonSampled
+	case "agent_turn_completed":
+		if flagged, _ := event.Data["safety_flagged"].(bool); !flagged {
+			return
+		}
+		reason = ReviewReasonSafety
+	default:
+		return
+	}
+	if err := e.reviews.QueueForReview(event.ConversationID, reason); err != nil {
+		slog.Warn("failed to queue conversation for review",
+			"conversation_id", event.ConversationID,
+			"reason", reason,
+			"error", err,
+		)
+	}
+}
+
+// MemoryReviewQueue is an in-memory ReviewQueue.
+type MemoryReviewQueue struct {
+	mu      sync.Mutex
+	reasons map[string]string // conversation ID -> reason
+}
+
+func NewMemoryReviewQueue() *MemoryReviewQueue {
+	return &MemoryReviewQueue{reasons: make(map[string]string)}
+}
+
+func (q *MemoryReviewQueue) QueueForReview(conversationID, reason string) error {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	if _, ok := q.reasons[conversationID]; !ok || reason == ReviewReasonSafety {
+		q.reasons[conversationID] = reason
+	}
+	return nil
+}
+
+// Queued returns the reason conversationID was queued for review.
+func (q *MemoryReviewQueue) Queued(conversationID string) (string, bool) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	reason, ok := q.reasons[conversationID]
+	return reason, ok
+}
+
+// PostgresReviewQueue queues conversations in conversation_reviews, where
+// the admin API lists and rates them.
+type PostgresReviewQueue struct {
+	pool     *pgxpool.Pool
+	tenantID string
+}
+
+func NewPostgresReviewQueue(pool *pgxpool.Pool, tenantID string) *PostgresReviewQueue {
+	return &PostgresReviewQueue{pool: pool, tenantID: tenantID}
+}
+
+func (q *PostgresReviewQueue) QueueForReview(conversationID, reason string) error {
+	ctx, cancel := context.WithTimeout(context.Background(), dbTimeout)
+	defer cancel()
+
+	if _, err := q.pool.Exec(ctx,
+		`INSERT INTO conversation_reviews (tenant_id, conversation_id, reason)
+		 VALUES ($1::uuid, $2::uuid, $3)
+		 ON CONFLICT (conversation_id) DO UPDATE
+		 SET reason = EXCLUDED.reason
+		 WHERE EXCLUDED.reason = 'safety'`,
+		q.tenantID, conversationID, reason,
+	); err != nil {
+		return fmt.Errorf("queue conversation review: %w", err)
+	}
+	return nil
+}
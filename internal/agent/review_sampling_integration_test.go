@@ -0,0 +1,66 @@
+// Copyright 2026 the P&AI authors. All rights reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+//go:build integration
+// +build integration
+
+package agent
+
+import (
+	"context"
+	"testing"
+
+	"github.com/p-n-ai/pai-bot/internal/adminapi"
+)
+
+func TestPostgresReviewQueue_RatedReviewsExportAsEvalCases(t *testing.T) {
+	ctx := context.Background()
+	pool, tenantID := startSchedulerPostgres(t, ctx)
+
+	store, err := NewPostgresStore(ctx, pool)
+	if err != nil {
+		t.Fatalf("NewPostgresStore() error = %v", err)
+	}
+	convID, err := store.CreateConversation(Conversation{UserID: "review-learner", State: "teaching"})
+	if err != nil {
+		t.Fatalf("CreateConversation() error = %v", err)
+	}
+	if _, err := store.AddMessage(convID, StoredMessage{Role: "user", Content: "Solve 2x = 10"}); err != nil {
+		t.Fatalf("AddMessage() error = %v", err)
+	}
+
+	queue := NewPostgresReviewQueue(pool, tenantID)
+	for _, reason := range []string{ReviewReasonSampled, ReviewReasonSafety, ReviewReasonSampled} {
+		if err := queue.QueueForReview(convID, reason); err != nil {
+			t.Fatalf("QueueForReview(%s) error = %v", reason, err)
+		}
+	}
+
+	admin := adminapi.New(pool, tenantID)
+	pending, err := admin.ListConversationReviews(adminapi.ReviewStatusPending, 0)
+	if err != nil {
+		t.Fatalf("ListConversationReviews() error = %v", err)
+	}
+	if len(pending) != 1 || pending[0].ConversationID != convID || pending[0].Reason != ReviewReasonSafety {
+		t.Fatalf("pending = %+v, want the conversation once, upgraded to safety", pending)
+	}
+
+	rated, err := admin.RateConversationReview(pending[0].ID, 2, "Gave away the answer", "")
+	if err != nil {
+		t.Fatalf("RateConversationReview() error = %v", err)
+	}
+	if rated.Rating == nil || *rated.Rating != 2 || rated.ReviewedAt == nil {
+		t.Fatalf("rated = %+v, want rating 2 with a review time", rated)
+	}
+	if pending, _ = admin.ListConversationReviews(adminapi.ReviewStatusPending, 0); len(pending) != 0 {
+		t.Fatalf("pending after rating = %+v, want none", pending)
+	}
+
+	fixture, err := admin.ExportReviewEvalFixture()
+	if err != nil {
+		t.Fatalf("ExportReviewEvalFixture() error = %v", err)
+	}
+	if len(fixture.Conversations) != 1 || len(fixture.Conversations[0].Turns) != 1 || fixture.Conversations[0].Turns[0].User != "Solve 2x = 10" {
+		t.Fatalf("fixture = %+v, want one case replaying the learner's turn", fixture)
+	}
+}
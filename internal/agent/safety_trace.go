@@ -0,0 +1,89 @@
+// Copyright 2026 the P&AI authors. All rights reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package agent
+
+import "github.com/p-n-ai/pai-bot/internal/chat"
+
+// Safety checks recorded in a SafetyTrace.
+const (
+	SafetyCheckContentFilter   = "content_filter"
+	SafetyCheckPromptInjection = "prompt_injection"
+	SafetyCheckTurnHook        = "turn_hook"
+)
+
+// Safety actions recorded in a SafetyTrace.
+const (
+	SafetyActionPassed      = "passed"
+	SafetyActionMasked      = "masked"
+	SafetyActionRegenerated = "regenerated"
+	SafetyActionFlagged     = "flagged"
+	SafetyActionRefused     = "refused"
+	SafetyActionBlocked     = "blocked"
+)
+
+// SafetyTrace records the safety decisions made while producing one reply.
+// It is stored on the assistant message so a transcript can be audited turn
+// by turn.
+type SafetyTrace struct {
+	TurnID    string           `json:"turn_id,omitempty"`
+	Decisions []SafetyDecision `json:"decisions"`
+}
+
+// SafetyDecision is one check and its outcome. It never carries the matched
+// words or the learner's text.
+type SafetyDecision struct {
+	Check  string `json:"check"`
+	Action string `json:"action"`
+	// Hits counts matches, such as words masked by the content filter.
+	Hits   int    `json:"hits,omitempty"`
+	Reason string `json:"reason,omitempty"`
+}
+
+func (t *SafetyTrace) add(d SafetyDecision) {
+	t.Decisions = append(t.Decisions, d)
+}
+
+// flagged reports whether any check did more than pass.
+func (t *SafetyTrace) flagged() bool {
+	for _, d := range t.Decisions {
+		if d.Action != SafetyActionPassed {
+			return true
+		}
+	}
+	return false
+}
+
+// eventData summarizes the trace for turn events.
+func (t *SafetyTrace) eventData() []map[string]any {
+	out := make([]map[string]any, 0, len(t.Decisions))
+	for _, d := range t.Decisions {
+		entry := map[string]any{"check": d.Check, "action": d.Action}
+		if d.Hits > 0 {
+			entry["hits"] = d.Hits
+		}
+		if d.Reason != "" {
+			entry["reason"] = d.Reason
+		}
+		out = append(out, entry)
+	}
+	return out
+}
+
+// traceInputSafety flags learner text that looks like a prompt injection
+// attempt. The turn still runs; learner text reaches the model as quoted
+// data, so the flag is for review, not enforcement.
+func (t *SafetyTrace) traceInputSafety(text string) {
+	if chat.ContainsPromptInjection(text) {
+		t.add(SafetyDecision{Check: SafetyCheckPromptInjection, Action: SafetyActionFlagged})
+	}
+}
+
+// storedSafetyTrace returns t for storage, or nil when no check ran.
+func storedSafetyTrace(t *SafetyTrace) *SafetyTrace {
+	if len(t.Decisions) == 0 {
+		return nil
+	}
+	stored := *t
+	return &stored
+}
@@ -0,0 +1,120 @@
+// Copyright 2026 the P&AI authors. All rights reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package agent
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/p-n-ai/pai-bot/internal/apperr"
+	"github.com/p-n-ai/pai-bot/internal/chat"
+)
+
+// SandboxUserPrefix starts the external ID of every sandbox learner, so the
+// engine can tell them apart without a lookup.
+const SandboxUserPrefix = "sandbox-"
+
+// sandboxChannel is the inbound channel of sandbox turns. It has no adapter:
+// replies go back to the staff member who sent the turn.
+const sandboxChannel = "sandbox"
+
+const defaultSandboxName = "Sandbox Student"
+
+var (
+	ErrNotSandboxUser     = apperr.UserInput(errors.New("not a sandbox user"))
+	ErrInvalidSandboxForm = apperr.UserInput(errors.New("sandbox form must be 1, 2 or 3"))
+)
+
+// IsSandboxUser reports whether userID belongs to a staff sandbox learner.
+// Sandbox learners skip token budgets, and analytics leave them out.
+func IsSandboxUser(userID string) bool {
+	return strings.HasPrefix(userID, SandboxUserPrefix)
+}
+
+// SandboxSession is a sandbox learner and the conversation opened for them.
+type SandboxSession struct {
+	UserID         string `json:"user_id"`
+	ConversationID string `json:"conversation_id"`
+}
+
+// sandboxUserStore is implemented by stores that persist the sandbox flag
+// analytics queries filter on.
+type sandboxUserStore interface {
+	CreateSandboxUser(externalID, name string) error
+}
+
+// StartSandbox creates a sandbox learner and opens their conversation. With
+// a form the learner skips onboarding and starts in teaching; without one the
+// first turn goes through form selection like a new learner's would.
+func (e *Engine) StartSandbox(ctx context.Context, name, form string) (SandboxSession, error) {
+	form = strings.TrimSpace(form)
+	if form != "" && !singleDigitPattern.MatchString(form) {
+		return SandboxSession{}, ErrInvalidSandboxForm
+	}
+	name = strings.TrimSpace(name)
+	if name == "" {
+		name = defaultSandboxName
+	}
+	suffix := make([]byte, 8)
+	if _, err := rand.Read(suffix); err != nil {
+		return SandboxSession{}, fmt.Errorf("sandbox user id: %w", err)
+	}
+	userID := SandboxUserPrefix + hex.EncodeToString(suffix)
+
+	if store, ok := e.store.(sandboxUserStore); ok {
+		if err := store.CreateSandboxUser(userID, name); err != nil {
+			return SandboxSession{}, err
+		}
+	} else if err := e.store.SetUserName(userID, name); err != nil {
+		return SandboxSession{}, fmt.Errorf("create sandbox user: %w", err)
+	}
+	state := "onboarding_form"
+	if form != "" {
+		if err := e.store.SetUserForm(userID, form); err != nil {
+			return SandboxSession{}, fmt.Errorf("set sandbox form: %w", err)
+		}
+		state = "teaching"
+	}
+	conv, err := e.createConversation(userID, state)
+	if err != nil {
+		return SandboxSession{}, fmt.Errorf("create sandbox conversation: %w", err)
+	}
+	return SandboxSession{UserID: userID, ConversationID: conv.ID}, nil
+}
+
+// SandboxTurn runs one learner turn for a sandbox learner and returns the
+// reply. It refuses real learners, so staff can't speak as them.
+func (e *Engine) SandboxTurn(ctx context.Context, userID, text string) (string, error) {
+	if !IsSandboxUser(userID) || !e.store.UserExists(userID) {
+		return "", ErrNotSandboxUser
+	}
+	return e.ProcessMessage(ctx, chat.InboundMessage{
+		Channel:    sandboxChannel,
+		UserID:     userID,
+		ExternalID: userID,
+		Text:       text,
+	})
+}
+
+// CreateSandboxUser inserts a student row flagged as a sandbox learner.
+func (s *PostgresStore) CreateSandboxUser(externalID, name string) error {
+	ctx, cancel := context.WithTimeout(context.Background(), dbTimeout)
+	defer cancel()
+
+	if _, err := s.pool.Exec(ctx,
+		`INSERT INTO users (tenant_id, role, name, external_id, channel, sandbox)
+		 VALUES ($1::uuid, 'student', $2, $3, $4, true)`,
+		s.tenantID,
+		name,
+		externalID,
+		s.channel,
+	); err != nil {
+		return fmt.Errorf("create sandbox user: %w", err)
+	}
+	return nil
+}
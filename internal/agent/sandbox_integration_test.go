@@ -0,0 +1,55 @@
+// Copyright 2026 the P&AI authors. All rights reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+//go:build integration
+// +build integration
+
+package agent
+
+import (
+	"context"
+	"testing"
+
+	"github.com/p-n-ai/pai-bot/internal/adminapi"
+)
+
+func TestPostgresStore_SandboxLearnersStayOutOfAIUsage(t *testing.T) {
+	ctx := context.Background()
+	pool, tenantID := startSchedulerPostgres(t, ctx)
+
+	store, err := NewPostgresStore(ctx, pool)
+	if err != nil {
+		t.Fatalf("NewPostgresStore() error = %v", err)
+	}
+	if err := store.CreateSandboxUser(SandboxUserPrefix+"staff", "Staff"); err != nil {
+		t.Fatalf("CreateSandboxUser() error = %v", err)
+	}
+	if !store.UserExists(SandboxUserPrefix + "staff") {
+		t.Fatal("sandbox learner not found after CreateSandboxUser")
+	}
+
+	for _, userID := range []string{"sandbox-usage-learner", SandboxUserPrefix + "staff"} {
+		convID, err := store.CreateConversation(Conversation{UserID: userID, State: "teaching"})
+		if err != nil {
+			t.Fatalf("CreateConversation(%s) error = %v", userID, err)
+		}
+		if _, err := store.AddMessage(convID, StoredMessage{Role: "assistant", Content: "Jawapan", Model: "openai/gpt-5.4-mini", InputTokens: 10, OutputTokens: 5}); err != nil {
+			t.Fatalf("AddMessage(%s) error = %v", userID, err)
+		}
+	}
+
+	var sandbox bool
+	if err := pool.QueryRow(ctx,
+		`SELECT sandbox FROM users WHERE external_id = $1`, SandboxUserPrefix+"staff",
+	).Scan(&sandbox); err != nil || !sandbox {
+		t.Fatalf("users.sandbox = %v, %v; want true", sandbox, err)
+	}
+
+	usage, err := adminapi.New(pool, tenantID).GetAIUsage()
+	if err != nil {
+		t.Fatalf("GetAIUsage() error = %v", err)
+	}
+	if usage.TotalMessages != 1 || usage.TotalInputTokens != 10 {
+		t.Fatalf("AI usage = %d messages / %d input tokens, want only the real learner's 1 / 10", usage.TotalMessages, usage.TotalInputTokens)
+	}
+}
@@ -0,0 +1,57 @@
+// Copyright 2026 the P&AI authors. All rights reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package agent_test
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/p-n-ai/pai-bot/internal/agent"
+	"github.com/p-n-ai/pai-bot/internal/ai"
+)
+
+func TestSandboxTurnsSkipBudgetsAndRefuseRealLearners(t *testing.T) {
+	mockAI := ai.NewMockProvider("Jawapan tutor.")
+	budget := ai.NewInMemoryBudget()
+	subs := agent.NewMemorySubscriptionStore()
+	engine := agent.NewEngine(agent.EngineConfig{
+		AIRouter:          mockRouter(mockAI),
+		TenantID:          "tenant-1",
+		Budget:            budget,
+		Subscriptions:     subs,
+		SubscriptionPlans: agent.SubscriptionPlans{FreeDailyTokens: 1},
+	})
+	ctx := context.Background()
+
+	session, err := engine.StartSandbox(ctx, "", "2")
+	if err != nil {
+		t.Fatalf("StartSandbox() error = %v", err)
+	}
+	if !strings.HasPrefix(session.UserID, agent.SandboxUserPrefix) || session.ConversationID == "" {
+		t.Fatalf("StartSandbox() = %+v, want a sandbox learner with a conversation", session)
+	}
+	budget.SetBudget("tenant-1", session.UserID, 1)
+
+	for _, text := range []string{"What is a fraction?", "And a decimal?"} {
+		got, err := engine.SandboxTurn(ctx, session.UserID, text)
+		if err != nil || got != "Jawapan tutor." {
+			t.Fatalf("SandboxTurn(%q) = %q, %v; want the tutor answer despite exhausted budgets", text, got, err)
+		}
+	}
+	if used, _, _ := budget.Usage("tenant-1", session.UserID); used != 0 {
+		t.Errorf("sandbox tokens recorded against the budget: %d", used)
+	}
+
+	if _, err := engine.SandboxTurn(ctx, "42", "hi"); !errors.Is(err, agent.ErrNotSandboxUser) {
+		t.Fatalf("SandboxTurn(real learner) error = %v, want ErrNotSandboxUser", err)
+	}
+	if _, err := engine.SandboxTurn(ctx, agent.SandboxUserPrefix+"unknown", "hi"); !errors.Is(err, agent.ErrNotSandboxUser) {
+		t.Fatalf("SandboxTurn(unknown sandbox id) error = %v, want ErrNotSandboxUser", err)
+	}
+	if _, err := engine.StartSandbox(ctx, "Staff", "Form 4"); !errors.Is(err, agent.ErrInvalidSandboxForm) {
+		t.Fatalf("StartSandbox(bad form) error = %v, want ErrInvalidSandboxForm", err)
+	}
+}
@@ -0,0 +1,121 @@
+// Copyright 2026 the P&AI authors. All rights reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package agent
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+
+	"github.com/p-n-ai/pai-bot/internal/chat"
+)
+
+// Enqueue stores msg in scheduled_messages until at. It makes PostgresStore
+// the gateway's persistent chat.DelayQueue.
+func (s *PostgresStore) Enqueue(ctx context.Context, msg chat.OutboundMessage, at time.Time) (string, error) {
+	ctx, cancel := context.WithTimeout(ctx, dbTimeout)
+	defer cancel()
+
+	payload, err := json.Marshal(msg)
+	if err != nil {
+		return "", fmt.Errorf("marshal scheduled message: %w", err)
+	}
+	var id string
+	if err := s.pool.QueryRow(ctx,
+		`INSERT INTO scheduled_messages (tenant_id, channel, external_id, message, send_at)
+		 VALUES ($1::uuid, $2, $3, $4::jsonb, $5)
+		 RETURNING id::text`,
+		s.tenantID, msg.Channel, msg.UserID, string(payload), at,
+	).Scan(&id); err != nil {
+		return "", fmt.Errorf("insert scheduled message: %w", err)
+	}
+	return id, nil
+}
+
+func (s *PostgresStore) Pending(ctx context.Context, channel, userID string) ([]chat.ScheduledMessage, error) {
+	ctx, cancel := context.WithTimeout(ctx, dbTimeout)
+	defer cancel()
+
+	rows, err := s.pool.Query(ctx,
+		`SELECT id::text, message, send_at
+		 FROM scheduled_messages
+		 WHERE tenant_id = $1::uuid AND channel = $2 AND external_id = $3
+		 ORDER BY send_at`,
+		s.tenantID, channel, userID,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("list scheduled messages: %w", err)
+	}
+	pending, err := collectScheduledMessages(rows)
+	if err != nil {
+		return nil, fmt.Errorf("list scheduled messages: %w", err)
+	}
+	return pending, nil
+}
+
+func (s *PostgresStore) CancelForUser(ctx context.Context, channel, userID string) (int, error) {
+	ctx, cancel := context.WithTimeout(ctx, dbTimeout)
+	defer cancel()
+
+	tag, err := s.pool.Exec(ctx,
+		`DELETE FROM scheduled_messages
+		 WHERE tenant_id = $1::uuid AND channel = $2 AND external_id = $3`,
+		s.tenantID, channel, userID,
+	)
+	if err != nil {
+		return 0, fmt.Errorf("cancel scheduled messages: %w", err)
+	}
+	return int(tag.RowsAffected()), nil
+}
+
+// ClaimDue deletes and returns due messages. SKIP LOCKED lets several
+// replicas drain the queue without sending a message twice.
+func (s *PostgresStore) ClaimDue(ctx context.Context, now time.Time, limit int) ([]chat.ScheduledMessage, error) {
+	ctx, cancel := context.WithTimeout(ctx, dbTimeout)
+	defer cancel()
+
+	rows, err := s.pool.Query(ctx,
+		`DELETE FROM scheduled_messages
+		 WHERE id IN (
+			SELECT id
+			FROM scheduled_messages
+			WHERE tenant_id = $1::uuid AND send_at <= $2
+			ORDER BY send_at
+			LIMIT $3
+			FOR UPDATE SKIP LOCKED
+		 )
+		 RETURNING id::text, message, send_at`,
+		s.tenantID, now, limit,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("claim scheduled messages: %w", err)
+	}
+	due, err := collectScheduledMessages(rows)
+	if err != nil {
+		return nil, fmt.Errorf("claim scheduled messages: %w", err)
+	}
+	return due, nil
+}
+
+func collectScheduledMessages(rows pgx.Rows) ([]chat.ScheduledMessage, error) {
+	defer rows.Close()
+	var messages []chat.ScheduledMessage
+	for rows.Next() {
+		var (
+			m       chat.ScheduledMessage
+			payload []byte
+		)
+		if err := rows.Scan(&m.ID, &payload, &m.SendAt); err != nil {
+			return nil, err
+		}
+		if err := json.Unmarshal(payload, &m.Message); err != nil {
+			return nil, fmt.Errorf("decode scheduled message %s: %w", m.ID, err)
+		}
+		messages = append(messages, m)
+	}
+	return messages, rows.Err()
+}
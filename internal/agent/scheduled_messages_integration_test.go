@@ -0,0 +1,59 @@
+// Copyright 2026 the P&AI authors. All rights reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+//go:build integration
+// +build integration
+
+package agent
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/p-n-ai/pai-bot/internal/chat"
+)
+
+func TestPostgresStore_DelayQueueClaimsDueAndCancels(t *testing.T) {
+	ctx := context.Background()
+	pool, _ := startSchedulerPostgres(t, ctx)
+
+	store, err := NewPostgresStore(ctx, pool)
+	if err != nil {
+		t.Fatalf("NewPostgresStore() error = %v", err)
+	}
+	now := time.Now().Truncate(time.Second)
+	enqueue := func(userID, text string, at time.Time) {
+		t.Helper()
+		msg := chat.OutboundMessage{Channel: "telegram", UserID: userID, Text: text, ParseMode: "Markdown"}
+		if _, err := store.Enqueue(ctx, msg, at); err != nil {
+			t.Fatalf("Enqueue(%s) error = %v", text, err)
+		}
+	}
+	enqueue("learner-1", "due", now.Add(-time.Minute))
+	enqueue("learner-1", "tomorrow", now.Add(24*time.Hour))
+	enqueue("learner-2", "cancelled", now.Add(-time.Minute))
+
+	if dropped, err := store.CancelForUser(ctx, "telegram", "learner-2"); err != nil || dropped != 1 {
+		t.Fatalf("CancelForUser() = %d, %v; want 1, nil", dropped, err)
+	}
+
+	due, err := store.ClaimDue(ctx, now, 10)
+	if err != nil {
+		t.Fatalf("ClaimDue() error = %v", err)
+	}
+	if len(due) != 1 || due[0].Message.Text != "due" || due[0].Message.ParseMode != "Markdown" {
+		t.Fatalf("due = %+v, want learner-1's due message round-tripped", due)
+	}
+	if again, _ := store.ClaimDue(ctx, now, 10); len(again) != 0 {
+		t.Fatalf("second ClaimDue() = %+v, want none", again)
+	}
+
+	pending, err := store.Pending(ctx, "telegram", "learner-1")
+	if err != nil {
+		t.Fatalf("Pending() error = %v", err)
+	}
+	if len(pending) != 1 || pending[0].Message.Text != "tomorrow" || !pending[0].SendAt.Equal(now.Add(24*time.Hour)) {
+		t.Fatalf("pending = %+v, want tomorrow's message", pending)
+	}
+}
@@ -8,12 +8,15 @@ import (
 	"fmt"
 	"log/slog"
 	"regexp"
+	"slices"
+	"strconv"
 	"strings"
 	"time"
 
 	"github.com/p-n-ai/pai-bot/internal/ai"
 	"github.com/p-n-ai/pai-bot/internal/chat"
 	"github.com/p-n-ai/pai-bot/internal/i18n"
+	"github.com/p-n-ai/pai-bot/internal/platform/featureflags"
 	"github.com/p-n-ai/pai-bot/internal/progress"
 )
 
@@ -43,6 +46,7 @@ func DefaultSchedulerConfig() SchedulerConfig {
 type nudgeLanguageStore interface {
 	GetUserPreferredLanguage(userID string) (string, bool)
 	GetUserABGroup(userID string) (string, bool)
+	GetUserForm(userID string) (string, bool)
 }
 
 var nudgeSentenceBreakRE = regexp.MustCompile(`([.!?。！？])\s+`)
@@ -57,6 +61,21 @@ func IsQuietHours(t time.Time) bool {
 	return hour >= QuietHoursStart || hour < QuietHoursEnd
 }
 
+// quietHoursEndAfter returns the first end of quiet hours (07:00 MYT) at or
+// after t.
+func quietHoursEndAfter(t time.Time) time.Time {
+	loc, err := time.LoadLocation("Asia/Kuala_Lumpur")
+	if err != nil {
+		loc = time.FixedZone("MYT", 8*60*60)
+	}
+	local := t.In(loc)
+	end := time.Date(local.Year(), local.Month(), local.Day(), QuietHoursEnd, 0, 0, 0, loc)
+	if end.Before(local) {
+		end = end.AddDate(0, 0, 1)
+	}
+	return end
+}
+
 // CanNudge returns true if a nudge can be sent at the given time with the given daily count.
 func CanNudge(t time.Time, nudgesSentToday int) bool {
 	if IsQuietHours(t) {
@@ -117,6 +136,14 @@ type Scheduler struct {
 	groups        GroupStore
 	tenantID      string
 	parentReports WeeklyParentReportSource
+	dailyProblems DailyProblemSource
+	studyPlans    StudyPlanStore
+	examCalendar  ExamCalendar
+	featureFlags  func() featureflags.Features
+	contentFilter *ContentFilter
+	lifecycle     UserLifecycleSource
+	presence      PresenceSource
+	templates     *i18n.Overrides
 	gateway  *chat.Gateway
 	aiRouter *ai.Router
 	store    nudgeLanguageStore
@@ -155,6 +182,48 @@ func (s *Scheduler) SetGroupStore(groups GroupStore, tenantID string) {
 	s.tenantID = tenantID
 }
 
+// SetStudyPlans lets active /plan study plans pace and focus nudges.
+func (s *Scheduler) SetStudyPlans(plans StudyPlanStore) {
+	s.studyPlans = plans
+}
+
+// SetRevisionMode lets the revision_mode feature tighten review intervals as
+// a learner's exam in calendar approaches.
+func (s *Scheduler) SetRevisionMode(calendar ExamCalendar, flags func() featureflags.Features) {
+	s.examCalendar = calendar
+	s.featureFlags = flags
+}
+
+// SetContentFilter screens AI-written nudges; a blocked nudge falls back to
+// the fixed template.
+func (s *Scheduler) SetContentFilter(f *ContentFilter) {
+	s.contentFilter = f
+}
+
+// SetMessageTemplates applies a tenant's review reminder copy in place of the
+// built-in reminder when no AI-written nudge is sent.
+func (s *Scheduler) SetMessageTemplates(templates *i18n.Overrides) {
+	s.templates = templates
+}
+
+// SetUserLifecycle pauses nudges to learners who blocked the bot or whose
+// account is gone. They are nudged again once a later send gets through.
+func (s *Scheduler) SetUserLifecycle(src UserLifecycleSource) {
+	s.lifecycle = src
+}
+
+// PresenceSource reports whether a learner is chatting live right now, as
+// chat.WSChannel does for the web embed.
+type PresenceSource interface {
+	ActivelyChatting(userID string, now time.Time) bool
+}
+
+// SetPresence holds nudges while a learner is actively chatting; the tutor
+// is already in front of them.
+func (s *Scheduler) SetPresence(src PresenceSource) {
+	s.presence = src
+}
+
 // Start begins the scheduler loop. Blocks until context is cancelled.
 func (s *Scheduler) Start(ctx context.Context, userIDs []string) {
 	ticker := time.NewTicker(s.config.CheckInterval)
@@ -162,13 +231,17 @@ func (s *Scheduler) Start(ctx context.Context, userIDs []string) {
 
 	// Start daily summary on a precise timer (22:00 MYT), not a polling tick.
 	go s.runDailySummaryTimer(ctx, userIDs)
-	go s.runWeeklyParentReportTimer(ctx)
 
 	// Start weekly leaderboard recap on Monday 8:00 AM MYT.
 	if s.groups != nil {
 		go s.runWeeklyLeaderboardTimer(ctx)
 	}
 
+	// Start the problem of the day push at 8:00 AM MYT.
+	if s.dailyProblems != nil {
+		go s.runDailyProblemTimer(ctx)
+	}
+
 	s.logger.Info("scheduler started", "interval", s.config.CheckInterval)
 
 	for {
@@ -216,7 +289,8 @@ func timeUntilNext(hour, minute int) time.Duration {
 func (s *Scheduler) checkAndNudge(ctx context.Context, userIDs []string) {
 	now := time.Now()
 
-	if IsQuietHours(now) {
+	// Quiet-hours checks only queue morning nudges, which needs a delay queue.
+	if IsQuietHours(now) && !s.canSendLater() {
 		return
 	}
 
@@ -286,17 +360,15 @@ func (s *Scheduler) sendWeeklyLeaderboards(ctx context.Context) {
 			continue
 		}
 
-		entries, err := s.groups.GetWeeklyLeaderboard(g.ID, 10)
+		msg, err := groupLeaderboard(s.groups, g, i18n.DefaultLocale)
 		if err != nil {
 			s.logger.Error("failed to get leaderboard", "group_id", g.ID, "error", err)
 			continue
 		}
-		if len(entries) == 0 {
+		if msg == "" {
 			continue
 		}
 
-		msg := formatLeaderboard(g.Name, entries, i18n.DefaultLocale)
-
 		recipients, err := s.groups.GetGroupMembersWithChannel(g.ID)
 		if err != nil {
 			s.logger.Error("failed to get group members", "group_id", g.ID, "error", err)
@@ -323,7 +395,27 @@ func (s *Scheduler) checkUser(ctx context.Context, userID string, now time.Time)
 	if err != nil {
 		return fmt.Errorf("get nudge count: %w", err)
 	}
-	if !CanNudge(now, count) {
+	// During quiet hours, queue the nudge for the morning instead; the
+	// learner's next message drops it if they come back before then.
+	sendAt := now
+	if IsQuietHours(now) && s.canSendLater() {
+		sendAt = quietHoursEndAfter(now)
+	}
+	if !CanNudge(sendAt, count) {
+		return nil
+	}
+
+	// Skip users who blocked the bot; nudging them only adds failures.
+	if s.lifecycle != nil {
+		if state, ok := s.lifecycle.UserLifecycleState(userID); ok && state != UserActive {
+			return nil
+		}
+	}
+
+	// Skip learners who are mid-conversation; the next check catches them
+	// once they go quiet.
+	if s.presence != nil && s.presence.ActivelyChatting(userID, now) {
+		s.logger.Debug("nudge held, learner is chatting", "user_id", userID)
 		return nil
 	}
 
@@ -334,8 +426,24 @@ func (s *Scheduler) checkUser(ctx context.Context, userID string, now time.Time)
 		}
 	}
 
+	// Queue at most one morning nudge per learner.
+	if sendAt.After(now) {
+		pending, err := s.gateway.PendingSends(ctx, "telegram", userID)
+		if err != nil {
+			return fmt.Errorf("list pending sends: %w", err)
+		}
+		if len(pending) > 0 {
+			return nil
+		}
+	}
+
+	week, hasPlan := s.currentPlanWeek(userID, now)
+	if hasPlan && count >= week.NudgesPerDay {
+		return nil
+	}
+
 	// Check for due reviews.
-	dueItems, err := s.tracker.GetDueReviews(userID)
+	dueItems, err := s.dueReviews(userID, now)
 	if err != nil {
 		return fmt.Errorf("get due reviews: %w", err)
 	}
@@ -343,7 +451,10 @@ func (s *Scheduler) checkUser(ctx context.Context, userID string, now time.Time)
 		return nil
 	}
 
-	// Pick the most overdue topic.
+	// Pick the most overdue topic, preferring this week's study-plan topics.
+	if hasPlan {
+		dueItems = preferPlanTopics(dueItems, week.TopicIDs)
+	}
 	item := dueItems[0]
 	for _, di := range dueItems[1:] {
 		if di.NextReviewAt.Before(item.NextReviewAt) {
@@ -351,8 +462,8 @@ func (s *Scheduler) checkUser(ctx context.Context, userID string, now time.Time)
 		}
 	}
 
-	// Build nudge message.
-	msg := s.buildNudgeMessage(ctx, userID, item, now)
+	// Build nudge message for the time it will be read.
+	msg := s.buildNudgeMessage(ctx, userID, item, sendAt)
 
 	// Send via chat gateway (default to telegram channel).
 	out := chat.OutboundMessage{
@@ -360,7 +471,11 @@ func (s *Scheduler) checkUser(ctx context.Context, userID string, now time.Time)
 		UserID:  userID,
 		Text:    msg,
 	}
-	if err := s.gateway.Send(ctx, out); err != nil {
+	if sendAt.After(now) {
+		if _, err := s.gateway.SendAt(ctx, out, sendAt); err != nil {
+			return fmt.Errorf("queue nudge: %w", err)
+		}
+	} else if err := s.gateway.Send(ctx, out); err != nil {
 		return fmt.Errorf("send nudge: %w", err)
 	}
 
@@ -373,11 +488,77 @@ func (s *Scheduler) checkUser(ctx context.Context, userID string, now time.Time)
 		"user_id", userID,
 		"topic_id", item.TopicID,
 		"due_since", now.Sub(item.NextReviewAt).Round(time.Minute),
+		"send_at", sendAt,
 	)
 
 	return nil
 }
 
+// canSendLater reports whether nudges can be queued past quiet hours.
+func (s *Scheduler) canSendLater() bool {
+	return s.gateway != nil && s.gateway.CanSendLater()
+}
+
+// dueReviews returns the user's due items, pulled forward by revisionDue when
+// the user is inside an exam's revision window.
+func (s *Scheduler) dueReviews(userID string, now time.Time) ([]progress.ProgressItem, error) {
+	if !s.inRevisionWindow(userID, now) {
+		return s.tracker.GetDueReviews(userID)
+	}
+	items, err := s.tracker.GetAllProgress(userID)
+	if err != nil {
+		return nil, err
+	}
+	var due []progress.ProgressItem
+	for _, item := range items {
+		if revisionDue(item, now) {
+			due = append(due, item)
+		}
+	}
+	return due, nil
+}
+
+func (s *Scheduler) inRevisionWindow(userID string, now time.Time) bool {
+	if len(s.examCalendar) == 0 || s.featureFlags == nil || !s.featureFlags().Enabled(featureflags.RevisionMode) {
+		return false
+	}
+	form := ""
+	if s.store != nil {
+		form, _ = s.store.GetUserForm(userID)
+	}
+	sitting, ok := s.examCalendar.NextFor(form, now)
+	return ok && sitting.InRevisionWindow(now)
+}
+
+func (s *Scheduler) currentPlanWeek(userID string, now time.Time) (StudyPlanWeek, bool) {
+	if s.studyPlans == nil {
+		return StudyPlanWeek{}, false
+	}
+	plan, found, err := s.studyPlans.GetStudyPlan(userID)
+	if err != nil {
+		s.logger.Warn("failed to load study plan for nudge", "user_id", userID, "error", err)
+		return StudyPlanWeek{}, false
+	}
+	if !found {
+		return StudyPlanWeek{}, false
+	}
+	return plan.CurrentWeek(now)
+}
+
+// preferPlanTopics narrows due items to the given topics when any are due.
+func preferPlanTopics(items []progress.ProgressItem, topicIDs []string) []progress.ProgressItem {
+	var planned []progress.ProgressItem
+	for _, item := range items {
+		if slices.Contains(topicIDs, item.TopicID) {
+			planned = append(planned, item)
+		}
+	}
+	if len(planned) == 0 {
+		return items
+	}
+	return planned
+}
+
 // CheckUserForNudge triggers a single due-review nudge check for the user at the given time.
 func (s *Scheduler) CheckUserForNudge(ctx context.Context, userID string, now time.Time) error {
 	return s.checkUser(ctx, userID, now)
@@ -390,17 +571,30 @@ func (s *Scheduler) buildNudgeMessage(ctx context.Context, userID string, item p
 			return msg
 		}
 	}
+	if body, ok := s.templates.Lookup(locale, i18n.MsgReviewReminder); ok {
+		return i18n.Fill(body, map[string]string{
+			"topic":        item.TopicID,
+			"mastery":      fmt.Sprintf("%d%%", int(item.MasteryScore*100)),
+			"days_overdue": strconv.Itoa(max(int(now.Sub(item.NextReviewAt).Hours()/24), 0)),
+			"streak":       strconv.Itoa(s.currentStreak(userID)),
+		})
+	}
 	return buildDefaultNudgeMessage(item, now, locale)
 }
 
-func (s *Scheduler) generateAINudge(ctx context.Context, userID string, item progress.ProgressItem, now time.Time, locale string) (string, bool) {
-	streakDays := 0
-	if s.streaks != nil {
-		streak, err := s.streaks.GetStreak(userID)
-		if err == nil {
-			streakDays = streak.CurrentStreak
-		}
+func (s *Scheduler) currentStreak(userID string) int {
+	if s.streaks == nil {
+		return 0
 	}
+	streak, err := s.streaks.GetStreak(userID)
+	if err != nil {
+		return 0
+	}
+	return streak.CurrentStreak
+}
+
+func (s *Scheduler) generateAINudge(ctx context.Context, userID string, item progress.ProgressItem, now time.Time, locale string) (string, bool) {
+	streakDays := s.currentStreak(userID)
 
 	totalXP := 0
 	if s.xp != nil {
@@ -432,6 +626,10 @@ func (s *Scheduler) generateAINudge(ctx context.Context, userID string, item pro
 	}
 
 	resp, err := s.aiRouter.Complete(ctx, ai.CompletionRequest{
+		RequestMetadata: ai.RequestMetadata{
+			Tenant:   s.tenantID,
+			UserHash: ai.HashUser(s.tenantID, userID),
+		},
 		Task:        ai.TaskNudge,
 		MaxTokens:   60,
 		Temperature: 0.7,
@@ -470,6 +668,10 @@ func (s *Scheduler) generateAINudge(ctx context.Context, userID string, item pro
 	if msg == "" {
 		return "", false
 	}
+	if s.contentFilter.Blocked(msg) {
+		s.logger.Warn("ai nudge blocked by content filter; using template", "user_id", userID)
+		return "", false
+	}
 	return formatAINudgeMessage(msg), true
 }
 
@@ -155,3 +155,98 @@ func TestScheduler_DailySummarySkipsInactiveUser(t *testing.T) {
 		t.Errorf("expected no message for inactive user, got %d", len(mockCh.SentMessages))
 	}
 }
+
+func TestScheduler_QueuesQuietHourNudgeForMorning(t *testing.T) {
+	tracker := progress.NewMemoryTracker()
+	_ = tracker.SetMastery("user1", "malaysia-kssm", "F1-02", 0.4)
+	mockCh := &chat.MockChannel{}
+	gw := chat.NewGateway()
+	gw.Register("telegram", mockCh)
+	gw.SetDelayQueue(chat.NewMemoryDelayQueue())
+	scheduler := agent.NewScheduler(
+		agent.SchedulerConfig{CheckInterval: time.Second, MaxNudgesPerDay: 3},
+		tracker, nil, nil, nil,
+		agent.NewMemoryNudgeTracker(), gw, nil, nil,
+	)
+	ctx := context.Background()
+
+	loc, _ := time.LoadLocation("Asia/Kuala_Lumpur")
+	y, m, d := time.Now().In(loc).Date()
+	night := time.Date(y, m, d, 23, 0, 0, 0, loc)
+	for range 2 {
+		if err := scheduler.CheckUserForNudge(ctx, "user1", night); err != nil {
+			t.Fatalf("CheckUserForNudge() error = %v", err)
+		}
+	}
+
+	if len(mockCh.SentMessages) != 0 {
+		t.Fatalf("sent during quiet hours = %d, want none", len(mockCh.SentMessages))
+	}
+	pending, _ := gw.PendingSends(ctx, "telegram", "user1")
+	if len(pending) != 1 {
+		t.Fatalf("pending = %+v, want one queued nudge", pending)
+	}
+	if want := time.Date(y, m, d+1, agent.QuietHoursEnd, 0, 0, 0, loc); !pending[0].SendAt.Equal(want) {
+		t.Fatalf("nudge queued for %v, want %v", pending[0].SendAt, want)
+	}
+}
+
+func TestScheduler_SkipsQuietHoursWithoutDelayQueue(t *testing.T) {
+	tracker := progress.NewMemoryTracker()
+	_ = tracker.SetMastery("user1", "malaysia-kssm", "F1-02", 0.4)
+	mockCh := &chat.MockChannel{}
+	gw := chat.NewGateway()
+	gw.Register("telegram", mockCh)
+	scheduler := agent.NewScheduler(
+		agent.SchedulerConfig{CheckInterval: time.Second, MaxNudgesPerDay: 3},
+		tracker, nil, nil, nil,
+		agent.NewMemoryNudgeTracker(), gw, nil, nil,
+	)
+
+	loc, _ := time.LoadLocation("Asia/Kuala_Lumpur")
+	night := time.Date(2026, 3, 18, 23, 0, 0, 0, loc)
+	if err := scheduler.CheckUserForNudge(context.Background(), "user1", night); err != nil {
+		t.Fatalf("CheckUserForNudge() error = %v", err)
+	}
+	if len(mockCh.SentMessages) != 0 {
+		t.Fatalf("sent during quiet hours = %d, want none", len(mockCh.SentMessages))
+	}
+}
+
+type fakePresence map[string]bool
+
+func (p fakePresence) ActivelyChatting(userID string, _ time.Time) bool {
+	return p[userID]
+}
+
+func TestScheduler_HoldsNudgeWhileLearnerIsChatting(t *testing.T) {
+	tracker := progress.NewMemoryTracker()
+	_ = tracker.SetMastery("user1", "malaysia-kssm", "F1-02", 0.4)
+	mockCh := &chat.MockChannel{}
+	gw := chat.NewGateway()
+	gw.Register("telegram", mockCh)
+	scheduler := agent.NewScheduler(
+		agent.SchedulerConfig{CheckInterval: time.Second, MaxNudgesPerDay: 3},
+		tracker, nil, nil, nil,
+		agent.NewMemoryNudgeTracker(), gw, nil, nil,
+	)
+	presence := fakePresence{"user1": true}
+	scheduler.SetPresence(presence)
+
+	loc, _ := time.LoadLocation("Asia/Kuala_Lumpur")
+	morning := time.Date(2026, 3, 18, 10, 0, 0, 0, loc)
+	if err := scheduler.CheckUserForNudge(context.Background(), "user1", morning); err != nil {
+		t.Fatalf("CheckUserForNudge() error = %v", err)
+	}
+	if len(mockCh.SentMessages) != 0 {
+		t.Fatalf("sent while chatting = %d, want none", len(mockCh.SentMessages))
+	}
+
+	presence["user1"] = false
+	if err := scheduler.CheckUserForNudge(context.Background(), "user1", morning.Add(10*time.Minute)); err != nil {
+		t.Fatalf("CheckUserForNudge() error = %v", err)
+	}
+	if len(mockCh.SentMessages) != 1 {
+		t.Fatalf("sent after going quiet = %d, want the held nudge", len(mockCh.SentMessages))
+	}
+}
@@ -0,0 +1,494 @@
+// Copyright 2026 the P&AI authors. All rights reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package agent
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"math"
+	"sort"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/p-n-ai/pai-bot/internal/curriculum"
+	"github.com/p-n-ai/pai-bot/internal/i18n"
+)
+
+const (
+	// DefaultFrustrationThreshold is the frustration score that counts as a
+	// spike when the job is given none.
+	DefaultFrustrationThreshold = 0.5
+	// sentimentWindow is how many of the learner's latest messages the
+	// frustration score looks at, so an early rough patch fades out.
+	sentimentWindow = 10
+	// sentimentMinMessages keeps one grumpy message from counting as a spike.
+	sentimentMinMessages = 3
+	// sessionSentimentBatchSize caps the sessions one run scores; the rest
+	// are picked up by the next run.
+	sessionSentimentBatchSize = 500
+)
+
+var (
+	frustrationMarkers = []string{
+		"give up",
+		"i quit",
+		"stupid",
+		"useless",
+		"boring",
+		"hate this",
+		"hate maths",
+		"hate math",
+		"annoying",
+		"forget it",
+		"nevermind",
+		"never mind",
+		"ugh",
+		"so hard",
+		"too hard",
+		"susah sangat",
+		"susah gila",
+		"bosan",
+		"penat",
+		"malas",
+		"geram",
+		"stress",
+		"tak nak belajar",
+		"x nak belajar",
+	}
+	// Chinese has no word boundaries, so these match as plain substrings.
+	frustrationSubstrings = []string{"好难", "太难", "烦", "不想学", "算了", "放弃"}
+	// dismissiveReplies only count as the whole message: "no" inside a
+	// sentence is not a brush-off.
+	dismissiveReplies = map[string]bool{
+		"idk": true, "dunno": true, "no idea": true, "whatever": true, "ok la": true,
+		"tak tahu": true, "tak tau": true, "x tau": true, "entah": true, "tak kisah": true,
+		"不知道": true, "随便": true,
+	}
+	questionOpeners = []string{"why", "how", "what", "when", "can you", "kenapa", "mengapa", "bagaimana", "macam mana", "apa", "boleh tak", "为什么", "怎么", "什么"}
+)
+
+// SessionSentiment is how a session is going for the learner, scored from
+// the transcript and kept beside the session summary. Both scores run from
+// 0 to 1.
+type SessionSentiment struct {
+	// Frustration weighs confusion, frustration and brush-off signals in
+	// the learner's latest messages, recent ones most.
+	Frustration float64 `json:"frustration"`
+	// Engagement rises with the number of turns, longer replies and
+	// questions the learner asks.
+	Engagement   float64   `json:"engagement"`
+	UserMessages int       `json:"user_messages"`
+	ScoredAt     time.Time `json:"scored_at"`
+	// SpikeAt is when frustration first reached the threshold. A session
+	// spikes at most once, so learners and teachers hear about it once.
+	SpikeAt *time.Time `json:"spike_at,omitempty"`
+}
+
+// ScoreSessionSentiment scores the learner's visible messages in a
+// transcript. ScoredAt and SpikeAt are left for the caller.
+func ScoreSessionSentiment(messages []StoredMessage) SessionSentiment {
+	var texts []string
+	for _, m := range messages {
+		if m.Role == "user" && m.Visible() && strings.TrimSpace(m.Content) != "" {
+			texts = append(texts, m.Content)
+		}
+	}
+	sentiment := SessionSentiment{UserMessages: len(texts)}
+	if len(texts) == 0 {
+		return sentiment
+	}
+
+	recent := texts[max(0, len(texts)-sentimentWindow):]
+	var weighted, weights float64
+	for i, text := range recent {
+		w := float64(i + 1)
+		weighted += w * frustrationSignal(text)
+		weights += w
+	}
+	sentiment.Frustration = roundScore(weighted / weights)
+
+	var words, questions int
+	for _, text := range texts {
+		words += len(strings.Fields(text))
+		if isLearnerQuestion(text) {
+			questions++
+		}
+	}
+	n := float64(len(texts))
+	turns := math.Min(n/8, 1)
+	length := math.Min(float64(words)/n/6, 1)
+	curiosity := math.Min(float64(questions)/n*2, 1)
+	sentiment.Engagement = roundScore(0.4*turns + 0.4*length + 0.2*curiosity)
+	return sentiment
+}
+
+// frustrationSignal scores one learner message from 0 to 1.
+func frustrationSignal(text string) float64 {
+	lower := strings.ToLower(strings.TrimSpace(text))
+	score := 0.0
+	switch {
+	case containsMarker(lower, frustrationMarkers) || containsAny(lower, frustrationSubstrings...):
+		score = 1
+	case isConfusionSignal(lower):
+		score = 0.6
+	case dismissiveReplies[strings.Trim(lower, ".!? ")]:
+		score = 0.5
+	}
+	if isShouting(text) {
+		score += 0.4
+	}
+	if strings.Contains(text, "???") || strings.Contains(text, "!!!") {
+		score += 0.3
+	}
+	return math.Min(score, 1)
+}
+
+// isShouting is a message of at least six letters, all in capitals.
+func isShouting(text string) bool {
+	letters := 0
+	for _, r := range text {
+		if !unicode.IsLetter(r) {
+			continue
+		}
+		if !unicode.IsUpper(r) {
+			return false
+		}
+		letters++
+	}
+	return letters >= 6
+}
+
+func isLearnerQuestion(text string) bool {
+	lower := strings.ToLower(strings.TrimSpace(text))
+	if strings.Contains(lower, "?") || strings.Contains(lower, "？") {
+		return true
+	}
+	for _, opener := range questionOpeners {
+		if strings.HasPrefix(lower, opener) {
+			return true
+		}
+	}
+	return false
+}
+
+func roundScore(v float64) float64 {
+	return math.Round(v*100) / 100
+}
+
+// SessionSentimentStore is what the sentiment job reads sessions from and
+// writes scores to. MemoryStore and PostgresStore implement it.
+type SessionSentimentStore interface {
+	// ListConversationsToScore returns sessions with learner messages since
+	// since that are newer than the session's last score.
+	ListConversationsToScore(ctx context.Context, since time.Time, limit int) ([]string, error)
+	GetConversation(id string) (*Conversation, error)
+	SetConversationSentiment(conversationID string, sentiment SessionSentiment) error
+	GetUserName(userID string) (string, bool)
+	GetUserPreferredLanguage(userID string) (string, bool)
+	ResolveUserUUID(externalID string) (string, error)
+}
+
+// SessionSentimentConfig wires a SessionSentimentAnalyzer. Notifier sends
+// check-ins and teacher alerts; Groups finds the learner's teachers. Events
+// and CurriculumLoader are optional.
+type SessionSentimentConfig struct {
+	Store            SessionSentimentStore
+	Notifier         Notifier
+	Groups           GroupStore
+	Events           EventLogger
+	CurriculumLoader *curriculum.Loader
+}
+
+// SessionSentimentOptions controls one run. A zero FrustrationThreshold
+// uses DefaultFrustrationThreshold and a zero Now uses the current time.
+type SessionSentimentOptions struct {
+	Since                time.Time
+	FrustrationThreshold float64
+	// CheckIn sends the learner a supportive message on a spike.
+	CheckIn bool
+	// TeacherAlert tells the teachers of the learner's groups on a spike.
+	TeacherAlert bool
+	Now          time.Time
+}
+
+// SessionSentimentReport summarises a run.
+type SessionSentimentReport struct {
+	Scored        int `json:"scored"`
+	Spikes        int `json:"spikes"`
+	CheckIns      int `json:"check_ins"`
+	TeacherAlerts int `json:"teacher_alerts"`
+}
+
+// SessionSentimentAnalyzer scores recently active sessions and reacts to
+// frustration spikes.
+type SessionSentimentAnalyzer struct {
+	cfg SessionSentimentConfig
+}
+
+func NewSessionSentimentAnalyzer(cfg SessionSentimentConfig) *SessionSentimentAnalyzer {
+	if cfg.Events == nil {
+		cfg.Events = NopEventLogger{}
+	}
+	return &SessionSentimentAnalyzer{cfg: cfg}
+}
+
+// Run scores every session with new learner messages since opts.Since. A
+// spike is recorded as a frustration_spike event; check-ins and teacher
+// alerts are not sent in quiet hours.
+func (a *SessionSentimentAnalyzer) Run(ctx context.Context, opts SessionSentimentOptions) (SessionSentimentReport, error) {
+	var report SessionSentimentReport
+	if a.cfg.Store == nil {
+		return report, fmt.Errorf("session sentiment store is required")
+	}
+	now := opts.Now
+	if now.IsZero() {
+		now = time.Now()
+	}
+	threshold := opts.FrustrationThreshold
+	if threshold <= 0 {
+		threshold = DefaultFrustrationThreshold
+	}
+
+	ids, err := a.cfg.Store.ListConversationsToScore(ctx, opts.Since, sessionSentimentBatchSize)
+	if err != nil {
+		return report, err
+	}
+	for _, id := range ids {
+		if err := ctx.Err(); err != nil {
+			return report, err
+		}
+		conv, err := a.cfg.Store.GetConversation(id)
+		if err != nil {
+			return report, fmt.Errorf("load conversation %s: %w", id, err)
+		}
+		sentiment := ScoreSessionSentiment(conv.Messages)
+		sentiment.ScoredAt = now
+		if conv.Sentiment != nil {
+			sentiment.SpikeAt = conv.Sentiment.SpikeAt
+		}
+		spike := sentiment.SpikeAt == nil && sentiment.UserMessages >= sentimentMinMessages && sentiment.Frustration >= threshold
+		if spike {
+			sentiment.SpikeAt = &now
+		}
+		if err := a.cfg.Store.SetConversationSentiment(id, sentiment); err != nil {
+			return report, err
+		}
+		report.Scored++
+		if spike {
+			report.Spikes++
+			a.handleSpike(ctx, conv, sentiment, opts, now, &report)
+		}
+	}
+	return report, nil
+}
+
+func (a *SessionSentimentAnalyzer) handleSpike(ctx context.Context, conv *Conversation, sentiment SessionSentiment, opts SessionSentimentOptions, now time.Time, report *SessionSentimentReport) {
+	checkIn, alerts := false, 0
+	if a.cfg.Notifier != nil && !IsQuietHours(now) {
+		if opts.CheckIn {
+			locale := a.locale(conv.UserID)
+			a.cfg.Notifier.Notify(ctx, "", conv.UserID, i18n.S(locale, i18n.MsgFrustrationCheckIn))
+			checkIn = true
+			report.CheckIns++
+		}
+		if opts.TeacherAlert {
+			alerts = a.alertTeachers(ctx, conv)
+			report.TeacherAlerts += alerts
+		}
+	}
+	if err := a.cfg.Events.LogEvent(Event{
+		ConversationID: conv.ID,
+		UserID:         conv.UserID,
+		EventType:      "frustration_spike",
+		Data: map[string]any{
+			"frustration":    sentiment.Frustration,
+			"engagement":     sentiment.Engagement,
+			"check_in":       checkIn,
+			"teacher_alerts": alerts,
+		},
+		CreatedAt: now,
+	}); err != nil {
+		slog.Warn("failed to log frustration spike", "conversation_id", conv.ID, "error", err)
+	}
+}
+
+// alertTeachers messages each teacher of the learner's groups once and
+// returns how many were told.
+func (a *SessionSentimentAnalyzer) alertTeachers(ctx context.Context, conv *Conversation) int {
+	if a.cfg.Groups == nil {
+		return 0
+	}
+	internalID, err := a.cfg.Store.ResolveUserUUID(conv.UserID)
+	if err != nil || internalID == "" {
+		slog.Warn("frustration alert: learner lookup failed", "user_id", conv.UserID, "error", err)
+		return 0
+	}
+	groups, err := a.cfg.Groups.GetUserGroups(internalID)
+	if err != nil {
+		slog.Warn("frustration alert: group lookup failed", "user_id", conv.UserID, "error", err)
+		return 0
+	}
+
+	name, ok := a.cfg.Store.GetUserName(conv.UserID)
+	topicName := ""
+	if a.cfg.CurriculumLoader != nil && conv.TopicID != "" {
+		if topic, found := a.cfg.CurriculumLoader.GetTopic(conv.TopicID); found {
+			topicName = topic.Name
+		}
+	}
+	told := map[string]bool{conv.UserID: true}
+	for _, g := range groups {
+		members, err := a.cfg.Groups.GetGroupMembersWithChannel(g.ID)
+		if err != nil {
+			slog.Warn("frustration alert: member lookup failed", "group_id", g.ID, "error", err)
+			continue
+		}
+		for _, m := range members {
+			if m.Role != "teacher" || told[m.ExternalID] {
+				continue
+			}
+			told[m.ExternalID] = true
+			locale := a.locale(m.ExternalID)
+			learner := name
+			if !ok || learner == "" {
+				learner = i18n.S(locale, i18n.MsgDefaultStudentName)
+			}
+			text := i18n.S(locale, i18n.MsgFrustrationTeacherAlert, learner)
+			if topicName != "" {
+				text = i18n.S(locale, i18n.MsgFrustrationTeacherAlertTopic, learner, topicName)
+			}
+			a.cfg.Notifier.Notify(ctx, m.Channel, m.ExternalID, text)
+		}
+	}
+	return len(told) - 1
+}
+
+func (a *SessionSentimentAnalyzer) locale(userID string) string {
+	if lang, ok := a.cfg.Store.GetUserPreferredLanguage(userID); ok && lang != "" {
+		return lang
+	}
+	return i18n.DefaultLocale
+}
+
+// ListConversationsToScore returns non-sandbox conversations whose latest
+// learner message is at or after since and after their last score.
+func (s *MemoryStore) ListConversationsToScore(_ context.Context, since time.Time, limit int) ([]string, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	var convs []*Conversation
+	for _, conv := range s.conversations {
+		if IsSandboxUser(conv.UserID) || conv.ArchivedAt != nil {
+			continue
+		}
+		var last time.Time
+		for _, m := range conv.Messages {
+			if m.Role == "user" && m.Visible() && m.CreatedAt.After(last) {
+				last = m.CreatedAt
+			}
+		}
+		if last.IsZero() || last.Before(since) {
+			continue
+		}
+		if conv.Sentiment != nil && !last.After(conv.Sentiment.ScoredAt) {
+			continue
+		}
+		convs = append(convs, conv)
+	}
+	sort.Slice(convs, func(i, j int) bool { return convs[i].StartedAt.Before(convs[j].StartedAt) })
+	ids := make([]string, 0, len(convs))
+	for _, conv := range convs {
+		if limit > 0 && len(ids) == limit {
+			break
+		}
+		ids = append(ids, conv.ID)
+	}
+	return ids, nil
+}
+
+func (s *MemoryStore) SetConversationSentiment(conversationID string, sentiment SessionSentiment) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	conv, ok := s.conversations[conversationID]
+	if !ok {
+		return fmt.Errorf("conversation not found: %s", conversationID)
+	}
+	conv.Sentiment = &sentiment
+	return nil
+}
+
+// ListConversationsToScore returns the tenant's non-sandbox conversations
+// with a learner message at or after since that is newer than their last
+// score, oldest session first.
+func (s *PostgresStore) ListConversationsToScore(ctx context.Context, since time.Time, limit int) ([]string, error) {
+	ctx, cancel := context.WithTimeout(ctx, dbTimeout)
+	defer cancel()
+
+	rows, err := s.pool.Query(ctx,
+		`SELECT c.id::text
+		 FROM conversations c
+		 JOIN users u ON u.id = c.user_id
+		 WHERE c.tenant_id = $1::uuid
+		   AND c.archived_at IS NULL
+		   AND NOT u.sandbox
+		   AND EXISTS (
+		     SELECT 1 FROM messages m
+		     WHERE m.conversation_id = c.id
+		       AND m.role = 'user'
+		       AND m.deleted_at IS NULL
+		       AND m.created_at >= $2
+		       AND m.created_at > COALESCE((c.metadata->'sentiment'->>'scored_at')::timestamptz, '-infinity')
+		   )
+		 ORDER BY c.started_at
+		 LIMIT $3`,
+		s.tenantID,
+		since,
+		limit,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("list conversations to score: %w", err)
+	}
+	defer rows.Close()
+
+	var ids []string
+	for rows.Next() {
+		var id string
+		if err := rows.Scan(&id); err != nil {
+			return nil, fmt.Errorf("scan conversation to score: %w", err)
+		}
+		ids = append(ids, id)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("list conversations to score: %w", err)
+	}
+	return ids, nil
+}
+
+func (s *PostgresStore) SetConversationSentiment(conversationID string, sentiment SessionSentiment) error {
+	ctx, cancel := context.WithTimeout(context.Background(), dbTimeout)
+	defer cancel()
+
+	payload, err := json.Marshal(sentiment)
+	if err != nil {
+		return fmt.Errorf("marshal sentiment: %w", err)
+	}
+	cmd, err := s.pool.Exec(ctx,
+		`UPDATE conversations
+		 SET metadata = jsonb_set(COALESCE(metadata, '{}'::jsonb), '{sentiment}', $2::jsonb, true)
+		 WHERE id = $1::uuid`,
+		conversationID,
+		string(payload),
+	)
+	if err != nil {
+		return fmt.Errorf("set conversation sentiment: %w", err)
+	}
+	if cmd.RowsAffected() == 0 {
+		return fmt.Errorf("conversation not found: %s", conversationID)
+	}
+	return nil
+}
@@ -0,0 +1,126 @@
+// Copyright 2026 the P&AI authors. All rights reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package agent_test
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/p-n-ai/pai-bot/internal/agent"
+)
+
+func userMessages(texts ...string) []agent.StoredMessage {
+	messages := make([]agent.StoredMessage, 0, len(texts))
+	for _, text := range texts {
+		messages = append(messages, agent.StoredMessage{Role: "user", Content: text})
+	}
+	return messages
+}
+
+func TestScoreSessionSentiment(t *testing.T) {
+	calm := agent.ScoreSessionSentiment(userMessages(
+		"Can you show me how to factorise x^2 + 5x + 6?",
+		"Oh so we need two numbers that multiply to 6 and add to 5",
+		"Is it (x+2)(x+3)?",
+		"Why does the order of the brackets not matter?",
+	))
+	upset := agent.ScoreSessionSentiment(userMessages(
+		"ok",
+		"idk",
+		"I don't understand",
+		"this is so stupid I give up",
+	))
+	if calm.UserMessages != 4 || upset.UserMessages != 4 {
+		t.Fatalf("user messages = %d, %d", calm.UserMessages, upset.UserMessages)
+	}
+	if calm.Frustration != 0 {
+		t.Fatalf("calm frustration = %v, want 0", calm.Frustration)
+	}
+	if upset.Frustration < 0.5 {
+		t.Fatalf("upset frustration = %v, want at least 0.5", upset.Frustration)
+	}
+	if calm.Engagement <= upset.Engagement {
+		t.Fatalf("engagement calm %v <= upset %v", calm.Engagement, upset.Engagement)
+	}
+
+	deleted := time.Now()
+	messages := userMessages("I hate this", "ok")
+	messages[0].DeletedAt = &deleted
+	messages = append(messages, agent.StoredMessage{Role: "assistant", Content: "Never give up!"})
+	if got := agent.ScoreSessionSentiment(messages); got.UserMessages != 1 || got.Frustration != 0 {
+		t.Fatalf("deleted and tutor messages should not count, got %+v", got)
+	}
+}
+
+func TestSessionSentimentAnalyzerAlertsOncePerSpike(t *testing.T) {
+	// 04:00 UTC is midday in Malaysia, outside quiet hours.
+	now := time.Date(2026, 10, 14, 4, 0, 0, 0, time.UTC)
+	store := agent.NewMemoryStore()
+	groups := agent.NewMemoryGroupStore()
+	events := agent.NewMemoryEventLogger()
+	notifier := &capturingNotifier{}
+
+	_ = store.SetUserName("learner-1", "Aina")
+	_ = store.SetUserPreferredLanguage("learner-1", "en")
+	_ = store.SetUserPreferredLanguage("teacher-1", "en")
+	group, err := groups.CreateGroup("tenant-1", "Form 1 Cempaka", "class", "", "kssm", "math", "", "teacher-1")
+	if err != nil {
+		t.Fatalf("CreateGroup() error = %v", err)
+	}
+	_ = groups.JoinGroup(group.ID, "teacher-1", "tenant-1", "teacher")
+	_ = groups.JoinGroup(group.ID, "learner-1", "tenant-1", "member")
+
+	upsetID, _ := store.CreateConversation(agent.Conversation{UserID: "learner-1", State: "teaching"})
+	for i, text := range []string{"idk", "I don't understand", "UGH THIS IS SO STUPID", "I give up"} {
+		_, _ = store.AddMessage(upsetID, agent.StoredMessage{Role: "user", Content: text, CreatedAt: now.Add(time.Duration(i-10) * time.Minute)})
+	}
+	calmID, _ := store.CreateConversation(agent.Conversation{UserID: "learner-2", State: "teaching"})
+	_, _ = store.AddMessage(calmID, agent.StoredMessage{Role: "user", Content: "What is a prime number?", CreatedAt: now.Add(-5 * time.Minute)})
+
+	analyzer := agent.NewSessionSentimentAnalyzer(agent.SessionSentimentConfig{
+		Store:    store,
+		Notifier: notifier,
+		Groups:   groups,
+		Events:   events,
+	})
+	opts := agent.SessionSentimentOptions{Since: now.Add(-time.Hour), CheckIn: true, TeacherAlert: true, Now: now}
+	report, err := analyzer.Run(context.Background(), opts)
+	if err != nil {
+		t.Fatalf("Run() error = %v", err)
+	}
+	want := agent.SessionSentimentReport{Scored: 2, Spikes: 1, CheckIns: 1, TeacherAlerts: 1}
+	if report != want {
+		t.Fatalf("report = %+v, want %+v", report, want)
+	}
+
+	conv, _ := store.GetConversation(upsetID)
+	if conv.Sentiment == nil || conv.Sentiment.SpikeAt == nil || !conv.Sentiment.ScoredAt.Equal(now) {
+		t.Fatalf("sentiment = %+v, want a scored spike", conv.Sentiment)
+	}
+	if len(notifier.sent) != 2 {
+		t.Fatalf("notifications = %+v, want a check-in and a teacher alert", notifier.sent)
+	}
+	if notifier.sent[0].userID != "learner-1" || notifier.sent[1].userID != "teacher-1" || !strings.Contains(notifier.sent[1].text, "Aina") {
+		t.Fatalf("notifications = %+v", notifier.sent)
+	}
+	if got := events.Events(); len(got) != 1 || got[0].EventType != "frustration_spike" || got[0].ConversationID != upsetID {
+		t.Fatalf("events = %+v", got)
+	}
+
+	// New messages get the session rescored, but the spike is not re-sent.
+	_, _ = store.AddMessage(upsetID, agent.StoredMessage{Role: "user", Content: "this is useless", CreatedAt: now.Add(time.Minute)})
+	opts.Now = now.Add(15 * time.Minute)
+	report, err = analyzer.Run(context.Background(), opts)
+	if err != nil {
+		t.Fatalf("second Run() error = %v", err)
+	}
+	if report != (agent.SessionSentimentReport{Scored: 1}) {
+		t.Fatalf("second report = %+v, want only a rescore", report)
+	}
+	if len(notifier.sent) != 2 {
+		t.Fatalf("notifications after second run = %d, want 2", len(notifier.sent))
+	}
+}
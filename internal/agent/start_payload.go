@@ -0,0 +1,269 @@
+// Copyright 2026 the P&AI authors. All rights reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package agent
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"strings"
+	"time"
+
+	"github.com/p-n-ai/pai-bot/internal/chat"
+	"github.com/p-n-ai/pai-bot/internal/curriculum"
+	"github.com/p-n-ai/pai-bot/internal/i18n"
+)
+
+// Start payload kinds, from the prefix of a t.me/<bot>?start=<payload> deep
+// link. They double as acquisition sources.
+const (
+	startPayloadReferral = "referral" // ref_<referral code>
+	startPayloadTopic    = "topic"    // topic_<topic ID>
+	startPayloadClass    = "class"    // join_<group join code>
+	startPayloadCampaign = "campaign" // src_<campaign tag>
+)
+
+// acquisitionDirect is the source of new learners who sent a bare /start.
+const acquisitionDirect = "direct"
+
+var startPayloadPrefixes = []struct{ prefix, kind string }{
+	{referralPayloadPrefix, startPayloadReferral},
+	{"topic_", startPayloadTopic},
+	{"join_", startPayloadClass},
+	{"src_", startPayloadCampaign},
+}
+
+// maxStartPayloadLen is Telegram's limit on the start parameter.
+const maxStartPayloadLen = 64
+
+type startPayload struct {
+	Kind  string
+	Value string
+}
+
+// parseStartPayload reads a deep-link parameter. Telegram only passes
+// A-Z, a-z, 0-9, _ and -, so anything else did not come from a link.
+func parseStartPayload(raw string) (startPayload, bool) {
+	if raw == "" || len(raw) > maxStartPayloadLen {
+		return startPayload{}, false
+	}
+	for _, c := range raw {
+		if !(c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c >= '0' && c <= '9' || c == '_' || c == '-') {
+			return startPayload{}, false
+		}
+	}
+	for _, p := range startPayloadPrefixes {
+		if value, ok := strings.CutPrefix(raw, p.prefix); ok && value != "" {
+			return startPayload{Kind: p.kind, Value: value}, true
+		}
+	}
+	return startPayload{}, false
+}
+
+// Acquisition is where a learner first came from.
+type Acquisition struct {
+	// Source is a start payload kind, or "direct" for a bare /start.
+	Source string    `json:"source"`
+	Value  string    `json:"value,omitempty"`
+	At     time.Time `json:"at"`
+}
+
+// AcquisitionRecorder is implemented by conversation stores that keep a
+// learner's acquisition source on their profile.
+type AcquisitionRecorder interface {
+	// RecordUserAcquisition stores acq unless the learner already has one,
+	// so the first touch wins.
+	RecordUserAcquisition(userID string, acq Acquisition) error
+	GetUserAcquisition(userID string) (Acquisition, bool)
+}
+
+// handleStartCommand runs /start, routing a deep-link payload to its flow
+// once onboarding has begun.
+func (e *Engine) handleStartCommand(ctx context.Context, msg chat.InboundMessage, args []string) (string, error) {
+	_, known := e.store.GetUserABGroup(msg.UserID)
+	var (
+		payload    startPayload
+		hasPayload bool
+	)
+	if len(args) == 1 {
+		if payload, hasPayload = parseStartPayload(args[0]); !hasPayload {
+			slog.Info("ignoring unknown start payload", "user_id", msg.UserID, "payload_len", len(args[0]))
+		}
+	}
+
+	// Returning learners following a topic link go straight to the topic
+	// instead of back through onboarding.
+	if hasPayload && payload.Kind == startPayloadTopic && known {
+		if _, hasForm := e.store.GetUserForm(msg.UserID); hasForm {
+			if topic, ok := e.lookupTopic(payload.Value); ok {
+				e.logStartPayload(msg, payload, false)
+				return e.setLearnTopic(msg, e.messageLocale(msg, nil), topic)
+			}
+		}
+	}
+
+	e.endActiveConversation(msg.UserID)
+	reply, err := e.handleStart(msg.UserID, msg)
+	if err != nil {
+		return reply, err
+	}
+	if !known {
+		e.recordAcquisition(msg, payload, hasPayload)
+	}
+	if !hasPayload {
+		return reply, nil
+	}
+	e.logStartPayload(msg, payload, !known)
+
+	switch payload.Kind {
+	case startPayloadReferral:
+		if !known {
+			reply = e.attributeReferral(msg, payload.Value, reply)
+		}
+	case startPayloadTopic:
+		reply = e.linkOnboardingTopic(msg, payload.Value, reply)
+	case startPayloadClass:
+		joined, err := e.handleJoinGroupCommand(ctx, msg, []string{payload.Value})
+		if err != nil {
+			slog.Error("failed to join group from start link", "user_id", msg.UserID, "error", err)
+		} else {
+			reply = joined + "\n\n" + reply
+		}
+	}
+	return reply, nil
+}
+
+// lookupTopic finds a topic by its exact ID.
+func (e *Engine) lookupTopic(id string) (curriculum.Topic, bool) {
+	if e.curriculumLoader == nil {
+		return curriculum.Topic{}, false
+	}
+	return e.curriculumLoader.GetTopic(id)
+}
+
+// linkOnboardingTopic starts onboarding on a deep-linked topic. The topic is
+// set on the new conversation now, and the form step then skips the topic
+// picker.
+func (e *Engine) linkOnboardingTopic(msg chat.InboundMessage, topicID, reply string) string {
+	topic, ok := e.lookupTopic(topicID)
+	if !ok {
+		slog.Info("start link names an unknown topic", "user_id", msg.UserID, "topic_id", topicID)
+		return reply
+	}
+	conv, ok := e.store.GetActiveConversation(msg.UserID)
+	if !ok {
+		return reply
+	}
+	if err := e.store.UpdateConversationTopicID(conv.ID, topic.ID); err != nil {
+		slog.Error("failed to set start link topic", "conversation_id", conv.ID, "topic_id", topic.ID, "error", err)
+		return reply
+	}
+	return i18n.S(e.messageLocale(msg, nil), i18n.MsgStartTopicLinked, topic.Name) + "\n\n" + reply
+}
+
+func (e *Engine) recordAcquisition(msg chat.InboundMessage, payload startPayload, hasPayload bool) {
+	recorder, ok := e.store.(AcquisitionRecorder)
+	if !ok {
+		return
+	}
+	acq := Acquisition{Source: acquisitionDirect, At: time.Now().UTC()}
+	if hasPayload {
+		acq.Source, acq.Value = payload.Kind, payload.Value
+	}
+	if err := recorder.RecordUserAcquisition(msg.UserID, acq); err != nil {
+		slog.Warn("failed to record acquisition source", "user_id", msg.UserID, "source", acq.Source, "error", err)
+	}
+}
+
+func (e *Engine) logStartPayload(msg chat.InboundMessage, payload startPayload, newLearner bool) {
+	e.logEventAsync(Event{
+		UserID:    msg.UserID,
+		EventType: "start_payload",
+		Data: map[string]any{
+			"channel":     msg.Channel,
+			"kind":        payload.Kind,
+			"value":       payload.Value,
+			"new_learner": newLearner,
+		},
+	})
+}
+
+func (s *MemoryStore) RecordUserAcquisition(userID string, acq Acquisition) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if userID == "" {
+		return fmt.Errorf("user_id is required")
+	}
+	if _, exists := s.acquisitions[userID]; !exists {
+		s.acquisitions[userID] = acq
+	}
+	return nil
+}
+
+func (s *MemoryStore) GetUserAcquisition(userID string) (Acquisition, bool) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	acq, ok := s.acquisitions[userID]
+	return acq, ok
+}
+
+func (s *PostgresStore) RecordUserAcquisition(externalID string, acq Acquisition) error {
+	ctx, cancel := context.WithTimeout(context.Background(), dbTimeout)
+	defer cancel()
+
+	if externalID == "" {
+		return fmt.Errorf("external_id is required")
+	}
+	if _, err := s.resolveOrCreateUser(ctx, externalID); err != nil {
+		return err
+	}
+	raw, err := json.Marshal(acq)
+	if err != nil {
+		return fmt.Errorf("encode acquisition: %w", err)
+	}
+	if _, err := s.pool.Exec(ctx,
+		`UPDATE users
+		 SET config = jsonb_set(COALESCE(config, '{}'::jsonb), '{acquisition}', $4::jsonb),
+		     updated_at = NOW()
+		 WHERE tenant_id = $1::uuid
+		   AND channel = $2
+		   AND external_id = $3
+		   AND NOT (COALESCE(config, '{}'::jsonb) ? 'acquisition')`,
+		s.tenantID,
+		s.channel,
+		externalID,
+		raw,
+	); err != nil {
+		return fmt.Errorf("record acquisition: %w", err)
+	}
+	return nil
+}
+
+func (s *PostgresStore) GetUserAcquisition(externalID string) (Acquisition, bool) {
+	ctx, cancel := context.WithTimeout(context.Background(), dbTimeout)
+	defer cancel()
+
+	var raw []byte
+	err := s.pool.QueryRow(ctx,
+		`SELECT config->'acquisition'
+		 FROM users
+		 WHERE tenant_id = $1::uuid
+		   AND channel = $2
+		   AND external_id = $3
+		 ORDER BY created_at ASC
+		 LIMIT 1`,
+		s.tenantID,
+		s.channel,
+		externalID,
+	).Scan(&raw)
+	if err != nil || len(raw) == 0 {
+		return Acquisition{}, false
+	}
+	var acq Acquisition
+	if err := json.Unmarshal(raw, &acq); err != nil || acq.Source == "" {
+		return Acquisition{}, false
+	}
+	return acq, true
+}
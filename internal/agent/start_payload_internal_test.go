@@ -0,0 +1,32 @@
+// Copyright 2026 the P&AI authors. All rights reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package agent
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestParseStartPayload(t *testing.T) {
+	tests := []struct {
+		raw  string
+		want startPayload
+		ok   bool
+	}{
+		{"ref_abc23456", startPayload{Kind: startPayloadReferral, Value: "abc23456"}, true},
+		{"topic_F1-02", startPayload{Kind: startPayloadTopic, Value: "F1-02"}, true},
+		{"join_QX7K2M", startPayload{Kind: startPayloadClass, Value: "QX7K2M"}, true},
+		{"src_tiktok", startPayload{Kind: startPayloadCampaign, Value: "tiktok"}, true},
+		{"topic_", startPayload{}, false},
+		{"hello", startPayload{}, false},
+		{"src_a b", startPayload{}, false},
+		{"src_" + strings.Repeat("x", 61), startPayload{}, false},
+	}
+	for _, tt := range tests {
+		got, ok := parseStartPayload(tt.raw)
+		if ok != tt.ok || got != tt.want {
+			t.Errorf("parseStartPayload(%q) = %+v, %v; want %+v, %v", tt.raw, got, ok, tt.want, tt.ok)
+		}
+	}
+}
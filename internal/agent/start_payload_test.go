@@ -0,0 +1,115 @@
+// Copyright 2026 the P&AI authors. All rights reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package agent_test
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/p-n-ai/pai-bot/internal/agent"
+	"github.com/p-n-ai/pai-bot/internal/ai"
+	"github.com/p-n-ai/pai-bot/internal/chat"
+	"github.com/p-n-ai/pai-bot/internal/i18n"
+)
+
+func TestStartTopicLinkSkipsOnboardingTopicStep(t *testing.T) {
+	store := agent.NewMemoryStore()
+	engine := agent.NewEngine(agent.EngineConfig{
+		AIRouter:         mockRouter(ai.NewMockProvider("AI response")),
+		Store:            store,
+		CurriculumLoader: createTestCurriculumLoader(t),
+	})
+
+	welcome := sendAs(t, engine, "telegram", "new", "/start topic_F1-02")
+	if want := i18n.S("en", i18n.MsgStartTopicLinked, "Linear Equations"); !strings.HasPrefix(welcome, want) {
+		t.Fatalf("/start = %q, want the linked topic noted first", welcome)
+	}
+
+	got, err := engine.ProcessMessage(context.Background(), chat.InboundMessage{
+		Channel: "telegram", UserID: "new", Text: "form:1", CallbackQueryID: "cb-form",
+	})
+	if err != nil {
+		t.Fatalf("ProcessMessage(form:1) error = %v", err)
+	}
+	if want := i18n.S("en", i18n.MsgLearnTopicSet, "Linear Equations"); got != want {
+		t.Fatalf("form reply = %q, want %q", got, want)
+	}
+	conv, ok := store.GetActiveConversation("new")
+	if !ok || conv.State != "teaching" || conv.TopicID != "F1-02" {
+		t.Fatalf("conversation = %+v, want teaching F1-02", conv)
+	}
+	if acq, ok := store.GetUserAcquisition("new"); !ok || acq.Source != "topic" || acq.Value != "F1-02" {
+		t.Fatalf("acquisition = %+v, %v; want topic F1-02", acq, ok)
+	}
+}
+
+func TestStartTopicLinkTakesReturningLearnerStraightToTopic(t *testing.T) {
+	store := agent.NewMemoryStore()
+	engine := agent.NewEngine(agent.EngineConfig{
+		AIRouter:         mockRouter(ai.NewMockProvider("AI response")),
+		Store:            store,
+		CurriculumLoader: createTestCurriculumLoader(t),
+	})
+	sendAs(t, engine, "telegram", "regular", "/start")
+	if _, err := engine.ProcessMessage(context.Background(), chat.InboundMessage{
+		Channel: "telegram", UserID: "regular", Text: "form:1", CallbackQueryID: "cb-form",
+	}); err != nil {
+		t.Fatalf("ProcessMessage(form:1) error = %v", err)
+	}
+
+	if got, want := sendAs(t, engine, "telegram", "regular", "/start topic_F1-02"), i18n.S("en", i18n.MsgLearnTopicSet, "Linear Equations"); got != want {
+		t.Fatalf("/start = %q, want %q", got, want)
+	}
+	if acq, _ := store.GetUserAcquisition("regular"); acq.Source != "direct" {
+		t.Fatalf("acquisition = %+v, want the first touch kept", acq)
+	}
+}
+
+func TestStartJoinLinkAddsLearnerToGroup(t *testing.T) {
+	groups := agent.NewMemoryGroupStore()
+	engine := agent.NewEngine(agent.EngineConfig{
+		AIRouter: mockRouter(ai.NewMockProvider("AI response")),
+		Store:    agent.NewMemoryStore(),
+		Groups:   groups,
+		TenantID: "test-tenant",
+	})
+	g, _ := groups.CreateGroup("test-tenant", "Class 1A", "class", "", "", "", "", "")
+
+	got := sendAs(t, engine, "telegram", "student", "/start join_"+strings.ToLower(g.JoinCode))
+	if !strings.Contains(got, "Class 1A") {
+		t.Fatalf("/start = %q, want the group joined", got)
+	}
+	if members, _ := groups.GetGroupMembers(g.ID); len(members) != 1 {
+		t.Fatalf("members = %d, want 1", len(members))
+	}
+}
+
+func TestStartRecordsFirstAcquisitionSource(t *testing.T) {
+	store := agent.NewMemoryStore()
+	engine := agent.NewEngine(agent.EngineConfig{
+		AIRouter: mockRouter(ai.NewMockProvider("AI response")),
+		Store:    store,
+	})
+
+	tests := []struct {
+		userID, text, source, value string
+	}{
+		{"a", "/start src_tiktok-oct", "campaign", "tiktok-oct"},
+		{"b", "/start", "direct", ""},
+		{"c", "/start not-a-link", "direct", ""},
+	}
+	for _, tt := range tests {
+		sendAs(t, engine, "telegram", tt.userID, tt.text)
+		acq, ok := store.GetUserAcquisition(tt.userID)
+		if !ok || acq.Source != tt.source || acq.Value != tt.value || acq.At.IsZero() {
+			t.Errorf("%q: acquisition = %+v, %v; want %s %q", tt.text, acq, ok, tt.source, tt.value)
+		}
+	}
+
+	sendAs(t, engine, "telegram", "a", "/start src_instagram")
+	if acq, _ := store.GetUserAcquisition("a"); acq.Value != "tiktok-oct" {
+		t.Fatalf("acquisition = %+v, want the first campaign kept", acq)
+	}
+}
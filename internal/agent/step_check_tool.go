@@ -0,0 +1,69 @@
+// Copyright 2026 the P&AI authors. All rights reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package agent
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"github.com/p-n-ai/pai-bot/internal/algebra"
+	"github.com/p-n-ai/pai-bot/internal/llm"
+)
+
+const checkWorkingStepsToolName = "check_working_steps"
+
+// maxCheckedSteps caps how much working one tool call parses.
+const maxCheckedSteps = 30
+
+// stepCheckTool finds the first wrong line in a student's algebra working,
+// so the tutor can point at that line instead of asking them to check
+// everything.
+type stepCheckTool struct{}
+
+func (stepCheckTool) Definition() llm.Tool {
+	return llm.Tool{
+		Name:        checkWorkingStepsToolName,
+		Description: "Check a student's algebra working line by line (expanding, simplifying, solving linear and quadratic equations) and find the first step that does not follow from the one before. Use it whenever a student shares multi-step working, then point to that exact step.",
+		Parameters: json.RawMessage(`{
+			"type":"object",
+			"properties":{
+				"problem":{"type":"string","description":"The question the working starts from, e.g. 3(x + 2) = 21."},
+				"steps":{"type":"array","items":{"type":"string"},"minItems":1,"maxItems":30,"description":"The student's lines of working, in order, copied as written."}
+			},
+			"required":["steps"],
+			"additionalProperties":false
+		}`),
+	}
+}
+
+func (stepCheckTool) Execute(_ context.Context, call llm.ToolCall) (llm.ToolResultMessage, error) {
+	problem, _ := call.Arguments["problem"].(string)
+	rawSteps, _ := call.Arguments["steps"].([]any)
+	var steps []string
+	for _, raw := range rawSteps {
+		if line, ok := raw.(string); ok {
+			steps = append(steps, algebra.SplitWorking(line)...)
+		}
+	}
+	if len(steps) == 0 || len(steps) > maxCheckedSteps {
+		return llm.ToolResultMessage{
+			Content:   []llm.UserContent{llm.TextContent{Text: "steps must list 1 to 30 lines of working"}},
+			IsError:   true,
+			Timestamp: time.Now(),
+		}, nil
+	}
+	payload, err := json.Marshal(algebra.CheckSteps(problem, steps))
+	if err != nil {
+		return llm.ToolResultMessage{
+			Content:   []llm.UserContent{llm.TextContent{Text: "step check could not be encoded"}},
+			IsError:   true,
+			Timestamp: time.Now(),
+		}, nil
+	}
+	return llm.ToolResultMessage{
+		Content:   []llm.UserContent{llm.TextContent{Text: string(payload)}},
+		Timestamp: time.Now(),
+	}, nil
+}
@@ -0,0 +1,43 @@
+// Copyright 2026 the P&AI authors. All rights reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package agent
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/p-n-ai/pai-bot/internal/algebra"
+	"github.com/p-n-ai/pai-bot/internal/llm"
+)
+
+func TestStepCheckToolReportsFirstIncorrectStep(t *testing.T) {
+	result, err := stepCheckTool{}.Execute(context.Background(), llm.ToolCall{
+		Name: checkWorkingStepsToolName,
+		Arguments: map[string]any{
+			"problem": "2(x - 1) = 8",
+			"steps":   []any{"2x - 2 = 8", "2x = 6\nx = 3"},
+		},
+	})
+	if err != nil || result.IsError {
+		t.Fatalf("Execute() = %#v, %v", result, err)
+	}
+	var report algebra.Report
+	if err := json.Unmarshal([]byte(result.Content[0].(llm.TextContent).Text), &report); err != nil {
+		t.Fatalf("decode report: %v", err)
+	}
+	if report.FirstIncorrect != 2 || len(report.Steps) != 3 || report.Steps[1].Text != "2x = 6" {
+		t.Fatalf("report = %+v, want step 2 of 3 flagged", report)
+	}
+}
+
+func TestStepCheckToolRejectsEmptyWorking(t *testing.T) {
+	result, err := stepCheckTool{}.Execute(context.Background(), llm.ToolCall{
+		Name:      checkWorkingStepsToolName,
+		Arguments: map[string]any{"steps": []any{"  "}},
+	})
+	if err != nil || !result.IsError {
+		t.Fatalf("Execute() = %#v, %v; want a tool error", result, err)
+	}
+}
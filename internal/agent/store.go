@@ -9,17 +9,65 @@ import (
 	"strings"
 	"sync"
 	"time"
+
+	"github.com/p-n-ai/pai-bot/internal/chat"
+)
+
+// MessageKind classifies what a stored message carries beyond its text.
+type MessageKind string
+
+const (
+	MessageKindText     MessageKind = "text"
+	MessageKindImage    MessageKind = "image"
+	MessageKindDocument MessageKind = "document"
+	MessageKindQuiz     MessageKind = "quiz"
 )
 
+// Valid reports whether k is one of the known message kinds.
+func (k MessageKind) Valid() bool {
+	switch k {
+	case MessageKindText, MessageKindImage, MessageKindDocument, MessageKindQuiz:
+		return true
+	}
+	return false
+}
+
+// MessageAttachment references non-text content sent with a message. Reference
+// is channel-specific (e.g. a Telegram file ID or a quiz question ID); payload
+// bytes are never stored.
+type MessageAttachment struct {
+	Type      string `json:"type"`
+	Reference string `json:"reference"`
+	Caption   string `json:"caption,omitempty"`
+}
+
 // StoredMessage represents a single message in a conversation.
 type StoredMessage struct {
-	ID           string    `json:"id,omitempty"`
-	Role         string    `json:"role"`
-	Content      string    `json:"content"`
-	Model        string    `json:"model,omitempty"`
-	InputTokens  int       `json:"input_tokens,omitempty"`
-	OutputTokens int       `json:"output_tokens,omitempty"`
-	CreatedAt    time.Time `json:"created_at"`
+	ID           string              `json:"id,omitempty"`
+	Role         string              `json:"role"`
+	Content      string              `json:"content"`
+	Kind         MessageKind         `json:"kind,omitempty"`
+	Attachments  []MessageAttachment `json:"attachments,omitempty"`
+	Model        string              `json:"model,omitempty"`
+	InputTokens  int                 `json:"input_tokens,omitempty"`
+	OutputTokens int                 `json:"output_tokens,omitempty"`
+	CreatedAt    time.Time           `json:"created_at"`
+	// ExternalID is the channel-native message ID, used to match later edits and deletes.
+	ExternalID string `json:"external_id,omitempty"`
+	// OriginalContent keeps the first version of an edited message.
+	OriginalContent string     `json:"original_content,omitempty"`
+	EditedAt        *time.Time `json:"edited_at,omitempty"`
+	// DeletedAt marks a soft-deleted message. It stays in Messages so
+	// CompactedAt offsets remain valid, but is left out of prompts.
+	DeletedAt *time.Time `json:"deleted_at,omitempty"`
+	// Safety is the safety trace of the turn that produced an assistant
+	// message.
+	Safety *SafetyTrace `json:"safety,omitempty"`
+}
+
+// Visible reports whether the message should reach prompts and summaries.
+func (m StoredMessage) Visible() bool {
+	return m.DeletedAt == nil
 }
 
 // ConversationQuizState is the persisted runtime state for an active quiz.
@@ -31,6 +79,14 @@ type ConversationQuizState struct {
 	RunState           string         `json:"run_state,omitempty"`
 	SuspendedBy        string         `json:"suspended_by,omitempty"`
 	GeneratedQuestions []QuizQuestion `json:"generated_questions,omitempty"`
+	Difficulty         string         `json:"difficulty,omitempty"`
+	HitStreak          int            `json:"hit_streak,omitempty"`
+	MissStreak         int            `json:"miss_streak,omitempty"`
+	// DailyProblemID marks a problem-of-the-day session. Its only question
+	// is the problem, kept in GeneratedQuestions.
+	DailyProblemID string `json:"daily_problem_id,omitempty"`
+	// Answers logs every attempt so far, for the QuizResult saved at the end.
+	Answers []QuizAnswerRecord `json:"answers,omitempty"`
 }
 
 // ConversationChallengeState is the persisted runtime state for an active challenge.
@@ -76,8 +132,11 @@ type Conversation struct {
 	QuizState          *ConversationQuizState      `json:"quiz_state,omitempty"`
 	PendingGoal        *PendingGoalDraft           `json:"pending_goal,omitempty"`
 	ChallengeState     *ConversationChallengeState `json:"challenge_state,omitempty"`
+	Sentiment          *SessionSentiment           `json:"sentiment,omitempty"`
 	StartedAt          time.Time                   `json:"started_at"`
 	EndedAt            *time.Time                  `json:"ended_at,omitempty"`
+	// ArchivedAt is set once the conversation's messages moved to cold storage.
+	ArchivedAt *time.Time `json:"archived_at,omitempty"`
 }
 
 // ConversationStore persists conversation state and message history.
@@ -98,6 +157,12 @@ type ConversationStore interface {
 	GetConversation(id string) (*Conversation, error)
 	GetActiveConversation(userID string) (*Conversation, bool)
 	AddMessage(conversationID string, msg StoredMessage) (string, error)
+	// EditMessage replaces a message's content, keeping the first version as OriginalContent.
+	EditMessage(conversationID, messageID, content string) error
+	// DeleteMessage soft-deletes a message; repeated deletes are no-ops.
+	DeleteMessage(conversationID, messageID string) error
+	// SetMessageExternalID records the channel-native ID a message was delivered as.
+	SetMessageExternalID(conversationID, messageID, externalID string) error
 	SetSummary(conversationID string, summary string, compactedAt int) error
 	UpdateConversationState(conversationID string, state string) error
 	UpdateConversationTopicID(conversationID, topicID string) error
@@ -122,7 +187,15 @@ type MemoryStore struct {
 	userLang      map[string]string
 	userQuizLevel map[string]string
 	userABGroup   map[string]string
-	mu            sync.RWMutex
+	linkCodes     map[string]memoryLinkCode
+	userLinks     map[string]string
+	deliveries    []chat.DeliveryRecord
+	userState     map[string]UserState
+	acquisitions  map[string]Acquisition
+	// lifecycleEvents holds churn and reactivation events; MemoryStore
+	// has no events table.
+	lifecycleEvents []Event
+	mu              sync.RWMutex
 }
 
 // NewMemoryStore creates a new in-memory conversation store.
@@ -134,6 +207,10 @@ func NewMemoryStore() *MemoryStore {
 		userLang:      make(map[string]string),
 		userQuizLevel: make(map[string]string),
 		userABGroup:   make(map[string]string),
+		linkCodes:     make(map[string]memoryLinkCode),
+		userLinks:     make(map[string]string),
+		userState:     make(map[string]UserState),
+		acquisitions:  make(map[string]Acquisition),
 	}
 }
 
@@ -306,12 +383,25 @@ func (s *MemoryStore) GetActiveConversation(userID string) (*Conversation, bool)
 	s.mu.RLock()
 	defer s.mu.RUnlock()
 
+	// A learner with linked chats continues whichever of their open
+	// conversations was active most recently.
+	var active *Conversation
 	for _, conv := range s.conversations {
-		if conv.UserID == userID && conv.EndedAt == nil {
-			return conv, true
+		if conv.EndedAt != nil || !s.sameLinkGroupLocked(userID, conv.UserID) {
+			continue
+		}
+		if active == nil || lastActivity(conv).After(lastActivity(active)) {
+			active = conv
 		}
 	}
-	return nil, false
+	return active, active != nil
+}
+
+func lastActivity(conv *Conversation) time.Time {
+	if n := len(conv.Messages); n > 0 && conv.Messages[n-1].CreatedAt.After(conv.StartedAt) {
+		return conv.Messages[n-1].CreatedAt
+	}
+	return conv.StartedAt
 }
 
 func (s *MemoryStore) AddMessage(conversationID string, msg StoredMessage) (string, error) {
@@ -322,16 +412,86 @@ func (s *MemoryStore) AddMessage(conversationID string, msg StoredMessage) (stri
 	if !ok {
 		return "", fmt.Errorf("conversation not found: %s", conversationID)
 	}
+	if msg.Kind == "" {
+		msg.Kind = MessageKindText
+	}
+	if !msg.Kind.Valid() {
+		return "", fmt.Errorf("unknown message kind: %s", msg.Kind)
+	}
 	if msg.ID == "" {
 		msg.ID = generateID()
 	}
 	if msg.CreatedAt.IsZero() {
 		msg.CreatedAt = time.Now()
 	}
+	msg.Attachments = append([]MessageAttachment(nil), msg.Attachments...)
 	conv.Messages = append(conv.Messages, msg)
 	return msg.ID, nil
 }
 
+func (s *MemoryStore) EditMessage(conversationID, messageID, content string) error {
+	if content == "" {
+		return fmt.Errorf("message content is required")
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	msg, err := s.findMessageLocked(conversationID, messageID)
+	if err != nil {
+		return err
+	}
+	if msg.DeletedAt != nil {
+		return fmt.Errorf("message is deleted: %s", messageID)
+	}
+	if msg.OriginalContent == "" {
+		msg.OriginalContent = msg.Content
+	}
+	now := time.Now()
+	msg.Content = content
+	msg.EditedAt = &now
+	return nil
+}
+
+func (s *MemoryStore) DeleteMessage(conversationID, messageID string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	msg, err := s.findMessageLocked(conversationID, messageID)
+	if err != nil {
+		return err
+	}
+	if msg.DeletedAt == nil {
+		now := time.Now()
+		msg.DeletedAt = &now
+	}
+	return nil
+}
+
+func (s *MemoryStore) SetMessageExternalID(conversationID, messageID, externalID string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	msg, err := s.findMessageLocked(conversationID, messageID)
+	if err != nil {
+		return err
+	}
+	msg.ExternalID = externalID
+	return nil
+}
+
+func (s *MemoryStore) findMessageLocked(conversationID, messageID string) (*StoredMessage, error) {
+	conv, ok := s.conversations[conversationID]
+	if !ok {
+		return nil, fmt.Errorf("conversation not found: %s", conversationID)
+	}
+	for i := range conv.Messages {
+		if conv.Messages[i].ID == messageID {
+			return &conv.Messages[i], nil
+		}
+	}
+	return nil, fmt.Errorf("message not found: %s", messageID)
+}
+
 func (s *MemoryStore) SetSummary(conversationID string, summary string, compactedAt int) error {
 	s.mu.Lock()
 	defer s.mu.Unlock()
@@ -14,6 +14,8 @@ import (
 	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgconn"
 	"github.com/jackc/pgx/v5/pgxpool"
+
+	"github.com/p-n-ai/pai-bot/internal/platform/msgcrypt"
 )
 
 const (
@@ -27,6 +29,7 @@ type PostgresStore struct {
 	pool     *pgxpool.Pool
 	tenantID string
 	channel  string
+	keys     *msgcrypt.Keyring // nil stores message content in plaintext
 }
 
 func (s *PostgresStore) UserExists(externalID string) bool {
@@ -508,60 +511,22 @@ func (s *PostgresStore) GetConversation(id string) (*Conversation, error) {
 	ctx, cancel := context.WithTimeout(context.Background(), dbTimeout)
 	defer cancel()
 
-	conv, err := s.getConversationByQuery(ctx,
-		`SELECT c.id::text, u.external_id, c.topic_id, c.state, c.started_at, c.ended_at, c.metadata
-		 FROM conversations c
-		 JOIN users u ON u.id = c.user_id
-		 WHERE c.id = $1::uuid
-		 LIMIT 1`,
-		id,
-	)
+	conv, err := s.getConversationByQuery(ctx, conversationByIDSQL, id)
 	if err != nil {
 		return nil, err
 	}
 
-	rows, err := s.pool.Query(ctx,
-		`SELECT id::text, role, content, model, input_tokens, output_tokens, created_at
-		 FROM messages
-		 WHERE conversation_id = $1::uuid
-		 ORDER BY created_at ASC`,
-		id,
-	)
-	if err != nil {
-		return nil, fmt.Errorf("query messages: %w", err)
-	}
-	defer rows.Close()
-
-	for rows.Next() {
-		var msg StoredMessage
-		var model *string
-		var inputTokens *int
-		var outputTokens *int
-		if err := rows.Scan(
-			&msg.ID,
-			&msg.Role,
-			&msg.Content,
-			&model,
-			&inputTokens,
-			&outputTokens,
-			&msg.CreatedAt,
-		); err != nil {
-			return nil, fmt.Errorf("scan message: %w", err)
-		}
-		if model != nil {
-			msg.Model = *model
-		}
-		if inputTokens != nil {
-			msg.InputTokens = *inputTokens
+	if conv.ArchivedAt != nil {
+		if err := s.loadArchivedMessages(ctx, conv); err != nil {
+			return nil, err
 		}
-		if outputTokens != nil {
-			msg.OutputTokens = *outputTokens
-		}
-		conv.Messages = append(conv.Messages, msg)
+		return conv, nil
 	}
-	if err := rows.Err(); err != nil {
-		return nil, fmt.Errorf("iterate messages: %w", err)
+	messages, err := queryConversationMessages(ctx, s.pool, s.keys, id)
+	if err != nil {
+		return nil, err
 	}
+	conv.Messages = append(conv.Messages, messages...)
 
 	return conv, nil
 }
@@ -570,20 +535,7 @@ func (s *PostgresStore) GetActiveConversation(userID string) (*Conversation, boo
 	ctx, cancel := context.WithTimeout(context.Background(), dbTimeout)
 	defer cancel()
 
-	conv, err := s.getConversationByQuery(ctx,
-		`SELECT c.id::text, u.external_id, c.topic_id, c.state, c.started_at, c.ended_at, c.metadata
-		 FROM conversations c
-		 JOIN users u ON u.id = c.user_id
-		 WHERE u.external_id = $1
-		   AND u.channel = $2
-		   AND c.tenant_id = $3::uuid
-		   AND c.ended_at IS NULL
-		 ORDER BY c.started_at DESC
-		 LIMIT 1`,
-		userID,
-		s.channel,
-		s.tenantID,
-	)
+	conv, err := s.getConversationByQuery(ctx, activeConversationSQL, userID, s.channel, s.tenantID)
 	if err != nil {
 		if errors.Is(err, pgx.ErrNoRows) {
 			return nil, false
@@ -598,45 +550,210 @@ func (s *PostgresStore) GetActiveConversation(userID string) (*Conversation, boo
 	return full, true
 }
 
+// Queries every teaching turn runs. HotStatements lists them so the pool
+// can prepare them on each new connection.
+const (
+	conversationByIDSQL = `SELECT c.id::text, u.external_id, c.topic_id, c.state, c.started_at, c.ended_at, c.archived_at, c.metadata
+	 FROM conversations c
+	 JOIN users u ON u.id = c.user_id
+	 WHERE c.id = $1::uuid
+	 LIMIT 1`
+
+	// activeConversationSQL finds the learner's open conversation on any
+	// channel linked to theirs, most recently used first.
+	activeConversationSQL = `WITH me AS (
+	     SELECT id FROM users
+	     WHERE external_id = $1 AND channel = $2 AND tenant_id = $3::uuid
+	 ), linked AS (
+	     SELECT id FROM me
+	     UNION
+	     SELECT peer.user_id
+	     FROM user_links own
+	     JOIN user_links peer ON peer.canonical_user_id = own.canonical_user_id
+	     WHERE own.user_id IN (SELECT id FROM me)
+	 )
+	 SELECT c.id::text, u.external_id, c.topic_id, c.state, c.started_at, c.ended_at, c.archived_at, c.metadata
+	 FROM conversations c
+	 JOIN users u ON u.id = c.user_id
+	 WHERE c.user_id IN (SELECT id FROM linked)
+	   AND c.tenant_id = $3::uuid
+	   AND c.ended_at IS NULL
+	 ORDER BY COALESCE((SELECT MAX(m.created_at) FROM messages m WHERE m.conversation_id = c.id), c.started_at) DESC
+	 LIMIT 1`
+
+	conversationMessagesSQL = `SELECT id::text, role, content, content_kind, attachments, model, input_tokens, output_tokens, created_at,
+	        COALESCE(external_id, ''), COALESCE(original_content, ''), edited_at, deleted_at, safety, COALESCE(content_key_id, ''), tenant_id::text
+	 FROM messages
+	 WHERE conversation_id = $1::uuid
+	 ORDER BY created_at ASC`
+)
+
+// HotStatements returns the SQL of the queries run on every turn.
+func HotStatements() []string {
+	return []string{conversationByIDSQL, activeConversationSQL, conversationMessagesSQL, insertMessageSQL, insertEventSQL}
+}
+
+// insertMessageSQL only writes into a conversation of the store's tenant, the
+// tenant a sealed message is bound to.
+const insertMessageSQL = `INSERT INTO messages (id, conversation_id, tenant_id, role, content, content_kind, attachments, model, input_tokens, output_tokens, created_at, external_id, safety, content_key_id)
+	 SELECT $13::uuid, $1::uuid, c.tenant_id, $2, $3, $4, $5::jsonb, $6, $7, $8, $9, $10, $11::jsonb, $12
+	 FROM conversations c
+	 WHERE c.id = $1::uuid
+	   AND c.tenant_id = $14::uuid
+	 RETURNING id::text`
+
 func (s *PostgresStore) AddMessage(conversationID string, msg StoredMessage) (string, error) {
 	ctx, cancel := context.WithTimeout(context.Background(), dbTimeout)
 	defer cancel()
 
+	args, err := insertMessageArgs(s.keys, s.tenantID, conversationID, msg)
+	if err != nil {
+		return "", err
+	}
+	var id string
+	if err := s.pool.QueryRow(ctx, insertMessageSQL, args...).Scan(&id); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return "", fmt.Errorf("conversation not found: %s", conversationID)
+		}
+		return "", fmt.Errorf("insert message: %w", err)
+	}
+
+	return id, nil
+}
+
+// insertMessageArgs validates msg and returns the parameters for
+// insertMessageSQL, sealing the content and attachments for the new row of
+// tenantID when keys is set.
+func insertMessageArgs(keys *msgcrypt.Keyring, tenantID, conversationID string, msg StoredMessage) ([]any, error) {
 	createdAt := msg.CreatedAt
 	if createdAt.IsZero() {
 		createdAt = time.Now()
 	}
 
 	if msg.Role == "" {
-		return "", fmt.Errorf("message role is required")
+		return nil, fmt.Errorf("message role is required")
 	}
 	if msg.Content == "" {
-		return "", fmt.Errorf("message content is required")
+		return nil, fmt.Errorf("message content is required")
 	}
-
-	var id string
-	err := s.pool.QueryRow(ctx,
-		`INSERT INTO messages (conversation_id, tenant_id, role, content, model, input_tokens, output_tokens, created_at)
-		 SELECT $1::uuid, c.tenant_id, $2, $3, $4, $5, $6, $7
-		 FROM conversations c
-		 WHERE c.id = $1::uuid
-		 RETURNING id::text`,
+	kind := msg.Kind
+	if kind == "" {
+		kind = MessageKindText
+	}
+	if !kind.Valid() {
+		return nil, fmt.Errorf("unknown message kind: %s", kind)
+	}
+	var attachments []byte
+	if len(msg.Attachments) > 0 {
+		encoded, err := json.Marshal(msg.Attachments)
+		if err != nil {
+			return nil, fmt.Errorf("encode message attachments: %w", err)
+		}
+		attachments = encoded
+	}
+	id := newMessageID()
+	content, attachments, keyID, err := sealMessage(keys, msgcrypt.Binding(tenantID, id), msg.Content, attachments)
+	if err != nil {
+		return nil, err
+	}
+	var safety []byte
+	if msg.Safety != nil {
+		encoded, err := json.Marshal(msg.Safety)
+		if err != nil {
+			return nil, fmt.Errorf("encode message safety trace: %w", err)
+		}
+		safety = encoded
+	}
+	return []any{
 		conversationID,
 		msg.Role,
-		msg.Content,
+		content,
+		string(kind),
+		attachments,
 		nullIfEmpty(msg.Model),
 		nullIfZero(msg.InputTokens),
 		nullIfZero(msg.OutputTokens),
 		createdAt,
-	).Scan(&id)
+		nullIfEmpty(msg.ExternalID),
+		safety,
+		nullIfEmpty(keyID),
+		id,
+		tenantID,
+	}, nil
+}
+
+func (s *PostgresStore) EditMessage(conversationID, messageID, content string) error {
+	ctx, cancel := context.WithTimeout(context.Background(), dbTimeout)
+	defer cancel()
+
+	if content == "" {
+		return fmt.Errorf("message content is required")
+	}
+	if s.keys != nil {
+		return s.editSealedMessage(ctx, conversationID, messageID, content)
+	}
+	cmd, err := s.pool.Exec(ctx,
+		`UPDATE messages
+		 SET original_content = COALESCE(original_content, content),
+		     content = $3,
+		     edited_at = NOW()
+		 WHERE id = $2::uuid
+		   AND conversation_id = $1::uuid
+		   AND deleted_at IS NULL`,
+		conversationID,
+		messageID,
+		content,
+	)
 	if err != nil {
-		if errors.Is(err, pgx.ErrNoRows) {
-			return "", fmt.Errorf("conversation not found: %s", conversationID)
-		}
-		return "", fmt.Errorf("insert message: %w", err)
+		return fmt.Errorf("edit message: %w", err)
 	}
+	if cmd.RowsAffected() == 0 {
+		return fmt.Errorf("message not found: %s", messageID)
+	}
+	return nil
+}
 
-	return id, nil
+func (s *PostgresStore) DeleteMessage(conversationID, messageID string) error {
+	ctx, cancel := context.WithTimeout(context.Background(), dbTimeout)
+	defer cancel()
+
+	cmd, err := s.pool.Exec(ctx,
+		`UPDATE messages
+		 SET deleted_at = COALESCE(deleted_at, NOW())
+		 WHERE id = $2::uuid
+		   AND conversation_id = $1::uuid`,
+		conversationID,
+		messageID,
+	)
+	if err != nil {
+		return fmt.Errorf("delete message: %w", err)
+	}
+	if cmd.RowsAffected() == 0 {
+		return fmt.Errorf("message not found: %s", messageID)
+	}
+	return nil
+}
+
+func (s *PostgresStore) SetMessageExternalID(conversationID, messageID, externalID string) error {
+	ctx, cancel := context.WithTimeout(context.Background(), dbTimeout)
+	defer cancel()
+
+	cmd, err := s.pool.Exec(ctx,
+		`UPDATE messages
+		 SET external_id = $3
+		 WHERE id = $2::uuid
+		   AND conversation_id = $1::uuid`,
+		conversationID,
+		messageID,
+		nullIfEmpty(externalID),
+	)
+	if err != nil {
+		return fmt.Errorf("set message external id: %w", err)
+	}
+	if cmd.RowsAffected() == 0 {
+		return fmt.Errorf("message not found: %s", messageID)
+	}
+	return nil
 }
 
 func (s *PostgresStore) SetSummary(conversationID string, summary string, compactedAt int) error {
@@ -997,6 +1114,7 @@ func (s *PostgresStore) getConversationByQuery(ctx context.Context, query string
 		&conv.State,
 		&conv.StartedAt,
 		&endedAt,
+		&conv.ArchivedAt,
 		&metadataBytes,
 	)
 	if err != nil {
@@ -1018,10 +1136,82 @@ func (s *PostgresStore) getConversationByQuery(ctx context.Context, query string
 	conv.QuizState = metadata.QuizState
 	conv.PendingGoal = metadata.PendingGoal
 	conv.ChallengeState = metadata.ChallengeState
+	conv.Sentiment = metadata.Sentiment
 
 	return conv, nil
 }
 
+// queryConversationMessages loads a conversation's hot message rows in order.
+func queryConversationMessages(ctx context.Context, q rowQuerier, keys *msgcrypt.Keyring, id string) ([]StoredMessage, error) {
+	rows, err := q.Query(ctx, conversationMessagesSQL, id)
+	if err != nil {
+		return nil, fmt.Errorf("query messages: %w", err)
+	}
+	defer rows.Close()
+
+	var messages []StoredMessage
+	for rows.Next() {
+		var msg StoredMessage
+		var attachments, safety []byte
+		var keyID, tenantID string
+		var model *string
+		var inputTokens *int
+		var outputTokens *int
+		if err := rows.Scan(
+			&msg.ID,
+			&msg.Role,
+			&msg.Content,
+			&msg.Kind,
+			&attachments,
+			&model,
+			&inputTokens,
+			&outputTokens,
+			&msg.CreatedAt,
+			&msg.ExternalID,
+			&msg.OriginalContent,
+			&msg.EditedAt,
+			&msg.DeletedAt,
+			&safety,
+			&keyID,
+			&tenantID,
+		); err != nil {
+			return nil, fmt.Errorf("scan message: %w", err)
+		}
+		if err := openMessage(keys, tenantID, keyID, &msg, &attachments); err != nil {
+			return nil, err
+		}
+		if len(attachments) > 0 {
+			if err := json.Unmarshal(attachments, &msg.Attachments); err != nil {
+				return nil, fmt.Errorf("decode message attachments: %w", err)
+			}
+		}
+		if len(safety) > 0 {
+			msg.Safety = &SafetyTrace{}
+			if err := json.Unmarshal(safety, msg.Safety); err != nil {
+				return nil, fmt.Errorf("decode message safety trace: %w", err)
+			}
+		}
+		if model != nil {
+			msg.Model = *model
+		}
+		if inputTokens != nil {
+			msg.InputTokens = *inputTokens
+		}
+		if outputTokens != nil {
+			msg.OutputTokens = *outputTokens
+		}
+		messages = append(messages, msg)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterate messages: %w", err)
+	}
+	return messages, nil
+}
+
+type rowQuerier interface {
+	Query(ctx context.Context, sql string, args ...any) (pgx.Rows, error)
+}
+
 type conversationMetadata struct {
 	Summary            string                      `json:"summary,omitempty"`
 	CompactedAt        int                         `json:"compacted_at,omitempty"`
@@ -1029,6 +1219,7 @@ type conversationMetadata struct {
 	QuizState          *ConversationQuizState      `json:"quiz_state,omitempty"`
 	PendingGoal        *PendingGoalDraft           `json:"pending_goal,omitempty"`
 	ChallengeState     *ConversationChallengeState `json:"challenge_state,omitempty"`
+	Sentiment          *SessionSentiment           `json:"sentiment,omitempty"`
 }
 
 func parseConversationMetadata(metadata []byte) conversationMetadata {
@@ -9,6 +9,7 @@ package agent
 import (
 	"context"
 	"testing"
+	"time"
 )
 
 func TestPostgresStore_ResetProfileClearsFormAndLanguage(t *testing.T) {
@@ -42,3 +43,184 @@ func TestPostgresStore_ResetProfileClearsFormAndLanguage(t *testing.T) {
 		t.Fatalf("GetUserPreferredLanguage() = %q, %v, want empty, false", lang, ok)
 	}
 }
+
+func TestPostgresStore_MessageAttachmentsRoundTrip(t *testing.T) {
+	ctx := context.Background()
+	pool, _ := startSchedulerPostgres(t, ctx)
+
+	store, err := NewPostgresStore(ctx, pool)
+	if err != nil {
+		t.Fatalf("NewPostgresStore() error = %v", err)
+	}
+
+	convID, err := store.CreateConversation(Conversation{UserID: "store-attachments-user", State: "teaching"})
+	if err != nil {
+		t.Fatalf("CreateConversation() error = %v", err)
+	}
+	if _, err := store.AddMessage(convID, StoredMessage{Role: "user", Content: "hello"}); err != nil {
+		t.Fatalf("AddMessage(text) error = %v", err)
+	}
+	if _, err := store.AddMessage(convID, StoredMessage{
+		Role:        "assistant",
+		Content:     "Question 1: solve 2x = 6",
+		Kind:        MessageKindQuiz,
+		Attachments: []MessageAttachment{{Type: "quiz", Reference: "F1-02/q1"}},
+	}); err != nil {
+		t.Fatalf("AddMessage(quiz) error = %v", err)
+	}
+
+	conv, err := store.GetConversation(convID)
+	if err != nil {
+		t.Fatalf("GetConversation() error = %v", err)
+	}
+	if len(conv.Messages) != 2 {
+		t.Fatalf("Messages count = %d, want 2", len(conv.Messages))
+	}
+	if conv.Messages[0].Kind != MessageKindText || len(conv.Messages[0].Attachments) != 0 {
+		t.Fatalf("Messages[0] = %#v, want plain text", conv.Messages[0])
+	}
+	quiz := conv.Messages[1]
+	if quiz.Kind != MessageKindQuiz || len(quiz.Attachments) != 1 || quiz.Attachments[0].Reference != "F1-02/q1" {
+		t.Fatalf("Messages[1] = %#v, want quiz with attachment", quiz)
+	}
+}
+
+func TestPostgresStore_MessageSafetyTraceRoundTrip(t *testing.T) {
+	ctx := context.Background()
+	pool, _ := startSchedulerPostgres(t, ctx)
+
+	store, err := NewPostgresStore(ctx, pool)
+	if err != nil {
+		t.Fatalf("NewPostgresStore() error = %v", err)
+	}
+
+	convID, err := store.CreateConversation(Conversation{UserID: "store-safety-user", State: "teaching"})
+	if err != nil {
+		t.Fatalf("CreateConversation() error = %v", err)
+	}
+	trace := &SafetyTrace{TurnID: "turn-1", Decisions: []SafetyDecision{
+		{Check: SafetyCheckContentFilter, Action: SafetyActionRegenerated},
+		{Check: SafetyCheckContentFilter, Action: SafetyActionMasked, Hits: 2},
+	}}
+	if _, err := store.AddMessage(convID, StoredMessage{Role: "user", Content: "hello"}); err != nil {
+		t.Fatalf("AddMessage(user) error = %v", err)
+	}
+	if _, err := store.AddMessage(convID, StoredMessage{Role: "assistant", Content: "Hi there.", Safety: trace}); err != nil {
+		t.Fatalf("AddMessage(assistant) error = %v", err)
+	}
+
+	conv, err := store.GetConversation(convID)
+	if err != nil {
+		t.Fatalf("GetConversation() error = %v", err)
+	}
+	if conv.Messages[0].Safety != nil {
+		t.Fatalf("Messages[0].Safety = %#v, want none", conv.Messages[0].Safety)
+	}
+	got := conv.Messages[1].Safety
+	if got == nil || got.TurnID != "turn-1" || len(got.Decisions) != 2 || got.Decisions[1].Hits != 2 {
+		t.Fatalf("Messages[1].Safety = %#v, want the stored trace", got)
+	}
+}
+
+func TestPostgresStore_ArchiveEndedConversationsKeepsRetrievalPath(t *testing.T) {
+	ctx := context.Background()
+	pool, _ := startSchedulerPostgres(t, ctx)
+
+	store, err := NewPostgresStore(ctx, pool)
+	if err != nil {
+		t.Fatalf("NewPostgresStore() error = %v", err)
+	}
+
+	convID, err := store.CreateConversation(Conversation{UserID: "store-archive-user", State: "teaching"})
+	if err != nil {
+		t.Fatalf("CreateConversation() error = %v", err)
+	}
+	for _, content := range []string{"what is 2x = 6?", "x = 3"} {
+		if _, err := store.AddMessage(convID, StoredMessage{Role: "user", Content: content}); err != nil {
+			t.Fatalf("AddMessage() error = %v", err)
+		}
+	}
+	if err := store.SetSummary(convID, "Solved a linear equation.", 1); err != nil {
+		t.Fatalf("SetSummary() error = %v", err)
+	}
+	if err := store.EndConversation(convID); err != nil {
+		t.Fatalf("EndConversation() error = %v", err)
+	}
+
+	archived, err := store.ArchiveEndedConversations(ctx, time.Now().Add(time.Minute), 10)
+	if err != nil {
+		t.Fatalf("ArchiveEndedConversations() error = %v", err)
+	}
+	if archived != 1 {
+		t.Fatalf("archived = %d, want 1", archived)
+	}
+	var hot int
+	if err := pool.QueryRow(ctx, `SELECT COUNT(*) FROM messages WHERE conversation_id = $1::uuid`, convID).Scan(&hot); err != nil {
+		t.Fatalf("count hot messages: %v", err)
+	}
+	if hot != 0 {
+		t.Fatalf("hot messages = %d, want 0 after archiving", hot)
+	}
+
+	conv, err := store.GetConversation(convID)
+	if err != nil {
+		t.Fatalf("GetConversation() error = %v", err)
+	}
+	if conv.ArchivedAt == nil || len(conv.Messages) != 2 || conv.Messages[1].Content != "x = 3" {
+		t.Fatalf("archived conversation = %+v, want messages restored from the archive", conv)
+	}
+	if conv.Summary != "Solved a linear equation." || conv.CompactedAt != 1 {
+		t.Fatalf("summary = %q/%d, want restored summary", conv.Summary, conv.CompactedAt)
+	}
+
+	again, err := store.ArchiveEndedConversations(ctx, time.Now().Add(time.Minute), 10)
+	if err != nil || again != 0 {
+		t.Fatalf("second ArchiveEndedConversations() = %d, %v; want 0, nil", again, err)
+	}
+}
+
+func TestPostgresStore_AddMessagesWritesOneBatch(t *testing.T) {
+	ctx := context.Background()
+	pool, _ := startSchedulerPostgres(t, ctx)
+
+	store, err := NewPostgresStore(ctx, pool)
+	if err != nil {
+		t.Fatalf("NewPostgresStore() error = %v", err)
+	}
+	convID, err := store.CreateConversation(Conversation{UserID: "store-batch-user", State: "teaching"})
+	if err != nil {
+		t.Fatalf("CreateConversation() error = %v", err)
+	}
+
+	now := time.Now()
+	ids, err := store.AddMessages(convID, []StoredMessage{
+		{Role: "user", Content: "soalan", CreatedAt: now},
+		{Role: "assistant", Content: "jawapan", Model: "mock", CreatedAt: now.Add(time.Millisecond)},
+	}, []Event{{EventType: "message_sent"}, {EventType: "ai_response"}})
+	if err != nil {
+		t.Fatalf("AddMessages() error = %v", err)
+	}
+	if len(ids) != 2 || ids[0] == "" || ids[1] == "" {
+		t.Fatalf("AddMessages() ids = %v, want two IDs", ids)
+	}
+
+	conv, err := store.GetConversation(convID)
+	if err != nil {
+		t.Fatalf("GetConversation() error = %v", err)
+	}
+	if len(conv.Messages) != 2 || conv.Messages[0].ID != ids[0] || conv.Messages[1].ID != ids[1] {
+		t.Fatalf("messages = %#v, want both batch rows in order", conv.Messages)
+	}
+	var events int
+	if err := pool.QueryRow(ctx, `SELECT COUNT(*) FROM events WHERE conversation_id = $1::uuid`, convID).Scan(&events); err != nil {
+		t.Fatalf("count events: %v", err)
+	}
+	if events != 2 {
+		t.Fatalf("events = %d, want 2", events)
+	}
+
+	// An invalid row fails the whole batch before anything is sent.
+	if _, err := store.AddMessages(convID, []StoredMessage{{Role: "user", Content: "x"}, {Role: "assistant"}}, nil); err == nil {
+		t.Fatal("AddMessages() with an empty message succeeded")
+	}
+}
@@ -192,6 +192,73 @@ func TestConversationStore_MultipleMessages(t *testing.T) {
 	}
 }
 
+func TestConversationStore_MessageKindAndAttachments(t *testing.T) {
+	store := agent.NewMemoryStore()
+	id, _ := store.CreateConversation(agent.Conversation{UserID: "123", State: "teaching"})
+
+	if _, err := store.AddMessage(id, agent.StoredMessage{Role: "user", Content: "Hello"}); err != nil {
+		t.Fatalf("AddMessage(text) error = %v", err)
+	}
+	if _, err := store.AddMessage(id, agent.StoredMessage{
+		Role:        "user",
+		Content:     "Can you check my working?",
+		Kind:        agent.MessageKindImage,
+		Attachments: []agent.MessageAttachment{{Type: "image", Reference: "file-123", Caption: "question 4"}},
+	}); err != nil {
+		t.Fatalf("AddMessage(image) error = %v", err)
+	}
+	if _, err := store.AddMessage(id, agent.StoredMessage{Role: "user", Content: "?", Kind: "video"}); err == nil {
+		t.Fatal("AddMessage(unknown kind) error = nil, want error")
+	}
+
+	got, _ := store.GetConversation(id)
+	if len(got.Messages) != 2 {
+		t.Fatalf("Messages count = %d, want 2", len(got.Messages))
+	}
+	if got.Messages[0].Kind != agent.MessageKindText {
+		t.Errorf("Messages[0].Kind = %q, want text default", got.Messages[0].Kind)
+	}
+	image := got.Messages[1]
+	if image.Kind != agent.MessageKindImage || len(image.Attachments) != 1 || image.Attachments[0].Reference != "file-123" || image.Attachments[0].Caption != "question 4" {
+		t.Errorf("Messages[1] = %#v, want image with attachment", image)
+	}
+}
+
+func TestConversationStore_EditAndDeleteMessage(t *testing.T) {
+	store := agent.NewMemoryStore()
+	id, _ := store.CreateConversation(agent.Conversation{UserID: "123", State: "teaching"})
+	msgID, _ := store.AddMessage(id, agent.StoredMessage{Role: "user", Content: "x = 3"})
+
+	if err := store.EditMessage(id, msgID, "x = 4"); err != nil {
+		t.Fatalf("EditMessage() error = %v", err)
+	}
+	if err := store.EditMessage(id, msgID, "x = 5"); err != nil {
+		t.Fatalf("EditMessage(second) error = %v", err)
+	}
+	got, _ := store.GetConversation(id)
+	edited := got.Messages[0]
+	if edited.Content != "x = 5" || edited.OriginalContent != "x = 3" || edited.EditedAt == nil {
+		t.Fatalf("edited message = %#v, want latest content with first version retained", edited)
+	}
+
+	if err := store.DeleteMessage(id, msgID); err != nil {
+		t.Fatalf("DeleteMessage() error = %v", err)
+	}
+	if err := store.DeleteMessage(id, msgID); err != nil {
+		t.Fatalf("DeleteMessage(repeat) error = %v", err)
+	}
+	got, _ = store.GetConversation(id)
+	if len(got.Messages) != 1 || got.Messages[0].Visible() {
+		t.Fatalf("messages = %#v, want one soft-deleted message", got.Messages)
+	}
+	if err := store.EditMessage(id, msgID, "x = 6"); err == nil {
+		t.Fatal("EditMessage(deleted) error = nil, want error")
+	}
+	if err := store.DeleteMessage(id, "missing"); err == nil {
+		t.Fatal("DeleteMessage(missing) error = nil, want error")
+	}
+}
+
 func TestConversationStore_SetSummary(t *testing.T) {
 	store := agent.NewMemoryStore()
 
@@ -0,0 +1,383 @@
+// Copyright 2026 the P&AI authors. All rights reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package agent
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"github.com/p-n-ai/pai-bot/internal/chat"
+	"github.com/p-n-ai/pai-bot/internal/curriculum"
+	"github.com/p-n-ai/pai-bot/internal/i18n"
+)
+
+// maxStudyPlanWeeks caps plans for far-off exams; beyond this the plan would
+// mostly be empty weeks.
+const maxStudyPlanWeeks = 26
+
+var studyPlanDateLayouts = []string{"2006-01-02", "2/1/2006", "02/01/2006", "2-1-2006"}
+
+// StudyPlan is a week-by-week revision plan leading up to an exam.
+type StudyPlan struct {
+	UserID    string
+	ExamDate  time.Time
+	Form      string
+	Weeks     []StudyPlanWeek
+	CreatedAt time.Time
+}
+
+// StudyPlanWeek is one week of a study plan. Review weeks revisit every weak
+// topic instead of introducing new ones.
+type StudyPlanWeek struct {
+	Week       int       `json:"week"`
+	StartsOn   time.Time `json:"starts_on"`
+	TopicIDs   []string  `json:"topic_ids"`
+	TopicNames []string  `json:"topic_names"`
+	Review     bool      `json:"review,omitempty"`
+	// NudgesPerDay paces scheduler reminders; it rises as the exam nears.
+	NudgesPerDay int `json:"nudges_per_day"`
+}
+
+// CurrentWeek returns the plan week containing now, or false once the exam
+// has passed or before the plan starts.
+func (p *StudyPlan) CurrentWeek(now time.Time) (StudyPlanWeek, bool) {
+	if p == nil || len(p.Weeks) == 0 || !now.Before(p.ExamDate.AddDate(0, 0, 1)) {
+		return StudyPlanWeek{}, false
+	}
+	for i := len(p.Weeks) - 1; i >= 0; i-- {
+		if !now.Before(p.Weeks[i].StartsOn) {
+			return p.Weeks[i], true
+		}
+	}
+	return StudyPlanWeek{}, false
+}
+
+// StudyPlanStore persists one active study plan per user.
+type StudyPlanStore interface {
+	SaveStudyPlan(userID string, plan StudyPlan) error
+	GetStudyPlan(userID string) (*StudyPlan, bool, error)
+	ClearStudyPlan(userID string) error
+}
+
+// MemoryStudyPlanStore is an in-memory StudyPlanStore.
+type MemoryStudyPlanStore struct {
+	mu    sync.RWMutex
+	plans map[string]StudyPlan
+}
+
+func NewMemoryStudyPlanStore() *MemoryStudyPlanStore {
+	return &MemoryStudyPlanStore{plans: make(map[string]StudyPlan)}
+}
+
+func (s *MemoryStudyPlanStore) SaveStudyPlan(userID string, plan StudyPlan) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	plan.UserID = userID
+	plan.Weeks = append([]StudyPlanWeek(nil), plan.Weeks...)
+	s.plans[userID] = plan
+	return nil
+}
+
+func (s *MemoryStudyPlanStore) GetStudyPlan(userID string) (*StudyPlan, bool, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	plan, ok := s.plans[userID]
+	if !ok {
+		return nil, false, nil
+	}
+	plan.Weeks = append([]StudyPlanWeek(nil), plan.Weeks...)
+	return &plan, true, nil
+}
+
+func (s *MemoryStudyPlanStore) ClearStudyPlan(userID string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.plans, userID)
+	return nil
+}
+
+// PostgresStudyPlanStore persists study plans in PostgreSQL.
+type PostgresStudyPlanStore struct {
+	pool     *pgxpool.Pool
+	tenantID string
+	channel  string
+}
+
+func NewPostgresStudyPlanStore(pool *pgxpool.Pool, tenantID string) *PostgresStudyPlanStore {
+	return &PostgresStudyPlanStore{pool: pool, tenantID: tenantID, channel: defaultChannel}
+}
+
+func (s *PostgresStudyPlanStore) SaveStudyPlan(externalID string, plan StudyPlan) error {
+	ctx, cancel := context.WithTimeout(context.Background(), dbTimeout)
+	defer cancel()
+
+	weeks, err := json.Marshal(plan.Weeks)
+	if err != nil {
+		return fmt.Errorf("marshal study plan weeks: %w", err)
+	}
+	tag, err := s.pool.Exec(ctx,
+		`INSERT INTO study_plans (user_id, tenant_id, exam_date, form, weeks)
+		 SELECT id, $1::uuid, $4, $5, $6
+		 FROM users
+		 WHERE tenant_id = $1::uuid AND channel = $2 AND external_id = $3
+		 ORDER BY created_at ASC
+		 LIMIT 1
+		 ON CONFLICT (user_id) DO UPDATE
+		 SET exam_date = EXCLUDED.exam_date,
+		     form = EXCLUDED.form,
+		     weeks = EXCLUDED.weeks,
+		     created_at = NOW()`,
+		s.tenantID, s.channel, externalID, plan.ExamDate, plan.Form, weeks,
+	)
+	if err != nil {
+		return fmt.Errorf("save study plan: %w", err)
+	}
+	if tag.RowsAffected() == 0 {
+		return fmt.Errorf("save study plan: user %s not found", externalID)
+	}
+	return nil
+}
+
+func (s *PostgresStudyPlanStore) GetStudyPlan(externalID string) (*StudyPlan, bool, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), dbTimeout)
+	defer cancel()
+
+	plan := &StudyPlan{UserID: externalID}
+	var weeks []byte
+	err := s.pool.QueryRow(ctx,
+		`SELECT p.exam_date, p.form, p.weeks, p.created_at
+		 FROM study_plans p
+		 JOIN users u ON u.id = p.user_id
+		 WHERE p.tenant_id = $1::uuid AND u.channel = $2 AND u.external_id = $3`,
+		s.tenantID, s.channel, externalID,
+	).Scan(&plan.ExamDate, &plan.Form, &weeks, &plan.CreatedAt)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, false, nil
+	}
+	if err != nil {
+		return nil, false, fmt.Errorf("get study plan: %w", err)
+	}
+	if err := json.Unmarshal(weeks, &plan.Weeks); err != nil {
+		return nil, false, fmt.Errorf("decode study plan weeks: %w", err)
+	}
+	return plan, true, nil
+}
+
+func (s *PostgresStudyPlanStore) ClearStudyPlan(externalID string) error {
+	ctx, cancel := context.WithTimeout(context.Background(), dbTimeout)
+	defer cancel()
+
+	_, err := s.pool.Exec(ctx,
+		`DELETE FROM study_plans p
+		 USING users u
+		 WHERE u.id = p.user_id AND p.tenant_id = $1::uuid AND u.channel = $2 AND u.external_id = $3`,
+		s.tenantID, s.channel, externalID,
+	)
+	if err != nil {
+		return fmt.Errorf("clear study plan: %w", err)
+	}
+	return nil
+}
+
+// handlePlanCommand handles "/plan", "/plan <exam date>", and "/plan clear".
+func (e *Engine) handlePlanCommand(msg chat.InboundMessage, args []string) (string, error) {
+	locale := e.messageLocale(msg, nil)
+	now := time.Now()
+
+	if len(args) == 0 {
+		plan, found, err := e.studyPlans.GetStudyPlan(msg.UserID)
+		if err != nil {
+			slog.Error("failed to load study plan", "user_id", msg.UserID, "error", err)
+			return i18n.S(locale, i18n.MsgTechnicalIssue), nil
+		}
+		if !found {
+			return i18n.S(locale, i18n.MsgPlanUsage), nil
+		}
+		return formatStudyPlan(plan, now), nil
+	}
+
+	if strings.EqualFold(args[0], "clear") {
+		if err := e.studyPlans.ClearStudyPlan(msg.UserID); err != nil {
+			slog.Error("failed to clear study plan", "user_id", msg.UserID, "error", err)
+			return i18n.S(locale, i18n.MsgTechnicalIssue), nil
+		}
+		return i18n.S(locale, i18n.MsgPlanCleared), nil
+	}
+
+	examDate, ok := parseStudyPlanDate(strings.Join(args, " "))
+	if !ok || !examDate.After(now) {
+		return i18n.S(locale, i18n.MsgPlanUsage), nil
+	}
+
+	form, _ := e.store.GetUserForm(msg.UserID)
+	plan := buildStudyPlan(examDate, form, e.studyPlanTopics(form), e.topicMastery(msg.UserID), now)
+	if len(plan.Weeks) == 0 {
+		return i18n.S(locale, i18n.MsgPlanNoTopics), nil
+	}
+	if err := e.studyPlans.SaveStudyPlan(msg.UserID, plan); err != nil {
+		slog.Error("failed to save study plan", "user_id", msg.UserID, "error", err)
+		return i18n.S(locale, i18n.MsgTechnicalIssue), nil
+	}
+
+	e.logEventAsync(Event{
+		UserID:    msg.UserID,
+		EventType: "study_plan_created",
+		Data: map[string]any{
+			"exam_date": examDate.Format("2006-01-02"),
+			"form":      form,
+			"weeks":     len(plan.Weeks),
+		},
+	})
+	return formatStudyPlan(&plan, now), nil
+}
+
+// studyPlanTopics returns the curriculum topics for the learner's form, or
+// every topic when the form is unknown or matches nothing.
+func (e *Engine) studyPlanTopics(form string) []curriculum.Topic {
+	if e.curriculumLoader == nil {
+		return nil
+	}
+	all := e.curriculumLoader.AllTopics()
+	if form == "" {
+		return all
+	}
+	var matched []curriculum.Topic
+	for _, topic := range all {
+		subject, _ := e.curriculumLoader.GetSubject(topic.SubjectID)
+		if inferTopicForm(topic, subject) == form {
+			matched = append(matched, topic)
+		}
+	}
+	if len(matched) == 0 {
+		return all
+	}
+	return matched
+}
+
+func (e *Engine) topicMastery(userID string) map[string]float64 {
+	mastery := make(map[string]float64)
+	if e.tracker == nil {
+		return mastery
+	}
+	items, err := e.tracker.GetAllProgress(userID)
+	if err != nil {
+		slog.Warn("failed to load progress for study plan", "user_id", userID, "error", err)
+		return mastery
+	}
+	for _, item := range items {
+		mastery[item.TopicID] = item.MasteryScore
+	}
+	return mastery
+}
+
+// buildStudyPlan spreads the learner's weak topics, weakest first, across the
+// weeks before the exam and keeps the final week for review.
+func buildStudyPlan(examDate time.Time, form string, topics []curriculum.Topic, mastery map[string]float64, now time.Time) StudyPlan {
+	plan := StudyPlan{ExamDate: examDate, Form: form, CreatedAt: now}
+
+	var weak []curriculum.Topic
+	for _, topic := range topics {
+		if mastery[topic.ID] < defaultGoalTargetMastery {
+			weak = append(weak, topic)
+		}
+	}
+	if len(weak) == 0 {
+		return plan
+	}
+	sort.SliceStable(weak, func(i, j int) bool {
+		if mastery[weak[i].ID] != mastery[weak[j].ID] {
+			return mastery[weak[i].ID] < mastery[weak[j].ID]
+		}
+		return weak[i].ID < weak[j].ID
+	})
+
+	start := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
+	totalWeeks := int(examDate.Sub(start).Hours()/(24*7)) + 1
+	totalWeeks = min(max(totalWeeks, 1), maxStudyPlanWeeks)
+
+	studyWeeks := totalWeeks
+	if totalWeeks > 1 {
+		studyWeeks--
+	}
+	perWeek := (len(weak) + studyWeeks - 1) / studyWeeks
+
+	for w := 0; w < totalWeeks; w++ {
+		week := StudyPlanWeek{
+			Week:         w + 1,
+			StartsOn:     start.AddDate(0, 0, 7*w),
+			NudgesPerDay: studyPlanNudgePace(totalWeeks - w),
+		}
+		var chunk []curriculum.Topic
+		if w < studyWeeks {
+			lo := min(w*perWeek, len(weak))
+			chunk = weak[lo:min(lo+perWeek, len(weak))]
+		} else {
+			week.Review = true
+			chunk = weak
+		}
+		for _, topic := range chunk {
+			week.TopicIDs = append(week.TopicIDs, topic.ID)
+			week.TopicNames = append(week.TopicNames, topic.Name)
+		}
+		plan.Weeks = append(plan.Weeks, week)
+	}
+	return plan
+}
+
+// studyPlanNudgePace returns the daily reminder budget for a week, given how
+// many weeks remain including it.
+func studyPlanNudgePace(weeksLeft int) int {
+	switch {
+	case weeksLeft <= 2:
+		return MaxNudgesPerDay
+	case weeksLeft <= 4:
+		return 2
+	default:
+		return 1
+	}
+}
+
+func parseStudyPlanDate(raw string) (time.Time, bool) {
+	raw = strings.TrimSpace(raw)
+	loc, err := time.LoadLocation("Asia/Kuala_Lumpur")
+	if err != nil {
+		loc = time.FixedZone("MYT", 8*60*60)
+	}
+	for _, layout := range studyPlanDateLayouts {
+		if t, err := time.ParseInLocation(layout, raw, loc); err == nil {
+			return t, true
+		}
+	}
+	return time.Time{}, false
+}
+
+func formatStudyPlan(plan *StudyPlan, now time.Time) string {
+	var b strings.Builder
+	days := int(plan.ExamDate.Sub(now).Hours()/24) + 1
+	fmt.Fprintf(&b, "Study plan for your exam on %s (%d days left):\n", plan.ExamDate.Format("2 Jan 2006"), max(days, 0))
+	current, hasCurrent := plan.CurrentWeek(now)
+	for _, week := range plan.Weeks {
+		marker := ""
+		if hasCurrent && week.Week == current.Week {
+			marker = " ← this week"
+		}
+		label := strings.Join(week.TopicNames, ", ")
+		if week.Review {
+			label = "Review: " + label
+		}
+		fmt.Fprintf(&b, "\nWeek %d (from %s)%s\n- %s", week.Week, week.StartsOn.Format("2 Jan"), marker, label)
+	}
+	b.WriteString("\n\nUse /plan clear to remove this plan.")
+	return b.String()
+}
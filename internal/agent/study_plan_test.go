@@ -0,0 +1,150 @@
+// Copyright 2026 the P&AI authors. All rights reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package agent_test
+
+import (
+	"context"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/p-n-ai/pai-bot/internal/agent"
+	"github.com/p-n-ai/pai-bot/internal/chat"
+	"github.com/p-n-ai/pai-bot/internal/platform/featureflags"
+	"github.com/p-n-ai/pai-bot/internal/progress"
+)
+
+func TestEngine_PlanCommandBuildsPlanForFormWeakTopics(t *testing.T) {
+	store := agent.NewMemoryStore()
+	_ = store.SetUserForm("plan-user", "1")
+	plans := agent.NewMemoryStudyPlanStore()
+	engine := agent.NewEngine(agent.EngineConfig{
+		Store:            store,
+		CurriculumLoader: createMultiTopicCurriculumLoaderForResolverTest(t, false),
+		Tracker:          progress.NewMemoryTracker(),
+		StudyPlans:       plans,
+	})
+	ctx := context.Background()
+	examDate := time.Now().AddDate(0, 0, 20).Format("2006-01-02")
+
+	resp, err := engine.ProcessMessage(ctx, chat.InboundMessage{Channel: "telegram", UserID: "plan-user", Text: "/plan " + examDate})
+	if err != nil {
+		t.Fatalf("ProcessMessage(/plan) error = %v", err)
+	}
+	if !strings.Contains(resp, "Week 1") || !strings.Contains(resp, "Review:") {
+		t.Fatalf("response = %q, want weekly plan ending in review", resp)
+	}
+
+	plan, found, err := plans.GetStudyPlan("plan-user")
+	if err != nil || !found {
+		t.Fatalf("GetStudyPlan() = %v, %v, want saved plan", found, err)
+	}
+	if len(plan.Weeks) != 3 {
+		t.Fatalf("weeks = %d, want 3", len(plan.Weeks))
+	}
+	if !slices.Equal(plan.Weeks[0].TopicIDs, []string{"F1-02"}) {
+		t.Fatalf("week 1 topics = %v, want only the Form 1 topic", plan.Weeks[0].TopicIDs)
+	}
+	if last := plan.Weeks[len(plan.Weeks)-1]; !last.Review || last.NudgesPerDay != agent.MaxNudgesPerDay {
+		t.Fatalf("final week = %#v, want review at full nudge pace", last)
+	}
+
+	resp, _ = engine.ProcessMessage(ctx, chat.InboundMessage{Channel: "telegram", UserID: "plan-user", Text: "/plan"})
+	if !strings.Contains(resp, "this week") {
+		t.Fatalf("/plan = %q, want saved plan with current week", resp)
+	}
+	_, _ = engine.ProcessMessage(ctx, chat.InboundMessage{Channel: "telegram", UserID: "plan-user", Text: "/plan clear"})
+	if _, found, _ := plans.GetStudyPlan("plan-user"); found {
+		t.Fatal("plan should be removed after /plan clear")
+	}
+}
+
+func TestEngine_PlanCommandRejectsPastDate(t *testing.T) {
+	engine := agent.NewEngine(agent.EngineConfig{Store: agent.NewMemoryStore()})
+	resp, _ := engine.ProcessMessage(context.Background(), chat.InboundMessage{Channel: "telegram", UserID: "plan-user", Text: "/plan 2020-01-01"})
+	if !strings.Contains(resp, "/plan 2026-11-20") {
+		t.Fatalf("response = %q, want usage", resp)
+	}
+}
+
+func TestScheduler_StudyPlanPacesAndFocusesNudges(t *testing.T) {
+	tracker := progress.NewMemoryTracker()
+	_ = tracker.SetMastery("plan-user", "malaysia-kssm", "F1-01", 0.2)
+	_ = tracker.SetMastery("plan-user", "malaysia-kssm", "F1-02", 0.4)
+	mockCh := &chat.MockChannel{}
+	gw := chat.NewGateway()
+	gw.Register("telegram", mockCh)
+
+	loc, _ := time.LoadLocation("Asia/Kuala_Lumpur")
+	y, m, d := time.Now().In(loc).Date()
+	now := time.Date(y, m, d, 10, 0, 0, 0, loc)
+	plans := agent.NewMemoryStudyPlanStore()
+	_ = plans.SaveStudyPlan("plan-user", agent.StudyPlan{
+		ExamDate: now.AddDate(0, 0, 40),
+		Weeks: []agent.StudyPlanWeek{{
+			Week: 1, StartsOn: now.AddDate(0, 0, -1), TopicIDs: []string{"F1-02"}, NudgesPerDay: 1,
+		}},
+	})
+
+	scheduler := agent.NewScheduler(
+		agent.SchedulerConfig{CheckInterval: time.Second, MaxNudgesPerDay: 3},
+		tracker, nil, nil, nil,
+		agent.NewMemoryNudgeTracker(), gw, nil, nil,
+	)
+	scheduler.SetStudyPlans(plans)
+
+	ctx := context.Background()
+	for range 2 {
+		if err := scheduler.CheckUserForNudge(ctx, "plan-user", now); err != nil {
+			t.Fatalf("CheckUserForNudge() error = %v", err)
+		}
+	}
+	if len(mockCh.SentMessages) != 1 {
+		t.Fatalf("nudges sent = %d, want 1 at the plan's daily pace", len(mockCh.SentMessages))
+	}
+	if text := mockCh.SentMessages[0].Text; !strings.Contains(text, "F1-02") {
+		t.Fatalf("nudge = %q, want this week's planned topic", text)
+	}
+}
+
+func TestEngine_ProgressShowsExamCountdownWhenRevisionModeEnabled(t *testing.T) {
+	store := agent.NewMemoryStore()
+	_ = store.SetUserForm("exam-user", "3")
+	// 23:30 UTC is already the next morning in Malaysia, where exam dates
+	// are counted.
+	now := time.Date(2026, 9, 1, 23, 30, 0, 0, time.UTC)
+	calendar, err := agent.ParseExamCalendar("PT3:1,2,3:2026-10-02")
+	if err != nil {
+		t.Fatalf("ParseExamCalendar() error = %v", err)
+	}
+	enabled := false
+	engine := agent.NewEngine(agent.EngineConfig{
+		Store:        store,
+		Tracker:      progress.NewMemoryTracker(),
+		ExamCalendar: calendar,
+		Clock:        func() time.Time { return now },
+		FeatureFlags: func() featureflags.Features {
+			if !enabled {
+				return featureflags.Features{}
+			}
+			flags, _ := featureflags.Parse(string(featureflags.RevisionMode))
+			return flags
+		},
+	})
+	msg := chat.InboundMessage{Channel: "telegram", UserID: "exam-user", Text: "/progress", Language: "en"}
+
+	resp, _ := engine.ProcessMessage(context.Background(), msg)
+	if strings.Contains(resp, "PT3") {
+		t.Fatalf("/progress = %q, want no countdown while revision_mode is off", resp)
+	}
+	enabled = true
+	resp, _ = engine.ProcessMessage(context.Background(), msg)
+	if !strings.Contains(resp, "PT3: 30 days to go") {
+		t.Fatalf("/progress = %q, want PT3 countdown", resp)
+	}
+	if !strings.Contains(resp, "Revision mode is on") {
+		t.Fatalf("/progress = %q, want revision mode notice", resp)
+	}
+}
@@ -0,0 +1,420 @@
+// Copyright 2026 the P&AI authors. All rights reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package agent
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"github.com/p-n-ai/pai-bot/internal/chat"
+	"github.com/p-n-ai/pai-bot/internal/i18n"
+)
+
+// SubscriptionTier is a learner's plan. The tier's name is also the router's
+// tier key, so it picks the models the learner may use.
+type SubscriptionTier string
+
+const (
+	TierFree    SubscriptionTier = "free"
+	TierPremium SubscriptionTier = "premium"
+)
+
+// ParseSubscriptionTier accepts "free" or "premium", ignoring case.
+func ParseSubscriptionTier(raw string) (SubscriptionTier, bool) {
+	switch tier := SubscriptionTier(strings.ToLower(strings.TrimSpace(raw))); tier {
+	case TierFree, TierPremium:
+		return tier, true
+	default:
+		return "", false
+	}
+}
+
+// Subscription statuses follow the billing provider's; only active and
+// trialing grant the tier.
+const (
+	SubscriptionActive   = "active"
+	SubscriptionTrialing = "trialing"
+	SubscriptionPastDue  = "past_due"
+	SubscriptionCanceled = "canceled"
+)
+
+// Subscription is a paid plan for one learner or, with an empty UserID, for
+// every learner in the tenant. A learner's own active subscription wins over
+// the tenant's.
+type Subscription struct {
+	UserID         string
+	Tier           SubscriptionTier
+	Status         string
+	Provider       string // e.g. "stripe"
+	CustomerID     string
+	SubscriptionID string // the provider's ID, used to match later events
+	PeriodEnd      *time.Time
+	UpdatedAt      time.Time
+}
+
+// Active reports whether s grants its tier at now.
+func (s Subscription) Active(now time.Time) bool {
+	if s.Status != SubscriptionActive && s.Status != SubscriptionTrialing {
+		return false
+	}
+	return s.PeriodEnd == nil || now.Before(*s.PeriodEnd)
+}
+
+// SubscriptionStore persists subscriptions and each learner's daily token
+// usage. Learners are keyed by external ID since payment webhooks arrive
+// without a users record.
+type SubscriptionStore interface {
+	// GetSubscription returns the learner's subscription, or the tenant-wide
+	// one when userID is empty.
+	GetSubscription(userID string) (*Subscription, bool, error)
+	// FindSubscription returns the subscription the provider knows as
+	// subscriptionID.
+	FindSubscription(provider, subscriptionID string) (*Subscription, bool, error)
+	UpsertSubscription(sub Subscription) error
+	// AddTokenUsage adds tokens to the learner's total for day (YYYY-MM-DD)
+	// and returns the new total.
+	AddTokenUsage(userID, day string, tokens int) (int, error)
+	TokenUsage(userID, day string) (int, error)
+}
+
+// SubscriptionPlans configures the tiers. A zero daily budget is unlimited.
+type SubscriptionPlans struct {
+	FreeDailyTokens    int
+	PremiumDailyTokens int
+	// CheckoutURL is a payment link /subscribe offers free learners. The
+	// learner's ID is added as client_reference_id so the checkout webhook
+	// can find them.
+	CheckoutURL string
+	// ManageURL is where premium learners manage or cancel their plan.
+	ManageURL string
+}
+
+func (p SubscriptionPlans) dailyTokens(tier SubscriptionTier) int {
+	if tier == TierPremium {
+		return p.PremiumDailyTokens
+	}
+	return p.FreeDailyTokens
+}
+
+// checkoutURL returns CheckoutURL with userID attached, or "" when unset.
+func (p SubscriptionPlans) checkoutURL(userID string) string {
+	if p.CheckoutURL == "" {
+		return ""
+	}
+	u, err := url.Parse(p.CheckoutURL)
+	if err != nil {
+		return ""
+	}
+	q := u.Query()
+	q.Set("client_reference_id", userID)
+	u.RawQuery = q.Encode()
+	return u.String()
+}
+
+// usageDay returns the MYT date budgets reset on.
+func usageDay(t time.Time) string {
+	loc, err := time.LoadLocation("Asia/Kuala_Lumpur")
+	if err != nil {
+		loc = time.FixedZone("MYT", 8*60*60)
+	}
+	return t.In(loc).Format("2006-01-02")
+}
+
+// MemorySubscriptionStore is an in-memory SubscriptionStore.
+type MemorySubscriptionStore struct {
+	mu    sync.RWMutex
+	subs  map[string]Subscription
+	usage map[string]int
+}
+
+func NewMemorySubscriptionStore() *MemorySubscriptionStore {
+	return &MemorySubscriptionStore{
+		subs:  make(map[string]Subscription),
+		usage: make(map[string]int),
+	}
+}
+
+func (s *MemorySubscriptionStore) GetSubscription(userID string) (*Subscription, bool, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	sub, ok := s.subs[userID]
+	if !ok {
+		return nil, false, nil
+	}
+	return &sub, true, nil
+}
+
+func (s *MemorySubscriptionStore) FindSubscription(provider, subscriptionID string) (*Subscription, bool, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	for _, sub := range s.subs {
+		if sub.Provider == provider && sub.SubscriptionID == subscriptionID {
+			return &sub, true, nil
+		}
+	}
+	return nil, false, nil
+}
+
+func (s *MemorySubscriptionStore) UpsertSubscription(sub Subscription) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	sub.UpdatedAt = time.Now()
+	s.subs[sub.UserID] = sub
+	return nil
+}
+
+func (s *MemorySubscriptionStore) AddTokenUsage(userID, day string, tokens int) (int, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	key := userID + ":" + day
+	s.usage[key] += tokens
+	return s.usage[key], nil
+}
+
+func (s *MemorySubscriptionStore) TokenUsage(userID, day string) (int, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.usage[userID+":"+day], nil
+}
+
+// PostgresSubscriptionStore persists subscriptions and token usage in
+// PostgreSQL.
+type PostgresSubscriptionStore struct {
+	pool     *pgxpool.Pool
+	tenantID string
+}
+
+func NewPostgresSubscriptionStore(pool *pgxpool.Pool, tenantID string) *PostgresSubscriptionStore {
+	return &PostgresSubscriptionStore{pool: pool, tenantID: tenantID}
+}
+
+const subscriptionColumns = `external_id, tier, status, provider, customer_id, subscription_id, period_end, updated_at`
+
+func scanSubscription(row pgx.Row) (*Subscription, bool, error) {
+	var sub Subscription
+	var tier string
+	err := row.Scan(&sub.UserID, &tier, &sub.Status, &sub.Provider, &sub.CustomerID, &sub.SubscriptionID, &sub.PeriodEnd, &sub.UpdatedAt)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, false, nil
+	}
+	if err != nil {
+		return nil, false, err
+	}
+	sub.Tier = SubscriptionTier(tier)
+	return &sub, true, nil
+}
+
+func (s *PostgresSubscriptionStore) GetSubscription(userID string) (*Subscription, bool, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), dbTimeout)
+	defer cancel()
+
+	sub, found, err := scanSubscription(s.pool.QueryRow(ctx,
+		`SELECT `+subscriptionColumns+`
+		 FROM subscriptions
+		 WHERE tenant_id = $1::uuid AND external_id = $2`,
+		s.tenantID, userID,
+	))
+	if err != nil {
+		return nil, false, fmt.Errorf("get subscription: %w", err)
+	}
+	return sub, found, nil
+}
+
+func (s *PostgresSubscriptionStore) FindSubscription(provider, subscriptionID string) (*Subscription, bool, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), dbTimeout)
+	defer cancel()
+
+	sub, found, err := scanSubscription(s.pool.QueryRow(ctx,
+		`SELECT `+subscriptionColumns+`
+		 FROM subscriptions
+		 WHERE tenant_id = $1::uuid AND provider = $2 AND subscription_id = $3`,
+		s.tenantID, provider, subscriptionID,
+	))
+	if err != nil {
+		return nil, false, fmt.Errorf("find subscription: %w", err)
+	}
+	return sub, found, nil
+}
+
+func (s *PostgresSubscriptionStore) UpsertSubscription(sub Subscription) error {
+	ctx, cancel := context.WithTimeout(context.Background(), dbTimeout)
+	defer cancel()
+
+	if _, err := s.pool.Exec(ctx,
+		`INSERT INTO subscriptions (tenant_id, external_id, tier, status, provider, customer_id, subscription_id, period_end)
+		 VALUES ($1::uuid, $2, $3, $4, $5, $6, $7, $8)
+		 ON CONFLICT (tenant_id, external_id) DO UPDATE
+		 SET tier = EXCLUDED.tier,
+		     status = EXCLUDED.status,
+		     provider = EXCLUDED.provider,
+		     customer_id = EXCLUDED.customer_id,
+		     subscription_id = EXCLUDED.subscription_id,
+		     period_end = EXCLUDED.period_end,
+		     updated_at = NOW()`,
+		s.tenantID, sub.UserID, string(sub.Tier), sub.Status, sub.Provider, sub.CustomerID, sub.SubscriptionID, sub.PeriodEnd,
+	); err != nil {
+		return fmt.Errorf("upsert subscription: %w", err)
+	}
+	return nil
+}
+
+func (s *PostgresSubscriptionStore) AddTokenUsage(userID, day string, tokens int) (int, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), dbTimeout)
+	defer cancel()
+
+	var total int
+	if err := s.pool.QueryRow(ctx,
+		`INSERT INTO daily_token_usage (tenant_id, external_id, day, tokens)
+		 VALUES ($1::uuid, $2, $3::date, $4)
+		 ON CONFLICT (tenant_id, external_id, day) DO UPDATE
+		 SET tokens = daily_token_usage.tokens + EXCLUDED.tokens
+		 RETURNING tokens`,
+		s.tenantID, userID, day, tokens,
+	).Scan(&total); err != nil {
+		return 0, fmt.Errorf("add token usage: %w", err)
+	}
+	return total, nil
+}
+
+func (s *PostgresSubscriptionStore) TokenUsage(userID, day string) (int, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), dbTimeout)
+	defer cancel()
+
+	var total int
+	err := s.pool.QueryRow(ctx,
+		`SELECT tokens FROM daily_token_usage
+		 WHERE tenant_id = $1::uuid AND external_id = $2 AND day = $3::date`,
+		s.tenantID, userID, day,
+	).Scan(&total)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return 0, nil
+	}
+	if err != nil {
+		return 0, fmt.Errorf("get token usage: %w", err)
+	}
+	return total, nil
+}
+
+// activeSubscription returns the subscription that sets userID's tier: their
+// own when active, otherwise the tenant's when active.
+func (e *Engine) activeSubscription(userID string) (*Subscription, error) {
+	now := time.Now()
+	for _, key := range []string{userID, ""} {
+		sub, found, err := e.subscriptions.GetSubscription(key)
+		if err != nil {
+			return nil, err
+		}
+		if found && sub.Active(now) {
+			return sub, nil
+		}
+	}
+	return nil, nil
+}
+
+// subscriptionTier returns userID's tier, or "" when subscriptions are off so
+// requests keep the usual routing. Lookup errors fall back to the free tier.
+func (e *Engine) subscriptionTier(userID string) SubscriptionTier {
+	if e.subscriptions == nil || userID == "" {
+		return ""
+	}
+	sub, err := e.activeSubscription(userID)
+	if err != nil {
+		slog.Warn("failed to load subscription", "user_id", userID, "error", err)
+		return TierFree
+	}
+	if sub == nil {
+		return TierFree
+	}
+	return sub.Tier
+}
+
+// overDailyBudget reports whether userID has used their tier's tokens for
+// today. Usage lookup errors let the turn through.
+func (e *Engine) overDailyBudget(userID string) bool {
+	if e.subscriptions == nil || IsSandboxUser(userID) {
+		return false
+	}
+	limit := e.subscriptionPlans.dailyTokens(e.subscriptionTier(userID))
+	if limit <= 0 {
+		return false
+	}
+	used, err := e.subscriptions.TokenUsage(userID, usageDay(time.Now()))
+	if err != nil {
+		slog.Warn("failed to load token usage", "user_id", userID, "error", err)
+		return false
+	}
+	return used >= limit
+}
+
+// recordTokenUsage counts a completion against the learner's daily tier
+// budget and the tenant's token budgets. Sandbox turns count against neither.
+func (e *Engine) recordTokenUsage(userID string, tokens int) {
+	if tokens <= 0 || IsSandboxUser(userID) {
+		return
+	}
+	if e.subscriptions != nil {
+		if _, err := e.subscriptions.AddTokenUsage(userID, usageDay(time.Now()), tokens); err != nil {
+			slog.Warn("failed to record token usage", "user_id", userID, "tokens", tokens, "error", err)
+		}
+	}
+	if e.budget != nil {
+		if err := e.budget.Record(e.tenantID, userID, tokens); err != nil {
+			slog.Warn("failed to record token budget usage", "tenant_id", e.tenantID, "user_id", userID, "tokens", tokens, "error", err)
+		}
+	}
+}
+
+// handleSubscribeCommand shows the learner's plan and today's usage, with a
+// checkout or manage link when configured.
+func (e *Engine) handleSubscribeCommand(msg chat.InboundMessage) (string, error) {
+	locale := e.messageLocale(msg, nil)
+	if e.subscriptions == nil {
+		return i18n.S(locale, i18n.MsgUnknownCommand, "/subscribe"), nil
+	}
+	sub, err := e.activeSubscription(msg.UserID)
+	if err != nil {
+		slog.Error("failed to load subscription for /subscribe", "user_id", msg.UserID, "error", err)
+		return i18n.S(locale, i18n.MsgTechnicalIssue), nil
+	}
+	tier := TierFree
+	if sub != nil {
+		tier = sub.Tier
+	}
+
+	var lines []string
+	switch {
+	case tier != TierPremium:
+		lines = append(lines, i18n.S(locale, i18n.MsgSubscribeFree))
+	case sub.PeriodEnd != nil:
+		lines = append(lines, i18n.S(locale, i18n.MsgSubscribePremiumEnds, sub.PeriodEnd.Format("2006-01-02")))
+	default:
+		lines = append(lines, i18n.S(locale, i18n.MsgSubscribePremium))
+	}
+	if limit := e.subscriptionPlans.dailyTokens(tier); limit > 0 {
+		used, err := e.subscriptions.TokenUsage(msg.UserID, usageDay(time.Now()))
+		if err != nil {
+			slog.Warn("failed to load token usage for /subscribe", "user_id", msg.UserID, "error", err)
+		} else {
+			lines = append(lines, i18n.S(locale, i18n.MsgSubscribeUsage, min(used, limit), limit))
+		}
+	}
+	if tier == TierPremium {
+		// A tenant-wide plan is managed by the school, not the learner.
+		if e.subscriptionPlans.ManageURL != "" && sub.UserID != "" {
+			lines = append(lines, i18n.S(locale, i18n.MsgSubscribeManage, e.subscriptionPlans.ManageURL))
+		}
+	} else if link := e.subscriptionPlans.checkoutURL(msg.UserID); link != "" {
+		lines = append(lines, i18n.S(locale, i18n.MsgSubscribeCheckout, link))
+	}
+	return strings.Join(lines, "\n\n"), nil
+}
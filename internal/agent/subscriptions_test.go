@@ -0,0 +1,134 @@
+// Copyright 2026 the P&AI authors. All rights reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package agent_test
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/p-n-ai/pai-bot/internal/agent"
+	"github.com/p-n-ai/pai-bot/internal/ai"
+	"github.com/p-n-ai/pai-bot/internal/i18n"
+)
+
+func newSubscriptionEngine(t *testing.T, plans agent.SubscriptionPlans) (*agent.Engine, *agent.MemorySubscriptionStore, *ai.MockProvider) {
+	t.Helper()
+	mockAI := ai.NewMockProvider("Jawapan tutor.")
+	subs := agent.NewMemorySubscriptionStore()
+	engine := agent.NewEngine(agent.EngineConfig{
+		AIRouter:          mockRouter(mockAI),
+		Subscriptions:     subs,
+		SubscriptionPlans: plans,
+	})
+	return engine, subs, mockAI
+}
+
+func TestSubscribeCommandShowsPlanUsageAndCheckoutLink(t *testing.T) {
+	engine, _, _ := newSubscriptionEngine(t, agent.SubscriptionPlans{
+		FreeDailyTokens: 1000,
+		CheckoutURL:     "https://buy.stripe.com/test_abc",
+	})
+	sendAs(t, engine, "websocket", "42", "What is a fraction?")
+
+	got := sendAs(t, engine, "websocket", "42", "/subscribe")
+	if !strings.HasPrefix(got, i18n.S("en", i18n.MsgSubscribeFree)) {
+		t.Fatalf("/subscribe = %q, want the free plan first", got)
+	}
+	if !strings.Contains(got, "Tokens today: 24 of 1000.") {
+		t.Errorf("/subscribe = %q, want today's usage", got)
+	}
+	if !strings.Contains(got, "https://buy.stripe.com/test_abc?client_reference_id=42") {
+		t.Errorf("/subscribe = %q, want the checkout link for this learner", got)
+	}
+}
+
+func TestSubscribeCommandShowsPremiumFromTenantPlan(t *testing.T) {
+	engine, subs, _ := newSubscriptionEngine(t, agent.SubscriptionPlans{
+		CheckoutURL: "https://buy.stripe.com/test_abc",
+		ManageURL:   "https://billing.stripe.com/p/login/test",
+	})
+	_ = subs.UpsertSubscription(agent.Subscription{Tier: agent.TierPremium, Status: agent.SubscriptionActive})
+
+	got := sendAs(t, engine, "websocket", "42", "/subscribe")
+	if got != i18n.S("en", i18n.MsgSubscribePremium) {
+		t.Fatalf("/subscribe = %q, want premium with no checkout or manage link", got)
+	}
+}
+
+func TestDailyBudgetStopsTeachingTurnsPerTier(t *testing.T) {
+	engine, subs, mockAI := newSubscriptionEngine(t, agent.SubscriptionPlans{FreeDailyTokens: 20, PremiumDailyTokens: 1000})
+
+	if got := sendAs(t, engine, "websocket", "42", "What is a fraction?"); got != "Jawapan tutor." {
+		t.Fatalf("first reply = %q, want the tutor answer", got)
+	}
+	if mockAI.LastRequest.Tier != string(agent.TierFree) {
+		t.Errorf("request tier = %q, want free", mockAI.LastRequest.Tier)
+	}
+	mockAI.LastRequest = nil
+	if got := sendAs(t, engine, "websocket", "42", "And a decimal?"); got != i18n.S("en", i18n.MsgDailyLimitReached) {
+		t.Fatalf("reply over budget = %q, want the daily limit message", got)
+	}
+	if mockAI.LastRequest != nil {
+		t.Error("learner over budget reached the model")
+	}
+
+	end := time.Now().Add(24 * time.Hour)
+	_ = subs.UpsertSubscription(agent.Subscription{UserID: "42", Tier: agent.TierPremium, Status: agent.SubscriptionActive, PeriodEnd: &end})
+	if got := sendAs(t, engine, "websocket", "42", "And a decimal?"); got != "Jawapan tutor." {
+		t.Fatalf("premium reply = %q, want the tutor answer under the premium budget", got)
+	}
+	if mockAI.LastRequest.Tier != string(agent.TierPremium) {
+		t.Errorf("request tier = %q, want premium", mockAI.LastRequest.Tier)
+	}
+}
+
+func TestSubscriptionActive(t *testing.T) {
+	now := time.Date(2026, 10, 17, 12, 0, 0, 0, time.UTC)
+	past := now.Add(-time.Hour)
+	tests := []struct {
+		name string
+		sub  agent.Subscription
+		want bool
+	}{
+		{"active", agent.Subscription{Status: agent.SubscriptionActive}, true},
+		{"trialing", agent.Subscription{Status: agent.SubscriptionTrialing}, true},
+		{"past due", agent.Subscription{Status: agent.SubscriptionPastDue}, false},
+		{"canceled", agent.Subscription{Status: agent.SubscriptionCanceled}, false},
+		{"period ended", agent.Subscription{Status: agent.SubscriptionActive, PeriodEnd: &past}, false},
+	}
+	for _, tt := range tests {
+		if got := tt.sub.Active(now); got != tt.want {
+			t.Errorf("%s: Active() = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestTokenBudgetStopsTeachingTurnsAndRecordsUsage(t *testing.T) {
+	mockAI := ai.NewMockProvider("Jawapan tutor.")
+	budget := ai.NewInMemoryBudget()
+	budget.SetBudget("tenant-1", "42", 20)
+	engine := agent.NewEngine(agent.EngineConfig{
+		AIRouter: mockRouter(mockAI),
+		TenantID: "tenant-1",
+		Budget:   budget,
+	})
+
+	if got := sendAs(t, engine, "websocket", "42", "What is a fraction?"); got != "Jawapan tutor." {
+		t.Fatalf("first reply = %q, want the tutor answer", got)
+	}
+	if used, _, _ := budget.Usage("tenant-1", "42"); used == 0 {
+		t.Fatal("completion tokens were not recorded against the budget")
+	}
+	mockAI.LastRequest = nil
+	if got := sendAs(t, engine, "websocket", "42", "And a decimal?"); got != i18n.S("en", i18n.MsgTokenBudgetReached) {
+		t.Fatalf("reply over budget = %q, want the budget message", got)
+	}
+	if mockAI.LastRequest != nil {
+		t.Error("learner over budget reached the model")
+	}
+	if got := sendAs(t, engine, "websocket", "43", "What is a fraction?"); got != "Jawapan tutor." {
+		t.Fatalf("other learner reply = %q, want the tutor answer", got)
+	}
+}
@@ -0,0 +1,59 @@
+// Copyright 2026 the P&AI authors. All rights reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package agent
+
+import (
+	"context"
+	"log/slog"
+	"strings"
+
+	"github.com/p-n-ai/pai-bot/internal/ai"
+)
+
+// streamTextTeachingTurn is completeTextTeachingTurn over Router.StreamComplete,
+// used with the streamed_teaching feature. The router changes provider when a
+// stream fails before its first token; a provider that fails after it keeps
+// what it sent, which is marked truncated so continueTruncatedReply can finish
+// it. Streams skip the response cache.
+func (e *Engine) streamTextTeachingTurn(ctx context.Context, turn *agentTurn, messages []ai.Message, model string) (teachingCompletion, error) {
+	stream, err := e.aiRouter.StreamComplete(ctx, ai.CompletionRequest{
+		RequestMetadata: e.requestMetadata(turn.UserID, turn.ConversationID, turn.ID),
+		Messages:        messages, Model: model, Task: ai.TaskTeaching, MaxTokens: turn.MaxTokens,
+	})
+	if err != nil {
+		return teachingCompletion{}, err
+	}
+
+	var content strings.Builder
+	var completion teachingCompletion
+	var streamErr error
+	for chunk := range stream {
+		content.WriteString(chunk.Content)
+		if chunk.Error != nil {
+			streamErr = chunk.Error
+		}
+		if chunk.Done {
+			completion.Model = chunk.Model
+			completion.InputTokens = chunk.InputTokens
+			completion.OutputTokens = chunk.OutputTokens
+			completion.CostUSD = chunk.CostUSD
+			completion.Truncated = chunk.Truncated
+		}
+	}
+	if err := ctx.Err(); err != nil {
+		return teachingCompletion{}, err
+	}
+	completion.Content = content.String()
+	if streamErr != nil {
+		if completion.Content == "" {
+			return teachingCompletion{}, streamErr
+		}
+		slog.Warn("teaching stream failed after content, keeping the partial reply",
+			"turn_id", turn.ID,
+			"error", streamErr,
+		)
+		completion.Truncated = true
+	}
+	return completion, nil
+}
@@ -0,0 +1,60 @@
+// Copyright 2026 the P&AI authors. All rights reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package agent_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/p-n-ai/pai-bot/internal/agent"
+	"github.com/p-n-ai/pai-bot/internal/ai"
+	"github.com/p-n-ai/pai-bot/internal/platform/featureflags"
+)
+
+// streamOnlyProvider answers only through StreamComplete, so a reply proves
+// the turn was streamed.
+type streamOnlyProvider struct {
+	*ai.MockProvider
+}
+
+func (streamOnlyProvider) Complete(context.Context, ai.CompletionRequest) (ai.CompletionResponse, error) {
+	return ai.CompletionResponse{}, errors.New("buffered completion not expected")
+}
+
+func TestStreamedTeachingTurnRecordsCost(t *testing.T) {
+	features, err := featureflags.Parse("streamed_teaching")
+	if err != nil {
+		t.Fatal(err)
+	}
+	router := ai.NewRouter()
+	router.Register("openai", streamOnlyProvider{ai.NewMockProvider("Subtract 3 from both sides.")})
+	router.SetModelPrices(map[string]ai.ModelPrice{"mock": {InputPerMTok: 1, OutputPerMTok: 2}})
+	eventLogger := agent.NewMemoryEventLogger()
+	engine := agent.NewEngine(agent.EngineConfig{
+		AIRouter:     router,
+		EventLogger:  eventLogger,
+		Store:        agent.NewMemoryStore(),
+		FeatureFlags: func() featureflags.Features { return features },
+	})
+
+	if got := sendAs(t, engine, "websocket", "42", "Solve 2x + 3 = 7"); got != "Subtract 3 from both sides." {
+		t.Fatalf("reply = %q, want the streamed answer", got)
+	}
+	deadline := time.Now().Add(500 * time.Millisecond)
+	for time.Now().Before(deadline) {
+		for _, e := range eventLogger.Events() {
+			if e.EventType != "ai_response" {
+				continue
+			}
+			if cost, _ := e.Data["cost_usd"].(float64); cost <= 0 {
+				t.Fatalf("ai_response cost_usd = %v, want the streamed reply priced", e.Data["cost_usd"])
+			}
+			return
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+	t.Fatal("no ai_response event recorded")
+}
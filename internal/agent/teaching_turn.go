@@ -6,14 +6,32 @@ package agent
 import (
 	"context"
 	"log/slog"
+	"slices"
 	"time"
 
 	"github.com/p-n-ai/pai-bot/internal/ai"
 	"github.com/p-n-ai/pai-bot/internal/chat"
+	"github.com/p-n-ai/pai-bot/internal/curriculum"
 	"github.com/p-n-ai/pai-bot/internal/i18n"
 )
 
 func (e *Engine) runTeachingTurn(ctx context.Context, msg chat.InboundMessage, conv *Conversation, responsePrefix string, turnResult *TurnResult) (string, error) {
+	return e.runTeachingTurnFor(ctx, msg, conv, responsePrefix, turnResult, teachingTurnOptions{})
+}
+
+// teachingTurnOptions adjusts a teaching turn that answers an already stored
+// user message instead of recording a new one.
+type teachingTurnOptions struct {
+	ExistingUserMessageID string          // an edited question, or the question /again re-explains
+	Strategy              explainStrategy // set by /again; the earlier answer stays in history
+	// Continue is set by /more for an answer cut off at the hard token
+	// limit: the partial answer stays in history and an unsaved instruction
+	// asks the tutor to carry on.
+	Continue bool
+}
+
+// runTeachingTurnFor runs a teaching turn.
+func (e *Engine) runTeachingTurnFor(ctx context.Context, msg chat.InboundMessage, conv *Conversation, responsePrefix string, turnResult *TurnResult, opts teachingTurnOptions) (string, error) {
 	userContent := msg.Text
 	if msg.HasImage {
 		if userContent == "" {
@@ -23,6 +41,12 @@ func (e *Engine) runTeachingTurn(ctx context.Context, msg chat.InboundMessage, c
 	if msg.HasImage && msg.ImageDataURL == "" {
 		return i18n.S(e.messageLocale(msg, conv), i18n.MsgImageProcessingFailed), nil
 	}
+	if e.overDailyBudget(msg.UserID) {
+		return i18n.S(e.messageLocale(msg, conv), i18n.MsgDailyLimitReached), nil
+	}
+	if e.overTokenBudget(msg.UserID) {
+		return i18n.S(e.messageLocale(msg, conv), i18n.MsgTokenBudgetReached), nil
+	}
 	turn := &agentTurn{
 		ID:             generateID(),
 		UserID:         msg.UserID,
@@ -31,6 +55,7 @@ func (e *Engine) runTeachingTurn(ctx context.Context, msg chat.InboundMessage, c
 		Language:       msg.Language,
 		Route:          agentTurnRouteTeaching,
 		TaskType:       ai.TaskTeaching,
+		MaxTokens:      e.replyLimits.forChannel(msg.Channel).maxTokens(e.aiRouter.GenerationDefaults(e.tenantID, ai.TaskTeaching).MaxTokens),
 		InputText:      msg.Text,
 		UserContent:    userContent,
 		HasImage:       msg.HasImage,
@@ -38,51 +63,58 @@ func (e *Engine) runTeachingTurn(ctx context.Context, msg chat.InboundMessage, c
 		ReplyText:      msg.ReplyToText,
 		ImageDataURL:   msg.ImageDataURL,
 	}
+	turn.Safety.TurnID = turn.ID
+	turn.Safety.traceInputSafety(msg.Text)
+	if opts.Strategy != "" {
+		turn.ExplainAgain = opts.Strategy
+		turn.UserContent = opts.Strategy.request(e.messageLocale(msg, conv))
+	}
+	if opts.Continue {
+		turn.ContinueReply = true
+		turn.UserContent = moreContinuationPrompt
+	}
 
-	// Record user message.
-	userMessageID, err := e.store.AddMessage(conv.ID, StoredMessage{
-		Role:    "user",
-		Content: userContent,
-	})
-	if err != nil {
-		slog.Error("failed to store user message", "error", err)
+	var pending *pendingUserMessage
+	if opts.ExistingUserMessageID != "" {
+		turn.UserMessageID = opts.ExistingUserMessageID
+	} else {
+		// Record the user message with the reply when the store can batch
+		// them; turns that end early write it on its own.
+		stored := inboundStoredMessage(msg, userContent)
+		pending, turn.UserMessageID = e.deferUserMessage(conv.ID, stored, Event{
+			ConversationID: conv.ID,
+			UserID:         msg.UserID,
+			EventType:      "message_sent",
+			Data: map[string]any{
+				"channel":   msg.Channel,
+				"text_len":  len(msg.Text),
+				"has_reply": msg.ReplyToText != "",
+				"has_image": msg.HasImage,
+				"source":    "chat",
+			},
+		})
+		defer pending.flush(e)
 	}
-	turn.UserMessageID = userMessageID
-	e.logEventAsync(Event{
-		ConversationID: conv.ID,
-		UserID:         msg.UserID,
-		EventType:      "message_sent",
-		Data: map[string]any{
-			"channel":   msg.Channel,
-			"text_len":  len(msg.Text),
-			"has_reply": msg.ReplyToText != "",
-			"has_image": msg.HasImage,
-			"source":    "chat",
-		},
-	})
 
 	// Refresh conversation to get latest messages.
 	conv, _ = e.store.GetConversation(conv.ID)
+	if pending != nil {
+		// Show the unwritten message to compaction and the prompt builder
+		// under a local ID, so history skips it like a stored current message.
+		// Work on a copy: a memory-backed store returns its own record.
+		preview := pending.msg
+		preview.ID = "pending-" + generateID()
+		turn.UserMessageID = preview.ID
+		withPending := *conv
+		withPending.Messages = append(slices.Clip(conv.Messages), preview)
+		conv = &withPending
+	}
 
 	// Compact if needed (summarize older messages).
 	e.maybeCompact(ctx, conv)
 
-	matchedTopic, teachingNotes := e.resolveCurriculumContext(msg.UserID, conv.TopicID, msg.Text)
-
-	// Guard: if the message is a vague continuation ("ok", "whats next", etc.)
-	// and the conversation already has a stored topic, always prefer the stored
-	// topic — even if the retriever matched a different topic (e.g. "next"
-	// matching "Patterns and Sequences" via assessment items).
-	vague := isVagueContinuation(msg.Text)
-	if vague && conv.TopicID != "" && e.curriculumLoader != nil {
-		if stored, ok := e.curriculumLoader.GetTopic(conv.TopicID); ok {
-			topicCopy := stored
-			matchedTopic = &topicCopy
-			if notes, ok := e.curriculumLoader.GetTeachingNotes(conv.TopicID); ok {
-				teachingNotes = notes
-			}
-		}
-	} else if matchedTopic != nil && matchedTopic.ID != "" && matchedTopic.ID != conv.TopicID {
+	matchedTopic, teachingNotes, switched := e.resolveTurnTopic(msg, conv)
+	if switched {
 		// Non-vague message matched a different topic — update the conversation.
 		if err := e.store.UpdateConversationTopicID(conv.ID, matchedTopic.ID); err != nil {
 			slog.Warn("failed to persist matched topic", "conversation_id", conv.ID, "topic_id", matchedTopic.ID, "error", err)
@@ -94,21 +126,24 @@ func (e *Engine) runTeachingTurn(ctx context.Context, msg chat.InboundMessage, c
 	turn.Topic = matchedTopic
 	turn.TeachingNotes = teachingNotes
 	turn.Packets = e.loadContextPackets(ctx, turn, msg, conv, matchedTopic, teachingNotes)
+	if opts.Strategy == "" {
+		turn.Packets = e.appendStrategySwitchPacket(turn.Packets, turn, switched)
+	}
 	if e.turnHooksEnabled() {
 		hookResult, err := e.runTurnHooks(ctx, turn)
 		if err != nil {
 			turn.Model.Error = err.Error()
 			e.logAgentTurnCompleted(turn, "failed")
-			slog.Error("turn hook failed", "error", err)
-			return i18n.S(e.messageLocale(msg, conv), i18n.MsgTechnicalIssue), nil
+			return e.errorReply(e.messageLocale(msg, conv), msg.UserID, conv.ID, "turn_hook", err), nil
 		}
 		turn.Packets = hookResult.Packets
 		if hookResult.Blocked {
+			turn.Safety.add(SafetyDecision{Check: SafetyCheckTurnHook, Action: SafetyActionBlocked})
 			e.logAgentTurnCompleted(turn, "blocked")
 			if hookResult.BlockMessage != "" {
 				return hookResult.BlockMessage, nil
 			}
-			return i18n.S(e.messageLocale(msg, conv), i18n.MsgTechnicalIssue), nil
+			return e.errorReply(e.messageLocale(msg, conv), msg.UserID, conv.ID, "turn_hook", errTurnBlocked), nil
 		}
 	}
 	messages := e.buildPromptMessagesFromTurn(turn)
@@ -126,50 +161,77 @@ func (e *Engine) runTeachingTurn(ctx context.Context, msg chat.InboundMessage, c
 	if err != nil {
 		turn.Model.Error = err.Error()
 		e.logAgentTurnCompleted(turn, "failed")
-		slog.Error("AI completion failed", "error", err)
-		return i18n.S(e.messageLocale(msg, conv), i18n.MsgTechnicalIssue), nil
+		return e.errorReply(e.messageLocale(msg, conv), msg.UserID, conv.ID, "ai_completion", err), nil
 	}
-	turn.Model.Model = resp.Model
-	turn.Model.InputTokens = resp.InputTokens
-	turn.Model.OutputTokens = resp.OutputTokens
 	if turnResult != nil {
 		turnResult.FocusedPage = artifact
+		turnResult.Graph = resp.Graph
 	}
 
-	// Telegram does not render LaTeX blocks; keep equations plain.
-	plainContent := postProcessTutorResponse(normalizeLegacyExamReferences(normalizeEquationFormatting(resp.Content)), msg.Text)
-	finalContent := plainContent
+	resp = e.continueTruncatedReply(ctx, turn, messages, reqModel, resp)
+	plainContent := e.finishTeachingReply(resp.Content, msg, conv)
+	resp, plainContent = e.screenTeachingReply(ctx, turn, messages, reqModel, msg, conv, resp, plainContent)
+	turn.Model.Model = resp.Model
+	turn.Model.InputTokens = resp.InputTokens
+	turn.Model.OutputTokens = resp.OutputTokens
+	turn.Model.CostUSD = resp.CostUSD
+	finalContent, hasMore := e.limitReply(msg.UserID, msg.Channel, plainContent, resp.Truncated)
+	move := classifyTutorMove(turn.UserContent, plainContent)
 
 	// Record assistant response with token metadata.
-	assistantMessageID, err := e.store.AddMessage(conv.ID, StoredMessage{
+	assistantMessageID, err := e.addReply(conv.ID, pending, StoredMessage{
 		Role:         "assistant",
 		Content:      finalContent,
 		Model:        resp.Model,
 		InputTokens:  resp.InputTokens,
 		OutputTokens: resp.OutputTokens,
-	})
-	if err != nil {
-		slog.Error("failed to store assistant message", "error", err)
-	}
-	turn.AssistantMessageID = assistantMessageID
-	e.logEventAsync(Event{
+		Safety:       storedSafetyTrace(&turn.Safety),
+	}, Event{
 		ConversationID: conv.ID,
 		UserID:         msg.UserID,
 		EventType:      "ai_response",
 		Data: map[string]any{
-			"channel":       msg.Channel,
-			"model":         resp.Model,
-			"input_tokens":  resp.InputTokens,
-			"output_tokens": resp.OutputTokens,
-			"text_len":      len(finalContent),
-			"has_image":     msg.HasImage,
+			"channel":        msg.Channel,
+			"model":          resp.Model,
+			"input_tokens":   resp.InputTokens,
+			"output_tokens":  resp.OutputTokens,
+			"cost_usd":       resp.CostUSD,
+			"text_len":       len(finalContent),
+			"has_image":      msg.HasImage,
+			"has_more":       hasMore,
+			"tutor_move":     move,
+			"prompt_version": PromptVersion,
+			"engine_build":   EngineBuild(),
 		},
 	})
+	if err != nil {
+		slog.Error("failed to store assistant message", "error", err)
+	}
+	if pending != nil {
+		turn.UserMessageID = pending.id
+	}
+	turn.AssistantMessageID = assistantMessageID
+	if turnResult != nil && assistantMessageID != "" {
+		turnResult.ConversationID = conv.ID
+		turnResult.AssistantMessageID = assistantMessageID
+	}
 	e.logAgentTurnCompleted(turn, "completed")
-	e.assessMasteryAsync(msg.UserID, matchedTopic, userContent, plainContent)
+	e.recordTokenUsage(msg.UserID, resp.InputTokens+resp.OutputTokens)
+	if opts.Strategy == "" && !opts.Continue {
+		// A re-explanation or continuation answers a question that was
+		// already assessed.
+		e.assessMasteryAsync(msg.UserID, matchedTopic, userContent, plainContent)
+	}
 	e.recordActivityAsync(msg.UserID)
+	e.scheduleCheckIn(ctx, msg, conv, matchedTopic)
 
 	responseContent := finalContent
+	if hasMore {
+		responseContent += "\n\n" + i18n.S(e.messageLocale(msg, conv), i18n.MsgReplyMoreHint)
+	}
+	if footer := e.metaFooterFor(e.messageLocale(msg, conv), move, matchedTopic); footer != "" {
+		responseContent += "\n\n" + footer
+	}
 
 	if responsePrefix != "" {
 		responseContent = responsePrefix + "\n\n" + responseContent
@@ -177,3 +239,51 @@ func (e *Engine) runTeachingTurn(ctx context.Context, msg chat.InboundMessage, c
 
 	return responseContent, nil
 }
+
+// finishTeachingReply turns raw model output into the text sent to the
+// learner.
+func (e *Engine) finishTeachingReply(raw string, msg chat.InboundMessage, conv *Conversation) string {
+	// Telegram does not render LaTeX blocks; keep equations plain, then apply
+	// the tenant's local notation conventions to the plain text.
+	content := localizeNotation(normalizeEquationFormatting(raw), e.messageLocale(msg, conv), *e.notation.Load())
+	return postProcessTutorResponse(normalizeLegacyExamReferences(content), msg.Text)
+}
+
+// resolveTurnTopic picks the curriculum topic for a message. switched reports
+// that a non-vague message matched a topic other than the conversation's.
+func (e *Engine) resolveTurnTopic(msg chat.InboundMessage, conv *Conversation) (*curriculum.Topic, string, bool) {
+	matchedTopic, teachingNotes := e.resolveCurriculumContext(msg.UserID, conv.TopicID, msg.Text)
+
+	// Guard: if the message is a vague continuation ("ok", "whats next", etc.)
+	// and the conversation already has a stored topic, always prefer the stored
+	// topic — even if the retriever matched a different topic (e.g. "next"
+	// matching "Patterns and Sequences" via assessment items).
+	if isVagueContinuation(msg.Text) && conv.TopicID != "" && e.curriculumLoader != nil {
+		if stored, ok := e.curriculumLoader.GetTopic(conv.TopicID); ok {
+			topicCopy := stored
+			matchedTopic = &topicCopy
+			if notes, ok := e.curriculumLoader.GetTeachingNotes(conv.TopicID); ok {
+				teachingNotes = notes
+			}
+		}
+		return matchedTopic, teachingNotes, false
+	}
+	switched := matchedTopic != nil && matchedTopic.ID != "" && matchedTopic.ID != conv.TopicID
+	return matchedTopic, teachingNotes, switched
+}
+
+// inboundStoredMessage records the learner's message with its channel message
+// ID and a reference to any image they sent, so later edits can find it and
+// history shows the image was part of the turn.
+func inboundStoredMessage(msg chat.InboundMessage, content string) StoredMessage {
+	stored := StoredMessage{Role: "user", Content: content, ExternalID: msg.MessageID}
+	if msg.HasImage && msg.ImageFileID != "" {
+		stored.Kind = MessageKindImage
+		stored.Attachments = []MessageAttachment{{
+			Type:      string(MessageKindImage),
+			Reference: msg.ImageFileID,
+			Caption:   msg.Caption,
+		}}
+	}
+	return stored
+}
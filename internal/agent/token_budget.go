@@ -0,0 +1,373 @@
+// Copyright 2026 the P&AI authors. All rights reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package agent
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"sync"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// DefaultTokenBudgetSyncInterval is how often CachedTokenBudget flushes
+// counters when the caller does not set an interval.
+const DefaultTokenBudgetSyncInterval = 30 * time.Second
+
+// TokenBudgetWindow is one token_budgets row: a token allowance for a tenant
+// (UserID empty) or one learner over [Start, End).
+type TokenBudgetWindow struct {
+	ID       string
+	TenantID string
+	UserID   string // learner's external ID; empty for the tenant-wide window
+	Budget   int64
+	Used     int64
+	Start    time.Time
+	End      time.Time
+}
+
+func (w TokenBudgetWindow) open(now time.Time) bool {
+	return !now.Before(w.Start) && now.Before(w.End)
+}
+
+func (w TokenBudgetWindow) applies(tenantID, userID string) bool {
+	return w.TenantID == tenantID && (w.UserID == "" || w.UserID == userID)
+}
+
+// TokenBudgetUsage is tokens spent against one window on one day
+// (YYYY-MM-DD, Malaysia time).
+type TokenBudgetUsage struct {
+	WindowID string
+	Day      string
+	Tokens   int64
+}
+
+// TokenBudgetStore is the durable side of token budgets: the windows
+// operators set, and the usage flushed against them.
+type TokenBudgetStore interface {
+	// LoadTokenBudgets returns every window that has not ended yet.
+	LoadTokenBudgets(ctx context.Context) ([]TokenBudgetWindow, error)
+	// FlushTokenUsage adds usage to the windows' totals and daily ledger.
+	// Usage for windows that no longer exist is dropped.
+	FlushTokenUsage(ctx context.Context, usage []TokenBudgetUsage) error
+}
+
+// MemoryTokenBudgetStore is a TokenBudgetStore for tests and development.
+type MemoryTokenBudgetStore struct {
+	mu      sync.Mutex
+	windows []TokenBudgetWindow
+	daily   map[string]map[string]int64 // window ID -> day -> tokens
+}
+
+func NewMemoryTokenBudgetStore(windows ...TokenBudgetWindow) *MemoryTokenBudgetStore {
+	return &MemoryTokenBudgetStore{
+		windows: append([]TokenBudgetWindow(nil), windows...),
+		daily:   make(map[string]map[string]int64),
+	}
+}
+
+// SetTokenBudget adds window, or replaces the window with the same ID.
+func (s *MemoryTokenBudgetStore) SetTokenBudget(window TokenBudgetWindow) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for i := range s.windows {
+		if s.windows[i].ID == window.ID {
+			s.windows[i] = window
+			return
+		}
+	}
+	s.windows = append(s.windows, window)
+}
+
+func (s *MemoryTokenBudgetStore) LoadTokenBudgets(context.Context) ([]TokenBudgetWindow, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	now := time.Now()
+	windows := make([]TokenBudgetWindow, 0, len(s.windows))
+	for _, w := range s.windows {
+		if now.Before(w.End) {
+			windows = append(windows, w)
+		}
+	}
+	return windows, nil
+}
+
+func (s *MemoryTokenBudgetStore) FlushTokenUsage(_ context.Context, usage []TokenBudgetUsage) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, u := range usage {
+		for i := range s.windows {
+			if s.windows[i].ID != u.WindowID {
+				continue
+			}
+			s.windows[i].Used += u.Tokens
+			if s.daily[u.WindowID] == nil {
+				s.daily[u.WindowID] = make(map[string]int64)
+			}
+			s.daily[u.WindowID][u.Day] += u.Tokens
+		}
+	}
+	return nil
+}
+
+// DailyUsage returns the tokens flushed against a window on day.
+func (s *MemoryTokenBudgetStore) DailyUsage(windowID, day string) int64 {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.daily[windowID][day]
+}
+
+// PostgresTokenBudgetStore keeps windows in token_budgets and their daily
+// usage in token_budget_usage. It serves every tenant.
+type PostgresTokenBudgetStore struct {
+	pool *pgxpool.Pool
+}
+
+func NewPostgresTokenBudgetStore(pool *pgxpool.Pool) *PostgresTokenBudgetStore {
+	return &PostgresTokenBudgetStore{pool: pool}
+}
+
+func (s *PostgresTokenBudgetStore) LoadTokenBudgets(ctx context.Context) ([]TokenBudgetWindow, error) {
+	ctx, cancel := context.WithTimeout(ctx, dbTimeout)
+	defer cancel()
+
+	rows, err := s.pool.Query(ctx,
+		`SELECT tb.id::text, tb.tenant_id::text, COALESCE(u.external_id, ''),
+		        tb.budget_tokens, tb.used_tokens, tb.period_start, tb.period_end
+		 FROM token_budgets tb
+		 LEFT JOIN users u ON u.id = tb.user_id
+		 WHERE tb.period_end > NOW()
+		   AND (tb.user_id IS NULL OR u.id IS NOT NULL)`,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("load token budgets: %w", err)
+	}
+	defer rows.Close()
+
+	var windows []TokenBudgetWindow
+	for rows.Next() {
+		var w TokenBudgetWindow
+		if err := rows.Scan(&w.ID, &w.TenantID, &w.UserID, &w.Budget, &w.Used, &w.Start, &w.End); err != nil {
+			return nil, fmt.Errorf("scan token budget: %w", err)
+		}
+		windows = append(windows, w)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("load token budgets: %w", err)
+	}
+	return windows, nil
+}
+
+func (s *PostgresTokenBudgetStore) FlushTokenUsage(ctx context.Context, usage []TokenBudgetUsage) error {
+	if len(usage) == 0 {
+		return nil
+	}
+	ctx, cancel := context.WithTimeout(ctx, dbTimeout)
+	defer cancel()
+
+	tx, err := s.pool.BeginTx(ctx, pgx.TxOptions{})
+	if err != nil {
+		return fmt.Errorf("begin token usage flush tx: %w", err)
+	}
+	defer func() { _ = tx.Rollback(ctx) }()
+
+	batch := &pgx.Batch{}
+	for _, u := range usage {
+		batch.Queue(
+			`UPDATE token_budgets
+			 SET used_tokens = used_tokens + $2,
+			     updated_at = NOW()
+			 WHERE id = $1::uuid`,
+			u.WindowID, u.Tokens,
+		)
+		batch.Queue(
+			`INSERT INTO token_budget_usage (budget_id, usage_date, tokens)
+			 SELECT id, $2::date, $3 FROM token_budgets WHERE id = $1::uuid
+			 ON CONFLICT (budget_id, usage_date) DO UPDATE
+			 SET tokens = token_budget_usage.tokens + EXCLUDED.tokens,
+			     updated_at = NOW()`,
+			u.WindowID, u.Day, u.Tokens,
+		)
+	}
+	if err := tx.SendBatch(ctx, batch).Close(); err != nil {
+		return fmt.Errorf("flush token usage: %w", err)
+	}
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("commit token usage flush: %w", err)
+	}
+	return nil
+}
+
+// CachedTokenBudget is an ai.BudgetChecker that answers from windows held in
+// memory, so turns never wait on the database. Load hydrates it at startup;
+// Run flushes recorded tokens to the store and reloads the windows, picking
+// up budgets operators changed. A learner with no open window is unlimited.
+type CachedTokenBudget struct {
+	store TokenBudgetStore
+	now   func() time.Time
+
+	mu      sync.Mutex
+	windows []TokenBudgetWindow
+	pending map[tokenUsageKey]int64
+}
+
+type tokenUsageKey struct {
+	windowID string
+	day      string
+}
+
+func NewCachedTokenBudget(store TokenBudgetStore) *CachedTokenBudget {
+	return &CachedTokenBudget{
+		store:   store,
+		now:     time.Now,
+		pending: make(map[tokenUsageKey]int64),
+	}
+}
+
+// Load replaces the cached windows with the store's.
+func (b *CachedTokenBudget) Load(ctx context.Context) error {
+	windows, err := b.store.LoadTokenBudgets(ctx)
+	if err != nil {
+		return err
+	}
+	b.mu.Lock()
+	b.windows = windows
+	b.mu.Unlock()
+	return nil
+}
+
+// Check reports false once any open window for the learner is used up,
+// counting tokens not yet flushed.
+func (b *CachedTokenBudget) Check(tenantID, userID string) (bool, error) {
+	now := b.now()
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	for _, w := range b.windows {
+		if w.applies(tenantID, userID) && w.open(now) && b.usedLocked(w) >= w.Budget {
+			return false, nil
+		}
+	}
+	return true, nil
+}
+
+// Record adds tokens to every open window for the learner. They reach the
+// store on the next sync.
+func (b *CachedTokenBudget) Record(tenantID, userID string, tokens int) error {
+	if tokens < 0 {
+		return fmt.Errorf("tokens must be non-negative, got %d", tokens)
+	}
+	now := b.now()
+	day := usageDay(now)
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	for _, w := range b.windows {
+		if w.applies(tenantID, userID) && w.open(now) {
+			b.pending[tokenUsageKey{windowID: w.ID, day: day}] += int64(tokens)
+		}
+	}
+	return nil
+}
+
+// Usage returns the learner's own open window, or the tenant's when the
+// learner has none. Both are zero without an open window.
+func (b *CachedTokenBudget) Usage(tenantID, userID string) (int64, int64, error) {
+	now := b.now()
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	var best *TokenBudgetWindow
+	for i := range b.windows {
+		w := &b.windows[i]
+		if !w.applies(tenantID, userID) || !w.open(now) {
+			continue
+		}
+		ownFirst := w.UserID != "" && best != nil && best.UserID == ""
+		sameScope := best != nil && (w.UserID == "") == (best.UserID == "")
+		if best == nil || ownFirst || (sameScope && w.Start.After(best.Start)) {
+			best = w
+		}
+	}
+	if best == nil {
+		return 0, 0, nil
+	}
+	return b.usedLocked(*best), best.Budget, nil
+}
+
+func (b *CachedTokenBudget) usedLocked(w TokenBudgetWindow) int64 {
+	used := w.Used
+	for key, tokens := range b.pending {
+		if key.windowID == w.ID {
+			used += tokens
+		}
+	}
+	return used
+}
+
+// Sync flushes recorded tokens to the store, then reloads the windows. Tokens
+// that fail to flush are kept for the next sync.
+func (b *CachedTokenBudget) Sync(ctx context.Context) error {
+	b.mu.Lock()
+	flushed := b.pending
+	b.pending = make(map[tokenUsageKey]int64)
+	b.mu.Unlock()
+
+	usage := make([]TokenBudgetUsage, 0, len(flushed))
+	for key, tokens := range flushed {
+		usage = append(usage, TokenBudgetUsage{WindowID: key.windowID, Day: key.day, Tokens: tokens})
+	}
+	if err := b.store.FlushTokenUsage(ctx, usage); err != nil {
+		b.mu.Lock()
+		for key, tokens := range flushed {
+			b.pending[key] += tokens
+		}
+		b.mu.Unlock()
+		return err
+	}
+	return b.Load(ctx)
+}
+
+// Run syncs every interval until ctx is done, then flushes once more so a
+// clean shutdown loses no usage.
+func (b *CachedTokenBudget) Run(ctx context.Context, interval time.Duration) {
+	if interval <= 0 {
+		interval = DefaultTokenBudgetSyncInterval
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	b.run(ctx, ticker.C)
+}
+
+func (b *CachedTokenBudget) run(ctx context.Context, ticks <-chan time.Time) {
+	for {
+		select {
+		case <-ctx.Done():
+			flushCtx, cancel := context.WithTimeout(context.Background(), dbTimeout)
+			if err := b.Sync(flushCtx); err != nil {
+				slog.Warn("final token budget sync failed", "error", err)
+			}
+			cancel()
+			return
+		case <-ticks:
+			if err := b.Sync(ctx); err != nil {
+				slog.Warn("token budget sync failed", "error", err)
+			}
+		}
+	}
+}
+
+// overTokenBudget reports whether the tenant or learner budget is used up.
+// Budget lookup errors let the turn through.
+func (e *Engine) overTokenBudget(userID string) bool {
+	if e.budget == nil || IsSandboxUser(userID) {
+		return false
+	}
+	ok, err := e.budget.Check(e.tenantID, userID)
+	if err != nil {
+		slog.Warn("failed to check token budget", "tenant_id", e.tenantID, "user_id", userID, "error", err)
+		return false
+	}
+	return !ok
+}
@@ -0,0 +1,158 @@
+// Copyright 2026 the P&AI authors. All rights reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package agent_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/p-n-ai/pai-bot/internal/agent"
+)
+
+type flakyTokenBudgetStore struct {
+	*agent.MemoryTokenBudgetStore
+	failFlush bool
+}
+
+func (s *flakyTokenBudgetStore) FlushTokenUsage(ctx context.Context, usage []agent.TokenBudgetUsage) error {
+	if s.failFlush {
+		return errors.New("database unavailable")
+	}
+	return s.MemoryTokenBudgetStore.FlushTokenUsage(ctx, usage)
+}
+
+func openTokenBudgetWindow(id, userID string, budget, used int64) agent.TokenBudgetWindow {
+	now := time.Now()
+	return agent.TokenBudgetWindow{
+		ID:       id,
+		TenantID: "tenant-1",
+		UserID:   userID,
+		Budget:   budget,
+		Used:     used,
+		Start:    now.Add(-time.Hour),
+		End:      now.Add(24 * time.Hour),
+	}
+}
+
+func TestCachedTokenBudgetHydratesAndChecksBeforeSync(t *testing.T) {
+	store := agent.NewMemoryTokenBudgetStore(
+		openTokenBudgetWindow("tenant-window", "", 1000, 100),
+		openTokenBudgetWindow("learner-window", "42", 50, 40),
+	)
+	budget := agent.NewCachedTokenBudget(store)
+	if err := budget.Load(context.Background()); err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+
+	if used, limit, _ := budget.Usage("tenant-1", "42"); used != 40 || limit != 50 {
+		t.Fatalf("learner usage = %d/%d, want 40/50 from the learner window", used, limit)
+	}
+	if used, limit, _ := budget.Usage("tenant-1", "43"); used != 100 || limit != 1000 {
+		t.Fatalf("other learner usage = %d/%d, want the tenant window", used, limit)
+	}
+	if err := budget.Record("tenant-1", "42", 10); err != nil {
+		t.Fatalf("Record() error = %v", err)
+	}
+	if ok, _ := budget.Check("tenant-1", "42"); ok {
+		t.Fatal("learner with unflushed usage at the limit passed the check")
+	}
+	if ok, _ := budget.Check("tenant-1", "43"); !ok {
+		t.Fatal("other learner was blocked by someone else's window")
+	}
+	if ok, _ := budget.Check("tenant-2", "42"); !ok {
+		t.Fatal("learner in a tenant without windows was blocked")
+	}
+}
+
+func TestCachedTokenBudgetSyncFlushesUsageAndReloadsWindows(t *testing.T) {
+	store := agent.NewMemoryTokenBudgetStore(openTokenBudgetWindow("tenant-window", "", 100, 0))
+	budget := agent.NewCachedTokenBudget(store)
+	if err := budget.Load(context.Background()); err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	if err := budget.Record("tenant-1", "42", 30); err != nil {
+		t.Fatalf("Record() error = %v", err)
+	}
+	if err := budget.Sync(context.Background()); err != nil {
+		t.Fatalf("Sync() error = %v", err)
+	}
+
+	windows, _ := store.LoadTokenBudgets(context.Background())
+	if len(windows) != 1 || windows[0].Used != 30 {
+		t.Fatalf("stored windows = %+v, want 30 tokens flushed", windows)
+	}
+	today := time.Now().In(time.FixedZone("MYT", 8*60*60)).Format("2006-01-02")
+	if got := store.DailyUsage("tenant-window", today); got != 30 {
+		t.Fatalf("daily usage = %d, want 30", got)
+	}
+	if used, _, _ := budget.Usage("tenant-1", "42"); used != 30 {
+		t.Fatalf("usage after sync = %d, want 30 counted once", used)
+	}
+
+	// An operator raises the budget; the next sync picks it up.
+	raised := openTokenBudgetWindow("tenant-window", "", 500, 30)
+	store.SetTokenBudget(raised)
+	if err := budget.Sync(context.Background()); err != nil {
+		t.Fatalf("Sync() error = %v", err)
+	}
+	if _, limit, _ := budget.Usage("tenant-1", "42"); limit != 500 {
+		t.Fatalf("budget after reload = %d, want 500", limit)
+	}
+}
+
+func TestCachedTokenBudgetKeepsUsageWhenFlushFails(t *testing.T) {
+	store := &flakyTokenBudgetStore{
+		MemoryTokenBudgetStore: agent.NewMemoryTokenBudgetStore(openTokenBudgetWindow("tenant-window", "", 100, 0)),
+		failFlush:              true,
+	}
+	budget := agent.NewCachedTokenBudget(store)
+	if err := budget.Load(context.Background()); err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	if err := budget.Record("tenant-1", "42", 25); err != nil {
+		t.Fatalf("Record() error = %v", err)
+	}
+	if err := budget.Sync(context.Background()); err == nil {
+		t.Fatal("Sync() error = nil, want the flush error")
+	}
+	if used, _, _ := budget.Usage("tenant-1", "42"); used != 25 {
+		t.Fatalf("usage after failed sync = %d, want 25 still counted", used)
+	}
+
+	store.failFlush = false
+	if err := budget.Sync(context.Background()); err != nil {
+		t.Fatalf("Sync() error = %v", err)
+	}
+	windows, _ := store.LoadTokenBudgets(context.Background())
+	if len(windows) != 1 || windows[0].Used != 25 {
+		t.Fatalf("stored windows = %+v, want the retried 25 tokens", windows)
+	}
+}
+
+func TestCachedTokenBudgetRunFlushesOnShutdown(t *testing.T) {
+	store := agent.NewMemoryTokenBudgetStore(openTokenBudgetWindow("tenant-window", "", 100, 0))
+	budget := agent.NewCachedTokenBudget(store)
+	if err := budget.Load(context.Background()); err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	if err := budget.Record("tenant-1", "42", 7); err != nil {
+		t.Fatalf("Record() error = %v", err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		budget.Run(ctx, time.Hour)
+	}()
+	cancel()
+	<-done
+
+	windows, _ := store.LoadTokenBudgets(context.Background())
+	if len(windows) != 1 || windows[0].Used != 7 {
+		t.Fatalf("stored windows = %+v, want usage flushed on shutdown", windows)
+	}
+}
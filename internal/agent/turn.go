@@ -7,6 +7,7 @@ import (
 	"github.com/p-n-ai/pai-bot/internal/ai"
 	"github.com/p-n-ai/pai-bot/internal/curriculum"
 	"github.com/p-n-ai/pai-bot/internal/focusedpage"
+	"github.com/p-n-ai/pai-bot/internal/plot"
 )
 
 const (
@@ -17,6 +18,12 @@ const (
 type TurnResult struct {
 	Text        string
 	FocusedPage *focusedpage.Artifact
+	// Graph is a plotted graph to send as an image ahead of Text.
+	Graph *plot.Graph
+	// ConversationID and AssistantMessageID identify the stored answer, when
+	// the turn produced one, so delivery can link it to the channel message.
+	ConversationID     string
+	AssistantMessageID string
 }
 
 // agentTurn is the runtime boundary for one inbound message that reaches the
@@ -29,6 +36,7 @@ type agentTurn struct {
 	Language       string
 	Route          string
 	TaskType       ai.TaskType
+	MaxTokens      int
 
 	InputText          string
 	UserContent        string
@@ -38,12 +46,15 @@ type agentTurn struct {
 	HasReply           bool
 	ReplyText          string
 	ImageDataURL       string
+	ExplainAgain       explainStrategy // /again: re-explain the last answer instead of answering UserContent
+	ContinueReply      bool            // /more: continue the last answer, which history ends with
 	Conversation       *Conversation
 	Topic              *curriculum.Topic
 	TeachingNotes      string
 	Packets            []contextPacket
 	Prompt             promptManifest
 	Model              modelResult
+	Safety             SafetyTrace
 }
 
 // learnerProfile is the small educational profile that can be shown to the
@@ -70,6 +81,7 @@ const (
 	contextKindCurrentInput        contextKind = "current_input"
 	contextKindImage               contextKind = "image"
 	contextKindControlInstruction  contextKind = "control_instruction"
+	contextKindLearnerMemory       contextKind = "learner_memory"
 )
 
 type contextTrust string
@@ -121,6 +133,7 @@ type promptManifest struct {
 	HasSummary      bool
 	HasImage        bool
 	ContextSources  []contextSource
+	Budget          promptBudget
 }
 
 // modelResult records the model call result for tracing.
@@ -128,6 +141,7 @@ type modelResult struct {
 	Model        string
 	InputTokens  int
 	OutputTokens int
+	CostUSD      float64
 	LatencyMS    int
 	Error        string
 }
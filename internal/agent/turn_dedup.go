@@ -0,0 +1,119 @@
+// Copyright 2026 the P&AI authors. All rights reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package agent
+
+import (
+	"crypto/sha256"
+	"log/slog"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/p-n-ai/pai-bot/internal/chat"
+	"github.com/p-n-ai/pai-bot/internal/i18n"
+)
+
+// duplicateTurnWindow is how soon after a message the same text must arrive
+// again to count as a double-tapped send.
+const duplicateTurnWindow = 10 * time.Second
+
+// recentTurns remembers each learner's last inbound message so a repeat sent
+// before the first was answered is not run as a second turn. The zero value
+// is ready to use.
+type recentTurns struct {
+	mu        sync.Mutex
+	seq       uint64
+	last      map[string]recentTurn
+	lastSweep time.Time
+}
+
+type recentTurn struct {
+	hash      [sha256.Size]byte
+	seq       uint64
+	arrivedAt time.Time
+	// doneAt is when the turn was answered; zero while it is in flight.
+	doneAt time.Time
+}
+
+// arrive records msg for key as it comes in, before it waits for the turn
+// lock. It returns the turn's sequence number for done, or dup when msg
+// repeats the previous message within the window while that one was still
+// unanswered, so the learner could not have seen a reply yet.
+func (t *recentTurns) arrive(key string, msg chat.InboundMessage, now time.Time) (seq uint64, dup bool) {
+	hash, ok := turnHash(msg)
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if now.Sub(t.lastSweep) > duplicateTurnWindow {
+		for k, turn := range t.last {
+			if now.Sub(turn.arrivedAt) > duplicateTurnWindow {
+				delete(t.last, k)
+			}
+		}
+		t.lastSweep = now
+	}
+	if !ok {
+		// Commands, callbacks and the like end the run of repeats.
+		delete(t.last, key)
+		return 0, false
+	}
+	if prev, seen := t.last[key]; seen && prev.hash == hash &&
+		now.Sub(prev.arrivedAt) <= duplicateTurnWindow &&
+		(prev.doneAt.IsZero() || now.Before(prev.doneAt)) {
+		return 0, true
+	}
+	if t.last == nil {
+		t.last = make(map[string]recentTurn)
+	}
+	t.seq++
+	t.last[key] = recentTurn{hash: hash, seq: t.seq, arrivedAt: now}
+	return t.seq, false
+}
+
+// done marks turn seq for key as answered. Later messages replace the entry,
+// so a stale seq is ignored.
+func (t *recentTurns) done(key string, seq uint64, now time.Time) {
+	if seq == 0 {
+		return
+	}
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if turn, ok := t.last[key]; ok && turn.seq == seq {
+		turn.doneAt = now
+		t.last[key] = turn
+	}
+}
+
+// turnHash identifies a message's content, ignoring case and spacing. Only
+// ordinary text and photo turns are hashed; ok is false for the rest.
+func turnHash(msg chat.InboundMessage) (hash [sha256.Size]byte, ok bool) {
+	if !msg.ExpectsReply() || msg.CallbackQueryID != "" || strings.HasPrefix(msg.Text, "/") {
+		return hash, false
+	}
+	text := strings.ToLower(strings.Join(strings.Fields(msg.Text), " "))
+	caption := strings.ToLower(strings.Join(strings.Fields(msg.Caption), " "))
+	if text == "" && caption == "" && !msg.HasImage {
+		return hash, false
+	}
+	image := msg.ImageFileID
+	if image == "" {
+		image = msg.ImageDataURL
+	}
+	return sha256.Sum256([]byte(text + "\x00" + caption + "\x00" + image)), true
+}
+
+// duplicateTurn answers a double-tapped message with a short note instead of
+// a second AI turn.
+func (e *Engine) duplicateTurn(msg chat.InboundMessage) TurnResult {
+	slog.Info("skipping duplicate message", "channel", msg.Channel, "user_id", msg.UserID)
+	e.logEventAsync(Event{
+		UserID:    msg.UserID,
+		EventType: "duplicate_turn_skipped",
+		Data: map[string]any{
+			"channel":   msg.Channel,
+			"has_image": msg.HasImage,
+		},
+	})
+	return TurnResult{Text: i18n.S(e.messageLocale(msg, nil), i18n.MsgDuplicateMessage)}
+}
@@ -0,0 +1,97 @@
+// Copyright 2026 the P&AI authors. All rights reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package agent_test
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/p-n-ai/pai-bot/internal/agent"
+	"github.com/p-n-ai/pai-bot/internal/ai"
+	"github.com/p-n-ai/pai-bot/internal/chat"
+)
+
+// gatedTeachingProvider holds teaching completions until release is closed.
+type gatedTeachingProvider struct {
+	mu       sync.Mutex
+	teaching int
+	started  chan struct{}
+	release  chan struct{}
+}
+
+func (p *gatedTeachingProvider) Complete(_ context.Context, req ai.CompletionRequest) (ai.CompletionResponse, error) {
+	if req.Task == ai.TaskTeaching {
+		p.mu.Lock()
+		p.teaching++
+		first := p.teaching == 1
+		p.mu.Unlock()
+		if first {
+			close(p.started)
+		}
+		<-p.release
+	}
+	return ai.CompletionResponse{Content: "Divide both sides by 2.", Model: "mock", InputTokens: 10, OutputTokens: 5}, nil
+}
+
+func (p *gatedTeachingProvider) StreamComplete(context.Context, ai.CompletionRequest) (<-chan ai.StreamChunk, error) {
+	return nil, errors.New("not implemented")
+}
+
+func (p *gatedTeachingProvider) Models() []ai.ModelInfo { return nil }
+
+func (p *gatedTeachingProvider) HealthCheck(context.Context) error { return nil }
+
+func (p *gatedTeachingProvider) teachingCalls() int {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.teaching
+}
+
+func TestEngine_DoubleTappedMessageIsAnsweredOnce(t *testing.T) {
+	provider := &gatedTeachingProvider{started: make(chan struct{}), release: make(chan struct{})}
+	engine := agent.NewEngine(agent.EngineConfig{AIRouter: mockRouter(provider)})
+	msg := chat.InboundMessage{Channel: "telegram", UserID: "42", Text: "How do I solve 2x = 4?", Language: "en"}
+
+	first := make(chan string, 1)
+	go func() {
+		resp, _ := engine.ProcessMessage(context.Background(), msg)
+		first <- resp
+	}()
+	select {
+	case <-provider.started:
+	case <-time.After(5 * time.Second):
+		t.Fatal("first turn never reached the model")
+	}
+
+	second := make(chan string, 1)
+	go func() {
+		tapped := msg
+		tapped.Text = "how do I solve  2x = 4?"
+		resp, _ := engine.ProcessMessage(context.Background(), tapped)
+		second <- resp
+	}()
+	time.Sleep(20 * time.Millisecond) // let the repeat arrive while the first is in flight
+	close(provider.release)
+
+	if got := <-first; got != "Divide both sides by 2." {
+		t.Fatalf("first reply = %q, want the tutor answer", got)
+	}
+	if got := <-second; got != "That message came through twice, so I've answered it once 🙂" {
+		t.Fatalf("second reply = %q, want the duplicate note", got)
+	}
+	if got := provider.teachingCalls(); got != 1 {
+		t.Fatalf("teaching calls = %d, want 1", got)
+	}
+
+	// Once answered, sending the same question again is a new turn.
+	if got := sendAs(t, engine, "telegram", "42", msg.Text); got != "Divide both sides by 2." {
+		t.Fatalf("repeat after the answer = %q, want a fresh answer", got)
+	}
+	if got := provider.teachingCalls(); got != 2 {
+		t.Fatalf("teaching calls = %d, want 2", got)
+	}
+}
@@ -5,11 +5,16 @@ package agent
 
 import (
 	"context"
+	"errors"
 	"fmt"
 
+	"github.com/p-n-ai/pai-bot/internal/apperr"
 	"github.com/p-n-ai/pai-bot/internal/platform/featureflags"
 )
 
+// errTurnBlocked is a turn a hook blocked without a message of its own.
+var errTurnBlocked = apperr.Safety(errors.New("turn blocked by hook"))
+
 type turnHookOutcome string
 
 const (
@@ -154,8 +154,7 @@ func (e *Engine) maybeHandleOutOfScopeTutorRequest(msg chat.InboundMessage, conv
 		return "", false
 	}
 
-	response := outOfScopeCalculusResponse(msg.Text)
-	e.recordDeterministicTutorReply(msg, conv, response, "tutor_scope_redirect", map[string]any{
+	response := e.recordDeterministicTutorReply(msg, conv, outOfScopeCalculusResponse(msg.Text), "tutor_scope_redirect", map[string]any{
 		"channel": msg.Channel,
 		"scope":   "lower_secondary_kssm_math",
 		"reason":  "calculus",
@@ -167,15 +166,27 @@ func (e *Engine) maybeHandleInstructionPrivacyRequest(msg chat.InboundMessage, c
 	if !asksForHiddenTutorInstructions(msg.Text) {
 		return "", false
 	}
-	response := instructionPrivacyRefusal(msg.Text)
-	e.recordDeterministicTutorReply(msg, conv, response, "tutor_instruction_privacy_refused", map[string]any{
+	response := e.recordDeterministicTutorReply(msg, conv, instructionPrivacyRefusal(msg.Text), "tutor_instruction_privacy_refused", map[string]any{
 		"channel": msg.Channel,
 		"reason":  "hidden_instruction_request",
-	})
+	}, SafetyDecision{Check: SafetyCheckPromptInjection, Action: SafetyActionRefused, Reason: "hidden_instruction_request"})
 	return response, true
 }
 
-func (e *Engine) recordDeterministicTutorReply(msg chat.InboundMessage, conv *Conversation, response, eventType string, data map[string]any) {
+// recordDeterministicTutorReply stores a fixed reply and its question without
+// a model call. It returns the reply after the content filter, which is what
+// was stored and should be sent.
+func (e *Engine) recordDeterministicTutorReply(msg chat.InboundMessage, conv *Conversation, response, eventType string, data map[string]any, decisions ...SafetyDecision) string {
+	trace := SafetyTrace{Decisions: decisions}
+	if e.contentFilter != nil {
+		var masked int
+		response, masked = e.contentFilter.Clean(response)
+		decision := SafetyDecision{Check: SafetyCheckContentFilter, Action: SafetyActionPassed}
+		if masked > 0 {
+			decision.Action, decision.Hits = SafetyActionMasked, masked
+		}
+		trace.add(decision)
+	}
 	userContent := strings.TrimSpace(msg.Text)
 	if userContent == "" {
 		userContent = msg.Text
@@ -189,6 +200,7 @@ func (e *Engine) recordDeterministicTutorReply(msg chat.InboundMessage, conv *Co
 	if _, err := e.store.AddMessage(conv.ID, StoredMessage{
 		Role:    "assistant",
 		Content: response,
+		Safety:  storedSafetyTrace(&trace),
 	}); err != nil {
 		slog.Error("failed to store deterministic tutor assistant response", "event_type", eventType, "error", err)
 	}
@@ -199,6 +211,7 @@ func (e *Engine) recordDeterministicTutorReply(msg chat.InboundMessage, conv *Co
 		Data:           data,
 	})
 	e.recordActivityAsync(msg.UserID)
+	return response
 }
 
 func asksForHiddenTutorInstructions(text string) bool {
@@ -283,11 +296,13 @@ func outOfScopeCalculusResponse(text string) string {
 }
 
 func latestMessageLanguageInstruction(text string) string {
-	switch detectLatestMessageLanguage(text) {
+	switch detectMessageLanguage(text) {
 	case "en":
 		return "Latest user message appears mostly English. Reply mainly in English for this reply."
 	case "ms":
 		return "Latest user message appears mostly Bahasa Melayu. Reply mainly in Bahasa Melayu for this reply."
+	case "zh":
+		return "Latest user message appears mostly Chinese. Reply mainly in Chinese (Simplified) for this reply."
 	default:
 		return ""
 	}
@@ -0,0 +1,62 @@
+// Copyright 2026 the P&AI authors. All rights reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package agent
+
+import "github.com/p-n-ai/pai-bot/internal/chat"
+
+// UserState is where a learner is in their lifecycle with the bot, as seen
+// from outbound delivery.
+type UserState string
+
+const (
+	UserActive UserState = "active"
+	// UserInactive means the learner's chat account was deactivated.
+	UserInactive UserState = "inactive"
+	// UserBlocked means the learner blocked the bot.
+	UserBlocked UserState = "blocked"
+)
+
+// Lifecycle event types logged when a learner's state changes.
+const (
+	EventUserChurned     = "user_churned"
+	EventUserReactivated = "user_reactivated"
+)
+
+// UserLifecycleSource reports a learner's lifecycle state. The scheduler
+// only nudges active learners.
+type UserLifecycleSource interface {
+	UserLifecycleState(userID string) (UserState, bool)
+}
+
+// lifecycleTransition returns the state a delivery moves a learner to and
+// the event to log, or ok=false when the state stays as it is. Failed and
+// rate-limited sends say nothing about the learner, so they never move it.
+func lifecycleTransition(current UserState, rec chat.DeliveryRecord) (next UserState, eventType string, ok bool) {
+	switch rec.Status {
+	case chat.DeliveryBlocked:
+		next = UserBlocked
+		if rec.AccountDeactivated() {
+			next = UserInactive
+		}
+		if next == current {
+			return "", "", false
+		}
+		return next, EventUserChurned, true
+	case chat.DeliverySent:
+		if current == UserActive {
+			return "", "", false
+		}
+		return UserActive, EventUserReactivated, true
+	default:
+		return "", "", false
+	}
+}
+
+func lifecycleEventData(rec chat.DeliveryRecord, from, to UserState) map[string]any {
+	return map[string]any{
+		"channel": rec.Channel,
+		"from":    string(from),
+		"to":      string(to),
+	}
+}
@@ -0,0 +1,372 @@
+// Copyright 2026 the P&AI authors. All rights reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package agent
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+
+	"github.com/p-n-ai/pai-bot/internal/progress"
+)
+
+const (
+	// warmStandbyHour and warmStandbyMinute schedule the morning prefetch
+	// just before quiet hours end.
+	warmStandbyHour   = 6
+	warmStandbyMinute = 30
+	// warmSnapshotTTL keeps a morning snapshot until late evening. The first
+	// turn of the day consumes it; writes to the learner state it holds made
+	// outside a turn, such as an admin learning-state import or a quiz
+	// regrade, drop it with ForgetWarmSnapshot.
+	warmSnapshotTTL = 18 * time.Hour
+	// warmLookback picks the returning learners worth prefetching.
+	warmLookback      = 7 * 24 * time.Hour
+	maxWarmLearners   = 5000
+	warmStandbyWorker = 8
+	// maxWarmSeenEntries caps the first-message-of-the-day tracker; a full
+	// tracker is dropped whole, which at worst prefetches a learner twice.
+	maxWarmSeenEntries = 65536
+)
+
+// WarmCache is the shared cache returning learners' prefetched state is kept
+// in. cache.Cache implements it over Redis.
+type WarmCache interface {
+	// GetDel returns and removes the value at key, or nil when it is unset.
+	GetDel(ctx context.Context, key string) ([]byte, error)
+	Set(ctx context.Context, key string, value []byte, ttl time.Duration) error
+}
+
+// RecentLearnerLister is implemented by stores that can list active learners
+// who messaged since a given time.
+type RecentLearnerLister interface {
+	RecentLearners(ctx context.Context, since time.Time, limit int) ([]string, error)
+}
+
+// learnerSnapshot is the per-learner state a teaching turn reads before it
+// calls the model. The conversation summary is not part of it: it comes back
+// with the active conversation row.
+type learnerSnapshot struct {
+	Profile  learnerProfile          `json:"profile"`
+	Progress []progress.ProgressItem `json:"progress,omitempty"`
+	WarmedAt time.Time               `json:"warmed_at"`
+}
+
+// dueReviews returns the snapshot's topics that are due for review at now.
+func (s learnerSnapshot) dueReviews(now time.Time) []progress.ProgressItem {
+	var due []progress.ProgressItem
+	for _, item := range s.Progress {
+		if !item.NextReviewAt.IsZero() && !item.NextReviewAt.After(now) {
+			due = append(due, item)
+		}
+	}
+	return due
+}
+
+// warmStandby hands a returning learner's first turn of the day a snapshot
+// loaded ahead of time, so it skips the sequential profile and mastery reads.
+// A snapshot is only used for the turn that claimed it; later turns read the
+// store as usual, so there is nothing to invalidate across turns.
+type warmStandby struct {
+	cache WarmCache
+	mu    sync.Mutex
+	seen  map[string]string // userID → MYT date of their last claimed turn
+	turns map[string]learnerSnapshot
+}
+
+func newWarmStandby(cache WarmCache) *warmStandby {
+	if cache == nil {
+		return nil
+	}
+	return &warmStandby{
+		cache: cache,
+		seen:  make(map[string]string),
+		turns: make(map[string]learnerSnapshot),
+	}
+}
+
+// firstToday records a turn for userID and reports whether it is their first
+// of the MYT day.
+func (w *warmStandby) firstToday(userID string, now time.Time) bool {
+	day := warmDay(now)
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if w.seen[userID] == day {
+		return false
+	}
+	if len(w.seen) >= maxWarmSeenEntries {
+		clear(w.seen)
+	}
+	w.seen[userID] = day
+	return true
+}
+
+// seenToday reports whether userID already had a turn today.
+func (w *warmStandby) seenToday(userID string, now time.Time) bool {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return w.seen[userID] == warmDay(now)
+}
+
+func (w *warmStandby) hold(userID string, snap learnerSnapshot) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.turns[userID] = snap
+}
+
+// snapshot returns the snapshot held for userID's in-flight turn.
+func (w *warmStandby) snapshot(userID string) (learnerSnapshot, bool) {
+	if w == nil {
+		return learnerSnapshot{}, false
+	}
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	snap, ok := w.turns[userID]
+	return snap, ok
+}
+
+// release drops the snapshot once the turn that claimed it is done, or once
+// the turn changes the profile it holds.
+func (w *warmStandby) release(userID string) {
+	if w == nil {
+		return
+	}
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	delete(w.turns, userID)
+}
+
+// setName keeps a held snapshot in step with the chat display name, which
+// every message re-saves.
+func (w *warmStandby) setName(userID, name string) {
+	if w == nil {
+		return
+	}
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if snap, ok := w.turns[userID]; ok {
+		snap.Profile.Name = name
+		w.turns[userID] = snap
+	}
+}
+
+func (e *Engine) warmKey(userID string) string {
+	return warmSnapshotKey(e.tenantID, userID)
+}
+
+func warmSnapshotKey(tenantID, userID string) string {
+	return "warm:" + tenantID + ":" + userID
+}
+
+// ForgetWarmSnapshot drops the snapshot prefetched for a learner, so their
+// next turn reads the store. Call it after changing their profile or mastery
+// outside a turn. A nil cache does nothing; failures are logged.
+func ForgetWarmSnapshot(ctx context.Context, cache WarmCache, tenantID, userID string) {
+	if cache == nil {
+		return
+	}
+	if _, err := cache.GetDel(ctx, warmSnapshotKey(tenantID, userID)); err != nil {
+		slog.Warn("warm snapshot invalidation failed", "user_id", userID, "error", err)
+	}
+}
+
+// claimWarmSnapshot runs when a learner's turn starts. On their first message
+// of the day it takes the snapshot prefetched that morning, or loads one with
+// the reads running concurrently when there is none.
+func (e *Engine) claimWarmSnapshot(ctx context.Context, userID string) {
+	now := time.Now()
+	if e.warm == nil || !e.warm.firstToday(userID, now) {
+		return
+	}
+	data, err := e.warm.cache.GetDel(ctx, e.warmKey(userID))
+	if err != nil {
+		slog.Warn("warm snapshot read failed", "user_id", userID, "error", err)
+	}
+	if len(data) > 0 {
+		// A snapshot from an earlier day may predate turns taken since.
+		var snap learnerSnapshot
+		if err := json.Unmarshal(data, &snap); err == nil && warmDay(snap.WarmedAt) == warmDay(now) {
+			e.warm.hold(userID, snap)
+			return
+		}
+	}
+	e.warm.hold(userID, e.readLearnerSnapshot(userID, true))
+}
+
+// readLearnerSnapshot loads a learner's profile and mastery. Each read fills
+// its own field, so concurrent reads need no locking.
+func (e *Engine) readLearnerSnapshot(userID string, concurrent bool) learnerSnapshot {
+	snap := learnerSnapshot{WarmedAt: time.Now()}
+	reads := []func(){
+		func() { snap.Profile.Name, _ = e.store.GetUserName(userID) },
+		func() { snap.Profile.Form, _ = e.store.GetUserForm(userID) },
+		func() { snap.Profile.Language, _ = e.store.GetUserPreferredLanguage(userID) },
+		func() { snap.Profile.QuizIntensity, _ = e.store.GetUserPreferredQuizIntensity(userID) },
+		func() { snap.Profile.ABGroup, _ = e.store.GetUserABGroup(userID) },
+	}
+	if e.tracker != nil {
+		reads = append(reads, func() {
+			if items, err := e.tracker.GetAllProgress(userID); err == nil {
+				snap.Progress = items
+			}
+		})
+	}
+	if !concurrent {
+		for _, read := range reads {
+			read()
+		}
+		return snap
+	}
+	var wg sync.WaitGroup
+	for _, read := range reads {
+		wg.Go(read)
+	}
+	wg.Wait()
+	return snap
+}
+
+// WarmLearners prefetches each learner's snapshot into the warm cache and
+// returns how many were stored.
+func (e *Engine) WarmLearners(ctx context.Context, userIDs []string) int {
+	if e.warm == nil {
+		return 0
+	}
+	jobs := make(chan string)
+	var (
+		mu     sync.Mutex
+		stored int
+		wg     sync.WaitGroup
+	)
+	for range min(warmStandbyWorker, len(userIDs)) {
+		wg.Go(func() {
+			for userID := range jobs {
+				// A turn today already paid the reads, and a snapshot
+				// written now could predate its mastery updates.
+				if e.warm.seenToday(userID, time.Now()) {
+					continue
+				}
+				data, err := json.Marshal(e.readLearnerSnapshot(userID, true))
+				if err != nil {
+					continue
+				}
+				if err := e.warm.cache.Set(ctx, e.warmKey(userID), data, warmSnapshotTTL); err != nil {
+					slog.Warn("warm snapshot write failed", "user_id", userID, "error", err)
+					continue
+				}
+				mu.Lock()
+				stored++
+				mu.Unlock()
+			}
+		})
+	}
+feed:
+	for _, userID := range userIDs {
+		select {
+		case <-ctx.Done():
+			break feed
+		case jobs <- userID:
+		}
+	}
+	close(jobs)
+	wg.Wait()
+	return stored
+}
+
+// RunWarmStandby prefetches every learner active in the last week at 06:30
+// MYT each day. It needs a warm cache and a store that can list recent
+// learners, and blocks until ctx is cancelled.
+func (e *Engine) RunWarmStandby(ctx context.Context) {
+	lister, ok := e.store.(RecentLearnerLister)
+	if e.warm == nil || !ok {
+		return
+	}
+	for {
+		timer := time.NewTimer(timeUntilNext(warmStandbyHour, warmStandbyMinute))
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return
+		case now := <-timer.C:
+			userIDs, err := lister.RecentLearners(ctx, now.Add(-warmLookback), maxWarmLearners)
+			if err != nil {
+				slog.Error("warm standby: list learners failed", "error", err)
+				continue
+			}
+			stored := e.WarmLearners(ctx, userIDs)
+			slog.Info("warm standby done", "learners", len(userIDs), "stored", stored)
+		}
+	}
+}
+
+// warmDay returns the MYT date of t.
+func warmDay(t time.Time) string {
+	loc, err := time.LoadLocation("Asia/Kuala_Lumpur")
+	if err != nil {
+		loc = time.FixedZone("MYT", 8*60*60)
+	}
+	return t.In(loc).Format("2006-01-02")
+}
+
+// RecentLearners returns active learners with a message since since.
+func (s *MemoryStore) RecentLearners(_ context.Context, since time.Time, limit int) ([]string, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	seen := make(map[string]bool)
+	var userIDs []string
+	for _, conv := range s.conversations {
+		if seen[conv.UserID] || len(conv.Messages) == 0 {
+			continue
+		}
+		if state, ok := s.userState[conv.UserID]; ok && state != UserActive {
+			continue
+		}
+		if conv.Messages[len(conv.Messages)-1].CreatedAt.Before(since) {
+			continue
+		}
+		seen[conv.UserID] = true
+		userIDs = append(userIDs, conv.UserID)
+	}
+	slices.Sort(userIDs)
+	if len(userIDs) > limit {
+		userIDs = userIDs[:limit]
+	}
+	return userIDs, nil
+}
+
+func (s *PostgresStore) RecentLearners(ctx context.Context, since time.Time, limit int) ([]string, error) {
+	ctx, cancel := context.WithTimeout(ctx, dbTimeout)
+	defer cancel()
+
+	rows, err := s.pool.Query(ctx,
+		`SELECT u.external_id
+		 FROM users u
+		 WHERE u.tenant_id = $1::uuid
+		   AND u.channel = $2
+		   AND u.external_id IS NOT NULL
+		   AND u.lifecycle_state = 'active'
+		   AND EXISTS (
+		       SELECT 1
+		       FROM conversations c
+		       JOIN messages m ON m.conversation_id = c.id
+		       WHERE c.user_id = u.id AND m.created_at >= $3
+		   )
+		 ORDER BY u.external_id
+		 LIMIT $4`,
+		s.tenantID, s.channel, since, limit,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("list recent learners: %w", err)
+	}
+	userIDs, err := pgx.CollectRows(rows, pgx.RowTo[string])
+	if err != nil {
+		return nil, fmt.Errorf("scan recent learners: %w", err)
+	}
+	return userIDs, nil
+}
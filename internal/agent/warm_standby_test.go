@@ -0,0 +1,151 @@
+// Copyright 2026 the P&AI authors. All rights reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package agent_test
+
+import (
+	"context"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/p-n-ai/pai-bot/internal/agent"
+	"github.com/p-n-ai/pai-bot/internal/ai"
+	"github.com/p-n-ai/pai-bot/internal/chat"
+	"github.com/p-n-ai/pai-bot/internal/progress"
+)
+
+// mapWarmCache is an in-process stand-in for the Redis warm cache.
+type mapWarmCache struct {
+	mu     sync.Mutex
+	values map[string][]byte
+}
+
+func newMapWarmCache() *mapWarmCache {
+	return &mapWarmCache{values: make(map[string][]byte)}
+}
+
+func (c *mapWarmCache) GetDel(_ context.Context, key string) ([]byte, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	value := c.values[key]
+	delete(c.values, key)
+	return value, nil
+}
+
+func (c *mapWarmCache) Set(_ context.Context, key string, value []byte, _ time.Duration) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.values[key] = value
+	return nil
+}
+
+func (c *mapWarmCache) len() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return len(c.values)
+}
+
+// lastTeachingPrompt returns the messages of the last teaching request
+// provider saw, joined into one string.
+func lastTeachingPrompt(t *testing.T, provider *scriptedReplyProvider) string {
+	t.Helper()
+	provider.mu.Lock()
+	defer provider.mu.Unlock()
+	var req *ai.CompletionRequest
+	for i := range provider.requests {
+		if provider.requests[i].Task == ai.TaskTeaching {
+			req = &provider.requests[i]
+		}
+	}
+	if req == nil {
+		t.Fatal("no teaching request was made")
+	}
+	var b strings.Builder
+	for _, m := range req.Messages {
+		b.WriteString(m.Content)
+		b.WriteString("\n")
+	}
+	return b.String()
+}
+
+func TestWarmStandbyFirstTurnOfTheDayUsesPrefetchedSnapshot(t *testing.T) {
+	provider := &scriptedReplyProvider{responses: []string{"ok"}}
+	store := agent.NewMemoryStore()
+	tracker := progress.NewMemoryTracker()
+	cache := newMapWarmCache()
+	_ = store.SetUserForm("warm-user", "1")
+	_ = store.SetUserPreferredLanguage("warm-user", "en")
+	_ = tracker.SetMastery("warm-user", "kssm-f1", "F1-02", 0.4)
+	engine := agent.NewEngine(agent.EngineConfig{
+		AIRouter:         mockRouter(provider),
+		Store:            store,
+		Tracker:          tracker,
+		CurriculumLoader: createTestCurriculumLoader(t),
+		WarmCache:        cache,
+	})
+	_, _ = store.CreateConversation(agent.Conversation{UserID: "warm-user", State: "teaching", TopicID: "F1-02"})
+
+	if got := engine.WarmLearners(t.Context(), []string{"warm-user"}); got != 1 {
+		t.Fatalf("WarmLearners() = %d, want 1", got)
+	}
+	// A write that bypasses the engine shows which turn read the snapshot.
+	_ = store.SetUserForm("warm-user", "2")
+
+	sendAs(t, engine, "telegram", "warm-user", "solve 2x + 3 = 7")
+	if text := lastTeachingPrompt(t, provider); !strings.Contains(text, "Form: 1") || !strings.Contains(text, "F1-02: 40% mastery") {
+		t.Fatalf("first turn should render the prefetched profile:\n%s", text)
+	}
+	if cache.len() != 0 {
+		t.Error("first turn should consume the prefetched snapshot")
+	}
+
+	sendAs(t, engine, "telegram", "warm-user", "now solve 3x = 12")
+	if text := lastTeachingPrompt(t, provider); !strings.Contains(text, "Form: 2") {
+		t.Fatalf("later turns should read the store:\n%s", text)
+	}
+	if got := engine.WarmLearners(t.Context(), []string{"warm-user"}); got != 0 {
+		t.Errorf("WarmLearners() after a turn today = %d, want 0", got)
+	}
+}
+
+func TestWarmStandbyColdFirstTurnLoadsSnapshot(t *testing.T) {
+	provider := &scriptedReplyProvider{responses: []string{"ok"}}
+	store := agent.NewMemoryStore()
+	tracker := progress.NewMemoryTracker()
+	_ = store.SetUserForm("cold-user", "1")
+	_ = tracker.SetMastery("cold-user", "kssm-f1", "F1-02", 0.4)
+	engine := agent.NewEngine(agent.EngineConfig{
+		AIRouter:  mockRouter(provider),
+		Store:     store,
+		Tracker:   tracker,
+		WarmCache: newMapWarmCache(),
+	})
+	_, _ = store.CreateConversation(agent.Conversation{UserID: "cold-user", State: "teaching"})
+
+	sendAs(t, engine, "telegram", "cold-user", "solve 2x + 3 = 7")
+	text := lastTeachingPrompt(t, provider)
+	if !strings.Contains(text, "Form: 1") || !strings.Contains(text, "F1-02: 40% mastery") {
+		t.Fatalf("cold first turn should still render profile and mastery:\n%s", text)
+	}
+}
+
+func TestMemoryStoreRecentLearners(t *testing.T) {
+	store := agent.NewMemoryStore()
+	for _, userID := range []string{"b", "a", "quiet"} {
+		id, _ := store.CreateConversation(agent.Conversation{UserID: userID, State: "teaching"})
+		if userID != "quiet" {
+			_, _ = store.AddMessage(id, agent.StoredMessage{Role: "user", Content: "hi", CreatedAt: time.Now()})
+		}
+	}
+	_ = store.RecordDelivery(chat.DeliveryRecord{Channel: "telegram", UserID: "b", Status: chat.DeliveryBlocked, At: time.Now()})
+
+	got, err := store.RecentLearners(t.Context(), time.Now().Add(-time.Hour), 10)
+	if err != nil {
+		t.Fatalf("RecentLearners() error = %v", err)
+	}
+	if len(got) != 1 || got[0] != "a" {
+		t.Errorf("RecentLearners() = %v, want [a]", got)
+	}
+}
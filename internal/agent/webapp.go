@@ -0,0 +1,129 @@
+// Copyright 2026 the P&AI authors. All rights reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package agent
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/p-n-ai/pai-bot/internal/i18n"
+	"github.com/p-n-ai/pai-bot/internal/progress"
+)
+
+// WebAppProgress is the learner's progress as the Telegram Mini App shows it.
+type WebAppProgress struct {
+	XP            int                   `json:"xp"`
+	CurrentStreak int                   `json:"current_streak"`
+	LongestStreak int                   `json:"longest_streak"`
+	Topics        []WebAppTopicProgress `json:"topics"`
+	Badges        []WebAppBadge         `json:"badges"`
+}
+
+// WebAppTopicProgress is one topic's mastery.
+type WebAppTopicProgress struct {
+	SyllabusID   string     `json:"syllabus_id"`
+	TopicID      string     `json:"topic_id"`
+	TopicName    string     `json:"topic_name,omitempty"`
+	Mastery      float64    `json:"mastery"`
+	Mastered     bool       `json:"mastered"`
+	NextReviewAt *time.Time `json:"next_review_at,omitempty"`
+}
+
+// WebAppBadge is an earned badge with its localized name.
+type WebAppBadge struct {
+	ID       BadgeID   `json:"id"`
+	Name     string    `json:"name"`
+	EarnedAt time.Time `json:"earned_at"`
+}
+
+// WebAppQuiz is the learner's current quiz question. It never carries the
+// answer; answers are checked by the engine like a chat reply.
+type WebAppQuiz struct {
+	Active         bool     `json:"active"`
+	Paused         bool     `json:"paused,omitempty"`
+	TopicID        string   `json:"topic_id,omitempty"`
+	TopicName      string   `json:"topic_name,omitempty"`
+	QuestionNumber int      `json:"question_number,omitempty"`
+	TotalQuestions int      `json:"total_questions,omitempty"`
+	CorrectAnswers int      `json:"correct_answers,omitempty"`
+	Question       string   `json:"question,omitempty"`
+	AnswerType     string   `json:"answer_type,omitempty"`
+	Options        []string `json:"options,omitempty"`
+}
+
+// WebAppProgress collects the learner's mastery, XP, streak and badges.
+func (e *Engine) WebAppProgress(userID string) (WebAppProgress, error) {
+	report := WebAppProgress{Topics: []WebAppTopicProgress{}, Badges: []WebAppBadge{}}
+	if e.tracker != nil {
+		items, err := e.tracker.GetAllProgress(userID)
+		if err != nil {
+			return WebAppProgress{}, fmt.Errorf("get progress: %w", err)
+		}
+		for _, item := range items {
+			topic := WebAppTopicProgress{
+				SyllabusID: item.SyllabusID,
+				TopicID:    item.TopicID,
+				TopicName:  e.lookupTopicName(item.TopicID),
+				Mastery:    item.MasteryScore,
+				Mastered:   progress.IsMastered(item.MasteryScore),
+			}
+			if !item.NextReviewAt.IsZero() {
+				next := item.NextReviewAt
+				topic.NextReviewAt = &next
+			}
+			report.Topics = append(report.Topics, topic)
+		}
+	}
+	if e.xp != nil {
+		report.XP, _ = e.xp.GetTotal(userID)
+	}
+	if e.streaks != nil {
+		streak, _ := e.streaks.GetStreak(userID)
+		report.CurrentStreak, report.LongestStreak = streak.CurrentStreak, streak.LongestStreak
+	}
+	if e.badges != nil {
+		earned, err := e.badges.ListBadges(userID)
+		if err != nil {
+			return WebAppProgress{}, fmt.Errorf("list badges: %w", err)
+		}
+		locale := e.resolveUserLocale(userID)
+		for _, badge := range earned {
+			report.Badges = append(report.Badges, WebAppBadge{
+				ID:       badge.ID,
+				Name:     i18n.S(locale, badgeNames[badge.ID]),
+				EarnedAt: badge.EarnedAt,
+			})
+		}
+	}
+	return report, nil
+}
+
+// WebAppQuiz returns the question the learner's active quiz is waiting on.
+func (e *Engine) WebAppQuiz(userID string) WebAppQuiz {
+	conv, found := e.store.GetActiveConversation(userID)
+	if !found || conv == nil || conv.QuizState == nil {
+		return WebAppQuiz{}
+	}
+	state := *conv.QuizState
+	session, ok := e.quizSessionFromState(userID, state)
+	if !ok {
+		return WebAppQuiz{}
+	}
+	question, hasQuestion := session.NextQuestion()
+	if !hasQuestion {
+		return WebAppQuiz{}
+	}
+	return WebAppQuiz{
+		Active:         true,
+		Paused:         state.RunState == quizRunStatePaused,
+		TopicID:        state.TopicID,
+		TopicName:      e.lookupTopicName(state.TopicID),
+		QuestionNumber: session.CurrentIndex + 1,
+		TotalQuestions: len(session.Questions),
+		CorrectAnswers: session.CorrectAnswers,
+		Question:       question.Text,
+		AnswerType:     question.AnswerType,
+		Options:        quizOptions(question),
+	}
+}
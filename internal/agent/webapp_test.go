@@ -0,0 +1,69 @@
+// Copyright 2026 the P&AI authors. All rights reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package agent_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/p-n-ai/pai-bot/internal/agent"
+	"github.com/p-n-ai/pai-bot/internal/ai"
+	"github.com/p-n-ai/pai-bot/internal/chat"
+	"github.com/p-n-ai/pai-bot/internal/progress"
+)
+
+func TestEngine_WebAppQuizFollowsActiveQuiz(t *testing.T) {
+	store := agent.NewMemoryStore()
+	engine := agent.NewEngine(agent.EngineConfig{
+		AIRouter:         mockRouter(ai.NewMockProvider("unused")),
+		Store:            store,
+		CurriculumLoader: createTestCurriculumLoader(t),
+	})
+	if quiz := engine.WebAppQuiz("webapp-quiz"); quiz.Active {
+		t.Fatalf("WebAppQuiz() before a quiz = %+v, want inactive", quiz)
+	}
+
+	if _, err := engine.ProcessMessage(context.Background(), chat.InboundMessage{
+		Channel: "telegram",
+		UserID:  "webapp-quiz",
+		Text:    "quiz me on linear equations",
+	}); err != nil {
+		t.Fatalf("ProcessMessage() error = %v", err)
+	}
+
+	quiz := engine.WebAppQuiz("webapp-quiz")
+	if !quiz.Active || quiz.TopicID != "F1-02" || quiz.QuestionNumber != 1 || quiz.TotalQuestions != 3 || quiz.Question == "" {
+		t.Fatalf("WebAppQuiz() = %+v, want the first of 3 F1-02 questions", quiz)
+	}
+}
+
+func TestEngine_WebAppProgressCollectsLearnerStats(t *testing.T) {
+	tracker := progress.NewMemoryTracker()
+	xp := progress.NewMemoryXPTracker()
+	badges := agent.NewMemoryBadgeStore()
+	engine := agent.NewEngine(agent.EngineConfig{
+		Store:            agent.NewMemoryStore(),
+		CurriculumLoader: createTestCurriculumLoader(t),
+		Tracker:          tracker,
+		XP:               xp,
+		Badges:           badges,
+	})
+	_ = tracker.UpdateMastery("webapp-progress", "kssm-form1", "F1-02", 0.9)
+	_ = xp.Award("webapp-progress", progress.XPSourceQuiz, 40, nil)
+	_, _ = badges.AwardBadge("webapp-progress", agent.BadgeFirstQuiz)
+
+	report, err := engine.WebAppProgress("webapp-progress")
+	if err != nil {
+		t.Fatalf("WebAppProgress() error = %v", err)
+	}
+	if report.XP != 40 {
+		t.Fatalf("XP = %d, want 40", report.XP)
+	}
+	if len(report.Topics) != 1 || report.Topics[0].TopicID != "F1-02" || !report.Topics[0].Mastered || report.Topics[0].TopicName == "" {
+		t.Fatalf("Topics = %+v, want mastered F1-02 with its name", report.Topics)
+	}
+	if len(report.Badges) != 1 || report.Badges[0].ID != agent.BadgeFirstQuiz || report.Badges[0].Name == "" {
+		t.Fatalf("Badges = %+v, want the named first quiz badge", report.Badges)
+	}
+}
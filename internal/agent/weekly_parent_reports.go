@@ -13,11 +13,6 @@ import (
 	"github.com/p-n-ai/pai-bot/internal/chat"
 )
 
-const (
-	weeklyParentReportHour    = 20
-	weeklyParentReportWeekday = time.Sunday
-)
-
 type WeeklyParentWeeklyStats struct {
 	DaysActive        int
 	MessagesExchanged int
@@ -48,22 +43,6 @@ func (s *Scheduler) SetWeeklyParentReportSource(source WeeklyParentReportSource)
 	s.parentReports = source
 }
 
-func (s *Scheduler) runWeeklyParentReportTimer(ctx context.Context) {
-	for {
-		delay := timeUntilNextWeekday(weeklyParentReportWeekday, weeklyParentReportHour, 0)
-		s.logger.Info("weekly parent reports scheduled", "fires_in", delay.Round(time.Second))
-
-		timer := time.NewTimer(delay)
-		select {
-		case <-ctx.Done():
-			timer.Stop()
-			return
-		case now := <-timer.C:
-			s.SendWeeklyParentReports(ctx, now)
-		}
-	}
-}
-
 func timeUntilNextWeekday(weekday time.Weekday, hour, minute int) time.Duration {
 	loc, err := time.LoadLocation("Asia/Kuala_Lumpur")
 	if err != nil {
@@ -78,6 +57,9 @@ func timeUntilNextWeekday(weekday time.Weekday, hour, minute int) time.Duration
 	return next.Sub(now)
 }
 
+// SendWeeklyParentReports sends each linked parent their child's week. The
+// server runs it as the weekly-parent-reports job, Sunday 20:00 MYT by
+// default.
 func (s *Scheduler) SendWeeklyParentReports(ctx context.Context, now time.Time) {
 	if s.parentReports == nil {
 		return
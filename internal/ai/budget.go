@@ -19,7 +19,7 @@ type BudgetChecker interface {
 }
 
 // InMemoryBudget is a simple in-memory budget tracker for development.
-// Production will use Dragonfly for real-time tracking with periodic PostgreSQL sync.
+// The server uses agent.CachedTokenBudget, which syncs to PostgreSQL.
 type InMemoryBudget struct {
 	mu      sync.RWMutex
 	budgets map[string]int64 // key -> budget limit
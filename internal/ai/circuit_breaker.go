@@ -0,0 +1,243 @@
+// Copyright 2026 the P&AI authors. All rights reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package ai
+
+import (
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"time"
+)
+
+// BreakerState is the state of a provider's circuit breaker.
+type BreakerState string
+
+const (
+	// BreakerClosed lets every request through.
+	BreakerClosed BreakerState = "closed"
+	// BreakerOpen skips the provider until the cooldown ends.
+	BreakerOpen BreakerState = "open"
+	// BreakerHalfOpen lets a single probe request through once the cooldown
+	// ends. A successful probe closes the circuit; a failed one reopens it.
+	BreakerHalfOpen BreakerState = "half_open"
+)
+
+// BreakerTransition reports a provider's circuit breaker changing state.
+type BreakerTransition struct {
+	Provider string
+	From     BreakerState
+	To       BreakerState
+	At       time.Time
+}
+
+// ProviderBreaker is a snapshot of one provider's circuit breaker, with
+// running counts of the transitions into each state since the provider was
+// registered.
+type ProviderBreaker struct {
+	Provider            string
+	State               BreakerState
+	ConsecutiveFailures int
+	Opened              int
+	HalfOpened          int
+	Closed              int
+}
+
+type breakerState struct {
+	consecutiveFailures int
+	openUntil           time.Time
+	// probeUntil is set while a half-open probe is in flight. It expires
+	// after a cooldown so a probe that never reports back cannot wedge the
+	// circuit.
+	probeUntil time.Time
+	opened     int
+	halfOpened int
+	closed     int
+}
+
+func (s breakerState) state(now time.Time) BreakerState {
+	switch {
+	case s.openUntil.IsZero():
+		return BreakerClosed
+	case now.Before(s.openUntil):
+		return BreakerOpen
+	default:
+		return BreakerHalfOpen
+	}
+}
+
+// SetBreakerObserver registers a callback for provider circuit breaker state
+// changes, for exporting them as metrics. It is called outside the router's
+// lock, after the transition is logged.
+func (r *Router) SetBreakerObserver(observer func(BreakerTransition)) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.breakerObserver = observer
+}
+
+// Breakers returns a snapshot of each registered provider's circuit breaker
+// in fallback order.
+func (r *Router) Breakers() []ProviderBreaker {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	now := time.Now()
+	breakers := make([]ProviderBreaker, 0, len(r.fallback))
+	for _, name := range r.fallback {
+		state := r.breakerStateByProvider[name]
+		breakers = append(breakers, ProviderBreaker{
+			Provider:            name,
+			State:               state.state(now),
+			ConsecutiveFailures: state.consecutiveFailures,
+			Opened:              state.opened,
+			HalfOpened:          state.halfOpened,
+			Closed:              state.closed,
+		})
+	}
+	return breakers
+}
+
+// BreakerStatsHandler serves Breakers as JSON.
+func (r *Router) BreakerStatsHandler() http.Handler {
+	return http.HandlerFunc(func(rw http.ResponseWriter, _ *http.Request) {
+		breakers := r.Breakers()
+		providers := make([]map[string]any, 0, len(breakers))
+		for _, b := range breakers {
+			providers = append(providers, map[string]any{
+				"provider":             b.Provider,
+				"state":                b.State,
+				"consecutive_failures": b.ConsecutiveFailures,
+				"opened":               b.Opened,
+				"half_opened":          b.HalfOpened,
+				"closed":               b.Closed,
+			})
+		}
+		rw.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(rw).Encode(map[string]any{"providers": providers})
+	})
+}
+
+// isCircuitOpen reports whether the provider would turn a request away right
+// now, without claiming the half-open probe.
+func (r *Router) isCircuitOpen(providerName string) bool {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	state := r.breakerStateByProvider[providerName]
+	now := time.Now()
+	switch state.state(now) {
+	case BreakerOpen:
+		return true
+	case BreakerHalfOpen:
+		return now.Before(state.probeUntil)
+	}
+	return false
+}
+
+// allowRequest reports whether a request may be sent to the provider. Once an
+// open circuit's cooldown ends it admits one caller as the half-open probe;
+// that caller must report back through markSuccess, markFailure or
+// releaseProbe.
+func (r *Router) allowRequest(providerName string) bool {
+	r.mu.Lock()
+	state := r.breakerStateByProvider[providerName]
+	now := time.Now()
+	var transition *BreakerTransition
+	switch state.state(now) {
+	case BreakerOpen:
+		r.mu.Unlock()
+		return false
+	case BreakerHalfOpen:
+		if now.Before(state.probeUntil) {
+			r.mu.Unlock()
+			return false
+		}
+		if state.probeUntil.IsZero() {
+			state.halfOpened++
+			transition = &BreakerTransition{Provider: providerName, From: BreakerOpen, To: BreakerHalfOpen, At: now}
+		}
+		state.probeUntil = now.Add(r.breakerCooldown)
+		r.breakerStateByProvider[providerName] = state
+	}
+	observer := r.breakerObserver
+	r.mu.Unlock()
+
+	r.emitBreakerTransition(observer, transition)
+	return true
+}
+
+// releaseProbe frees a half-open probe slot when the request ended without a
+// verdict on the provider, such as a cancelled context.
+func (r *Router) releaseProbe(providerName string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if state, ok := r.breakerStateByProvider[providerName]; ok && !state.probeUntil.IsZero() {
+		state.probeUntil = time.Now()
+		r.breakerStateByProvider[providerName] = state
+	}
+}
+
+func (r *Router) markFailure(providerName string, gen uint64) {
+	r.mu.Lock()
+	if gen != r.gen {
+		r.mu.Unlock()
+		return
+	}
+	state := r.breakerStateByProvider[providerName]
+	now := time.Now()
+	from := state.state(now)
+	var transition *BreakerTransition
+	// Failures landing while the circuit is open come from requests sent
+	// before it tripped and must not extend the cooldown.
+	if from != BreakerOpen {
+		state.consecutiveFailures++
+		if from == BreakerHalfOpen || state.consecutiveFailures >= r.breakerFailureThreshold {
+			state.openUntil = now.Add(r.breakerCooldown)
+			state.probeUntil = time.Time{}
+			state.consecutiveFailures = 0
+			state.opened++
+			transition = &BreakerTransition{Provider: providerName, From: from, To: BreakerOpen, At: now}
+		}
+		r.breakerStateByProvider[providerName] = state
+	}
+	observer := r.breakerObserver
+	r.mu.Unlock()
+
+	r.emitBreakerTransition(observer, transition)
+}
+
+func (r *Router) markSuccess(providerName string, gen uint64) {
+	r.mu.Lock()
+	if gen != r.gen {
+		r.mu.Unlock()
+		return
+	}
+	state := r.breakerStateByProvider[providerName]
+	now := time.Now()
+	var transition *BreakerTransition
+	if from := state.state(now); from != BreakerClosed {
+		state.closed++
+		transition = &BreakerTransition{Provider: providerName, From: from, To: BreakerClosed, At: now}
+	}
+	state.consecutiveFailures = 0
+	state.openUntil = time.Time{}
+	state.probeUntil = time.Time{}
+	r.breakerStateByProvider[providerName] = state
+	observer := r.breakerObserver
+	r.mu.Unlock()
+
+	r.emitBreakerTransition(observer, transition)
+}
+
+func (r *Router) emitBreakerTransition(observer func(BreakerTransition), transition *BreakerTransition) {
+	if transition == nil {
+		return
+	}
+	attrs := []any{"provider", transition.Provider, "from", transition.From, "to", transition.To}
+	if transition.To == BreakerOpen {
+		slog.Warn("AI provider circuit opened", append(attrs, "cooldown_seconds", int(r.breakerCooldown.Seconds()))...)
+	} else {
+		slog.Info("AI provider circuit state changed", attrs...)
+	}
+	if observer != nil {
+		observer(*transition)
+	}
+}
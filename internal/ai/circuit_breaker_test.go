@@ -0,0 +1,121 @@
+// Copyright 2026 the P&AI authors. All rights reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package ai
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestAllowRequestAdmitsOneHalfOpenProbe(t *testing.T) {
+	router := NewRouterWithConfig(RouterConfig{BreakerFailureThreshold: 1, BreakerCooldown: 10 * time.Millisecond})
+	router.Register("primary", NewMockProvider("ok"))
+	router.markFailure("primary", 0)
+	if router.allowRequest("primary") {
+		t.Fatal("allowRequest() = true while the circuit is open")
+	}
+
+	time.Sleep(15 * time.Millisecond)
+	if !router.allowRequest("primary") {
+		t.Fatal("allowRequest() = false after the cooldown, want the half-open probe")
+	}
+	if router.allowRequest("primary") {
+		t.Fatal("allowRequest() admitted a second request while the probe is in flight")
+	}
+	if !router.isCircuitOpen("primary") {
+		t.Fatal("isCircuitOpen() = false while the probe is in flight")
+	}
+
+	router.releaseProbe("primary")
+	if !router.allowRequest("primary") {
+		t.Fatal("allowRequest() = false after the probe was released")
+	}
+	if got := router.Breakers()[0].HalfOpened; got != 1 {
+		t.Fatalf("HalfOpened = %d, want 1 for one cooldown", got)
+	}
+}
+
+// cancellingProvider cancels the caller's context mid-request, as a learner
+// leaving or a request deadline would.
+type cancellingProvider struct {
+	*MockProvider
+	cancel context.CancelFunc
+}
+
+func (p cancellingProvider) Complete(ctx context.Context, _ CompletionRequest) (CompletionResponse, error) {
+	p.cancel()
+	return CompletionResponse{}, ctx.Err()
+}
+
+func TestCancelledProbeDoesNotReopenTheCircuit(t *testing.T) {
+	router := NewRouterWithConfig(RouterConfig{BreakerFailureThreshold: 1, BreakerCooldown: 10 * time.Millisecond})
+	ctx, cancel := context.WithCancel(context.Background())
+	router.Register("primary", cancellingProvider{MockProvider: NewMockProvider("ok"), cancel: cancel})
+	router.markFailure("primary", router.gen)
+	time.Sleep(15 * time.Millisecond)
+
+	_, err := router.Complete(ctx, CompletionRequest{Messages: []Message{{Role: "user", Content: "hi"}}})
+	if !errors.Is(err, context.Canceled) {
+		t.Fatalf("Complete() error = %v, want context.Canceled", err)
+	}
+	breaker := router.Breakers()[0]
+	if breaker.State != BreakerHalfOpen || breaker.Opened != 1 {
+		t.Fatalf("breaker = %+v, want still half-open after a cancelled probe", breaker)
+	}
+	if !router.allowRequest("primary") {
+		t.Fatal("allowRequest() = false, want the probe slot released")
+	}
+}
+
+func TestMalformedStructuredProbeClosesTheCircuit(t *testing.T) {
+	router := NewRouterWithConfig(RouterConfig{BreakerFailureThreshold: 1, BreakerCooldown: 10 * time.Millisecond})
+	router.Register("openai", NewMockProvider("not json"))
+	router.markFailure("openai", router.gen)
+	time.Sleep(15 * time.Millisecond)
+
+	var out map[string]any
+	_, err := router.CompleteJSON(context.Background(), CompletionRequest{
+		Messages: []Message{{Role: "user", Content: "grade this"}},
+		StructuredOutput: &StructuredOutputSpec{
+			Name:       "grading_result",
+			JSONSchema: json.RawMessage(`{"type":"object"}`),
+		},
+	}, &out)
+	if err == nil {
+		t.Fatal("CompleteJSON() error = nil, want the invalid payload reported")
+	}
+	if breaker := router.Breakers()[0]; breaker.State != BreakerClosed {
+		t.Fatalf("breaker = %+v, want closed after the probe's transport succeeded", breaker)
+	}
+	if !router.allowRequest("openai") {
+		t.Fatal("allowRequest() = false, want the probe slot released")
+	}
+}
+
+func TestBreakerStatsHandler(t *testing.T) {
+	router := NewRouterWithConfig(RouterConfig{BreakerFailureThreshold: 1, BreakerCooldown: time.Minute})
+	router.Register("openai", NewMockProvider("ok"))
+	router.markFailure("openai", router.gen)
+
+	rec := httptest.NewRecorder()
+	router.BreakerStatsHandler().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/", nil))
+	var got struct {
+		Providers []struct {
+			Provider string       `json:"provider"`
+			State    BreakerState `json:"state"`
+			Opened   int          `json:"opened"`
+		} `json:"providers"`
+	}
+	if err := json.Unmarshal(rec.Body.Bytes(), &got); err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+	if len(got.Providers) != 1 || got.Providers[0].Provider != "openai" || got.Providers[0].State != BreakerOpen || got.Providers[0].Opened != 1 {
+		t.Fatalf("stats = %+v", got)
+	}
+}
@@ -0,0 +1,125 @@
+// Copyright 2026 the P&AI authors. All rights reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package ai
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/p-n-ai/pai-bot/internal/apperr"
+)
+
+const defaultOpenAIEmbeddingModel = "text-embedding-3-small"
+
+// ErrNoEmbedder is returned by Router.Embed when no registered provider can
+// produce embeddings.
+var ErrNoEmbedder = apperr.Dependency(errors.New("no AI provider supports embeddings"))
+
+// Embedding is one embedding call's result. Vectors from different models are
+// not comparable, so callers store Model alongside each vector.
+type Embedding struct {
+	Model   string
+	Vectors [][]float32
+}
+
+// Embedder is implemented by providers that expose a text embedding API.
+type Embedder interface {
+	Embed(ctx context.Context, texts []string) (Embedding, error)
+}
+
+// Embed returns one vector per text from the first provider in fallback order
+// that supports embeddings and whose circuit is closed.
+func (r *Router) Embed(ctx context.Context, texts []string) (Embedding, error) {
+	providers, order, gen := r.snapshotProviders()
+	var failures []string
+	for _, name := range order {
+		embedder, ok := providers[name].(Embedder)
+		if !ok || !r.allowRequest(name) {
+			continue
+		}
+		out, err := embedder.Embed(ctx, texts)
+		if err != nil {
+			if ctxErr := ctx.Err(); ctxErr != nil {
+				r.releaseProbe(name)
+				return Embedding{}, ctxErr
+			}
+			r.markFailure(name, gen)
+			failures = append(failures, fmt.Sprintf("%s: %v", name, err))
+			continue
+		}
+		r.markSuccess(name, gen)
+		return out, nil
+	}
+	if len(failures) == 0 {
+		return Embedding{}, ErrNoEmbedder
+	}
+	return Embedding{}, fmt.Errorf("all embedding providers failed: %s", strings.Join(failures, "; "))
+}
+
+type openaiEmbeddingRequest struct {
+	Model string   `json:"model"`
+	Input []string `json:"input"`
+}
+
+type openaiEmbeddingResponse struct {
+	Model string `json:"model"`
+	Data  []struct {
+		Index     int       `json:"index"`
+		Embedding []float32 `json:"embedding"`
+	} `json:"data"`
+}
+
+// Embed calls the OpenAI embeddings API. Only the direct OpenAI provider
+// implements it; OpenAI-compatible hosts rarely serve the same models.
+func (p *directOpenAIProvider) Embed(ctx context.Context, texts []string) (Embedding, error) {
+	body, err := json.Marshal(openaiEmbeddingRequest{Model: defaultOpenAIEmbeddingModel, Input: texts})
+	if err != nil {
+		return Embedding{}, fmt.Errorf("marshal embedding request: %w", err)
+	}
+	httpReq, err := http.NewRequestWithContext(ctx, http.MethodPost, strings.TrimRight(p.baseURL, "/")+"/embeddings", bytes.NewReader(body))
+	if err != nil {
+		return Embedding{}, fmt.Errorf("create embedding request: %w", err)
+	}
+	httpReq.Header.Set("Content-Type", "application/json")
+	httpReq.Header.Set("Authorization", "Bearer "+p.apiKey)
+
+	resp, err := p.client.Do(httpReq)
+	if err != nil {
+		return Embedding{}, fmt.Errorf("send embedding request: %w", err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+	respBody, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return Embedding{}, fmt.Errorf("read embedding response: %w", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return Embedding{}, apperr.HTTPStatus(resp.StatusCode, fmt.Errorf("openai embeddings error (status %d): %s", resp.StatusCode, string(respBody)))
+	}
+
+	var parsed openaiEmbeddingResponse
+	if err := json.Unmarshal(respBody, &parsed); err != nil {
+		return Embedding{}, fmt.Errorf("unmarshal embedding response: %w", err)
+	}
+	if len(parsed.Data) != len(texts) {
+		return Embedding{}, fmt.Errorf("embedding response has %d vectors for %d inputs", len(parsed.Data), len(texts))
+	}
+	vectors := make([][]float32, len(texts))
+	for _, d := range parsed.Data {
+		if d.Index < 0 || d.Index >= len(vectors) {
+			return Embedding{}, fmt.Errorf("embedding response index %d out of range", d.Index)
+		}
+		vectors[d.Index] = d.Embedding
+	}
+	model := parsed.Model
+	if model == "" {
+		model = defaultOpenAIEmbeddingModel
+	}
+	return Embedding{Model: model, Vectors: vectors}, nil
+}
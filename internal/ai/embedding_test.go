@@ -0,0 +1,48 @@
+// Copyright 2026 the P&AI authors. All rights reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package ai
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestOpenAIProvider_Embed(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/embeddings" {
+			t.Errorf("unexpected path: %s", r.URL.Path)
+		}
+		var req openaiEmbeddingRequest
+		_ = json.NewDecoder(r.Body).Decode(&req)
+		if req.Model != defaultOpenAIEmbeddingModel || len(req.Input) != 2 {
+			t.Errorf("unexpected request: %+v", req)
+		}
+		// Out-of-order indexes must still line up with the inputs.
+		_, _ = w.Write([]byte(`{"model":"text-embedding-3-small","data":[{"index":1,"embedding":[0,1]},{"index":0,"embedding":[1,0]}]}`))
+	}))
+	defer server.Close()
+
+	router := NewRouter()
+	router.Register("openai", NewOpenAIProvider("test-key", WithBaseURL(server.URL)))
+	got, err := router.Embed(context.Background(), []string{"pecahan", "algebra"})
+	if err != nil {
+		t.Fatalf("Embed() error = %v", err)
+	}
+	if got.Model != "text-embedding-3-small" || got.Vectors[0][0] != 1 || got.Vectors[1][1] != 1 {
+		t.Fatalf("Embed() = %+v, want vectors in input order", got)
+	}
+}
+
+func TestRouter_EmbedWithoutEmbedder(t *testing.T) {
+	router := NewRouter()
+	router.Register("mock", NewMockProvider("hi"))
+	router.Register("deepseek", NewDeepSeekProvider("test-key"))
+	if _, err := router.Embed(context.Background(), []string{"x"}); !errors.Is(err, ErrNoEmbedder) {
+		t.Fatalf("Embed() error = %v, want ErrNoEmbedder", err)
+	}
+}
@@ -0,0 +1,134 @@
+// Copyright 2026 the P&AI authors. All rights reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package ai
+
+import (
+	"context"
+	"errors"
+	"math/rand/v2"
+	"time"
+
+	"github.com/p-n-ai/pai-bot/internal/apperr"
+	"github.com/p-n-ai/pai-bot/internal/llm"
+)
+
+// ErrInjectedFault marks a failure produced by the fault layer, not a real provider.
+var ErrInjectedFault = apperr.Dependency(errors.New("injected provider fault"))
+
+// FaultConfig sets per-call probabilities (0..1) for staged provider faults.
+// The zero value injects nothing.
+type FaultConfig struct {
+	ErrorRate     float64
+	SlowRate      float64
+	SlowDelay     time.Duration
+	MalformedRate float64
+}
+
+// Enabled reports whether any fault has a positive rate.
+func (c FaultConfig) Enabled() bool {
+	return c.ErrorRate > 0 || c.SlowRate > 0 || c.MalformedRate > 0
+}
+
+// WithFaults wraps provider so calls fail, stall, or return malformed output at
+// the configured rates. Native tool-call support is preserved when present.
+func WithFaults(provider Provider, cfg FaultConfig) Provider {
+	if provider == nil || !cfg.Enabled() {
+		return provider
+	}
+	base := &faultProvider{inner: provider, cfg: cfg, roll: rand.Float64}
+	if native, ok := provider.(NativeProvider); ok {
+		return &faultNativeProvider{faultProvider: base, native: native}
+	}
+	return base
+}
+
+type faultProvider struct {
+	inner Provider
+	cfg   FaultConfig
+	roll  func() float64
+}
+
+type faultNativeProvider struct {
+	*faultProvider
+	native NativeProvider
+}
+
+var _ NativeProvider = (*faultNativeProvider)(nil)
+
+func (p *faultProvider) Complete(ctx context.Context, req CompletionRequest) (CompletionResponse, error) {
+	if err := p.before(ctx); err != nil {
+		return CompletionResponse{}, err
+	}
+	resp, err := p.inner.Complete(ctx, req)
+	if err != nil {
+		return resp, err
+	}
+	if p.hit(p.cfg.MalformedRate) {
+		resp.Content = malformContent(resp.Content)
+		if len(resp.StructuredOutput) > 0 {
+			resp.StructuredOutput = []byte(malformContent(string(resp.StructuredOutput)))
+		}
+	}
+	return resp, nil
+}
+
+func (p *faultProvider) StreamComplete(ctx context.Context, req CompletionRequest) (<-chan StreamChunk, error) {
+	if err := p.before(ctx); err != nil {
+		return nil, err
+	}
+	return p.inner.StreamComplete(ctx, req)
+}
+
+func (p *faultProvider) Models() []ModelInfo {
+	return p.inner.Models()
+}
+
+func (p *faultProvider) HealthCheck(ctx context.Context) error {
+	return p.inner.HealthCheck(ctx)
+}
+
+func (p *faultProvider) RefreshModels(ctx context.Context) error {
+	if refresher, ok := p.inner.(ModelRefresher); ok {
+		return refresher.RefreshModels(ctx)
+	}
+	return nil
+}
+
+func (p *faultNativeProvider) CompleteNative(ctx context.Context, model string, c llm.Context, opts *llm.StreamOptions) (llm.AssistantMessage, error) {
+	if err := p.before(ctx); err != nil {
+		return llm.AssistantMessage{}, err
+	}
+	return p.native.CompleteNative(ctx, model, c, opts)
+}
+
+func (p *faultProvider) before(ctx context.Context) error {
+	if p.hit(p.cfg.SlowRate) && p.cfg.SlowDelay > 0 {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(p.cfg.SlowDelay):
+		}
+	}
+	if p.hit(p.cfg.ErrorRate) {
+		return ErrInjectedFault
+	}
+	return nil
+}
+
+func (p *faultProvider) hit(rate float64) bool {
+	if rate <= 0 {
+		return false
+	}
+	return rate >= 1 || p.roll() < rate
+}
+
+// malformContent cuts the payload mid-way so JSON parsing and truncation
+// handling are exercised the same way a dropped stream would.
+func malformContent(content string) string {
+	runes := []rune(content)
+	if len(runes) < 2 {
+		return "{"
+	}
+	return string(runes[:len(runes)/2])
+}
@@ -0,0 +1,69 @@
+// Copyright 2026 the P&AI authors. All rights reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package ai_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/p-n-ai/pai-bot/internal/ai"
+)
+
+func TestWithFaults_DisabledReturnsProvider(t *testing.T) {
+	mock := ai.NewMockProvider("ok")
+	if got := ai.WithFaults(mock, ai.FaultConfig{}); got != ai.Provider(mock) {
+		t.Fatalf("WithFaults(zero config) = %T, want original provider", got)
+	}
+}
+
+func TestWithFaults_ErrorRateFallsBackToNextProvider(t *testing.T) {
+	router := newTestRouter()
+	router.Register("openai", ai.WithFaults(ai.NewMockProvider("primary"), ai.FaultConfig{ErrorRate: 1}))
+	router.Register("ollama", ai.NewMockProvider("fallback"))
+
+	resp, err := router.Complete(context.Background(), ai.CompletionRequest{
+		Messages: []ai.Message{{Role: "user", Content: "hi"}},
+	})
+	if err != nil {
+		t.Fatalf("Complete() error = %v", err)
+	}
+	if resp.Content != "fallback" {
+		t.Fatalf("Content = %q, want fallback", resp.Content)
+	}
+}
+
+func TestWithFaults_ErrorIsInjectedFault(t *testing.T) {
+	provider := ai.WithFaults(ai.NewMockProvider("ok"), ai.FaultConfig{ErrorRate: 1})
+
+	_, err := provider.Complete(context.Background(), ai.CompletionRequest{})
+	if !errors.Is(err, ai.ErrInjectedFault) {
+		t.Fatalf("Complete() error = %v, want ErrInjectedFault", err)
+	}
+}
+
+func TestWithFaults_SlowHonorsContext(t *testing.T) {
+	provider := ai.WithFaults(ai.NewMockProvider("ok"), ai.FaultConfig{SlowRate: 1, SlowDelay: time.Minute})
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Millisecond)
+	defer cancel()
+
+	_, err := provider.Complete(ctx, ai.CompletionRequest{})
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("Complete() error = %v, want deadline exceeded", err)
+	}
+}
+
+func TestWithFaults_MalformedBreaksJSON(t *testing.T) {
+	provider := ai.WithFaults(ai.NewMockProvider(`{"answer":"42"}`), ai.FaultConfig{MalformedRate: 1})
+
+	resp, err := provider.Complete(context.Background(), ai.CompletionRequest{})
+	if err != nil {
+		t.Fatalf("Complete() error = %v", err)
+	}
+	if json.Valid([]byte(resp.Content)) {
+		t.Fatalf("Content = %q, want malformed JSON", resp.Content)
+	}
+}
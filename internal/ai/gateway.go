@@ -6,7 +6,10 @@ package ai
 
 import (
 	"context"
+	"crypto/sha256"
+	"encoding/hex"
 	"encoding/json"
+	"strings"
 	"time"
 )
 
@@ -35,29 +38,134 @@ func (t TaskType) String() string {
 	}
 }
 
-// Message represents a chat message.
+// ParseTaskType maps a task name such as "nudge" back to its TaskType.
+func ParseTaskType(name string) (TaskType, bool) {
+	for _, task := range []TaskType{TaskTeaching, TaskGrading, TaskNudge, TaskAnalysis} {
+		if strings.EqualFold(strings.TrimSpace(name), task.String()) {
+			return task, true
+		}
+	}
+	return 0, false
+}
+
+// Message represents a chat message. An assistant message may carry the
+// ToolCalls the model made; each result comes back as a "tool" message with
+// ToolCallID and ToolName set and the result text in Content.
 type Message struct {
-	Role      string   `json:"role"`
-	Content   string   `json:"content"`
-	ImageURLs []string `json:"image_urls,omitempty"`
+	Role       string     `json:"role"`
+	Content    string     `json:"content"`
+	ImageURLs  []string   `json:"image_urls,omitempty"`
+	ToolCalls  []ToolCall `json:"tool_calls,omitempty"`
+	ToolCallID string     `json:"tool_call_id,omitempty"`
+	ToolName   string     `json:"tool_name,omitempty"`
+	// CacheBreakpoint marks the end of a prompt prefix that repeats across
+	// requests, such as the system prompt with its teaching notes. Anthropic
+	// caches everything up to here; other providers cache prefixes on their
+	// own and ignore it.
+	CacheBreakpoint bool `json:"cache_breakpoint,omitempty"`
+}
+
+// ToolDefinition describes a function the model may call during a
+// completion. Parameters is a JSON schema for the arguments object.
+type ToolDefinition struct {
+	Name        string          `json:"name"`
+	Description string          `json:"description"`
+	Parameters  json.RawMessage `json:"parameters"`
+}
+
+// ToolCall is one function call requested by the model. Arguments is a JSON
+// object. Signature is an opaque provider token (Gemini's thought signature)
+// that must be sent back unchanged with the call on the next turn.
+type ToolCall struct {
+	ID        string          `json:"id"`
+	Name      string          `json:"name"`
+	Arguments json.RawMessage `json:"arguments"`
+	Signature string          `json:"signature,omitempty"`
 }
 
 // StructuredOutputSpec requests a structured response conforming to a JSON schema.
-// Providers may enforce this natively (preferred) or best-effort via instructions.
+// Providers may enforce this natively (preferred), by forcing a tool call whose
+// input is the schema (older Anthropic models), or best-effort via instructions.
 type StructuredOutputSpec struct {
 	Name       string          `json:"name"`
 	JSONSchema json.RawMessage `json:"json_schema"`
 	Strict     bool            `json:"strict,omitempty"`
 }
 
+// RequestMetadata attributes a completion to a tenant, learner and turn. It is
+// forwarded to providers that accept user or metadata fields and attached to
+// router logs so abuse reports can be traced back without raw user IDs.
+// Tier picks the learner's subscription route table and is never sent to
+// providers.
+type RequestMetadata struct {
+	Tenant         string `json:"tenant,omitempty"`
+	UserHash       string `json:"user_hash,omitempty"`
+	ConversationID string `json:"conversation_id,omitempty"`
+	TurnID         string `json:"turn_id,omitempty"`
+	Tier           string `json:"tier,omitempty"`
+}
+
+// HashUser returns a stable, non-reversible identifier for a tenant's user,
+// suitable for sending to providers.
+func HashUser(tenant, userID string) string {
+	if userID == "" {
+		return ""
+	}
+	sum := sha256.Sum256([]byte(tenant + ":" + userID))
+	return hex.EncodeToString(sum[:12])
+}
+
+// Map returns the non-empty metadata fields keyed by their JSON names.
+func (m RequestMetadata) Map() map[string]string {
+	out := make(map[string]string, 4)
+	for key, value := range map[string]string{
+		"tenant":          m.Tenant,
+		"user_hash":       m.UserHash,
+		"conversation_id": m.ConversationID,
+		"turn_id":         m.TurnID,
+	} {
+		if value != "" {
+			out[key] = value
+		}
+	}
+	if len(out) == 0 {
+		return nil
+	}
+	return out
+}
+
+func (m RequestMetadata) logAttrs() []any {
+	var attrs []any
+	if m.Tenant != "" {
+		attrs = append(attrs, "tenant", m.Tenant)
+	}
+	if m.UserHash != "" {
+		attrs = append(attrs, "user_hash", m.UserHash)
+	}
+	if m.ConversationID != "" {
+		attrs = append(attrs, "conversation_id", m.ConversationID)
+	}
+	if m.TurnID != "" {
+		attrs = append(attrs, "turn_id", m.TurnID)
+	}
+	if m.Tier != "" {
+		attrs = append(attrs, "tier", m.Tier)
+	}
+	return attrs
+}
+
 // CompletionRequest is the input to an AI completion.
 type CompletionRequest struct {
+	RequestMetadata
 	Messages         []Message             `json:"messages"`
 	StructuredOutput *StructuredOutputSpec `json:"structured_output,omitempty"`
 	Model            string                `json:"model,omitempty"`
 	MaxTokens        int                   `json:"max_tokens,omitempty"`
 	Temperature      float64               `json:"temperature,omitempty"`
 	Task             TaskType              `json:"task,omitempty"`
+	// Tools the model may call. OpenAI, Anthropic and Gemini encode them in
+	// Complete; streams carry text only.
+	Tools []ToolDefinition `json:"tools,omitempty"`
 }
 
 // CompletionResponse is the output from an AI completion.
@@ -67,6 +175,25 @@ type CompletionResponse struct {
 	Model            string          `json:"model"`
 	InputTokens      int             `json:"input_tokens"`
 	OutputTokens     int             `json:"output_tokens"`
+	// CacheReadTokens and CacheWriteTokens are the parts of InputTokens
+	// read from and written to the provider's prompt cache, when it reports
+	// them.
+	CacheReadTokens  int `json:"cache_read_tokens,omitempty"`
+	CacheWriteTokens int `json:"cache_write_tokens,omitempty"`
+	// CostUSD is what the call cost: the provider's own figure when it
+	// reports one, otherwise priced from the router's model price table.
+	// Zero when the model has no known price.
+	CostUSD float64 `json:"cost_usd,omitempty"`
+	// Truncated reports that the provider stopped at the token limit, so
+	// Content may end mid-sentence.
+	Truncated bool `json:"truncated,omitempty"`
+	// ToolCalls are the functions the model wants run before it answers.
+	// Send them back on an assistant message, followed by one "tool"
+	// message per result.
+	ToolCalls []ToolCall `json:"tool_calls,omitempty"`
+	// Cached reports that the router answered from its response cache
+	// without calling a provider.
+	Cached bool `json:"cached,omitempty"`
 }
 
 // TotalTokens returns the sum of input and output tokens.
@@ -84,11 +211,19 @@ type CompletionTrace struct {
 	CompletedAt time.Time           `json:"completed_at"`
 }
 
-// StreamChunk represents a streaming response chunk.
+// StreamChunk represents a streaming response chunk. The Done chunk carries
+// the model and token usage when the provider reports them; from
+// Router.StreamComplete it also carries CostUSD, priced as Complete prices a
+// response.
 type StreamChunk struct {
-	Content string
-	Done    bool
-	Error   error
+	Content      string
+	Done         bool
+	Error        error
+	Model        string
+	InputTokens  int
+	OutputTokens int
+	CostUSD      float64
+	Truncated    bool
 }
 
 // ModelInfo describes an available model.
@@ -97,6 +232,9 @@ type ModelInfo struct {
 	Name        string `json:"name"`
 	MaxTokens   int    `json:"max_tokens"`
 	Description string `json:"description"`
+	// Price is the provider's live list price, for catalogs fetched from
+	// the provider. Nil leaves pricing to the router's price table.
+	Price *ModelPrice `json:"price,omitempty"`
 }
 
 // Provider is the interface all AI providers must implement.
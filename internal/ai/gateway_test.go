@@ -5,6 +5,7 @@ package ai_test
 
 import (
 	"context"
+	"errors"
 	"testing"
 
 	"github.com/p-n-ai/pai-bot/internal/ai"
@@ -44,6 +45,62 @@ func TestMockProvider_Models(t *testing.T) {
 	}
 }
 
+func TestMockProvider_ScriptedConversation(t *testing.T) {
+	errTimeout := errors.New("upstream timeout")
+	mock := ai.NewMockProvider("fallback").
+		Queue("Let x be the unknown.", "So x = 4.").
+		RespondTo(ai.TaskAnalysis, "Summary: solving 2x = 8.").
+		FailOn(2, errTimeout)
+
+	calls := []struct {
+		task    ai.TaskType
+		want    string
+		wantErr error
+	}{
+		{ai.TaskTeaching, "Let x be the unknown.", nil},
+		{ai.TaskTeaching, "", errTimeout},
+		{ai.TaskAnalysis, "Summary: solving 2x = 8.", nil},
+		{ai.TaskTeaching, "So x = 4.", nil},
+		{ai.TaskTeaching, "fallback", nil},
+	}
+	for i, call := range calls {
+		req := ai.CompletionRequest{Task: call.task, Messages: []ai.Message{{Role: "user", Content: "2x = 8"}}}
+		var (
+			got string
+			err error
+		)
+		if i == 3 {
+			// Streams follow the same script.
+			var stream <-chan ai.StreamChunk
+			if stream, err = mock.StreamComplete(context.Background(), req); err == nil {
+				for chunk := range stream {
+					got += chunk.Content
+				}
+			}
+		} else {
+			var resp ai.CompletionResponse
+			resp, err = mock.Complete(context.Background(), req)
+			got = resp.Content
+		}
+		if !errors.Is(err, call.wantErr) || got != call.want {
+			t.Fatalf("call %d = %q, %v; want %q, %v", i+1, got, err, call.want, call.wantErr)
+		}
+	}
+
+	requests := mock.Requests()
+	if mock.Calls() != len(calls) || len(requests) != len(calls) {
+		t.Fatalf("Calls() = %d, len(Requests()) = %d, want %d", mock.Calls(), len(requests), len(calls))
+	}
+	for i, req := range requests {
+		if req.Task != calls[i].task {
+			t.Fatalf("request %d task = %s, want %s", i+1, req.Task, calls[i].task)
+		}
+	}
+	if mock.LastRequest == nil || mock.LastRequest.Task != ai.TaskTeaching {
+		t.Fatalf("LastRequest = %+v, want the last teaching request", mock.LastRequest)
+	}
+}
+
 func TestTaskType_String(t *testing.T) {
 	tests := []struct {
 		task     ai.TaskType
@@ -0,0 +1,117 @@
+// Copyright 2026 the P&AI authors. All rights reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package ai
+
+import (
+	"strings"
+
+	"github.com/p-n-ai/pai-bot/internal/llm"
+)
+
+// GenerationDefaults are the output cap and sampling temperature a task's
+// requests get when the caller leaves them unset. A zero field leaves the
+// choice to the provider.
+type GenerationDefaults struct {
+	MaxTokens   int
+	Temperature float64
+}
+
+// builtinGenerationDefaults apply when config sets nothing for a task:
+// teaching answers and conversation summaries.
+var builtinGenerationDefaults = map[TaskType]GenerationDefaults{
+	TaskTeaching: {MaxTokens: 1024},
+	TaskAnalysis: {MaxTokens: 256},
+}
+
+// overlay returns d with o's non-zero fields on top.
+func (d GenerationDefaults) overlay(o GenerationDefaults) GenerationDefaults {
+	if o.MaxTokens > 0 {
+		d.MaxTokens = o.MaxTokens
+	}
+	if o.Temperature > 0 {
+		d.Temperature = o.Temperature
+	}
+	return d
+}
+
+// SetGenerationDefaults replaces the per-task defaults and each tenant's
+// overrides, keyed by the tenant in RequestMetadata. Fields left zero fall
+// through: a tenant override to the task default, and that to the built-in
+// one.
+func (r *Router) SetGenerationDefaults(tasks map[TaskType]GenerationDefaults, tenants map[string]map[TaskType]GenerationDefaults) {
+	taskTable := make(map[TaskType]GenerationDefaults, len(tasks))
+	for task, defaults := range tasks {
+		taskTable[task] = defaults
+	}
+	tenantTable := make(map[string]map[TaskType]GenerationDefaults, len(tenants))
+	for tenant, overrides := range tenants {
+		tenant = strings.TrimSpace(tenant)
+		if tenant == "" || len(overrides) == 0 {
+			continue
+		}
+		table := make(map[TaskType]GenerationDefaults, len(overrides))
+		for task, defaults := range overrides {
+			table[task] = defaults
+		}
+		tenantTable[tenant] = table
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.generationDefaults = taskTable
+	r.tenantGenerationDefaults = tenantTable
+}
+
+// GenerationDefaults returns the defaults a request from tenant for task
+// gets. A nil router returns the built-in defaults.
+func (r *Router) GenerationDefaults(tenant string, task TaskType) GenerationDefaults {
+	defaults := builtinGenerationDefaults[task]
+	if r == nil {
+		return defaults
+	}
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	defaults = defaults.overlay(r.generationDefaults[task])
+	if tenant = strings.TrimSpace(tenant); tenant != "" {
+		defaults = defaults.overlay(r.tenantGenerationDefaults[tenant][task])
+	}
+	return defaults
+}
+
+// withGenerationDefaults fills the output cap and temperature req leaves
+// unset.
+func (r *Router) withGenerationDefaults(req CompletionRequest) CompletionRequest {
+	if req.MaxTokens > 0 && req.Temperature > 0 {
+		return req
+	}
+	defaults := r.GenerationDefaults(req.Tenant, req.Task)
+	if req.MaxTokens <= 0 {
+		req.MaxTokens = defaults.MaxTokens
+	}
+	if req.Temperature <= 0 {
+		req.Temperature = defaults.Temperature
+	}
+	return req
+}
+
+// nativeGenerationOptions is withGenerationDefaults for native calls. It
+// copies opts rather than changing the caller's.
+func (r *Router) nativeGenerationOptions(config NativeModelConfig, opts *llm.StreamOptions) *llm.StreamOptions {
+	defaults := r.GenerationDefaults(config.Metadata.Tenant, config.Task)
+	var out llm.StreamOptions
+	if opts != nil {
+		out = *opts
+	}
+	if out.MaxTokens <= 0 && defaults.MaxTokens > 0 {
+		out.MaxTokens = defaults.MaxTokens
+	}
+	if out.Temperature == nil && defaults.Temperature > 0 {
+		temperature := defaults.Temperature
+		out.Temperature = &temperature
+	}
+	if opts == nil && out.MaxTokens == 0 && out.Temperature == nil {
+		return nil
+	}
+	return &out
+}
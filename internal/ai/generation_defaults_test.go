@@ -0,0 +1,51 @@
+// Copyright 2026 the P&AI authors. All rights reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package ai_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/p-n-ai/pai-bot/internal/ai"
+)
+
+func TestRouter_GenerationDefaultsFillUnsetFields(t *testing.T) {
+	router := newTestRouter()
+	provider := ai.NewMockProvider("ok")
+	router.Register("mock", provider)
+
+	// Built-in defaults apply before any are configured.
+	if _, err := router.Complete(context.Background(), ai.CompletionRequest{Task: ai.TaskTeaching}); err != nil {
+		t.Fatalf("Complete() error = %v", err)
+	}
+	if got := provider.LastRequest.MaxTokens; got != 1024 {
+		t.Fatalf("teaching MaxTokens = %d, want the built-in 1024", got)
+	}
+
+	router.SetGenerationDefaults(
+		map[ai.TaskType]ai.GenerationDefaults{ai.TaskNudge: {MaxTokens: 80, Temperature: 0.9}},
+		map[string]map[ai.TaskType]ai.GenerationDefaults{"school-a": {ai.TaskNudge: {Temperature: 0.4}}},
+	)
+	tests := []struct {
+		name string
+		req  ai.CompletionRequest
+		want ai.GenerationDefaults
+	}{
+		{name: "task default", req: ai.CompletionRequest{Task: ai.TaskNudge}, want: ai.GenerationDefaults{MaxTokens: 80, Temperature: 0.9}},
+		{name: "tenant override", req: ai.CompletionRequest{RequestMetadata: ai.RequestMetadata{Tenant: "school-a"}, Task: ai.TaskNudge}, want: ai.GenerationDefaults{MaxTokens: 80, Temperature: 0.4}},
+		{name: "caller wins", req: ai.CompletionRequest{Task: ai.TaskNudge, MaxTokens: 60, Temperature: 0.2}, want: ai.GenerationDefaults{MaxTokens: 60, Temperature: 0.2}},
+		{name: "no default", req: ai.CompletionRequest{Task: ai.TaskGrading}, want: ai.GenerationDefaults{}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := router.Complete(context.Background(), tt.req); err != nil {
+				t.Fatalf("Complete() error = %v", err)
+			}
+			got := ai.GenerationDefaults{MaxTokens: provider.LastRequest.MaxTokens, Temperature: provider.LastRequest.Temperature}
+			if got != tt.want {
+				t.Fatalf("sent %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,108 @@
+// Copyright 2026 the P&AI authors. All rights reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package ai
+
+import (
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"time"
+)
+
+// HTTPClientConfig tunes the HTTP client providers share.
+type HTTPClientConfig struct {
+	// Timeout bounds a whole request, including reading a streamed reply.
+	Timeout time.Duration
+	// ResponseHeaderTimeout bounds the wait for the first response byte, so
+	// a hung connection fails fast even when Timeout is long.
+	ResponseHeaderTimeout time.Duration
+	DialTimeout           time.Duration
+	TLSHandshakeTimeout   time.Duration
+	IdleConnTimeout       time.Duration
+	MaxIdleConnsPerHost   int
+	// ProxyURL routes provider traffic through a proxy. Empty uses the
+	// HTTP_PROXY/HTTPS_PROXY environment.
+	ProxyURL string
+	// Retry retries transient provider errors. Unlike the other fields, its
+	// zero value disables retries rather than taking a default.
+	Retry RetryConfig
+}
+
+// DefaultHTTPClientConfig returns timeouts that fit a long teaching reply but
+// still give up on a provider that stops responding.
+func DefaultHTTPClientConfig() HTTPClientConfig {
+	return HTTPClientConfig{
+		Timeout:               120 * time.Second,
+		ResponseHeaderTimeout: 60 * time.Second,
+		DialTimeout:           10 * time.Second,
+		TLSHandshakeTimeout:   10 * time.Second,
+		IdleConnTimeout:       90 * time.Second,
+		MaxIdleConnsPerHost:   16,
+	}
+}
+
+// defaultHTTPClient is used by providers built without an explicit client.
+var defaultHTTPClient = mustHTTPClient(DefaultHTTPClientConfig())
+
+// NewHTTPClient builds a pooled HTTP/2-capable client from cfg. Zero fields
+// take their DefaultHTTPClientConfig value.
+func NewHTTPClient(cfg HTTPClientConfig) (*http.Client, error) {
+	def := DefaultHTTPClientConfig()
+	if cfg.Timeout <= 0 {
+		cfg.Timeout = def.Timeout
+	}
+	if cfg.ResponseHeaderTimeout <= 0 {
+		cfg.ResponseHeaderTimeout = def.ResponseHeaderTimeout
+	}
+	if cfg.DialTimeout <= 0 {
+		cfg.DialTimeout = def.DialTimeout
+	}
+	if cfg.TLSHandshakeTimeout <= 0 {
+		cfg.TLSHandshakeTimeout = def.TLSHandshakeTimeout
+	}
+	if cfg.IdleConnTimeout <= 0 {
+		cfg.IdleConnTimeout = def.IdleConnTimeout
+	}
+	if cfg.MaxIdleConnsPerHost <= 0 {
+		cfg.MaxIdleConnsPerHost = def.MaxIdleConnsPerHost
+	}
+
+	proxy := http.ProxyFromEnvironment
+	if cfg.ProxyURL != "" {
+		u, err := url.Parse(cfg.ProxyURL)
+		if err != nil || u.Scheme == "" || u.Host == "" {
+			return nil, fmt.Errorf("invalid AI proxy URL %q", cfg.ProxyURL)
+		}
+		proxy = http.ProxyURL(u)
+	}
+
+	transport := &http.Transport{
+		Proxy: proxy,
+		DialContext: (&net.Dialer{
+			Timeout:   cfg.DialTimeout,
+			KeepAlive: 30 * time.Second,
+		}).DialContext,
+		ForceAttemptHTTP2:     true,
+		MaxIdleConns:          cfg.MaxIdleConnsPerHost * 4,
+		MaxIdleConnsPerHost:   cfg.MaxIdleConnsPerHost,
+		IdleConnTimeout:       cfg.IdleConnTimeout,
+		TLSHandshakeTimeout:   cfg.TLSHandshakeTimeout,
+		ResponseHeaderTimeout: cfg.ResponseHeaderTimeout,
+		ExpectContinueTimeout: time.Second,
+	}
+	client := &http.Client{Transport: transport, Timeout: cfg.Timeout}
+	if cfg.Retry.MaxRetries > 0 {
+		client.Transport = newRetryTransport(transport, cfg.Retry)
+	}
+	return client, nil
+}
+
+func mustHTTPClient(cfg HTTPClientConfig) *http.Client {
+	client, err := NewHTTPClient(cfg)
+	if err != nil {
+		panic(err)
+	}
+	return client
+}
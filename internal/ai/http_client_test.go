@@ -0,0 +1,76 @@
+// Copyright 2026 the P&AI authors. All rights reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package ai
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestNewHTTPClient_FillsDefaults(t *testing.T) {
+	client, err := NewHTTPClient(HTTPClientConfig{Timeout: 30 * time.Second})
+	if err != nil {
+		t.Fatalf("NewHTTPClient() error = %v", err)
+	}
+	if client.Timeout != 30*time.Second {
+		t.Fatalf("Timeout = %v, want 30s", client.Timeout)
+	}
+	transport := client.Transport.(*http.Transport)
+	def := DefaultHTTPClientConfig()
+	if transport.ResponseHeaderTimeout != def.ResponseHeaderTimeout {
+		t.Fatalf("ResponseHeaderTimeout = %v, want %v", transport.ResponseHeaderTimeout, def.ResponseHeaderTimeout)
+	}
+	if transport.MaxIdleConnsPerHost != def.MaxIdleConnsPerHost {
+		t.Fatalf("MaxIdleConnsPerHost = %d, want %d", transport.MaxIdleConnsPerHost, def.MaxIdleConnsPerHost)
+	}
+	if !transport.ForceAttemptHTTP2 {
+		t.Fatal("ForceAttemptHTTP2 = false, want true")
+	}
+}
+
+func TestNewHTTPClient_Proxy(t *testing.T) {
+	client, err := NewHTTPClient(HTTPClientConfig{ProxyURL: "http://proxy.internal:3128"})
+	if err != nil {
+		t.Fatalf("NewHTTPClient() error = %v", err)
+	}
+	req, _ := http.NewRequest(http.MethodGet, "https://api.openai.com/v1/chat/completions", nil)
+	proxy, err := client.Transport.(*http.Transport).Proxy(req)
+	if err != nil || proxy == nil || proxy.Host != "proxy.internal:3128" {
+		t.Fatalf("Proxy() = %v, %v, want proxy.internal:3128", proxy, err)
+	}
+
+	if _, err := NewHTTPClient(HTTPClientConfig{ProxyURL: "proxy.internal"}); err == nil {
+		t.Fatal("NewHTTPClient() with a proxy URL lacking a scheme should fail")
+	}
+}
+
+func TestNewHTTPClient_ResponseHeaderTimeout(t *testing.T) {
+	release := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		<-release
+	}))
+	defer server.Close()
+	defer close(release)
+
+	client, err := NewHTTPClient(HTTPClientConfig{ResponseHeaderTimeout: 50 * time.Millisecond})
+	if err != nil {
+		t.Fatalf("NewHTTPClient() error = %v", err)
+	}
+	provider := NewOpenAIProvider("sk-test", WithBaseURL(server.URL), WithHTTPClient(client))
+
+	start := time.Now()
+	_, err = provider.Complete(context.Background(), CompletionRequest{
+		Model:    "gpt-5.4",
+		Messages: []Message{{Role: "user", Content: "hi"}},
+	})
+	if err == nil {
+		t.Fatal("Complete() against a hung server should fail")
+	}
+	if elapsed := time.Since(start); elapsed > 5*time.Second {
+		t.Fatalf("Complete() took %v, want the header timeout to cut it short", elapsed)
+	}
+}
@@ -0,0 +1,146 @@
+// Copyright 2026 the P&AI authors. All rights reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package ai
+
+import (
+	"context"
+	"errors"
+	"strings"
+)
+
+// CompleteFunc serves one completion request.
+type CompleteFunc func(ctx context.Context, req CompletionRequest) (CompletionResponse, error)
+
+// Middleware wraps every completion the router serves, for concerns that
+// apply to all of them: budget checks, redaction, logging, metrics. It may
+// change the request before calling next, for example to lower MaxTokens,
+// refuse it by returning an error without calling next, or inspect and
+// change the response next returns.
+//
+// The request reaching a middleware already carries the task's generation
+// defaults. For CompleteJSON, the caller's out value is filled from the
+// provider's payload before next returns, so changing the response does not
+// change out. For StreamComplete, next returns once the stream has ended,
+// with the whole reply; it has already reached the caller, so changes to it
+// are lost. A response returned without calling next is streamed as one
+// chunk.
+type Middleware func(next CompleteFunc) CompleteFunc
+
+var errStreamReopened = errors.New("stream already opened; a middleware called next twice")
+
+// Use appends middleware to the router's chain. The first added runs
+// outermost, seeing the request first and the response last.
+func (r *Router) Use(middleware ...Middleware) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, mw := range middleware {
+		if mw != nil {
+			r.middleware = append(r.middleware, mw)
+		}
+	}
+}
+
+// withMiddleware wraps final in the router's current chain.
+func (r *Router) withMiddleware(final CompleteFunc) CompleteFunc {
+	r.mu.RLock()
+	chain := r.middleware
+	r.mu.RUnlock()
+	handler := final
+	for i := len(chain) - 1; i >= 0; i-- {
+		handler = chain[i](handler)
+	}
+	return handler
+}
+
+type openedStream struct {
+	stream <-chan StreamChunk
+	err    error
+}
+
+// streamWithMiddleware runs the chain around a stream. The chain runs in its
+// own goroutine: the innermost handler opens the stream, hands it to the
+// caller, relays it to the end, and returns the whole reply to the
+// middleware above it. The caller's stream closes once the chain returns,
+// so every middleware is done with the reply by then.
+func (r *Router) streamWithMiddleware(ctx context.Context, req CompletionRequest) (<-chan StreamChunk, error) {
+	opened := make(chan openedStream, 1)
+	var (
+		reached bool
+		out     chan StreamChunk
+	)
+	handler := r.withMiddleware(func(ctx context.Context, req CompletionRequest) (CompletionResponse, error) {
+		if reached {
+			return CompletionResponse{}, errStreamReopened
+		}
+		reached = true
+		stream, err := r.streamComplete(ctx, req)
+		if err != nil {
+			opened <- openedStream{err: err}
+			return CompletionResponse{}, err
+		}
+		out = make(chan StreamChunk)
+		opened <- openedStream{stream: out}
+		return relayStream(ctx, out, stream)
+	})
+
+	go func() {
+		resp, err := handler(ctx, req)
+		if reached {
+			if out != nil {
+				close(out)
+			}
+			return
+		}
+		// A middleware answered or refused without opening the stream.
+		if err != nil {
+			opened <- openedStream{err: err}
+			return
+		}
+		out = make(chan StreamChunk, 1)
+		out <- StreamChunk{
+			Content:      resp.Content,
+			Done:         true,
+			Model:        resp.Model,
+			InputTokens:  resp.InputTokens,
+			OutputTokens: resp.OutputTokens,
+			CostUSD:      resp.CostUSD,
+			Truncated:    resp.Truncated,
+		}
+		close(out)
+		opened <- openedStream{stream: out}
+	}()
+
+	result := <-opened
+	return result.stream, result.err
+}
+
+// relayStream copies stream to out and returns the reply it carried.
+func relayStream(ctx context.Context, out chan<- StreamChunk, stream <-chan StreamChunk) (CompletionResponse, error) {
+	var (
+		content strings.Builder
+		resp    CompletionResponse
+		err     error
+	)
+	for chunk := range stream {
+		content.WriteString(chunk.Content)
+		if chunk.Error != nil {
+			err = chunk.Error
+		}
+		if chunk.Done {
+			resp.Model = chunk.Model
+			resp.InputTokens = chunk.InputTokens
+			resp.OutputTokens = chunk.OutputTokens
+			resp.CostUSD = chunk.CostUSD
+			resp.Truncated = chunk.Truncated
+		}
+		select {
+		case out <- chunk:
+		case <-ctx.Done():
+			go drainStream(stream)
+			return CompletionResponse{}, ctx.Err()
+		}
+	}
+	resp.Content = content.String()
+	return resp, err
+}
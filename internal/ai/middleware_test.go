@@ -0,0 +1,142 @@
+// Copyright 2026 the P&AI authors. All rights reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package ai_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/p-n-ai/pai-bot/internal/ai"
+)
+
+// recordMiddleware appends name to calls around each request.
+func recordMiddleware(name string, calls *[]string) ai.Middleware {
+	return func(next ai.CompleteFunc) ai.CompleteFunc {
+		return func(ctx context.Context, req ai.CompletionRequest) (ai.CompletionResponse, error) {
+			*calls = append(*calls, name+" before")
+			resp, err := next(ctx, req)
+			*calls = append(*calls, name+" after")
+			return resp, err
+		}
+	}
+}
+
+func TestRouter_MiddlewareWrapsCompletions(t *testing.T) {
+	router := newTestRouter()
+	provider := ai.NewMockProvider("Divide both sides by 2.")
+	router.Register("mock", provider)
+
+	var calls []string
+	router.Use(recordMiddleware("outer", &calls), recordMiddleware("inner", &calls))
+	router.Use(func(next ai.CompleteFunc) ai.CompleteFunc {
+		return func(ctx context.Context, req ai.CompletionRequest) (ai.CompletionResponse, error) {
+			req.MaxTokens = min(req.MaxTokens, 300)
+			resp, err := next(ctx, req)
+			resp.Content = strings.ToUpper(resp.Content)
+			return resp, err
+		}
+	})
+
+	resp, err := router.Complete(context.Background(), ai.CompletionRequest{Task: ai.TaskTeaching})
+	if err != nil {
+		t.Fatalf("Complete() error = %v", err)
+	}
+	if want := []string{"outer before", "inner before", "inner after", "outer after"}; !slices.Equal(calls, want) {
+		t.Fatalf("calls = %v, want %v", calls, want)
+	}
+	if got := provider.LastRequest.MaxTokens; got != 300 {
+		t.Fatalf("MaxTokens = %d, want 300 from the middleware, lowered from the teaching default", got)
+	}
+	if resp.Content != "DIVIDE BOTH SIDES BY 2." {
+		t.Fatalf("Content = %q, want the middleware's change", resp.Content)
+	}
+}
+
+func TestRouter_MiddlewareCanRefuseRequests(t *testing.T) {
+	router := newTestRouter()
+	provider := ai.NewMockProvider("ok")
+	router.Register("mock", provider)
+	errOverBudget := errors.New("over budget")
+	router.Use(func(next ai.CompleteFunc) ai.CompleteFunc {
+		return func(ctx context.Context, req ai.CompletionRequest) (ai.CompletionResponse, error) {
+			if req.Tenant == "school-a" {
+				return ai.CompletionResponse{}, errOverBudget
+			}
+			return next(ctx, req)
+		}
+	})
+
+	req := ai.CompletionRequest{RequestMetadata: ai.RequestMetadata{Tenant: "school-a"}, Task: ai.TaskGrading}
+	if _, err := router.Complete(context.Background(), req); !errors.Is(err, errOverBudget) {
+		t.Fatalf("Complete() error = %v, want the middleware's", err)
+	}
+	jsonReq := req
+	jsonReq.StructuredOutput = &ai.StructuredOutputSpec{Name: "grade", JSONSchema: json.RawMessage(`{"type":"object"}`)}
+	var out map[string]any
+	if _, err := router.CompleteJSON(context.Background(), jsonReq, &out); !errors.Is(err, errOverBudget) {
+		t.Fatalf("CompleteJSON() error = %v, want the middleware's", err)
+	}
+	if _, err := router.StreamComplete(context.Background(), req); !errors.Is(err, errOverBudget) {
+		t.Fatalf("StreamComplete() error = %v, want the middleware's", err)
+	}
+	if provider.LastRequest != nil {
+		t.Fatal("provider was called for a refused request")
+	}
+}
+
+func TestRouter_MiddlewareSeesWholeStream(t *testing.T) {
+	router := newTestRouter()
+	router.Register("anthropic", &scriptedStreamProvider{chunks: []ai.StreamChunk{
+		{Content: "Hel"},
+		{Content: "lo"},
+		{Done: true, Model: "claude", InputTokens: 4, OutputTokens: 2},
+	}})
+	var seen ai.CompletionResponse
+	router.Use(func(next ai.CompleteFunc) ai.CompleteFunc {
+		return func(ctx context.Context, req ai.CompletionRequest) (ai.CompletionResponse, error) {
+			resp, err := next(ctx, req)
+			seen = resp
+			return resp, err
+		}
+	})
+
+	stream, err := router.StreamComplete(context.Background(), ai.CompletionRequest{Task: ai.TaskTeaching})
+	if err != nil {
+		t.Fatalf("StreamComplete() error = %v", err)
+	}
+	content, last := collectStream(t, stream)
+	if content != "Hello" || !last.Done {
+		t.Fatalf("stream = %q, last = %+v", content, last)
+	}
+	if seen.Content != "Hello" || seen.Model != "claude" || seen.OutputTokens != 2 {
+		t.Fatalf("middleware saw %+v, want the whole reply", seen)
+	}
+}
+
+func TestRouter_MiddlewareAnswerIsStreamed(t *testing.T) {
+	router := newTestRouter()
+	provider := &scriptedStreamProvider{}
+	router.Register("anthropic", provider)
+	router.Use(func(ai.CompleteFunc) ai.CompleteFunc {
+		return func(context.Context, ai.CompletionRequest) (ai.CompletionResponse, error) {
+			return ai.CompletionResponse{Content: "Canned answer.", Model: "canned"}, nil
+		}
+	})
+
+	stream, err := router.StreamComplete(context.Background(), ai.CompletionRequest{Task: ai.TaskTeaching})
+	if err != nil {
+		t.Fatalf("StreamComplete() error = %v", err)
+	}
+	content, last := collectStream(t, stream)
+	if content != "Canned answer." || !last.Done || last.Model != "canned" {
+		t.Fatalf("stream = %q, last = %+v", content, last)
+	}
+	if provider.calls != 0 {
+		t.Fatalf("provider streamed %d times, want 0", provider.calls)
+	}
+}
@@ -3,13 +3,34 @@
 
 package ai
 
-import "context"
+import (
+	"context"
+	"sync"
+)
 
-// MockProvider is a test double for AI providers.
+// MockProvider is a test double for AI providers. Without a script it
+// answers every call with Response. Queue, RespondTo and FailOn script
+// multi-turn conversations; each call takes the first of these that
+// applies:
+//
+//  1. the error FailOn set for this call number, then Err;
+//  2. the reply RespondTo set for the request's task;
+//  3. the next queued reply;
+//  4. Response.
+//
+// Every request is recorded, in order, for Requests.
 type MockProvider struct {
-	Response    string
-	Err         error
+	Response string
+	Err      error
+	// Truncated marks every response as cut off at the token limit.
+	Truncated   bool
 	LastRequest *CompletionRequest // captures the last request for inspection
+
+	mu       sync.Mutex
+	queue    []string
+	byTask   map[TaskType]string
+	failOn   map[int]error
+	requests []CompletionRequest
 }
 
 // NewMockProvider creates a MockProvider that returns the given response.
@@ -17,24 +38,107 @@ func NewMockProvider(response string) *MockProvider {
 	return &MockProvider{Response: response}
 }
 
-func (m *MockProvider) Complete(_ context.Context, req CompletionRequest) (CompletionResponse, error) {
+// Queue appends replies served one per call, in order, before falling back
+// to Response.
+func (m *MockProvider) Queue(replies ...string) *MockProvider {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.queue = append(m.queue, replies...)
+	return m
+}
+
+// RespondTo answers every request for task with reply. Such requests leave
+// the queue alone, so background calls such as conversation summaries do
+// not use up the replies queued for tutor turns.
+func (m *MockProvider) RespondTo(task TaskType, reply string) *MockProvider {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.byTask == nil {
+		m.byTask = make(map[TaskType]string)
+	}
+	m.byTask[task] = reply
+	return m
+}
+
+// FailOn makes the nth call, counting from 1, fail with err. The failed
+// call is recorded but takes nothing from the queue.
+func (m *MockProvider) FailOn(n int, err error) *MockProvider {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.failOn == nil {
+		m.failOn = make(map[int]error)
+	}
+	m.failOn[n] = err
+	return m
+}
+
+// Requests returns every request received so far, in order.
+func (m *MockProvider) Requests() []CompletionRequest {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return append([]CompletionRequest(nil), m.requests...)
+}
+
+// Calls returns how many requests have been received.
+func (m *MockProvider) Calls() int {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return len(m.requests)
+}
+
+// next records req and picks its reply.
+func (m *MockProvider) next(req CompletionRequest) (string, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.requests = append(m.requests, req)
 	m.LastRequest = &req
+	if err := m.failOn[len(m.requests)]; err != nil {
+		return "", err
+	}
 	if m.Err != nil {
-		return CompletionResponse{}, m.Err
+		return "", m.Err
+	}
+	if reply, ok := m.byTask[req.Task]; ok {
+		return reply, nil
+	}
+	if len(m.queue) > 0 {
+		reply := m.queue[0]
+		m.queue = m.queue[1:]
+		return reply, nil
+	}
+	return m.Response, nil
+}
+
+func (m *MockProvider) Complete(_ context.Context, req CompletionRequest) (CompletionResponse, error) {
+	reply, err := m.next(req)
+	if err != nil {
+		return CompletionResponse{}, err
 	}
 	return CompletionResponse{
-		Content:      m.Response,
+		Content:      reply,
 		Model:        "mock",
 		InputTokens:  10,
-		OutputTokens: len(m.Response),
+		OutputTokens: len(reply),
+		Truncated:    m.Truncated,
 	}, nil
 }
 
-func (m *MockProvider) StreamComplete(_ context.Context, _ CompletionRequest) (<-chan StreamChunk, error) {
+func (m *MockProvider) StreamComplete(_ context.Context, req CompletionRequest) (<-chan StreamChunk, error) {
+	reply, err := m.next(req)
+	if err != nil {
+		return nil, err
+	}
 	ch := make(chan StreamChunk, 1)
 	go func() {
 		defer close(ch)
-		ch <- StreamChunk{Content: m.Response, Done: true}
+		ch <- StreamChunk{
+			Content:      reply,
+			Done:         true,
+			Model:        "mock",
+			InputTokens:  10,
+			OutputTokens: len(reply),
+			Truncated:    m.Truncated,
+		}
 	}()
 	return ch, nil
 }
@@ -0,0 +1,55 @@
+// Copyright 2026 the P&AI authors. All rights reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package ai
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// ModelRefresher is implemented by providers whose model catalog is
+// fetched from the provider rather than built in.
+type ModelRefresher interface {
+	// RefreshModels replaces the cached catalog Models returns. On error
+	// the previous catalog stays.
+	RefreshModels(ctx context.Context) error
+}
+
+// RefreshModels refreshes every registered provider's live catalog, then
+// adds the prices they list to the price table, so cost tracking uses each
+// model's current price. Prices set with SetModelPrices still win. Each
+// provider is tried even if another fails; the errors are joined.
+func (r *Router) RefreshModels(ctx context.Context) error {
+	r.mu.RLock()
+	providers := make(map[string]Provider, len(r.providers))
+	for name, provider := range r.providers {
+		providers[name] = provider
+	}
+	r.mu.RUnlock()
+
+	var errs []error
+	discovered := make(map[string]ModelPrice)
+	for name, provider := range providers {
+		refresher, ok := provider.(ModelRefresher)
+		if !ok {
+			continue
+		}
+		if err := refresher.RefreshModels(ctx); err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", name, err))
+		}
+		for _, info := range provider.Models() {
+			if info.Price != nil {
+				discovered[strings.ToLower(info.ID)] = *info.Price
+			}
+		}
+	}
+
+	r.mu.Lock()
+	r.discoveredPrices = discovered
+	r.rebuildModelPricesLocked()
+	r.mu.Unlock()
+	return errors.Join(errs...)
+}
@@ -0,0 +1,40 @@
+// Copyright 2026 the P&AI authors. All rights reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package ai
+
+import (
+	"context"
+	"net/http"
+	"testing"
+)
+
+func TestRouterRefreshModelsPricesFromLiveCatalog(t *testing.T) {
+	status := http.StatusOK
+	server := newOpenRouterModelsServer(t, &status)
+	router := NewRouter()
+	router.Register("openrouter", newOpenRouterLLMAdapter("test-key", server.URL))
+	router.Register("mock", NewMockProvider("ok"))
+
+	if _, ok := router.ModelPrice("qwen/qwen3-max"); ok {
+		t.Fatal("qwen/qwen3-max priced before discovery")
+	}
+	if err := router.RefreshModels(context.Background()); err != nil {
+		t.Fatalf("RefreshModels() error = %v", err)
+	}
+	if price, ok := router.ModelPrice("qwen/qwen3-max"); !ok || price.OutputPerMTok != 6 {
+		t.Fatalf("ModelPrice(qwen/qwen3-max) = %+v, %v; want the live price", price, ok)
+	}
+	if price, ok := router.ModelPrice("openai/gpt-4o"); !ok || price.InputPerMTok != 2.50 {
+		t.Fatalf("ModelPrice(openai/gpt-4o) = %+v, %v; want the built-in price", price, ok)
+	}
+
+	// Configured prices win over discovered ones, whichever is set first.
+	router.SetModelPrices(map[string]ModelPrice{"qwen/qwen3-max": {InputPerMTok: 1, OutputPerMTok: 5}})
+	if err := router.RefreshModels(context.Background()); err != nil {
+		t.Fatalf("RefreshModels() error = %v", err)
+	}
+	if price, _ := router.ModelPrice("qwen/qwen3-max"); price.OutputPerMTok != 5 {
+		t.Fatalf("ModelPrice(qwen/qwen3-max) = %+v, want the configured price", price)
+	}
+}
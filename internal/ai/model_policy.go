@@ -0,0 +1,128 @@
+// Copyright 2026 the P&AI authors. All rights reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package ai
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+	"strings"
+
+	"github.com/p-n-ai/pai-bot/internal/apperr"
+)
+
+// ErrModelNotAllowed is returned when a tenant's model policy leaves no
+// provider and model to try for a request.
+var ErrModelNotAllowed = apperr.Permanent(errors.New("no AI model is allowed for this tenant"))
+
+// ModelPolicy limits the models one tenant's requests may use. Patterns match
+// model IDs case-insensitively, and a trailing "*" matches any suffix, e.g.
+// "claude-*". An empty Allow allows every model not denied; Deny wins over
+// Allow.
+type ModelPolicy struct {
+	Allow []string
+	Deny  []string
+}
+
+// Allows reports whether model may be used under p. Under an allowlist, an
+// unnamed model (the provider picks its own) is not allowed, since it cannot
+// be checked.
+func (p ModelPolicy) Allows(model string) bool {
+	model = strings.ToLower(strings.TrimSpace(model))
+	for _, pattern := range p.Deny {
+		if model != "" && matchModel(pattern, model) {
+			return false
+		}
+	}
+	if len(p.Allow) == 0 {
+		return true
+	}
+	for _, pattern := range p.Allow {
+		if model != "" && matchModel(pattern, model) {
+			return true
+		}
+	}
+	return false
+}
+
+func (p ModelPolicy) empty() bool {
+	return len(p.Allow) == 0 && len(p.Deny) == 0
+}
+
+func matchModel(pattern, model string) bool {
+	pattern = strings.ToLower(strings.TrimSpace(pattern))
+	if prefix, ok := strings.CutSuffix(pattern, "*"); ok {
+		return strings.HasPrefix(model, prefix)
+	}
+	return pattern == model
+}
+
+// SetTenantModelPolicies replaces every tenant's model policy, keyed by the
+// tenant in RequestMetadata. Tenants without a policy may use any model.
+func (r *Router) SetTenantModelPolicies(policies map[string]ModelPolicy) {
+	table := make(map[string]ModelPolicy, len(policies))
+	for tenant, policy := range policies {
+		tenant = strings.TrimSpace(tenant)
+		if tenant == "" || policy.empty() {
+			continue
+		}
+		table[tenant] = ModelPolicy{
+			Allow: append([]string(nil), policy.Allow...),
+			Deny:  append([]string(nil), policy.Deny...),
+		}
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.modelPolicies = table
+}
+
+// TenantModelPolicy returns tenant's model policy; ok is false when the
+// tenant has none.
+func (r *Router) TenantModelPolicy(tenant string) (ModelPolicy, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	policy, ok := r.modelPolicies[tenant]
+	return policy, ok
+}
+
+// requestPlan is taskPlan with the tenant's model policy applied before
+// unhealthy providers are skipped. A step whose model the policy rejects is
+// dropped, except that a requested model the tenant may not use falls back
+// to the step's own model when that one is allowed; such steps are pinned so
+// the request's model is not sent. When nothing is left the request fails
+// with ErrModelNotAllowed rather than reaching a disallowed model.
+func (r *Router) requestPlan(task TaskType, meta RequestMetadata, requested string, providers map[string]Provider, order []string) ([]routeStep, error) {
+	steps := r.routePlan(task, meta.Tier, providers, order)
+	policy, ok := r.TenantModelPolicy(meta.Tenant)
+	if !ok {
+		return r.skipUnhealthy(steps), nil
+	}
+
+	allowed := make([]routeStep, 0, len(steps))
+	seen := make(map[routeStep]bool, len(steps))
+	for _, step := range steps {
+		if !policy.Allows(step.modelFor(requested)) {
+			if step.pinned || !policy.Allows(step.model) {
+				continue
+			}
+			slog.Debug("AI model not allowed for tenant, using provider model",
+				"tenant", meta.Tenant,
+				"requested_model", requested,
+				"provider", step.provider,
+				"model", step.model,
+			)
+			step.pinned = true
+		}
+		if seen[step] {
+			continue
+		}
+		seen[step] = true
+		allowed = append(allowed, step)
+	}
+	if len(allowed) == 0 {
+		return nil, fmt.Errorf("%w: tenant %s, task %s", ErrModelNotAllowed, meta.Tenant, task)
+	}
+	return r.skipUnhealthy(allowed), nil
+}
@@ -0,0 +1,107 @@
+// Copyright 2026 the P&AI authors. All rights reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package ai_test
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"testing"
+
+	"github.com/p-n-ai/pai-bot/internal/ai"
+)
+
+func TestModelPolicy_Allows(t *testing.T) {
+	tests := []struct {
+		name   string
+		policy ai.ModelPolicy
+		model  string
+		want   bool
+	}{
+		{name: "no policy", model: "gpt-5.4", want: true},
+		{name: "allow exact", policy: ai.ModelPolicy{Allow: []string{"gpt-5.4-mini"}}, model: "GPT-5.4-mini", want: true},
+		{name: "allow prefix", policy: ai.ModelPolicy{Allow: []string{"claude-*"}}, model: "claude-sonnet", want: true},
+		{name: "not allowed", policy: ai.ModelPolicy{Allow: []string{"claude-*"}}, model: "gpt-5.4", want: false},
+		{name: "unnamed model under allowlist", policy: ai.ModelPolicy{Allow: []string{"*"}}, model: "", want: false},
+		{name: "denied", policy: ai.ModelPolicy{Deny: []string{"gpt-5.4"}}, model: "gpt-5.4", want: false},
+		{name: "deny is exact without star", policy: ai.ModelPolicy{Deny: []string{"gpt-5.4"}}, model: "gpt-5.4-mini", want: true},
+		{name: "deny wins over allow", policy: ai.ModelPolicy{Allow: []string{"gpt-*"}, Deny: []string{"gpt-5.4"}}, model: "gpt-5.4", want: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.policy.Allows(tt.model); got != tt.want {
+				t.Fatalf("Allows(%q) = %v, want %v", tt.model, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRouter_TenantModelPolicySkipsDisallowedModels(t *testing.T) {
+	router := newTestRouter()
+	strong := ai.NewMockProvider("strong")
+	cheap := ai.NewMockProvider("cheap")
+	router.ReplaceProviders([]ai.ProviderRegistration{
+		{Name: "anthropic", Provider: strong, DefaultModel: "claude-sonnet"},
+		{Name: "deepseek", Provider: cheap, DefaultModel: "deepseek-chat"},
+	})
+	router.SetTenantModelPolicies(map[string]ai.ModelPolicy{
+		"school-a": {Allow: []string{"deepseek-*"}},
+	})
+
+	var traced []string
+	router.SetTraceFunc(func(trace ai.CompletionTrace) {
+		traced = append(traced, trace.Provider+":"+trace.Request.Model)
+	})
+
+	req := ai.CompletionRequest{RequestMetadata: ai.RequestMetadata{Tenant: "school-a"}, Task: ai.TaskTeaching, Model: "gpt-4o"}
+	resp, err := router.Complete(context.Background(), req)
+	if err != nil {
+		t.Fatalf("Complete() error = %v", err)
+	}
+	if resp.Content != "cheap" {
+		t.Fatalf("Content = %q, want the allowed provider", resp.Content)
+	}
+	if want := []string{"deepseek:deepseek-chat"}; !slices.Equal(traced, want) {
+		t.Fatalf("attempts = %v, want %v with the requested model replaced", traced, want)
+	}
+
+	traced = nil
+	other := ai.CompletionRequest{RequestMetadata: ai.RequestMetadata{Tenant: "school-b"}, Task: ai.TaskTeaching}
+	if _, err := router.Complete(context.Background(), other); err != nil {
+		t.Fatalf("Complete(school-b) error = %v", err)
+	}
+	if want := []string{"anthropic:claude-sonnet"}; !slices.Equal(traced, want) {
+		t.Fatalf("school-b attempts = %v, want %v", traced, want)
+	}
+}
+
+func TestRouter_TenantModelPolicyFailsWhenNothingIsAllowed(t *testing.T) {
+	router := newTestRouter()
+	provider := ai.NewMockProvider("reply")
+	router.ReplaceProviders([]ai.ProviderRegistration{
+		{Name: "openai", Provider: provider, DefaultModel: "gpt-5.4"},
+	})
+	router.SetTenantModelPolicies(map[string]ai.ModelPolicy{
+		"school-a": {Deny: []string{"gpt-*"}},
+	})
+	if _, ok := router.TenantModelPolicy("school-a"); !ok {
+		t.Fatal("TenantModelPolicy(school-a) ok = false, want the policy")
+	}
+
+	req := ai.CompletionRequest{RequestMetadata: ai.RequestMetadata{Tenant: "school-a"}, Task: ai.TaskTeaching}
+	if _, err := router.Complete(context.Background(), req); !errors.Is(err, ai.ErrModelNotAllowed) {
+		t.Fatalf("Complete() error = %v, want ErrModelNotAllowed", err)
+	}
+	if _, err := router.StreamComplete(context.Background(), req); !errors.Is(err, ai.ErrModelNotAllowed) {
+		t.Fatalf("StreamComplete() error = %v, want ErrModelNotAllowed", err)
+	}
+	if provider.LastRequest != nil {
+		t.Fatalf("provider got %+v, want no request", provider.LastRequest)
+	}
+
+	router.SetTenantModelPolicies(nil)
+	if _, err := router.Complete(context.Background(), req); err != nil {
+		t.Fatalf("Complete() after clearing policies error = %v", err)
+	}
+}
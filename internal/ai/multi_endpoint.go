@@ -0,0 +1,210 @@
+// Copyright 2026 the P&AI authors. All rights reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package ai
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"sync"
+	"time"
+
+	"github.com/p-n-ai/pai-bot/internal/llm"
+)
+
+const (
+	// endpointFailureThreshold is how many consecutive failures mark a base
+	// URL unhealthy.
+	endpointFailureThreshold = 2
+	// endpointCooldown is how long an unhealthy base URL is tried last.
+	endpointCooldown = 30 * time.Second
+)
+
+// Endpoint is one base URL of a logical provider, e.g. a regional mirror.
+type Endpoint struct {
+	URL      string
+	Provider Provider
+}
+
+// WithEndpoints joins providers that serve the same models from different
+// base URLs into one logical provider. Calls go to the first healthy endpoint
+// in order and fail over to the next on error, so a regional outage is
+// absorbed without falling back to another provider's models. Native
+// tool-call support is kept when every endpoint has it.
+func WithEndpoints(name string, endpoints []Endpoint) Provider {
+	switch len(endpoints) {
+	case 0:
+		return nil
+	case 1:
+		return endpoints[0].Provider
+	}
+	base := &multiEndpointProvider{
+		name:      name,
+		endpoints: append([]Endpoint(nil), endpoints...),
+		health:    make([]endpointHealth, len(endpoints)),
+		now:       time.Now,
+	}
+	for _, endpoint := range endpoints {
+		if _, ok := endpoint.Provider.(NativeProvider); !ok {
+			return base
+		}
+	}
+	return &multiEndpointNativeProvider{multiEndpointProvider: base}
+}
+
+type endpointHealth struct {
+	consecutiveFailures int
+	unhealthyUntil      time.Time
+}
+
+type multiEndpointProvider struct {
+	name      string
+	endpoints []Endpoint
+	now       func() time.Time
+
+	mu     sync.Mutex
+	health []endpointHealth
+}
+
+type multiEndpointNativeProvider struct {
+	*multiEndpointProvider
+}
+
+var _ NativeProvider = (*multiEndpointNativeProvider)(nil)
+
+func (p *multiEndpointProvider) Complete(ctx context.Context, req CompletionRequest) (CompletionResponse, error) {
+	var resp CompletionResponse
+	err := p.try(ctx, func(endpoint Endpoint) error {
+		var err error
+		resp, err = endpoint.Provider.Complete(ctx, req)
+		return err
+	})
+	return resp, err
+}
+
+// StreamComplete fails over only while opening the stream; once chunks
+// flow, errors are reported on the stream as usual.
+func (p *multiEndpointProvider) StreamComplete(ctx context.Context, req CompletionRequest) (<-chan StreamChunk, error) {
+	var ch <-chan StreamChunk
+	err := p.try(ctx, func(endpoint Endpoint) error {
+		var err error
+		ch, err = endpoint.Provider.StreamComplete(ctx, req)
+		return err
+	})
+	return ch, err
+}
+
+func (p *multiEndpointNativeProvider) CompleteNative(ctx context.Context, model string, c llm.Context, opts *llm.StreamOptions) (llm.AssistantMessage, error) {
+	var msg llm.AssistantMessage
+	err := p.try(ctx, func(endpoint Endpoint) error {
+		var err error
+		msg, err = endpoint.Provider.(NativeProvider).CompleteNative(ctx, model, c, opts)
+		return err
+	})
+	return msg, err
+}
+
+// Models merges the endpoints' catalogs in order. Endpoints with built-in
+// catalogs all list the same models; live ones, such as Ollama hosts, list
+// what each has pulled.
+func (p *multiEndpointProvider) Models() []ModelInfo {
+	var models []ModelInfo
+	seen := make(map[string]bool)
+	for _, endpoint := range p.endpoints {
+		for _, info := range endpoint.Provider.Models() {
+			if !seen[info.ID] {
+				seen[info.ID] = true
+				models = append(models, info)
+			}
+		}
+	}
+	return models
+}
+
+// RefreshModels refreshes every endpoint with a live catalog.
+func (p *multiEndpointProvider) RefreshModels(ctx context.Context) error {
+	var errs []error
+	for _, endpoint := range p.endpoints {
+		if refresher, ok := endpoint.Provider.(ModelRefresher); ok {
+			if err := refresher.RefreshModels(ctx); err != nil {
+				errs = append(errs, fmt.Errorf("%s: %w", endpoint.URL, err))
+			}
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// HealthCheck checks every endpoint and passes when any of them is up.
+func (p *multiEndpointProvider) HealthCheck(ctx context.Context) error {
+	var errs []error
+	for i, endpoint := range p.endpoints {
+		err := endpoint.Provider.HealthCheck(ctx)
+		p.report(i, err)
+		if err == nil {
+			return nil
+		}
+		errs = append(errs, fmt.Errorf("%s: %w", endpoint.URL, err))
+	}
+	return errors.Join(errs...)
+}
+
+// try calls each endpoint in order, healthy ones first, until one succeeds.
+// Unhealthy endpoints are still tried last so a provider never refuses a
+// request only because every base URL recently failed.
+func (p *multiEndpointProvider) try(ctx context.Context, call func(Endpoint) error) error {
+	var errs []error
+	for _, i := range p.order() {
+		endpoint := p.endpoints[i]
+		err := call(endpoint)
+		if ctx.Err() != nil {
+			return err
+		}
+		p.report(i, err)
+		if err == nil {
+			return nil
+		}
+		slog.Warn("AI endpoint failed, trying next base URL",
+			"provider", p.name,
+			"base_url", endpoint.URL,
+			"error", err,
+		)
+		errs = append(errs, fmt.Errorf("%s: %w", endpoint.URL, err))
+	}
+	return errors.Join(errs...)
+}
+
+func (p *multiEndpointProvider) order() []int {
+	now := p.now()
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	healthy := make([]int, 0, len(p.endpoints))
+	var unhealthy []int
+	for i := range p.endpoints {
+		if now.Before(p.health[i].unhealthyUntil) {
+			unhealthy = append(unhealthy, i)
+			continue
+		}
+		healthy = append(healthy, i)
+	}
+	return append(healthy, unhealthy...)
+}
+
+func (p *multiEndpointProvider) report(i int, err error) {
+	now := p.now()
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	health := &p.health[i]
+	if err == nil {
+		if !health.unhealthyUntil.IsZero() {
+			slog.Info("AI endpoint recovered", "provider", p.name, "base_url", p.endpoints[i].URL)
+		}
+		*health = endpointHealth{}
+		return
+	}
+	health.consecutiveFailures++
+	if health.consecutiveFailures >= endpointFailureThreshold {
+		health.unhealthyUntil = now.Add(endpointCooldown)
+	}
+}
@@ -0,0 +1,119 @@
+// Copyright 2026 the P&AI authors. All rights reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package ai
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// fakeOllamaHost answers chat completions with content while up and 503
+// otherwise, counting the requests it receives.
+type fakeOllamaHost struct {
+	up    atomic.Bool
+	calls atomic.Int32
+	*httptest.Server
+}
+
+func newFakeOllamaHost(t *testing.T, content string) *fakeOllamaHost {
+	t.Helper()
+	host := &fakeOllamaHost{}
+	host.up.Store(true)
+	host.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		host.calls.Add(1)
+		if !host.up.Load() {
+			http.Error(w, "region down", http.StatusServiceUnavailable)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"model":"qwen3","choices":[{"message":{"content":"` + content + `"}}]}`))
+	}))
+	t.Cleanup(host.Close)
+	return host
+}
+
+func TestWithEndpointsFailsOverAndPrefersHealthyBaseURLs(t *testing.T) {
+	primary := newFakeOllamaHost(t, "from primary")
+	mirror := newFakeOllamaHost(t, "from mirror")
+	provider := WithEndpoints("ollama", []Endpoint{
+		{URL: primary.URL, Provider: NewOllamaProvider(primary.URL)},
+		{URL: mirror.URL, Provider: NewOllamaProvider(mirror.URL)},
+	}).(*multiEndpointProvider)
+	now := time.Date(2026, 10, 17, 9, 0, 0, 0, time.UTC)
+	provider.now = func() time.Time { return now }
+	complete := func() string {
+		t.Helper()
+		resp, err := provider.Complete(context.Background(), CompletionRequest{Messages: []Message{{Role: "user", Content: "hi"}}})
+		if err != nil {
+			t.Fatalf("Complete() error = %v", err)
+		}
+		return resp.Content
+	}
+
+	if got := complete(); got != "from primary" {
+		t.Fatalf("healthy primary answered %q", got)
+	}
+
+	primary.up.Store(false)
+	for i := 0; i < endpointFailureThreshold; i++ {
+		if got := complete(); got != "from mirror" {
+			t.Fatalf("failover %d answered %q, want the mirror", i, got)
+		}
+	}
+	// The primary is now unhealthy, so the mirror is tried first.
+	before := primary.calls.Load()
+	if got := complete(); got != "from mirror" {
+		t.Fatalf("unhealthy primary: answered %q", got)
+	}
+	if primary.calls.Load() != before {
+		t.Fatal("unhealthy primary was tried before the healthy mirror")
+	}
+
+	// After the cooldown the recovered primary takes over again.
+	primary.up.Store(true)
+	now = now.Add(endpointCooldown)
+	if got := complete(); got != "from primary" {
+		t.Fatalf("after cooldown answered %q, want the primary", got)
+	}
+}
+
+func TestWithEndpointsReportsEveryFailure(t *testing.T) {
+	primary := newFakeOllamaHost(t, "")
+	mirror := newFakeOllamaHost(t, "")
+	primary.up.Store(false)
+	mirror.up.Store(false)
+	provider := WithEndpoints("ollama", []Endpoint{
+		{URL: primary.URL, Provider: NewOllamaProvider(primary.URL)},
+		{URL: mirror.URL, Provider: NewOllamaProvider(mirror.URL)},
+	})
+
+	_, err := provider.Complete(context.Background(), CompletionRequest{Messages: []Message{{Role: "user", Content: "hi"}}})
+	if err == nil {
+		t.Fatal("Complete() error = nil with every base URL down")
+	}
+	if primary.calls.Load() == 0 || mirror.calls.Load() == 0 {
+		t.Fatalf("calls = %d/%d, want both base URLs tried", primary.calls.Load(), mirror.calls.Load())
+	}
+}
+
+func TestWithEndpointsKeepsNativeSupport(t *testing.T) {
+	openai := WithEndpoints("openai", []Endpoint{
+		{URL: "https://a.example/v1", Provider: NewOpenAIProvider("key", WithBaseURL("https://a.example/v1"))},
+		{URL: "https://b.example/v1", Provider: NewOpenAIProvider("key", WithBaseURL("https://b.example/v1"))},
+	})
+	if _, ok := openai.(NativeProvider); !ok {
+		t.Fatalf("OpenAI endpoints = %T, want NativeProvider", openai)
+	}
+	ollama := WithEndpoints("ollama", []Endpoint{
+		{URL: "http://a:11434", Provider: NewOllamaProvider("http://a:11434")},
+		{URL: "http://b:11434", Provider: NewOllamaProvider("http://b:11434")},
+	})
+	if _, ok := ollama.(NativeProvider); ok {
+		t.Fatal("Ollama endpoints claim native support")
+	}
+}
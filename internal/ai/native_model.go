@@ -22,8 +22,9 @@ type NativeProvider interface {
 
 // NativeModelConfig fixes routing policy around one provider-neutral native model port.
 type NativeModelConfig struct {
-	Task  TaskType
-	Model string
+	Task     TaskType
+	Model    string
+	Metadata RequestMetadata
 }
 
 // NativeModel routes native-message calls through the existing provider fallback policy.
@@ -51,40 +52,57 @@ func (r *Router) completeNative(ctx context.Context, config NativeModelConfig, c
 		return llm.AssistantMessage{}, errors.New("all AI providers failed (no providers registered)")
 	}
 
+	plan, err := r.requestPlan(config.Task, config.Metadata, strings.TrimSpace(config.Model), providers, order)
+	if err != nil {
+		return llm.AssistantMessage{}, err
+	}
+
+	opts = r.nativeGenerationOptions(config, opts)
 	legacyRequest, legacyCompatible := projectNativeCompletionRequest(config, c, opts)
+	opts = withNativeMetadata(opts, config.Metadata)
+	logger := slog.With(config.Metadata.logAttrs()...)
 	var failures []string
-	for _, name := range order {
+	for _, step := range plan {
+		name := step.provider
 		provider := providers[name]
 		if provider == nil {
 			continue
 		}
-		if r.isCircuitOpen(name) {
+		native, isNative := provider.(NativeProvider)
+		if !isNative && !legacyCompatible {
+			failures = append(failures, name+": native tool messages unsupported")
+			continue
+		}
+		modelID := step.modelFor(strings.TrimSpace(config.Model))
+		traceRequest := projectNativeTraceRequest(config, modelID, c, opts)
+		tokens := estimateRequestTokens(traceRequest)
+		reservation, err := r.admitRequest(ctx, name, tokens)
+		if errors.Is(err, errRateLimited) {
+			failures = append(failures, name+": rate limited")
+			continue
+		}
+		if errors.Is(err, errCircuitOpen) {
 			failures = append(failures, name+": circuit open")
 			continue
 		}
-
-		modelID := strings.TrimSpace(config.Model)
-		if modelID == "" {
-			modelID = r.defaultModelForProvider(name)
+		if err != nil {
+			return llm.AssistantMessage{}, err
 		}
+
 		startedAt := time.Now()
 		var response llm.AssistantMessage
-		var err error
-		if native, ok := provider.(NativeProvider); ok {
-			response, err = r.completeNativeWithRetry(ctx, native, modelID, c, opts)
-		} else if legacyCompatible {
+		var attemptStart time.Time
+		if isNative {
+			response, reservation, attemptStart, err = r.completeNativeWithRetry(ctx, name, native, modelID, c, opts, reservation, tokens)
+		} else {
 			req := legacyRequest
 			req.Model = modelID
 			var legacyResponse CompletionResponse
-			legacyResponse, err = r.completeWithRetry(ctx, provider, req)
+			legacyResponse, reservation, attemptStart, err = r.completeWithRetry(ctx, name, provider, req, reservation)
 			if err == nil {
 				response = projectLegacyCompletionResponse(name, legacyResponse)
 			}
-		} else {
-			failures = append(failures, name+": native tool messages unsupported")
-			continue
 		}
-		traceRequest := projectNativeTraceRequest(config, modelID, c, opts)
 		trace := CompletionTrace{
 			Provider:    name,
 			Request:     traceRequest,
@@ -100,13 +118,15 @@ func (r *Router) completeNative(ctx context.Context, config NativeModelConfig, c
 
 		if err != nil {
 			if ctxErr := ctx.Err(); ctxErr != nil {
+				r.releaseProbe(name)
 				return llm.AssistantMessage{}, ctxErr
 			}
 			if errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+				r.releaseProbe(name)
 				return llm.AssistantMessage{}, err
 			}
 			r.markFailure(name, gen)
-			slog.Warn("native AI provider failed, trying next",
+			logger.Warn("native AI provider failed, trying next",
 				"provider", name,
 				"duration_ms", time.Since(startedAt).Milliseconds(),
 				"error", true,
@@ -116,11 +136,15 @@ func (r *Router) completeNative(ctx context.Context, config NativeModelConfig, c
 		}
 
 		r.markSuccess(name, gen)
-		slog.Debug("native AI request completed",
+		r.recordLatency(name, gen, time.Since(attemptStart))
+		reservation.settle(response.Usage.Input + response.Usage.CacheRead + response.Usage.CacheWrite + response.Usage.Output)
+		r.priceNativeResponse(name, &response)
+		logger.Debug("native AI request completed",
 			"provider", name,
 			"model", response.ResponseModel,
 			"input_tokens", response.Usage.Input+response.Usage.CacheRead+response.Usage.CacheWrite,
 			"output_tokens", response.Usage.Output,
+			"cost_usd", response.Usage.Cost.Total,
 			"duration_ms", time.Since(startedAt).Milliseconds(),
 		)
 		return response, nil
@@ -129,8 +153,24 @@ func (r *Router) completeNative(ctx context.Context, config NativeModelConfig, c
 	return llm.AssistantMessage{}, fmt.Errorf("all AI providers failed: %s", strings.Join(failures, "; "))
 }
 
+// withNativeMetadata copies metadata into the stream options so native
+// providers can forward it, leaving the caller's options untouched.
+func withNativeMetadata(opts *llm.StreamOptions, metadata RequestMetadata) *llm.StreamOptions {
+	fields := metadata.Map()
+	if fields == nil {
+		return opts
+	}
+	var copied llm.StreamOptions
+	if opts != nil {
+		copied = *opts
+	}
+	copied.User = metadata.UserHash
+	copied.Metadata = fields
+	return &copied
+}
+
 func projectNativeTraceRequest(config NativeModelConfig, modelID string, c llm.Context, opts *llm.StreamOptions) CompletionRequest {
-	req := CompletionRequest{Task: config.Task, Model: modelID}
+	req := CompletionRequest{RequestMetadata: config.Metadata, Task: config.Task, Model: modelID}
 	if c.SystemPrompt != "" {
 		req.Messages = append(req.Messages, Message{Role: "system", Content: c.SystemPrompt})
 	}
@@ -169,6 +209,7 @@ func projectNativeCompletionResponse(response llm.AssistantMessage) CompletionRe
 		Model:        model,
 		InputTokens:  response.Usage.Input + response.Usage.CacheRead + response.Usage.CacheWrite,
 		OutputTokens: response.Usage.Output,
+		Truncated:    response.StopReason == llm.StopReasonLength,
 	}
 }
 
@@ -182,32 +223,46 @@ func nativeAssistantText(message llm.AssistantMessage) string {
 	return text.String()
 }
 
-func (r *Router) completeNativeWithRetry(ctx context.Context, provider NativeProvider, model string, c llm.Context, opts *llm.StreamOptions) (llm.AssistantMessage, error) {
+// completeNativeWithRetry is completeWithRetry for native providers.
+func (r *Router) completeNativeWithRetry(ctx context.Context, name string, provider NativeProvider, model string, c llm.Context, opts *llm.StreamOptions, reservation *rateReservation, tokens int) (llm.AssistantMessage, *rateReservation, time.Time, error) {
 	var lastErr error
-	attempts := 1 + len(r.retryBackoff)
+	backoff := r.attemptBackoff()
+	attempts := 1 + len(backoff)
 	for attempt := 1; attempt <= attempts; attempt++ {
+		if attempt > 1 {
+			var err error
+			reservation, err = r.waitForRateLimit(ctx, name, tokens)
+			if errors.Is(err, errRateLimited) {
+				break
+			}
+			if err != nil {
+				return llm.AssistantMessage{}, nil, time.Time{}, err
+			}
+		}
+		attemptStart := time.Now()
 		response, err := provider.CompleteNative(ctx, model, c, opts)
 		if err == nil {
-			return response, nil
+			return response, reservation, attemptStart, nil
 		}
+		reservation.release()
 		lastErr = err
-		if attempt == attempts {
+		if attempt == attempts || !retryable(err) {
 			break
 		}
 		select {
 		case <-ctx.Done():
-			return llm.AssistantMessage{}, ctx.Err()
-		case <-time.After(r.retryBackoff[attempt-1]):
+			return llm.AssistantMessage{}, nil, time.Time{}, ctx.Err()
+		case <-time.After(backoff[attempt-1]):
 		}
 	}
-	return llm.AssistantMessage{}, lastErr
+	return llm.AssistantMessage{}, nil, time.Time{}, lastErr
 }
 
 func projectNativeCompletionRequest(config NativeModelConfig, c llm.Context, opts *llm.StreamOptions) (CompletionRequest, bool) {
 	if len(c.Tools) > 0 {
 		return CompletionRequest{}, false
 	}
-	req := CompletionRequest{Task: config.Task, Model: config.Model}
+	req := CompletionRequest{RequestMetadata: config.Metadata, Task: config.Task, Model: config.Model}
 	if c.SystemPrompt != "" {
 		req.Messages = append(req.Messages, Message{Role: "system", Content: c.SystemPrompt})
 	}
@@ -261,6 +316,10 @@ func projectNativeUserMessage(message llm.UserMessage) (Message, bool) {
 }
 
 func projectLegacyCompletionResponse(provider string, response CompletionResponse) llm.AssistantMessage {
+	stopReason := llm.StopReasonStop
+	if response.Truncated {
+		stopReason = llm.StopReasonLength
+	}
 	return llm.AssistantMessage{
 		Content:       []llm.AssistantContent{llm.TextContent{Text: response.Content}},
 		Provider:      provider,
@@ -270,8 +329,9 @@ func projectLegacyCompletionResponse(provider string, response CompletionRespons
 			Input:       response.InputTokens,
 			Output:      response.OutputTokens,
 			TotalTokens: response.TotalTokens(),
+			Cost:        llm.Cost{Total: response.CostUSD},
 		},
-		StopReason: llm.StopReasonStop,
+		StopReason: stopReason,
 		Timestamp:  time.Now(),
 	}
 }
@@ -17,12 +17,14 @@ type nativeTestProvider struct {
 	response llm.AssistantMessage
 	err      error
 	context  llm.Context
+	opts     *llm.StreamOptions
 	calls    int
 }
 
-func (p *nativeTestProvider) CompleteNative(_ context.Context, _ string, c llm.Context, _ *llm.StreamOptions) (llm.AssistantMessage, error) {
+func (p *nativeTestProvider) CompleteNative(_ context.Context, _ string, c llm.Context, opts *llm.StreamOptions) (llm.AssistantMessage, error) {
 	p.calls++
 	p.context = c
+	p.opts = opts
 	return p.response, p.err
 }
 
@@ -68,6 +70,30 @@ func TestNativeModelPreservesContextAndAssistantMessage(t *testing.T) {
 	}
 }
 
+func TestNativeModelForwardsRequestMetadata(t *testing.T) {
+	provider := &nativeTestProvider{response: llm.AssistantMessage{Content: []llm.AssistantContent{llm.TextContent{Text: "ok"}}}}
+	router := NewRouterWithConfig(RouterConfig{RetryBackoff: []time.Duration{0}})
+	router.Register("test", provider)
+	var traces []CompletionTrace
+	router.SetTraceFunc(func(trace CompletionTrace) { traces = append(traces, trace) })
+	metadata := RequestMetadata{Tenant: "tenant-1", UserHash: "hash-1", ConversationID: "conv-1", TurnID: "turn-1"}
+	model := NewNativeModel(router, NativeModelConfig{Task: TaskTeaching, Metadata: metadata})
+
+	callerOpts := &llm.StreamOptions{MaxTokens: 64}
+	if _, err := model.Complete(context.Background(), llm.Context{Messages: []llm.Message{llm.UserText("Help")}}, callerOpts); err != nil {
+		t.Fatalf("Complete() error = %v", err)
+	}
+	if provider.opts == nil || provider.opts.User != "hash-1" || provider.opts.Metadata["turn_id"] != "turn-1" || provider.opts.MaxTokens != 64 {
+		t.Fatalf("provider opts = %#v, want metadata merged into caller options", provider.opts)
+	}
+	if callerOpts.User != "" || callerOpts.Metadata != nil {
+		t.Fatalf("caller opts mutated: %#v", callerOpts)
+	}
+	if len(traces) != 1 || traces[0].Request.RequestMetadata != metadata {
+		t.Fatalf("traces = %#v, want metadata on trace request", traces)
+	}
+}
+
 func TestNativeModelEmitsSanitizedTraceForNativeToolRequest(t *testing.T) {
 	provider := &nativeTestProvider{response: llm.AssistantMessage{
 		Content:       []llm.AssistantContent{llm.ToolCall{ID: "call-1", Name: "lookup", Arguments: map[string]any{"topic_id": "F1-02"}}},
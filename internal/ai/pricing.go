@@ -0,0 +1,197 @@
+// Copyright 2026 the P&AI authors. All rights reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package ai
+
+import (
+	"log/slog"
+	"maps"
+	"strings"
+
+	"github.com/p-n-ai/pai-bot/internal/llm"
+)
+
+// ModelPrice is a model's list price in USD per million tokens.
+type ModelPrice struct {
+	InputPerMTok  float64 `json:"input_per_mtok"`
+	OutputPerMTok float64 `json:"output_per_mtok"`
+}
+
+// Cost returns the USD cost of a call with the given token counts.
+func (p ModelPrice) Cost(inputTokens, outputTokens int) float64 {
+	return (p.InputPerMTok*float64(inputTokens) + p.OutputPerMTok*float64(outputTokens)) / 1e6
+}
+
+// Prompt cache reads and writes are billed relative to the input price, at
+// Anthropic's rates for five-minute ephemeral entries.
+const (
+	cacheReadPriceFactor  = 0.1
+	cacheWritePriceFactor = 1.25
+)
+
+// responseCost prices resp, charging its cached input tokens at the cache
+// read and write rates instead of the full input price.
+func (p ModelPrice) responseCost(resp CompletionResponse) float64 {
+	uncached := max(resp.InputTokens-resp.CacheReadTokens-resp.CacheWriteTokens, 0)
+	cached := cacheReadPriceFactor*float64(resp.CacheReadTokens) + cacheWritePriceFactor*float64(resp.CacheWriteTokens)
+	return p.Cost(uncached, resp.OutputTokens) + p.InputPerMTok*cached/1e6
+}
+
+// DefaultModelPrices are published list prices for common hosted models.
+// Dated snapshots such as "gpt-4o-2024-08-06" use their base model's price.
+// Models missing here can be priced with SetModelPrices; until then their
+// calls are logged without a cost.
+func DefaultModelPrices() map[string]ModelPrice {
+	return map[string]ModelPrice{
+		"gpt-4o":                  {InputPerMTok: 2.50, OutputPerMTok: 10.00},
+		"gpt-4o-mini":             {InputPerMTok: 0.15, OutputPerMTok: 0.60},
+		"gpt-4.1":                 {InputPerMTok: 2.00, OutputPerMTok: 8.00},
+		"gpt-4.1-mini":            {InputPerMTok: 0.40, OutputPerMTok: 1.60},
+		"gpt-4.1-nano":            {InputPerMTok: 0.10, OutputPerMTok: 0.40},
+		"gpt-5":                   {InputPerMTok: 1.25, OutputPerMTok: 10.00},
+		"gpt-5-mini":              {InputPerMTok: 0.25, OutputPerMTok: 2.00},
+		"gpt-5-nano":              {InputPerMTok: 0.05, OutputPerMTok: 0.40},
+		"claude-sonnet-4":         {InputPerMTok: 3.00, OutputPerMTok: 15.00},
+		"claude-haiku-4-5":        {InputPerMTok: 1.00, OutputPerMTok: 5.00},
+		"claude-3-5-haiku":        {InputPerMTok: 0.80, OutputPerMTok: 4.00},
+		"deepseek-chat":           {InputPerMTok: 0.28, OutputPerMTok: 0.42},
+		"deepseek-reasoner":       {InputPerMTok: 0.28, OutputPerMTok: 0.42},
+		"gemini-2.5-pro":          {InputPerMTok: 1.25, OutputPerMTok: 10.00},
+		"gemini-2.5-flash":        {InputPerMTok: 0.30, OutputPerMTok: 2.50},
+		"gemini-2.0-flash":        {InputPerMTok: 0.10, OutputPerMTok: 0.40},
+		"llama-3.1-8b-instant":    {InputPerMTok: 0.05, OutputPerMTok: 0.08},
+		"llama-3.3-70b-versatile": {InputPerMTok: 0.59, OutputPerMTok: 0.79},
+	}
+}
+
+// freeProviders run on the operator's own hardware and never cost anything
+// per token.
+var freeProviders = map[string]bool{"ollama": true, "mock": true}
+
+// SetModelPrices adds prices to the defaults, replacing any for the same
+// model. They also win over prices discovered from provider catalogs.
+func (r *Router) SetModelPrices(prices map[string]ModelPrice) {
+	configured := maps.Clone(prices)
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.configuredPrices = configured
+	r.rebuildModelPricesLocked()
+}
+
+// rebuildModelPricesLocked layers the price table: defaults, then prices
+// discovered from provider catalogs, then configured prices. The caller
+// holds r.mu.
+func (r *Router) rebuildModelPricesLocked() {
+	table := DefaultModelPrices()
+	maps.Copy(table, r.discoveredPrices)
+	maps.Copy(table, r.configuredPrices)
+	r.modelPrices = table
+	r.unpricedModels = nil
+}
+
+// ModelPrice returns the price used for model. An exact match wins, such as
+// an OpenRouter "vendor/model" ID priced from its live catalog; otherwise
+// dated snapshots and "vendor/model" IDs match their base model.
+func (r *Router) ModelPrice(model string) (ModelPrice, bool) {
+	r.mu.RLock()
+	prices := r.modelPrices
+	r.mu.RUnlock()
+	if prices == nil {
+		prices = defaultModelPrices
+	}
+	return lookupModelPrice(prices, model)
+}
+
+var defaultModelPrices = DefaultModelPrices()
+
+func lookupModelPrice(prices map[string]ModelPrice, model string) (ModelPrice, bool) {
+	model = strings.ToLower(strings.TrimSpace(model))
+	if price, ok := prices[model]; ok {
+		return price, true
+	}
+	if _, name, ok := strings.Cut(model, "/"); ok {
+		model = name
+		if price, ok := prices[model]; ok {
+			return price, true
+		}
+	}
+	// Try shorter names while what was cut off is a version or date, so
+	// "claude-sonnet-4-6" and "gpt-4o-2024-08-06" match but "gpt-5-mini"
+	// never falls back to "gpt-5".
+	for i := strings.LastIndexByte(model, '-'); i > 0; i = strings.LastIndexByte(model[:i], '-') {
+		if !isVersionSuffix(model[i+1:]) {
+			break
+		}
+		if price, ok := prices[model[:i]]; ok {
+			return price, true
+		}
+	}
+	return ModelPrice{}, false
+}
+
+func isVersionSuffix(s string) bool {
+	if s == "" {
+		return false
+	}
+	for _, c := range s {
+		if (c < '0' || c > '9') && c != '-' {
+			return false
+		}
+	}
+	return true
+}
+
+// priceResponse fills resp.CostUSD from the price table when the provider
+// did not report a cost. A hosted model without a price is logged once.
+func (r *Router) priceResponse(provider string, resp *CompletionResponse) {
+	if resp.CostUSD > 0 || freeProviders[provider] {
+		return
+	}
+	price, ok := r.ModelPrice(resp.Model)
+	if ok {
+		resp.CostUSD = price.responseCost(*resp)
+		return
+	}
+	r.warnUnpriced(provider, resp.Model)
+}
+
+// priceNativeResponse is priceResponse for native messages. Cached input is
+// priced as ordinary input, which may overstate the cost slightly.
+func (r *Router) priceNativeResponse(provider string, msg *llm.AssistantMessage) {
+	if msg.Usage.Cost.Total > 0 || freeProviders[provider] {
+		return
+	}
+	model := msg.ResponseModel
+	if model == "" {
+		model = msg.Model
+	}
+	price, ok := r.ModelPrice(model)
+	if !ok {
+		r.warnUnpriced(provider, model)
+		return
+	}
+	input := msg.Usage.Input + msg.Usage.CacheRead + msg.Usage.CacheWrite
+	msg.Usage.Cost = llm.Cost{
+		Input:  price.Cost(input, 0),
+		Output: price.Cost(0, msg.Usage.Output),
+	}
+	msg.Usage.Cost.Total = msg.Usage.Cost.Input + msg.Usage.Cost.Output
+}
+
+func (r *Router) warnUnpriced(provider, model string) {
+	if model == "" {
+		return
+	}
+	r.mu.Lock()
+	if r.unpricedModels[model] {
+		r.mu.Unlock()
+		return
+	}
+	if r.unpricedModels == nil {
+		r.unpricedModels = make(map[string]bool)
+	}
+	r.unpricedModels[model] = true
+	r.mu.Unlock()
+	slog.Warn("AI model has no price; its cost is not tracked", "provider", provider, "model", model)
+}
@@ -0,0 +1,92 @@
+// Copyright 2026 the P&AI authors. All rights reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package ai_test
+
+import (
+	"context"
+	"math"
+	"testing"
+
+	"github.com/p-n-ai/pai-bot/internal/ai"
+)
+
+func TestRouter_ModelPriceMatchesSnapshotsAndVendorPrefixes(t *testing.T) {
+	router := ai.NewRouter()
+	tests := []struct {
+		model string
+		want  ai.ModelPrice
+		ok    bool
+	}{
+		{"gpt-4o", ai.ModelPrice{InputPerMTok: 2.50, OutputPerMTok: 10.00}, true},
+		{"gpt-4o-2024-08-06", ai.ModelPrice{InputPerMTok: 2.50, OutputPerMTok: 10.00}, true},
+		{"openai/gpt-4o-mini", ai.ModelPrice{InputPerMTok: 0.15, OutputPerMTok: 0.60}, true},
+		{"claude-sonnet-4-20250514", ai.ModelPrice{InputPerMTok: 3.00, OutputPerMTok: 15.00}, true},
+		{"GPT-5-Mini", ai.ModelPrice{InputPerMTok: 0.25, OutputPerMTok: 2.00}, true},
+		// A variant name must not fall back to its family's base price.
+		{"gpt-5-pro", ai.ModelPrice{}, false},
+		{"llama3", ai.ModelPrice{}, false},
+	}
+	for _, tt := range tests {
+		got, ok := router.ModelPrice(tt.model)
+		if ok != tt.ok || got != tt.want {
+			t.Errorf("ModelPrice(%q) = %+v, %v; want %+v, %v", tt.model, got, ok, tt.want, tt.ok)
+		}
+	}
+}
+
+func TestRouter_CompleteSetsCostUSD(t *testing.T) {
+	router := newTestRouter()
+	router.Register("openai", ai.NewMockProvider("Hello!"))
+	router.SetModelPrices(map[string]ai.ModelPrice{"mock": {InputPerMTok: 1, OutputPerMTok: 2}})
+
+	resp, err := router.Complete(context.Background(), ai.CompletionRequest{
+		Messages: []ai.Message{{Role: "user", Content: "Hi"}},
+	})
+	if err != nil {
+		t.Fatalf("Complete() error = %v", err)
+	}
+	// 10 input tokens at $1/M plus 6 output tokens at $2/M.
+	if want := 22.0 / 1e6; math.Abs(resp.CostUSD-want) > 1e-12 {
+		t.Fatalf("CostUSD = %v, want %v", resp.CostUSD, want)
+	}
+}
+
+func TestRouter_CompleteChargesCachedInputAtCacheRates(t *testing.T) {
+	router := newTestRouter()
+	router.Register("anthropic", &cachedUsageProvider{})
+	router.SetModelPrices(map[string]ai.ModelPrice{"cached": {InputPerMTok: 10, OutputPerMTok: 0}})
+
+	resp, err := router.Complete(context.Background(), ai.CompletionRequest{
+		Messages: []ai.Message{{Role: "user", Content: "Hi"}},
+	})
+	if err != nil {
+		t.Fatalf("Complete() error = %v", err)
+	}
+	// 100 uncached, 1000 read at 0.1x and 100 written at 1.25x, all at $10/M.
+	if want := (100 + 100 + 125) * 10.0 / 1e6; math.Abs(resp.CostUSD-want) > 1e-12 {
+		t.Fatalf("CostUSD = %v, want %v", resp.CostUSD, want)
+	}
+}
+
+type cachedUsageProvider struct{ ai.MockProvider }
+
+func (p *cachedUsageProvider) Complete(_ context.Context, _ ai.CompletionRequest) (ai.CompletionResponse, error) {
+	return ai.CompletionResponse{Content: "Hello!", Model: "cached", InputTokens: 1200, CacheReadTokens: 1000, CacheWriteTokens: 100}, nil
+}
+
+func TestRouter_CompleteLeavesLocalProvidersFree(t *testing.T) {
+	router := newTestRouter()
+	router.Register("ollama", ai.NewMockProvider("Hello!"))
+	router.SetModelPrices(map[string]ai.ModelPrice{"mock": {InputPerMTok: 1, OutputPerMTok: 2}})
+
+	resp, err := router.Complete(context.Background(), ai.CompletionRequest{
+		Messages: []ai.Message{{Role: "user", Content: "Hi"}},
+	})
+	if err != nil {
+		t.Fatalf("Complete() error = %v", err)
+	}
+	if resp.CostUSD != 0 {
+		t.Fatalf("CostUSD = %v, want 0 for ollama", resp.CostUSD)
+	}
+}
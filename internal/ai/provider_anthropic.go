@@ -4,6 +4,7 @@
 package ai
 
 import (
+	"bufio"
 	"bytes"
 	"context"
 	"encoding/base64"
@@ -12,6 +13,8 @@ import (
 	"io"
 	"net/http"
 	"strings"
+
+	"github.com/p-n-ai/pai-bot/internal/apperr"
 )
 
 const defaultAnthropicBaseURL = "https://api.anthropic.com/v1"
@@ -32,12 +35,33 @@ type anthropicMessage struct {
 }
 
 type anthropicRequest struct {
-	Model        string                 `json:"model"`
-	MaxTokens    int                    `json:"max_tokens"`
-	Messages     []anthropicMessage     `json:"messages"`
-	System       string                 `json:"system,omitempty"`
+	Model     string             `json:"model"`
+	MaxTokens int                `json:"max_tokens"`
+	Messages  []anthropicMessage `json:"messages"`
+	// System is a string, or text blocks when one carries a cache breakpoint.
+	System       any                    `json:"system,omitempty"`
 	Temperature  *float64               `json:"temperature,omitempty"`
 	OutputConfig *anthropicOutputConfig `json:"output_config,omitempty"`
+	Metadata     *anthropicMetadata     `json:"metadata,omitempty"`
+	Tools        []anthropicTool        `json:"tools,omitempty"`
+	ToolChoice   *anthropicToolChoice   `json:"tool_choice,omitempty"`
+	Stream       bool                   `json:"stream,omitempty"`
+}
+
+type anthropicToolChoice struct {
+	Type string `json:"type"`
+	Name string `json:"name,omitempty"`
+}
+
+type anthropicTool struct {
+	Name        string          `json:"name"`
+	Description string          `json:"description,omitempty"`
+	InputSchema json.RawMessage `json:"input_schema"`
+}
+
+// anthropicMetadata carries the only metadata field the Messages API accepts.
+type anthropicMetadata struct {
+	UserID string `json:"user_id"`
 }
 
 type anthropicOutputConfig struct {
@@ -49,12 +73,29 @@ type anthropicOutputFormat struct {
 	Schema json.RawMessage `json:"schema"`
 }
 
+// anthropicContentBlock is a text, image, tool_use or tool_result block;
+// Type says which fields are set.
 type anthropicContentBlock struct {
-	Type   string                `json:"type"`
-	Text   string                `json:"text,omitempty"`
-	Source *anthropicImageSource `json:"source,omitempty"`
+	Type      string                `json:"type"`
+	Text      string                `json:"text,omitempty"`
+	Source    *anthropicImageSource `json:"source,omitempty"`
+	ID        string                `json:"id,omitempty"`
+	Name      string                `json:"name,omitempty"`
+	Input     json.RawMessage       `json:"input,omitempty"`
+	ToolUseID string                `json:"tool_use_id,omitempty"`
+	Content   string                `json:"content,omitempty"`
+	// CacheControl ends a cached prompt prefix at this block.
+	CacheControl *anthropicCacheControl `json:"cache_control,omitempty"`
+}
+
+type anthropicCacheControl struct {
+	Type string `json:"type"`
 }
 
+// anthropicMaxCacheBreakpoints is how many cache_control markers the
+// Messages API accepts per request.
+const anthropicMaxCacheBreakpoints = 4
+
 type anthropicImageSource struct {
 	Type      string `json:"type"`
 	MediaType string `json:"media_type,omitempty"`
@@ -69,6 +110,13 @@ func WithAnthropicBaseURL(url string) AnthropicOption {
 	}
 }
 
+// WithAnthropicHTTPClient sets a custom HTTP client.
+func WithAnthropicHTTPClient(client *http.Client) AnthropicOption {
+	return func(p *AnthropicProvider) {
+		p.client = client
+	}
+}
+
 // NewAnthropicProvider creates a new Anthropic provider.
 func NewAnthropicProvider(apiKey string, opts ...AnthropicOption) (*AnthropicProvider, error) {
 	if apiKey == "" {
@@ -77,7 +125,7 @@ func NewAnthropicProvider(apiKey string, opts ...AnthropicOption) (*AnthropicPro
 	p := &AnthropicProvider{
 		apiKey:  apiKey,
 		baseURL: defaultAnthropicBaseURL,
-		client:  &http.Client{},
+		client:  defaultHTTPClient,
 	}
 	for _, opt := range opts {
 		opt(p)
@@ -86,6 +134,79 @@ func NewAnthropicProvider(apiKey string, opts ...AnthropicOption) (*AnthropicPro
 }
 
 func (p *AnthropicProvider) Complete(ctx context.Context, req CompletionRequest) (CompletionResponse, error) {
+	body, err := buildAnthropicRequest(req)
+	if err != nil {
+		return CompletionResponse{}, err
+	}
+	httpReq, err := p.newRequest(ctx, body)
+	if err != nil {
+		return CompletionResponse{}, err
+	}
+
+	resp, err := p.client.Do(httpReq)
+	if err != nil {
+		return CompletionResponse{}, fmt.Errorf("anthropic API call: %w", err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	respBody, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return CompletionResponse{}, fmt.Errorf("reading response: %w", err)
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return CompletionResponse{}, apperr.HTTPStatus(resp.StatusCode, fmt.Errorf("anthropic API error %d: %s", resp.StatusCode, string(respBody)))
+	}
+
+	var result struct {
+		Content    []anthropicContentBlock `json:"content"`
+		Model      string                  `json:"model"`
+		StopReason string                  `json:"stop_reason"`
+		Usage      anthropicUsage          `json:"usage"`
+	}
+	if err := json.Unmarshal(respBody, &result); err != nil {
+		return CompletionResponse{}, fmt.Errorf("parsing response: %w", err)
+	}
+
+	if len(result.Content) == 0 {
+		return CompletionResponse{}, fmt.Errorf("anthropic returned no content")
+	}
+
+	var text strings.Builder
+	var toolCalls []ToolCall
+	var structured json.RawMessage
+	for _, block := range result.Content {
+		switch block.Type {
+		case "text":
+			text.WriteString(block.Text)
+		case "tool_use":
+			if body.ToolChoice != nil && block.Name == body.ToolChoice.Name {
+				structured = block.Input
+				continue
+			}
+			toolCalls = append(toolCalls, ToolCall{ID: block.ID, Name: block.Name, Arguments: block.Input})
+		}
+	}
+
+	return CompletionResponse{
+		Content:          text.String(),
+		Model:            result.Model,
+		InputTokens:      result.Usage.promptTokens(),
+		OutputTokens:     result.Usage.OutputTokens,
+		CacheReadTokens:  result.Usage.CacheReadInputTokens,
+		CacheWriteTokens: result.Usage.CacheCreationInputTokens,
+		Truncated:        result.StopReason == "max_tokens",
+		ToolCalls:        toolCalls,
+		StructuredOutput: structured,
+	}, nil
+}
+
+// buildAnthropicRequest converts req to a Messages API body, moving system
+// messages to the top-level system prompt. Tool calls become tool_use blocks
+// and "tool" results become tool_result blocks in the following user turn.
+// Messages marked CacheBreakpoint get an ephemeral cache_control on their
+// last block, up to the API's limit of four.
+func buildAnthropicRequest(req CompletionRequest) (anthropicRequest, error) {
 	model := req.Model
 	if model == "" {
 		model = "claude-sonnet-4-6"
@@ -96,17 +217,35 @@ func (p *AnthropicProvider) Complete(ctx context.Context, req CompletionRequest)
 	}
 
 	// Separate system message from user/assistant messages.
-	var systemPrompts []string
+	var systemBlocks []anthropicContentBlock
 	var messages []anthropicMessage
+	breakpoints := 0
+	cacheControl := func(m Message) *anthropicCacheControl {
+		if !m.CacheBreakpoint || breakpoints >= anthropicMaxCacheBreakpoints {
+			return nil
+		}
+		breakpoints++
+		return &anthropicCacheControl{Type: "ephemeral"}
+	}
 	for _, m := range req.Messages {
 		if m.Role == "system" {
 			if m.Content != "" {
-				systemPrompts = append(systemPrompts, m.Content)
+				systemBlocks = append(systemBlocks, anthropicContentBlock{Type: "text", Text: m.Content, CacheControl: cacheControl(m)})
+			}
+			continue
+		}
+		if m.Role == "tool" {
+			// Every result for one assistant turn goes in a single user turn.
+			block := anthropicContentBlock{Type: "tool_result", ToolUseID: m.ToolCallID, Content: m.Content}
+			if last := len(messages) - 1; last >= 0 && isAnthropicToolResultTurn(messages[last]) {
+				messages[last].Content = append(messages[last].Content, block)
+			} else {
+				messages = append(messages, anthropicMessage{Role: "user", Content: []anthropicContentBlock{block}})
 			}
 			continue
 		}
 
-		content := make([]anthropicContentBlock, 0, 1+len(m.ImageURLs))
+		content := make([]anthropicContentBlock, 0, 1+len(m.ImageURLs)+len(m.ToolCalls))
 		if m.Content != "" {
 			content = append(content, anthropicContentBlock{
 				Type: "text",
@@ -116,7 +255,7 @@ func (p *AnthropicProvider) Complete(ctx context.Context, req CompletionRequest)
 		for _, rawImage := range m.ImageURLs {
 			image, err := normalizeImageInput(rawImage)
 			if err != nil {
-				return CompletionResponse{}, fmt.Errorf("normalize image for Anthropic: %w", err)
+				return anthropicRequest{}, fmt.Errorf("normalize image for Anthropic: %w", err)
 			}
 
 			block := anthropicContentBlock{Type: "image"}
@@ -134,9 +273,17 @@ func (p *AnthropicProvider) Complete(ctx context.Context, req CompletionRequest)
 			}
 			content = append(content, block)
 		}
+		for _, call := range m.ToolCalls {
+			input := call.Arguments
+			if len(input) == 0 {
+				input = json.RawMessage("{}")
+			}
+			content = append(content, anthropicContentBlock{Type: "tool_use", ID: call.ID, Name: call.Name, Input: input})
+		}
 		if len(content) == 0 {
 			continue
 		}
+		content[len(content)-1].CacheControl = cacheControl(m)
 		messages = append(messages, anthropicMessage{
 			Role:    m.Role,
 			Content: content,
@@ -148,69 +295,75 @@ func (p *AnthropicProvider) Complete(ctx context.Context, req CompletionRequest)
 		MaxTokens: maxTokens,
 		Messages:  messages,
 	}
-	if len(systemPrompts) > 0 {
-		body.System = strings.Join(systemPrompts, "\n\n")
+	body.System = anthropicSystem(systemBlocks)
+	if req.UserHash != "" {
+		body.Metadata = &anthropicMetadata{UserID: req.UserHash}
+	}
+	for _, tool := range req.Tools {
+		body.Tools = append(body.Tools, anthropicTool{Name: tool.Name, Description: tool.Description, InputSchema: tool.Parameters})
 	}
 	if req.Temperature > 0 {
 		temp := req.Temperature
 		body.Temperature = &temp
 	}
 	if err := applyAnthropicStructuredOutput(&body, req.StructuredOutput); err != nil {
-		return CompletionResponse{}, err
+		return anthropicRequest{}, err
+	}
+	return body, nil
+}
+
+// anthropicSystem joins the system blocks into one prompt string, keeping
+// them as blocks only when one marks a cache breakpoint.
+func anthropicSystem(blocks []anthropicContentBlock) any {
+	if len(blocks) == 0 {
+		return nil
+	}
+	texts := make([]string, 0, len(blocks))
+	for _, block := range blocks {
+		if block.CacheControl != nil {
+			return blocks
+		}
+		texts = append(texts, block.Text)
 	}
+	return strings.Join(texts, "\n\n")
+}
+
+// anthropicNativeStructuredOutput reports whether model accepts
+// output_config. Claude 3 and the first Claude 4 releases predate it.
+func anthropicNativeStructuredOutput(model string) bool {
+	switch {
+	case strings.HasPrefix(model, "claude-3"),
+		strings.HasPrefix(model, "claude-sonnet-4-0"),
+		strings.HasPrefix(model, "claude-sonnet-4-2025"),
+		strings.HasPrefix(model, "claude-opus-4-0"),
+		strings.HasPrefix(model, "claude-opus-4-2025"):
+		return false
+	default:
+		return true
+	}
+}
 
+func isAnthropicToolResultTurn(m anthropicMessage) bool {
+	return m.Role == "user" && len(m.Content) > 0 && m.Content[0].Type == "tool_result"
+}
+
+func (p *AnthropicProvider) newRequest(ctx context.Context, body anthropicRequest) (*http.Request, error) {
 	jsonBody, err := json.Marshal(body)
 	if err != nil {
-		return CompletionResponse{}, fmt.Errorf("marshaling request: %w", err)
+		return nil, fmt.Errorf("marshaling request: %w", err)
 	}
 
 	httpReq, err := http.NewRequestWithContext(ctx, http.MethodPost, p.baseURL+"/messages", bytes.NewReader(jsonBody))
 	if err != nil {
-		return CompletionResponse{}, err
+		return nil, err
 	}
 	httpReq.Header.Set("Content-Type", "application/json")
 	httpReq.Header.Set("x-api-key", p.apiKey)
 	httpReq.Header.Set("anthropic-version", "2023-06-01")
-
-	resp, err := p.client.Do(httpReq)
-	if err != nil {
-		return CompletionResponse{}, fmt.Errorf("anthropic API call: %w", err)
-	}
-	defer func() { _ = resp.Body.Close() }()
-
-	respBody, err := io.ReadAll(resp.Body)
-	if err != nil {
-		return CompletionResponse{}, fmt.Errorf("reading response: %w", err)
+	if body.Stream {
+		httpReq.Header.Set("Accept", "text/event-stream")
 	}
-
-	if resp.StatusCode != http.StatusOK {
-		return CompletionResponse{}, fmt.Errorf("anthropic API error %d: %s", resp.StatusCode, string(respBody))
-	}
-
-	var result struct {
-		Content []struct {
-			Text string `json:"text"`
-		} `json:"content"`
-		Model string `json:"model"`
-		Usage struct {
-			InputTokens  int `json:"input_tokens"`
-			OutputTokens int `json:"output_tokens"`
-		} `json:"usage"`
-	}
-	if err := json.Unmarshal(respBody, &result); err != nil {
-		return CompletionResponse{}, fmt.Errorf("parsing response: %w", err)
-	}
-
-	if len(result.Content) == 0 {
-		return CompletionResponse{}, fmt.Errorf("anthropic returned no content")
-	}
-
-	return CompletionResponse{
-		Content:      result.Content[0].Text,
-		Model:        result.Model,
-		InputTokens:  result.Usage.InputTokens,
-		OutputTokens: result.Usage.OutputTokens,
-	}, nil
+	return httpReq, nil
 }
 
 func applyAnthropicStructuredOutput(body *anthropicRequest, spec *StructuredOutputSpec) error {
@@ -224,6 +377,17 @@ func applyAnthropicStructuredOutput(body *anthropicRequest, spec *StructuredOutp
 		return fmt.Errorf("structured output JSON schema is required")
 	}
 
+	if !anthropicNativeStructuredOutput(body.Model) {
+		// Force a single tool whose input is the schema; Complete returns
+		// that input as the structured output.
+		body.Tools = append(body.Tools, anthropicTool{
+			Name:        spec.Name,
+			Description: "Return the response in this structure.",
+			InputSchema: spec.JSONSchema,
+		})
+		body.ToolChoice = &anthropicToolChoice{Type: "tool", Name: spec.Name}
+		return nil
+	}
 	body.OutputConfig = &anthropicOutputConfig{
 		Format: anthropicOutputFormat{
 			Type:   "json_schema",
@@ -233,20 +397,134 @@ func applyAnthropicStructuredOutput(body *anthropicRequest, spec *StructuredOutp
 	return nil
 }
 
+// StreamComplete streams a reply over the Messages API event stream. Text
+// arrives in content_block_delta events; the closing Done chunk carries the
+// usage from message_start and message_delta.
 func (p *AnthropicProvider) StreamComplete(ctx context.Context, req CompletionRequest) (<-chan StreamChunk, error) {
-	ch := make(chan StreamChunk, 1)
+	body, err := buildAnthropicRequest(req)
+	if err != nil {
+		return nil, err
+	}
+	body.Stream = true
+	httpReq, err := p.newRequest(ctx, body)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := p.client.Do(httpReq)
+	if err != nil {
+		return nil, fmt.Errorf("anthropic API call: %w", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		defer func() { _ = resp.Body.Close() }()
+		respBody, _ := io.ReadAll(resp.Body)
+		return nil, apperr.HTTPStatus(resp.StatusCode, fmt.Errorf("anthropic API error %d: %s", resp.StatusCode, string(respBody)))
+	}
+
+	ch := make(chan StreamChunk)
 	go func() {
 		defer close(ch)
-		resp, err := p.Complete(ctx, req)
+		defer func() { _ = resp.Body.Close() }()
+		send := func(chunk StreamChunk) bool {
+			select {
+			case ch <- chunk:
+				return true
+			case <-ctx.Done():
+				return false
+			}
+		}
+		final, err := readAnthropicStream(resp.Body, func(text string) bool {
+			return send(StreamChunk{Content: text})
+		})
 		if err != nil {
-			ch <- StreamChunk{Error: err}
+			send(StreamChunk{Error: err})
 			return
 		}
-		ch <- StreamChunk{Content: resp.Content, Done: true}
+		final.Done = true
+		send(final)
 	}()
 	return ch, nil
 }
 
+// anthropicStreamEvent covers the fields read from each Messages API stream
+// event; the type field says which of them are set.
+type anthropicStreamEvent struct {
+	Type    string `json:"type"`
+	Message struct {
+		Model string         `json:"model"`
+		Usage anthropicUsage `json:"usage"`
+	} `json:"message"`
+	Delta struct {
+		Type       string `json:"type"`
+		Text       string `json:"text"`
+		StopReason string `json:"stop_reason"`
+	} `json:"delta"`
+	Usage anthropicUsage `json:"usage"`
+	Error struct {
+		Type    string `json:"type"`
+		Message string `json:"message"`
+	} `json:"error"`
+}
+
+// anthropicUsage splits prompt tokens three ways: input_tokens counts only
+// those after the last cache breakpoint.
+type anthropicUsage struct {
+	InputTokens              int `json:"input_tokens"`
+	OutputTokens             int `json:"output_tokens"`
+	CacheCreationInputTokens int `json:"cache_creation_input_tokens"`
+	CacheReadInputTokens     int `json:"cache_read_input_tokens"`
+}
+
+// promptTokens is the whole prompt, cached or not.
+func (u anthropicUsage) promptTokens() int {
+	return u.InputTokens + u.CacheCreationInputTokens + u.CacheReadInputTokens
+}
+
+// readAnthropicStream passes each text delta in r to emit and returns the
+// model and usage for the final chunk. It stops early when emit returns false.
+func readAnthropicStream(r io.Reader, emit func(string) bool) (StreamChunk, error) {
+	var final StreamChunk
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 64<<10), 1<<20)
+	for scanner.Scan() {
+		data, ok := strings.CutPrefix(scanner.Text(), "data:")
+		if !ok {
+			continue
+		}
+		var event anthropicStreamEvent
+		if err := json.Unmarshal([]byte(strings.TrimSpace(data)), &event); err != nil {
+			return StreamChunk{}, fmt.Errorf("parsing stream event: %w", err)
+		}
+		switch event.Type {
+		case "message_start":
+			final.Model = event.Message.Model
+			final.InputTokens = event.Message.Usage.promptTokens()
+			final.OutputTokens = event.Message.Usage.OutputTokens
+		case "content_block_delta":
+			if event.Delta.Type == "text_delta" && event.Delta.Text != "" && !emit(event.Delta.Text) {
+				return final, nil
+			}
+		case "message_delta":
+			// message_delta usage counts are cumulative for the message.
+			if event.Usage.OutputTokens > 0 {
+				final.OutputTokens = event.Usage.OutputTokens
+			}
+			if event.Usage.promptTokens() > 0 {
+				final.InputTokens = event.Usage.promptTokens()
+			}
+			final.Truncated = event.Delta.StopReason == "max_tokens"
+		case "message_stop":
+			return final, nil
+		case "error":
+			return StreamChunk{}, fmt.Errorf("anthropic stream error %s: %s", event.Error.Type, event.Error.Message)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return StreamChunk{}, fmt.Errorf("reading stream: %w", err)
+	}
+	return StreamChunk{}, fmt.Errorf("anthropic stream ended before message_stop")
+}
+
 func (p *AnthropicProvider) Models() []ModelInfo {
 	return []ModelInfo{
 		{ID: "claude-sonnet-4-6", Name: "Claude Sonnet 4.6", MaxTokens: 200000, Description: "Best for teaching"},
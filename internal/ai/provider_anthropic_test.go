@@ -7,6 +7,7 @@ import (
 	"context"
 	"encoding/base64"
 	"encoding/json"
+	"io"
 	"net/http"
 	"net/http/httptest"
 	"strings"
@@ -184,6 +185,43 @@ func TestAnthropicProvider_Complete_StructuredOutput_UsesStableOutputConfigWitho
 	}
 }
 
+func TestAnthropicProvider_Complete_StructuredOutput_ForcesToolOnOlderModels(t *testing.T) {
+	var received anthropicRequest
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_ = json.NewDecoder(r.Body).Decode(&received)
+		_, _ = w.Write([]byte(`{"content":[
+			{"type":"tool_use","id":"toolu_1","name":"tutor_response","input":{"final_answer":"ok"}}
+		],"model":"claude-3-5-haiku-20241022","stop_reason":"tool_use"}`))
+	}))
+	defer server.Close()
+
+	provider, _ := NewAnthropicProvider("test-key", WithAnthropicBaseURL(server.URL))
+	resp, err := provider.Complete(context.Background(), CompletionRequest{
+		Model:    "claude-3-5-haiku-20241022",
+		Messages: []Message{{Role: "user", Content: "hello"}},
+		StructuredOutput: &StructuredOutputSpec{
+			Name:       "tutor_response",
+			JSONSchema: testStructuredSchema,
+		},
+	})
+	if err != nil {
+		t.Fatalf("Complete() error = %v", err)
+	}
+
+	if received.OutputConfig != nil {
+		t.Fatalf("output_config = %+v, want none for a model without native structured output", received.OutputConfig)
+	}
+	if received.ToolChoice == nil || received.ToolChoice.Type != "tool" || received.ToolChoice.Name != "tutor_response" {
+		t.Fatalf("tool_choice = %+v, want the schema tool forced", received.ToolChoice)
+	}
+	if len(received.Tools) != 1 || received.Tools[0].Name != "tutor_response" {
+		t.Fatalf("tools = %+v, want the schema tool", received.Tools)
+	}
+	if string(resp.StructuredOutput) != `{"final_answer":"ok"}` || len(resp.ToolCalls) != 0 {
+		t.Fatalf("structured = %s, tool calls = %+v; want the forced tool input only", resp.StructuredOutput, resp.ToolCalls)
+	}
+}
+
 func TestAnthropicProvider_Complete_PlainTextRequestOmitsBetaHeader(t *testing.T) {
 	var receivedBeta string
 
@@ -367,6 +405,127 @@ func TestAnthropicProvider_Complete_APIError(t *testing.T) {
 	}
 }
 
+func TestAnthropicProvider_StreamComplete(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var body map[string]interface{}
+		_ = json.NewDecoder(r.Body).Decode(&body)
+		if body["stream"] != true {
+			t.Errorf("stream = %v, want true", body["stream"])
+		}
+		if body["system"] != "Be concise." {
+			t.Errorf("system = %v, want Be concise.", body["system"])
+		}
+
+		w.Header().Set("Content-Type", "text/event-stream")
+		_, _ = io.WriteString(w, `event: message_start
+data: {"type":"message_start","message":{"model":"claude-sonnet-4-6","usage":{"input_tokens":25,"output_tokens":1}}}
+
+event: content_block_start
+data: {"type":"content_block_start","index":0,"content_block":{"type":"text","text":""}}
+
+event: ping
+data: {"type":"ping"}
+
+event: content_block_delta
+data: {"type":"content_block_delta","index":0,"delta":{"type":"text_delta","text":"x = "}}
+
+event: content_block_delta
+data: {"type":"content_block_delta","index":0,"delta":{"type":"text_delta","text":"4"}}
+
+event: content_block_stop
+data: {"type":"content_block_stop","index":0}
+
+event: message_delta
+data: {"type":"message_delta","delta":{"stop_reason":"max_tokens"},"usage":{"output_tokens":15}}
+
+event: message_stop
+data: {"type":"message_stop"}
+
+`)
+	}))
+	defer server.Close()
+
+	provider, err := NewAnthropicProvider("test-key", WithAnthropicBaseURL(server.URL))
+	if err != nil {
+		t.Fatalf("NewAnthropicProvider() error = %v", err)
+	}
+	ch, err := provider.StreamComplete(context.Background(), CompletionRequest{
+		Messages: []Message{
+			{Role: "system", Content: "Be concise."},
+			{Role: "user", Content: "Solve 2x = 8"},
+		},
+	})
+	if err != nil {
+		t.Fatalf("StreamComplete() error = %v", err)
+	}
+
+	var (
+		text  strings.Builder
+		final StreamChunk
+		count int
+	)
+	for chunk := range ch {
+		if chunk.Error != nil {
+			t.Fatalf("stream chunk error = %v", chunk.Error)
+		}
+		if chunk.Done {
+			final = chunk
+			continue
+		}
+		count++
+		text.WriteString(chunk.Content)
+	}
+	if text.String() != "x = 4" || count != 2 {
+		t.Fatalf("streamed %d chunks %q, want 2 chunks \"x = 4\"", count, text.String())
+	}
+	if !final.Done || final.Model != "claude-sonnet-4-6" || final.InputTokens != 25 || final.OutputTokens != 15 || !final.Truncated {
+		t.Fatalf("final chunk = %+v, want model, 25 in / 15 out, truncated", final)
+	}
+}
+
+func TestAnthropicProvider_StreamComplete_ErrorEvent(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = io.WriteString(w, `event: message_start
+data: {"type":"message_start","message":{"model":"claude-sonnet-4-6","usage":{"input_tokens":5}}}
+
+event: error
+data: {"type":"error","error":{"type":"overloaded_error","message":"Overloaded"}}
+
+`)
+	}))
+	defer server.Close()
+
+	provider, _ := NewAnthropicProvider("test-key", WithAnthropicBaseURL(server.URL))
+	ch, err := provider.StreamComplete(context.Background(), CompletionRequest{
+		Messages: []Message{{Role: "user", Content: "hi"}},
+	})
+	if err != nil {
+		t.Fatalf("StreamComplete() error = %v", err)
+	}
+	var last StreamChunk
+	for chunk := range ch {
+		last = chunk
+	}
+	if last.Error == nil || !strings.Contains(last.Error.Error(), "overloaded_error") {
+		t.Fatalf("last chunk = %+v, want overloaded_error", last)
+	}
+}
+
+func TestAnthropicProvider_StreamComplete_APIError(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusUnauthorized)
+		_, _ = io.WriteString(w, `{"type":"error","error":{"type":"authentication_error"}}`)
+	}))
+	defer server.Close()
+
+	provider, _ := NewAnthropicProvider("test-key", WithAnthropicBaseURL(server.URL))
+	if _, err := provider.StreamComplete(context.Background(), CompletionRequest{
+		Messages: []Message{{Role: "user", Content: "hi"}},
+	}); err == nil || !strings.Contains(err.Error(), "401") {
+		t.Fatalf("StreamComplete() error = %v, want 401", err)
+	}
+}
+
 func TestAnthropicProvider_HealthCheck(t *testing.T) {
 	tests := []struct {
 		name       string
@@ -417,3 +576,89 @@ func TestAnthropicProvider_Models(t *testing.T) {
 		}
 	}
 }
+
+func TestAnthropicProvider_Complete_EncodesToolsAndDecodesToolUse(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req anthropicRequest
+		_ = json.NewDecoder(r.Body).Decode(&req)
+
+		if len(req.Tools) != 1 || req.Tools[0].Name != "calculator" || len(req.Tools[0].InputSchema) == 0 {
+			t.Errorf("tools = %+v, want the calculator tool", req.Tools)
+		}
+		if len(req.Messages) != 3 {
+			t.Fatalf("messages = %+v, want 3", req.Messages)
+		}
+		use := req.Messages[1].Content
+		if req.Messages[1].Role != "assistant" || len(use) != 1 || use[0].Type != "tool_use" || use[0].ID != "call_1" || use[0].Name != "calculator" {
+			t.Errorf("assistant turn = %+v, want a tool_use block", req.Messages[1])
+		}
+		result := req.Messages[2].Content
+		if req.Messages[2].Role != "user" || len(result) != 1 || result[0].Type != "tool_result" || result[0].ToolUseID != "call_1" || result[0].Content != "84" {
+			t.Errorf("result turn = %+v, want a tool_result block", req.Messages[2])
+		}
+
+		_, _ = w.Write([]byte(`{"content":[
+			{"type":"text","text":"Let me check the syllabus."},
+			{"type":"tool_use","id":"toolu_2","name":"curriculum_lookup","input":{"topic":"F1-02"}}
+		],"model":"claude-sonnet-4-6","stop_reason":"tool_use"}`))
+	}))
+	defer server.Close()
+
+	provider, err := NewAnthropicProvider("test-key", WithAnthropicBaseURL(server.URL))
+	if err != nil {
+		t.Fatalf("NewAnthropicProvider() error = %v", err)
+	}
+	resp, err := provider.Complete(context.Background(), calculatorToolRequest())
+	if err != nil {
+		t.Fatalf("Complete() error = %v", err)
+	}
+	if resp.Content != "Let me check the syllabus." {
+		t.Errorf("content = %q", resp.Content)
+	}
+	if len(resp.ToolCalls) != 1 || resp.ToolCalls[0].ID != "toolu_2" || string(resp.ToolCalls[0].Arguments) != `{"topic":"F1-02"}` {
+		t.Fatalf("tool calls = %+v", resp.ToolCalls)
+	}
+}
+
+func TestAnthropicComplete_MarksCacheBreakpointsAndReportsCachedTokens(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var body struct {
+			System []struct {
+				Text         string `json:"text"`
+				CacheControl *struct {
+					Type string `json:"type"`
+				} `json:"cache_control"`
+			} `json:"system"`
+			Messages []struct {
+				Content []map[string]any `json:"content"`
+			} `json:"messages"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+			t.Fatalf("system should be sent as blocks: %v", err)
+		}
+		if len(body.System) != 2 || body.System[0].CacheControl == nil || body.System[0].CacheControl.Type != "ephemeral" || body.System[1].CacheControl != nil {
+			t.Errorf("system = %+v, want only the tutor prompt marked ephemeral", body.System)
+		}
+		if _, ok := body.Messages[0].Content[0]["cache_control"]; ok {
+			t.Errorf("user block = %+v, want no cache_control", body.Messages[0].Content[0])
+		}
+		_, _ = w.Write([]byte(`{"content":[{"type":"text","text":"x = 4"}],"model":"claude-sonnet-4-6",
+			"usage":{"input_tokens":20,"output_tokens":5,"cache_creation_input_tokens":0,"cache_read_input_tokens":3000}}`))
+	}))
+	defer server.Close()
+
+	provider, _ := NewAnthropicProvider("test-key", WithAnthropicBaseURL(server.URL))
+	resp, err := provider.Complete(context.Background(), CompletionRequest{
+		Messages: []Message{
+			{Role: "system", Content: "You are a math tutor. Teaching notes: ...", CacheBreakpoint: true},
+			{Role: "system", Content: "Current topic: linear equations."},
+			{Role: "user", Content: "Solve 2x = 8"},
+		},
+	})
+	if err != nil {
+		t.Fatalf("Complete() error = %v", err)
+	}
+	if resp.InputTokens != 3020 || resp.CacheReadTokens != 3000 || resp.CacheWriteTokens != 0 {
+		t.Fatalf("usage = %d input, %d cache read, %d cache write; want 3020, 3000, 0", resp.InputTokens, resp.CacheReadTokens, resp.CacheWriteTokens)
+	}
+}
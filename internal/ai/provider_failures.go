@@ -0,0 +1,53 @@
+// Copyright 2026 the P&AI authors. All rights reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package ai
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/p-n-ai/pai-bot/internal/apperr"
+)
+
+var (
+	errNoProviders           = apperr.Dependency(errors.New("all AI providers failed (no providers registered)"))
+	errCircuitOpen           = apperr.Transient(errors.New("circuit open"))
+	errStructuredCircuitOpen = apperr.Transient(errors.New("structured circuit open"))
+	errStructuredUnsupported = apperr.Permanent(errors.New("structured output unsupported"))
+)
+
+// providerFailures collects why each provider in a request's plan was
+// skipped or failed, for the error returned when none succeeds.
+type providerFailures struct {
+	reasons   []string
+	transient int
+	permanent int
+}
+
+func (f *providerFailures) add(provider string, err error) {
+	f.reasons = append(f.reasons, fmt.Sprintf("%s: %v", provider, err))
+	switch apperr.ClassOf(err) {
+	case apperr.ClassTransient:
+		f.transient++
+	case apperr.ClassPermanent:
+		f.permanent++
+	}
+}
+
+// err reports that every provider failed. It is transient when every
+// provider was only rate limited, overloaded or cooling down, so the learner
+// is asked to try again shortly; permanent when every provider rejected the
+// request itself; and a dependency failure otherwise.
+func (f *providerFailures) err() error {
+	err := fmt.Errorf("all AI providers failed: %s", strings.Join(f.reasons, "; "))
+	switch n := len(f.reasons); {
+	case n > 0 && f.transient == n:
+		return apperr.Transient(err)
+	case n > 0 && f.permanent == n:
+		return apperr.Permanent(err)
+	default:
+		return apperr.Dependency(err)
+	}
+}
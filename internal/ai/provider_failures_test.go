@@ -0,0 +1,80 @@
+// Copyright 2026 the P&AI authors. All rights reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package ai_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/p-n-ai/pai-bot/internal/ai"
+	"github.com/p-n-ai/pai-bot/internal/apperr"
+)
+
+func TestRouterAllProvidersFailedIsClassified(t *testing.T) {
+	busy := apperr.HTTPStatus(http.StatusTooManyRequests, errors.New("429"))
+	badKey := apperr.HTTPStatus(http.StatusUnauthorized, errors.New("401"))
+	badRequest := apperr.HTTPStatus(http.StatusBadRequest, errors.New("400"))
+
+	tests := []struct {
+		name string
+		errs []error
+		want apperr.Class
+	}{
+		{name: "all rate limited", errs: []error{busy, busy}, want: apperr.ClassTransient},
+		{name: "all rejected the request", errs: []error{badRequest, badRequest}, want: apperr.ClassPermanent},
+		{name: "one misconfigured", errs: []error{busy, badKey}, want: apperr.ClassDependency},
+		{name: "unclassified", errs: []error{errors.New("boom")}, want: apperr.ClassDependency},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			router := newTestRouter()
+			for i, err := range tt.errs {
+				router.Register(string(rune('a'+i)), &ai.MockProvider{Err: err})
+			}
+			_, err := router.Complete(context.Background(), ai.CompletionRequest{
+				Messages: []ai.Message{{Role: "user", Content: "hi"}},
+			})
+			if err == nil || !strings.HasPrefix(err.Error(), "all AI providers failed: ") {
+				t.Fatalf("Complete() error = %v", err)
+			}
+			if got := apperr.ClassOf(err); got != tt.want {
+				t.Fatalf("ClassOf(%v) = %q, want %q", err, got, tt.want)
+			}
+		})
+	}
+
+	_, err := newTestRouter().Complete(context.Background(), ai.CompletionRequest{})
+	if got := apperr.ClassOf(err); got != apperr.ClassDependency {
+		t.Fatalf("no providers: ClassOf(%v) = %q, want dependency", err, got)
+	}
+}
+
+func TestProviderHTTPErrorsAreClassifiedByStatus(t *testing.T) {
+	status := http.StatusServiceUnavailable
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, `{"error":"overloaded"}`, status)
+	}))
+	defer server.Close()
+
+	provider, err := ai.NewAnthropicProvider("test-key", ai.WithAnthropicBaseURL(server.URL))
+	if err != nil {
+		t.Fatalf("NewAnthropicProvider() error = %v", err)
+	}
+	req := ai.CompletionRequest{Messages: []ai.Message{{Role: "user", Content: "hi"}}}
+
+	_, err = provider.Complete(context.Background(), req)
+	if got := apperr.ClassOf(err); got != apperr.ClassTransient {
+		t.Fatalf("503: ClassOf(%v) = %q, want transient", err, got)
+	}
+
+	status = http.StatusUnauthorized
+	_, err = provider.Complete(context.Background(), req)
+	if got := apperr.ClassOf(err); got != apperr.ClassDependency {
+		t.Fatalf("401: ClassOf(%v) = %q, want dependency", err, got)
+	}
+}
@@ -10,6 +10,10 @@ import (
 	"fmt"
 	"io"
 	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/p-n-ai/pai-bot/internal/apperr"
 )
 
 const defaultGeminiBaseURL = "https://generativelanguage.googleapis.com/v1beta"
@@ -44,7 +48,7 @@ func NewGoogleProvider(apiKey string, opts ...GoogleOption) *GoogleProvider {
 	p := &GoogleProvider{
 		apiKey:  apiKey,
 		baseURL: defaultGeminiBaseURL,
-		client:  http.DefaultClient,
+		client:  defaultHTTPClient,
 	}
 	for _, opt := range opts {
 		opt(p)
@@ -57,6 +61,17 @@ type geminiRequest struct {
 	SystemInstruction *geminiInstruction      `json:"systemInstruction,omitempty"`
 	Contents          []geminiContent         `json:"contents"`
 	GenerationConfig  *geminiGenerationConfig `json:"generationConfig,omitempty"`
+	Tools             []geminiTool            `json:"tools,omitempty"`
+}
+
+type geminiTool struct {
+	FunctionDeclarations []geminiFunctionDeclaration `json:"functionDeclarations"`
+}
+
+type geminiFunctionDeclaration struct {
+	Name                 string          `json:"name"`
+	Description          string          `json:"description,omitempty"`
+	ParametersJSONSchema json.RawMessage `json:"parametersJsonSchema,omitempty"`
 }
 
 type geminiContent struct {
@@ -69,8 +84,28 @@ type geminiInstruction struct {
 }
 
 type geminiPart struct {
-	Text       string            `json:"text,omitempty"`
-	InlineData *geminiInlineData `json:"inlineData,omitempty"`
+	Text             string                  `json:"text,omitempty"`
+	InlineData       *geminiInlineData       `json:"inlineData,omitempty"`
+	FunctionCall     *geminiFunctionCall     `json:"functionCall,omitempty"`
+	FunctionResponse *geminiFunctionResponse `json:"functionResponse,omitempty"`
+	// ThoughtSignature comes with function calls on thinking models and must
+	// be echoed back on the same part.
+	ThoughtSignature string `json:"thoughtSignature,omitempty"`
+}
+
+type geminiFunctionCall struct {
+	ID   string          `json:"id,omitempty"`
+	Name string          `json:"name"`
+	Args json.RawMessage `json:"args,omitempty"`
+}
+
+// geminiFunctionResponse answers a call by name; Response must be an object,
+// so the result text is wrapped in {"content": ...}.
+type geminiFunctionResponse struct {
+	Name     string `json:"name"`
+	Response struct {
+		Content string `json:"content"`
+	} `json:"response"`
 }
 
 type geminiInlineData struct {
@@ -89,10 +124,9 @@ type geminiGenerationConfig struct {
 type geminiResponse struct {
 	Candidates []struct {
 		Content struct {
-			Parts []struct {
-				Text string `json:"text"`
-			} `json:"parts"`
+			Parts []geminiPart `json:"parts"`
 		} `json:"content"`
+		FinishReason string `json:"finishReason"`
 	} `json:"candidates"`
 	UsageMetadata struct {
 		PromptTokenCount     int `json:"promptTokenCount"`
@@ -120,8 +154,19 @@ func (p *GoogleProvider) Complete(ctx context.Context, req CompletionRequest) (C
 			}
 			continue
 		}
+		if role == "tool" {
+			// Every result for one model turn goes in a single user turn.
+			part := geminiPart{FunctionResponse: &geminiFunctionResponse{Name: m.ToolName}}
+			part.FunctionResponse.Response.Content = m.Content
+			if last := len(contents) - 1; last >= 0 && isGeminiFunctionResponseTurn(contents[last]) {
+				contents[last].Parts = append(contents[last].Parts, part)
+			} else {
+				contents = append(contents, geminiContent{Role: "user", Parts: []geminiPart{part}})
+			}
+			continue
+		}
 
-		parts := make([]geminiPart, 0, 1+len(m.ImageURLs))
+		parts := make([]geminiPart, 0, 1+len(m.ImageURLs)+len(m.ToolCalls))
 		if m.Content != "" {
 			parts = append(parts, geminiPart{Text: m.Content})
 		}
@@ -143,6 +188,12 @@ func (p *GoogleProvider) Complete(ctx context.Context, req CompletionRequest) (C
 				},
 			})
 		}
+		for _, call := range m.ToolCalls {
+			parts = append(parts, geminiPart{
+				FunctionCall:     &geminiFunctionCall{Name: call.Name, Args: call.Arguments},
+				ThoughtSignature: call.Signature,
+			})
+		}
 		if len(parts) == 0 {
 			continue
 		}
@@ -156,6 +207,13 @@ func (p *GoogleProvider) Complete(ctx context.Context, req CompletionRequest) (C
 	if len(systemParts) > 0 {
 		gemReq.SystemInstruction = &geminiInstruction{Parts: systemParts}
 	}
+	if len(req.Tools) > 0 {
+		declarations := make([]geminiFunctionDeclaration, len(req.Tools))
+		for i, tool := range req.Tools {
+			declarations[i] = geminiFunctionDeclaration{Name: tool.Name, Description: tool.Description, ParametersJSONSchema: tool.Parameters}
+		}
+		gemReq.Tools = []geminiTool{{FunctionDeclarations: declarations}}
+	}
 	if req.MaxTokens > 0 || req.Temperature > 0 {
 		config := &geminiGenerationConfig{}
 		if req.MaxTokens > 0 {
@@ -195,7 +253,7 @@ func (p *GoogleProvider) Complete(ctx context.Context, req CompletionRequest) (C
 	}
 
 	if resp.StatusCode != http.StatusOK {
-		return CompletionResponse{}, fmt.Errorf("gemini api error (status %d): %s", resp.StatusCode, string(respBody))
+		return CompletionResponse{}, apperr.HTTPStatus(resp.StatusCode, fmt.Errorf("gemini api error (status %d): %s", resp.StatusCode, string(respBody)))
 	}
 
 	var gemResp geminiResponse
@@ -207,14 +265,38 @@ func (p *GoogleProvider) Complete(ctx context.Context, req CompletionRequest) (C
 		return CompletionResponse{}, fmt.Errorf("no content in response")
 	}
 
+	var text strings.Builder
+	var toolCalls []ToolCall
+	for _, part := range gemResp.Candidates[0].Content.Parts {
+		text.WriteString(part.Text)
+		if call := part.FunctionCall; call != nil {
+			// Gemini matches results by name; IDs are only for the caller.
+			id := call.ID
+			if id == "" {
+				id = "call_" + strconv.Itoa(len(toolCalls)+1)
+			}
+			args := call.Args
+			if len(args) == 0 {
+				args = json.RawMessage("{}")
+			}
+			toolCalls = append(toolCalls, ToolCall{ID: id, Name: call.Name, Arguments: args, Signature: part.ThoughtSignature})
+		}
+	}
+
 	return CompletionResponse{
-		Content:      gemResp.Candidates[0].Content.Parts[0].Text,
+		Content:      text.String(),
 		Model:        model,
 		InputTokens:  gemResp.UsageMetadata.PromptTokenCount,
 		OutputTokens: gemResp.UsageMetadata.CandidatesTokenCount,
+		Truncated:    gemResp.Candidates[0].FinishReason == "MAX_TOKENS",
+		ToolCalls:    toolCalls,
 	}, nil
 }
 
+func isGeminiFunctionResponseTurn(c geminiContent) bool {
+	return c.Role == "user" && len(c.Parts) > 0 && c.Parts[0].FunctionResponse != nil
+}
+
 func applyGeminiStructuredOutput(gemReq *geminiRequest, spec *StructuredOutputSpec) error {
 	if spec == nil {
 		return nil
@@ -33,18 +33,13 @@ func TestGoogleProvider_Complete(t *testing.T) {
 		_ = json.NewEncoder(w).Encode(geminiResponse{
 			Candidates: []struct {
 				Content struct {
-					Parts []struct {
-						Text string `json:"text"`
-					} `json:"parts"`
+					Parts []geminiPart `json:"parts"`
 				} `json:"content"`
+				FinishReason string `json:"finishReason"`
 			}{
 				{Content: struct {
-					Parts []struct {
-						Text string `json:"text"`
-					} `json:"parts"`
-				}{Parts: []struct {
-					Text string `json:"text"`
-				}{{Text: "Gemini response"}}}},
+					Parts []geminiPart `json:"parts"`
+				}{Parts: []geminiPart{{Text: "Gemini response"}}}},
 			},
 			UsageMetadata: struct {
 				PromptTokenCount     int `json:"promptTokenCount"`
@@ -80,18 +75,13 @@ func TestGoogleProvider_Complete_RoleMappings(t *testing.T) {
 		_ = json.NewEncoder(w).Encode(geminiResponse{
 			Candidates: []struct {
 				Content struct {
-					Parts []struct {
-						Text string `json:"text"`
-					} `json:"parts"`
+					Parts []geminiPart `json:"parts"`
 				} `json:"content"`
+				FinishReason string `json:"finishReason"`
 			}{
 				{Content: struct {
-					Parts []struct {
-						Text string `json:"text"`
-					} `json:"parts"`
-				}{Parts: []struct {
-					Text string `json:"text"`
-				}{{Text: "ok"}}}},
+					Parts []geminiPart `json:"parts"`
+				}{Parts: []geminiPart{{Text: "ok"}}}},
 			},
 		})
 	}))
@@ -150,18 +140,13 @@ func TestGoogleProvider_Complete_WithDataURLImageUsesInlineData(t *testing.T) {
 		_ = json.NewEncoder(w).Encode(geminiResponse{
 			Candidates: []struct {
 				Content struct {
-					Parts []struct {
-						Text string `json:"text"`
-					} `json:"parts"`
+					Parts []geminiPart `json:"parts"`
 				} `json:"content"`
+				FinishReason string `json:"finishReason"`
 			}{
 				{Content: struct {
-					Parts []struct {
-						Text string `json:"text"`
-					} `json:"parts"`
-				}{Parts: []struct {
-					Text string `json:"text"`
-				}{{Text: "ok"}}}},
+					Parts []geminiPart `json:"parts"`
+				}{Parts: []geminiPart{{Text: "ok"}}}},
 			},
 		})
 	}))
@@ -222,18 +207,13 @@ func TestGoogleProvider_Complete_WithRemoteImageURLDownloadsToInlineData(t *test
 		_ = json.NewEncoder(w).Encode(geminiResponse{
 			Candidates: []struct {
 				Content struct {
-					Parts []struct {
-						Text string `json:"text"`
-					} `json:"parts"`
+					Parts []geminiPart `json:"parts"`
 				} `json:"content"`
+				FinishReason string `json:"finishReason"`
 			}{
 				{Content: struct {
-					Parts []struct {
-						Text string `json:"text"`
-					} `json:"parts"`
-				}{Parts: []struct {
-					Text string `json:"text"`
-				}{{Text: "ok"}}}},
+					Parts []geminiPart `json:"parts"`
+				}{Parts: []geminiPart{{Text: "ok"}}}},
 			},
 		})
 	}))
@@ -307,18 +287,13 @@ func TestGoogleProvider_Complete_StructuredOutput_AddsJSONSchemaConfig(t *testin
 		_ = json.NewEncoder(w).Encode(geminiResponse{
 			Candidates: []struct {
 				Content struct {
-					Parts []struct {
-						Text string `json:"text"`
-					} `json:"parts"`
+					Parts []geminiPart `json:"parts"`
 				} `json:"content"`
+				FinishReason string `json:"finishReason"`
 			}{
 				{Content: struct {
-					Parts []struct {
-						Text string `json:"text"`
-					} `json:"parts"`
-				}{Parts: []struct {
-					Text string `json:"text"`
-				}{{Text: `{"final_answer":"ok"}`}}}},
+					Parts []geminiPart `json:"parts"`
+				}{Parts: []geminiPart{{Text: `{"final_answer":"ok"}`}}}},
 			},
 		})
 	}))
@@ -418,3 +393,44 @@ func TestGoogleProvider_Models(t *testing.T) {
 		}
 	}
 }
+
+func TestGoogleProvider_Complete_EncodesFunctionsAndDecodesFunctionCalls(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req geminiRequest
+		_ = json.NewDecoder(r.Body).Decode(&req)
+
+		if len(req.Tools) != 1 || len(req.Tools[0].FunctionDeclarations) != 1 || req.Tools[0].FunctionDeclarations[0].Name != "calculator" {
+			t.Errorf("tools = %+v, want the calculator declaration", req.Tools)
+		}
+		if len(req.Contents) != 3 {
+			t.Fatalf("contents = %+v, want 3", req.Contents)
+		}
+		call := req.Contents[1].Parts
+		if req.Contents[1].Role != "model" || len(call) != 1 || call[0].FunctionCall == nil || call[0].FunctionCall.Name != "calculator" || call[0].ThoughtSignature != "sig-1" {
+			t.Errorf("model turn = %+v, want a signed functionCall", req.Contents[1])
+		}
+		result := req.Contents[2].Parts
+		if req.Contents[2].Role != "user" || len(result) != 1 || result[0].FunctionResponse == nil ||
+			result[0].FunctionResponse.Name != "calculator" || result[0].FunctionResponse.Response.Content != "84" {
+			t.Errorf("result turn = %+v, want a functionResponse", req.Contents[2])
+		}
+
+		_, _ = w.Write([]byte(`{"candidates":[{"content":{"parts":[
+			{"functionCall":{"name":"curriculum_lookup","args":{"topic":"F1-02"}},"thoughtSignature":"sig-2"}
+		]},"finishReason":"STOP"}]}`))
+	}))
+	defer server.Close()
+
+	provider := NewGoogleProvider("test-key", WithGoogleBaseURL(server.URL))
+	resp, err := provider.Complete(context.Background(), calculatorToolRequest())
+	if err != nil {
+		t.Fatalf("Complete() error = %v", err)
+	}
+	if len(resp.ToolCalls) != 1 {
+		t.Fatalf("tool calls = %+v, want 1", resp.ToolCalls)
+	}
+	got := resp.ToolCalls[0]
+	if got.ID != "call_1" || got.Name != "curriculum_lookup" || string(got.Arguments) != `{"topic":"F1-02"}` || got.Signature != "sig-2" {
+		t.Fatalf("tool call = %+v, want a generated ID and the echoed signature", got)
+	}
+}
@@ -0,0 +1,154 @@
+// Copyright 2026 the P&AI authors. All rights reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package ai
+
+import (
+	"context"
+	"log/slog"
+	"sync"
+	"time"
+)
+
+// healthCheckTimeout caps one provider's HealthCheck call.
+const healthCheckTimeout = 10 * time.Second
+
+// ProviderHealth is the last health check result for one provider.
+type ProviderHealth struct {
+	Provider  string    `json:"provider"`
+	Healthy   bool      `json:"healthy"`
+	LastError string    `json:"last_error,omitempty"`
+	CheckedAt time.Time `json:"checked_at"`
+	// Since is when the provider last changed between healthy and unhealthy.
+	Since time.Time `json:"since"`
+}
+
+// ProviderHealthChange reports a provider turning unhealthy or recovering.
+type ProviderHealthChange struct {
+	Provider string
+	Healthy  bool
+	Error    string
+	At       time.Time
+}
+
+// SetHealthObserver registers a callback for providers turning unhealthy or
+// recovering. It is called outside the router's lock, after the change is
+// logged.
+func (r *Router) SetHealthObserver(observer func(ProviderHealthChange)) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.healthObserver = observer
+}
+
+// RunHealthChecks calls CheckProviderHealth every interval. Blocks until
+// ctx is cancelled.
+func (r *Router) RunHealthChecks(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			r.CheckProviderHealth(ctx)
+		}
+	}
+}
+
+// CheckProviderHealth runs every registered provider's HealthCheck in
+// parallel and records the results. Providers that fail are left out of
+// fallback until a later check passes; see ProviderHealth.
+func (r *Router) CheckProviderHealth(ctx context.Context) []ProviderHealth {
+	providers, order, gen := r.snapshotProviders()
+	errs := make([]error, len(order))
+	var wg sync.WaitGroup
+	for i, name := range order {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			checkCtx, cancel := context.WithTimeout(ctx, healthCheckTimeout)
+			defer cancel()
+			errs[i] = providers[name].HealthCheck(checkCtx)
+		}()
+	}
+	wg.Wait()
+	if ctx.Err() != nil {
+		return r.ProviderHealth()
+	}
+
+	now := time.Now()
+	var changes []ProviderHealthChange
+	r.mu.Lock()
+	if gen != r.gen {
+		// The provider set was replaced mid-check; these results are stale.
+		r.mu.Unlock()
+		return r.ProviderHealth()
+	}
+	if r.health == nil {
+		r.health = make(map[string]ProviderHealth)
+	}
+	for i, name := range order {
+		next := ProviderHealth{Provider: name, Healthy: errs[i] == nil, CheckedAt: now, Since: now}
+		if errs[i] != nil {
+			next.LastError = errs[i].Error()
+		}
+		prev, seen := r.health[name]
+		if seen && prev.Healthy == next.Healthy {
+			next.Since = prev.Since
+		} else if seen || !next.Healthy {
+			changes = append(changes, ProviderHealthChange{Provider: name, Healthy: next.Healthy, Error: next.LastError, At: now})
+		}
+		r.health[name] = next
+	}
+	observer := r.healthObserver
+	r.mu.Unlock()
+
+	for _, change := range changes {
+		if change.Healthy {
+			slog.Info("AI provider recovered, back in fallback", "provider", change.Provider)
+		} else {
+			slog.Warn("AI provider unhealthy, skipping in fallback", "provider", change.Provider, "error", change.Error)
+		}
+		if observer != nil {
+			observer(change)
+		}
+	}
+	return r.ProviderHealth()
+}
+
+// ProviderHealth returns the last health check result of each registered
+// provider in fallback order. Providers not yet checked count as healthy.
+func (r *Router) ProviderHealth() []ProviderHealth {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	out := make([]ProviderHealth, 0, len(r.fallback))
+	for _, name := range r.fallback {
+		health, ok := r.health[name]
+		if !ok {
+			health = ProviderHealth{Provider: name, Healthy: true}
+		}
+		out = append(out, health)
+	}
+	return out
+}
+
+// skipUnhealthy drops steps on providers whose last health check failed.
+// When every step is unhealthy the plan is kept whole, so a health check
+// that is wrong cannot take the router down.
+func (r *Router) skipUnhealthy(steps []routeStep) []routeStep {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	if len(r.health) == 0 {
+		return steps
+	}
+	healthy := make([]routeStep, 0, len(steps))
+	for _, step := range steps {
+		if health, ok := r.health[step.provider]; !ok || health.Healthy {
+			healthy = append(healthy, step)
+		}
+	}
+	if len(healthy) == 0 {
+		return steps
+	}
+	return healthy
+}
@@ -0,0 +1,80 @@
+// Copyright 2026 the P&AI authors. All rights reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package ai_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/p-n-ai/pai-bot/internal/ai"
+)
+
+// healthToggleProvider answers completions but fails HealthCheck while down.
+type healthToggleProvider struct {
+	countingProvider
+	down bool
+}
+
+func (p *healthToggleProvider) HealthCheck(_ context.Context) error {
+	if p.down {
+		return errors.New("connection refused")
+	}
+	return nil
+}
+
+func TestRouter_HealthChecksSkipUnhealthyProviderUntilRecovered(t *testing.T) {
+	router := newTestRouter()
+	primary := &healthToggleProvider{countingProvider: countingProvider{response: "primary"}, down: true}
+	backup := &countingProvider{response: "backup"}
+	router.Register("openai", primary)
+	router.Register("ollama", backup)
+	var changes []ai.ProviderHealthChange
+	router.SetHealthObserver(func(change ai.ProviderHealthChange) {
+		changes = append(changes, change)
+	})
+
+	complete := func() string {
+		t.Helper()
+		resp, err := router.Complete(context.Background(), ai.CompletionRequest{
+			Messages: []ai.Message{{Role: "user", Content: "hi"}},
+		})
+		if err != nil {
+			t.Fatalf("Complete() error = %v", err)
+		}
+		return resp.Content
+	}
+
+	health := router.CheckProviderHealth(context.Background())
+	if len(health) != 2 || health[0].Healthy || health[0].LastError != "connection refused" || !health[1].Healthy {
+		t.Fatalf("health = %+v, want openai unhealthy and ollama healthy", health)
+	}
+	if got := complete(); got != "backup" || primary.calls != 0 {
+		t.Fatalf("content = %q, primary calls = %d, want the unhealthy provider skipped", got, primary.calls)
+	}
+
+	primary.down = false
+	router.CheckProviderHealth(context.Background())
+	if got := complete(); got != "primary" {
+		t.Fatalf("content = %q, want the recovered provider back first", got)
+	}
+
+	if len(changes) != 2 || changes[0].Healthy || !changes[1].Healthy || changes[0].Provider != "openai" {
+		t.Fatalf("changes = %+v, want openai down then recovered", changes)
+	}
+}
+
+func TestRouter_HealthChecksKeepPlanWhenEveryProviderUnhealthy(t *testing.T) {
+	router := newTestRouter()
+	only := &healthToggleProvider{countingProvider: countingProvider{response: "still here"}, down: true}
+	router.Register("openai", only)
+
+	router.CheckProviderHealth(context.Background())
+	resp, err := router.Complete(context.Background(), ai.CompletionRequest{
+		Messages: []ai.Message{{Role: "user", Content: "hi"}},
+	})
+	if err != nil || resp.Content != "still here" {
+		t.Fatalf("Complete() = %q, %v, want the only provider still tried", resp.Content, err)
+	}
+}
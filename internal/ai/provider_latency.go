@@ -0,0 +1,198 @@
+// Copyright 2026 the P&AI authors. All rights reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package ai
+
+import (
+	"encoding/json"
+	"math"
+	"net/http"
+	"slices"
+	"sort"
+	"time"
+)
+
+const (
+	// latencyWindow is how many of a provider's latest successful calls its
+	// percentiles cover.
+	latencyWindow = 100
+	// minLatencySamples keeps a provider with a handful of calls from being
+	// judged slow on one unlucky request.
+	minLatencySamples = 10
+	// latencyMaxAge is how long a sample counts. A provider demoted as slow
+	// only gets calls when the faster ones fail, so without this its p95
+	// would never recover; once its old samples age out it is back in its
+	// configured place and measured afresh.
+	latencyMaxAge = 10 * time.Minute
+)
+
+// ProviderLatency is one provider's rolling latency over its latest
+// successful calls in the last ten minutes. Each call is timed from its
+// final attempt, so retries and backoff are left out.
+type ProviderLatency struct {
+	Provider string
+	Samples  int
+	P50      time.Duration
+	P95      time.Duration
+	// Slow is set when latency routing is on and P95 is over the budget,
+	// so interactive requests try the provider last.
+	Slow bool
+}
+
+type latencySample struct {
+	d  time.Duration
+	at time.Time
+}
+
+// latencyRing holds a provider's latest call durations, oldest overwritten
+// first.
+type latencyRing struct {
+	samples []latencySample
+	next    int
+}
+
+func (l *latencyRing) add(d time.Duration, at time.Time) {
+	sample := latencySample{d: d, at: at}
+	if len(l.samples) < latencyWindow {
+		l.samples = append(l.samples, sample)
+		return
+	}
+	l.samples[l.next] = sample
+	l.next = (l.next + 1) % latencyWindow
+}
+
+// fresh returns the durations recorded within latencyMaxAge of now.
+func (l *latencyRing) fresh(now time.Time) []time.Duration {
+	cutoff := now.Add(-latencyMaxAge)
+	out := make([]time.Duration, 0, len(l.samples))
+	for _, s := range l.samples {
+		if s.at.After(cutoff) {
+			out = append(out, s.d)
+		}
+	}
+	return out
+}
+
+// percentiles returns the nearest-rank p50 and p95 of the fresh samples and
+// how many there are.
+func (l *latencyRing) percentiles(now time.Time) (p50, p95 time.Duration, n int) {
+	sorted := l.fresh(now)
+	if len(sorted) == 0 {
+		return 0, 0, 0
+	}
+	slices.Sort(sorted)
+	rank := func(p float64) time.Duration {
+		i := int(math.Ceil(p*float64(len(sorted)))) - 1
+		return sorted[max(0, i)]
+	}
+	return rank(0.50), rank(0.95), len(sorted)
+}
+
+// SetLatencyBudget sets the p95 latency an interactive request will accept
+// from a provider. Teaching requests try providers over budget last, slowest
+// last of all; analysis and other background tasks keep their usual order.
+// Zero turns latency routing off; latency is tracked either way.
+func (r *Router) SetLatencyBudget(budget time.Duration) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.latencyBudget = max(budget, 0)
+}
+
+// ProviderLatency returns each registered provider's rolling latency in
+// fallback order. Providers with no recent successful calls report zero
+// samples.
+func (r *Router) ProviderLatency() []ProviderLatency {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	out := make([]ProviderLatency, 0, len(r.fallback))
+	for _, name := range r.fallback {
+		out = append(out, r.providerLatencyLocked(name))
+	}
+	return out
+}
+
+// LatencyStatsHandler serves ProviderLatency as JSON, in milliseconds.
+func (r *Router) LatencyStatsHandler() http.Handler {
+	return http.HandlerFunc(func(rw http.ResponseWriter, _ *http.Request) {
+		r.mu.RLock()
+		budget := r.latencyBudget
+		r.mu.RUnlock()
+		latencies := r.ProviderLatency()
+		providers := make([]map[string]any, 0, len(latencies))
+		for _, l := range latencies {
+			providers = append(providers, map[string]any{
+				"provider": l.Provider,
+				"samples":  l.Samples,
+				"p50_ms":   l.P50.Milliseconds(),
+				"p95_ms":   l.P95.Milliseconds(),
+				"slow":     l.Slow,
+			})
+		}
+		rw.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(rw).Encode(map[string]any{
+			"budget_ms": budget.Milliseconds(),
+			"providers": providers,
+		})
+	})
+}
+
+func (r *Router) providerLatencyLocked(name string) ProviderLatency {
+	out := ProviderLatency{Provider: name}
+	ring := r.latency[name]
+	if ring == nil {
+		return out
+	}
+	out.P50, out.P95, out.Samples = ring.percentiles(time.Now())
+	out.Slow = r.latencyBudget > 0 && out.Samples >= minLatencySamples && out.P95 > r.latencyBudget
+	return out
+}
+
+// recordLatency adds a successful call's duration to the provider's window.
+// Calls from a provider set since replaced are dropped, as for the breaker.
+func (r *Router) recordLatency(providerName string, gen uint64, d time.Duration) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if gen != r.gen {
+		return
+	}
+	if r.latency == nil {
+		r.latency = make(map[string]*latencyRing)
+	}
+	ring := r.latency[providerName]
+	if ring == nil {
+		ring = &latencyRing{}
+		r.latency[providerName] = ring
+	}
+	ring.add(d, time.Now())
+}
+
+// preferFast moves providers that are over the latency budget to the end of
+// an interactive task's plan, fastest first. The rest keep their configured
+// order, and slow providers stay in the plan as a last resort.
+func (r *Router) preferFast(task TaskType, steps []routeStep) []routeStep {
+	if task != TaskTeaching {
+		return steps
+	}
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	if r.latencyBudget <= 0 || len(r.latency) == 0 {
+		return steps
+	}
+	fast := make([]routeStep, 0, len(steps))
+	var slow []routeStep
+	p95 := make(map[string]time.Duration)
+	for _, step := range steps {
+		latency := r.providerLatencyLocked(step.provider)
+		if !latency.Slow {
+			fast = append(fast, step)
+			continue
+		}
+		p95[step.provider] = latency.P95
+		slow = append(slow, step)
+	}
+	if len(slow) == 0 {
+		return steps
+	}
+	sort.SliceStable(slow, func(i, j int) bool { return p95[slow[i].provider] < p95[slow[j].provider] })
+	return append(fast, slow...)
+}
@@ -0,0 +1,151 @@
+// Copyright 2026 the P&AI authors. All rights reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package ai
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func recordLatencies(r *Router, provider string, n int, d time.Duration) {
+	for range n {
+		r.recordLatency(provider, r.gen, d)
+	}
+}
+
+func TestLatencyRingPercentilesRollOver(t *testing.T) {
+	var ring latencyRing
+	now := time.Now()
+	for i := 1; i <= latencyWindow; i++ {
+		ring.add(time.Duration(i)*time.Millisecond, now)
+	}
+	if p50, p95, _ := ring.percentiles(now); p50 != 50*time.Millisecond || p95 != 95*time.Millisecond {
+		t.Fatalf("percentiles = %v, %v, want 50ms, 95ms", p50, p95)
+	}
+
+	for range latencyWindow {
+		ring.add(time.Second, now)
+	}
+	if len(ring.samples) != latencyWindow {
+		t.Fatalf("samples = %d, want the window of %d", len(ring.samples), latencyWindow)
+	}
+	if p50, _, _ := ring.percentiles(now); p50 != time.Second {
+		t.Fatalf("p50 = %v, want old samples rolled out", p50)
+	}
+}
+
+func TestSlowProviderAgesBackIntoThePlan(t *testing.T) {
+	r := NewRouter()
+	r.Register("slow", NewMockProvider("slow"))
+	r.Register("fast", NewMockProvider("fast"))
+	r.SetLatencyBudget(2 * time.Second)
+	recordLatencies(r, "slow", minLatencySamples, 5*time.Second)
+	if provider, _, _ := r.SelectModel(TaskTeaching, ""); provider != "fast" {
+		t.Fatalf("teaching provider = %q, want fast while slow is over budget", provider)
+	}
+
+	for i := range r.latency["slow"].samples {
+		r.latency["slow"].samples[i].at = time.Now().Add(-latencyMaxAge - time.Second)
+	}
+	if provider, _, _ := r.SelectModel(TaskTeaching, ""); provider != "slow" {
+		t.Fatalf("teaching provider = %q, want slow back in order once its samples age out", provider)
+	}
+	if latency := r.ProviderLatency(); latency[0].Samples != 0 {
+		t.Fatalf("latency = %+v, want stale samples ignored", latency)
+	}
+}
+
+func TestRouterTimesOnlyTheFinalAttempt(t *testing.T) {
+	r := NewRouterWithConfig(RouterConfig{RetryBackoff: []time.Duration{50 * time.Millisecond}})
+	r.Register("mock", NewMockProvider("ok").FailOn(1, errors.New("flaky")))
+	if _, err := r.Complete(context.Background(), CompletionRequest{Task: TaskTeaching, Messages: []Message{{Role: "user", Content: "hi"}}}); err != nil {
+		t.Fatalf("Complete() error = %v", err)
+	}
+	if latency := r.ProviderLatency(); latency[0].Samples != 1 || latency[0].P95 >= 50*time.Millisecond {
+		t.Fatalf("latency = %+v, want the retry backoff left out", latency)
+	}
+}
+
+func TestRouterPrefersFastProvidersForTeaching(t *testing.T) {
+	r := NewRouter()
+	r.Register("slow", NewMockProvider("slow"))
+	r.Register("fast", NewMockProvider("fast"))
+	r.SetLatencyBudget(2 * time.Second)
+	recordLatencies(r, "slow", minLatencySamples, 5*time.Second)
+	recordLatencies(r, "fast", minLatencySamples, 500*time.Millisecond)
+
+	if provider, _, _ := r.SelectModel(TaskTeaching, ""); provider != "fast" {
+		t.Fatalf("teaching provider = %q, want fast", provider)
+	}
+	if provider, _, _ := r.SelectModel(TaskAnalysis, ""); provider != "slow" {
+		t.Fatalf("analysis provider = %q, want the configured order", provider)
+	}
+
+	r.SetLatencyBudget(0)
+	if provider, _, _ := r.SelectModel(TaskTeaching, ""); provider != "slow" {
+		t.Fatalf("teaching provider with latency routing off = %q, want slow", provider)
+	}
+}
+
+func TestRouterNeedsEnoughSamplesToCallAProviderSlow(t *testing.T) {
+	r := NewRouter()
+	r.Register("primary", NewMockProvider("primary"))
+	r.Register("backup", NewMockProvider("backup"))
+	r.SetLatencyBudget(time.Second)
+	recordLatencies(r, "primary", minLatencySamples-1, 30*time.Second)
+
+	if provider, _, _ := r.SelectModel(TaskTeaching, ""); provider != "primary" {
+		t.Fatalf("teaching provider = %q, want primary until it has %d samples", provider, minLatencySamples)
+	}
+	latency := r.ProviderLatency()
+	if len(latency) != 2 || latency[0].Samples != minLatencySamples-1 || latency[0].Slow || latency[1].Samples != 0 {
+		t.Fatalf("latency = %+v", latency)
+	}
+}
+
+func TestRouterRecordsCompletionLatency(t *testing.T) {
+	r := NewRouter()
+	r.Register("mock", NewMockProvider("ok"))
+	if _, err := r.Complete(context.Background(), CompletionRequest{Task: TaskTeaching, Messages: []Message{{Role: "user", Content: "hi"}}}); err != nil {
+		t.Fatalf("Complete() error = %v", err)
+	}
+	if latency := r.ProviderLatency(); len(latency) != 1 || latency[0].Samples != 1 {
+		t.Fatalf("latency = %+v, want one sample", latency)
+	}
+
+	r.ReplaceProviders([]ProviderRegistration{{Name: "mock", Provider: NewMockProvider("ok")}})
+	if latency := r.ProviderLatency(); latency[0].Samples != 0 {
+		t.Fatalf("latency after provider swap = %+v, want reset", latency)
+	}
+}
+
+func TestLatencyStatsHandler(t *testing.T) {
+	r := NewRouter()
+	r.Register("openai", NewMockProvider("ok"))
+	r.SetLatencyBudget(8 * time.Second)
+	recordLatencies(r, "openai", minLatencySamples, 1200*time.Millisecond)
+
+	rec := httptest.NewRecorder()
+	r.LatencyStatsHandler().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/", nil))
+	var got struct {
+		BudgetMS  int64 `json:"budget_ms"`
+		Providers []struct {
+			Provider string `json:"provider"`
+			Samples  int    `json:"samples"`
+			P95MS    int64  `json:"p95_ms"`
+			Slow     bool   `json:"slow"`
+		} `json:"providers"`
+	}
+	if err := json.Unmarshal(rec.Body.Bytes(), &got); err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+	if got.BudgetMS != 8000 || len(got.Providers) != 1 || got.Providers[0].P95MS != 1200 || got.Providers[0].Slow {
+		t.Fatalf("stats = %+v", got)
+	}
+}
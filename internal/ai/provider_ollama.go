@@ -10,6 +10,10 @@ import (
 	"fmt"
 	"io"
 	"net/http"
+	"sync"
+	"time"
+
+	"github.com/p-n-ai/pai-bot/internal/apperr"
 )
 
 // OllamaProvider implements Provider for self-hosted Ollama.
@@ -17,9 +21,14 @@ import (
 type OllamaProvider struct {
 	baseURL string
 	client  *http.Client
-	models  []ModelInfo
+
+	modelsMu sync.Mutex
+	models   []ModelInfo // pulled models; see RefreshModels
+	modelsAt time.Time   // when /api/tags was last asked
 }
 
+var _ ModelRefresher = (*OllamaProvider)(nil)
+
 // OllamaOption configures an OllamaProvider.
 type OllamaOption func(*OllamaProvider)
 
@@ -34,7 +43,7 @@ func WithOllamaHTTPClient(client *http.Client) OllamaOption {
 func NewOllamaProvider(baseURL string, opts ...OllamaOption) *OllamaProvider {
 	p := &OllamaProvider{
 		baseURL: baseURL,
-		client:  http.DefaultClient,
+		client:  defaultHTTPClient,
 	}
 	for _, opt := range opts {
 		opt(p)
@@ -79,7 +88,7 @@ func (p *OllamaProvider) Complete(ctx context.Context, req CompletionRequest) (C
 	}
 
 	if resp.StatusCode != http.StatusOK {
-		return CompletionResponse{}, fmt.Errorf("ollama api error (status %d): %s", resp.StatusCode, string(respBody))
+		return CompletionResponse{}, apperr.HTTPStatus(resp.StatusCode, fmt.Errorf("ollama api error (status %d): %s", resp.StatusCode, string(respBody)))
 	}
 
 	var oaiResp openaiResponse
@@ -96,6 +105,7 @@ func (p *OllamaProvider) Complete(ctx context.Context, req CompletionRequest) (C
 		Model:        oaiResp.Model,
 		InputTokens:  oaiResp.Usage.PromptTokens,
 		OutputTokens: oaiResp.Usage.CompletionTokens,
+		Truncated:    oaiResp.Choices[0].FinishReason == "length",
 	}, nil
 }
 
@@ -111,15 +121,6 @@ func (p *OllamaProvider) StreamComplete(ctx context.Context, req CompletionReque
 	return ch, nil
 }
 
-func (p *OllamaProvider) Models() []ModelInfo {
-	if p.models != nil {
-		return p.models
-	}
-	return []ModelInfo{
-		{ID: "qwen3", Name: "Qwen3", MaxTokens: 40000, Description: "Latest default self-hosted model via Ollama"},
-	}
-}
-
 func (p *OllamaProvider) HealthCheck(ctx context.Context) error {
 	req, err := http.NewRequestWithContext(ctx, http.MethodGet, p.baseURL+"/api/tags", nil)
 	if err != nil {
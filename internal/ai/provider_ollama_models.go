@@ -0,0 +1,126 @@
+// Copyright 2026 the P&AI authors. All rights reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package ai
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/p-n-ai/pai-bot/internal/apperr"
+)
+
+const (
+	// ollamaModelsTTL is how long Models serves the last /api/tags listing
+	// before asking Ollama again.
+	ollamaModelsTTL = 30 * time.Second
+	// ollamaModelsTimeout bounds the /api/tags call Models makes itself.
+	ollamaModelsTimeout = 2 * time.Second
+	// ollamaTagsMaxBytes bounds the /api/tags response.
+	ollamaTagsMaxBytes = 4 << 20
+)
+
+var ollamaFallbackModels = []ModelInfo{{
+	ID:          "qwen3",
+	Name:        "Qwen3",
+	MaxTokens:   40000,
+	Description: "Latest default self-hosted model via Ollama",
+}}
+
+type ollamaTagsResponse struct {
+	Models []struct {
+		Name    string `json:"name"`
+		Details struct {
+			Family            string `json:"family"`
+			ParameterSize     string `json:"parameter_size"`
+			QuantizationLevel string `json:"quantization_level"`
+		} `json:"details"`
+	} `json:"models"`
+}
+
+// Models returns the models pulled on the Ollama host, listed by
+// /api/tags and cached for ollamaModelsTTL. Until a listing succeeds it
+// returns the default model.
+func (p *OllamaProvider) Models() []ModelInfo {
+	p.modelsMu.Lock()
+	stale := time.Since(p.modelsAt) >= ollamaModelsTTL
+	p.modelsMu.Unlock()
+	if stale {
+		ctx, cancel := context.WithTimeout(context.Background(), ollamaModelsTimeout)
+		if err := p.RefreshModels(ctx); err != nil {
+			slog.Debug("ollama model list unavailable", "base_url", p.baseURL, "error", err)
+		}
+		cancel()
+	}
+
+	p.modelsMu.Lock()
+	defer p.modelsMu.Unlock()
+	if len(p.models) == 0 {
+		return append([]ModelInfo(nil), ollamaFallbackModels...)
+	}
+	return append([]ModelInfo(nil), p.models...)
+}
+
+// RefreshModels lists the models pulled on the Ollama host and caches them
+// for Models. On error the cached list is kept until the next attempt,
+// ollamaModelsTTL later.
+func (p *OllamaProvider) RefreshModels(ctx context.Context) error {
+	p.modelsMu.Lock()
+	p.modelsAt = time.Now()
+	p.modelsMu.Unlock()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, strings.TrimRight(p.baseURL, "/")+"/api/tags", nil)
+	if err != nil {
+		return fmt.Errorf("create request: %w", err)
+	}
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("list ollama models: %w", err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+	if resp.StatusCode != http.StatusOK {
+		return apperr.HTTPStatus(resp.StatusCode, fmt.Errorf("ollama tags returned status %d", resp.StatusCode))
+	}
+	var body ollamaTagsResponse
+	if err := json.NewDecoder(io.LimitReader(resp.Body, ollamaTagsMaxBytes)).Decode(&body); err != nil {
+		return fmt.Errorf("decoding ollama tags: %w", err)
+	}
+
+	models := make([]ModelInfo, 0, len(body.Models))
+	for _, m := range body.Models {
+		if m.Name == "" {
+			continue
+		}
+		models = append(models, ModelInfo{ID: m.Name, Name: m.Name, Description: ollamaModelDescription(m.Details.Family, m.Details.ParameterSize, m.Details.QuantizationLevel)})
+	}
+	if len(models) == 0 {
+		return errors.New("no models pulled on the ollama host")
+	}
+
+	p.modelsMu.Lock()
+	p.models = models
+	p.modelsMu.Unlock()
+	return nil
+}
+
+// ollamaModelDescription joins a pulled model's details, e.g.
+// "llama 8.0B Q4_0, pulled locally".
+func ollamaModelDescription(details ...string) string {
+	var parts []string
+	for _, detail := range details {
+		if detail = strings.TrimSpace(detail); detail != "" {
+			parts = append(parts, detail)
+		}
+	}
+	if len(parts) == 0 {
+		return "Pulled locally"
+	}
+	return strings.Join(parts, " ") + ", pulled locally"
+}
@@ -0,0 +1,101 @@
+// Copyright 2026 the P&AI authors. All rights reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package ai
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// newOllamaTagsServer serves tags as the /api/tags body while up is true
+// and 503 otherwise, counting the requests it receives.
+func newOllamaTagsServer(t *testing.T, tags string, up *atomic.Bool, calls *atomic.Int32) *httptest.Server {
+	t.Helper()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/tags" {
+			t.Errorf("unexpected path: %s", r.URL.Path)
+		}
+		calls.Add(1)
+		if !up.Load() {
+			http.Error(w, "down", http.StatusServiceUnavailable)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(tags))
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+func TestOllamaProviderModelsListsPulledModelsWithCache(t *testing.T) {
+	var up atomic.Bool
+	var calls atomic.Int32
+	up.Store(true)
+	server := newOllamaTagsServer(t, `{"models":[
+		{"name":"llama3:8b","details":{"family":"llama","parameter_size":"8.0B","quantization_level":"Q4_0"}},
+		{"name":"qwen3:14b","details":{}}
+	]}`, &up, &calls)
+	provider := NewOllamaProvider(server.URL)
+
+	models := provider.Models()
+	if len(models) != 2 || models[0].ID != "llama3:8b" || models[1].ID != "qwen3:14b" {
+		t.Fatalf("Models() = %+v", models)
+	}
+	if models[0].Description != "llama 8.0B Q4_0, pulled locally" || models[1].Description != "Pulled locally" {
+		t.Fatalf("descriptions = %q, %q", models[0].Description, models[1].Description)
+	}
+	_ = provider.Models()
+	if calls.Load() != 1 {
+		t.Fatalf("/api/tags calls = %d, want the second Models() served from cache", calls.Load())
+	}
+
+	// Once the cache expires a failed listing keeps the pulled models.
+	up.Store(false)
+	provider.modelsAt = time.Now().Add(-ollamaModelsTTL)
+	if models := provider.Models(); calls.Load() != 2 || len(models) != 2 {
+		t.Fatalf("after a failed refresh: calls = %d, Models() = %+v", calls.Load(), models)
+	}
+	if err := provider.RefreshModels(context.Background()); err == nil {
+		t.Fatal("RefreshModels() error = nil for a 503")
+	}
+}
+
+func TestOllamaProviderModelsFallsBackWithoutPulledModels(t *testing.T) {
+	var up atomic.Bool
+	var calls atomic.Int32
+	up.Store(true)
+	server := newOllamaTagsServer(t, `{"models":[]}`, &up, &calls)
+	provider := NewOllamaProvider(server.URL)
+
+	if models := provider.Models(); len(models) != 1 || models[0].ID != "qwen3" {
+		t.Fatalf("Models() = %+v, want the default model", models)
+	}
+}
+
+func TestWithEndpointsMergesPulledModels(t *testing.T) {
+	var up atomic.Bool
+	var calls atomic.Int32
+	up.Store(true)
+	a := newOllamaTagsServer(t, `{"models":[{"name":"llama3:8b"},{"name":"qwen3"}]}`, &up, &calls)
+	b := newOllamaTagsServer(t, `{"models":[{"name":"qwen3"},{"name":"gemma3:4b"}]}`, &up, &calls)
+	provider := WithEndpoints("ollama", []Endpoint{
+		{URL: a.URL, Provider: NewOllamaProvider(a.URL)},
+		{URL: b.URL, Provider: NewOllamaProvider(b.URL)},
+	})
+
+	if err := provider.(ModelRefresher).RefreshModels(context.Background()); err != nil {
+		t.Fatalf("RefreshModels() error = %v", err)
+	}
+	var ids []string
+	for _, info := range provider.Models() {
+		ids = append(ids, info.ID)
+	}
+	if len(ids) != 3 || ids[0] != "llama3:8b" || ids[1] != "qwen3" || ids[2] != "gemma3:4b" {
+		t.Fatalf("Models() = %v, want each pulled model once", ids)
+	}
+}
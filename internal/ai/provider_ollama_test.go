@@ -27,11 +27,14 @@ func TestOllamaProvider_Complete(t *testing.T) {
 		_ = json.NewEncoder(w).Encode(openaiResponse{
 			Choices: []struct {
 				Message struct {
-					Content string `json:"content"`
+					Content   string           `json:"content"`
+					ToolCalls []openaiToolCall `json:"tool_calls"`
 				} `json:"message"`
+				FinishReason string `json:"finish_reason"`
 			}{
 				{Message: struct {
-					Content string `json:"content"`
+					Content   string           `json:"content"`
+					ToolCalls []openaiToolCall `json:"tool_calls"`
 				}{Content: "Ollama response"}},
 			},
 			Model: "qwen3",
@@ -60,6 +63,42 @@ func TestOllamaProvider_Complete(t *testing.T) {
 	}
 }
 
+func TestOllamaProvider_Complete_SendsImagesAsContentParts(t *testing.T) {
+	var received struct {
+		Messages []struct {
+			Role    string          `json:"role"`
+			Content json.RawMessage `json:"content"`
+		} `json:"messages"`
+	}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_ = json.NewDecoder(r.Body).Decode(&received)
+		writeOpenAITextResponse(t, w, "ok", "qwen3", 0, 0)
+	}))
+	defer server.Close()
+
+	provider := NewOllamaProvider(server.URL)
+	_, err := provider.Complete(context.Background(), CompletionRequest{
+		Messages: []Message{
+			{Role: "system", Content: "Be a tutor."},
+			{Role: "user", Content: "Check my working", ImageURLs: []string{"data:image/png;base64,AAEC"}},
+		},
+	})
+	if err != nil {
+		t.Fatalf("Complete() error = %v", err)
+	}
+
+	if len(received.Messages) != 2 || string(received.Messages[0].Content) != `"Be a tutor."` {
+		t.Fatalf("messages = %s, want a plain system message first", received.Messages)
+	}
+	var parts []openaiContentPart
+	if err := json.Unmarshal(received.Messages[1].Content, &parts); err != nil {
+		t.Fatalf("user content = %s, want content parts: %v", received.Messages[1].Content, err)
+	}
+	if len(parts) != 2 || parts[0].Text != "Check my working" || parts[1].Type != "image_url" || parts[1].ImageURL.URL != "data:image/png;base64,AAEC" {
+		t.Fatalf("parts = %+v, want text then image_url", parts)
+	}
+}
+
 func TestOllamaProvider_Complete_APIError(t *testing.T) {
 	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		w.WriteHeader(http.StatusInternalServerError)
@@ -109,7 +148,10 @@ func TestOllamaProvider_HealthCheck(t *testing.T) {
 }
 
 func TestOllamaProvider_Models(t *testing.T) {
-	provider := NewOllamaProvider("http://localhost:11434")
+	server := httptest.NewServer(http.NotFoundHandler())
+	defer server.Close()
+
+	provider := NewOllamaProvider(server.URL)
 	models := provider.Models()
 
 	if len(models) == 0 {
@@ -13,12 +13,14 @@ import (
 	"strings"
 	"time"
 
+	"github.com/p-n-ai/pai-bot/internal/apperr"
 	"github.com/p-n-ai/pai-bot/internal/llm"
 )
 
 const (
 	defaultOpenAIBaseURL   = "https://api.openai.com/v1"
 	defaultDeepSeekBaseURL = "https://api.deepseek.com"
+	defaultGroqBaseURL     = "https://api.groq.com/openai/v1"
 )
 
 // OpenAIProvider implements Provider for OpenAI and OpenAI-compatible APIs
@@ -29,6 +31,8 @@ type OpenAIProvider struct {
 	client  *http.Client
 	name    string
 	models  []ModelInfo
+	// defaultModel answers requests that name no model.
+	defaultModel string
 }
 
 type directOpenAIProvider struct {
@@ -80,10 +84,11 @@ func NewOpenAIProvider(apiKey string, opts ...OpenAIOption) Provider {
 
 func newOpenAIProvider(apiKey string, opts ...OpenAIOption) *OpenAIProvider {
 	p := &OpenAIProvider{
-		apiKey:  apiKey,
-		baseURL: defaultOpenAIBaseURL,
-		client:  http.DefaultClient,
-		name:    "openai",
+		apiKey:       apiKey,
+		baseURL:      defaultOpenAIBaseURL,
+		client:       defaultHTTPClient,
+		name:         "openai",
+		defaultModel: "gpt-5.4-mini", // current low-latency default
 	}
 	for _, opt := range opts {
 		opt(p)
@@ -100,6 +105,25 @@ func NewDeepSeekProvider(apiKey string, opts ...OpenAIOption) *OpenAIProvider {
 	return newOpenAIProvider(apiKey, opts...)
 }
 
+// groqModels are the Llama models Groq serves at low latency.
+var groqModels = []ModelInfo{
+	{ID: "llama-3.1-8b-instant", Name: "Llama 3.1 8B Instant", MaxTokens: 131072, Description: "Fastest Groq model for nudges and quick hints"},
+	{ID: "llama-3.3-70b-versatile", Name: "Llama 3.3 70B Versatile", MaxTokens: 131072, Description: "Stronger Groq model, still low latency"},
+}
+
+// NewGroqProvider creates a provider for the Groq API (OpenAI-compatible),
+// defaulting to its fastest Llama model.
+func NewGroqProvider(apiKey string, opts ...OpenAIOption) *OpenAIProvider {
+	opts = append([]OpenAIOption{
+		WithBaseURL(defaultGroqBaseURL),
+		WithProviderName("groq"),
+		WithModels(groqModels),
+	}, opts...)
+	p := newOpenAIProvider(apiKey, opts...)
+	p.defaultModel = groqModels[0].ID
+	return p
+}
+
 // openaiRequest is the request body for the OpenAI chat completions API.
 type openaiRequest struct {
 	Model               string                `json:"model"`
@@ -109,6 +133,7 @@ type openaiRequest struct {
 	MaxTokens           int                   `json:"max_tokens,omitempty"`
 	MaxCompletionTokens int                   `json:"max_completion_tokens,omitempty"`
 	Temperature         *float64              `json:"temperature,omitempty"`
+	User                string                `json:"user,omitempty"`
 }
 
 type openaiMessage struct {
@@ -163,8 +188,10 @@ type openaiImagePart struct {
 type openaiResponse struct {
 	Choices []struct {
 		Message struct {
-			Content string `json:"content"`
+			Content   string           `json:"content"`
+			ToolCalls []openaiToolCall `json:"tool_calls"`
 		} `json:"message"`
+		FinishReason string `json:"finish_reason"`
 	} `json:"choices"`
 	Model string `json:"model"`
 	Usage struct {
@@ -195,12 +222,14 @@ type openaiNativeResponse struct {
 func (p *OpenAIProvider) Complete(ctx context.Context, req CompletionRequest) (CompletionResponse, error) {
 	model := req.Model
 	if model == "" {
-		model = "gpt-5.4-mini" // current low-latency default
+		model = p.defaultModel
 	}
 
 	oaiReq := openaiRequest{
 		Model:    model,
 		Messages: buildOpenAIMessages(req.Messages),
+		Tools:    buildOpenAITools(req.Tools),
+		User:     req.UserHash,
 	}
 	if req.MaxTokens > 0 {
 		// Newer OpenAI models (o1+, gpt-5+) reject max_tokens and require
@@ -243,7 +272,7 @@ func (p *OpenAIProvider) Complete(ctx context.Context, req CompletionRequest) (C
 	}
 
 	if resp.StatusCode != http.StatusOK {
-		return CompletionResponse{}, fmt.Errorf("openai api error (status %d): %s", resp.StatusCode, string(respBody))
+		return CompletionResponse{}, apperr.HTTPStatus(resp.StatusCode, fmt.Errorf("openai api error (status %d): %s", resp.StatusCode, string(respBody)))
 	}
 
 	var oaiResp openaiResponse
@@ -254,18 +283,24 @@ func (p *OpenAIProvider) Complete(ctx context.Context, req CompletionRequest) (C
 	if len(oaiResp.Choices) == 0 {
 		return CompletionResponse{}, fmt.Errorf("no choices in response")
 	}
+	toolCalls, err := decodeOpenAIToolCalls(oaiResp.Choices[0].Message.ToolCalls)
+	if err != nil {
+		return CompletionResponse{}, err
+	}
 
 	return CompletionResponse{
 		Content:      oaiResp.Choices[0].Message.Content,
 		Model:        oaiResp.Model,
 		InputTokens:  oaiResp.Usage.PromptTokens,
 		OutputTokens: oaiResp.Usage.CompletionTokens,
+		Truncated:    oaiResp.Choices[0].FinishReason == "length",
+		ToolCalls:    toolCalls,
 	}, nil
 }
 
 func (p *directOpenAIProvider) CompleteNative(ctx context.Context, model string, c llm.Context, opts *llm.StreamOptions) (llm.AssistantMessage, error) {
 	if model == "" {
-		model = "gpt-5.4-mini"
+		model = p.defaultModel
 	}
 	messages, err := buildNativeOpenAIMessages(c)
 	if err != nil {
@@ -289,6 +324,7 @@ func (p *directOpenAIProvider) CompleteNative(ctx context.Context, model string,
 			}
 		}
 		request.Temperature = opts.Temperature
+		request.User = opts.User
 		if opts.StructuredOutput != nil {
 			spec := &StructuredOutputSpec{
 				Name:       opts.StructuredOutput.Name,
@@ -331,7 +367,7 @@ func (p *directOpenAIProvider) CompleteNative(ctx context.Context, model string,
 		return llm.AssistantMessage{}, fmt.Errorf("read native OpenAI response: %w", err)
 	}
 	if response.StatusCode != http.StatusOK {
-		return llm.AssistantMessage{}, fmt.Errorf("native OpenAI API returned status %d", response.StatusCode)
+		return llm.AssistantMessage{}, apperr.HTTPStatus(response.StatusCode, fmt.Errorf("native OpenAI API returned status %d", response.StatusCode))
 	}
 
 	var decoded openaiNativeResponse
@@ -528,7 +564,8 @@ func applyOpenAIStructuredOutput(providerName string, oaiReq *openaiRequest, spe
 		return fmt.Errorf("structured output JSON schema is required")
 	}
 
-	if providerName == "deepseek" {
+	// DeepSeek and Groq's Llama models take JSON mode but not json_schema.
+	if providerName == "deepseek" || providerName == "groq" {
 		oaiReq.ResponseFormat = &openaiResponseFormat{Type: "json_object"}
 		oaiReq.Messages = append([]openaiMessage{{
 			Role:    "system",
@@ -563,28 +600,33 @@ func needsMaxCompletionTokens(model string) bool {
 	}
 }
 
+// buildOpenAIMessages encodes messages for OpenAI-compatible chat APIs.
+// A user message with images becomes a content-part array of its text and
+// image_url parts; everything else keeps plain string content, which every
+// compatible server accepts. Blank image entries are dropped. Assistant tool
+// calls and "tool" results keep their call IDs.
 func buildOpenAIMessages(messages []Message) []openaiMessage {
 	out := make([]openaiMessage, 0, len(messages))
 	for _, m := range messages {
-		if len(m.ImageURLs) == 0 {
+		imageURLs := nonEmptyImageURLs(m.ImageURLs)
+		if len(imageURLs) == 0 || m.Role != "user" {
 			out = append(out, openaiMessage{
-				Role:    m.Role,
-				Content: m.Content,
+				Role:       m.Role,
+				Content:    m.Content,
+				ToolCalls:  encodeOpenAIToolCalls(m.ToolCalls),
+				ToolCallID: m.ToolCallID,
 			})
 			continue
 		}
 
-		parts := make([]openaiContentPart, 0, 1+len(m.ImageURLs))
+		parts := make([]openaiContentPart, 0, 1+len(imageURLs))
 		if m.Content != "" {
 			parts = append(parts, openaiContentPart{
 				Type: "text",
 				Text: m.Content,
 			})
 		}
-		for _, imageURL := range m.ImageURLs {
-			if imageURL == "" {
-				continue
-			}
+		for _, imageURL := range imageURLs {
 			parts = append(parts, openaiContentPart{
 				Type: "image_url",
 				ImageURL: &openaiImagePart{
@@ -601,6 +643,83 @@ func buildOpenAIMessages(messages []Message) []openaiMessage {
 	return out
 }
 
+func buildOpenAITools(tools []ToolDefinition) []openaiTool {
+	if len(tools) == 0 {
+		return nil
+	}
+	out := make([]openaiTool, len(tools))
+	for i, tool := range tools {
+		out[i].Type = "function"
+		out[i].Function.Name = tool.Name
+		out[i].Function.Description = tool.Description
+		out[i].Function.Parameters = tool.Parameters
+	}
+	return out
+}
+
+func encodeOpenAIToolCalls(calls []ToolCall) []openaiToolCall {
+	if len(calls) == 0 {
+		return nil
+	}
+	out := make([]openaiToolCall, len(calls))
+	for i, call := range calls {
+		arguments := string(call.Arguments)
+		if arguments == "" {
+			arguments = "{}"
+		}
+		out[i] = openaiToolCall{
+			ID:       call.ID,
+			Type:     "function",
+			Function: openaiToolFunction{Name: call.Name, Arguments: arguments},
+		}
+	}
+	return out
+}
+
+// decodeOpenAIToolCalls checks each call's arguments string is a JSON
+// object; models occasionally emit truncated or non-object arguments.
+func decodeOpenAIToolCalls(calls []openaiToolCall) ([]ToolCall, error) {
+	if len(calls) == 0 {
+		return nil, nil
+	}
+	out := make([]ToolCall, len(calls))
+	for i, call := range calls {
+		arguments, err := toolArgumentsObject(call.Function.Arguments)
+		if err != nil {
+			return nil, fmt.Errorf("tool call %q arguments: %w", call.Function.Name, err)
+		}
+		out[i] = ToolCall{ID: call.ID, Name: call.Function.Name, Arguments: arguments}
+	}
+	return out, nil
+}
+
+// toolArgumentsObject returns encoded as JSON object arguments, treating
+// blank arguments as {}.
+func toolArgumentsObject(encoded string) (json.RawMessage, error) {
+	encoded = strings.TrimSpace(encoded)
+	if encoded == "" {
+		return json.RawMessage("{}"), nil
+	}
+	var object map[string]any
+	if err := json.Unmarshal([]byte(encoded), &object); err != nil {
+		return nil, err
+	}
+	if object == nil {
+		return nil, fmt.Errorf("must be a JSON object")
+	}
+	return json.RawMessage(encoded), nil
+}
+
+func nonEmptyImageURLs(imageURLs []string) []string {
+	var out []string
+	for _, imageURL := range imageURLs {
+		if imageURL != "" {
+			out = append(out, imageURL)
+		}
+	}
+	return out
+}
+
 func (p *OpenAIProvider) StreamComplete(ctx context.Context, req CompletionRequest) (<-chan StreamChunk, error) {
 	// TODO: implement SSE streaming
 	ch := make(chan StreamChunk, 1)
@@ -89,6 +89,30 @@ func TestOpenAIProvider_Complete_StructuredOutput_AddsResponseFormat(t *testing.
 	}
 }
 
+func TestOpenAIProvider_Complete_SendsUserHash(t *testing.T) {
+	var captured map[string]any
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_ = json.NewDecoder(r.Body).Decode(&captured)
+		writeOpenAITextResponse(t, w, "ok", "gpt-4o", 1, 1)
+	}))
+	defer server.Close()
+
+	provider := NewOpenAIProvider("test-key", WithBaseURL(server.URL))
+	_, err := provider.Complete(context.Background(), CompletionRequest{
+		RequestMetadata: RequestMetadata{Tenant: "tenant-1", UserHash: HashUser("tenant-1", "user-1"), TurnID: "turn-1"},
+		Messages:        []Message{{Role: "user", Content: "hello"}},
+	})
+	if err != nil {
+		t.Fatalf("Complete() error = %v", err)
+	}
+	if captured["user"] != HashUser("tenant-1", "user-1") {
+		t.Fatalf("user = %v, want hashed user ID", captured["user"])
+	}
+	if captured["user"] == "user-1" {
+		t.Fatal("raw user ID should not be sent to the provider")
+	}
+}
+
 func TestOpenAIProvider_Complete_APIError(t *testing.T) {
 	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		w.WriteHeader(http.StatusTooManyRequests)
@@ -124,6 +148,25 @@ func TestOpenAIProvider_Complete_EmptyChoices(t *testing.T) {
 	}
 }
 
+func TestOpenAIProvider_Complete_FlagsLengthFinishAsTruncated(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`{"choices":[{"message":{"content":"2x + 3 = 7, so 2x ="},"finish_reason":"length"}],"model":"gpt-4o"}`))
+	}))
+	defer server.Close()
+
+	provider := NewOpenAIProvider("test-key", WithBaseURL(server.URL))
+
+	resp, err := provider.Complete(context.Background(), CompletionRequest{
+		Messages: []Message{{Role: "user", Content: "hello"}},
+	})
+	if err != nil {
+		t.Fatalf("Complete() error = %v", err)
+	}
+	if !resp.Truncated {
+		t.Error("Truncated = false, want true for finish_reason length")
+	}
+}
+
 func TestDeepSeekProvider_UsesCorrectBaseURL(t *testing.T) {
 	var receivedPath string
 	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
@@ -181,6 +224,38 @@ func TestDeepSeekProvider_Complete_StructuredOutput_UsesJSONObjectMode(t *testin
 	}
 }
 
+func TestGroqProvider_DefaultsToFastLlamaAndJSONObjectMode(t *testing.T) {
+	var captured map[string]any
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/chat/completions" || r.Header.Get("Authorization") != "Bearer gsk-test" {
+			t.Errorf("request = %s with auth %q", r.URL.Path, r.Header.Get("Authorization"))
+		}
+		_ = json.NewDecoder(r.Body).Decode(&captured)
+		writeOpenAITextResponse(t, w, `{"final_answer":"ok"}`, "llama-3.1-8b-instant", 10, 5)
+	}))
+	defer server.Close()
+
+	provider := NewGroqProvider("gsk-test", WithBaseURL(server.URL))
+	if _, err := provider.Complete(context.Background(), CompletionRequest{
+		Messages: []Message{{Role: "user", Content: "hello"}},
+		StructuredOutput: &StructuredOutputSpec{
+			Name:       "tutor_response",
+			JSONSchema: testStructuredSchema,
+		},
+	}); err != nil {
+		t.Fatalf("Complete() error = %v", err)
+	}
+	if captured["model"] != "llama-3.1-8b-instant" {
+		t.Fatalf("model = %#v, want the Groq default", captured["model"])
+	}
+	if rf, _ := captured["response_format"].(map[string]any); rf["type"] != "json_object" {
+		t.Fatalf("response_format = %#v, want json_object", captured["response_format"])
+	}
+	if models := provider.Models(); len(models) == 0 || models[0].ID != "llama-3.1-8b-instant" {
+		t.Fatalf("Models() = %+v, want the Groq catalog", models)
+	}
+}
+
 func TestOpenAIProvider_HealthCheck(t *testing.T) {
 	tests := []struct {
 		name       string
@@ -296,3 +371,80 @@ func TestOpenAIProvider_Complete_WithImageURL(t *testing.T) {
 		t.Fatalf("content parts len = %d, want >= 2", len(parts))
 	}
 }
+
+func TestBuildOpenAIMessages_UsesContentPartsOnlyForUserImages(t *testing.T) {
+	got := buildOpenAIMessages([]Message{
+		{Role: "system", Content: "Be a tutor."},
+		{Role: "user", Content: "Solve 2x = 10", ImageURLs: []string{""}},
+		{Role: "assistant", Content: "x = 5", ImageURLs: []string{"https://example.com/a.png"}},
+		{Role: "user", ImageURLs: []string{"", "https://example.com/b.png"}},
+	})
+
+	for i, want := range []string{"Be a tutor.", "Solve 2x = 10", "x = 5"} {
+		if got[i].Content != want {
+			t.Errorf("messages[%d].Content = %#v, want plain %q", i, got[i].Content, want)
+		}
+	}
+	parts, ok := got[3].Content.([]openaiContentPart)
+	if !ok || len(parts) != 1 || parts[0].Type != "image_url" || parts[0].ImageURL.URL != "https://example.com/b.png" {
+		t.Fatalf("messages[3].Content = %#v, want a single image_url part", got[3].Content)
+	}
+}
+
+// calculatorToolRequest is a finished tool round trip: the model called the
+// calculator, the result came back, and the tool is still on offer.
+func calculatorToolRequest() CompletionRequest {
+	return CompletionRequest{
+		Messages: []Message{
+			{Role: "user", Content: "What is 12 x 7?"},
+			{Role: "assistant", ToolCalls: []ToolCall{{
+				ID:        "call_1",
+				Name:      "calculator",
+				Arguments: json.RawMessage(`{"expression":"12*7"}`),
+				Signature: "sig-1",
+			}}},
+			{Role: "tool", ToolCallID: "call_1", ToolName: "calculator", Content: "84"},
+		},
+		Tools: []ToolDefinition{{
+			Name:        "calculator",
+			Description: "Evaluate an arithmetic expression.",
+			Parameters:  json.RawMessage(`{"type":"object","properties":{"expression":{"type":"string"}}}`),
+		}},
+	}
+}
+
+func TestOpenAIProvider_Complete_EncodesToolsAndDecodesToolCalls(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req openaiRequest
+		_ = json.NewDecoder(r.Body).Decode(&req)
+
+		if len(req.Tools) != 1 || req.Tools[0].Type != "function" || req.Tools[0].Function.Name != "calculator" {
+			t.Errorf("tools = %+v, want the calculator function", req.Tools)
+		}
+		if len(req.Messages) != 3 {
+			t.Fatalf("messages = %+v, want 3", req.Messages)
+		}
+		call := req.Messages[1].ToolCalls
+		if len(call) != 1 || call[0].ID != "call_1" || call[0].Function.Arguments != `{"expression":"12*7"}` {
+			t.Errorf("assistant tool_calls = %+v", call)
+		}
+		if req.Messages[2].Role != "tool" || req.Messages[2].ToolCallID != "call_1" || req.Messages[2].Content != "84" {
+			t.Errorf("tool message = %+v", req.Messages[2])
+		}
+
+		_, _ = w.Write([]byte(`{"choices":[{"message":{"content":"","tool_calls":[
+			{"id":"call_2","type":"function","function":{"name":"curriculum_lookup","arguments":"{\"topic\":\"F1-02\"}"}}
+		]}}],"model":"gpt-4o"}`))
+	}))
+	defer server.Close()
+
+	provider := NewOpenAIProvider("test-key", WithBaseURL(server.URL))
+	resp, err := provider.Complete(context.Background(), calculatorToolRequest())
+	if err != nil {
+		t.Fatalf("Complete() error = %v", err)
+	}
+	if len(resp.ToolCalls) != 1 || resp.ToolCalls[0].ID != "call_2" || resp.ToolCalls[0].Name != "curriculum_lookup" ||
+		string(resp.ToolCalls[0].Arguments) != `{"topic":"F1-02"}` {
+		t.Fatalf("tool calls = %+v", resp.ToolCalls)
+	}
+}
@@ -10,7 +10,11 @@ import (
 	"fmt"
 	"net/http"
 	"strings"
+	"sync"
 
+	"github.com/OpenRouterTeam/go-sdk/models/sdkerrors"
+
+	"github.com/p-n-ai/pai-bot/internal/apperr"
 	"github.com/p-n-ai/pai-bot/internal/llm"
 )
 
@@ -22,21 +26,54 @@ const (
 
 var errOpenRouterLLMCompletion = errors.New("openrouter completion failed")
 
+// openRouterCompletionError replaces a failed completion's error, whose text
+// can echo the request, with errOpenRouterLLMCompletion classified by the
+// status OpenRouter returned.
+func openRouterCompletionError(err error) error {
+	var apiErr *sdkerrors.APIError
+	if errors.As(err, &apiErr) {
+		return apperr.HTTPStatus(apiErr.StatusCode, errOpenRouterLLMCompletion)
+	}
+	if class := apperr.ClassOf(err); class != apperr.ClassUnknown {
+		return apperr.Wrap(class, errOpenRouterLLMCompletion)
+	}
+	return apperr.Dependency(errOpenRouterLLMCompletion)
+}
+
 type openRouterLLMAdapter struct {
 	apiKey  string
 	baseURL string
+	client  *http.Client
+
+	modelsMu sync.RWMutex
+	models   []ModelInfo // live catalog; see RefreshModels
 }
 
 var _ Provider = (*openRouterLLMAdapter)(nil)
 var _ NativeProvider = (*openRouterLLMAdapter)(nil)
+var _ ModelRefresher = (*openRouterLLMAdapter)(nil)
+
+// OpenRouterOption configures the OpenRouter adapter.
+type OpenRouterOption func(*openRouterLLMAdapter)
+
+// WithOpenRouterHTTPClient sets a custom HTTP client.
+func WithOpenRouterHTTPClient(client *http.Client) OpenRouterOption {
+	return func(p *openRouterLLMAdapter) {
+		p.client = client
+	}
+}
 
 // NewOpenRouterLLMAdapter adapts the native llm OpenRouter path to Provider.
-func NewOpenRouterLLMAdapter(apiKey string) Provider {
-	return newOpenRouterLLMAdapter(apiKey, openRouterLLMDefaultBaseURL)
+func NewOpenRouterLLMAdapter(apiKey string, opts ...OpenRouterOption) Provider {
+	p := newOpenRouterLLMAdapter(apiKey, openRouterLLMDefaultBaseURL)
+	for _, opt := range opts {
+		opt(p)
+	}
+	return p
 }
 
 func newOpenRouterLLMAdapter(apiKey, baseURL string) *openRouterLLMAdapter {
-	return &openRouterLLMAdapter{apiKey: apiKey, baseURL: baseURL}
+	return &openRouterLLMAdapter{apiKey: apiKey, baseURL: baseURL, client: defaultHTTPClient}
 }
 
 func (p *openRouterLLMAdapter) Complete(ctx context.Context, req CompletionRequest) (CompletionResponse, error) {
@@ -50,6 +87,7 @@ func (p *openRouterLLMAdapter) Complete(ctx context.Context, req CompletionReque
 		return CompletionResponse{}, err
 	}
 	options := projectOpenRouterLLMOptions(p.apiKey, modelID, req)
+	options.HTTPClient = p.client
 	message, err := llm.StreamOpenRouterChat(ctx, llm.Model{
 		ID:       modelID,
 		API:      llm.APIOpenRouterChat,
@@ -60,7 +98,7 @@ func (p *openRouterLLMAdapter) Complete(ctx context.Context, req CompletionReque
 		if ctxErr := ctx.Err(); ctxErr != nil {
 			return CompletionResponse{}, ctxErr
 		}
-		return CompletionResponse{}, errOpenRouterLLMCompletion
+		return CompletionResponse{}, openRouterCompletionError(err)
 	}
 
 	responseModel := message.ResponseModel
@@ -72,6 +110,8 @@ func (p *openRouterLLMAdapter) Complete(ctx context.Context, req CompletionReque
 		Model:        responseModel,
 		InputTokens:  message.Usage.Input + message.Usage.CacheRead + message.Usage.CacheWrite,
 		OutputTokens: message.Usage.Output,
+		CostUSD:      message.Usage.Cost.Total,
+		Truncated:    message.StopReason == llm.StopReasonLength,
 	}, nil
 }
 
@@ -85,6 +125,7 @@ func (p *openRouterLLMAdapter) CompleteNative(ctx context.Context, modelID strin
 		options.Headers = cloneStringMap(opts.Headers)
 	}
 	options.APIKey = p.apiKey
+	options.HTTPClient = p.client
 	if modelID == openRouterLLMMinimalReasoningModel {
 		options.ReasoningEffort = llm.ReasoningEffortMinimal
 	}
@@ -98,7 +139,7 @@ func (p *openRouterLLMAdapter) CompleteNative(ctx context.Context, modelID strin
 		if ctxErr := ctx.Err(); ctxErr != nil {
 			return llm.AssistantMessage{}, ctxErr
 		}
-		return llm.AssistantMessage{}, errOpenRouterLLMCompletion
+		return llm.AssistantMessage{}, openRouterCompletionError(err)
 	}
 	return message, nil
 }
@@ -182,6 +223,8 @@ func projectOpenRouterLLMOptions(apiKey, modelID string, req CompletionRequest)
 	options := llm.StreamOptions{
 		APIKey:    apiKey,
 		MaxTokens: req.MaxTokens,
+		User:      req.UserHash,
+		Metadata:  req.RequestMetadata.Map(),
 	}
 	if modelID == openRouterLLMMinimalReasoningModel {
 		options.ReasoningEffort = llm.ReasoningEffortMinimal
@@ -221,15 +264,6 @@ func (p *openRouterLLMAdapter) StreamComplete(ctx context.Context, req Completio
 	return chunks, nil
 }
 
-func (p *openRouterLLMAdapter) Models() []ModelInfo {
-	return []ModelInfo{{
-		ID:          openRouterLLMDefaultModel,
-		Name:        "Qwen3 Max",
-		MaxTokens:   262144,
-		Description: "Current general-purpose OpenRouter default",
-	}}
-}
-
 func (p *openRouterLLMAdapter) HealthCheck(ctx context.Context) error {
 	req, err := http.NewRequestWithContext(ctx, http.MethodGet, strings.TrimRight(p.baseURL, "/")+"/models", nil)
 	if err != nil {
@@ -237,7 +271,7 @@ func (p *openRouterLLMAdapter) HealthCheck(ctx context.Context) error {
 	}
 	req.Header.Set("Authorization", "Bearer "+p.apiKey)
 
-	resp, err := http.DefaultClient.Do(req)
+	resp, err := p.client.Do(req)
 	if err != nil {
 		if ctxErr := ctx.Err(); ctxErr != nil {
 			return ctxErr
@@ -0,0 +1,111 @@
+// Copyright 2026 the P&AI authors. All rights reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package ai
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/p-n-ai/pai-bot/internal/apperr"
+)
+
+// openRouterModelsMaxBytes bounds the /models response; the full catalog
+// is a few hundred kilobytes.
+const openRouterModelsMaxBytes = 16 << 20
+
+var openRouterFallbackModels = []ModelInfo{{
+	ID:          openRouterLLMDefaultModel,
+	Name:        "Qwen3 Max",
+	MaxTokens:   262144,
+	Description: "Current general-purpose OpenRouter default",
+}}
+
+type openRouterModelsResponse struct {
+	Data []struct {
+		ID            string `json:"id"`
+		Name          string `json:"name"`
+		Description   string `json:"description"`
+		ContextLength int    `json:"context_length"`
+		Pricing       struct {
+			Prompt     string `json:"prompt"`
+			Completion string `json:"completion"`
+		} `json:"pricing"`
+	} `json:"data"`
+}
+
+// Models returns the catalog from the last RefreshModels, or the default
+// model until one succeeds.
+func (p *openRouterLLMAdapter) Models() []ModelInfo {
+	p.modelsMu.RLock()
+	defer p.modelsMu.RUnlock()
+	if len(p.models) == 0 {
+		return append([]ModelInfo(nil), openRouterFallbackModels...)
+	}
+	return append([]ModelInfo(nil), p.models...)
+}
+
+// RefreshModels fetches OpenRouter's live catalog, with each model's
+// context length and per-token prices, and caches it for Models. On error
+// the cached catalog is kept.
+func (p *openRouterLLMAdapter) RefreshModels(ctx context.Context) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, strings.TrimRight(p.baseURL, "/")+"/models", nil)
+	if err != nil {
+		return errors.New("openrouter models request is invalid")
+	}
+	req.Header.Set("Authorization", "Bearer "+p.apiKey)
+
+	resp, err := p.client.Do(req)
+	if err != nil {
+		if ctxErr := ctx.Err(); ctxErr != nil {
+			return ctxErr
+		}
+		return errors.New("openrouter models request failed")
+	}
+	defer func() { _ = resp.Body.Close() }()
+	if resp.StatusCode != http.StatusOK {
+		return apperr.HTTPStatus(resp.StatusCode, fmt.Errorf("openrouter models returned status %d", resp.StatusCode))
+	}
+	var body openRouterModelsResponse
+	if err := json.NewDecoder(io.LimitReader(resp.Body, openRouterModelsMaxBytes)).Decode(&body); err != nil {
+		return fmt.Errorf("decoding openrouter models: %w", err)
+	}
+
+	models := make([]ModelInfo, 0, len(body.Data))
+	for _, m := range body.Data {
+		if m.ID == "" {
+			continue
+		}
+		info := ModelInfo{ID: m.ID, Name: m.Name, MaxTokens: m.ContextLength, Description: m.Description}
+		if price, ok := parseOpenRouterPrice(m.Pricing.Prompt, m.Pricing.Completion); ok {
+			info.Price = &price
+		}
+		models = append(models, info)
+	}
+	if len(models) == 0 {
+		return errors.New("openrouter models returned an empty catalog")
+	}
+
+	p.modelsMu.Lock()
+	p.models = models
+	p.modelsMu.Unlock()
+	return nil
+}
+
+// parseOpenRouterPrice converts OpenRouter's USD-per-token strings to a
+// ModelPrice. Meta-models such as "openrouter/auto" price as "-1" because
+// the cost depends on the model picked; they have no price.
+func parseOpenRouterPrice(prompt, completion string) (ModelPrice, bool) {
+	in, errIn := strconv.ParseFloat(prompt, 64)
+	out, errOut := strconv.ParseFloat(completion, 64)
+	if errIn != nil || errOut != nil || in < 0 || out < 0 {
+		return ModelPrice{}, false
+	}
+	return ModelPrice{InputPerMTok: in * 1e6, OutputPerMTok: out * 1e6}, true
+}
@@ -0,0 +1,68 @@
+// Copyright 2026 the P&AI authors. All rights reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package ai
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+const openRouterModelsFixture = `{"data":[
+	{"id":"qwen/qwen3-max","name":"Qwen: Qwen3 Max","context_length":262144,
+	 "pricing":{"prompt":"0.0000012","completion":"0.000006"}},
+	{"id":"meta-llama/llama-3.3-70b-instruct:free","name":"Llama 3.3 70B (free)","context_length":131072,
+	 "pricing":{"prompt":"0","completion":"0"}},
+	{"id":"openrouter/auto","name":"Auto Router","context_length":2000000,
+	 "pricing":{"prompt":"-1","completion":"-1"}}
+]}`
+
+func newOpenRouterModelsServer(t *testing.T, status *int) *httptest.Server {
+	t.Helper()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/models" || r.Header.Get("Authorization") != "Bearer test-key" {
+			t.Errorf("request = %s %s, auth %q", r.Method, r.URL.Path, r.Header.Get("Authorization"))
+		}
+		w.WriteHeader(*status)
+		_, _ = w.Write([]byte(openRouterModelsFixture))
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+func TestOpenRouterLLMAdapterRefreshModelsCachesLiveCatalog(t *testing.T) {
+	status := http.StatusOK
+	server := newOpenRouterModelsServer(t, &status)
+	provider := newOpenRouterLLMAdapter("test-key", server.URL)
+
+	if models := provider.Models(); len(models) != 1 || models[0].ID != openRouterLLMDefaultModel {
+		t.Fatalf("Models() before refresh = %+v, want the default model", models)
+	}
+	if err := provider.RefreshModels(context.Background()); err != nil {
+		t.Fatalf("RefreshModels() error = %v", err)
+	}
+	models := provider.Models()
+	if len(models) != 3 {
+		t.Fatalf("Models() = %d models, want 3", len(models))
+	}
+	qwen := models[0]
+	if qwen.MaxTokens != 262144 || qwen.Price == nil || qwen.Price.InputPerMTok != 1.2 || qwen.Price.OutputPerMTok != 6 {
+		t.Fatalf("qwen = %+v, price %+v; want 262144 tokens at $1.20/$6 per MTok", qwen, qwen.Price)
+	}
+	if free := models[1].Price; free == nil || free.InputPerMTok != 0 {
+		t.Fatalf("free model price = %+v, want zero", free)
+	}
+	if models[2].Price != nil {
+		t.Fatalf("openrouter/auto price = %+v, want none", models[2].Price)
+	}
+
+	status = http.StatusServiceUnavailable
+	if err := provider.RefreshModels(context.Background()); err == nil {
+		t.Fatal("RefreshModels() error = nil for a 503")
+	}
+	if got := provider.Models(); len(got) != 3 {
+		t.Fatalf("Models() after a failed refresh = %d models, want the cached 3", len(got))
+	}
+}
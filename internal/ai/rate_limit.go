@@ -0,0 +1,263 @@
+// Copyright 2026 the P&AI authors. All rights reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package ai
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/p-n-ai/pai-bot/internal/apperr"
+)
+
+// rateLimitMaxWait is the longest a request queues for a provider's rate
+// limit. A longer wait falls back to the next provider instead.
+const rateLimitMaxWait = 10 * time.Second
+
+// reservedOutputTokens is charged against a tokens-per-minute limit for a
+// request without MaxTokens, until the response reports its usage.
+const reservedOutputTokens = 1024
+
+var errRateLimited = apperr.Transient(errors.New("rate limited"))
+
+// RateLimit caps the traffic the router sends one provider. Zero fields are
+// unlimited. Requests over the limit wait their turn rather than fail, so a
+// burst is spread out instead of tripping the provider's own limits and
+// falling through the whole fallback chain.
+type RateLimit struct {
+	RequestsPerSecond float64
+	RequestsPerMinute int
+	TokensPerMinute   int
+}
+
+func (l RateLimit) empty() bool {
+	return l.RequestsPerSecond <= 0 && l.RequestsPerMinute <= 0 && l.TokensPerMinute <= 0
+}
+
+// SetRateLimits replaces every provider's rate limit, keyed by provider name.
+// A provider whose limit is unchanged keeps its current usage, so re-applying
+// the same settings does not grant a fresh burst.
+func (r *Router) SetRateLimits(limits map[string]RateLimit) {
+	now := time.Now()
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	table := make(map[string]*providerRateLimiter, len(limits))
+	for name, limit := range limits {
+		name = strings.TrimSpace(name)
+		if name == "" || limit.empty() {
+			continue
+		}
+		if current, ok := r.rateLimiters[name]; ok && current.limit == limit {
+			table[name] = current
+			continue
+		}
+		table[name] = newProviderRateLimiter(limit, now)
+	}
+	r.rateLimiters = table
+}
+
+// RateLimit returns provider's rate limit; ok is false when it has none.
+func (r *Router) RateLimit(provider string) (RateLimit, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	limiter, ok := r.rateLimiters[provider]
+	if !ok {
+		return RateLimit{}, false
+	}
+	return limiter.limit, true
+}
+
+// waitForRateLimit blocks until provider has room for a request of about
+// tokens tokens. It returns errRateLimited without waiting when the wait
+// would exceed rateLimitMaxWait, or ctx's error if ctx ends first. The
+// returned reservation is nil when the provider has no limit.
+func (r *Router) waitForRateLimit(ctx context.Context, provider string, tokens int) (*rateReservation, error) {
+	r.mu.RLock()
+	limiter := r.rateLimiters[provider]
+	r.mu.RUnlock()
+	if limiter == nil {
+		return nil, nil
+	}
+
+	reservation, wait := limiter.reserve(tokens, time.Now())
+	if wait > rateLimitMaxWait {
+		limiter.cancel(reservation)
+		return nil, errRateLimited
+	}
+	if wait <= 0 {
+		return reservation, nil
+	}
+	slog.Debug("waiting for AI provider rate limit", "provider", provider, "wait_ms", wait.Milliseconds())
+	timer := time.NewTimer(wait)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		limiter.cancel(reservation)
+		return nil, ctx.Err()
+	case <-timer.C:
+		return reservation, nil
+	}
+}
+
+// admitRequest clears provider for one request: it checks the circuit,
+// waits for the rate limit, then claims the half-open probe. Checking the
+// circuit first keeps a request from queueing for a provider it would skip.
+// errCircuitOpen and errRateLimited mean try the next provider; any other
+// error is ctx's.
+func (r *Router) admitRequest(ctx context.Context, provider string, tokens int) (*rateReservation, error) {
+	if r.isCircuitOpen(provider) {
+		return nil, errCircuitOpen
+	}
+	reservation, err := r.waitForRateLimit(ctx, provider, tokens)
+	if err != nil {
+		return nil, err
+	}
+	if !r.allowRequest(provider) {
+		// The circuit opened while this request waited.
+		reservation.cancel()
+		return nil, errCircuitOpen
+	}
+	return reservation, nil
+}
+
+// estimateRequestTokens guesses a request's token use before it is sent:
+// about four characters per prompt token plus the output it may produce.
+func estimateRequestTokens(req CompletionRequest) int {
+	chars := 0
+	for _, message := range req.Messages {
+		chars += len(message.Content)
+	}
+	output := req.MaxTokens
+	if output <= 0 {
+		output = reservedOutputTokens
+	}
+	return chars/4 + output
+}
+
+// providerRateLimiter holds one provider's token buckets. Requests reserve
+// capacity up front and may take it on credit; the debt is how long the
+// next caller has to wait, which queues concurrent requests in order.
+type providerRateLimiter struct {
+	limit RateLimit
+
+	mu       sync.Mutex
+	requests []*tokenBucket
+	tokens   *tokenBucket
+}
+
+// rateReservation is the capacity one request took, so it can be returned
+// or corrected once the request's real token use is known.
+type rateReservation struct {
+	limiter *providerRateLimiter
+	tokens  int
+}
+
+func newProviderRateLimiter(limit RateLimit, now time.Time) *providerRateLimiter {
+	limiter := &providerRateLimiter{limit: limit}
+	if limit.RequestsPerSecond > 0 {
+		limiter.requests = append(limiter.requests, newTokenBucket(max(limit.RequestsPerSecond, 1), limit.RequestsPerSecond, now))
+	}
+	if limit.RequestsPerMinute > 0 {
+		perMinute := float64(limit.RequestsPerMinute)
+		limiter.requests = append(limiter.requests, newTokenBucket(perMinute, perMinute/60, now))
+	}
+	if limit.TokensPerMinute > 0 {
+		perMinute := float64(limit.TokensPerMinute)
+		limiter.tokens = newTokenBucket(perMinute, perMinute/60, now)
+	}
+	return limiter
+}
+
+func (l *providerRateLimiter) reserve(tokens int, now time.Time) (*rateReservation, time.Duration) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	var wait time.Duration
+	for _, bucket := range l.requests {
+		wait = max(wait, bucket.take(1, now))
+	}
+	reservation := &rateReservation{limiter: l}
+	if l.tokens != nil {
+		// A request larger than the whole budget could never fit; it waits
+		// for an empty bucket instead.
+		reservation.tokens = min(tokens, int(l.tokens.capacity))
+		wait = max(wait, l.tokens.take(float64(reservation.tokens), now))
+	}
+	return reservation, wait
+}
+
+// cancel returns a reservation that was never sent.
+func (l *providerRateLimiter) cancel(reservation *rateReservation) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	for _, bucket := range l.requests {
+		bucket.give(1)
+	}
+	if l.tokens != nil {
+		l.tokens.give(float64(reservation.tokens))
+	}
+}
+
+// cancel returns a reservation whose request was never sent. It is a no-op
+// for a nil reservation.
+func (r *rateReservation) cancel() {
+	if r != nil {
+		r.limiter.cancel(r)
+	}
+}
+
+// release returns the tokens of a request that was sent but failed. The
+// request still counts against the provider's request limits.
+func (r *rateReservation) release() {
+	if r == nil || r.limiter.tokens == nil {
+		return
+	}
+	r.limiter.mu.Lock()
+	defer r.limiter.mu.Unlock()
+	r.limiter.tokens.give(float64(r.tokens))
+}
+
+// settle corrects the tokens-per-minute bucket with the tokens the request
+// actually used. It is a no-op for a nil reservation or unknown usage.
+func (r *rateReservation) settle(used int) {
+	if r == nil || used <= 0 || r.limiter.tokens == nil {
+		return
+	}
+	r.limiter.mu.Lock()
+	defer r.limiter.mu.Unlock()
+	r.limiter.tokens.give(float64(r.tokens - used))
+}
+
+// tokenBucket refills at perSecond up to capacity. Its level may go
+// negative while requests are queued on credit.
+type tokenBucket struct {
+	capacity  float64
+	perSecond float64
+	level     float64
+	updated   time.Time
+}
+
+func newTokenBucket(capacity, perSecond float64, now time.Time) *tokenBucket {
+	return &tokenBucket{capacity: capacity, perSecond: perSecond, level: capacity, updated: now}
+}
+
+// take removes n and returns how long until the bucket is back at zero.
+func (b *tokenBucket) take(n float64, now time.Time) time.Duration {
+	if elapsed := now.Sub(b.updated); elapsed > 0 {
+		b.level = min(b.capacity, b.level+elapsed.Seconds()*b.perSecond)
+		b.updated = now
+	}
+	b.level -= n
+	if b.level >= 0 {
+		return 0
+	}
+	return time.Duration(-b.level / b.perSecond * float64(time.Second))
+}
+
+// give adds n back, or removes it when n is negative.
+func (b *tokenBucket) give(n float64) {
+	b.level = min(b.capacity, b.level+n)
+}
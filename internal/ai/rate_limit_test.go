@@ -0,0 +1,132 @@
+// Copyright 2026 the P&AI authors. All rights reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package ai
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestProviderRateLimiter_QueuesRequestsOverTheLimit(t *testing.T) {
+	now := time.Unix(1_700_000_000, 0)
+	limiter := newProviderRateLimiter(RateLimit{RequestsPerSecond: 2, TokensPerMinute: 600}, now)
+
+	first, wait := limiter.reserve(100, now)
+	if wait != 0 {
+		t.Fatalf("first wait = %v, want 0", wait)
+	}
+	if _, wait := limiter.reserve(100, now); wait != 0 {
+		t.Fatalf("second wait = %v, want 0 within the burst", wait)
+	}
+	third, wait := limiter.reserve(100, now)
+	if wait != 500*time.Millisecond {
+		t.Fatalf("third wait = %v, want 500ms for one request per half second", wait)
+	}
+	limiter.cancel(third)
+
+	// 200 of 600 tokens are reserved and 5 have refilled, at 10 tokens a
+	// second; a 500-token request is 95 short.
+	big, wait := limiter.reserve(500, now.Add(500*time.Millisecond))
+	if wait != 9500*time.Millisecond {
+		t.Fatalf("big wait = %v, want 9.5s", wait)
+	}
+	limiter.cancel(big)
+
+	// The first request used only 50 tokens, so 50 come back.
+	first.settle(50)
+	if _, wait := limiter.reserve(450, now.Add(500*time.Millisecond)); wait != 0 {
+		t.Fatalf("wait after settling = %v, want 0", wait)
+	}
+}
+
+func TestRouter_RateLimitedProviderFallsBackWithoutOpeningCircuit(t *testing.T) {
+	router := NewRouterWithConfig(RouterConfig{RetryBackoff: []time.Duration{time.Millisecond}, BreakerFailureThreshold: 1})
+	router.Register("openai", NewMockProvider("from openai"))
+	router.Register("anthropic", NewMockProvider("from anthropic"))
+	router.SetRateLimits(map[string]RateLimit{"openai": {RequestsPerMinute: 1}})
+	if got, ok := router.RateLimit("openai"); !ok || got.RequestsPerMinute != 1 {
+		t.Fatalf("RateLimit(openai) = %+v, %v", got, ok)
+	}
+
+	req := CompletionRequest{Task: TaskTeaching, Messages: []Message{{Role: "user", Content: "hi"}}}
+	want := []string{"from openai", "from anthropic", "from anthropic"}
+	for i, reply := range want {
+		resp, err := router.Complete(context.Background(), req)
+		if err != nil {
+			t.Fatalf("Complete() #%d error = %v", i+1, err)
+		}
+		if resp.Content != reply {
+			t.Fatalf("Complete() #%d = %q, want %q", i+1, resp.Content, reply)
+		}
+	}
+	if router.isCircuitOpen("openai") {
+		t.Fatal("openai circuit opened; a rate-limited provider has not failed")
+	}
+
+	// Re-applying the same limit keeps the bucket empty.
+	router.SetRateLimits(map[string]RateLimit{"openai": {RequestsPerMinute: 1}})
+	if resp, _ := router.Complete(context.Background(), req); resp.Content != "from anthropic" {
+		t.Fatalf("Complete() after re-apply = %q, want the fallback", resp.Content)
+	}
+}
+
+func TestRouter_RateLimitWaitStopsWithContext(t *testing.T) {
+	router := NewRouter()
+	router.Register("openai", NewMockProvider("ok"))
+	router.SetRateLimits(map[string]RateLimit{"openai": {RequestsPerSecond: 0.5}})
+	req := CompletionRequest{Task: TaskTeaching, Messages: []Message{{Role: "user", Content: "hi"}}}
+	if _, err := router.Complete(context.Background(), req); err != nil {
+		t.Fatalf("first Complete() error = %v", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	if _, err := router.Complete(ctx, req); err != context.DeadlineExceeded {
+		t.Fatalf("queued Complete() error = %v, want context.DeadlineExceeded", err)
+	}
+}
+
+func TestRouter_OpenCircuitSkipsRateLimitWait(t *testing.T) {
+	router := NewRouterWithConfig(RouterConfig{RetryBackoff: []time.Duration{time.Millisecond}, BreakerFailureThreshold: 1})
+	router.Register("openai", NewMockProvider("from openai"))
+	router.Register("anthropic", NewMockProvider("from anthropic"))
+	router.SetRateLimits(map[string]RateLimit{"openai": {RequestsPerSecond: 0.2}})
+	limiter := router.rateLimiters["openai"]
+	limiter.reserve(0, time.Now()) // the next request would wait 5s
+	router.markFailure("openai", router.gen)
+
+	started := time.Now()
+	req := CompletionRequest{Task: TaskTeaching, Messages: []Message{{Role: "user", Content: "hi"}}}
+	resp, err := router.Complete(context.Background(), req)
+	if err != nil || resp.Content != "from anthropic" {
+		t.Fatalf("Complete() = %q, %v, want the fallback", resp.Content, err)
+	}
+	if elapsed := time.Since(started); elapsed > time.Second {
+		t.Fatalf("Complete() took %v, want no wait for an open circuit", elapsed)
+	}
+	// The skipped provider's bucket was not charged: one request is still
+	// about 5s ahead, not 10s.
+	reservation, wait := limiter.reserve(0, time.Now())
+	limiter.cancel(reservation)
+	if wait > 6*time.Second {
+		t.Fatalf("openai wait = %v, want the skipped request not reserved", wait)
+	}
+}
+
+func TestRouter_FailedAttemptsReturnTheirTokens(t *testing.T) {
+	router := NewRouterWithConfig(RouterConfig{RetryBackoff: []time.Duration{time.Millisecond}, BreakerFailureThreshold: 5})
+	router.Register("openai", NewMockProvider("ok").FailOn(1, errors.New("boom")).FailOn(2, errors.New("boom")))
+	router.SetRateLimits(map[string]RateLimit{"openai": {TokensPerMinute: 1000}})
+
+	req := CompletionRequest{Task: TaskTeaching, MaxTokens: 400, Messages: []Message{{Role: "user", Content: "hi"}}}
+	if _, err := router.Complete(context.Background(), req); err == nil {
+		t.Fatal("Complete() error = nil, want both attempts to fail")
+	}
+	// Two failed 400-token attempts would leave 200 tokens if kept.
+	if _, wait := router.rateLimiters["openai"].reserve(900, time.Now()); wait != 0 {
+		t.Fatalf("wait = %v, want failed attempts' tokens returned", wait)
+	}
+}
@@ -0,0 +1,113 @@
+// Copyright 2026 the P&AI authors. All rights reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package ai
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"log/slog"
+	"time"
+)
+
+// responseCacheKeyPrefix namespaces router entries in a shared cache.
+const responseCacheKeyPrefix = "ai:response:"
+
+// ResponseCache stores serialized completions by key. *cache.Cache
+// satisfies it.
+type ResponseCache interface {
+	// Get returns the value at key, or nil when it is unset.
+	Get(ctx context.Context, key string) ([]byte, error)
+	Set(ctx context.Context, key string, value []byte, ttl time.Duration) error
+}
+
+// SetResponseCache serves repeated grading and analysis requests from c for
+// ttl instead of calling a provider. Teaching and nudge requests are never
+// cached. A nil c or non-positive ttl turns caching off.
+func (r *Router) SetResponseCache(c ResponseCache, ttl time.Duration) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if c == nil || ttl <= 0 {
+		r.responseCache, r.responseCacheTTL = nil, 0
+		return
+	}
+	r.responseCache, r.responseCacheTTL = c, ttl
+}
+
+// responseCacheFor returns the cache and its key for req, or nil when req
+// is not cacheable.
+func (r *Router) responseCacheFor(req CompletionRequest) (ResponseCache, time.Duration, string) {
+	r.mu.RLock()
+	c, ttl := r.responseCache, r.responseCacheTTL
+	r.mu.RUnlock()
+	if c == nil || (req.Task != TaskGrading && req.Task != TaskAnalysis) {
+		return nil, 0, ""
+	}
+	key, err := responseCacheKey(req)
+	if err != nil {
+		return nil, 0, ""
+	}
+	return c, ttl, key
+}
+
+// responseCacheKey hashes everything that shapes the answer: the messages,
+// requested model and task, plus any schema, tools and sampling settings.
+// Tenant and tier are included because they pick the route and the model
+// policy, so a hit never hands a tenant output from a model it forbids.
+// The rest of the metadata, such as the learner, is left out so identical
+// requests within a tenant share an entry.
+func responseCacheKey(req CompletionRequest) (string, error) {
+	raw, err := json.Marshal(struct {
+		Messages         []Message             `json:"messages"`
+		Model            string                `json:"model"`
+		Task             string                `json:"task"`
+		StructuredOutput *StructuredOutputSpec `json:"structured_output,omitempty"`
+		Tools            []ToolDefinition      `json:"tools,omitempty"`
+		MaxTokens        int                   `json:"max_tokens,omitempty"`
+		Temperature      float64               `json:"temperature,omitempty"`
+		Tenant           string                `json:"tenant,omitempty"`
+		Tier             string                `json:"tier,omitempty"`
+	}{req.Messages, req.Model, req.Task.String(), req.StructuredOutput, req.Tools, req.MaxTokens, req.Temperature, req.Tenant, req.Tier})
+	if err != nil {
+		return "", err
+	}
+	sum := sha256.Sum256(raw)
+	return responseCacheKeyPrefix + hex.EncodeToString(sum[:]), nil
+}
+
+// cachedResponse looks key up. A hit comes back marked Cached with no
+// token usage or cost, since no provider was called; cache errors count as
+// a miss.
+func cachedResponse(ctx context.Context, c ResponseCache, key string) (CompletionResponse, bool) {
+	raw, err := c.Get(ctx, key)
+	if err != nil {
+		slog.Warn("AI response cache read failed", "error", err)
+		return CompletionResponse{}, false
+	}
+	if raw == nil {
+		return CompletionResponse{}, false
+	}
+	var resp CompletionResponse
+	if err := json.Unmarshal(raw, &resp); err != nil {
+		return CompletionResponse{}, false
+	}
+	resp.InputTokens, resp.OutputTokens, resp.CostUSD = 0, 0, 0
+	resp.Cached = true
+	return resp, true
+}
+
+// storeResponse caches resp under key. Truncated answers are not kept.
+func storeResponse(ctx context.Context, c ResponseCache, ttl time.Duration, key string, resp CompletionResponse) {
+	if resp.Truncated {
+		return
+	}
+	raw, err := json.Marshal(resp)
+	if err != nil {
+		return
+	}
+	if err := c.Set(ctx, key, raw, ttl); err != nil {
+		slog.Warn("AI response cache write failed", "error", err)
+	}
+}
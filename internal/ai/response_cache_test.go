@@ -0,0 +1,156 @@
+// Copyright 2026 the P&AI authors. All rights reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package ai_test
+
+import (
+	"context"
+	"encoding/json"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/p-n-ai/pai-bot/internal/ai"
+)
+
+type memoryResponseCache struct {
+	mu      sync.Mutex
+	entries map[string][]byte
+	ttl     time.Duration
+}
+
+func (c *memoryResponseCache) Get(_ context.Context, key string) ([]byte, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.entries[key], nil
+}
+
+func (c *memoryResponseCache) Set(_ context.Context, key string, value []byte, ttl time.Duration) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.entries == nil {
+		c.entries = make(map[string][]byte)
+	}
+	c.entries[key] = value
+	c.ttl = ttl
+	return nil
+}
+
+func TestRouter_ResponseCacheServesRepeatedGrading(t *testing.T) {
+	router := newTestRouter()
+	provider := &countingProvider{response: "Correct: x = 2"}
+	router.Register("openai", provider)
+	cache := &memoryResponseCache{}
+	router.SetResponseCache(cache, time.Hour)
+
+	grade := func(answer string) ai.CompletionResponse {
+		t.Helper()
+		resp, err := router.Complete(context.Background(), ai.CompletionRequest{
+			Task:     ai.TaskGrading,
+			Messages: []ai.Message{{Role: "user", Content: "Grade: 2x + 3 = 7, answer " + answer}},
+		})
+		if err != nil {
+			t.Fatalf("Complete() error = %v", err)
+		}
+		return resp
+	}
+
+	first := grade("x = 2")
+	second := grade("x = 2")
+	if provider.calls != 1 {
+		t.Fatalf("provider calls = %d, want 1", provider.calls)
+	}
+	if first.Cached || !second.Cached || second.Content != first.Content {
+		t.Fatalf("first = %+v, second = %+v, want the second served from cache", first, second)
+	}
+	if second.InputTokens != 0 || second.OutputTokens != 0 {
+		t.Fatalf("cached usage = %d/%d, want none", second.InputTokens, second.OutputTokens)
+	}
+	if cache.ttl != time.Hour {
+		t.Fatalf("ttl = %v, want 1h", cache.ttl)
+	}
+
+	grade("x = 3")
+	if provider.calls != 2 {
+		t.Fatalf("provider calls = %d, want a different answer to miss", provider.calls)
+	}
+}
+
+func TestRouter_ResponseCacheIsPerTenantAndTier(t *testing.T) {
+	router := newTestRouter()
+	provider := &countingProvider{response: "Correct"}
+	router.Register("openai", provider)
+	router.SetResponseCache(&memoryResponseCache{}, time.Hour)
+
+	grade := func(tenant, tier string) {
+		t.Helper()
+		_, err := router.Complete(context.Background(), ai.CompletionRequest{
+			RequestMetadata: ai.RequestMetadata{Tenant: tenant, Tier: tier},
+			Task:            ai.TaskGrading,
+			Messages:        []ai.Message{{Role: "user", Content: "Grade: 2x + 3 = 7, answer x = 2"}},
+		})
+		if err != nil {
+			t.Fatalf("Complete() error = %v", err)
+		}
+	}
+
+	grade("tenant-a", "free")
+	grade("tenant-b", "free")
+	grade("tenant-a", "pro")
+	if provider.calls != 3 {
+		t.Fatalf("provider calls = %d, want each tenant and tier to miss", provider.calls)
+	}
+	grade("tenant-a", "free")
+	if provider.calls != 3 {
+		t.Fatalf("provider calls = %d, want a repeat within a tenant and tier to hit", provider.calls)
+	}
+}
+
+func TestRouter_ResponseCacheSkipsTeaching(t *testing.T) {
+	router := newTestRouter()
+	provider := &countingProvider{response: "Let's start with the x term."}
+	router.Register("openai", provider)
+	router.SetResponseCache(&memoryResponseCache{}, time.Hour)
+
+	for range 2 {
+		if _, err := router.Complete(context.Background(), ai.CompletionRequest{
+			Task:     ai.TaskTeaching,
+			Messages: []ai.Message{{Role: "user", Content: "How do I solve 2x + 3 = 7?"}},
+		}); err != nil {
+			t.Fatalf("Complete() error = %v", err)
+		}
+	}
+	if provider.calls != 2 {
+		t.Fatalf("provider calls = %d, want teaching never cached", provider.calls)
+	}
+}
+
+func TestRouter_CompleteJSONResponseCacheFillsOutput(t *testing.T) {
+	router := newTestRouter()
+	mock := ai.NewMockProvider(`{"final_answer":"12"}`)
+	router.Register("openai", mock)
+	router.SetResponseCache(&memoryResponseCache{}, time.Hour)
+	req := ai.CompletionRequest{
+		Task:     ai.TaskAnalysis,
+		Messages: []ai.Message{{Role: "user", Content: "grade this"}},
+		StructuredOutput: &ai.StructuredOutputSpec{
+			Name:       "grading_result",
+			JSONSchema: json.RawMessage(`{"type":"object","properties":{"final_answer":{"type":"string"}},"required":["final_answer"]}`),
+			Strict:     true,
+		},
+	}
+
+	var first structuredReply
+	if _, err := router.CompleteJSON(context.Background(), req, &first); err != nil {
+		t.Fatalf("CompleteJSON() error = %v", err)
+	}
+	mock.Response = `{"final_answer":"99"}`
+	var second structuredReply
+	resp, err := router.CompleteJSON(context.Background(), req, &second)
+	if err != nil {
+		t.Fatalf("CompleteJSON() error = %v", err)
+	}
+	if !resp.Cached || second.FinalAnswer != "12" {
+		t.Fatalf("second = %#v (cached %v), want the cached 12", second, resp.Cached)
+	}
+}
@@ -0,0 +1,173 @@
+// Copyright 2026 the P&AI authors. All rights reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package ai
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"log/slog"
+	"math/rand/v2"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// RetryConfig sets how provider HTTP calls retry transient failures. The zero
+// value disables retries.
+type RetryConfig struct {
+	// MaxRetries is the number of retries after the first attempt.
+	MaxRetries int
+	// BaseDelay is the backoff before the first retry; it doubles after
+	// each one.
+	BaseDelay time.Duration
+	// MaxDelay caps the backoff. A provider asking to wait longer than this
+	// is not retried and its error response is returned as is; a router
+	// told about the transport retry (Router.SetTransportRetry) then falls
+	// back to the next provider instead of stalling the reply.
+	MaxDelay time.Duration
+}
+
+// DefaultRetryConfig returns a short retry budget that rides out brief rate
+// limiting without holding a learner's reply for long. Zero delays in an
+// enabled RetryConfig take these values.
+func DefaultRetryConfig() RetryConfig {
+	return RetryConfig{
+		MaxRetries: 2,
+		BaseDelay:  500 * time.Millisecond,
+		MaxDelay:   10 * time.Second,
+	}
+}
+
+// maxRetryHintBody bounds how much of an error body is read for a
+// retry_after hint.
+const maxRetryHintBody = 64 << 10
+
+// retryTransport retries requests that fail with a transient status, waiting
+// for the provider's Retry-After hint when it gives one and backing off
+// exponentially when it does not.
+type retryTransport struct {
+	next   http.RoundTripper
+	cfg    RetryConfig
+	jitter func(time.Duration) time.Duration
+}
+
+func newRetryTransport(next http.RoundTripper, cfg RetryConfig) *retryTransport {
+	def := DefaultRetryConfig()
+	if cfg.BaseDelay <= 0 {
+		cfg.BaseDelay = def.BaseDelay
+	}
+	if cfg.MaxDelay <= 0 {
+		cfg.MaxDelay = def.MaxDelay
+	}
+	return &retryTransport{next: next, cfg: cfg, jitter: halfJitter}
+}
+
+func (t *retryTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	for attempt := 0; ; attempt++ {
+		resp, err := t.next.RoundTrip(req)
+		if err != nil || !retryableStatus(resp.StatusCode) || attempt >= t.cfg.MaxRetries {
+			return resp, err
+		}
+		if req.Body != nil && req.GetBody == nil {
+			return resp, nil
+		}
+
+		delay, hinted := retryAfter(resp)
+		if !hinted {
+			delay = t.jitter(min(t.cfg.BaseDelay<<attempt, t.cfg.MaxDelay))
+		} else if delay > t.cfg.MaxDelay {
+			return resp, nil
+		}
+		_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, maxRetryHintBody))
+		_ = resp.Body.Close()
+
+		slog.Warn("AI provider returned a transient error, retrying",
+			"host", req.URL.Host,
+			"status", resp.StatusCode,
+			"attempt", attempt+1,
+			"delay_ms", delay.Milliseconds(),
+		)
+		timer := time.NewTimer(delay)
+		select {
+		case <-req.Context().Done():
+			timer.Stop()
+			return nil, req.Context().Err()
+		case <-timer.C:
+		}
+
+		if req.GetBody != nil {
+			body, err := req.GetBody()
+			if err != nil {
+				return nil, err
+			}
+			req = req.Clone(req.Context())
+			req.Body = body
+		}
+	}
+}
+
+// retryableStatus reports whether status is worth retrying: request timeouts,
+// rate limits, server errors and Anthropic's 529 overloaded.
+func retryableStatus(status int) bool {
+	switch status {
+	case http.StatusRequestTimeout, http.StatusTooManyRequests,
+		http.StatusInternalServerError, http.StatusBadGateway,
+		http.StatusServiceUnavailable, http.StatusGatewayTimeout, 529:
+		return true
+	}
+	return false
+}
+
+// retryAfter reads the provider's wait hint from the retry-after-ms or
+// Retry-After header, or a retry_after field in the JSON error body. Reading
+// the body replaces it, so the caller can still return the response as is.
+func retryAfter(resp *http.Response) (time.Duration, bool) {
+	if ms, err := strconv.ParseFloat(strings.TrimSpace(resp.Header.Get("retry-after-ms")), 64); err == nil && ms >= 0 {
+		return time.Duration(ms * float64(time.Millisecond)), true
+	}
+	if value := strings.TrimSpace(resp.Header.Get("Retry-After")); value != "" {
+		if seconds, err := strconv.ParseFloat(value, 64); err == nil && seconds >= 0 {
+			return time.Duration(seconds * float64(time.Second)), true
+		}
+		if at, err := http.ParseTime(value); err == nil {
+			return max(time.Until(at), 0), true
+		}
+	}
+	if resp.Body == nil {
+		return 0, false
+	}
+
+	body, _ := io.ReadAll(io.LimitReader(resp.Body, maxRetryHintBody))
+	resp.Body = struct {
+		io.Reader
+		io.Closer
+	}{io.MultiReader(bytes.NewReader(body), resp.Body), resp.Body}
+	var hint struct {
+		RetryAfter *float64 `json:"retry_after"`
+		Error      struct {
+			RetryAfter *float64 `json:"retry_after"`
+		} `json:"error"`
+	}
+	if json.Unmarshal(body, &hint) != nil {
+		return 0, false
+	}
+	seconds := hint.RetryAfter
+	if seconds == nil {
+		seconds = hint.Error.RetryAfter
+	}
+	if seconds == nil || *seconds < 0 {
+		return 0, false
+	}
+	return time.Duration(*seconds * float64(time.Second)), true
+}
+
+// halfJitter spreads retries from concurrent requests over [d/2, d).
+func halfJitter(d time.Duration) time.Duration {
+	if d <= 1 {
+		return d
+	}
+	return d/2 + rand.N(d/2)
+}
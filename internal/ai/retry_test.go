@@ -0,0 +1,163 @@
+// Copyright 2026 the P&AI authors. All rights reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package ai
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func newRetryTestClient(cfg RetryConfig) *http.Client {
+	transport := newRetryTransport(http.DefaultTransport, cfg)
+	transport.jitter = func(d time.Duration) time.Duration { return d }
+	return &http.Client{Transport: transport}
+}
+
+func TestRetryTransport_RetriesTransientStatusWithBody(t *testing.T) {
+	var bodies []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		bodies = append(bodies, string(body))
+		if len(bodies) < 3 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		_, _ = io.WriteString(w, "ok")
+	}))
+	defer server.Close()
+
+	client := newRetryTestClient(RetryConfig{MaxRetries: 2, BaseDelay: time.Millisecond, MaxDelay: 5 * time.Millisecond})
+	resp, err := client.Post(server.URL, "application/json", strings.NewReader(`{"q":1}`))
+	if err != nil {
+		t.Fatalf("Post() error = %v", err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("status = %d, want 200 after retries", resp.StatusCode)
+	}
+	if len(bodies) != 3 || bodies[2] != `{"q":1}` {
+		t.Fatalf("request bodies = %q, want the body resent on each of 3 attempts", bodies)
+	}
+}
+
+func TestRetryTransport_StopsAfterMaxRetries(t *testing.T) {
+	calls := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		calls++
+		w.WriteHeader(http.StatusTooManyRequests)
+		_, _ = io.WriteString(w, `{"error":"slow down"}`)
+	}))
+	defer server.Close()
+
+	client := newRetryTestClient(RetryConfig{MaxRetries: 1, BaseDelay: time.Millisecond})
+	resp, err := client.Get(server.URL)
+	if err != nil {
+		t.Fatalf("Get() error = %v", err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+	body, _ := io.ReadAll(resp.Body)
+	if resp.StatusCode != http.StatusTooManyRequests || string(body) != `{"error":"slow down"}` {
+		t.Fatalf("final response = %d %q, want the last 429 intact", resp.StatusCode, body)
+	}
+	if calls != 2 {
+		t.Fatalf("calls = %d, want 2", calls)
+	}
+}
+
+func TestRetryTransport_DoesNotRetryClientErrors(t *testing.T) {
+	calls := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		calls++
+		w.WriteHeader(http.StatusBadRequest)
+	}))
+	defer server.Close()
+
+	resp, err := newRetryTestClient(RetryConfig{MaxRetries: 3, BaseDelay: time.Millisecond}).Get(server.URL)
+	if err != nil {
+		t.Fatalf("Get() error = %v", err)
+	}
+	_ = resp.Body.Close()
+	if calls != 1 {
+		t.Fatalf("calls = %d, want 1 for a 400", calls)
+	}
+}
+
+func TestRetryTransport_HonorsRetryAfterHints(t *testing.T) {
+	tests := []struct {
+		name   string
+		header map[string]string
+		body   string
+		want   time.Duration
+	}{
+		{name: "seconds header", header: map[string]string{"Retry-After": "2"}, want: 2 * time.Second},
+		{name: "milliseconds header", header: map[string]string{"retry-after-ms": "150", "Retry-After": "9"}, want: 150 * time.Millisecond},
+		{name: "body field", body: `{"retry_after":1.5}`, want: 1500 * time.Millisecond},
+		{name: "nested body field", body: `{"error":{"message":"rate limited","retry_after":3}}`, want: 3 * time.Second},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resp := &http.Response{Header: http.Header{}, Body: io.NopCloser(strings.NewReader(tt.body))}
+			for key, value := range tt.header {
+				resp.Header.Set(key, value)
+			}
+			got, ok := retryAfter(resp)
+			if !ok || got != tt.want {
+				t.Fatalf("retryAfter() = %v, %v; want %v, true", got, ok, tt.want)
+			}
+			body, _ := io.ReadAll(resp.Body)
+			if string(body) != tt.body {
+				t.Fatalf("body after retryAfter() = %q, want %q", body, tt.body)
+			}
+		})
+	}
+
+	resp := &http.Response{Header: http.Header{}, Body: io.NopCloser(strings.NewReader(`{"error":"busy"}`))}
+	if _, ok := retryAfter(resp); ok {
+		t.Fatal("retryAfter() found a hint in a body without one")
+	}
+}
+
+func TestRetryTransport_GivesUpWhenHintExceedsMaxDelay(t *testing.T) {
+	calls := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		calls++
+		w.Header().Set("Retry-After", "60")
+		w.WriteHeader(http.StatusTooManyRequests)
+	}))
+	defer server.Close()
+
+	resp, err := newRetryTestClient(RetryConfig{MaxRetries: 3, MaxDelay: time.Second}).Get(server.URL)
+	if err != nil {
+		t.Fatalf("Get() error = %v", err)
+	}
+	_ = resp.Body.Close()
+	if calls != 1 {
+		t.Fatalf("calls = %d, want 1 so the router can fall back instead of waiting", calls)
+	}
+}
+
+func TestRetryTransport_StopsWaitingWhenContextEnds(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set("Retry-After", "5")
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer server.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	req, _ := http.NewRequestWithContext(ctx, http.MethodGet, server.URL, nil)
+	started := time.Now()
+	_, err := newRetryTestClient(RetryConfig{MaxRetries: 1, MaxDelay: 10 * time.Second}).Do(req)
+	if err == nil {
+		t.Fatal("Do() error = nil, want the context error")
+	}
+	if elapsed := time.Since(started); elapsed > time.Second {
+		t.Fatalf("Do() waited %v, want it to stop when the context ends", elapsed)
+	}
+}
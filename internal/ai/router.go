@@ -6,6 +6,7 @@ package ai
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"log/slog"
 	"reflect"
@@ -14,30 +15,47 @@ import (
 	"time"
 
 	"github.com/xeipuuv/gojsonschema"
+
+	"github.com/p-n-ai/pai-bot/internal/apperr"
 )
 
 // Router selects the best provider based on task type and availability.
 type Router struct {
-	providers               map[string]Provider
-	fallback                []string // ordered fallback chain
-	defaultModels           map[string]string
-	retryBackoff            []time.Duration
-	breakerFailureThreshold int
-	breakerCooldown         time.Duration
-	breakerStateByProvider  map[string]breakerState
-	structuredBreakerState  map[string]breakerState
-	traceFunc               func(CompletionTrace)
+	providers                map[string]Provider
+	fallback                 []string // ordered fallback chain
+	defaultModels            map[string]string
+	taskModels               map[string]map[TaskType]string
+	taskRoutes               map[TaskType][]TaskRoute
+	tierRoutes               map[string]map[TaskType][]TaskRoute
+	modelPolicies            map[string]ModelPolicy
+	retryBackoff             []time.Duration
+	transportRetry           bool
+	breakerFailureThreshold  int
+	breakerCooldown          time.Duration
+	breakerStateByProvider   map[string]breakerState
+	structuredBreakerState   map[string]breakerState
+	traceFunc                func(CompletionTrace)
+	breakerObserver          func(BreakerTransition)
+	modelPrices              map[string]ModelPrice
+	configuredPrices         map[string]ModelPrice
+	discoveredPrices         map[string]ModelPrice
+	unpricedModels           map[string]bool
+	responseCache            ResponseCache
+	responseCacheTTL         time.Duration
+	health                   map[string]ProviderHealth
+	healthObserver           func(ProviderHealthChange)
+	latency                  map[string]*latencyRing
+	latencyBudget            time.Duration
+	rateLimiters             map[string]*providerRateLimiter
+	generationDefaults       map[TaskType]GenerationDefaults
+	middleware               []Middleware
+	tenantGenerationDefaults map[string]map[TaskType]GenerationDefaults
 	// gen bumps on ReplaceProviders so in-flight requests from an older
 	// provider set cannot pollute the fresh breaker maps by name.
 	gen uint64
 	mu  sync.RWMutex
 }
 
-type breakerState struct {
-	consecutiveFailures int
-	openUntil           time.Time
-}
-
 // RouterConfig defines retry and circuit-breaker behavior.
 type RouterConfig struct {
 	RetryBackoff            []time.Duration
@@ -67,6 +85,7 @@ func NewRouterWithConfig(cfg RouterConfig) *Router {
 	return &Router{
 		providers:               make(map[string]Provider),
 		defaultModels:           make(map[string]string),
+		taskModels:              make(map[string]map[TaskType]string),
 		retryBackoff:            retryBackoff,
 		breakerFailureThreshold: breakerThreshold,
 		breakerCooldown:         breakerCooldown,
@@ -98,6 +117,8 @@ type ProviderRegistration struct {
 	Name         string
 	Provider     Provider
 	DefaultModel string
+	// TaskModels overrides DefaultModel for specific task types.
+	TaskModels map[TaskType]string
 }
 
 // ReplaceProviders atomically swaps the full provider set; absent providers unregister and breaker state resets.
@@ -109,8 +130,11 @@ func (r *Router) ReplaceProviders(regs []ProviderRegistration) {
 	r.providers = make(map[string]Provider, len(regs))
 	r.fallback = nil
 	r.defaultModels = make(map[string]string, len(regs))
+	r.taskModels = make(map[string]map[TaskType]string, len(regs))
 	r.breakerStateByProvider = make(map[string]breakerState, len(regs))
 	r.structuredBreakerState = make(map[string]breakerState, len(regs))
+	r.health = nil
+	r.latency = nil
 	for _, reg := range regs {
 		name := strings.TrimSpace(reg.Name)
 		if name == "" || reg.Provider == nil {
@@ -124,6 +148,15 @@ func (r *Router) ReplaceProviders(regs []ProviderRegistration) {
 		if model := strings.TrimSpace(reg.DefaultModel); model != "" {
 			r.defaultModels[name] = model
 		}
+		for task, model := range reg.TaskModels {
+			if model = strings.TrimSpace(model); model == "" {
+				continue
+			}
+			if r.taskModels[name] == nil {
+				r.taskModels[name] = make(map[TaskType]string)
+			}
+			r.taskModels[name][task] = model
+		}
 		r.breakerStateByProvider[name] = breakerState{}
 		r.structuredBreakerState[name] = breakerState{}
 	}
@@ -145,30 +178,51 @@ func (r *Router) SetTraceFunc(traceFunc func(CompletionTrace)) {
 	r.traceFunc = traceFunc
 }
 
-// Complete routes a request to the best available provider.
+// Complete routes a request to the best available provider, through the
+// router's middleware.
 func (r *Router) Complete(ctx context.Context, req CompletionRequest) (CompletionResponse, error) {
+	return r.withMiddleware(r.complete)(ctx, r.withGenerationDefaults(req))
+}
+
+func (r *Router) complete(ctx context.Context, req CompletionRequest) (CompletionResponse, error) {
 	providers, order, gen := r.snapshotProviders()
 	if len(order) == 0 {
-		return CompletionResponse{}, fmt.Errorf("all AI providers failed (no providers registered)")
+		return CompletionResponse{}, errNoProviders
 	}
 
-	var failures []string
-	for _, name := range order {
+	plan, err := r.requestPlan(req.Task, req.RequestMetadata, req.Model, providers, order)
+	if err != nil {
+		return CompletionResponse{}, err
+	}
+
+	logger := slog.With(req.logAttrs()...)
+	cache, cacheTTL, cacheKey := r.responseCacheFor(req)
+	if cache != nil {
+		if resp, ok := cachedResponse(ctx, cache, cacheKey); ok {
+			logger.Debug("AI request served from cache", "model", resp.Model)
+			return resp, nil
+		}
+	}
+	var failures providerFailures
+	for _, step := range plan {
+		name := step.provider
 		provider := providers[name]
 		if provider == nil {
 			continue
 		}
-		if r.isCircuitOpen(name) {
-			failures = append(failures, fmt.Sprintf("%s: circuit open", name))
+		providerReq := req
+		providerReq.Model = step.modelFor(req.Model)
+		reservation, err := r.admitRequest(ctx, name, estimateRequestTokens(providerReq))
+		if errors.Is(err, errCircuitOpen) || errors.Is(err, errRateLimited) {
+			failures.add(name, err)
 			continue
 		}
-
-		providerReq := req
-		if providerReq.Model == "" {
-			providerReq.Model = r.defaultModelForProvider(name)
+		if err != nil {
+			return CompletionResponse{}, err
 		}
+
 		startedAt := time.Now()
-		resp, err := r.completeWithRetry(ctx, provider, providerReq)
+		resp, reservation, attemptStart, err := r.completeWithRetry(ctx, name, provider, providerReq, reservation)
 		r.emitTrace(CompletionTrace{
 			Provider:    name,
 			Request:     providerReq,
@@ -178,62 +232,101 @@ func (r *Router) Complete(ctx context.Context, req CompletionRequest) (Completio
 			CompletedAt: time.Now(),
 		})
 		if err != nil {
+			if ctxErr := ctx.Err(); ctxErr != nil {
+				// The caller gave up; that says nothing about the provider.
+				r.releaseProbe(name)
+				return CompletionResponse{}, ctxErr
+			}
 			r.markFailure(name, gen)
-			slog.Warn("AI provider failed, trying next",
+			logger.Warn("AI provider failed, trying next",
 				"provider", name,
 				"error", err,
 			)
-			failures = append(failures, fmt.Sprintf("%s: %v", name, err))
+			failures.add(name, err)
 			continue
 		}
 
 		r.markSuccess(name, gen)
-		slog.Debug("AI request completed",
+		r.recordLatency(name, gen, time.Since(attemptStart))
+		reservation.settle(resp.InputTokens + resp.OutputTokens)
+		r.priceResponse(name, &resp)
+		if cache != nil {
+			storeResponse(ctx, cache, cacheTTL, cacheKey, resp)
+		}
+		logger.Debug("AI request completed",
 			"provider", name,
 			"model", resp.Model,
 			"input_tokens", resp.InputTokens,
 			"output_tokens", resp.OutputTokens,
+			"cost_usd", resp.CostUSD,
 		)
 		return resp, nil
 	}
 
-	return CompletionResponse{}, fmt.Errorf("all AI providers failed: %s", strings.Join(failures, "; "))
+	return CompletionResponse{}, failures.err()
 }
 
 // CompleteJSON requests structured JSON output and unmarshals it into out.
-// If no model is specified, it prefers a cheap default per provider.
+// If no model is specified, it prefers a cheap default per provider. The
+// request runs through the router's middleware.
 func (r *Router) CompleteJSON(ctx context.Context, req CompletionRequest, out any) (CompletionResponse, error) {
 	if err := validateCompleteJSONRequest(req, out); err != nil {
 		return CompletionResponse{}, err
 	}
+	return r.withMiddleware(func(ctx context.Context, req CompletionRequest) (CompletionResponse, error) {
+		return r.completeJSON(ctx, req, out)
+	})(ctx, r.withGenerationDefaults(req))
+}
+
+func (r *Router) completeJSON(ctx context.Context, req CompletionRequest, out any) (CompletionResponse, error) {
 
 	providers, order, gen := r.snapshotProviders()
 	if len(order) == 0 {
-		return CompletionResponse{}, fmt.Errorf("all AI providers failed (no providers registered)")
+		return CompletionResponse{}, errNoProviders
 	}
 
-	var failures []string
-	for _, name := range order {
+	plan, err := r.requestPlan(req.Task, req.RequestMetadata, req.Model, providers, order)
+	if err != nil {
+		return CompletionResponse{}, err
+	}
+
+	logger := slog.With(req.logAttrs()...)
+	cache, cacheTTL, cacheKey := r.responseCacheFor(req)
+	if cache != nil {
+		if resp, ok := cachedResponse(ctx, cache, cacheKey); ok && unmarshalStructuredOutput(resp.StructuredOutput, out) == nil {
+			logger.Debug("AI structured request served from cache", "model", resp.Model)
+			return resp, nil
+		}
+	}
+	var failures providerFailures
+	for _, step := range plan {
+		name := step.provider
 		provider := providers[name]
 		if provider == nil {
 			continue
 		}
-		providerReq, ok := r.structuredProviderRequest(name, req)
+		stepReq := req
+		stepReq.Model = step.modelFor(req.Model)
+		providerReq, ok := r.structuredProviderRequest(name, stepReq)
 		if !ok {
-			failures = append(failures, fmt.Sprintf("%s: structured output unsupported", name))
+			failures.add(name, errStructuredUnsupported)
 			continue
 		}
-		if r.isCircuitOpen(name) {
-			failures = append(failures, fmt.Sprintf("%s: circuit open", name))
+		if r.isStructuredCircuitOpen(name) {
+			failures.add(name, errStructuredCircuitOpen)
 			continue
 		}
-		if r.isStructuredCircuitOpen(name) {
-			failures = append(failures, fmt.Sprintf("%s: structured circuit open", name))
+		reservation, err := r.admitRequest(ctx, name, estimateRequestTokens(providerReq))
+		if errors.Is(err, errCircuitOpen) || errors.Is(err, errRateLimited) {
+			failures.add(name, err)
 			continue
 		}
+		if err != nil {
+			return CompletionResponse{}, err
+		}
 
 		startedAt := time.Now()
-		resp, err := r.completeWithRetry(ctx, provider, providerReq)
+		resp, reservation, attemptStart, err := r.completeWithRetry(ctx, name, provider, providerReq, reservation)
 		trace := CompletionTrace{
 			Provider:    name,
 			Request:     providerReq,
@@ -244,12 +337,16 @@ func (r *Router) CompleteJSON(ctx context.Context, req CompletionRequest, out an
 		}
 		if err != nil {
 			r.emitTrace(trace)
+			if ctxErr := ctx.Err(); ctxErr != nil {
+				r.releaseProbe(name)
+				return CompletionResponse{}, ctxErr
+			}
 			r.markFailure(name, gen)
-			slog.Warn("AI provider failed structured request, trying next",
+			logger.Warn("AI provider failed structured request, trying next",
 				"provider", name,
 				"error", err,
 			)
-			failures = append(failures, fmt.Sprintf("%s: %v", name, err))
+			failures.add(name, err)
 			continue
 		}
 
@@ -261,32 +358,43 @@ func (r *Router) CompleteJSON(ctx context.Context, req CompletionRequest, out an
 			payloadErr = unmarshalStructuredOutput(raw, out)
 		}
 		if payloadErr != nil {
+			reservation.settle(resp.InputTokens + resp.OutputTokens)
 			trace.Error = payloadErr.Error()
 			r.emitTrace(trace)
+			// The transport answered, so the provider's main circuit (and any
+			// half-open probe) closes; only the structured circuit counts this.
+			r.markSuccess(name, gen)
 			r.markStructuredFailure(name, gen)
-			slog.Warn("AI provider returned invalid structured payload, trying next",
+			logger.Warn("AI provider returned invalid structured payload, trying next",
 				"provider", name,
 				"error", payloadErr,
 			)
-			failures = append(failures, fmt.Sprintf("%s: %v", name, payloadErr))
+			failures.add(name, payloadErr)
 			continue
 		}
 
 		r.markSuccess(name, gen)
+		r.recordLatency(name, gen, time.Since(attemptStart))
 		r.markStructuredSuccess(name, gen)
+		reservation.settle(resp.InputTokens + resp.OutputTokens)
 		resp.StructuredOutput = raw
+		r.priceResponse(name, &resp)
 		trace.Response = &resp
 		r.emitTrace(trace)
-		slog.Debug("AI structured request completed",
+		if cache != nil {
+			storeResponse(ctx, cache, cacheTTL, cacheKey, resp)
+		}
+		logger.Debug("AI structured request completed",
 			"provider", name,
 			"model", resp.Model,
 			"input_tokens", resp.InputTokens,
 			"output_tokens", resp.OutputTokens,
+			"cost_usd", resp.CostUSD,
 		)
 		return resp, nil
 	}
 
-	return CompletionResponse{}, fmt.Errorf("all AI providers failed: %s", strings.Join(failures, "; "))
+	return CompletionResponse{}, failures.err()
 }
 
 // HasProvider returns true if at least one provider is registered.
@@ -308,6 +416,23 @@ func (r *Router) HasNativeProvider() bool {
 	return false
 }
 
+// SelectModel reports which provider and model Complete would try first for
+// task, skipping providers whose circuit is open. An empty model means the
+// provider's own default. ok is false when no provider is available.
+func (r *Router) SelectModel(task TaskType, model string) (provider, modelID string, ok bool) {
+	providers, order, _ := r.snapshotProviders()
+	for _, step := range r.taskPlan(task, "", providers, order) {
+		if providers[step.provider] == nil || r.isCircuitOpen(step.provider) {
+			continue
+		}
+		if model == "" {
+			model = step.model
+		}
+		return step.provider, model, true
+	}
+	return "", "", false
+}
+
 func (r *Router) snapshotProviders() (map[string]Provider, []string, uint64) {
 	r.mu.RLock()
 	defer r.mu.RUnlock()
@@ -343,36 +468,75 @@ func completionErrorString(err error) string {
 	return err.Error()
 }
 
-func (r *Router) completeWithRetry(ctx context.Context, provider Provider, req CompletionRequest) (CompletionResponse, error) {
+// SetTransportRetry records whether provider HTTP clients already retry
+// transient failures themselves. When they do, the router sends each request
+// to a provider once and falls back on failure rather than stacking its own
+// backoff on top, which would also ignore the provider's Retry-After.
+func (r *Router) SetTransportRetry(enabled bool) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.transportRetry = enabled
+}
+
+// attemptBackoff returns the waits between router-level attempts at one
+// provider; none when the transport retries.
+func (r *Router) attemptBackoff() []time.Duration {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	if r.transportRetry {
+		return nil
+	}
+	return r.retryBackoff
+}
+
+// retryable reports whether another attempt at the same provider could
+// succeed. Permanent failures such as a 400 or 404 fail the same way again.
+func retryable(err error) bool {
+	return apperr.ClassOf(err) != apperr.ClassPermanent
+}
+
+// completeWithRetry sends req to provider, retrying transient and unknown
+// failures after r.retryBackoff. Each attempt holds its own rate limit
+// reservation, starting with reservation; a failed attempt releases its
+// tokens. On success the caller settles the returned reservation; the
+// returned time is when the successful attempt started, so latency leaves
+// out earlier attempts and backoff. Retries stop early when the rate limit
+// has no room.
+func (r *Router) completeWithRetry(ctx context.Context, name string, provider Provider, req CompletionRequest, reservation *rateReservation) (CompletionResponse, *rateReservation, time.Time, error) {
 	var lastErr error
-	attempts := 1 + len(r.retryBackoff)
+	backoff := r.attemptBackoff()
+	attempts := 1 + len(backoff)
 
 	for attempt := 1; attempt <= attempts; attempt++ {
+		if attempt > 1 {
+			var err error
+			reservation, err = r.waitForRateLimit(ctx, name, estimateRequestTokens(req))
+			if errors.Is(err, errRateLimited) {
+				break
+			}
+			if err != nil {
+				return CompletionResponse{}, nil, time.Time{}, err
+			}
+		}
+		attemptStart := time.Now()
 		resp, err := provider.Complete(ctx, req)
 		if err == nil {
-			return resp, nil
+			return resp, reservation, attemptStart, nil
 		}
+		reservation.release()
 		lastErr = err
-		if attempt == attempts {
+		if attempt == attempts || !retryable(err) {
 			break
 		}
 
-		delay := r.retryBackoff[attempt-1]
 		select {
 		case <-ctx.Done():
-			return CompletionResponse{}, ctx.Err()
-		case <-time.After(delay):
+			return CompletionResponse{}, nil, time.Time{}, ctx.Err()
+		case <-time.After(backoff[attempt-1]):
 		}
 	}
 
-	return CompletionResponse{}, lastErr
-}
-
-func (r *Router) isCircuitOpen(providerName string) bool {
-	r.mu.RLock()
-	defer r.mu.RUnlock()
-	state := r.breakerStateByProvider[providerName]
-	return time.Now().Before(state.openUntil)
+	return CompletionResponse{}, nil, time.Time{}, lastErr
 }
 
 func (r *Router) isStructuredCircuitOpen(providerName string) bool {
@@ -382,38 +546,6 @@ func (r *Router) isStructuredCircuitOpen(providerName string) bool {
 	return time.Now().Before(state.openUntil)
 }
 
-func (r *Router) markFailure(providerName string, gen uint64) {
-	r.mu.Lock()
-	defer r.mu.Unlock()
-
-	if gen != r.gen {
-		return
-	}
-	state := r.breakerStateByProvider[providerName]
-	state.consecutiveFailures++
-	if state.consecutiveFailures >= r.breakerFailureThreshold {
-		state.openUntil = time.Now().Add(r.breakerCooldown)
-		state.consecutiveFailures = 0
-		slog.Warn("AI provider circuit opened",
-			"provider", providerName,
-			"cooldown_seconds", int(r.breakerCooldown.Seconds()),
-		)
-	}
-	r.breakerStateByProvider[providerName] = state
-}
-
-func (r *Router) markSuccess(providerName string, gen uint64) {
-	r.mu.Lock()
-	defer r.mu.Unlock()
-	if gen != r.gen {
-		return
-	}
-	state := r.breakerStateByProvider[providerName]
-	state.consecutiveFailures = 0
-	state.openUntil = time.Time{}
-	r.breakerStateByProvider[providerName] = state
-}
-
 func (r *Router) markStructuredFailure(providerName string, gen uint64) {
 	r.mu.Lock()
 	defer r.mu.Unlock()
@@ -483,7 +615,7 @@ func (r *Router) structuredProviderRequest(providerName string, req CompletionRe
 		return req, true
 	}
 
-	req.Model = r.structuredDefaultModelForProvider(providerName)
+	req.Model = r.structuredDefaultModelForProvider(providerName, req.Task)
 	return req, true
 }
 
@@ -501,6 +633,11 @@ func structuredProviderCapabilities(providerName string) (providerStructuredCapa
 			SystemMessages:   true,
 			ImageInputs:      true,
 		}, true
+	case "groq":
+		return providerStructuredCapabilities{
+			StructuredOutput: true,
+			SystemMessages:   true,
+		}, true
 	default:
 		return providerStructuredCapabilities{}, false
 	}
@@ -530,6 +667,8 @@ func hardcodedStructuredModelForProvider(providerName string) string {
 		return "gpt-5.4-mini"
 	case "deepseek":
 		return "deepseek-chat"
+	case "groq":
+		return "llama-3.1-8b-instant"
 	case "openrouter":
 		return "qwen/qwen3-max"
 	case "google":
@@ -541,14 +680,19 @@ func hardcodedStructuredModelForProvider(providerName string) string {
 	}
 }
 
-func (r *Router) defaultModelForProvider(providerName string) string {
+// defaultModelForTask prefers the provider's model for task, then its
+// default model.
+func (r *Router) defaultModelForTask(providerName string, task TaskType) string {
 	r.mu.RLock()
 	defer r.mu.RUnlock()
+	if model := r.taskModels[providerName][task]; model != "" {
+		return model
+	}
 	return strings.TrimSpace(r.defaultModels[providerName])
 }
 
-func (r *Router) structuredDefaultModelForProvider(providerName string) string {
-	if model := r.defaultModelForProvider(providerName); model != "" {
+func (r *Router) structuredDefaultModelForProvider(providerName string, task TaskType) string {
+	if model := r.defaultModelForTask(providerName, task); model != "" {
 		return model
 	}
 	return hardcodedStructuredModelForProvider(providerName)
@@ -0,0 +1,206 @@
+// Copyright 2026 the P&AI authors. All rights reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package ai
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"strings"
+	"time"
+)
+
+// StreamComplete streams a completion from the first provider, in fallback
+// order, that starts producing content. A provider that fails before its
+// first content chunk, whether opening the stream or partway through it, is
+// skipped for the next one, so StreamComplete returns an error only when
+// every provider failed. Once content has reached the caller the provider is
+// committed: a later failure arrives as a final chunk with Error set, because
+// the partial reply cannot be taken back.
+//
+// Streams are not retried on the same provider; the fallback chain stands in
+// for Complete's retry backoff so the first token is not delayed by it.
+// Streams run through the router's middleware; see Middleware.
+func (r *Router) StreamComplete(ctx context.Context, req CompletionRequest) (<-chan StreamChunk, error) {
+	req = r.withGenerationDefaults(req)
+	r.mu.RLock()
+	wrapped := len(r.middleware) > 0
+	r.mu.RUnlock()
+	if wrapped {
+		return r.streamWithMiddleware(ctx, req)
+	}
+	return r.streamComplete(ctx, req)
+}
+
+func (r *Router) streamComplete(ctx context.Context, req CompletionRequest) (<-chan StreamChunk, error) {
+	providers, order, gen := r.snapshotProviders()
+	if len(order) == 0 {
+		return nil, errNoProviders
+	}
+
+	plan, err := r.requestPlan(req.Task, req.RequestMetadata, req.Model, providers, order)
+	if err != nil {
+		return nil, err
+	}
+
+	logger := slog.With(req.logAttrs()...)
+	var failures providerFailures
+	for _, step := range plan {
+		name := step.provider
+		provider := providers[name]
+		if provider == nil {
+			continue
+		}
+		providerReq := req
+		providerReq.Model = step.modelFor(req.Model)
+		reservation, err := r.admitRequest(ctx, name, estimateRequestTokens(providerReq))
+		if errors.Is(err, errCircuitOpen) || errors.Is(err, errRateLimited) {
+			failures.add(name, err)
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		startedAt := time.Now()
+		stream, first, err := openStream(ctx, provider, providerReq)
+		if err != nil {
+			reservation.release()
+			if ctxErr := ctx.Err(); ctxErr != nil {
+				r.releaseProbe(name)
+				return nil, ctxErr
+			}
+			r.emitTrace(CompletionTrace{
+				Provider:    name,
+				Request:     providerReq,
+				Error:       err.Error(),
+				StartedAt:   startedAt,
+				CompletedAt: time.Now(),
+			})
+			r.markFailure(name, gen)
+			logger.Warn("AI provider stream failed, trying next",
+				"provider", name,
+				"error", err,
+			)
+			failures.add(name, err)
+			continue
+		}
+
+		out := make(chan StreamChunk)
+		go r.forwardStream(ctx, out, stream, first, name, gen, providerReq, startedAt, reservation)
+		return out, nil
+	}
+
+	return nil, failures.err()
+}
+
+// openStream starts provider's stream and reads up to its first content or
+// final chunk. Empty chunks before that carry nothing and are dropped.
+func openStream(ctx context.Context, provider Provider, req CompletionRequest) (<-chan StreamChunk, StreamChunk, error) {
+	stream, err := provider.StreamComplete(ctx, req)
+	if err != nil {
+		return nil, StreamChunk{}, err
+	}
+	for {
+		select {
+		case <-ctx.Done():
+			go drainStream(stream)
+			return nil, StreamChunk{}, ctx.Err()
+		case chunk, ok := <-stream:
+			switch {
+			case !ok:
+				return nil, StreamChunk{}, errors.New("stream ended before any content")
+			case chunk.Error != nil:
+				go drainStream(stream)
+				return nil, StreamChunk{}, chunk.Error
+			case chunk.Content != "" || chunk.Done:
+				return stream, chunk, nil
+			}
+		}
+	}
+}
+
+// forwardStream relays a committed provider's chunks to out, then records the
+// outcome against the provider's circuit breaker.
+func (r *Router) forwardStream(ctx context.Context, out chan<- StreamChunk, stream <-chan StreamChunk, first StreamChunk, name string, gen uint64, req CompletionRequest, startedAt time.Time, reservation *rateReservation) {
+	defer close(out)
+
+	var content strings.Builder
+	var final StreamChunk
+	var streamErr error
+	chunk, ok := first, true
+	for ok {
+		if chunk.Error != nil {
+			streamErr = chunk.Error
+		}
+		content.WriteString(chunk.Content)
+		if chunk.Done && chunk.Error == nil {
+			// Price the reply before the caller sees the final chunk, so the
+			// cost reaches budgets and the turn log as it does from Complete.
+			priced := CompletionResponse{Model: chunk.Model, InputTokens: chunk.InputTokens, OutputTokens: chunk.OutputTokens, CostUSD: chunk.CostUSD}
+			r.priceResponse(name, &priced)
+			chunk.CostUSD = priced.CostUSD
+		}
+		select {
+		case out <- chunk:
+		case <-ctx.Done():
+			go drainStream(stream)
+			r.releaseProbe(name)
+			return
+		}
+		if chunk.Done || chunk.Error != nil {
+			final = chunk
+			break
+		}
+		chunk, ok = <-stream
+	}
+	if !ok && streamErr == nil {
+		// The provider closed the stream without a final chunk; the caller
+		// still needs one to know the reply is over.
+		streamErr = errors.New("stream ended without a final chunk")
+		select {
+		case out <- StreamChunk{Done: true, Error: streamErr}:
+		case <-ctx.Done():
+		}
+	}
+	go drainStream(stream)
+
+	trace := CompletionTrace{
+		Provider:    name,
+		Request:     req,
+		Error:       completionErrorString(streamErr),
+		StartedAt:   startedAt,
+		CompletedAt: time.Now(),
+	}
+	if streamErr != nil {
+		// Content was already produced, so the reservation is settled, not
+		// returned; without usage it keeps the estimate.
+		reservation.settle(final.InputTokens + final.OutputTokens)
+		r.markFailure(name, gen)
+		slog.With(req.logAttrs()...).Warn("AI provider stream failed after content was sent",
+			"provider", name,
+			"error", streamErr,
+		)
+	} else {
+		r.markSuccess(name, gen)
+		r.recordLatency(name, gen, time.Since(startedAt))
+		reservation.settle(final.InputTokens + final.OutputTokens)
+		trace.Response = &CompletionResponse{
+			Content:      content.String(),
+			Model:        final.Model,
+			InputTokens:  final.InputTokens,
+			OutputTokens: final.OutputTokens,
+			CostUSD:      final.CostUSD,
+			Truncated:    final.Truncated,
+		}
+	}
+	r.emitTrace(trace)
+}
+
+// drainStream discards whatever a provider still sends so its goroutine can
+// finish after the router stops reading.
+func drainStream(stream <-chan StreamChunk) {
+	for range stream {
+	}
+}
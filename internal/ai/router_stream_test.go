@@ -0,0 +1,171 @@
+// Copyright 2026 the P&AI authors. All rights reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package ai_test
+
+import (
+	"context"
+	"errors"
+	"math"
+	"strings"
+	"testing"
+
+	"github.com/p-n-ai/pai-bot/internal/ai"
+)
+
+// scriptedStreamProvider replays fixed chunks from StreamComplete.
+type scriptedStreamProvider struct {
+	chunks []ai.StreamChunk
+	calls  int
+}
+
+func (p *scriptedStreamProvider) Complete(_ context.Context, _ ai.CompletionRequest) (ai.CompletionResponse, error) {
+	return ai.CompletionResponse{}, errors.New("not implemented")
+}
+
+func (p *scriptedStreamProvider) StreamComplete(_ context.Context, _ ai.CompletionRequest) (<-chan ai.StreamChunk, error) {
+	p.calls++
+	ch := make(chan ai.StreamChunk)
+	go func() {
+		defer close(ch)
+		for _, chunk := range p.chunks {
+			ch <- chunk
+		}
+	}()
+	return ch, nil
+}
+
+func (p *scriptedStreamProvider) Models() []ai.ModelInfo {
+	return nil
+}
+
+func (p *scriptedStreamProvider) HealthCheck(_ context.Context) error {
+	return nil
+}
+
+func collectStream(t *testing.T, stream <-chan ai.StreamChunk) (string, ai.StreamChunk) {
+	t.Helper()
+	var content strings.Builder
+	var last ai.StreamChunk
+	for chunk := range stream {
+		content.WriteString(chunk.Content)
+		last = chunk
+	}
+	return content.String(), last
+}
+
+func TestRouter_StreamCompleteRelaysChunks(t *testing.T) {
+	router := newTestRouter()
+	router.Register("anthropic", &scriptedStreamProvider{chunks: []ai.StreamChunk{
+		{Content: "Hel"},
+		{Content: "lo"},
+		{Done: true, Model: "claude", OutputTokens: 2},
+	}})
+
+	stream, err := router.StreamComplete(context.Background(), ai.CompletionRequest{})
+	if err != nil {
+		t.Fatalf("StreamComplete() error = %v", err)
+	}
+	content, last := collectStream(t, stream)
+	if content != "Hello" {
+		t.Fatalf("content = %q, want Hello", content)
+	}
+	if !last.Done || last.Model != "claude" || last.OutputTokens != 2 {
+		t.Fatalf("final chunk = %+v, want the provider's usage", last)
+	}
+}
+
+func TestRouter_StreamCompletePricesTheFinalChunk(t *testing.T) {
+	router := newTestRouter()
+	router.Register("anthropic", &scriptedStreamProvider{chunks: []ai.StreamChunk{
+		{Content: "Hello"},
+		{Done: true, Model: "claude", InputTokens: 10, OutputTokens: 6},
+	}})
+	router.SetModelPrices(map[string]ai.ModelPrice{"claude": {InputPerMTok: 1, OutputPerMTok: 2}})
+
+	stream, err := router.StreamComplete(context.Background(), ai.CompletionRequest{})
+	if err != nil {
+		t.Fatalf("StreamComplete() error = %v", err)
+	}
+	_, last := collectStream(t, stream)
+	if want := 22.0 / 1e6; math.Abs(last.CostUSD-want) > 1e-12 {
+		t.Fatalf("final chunk CostUSD = %v, want %v", last.CostUSD, want)
+	}
+}
+
+func TestRouter_StreamCompleteFallsBackBeforeContent(t *testing.T) {
+	router := newTestRouter()
+	opening := &ai.MockProvider{Err: errors.New("connection refused")}
+	midStream := &scriptedStreamProvider{chunks: []ai.StreamChunk{{Error: errors.New("overloaded")}}}
+	fallback := ai.NewMockProvider("Fallback response")
+	router.Register("openai", opening)
+	router.Register("anthropic", midStream)
+	router.Register("ollama", fallback)
+
+	var traced []string
+	router.SetTraceFunc(func(trace ai.CompletionTrace) {
+		traced = append(traced, trace.Provider+":"+trace.Error)
+	})
+
+	stream, err := router.StreamComplete(context.Background(), ai.CompletionRequest{})
+	if err != nil {
+		t.Fatalf("StreamComplete() error = %v", err)
+	}
+	content, last := collectStream(t, stream)
+	if content != "Fallback response" || last.Error != nil {
+		t.Fatalf("stream = %q (error %v), want the fallback's reply", content, last.Error)
+	}
+	if midStream.calls != 1 {
+		t.Fatalf("mid-stream provider calls = %d, want 1", midStream.calls)
+	}
+	want := []string{"openai:connection refused", "anthropic:overloaded", "ollama:"}
+	if strings.Join(traced, ",") != strings.Join(want, ",") {
+		t.Fatalf("traces = %v, want %v", traced, want)
+	}
+}
+
+func TestRouter_StreamCompleteKeepsProviderAfterContent(t *testing.T) {
+	router := newTestRouter()
+	committed := &scriptedStreamProvider{chunks: []ai.StreamChunk{
+		{Content: "Step 1: "},
+		{Error: errors.New("connection reset")},
+	}}
+	fallback := &scriptedStreamProvider{chunks: []ai.StreamChunk{{Content: "unused", Done: true}}}
+	router.Register("anthropic", committed)
+	router.Register("ollama", fallback)
+
+	stream, err := router.StreamComplete(context.Background(), ai.CompletionRequest{})
+	if err != nil {
+		t.Fatalf("StreamComplete() error = %v", err)
+	}
+	content, last := collectStream(t, stream)
+	if content != "Step 1: " {
+		t.Fatalf("content = %q, want only the committed provider's text", content)
+	}
+	if last.Error == nil {
+		t.Fatal("final chunk has no error, want the mid-stream failure")
+	}
+	if fallback.calls != 0 {
+		t.Fatalf("fallback calls = %d, want 0 once content was sent", fallback.calls)
+	}
+}
+
+func TestRouter_StreamCompleteAllProvidersFail(t *testing.T) {
+	router := newTestRouter()
+	router.Register("openai", &ai.MockProvider{Err: errors.New("fail 1")})
+	router.Register("ollama", &scriptedStreamProvider{})
+
+	_, err := router.StreamComplete(context.Background(), ai.CompletionRequest{})
+	if err == nil {
+		t.Fatal("StreamComplete() error = nil, want all providers failed")
+	}
+	if !strings.Contains(err.Error(), "fail 1") || !strings.Contains(err.Error(), "ollama") {
+		t.Fatalf("error = %v, want each provider's failure", err)
+	}
+}
+
+func TestRouter_StreamCompleteNoProviders(t *testing.T) {
+	if _, err := newTestRouter().StreamComplete(context.Background(), ai.CompletionRequest{}); err == nil {
+		t.Fatal("StreamComplete() error = nil, want no providers registered")
+	}
+}
@@ -5,12 +5,17 @@ package ai_test
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
 	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"slices"
 	"testing"
 	"time"
 
 	"github.com/p-n-ai/pai-bot/internal/ai"
+	"github.com/p-n-ai/pai-bot/internal/apperr"
 )
 
 func TestRouter_SingleProvider(t *testing.T) {
@@ -51,6 +56,111 @@ func TestRouter_Fallback(t *testing.T) {
 	}
 }
 
+func TestRouter_FallbackCarriesPhotoToAnthropic(t *testing.T) {
+	var imageSource map[string]any
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var body struct {
+			Messages []struct {
+				Content []struct {
+					Type   string         `json:"type"`
+					Source map[string]any `json:"source"`
+				} `json:"content"`
+			} `json:"messages"`
+		}
+		_ = json.NewDecoder(r.Body).Decode(&body)
+		for _, message := range body.Messages {
+			for _, block := range message.Content {
+				if block.Type == "image" {
+					imageSource = block.Source
+				}
+			}
+		}
+		_ = json.NewEncoder(w).Encode(map[string]any{
+			"content": []map[string]string{{"type": "text", "text": "That is a right triangle."}},
+			"model":   "claude-sonnet-4-6",
+			"usage":   map[string]int{"input_tokens": 1, "output_tokens": 1},
+		})
+	}))
+	defer server.Close()
+
+	anthropic, err := ai.NewAnthropicProvider("test-key", ai.WithAnthropicBaseURL(server.URL))
+	if err != nil {
+		t.Fatalf("NewAnthropicProvider() error = %v", err)
+	}
+	router := newTestRouter()
+	router.Register("openai", &ai.MockProvider{Err: errors.New("rate limited")})
+	router.Register("anthropic", anthropic)
+
+	resp, err := router.Complete(context.Background(), ai.CompletionRequest{
+		Messages: []ai.Message{{
+			Role:      "user",
+			Content:   "Is my working right?",
+			ImageURLs: []string{"data:image/jpeg;base64,AAEC"},
+		}},
+	})
+	if err != nil {
+		t.Fatalf("Complete() error = %v", err)
+	}
+	if resp.Content != "That is a right triangle." {
+		t.Errorf("Content = %q, want the Anthropic answer", resp.Content)
+	}
+	if imageSource["type"] != "base64" || imageSource["media_type"] != "image/jpeg" || imageSource["data"] != "AAEC" {
+		t.Fatalf("image source = %#v, want the uploaded photo as a base64 block", imageSource)
+	}
+}
+
+func TestRouter_StreamsPhotoToGeminiAsInlineData(t *testing.T) {
+	var inlineData map[string]any
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var body struct {
+			Contents []struct {
+				Parts []struct {
+					InlineData map[string]any `json:"inlineData"`
+				} `json:"parts"`
+			} `json:"contents"`
+		}
+		_ = json.NewDecoder(r.Body).Decode(&body)
+		for _, content := range body.Contents {
+			for _, part := range content.Parts {
+				if part.InlineData != nil {
+					inlineData = part.InlineData
+				}
+			}
+		}
+		_ = json.NewEncoder(w).Encode(map[string]any{
+			"candidates": []map[string]any{{
+				"content": map[string]any{"parts": []map[string]string{{"text": "Your working is right."}}},
+			}},
+		})
+	}))
+	defer server.Close()
+
+	router := newTestRouter()
+	router.Register("google", ai.NewGoogleProvider("test-key", ai.WithGoogleBaseURL(server.URL)))
+	router.Register("openai", ai.NewMockProvider("wrong provider"))
+
+	chunks, err := router.StreamComplete(context.Background(), ai.CompletionRequest{
+		Messages: []ai.Message{{
+			Role:      "user",
+			Content:   "Is my working right?",
+			ImageURLs: []string{"data:image/png;base64,AAEC"},
+		}},
+	})
+	if err != nil {
+		t.Fatalf("StreamComplete() error = %v", err)
+	}
+	var reply string
+	for chunk := range chunks {
+		reply += chunk.Content
+	}
+	if reply != "Your working is right." {
+		t.Errorf("reply = %q, want the Gemini answer", reply)
+	}
+	if inlineData["mimeType"] != "image/png" || inlineData["data"] != "AAEC" {
+		t.Fatalf("inlineData = %#v, want the uploaded photo", inlineData)
+	}
+}
+
 func TestRouter_AllProvidersFail(t *testing.T) {
 	router := newTestRouter()
 
@@ -133,6 +243,36 @@ func TestRouter_UsesConfiguredDefaultModelForProvider(t *testing.T) {
 	}
 }
 
+func TestRouter_UsesTaskModelOverDefaultModel(t *testing.T) {
+	router := newTestRouter()
+	mock := ai.NewMockProvider("Hello!")
+	router.ReplaceProviders([]ai.ProviderRegistration{{
+		Name:         "openai",
+		Provider:     mock,
+		DefaultModel: "gpt-5.4",
+		TaskModels:   map[ai.TaskType]string{ai.TaskNudge: "gpt-5.4-mini"},
+	}})
+
+	for _, tc := range []struct {
+		task ai.TaskType
+		want string
+	}{
+		{ai.TaskNudge, "gpt-5.4-mini"},
+		{ai.TaskTeaching, "gpt-5.4"},
+	} {
+		_, err := router.Complete(context.Background(), ai.CompletionRequest{
+			Messages: []ai.Message{{Role: "user", Content: "hi"}},
+			Task:     tc.task,
+		})
+		if err != nil {
+			t.Fatalf("Complete(%s) error = %v", tc.task, err)
+		}
+		if mock.LastRequest.Model != tc.want {
+			t.Fatalf("%s model = %q, want %q", tc.task, mock.LastRequest.Model, tc.want)
+		}
+	}
+}
+
 func TestRouter_TraceFuncCapturesProviderRequest(t *testing.T) {
 	router := newTestRouter()
 	mock := ai.NewMockProvider("Hello!")
@@ -193,6 +333,41 @@ func TestRouter_RetryThenSuccess(t *testing.T) {
 	}
 }
 
+func TestRouter_DoesNotRetryPermanentFailures(t *testing.T) {
+	router := newTestRouter()
+	primary := ai.NewMockProvider("never").FailOn(1, apperr.HTTPStatus(http.StatusBadRequest, errors.New("bad request")))
+	router.Register("primary", primary)
+	router.Register("secondary", ai.NewMockProvider("fallback"))
+
+	resp, err := router.Complete(context.Background(), ai.CompletionRequest{
+		Messages: []ai.Message{{Role: "user", Content: "hi"}},
+	})
+	if err != nil {
+		t.Fatalf("Complete() error = %v", err)
+	}
+	if resp.Content != "fallback" || primary.Calls() != 1 {
+		t.Fatalf("content = %q, primary calls = %d, want fallback after one call", resp.Content, primary.Calls())
+	}
+}
+
+func TestRouter_TransportRetryTurnsOffRouterRetries(t *testing.T) {
+	router := newTestRouter()
+	router.SetTransportRetry(true)
+	flaky := &countingProvider{failuresBeforeSuccess: 1, response: "ok"}
+	router.Register("flaky", flaky)
+	router.Register("secondary", ai.NewMockProvider("fallback"))
+
+	resp, err := router.Complete(context.Background(), ai.CompletionRequest{
+		Messages: []ai.Message{{Role: "user", Content: "hi"}},
+	})
+	if err != nil {
+		t.Fatalf("Complete() error = %v", err)
+	}
+	if resp.Content != "fallback" || flaky.calls != 1 {
+		t.Fatalf("content = %q, flaky calls = %d, want fallback after one call", resp.Content, flaky.calls)
+	}
+}
+
 func TestRouter_CircuitBreakerOpensAndFallsBack(t *testing.T) {
 	router := ai.NewRouterWithConfig(ai.RouterConfig{
 		RetryBackoff:            []time.Duration{1 * time.Millisecond},
@@ -363,3 +538,200 @@ func (p *blockingFailProvider) Models() []ai.ModelInfo {
 func (p *blockingFailProvider) HealthCheck(_ context.Context) error {
 	return nil
 }
+
+func TestRouter_SelectModelSkipsOpenCircuit(t *testing.T) {
+	router := ai.NewRouterWithConfig(ai.RouterConfig{
+		RetryBackoff:            []time.Duration{time.Millisecond},
+		BreakerFailureThreshold: 1,
+		BreakerCooldown:         time.Hour,
+	})
+	primary := ai.NewMockProvider("")
+	primary.Err = errors.New("down")
+	router.ReplaceProviders([]ai.ProviderRegistration{
+		{Name: "primary", Provider: primary, DefaultModel: "primary-model"},
+		{
+			Name:         "backup",
+			Provider:     ai.NewMockProvider("ok"),
+			DefaultModel: "backup-model",
+			TaskModels:   map[ai.TaskType]string{ai.TaskTeaching: "backup-teaching"},
+		},
+	})
+
+	if provider, model, ok := router.SelectModel(ai.TaskTeaching, ""); !ok || provider != "primary" || model != "primary-model" {
+		t.Fatalf("SelectModel() = %q, %q, %v; want primary, primary-model, true", provider, model, ok)
+	}
+	_, _ = router.Complete(context.Background(), ai.CompletionRequest{Task: ai.TaskTeaching})
+
+	if provider, model, ok := router.SelectModel(ai.TaskTeaching, ""); !ok || provider != "backup" || model != "backup-teaching" {
+		t.Fatalf("SelectModel() = %q, %q, %v; want backup, backup-teaching, true", provider, model, ok)
+	}
+	if _, model, _ := router.SelectModel(ai.TaskTeaching, "gpt-4o"); model != "gpt-4o" {
+		t.Fatalf("SelectModel() model = %q, want explicit model kept", model)
+	}
+}
+
+func TestRouter_TaskRoutesPickProviderAndModelPerTask(t *testing.T) {
+	router := newTestRouter()
+	strong := ai.NewMockProvider("strong")
+	cheap := ai.NewMockProvider("cheap")
+	router.ReplaceProviders([]ai.ProviderRegistration{
+		{Name: "anthropic", Provider: strong, DefaultModel: "claude-sonnet"},
+		{Name: "deepseek", Provider: cheap, DefaultModel: "deepseek-reasoner"},
+	})
+	router.SetTaskRoutes(map[ai.TaskType][]ai.TaskRoute{
+		ai.TaskGrading: {{Provider: "deepseek", Model: "deepseek-chat"}},
+	})
+
+	resp, err := router.Complete(context.Background(), ai.CompletionRequest{Task: ai.TaskGrading})
+	if err != nil {
+		t.Fatalf("Complete(grading) error = %v", err)
+	}
+	if resp.Content != "cheap" || cheap.LastRequest.Model != "deepseek-chat" {
+		t.Fatalf("grading = %q on model %q, want the routed deepseek-chat", resp.Content, cheap.LastRequest.Model)
+	}
+
+	resp, err = router.Complete(context.Background(), ai.CompletionRequest{Task: ai.TaskTeaching})
+	if err != nil {
+		t.Fatalf("Complete(teaching) error = %v", err)
+	}
+	if resp.Content != "strong" || strong.LastRequest.Model != "claude-sonnet" {
+		t.Fatalf("teaching = %q on model %q, want the default chain", resp.Content, strong.LastRequest.Model)
+	}
+
+	if provider, model, ok := router.SelectModel(ai.TaskGrading, ""); !ok || provider != "deepseek" || model != "deepseek-chat" {
+		t.Fatalf("SelectModel(grading) = %q %q %v, want deepseek deepseek-chat", provider, model, ok)
+	}
+}
+
+func TestRouter_TaskRoutesFallBackThroughRoutesThenChain(t *testing.T) {
+	router := newTestRouter()
+	backup := ai.NewMockProvider("backup")
+	routed := &ai.MockProvider{Err: errors.New("overloaded")}
+	router.ReplaceProviders([]ai.ProviderRegistration{
+		{Name: "openai", Provider: routed, DefaultModel: "gpt-5.4"},
+		{Name: "ollama", Provider: backup, DefaultModel: "llama3"},
+	})
+	router.SetTaskRoutes(map[ai.TaskType][]ai.TaskRoute{
+		ai.TaskNudge: {
+			{Provider: "google"}, // not registered: skipped
+			{Provider: "openai", Model: "gpt-5.4-mini"},
+			{Provider: "openai", Model: "gpt-5.4-nano"},
+		},
+	})
+
+	var traced []string
+	router.SetTraceFunc(func(trace ai.CompletionTrace) {
+		traced = append(traced, trace.Provider+":"+trace.Request.Model)
+	})
+
+	resp, err := router.Complete(context.Background(), ai.CompletionRequest{Task: ai.TaskNudge})
+	if err != nil {
+		t.Fatalf("Complete() error = %v", err)
+	}
+	if resp.Content != "backup" {
+		t.Fatalf("Content = %q, want the remaining chain after the routes fail", resp.Content)
+	}
+	want := []string{"openai:gpt-5.4-mini", "openai:gpt-5.4-nano", "ollama:llama3"}
+	if !slices.Equal(traced, want) {
+		t.Fatalf("attempts = %v, want %v", traced, want)
+	}
+}
+
+func TestRouter_TierTaskRoutesPinTheTierToItsModels(t *testing.T) {
+	router := newTestRouter()
+	strong := ai.NewMockProvider("strong")
+	cheap := &ai.MockProvider{Err: errors.New("overloaded")}
+	router.ReplaceProviders([]ai.ProviderRegistration{
+		{Name: "anthropic", Provider: strong, DefaultModel: "claude-sonnet"},
+		{Name: "deepseek", Provider: cheap, DefaultModel: "deepseek-reasoner"},
+	})
+	router.SetTierTaskRoutes("free", map[ai.TaskType][]ai.TaskRoute{
+		ai.TaskTeaching: {{Provider: "deepseek", Model: "deepseek-chat"}},
+	})
+
+	var traced []string
+	router.SetTraceFunc(func(trace ai.CompletionTrace) {
+		traced = append(traced, trace.Provider+":"+trace.Request.Model)
+	})
+
+	free := ai.CompletionRequest{RequestMetadata: ai.RequestMetadata{Tier: "free"}, Task: ai.TaskTeaching, Model: "gpt-4o"}
+	if _, err := router.Complete(context.Background(), free); err == nil {
+		t.Fatal("Complete(free) error = nil, want failure without falling back past the tier routes")
+	}
+	if want := []string{"deepseek:deepseek-chat"}; !slices.Equal(traced, want) {
+		t.Fatalf("free attempts = %v, want %v", traced, want)
+	}
+
+	traced = nil
+	premium := ai.CompletionRequest{RequestMetadata: ai.RequestMetadata{Tier: "premium"}, Task: ai.TaskTeaching}
+	resp, err := router.Complete(context.Background(), premium)
+	if err != nil {
+		t.Fatalf("Complete(premium) error = %v", err)
+	}
+	if resp.Content != "strong" || strong.LastRequest.Model != "claude-sonnet" {
+		t.Fatalf("premium = %q on model %q, want the default chain", resp.Content, strong.LastRequest.Model)
+	}
+	if got := router.TierTaskRoutes("free", ai.TaskGrading); got != nil {
+		t.Fatalf("TierTaskRoutes(free, grading) = %v, want none", got)
+	}
+}
+
+func TestRouter_CircuitBreakerHalfOpenProbe(t *testing.T) {
+	router := ai.NewRouterWithConfig(ai.RouterConfig{
+		RetryBackoff:            []time.Duration{1 * time.Millisecond},
+		BreakerFailureThreshold: 1,
+		BreakerCooldown:         20 * time.Millisecond,
+	})
+	var transitions []string
+	router.SetBreakerObserver(func(transition ai.BreakerTransition) {
+		transitions = append(transitions, fmt.Sprintf("%s:%s->%s", transition.Provider, transition.From, transition.To))
+	})
+
+	// Each request makes two attempts, so the first two requests to primary fail.
+	primary := &countingProvider{failuresBeforeSuccess: 4, response: "primary"}
+	router.Register("primary", primary)
+	router.Register("secondary", &countingProvider{response: "fallback"})
+	complete := func() string {
+		t.Helper()
+		resp, err := router.Complete(context.Background(), ai.CompletionRequest{
+			Messages: []ai.Message{{Role: "user", Content: "hello"}},
+		})
+		if err != nil {
+			t.Fatalf("Complete() error = %v", err)
+		}
+		return resp.Content
+	}
+
+	complete()
+	time.Sleep(30 * time.Millisecond)
+	// The probe fails, so the circuit reopens without waiting for the threshold.
+	if got := complete(); got != "fallback" {
+		t.Fatalf("probe response = %q, want fallback", got)
+	}
+	breakers := router.Breakers()
+	if breakers[0].State != ai.BreakerOpen || breakers[0].Opened != 2 || breakers[0].HalfOpened != 1 {
+		t.Fatalf("primary breaker = %+v, want open after two trips and one probe", breakers[0])
+	}
+	if breakers[1].State != ai.BreakerClosed {
+		t.Fatalf("secondary breaker state = %q, want closed", breakers[1].State)
+	}
+
+	time.Sleep(30 * time.Millisecond)
+	if got := complete(); got != "primary" {
+		t.Fatalf("recovered response = %q, want primary", got)
+	}
+	if state := router.Breakers()[0].State; state != ai.BreakerClosed {
+		t.Fatalf("primary state after successful probe = %q, want closed", state)
+	}
+
+	want := []string{
+		"primary:closed->open",
+		"primary:open->half_open",
+		"primary:half_open->open",
+		"primary:open->half_open",
+		"primary:half_open->closed",
+	}
+	if !slices.Equal(transitions, want) {
+		t.Fatalf("transitions = %v, want %v", transitions, want)
+	}
+}
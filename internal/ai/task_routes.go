@@ -0,0 +1,160 @@
+// Copyright 2026 the P&AI authors. All rights reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package ai
+
+import "strings"
+
+// TaskRoute names a provider, and optionally one of its models, that a task
+// should try. An empty Model uses the provider's model for the task.
+type TaskRoute struct {
+	Provider string
+	Model    string
+}
+
+// routeStep is one provider attempt in a request's fallback plan, with the
+// model already resolved. A pinned step comes from a tier's routes and
+// overrides the model named in the request.
+type routeStep struct {
+	provider string
+	model    string
+	pinned   bool
+}
+
+// modelFor returns the model to send when the request asked for requested.
+func (s routeStep) modelFor(requested string) string {
+	if requested == "" || s.pinned {
+		return s.model
+	}
+	return requested
+}
+
+// SetTaskRoutes replaces the per-task routing table. A task with routes tries
+// them in order before the rest of the fallback chain, so grading can start on
+// a cheap model and teaching on a strong one. Routes naming a provider that is
+// not registered are skipped at request time.
+func (r *Router) SetTaskRoutes(routes map[TaskType][]TaskRoute) {
+	table := cleanTaskRoutes(routes)
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.taskRoutes = table
+}
+
+// SetTierTaskRoutes replaces the routing table for requests whose metadata
+// names tier. Unlike SetTaskRoutes, a tier's routes for a task are the whole
+// plan: there is no fallback to the rest of the chain, and the request's own
+// model is ignored, so a subscription tier cannot reach models outside its
+// routes. Tasks without tier routes use the usual plan. Nil or empty routes
+// clear the tier.
+func (r *Router) SetTierTaskRoutes(tier string, routes map[TaskType][]TaskRoute) {
+	tier = strings.TrimSpace(tier)
+	if tier == "" {
+		return
+	}
+	table := cleanTaskRoutes(routes)
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if len(table) == 0 {
+		delete(r.tierRoutes, tier)
+		return
+	}
+	if r.tierRoutes == nil {
+		r.tierRoutes = make(map[string]map[TaskType][]TaskRoute)
+	}
+	r.tierRoutes[tier] = table
+}
+
+func cleanTaskRoutes(routes map[TaskType][]TaskRoute) map[TaskType][]TaskRoute {
+	table := make(map[TaskType][]TaskRoute, len(routes))
+	for task, chain := range routes {
+		for _, route := range chain {
+			route.Provider = strings.TrimSpace(route.Provider)
+			route.Model = strings.TrimSpace(route.Model)
+			if route.Provider == "" {
+				continue
+			}
+			table[task] = append(table[task], route)
+		}
+	}
+	return table
+}
+
+// TaskRoutes returns the routes configured for task.
+func (r *Router) TaskRoutes(task TaskType) []TaskRoute {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return append([]TaskRoute(nil), r.taskRoutes[task]...)
+}
+
+// TierTaskRoutes returns the routes tier is limited to for task, or nil when
+// the tier uses the usual plan.
+func (r *Router) TierTaskRoutes(tier string, task TaskType) []TaskRoute {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return append([]TaskRoute(nil), r.tierRoutes[tier][task]...)
+}
+
+// taskPlan orders the provider attempts for task: its routes first, then
+// every other provider in order with its usual model. A provider may appear
+// in the routes more than once with different models, but is not retried once
+// the routes are exhausted. When tier has routes for task, the plan is those
+// routes alone, pinned to their models. Providers that failed their last
+// health check are left out; see skipUnhealthy. Teaching tries providers
+// over the latency budget last; see preferFast.
+func (r *Router) taskPlan(task TaskType, tier string, providers map[string]Provider, order []string) []routeStep {
+	return r.preferFast(task, r.skipUnhealthy(r.routePlan(task, tier, providers, order)))
+}
+
+func (r *Router) routePlan(task TaskType, tier string, providers map[string]Provider, order []string) []routeStep {
+	if tierRoutes := r.TierTaskRoutes(tier, task); len(tierRoutes) > 0 {
+		return r.pinnedPlan(task, tierRoutes, providers)
+	}
+	routes := r.TaskRoutes(task)
+	steps := make([]routeStep, 0, len(routes)+len(order))
+	routed := make(map[string]bool, len(routes))
+	seen := make(map[routeStep]bool, len(routes))
+	for _, route := range routes {
+		if providers[route.Provider] == nil {
+			continue
+		}
+		step := routeStep{provider: route.Provider, model: route.Model}
+		if step.model == "" {
+			step.model = r.defaultModelForTask(step.provider, task)
+		}
+		routed[step.provider] = true
+		if seen[step] {
+			continue
+		}
+		seen[step] = true
+		steps = append(steps, step)
+	}
+	for _, name := range order {
+		if routed[name] {
+			continue
+		}
+		steps = append(steps, routeStep{provider: name, model: r.defaultModelForTask(name, task)})
+	}
+	return steps
+}
+
+func (r *Router) pinnedPlan(task TaskType, routes []TaskRoute, providers map[string]Provider) []routeStep {
+	steps := make([]routeStep, 0, len(routes))
+	seen := make(map[routeStep]bool, len(routes))
+	for _, route := range routes {
+		if providers[route.Provider] == nil {
+			continue
+		}
+		step := routeStep{provider: route.Provider, model: route.Model, pinned: true}
+		if step.model == "" {
+			step.model = r.defaultModelForTask(step.provider, task)
+		}
+		if seen[step] {
+			continue
+		}
+		seen[step] = true
+		steps = append(steps, step)
+	}
+	return steps
+}
@@ -0,0 +1,251 @@
+// Copyright 2026 the P&AI authors. All rights reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package algebra
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
+	"unicode"
+)
+
+// maxExponent bounds x^n so a typo like x^999 cannot blow up expansion.
+const maxExponent = 12
+
+// ErrUnsupported marks input outside what the checker can reason about,
+// such as a variable in a denominator, roots, or inequalities.
+var ErrUnsupported = errors.New("unsupported expression")
+
+type tokenKind int
+
+const (
+	tokenNumber tokenKind = iota
+	tokenVariable
+	tokenOperator
+	tokenOpen
+	tokenClose
+)
+
+type token struct {
+	kind  tokenKind
+	text  string
+	value *big.Rat
+}
+
+// normalizeMath maps the symbols students type or paste to ASCII.
+var normalizeMath = strings.NewReplacer(
+	"−", "-", "–", "-", "×", "*", "·", "*", "÷", "/",
+	"²", "^2", "³", "^3", "[", "(", "]", ")", "{", "(", "}", ")",
+)
+
+func tokenize(input string) ([]token, error) {
+	input = normalizeMath.Replace(input)
+	runes := []rune(input)
+	var tokens []token
+	for i := 0; i < len(runes); {
+		r := runes[i]
+		switch {
+		case unicode.IsSpace(r):
+			i++
+		case unicode.IsDigit(r) || r == '.':
+			start := i
+			for i < len(runes) && (unicode.IsDigit(runes[i]) || runes[i] == '.') {
+				i++
+			}
+			value, ok := new(big.Rat).SetString(string(runes[start:i]))
+			if !ok {
+				return nil, fmt.Errorf("invalid number %q", string(runes[start:i]))
+			}
+			tokens = append(tokens, token{kind: tokenNumber, text: string(runes[start:i]), value: value})
+		case r < unicode.MaxASCII && unicode.IsLetter(r):
+			// Adjacent letters are implicit products, so "xy" is x*y. Longer
+			// runs are words or functions such as sqrt, which are out of scope.
+			start := i
+			for i < len(runes) && runes[i] < unicode.MaxASCII && unicode.IsLetter(runes[i]) {
+				i++
+			}
+			if i-start > 2 {
+				return nil, fmt.Errorf("%w: %q", ErrUnsupported, string(runes[start:i]))
+			}
+			for _, letter := range runes[start:i] {
+				tokens = append(tokens, token{kind: tokenVariable, text: string(letter)})
+			}
+		case strings.ContainsRune("+-*/^", r):
+			tokens = append(tokens, token{kind: tokenOperator, text: string(r)})
+			i++
+		case r == '(':
+			tokens = append(tokens, token{kind: tokenOpen, text: "("})
+			i++
+		case r == ')':
+			tokens = append(tokens, token{kind: tokenClose, text: ")"})
+			i++
+		default:
+			return nil, fmt.Errorf("%w: unexpected %q", ErrUnsupported, string(r))
+		}
+	}
+	return tokens, nil
+}
+
+// ParseExpression parses a polynomial expression such as "3(x - 2)^2 + x/2".
+// Products may be implicit ("2x", "(x+1)(x-1)"); exponents must be whole
+// numbers and divisors must be nonzero constants.
+func ParseExpression(input string) (Polynomial, error) {
+	tokens, err := tokenize(input)
+	if err != nil {
+		return Polynomial{}, err
+	}
+	if len(tokens) == 0 {
+		return Polynomial{}, errors.New("empty expression")
+	}
+	p := parser{tokens: tokens}
+	expr, err := p.expression()
+	if err != nil {
+		return Polynomial{}, err
+	}
+	if p.pos < len(p.tokens) {
+		return Polynomial{}, fmt.Errorf("unexpected %q", p.tokens[p.pos].text)
+	}
+	return expr, nil
+}
+
+type parser struct {
+	tokens []token
+	pos    int
+}
+
+func (p *parser) peek() (token, bool) {
+	if p.pos >= len(p.tokens) {
+		return token{}, false
+	}
+	return p.tokens[p.pos], true
+}
+
+func (p *parser) peekOperator(ops string) (string, bool) {
+	tok, ok := p.peek()
+	if !ok || tok.kind != tokenOperator || !strings.Contains(ops, tok.text) {
+		return "", false
+	}
+	return tok.text, true
+}
+
+func (p *parser) expression() (Polynomial, error) {
+	left, err := p.term()
+	if err != nil {
+		return Polynomial{}, err
+	}
+	for {
+		op, ok := p.peekOperator("+-")
+		if !ok {
+			return left, nil
+		}
+		p.pos++
+		right, err := p.term()
+		if err != nil {
+			return Polynomial{}, err
+		}
+		if op == "+" {
+			left = left.Add(right)
+		} else {
+			left = left.Sub(right)
+		}
+	}
+}
+
+func (p *parser) term() (Polynomial, error) {
+	left, err := p.unary()
+	if err != nil {
+		return Polynomial{}, err
+	}
+	for {
+		op, explicit := p.peekOperator("*/")
+		if explicit {
+			p.pos++
+		} else if tok, ok := p.peek(); !ok || tok.kind == tokenOperator || tok.kind == tokenClose {
+			return left, nil
+		}
+		var right Polynomial
+		if explicit {
+			right, err = p.unary()
+		} else {
+			right, err = p.power()
+		}
+		if err != nil {
+			return Polynomial{}, err
+		}
+		if op != "/" {
+			left = left.Mul(right)
+			continue
+		}
+		divisor, ok := right.constantValue()
+		if !ok {
+			return Polynomial{}, fmt.Errorf("%w: division by an expression with a variable", ErrUnsupported)
+		}
+		if divisor.Sign() == 0 {
+			return Polynomial{}, errors.New("division by zero")
+		}
+		left = left.scale(new(big.Rat).Inv(divisor))
+	}
+}
+
+func (p *parser) unary() (Polynomial, error) {
+	if op, ok := p.peekOperator("+-"); ok {
+		p.pos++
+		operand, err := p.unary()
+		if err != nil {
+			return Polynomial{}, err
+		}
+		if op == "-" {
+			return operand.scale(big.NewRat(-1, 1)), nil
+		}
+		return operand, nil
+	}
+	return p.power()
+}
+
+func (p *parser) power() (Polynomial, error) {
+	base, err := p.atom()
+	if err != nil {
+		return Polynomial{}, err
+	}
+	if _, ok := p.peekOperator("^"); !ok {
+		return base, nil
+	}
+	p.pos++
+	tok, ok := p.peek()
+	if !ok || tok.kind != tokenNumber || !tok.value.IsInt() {
+		return Polynomial{}, fmt.Errorf("%w: exponents must be whole numbers", ErrUnsupported)
+	}
+	p.pos++
+	exp := tok.value.Num()
+	if !exp.IsInt64() || exp.Int64() > maxExponent {
+		return Polynomial{}, fmt.Errorf("%w: exponent %s is too large", ErrUnsupported, tok.text)
+	}
+	return base.pow(int(exp.Int64())), nil
+}
+
+func (p *parser) atom() (Polynomial, error) {
+	tok, ok := p.peek()
+	if !ok {
+		return Polynomial{}, errors.New("expression ends early")
+	}
+	p.pos++
+	switch tok.kind {
+	case tokenNumber:
+		return constant(tok.value), nil
+	case tokenVariable:
+		return variable(tok.text), nil
+	case tokenOpen:
+		inner, err := p.expression()
+		if err != nil {
+			return Polynomial{}, err
+		}
+		if next, ok := p.peek(); !ok || next.kind != tokenClose {
+			return Polynomial{}, errors.New("missing closing bracket")
+		}
+		p.pos++
+		return inner, nil
+	}
+	return Polynomial{}, fmt.Errorf("unexpected %q", tok.text)
+}
@@ -0,0 +1,259 @@
+// Copyright 2026 the P&AI authors. All rights reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+// Package algebra is a small exact computer algebra system for checking
+// school algebra working: polynomial expressions with rational
+// coefficients, and the linear and quadratic equations built from them.
+package algebra
+
+import (
+	"math"
+	"math/big"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// monomial is a canonical product of variables, e.g. "x^2*y". The empty
+// monomial is the constant term.
+type monomial string
+
+// Polynomial is a sum of monomials with exact rational coefficients.
+// Terms with a zero coefficient are never stored.
+type Polynomial struct {
+	terms map[monomial]*big.Rat
+}
+
+func constant(r *big.Rat) Polynomial {
+	p := Polynomial{terms: map[monomial]*big.Rat{}}
+	if r.Sign() != 0 {
+		p.terms[""] = new(big.Rat).Set(r)
+	}
+	return p
+}
+
+func variable(name string) Polynomial {
+	return Polynomial{terms: map[monomial]*big.Rat{monomial(name): big.NewRat(1, 1)}}
+}
+
+// IsZero reports whether p is identically zero.
+func (p Polynomial) IsZero() bool {
+	return len(p.terms) == 0
+}
+
+// constantValue returns p's value when p has no variables.
+func (p Polynomial) constantValue() (*big.Rat, bool) {
+	switch len(p.terms) {
+	case 0:
+		return new(big.Rat), true
+	case 1:
+		c, ok := p.terms[""]
+		return c, ok
+	}
+	return nil, false
+}
+
+func (p Polynomial) add(q Polynomial, sign int) Polynomial {
+	sum := Polynomial{terms: make(map[monomial]*big.Rat, len(p.terms)+len(q.terms))}
+	for m, c := range p.terms {
+		sum.terms[m] = new(big.Rat).Set(c)
+	}
+	for m, c := range q.terms {
+		term := new(big.Rat).Set(c)
+		if sign < 0 {
+			term.Neg(term)
+		}
+		if existing, ok := sum.terms[m]; ok {
+			term.Add(term, existing)
+		}
+		if term.Sign() == 0 {
+			delete(sum.terms, m)
+			continue
+		}
+		sum.terms[m] = term
+	}
+	return sum
+}
+
+// Add returns p + q.
+func (p Polynomial) Add(q Polynomial) Polynomial { return p.add(q, 1) }
+
+// Sub returns p - q.
+func (p Polynomial) Sub(q Polynomial) Polynomial { return p.add(q, -1) }
+
+// Mul returns p * q.
+func (p Polynomial) Mul(q Polynomial) Polynomial {
+	product := Polynomial{terms: map[monomial]*big.Rat{}}
+	for mp, cp := range p.terms {
+		for mq, cq := range q.terms {
+			m := multiplyMonomials(mp, mq)
+			term := new(big.Rat).Mul(cp, cq)
+			if existing, ok := product.terms[m]; ok {
+				term.Add(term, existing)
+			}
+			if term.Sign() == 0 {
+				delete(product.terms, m)
+				continue
+			}
+			product.terms[m] = term
+		}
+	}
+	return product
+}
+
+func (p Polynomial) scale(r *big.Rat) Polynomial {
+	return p.Mul(constant(r))
+}
+
+func (p Polynomial) pow(n int) Polynomial {
+	result := constant(big.NewRat(1, 1))
+	for range n {
+		result = result.Mul(p)
+	}
+	return result
+}
+
+// Equal reports whether p and q are the same polynomial.
+func (p Polynomial) Equal(q Polynomial) bool {
+	return p.Sub(q).IsZero()
+}
+
+// proportional reports whether q = k*p for some nonzero constant k. Two
+// equations p = 0 and q = 0 with proportional sides have the same solutions.
+func (p Polynomial) proportional(q Polynomial) bool {
+	if p.IsZero() || q.IsZero() {
+		return p.IsZero() && q.IsZero()
+	}
+	for m, c := range p.terms {
+		other, ok := q.terms[m]
+		if !ok {
+			return false
+		}
+		return p.scale(new(big.Rat).Quo(other, c)).Equal(q)
+	}
+	return false
+}
+
+// Degree returns the highest total degree of any term.
+func (p Polynomial) Degree() int {
+	degree := 0
+	for m := range p.terms {
+		degree = max(degree, monomialDegree(m))
+	}
+	return degree
+}
+
+// String renders p with terms in a stable order, e.g. "x^2 - 5*x + 6".
+func (p Polynomial) String() string {
+	if p.IsZero() {
+		return "0"
+	}
+	monomials := make([]monomial, 0, len(p.terms))
+	for m := range p.terms {
+		monomials = append(monomials, m)
+	}
+	slices.SortFunc(monomials, func(a, b monomial) int {
+		if da, db := monomialDegree(a), monomialDegree(b); da != db {
+			return db - da
+		}
+		return strings.Compare(string(a), string(b))
+	})
+	var b strings.Builder
+	for i, m := range monomials {
+		c := new(big.Rat).Set(p.terms[m])
+		switch {
+		case i == 0 && c.Sign() < 0:
+			b.WriteString("-")
+		case i > 0 && c.Sign() < 0:
+			b.WriteString(" - ")
+		case i > 0:
+			b.WriteString(" + ")
+		}
+		c.Abs(c)
+		coefficient := c.RatString()
+		switch {
+		case m == "":
+			b.WriteString(coefficient)
+		case coefficient == "1":
+			b.WriteString(string(m))
+		default:
+			b.WriteString(coefficient + "*" + string(m))
+		}
+	}
+	return b.String()
+}
+
+func monomialPowers(m monomial) map[string]int {
+	powers := map[string]int{}
+	if m == "" {
+		return powers
+	}
+	for _, factor := range strings.Split(string(m), "*") {
+		name, exp, found := strings.Cut(factor, "^")
+		n := 1
+		if found {
+			n, _ = strconv.Atoi(exp)
+		}
+		powers[name] += n
+	}
+	return powers
+}
+
+func monomialDegree(m monomial) int {
+	total := 0
+	for _, exp := range monomialPowers(m) {
+		total += exp
+	}
+	return total
+}
+
+func multiplyMonomials(a, b monomial) monomial {
+	powers := monomialPowers(a)
+	for name, exp := range monomialPowers(b) {
+		powers[name] += exp
+	}
+	names := make([]string, 0, len(powers))
+	for name := range powers {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+	factors := make([]string, 0, len(names))
+	for _, name := range names {
+		if powers[name] == 1 {
+			factors = append(factors, name)
+			continue
+		}
+		factors = append(factors, name+"^"+strconv.Itoa(powers[name]))
+	}
+	return monomial(strings.Join(factors, "*"))
+}
+
+// Variables returns the variables p uses, sorted.
+func (p Polynomial) Variables() []string {
+	seen := map[string]bool{}
+	for m := range p.terms {
+		for name := range monomialPowers(m) {
+			seen[name] = true
+		}
+	}
+	names := make([]string, 0, len(seen))
+	for name := range seen {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+	return names
+}
+
+// Eval evaluates p with each variable set from values; a missing variable
+// counts as zero.
+func (p Polynomial) Eval(values map[string]float64) float64 {
+	total := 0.0
+	for m, c := range p.terms {
+		term, _ := c.Float64()
+		for name, exp := range monomialPowers(m) {
+			term *= math.Pow(values[name], float64(exp))
+		}
+		total += term
+	}
+	return total
+}
@@ -0,0 +1,208 @@
+// Copyright 2026 the P&AI authors. All rights reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package algebra
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// StepStatus is the verdict on one line of working.
+type StepStatus string
+
+const (
+	// StepOK follows from the line before it, or starts the working.
+	StepOK StepStatus = "ok"
+	// StepIncorrect does not follow from the line before it.
+	StepIncorrect StepStatus = "incorrect"
+	// StepUnchecked could not be parsed, e.g. prose or a square root.
+	StepUnchecked StepStatus = "unchecked"
+)
+
+// Step is the verdict on one line of a student's working.
+type Step struct {
+	Number int        `json:"number"`
+	Text   string     `json:"text"`
+	Status StepStatus `json:"status"`
+	Note   string     `json:"note,omitempty"`
+}
+
+// Report is the result of CheckSteps.
+type Report struct {
+	Steps []Step `json:"steps"`
+	// FirstIncorrect is the Number of the first incorrect step, or 0 when
+	// every checked step follows.
+	FirstIncorrect int `json:"first_incorrect_step"`
+}
+
+var (
+	stepLabelPattern   = regexp.MustCompile(`(?i)^\s*(?:(?:step|langkah)\s*\d+\s*[:.)]?|\d+[.)]\s|=>|⇒|→|∴|so\s|maka\s|jadi\s)\s*`)
+	disjunctionPattern = regexp.MustCompile(`(?i)\s+(?:or|atau)\s+|或|,|;`)
+	plusMinusPattern   = regexp.MustCompile(`±|\+/-|\+-`)
+)
+
+type checker struct {
+	// expr is the last expression value, for "= ..." continuation lines.
+	expr, equation         Polynomial
+	haveExpr, haveEquation bool
+	exprStep, equationStep int
+	// expressionMode is set while the working rewrites an expression rather
+	// than solving an equation.
+	expressionMode bool
+}
+
+// CheckSteps checks a student's working one line at a time and reports the
+// first line that does not follow from the one before it. problem, when
+// set, is the question the working starts from. Each line is compared with
+// the line before it, so steps after a mistake are judged on their own.
+//
+// Lines may rewrite an expression ("= x^2 + 5x + 6"), or transform an
+// equation ("2x = 4", "x = 2 or x = -3", "x = ±2"). An equation step is
+// correct when it has the same solutions as the previous one.
+func CheckSteps(problem string, lines []string) Report {
+	var c checker
+	if strings.TrimSpace(problem) != "" {
+		c.check(0, problem)
+	}
+	var report Report
+	number := 0
+	for _, line := range lines {
+		if strings.TrimSpace(line) == "" {
+			continue
+		}
+		number++
+		step := c.check(number, line)
+		step.Text = strings.TrimSpace(line)
+		if step.Status == StepIncorrect && report.FirstIncorrect == 0 {
+			report.FirstIncorrect = number
+		}
+		report.Steps = append(report.Steps, step)
+	}
+	return report
+}
+
+// SplitWorking splits submitted working into lines.
+func SplitWorking(working string) []string {
+	var lines []string
+	for _, line := range strings.Split(strings.ReplaceAll(working, "\r\n", "\n"), "\n") {
+		if strings.TrimSpace(line) != "" {
+			lines = append(lines, line)
+		}
+	}
+	return lines
+}
+
+func (c *checker) check(number int, line string) Step {
+	step := Step{Number: number, Status: StepOK}
+	text := strings.TrimSpace(stepLabelPattern.ReplaceAllString(line, ""))
+	if strings.ContainsAny(text, "<>≤≥≠") {
+		return unchecked(step, "inequalities are not checked")
+	}
+
+	if rest, ok := strings.CutPrefix(text, "="); ok {
+		if !c.haveExpr {
+			return unchecked(step, "nothing to continue from")
+		}
+		return c.checkChain(step, strings.Split(rest, "="), true)
+	}
+
+	if equation, ok, err := parseEquation(text); err != nil {
+		return unchecked(step, err.Error())
+	} else if ok && !c.expressionMode {
+		if c.haveEquation && !sameSolutions(c.equation, equation) {
+			step.Status = StepIncorrect
+			step.Note = fmt.Sprintf("does not have the same solutions as %s", stepName(c.equationStep))
+		}
+		c.equation, c.haveEquation, c.equationStep = equation, true, number
+		if sides := strings.Split(text, "="); len(sides) == 2 {
+			if rhs, err := ParseExpression(sides[1]); err == nil {
+				c.expr, c.haveExpr, c.exprStep = rhs, true, number
+			}
+		}
+		return step
+	}
+
+	step = c.checkChain(step, strings.Split(text, "="), c.expressionMode && c.haveExpr)
+	if step.Status != StepUnchecked {
+		c.expressionMode = true
+	}
+	return step
+}
+
+// checkChain checks parts of an expression chain, each equal to the one
+// before. When continuing, the first part must equal the last expression.
+func (c *checker) checkChain(step Step, parts []string, continuing bool) Step {
+	for i, part := range parts {
+		value, err := ParseExpression(part)
+		if err != nil {
+			return unchecked(step, err.Error())
+		}
+		if (i > 0 || continuing) && !value.Equal(c.expr) && step.Status == StepOK {
+			step.Status = StepIncorrect
+			if i == 0 {
+				step.Note = fmt.Sprintf("is not equal to %s", stepName(c.exprStep))
+			} else {
+				step.Note = fmt.Sprintf("%s is not equal to %s", strings.TrimSpace(part), strings.TrimSpace(parts[i-1]))
+			}
+		}
+		c.expr, c.haveExpr, c.exprStep = value, true, step.Number
+	}
+	return step
+}
+
+// parseEquation parses a line of one or more alternative equations, such as
+// "x = 2 or x = -3", into one polynomial that is zero exactly on their
+// solutions. ok is false when the line is not of that shape.
+func parseEquation(text string) (Polynomial, bool, error) {
+	alternatives := []string{text}
+	if parts := disjunctionPattern.Split(text, -1); len(parts) > 1 {
+		alternatives = parts
+	}
+	var expanded []string
+	for _, alt := range alternatives {
+		if strings.Count(alt, "=") != 1 {
+			return Polynomial{}, false, nil
+		}
+		if plusMinusPattern.MatchString(alt) {
+			expanded = append(expanded,
+				plusMinusPattern.ReplaceAllLiteralString(alt, "+"),
+				plusMinusPattern.ReplaceAllLiteralString(alt, "-"))
+			continue
+		}
+		expanded = append(expanded, alt)
+	}
+
+	var product Polynomial
+	for i, alt := range expanded {
+		lhs, rhs, _ := strings.Cut(alt, "=")
+		left, err := ParseExpression(lhs)
+		if err != nil {
+			return Polynomial{}, false, err
+		}
+		right, err := ParseExpression(rhs)
+		if err != nil {
+			return Polynomial{}, false, err
+		}
+		if i == 0 {
+			product = left.Sub(right)
+			continue
+		}
+		product = product.Mul(left.Sub(right))
+	}
+	return product, true, nil
+}
+
+func unchecked(step Step, note string) Step {
+	step.Status = StepUnchecked
+	step.Note = note
+	return step
+}
+
+func stepName(number int) string {
+	if number == 0 {
+		return "the question"
+	}
+	return fmt.Sprintf("step %d", number)
+}
@@ -0,0 +1,129 @@
+// Copyright 2026 the P&AI authors. All rights reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package algebra
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestParseExpression(t *testing.T) {
+	tests := []struct {
+		input string
+		want  string
+	}{
+		{input: "2x + 3x", want: "5*x"},
+		{input: "(x + 2)(x + 3)", want: "x^2 + 5*x + 6"},
+		{input: "3(x − 2)²", want: "3*x^2 - 12*x + 12"},
+		{input: "x/2 + 0.5", want: "1/2*x + 1/2"},
+		{input: "-x^2 + 2*-3", want: "-x^2 - 6"},
+		{input: "2xy - yx", want: "x*y"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			got, err := ParseExpression(tt.input)
+			if err != nil {
+				t.Fatalf("ParseExpression() error = %v", err)
+			}
+			if got.String() != tt.want {
+				t.Fatalf("ParseExpression() = %q, want %q", got.String(), tt.want)
+			}
+		})
+	}
+}
+
+func TestParseExpressionRejectsNonPolynomials(t *testing.T) {
+	for _, input := range []string{"1/x", "sqrt(x)", "x^0.5", "x^99", "√x"} {
+		if _, err := ParseExpression(input); !errors.Is(err, ErrUnsupported) {
+			t.Errorf("ParseExpression(%q) error = %v, want ErrUnsupported", input, err)
+		}
+	}
+}
+
+func TestCheckSteps(t *testing.T) {
+	tests := []struct {
+		name           string
+		problem        string
+		working        string
+		firstIncorrect int
+		wantNote       string
+	}{
+		{
+			name:    "linear equation solved correctly",
+			problem: "3(x + 2) = 21",
+			working: "3x + 6 = 21\n3x = 15\nx = 5",
+		},
+		{
+			name:           "sign slip when moving a term",
+			problem:        "3(x + 2) = 21",
+			working:        "3x + 6 = 21\n3x = 27\nx = 9",
+			firstIncorrect: 2,
+			wantNote:       "does not have the same solutions as step 1",
+		},
+		{
+			name:           "first line checked against the question",
+			problem:        "3(x + 2) = 21",
+			working:        "3x + 2 = 21\n3x = 19",
+			firstIncorrect: 1,
+			wantNote:       "does not have the same solutions as the question",
+		},
+		{
+			name:    "quadratic by factorising",
+			working: "x² - x - 6 = 0\n(x - 3)(x + 2) = 0\nx = 3 or x = -2",
+		},
+		{
+			name:           "quadratic factorised with the wrong signs",
+			working:        "x^2 - x - 6 = 0\n(x + 3)(x - 2) = 0\nx = -3 or x = 2",
+			firstIncorrect: 2,
+		},
+		{
+			name:           "square root loses the negative root",
+			working:        "x^2 = 9\nx = 3",
+			firstIncorrect: 2,
+		},
+		{
+			name:    "plus-minus keeps both roots",
+			working: "x^2 = 9\nx = ±3",
+		},
+		{
+			name:    "repeated root",
+			working: "x^2 - 4x + 4 = 0\n(x - 2)^2 = 0\nx = 2",
+		},
+		{
+			name:           "expansion chain",
+			problem:        "(x + 2)(x + 3)",
+			working:        "= x^2 + 3x + 2x + 6\n= x^2 + 6x + 6",
+			firstIncorrect: 2,
+			wantNote:       "is not equal to step 1",
+		},
+		{
+			name:           "expansion chain after an expression",
+			working:        "2(x + 4) - 3x\n= 2x + 8 - 3x = -x + 5",
+			firstIncorrect: 2,
+			wantNote:       "-x + 5 is not equal to 2x + 8 - 3x",
+		},
+		{
+			name:    "labels and prose are skipped",
+			working: "Step 1: 2x + 4 = 10\nsubtract 4 from both sides\nStep 2: 2x = 6\n∴ x = 3",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			report := CheckSteps(tt.problem, SplitWorking(tt.working))
+			if report.FirstIncorrect != tt.firstIncorrect {
+				t.Fatalf("FirstIncorrect = %d, want %d (steps %+v)", report.FirstIncorrect, tt.firstIncorrect, report.Steps)
+			}
+			if tt.wantNote != "" && report.Steps[tt.firstIncorrect-1].Note != tt.wantNote {
+				t.Fatalf("note = %q, want %q", report.Steps[tt.firstIncorrect-1].Note, tt.wantNote)
+			}
+		})
+	}
+}
+
+func TestCheckStepsMarksUnparsedLines(t *testing.T) {
+	report := CheckSteps("", []string{"2x + 4 = 10", "subtract 4 from both sides", "2x ≥ 6"})
+	if report.Steps[1].Status != StepUnchecked || report.Steps[2].Status != StepUnchecked {
+		t.Fatalf("steps = %+v, want prose and inequality unchecked", report.Steps)
+	}
+}
@@ -0,0 +1,144 @@
+// Copyright 2026 the P&AI authors. All rights reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package algebra
+
+import (
+	"math/big"
+	"strconv"
+)
+
+// singleVariable returns the only variable in p, or "" when p is constant.
+// ok is false when p has more than one variable.
+func (p Polynomial) singleVariable() (name string, ok bool) {
+	for m := range p.terms {
+		for v := range monomialPowers(m) {
+			if name != "" && v != name {
+				return "", false
+			}
+			name = v
+		}
+	}
+	return name, true
+}
+
+// coefficients returns p as a dense coefficient list in x, lowest power
+// first. p must have no variable other than x.
+func (p Polynomial) coefficients(x string) []*big.Rat {
+	coeffs := make([]*big.Rat, p.Degree()+1)
+	for i := range coeffs {
+		coeffs[i] = new(big.Rat)
+	}
+	for m, c := range p.terms {
+		coeffs[monomialPowers(m)[x]].Set(c)
+	}
+	return trimCoefficients(coeffs)
+}
+
+func fromCoefficients(x string, coeffs []*big.Rat) Polynomial {
+	p := Polynomial{terms: map[monomial]*big.Rat{}}
+	for i, c := range coeffs {
+		if c.Sign() == 0 {
+			continue
+		}
+		var m monomial
+		switch i {
+		case 0:
+		case 1:
+			m = monomial(x)
+		default:
+			m = monomial(x + "^" + strconv.Itoa(i))
+		}
+		p.terms[m] = new(big.Rat).Set(c)
+	}
+	return p
+}
+
+func trimCoefficients(coeffs []*big.Rat) []*big.Rat {
+	for len(coeffs) > 0 && coeffs[len(coeffs)-1].Sign() == 0 {
+		coeffs = coeffs[:len(coeffs)-1]
+	}
+	return coeffs
+}
+
+// remainder returns a mod b for dense coefficient lists; b must be nonzero.
+func remainder(a, b []*big.Rat) []*big.Rat {
+	r := make([]*big.Rat, len(a))
+	for i, c := range a {
+		r[i] = new(big.Rat).Set(c)
+	}
+	lead := b[len(b)-1]
+	for r = trimCoefficients(r); len(r) >= len(b); r = trimCoefficients(r) {
+		factor := new(big.Rat).Quo(r[len(r)-1], lead)
+		shift := len(r) - len(b)
+		for i, c := range b {
+			r[shift+i].Sub(r[shift+i], new(big.Rat).Mul(factor, c))
+		}
+	}
+	return r
+}
+
+func gcdCoefficients(a, b []*big.Rat) []*big.Rat {
+	for len(b) > 0 {
+		a, b = b, remainder(a, b)
+	}
+	return a
+}
+
+func derivative(coeffs []*big.Rat) []*big.Rat {
+	if len(coeffs) <= 1 {
+		return nil
+	}
+	d := make([]*big.Rat, len(coeffs)-1)
+	for i := range d {
+		d[i] = new(big.Rat).Mul(coeffs[i+1], big.NewRat(int64(i+1), 1))
+	}
+	return trimCoefficients(d)
+}
+
+// quotient returns a / b for dense coefficient lists where b divides a.
+func quotient(a, b []*big.Rat) []*big.Rat {
+	r := make([]*big.Rat, len(a))
+	for i, c := range a {
+		r[i] = new(big.Rat).Set(c)
+	}
+	q := make([]*big.Rat, len(a)-len(b)+1)
+	lead := b[len(b)-1]
+	for i := len(q) - 1; i >= 0; i-- {
+		q[i] = new(big.Rat).Quo(r[i+len(b)-1], lead)
+		for j, c := range b {
+			r[i+j].Sub(r[i+j], new(big.Rat).Mul(q[i], c))
+		}
+	}
+	return q
+}
+
+// squareFree drops repeated factors from a one-variable p, so (x-2)^2 and
+// x-2 compare as having the same roots.
+func (p Polynomial) squareFree() Polynomial {
+	x, ok := p.singleVariable()
+	if !ok || x == "" || p.Degree() < 2 {
+		return p
+	}
+	coeffs := p.coefficients(x)
+	g := gcdCoefficients(coeffs, derivative(coeffs))
+	if len(g) <= 1 {
+		return p
+	}
+	return fromCoefficients(x, quotient(coeffs, g))
+}
+
+// sameSolutions reports whether p = 0 and q = 0 have the same solutions.
+// One-variable equations compare by their distinct roots; anything else
+// must match up to a nonzero constant factor.
+func sameSolutions(p, q Polynomial) bool {
+	if p.proportional(q) {
+		return true
+	}
+	px, pok := p.singleVariable()
+	qx, qok := q.singleVariable()
+	if !pok || !qok || px != qx || px == "" {
+		return false
+	}
+	return p.squareFree().proportional(q.squareFree())
+}
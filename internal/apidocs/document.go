@@ -35,9 +35,10 @@ type Tag struct {
 type Paths map[string]*PathItem
 
 type PathItem struct {
-	Get  *Operation `json:"get,omitempty"`
-	Post *Operation `json:"post,omitempty"`
-	Put  *Operation `json:"put,omitempty"`
+	Get    *Operation `json:"get,omitempty"`
+	Post   *Operation `json:"post,omitempty"`
+	Put    *Operation `json:"put,omitempty"`
+	Delete *Operation `json:"delete,omitempty"`
 }
 
 type Operation struct {
@@ -33,6 +33,10 @@ func TestBuild_GeneratesExplicitSchemas(t *testing.T) {
 	if aiSettings.Get == nil || aiSettings.Put == nil {
 		t.Fatalf("/api/admin/ai/settings = %#v, want GET and PUT", aiSettings)
 	}
+	templates, ok := doc.Paths["/api/admin/message-templates/{key}/{locale}"]
+	if !ok || templates.Put == nil || templates.Delete == nil {
+		t.Fatalf("/api/admin/message-templates/{key}/{locale} = %#v, want PUT and DELETE", templates)
+	}
 	updateSchema, ok := doc.Components.Schemas["aiSettingsUpdateRequestDoc"]
 	if !ok {
 		t.Fatal("missing aiSettingsUpdateRequestDoc schema")
@@ -8,6 +8,7 @@ import (
 
 	"github.com/p-n-ai/pai-bot/internal/adminapi"
 	"github.com/p-n-ai/pai-bot/internal/auth"
+	"github.com/p-n-ai/pai-bot/internal/curriculum"
 )
 
 type refreshTokenRequest struct {
@@ -31,6 +32,56 @@ type manualNudgeResponse struct {
 	Channel string `json:"channel"`
 }
 
+type resummarizeResponse struct {
+	ConversationID string `json:"conversation_id"`
+	Summary        string `json:"summary"`
+}
+
+type sandboxStartRequestDoc struct {
+	Name string `json:"name,omitempty"`
+	Form string `json:"form,omitempty"`
+}
+
+type sandboxSessionDoc struct {
+	UserID         string `json:"user_id"`
+	ConversationID string `json:"conversation_id"`
+}
+
+type sandboxMessageRequestDoc struct {
+	Text string `json:"text"`
+}
+
+type sandboxReplyDoc struct {
+	UserID string `json:"user_id"`
+	Reply  string `json:"reply"`
+}
+
+type teachingNotesResponse struct {
+	Notes []adminapi.TeachingNote `json:"notes"`
+}
+
+type teachingNoteHistoryResponse struct {
+	Versions []adminapi.TeachingNote `json:"versions"`
+}
+
+type messageTemplateRequestDoc struct {
+	Body string `json:"body"`
+}
+
+type dailyProblemRequestDoc struct {
+	TopicID  string                        `json:"topic_id"`
+	Question adminapi.DailyProblemQuestion `json:"question"`
+}
+
+type createAPITokenRequestDoc struct {
+	Name string `json:"name"`
+}
+
+type conversationReviewRatingDoc struct {
+	Rating  int    `json:"rating"`
+	Comment string `json:"comment,omitempty"`
+}
+
 type aiSettingsKeyStatusDoc struct {
 	Set   bool   `json:"set"`
 	Last4 string `json:"last4"`
@@ -63,6 +114,21 @@ type healthResponse struct {
 	Status string `json:"status"`
 }
 
+type readinessChannelDoc struct {
+	Channel         string  `json:"channel"`
+	Healthy         bool    `json:"healthy"`
+	LastReceivedAt  *string `json:"last_received_at,omitempty"`
+	LastSentAt      *string `json:"last_sent_at,omitempty"`
+	WebhookVerified *bool   `json:"webhook_verified,omitempty"`
+	LoopFailures    int     `json:"loop_failures"`
+	Restarts        int     `json:"restarts"`
+}
+
+type readinessResponse struct {
+	Status   string                `json:"status"`
+	Channels []readinessChannelDoc `json:"channels,omitempty"`
+}
+
 func Build() (*Document, error) {
 	registry := newSchemaRegistry()
 
@@ -78,6 +144,7 @@ func Build() (*Document, error) {
 			{Name: "Health"},
 			{Name: "Auth"},
 			{Name: "Admin"},
+			{Name: "Public"},
 		},
 		Components: Components{
 			Schemas: map[string]*Schema{},
@@ -87,6 +154,11 @@ func Build() (*Document, error) {
 					Scheme:       "bearer",
 					BearerFormat: "JWT",
 				},
+				"APIToken": {
+					Type:         "http",
+					Scheme:       "bearer",
+					BearerFormat: "pai_ API token",
+				},
 			},
 		},
 		Paths: Paths{},
@@ -100,7 +172,7 @@ func Build() (*Document, error) {
 	doc.Paths["/readyz"] = route("GET", Operation{
 		Summary:   "Readiness check",
 		Tags:      []string{"Health"},
-		Responses: okJSON("Service is ready.", registry.refFor(healthResponse{})),
+		Responses: okJSON("Service is ready, with per-channel health when chat channels run in this process.", registry.refFor(readinessResponse{})),
 	})
 
 	doc.Paths["/api/auth/login"] = route("POST", Operation{
@@ -169,6 +241,15 @@ func Build() (*Document, error) {
 			Schema:      &Schema{Type: "string"},
 		}}
 	}
+	queryParam := func(name, description string, required bool) Parameter {
+		return Parameter{
+			Name:        name,
+			In:          "query",
+			Required:    required,
+			Description: description,
+			Schema:      &Schema{Type: "string"},
+		}
+	}
 
 	doc.Paths["/api/admin/invites"] = route("POST", Operation{
 		Summary:     "Create a teacher, parent, or admin invite",
@@ -238,6 +319,75 @@ func Build() (*Document, error) {
 			responseText("404", "Requested student was not found."),
 		),
 	})
+	doc.Paths["/api/admin/conversations/{id}/transcript"] = route("GET", Operation{
+		Summary:     "Get an annotated conversation transcript",
+		Description: "Interleaves messages with the events fired during each turn (topic, tool calls, fallbacks) for tutor review.",
+		Tags:        []string{"Admin"},
+		Security:    protected,
+		Parameters:  idParam("Conversation identifier."),
+		Responses: mergeResponses(
+			responseJSON("200", "Annotated transcript.", registry.refFor(adminapi.ConversationTranscript{})),
+			protectedErrors(),
+			responseText("404", "Requested conversation was not found."),
+		),
+	})
+	doc.Paths["/api/admin/messages/search"] = route("GET", Operation{
+		Summary:     "Search message history",
+		Description: "Full-text search over tenant message history, best match first. Every word must match as a word prefix. Archived conversations are not searched.",
+		Tags:        []string{"Admin"},
+		Security:    protected,
+		Parameters: []Parameter{
+			queryParam("q", "Search words.", true),
+			queryParam("student_id", "Only search this student's conversations.", false),
+			queryParam("limit", "Maximum results, 1-100. Defaults to 20.", false),
+		},
+		Responses: mergeResponses(
+			responseJSON("200", "Matching messages.", arrayOf(registry.refFor(adminapi.MessageSearchHit{}))),
+			protectedErrors(),
+			responseText("400", "Missing query or invalid limit."),
+		),
+	})
+	doc.Paths["/api/admin/conversations/{id}/resummarize"] = route("POST", Operation{
+		Summary:     "Regenerate a conversation summary",
+		Description: "Rebuilds the compaction summary from the raw message history. The new summary is validated for length, language, and topic coverage before it replaces the old one.",
+		Tags:        []string{"Admin"},
+		Security:    protected,
+		Parameters:  idParam("Conversation identifier."),
+		Responses: mergeResponses(
+			responseJSON("200", "Regenerated summary.", registry.refFor(resummarizeResponse{})),
+			protectedErrors(),
+			responseText("404", "Requested conversation was not found."),
+			responseText("409", "Conversation has no messages older than the recent window."),
+			responseText("502", "Summary could not be regenerated."),
+		),
+	})
+	doc.Paths["/api/admin/sandbox"] = route("POST", Operation{
+		Summary:     "Start a sandbox learner",
+		Description: "Creates a throwaway learner and conversation for staff to test prompts as a student. Sandbox learners are left out of analytics and never count against token budgets. With a form, the learner skips onboarding. Platform admins only in multi-tenant mode.",
+		Tags:        []string{"Admin"},
+		Security:    protected,
+		RequestBody: jsonBody(registry.refFor(sandboxStartRequestDoc{})),
+		Responses: mergeResponses(
+			responseJSON("201", "Sandbox learner and conversation.", registry.refFor(sandboxSessionDoc{})),
+			protectedErrors(),
+			responseText("400", "Form is not 1, 2 or 3."),
+		),
+	})
+	doc.Paths["/api/admin/sandbox/{id}/messages"] = route("POST", Operation{
+		Summary:     "Chat as a sandbox learner",
+		Description: "Runs one learner turn through the tutor and returns its reply.",
+		Tags:        []string{"Admin"},
+		Security:    protected,
+		Parameters:  idParam("Sandbox learner identifier from the start response."),
+		RequestBody: jsonBody(registry.refFor(sandboxMessageRequestDoc{})),
+		Responses: mergeResponses(
+			responseJSON("200", "Tutor reply.", registry.refFor(sandboxReplyDoc{})),
+			protectedErrors(),
+			responseText("400", "Missing text."),
+			responseText("404", "Not a sandbox learner."),
+			responseText("502", "Tutor turn failed."),
+		),
+	})
 	doc.Paths["/api/admin/students/{id}/nudge"] = route("POST", Operation{
 		Summary:    "Queue a manual nudge for a student",
 		Tags:       []string{"Admin"},
@@ -341,6 +491,30 @@ func Build() (*Document, error) {
 			protectedErrors(),
 		),
 	})
+	doc.Paths["/api/admin/students/{id}/learning-state"] = route("GET", Operation{
+		Summary:     "Export a student's learning state",
+		Description: "Downloads the student's profile, topic mastery, streak, XP awards, goals and study plan as JSON for the import endpoint. Conversations are not included.",
+		Tags:        []string{"Admin"},
+		Security:    protected,
+		Parameters:  idParam("Student external identifier or user ID."),
+		Responses: mergeResponses(
+			responseJSON("200", "Learning state.", registry.refFor(adminapi.LearningState{})),
+			protectedErrors(),
+			responseText("404", "Student was not found."),
+		),
+	})
+	doc.Paths["/api/admin/learning-state/import"] = route("POST", Operation{
+		Summary:     "Import a student's learning state",
+		Description: "Writes an exported learning state to the student with the same channel and external ID in the caller's tenant, creating them if needed. Their progress, streak, XP, goals and study plan are replaced, so importing the same export twice is safe.",
+		Tags:        []string{"Admin"},
+		Security:    protected,
+		RequestBody: jsonBody(registry.refFor(adminapi.LearningState{})),
+		Responses: mergeResponses(
+			responseJSON("200", "Import summary.", registry.refFor(adminapi.LearningStateImportResult{})),
+			protectedErrors(),
+			responseText("400", "Unsupported version, missing learner identity, or values outside the database's limits."),
+		),
+	})
 	doc.Paths["/api/admin/parents/{id}"] = route("GET", Operation{
 		Summary:    "Get parent summary",
 		Tags:       []string{"Admin"},
@@ -352,6 +526,232 @@ func Build() (*Document, error) {
 			responseText("404", "Requested parent was not found."),
 		),
 	})
+	doc.Paths["/api/admin/teaching-notes"] = route("GET", Operation{
+		Summary:     "List school teaching notes",
+		Description: "Returns the latest version of each topic's school-specific notes and worked examples.",
+		Tags:        []string{"Admin"},
+		Security:    protected,
+		Responses: mergeResponses(
+			responseJSON("200", "Latest teaching note per topic.", registry.refFor(teachingNotesResponse{})),
+			protectedErrors(),
+		),
+	})
+	topicParam := []Parameter{{
+		Name:        "topicID",
+		In:          "path",
+		Required:    true,
+		Description: "Curriculum topic identifier, e.g. F1-03.",
+		Schema:      &Schema{Type: "string"},
+	}}
+	doc.Paths["/api/admin/teaching-notes/{topicID}"] = &PathItem{
+		Get: &Operation{
+			Summary:    "Get a topic's teaching note history",
+			Tags:       []string{"Admin"},
+			Security:   protected,
+			Parameters: topicParam,
+			Responses: mergeResponses(
+				responseJSON("200", "Every saved version, newest first.", registry.refFor(teachingNoteHistoryResponse{})),
+				protectedErrors(),
+				responseText("404", "Topic has no teaching notes."),
+			),
+		},
+		Put: &Operation{
+			Summary:     "Save a topic's teaching note",
+			Description: "Validates the notes and worked examples against the loaded curriculum, stores them as the topic's next version, and applies them to the tutor immediately. The tutor sees them after the OSS teaching notes.",
+			Tags:        []string{"Admin"},
+			Security:    protected,
+			Parameters:  topicParam,
+			RequestBody: jsonBody(registry.refFor(curriculum.TeachingNoteOverlay{})),
+			Responses: mergeResponses(
+				responseJSON("200", "Saved version.", registry.refFor(adminapi.TeachingNote{})),
+				protectedErrors(),
+				responseText("400", "Unknown topic, or notes and examples are missing, incomplete, or too long."),
+			),
+		},
+	}
+	doc.Paths["/api/admin/message-templates"] = route("GET", Operation{
+		Summary:     "List message templates",
+		Description: "Returns the outbound messages a tenant may override, with the {{variables}} each can use, and the tenant's saved overrides.",
+		Tags:        []string{"Admin"},
+		Security:    protected,
+		Responses: mergeResponses(
+			responseJSON("200", "Overridable messages and saved overrides.", registry.refFor(adminapi.MessageTemplateView{})),
+			protectedErrors(),
+		),
+	})
+	templateParams := []Parameter{
+		{Name: "key", In: "path", Required: true, Description: "Message key, e.g. start_onboarding_form or review_reminder.", Schema: &Schema{Type: "string"}},
+		{Name: "locale", In: "path", Required: true, Description: "en, ms, or zh.", Schema: &Schema{Type: "string"}},
+	}
+	doc.Paths["/api/admin/message-templates/{key}/{locale}"] = &PathItem{
+		Put: &Operation{
+			Summary:     "Save a message template",
+			Description: "Stores the tenant's copy of a message in one locale and applies it to the bot immediately.",
+			Tags:        []string{"Admin"},
+			Security:    protected,
+			Parameters:  templateParams,
+			RequestBody: jsonBody(registry.refFor(messageTemplateRequestDoc{})),
+			Responses: mergeResponses(
+				responseJSON("200", "Saved template.", registry.refFor(adminapi.MessageTemplate{})),
+				protectedErrors(),
+				responseText("400", "Message cannot be overridden, unsupported locale, blank body, or unknown variable."),
+			),
+		},
+		Delete: &Operation{
+			Summary:    "Delete a message template",
+			Tags:       []string{"Admin"},
+			Security:   protected,
+			Parameters: templateParams,
+			Responses: mergeResponses(
+				responseEmpty("204", "Built-in copy restored."),
+				protectedErrors(),
+				responseText("404", "No override saved for this message and locale."),
+			),
+		},
+	}
+	doc.Paths["/api/admin/tenant-settings"] = &PathItem{
+		Get: &Operation{
+			Summary:     "Get tenant settings",
+			Description: "Returns the tenant's tutor switches, all off when none are saved.",
+			Tags:        []string{"Admin"},
+			Security:    protected,
+			Responses: mergeResponses(
+				responseJSON("200", "Tenant settings.", registry.refFor(adminapi.TenantSettings{})),
+				protectedErrors(),
+			),
+		},
+		Put: &Operation{
+			Summary:     "Save tenant settings",
+			Description: "Replaces the tenant's tutor switches and applies them to the bot immediately. meta_footer appends the tutor's move and the topic practised to each answer.",
+			Tags:        []string{"Admin"},
+			Security:    protected,
+			RequestBody: jsonBody(registry.refFor(adminapi.TenantSettings{})),
+			Responses: mergeResponses(
+				responseJSON("200", "Saved settings.", registry.refFor(adminapi.TenantSettings{})),
+				protectedErrors(),
+			),
+		},
+	}
+	doc.Paths["/api/admin/daily-problems"] = route("GET", Operation{
+		Summary:     "List daily problems",
+		Description: "Returns the tenant's problems of the day from the last 14 days and any scheduled ahead, newest first.",
+		Tags:        []string{"Admin"},
+		Security:    protected,
+		Responses: mergeResponses(
+			responseJSON("200", "Daily problems.", arrayOf(registry.refFor(adminapi.DailyProblem{}))),
+			protectedErrors(),
+		),
+	})
+	doc.Paths["/api/admin/daily-problems/{form}/{day}"] = &PathItem{
+		Put: &Operation{
+			Summary:     "Save a daily problem",
+			Description: "Curates a form's problem of the day, replacing any problem already set for that day. Options make it multiple choice and must include the answer.",
+			Tags:        []string{"Admin"},
+			Security:    protected,
+			Parameters: []Parameter{
+				{Name: "form", In: "path", Required: true, Description: "Form the problem is for, e.g. 1.", Schema: &Schema{Type: "string"}},
+				{Name: "day", In: "path", Required: true, Description: "Publish date in MYT, YYYY-MM-DD.", Schema: &Schema{Type: "string"}},
+			},
+			RequestBody: jsonBody(registry.refFor(dailyProblemRequestDoc{})),
+			Responses: mergeResponses(
+				responseJSON("200", "Saved problem.", registry.refFor(adminapi.DailyProblem{})),
+				protectedErrors(),
+				responseText("400", "Missing topic, question text or answer, bad date, or options without the answer."),
+			),
+		},
+	}
+	doc.Paths["/api/admin/reviews"] = route("GET", Operation{
+		Summary:     "List the conversation review queue",
+		Description: "Conversations queued for human quality review: a random sample of sessions (LEARN_REVIEW_SAMPLE_PERCENT) and every conversation with a safety-flagged turn. Pending reviews list safety flags first, then oldest first. Open one with the transcript endpoint.",
+		Tags:        []string{"Admin"},
+		Security:    protected,
+		Parameters: []Parameter{
+			queryParam("status", "pending (default), reviewed, or all.", false),
+			queryParam("limit", "Maximum results, up to 200. Defaults to 50.", false),
+		},
+		Responses: mergeResponses(
+			responseJSON("200", "Queued conversations.", arrayOf(registry.refFor(adminapi.ConversationReview{}))),
+			protectedErrors(),
+			responseText("400", "Unknown status or invalid limit."),
+		),
+	})
+	doc.Paths["/api/admin/reviews/{id}"] = route("POST", Operation{
+		Summary:     "Rate a queued conversation",
+		Description: "Records a 1-5 rating and an optional comment. Rating again replaces the earlier verdict.",
+		Tags:        []string{"Admin"},
+		Security:    protected,
+		Parameters:  idParam("Review identifier."),
+		RequestBody: jsonBody(registry.refFor(conversationReviewRatingDoc{})),
+		Responses: mergeResponses(
+			responseJSON("200", "Rated review.", registry.refFor(adminapi.ConversationReview{})),
+			protectedErrors(),
+			responseText("400", "Rating is not between 1 and 5."),
+			responseText("404", "Review was not found."),
+		),
+	})
+	doc.Paths["/api/admin/reviews/export"] = route("GET", Operation{
+		Summary:     "Export rated reviews as eval cases",
+		Description: "Downloads the 200 most recently rated conversations as a conversation-harness YAML fixture. Each case replays the learner's turns and is tagged with its reason, rating and topic.",
+		Tags:        []string{"Admin"},
+		Security:    protected,
+		Responses: mergeResponses(
+			responseText("200", "YAML fixture for cmd/conversation-harness."),
+			protectedErrors(),
+		),
+	})
+
+	doc.Paths["/api/admin/api-tokens"] = &PathItem{
+		Get: &Operation{
+			Summary:     "List API tokens",
+			Description: "Returns the tenant's read-only API tokens, revoked ones included, newest first. Secrets are never returned here.",
+			Tags:        []string{"Admin"},
+			Security:    protected,
+			Responses: mergeResponses(
+				responseJSON("200", "API tokens.", arrayOf(registry.refFor(adminapi.APIToken{}))),
+				protectedErrors(),
+			),
+		},
+		Post: &Operation{
+			Summary:     "Create an API token",
+			Description: "Issues a token for the public usage API. The response is the only time the token is shown; only its hash is stored.",
+			Tags:        []string{"Admin"},
+			Security:    protected,
+			RequestBody: jsonBody(registry.refFor(createAPITokenRequestDoc{})),
+			Responses: mergeResponses(
+				responseJSON("201", "Created token with its secret.", registry.refFor(adminapi.CreatedAPIToken{})),
+				protectedErrors(),
+				responseText("400", "Name is blank or longer than 100 characters."),
+			),
+		},
+	}
+	doc.Paths["/api/admin/api-tokens/{id}"] = &PathItem{
+		Delete: &Operation{
+			Summary:    "Revoke an API token",
+			Tags:       []string{"Admin"},
+			Security:   protected,
+			Parameters: idParam("API token identifier."),
+			Responses: mergeResponses(
+				responseEmpty("204", "Token revoked."),
+				protectedErrors(),
+				responseText("404", "API token was not found."),
+			),
+		},
+	}
+	doc.Paths["/api/public/v1/usage"] = route("GET", Operation{
+		Summary:     "Get tenant usage statistics",
+		Description: "Aggregate usage for the tenant that owns the API token: students who sent a message, messages, AI tokens, and the five busiest topics. Sandbox learners are left out. Authenticate with a token from /api/admin/api-tokens, not a session.",
+		Tags:        []string{"Public"},
+		Security:    []Security{{"APIToken": []string{}}},
+		Parameters: []Parameter{
+			queryParam("from", "Start of the window, inclusive: YYYY-MM-DD (UTC) or RFC 3339. Defaults to 30 days before to.", false),
+			queryParam("to", "End of the window, exclusive: YYYY-MM-DD (UTC) or RFC 3339. Defaults to now. At most 366 days after from.", false),
+		},
+		Responses: mergeResponses(
+			responseJSON("200", "Usage statistics.", registry.refFor(adminapi.UsageStats{})),
+			responseText("400", "Bad date, from not before to, or a window longer than 366 days."),
+			responseText("401", "API token is missing, unknown, or revoked."),
+		),
+	})
 
 	doc.Components.Schemas = registry.schemas
 	return doc, nil
@@ -0,0 +1,177 @@
+// Copyright 2026 the P&AI authors. All rights reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+// Package apperr classifies errors so every layer handles a failure the same
+// way: whether to retry it, what the learner is told, and which analytics
+// event records it.
+//
+// Errors are classified where they are created, by wrapping them with
+// Transient, Permanent, Dependency, UserInput or Safety, or by giving the
+// error type an ErrorClass method. Callers further up read the class with
+// ClassOf and never match on error text.
+package apperr
+
+import (
+	"context"
+	"errors"
+	"net"
+	"net/http"
+	"strings"
+
+	"github.com/p-n-ai/pai-bot/internal/i18n"
+)
+
+// Class is the kind of failure an error represents.
+type Class string
+
+const (
+	// ClassUnknown is an error nobody classified.
+	ClassUnknown Class = "unknown"
+	// ClassTransient is a failure that retrying soon may fix: rate limits,
+	// timeouts, overload.
+	ClassTransient Class = "transient"
+	// ClassPermanent is a failure that retrying will not fix: a bug, a bad
+	// request to a provider, a violated invariant.
+	ClassPermanent Class = "permanent"
+	// ClassDependency is a downstream service that is down or misconfigured:
+	// a rejected API key, an unreachable database.
+	ClassDependency Class = "dependency"
+	// ClassUserInput is a request the user can fix: an unknown code, a
+	// malformed argument, an action not allowed in their current state.
+	ClassUserInput Class = "user_input"
+	// ClassSafety is content a safety check blocked.
+	ClassSafety Class = "safety"
+)
+
+// Classes lists every class, ClassUnknown first.
+func Classes() []Class {
+	return []Class{ClassUnknown, ClassTransient, ClassPermanent, ClassDependency, ClassUserInput, ClassSafety}
+}
+
+// Error is an error tagged with its class. Its text is the wrapped error's
+// text, so classifying an error never changes what is logged.
+type Error struct {
+	Class Class
+	// Message overrides the catalog message the user sees for Class.
+	Message i18n.Key
+	Err     error
+}
+
+func (e *Error) Error() string     { return e.Err.Error() }
+func (e *Error) Unwrap() error     { return e.Err }
+func (e *Error) ErrorClass() Class { return e.Class }
+
+// Wrap tags err with class. It returns nil for a nil err.
+func Wrap(class Class, err error) error {
+	if err == nil {
+		return nil
+	}
+	return &Error{Class: class, Err: err}
+}
+
+// Transient tags err as worth retrying soon.
+func Transient(err error) error { return Wrap(ClassTransient, err) }
+
+// Permanent tags err as not worth retrying.
+func Permanent(err error) error { return Wrap(ClassPermanent, err) }
+
+// Dependency tags err as a failed downstream service.
+func Dependency(err error) error { return Wrap(ClassDependency, err) }
+
+// UserInput tags err as something the user can fix.
+func UserInput(err error) error { return Wrap(ClassUserInput, err) }
+
+// Safety tags err as blocked by a safety check.
+func Safety(err error) error { return Wrap(ClassSafety, err) }
+
+// WithMessage keeps err's class but shows the user key instead of the
+// class's catalog message. It returns nil for a nil err.
+func WithMessage(err error, key i18n.Key) error {
+	if err == nil {
+		return nil
+	}
+	return &Error{Class: ClassOf(err), Message: key, Err: err}
+}
+
+// classifier is implemented by errors that know their own class, such as
+// Error and chat.APIError.
+type classifier interface {
+	ErrorClass() Class
+}
+
+// sqlStater matches pgconn.PgError without importing the driver.
+type sqlStater interface {
+	SQLState() string
+}
+
+// ClassOf returns err's class. The outermost classified error in the chain
+// wins, so a caller can reclassify what it wraps. Unclassified timeouts,
+// cancellations, network failures and PostgreSQL errors are classified by
+// kind; anything else is ClassUnknown. ClassOf(nil) is "".
+func ClassOf(err error) Class {
+	if err == nil {
+		return ""
+	}
+	var c classifier
+	if errors.As(err, &c) {
+		return c.ErrorClass()
+	}
+	if errors.Is(err, context.DeadlineExceeded) || errors.Is(err, context.Canceled) {
+		return ClassTransient
+	}
+	var pg sqlStater
+	if errors.As(err, &pg) {
+		return sqlStateClass(pg.SQLState())
+	}
+	var netErr net.Error
+	if errors.As(err, &netErr) {
+		if netErr.Timeout() {
+			return ClassTransient
+		}
+		return ClassDependency
+	}
+	return ClassUnknown
+}
+
+// Retryable reports whether err is worth retrying.
+func Retryable(err error) bool {
+	return ClassOf(err) == ClassTransient
+}
+
+// StatusClass classifies an HTTP status from a downstream API: the statuses
+// providers use for rate limiting and overload are transient, rejected
+// credentials and other server errors are the dependency's fault, and any
+// other client error is a request that will never succeed.
+func StatusClass(status int) Class {
+	switch {
+	case status == http.StatusRequestTimeout, status == http.StatusTooManyRequests,
+		status == http.StatusInternalServerError, status == http.StatusBadGateway,
+		status == http.StatusServiceUnavailable, status == http.StatusGatewayTimeout,
+		status == 529: // Anthropic: overloaded
+		return ClassTransient
+	case status == http.StatusUnauthorized, status == http.StatusForbidden, status >= 500:
+		return ClassDependency
+	default:
+		return ClassPermanent
+	}
+}
+
+// HTTPStatus tags err with the class of a downstream HTTP status.
+func HTTPStatus(status int, err error) error {
+	return Wrap(StatusClass(status), err)
+}
+
+// sqlStateClass classifies a PostgreSQL error code by its class: conflicts
+// between transactions and resource exhaustion pass, a lost connection or a
+// shutting-down server is the database's fault, and the rest are bad
+// queries or data.
+func sqlStateClass(code string) Class {
+	switch {
+	case code == "40001", code == "40P01", code == "55P03", strings.HasPrefix(code, "53"):
+		return ClassTransient
+	case strings.HasPrefix(code, "08"), strings.HasPrefix(code, "57P"), strings.HasPrefix(code, "58"):
+		return ClassDependency
+	default:
+		return ClassPermanent
+	}
+}
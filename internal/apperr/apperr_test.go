@@ -0,0 +1,65 @@
+// Copyright 2026 the P&AI authors. All rights reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package apperr
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"testing"
+)
+
+type pgError struct{ code string }
+
+func (e *pgError) Error() string    { return "pg " + e.code }
+func (e *pgError) SQLState() string { return e.code }
+
+func TestClassOf(t *testing.T) {
+	base := errors.New("boom")
+	tests := []struct {
+		name string
+		err  error
+		want Class
+	}{
+		{name: "nil", err: nil, want: ""},
+		{name: "unclassified", err: base, want: ClassUnknown},
+		{name: "wrapped", err: fmt.Errorf("load: %w", UserInput(base)), want: ClassUserInput},
+		{name: "outermost wins", err: Permanent(fmt.Errorf("retry gave up: %w", Transient(base))), want: ClassPermanent},
+		{name: "deadline", err: fmt.Errorf("call: %w", context.DeadlineExceeded), want: ClassTransient},
+		{name: "serialization failure", err: &pgError{code: "40001"}, want: ClassTransient},
+		{name: "connection lost", err: &pgError{code: "08006"}, want: ClassDependency},
+		{name: "unique violation", err: &pgError{code: "23505"}, want: ClassPermanent},
+		{name: "connection refused", err: &net.OpError{Op: "dial", Err: errors.New("connection refused")}, want: ClassDependency},
+		{name: "http 429", err: HTTPStatus(http.StatusTooManyRequests, base), want: ClassTransient},
+		{name: "http 401", err: HTTPStatus(http.StatusUnauthorized, base), want: ClassDependency},
+		{name: "http 501", err: HTTPStatus(http.StatusNotImplemented, base), want: ClassDependency},
+		{name: "http 404", err: HTTPStatus(http.StatusNotFound, base), want: ClassPermanent},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := ClassOf(tt.err); got != tt.want {
+				t.Fatalf("ClassOf(%v) = %q, want %q", tt.err, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestWrapKeepsErrorTextAndIdentity(t *testing.T) {
+	if Transient(nil) != nil || WithMessage(nil, "x") != nil {
+		t.Fatal("wrapping nil should return nil")
+	}
+	base := errors.New("rate limited")
+	err := fmt.Errorf("openai: %w", Transient(base))
+	if err.Error() != "openai: rate limited" {
+		t.Fatalf("Error() = %q", err.Error())
+	}
+	if !errors.Is(err, base) {
+		t.Fatal("errors.Is should see through the class")
+	}
+	if !Retryable(err) || Retryable(Permanent(base)) {
+		t.Fatal("only transient errors are retryable")
+	}
+}
@@ -0,0 +1,50 @@
+// Copyright 2026 the P&AI authors. All rights reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package apperr
+
+import (
+	"errors"
+
+	"github.com/p-n-ai/pai-bot/internal/i18n"
+)
+
+// Entry is how one class of error is surfaced.
+type Entry struct {
+	// Message is the localized reply the user sees.
+	Message i18n.Key
+	// EventType is the analytics event that records the failure.
+	EventType string
+}
+
+var catalog = map[Class]Entry{
+	ClassUnknown:    {Message: i18n.MsgTechnicalIssue, EventType: "error_unknown"},
+	ClassTransient:  {Message: i18n.MsgServerBusy, EventType: "error_transient"},
+	ClassPermanent:  {Message: i18n.MsgTechnicalIssue, EventType: "error_permanent"},
+	ClassDependency: {Message: i18n.MsgTechnicalIssue, EventType: "error_dependency"},
+	ClassUserInput:  {Message: i18n.MsgInvalidInput, EventType: "error_user_input"},
+	ClassSafety:     {Message: i18n.MsgSafetyBlocked, EventType: "error_safety"},
+}
+
+// Lookup returns the catalog entry for class, falling back to ClassUnknown's.
+func Lookup(class Class) Entry {
+	if entry, ok := catalog[class]; ok {
+		return entry
+	}
+	return catalog[ClassUnknown]
+}
+
+// UserMessage returns what the user is told about err in locale: the
+// message set by WithMessage, or else its class's catalog message.
+func UserMessage(locale string, err error) string {
+	var e *Error
+	if errors.As(err, &e) && e.Message != "" {
+		return i18n.S(locale, e.Message)
+	}
+	return i18n.S(locale, Lookup(ClassOf(err)).Message)
+}
+
+// EventType returns the analytics event type for err's class.
+func EventType(err error) string {
+	return Lookup(ClassOf(err)).EventType
+}
@@ -0,0 +1,53 @@
+// Copyright 2026 the P&AI authors. All rights reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package apperr
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/p-n-ai/pai-bot/internal/i18n"
+)
+
+func TestCatalogCoversEveryClassInEveryLocale(t *testing.T) {
+	events := map[string]Class{}
+	for _, class := range Classes() {
+		entry, ok := catalog[class]
+		if !ok {
+			t.Fatalf("class %q has no catalog entry", class)
+		}
+		if prev, dup := events[entry.EventType]; dup {
+			t.Fatalf("classes %q and %q share event type %q", prev, class, entry.EventType)
+		}
+		events[entry.EventType] = class
+		for _, locale := range []string{"en", "ms", "zh"} {
+			if msg := i18n.S(locale, entry.Message); msg == string(entry.Message) {
+				t.Fatalf("%s message %q is missing for %s", class, entry.Message, locale)
+			}
+		}
+	}
+}
+
+func TestUserMessage(t *testing.T) {
+	base := errors.New("boom")
+	if got, want := UserMessage("en", fmt.Errorf("ai: %w", Transient(base))), i18n.S("en", i18n.MsgServerBusy); got != want {
+		t.Fatalf("transient message = %q, want %q", got, want)
+	}
+	if got, want := UserMessage("en", base), i18n.S("en", i18n.MsgTechnicalIssue); got != want {
+		t.Fatalf("unknown message = %q, want %q", got, want)
+	}
+
+	err := WithMessage(UserInput(base), i18n.MsgLinkInvalid)
+	if got, want := UserMessage("ms", err), i18n.S("ms", i18n.MsgLinkInvalid); got != want {
+		t.Fatalf("override message = %q, want %q", got, want)
+	}
+	if ClassOf(err) != ClassUserInput || EventType(err) != "error_user_input" {
+		t.Fatalf("WithMessage changed the class: %q, %q", ClassOf(err), EventType(err))
+	}
+	if !strings.HasPrefix(EventType(Safety(base)), "error_") {
+		t.Fatalf("EventType(safety) = %q", EventType(Safety(base)))
+	}
+}
@@ -11,6 +11,7 @@ import (
 	"errors"
 	"fmt"
 	"strings"
+	"sync"
 	"time"
 )
 
@@ -48,17 +49,55 @@ type tokenPayload struct {
 	ExpiresAt int64  `json:"exp"`
 }
 
+// SigningSecret is an HS256 secret that can be rotated while tokens signed
+// with it are still in use. After Rotate, new tokens are signed with the new
+// secret and tokens signed with the one before it keep verifying until they
+// expire, so rotation doesn't sign anyone out.
+type SigningSecret struct {
+	mu       sync.RWMutex
+	current  []byte
+	previous []byte
+}
+
+func NewSigningSecret(secret string) *SigningSecret {
+	return &SigningSecret{current: []byte(secret)}
+}
+
+// Rotate makes next the signing secret. Rotating to the current secret is a
+// no-op, so callers can rotate on every reload.
+func (s *SigningSecret) Rotate(next string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if next == string(s.current) {
+		return
+	}
+	s.previous = s.current
+	s.current = []byte(next)
+}
+
+func (s *SigningSecret) secrets() (current, previous []byte) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.current, s.previous
+}
+
 type TokenManager struct {
-	secret []byte
+	secret *SigningSecret
 	ttl    time.Duration
 }
 
 func NewTokenManager(secret string, ttl time.Duration) *TokenManager {
+	return NewRotatingTokenManager(NewSigningSecret(secret), ttl)
+}
+
+// NewRotatingTokenManager returns a TokenManager that follows secret's
+// rotations.
+func NewRotatingTokenManager(secret *SigningSecret, ttl time.Duration) *TokenManager {
 	if ttl <= 0 {
 		ttl = defaultTokenTTL
 	}
 	return &TokenManager{
-		secret: []byte(secret),
+		secret: secret,
 		ttl:    ttl,
 	}
 }
@@ -70,7 +109,8 @@ func (m *TokenManager) Issue(claims TokenClaims, now time.Time) (string, error)
 	if claims.Role != RolePlatformAdmin && strings.TrimSpace(claims.TenantID) == "" {
 		return "", fmt.Errorf("issue token: %w", ErrInvalidToken)
 	}
-	if len(m.secret) == 0 {
+	secret, _ := m.secret.secrets()
+	if len(secret) == 0 {
 		return "", fmt.Errorf("issue token: %w", ErrInvalidToken)
 	}
 
@@ -94,7 +134,7 @@ func (m *TokenManager) Issue(claims TokenClaims, now time.Time) (string, error)
 	}
 
 	unsigned := encodeSegment(headerJSON) + "." + encodeSegment(payloadJSON)
-	signature := signHS256(unsigned, m.secret)
+	signature := signHS256(unsigned, secret)
 	return unsigned + "." + encodeSegment(signature), nil
 }
 
@@ -131,7 +171,9 @@ func (m *TokenManager) Parse(token string, now time.Time) (TokenClaims, error) {
 	}
 
 	unsigned := parts[0] + "." + parts[1]
-	if !hmac.Equal(signature, signHS256(unsigned, m.secret)) {
+	current, previous := m.secret.secrets()
+	if !hmac.Equal(signature, signHS256(unsigned, current)) &&
+		(len(previous) == 0 || !hmac.Equal(signature, signHS256(unsigned, previous))) {
 		return TokenClaims{}, ErrInvalidToken
 	}
 
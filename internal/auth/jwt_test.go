@@ -161,3 +161,33 @@ func TestTokenManagerRejectsNonPlatformRoleWithoutTenant(t *testing.T) {
 		t.Fatalf("Issue() error = %v, want %v", err, ErrInvalidToken)
 	}
 }
+
+func TestTokenManagerFollowsSecretRotation(t *testing.T) {
+	t.Parallel()
+
+	secret := NewSigningSecret("secret-2026a")
+	manager := NewRotatingTokenManager(secret, time.Minute)
+	now := time.Date(2026, 10, 17, 10, 0, 0, 0, time.UTC)
+	claims := TokenClaims{Subject: "user-123", TenantID: "tenant-abc", Role: RoleTeacher}
+
+	before, err := manager.Issue(claims, now)
+	if err != nil {
+		t.Fatalf("Issue() error = %v", err)
+	}
+	secret.Rotate("secret-2026b")
+	after, err := manager.Issue(claims, now)
+	if err != nil {
+		t.Fatalf("Issue() after rotation error = %v", err)
+	}
+	if _, err := NewTokenManager("secret-2026b", time.Minute).Parse(after, now); err != nil {
+		t.Fatalf("token issued after rotation not signed with the new secret: %v", err)
+	}
+	if _, err := manager.Parse(before, now); err != nil {
+		t.Fatalf("Parse(token signed before rotation) error = %v", err)
+	}
+
+	secret.Rotate("secret-2026c")
+	if _, err := manager.Parse(before, now); !errors.Is(err, ErrInvalidToken) {
+		t.Fatalf("Parse(token two rotations old) error = %v, want ErrInvalidToken", err)
+	}
+}
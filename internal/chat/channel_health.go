@@ -0,0 +1,250 @@
+// Copyright 2026 the P&AI authors. All rights reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package chat
+
+import (
+	"context"
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"slices"
+	"sync"
+	"time"
+)
+
+// DefaultChannelRestartThreshold is how many consecutive receive-loop
+// failures make the supervisor restart a channel.
+const DefaultChannelRestartThreshold = 5
+
+// ChannelHealth is a point-in-time view of one registered channel.
+type ChannelHealth struct {
+	Channel         string     `json:"channel"`
+	Healthy         bool       `json:"healthy"`
+	LastReceivedAt  *time.Time `json:"last_received_at,omitempty"`
+	LastSentAt      *time.Time `json:"last_sent_at,omitempty"`
+	LastSendError   string     `json:"last_send_error,omitempty"`
+	LastSendErrorAt *time.Time `json:"last_send_error_at,omitempty"`
+	// LoopFailures counts consecutive receive-loop failures (polling errors,
+	// socket keepalive timeouts) since the loop last succeeded.
+	LoopFailures  int    `json:"loop_failures"`
+	LastLoopError string `json:"last_loop_error,omitempty"`
+	// WebhookVerified is only set for webhook channels.
+	WebhookVerified *bool      `json:"webhook_verified,omitempty"`
+	Restarts        int        `json:"restarts"`
+	LastRestartAt   *time.Time `json:"last_restart_at,omitempty"`
+}
+
+// LoopHealthReporter is implemented by channels with a receive loop. The
+// gateway installs report on Register; the loop calls it with nil after each
+// successful cycle and with the error after each failed one.
+type LoopHealthReporter interface {
+	SetLoopHealthReporter(report func(error))
+}
+
+// RestartableChannel can tear down and restart its receive loop in place.
+type RestartableChannel interface {
+	Restart(ctx context.Context, handler func(InboundMessage)) error
+}
+
+// WebhookChannel reports whether the platform has reached its webhook,
+// either through the verification handshake or a delivered update.
+type WebhookChannel interface {
+	WebhookVerified() bool
+}
+
+type channelHealthState struct {
+	lastReceivedAt  time.Time
+	lastSentAt      time.Time
+	lastSendError   string
+	lastSendErrorAt time.Time
+	loopFailures    int
+	lastLoopError   string
+	restarts        int
+	lastRestartAt   time.Time
+}
+
+// channelHealth tracks every registered channel; the zero value is ready.
+type channelHealth struct {
+	mu     sync.Mutex
+	states map[string]*channelHealthState
+}
+
+// update runs fn on name's state under the lock.
+func (h *channelHealth) update(name string, fn func(*channelHealthState)) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if h.states == nil {
+		h.states = make(map[string]*channelHealthState)
+	}
+	state := h.states[name]
+	if state == nil {
+		state = &channelHealthState{}
+		h.states[name] = state
+	}
+	fn(state)
+}
+
+func (h *channelHealth) recordReceived(name string, at time.Time) {
+	h.update(name, func(s *channelHealthState) { s.lastReceivedAt = at })
+}
+
+func (h *channelHealth) recordSend(name string, err error, at time.Time) {
+	h.update(name, func(s *channelHealthState) {
+		if err != nil {
+			s.lastSendError = err.Error()
+			s.lastSendErrorAt = at
+			return
+		}
+		s.lastSentAt = at
+	})
+}
+
+func (h *channelHealth) recordLoop(name string, err error) {
+	h.update(name, func(s *channelHealthState) {
+		if err == nil {
+			s.loopFailures = 0
+			return
+		}
+		s.loopFailures++
+		s.lastLoopError = err.Error()
+	})
+}
+
+// RecordInbound marks an inbound message on its channel. StartAll wraps
+// its handler with it; webhook handlers mounted outside StartAll should
+// pass their handler through TrackInbound.
+func (g *Gateway) RecordInbound(msg InboundMessage) {
+	g.health.recordReceived(msg.Channel, time.Now())
+}
+
+// TrackInbound wraps handler so every message it sees is recorded as
+// received on its channel.
+func (g *Gateway) TrackInbound(handler func(InboundMessage)) func(InboundMessage) {
+	return func(msg InboundMessage) {
+		g.RecordInbound(msg)
+		handler(msg)
+	}
+}
+
+// ChannelHealth reports every registered channel, sorted by name.
+func (g *Gateway) ChannelHealth() []ChannelHealth {
+	g.mu.RLock()
+	channels := make(map[string]Channel, len(g.channels))
+	for name, ch := range g.channels {
+		channels[name] = ch
+	}
+	g.mu.RUnlock()
+
+	g.health.mu.Lock()
+	defer g.health.mu.Unlock()
+	out := make([]ChannelHealth, 0, len(channels))
+	for name, ch := range channels {
+		report := ChannelHealth{Channel: name}
+		if s := g.health.states[name]; s != nil {
+			report.LastReceivedAt = timePtr(s.lastReceivedAt)
+			report.LastSentAt = timePtr(s.lastSentAt)
+			report.LastSendError = s.lastSendError
+			report.LastSendErrorAt = timePtr(s.lastSendErrorAt)
+			report.LoopFailures = s.loopFailures
+			report.LastLoopError = s.lastLoopError
+			report.Restarts = s.restarts
+			report.LastRestartAt = timePtr(s.lastRestartAt)
+		}
+		if wc, ok := ch.(WebhookChannel); ok {
+			verified := wc.WebhookVerified()
+			report.WebhookVerified = &verified
+		}
+		// A send failure counts until a later send succeeds.
+		sendFailing := report.LastSendErrorAt != nil && (report.LastSentAt == nil || report.LastSentAt.Before(*report.LastSendErrorAt))
+		report.Healthy = report.LoopFailures == 0 && !sendFailing
+		out = append(out, report)
+	}
+	slices.SortFunc(out, func(a, b ChannelHealth) int {
+		if a.Channel < b.Channel {
+			return -1
+		}
+		if a.Channel > b.Channel {
+			return 1
+		}
+		return 0
+	})
+	return out
+}
+
+// ChannelHealthHandler serves ChannelHealth as JSON.
+func (g *Gateway) ChannelHealthHandler() http.Handler {
+	return http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+		rw.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(rw).Encode(map[string]any{"channels": g.ChannelHealth()})
+	})
+}
+
+// SuperviseChannels restarts failing channels every interval. Blocks until
+// ctx is cancelled.
+func (g *Gateway) SuperviseChannels(ctx context.Context, interval time.Duration, threshold int) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			g.RestartFailingChannels(ctx, threshold)
+		}
+	}
+}
+
+// RestartFailingChannels restarts every restartable channel whose receive
+// loop has failed at least threshold times in a row, and returns how many
+// it restarted. Channels are restarted with the handler given to StartAll.
+func (g *Gateway) RestartFailingChannels(ctx context.Context, threshold int) int {
+	if threshold <= 0 {
+		threshold = DefaultChannelRestartThreshold
+	}
+	g.mu.RLock()
+	handler := g.inbound
+	var failing []string
+	g.health.mu.Lock()
+	for name := range g.channels {
+		if s := g.health.states[name]; s != nil && s.loopFailures >= threshold {
+			failing = append(failing, name)
+		}
+	}
+	g.health.mu.Unlock()
+	channels := make(map[string]Channel, len(failing))
+	for _, name := range failing {
+		channels[name] = g.channels[name]
+	}
+	g.mu.RUnlock()
+	if handler == nil {
+		return 0
+	}
+
+	restarted := 0
+	for name, ch := range channels {
+		rc, ok := ch.(RestartableChannel)
+		if !ok {
+			continue
+		}
+		slog.Warn("restarting failing channel", "channel", name)
+		if err := rc.Restart(ctx, handler); err != nil {
+			slog.Error("channel restart failed", "channel", name, "error", err)
+			continue
+		}
+		g.health.update(name, func(s *channelHealthState) {
+			s.loopFailures = 0
+			s.restarts++
+			s.lastRestartAt = time.Now()
+		})
+		restarted++
+	}
+	return restarted
+}
+
+func timePtr(t time.Time) *time.Time {
+	if t.IsZero() {
+		return nil
+	}
+	return &t
+}
@@ -0,0 +1,106 @@
+// Copyright 2026 the P&AI authors. All rights reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package chat_test
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+
+	"github.com/p-n-ai/pai-bot/internal/chat"
+)
+
+// loopChannel is a channel whose receive loop health the test drives.
+type loopChannel struct {
+	chat.MockChannel
+	mu       sync.Mutex
+	report   func(error)
+	restarts int
+	handler  func(chat.InboundMessage)
+}
+
+func (c *loopChannel) Start(_ context.Context, handler func(chat.InboundMessage)) error {
+	c.handler = handler
+	return nil
+}
+
+func (c *loopChannel) SetLoopHealthReporter(report func(error)) { c.report = report }
+
+func (c *loopChannel) Restart(_ context.Context, handler func(chat.InboundMessage)) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.restarts++
+	c.handler = handler
+	return nil
+}
+
+func healthOf(t *testing.T, gw *chat.Gateway, name string) chat.ChannelHealth {
+	t.Helper()
+	for _, h := range gw.ChannelHealth() {
+		if h.Channel == name {
+			return h
+		}
+	}
+	t.Fatalf("no health for channel %q", name)
+	return chat.ChannelHealth{}
+}
+
+func TestGateway_ChannelHealthTracksReceiveAndSend(t *testing.T) {
+	gw := chat.NewGateway()
+	ch := &loopChannel{}
+	gw.Register("telegram", ch)
+	gw.Register("whatsapp", &failingChannel{err: errors.New("bot was blocked by the user")})
+	if err := gw.StartAll(context.Background(), func(chat.InboundMessage) {}); err != nil {
+		t.Fatal(err)
+	}
+
+	if h := healthOf(t, gw, "telegram"); !h.Healthy || h.LastReceivedAt != nil || h.LastSentAt != nil {
+		t.Fatalf("fresh health = %+v, want healthy with nothing seen", h)
+	}
+	ch.handler(chat.InboundMessage{Channel: "telegram", UserID: "42"})
+	_ = gw.Send(context.Background(), chat.OutboundMessage{Channel: "telegram", UserID: "42", Text: "hi"})
+	if h := healthOf(t, gw, "telegram"); h.LastReceivedAt == nil || h.LastSentAt == nil {
+		t.Fatalf("health = %+v, want receive and send recorded", h)
+	}
+
+	_ = gw.Send(context.Background(), chat.OutboundMessage{Channel: "whatsapp", UserID: "60123", Text: "hi"})
+	if h := healthOf(t, gw, "whatsapp"); h.Healthy || h.LastSendError == "" {
+		t.Fatalf("health = %+v, want the failed send to mark it unhealthy", h)
+	}
+}
+
+func TestGateway_RestartFailingChannelsAfterThreshold(t *testing.T) {
+	gw := chat.NewGateway()
+	ch := &loopChannel{}
+	gw.Register("telegram", ch)
+	if err := gw.StartAll(context.Background(), func(chat.InboundMessage) {}); err != nil {
+		t.Fatal(err)
+	}
+
+	for range 2 {
+		ch.report(errors.New("getUpdates: connection reset"))
+	}
+	if n := gw.RestartFailingChannels(context.Background(), 3); n != 0 {
+		t.Fatalf("restarted %d below the threshold, want 0", n)
+	}
+	if h := healthOf(t, gw, "telegram"); h.Healthy || h.LoopFailures != 2 {
+		t.Fatalf("health = %+v, want 2 loop failures", h)
+	}
+
+	ch.report(errors.New("getUpdates: connection reset"))
+	if n := gw.RestartFailingChannels(context.Background(), 3); n != 1 || ch.restarts != 1 {
+		t.Fatalf("restarted %d (channel saw %d), want 1", n, ch.restarts)
+	}
+	h := healthOf(t, gw, "telegram")
+	if h.LoopFailures != 0 || h.Restarts != 1 || h.LastRestartAt == nil {
+		t.Fatalf("health after restart = %+v", h)
+	}
+
+	// The restarted loop still feeds the tracked handler.
+	ch.handler(chat.InboundMessage{Channel: "telegram", UserID: "42"})
+	if h := healthOf(t, gw, "telegram"); h.LastReceivedAt == nil {
+		t.Fatal("restarted channel lost inbound tracking")
+	}
+}
@@ -19,11 +19,21 @@ var RegisteredCommands = []BotCommand{
 	{Command: "language", Description: "Tukar bahasa (English/BM/中文)"},
 	{Command: "progress", Description: "Lihat kemajuan pembelajaran"},
 	{Command: "goal", Description: "Tetapkan matlamat pembelajaran"},
+	{Command: "plan", Description: "Jana pelan ulang kaji sehingga tarikh peperiksaan"},
 	{Command: "learn", Description: "Pilih topik untuk belajar"},
+	{Command: "next", Description: "Cadangan topik seterusnya untuk dipelajari"},
 	{Command: "create_group", Description: "Buat kumpulan belajar baru"},
 	{Command: "join", Description: "Sertai kumpulan dengan kod"},
 	{Command: "leaderboard", Description: "Papan pendahulu mingguan kumpulan"},
 	{Command: "challenge", Description: "Cabaran kuiz dengan rakan atau AI"},
+	{Command: "more", Description: "Sambung jawapan yang dipendekkan"},
+	{Command: "search", Description: "Cari mesej lama dalam perbualan anda"},
+	{Command: "link", Description: "Pautkan akaun untuk sambung perbualan di aplikasi lain"},
+	{Command: "feedback", Description: "Hantar maklum balas tentang jawapan bot"},
+	{Command: "subscribe", Description: "Lihat pelan langganan dan naik taraf ke Premium"},
+	{Command: "invite", Description: "Jemput rakan dan dapatkan XP"},
+	{Command: "daily", Description: "Soalan Hari Ini untuk tingkatan anda"},
+	{Command: "again", Description: "Terangkan jawapan terakhir dengan cara lain"},
 }
 
 // DevCommands are only shown when dev mode is enabled.
@@ -0,0 +1,201 @@
+// Copyright 2026 the P&AI authors. All rights reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package chat
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"slices"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/p-n-ai/pai-bot/internal/apperr"
+)
+
+// ErrNoDelayQueue is returned by SendAt when the gateway has no delay queue.
+var ErrNoDelayQueue = apperr.Permanent(errors.New("chat: no delay queue configured"))
+
+// delayQueueBatch caps how many due messages one dispatch pass claims.
+const delayQueueBatch = 100
+
+// ScheduledMessage is an outbound message waiting in a DelayQueue.
+type ScheduledMessage struct {
+	ID      string
+	Message OutboundMessage
+	SendAt  time.Time
+}
+
+// DelayQueue persists messages until they are due. ClaimDue removes the
+// messages it returns, so each is sent at most once even with several
+// gateways draining the same queue.
+type DelayQueue interface {
+	Enqueue(ctx context.Context, msg OutboundMessage, at time.Time) (string, error)
+	// Pending returns the user's queued messages, soonest first.
+	Pending(ctx context.Context, channel, userID string) ([]ScheduledMessage, error)
+	// CancelForUser drops every queued message for the user and reports how
+	// many were dropped.
+	CancelForUser(ctx context.Context, channel, userID string) (int, error)
+	ClaimDue(ctx context.Context, now time.Time, limit int) ([]ScheduledMessage, error)
+}
+
+// SetDelayQueue enables SendAt. Run RunDelayQueue to deliver due messages.
+func (g *Gateway) SetDelayQueue(q DelayQueue) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	g.delayQueue = q
+}
+
+// CanSendLater reports whether SendAt has a delay queue to write to.
+func (g *Gateway) CanSendLater() bool {
+	return g.queue() != nil
+}
+
+func (g *Gateway) queue() DelayQueue {
+	g.mu.RLock()
+	defer g.mu.RUnlock()
+	return g.delayQueue
+}
+
+// SendAt queues msg for delivery at at and returns the queued message ID.
+// A time that has already passed is sent on the next dispatch pass.
+func (g *Gateway) SendAt(ctx context.Context, msg OutboundMessage, at time.Time) (string, error) {
+	q := g.queue()
+	if q == nil {
+		return "", ErrNoDelayQueue
+	}
+	return q.Enqueue(ctx, msg, at)
+}
+
+// PendingSends returns the messages queued for the user, soonest first.
+func (g *Gateway) PendingSends(ctx context.Context, channel, userID string) ([]ScheduledMessage, error) {
+	q := g.queue()
+	if q == nil {
+		return nil, nil
+	}
+	return q.Pending(ctx, channel, userID)
+}
+
+// CancelScheduled drops the user's queued messages, e.g. once they become
+// active again and a check-in or reminder is no longer needed.
+func (g *Gateway) CancelScheduled(ctx context.Context, channel, userID string) (int, error) {
+	q := g.queue()
+	if q == nil {
+		return 0, nil
+	}
+	return q.CancelForUser(ctx, channel, userID)
+}
+
+// RunDelayQueue sends due queued messages every interval. Blocks until ctx
+// is cancelled.
+func (g *Gateway) RunDelayQueue(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case now := <-ticker.C:
+			g.DispatchDue(ctx, now)
+		}
+	}
+}
+
+// DispatchDue sends every queued message due at now and returns how many
+// were sent. Failed sends are recorded like any other and not retried.
+func (g *Gateway) DispatchDue(ctx context.Context, now time.Time) int {
+	q := g.queue()
+	if q == nil {
+		return 0
+	}
+	sent := 0
+	for {
+		due, err := q.ClaimDue(ctx, now, delayQueueBatch)
+		if err != nil {
+			slog.Error("failed to claim scheduled messages", "error", err)
+			return sent
+		}
+		for _, scheduled := range due {
+			if err := g.Send(ctx, scheduled.Message); err != nil {
+				slog.Warn("scheduled send failed",
+					"id", scheduled.ID,
+					"channel", scheduled.Message.Channel,
+					"user_id", scheduled.Message.UserID,
+					"error", err,
+				)
+				continue
+			}
+			sent++
+		}
+		if len(due) < delayQueueBatch {
+			return sent
+		}
+	}
+}
+
+// MemoryDelayQueue is an in-memory DelayQueue for tests and local runs.
+type MemoryDelayQueue struct {
+	mu       sync.Mutex
+	nextID   int
+	messages []ScheduledMessage
+}
+
+// NewMemoryDelayQueue creates an empty in-memory delay queue.
+func NewMemoryDelayQueue() *MemoryDelayQueue {
+	return &MemoryDelayQueue{}
+}
+
+func (q *MemoryDelayQueue) Enqueue(_ context.Context, msg OutboundMessage, at time.Time) (string, error) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	q.nextID++
+	id := strconv.Itoa(q.nextID)
+	q.messages = append(q.messages, ScheduledMessage{ID: id, Message: msg, SendAt: at})
+	return id, nil
+}
+
+func (q *MemoryDelayQueue) Pending(_ context.Context, channel, userID string) ([]ScheduledMessage, error) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	var pending []ScheduledMessage
+	for _, m := range q.messages {
+		if m.Message.Channel == channel && m.Message.UserID == userID {
+			pending = append(pending, m)
+		}
+	}
+	sortBySendAt(pending)
+	return pending, nil
+}
+
+func (q *MemoryDelayQueue) CancelForUser(_ context.Context, channel, userID string) (int, error) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	before := len(q.messages)
+	q.messages = slices.DeleteFunc(q.messages, func(m ScheduledMessage) bool {
+		return m.Message.Channel == channel && m.Message.UserID == userID
+	})
+	return before - len(q.messages), nil
+}
+
+func (q *MemoryDelayQueue) ClaimDue(_ context.Context, now time.Time, limit int) ([]ScheduledMessage, error) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	sortBySendAt(q.messages)
+	var due []ScheduledMessage
+	q.messages = slices.DeleteFunc(q.messages, func(m ScheduledMessage) bool {
+		if len(due) >= limit || m.SendAt.After(now) {
+			return false
+		}
+		due = append(due, m)
+		return true
+	})
+	return due, nil
+}
+
+func sortBySendAt(messages []ScheduledMessage) {
+	slices.SortStableFunc(messages, func(a, b ScheduledMessage) int {
+		return a.SendAt.Compare(b.SendAt)
+	})
+}
@@ -0,0 +1,81 @@
+// Copyright 2026 the P&AI authors. All rights reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package chat_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/p-n-ai/pai-bot/internal/chat"
+)
+
+func TestGateway_SendAtWithoutDelayQueue(t *testing.T) {
+	gw := chat.NewGateway()
+	if gw.CanSendLater() {
+		t.Fatal("CanSendLater() = true without a delay queue")
+	}
+	_, err := gw.SendAt(context.Background(), chat.OutboundMessage{Channel: "telegram", UserID: "u1", Text: "Hi"}, time.Now())
+	if !errors.Is(err, chat.ErrNoDelayQueue) {
+		t.Fatalf("SendAt() error = %v, want ErrNoDelayQueue", err)
+	}
+}
+
+func TestGateway_DispatchDueSendsOnlyDueMessages(t *testing.T) {
+	ctx := context.Background()
+	mock := &chat.MockChannel{}
+	gw := chat.NewGateway()
+	gw.Register("telegram", mock)
+	gw.SetDelayQueue(chat.NewMemoryDelayQueue())
+
+	now := time.Date(2026, 10, 18, 7, 0, 0, 0, time.UTC)
+	for _, m := range []struct {
+		text string
+		at   time.Time
+	}{
+		{"later", now.Add(time.Hour)},
+		{"second", now.Add(-time.Minute)},
+		{"first", now.Add(-time.Hour)},
+	} {
+		if _, err := gw.SendAt(ctx, chat.OutboundMessage{Channel: "telegram", UserID: "u1", Text: m.text}, m.at); err != nil {
+			t.Fatalf("SendAt(%s) error = %v", m.text, err)
+		}
+	}
+
+	if sent := gw.DispatchDue(ctx, now); sent != 2 {
+		t.Fatalf("DispatchDue() = %d, want 2", sent)
+	}
+	if len(mock.SentMessages) != 2 || mock.SentMessages[0].Text != "first" || mock.SentMessages[1].Text != "second" {
+		t.Fatalf("sent = %+v, want first then second", mock.SentMessages)
+	}
+	if sent := gw.DispatchDue(ctx, now); sent != 0 {
+		t.Fatalf("second DispatchDue() = %d, want 0: due messages are sent once", sent)
+	}
+	pending, _ := gw.PendingSends(ctx, "telegram", "u1")
+	if len(pending) != 1 || pending[0].Message.Text != "later" {
+		t.Fatalf("pending = %+v, want only the later message", pending)
+	}
+}
+
+func TestGateway_CancelScheduledDropsOnlyThatUser(t *testing.T) {
+	ctx := context.Background()
+	mock := &chat.MockChannel{}
+	gw := chat.NewGateway()
+	gw.Register("telegram", mock)
+	gw.SetDelayQueue(chat.NewMemoryDelayQueue())
+
+	at := time.Now().Add(-time.Second)
+	_, _ = gw.SendAt(ctx, chat.OutboundMessage{Channel: "telegram", UserID: "u1", Text: "check-in"}, at)
+	_, _ = gw.SendAt(ctx, chat.OutboundMessage{Channel: "telegram", UserID: "u2", Text: "nudge"}, at)
+
+	dropped, err := gw.CancelScheduled(ctx, "telegram", "u1")
+	if err != nil || dropped != 1 {
+		t.Fatalf("CancelScheduled() = %d, %v; want 1, nil", dropped, err)
+	}
+	gw.DispatchDue(ctx, time.Now())
+	if len(mock.SentMessages) != 1 || mock.SentMessages[0].UserID != "u2" {
+		t.Fatalf("sent = %+v, want only u2's message", mock.SentMessages)
+	}
+}
@@ -0,0 +1,95 @@
+// Copyright 2026 the P&AI authors. All rights reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package chat
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/p-n-ai/pai-bot/internal/apperr"
+)
+
+// DeliveryStatus is the outcome of one outbound send.
+type DeliveryStatus string
+
+const (
+	DeliverySent        DeliveryStatus = "sent"
+	DeliveryFailed      DeliveryStatus = "failed"
+	DeliveryRateLimited DeliveryStatus = "rate_limited"
+	// DeliveryBlocked means the user blocked the bot or deleted their
+	// account; further sends fail until they message us again.
+	DeliveryBlocked DeliveryStatus = "blocked"
+)
+
+// APIError is a non-OK response from a channel's send API.
+type APIError struct {
+	Channel     string
+	Code        int
+	Description string
+}
+
+func (e *APIError) Error() string {
+	if e.Description == "" {
+		return fmt.Sprintf("%s API error %d", e.Channel, e.Code)
+	}
+	return fmt.Sprintf("%s API error %d: %s", e.Channel, e.Code, e.Description)
+}
+
+// ErrorClass classifies the send by its status. A user who blocked the bot
+// is permanent: resending fails until they message us again.
+func (e *APIError) ErrorClass() apperr.Class {
+	if DeliveryStatusOf(e) == DeliveryBlocked {
+		return apperr.ClassPermanent
+	}
+	return apperr.StatusClass(e.Code)
+}
+
+// DeliveryStatusOf classifies the error a send returned.
+func DeliveryStatusOf(err error) DeliveryStatus {
+	if err == nil {
+		return DeliverySent
+	}
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) {
+		return DeliveryFailed
+	}
+	switch {
+	case apiErr.Code == http.StatusTooManyRequests:
+		return DeliveryRateLimited
+	case apiErr.Code == http.StatusForbidden && isBlockedDescription(apiErr.Description):
+		return DeliveryBlocked
+	default:
+		return DeliveryFailed
+	}
+}
+
+// isBlockedDescription matches Telegram's 403 descriptions for a user who
+// blocked the bot or whose account is gone.
+func isBlockedDescription(desc string) bool {
+	desc = strings.ToLower(desc)
+	return strings.Contains(desc, "blocked") || strings.Contains(desc, "deactivated")
+}
+
+// DeliveryRecord is the stored outcome of one outbound message.
+type DeliveryRecord struct {
+	Channel string
+	UserID  string
+	Status  DeliveryStatus
+	Error   string
+	At      time.Time
+}
+
+// AccountDeactivated reports whether a blocked delivery failed because the
+// user's account is gone rather than because they blocked the bot.
+func (r DeliveryRecord) AccountDeactivated() bool {
+	return r.Status == DeliveryBlocked && strings.Contains(strings.ToLower(r.Error), "deactivated")
+}
+
+// DeliveryRecorder stores delivery outcomes reported by the Gateway.
+type DeliveryRecorder interface {
+	RecordDelivery(rec DeliveryRecord) error
+}
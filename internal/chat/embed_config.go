@@ -12,10 +12,12 @@ import (
 
 	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgxpool"
+
+	"github.com/p-n-ai/pai-bot/internal/apperr"
 )
 
 // ErrEmbedNotConfigured is returned when no embed config exists for a given tenant/origin combination.
-var ErrEmbedNotConfigured = errors.New("embed not configured for tenant")
+var ErrEmbedNotConfigured = apperr.UserInput(errors.New("embed not configured for tenant"))
 
 // EmbedConfig holds the web-embed configuration for a tenant.
 type EmbedConfig struct {
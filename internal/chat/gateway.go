@@ -9,6 +9,7 @@ import (
 	"fmt"
 	"log/slog"
 	"sync"
+	"time"
 )
 
 // InboundMessage is a message received from any channel.
@@ -30,6 +31,33 @@ type InboundMessage struct {
 	CallbackQueryID string
 	// CallbackMessageID is the Telegram message ID that contains the clicked inline button.
 	CallbackMessageID int
+	// MessageID is the channel-native ID of this message, when the channel has one.
+	MessageID string
+	// Edited marks a user edit of the earlier message MessageID; Text holds the new content.
+	Edited bool
+	// DeletedMessageIDs lists channel-native message IDs the user deleted.
+	DeletedMessageIDs []string
+	// Reaction is ReactionThumbsUp or ReactionThumbsDown when the user reacted
+	// to the bot message MessageID.
+	Reaction string
+}
+
+// Reactions a channel maps onto answer feedback.
+const (
+	ReactionThumbsUp   = "thumbs_up"
+	ReactionThumbsDown = "thumbs_down"
+)
+
+// IsRevision reports whether the message edits or deletes earlier history
+// instead of starting a new turn.
+func (m InboundMessage) IsRevision() bool {
+	return m.Edited || len(m.DeletedMessageIDs) > 0
+}
+
+// ExpectsReply reports whether the message starts a normal turn. Revisions
+// and reactions are recorded silently; an edit may still earn a revised answer.
+func (m InboundMessage) ExpectsReply() bool {
+	return !m.IsRevision() && m.Reaction == ""
 }
 
 type InlineButton struct {
@@ -44,14 +72,34 @@ type OutboundMessage struct {
 	UserID         string
 	Text           string
 	FocusedPageURL string
-	ParseMode      string // "Markdown", "HTML", or ""
+	// Photo is a PNG sent before Text on channels that SupportsPhotos.
+	Photo     []byte
+	ParseMode string // "Markdown", "HTML", or ""
 	// ReplyKeyboard is Telegram-style keyboard rows. Other channels may ignore it.
 	ReplyKeyboard [][]string
 	// InlineKeyboard is Telegram inline keyboard rows. Other channels may ignore it.
 	InlineKeyboard [][]InlineButton
 }
 
-// Channel is the interface each messaging platform must implement.
+// SupportsPhotos reports whether channel delivers OutboundMessage.Photo.
+func SupportsPhotos(channel string) bool {
+	return channel == "telegram" || channel == "websocket"
+}
+
+// SendReceipt carries the channel-native IDs of the messages a send produced,
+// in order. It is empty when the channel does not report IDs.
+type SendReceipt struct {
+	MessageIDs []string
+}
+
+// ReceiptChannel is implemented by channels that can report sent message IDs.
+type ReceiptChannel interface {
+	SendMessageWithReceipt(ctx context.Context, userID string, msg OutboundMessage) (SendReceipt, error)
+}
+
+// Channel is the interface each messaging platform must implement. Start
+// calls handler from the channel's receive path, so the handler should hand
+// work off quickly, e.g. to an InboundPool.
 type Channel interface {
 	SendMessage(ctx context.Context, userID string, msg OutboundMessage) error
 	SendTyping(ctx context.Context, userID string) error
@@ -61,8 +109,13 @@ type Channel interface {
 
 // Gateway routes messages to/from registered channels.
 type Gateway struct {
-	channels map[string]Channel
-	mu       sync.RWMutex
+	channels   map[string]Channel
+	recorder   DeliveryRecorder
+	delayQueue DelayQueue
+	// inbound is the tracked handler from StartAll, reused on restarts.
+	inbound func(InboundMessage)
+	health  channelHealth
+	mu      sync.RWMutex
 }
 
 // NewGateway creates a new chat gateway.
@@ -77,9 +130,20 @@ func (g *Gateway) Register(name string, ch Channel) {
 	g.mu.Lock()
 	defer g.mu.Unlock()
 	g.channels[name] = ch
+	if reporter, ok := ch.(LoopHealthReporter); ok {
+		reporter.SetLoopHealthReporter(func(err error) { g.health.recordLoop(name, err) })
+	}
 	slog.Info("chat channel registered", "channel", name)
 }
 
+// SetDeliveryRecorder stores the outcome of every Send and SendWithReceipt
+// to a registered channel.
+func (g *Gateway) SetDeliveryRecorder(r DeliveryRecorder) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	g.recorder = r
+}
+
 // HasChannel returns true if the named channel is registered.
 func (g *Gateway) HasChannel(name string) bool {
 	g.mu.RLock()
@@ -109,7 +173,57 @@ func (g *Gateway) Send(ctx context.Context, msg OutboundMessage) error {
 		return fmt.Errorf("unknown channel: %s", msg.Channel)
 	}
 
-	return ch.SendMessage(ctx, msg.UserID, msg)
+	err := ch.SendMessage(ctx, msg.UserID, msg)
+	g.health.recordSend(msg.Channel, err, time.Now())
+	g.recordDelivery(msg, err)
+	return err
+}
+
+// SendWithReceipt dispatches a message and returns the sent message IDs when
+// the channel reports them.
+func (g *Gateway) SendWithReceipt(ctx context.Context, msg OutboundMessage) (SendReceipt, error) {
+	g.mu.RLock()
+	ch, ok := g.channels[msg.Channel]
+	g.mu.RUnlock()
+
+	if !ok {
+		return SendReceipt{}, fmt.Errorf("unknown channel: %s", msg.Channel)
+	}
+	var (
+		receipt SendReceipt
+		err     error
+	)
+	if rc, ok := ch.(ReceiptChannel); ok {
+		receipt, err = rc.SendMessageWithReceipt(ctx, msg.UserID, msg)
+	} else {
+		err = ch.SendMessage(ctx, msg.UserID, msg)
+	}
+	g.health.recordSend(msg.Channel, err, time.Now())
+	g.recordDelivery(msg, err)
+	return receipt, err
+}
+
+// recordDelivery hands the send outcome to the recorder. A failure to
+// record is logged and never fails the send.
+func (g *Gateway) recordDelivery(msg OutboundMessage, sendErr error) {
+	g.mu.RLock()
+	r := g.recorder
+	g.mu.RUnlock()
+	if r == nil {
+		return
+	}
+	rec := DeliveryRecord{
+		Channel: msg.Channel,
+		UserID:  msg.UserID,
+		Status:  DeliveryStatusOf(sendErr),
+		At:      time.Now(),
+	}
+	if sendErr != nil {
+		rec.Error = sendErr.Error()
+	}
+	if err := r.RecordDelivery(rec); err != nil {
+		slog.Warn("failed to record delivery", "channel", msg.Channel, "user_id", msg.UserID, "error", err)
+	}
 }
 
 // SendTyping sends a typing indicator to the user on the given channel.
@@ -125,11 +239,16 @@ func (g *Gateway) SendTyping(ctx context.Context, channel, userID string) error
 	return ch.SendTyping(ctx, userID)
 }
 
-// StartAll starts all registered channels with the given message handler.
+// StartAll starts all registered channels with the given message handler,
+// recording each inbound message for ChannelHealth.
 func (g *Gateway) StartAll(ctx context.Context, handler func(InboundMessage)) error {
+	g.mu.Lock()
+	handler = g.TrackInbound(handler)
+	g.inbound = handler
+	g.mu.Unlock()
+
 	g.mu.RLock()
 	defer g.mu.RUnlock()
-
 	for name, ch := range g.channels {
 		slog.Info("starting channel", "channel", name)
 		if err := ch.Start(ctx, handler); err != nil {
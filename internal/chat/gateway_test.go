@@ -54,6 +54,43 @@ func TestGateway_SendMessage(t *testing.T) {
 	}
 }
 
+type deliveryLog []chat.DeliveryRecord
+
+func (l *deliveryLog) RecordDelivery(rec chat.DeliveryRecord) error {
+	*l = append(*l, rec)
+	return nil
+}
+
+type failingChannel struct {
+	chat.MockChannel
+	err error
+}
+
+func (f *failingChannel) SendMessage(context.Context, string, chat.OutboundMessage) error {
+	return f.err
+}
+
+func TestGateway_RecordsDeliveryStatus(t *testing.T) {
+	gw := chat.NewGateway()
+	var log deliveryLog
+	gw.SetDeliveryRecorder(&log)
+	gw.Register("telegram", &chat.MockChannel{})
+	gw.Register("whatsapp", &failingChannel{err: &chat.APIError{Channel: "whatsapp", Code: 429}})
+
+	_ = gw.Send(context.Background(), chat.OutboundMessage{Channel: "telegram", UserID: "1", Text: "a"})
+	_, _ = gw.SendWithReceipt(context.Background(), chat.OutboundMessage{Channel: "whatsapp", UserID: "2", Text: "b"})
+
+	if len(log) != 2 {
+		t.Fatalf("records = %d, want 2", len(log))
+	}
+	if log[0].UserID != "1" || log[0].Status != chat.DeliverySent {
+		t.Errorf("record[0] = %+v, want sent to 1", log[0])
+	}
+	if log[1].UserID != "2" || log[1].Status != chat.DeliveryRateLimited || log[1].Error == "" {
+		t.Errorf("record[1] = %+v, want rate_limited to 2 with error", log[1])
+	}
+}
+
 func TestGateway_SendMessage_UnknownChannel(t *testing.T) {
 	gw := chat.NewGateway()
 
@@ -0,0 +1,196 @@
+// Copyright 2026 the P&AI authors. All rights reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package chat
+
+import (
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"net/http"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+const (
+	defaultInboundWorkers   = 32
+	defaultInboundQueueSize = 1000
+	// maxConcurrentShedNotices bounds apology sends during a burst, so
+	// shedding load does not itself start unbounded goroutines.
+	maxConcurrentShedNotices = 8
+)
+
+// InboundPoolConfig sizes an InboundPool.
+type InboundPoolConfig struct {
+	Workers   int
+	QueueSize int
+	// ShedNotice tells a learner their message was dropped because the queue
+	// was full. It is only called for messages that expect a reply.
+	ShedNotice func(InboundMessage)
+}
+
+// InboundPoolStats is a point-in-time view of an InboundPool.
+type InboundPoolStats struct {
+	Workers       int   `json:"workers"`
+	Busy          int64 `json:"busy"`
+	QueueDepth    int   `json:"queue_depth"`
+	QueueCapacity int   `json:"queue_capacity"`
+	Processed     int64 `json:"processed"`
+	Shed          int64 `json:"shed"`
+	// LastLagMS is how long the most recently started message waited in the
+	// queue; MaxLagMS is the longest wait seen since start.
+	LastLagMS int64 `json:"last_lag_ms"`
+	MaxLagMS  int64 `json:"max_lag_ms"`
+}
+
+type queuedInbound struct {
+	msg      InboundMessage
+	queuedAt time.Time
+}
+
+// InboundPool runs inbound messages on a fixed number of workers fed by a
+// bounded queue. Channels hand messages to Submit, which never blocks: when
+// the queue is full the message is shed and the learner is told to retry.
+type InboundPool struct {
+	cfg    InboundPoolConfig
+	handle func(InboundMessage)
+	queue  chan queuedInbound
+	notice chan struct{}
+	wg     sync.WaitGroup
+
+	mu     sync.RWMutex
+	closed bool
+
+	busy      atomic.Int64
+	processed atomic.Int64
+	shed      atomic.Int64
+	lastLag   atomic.Int64
+	maxLag    atomic.Int64
+}
+
+// NewInboundPool returns a pool that runs handle for each submitted message.
+// Call Start before submitting.
+func NewInboundPool(cfg InboundPoolConfig, handle func(InboundMessage)) (*InboundPool, error) {
+	if handle == nil {
+		return nil, errors.New("inbound pool handler is required")
+	}
+	if cfg.Workers <= 0 {
+		cfg.Workers = defaultInboundWorkers
+	}
+	if cfg.QueueSize <= 0 {
+		cfg.QueueSize = defaultInboundQueueSize
+	}
+	return &InboundPool{
+		cfg:    cfg,
+		handle: handle,
+		queue:  make(chan queuedInbound, cfg.QueueSize),
+		notice: make(chan struct{}, maxConcurrentShedNotices),
+	}, nil
+}
+
+// Start launches the workers.
+func (p *InboundPool) Start() {
+	for range p.cfg.Workers {
+		p.wg.Add(1)
+		go p.work()
+	}
+	slog.Info("inbound worker pool started", "workers", p.cfg.Workers, "queue_size", p.cfg.QueueSize)
+}
+
+// Submit queues msg for a worker. It returns false when msg was shed.
+func (p *InboundPool) Submit(msg InboundMessage) bool {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+	if p.closed {
+		return false
+	}
+	select {
+	case p.queue <- queuedInbound{msg: msg, queuedAt: time.Now()}:
+		return true
+	default:
+	}
+	p.Shed(msg)
+	return false
+}
+
+// Shed counts msg as dropped for lack of capacity and, when it expects a
+// reply, tells the learner to retry. Submit calls it when the queue is full;
+// stages past the pool that run out of room call it too, so every dropped
+// message shows in Stats and gets the same notice.
+func (p *InboundPool) Shed(msg InboundMessage) {
+	shed := p.shed.Add(1)
+	// Log the first drop of a burst and then every hundredth, not each one.
+	if shed == 1 || shed%100 == 0 {
+		slog.Warn("inbound queue full; shedding messages",
+			"queue_size", p.cfg.QueueSize, "shed_total", shed, "channel", msg.Channel)
+	}
+	if p.cfg.ShedNotice != nil && msg.ExpectsReply() {
+		select {
+		case p.notice <- struct{}{}:
+			go func() {
+				defer func() { <-p.notice }()
+				p.cfg.ShedNotice(msg)
+			}()
+		default:
+		}
+	}
+}
+
+// Handle is Submit without the result, for use as a channel handler.
+func (p *InboundPool) Handle(msg InboundMessage) {
+	p.Submit(msg)
+}
+
+// Close stops accepting messages and waits for queued ones to finish.
+func (p *InboundPool) Close() {
+	p.mu.Lock()
+	if p.closed {
+		p.mu.Unlock()
+		return
+	}
+	p.closed = true
+	close(p.queue)
+	p.mu.Unlock()
+	p.wg.Wait()
+}
+
+func (p *InboundPool) work() {
+	defer p.wg.Done()
+	for item := range p.queue {
+		lag := time.Since(item.queuedAt).Milliseconds()
+		p.lastLag.Store(lag)
+		for {
+			prev := p.maxLag.Load()
+			if lag <= prev || p.maxLag.CompareAndSwap(prev, lag) {
+				break
+			}
+		}
+		p.busy.Add(1)
+		p.handle(item.msg)
+		p.busy.Add(-1)
+		p.processed.Add(1)
+	}
+}
+
+// Stats reports queue depth, worker use and queueing lag.
+func (p *InboundPool) Stats() InboundPoolStats {
+	return InboundPoolStats{
+		Workers:       p.cfg.Workers,
+		Busy:          p.busy.Load(),
+		QueueDepth:    len(p.queue),
+		QueueCapacity: cap(p.queue),
+		Processed:     p.processed.Load(),
+		Shed:          p.shed.Load(),
+		LastLagMS:     p.lastLag.Load(),
+		MaxLagMS:      p.maxLag.Load(),
+	}
+}
+
+// StatsHandler serves Stats as JSON.
+func (p *InboundPool) StatsHandler() http.Handler {
+	return http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+		rw.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(rw).Encode(p.Stats())
+	})
+}
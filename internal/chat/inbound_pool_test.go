@@ -0,0 +1,128 @@
+// Copyright 2026 the P&AI authors. All rights reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package chat
+
+import (
+	"encoding/json"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestInboundPoolRunsOnBoundedWorkers(t *testing.T) {
+	var mu sync.Mutex
+	running, peak, done := 0, 0, 0
+	pool, err := NewInboundPool(InboundPoolConfig{Workers: 3, QueueSize: 50}, func(InboundMessage) {
+		mu.Lock()
+		running++
+		peak = max(peak, running)
+		mu.Unlock()
+		time.Sleep(5 * time.Millisecond)
+		mu.Lock()
+		running--
+		done++
+		mu.Unlock()
+	})
+	if err != nil {
+		t.Fatalf("NewInboundPool() error = %v", err)
+	}
+	pool.Start()
+	for i := range 20 {
+		if !pool.Submit(InboundMessage{Channel: "telegram", UserID: "u", Text: string(rune('a' + i))}) {
+			t.Fatalf("Submit(%d) shed with room in the queue", i)
+		}
+	}
+	pool.Close()
+
+	if done != 20 {
+		t.Errorf("handled %d messages, want 20", done)
+	}
+	if peak > 3 {
+		t.Errorf("peak concurrency = %d, want at most 3 workers", peak)
+	}
+	if stats := pool.Stats(); stats.Processed != 20 || stats.QueueDepth != 0 || stats.Busy != 0 {
+		t.Errorf("Stats() = %+v, want 20 processed and an idle pool", stats)
+	}
+}
+
+func TestInboundPoolShedsWhenFull(t *testing.T) {
+	release := make(chan struct{})
+	notices := make(chan InboundMessage, 4)
+	pool, err := NewInboundPool(InboundPoolConfig{
+		Workers:    1,
+		QueueSize:  1,
+		ShedNotice: func(msg InboundMessage) { notices <- msg },
+	}, func(InboundMessage) { <-release })
+	if err != nil {
+		t.Fatalf("NewInboundPool() error = %v", err)
+	}
+	pool.Start()
+
+	pool.Submit(InboundMessage{UserID: "busy", Text: "first"})
+	waitFor(t, func() bool { return pool.Stats().Busy == 1 })
+	if !pool.Submit(InboundMessage{UserID: "queued", Text: "second"}) {
+		t.Fatal("second message shed, want it queued")
+	}
+	if pool.Submit(InboundMessage{Channel: "telegram", UserID: "late", Text: "third"}) {
+		t.Fatal("third message queued past capacity")
+	}
+	// Reactions expect no reply, so they are dropped without a notice.
+	pool.Submit(InboundMessage{UserID: "late", MessageID: "9", Reaction: ReactionThumbsUp})
+
+	select {
+	case msg := <-notices:
+		if msg.UserID != "late" || msg.Text != "third" {
+			t.Errorf("shed notice for %+v, want the third message", msg)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("no shed notice sent")
+	}
+	select {
+	case msg := <-notices:
+		t.Errorf("unexpected shed notice for %+v", msg)
+	case <-time.After(20 * time.Millisecond):
+	}
+
+	stats := pool.Stats()
+	if stats.Shed != 2 || stats.QueueDepth != 1 {
+		t.Errorf("Stats() = %+v, want 2 shed and 1 queued", stats)
+	}
+	close(release)
+	pool.Close()
+	if got := pool.Stats(); got.Processed != 2 || got.MaxLagMS < got.LastLagMS {
+		t.Errorf("Stats() after close = %+v", got)
+	}
+	if pool.Submit(InboundMessage{UserID: "after"}) {
+		t.Error("Submit after Close accepted a message")
+	}
+}
+
+func TestInboundPoolStatsHandler(t *testing.T) {
+	pool, err := NewInboundPool(InboundPoolConfig{}, func(InboundMessage) {})
+	if err != nil {
+		t.Fatalf("NewInboundPool() error = %v", err)
+	}
+	rec := httptest.NewRecorder()
+	pool.StatsHandler().ServeHTTP(rec, httptest.NewRequest("GET", "/api/admin/inbound/stats", nil))
+
+	var stats InboundPoolStats
+	if err := json.Unmarshal(rec.Body.Bytes(), &stats); err != nil {
+		t.Fatalf("decode stats: %v", err)
+	}
+	if stats.Workers != defaultInboundWorkers || stats.QueueCapacity != defaultInboundQueueSize {
+		t.Errorf("stats = %+v, want default sizing", stats)
+	}
+}
+
+func waitFor(t *testing.T, cond func() bool) {
+	t.Helper()
+	deadline := time.Now().Add(time.Second)
+	for !cond() {
+		if time.Now().After(deadline) {
+			t.Fatal("condition not met within 1s")
+		}
+		time.Sleep(time.Millisecond)
+	}
+}
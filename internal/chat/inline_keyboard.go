@@ -6,10 +6,20 @@ package chat
 import (
 	"regexp"
 	"strings"
+	"unicode/utf8"
 )
 
 var reviewActionPattern = regexp.MustCompile(`\[\[PAI_REVIEW(?::([A-Za-z0-9-]+))?\]\]`)
 
+var choiceActionPattern = regexp.MustCompile(`\[\[PAI_CHOICE:([^|\]]+)\|([^\]]+)\]\]`)
+
+// choicesPerRow caps how many short choices share a keyboard row; longer
+// labels get a row of their own so they are not truncated.
+const (
+	choicesPerRow       = 3
+	shortChoiceMaxRunes = 12
+)
+
 type TelegramInlineKeyboardContext struct {
 	QuizIntensityPending bool
 	QuizActive           bool
@@ -35,6 +45,10 @@ func AppendFocusedPageButton(rows [][]InlineButton, pageURL string) [][]InlineBu
 // BuildTelegramInlineKeyboardWithContext returns inline keyboard rows inferred
 // from the outgoing message text plus explicit runtime state when available.
 func BuildTelegramInlineKeyboardWithContext(text string, ctx TelegramInlineKeyboardContext) [][]InlineButton {
+	if rows := choiceKeyboard(text); rows != nil {
+		return rows
+	}
+
 	lower := strings.ToLower(text)
 
 	hasLangPrompt :=
@@ -127,7 +141,52 @@ func BuildTelegramInlineKeyboardWithContext(text string, ctx TelegramInlineKeybo
 	return nil
 }
 
+// ChoiceActionCode returns a control token that renders as a tappable choice
+// on channels with inline keyboards. Tapping it sends data back as the
+// student's message. Other channels drop the token, so the text around it
+// must still say how to answer.
+func ChoiceActionCode(data, label string) string {
+	label = strings.NewReplacer("[", "(", "]", ")").Replace(strings.TrimSpace(label))
+	return "[[PAI_CHOICE:" + data + "|" + label + "]]"
+}
+
+// choiceKeyboard lays out the choice tokens in text in the order they appear.
+func choiceKeyboard(text string) [][]InlineButton {
+	matches := choiceActionPattern.FindAllStringSubmatch(text, -1)
+	if len(matches) == 0 {
+		return nil
+	}
+	var rows [][]InlineButton
+	var row []InlineButton
+	for _, match := range matches {
+		button := InlineButton{Text: match[2], CallbackData: match[1]}
+		if utf8.RuneCountInString(button.Text) > shortChoiceMaxRunes {
+			if len(row) > 0 {
+				rows = append(rows, row)
+				row = nil
+			}
+			rows = append(rows, []InlineButton{button})
+			continue
+		}
+		row = append(row, button)
+		if len(row) == choicesPerRow {
+			rows = append(rows, row)
+			row = nil
+		}
+	}
+	if len(row) > 0 {
+		rows = append(rows, row)
+	}
+	return rows
+}
+
 // StripReviewActionCodes removes review control tokens from outgoing text.
 func StripReviewActionCodes(text string) string {
 	return strings.TrimSpace(reviewActionPattern.ReplaceAllString(text, ""))
 }
+
+// StripActionCodes removes every control token (review and choice) from
+// outgoing text.
+func StripActionCodes(text string) string {
+	return StripReviewActionCodes(choiceActionPattern.ReplaceAllString(text, ""))
+}
@@ -114,3 +114,38 @@ func TestStripReviewActionCodes(t *testing.T) {
 		t.Fatalf("StripReviewActionCodes() = %q, want %q", got, "Nice explanation")
 	}
 }
+
+func TestBuildTelegramInlineKeyboard_ChoiceTokens(t *testing.T) {
+	text := "Pick a first topic:\n" +
+		chat.ChoiceActionCode("topic:F1-01", "Rational Numbers") +
+		chat.ChoiceActionCode("topic:F1-02", "Linear [Equations]") +
+		chat.ChoiceActionCode("onboarding:skip", "Skip")
+	got := chat.BuildTelegramInlineKeyboard(text)
+	want := [][]chat.InlineButton{
+		{{Text: "Rational Numbers", CallbackData: "topic:F1-01"}},
+		{{Text: "Linear (Equations)", CallbackData: "topic:F1-02"}},
+		{{Text: "Skip", CallbackData: "onboarding:skip"}},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("BuildTelegramInlineKeyboard() = %#v, want %#v", got, want)
+	}
+	if stripped := chat.StripActionCodes(text); stripped != "Pick a first topic:" {
+		t.Fatalf("StripActionCodes() = %q, want %q", stripped, "Pick a first topic:")
+	}
+}
+
+func TestBuildTelegramInlineKeyboard_ShortChoicesShareRow(t *testing.T) {
+	text := "Which form are you in?\n" +
+		chat.ChoiceActionCode("form:1", "Form 1") +
+		chat.ChoiceActionCode("form:2", "Form 2") +
+		chat.ChoiceActionCode("form:3", "Form 3")
+	got := chat.BuildTelegramInlineKeyboard(text)
+	want := [][]chat.InlineButton{{
+		{Text: "Form 1", CallbackData: "form:1"},
+		{Text: "Form 2", CallbackData: "form:2"},
+		{Text: "Form 3", CallbackData: "form:3"},
+	}}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("BuildTelegramInlineKeyboard() = %#v, want %#v", got, want)
+	}
+}
@@ -0,0 +1,119 @@
+// Copyright 2026 the P&AI authors. All rights reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package chat
+
+import (
+	"encoding/json"
+	"net/http"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/p-n-ai/pai-bot/internal/auth"
+)
+
+// Presence statuses reported for a connected WebSocket user.
+const (
+	PresenceOnline = "online"
+	PresenceTyping = "typing"
+)
+
+const (
+	// presenceTypingTTL is how long one "typing" event keeps a user typing;
+	// the embed resends it while the learner keeps typing.
+	presenceTypingTTL = 8 * time.Second
+	// presenceActiveWindow is how recently a connected user must have sent
+	// or typed something to count as actively chatting.
+	presenceActiveWindow = 5 * time.Minute
+)
+
+// Presence is one connected WebSocket user. LastActiveAt is their last
+// message or typing event, or when they connected.
+type Presence struct {
+	UserID       string    `json:"user_id"`
+	TenantID     string    `json:"tenant_id,omitempty"`
+	Status       string    `json:"status"`
+	ConnectedAt  time.Time `json:"connected_at"`
+	LastActiveAt time.Time `json:"last_active_at"`
+}
+
+// wsPresence is the presence kept per connection, guarded by WSChannel.mu.
+type wsPresence struct {
+	tenantID     string
+	connectedAt  time.Time
+	lastActiveAt time.Time
+	typingUntil  time.Time
+}
+
+func (p *wsPresence) status(now time.Time) string {
+	if now.Before(p.typingUntil) {
+		return PresenceTyping
+	}
+	return PresenceOnline
+}
+
+// touchPresence records activity from userID: a sent message ends typing,
+// a typing event extends it.
+func (ws *WSChannel) touchPresence(userID string, typing bool, now time.Time) {
+	ws.mu.Lock()
+	defer ws.mu.Unlock()
+	p, ok := ws.presence[userID]
+	if !ok {
+		return
+	}
+	p.lastActiveAt = now
+	if typing {
+		p.typingUntil = now.Add(presenceTypingTTL)
+	} else {
+		p.typingUntil = time.Time{}
+	}
+}
+
+// Presence returns every connected user, sorted by user ID.
+func (ws *WSChannel) Presence() []Presence {
+	now := time.Now()
+	ws.mu.RLock()
+	defer ws.mu.RUnlock()
+	out := make([]Presence, 0, len(ws.presence))
+	for userID, p := range ws.presence {
+		out = append(out, Presence{
+			UserID:       userID,
+			TenantID:     p.tenantID,
+			Status:       p.status(now),
+			ConnectedAt:  p.connectedAt,
+			LastActiveAt: p.lastActiveAt,
+		})
+	}
+	slices.SortFunc(out, func(a, b Presence) int { return strings.Compare(a.UserID, b.UserID) })
+	return out
+}
+
+// ActivelyChatting reports whether userID is connected and is typing or has
+// been active within the last few minutes. The scheduler holds nudges for
+// such users; they are already talking to the tutor.
+func (ws *WSChannel) ActivelyChatting(userID string, now time.Time) bool {
+	ws.mu.RLock()
+	defer ws.mu.RUnlock()
+	p, ok := ws.presence[userID]
+	if !ok {
+		return false
+	}
+	return now.Before(p.typingUntil) || now.Sub(p.lastActiveAt) < presenceActiveWindow
+}
+
+// PresenceHandler serves Presence as JSON. Tenant admins see their own
+// tenant's users; platform admins see everyone.
+func (ws *WSChannel) PresenceHandler() http.Handler {
+	return http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+		users := ws.Presence()
+		if claims, ok := auth.ClaimsFromContext(r.Context()); !ok || claims.Role != auth.RolePlatformAdmin {
+			// Connections without a tenant are dev-mode terminal clients.
+			users = slices.DeleteFunc(users, func(p Presence) bool {
+				return p.TenantID != "" && p.TenantID != claims.TenantID
+			})
+		}
+		rw.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(rw).Encode(map[string]any{"users": users})
+	})
+}
@@ -0,0 +1,82 @@
+// Copyright 2026 the P&AI authors. All rights reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package chat
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/coder/websocket"
+
+	"github.com/p-n-ai/pai-bot/internal/auth"
+)
+
+func TestWSChannel_PresenceTracksTypingAndDisconnect(t *testing.T) {
+	ws := NewWSChannel()
+	_ = ws.Start(context.Background(), func(InboundMessage) {})
+	srv := httptest.NewServer(ws.Handler())
+	defer srv.Close()
+
+	conn := dialAndAuth(t, "ws"+strings.TrimPrefix(srv.URL, "http"), "learner-1")
+	typing, _ := json.Marshal(wsInboundMsg{Type: "typing"})
+	if err := conn.Write(context.Background(), websocket.MessageText, typing); err != nil {
+		t.Fatalf("write typing: %v", err)
+	}
+	time.Sleep(100 * time.Millisecond)
+
+	users := ws.Presence()
+	if len(users) != 1 || users[0].UserID != "learner-1" || users[0].Status != PresenceTyping {
+		t.Fatalf("presence = %+v, want learner-1 typing", users)
+	}
+	now := time.Now()
+	if !ws.ActivelyChatting("learner-1", now) {
+		t.Fatal("ActivelyChatting() = false right after typing")
+	}
+	if ws.ActivelyChatting("learner-1", now.Add(presenceActiveWindow+presenceTypingTTL)) {
+		t.Fatal("ActivelyChatting() = true long after the last activity")
+	}
+
+	_ = conn.Close(websocket.StatusNormalClosure, "")
+	time.Sleep(100 * time.Millisecond)
+	if users := ws.Presence(); len(users) != 0 {
+		t.Fatalf("presence after disconnect = %+v, want none", users)
+	}
+	if ws.ActivelyChatting("learner-1", time.Now()) {
+		t.Fatal("ActivelyChatting() = true after disconnect")
+	}
+}
+
+func TestWSChannel_PresenceHandlerScopesToTenant(t *testing.T) {
+	ws := NewWSChannel()
+	now := time.Now()
+	ws.presence["a"] = &wsPresence{tenantID: "tenant-1", connectedAt: now, lastActiveAt: now}
+	ws.presence["b"] = &wsPresence{tenantID: "tenant-2", connectedAt: now, lastActiveAt: now}
+
+	get := func(claims auth.TokenClaims) []Presence {
+		t.Helper()
+		req := httptest.NewRequest(http.MethodGet, "/api/admin/presence", nil)
+		req = req.WithContext(auth.WithClaims(req.Context(), claims))
+		rec := httptest.NewRecorder()
+		ws.PresenceHandler().ServeHTTP(rec, req)
+		var body struct {
+			Users []Presence `json:"users"`
+		}
+		if err := json.Unmarshal(rec.Body.Bytes(), &body); err != nil {
+			t.Fatalf("decode presence: %v", err)
+		}
+		return body.Users
+	}
+
+	if users := get(auth.TokenClaims{Role: auth.RoleAdmin, TenantID: "tenant-1"}); len(users) != 1 || users[0].UserID != "a" {
+		t.Fatalf("tenant admin sees %+v, want only their tenant", users)
+	}
+	if users := get(auth.TokenClaims{Role: auth.RolePlatformAdmin}); len(users) != 2 {
+		t.Fatalf("platform admin sees %+v, want everyone", users)
+	}
+}
@@ -4,22 +4,34 @@
 package chat
 
 import (
+	"bytes"
 	"context"
 	"encoding/base64"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"log/slog"
+	"mime/multipart"
 	"net/http"
 	"net/url"
 	"path/filepath"
 	"strconv"
 	"strings"
+	"sync"
 	"time"
 )
 
 const telegramMaxMessageLen = 4096
 
+// telegramTopicMemory bounds how many sent forum-topic messages are
+// remembered for routing reactions back to their topic.
+const telegramTopicMemory = 4096
+
+// telegramAllowedUpdates opts in to message_reaction, which Telegram leaves
+// out of the default update set.
+const telegramAllowedUpdates = `["message","edited_message","callback_query","message_reaction","deleted_business_messages"]`
+
 // TelegramChannel implements the Channel interface for Telegram Bot API.
 type TelegramChannel struct {
 	token   string
@@ -29,6 +41,17 @@ type TelegramChannel struct {
 	stop    chan struct{}
 
 	devMode bool
+
+	mu         sync.Mutex
+	loopCancel context.CancelFunc
+	loopDone   chan struct{}
+	reportLoop func(error)
+
+	// sentTopics maps "chatID:messageID" of messages sent into a forum
+	// topic to the topic's UserID. message_reaction updates carry no
+	// thread, so this is how a reaction finds its conversation.
+	sentTopics     map[string]string
+	sentTopicOrder []string
 }
 
 // NewTelegramChannel creates a Telegram channel adapter.
@@ -52,10 +75,8 @@ func (t *TelegramChannel) SetDevMode(enabled bool) {
 }
 
 func (t *TelegramChannel) SendTyping(_ context.Context, userID string) error {
-	params := url.Values{
-		"chat_id": {userID},
-		"action":  {"typing"},
-	}
+	params := url.Values{"action": {"typing"}}
+	setTelegramChat(params, userID)
 	resp, err := t.client.PostForm(t.baseURL+"/sendChatAction", params)
 	if err != nil {
 		return fmt.Errorf("sending typing indicator: %w", err)
@@ -65,13 +86,28 @@ func (t *TelegramChannel) SendTyping(_ context.Context, userID string) error {
 }
 
 func (t *TelegramChannel) SendMessage(ctx context.Context, userID string, msg OutboundMessage) error {
+	_, err := t.SendMessageWithReceipt(ctx, userID, msg)
+	return err
+}
+
+// SendMessageWithReceipt sends msg and reports the Telegram message ID of each part.
+func (t *TelegramChannel) SendMessageWithReceipt(_ context.Context, userID string, msg OutboundMessage) (SendReceipt, error) {
+	var receipt SendReceipt
+	if len(msg.Photo) > 0 {
+		messageID, err := t.sendPhoto(userID, msg.Photo)
+		if err != nil {
+			return receipt, fmt.Errorf("sending Telegram photo: %w", err)
+		}
+		receipt.MessageIDs = appendMessageID(receipt.MessageIDs, messageID)
+		if strings.TrimSpace(msg.Text) == "" {
+			return receipt, nil
+		}
+	}
 	parts := SplitMessage(msg.Text, telegramMaxMessageLen)
 
 	for i, part := range parts {
-		params := url.Values{
-			"chat_id": {userID},
-			"text":    {part},
-		}
+		params := url.Values{"text": {part}}
+		setTelegramChat(params, userID)
 		if msg.ParseMode != "" {
 			params.Set("parse_mode", msg.ParseMode)
 		}
@@ -94,7 +130,7 @@ func (t *TelegramChannel) SendMessage(ctx context.Context, userID string, msg Ou
 			}
 			b, err := json.Marshal(replyMarkup)
 			if err != nil {
-				return fmt.Errorf("marshal telegram inline reply markup: %w", err)
+				return receipt, fmt.Errorf("marshal telegram inline reply markup: %w", err)
 			}
 			params.Set("reply_markup", string(b))
 		}
@@ -106,47 +142,231 @@ func (t *TelegramChannel) SendMessage(ctx context.Context, userID string, msg Ou
 			}
 			b, err := json.Marshal(replyMarkup)
 			if err != nil {
-				return fmt.Errorf("marshal telegram reply markup: %w", err)
+				return receipt, fmt.Errorf("marshal telegram reply markup: %w", err)
 			}
 			params.Set("reply_markup", string(b))
 		}
 
-		resp, err := t.client.PostForm(t.baseURL+"/sendMessage", params)
+		messageID, err := t.postSendMessage(params)
+		var apiErr *APIError
+		if err != nil && msg.ParseMode != "" && errors.As(err, &apiErr) && apiErr.Code == http.StatusBadRequest {
+			// If Markdown parsing fails, retry without parse mode
+			slog.Warn("Telegram markdown parse failed, retrying plain")
+			params.Del("parse_mode")
+			messageID, err = t.postSendMessage(params)
+			if err != nil {
+				return receipt, fmt.Errorf("sending Telegram message (retry): %w", err)
+			}
+		}
 		if err != nil {
-			return fmt.Errorf("sending Telegram message: %w", err)
+			return receipt, fmt.Errorf("sending Telegram message: %w", err)
 		}
-		_ = resp.Body.Close()
+		receipt.MessageIDs = appendMessageID(receipt.MessageIDs, messageID)
+	}
 
-		if resp.StatusCode != http.StatusOK {
-			// If Markdown parsing fails, retry without parse mode
-			if msg.ParseMode != "" && resp.StatusCode == http.StatusBadRequest {
-				slog.Warn("Telegram markdown parse failed, retrying plain")
-				params.Del("parse_mode")
-				retryResp, retryErr := t.client.PostForm(t.baseURL+"/sendMessage", params)
-				if retryErr != nil {
-					return fmt.Errorf("sending Telegram message (retry): %w", retryErr)
-				}
-				_ = retryResp.Body.Close()
-				if retryResp.StatusCode != http.StatusOK {
-					return fmt.Errorf("telegram API error %d on retry", retryResp.StatusCode)
-				}
-				continue
-			}
-			return fmt.Errorf("telegram API error %d", resp.StatusCode)
+	t.rememberTopicMessages(userID, receipt.MessageIDs)
+	return receipt, nil
+}
+
+// rememberTopicMessages records messageIDs sent to a forum topic, dropping
+// the oldest beyond telegramTopicMemory.
+func (t *TelegramChannel) rememberTopicMessages(userID string, messageIDs []string) {
+	chatID, threadID := SplitTelegramUserID(userID)
+	if threadID == "" || len(messageIDs) == 0 {
+		return
+	}
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if t.sentTopics == nil {
+		t.sentTopics = make(map[string]string)
+	}
+	for _, id := range messageIDs {
+		key := chatID + ":" + id
+		if _, ok := t.sentTopics[key]; !ok {
+			t.sentTopicOrder = append(t.sentTopicOrder, key)
 		}
+		t.sentTopics[key] = userID
+	}
+	for len(t.sentTopicOrder) > telegramTopicMemory {
+		delete(t.sentTopics, t.sentTopicOrder[0])
+		t.sentTopicOrder = t.sentTopicOrder[1:]
 	}
+}
 
-	return nil
+// topicForReaction moves a reaction to the forum topic of the bot message
+// it is on, when that message was sent into a topic.
+func (t *TelegramChannel) topicForReaction(msg InboundMessage) InboundMessage {
+	if msg.Reaction == "" {
+		return msg
+	}
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if userID, ok := t.sentTopics[msg.UserID+":"+msg.MessageID]; ok {
+		msg.UserID = userID
+	}
+	return msg
+}
+
+// postSendMessage calls sendMessage and returns the new message ID when the
+// response carries one. A missing or unparsable ID is not an error: the text
+// was delivered, it just cannot be linked to later reactions. A non-OK
+// response is returned as an *APIError.
+func (t *TelegramChannel) postSendMessage(params url.Values) (int, error) {
+	resp, err := t.client.PostForm(t.baseURL+"/sendMessage", params)
+	if err != nil {
+		return 0, err
+	}
+	defer func() { _ = resp.Body.Close() }()
+	if resp.StatusCode != http.StatusOK {
+		return 0, telegramAPIError(resp)
+	}
+	var result struct {
+		Result struct {
+			MessageID int `json:"message_id"`
+		} `json:"result"`
+	}
+	if body, err := io.ReadAll(resp.Body); err == nil {
+		_ = json.Unmarshal(body, &result)
+	}
+	return result.Result.MessageID, nil
+}
+
+// telegramAPIError reads the description Telegram puts in error bodies,
+// e.g. "Forbidden: bot was blocked by the user".
+func telegramAPIError(resp *http.Response) *APIError {
+	var body struct {
+		Description string `json:"description"`
+	}
+	if raw, err := io.ReadAll(resp.Body); err == nil {
+		_ = json.Unmarshal(raw, &body)
+	}
+	return &APIError{Channel: "telegram", Code: resp.StatusCode, Description: body.Description}
+}
+
+// sendPhoto uploads a PNG with sendPhoto and returns its message ID.
+func (t *TelegramChannel) sendPhoto(userID string, photo []byte) (int, error) {
+	var body bytes.Buffer
+	form := multipart.NewWriter(&body)
+	chatID, threadID := SplitTelegramUserID(userID)
+	if err := form.WriteField("chat_id", chatID); err != nil {
+		return 0, err
+	}
+	if threadID != "" {
+		if err := form.WriteField("message_thread_id", threadID); err != nil {
+			return 0, err
+		}
+	}
+	part, err := form.CreateFormFile("photo", "graph.png")
+	if err != nil {
+		return 0, err
+	}
+	if _, err := part.Write(photo); err != nil {
+		return 0, err
+	}
+	if err := form.Close(); err != nil {
+		return 0, err
+	}
+	resp, err := t.client.Post(t.baseURL+"/sendPhoto", form.FormDataContentType(), &body)
+	if err != nil {
+		return 0, err
+	}
+	defer func() { _ = resp.Body.Close() }()
+	if resp.StatusCode != http.StatusOK {
+		return 0, telegramAPIError(resp)
+	}
+	var result struct {
+		Result struct {
+			MessageID int `json:"message_id"`
+		} `json:"result"`
+	}
+	if raw, err := io.ReadAll(resp.Body); err == nil {
+		_ = json.Unmarshal(raw, &result)
+	}
+	return result.Result.MessageID, nil
+}
+
+// TelegramThreadUserID is the UserID of a forum topic: the chat ID and the
+// topic's message_thread_id joined by a colon. Each topic of a forum
+// supergroup keeps its own conversation, so a school can run one group with
+// a topic per class.
+func TelegramThreadUserID(chatID int64, threadID int) string {
+	return strconv.FormatInt(chatID, 10) + ":" + strconv.Itoa(threadID)
+}
+
+// SplitTelegramUserID undoes TelegramThreadUserID. threadID is empty for
+// private chats, plain groups and a forum's General topic.
+func SplitTelegramUserID(userID string) (chatID, threadID string) {
+	chatID, threadID, _ = strings.Cut(userID, ":")
+	return chatID, threadID
+}
+
+// setTelegramChat addresses a Bot API call to userID's chat and, for a forum
+// topic, its thread.
+func setTelegramChat(params url.Values, userID string) {
+	chatID, threadID := SplitTelegramUserID(userID)
+	params.Set("chat_id", chatID)
+	if threadID != "" {
+		params.Set("message_thread_id", threadID)
+	}
+}
+
+func appendMessageID(ids []string, messageID int) []string {
+	if messageID == 0 {
+		return ids
+	}
+	return append(ids, strconv.Itoa(messageID))
 }
 
 func (t *TelegramChannel) Start(ctx context.Context, handler func(InboundMessage)) error {
 	if err := t.syncCommands(); err != nil {
 		slog.Warn("failed to sync Telegram commands", "error", err)
 	}
-	go t.pollLoop(ctx, handler)
+	t.startPollLoop(ctx, handler)
+	return nil
+}
+
+// SetLoopHealthReporter receives the outcome of every getUpdates call.
+func (t *TelegramChannel) SetLoopHealthReporter(report func(error)) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.reportLoop = report
+}
+
+// Restart stops the running poll loop, waits for it to exit so two loops
+// never share the update offset, and starts a fresh one.
+func (t *TelegramChannel) Restart(ctx context.Context, handler func(InboundMessage)) error {
+	t.mu.Lock()
+	cancel, done := t.loopCancel, t.loopDone
+	t.mu.Unlock()
+	if cancel != nil {
+		cancel()
+		<-done
+	}
+	t.startPollLoop(ctx, handler)
 	return nil
 }
 
+func (t *TelegramChannel) startPollLoop(ctx context.Context, handler func(InboundMessage)) {
+	loopCtx, cancel := context.WithCancel(ctx)
+	done := make(chan struct{})
+	t.mu.Lock()
+	t.loopCancel, t.loopDone = cancel, done
+	t.mu.Unlock()
+	go func() {
+		defer close(done)
+		t.pollLoop(loopCtx, handler)
+	}()
+}
+
+func (t *TelegramChannel) report(err error) {
+	t.mu.Lock()
+	report := t.reportLoop
+	t.mu.Unlock()
+	if report != nil {
+		report(err)
+	}
+}
+
 func (t *TelegramChannel) Stop() error {
 	close(t.stop)
 	return nil
@@ -162,9 +382,17 @@ func (t *TelegramChannel) pollLoop(ctx context.Context, handler func(InboundMess
 			return
 		default:
 			updates, err := t.getUpdates(ctx)
+			if ctx.Err() != nil {
+				return
+			}
+			t.report(err)
 			if err != nil {
 				slog.Error("Telegram getUpdates error", "error", err)
-				time.Sleep(5 * time.Second)
+				select {
+				case <-ctx.Done():
+					return
+				case <-time.After(5 * time.Second):
+				}
 				continue
 			}
 
@@ -174,7 +402,8 @@ func (t *TelegramChannel) pollLoop(ctx context.Context, handler func(InboundMess
 				if !ok {
 					continue
 				}
-				if msg.HasImage && msg.ImageFileID != "" {
+				msg = t.topicForReaction(msg)
+				if msg.HasImage && msg.ImageFileID != "" && msg.ExpectsReply() {
 					dataURL, err := t.getImageDataURL(ctx, msg.ImageFileID)
 					if err != nil {
 						slog.Warn("failed to fetch telegram image", "error", err)
@@ -188,7 +417,7 @@ func (t *TelegramChannel) pollLoop(ctx context.Context, handler func(InboundMess
 					}
 				}
 
-				go handler(msg)
+				handler(msg)
 			}
 		}
 	}
@@ -196,8 +425,9 @@ func (t *TelegramChannel) pollLoop(ctx context.Context, handler func(InboundMess
 
 func (t *TelegramChannel) getUpdates(ctx context.Context) ([]tgUpdate, error) {
 	params := url.Values{
-		"offset":  {strconv.Itoa(t.offset)},
-		"timeout": {"30"},
+		"offset":          {strconv.Itoa(t.offset)},
+		"timeout":         {"30"},
+		"allowed_updates": {telegramAllowedUpdates},
 	}
 
 	req, err := http.NewRequestWithContext(ctx, "GET", t.baseURL+"/getUpdates?"+params.Encode(), nil)
@@ -233,20 +463,46 @@ func (t *TelegramChannel) getUpdates(ctx context.Context) ([]tgUpdate, error) {
 
 // Telegram API types (minimal)
 type tgUpdate struct {
-	UpdateID      int              `json:"update_id"`
-	Message       *tgMessage       `json:"message"`
-	CallbackQuery *tgCallbackQuery `json:"callback_query,omitempty"`
+	UpdateID                int                        `json:"update_id"`
+	Message                 *tgMessage                 `json:"message"`
+	EditedMessage           *tgMessage                 `json:"edited_message,omitempty"`
+	DeletedBusinessMessages *tgBusinessMessagesDeleted `json:"deleted_business_messages,omitempty"`
+	MessageReaction         *tgMessageReaction         `json:"message_reaction,omitempty"`
+	CallbackQuery           *tgCallbackQuery           `json:"callback_query,omitempty"`
+}
+
+type tgMessageReaction struct {
+	Chat        tgChat           `json:"chat"`
+	MessageID   int              `json:"message_id"`
+	User        *tgUser          `json:"user,omitempty"`
+	NewReaction []tgReactionType `json:"new_reaction"`
+}
+
+type tgReactionType struct {
+	Type  string `json:"type"`
+	Emoji string `json:"emoji,omitempty"`
+}
+
+// tgBusinessMessagesDeleted is the only deletion signal the Bot API delivers;
+// plain private-chat deletions are not reported to bots.
+type tgBusinessMessagesDeleted struct {
+	Chat       tgChat `json:"chat"`
+	MessageIDs []int  `json:"message_ids"`
 }
 
 type tgMessage struct {
-	MessageID      int         `json:"message_id"`
-	Text           string      `json:"text"`
-	Caption        string      `json:"caption"`
-	Photo          []tgPhoto   `json:"photo,omitempty"`
-	Document       *tgDocument `json:"document,omitempty"`
-	Chat           tgChat      `json:"chat"`
-	From           tgUser      `json:"from"`
-	ReplyToMessage *tgMessage  `json:"reply_to_message,omitempty"`
+	MessageID int `json:"message_id"`
+	// MessageThreadID is the forum topic, set with IsTopicMessage. Replies
+	// in ordinary groups also carry a thread ID, so it alone is not enough.
+	MessageThreadID int         `json:"message_thread_id,omitempty"`
+	IsTopicMessage  bool        `json:"is_topic_message,omitempty"`
+	Text            string      `json:"text"`
+	Caption         string      `json:"caption"`
+	Photo           []tgPhoto   `json:"photo,omitempty"`
+	Document        *tgDocument `json:"document,omitempty"`
+	Chat            tgChat      `json:"chat"`
+	From            tgUser      `json:"from"`
+	ReplyToMessage  *tgMessage  `json:"reply_to_message,omitempty"`
 }
 
 type tgPhoto struct {
@@ -319,7 +575,7 @@ func mapTelegramInbound(u tgUpdate) (InboundMessage, bool) {
 		}
 		return InboundMessage{
 			Channel:           "telegram",
-			UserID:            strconv.FormatInt(cb.Message.Chat.ID, 10),
+			UserID:            telegramUserID(cb.Message),
 			ExternalID:        strconv.FormatInt(cb.From.ID, 10),
 			Text:              data,
 			Username:          cb.From.Username,
@@ -331,17 +587,88 @@ func mapTelegramInbound(u tgUpdate) (InboundMessage, bool) {
 		}, true
 	}
 
+	if u.MessageReaction != nil {
+		return mapTelegramReaction(u.MessageReaction)
+	}
+
+	if u.DeletedBusinessMessages != nil {
+		deleted := u.DeletedBusinessMessages
+		if len(deleted.MessageIDs) == 0 {
+			return InboundMessage{}, false
+		}
+		ids := make([]string, 0, len(deleted.MessageIDs))
+		for _, id := range deleted.MessageIDs {
+			ids = append(ids, strconv.Itoa(id))
+		}
+		return InboundMessage{
+			Channel:           "telegram",
+			UserID:            strconv.FormatInt(deleted.Chat.ID, 10),
+			DeletedMessageIDs: ids,
+		}, true
+	}
+
+	if u.EditedMessage != nil {
+		msg, ok := mapTelegramMessage(u.EditedMessage)
+		if !ok {
+			return InboundMessage{}, false
+		}
+		msg.Edited = true
+		return msg, true
+	}
+
 	if u.Message == nil {
 		return InboundMessage{}, false
 	}
+	return mapTelegramMessage(u.Message)
+}
+
+// mapTelegramReaction keeps only thumbs up/down; other emoji and reaction
+// removals carry no feedback signal.
+func mapTelegramReaction(r *tgMessageReaction) (InboundMessage, bool) {
+	reaction := ""
+	for _, rt := range r.NewReaction {
+		if rt.Type != "emoji" {
+			continue
+		}
+		switch rt.Emoji {
+		case "👍":
+			reaction = ReactionThumbsUp
+		case "👎":
+			reaction = ReactionThumbsDown
+		}
+	}
+	if reaction == "" {
+		return InboundMessage{}, false
+	}
+	msg := InboundMessage{
+		Channel:   "telegram",
+		UserID:    strconv.FormatInt(r.Chat.ID, 10),
+		MessageID: strconv.Itoa(r.MessageID),
+		Reaction:  reaction,
+	}
+	if r.User != nil {
+		msg.ExternalID = strconv.FormatInt(r.User.ID, 10)
+	}
+	return msg, true
+}
+
+// telegramUserID is the conversation m belongs to: its chat, or its forum
+// topic when posted in one.
+func telegramUserID(m *tgMessage) string {
+	if m.IsTopicMessage && m.MessageThreadID != 0 {
+		return TelegramThreadUserID(m.Chat.ID, m.MessageThreadID)
+	}
+	return strconv.FormatInt(m.Chat.ID, 10)
+}
 
-	text := strings.TrimSpace(u.Message.Text)
-	caption := strings.TrimSpace(u.Message.Caption)
+func mapTelegramMessage(m *tgMessage) (InboundMessage, bool) {
+	text := strings.TrimSpace(m.Text)
+	caption := strings.TrimSpace(m.Caption)
 	if text == "" && caption != "" {
 		text = caption
 	}
 
-	imageFileID := pickImageFileID(u.Message)
+	imageFileID := pickImageFileID(m)
 	hasImage := imageFileID != ""
 	if text == "" && !hasImage {
 		return InboundMessage{}, false
@@ -349,29 +676,30 @@ func mapTelegramInbound(u tgUpdate) (InboundMessage, bool) {
 
 	msg := InboundMessage{
 		Channel:    "telegram",
-		UserID:     strconv.FormatInt(u.Message.Chat.ID, 10),
-		ExternalID: strconv.FormatInt(u.Message.From.ID, 10),
+		UserID:     telegramUserID(m),
+		ExternalID: strconv.FormatInt(m.From.ID, 10),
+		MessageID:  strconv.Itoa(m.MessageID),
 		Text:       text,
 		Caption:    caption,
 		HasImage:   hasImage,
-		Username:   u.Message.From.Username,
-		FirstName:  u.Message.From.FirstName,
-		LastName:   u.Message.From.LastName,
-		Language:   u.Message.From.LanguageCode,
+		Username:   m.From.Username,
+		FirstName:  m.From.FirstName,
+		LastName:   m.From.LastName,
+		Language:   m.From.LanguageCode,
 	}
 	if hasImage {
 		msg.ImageFileID = imageFileID
 	}
-	if u.Message.ReplyToMessage != nil {
-		if u.Message.ReplyToMessage.Text != "" {
-			msg.ReplyToText = u.Message.ReplyToMessage.Text
-		} else if u.Message.ReplyToMessage.Caption != "" {
-			msg.ReplyToText = u.Message.ReplyToMessage.Caption
+	if m.ReplyToMessage != nil {
+		if m.ReplyToMessage.Text != "" {
+			msg.ReplyToText = m.ReplyToMessage.Text
+		} else if m.ReplyToMessage.Caption != "" {
+			msg.ReplyToText = m.ReplyToMessage.Caption
 		}
 		// If this message is a reply to media and the current message has no media,
 		// carry the replied image forward so AI can answer follow-up questions.
 		if msg.ImageFileID == "" {
-			if replyImageFileID := pickImageFileID(u.Message.ReplyToMessage); replyImageFileID != "" {
+			if replyImageFileID := pickImageFileID(m.ReplyToMessage); replyImageFileID != "" {
 				msg.HasImage = true
 				msg.ImageFileID = replyImageFileID
 			}
@@ -145,3 +145,35 @@ func TestMapTelegramInbound_ReplyToImageDocumentCarriesImage(t *testing.T) {
 		t.Fatalf("ImageFileID = %q, want doc-image", msg.ImageFileID)
 	}
 }
+
+func TestMapTelegramInbound_ForumTopicKeepsItsOwnConversation(t *testing.T) {
+	topic, ok := mapTelegramInbound(tgUpdate{
+		UpdateID: 10,
+		Message: &tgMessage{
+			MessageID:       7,
+			MessageThreadID: 42,
+			IsTopicMessage:  true,
+			Text:            "solve 2x = 8",
+			Chat:            tgChat{ID: -1001234},
+			From:            tgUser{ID: 456},
+		},
+	})
+	if !ok || topic.UserID != "-1001234:42" {
+		t.Fatalf("topic UserID = %q (ok %v), want -1001234:42", topic.UserID, ok)
+	}
+
+	// A reply in an ordinary group also carries a thread ID; it stays in the chat.
+	reply, ok := mapTelegramInbound(tgUpdate{
+		UpdateID: 11,
+		Message: &tgMessage{
+			MessageID:       8,
+			MessageThreadID: 7,
+			Text:            "and this one?",
+			Chat:            tgChat{ID: -1001234},
+			From:            tgUser{ID: 456},
+		},
+	})
+	if !ok || reply.UserID != "-1001234" {
+		t.Fatalf("reply UserID = %q (ok %v), want -1001234", reply.UserID, ok)
+	}
+}
@@ -98,3 +98,83 @@ func TestMapTelegramInbound_CallbackQuery(t *testing.T) {
 		t.Fatalf("UserID = %q, want 123456", msg.UserID)
 	}
 }
+
+func TestMapTelegramInbound_EditedMessage(t *testing.T) {
+	update := map[string]any{
+		"update_id": 1002,
+		"edited_message": map[string]any{
+			"message_id": 42,
+			"text":       "x = 4, not 3",
+			"chat":       map[string]any{"id": 123456},
+			"from":       map[string]any{"id": 777},
+		},
+	}
+	msg, ok := chat.MapTelegramInboundForTest(update)
+	if !ok {
+		t.Fatal("expected edited message to map as inbound")
+	}
+	if !msg.Edited || !msg.IsRevision() {
+		t.Fatalf("Edited = %v, IsRevision = %v, want both true", msg.Edited, msg.IsRevision())
+	}
+	if msg.MessageID != "42" || msg.Text != "x = 4, not 3" {
+		t.Fatalf("MessageID = %q Text = %q, want 42 and edited text", msg.MessageID, msg.Text)
+	}
+}
+
+func TestMapTelegramInbound_DeletedBusinessMessages(t *testing.T) {
+	update := map[string]any{
+		"update_id": 1003,
+		"deleted_business_messages": map[string]any{
+			"business_connection_id": "bc-1",
+			"chat":                   map[string]any{"id": 123456},
+			"message_ids":            []int{41, 42},
+		},
+	}
+	msg, ok := chat.MapTelegramInboundForTest(update)
+	if !ok {
+		t.Fatal("expected deleted messages to map as inbound")
+	}
+	if msg.UserID != "123456" || !msg.IsRevision() {
+		t.Fatalf("msg = %#v, want revision for chat 123456", msg)
+	}
+	if len(msg.DeletedMessageIDs) != 2 || msg.DeletedMessageIDs[0] != "41" || msg.DeletedMessageIDs[1] != "42" {
+		t.Fatalf("DeletedMessageIDs = %v, want [41 42]", msg.DeletedMessageIDs)
+	}
+}
+
+func TestMapTelegramInbound_MessageReaction(t *testing.T) {
+	tests := []struct {
+		name     string
+		reaction []map[string]any
+		want     string
+		wantOK   bool
+	}{
+		{name: "thumbs up", reaction: []map[string]any{{"type": "emoji", "emoji": "👍"}}, want: chat.ReactionThumbsUp, wantOK: true},
+		{name: "thumbs down", reaction: []map[string]any{{"type": "emoji", "emoji": "👎"}}, want: chat.ReactionThumbsDown, wantOK: true},
+		{name: "other emoji", reaction: []map[string]any{{"type": "emoji", "emoji": "🔥"}}},
+		{name: "removed", reaction: []map[string]any{}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			update := map[string]any{
+				"update_id": 1004,
+				"message_reaction": map[string]any{
+					"chat":         map[string]any{"id": 123456},
+					"message_id":   77,
+					"user":         map[string]any{"id": 777},
+					"new_reaction": tt.reaction,
+				},
+			}
+			msg, ok := chat.MapTelegramInboundForTest(update)
+			if ok != tt.wantOK {
+				t.Fatalf("ok = %v, want %v", ok, tt.wantOK)
+			}
+			if !ok {
+				return
+			}
+			if msg.Reaction != tt.want || msg.MessageID != "77" || msg.UserID != "123456" || msg.ExpectsReply() {
+				t.Fatalf("msg = %#v, want silent %s reaction on message 77", msg, tt.want)
+			}
+		})
+	}
+}
@@ -11,7 +11,11 @@ import (
 	"net/http/httptest"
 	"net/url"
 	"strings"
+	"sync/atomic"
 	"testing"
+	"time"
+
+	"github.com/p-n-ai/pai-bot/internal/apperr"
 )
 
 func TestTelegramChannel_SendMessage_QuizInlineKeyboardPayload(t *testing.T) {
@@ -143,3 +147,177 @@ func TestTelegramChannel_AnswerCallbackQuery_SendsCallbackAck(t *testing.T) {
 		t.Fatalf("callback_query_id = %q, want cb-123", got)
 	}
 }
+
+func TestTelegramChannel_SendMessageWithReceipt_ReturnsMessageIDs(t *testing.T) {
+	nextID := 500
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		nextID++
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(map[string]any{"ok": true, "result": map[string]any{"message_id": nextID}})
+	}))
+	defer server.Close()
+
+	ch, err := NewTelegramChannel("test-token")
+	if err != nil {
+		t.Fatalf("NewTelegramChannel() error = %v", err)
+	}
+	ch.baseURL = server.URL
+
+	text := strings.Repeat("a", telegramMaxMessageLen) + " tail"
+	receipt, err := ch.SendMessageWithReceipt(context.Background(), "123456", OutboundMessage{Channel: "telegram", UserID: "123456", Text: text})
+	if err != nil {
+		t.Fatalf("SendMessageWithReceipt() error = %v", err)
+	}
+	if strings.Join(receipt.MessageIDs, ",") != "501,502" {
+		t.Fatalf("MessageIDs = %v, want [501 502]", receipt.MessageIDs)
+	}
+}
+
+func TestTelegramChannel_SendMessage_RepliesInForumTopic(t *testing.T) {
+	var captured url.Values
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		captured, _ = url.ParseQuery(string(body))
+		_, _ = w.Write([]byte(`{"ok":true,"result":{"message_id":301}}`))
+	}))
+	defer server.Close()
+
+	ch, err := NewTelegramChannel("test-token")
+	if err != nil {
+		t.Fatalf("NewTelegramChannel() error = %v", err)
+	}
+	ch.baseURL = server.URL
+
+	if err := ch.SendMessage(context.Background(), "-1001234:42", OutboundMessage{Text: "x = 4"}); err != nil {
+		t.Fatalf("SendMessage() error = %v", err)
+	}
+	if captured.Get("chat_id") != "-1001234" || captured.Get("message_thread_id") != "42" {
+		t.Fatalf("chat_id = %q, message_thread_id = %q, want -1001234 and 42", captured.Get("chat_id"), captured.Get("message_thread_id"))
+	}
+
+	// Reactions carry no thread; the sent message ID leads back to the topic.
+	reaction := ch.topicForReaction(InboundMessage{Channel: "telegram", UserID: "-1001234", MessageID: "301", Reaction: ReactionThumbsUp})
+	if reaction.UserID != "-1001234:42" {
+		t.Fatalf("reaction UserID = %q, want -1001234:42", reaction.UserID)
+	}
+}
+
+func TestTelegramChannel_SendMessage_SendsPhotoBeforeText(t *testing.T) {
+	var paths []string
+	var photo []byte
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		paths = append(paths, r.URL.Path)
+		if r.URL.Path == "/sendPhoto" {
+			if r.FormValue("chat_id") != "123456" {
+				t.Errorf("chat_id = %q, want 123456", r.FormValue("chat_id"))
+			}
+			file, _, err := r.FormFile("photo")
+			if err != nil {
+				t.Fatalf("FormFile() error = %v", err)
+			}
+			photo, _ = io.ReadAll(file)
+			_, _ = w.Write([]byte(`{"ok":true,"result":{"message_id":7}}`))
+			return
+		}
+		_, _ = w.Write([]byte(`{"ok":true,"result":{"message_id":8}}`))
+	}))
+	defer server.Close()
+
+	ch, err := NewTelegramChannel("test-token")
+	if err != nil {
+		t.Fatalf("NewTelegramChannel() error = %v", err)
+	}
+	ch.baseURL = server.URL
+
+	receipt, err := ch.SendMessageWithReceipt(context.Background(), "123456", OutboundMessage{
+		Channel: "telegram",
+		UserID:  "123456",
+		Text:    "Look where the curve crosses the x-axis.",
+		Photo:   []byte("png-bytes"),
+	})
+	if err != nil {
+		t.Fatalf("SendMessageWithReceipt() error = %v", err)
+	}
+	if strings.Join(paths, ",") != "/sendPhoto,/sendMessage" {
+		t.Fatalf("paths = %v, want photo then text", paths)
+	}
+	if string(photo) != "png-bytes" {
+		t.Fatalf("uploaded photo = %q", photo)
+	}
+	if strings.Join(receipt.MessageIDs, ",") != "7,8" {
+		t.Fatalf("receipt = %v, want 7,8", receipt.MessageIDs)
+	}
+}
+
+func TestTelegramChannel_SendMessage_BlockedByUser(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusForbidden)
+		_, _ = w.Write([]byte(`{"ok":false,"error_code":403,"description":"Forbidden: bot was blocked by the user"}`))
+	}))
+	defer server.Close()
+
+	ch, err := NewTelegramChannel("test-token")
+	if err != nil {
+		t.Fatalf("NewTelegramChannel() error = %v", err)
+	}
+	ch.baseURL = server.URL
+
+	err = ch.SendMessage(context.Background(), "123456", OutboundMessage{Channel: "telegram", UserID: "123456", Text: "Hi"})
+	if err == nil {
+		t.Fatal("SendMessage() error = nil, want API error")
+	}
+	if got := DeliveryStatusOf(err); got != DeliveryBlocked {
+		t.Fatalf("DeliveryStatusOf(%v) = %q, want %q", err, got, DeliveryBlocked)
+	}
+	if got := apperr.ClassOf(err); got != apperr.ClassPermanent {
+		t.Fatalf("ClassOf(%v) = %q, want permanent", err, got)
+	}
+	if got := apperr.ClassOf(&APIError{Channel: "telegram", Code: http.StatusTooManyRequests}); got != apperr.ClassTransient {
+		t.Fatalf("ClassOf(429) = %q, want transient", got)
+	}
+}
+
+func TestTelegramChannel_ReportsPollFailuresAndRestartsLoop(t *testing.T) {
+	var polls atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if strings.HasSuffix(r.URL.Path, "/getUpdates") && polls.Add(1) == 1 {
+			_, _ = w.Write([]byte(`{"ok":false}`))
+			return
+		}
+		<-r.Context().Done()
+	}))
+	defer server.Close()
+
+	ch, err := NewTelegramChannel("test-token")
+	if err != nil {
+		t.Fatalf("NewTelegramChannel() error = %v", err)
+	}
+	ch.baseURL = server.URL
+	reports := make(chan error, 4)
+	ch.SetLoopHealthReporter(func(err error) { reports <- err })
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	ch.startPollLoop(ctx, func(InboundMessage) {})
+	select {
+	case err := <-reports:
+		if err == nil {
+			t.Fatal("first poll reported success, want the ok=false failure")
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("poll failure was not reported")
+	}
+
+	// Restart abandons the loop sleeping after its failure and polls again
+	// right away.
+	if err := ch.Restart(ctx, func(InboundMessage) {}); err != nil {
+		t.Fatalf("Restart() error = %v", err)
+	}
+	deadline := time.Now().Add(2 * time.Second)
+	for polls.Load() < 2 {
+		if time.Now().After(deadline) {
+			t.Fatal("restarted loop did not poll")
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+}
@@ -0,0 +1,90 @@
+// Copyright 2026 the P&AI authors. All rights reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package chat
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/p-n-ai/pai-bot/internal/apperr"
+)
+
+var (
+	// ErrInvalidWebAppInitData marks Mini App initData that is malformed or
+	// was not signed with the bot's token.
+	ErrInvalidWebAppInitData = apperr.UserInput(errors.New("invalid telegram web app init data"))
+	// ErrExpiredWebAppInitData marks Mini App initData signed too long ago.
+	ErrExpiredWebAppInitData = apperr.UserInput(errors.New("expired telegram web app init data"))
+)
+
+// TelegramWebAppUser is the Telegram user a Mini App was opened by.
+type TelegramWebAppUser struct {
+	ID           int64  `json:"id"`
+	FirstName    string `json:"first_name"`
+	LastName     string `json:"last_name,omitempty"`
+	Username     string `json:"username,omitempty"`
+	LanguageCode string `json:"language_code,omitempty"`
+}
+
+// BotUserID is the ID the engine knows the user by. Mini Apps open from the
+// private chat with the bot, whose chat ID is the user's ID.
+func (u TelegramWebAppUser) BotUserID() string {
+	return strconv.FormatInt(u.ID, 10)
+}
+
+// ValidateTelegramWebAppInitData checks the signature on a Mini App's
+// initData and returns the user it names. The signature is an HMAC-SHA256 of
+// the sorted fields, keyed by the bot token, so only Telegram could have
+// produced it. initData older than maxAge is rejected so a leaked copy
+// cannot be replayed indefinitely; a maxAge of zero skips that check.
+func ValidateTelegramWebAppInitData(initData, botToken string, maxAge time.Duration, now time.Time) (TelegramWebAppUser, error) {
+	values, err := url.ParseQuery(initData)
+	if err != nil || botToken == "" {
+		return TelegramWebAppUser{}, ErrInvalidWebAppInitData
+	}
+	hash := values.Get("hash")
+	if hash == "" {
+		return TelegramWebAppUser{}, ErrInvalidWebAppInitData
+	}
+
+	fields := make([]string, 0, len(values))
+	for key := range values {
+		if key == "hash" {
+			continue
+		}
+		fields = append(fields, key+"="+values.Get(key))
+	}
+	sort.Strings(fields)
+
+	secret := hmac.New(sha256.New, []byte("WebAppData"))
+	secret.Write([]byte(botToken))
+	mac := hmac.New(sha256.New, secret.Sum(nil))
+	mac.Write([]byte(strings.Join(fields, "\n")))
+	got, err := hex.DecodeString(hash)
+	if err != nil || !hmac.Equal(got, mac.Sum(nil)) {
+		return TelegramWebAppUser{}, ErrInvalidWebAppInitData
+	}
+
+	authDate, err := strconv.ParseInt(values.Get("auth_date"), 10, 64)
+	if err != nil {
+		return TelegramWebAppUser{}, ErrInvalidWebAppInitData
+	}
+	if maxAge > 0 && now.Sub(time.Unix(authDate, 0)) > maxAge {
+		return TelegramWebAppUser{}, ErrExpiredWebAppInitData
+	}
+
+	var user TelegramWebAppUser
+	if err := json.Unmarshal([]byte(values.Get("user")), &user); err != nil || user.ID == 0 {
+		return TelegramWebAppUser{}, ErrInvalidWebAppInitData
+	}
+	return user, nil
+}
@@ -0,0 +1,90 @@
+// Copyright 2026 the P&AI authors. All rights reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package chat_test
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/p-n-ai/pai-bot/internal/chat"
+)
+
+const webAppBotToken = "123456:test-token"
+
+// signWebAppInitData signs fields the way Telegram does for a Mini App.
+func signWebAppInitData(fields map[string]string, botToken string) string {
+	lines := make([]string, 0, len(fields))
+	values := url.Values{}
+	for key, value := range fields {
+		lines = append(lines, key+"="+value)
+		values.Set(key, value)
+	}
+	sort.Strings(lines)
+	secret := hmac.New(sha256.New, []byte("WebAppData"))
+	secret.Write([]byte(botToken))
+	mac := hmac.New(sha256.New, secret.Sum(nil))
+	mac.Write([]byte(strings.Join(lines, "\n")))
+	values.Set("hash", hex.EncodeToString(mac.Sum(nil)))
+	return values.Encode()
+}
+
+func webAppFields(authDate time.Time) map[string]string {
+	return map[string]string{
+		"auth_date": strconv.FormatInt(authDate.Unix(), 10),
+		"query_id":  "AAHdF6IQAAAAAN0XohDhrOrc",
+		"user":      `{"id":279058397,"first_name":"Aina","username":"aina","language_code":"ms"}`,
+	}
+}
+
+func TestValidateTelegramWebAppInitData_AcceptsSignedData(t *testing.T) {
+	now := time.Date(2026, 10, 17, 9, 0, 0, 0, time.UTC)
+	initData := signWebAppInitData(webAppFields(now.Add(-time.Minute)), webAppBotToken)
+
+	user, err := chat.ValidateTelegramWebAppInitData(initData, webAppBotToken, time.Hour, now)
+	if err != nil {
+		t.Fatalf("ValidateTelegramWebAppInitData() error = %v", err)
+	}
+	if user.ID != 279058397 || user.FirstName != "Aina" || user.LanguageCode != "ms" {
+		t.Fatalf("user = %+v, want the signed user", user)
+	}
+	if user.BotUserID() != "279058397" {
+		t.Fatalf("BotUserID() = %q, want the Telegram ID", user.BotUserID())
+	}
+}
+
+func TestValidateTelegramWebAppInitData_Rejects(t *testing.T) {
+	now := time.Date(2026, 10, 17, 9, 0, 0, 0, time.UTC)
+	signed := signWebAppInitData(webAppFields(now.Add(-time.Minute)), webAppBotToken)
+	tampered := strings.Replace(signed, "Aina", "Mallory", 1)
+	noUser := webAppFields(now)
+	delete(noUser, "user")
+
+	tests := []struct {
+		name     string
+		initData string
+		want     error
+	}{
+		{name: "other bot token", initData: signWebAppInitData(webAppFields(now), "654321:other"), want: chat.ErrInvalidWebAppInitData},
+		{name: "tampered field", initData: tampered, want: chat.ErrInvalidWebAppInitData},
+		{name: "missing hash", initData: "auth_date=1&user=%7B%7D", want: chat.ErrInvalidWebAppInitData},
+		{name: "missing user", initData: signWebAppInitData(noUser, webAppBotToken), want: chat.ErrInvalidWebAppInitData},
+		{name: "too old", initData: signWebAppInitData(webAppFields(now.Add(-2*time.Hour)), webAppBotToken), want: chat.ErrExpiredWebAppInitData},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := chat.ValidateTelegramWebAppInitData(tt.initData, webAppBotToken, time.Hour, now)
+			if !errors.Is(err, tt.want) {
+				t.Fatalf("error = %v, want %v", err, tt.want)
+			}
+		})
+	}
+}
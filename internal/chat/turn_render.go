@@ -10,17 +10,17 @@ func RenderTurn(in InboundMessage, text, focusedPageURL string, telegramContext
 	out := OutboundMessage{
 		Channel:        in.Channel,
 		UserID:         in.UserID,
-		Text:           StripReviewActionCodes(text),
+		Text:           StripActionCodes(text),
 		FocusedPageURL: strings.TrimSpace(focusedPageURL),
 	}
 	if in.Channel == "telegram" {
-		out.Text = ConvertLaTeXToUnicode(text)
+		out.Text = ConvertLaTeXToUnicode(out.Text)
 		out.Text = NormalizeTelegramMarkdown(out.Text)
 		out.ParseMode = "Markdown"
 		out.ReplyKeyboard = BuildTelegramReplyKeyboard(text)
 		out.InlineKeyboard = BuildTelegramInlineKeyboardWithContext(text, telegramContext)
 		out.InlineKeyboard = AppendFocusedPageButton(out.InlineKeyboard, focusedPageURL)
-		out.Text = StripReviewActionCodes(out.Text)
+		out.Text = strings.TrimSpace(out.Text)
 	}
 	return out, strings.TrimSpace(out.Text) != ""
 }
@@ -5,6 +5,7 @@ package chat
 
 import (
 	"context"
+	"encoding/base64"
 	"encoding/json"
 	"fmt"
 	"log/slog"
@@ -18,7 +19,8 @@ import (
 	"github.com/p-n-ai/pai-bot/internal/auth"
 )
 
-// wsInboundMsg is the JSON envelope clients send over the WebSocket.
+// wsInboundMsg is the JSON envelope clients send over the WebSocket. Type is
+// "auth", "message", or "typing" while the learner types.
 type wsInboundMsg struct {
 	Type   string `json:"type"`
 	UserID string `json:"user_id,omitempty"`
@@ -30,6 +32,8 @@ type wsOutboundMsg struct {
 	Type        string         `json:"type"`
 	Text        string         `json:"text,omitempty"`
 	FocusedPage *wsFocusedPage `json:"focused_page,omitempty"`
+	// Image is a PNG data URL, e.g. a graph drawn for the reply.
+	Image string `json:"image,omitempty"`
 }
 
 type wsFocusedPage struct {
@@ -40,6 +44,7 @@ type wsFocusedPage struct {
 type WSChannel struct {
 	mu               sync.RWMutex
 	conns            map[string]*websocket.Conn // userID -> connection
+	presence         map[string]*wsPresence     // userID -> presence, alongside conns
 	handler          func(InboundMessage)       // set by Start()
 	stop             chan struct{}
 	embedConfigStore EmbedConfigStore   // nil for non-embed (terminal-chat) use
@@ -51,8 +56,9 @@ type WSChannel struct {
 // NewWSChannel creates a new WebSocket channel.
 func NewWSChannel() *WSChannel {
 	return &WSChannel{
-		conns: make(map[string]*websocket.Conn),
-		stop:  make(chan struct{}),
+		conns:    make(map[string]*websocket.Conn),
+		presence: make(map[string]*wsPresence),
+		stop:     make(chan struct{}),
 	}
 }
 
@@ -60,6 +66,7 @@ func NewWSChannel() *WSChannel {
 func NewEmbedWSChannel(store EmbedConfigStore, tm *auth.TokenManager) *WSChannel {
 	return &WSChannel{
 		conns:            make(map[string]*websocket.Conn),
+		presence:         make(map[string]*wsPresence),
 		stop:             make(chan struct{}),
 		embedConfigStore: store,
 		tokenManager:     tm,
@@ -174,7 +181,7 @@ func extractClientIP(r *http.Request) string {
 
 // handleConn manages a single WebSocket connection lifecycle.
 func (ws *WSChannel) handleConn(ctx context.Context, conn *websocket.Conn, jwtToken string) {
-	var userID string
+	var userID, tenantID string
 
 	if jwtToken != "" && ws.tokenManager != nil {
 		// Subprotocol JWT auth (embed mode — already validated in Handler,
@@ -186,6 +193,7 @@ func (ws *WSChannel) handleConn(ctx context.Context, conn *websocket.Conn, jwtTo
 			return
 		}
 		userID = claims.Subject
+		tenantID = claims.TenantID
 	} else if ws.embedConfigStore == nil {
 		// Non-embed (terminal-chat): first message must be auth.
 		var err error
@@ -202,8 +210,10 @@ func (ws *WSChannel) handleConn(ctx context.Context, conn *websocket.Conn, jwtTo
 	}
 
 	// Register the connection.
+	now := time.Now()
 	ws.mu.Lock()
 	ws.conns[userID] = conn
+	ws.presence[userID] = &wsPresence{tenantID: tenantID, connectedAt: now, lastActiveAt: now}
 	ws.mu.Unlock()
 
 	slog.Info("websocket client connected", "user_id", userID)
@@ -291,13 +301,17 @@ func (ws *WSChannel) readLoop(ctx context.Context, conn *websocket.Conn, userID
 			continue
 		}
 
+		if msg.Type == "typing" {
+			ws.touchPresence(userID, true, time.Now())
+			continue
+		}
 		if msg.Type != "message" {
 			slog.Warn("websocket unexpected message type", "type", msg.Type, "user_id", userID)
 			continue
 		}
 
 		// Content filtering for embed connections.
-		if ws.embedConfigStore != nil && containsPromptInjection(msg.Text) {
+		if ws.embedConfigStore != nil && ContainsPromptInjection(msg.Text) {
 			slog.Warn("embed content filter triggered", "user_id", userID)
 			_ = ws.writeJSON(ctx, conn, wsOutboundMsg{
 				Type: "error",
@@ -315,6 +329,8 @@ func (ws *WSChannel) readLoop(ctx context.Context, conn *websocket.Conn, userID
 			continue
 		}
 
+		ws.touchPresence(userID, false, time.Now())
+
 		ws.mu.RLock()
 		handler := ws.handler
 		ws.mu.RUnlock()
@@ -346,6 +362,9 @@ func (ws *WSChannel) SendMessage(ctx context.Context, userID string, msg Outboun
 	if pageURL := strings.TrimSpace(msg.FocusedPageURL); pageURL != "" {
 		response.FocusedPage = &wsFocusedPage{URL: pageURL}
 	}
+	if len(msg.Photo) > 0 {
+		response.Image = "data:image/png;base64," + base64.StdEncoding.EncodeToString(msg.Photo)
+	}
 	return ws.writeJSON(ctx, conn, response)
 }
 
@@ -385,6 +404,7 @@ func (ws *WSChannel) Stop() error {
 	for userID, conn := range ws.conns {
 		_ = conn.Close(websocket.StatusGoingAway, "server shutting down")
 		delete(ws.conns, userID)
+		delete(ws.presence, userID)
 	}
 
 	slog.Info("websocket channel stopped")
@@ -434,10 +454,11 @@ func (ws *WSChannel) removeConn(userID string) {
 	ws.mu.Lock()
 	defer ws.mu.Unlock()
 	delete(ws.conns, userID)
+	delete(ws.presence, userID)
 }
 
-// containsPromptInjection checks if a message contains common prompt injection markers.
-func containsPromptInjection(text string) bool {
+// ContainsPromptInjection reports whether text contains common prompt injection markers.
+func ContainsPromptInjection(text string) bool {
 	lower := strings.ToLower(text)
 	markers := []string{
 		"<|system|>",
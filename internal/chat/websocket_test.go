@@ -597,8 +597,8 @@ func TestContainsPromptInjection(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.text[:min(len(tt.text), 30)], func(t *testing.T) {
-			if got := containsPromptInjection(tt.text); got != tt.expected {
-				t.Errorf("containsPromptInjection(%q) = %v, want %v", tt.text, got, tt.expected)
+			if got := ContainsPromptInjection(tt.text); got != tt.expected {
+				t.Errorf("ContainsPromptInjection(%q) = %v, want %v", tt.text, got, tt.expected)
 			}
 		})
 	}
@@ -8,6 +8,7 @@ import (
 	"io"
 	"log/slog"
 	"net/http"
+	"sync/atomic"
 )
 
 const defaultWhatsAppBaseURL = "https://graph.facebook.com"
@@ -19,6 +20,7 @@ type WhatsAppChannel struct {
 	verifyToken string
 	baseURL     string
 	client      *http.Client
+	verified    atomic.Bool
 }
 
 // NewWhatsAppChannel creates a WhatsApp channel adapter.
@@ -68,6 +70,12 @@ func (w *WhatsAppChannel) Stop() error {
 	return nil
 }
 
+// WebhookVerified reports whether Meta has completed the verification
+// handshake or delivered an update since startup.
+func (w *WhatsAppChannel) WebhookVerified() bool {
+	return w.verified.Load()
+}
+
 // WebhookHandler returns an http.Handler for the WhatsApp webhook endpoint.
 // GET requests handle verification; POST requests handle inbound messages.
 func (w *WhatsAppChannel) WebhookHandler(handler func(InboundMessage)) http.Handler {
@@ -90,6 +98,7 @@ func (w *WhatsAppChannel) handleVerification(rw http.ResponseWriter, r *http.Req
 	challenge := r.URL.Query().Get("hub.challenge")
 
 	if mode == "subscribe" && token == w.verifyToken {
+		w.verified.Store(true)
 		rw.WriteHeader(http.StatusOK)
 		_, _ = rw.Write([]byte(challenge))
 		return
@@ -116,6 +125,7 @@ func (w *WhatsAppChannel) handleInbound(rw http.ResponseWriter, r *http.Request,
 
 	// Always respond 200 to avoid retries.
 	rw.WriteHeader(http.StatusOK)
+	w.verified.Store(true)
 
 	for _, entry := range payload.Entry {
 		for _, change := range entry.Changes {
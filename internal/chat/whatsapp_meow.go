@@ -4,6 +4,7 @@ import (
 	"context"
 	"encoding/base64"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"log/slog"
 	"net/http"
@@ -28,8 +29,9 @@ type WhatsAppMeowChannel struct {
 	client    *whatsmeow.Client
 	container *sqlstore.Container
 
-	handler func(InboundMessage)
-	mu      sync.RWMutex
+	handler    func(InboundMessage)
+	reportLoop func(error)
+	mu         sync.RWMutex
 
 	// latestQR holds the current QR code string for the HTTP endpoint.
 	latestQR string
@@ -240,11 +242,50 @@ func (w *WhatsAppMeowChannel) StatusHandler() http.Handler {
 	})
 }
 
+// SetLoopHealthReporter receives socket health: keepalive timeouts and
+// disconnects count as failures, a (re)connect as success.
+func (w *WhatsAppMeowChannel) SetLoopHealthReporter(report func(error)) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.reportLoop = report
+}
+
+// Restart drops the socket and reconnects an authenticated session. An
+// unpaired client is left to the QR flow.
+func (w *WhatsAppMeowChannel) Restart(_ context.Context, handler func(InboundMessage)) error {
+	w.mu.Lock()
+	w.handler = handler
+	w.mu.Unlock()
+	if w.client.Store.ID == nil {
+		return fmt.Errorf("whatsmeow restart: session not paired")
+	}
+	w.client.Disconnect()
+	if err := w.client.Connect(); err != nil {
+		return fmt.Errorf("whatsmeow reconnect: %w", err)
+	}
+	return nil
+}
+
+func (w *WhatsAppMeowChannel) report(err error) {
+	w.mu.RLock()
+	report := w.reportLoop
+	w.mu.RUnlock()
+	if report != nil {
+		report(err)
+	}
+}
+
 // eventHandler processes whatsmeow events and dispatches inbound messages.
 func (w *WhatsAppMeowChannel) eventHandler(evt interface{}) {
 	switch v := evt.(type) {
 	case *events.Message:
 		w.handleMessage(v)
+	case *events.Connected, *events.KeepAliveRestored:
+		w.report(nil)
+	case *events.KeepAliveTimeout:
+		w.report(fmt.Errorf("keepalive timeout (%d in a row)", v.ErrorCount))
+	case *events.Disconnected:
+		w.report(errors.New("disconnected"))
 	}
 }
 
@@ -8,6 +8,7 @@ import (
 	"log/slog"
 	"os"
 	"path/filepath"
+	"reflect"
 	"strings"
 	"sync"
 
@@ -22,7 +23,10 @@ type Loader struct {
 	syllabi       map[string]Syllabus
 	assessments   map[string]Assessment
 	teachingNotes map[string]string
-	mu            sync.RWMutex
+	overlays      map[string]TeachingNoteOverlay
+	// version changes whenever loaded content changes at runtime.
+	version uint64
+	mu      sync.RWMutex
 }
 
 // NewLoader creates a new curriculum loader and loads all content.
@@ -34,6 +38,7 @@ func NewLoader(rootDir string) (*Loader, error) {
 		syllabi:       make(map[string]Syllabus),
 		assessments:   make(map[string]Assessment),
 		teachingNotes: make(map[string]string),
+		overlays:      make(map[string]TeachingNoteOverlay),
 	}
 
 	if err := l.loadAll(); err != nil {
@@ -44,6 +49,15 @@ func NewLoader(rootDir string) (*Loader, error) {
 	return l, nil
 }
 
+// Version identifies the current content. It changes when an overlay edit
+// alters what GetTeachingNotes returns, or a Reload finds new content, so
+// callers can key caches on it.
+func (l *Loader) Version() uint64 {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+	return l.version
+}
+
 // GetTopic returns a topic by ID.
 func (l *Loader) GetTopic(id string) (Topic, bool) {
 	l.mu.RLock()
@@ -68,11 +82,15 @@ func (l *Loader) GetSyllabus(id string) (Syllabus, bool) {
 	return syllabus, ok
 }
 
-// GetTeachingNotes returns teaching notes for a topic ID.
+// GetTeachingNotes returns teaching notes for a topic ID, followed by any
+// school overlay set with SetTeachingNoteOverlay.
 func (l *Loader) GetTeachingNotes(id string) (string, bool) {
 	l.mu.RLock()
 	defer l.mu.RUnlock()
 	n, ok := l.teachingNotes[id]
+	if overlay, has := l.overlays[id]; has {
+		return appendTeachingNoteOverlay(n, overlay), true
+	}
 	return n, ok
 }
 
@@ -95,6 +113,42 @@ func (l *Loader) AllTopics() []Topic {
 	return topics
 }
 
+// Reload re-reads the curriculum from disk and swaps it in. Overlays are
+// kept. Version changes only when the content did, so caches keyed on it
+// survive a reload that found nothing new. On error the current content
+// stays in place.
+func (l *Loader) Reload() (changed bool, err error) {
+	fresh := &Loader{
+		rootDir:       l.rootDir,
+		topics:        make(map[string]Topic),
+		subjects:      make(map[string]Subject),
+		syllabi:       make(map[string]Syllabus),
+		assessments:   make(map[string]Assessment),
+		teachingNotes: make(map[string]string),
+	}
+	if err := fresh.loadAll(); err != nil {
+		return false, fmt.Errorf("reloading curriculum: %w", err)
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	changed = !reflect.DeepEqual(l.topics, fresh.topics) ||
+		!reflect.DeepEqual(l.subjects, fresh.subjects) ||
+		!reflect.DeepEqual(l.syllabi, fresh.syllabi) ||
+		!reflect.DeepEqual(l.assessments, fresh.assessments) ||
+		!reflect.DeepEqual(l.teachingNotes, fresh.teachingNotes)
+	if !changed {
+		return false, nil
+	}
+	l.topics = fresh.topics
+	l.subjects = fresh.subjects
+	l.syllabi = fresh.syllabi
+	l.assessments = fresh.assessments
+	l.teachingNotes = fresh.teachingNotes
+	l.version++
+	return true, nil
+}
+
 func (l *Loader) loadAll() error {
 	return filepath.Walk(l.rootDir, func(path string, info os.FileInfo, err error) error {
 		if err != nil || info.IsDir() {
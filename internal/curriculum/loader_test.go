@@ -6,6 +6,7 @@ package curriculum_test
 import (
 	"os"
 	"path/filepath"
+	"strings"
 	"testing"
 
 	"github.com/p-n-ai/pai-bot/internal/curriculum"
@@ -196,6 +197,38 @@ func TestLoader_TeachingNotesWithoutYAML(t *testing.T) {
 	}
 }
 
+func TestLoader_ReloadPicksUpChangedContent(t *testing.T) {
+	dir := setupTestCurriculum(t)
+	loader, err := curriculum.NewLoader(dir)
+	if err != nil {
+		t.Fatalf("NewLoader() error = %v", err)
+	}
+	loader.SetTeachingNoteOverlay("F1-01", curriculum.TeachingNoteOverlay{Notes: "Use the school's balance-scale kit."})
+	version := loader.Version()
+
+	if changed, err := loader.Reload(); err != nil || changed {
+		t.Fatalf("Reload() = %v, %v; want no change for unchanged files", changed, err)
+	}
+	if loader.Version() != version {
+		t.Fatal("Version() changed on a reload that found nothing new")
+	}
+
+	notesPath := filepath.Join(dir, "curricula", "malaysia", "kssm", "topics", "algebra", "01-variables.teaching.md")
+	if err := os.WriteFile(notesPath, []byte("# Variables — revised notes"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if changed, err := loader.Reload(); err != nil || !changed {
+		t.Fatalf("Reload() = %v, %v; want a change", changed, err)
+	}
+	if loader.Version() == version {
+		t.Fatal("Version() unchanged after a reload with new notes")
+	}
+	notes, _ := loader.GetTeachingNotes("F1-01")
+	if !strings.Contains(notes, "revised notes") || !strings.Contains(notes, "balance-scale kit") {
+		t.Fatalf("notes = %q, want the revised notes with the overlay kept", notes)
+	}
+}
+
 func setupTestCurriculum(t *testing.T) string {
 	t.Helper()
 	dir := t.TempDir()
@@ -0,0 +1,91 @@
+// Copyright 2026 the P&AI authors. All rights reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package curriculum
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/p-n-ai/pai-bot/internal/apperr"
+)
+
+const (
+	// MaxOverlayNotesBytes bounds school notes so one topic cannot crowd the
+	// tutor prompt.
+	MaxOverlayNotesBytes = 8000
+	// MaxOverlayExamples bounds worked examples per topic.
+	MaxOverlayExamples = 10
+)
+
+// ErrInvalidOverlay reports a teaching note overlay that does not fit the
+// curriculum schema.
+var ErrInvalidOverlay = apperr.UserInput(errors.New("invalid teaching note overlay"))
+
+// ValidateTeachingNoteOverlay checks that overlay targets a loaded topic and
+// that its notes and examples are complete and within limits.
+func (l *Loader) ValidateTeachingNoteOverlay(topicID string, overlay TeachingNoteOverlay) error {
+	if _, ok := l.GetTopic(topicID); !ok {
+		return fmt.Errorf("%w: unknown topic %q", ErrInvalidOverlay, topicID)
+	}
+	if strings.TrimSpace(overlay.Notes) == "" && len(overlay.Examples) == 0 {
+		return fmt.Errorf("%w: notes or examples are required", ErrInvalidOverlay)
+	}
+	if len(overlay.Notes) > MaxOverlayNotesBytes {
+		return fmt.Errorf("%w: notes exceed %d bytes", ErrInvalidOverlay, MaxOverlayNotesBytes)
+	}
+	if len(overlay.Examples) > MaxOverlayExamples {
+		return fmt.Errorf("%w: more than %d examples", ErrInvalidOverlay, MaxOverlayExamples)
+	}
+	for i, ex := range overlay.Examples {
+		if strings.TrimSpace(ex.Problem) == "" {
+			return fmt.Errorf("%w: example %d has no problem", ErrInvalidOverlay, i+1)
+		}
+		if strings.TrimSpace(ex.Answer) == "" {
+			return fmt.Errorf("%w: example %d has no answer", ErrInvalidOverlay, i+1)
+		}
+		for _, step := range ex.Steps {
+			if strings.TrimSpace(step) == "" {
+				return fmt.Errorf("%w: example %d has an empty step", ErrInvalidOverlay, i+1)
+			}
+		}
+	}
+	return nil
+}
+
+// SetTeachingNoteOverlay replaces the school overlay for a topic. It takes
+// effect on the next GetTeachingNotes call, so edits need no reload. An
+// empty overlay removes it.
+func (l *Loader) SetTeachingNoteOverlay(topicID string, overlay TeachingNoteOverlay) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.version++
+	if strings.TrimSpace(overlay.Notes) == "" && len(overlay.Examples) == 0 {
+		delete(l.overlays, topicID)
+		return
+	}
+	l.overlays[topicID] = overlay
+}
+
+// appendTeachingNoteOverlay renders overlay as markdown sections after the
+// OSS notes, so prompts see school content alongside the shared baseline.
+func appendTeachingNoteOverlay(notes string, overlay TeachingNoteOverlay) string {
+	var b strings.Builder
+	b.WriteString(strings.TrimRight(notes, "\n"))
+	if text := strings.TrimSpace(overlay.Notes); text != "" {
+		b.WriteString("\n\n## School Notes\n\n")
+		b.WriteString(text)
+	}
+	if len(overlay.Examples) > 0 {
+		b.WriteString("\n\n## School Worked Examples")
+		for i, ex := range overlay.Examples {
+			fmt.Fprintf(&b, "\n\n### Example %d\n\n%s\n", i+1, strings.TrimSpace(ex.Problem))
+			for j, step := range ex.Steps {
+				fmt.Fprintf(&b, "\n%d. %s", j+1, strings.TrimSpace(step))
+			}
+			fmt.Fprintf(&b, "\n\n**Answer:** %s", strings.TrimSpace(ex.Answer))
+		}
+	}
+	return strings.TrimLeft(b.String(), "\n") + "\n"
+}
@@ -0,0 +1,74 @@
+// Copyright 2026 the P&AI authors. All rights reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package curriculum_test
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/p-n-ai/pai-bot/internal/curriculum"
+)
+
+func TestLoader_TeachingNoteOverlay(t *testing.T) {
+	loader, err := curriculum.NewLoader(setupTestCurriculum(t))
+	if err != nil {
+		t.Fatalf("NewLoader() error = %v", err)
+	}
+	base, _ := loader.GetTeachingNotes("F1-01")
+
+	loader.SetTeachingNoteOverlay("F1-01", curriculum.TeachingNoteOverlay{
+		Notes: "Our Form 1 classes use durian prices for word problems.",
+		Examples: []curriculum.WorkedExample{{
+			Problem: "A durian costs RM x. Write the cost of 3 durians.",
+			Steps:   []string{"One durian is x.", "Three durians are 3 times x."},
+			Answer:  "3x",
+		}},
+	})
+	notes, found := loader.GetTeachingNotes("F1-01")
+	if !found || !strings.HasPrefix(notes, strings.TrimRight(base, "\n")) {
+		t.Fatalf("GetTeachingNotes() = %q, want the OSS notes first", notes)
+	}
+	for _, want := range []string{"## School Notes", "durian prices", "### Example 1", "2. Three durians", "**Answer:** 3x"} {
+		if !strings.Contains(notes, want) {
+			t.Errorf("GetTeachingNotes() missing %q:\n%s", want, notes)
+		}
+	}
+
+	loader.SetTeachingNoteOverlay("F1-01", curriculum.TeachingNoteOverlay{})
+	if notes, _ := loader.GetTeachingNotes("F1-01"); notes != base {
+		t.Errorf("notes after clearing overlay = %q, want the OSS notes", notes)
+	}
+}
+
+func TestLoader_ValidateTeachingNoteOverlay(t *testing.T) {
+	loader, err := curriculum.NewLoader(setupTestCurriculum(t))
+	if err != nil {
+		t.Fatalf("NewLoader() error = %v", err)
+	}
+	tests := []struct {
+		name    string
+		topicID string
+		overlay curriculum.TeachingNoteOverlay
+		wantErr bool
+	}{
+		{"notes only", "F1-01", curriculum.TeachingNoteOverlay{Notes: "Use local examples."}, false},
+		{"unknown topic", "F9-99", curriculum.TeachingNoteOverlay{Notes: "x"}, true},
+		{"empty", "F1-01", curriculum.TeachingNoteOverlay{Notes: "  "}, true},
+		{"too long", "F1-01", curriculum.TeachingNoteOverlay{Notes: strings.Repeat("a", curriculum.MaxOverlayNotesBytes+1)}, true},
+		{"example without answer", "F1-01", curriculum.TeachingNoteOverlay{Examples: []curriculum.WorkedExample{{Problem: "2x = 4"}}}, true},
+		{"blank step", "F1-01", curriculum.TeachingNoteOverlay{Examples: []curriculum.WorkedExample{{Problem: "2x = 4", Steps: []string{""}, Answer: "x = 2"}}}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := loader.ValidateTeachingNoteOverlay(tt.topicID, tt.overlay)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ValidateTeachingNoteOverlay() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err != nil && !errors.Is(err, curriculum.ErrInvalidOverlay) {
+				t.Errorf("error = %v, want ErrInvalidOverlay", err)
+			}
+		})
+	}
+}
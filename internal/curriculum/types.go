@@ -98,3 +98,17 @@ type AssessmentDistractor struct {
 	Value    string `yaml:"value"`
 	Feedback string `yaml:"feedback"`
 }
+
+// TeachingNoteOverlay is school-specific teaching content a tenant layers on
+// top of a topic's OSS teaching notes.
+type TeachingNoteOverlay struct {
+	Notes    string          `json:"notes,omitempty" yaml:"notes"`
+	Examples []WorkedExample `json:"examples,omitempty" yaml:"examples"`
+}
+
+// WorkedExample is one problem worked through step by step.
+type WorkedExample struct {
+	Problem string   `json:"problem" yaml:"problem"`
+	Steps   []string `json:"steps,omitempty" yaml:"steps"`
+	Answer  string   `json:"answer" yaml:"answer"`
+}
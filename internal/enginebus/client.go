@@ -0,0 +1,67 @@
+// Copyright 2026 the P&AI authors. All rights reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package enginebus
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/nats-io/nats.go"
+)
+
+// DefaultClientTimeout applies when the caller's context has no deadline.
+const DefaultClientTimeout = 60 * time.Second
+
+// Requester sends one NATS request; *nats.Conn implements it.
+type Requester interface {
+	RequestMsgWithContext(ctx context.Context, msg *nats.Msg) (*nats.Msg, error)
+}
+
+// Client submits turns to the engine for one tenant.
+type Client struct {
+	conn    Requester
+	subject string
+}
+
+// NewClient creates a client for tenantID's turn subject.
+func NewClient(conn Requester, tenantID string) *Client {
+	return &Client{conn: conn, subject: TurnSubject(tenantID)}
+}
+
+// SubmitTurn sends req and waits for the reply. The context deadline, or
+// DefaultClientTimeout, is forwarded as the request's timeout so the
+// responder stops when the caller stops waiting. A turn the engine rejected
+// or could not finish is returned as a *TurnError.
+func (c *Client) SubmitTurn(ctx context.Context, req TurnRequest) (*TurnResponse, error) {
+	if _, ok := ctx.Deadline(); !ok {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, DefaultClientTimeout)
+		defer cancel()
+	}
+	deadline, _ := ctx.Deadline()
+	req.SchemaVersion = SchemaVersion
+	req.TimeoutMS = max(time.Until(deadline).Milliseconds(), 1)
+
+	data, err := json.Marshal(req)
+	if err != nil {
+		return nil, fmt.Errorf("encode turn request: %w", err)
+	}
+	msg := &nats.Msg{Subject: c.subject, Data: data, Header: nats.Header{}}
+	msg.Header.Set(TraceParentHeader, childTraceParent(TraceParentFromContext(ctx)))
+
+	reply, err := c.conn.RequestMsgWithContext(ctx, msg)
+	if err != nil {
+		return nil, fmt.Errorf("request engine turn: %w", err)
+	}
+	var resp TurnResponse
+	if err := json.Unmarshal(reply.Data, &resp); err != nil {
+		return nil, fmt.Errorf("decode turn response: %w", err)
+	}
+	if resp.Error != nil {
+		return &resp, resp.Error
+	}
+	return &resp, nil
+}
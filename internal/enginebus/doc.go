@@ -0,0 +1,20 @@
+// Copyright 2026 the P&AI authors. All rights reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+// Package enginebus exposes the agent engine over NATS request/reply so other
+// services (web backend, workers) can submit tutor turns without sharing the
+// bot process.
+//
+// Each tenant has one subject, TurnSubject(tenantID). Server replicas join the
+// QueueGroup, so a request reaches exactly one of them. Payloads are JSON and
+// described by the schemas in schema/; requests are validated against
+// RequestSchema before the engine sees them.
+//
+// Tracing uses the W3C traceparent header. The responder keeps the caller's
+// trace ID, starts its own span ID, logs the trace ID with the turn, and
+// returns its traceparent on the reply.
+//
+// InboundSharder spreads chat traffic across replicas: each user is owned by
+// one replica on a consistent-hash ring, and messages arriving elsewhere are
+// forwarded to it so that user's turns stay ordered.
+package enginebus
@@ -0,0 +1,154 @@
+// Copyright 2026 the P&AI authors. All rights reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package enginebus
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/nats-io/nats.go"
+	"github.com/xeipuuv/gojsonschema"
+
+	"github.com/p-n-ai/pai-bot/internal/chat"
+)
+
+type stubProcessor struct {
+	reply string
+	err   error
+	delay time.Duration
+	last  chat.InboundMessage
+}
+
+func (p *stubProcessor) ProcessMessage(ctx context.Context, msg chat.InboundMessage) (string, error) {
+	p.last = msg
+	if p.delay > 0 {
+		select {
+		case <-time.After(p.delay):
+		case <-ctx.Done():
+			return "", ctx.Err()
+		}
+	}
+	return p.reply, p.err
+}
+
+// loopbackRequester delivers client requests straight to a responder.
+type loopbackRequester struct {
+	responder *Responder
+	subject   string
+}
+
+func (l *loopbackRequester) RequestMsgWithContext(ctx context.Context, msg *nats.Msg) (*nats.Msg, error) {
+	l.subject = msg.Subject
+	data, header := l.responder.handle(ctx, msg.Data, msg.Header)
+	return &nats.Msg{Data: data, Header: header}, nil
+}
+
+func newTestResponder(t *testing.T, processor TurnProcessor, timeout time.Duration) *Responder {
+	t.Helper()
+	r, err := NewResponder(processor, "tenant-a", timeout)
+	if err != nil {
+		t.Fatalf("NewResponder() error = %v", err)
+	}
+	return r
+}
+
+func assertResponseSchema(t *testing.T, data []byte) {
+	t.Helper()
+	result, err := gojsonschema.Validate(gojsonschema.NewBytesLoader(ResponseSchema), gojsonschema.NewBytesLoader(data))
+	if err != nil || !result.Valid() {
+		t.Fatalf("response %s does not match schema: %v %v", data, err, result.Errors())
+	}
+}
+
+func TestClientSubmitTurnRoundTrip(t *testing.T) {
+	processor := &stubProcessor{reply: "Mari kita mulakan dengan pecahan."}
+	loop := &loopbackRequester{responder: newTestResponder(t, processor, time.Minute)}
+	client := NewClient(loop, "tenant-a")
+
+	parent := "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01"
+	ctx := ContextWithTraceParent(context.Background(), parent)
+	resp, err := client.SubmitTurn(ctx, TurnRequest{Channel: "web", UserID: "learner-1", Text: "ajar saya pecahan", Language: "ms"})
+	if err != nil {
+		t.Fatalf("SubmitTurn() error = %v", err)
+	}
+	if resp.Reply != processor.reply {
+		t.Fatalf("reply = %q, want %q", resp.Reply, processor.reply)
+	}
+	if resp.TraceID != "4bf92f3577b34da6a3ce929d0e0e4736" {
+		t.Fatalf("trace ID = %q, want the caller's trace continued", resp.TraceID)
+	}
+	if loop.subject != "pai.engine.turn.tenant-a" {
+		t.Fatalf("subject = %q, want tenant subject", loop.subject)
+	}
+	if processor.last.Channel != "web" || processor.last.UserID != "learner-1" || processor.last.Language != "ms" {
+		t.Fatalf("engine message = %+v, want request fields mapped", processor.last)
+	}
+}
+
+func TestResponderRejectsInvalidRequests(t *testing.T) {
+	processor := &stubProcessor{reply: "unused"}
+	r := newTestResponder(t, processor, time.Minute)
+	for name, body := range map[string]string{
+		"not json":      `{`,
+		"missing user":  `{"schema_version":1,"channel":"web","text":"hi"}`,
+		"empty turn":    `{"schema_version":1,"channel":"web","user_id":"u"}`,
+		"wrong version": `{"schema_version":2,"channel":"web","user_id":"u","text":"hi"}`,
+		"unknown field": `{"schema_version":1,"channel":"web","user_id":"u","text":"hi","admin":true}`,
+	} {
+		t.Run(name, func(t *testing.T) {
+			data, header := r.handle(context.Background(), []byte(body), nil)
+			var resp TurnResponse
+			_ = json.Unmarshal(data, &resp)
+			if resp.Error == nil || resp.Error.Code != CodeInvalidRequest {
+				t.Fatalf("response = %s, want invalid_request", data)
+			}
+			if header.Get(TraceParentHeader) == "" {
+				t.Fatal("reply should carry a traceparent even for rejected requests")
+			}
+			assertResponseSchema(t, data)
+		})
+	}
+	if processor.last.UserID != "" {
+		t.Fatal("engine should not see invalid requests")
+	}
+}
+
+func TestResponderReportsTimeoutAndEngineErrors(t *testing.T) {
+	slow := newTestResponder(t, &stubProcessor{delay: time.Second}, time.Minute)
+	data, _ := slow.handle(context.Background(), []byte(`{"schema_version":1,"channel":"web","user_id":"u","text":"hi","timeout_ms":10}`), nil)
+	if !strings.Contains(string(data), `"code":"timeout"`) {
+		t.Fatalf("response = %s, want timeout honoring the request's timeout_ms", data)
+	}
+	assertResponseSchema(t, data)
+
+	failing := newTestResponder(t, &stubProcessor{err: errors.New("provider key sk-secret rejected")}, time.Minute)
+	client := NewClient(&loopbackRequester{responder: failing}, "tenant-a")
+	_, err := client.SubmitTurn(context.Background(), TurnRequest{Channel: "web", UserID: "u", Text: "hi"})
+	var turnErr *TurnError
+	if !errors.As(err, &turnErr) || turnErr.Code != CodeEngineError {
+		t.Fatalf("SubmitTurn() error = %v, want engine_error", err)
+	}
+	if strings.Contains(turnErr.Message, "sk-secret") {
+		t.Fatalf("error message %q leaks engine internals", turnErr.Message)
+	}
+}
+
+func TestChildTraceParent(t *testing.T) {
+	fresh := childTraceParent("")
+	traceID, flags, ok := parseTraceParent(fresh)
+	if !ok || flags != "01" || len(traceID) != 32 {
+		t.Fatalf("childTraceParent(\"\") = %q, want a new sampled trace", fresh)
+	}
+	child := childTraceParent("00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-00")
+	if !strings.HasPrefix(child, "00-4bf92f3577b34da6a3ce929d0e0e4736-") || !strings.HasSuffix(child, "-00") || strings.Contains(child, "00f067aa0ba902b7") {
+		t.Fatalf("child = %q, want same trace and flags with a new span", child)
+	}
+	if got := TraceParentFromContext(ContextWithTraceParent(context.Background(), "bogus")); got != "" {
+		t.Fatalf("invalid traceparent stored as %q", got)
+	}
+}
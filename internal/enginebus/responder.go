@@ -0,0 +1,158 @@
+// Copyright 2026 the P&AI authors. All rights reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package enginebus
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"sync"
+	"time"
+
+	"github.com/nats-io/nats.go"
+
+	"github.com/p-n-ai/pai-bot/internal/chat"
+)
+
+// maxConcurrentTurns bounds turns one responder runs at once; further
+// requests wait in the subscription until a slot frees.
+const maxConcurrentTurns = 16
+
+// TurnProcessor runs one tutor turn and returns the reply text.
+// *agent.Engine implements it.
+type TurnProcessor interface {
+	ProcessMessage(ctx context.Context, msg chat.InboundMessage) (string, error)
+}
+
+// Responder answers turn requests for one tenant.
+type Responder struct {
+	processor  TurnProcessor
+	tenantID   string
+	maxTimeout time.Duration
+
+	sub      *nats.Subscription
+	inflight sync.WaitGroup
+	slots    chan struct{}
+}
+
+// NewResponder creates a responder that gives each turn at most maxTimeout.
+func NewResponder(processor TurnProcessor, tenantID string, maxTimeout time.Duration) (*Responder, error) {
+	if processor == nil {
+		return nil, errors.New("enginebus: turn processor is required")
+	}
+	if tenantID == "" {
+		return nil, errors.New("enginebus: tenant ID is required")
+	}
+	if maxTimeout <= 0 {
+		return nil, errors.New("enginebus: turn timeout must be positive")
+	}
+	return &Responder{
+		processor:  processor,
+		tenantID:   tenantID,
+		maxTimeout: maxTimeout,
+		slots:      make(chan struct{}, maxConcurrentTurns),
+	}, nil
+}
+
+// Subscribe starts answering TurnSubject(tenantID) in QueueGroup. Turns run
+// under ctx, so cancelling it aborts in-flight work.
+func (r *Responder) Subscribe(ctx context.Context, nc *nats.Conn) error {
+	sub, err := nc.QueueSubscribe(TurnSubject(r.tenantID), QueueGroup, func(msg *nats.Msg) {
+		r.slots <- struct{}{}
+		r.inflight.Add(1)
+		go func() {
+			defer func() {
+				<-r.slots
+				r.inflight.Done()
+			}()
+			data, header := r.handle(ctx, msg.Data, msg.Header)
+			if err := msg.RespondMsg(&nats.Msg{Data: data, Header: header}); err != nil {
+				slog.Warn("engine turn reply failed", "subject", msg.Subject, "error", err)
+			}
+		}()
+	})
+	if err != nil {
+		return fmt.Errorf("subscribe %s: %w", TurnSubject(r.tenantID), err)
+	}
+	r.sub = sub
+	slog.Info("engine turns available over NATS", "subject", sub.Subject, "queue", QueueGroup)
+	return nil
+}
+
+// Close stops taking requests and waits for in-flight turns to reply.
+func (r *Responder) Close() error {
+	var err error
+	if r.sub != nil {
+		err = r.sub.Drain()
+	}
+	r.inflight.Wait()
+	return err
+}
+
+// handle runs one request and returns the reply payload and headers.
+func (r *Responder) handle(ctx context.Context, data []byte, header nats.Header) ([]byte, nats.Header) {
+	traceParent := childTraceParent(header.Get(TraceParentHeader))
+	traceID, _, _ := parseTraceParent(traceParent)
+	replyHeader := nats.Header{}
+	replyHeader.Set(TraceParentHeader, traceParent)
+	resp := TurnResponse{SchemaVersion: SchemaVersion, TraceID: traceID}
+	logger := slog.With("trace_id", traceID, "tenant_id", r.tenantID)
+
+	if err := validateRequest(data); err != nil {
+		resp.Error = &TurnError{Code: CodeInvalidRequest, Message: err.Error()}
+		return encodeResponse(resp), replyHeader
+	}
+	var req TurnRequest
+	if err := json.Unmarshal(data, &req); err != nil {
+		resp.Error = &TurnError{Code: CodeInvalidRequest, Message: err.Error()}
+		return encodeResponse(resp), replyHeader
+	}
+
+	timeout := r.maxTimeout
+	if req.TimeoutMS > 0 {
+		timeout = min(timeout, time.Duration(req.TimeoutMS)*time.Millisecond)
+	}
+	turnCtx, cancel := context.WithTimeout(ContextWithTraceParent(ctx, traceParent), timeout)
+	defer cancel()
+
+	start := time.Now()
+	reply, err := r.processor.ProcessMessage(turnCtx, chat.InboundMessage{
+		Channel:      req.Channel,
+		UserID:       req.UserID,
+		Text:         req.Text,
+		Language:     req.Language,
+		ReplyToText:  req.ReplyToText,
+		HasImage:     req.ImageDataURL != "",
+		ImageDataURL: req.ImageDataURL,
+	})
+	switch {
+	case err != nil && errors.Is(turnCtx.Err(), context.DeadlineExceeded):
+		resp.Error = &TurnError{Code: CodeTimeout, Message: fmt.Sprintf("turn exceeded %s", timeout)}
+	case err != nil:
+		// The engine error may carry provider details; callers get a stable
+		// message and the trace ID to look it up in the logs.
+		logger.Error("engine turn failed", "user_id", req.UserID, "error", err)
+		resp.Error = &TurnError{Code: CodeEngineError, Message: "engine failed to process the turn"}
+	default:
+		resp.Reply = reply
+	}
+	logger.Info("engine turn served over NATS",
+		"channel", req.Channel,
+		"user_id", req.UserID,
+		"duration_ms", time.Since(start).Milliseconds(),
+		"ok", resp.Error == nil,
+	)
+	return encodeResponse(resp), replyHeader
+}
+
+func encodeResponse(resp TurnResponse) []byte {
+	b, err := json.Marshal(resp)
+	if err != nil {
+		// TurnResponse only holds strings and ints.
+		panic(fmt.Sprintf("enginebus: encode response: %v", err))
+	}
+	return b
+}
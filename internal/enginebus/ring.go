@@ -0,0 +1,62 @@
+// Copyright 2026 the P&AI authors. All rights reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package enginebus
+
+import (
+	"crypto/sha256"
+	"encoding/binary"
+	"sort"
+	"strconv"
+)
+
+// ringReplicas is the number of virtual points per node. More points spread
+// users more evenly at the cost of a slightly larger ring.
+const ringReplicas = 64
+
+// hashRing is an immutable consistent-hash ring. Adding or removing one node
+// only moves the users that node owned or now owns.
+type hashRing struct {
+	points []uint32
+	owners map[uint32]string
+}
+
+func newHashRing(nodes []string) *hashRing {
+	r := &hashRing{owners: make(map[uint32]string, len(nodes)*ringReplicas)}
+	sorted := append([]string(nil), nodes...)
+	sort.Strings(sorted)
+	for _, node := range sorted {
+		for i := 0; i < ringReplicas; i++ {
+			point := ringHash(node + "#" + strconv.Itoa(i))
+			if _, taken := r.owners[point]; taken {
+				// A collision keeps the first node in sorted order, so every
+				// replica builds the same ring.
+				continue
+			}
+			r.owners[point] = node
+			r.points = append(r.points, point)
+		}
+	}
+	sort.Slice(r.points, func(i, j int) bool { return r.points[i] < r.points[j] })
+	return r
+}
+
+// owner returns the node responsible for key, or "" for an empty ring.
+func (r *hashRing) owner(key string) string {
+	if len(r.points) == 0 {
+		return ""
+	}
+	h := ringHash(key)
+	i := sort.Search(len(r.points), func(i int) bool { return r.points[i] >= h })
+	if i == len(r.points) {
+		i = 0
+	}
+	return r.owners[r.points[i]]
+}
+
+// ringHash uses SHA-256 rather than a faster hash because node and user IDs
+// are short and similar, and spread matters more than speed here.
+func ringHash(s string) uint32 {
+	sum := sha256.Sum256([]byte(s))
+	return binary.BigEndian.Uint32(sum[:4])
+}
@@ -0,0 +1,101 @@
+// Copyright 2026 the P&AI authors. All rights reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package enginebus
+
+import (
+	_ "embed"
+	"fmt"
+	"strings"
+
+	"github.com/xeipuuv/gojsonschema"
+)
+
+// SchemaVersion is the payload version this package reads and writes.
+const SchemaVersion = 1
+
+// QueueGroup load-balances turn requests across server replicas.
+const QueueGroup = "pai-engine"
+
+// Error codes carried in TurnResponse.Error.
+const (
+	CodeInvalidRequest = "invalid_request"
+	CodeTimeout        = "timeout"
+	CodeEngineError    = "engine_error"
+)
+
+// RequestSchema and ResponseSchema are the JSON schemas for turn payloads,
+// published so non-Go services can validate what they send and receive.
+var (
+	//go:embed schema/turn_request.schema.json
+	RequestSchema []byte
+	//go:embed schema/turn_response.schema.json
+	ResponseSchema []byte
+)
+
+var requestSchema = mustSchema(RequestSchema)
+
+// TurnSubject is the NATS subject serving engine turns for one tenant.
+func TurnSubject(tenantID string) string {
+	return "pai.engine.turn." + tenantID
+}
+
+// TurnRequest is one learner message for the engine.
+type TurnRequest struct {
+	SchemaVersion int    `json:"schema_version"`
+	Channel       string `json:"channel"`
+	UserID        string `json:"user_id"`
+	Text          string `json:"text,omitempty"`
+	Language      string `json:"language,omitempty"`
+	ReplyToText   string `json:"reply_to_text,omitempty"`
+	ImageDataURL  string `json:"image_data_url,omitempty"`
+	// TimeoutMS asks the responder to give up sooner than its own limit,
+	// normally set from the caller's deadline.
+	TimeoutMS int64 `json:"timeout_ms,omitempty"`
+}
+
+// TurnResponse carries either the tutor's reply or an error.
+type TurnResponse struct {
+	SchemaVersion int        `json:"schema_version"`
+	TraceID       string     `json:"trace_id"`
+	Reply         string     `json:"reply,omitempty"`
+	Error         *TurnError `json:"error,omitempty"`
+}
+
+// TurnError is a failed turn as reported by the responder.
+type TurnError struct {
+	Code    string `json:"code"`
+	Message string `json:"message"`
+}
+
+func (e *TurnError) Error() string {
+	return fmt.Sprintf("engine turn %s: %s", e.Code, e.Message)
+}
+
+func mustSchema(raw []byte) *gojsonschema.Schema {
+	schema, err := gojsonschema.NewSchema(gojsonschema.NewBytesLoader(raw))
+	if err != nil {
+		panic(fmt.Sprintf("enginebus: invalid embedded schema: %v", err))
+	}
+	return schema
+}
+
+// validateRequest checks raw against RequestSchema and returns the first few
+// violations in one error.
+func validateRequest(raw []byte) error {
+	result, err := requestSchema.Validate(gojsonschema.NewBytesLoader(raw))
+	if err != nil {
+		return fmt.Errorf("request is not valid JSON: %w", err)
+	}
+	if result.Valid() {
+		return nil
+	}
+	var problems []string
+	for i, e := range result.Errors() {
+		if i == 3 {
+			break
+		}
+		problems = append(problems, e.String())
+	}
+	return fmt.Errorf("request does not match schema: %s", strings.Join(problems, "; "))
+}
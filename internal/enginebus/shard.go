@@ -0,0 +1,363 @@
+// Copyright 2026 the P&AI authors. All rights reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package enginebus
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/nats-io/nats.go"
+
+	"github.com/p-n-ai/pai-bot/internal/chat"
+)
+
+const (
+	defaultHeartbeatInterval = 2 * time.Second
+	defaultForwardTimeout    = 5 * time.Second
+	// missedHeartbeats is how many intervals a silent replica keeps its
+	// users before the ring drops it.
+	missedHeartbeats = 3
+)
+
+// ShardConfig configures inbound sharding for one tenant.
+type ShardConfig struct {
+	TenantID string
+	// NodeID names this replica on the ring; empty picks a random ID.
+	NodeID string
+	// Channels lists the chat channels whose turns are sharded by user. Only
+	// channels that can deliver from any replica belong here; connection-bound
+	// channels such as websocket keep handling turns where they arrive.
+	Channels          []string
+	HeartbeatInterval time.Duration
+	ForwardTimeout    time.Duration
+	// MaxPending caps the sharded turns queued or running on this replica,
+	// local and forwarded together; zero leaves them unbounded. Past it a
+	// message goes to Shed instead.
+	MaxPending int
+	// Shed is called with each message dropped for MaxPending, typically to
+	// count it and send a busy notice. It must not block.
+	Shed func(chat.InboundMessage)
+}
+
+type shardHeartbeat struct {
+	NodeID  string `json:"node_id"`
+	Leaving bool   `json:"leaving,omitempty"`
+}
+
+// InboundSharder routes each inbound message to the replica that owns its
+// user on a consistent-hash ring, so one user's turns run on one replica in
+// arrival order and the engine's per-user locks hold across instances.
+//
+// Replicas find each other through heartbeats on MembersSubject. When the
+// owner cannot be reached the message is handled locally rather than
+// dropped; ownership is only eventually consistent while replicas join or
+// leave.
+type InboundSharder struct {
+	nc       *nats.Conn
+	cfg      ShardConfig
+	handle   func(chat.InboundMessage)
+	queues   *userQueues
+	subs     []*nats.Subscription
+	stopBeat context.CancelFunc
+	beatDone chan struct{}
+
+	mu      sync.RWMutex
+	members map[string]time.Time
+	ring    *hashRing
+}
+
+// NewInboundSharder wraps handle, which runs one turn to completion, with
+// user-sharded routing.
+func NewInboundSharder(nc *nats.Conn, cfg ShardConfig, handle func(chat.InboundMessage)) (*InboundSharder, error) {
+	if nc == nil {
+		return nil, errors.New("enginebus: NATS connection is required for sharding")
+	}
+	if cfg.TenantID == "" {
+		return nil, errors.New("enginebus: tenant ID is required")
+	}
+	if handle == nil {
+		return nil, errors.New("enginebus: inbound handler is required")
+	}
+	if cfg.NodeID == "" {
+		b := make([]byte, 6)
+		_, _ = rand.Read(b)
+		cfg.NodeID = hex.EncodeToString(b)
+	}
+	if cfg.HeartbeatInterval <= 0 {
+		cfg.HeartbeatInterval = defaultHeartbeatInterval
+	}
+	if cfg.ForwardTimeout <= 0 {
+		cfg.ForwardTimeout = defaultForwardTimeout
+	}
+	s := &InboundSharder{
+		nc:      nc,
+		cfg:     cfg,
+		handle:  handle,
+		queues:  newUserQueues(handle, cfg.MaxPending),
+		members: map[string]time.Time{cfg.NodeID: time.Now()},
+	}
+	s.ring = newHashRing([]string{cfg.NodeID})
+	return s, nil
+}
+
+// MembersSubject carries replica heartbeats for a tenant.
+func MembersSubject(tenantID string) string {
+	return "pai.shard." + tenantID + ".members"
+}
+
+// NodeSubject receives messages forwarded to one replica.
+func NodeSubject(tenantID, nodeID string) string {
+	return "pai.shard." + tenantID + ".node." + nodeID
+}
+
+// NodeID returns this replica's ring identity.
+func (s *InboundSharder) NodeID() string {
+	return s.cfg.NodeID
+}
+
+// Start joins the ring and begins accepting forwarded messages.
+func (s *InboundSharder) Start(ctx context.Context) error {
+	membersSub, err := s.nc.Subscribe(MembersSubject(s.cfg.TenantID), s.onHeartbeat)
+	if err != nil {
+		return fmt.Errorf("subscribe shard members: %w", err)
+	}
+	nodeSub, err := s.nc.Subscribe(NodeSubject(s.cfg.TenantID, s.cfg.NodeID), s.onForwarded)
+	if err != nil {
+		_ = membersSub.Unsubscribe()
+		return fmt.Errorf("subscribe shard node: %w", err)
+	}
+	s.subs = []*nats.Subscription{membersSub, nodeSub}
+
+	beatCtx, cancel := context.WithCancel(ctx)
+	s.stopBeat = cancel
+	s.beatDone = make(chan struct{})
+	go func() {
+		defer close(s.beatDone)
+		s.runHeartbeats(beatCtx)
+	}()
+	slog.Info("inbound sharding started", "node_id", s.cfg.NodeID, "channels", s.cfg.Channels)
+	return nil
+}
+
+// Close announces departure so peers take over this replica's users at once,
+// then waits for queued turns to finish.
+func (s *InboundSharder) Close() {
+	if s.stopBeat != nil {
+		s.stopBeat()
+		<-s.beatDone
+	}
+	s.publishHeartbeat(true)
+	for _, sub := range s.subs {
+		_ = sub.Drain()
+	}
+	s.queues.wait()
+}
+
+// Handle is the gateway inbound handler.
+func (s *InboundSharder) Handle(msg chat.InboundMessage) {
+	if !slices.Contains(s.cfg.Channels, msg.Channel) {
+		s.handle(msg)
+		return
+	}
+	key := shardKey(msg)
+	owner := s.owner(key)
+	if owner == s.cfg.NodeID {
+		s.enqueue(key, msg)
+		return
+	}
+	if err := s.forward(owner, msg); err != nil {
+		slog.Warn("inbound forward failed; handling locally",
+			"owner", owner, "channel", msg.Channel, "user_id", msg.UserID, "error", err)
+		s.enqueue(key, msg)
+	}
+}
+
+// enqueue queues msg behind its user's earlier turns, shedding it when this
+// replica already holds MaxPending turns.
+func (s *InboundSharder) enqueue(key string, msg chat.InboundMessage) {
+	if s.queues.enqueue(key, msg) {
+		return
+	}
+	if s.cfg.Shed != nil {
+		s.cfg.Shed(msg)
+		return
+	}
+	slog.Warn("shard queue full; dropping inbound message",
+		"max_pending", s.cfg.MaxPending, "channel", msg.Channel, "user_id", msg.UserID)
+}
+
+func shardKey(msg chat.InboundMessage) string {
+	return msg.Channel + ":" + msg.UserID
+}
+
+func (s *InboundSharder) owner(key string) string {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.ring.owner(key)
+}
+
+func (s *InboundSharder) forward(owner string, msg chat.InboundMessage) error {
+	data, err := json.Marshal(msg)
+	if err != nil {
+		return fmt.Errorf("encode inbound message: %w", err)
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), s.cfg.ForwardTimeout)
+	defer cancel()
+	// The owner acks once the message is queued, not when the turn ends.
+	if _, err := s.nc.RequestWithContext(ctx, NodeSubject(s.cfg.TenantID, owner), data); err != nil {
+		return err
+	}
+	return nil
+}
+
+func (s *InboundSharder) onForwarded(m *nats.Msg) {
+	var msg chat.InboundMessage
+	if err := json.Unmarshal(m.Data, &msg); err != nil {
+		slog.Warn("dropping malformed forwarded inbound message", "error", err)
+		_ = m.Respond([]byte(`{"ok":false}`))
+		return
+	}
+	// A full queue sheds here: sharded channels deliver from any replica, so
+	// the owner sends the busy notice itself and the sender must not retry
+	// locally.
+	s.enqueue(shardKey(msg), msg)
+	_ = m.Respond([]byte(`{"ok":true}`))
+}
+
+func (s *InboundSharder) onHeartbeat(m *nats.Msg) {
+	var hb shardHeartbeat
+	if err := json.Unmarshal(m.Data, &hb); err != nil || hb.NodeID == "" || hb.NodeID == s.cfg.NodeID {
+		return
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	_, known := s.members[hb.NodeID]
+	if hb.Leaving {
+		if known {
+			delete(s.members, hb.NodeID)
+			s.rebuildRingLocked()
+		}
+		return
+	}
+	s.members[hb.NodeID] = time.Now()
+	if !known {
+		s.rebuildRingLocked()
+	}
+}
+
+func (s *InboundSharder) runHeartbeats(ctx context.Context) {
+	ticker := time.NewTicker(s.cfg.HeartbeatInterval)
+	defer ticker.Stop()
+	s.publishHeartbeat(false)
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case now := <-ticker.C:
+			s.publishHeartbeat(false)
+			s.expireMembers(now)
+		}
+	}
+}
+
+func (s *InboundSharder) publishHeartbeat(leaving bool) {
+	data, _ := json.Marshal(shardHeartbeat{NodeID: s.cfg.NodeID, Leaving: leaving})
+	if err := s.nc.Publish(MembersSubject(s.cfg.TenantID), data); err != nil {
+		slog.Warn("shard heartbeat failed", "node_id", s.cfg.NodeID, "error", err)
+	}
+}
+
+func (s *InboundSharder) expireMembers(now time.Time) {
+	cutoff := now.Add(-missedHeartbeats * s.cfg.HeartbeatInterval)
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	changed := false
+	for node, seen := range s.members {
+		if node != s.cfg.NodeID && seen.Before(cutoff) {
+			delete(s.members, node)
+			changed = true
+		}
+	}
+	if changed {
+		s.rebuildRingLocked()
+	}
+}
+
+func (s *InboundSharder) rebuildRingLocked() {
+	nodes := make([]string, 0, len(s.members))
+	for node := range s.members {
+		nodes = append(nodes, node)
+	}
+	s.ring = newHashRing(nodes)
+	slog.Info("shard ring updated", "node_id", s.cfg.NodeID, "members", len(nodes))
+}
+
+// userQueues runs each key's messages one at a time in arrival order, with
+// one goroutine per key that has pending work. With a limit, at most that
+// many messages are queued or running at once, which also bounds the
+// goroutines.
+type userQueues struct {
+	handle  func(chat.InboundMessage)
+	limit   int
+	mu      sync.Mutex
+	pending map[string][]chat.InboundMessage
+	// inFlight counts messages queued or running across all keys.
+	inFlight int
+	running  sync.WaitGroup
+}
+
+func newUserQueues(handle func(chat.InboundMessage), limit int) *userQueues {
+	return &userQueues{handle: handle, limit: limit, pending: make(map[string][]chat.InboundMessage)}
+}
+
+// enqueue reports false, without queueing msg, when the queues are full.
+func (q *userQueues) enqueue(key string, msg chat.InboundMessage) bool {
+	q.mu.Lock()
+	if q.limit > 0 && q.inFlight >= q.limit {
+		q.mu.Unlock()
+		return false
+	}
+	q.inFlight++
+	queue, active := q.pending[key]
+	q.pending[key] = append(queue, msg)
+	q.mu.Unlock()
+	if active {
+		return true
+	}
+	q.running.Add(1)
+	go q.drain(key)
+	return true
+}
+
+func (q *userQueues) drain(key string) {
+	defer q.running.Done()
+	for {
+		q.mu.Lock()
+		queue := q.pending[key]
+		if len(queue) == 0 {
+			delete(q.pending, key)
+			q.mu.Unlock()
+			return
+		}
+		msg := queue[0]
+		q.pending[key] = queue[1:]
+		q.mu.Unlock()
+		q.handle(msg)
+		q.mu.Lock()
+		q.inFlight--
+		q.mu.Unlock()
+	}
+}
+
+func (q *userQueues) wait() {
+	q.running.Wait()
+}
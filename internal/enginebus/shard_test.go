@@ -0,0 +1,211 @@
+// Copyright 2026 the P&AI authors. All rights reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package enginebus
+
+import (
+	"encoding/json"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/nats-io/nats.go"
+
+	"github.com/p-n-ai/pai-bot/internal/chat"
+)
+
+func TestHashRingSpreadsAndKeepsOwnership(t *testing.T) {
+	ring := newHashRing([]string{"a", "b", "c"})
+	counts := map[string]int{}
+	before := map[string]string{}
+	for i := range 3000 {
+		key := fmt.Sprintf("telegram:%d", i)
+		owner := ring.owner(key)
+		counts[owner]++
+		before[key] = owner
+	}
+	for _, node := range []string{"a", "b", "c"} {
+		if counts[node] < 600 {
+			t.Errorf("node %s owns %d of 3000 keys, want a fair share", node, counts[node])
+		}
+	}
+
+	grown := newHashRing([]string{"c", "a", "b", "d"})
+	moved := 0
+	for key, owner := range before {
+		got := grown.owner(key)
+		if got != owner {
+			moved++
+			if got != "d" {
+				t.Fatalf("key %s moved from %s to %s, want only moves to the new node", key, owner, got)
+			}
+		}
+	}
+	if moved == 0 || moved > 1300 {
+		t.Errorf("moved = %d keys after adding a node, want roughly a quarter", moved)
+	}
+}
+
+func TestHashRingEmptyHasNoOwner(t *testing.T) {
+	if got := newHashRing(nil).owner("telegram:1"); got != "" {
+		t.Errorf("owner = %q, want empty", got)
+	}
+}
+
+func TestUserQueuesKeepPerUserOrder(t *testing.T) {
+	var mu sync.Mutex
+	got := map[string][]string{}
+	q := newUserQueues(func(msg chat.InboundMessage) {
+		time.Sleep(time.Millisecond)
+		mu.Lock()
+		got[msg.UserID] = append(got[msg.UserID], msg.Text)
+		mu.Unlock()
+	}, 0)
+	for i := range 20 {
+		for _, user := range []string{"u1", "u2"} {
+			q.enqueue("telegram:"+user, chat.InboundMessage{Channel: "telegram", UserID: user, Text: fmt.Sprint(i)})
+		}
+	}
+	q.wait()
+	for _, user := range []string{"u1", "u2"} {
+		if len(got[user]) != 20 {
+			t.Fatalf("%s handled %d messages, want 20", user, len(got[user]))
+		}
+		for i, text := range got[user] {
+			if text != fmt.Sprint(i) {
+				t.Fatalf("%s message %d = %s, want arrival order", user, i, text)
+			}
+		}
+	}
+}
+
+func TestInboundSharderTracksMembership(t *testing.T) {
+	s, err := NewInboundSharder(&nats.Conn{}, ShardConfig{TenantID: "t1", NodeID: "self", Channels: []string{"telegram"}}, func(chat.InboundMessage) {})
+	if err != nil {
+		t.Fatalf("NewInboundSharder() error = %v", err)
+	}
+	heartbeat := func(node string, leaving bool) {
+		data, _ := json.Marshal(shardHeartbeat{NodeID: node, Leaving: leaving})
+		s.onHeartbeat(&nats.Msg{Data: data})
+	}
+
+	heartbeat("peer", false)
+	owners := map[string]bool{}
+	for i := range 200 {
+		owners[s.owner(fmt.Sprintf("telegram:%d", i))] = true
+	}
+	if !owners["self"] || !owners["peer"] {
+		t.Fatalf("owners = %v, want users split between self and peer", owners)
+	}
+
+	heartbeat("peer", true)
+	for i := range 200 {
+		if got := s.owner(fmt.Sprintf("telegram:%d", i)); got != "self" {
+			t.Fatalf("owner after leave = %q, want self", got)
+		}
+	}
+
+	heartbeat("quiet", false)
+	s.expireMembers(time.Now().Add(missedHeartbeats*s.cfg.HeartbeatInterval + time.Second))
+	s.mu.RLock()
+	_, stillKnown := s.members["quiet"]
+	_, selfKnown := s.members["self"]
+	s.mu.RUnlock()
+	if stillKnown || !selfKnown {
+		t.Errorf("members after expiry: quiet=%v self=%v, want quiet dropped and self kept", stillKnown, selfKnown)
+	}
+}
+
+func TestInboundSharderShedsPastMaxPending(t *testing.T) {
+	release := make(chan struct{})
+	var shed []string
+	s, err := NewInboundSharder(&nats.Conn{}, ShardConfig{
+		TenantID:   "t1",
+		NodeID:     "self",
+		Channels:   []string{"telegram"},
+		MaxPending: 2,
+		Shed:       func(msg chat.InboundMessage) { shed = append(shed, msg.Text) },
+	}, func(chat.InboundMessage) { <-release })
+	if err != nil {
+		t.Fatalf("NewInboundSharder() error = %v", err)
+	}
+
+	s.Handle(chat.InboundMessage{Channel: "telegram", UserID: "u1", Text: "1"})
+	s.Handle(chat.InboundMessage{Channel: "telegram", UserID: "u2", Text: "2"})
+	s.Handle(chat.InboundMessage{Channel: "telegram", UserID: "u1", Text: "3"})
+	data, _ := json.Marshal(chat.InboundMessage{Channel: "telegram", UserID: "u3", Text: "4"})
+	s.onForwarded(&nats.Msg{Data: data})
+	if len(shed) != 2 || shed[0] != "3" || shed[1] != "4" {
+		t.Fatalf("shed = %v, want local and forwarded messages past the cap", shed)
+	}
+
+	close(release)
+	s.queues.wait()
+	s.Handle(chat.InboundMessage{Channel: "telegram", UserID: "u1", Text: "5"})
+	s.queues.wait()
+	if len(shed) != 2 {
+		t.Fatalf("shed = %v, want room again once turns finish", shed)
+	}
+}
+
+func TestInboundSharderHandlesOwnedAndUnshardedLocally(t *testing.T) {
+	var mu sync.Mutex
+	var handled []string
+	s, err := NewInboundSharder(&nats.Conn{}, ShardConfig{TenantID: "t1", NodeID: "self", Channels: []string{"telegram"}}, func(msg chat.InboundMessage) {
+		mu.Lock()
+		handled = append(handled, msg.Channel+":"+msg.Text)
+		mu.Unlock()
+	})
+	if err != nil {
+		t.Fatalf("NewInboundSharder() error = %v", err)
+	}
+
+	s.Handle(chat.InboundMessage{Channel: "websocket", UserID: "u1", Text: "ws"})
+	s.Handle(chat.InboundMessage{Channel: "telegram", UserID: "u1", Text: "tg"})
+	s.queues.wait()
+
+	mu.Lock()
+	defer mu.Unlock()
+	if len(handled) != 2 || handled[0] != "websocket:ws" || handled[1] != "telegram:tg" {
+		t.Errorf("handled = %v, want websocket inline then telegram via the local queue", handled)
+	}
+}
+
+func TestInboundSharderQueuesForwardedMessages(t *testing.T) {
+	done := make(chan chat.InboundMessage, 1)
+	s, err := NewInboundSharder(&nats.Conn{}, ShardConfig{TenantID: "t1", NodeID: "self", Channels: []string{"telegram"}}, func(msg chat.InboundMessage) {
+		done <- msg
+	})
+	if err != nil {
+		t.Fatalf("NewInboundSharder() error = %v", err)
+	}
+	data, _ := json.Marshal(chat.InboundMessage{Channel: "telegram", UserID: "u9", Text: "hai"})
+	s.onForwarded(&nats.Msg{Data: data})
+
+	select {
+	case msg := <-done:
+		if msg.UserID != "u9" || msg.Text != "hai" {
+			t.Errorf("forwarded message = %+v", msg)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("forwarded message was not handled")
+	}
+}
+
+func TestNewInboundSharderValidates(t *testing.T) {
+	handle := func(chat.InboundMessage) {}
+	if _, err := NewInboundSharder(nil, ShardConfig{TenantID: "t1"}, handle); err == nil {
+		t.Error("expected error without a NATS connection")
+	}
+	if _, err := NewInboundSharder(&nats.Conn{}, ShardConfig{}, handle); err == nil {
+		t.Error("expected error without a tenant")
+	}
+	s, err := NewInboundSharder(&nats.Conn{}, ShardConfig{TenantID: "t1"}, handle)
+	if err != nil {
+		t.Fatalf("NewInboundSharder() error = %v", err)
+	}
+	if s.NodeID() == "" || s.cfg.HeartbeatInterval != defaultHeartbeatInterval || s.cfg.ForwardTimeout != defaultForwardTimeout {
+		t.Errorf("defaults not applied: %+v", s.cfg)
+	}
+}
@@ -0,0 +1,72 @@
+// Copyright 2026 the P&AI authors. All rights reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package enginebus
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"strings"
+)
+
+// TraceParentHeader is the W3C trace context header carried on NATS messages.
+const TraceParentHeader = "traceparent"
+
+type traceParentKey struct{}
+
+// ContextWithTraceParent attaches a W3C traceparent to ctx so Client requests
+// continue the caller's trace. Invalid values are ignored.
+func ContextWithTraceParent(ctx context.Context, traceParent string) context.Context {
+	if _, _, ok := parseTraceParent(traceParent); !ok {
+		return ctx
+	}
+	return context.WithValue(ctx, traceParentKey{}, traceParent)
+}
+
+// TraceParentFromContext returns the traceparent attached to ctx, if any.
+func TraceParentFromContext(ctx context.Context) string {
+	tp, _ := ctx.Value(traceParentKey{}).(string)
+	return tp
+}
+
+// parseTraceParent returns the trace ID and flags of a version-00
+// traceparent: "00-<32 hex trace id>-<16 hex span id>-<2 hex flags>".
+func parseTraceParent(tp string) (traceID, flags string, ok bool) {
+	parts := strings.Split(strings.TrimSpace(tp), "-")
+	if len(parts) != 4 || parts[0] != "00" || !isLowerHex(parts[1], 32) || !isLowerHex(parts[2], 16) || !isLowerHex(parts[3], 2) {
+		return "", "", false
+	}
+	if parts[1] == strings.Repeat("0", 32) || parts[2] == strings.Repeat("0", 16) {
+		return "", "", false
+	}
+	return parts[1], parts[3], true
+}
+
+// childTraceParent continues tp's trace with a new span ID, or starts a new
+// sampled trace when tp is missing or invalid.
+func childTraceParent(tp string) string {
+	traceID, flags, ok := parseTraceParent(tp)
+	if !ok {
+		traceID, flags = randomHex(16), "01"
+	}
+	return "00-" + traceID + "-" + randomHex(8) + "-" + flags
+}
+
+func randomHex(n int) string {
+	b := make([]byte, n)
+	_, _ = rand.Read(b)
+	return hex.EncodeToString(b)
+}
+
+func isLowerHex(s string, n int) bool {
+	if len(s) != n {
+		return false
+	}
+	for _, r := range s {
+		if (r < '0' || r > '9') && (r < 'a' || r > 'f') {
+			return false
+		}
+	}
+	return true
+}
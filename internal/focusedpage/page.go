@@ -15,6 +15,8 @@ import (
 	"net/url"
 	"strings"
 	"time"
+
+	"github.com/p-n-ai/pai-bot/internal/apperr"
 )
 
 const (
@@ -28,10 +30,10 @@ const (
 )
 
 var (
-	ErrNotFound  = errors.New("focused page not found")
-	ErrForbidden = errors.New("focused page forbidden")
-	ErrExpired   = errors.New("focused page expired")
-	ErrRevoked   = errors.New("focused page revoked")
+	ErrNotFound  = apperr.UserInput(errors.New("focused page not found"))
+	ErrForbidden = apperr.UserInput(errors.New("focused page forbidden"))
+	ErrExpired   = apperr.UserInput(errors.New("focused page expired"))
+	ErrRevoked   = apperr.UserInput(errors.New("focused page revoked"))
 )
 
 type Status string
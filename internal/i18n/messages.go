@@ -15,6 +15,13 @@ const (
 
 	MsgHelpHeader                Key = "help_header"
 	MsgTechnicalIssue            Key = "technical_issue"
+	MsgServerBusy                Key = "server_busy"
+	MsgInvalidInput              Key = "invalid_input"
+	MsgSafetyBlocked             Key = "safety_blocked"
+	MsgAccessRequested           Key = "access_requested"
+	MsgAccessPending             Key = "access_pending"
+	MsgAccessGranted             Key = "access_granted"
+	MsgAccessApproved            Key = "access_approved"
 	MsgImageProcessingFailed     Key = "image_processing_failed"
 	MsgHistoryCleared            Key = "history_cleared"
 	MsgUnknownCommand            Key = "unknown_command"
@@ -29,6 +36,9 @@ const (
 	MsgOnboardingFormUnclear     Key = "onboarding_form_unclear"
 	MsgOnboardingFormPrompt      Key = "onboarding_form_prompt"
 	MsgOnboardingCompleted       Key = "onboarding_completed"
+	MsgOnboardingFormChoice      Key = "onboarding_form_choice"
+	MsgOnboardingTopicPrompt     Key = "onboarding_topic_prompt"
+	MsgOnboardingTopicSkip       Key = "onboarding_topic_skip"
 	MsgLanguageChanged           Key = "language_changed"
 	MsgRatingThanks              Key = "rating_thanks"
 	MsgProfileReset              Key = "profile_reset"
@@ -36,6 +46,33 @@ const (
 	MsgLearnTopicNotFound        Key = "learn_topic_not_found"
 	MsgLearnTopicSet             Key = "learn_topic_set"
 	MsgTopicUnlocked             Key = "topic_unlocked"
+	MsgFeedbackPrompt            Key = "feedback_prompt"
+	MsgFeedbackUsage             Key = "feedback_usage"
+	MsgFeedbackThanks            Key = "feedback_thanks"
+	MsgPlanUsage                 Key = "plan_usage"
+	MsgPlanCleared               Key = "plan_cleared"
+	MsgPlanNoTopics              Key = "plan_no_topics"
+	MsgNextContinue              Key = "next_continue"
+	MsgNextStart                 Key = "next_start"
+	MsgNextPrerequisite          Key = "next_prerequisite"
+	MsgNextAllDone               Key = "next_all_done"
+	MsgNextSessionEnd            Key = "next_session_end"
+	MsgExamCountdown             Key = "exam_countdown"
+	MsgRevisionModeOn            Key = "revision_mode_on"
+	MsgReplyMoreHint             Key = "reply_more_hint"
+	MsgMoreNothing               Key = "more_nothing"
+	MsgSearchUsage               Key = "search_usage"
+	MsgSearchUnavailable         Key = "search_unavailable"
+	MsgSearchNoResults           Key = "search_no_results"
+	MsgSearchResultsHeader       Key = "search_results_header"
+	MsgSearchRoleLearner         Key = "search_role_learner"
+	MsgSearchRoleTutor           Key = "search_role_tutor"
+	MsgLinkCode                  Key = "link_code"
+	MsgLinkDone                  Key = "link_done"
+	MsgLinkDoneTopic             Key = "link_done_topic"
+	MsgLinkInvalid               Key = "link_invalid"
+	MsgLinkSameChat              Key = "link_same_chat"
+	MsgLinkUnavailable           Key = "link_unavailable"
 
 	MsgMilestoneTopicMastered Key = "milestone_topic_mastered"
 	MsgMilestoneXP            Key = "milestone_xp"
@@ -52,6 +89,14 @@ const (
 	MsgLeaderboardEmpty  Key = "leaderboard_empty"
 	MsgGroupClosed       Key = "group_closed"
 
+	MsgQuizLeaderboardHeader  Key = "quiz_leaderboard_header"
+	MsgLeaderboardPrivacyHint Key = "leaderboard_privacy_hint"
+	MsgLeaderboardNickSet     Key = "leaderboard_nickname_set"
+	MsgLeaderboardNickCleared Key = "leaderboard_nickname_cleared"
+	MsgLeaderboardNickInvalid Key = "leaderboard_nickname_invalid"
+	MsgLeaderboardHidden      Key = "leaderboard_hidden"
+	MsgLeaderboardShown       Key = "leaderboard_shown"
+
 	MsgChallengeComplete    Key = "challenge_complete"
 	MsgChallengeReviewOffer Key = "challenge_review_offer"
 	MsgChallengeReviewDone  Key = "challenge_review_done"
@@ -60,12 +105,77 @@ const (
 	MsgChallengeCorrect     Key = "challenge_correct"
 	MsgChallengeIncorrect   Key = "challenge_incorrect"
 	MsgChallengeReviewRetry Key = "challenge_review_retry"
+
+	MsgBadgeEarned        Key = "badge_earned"
+	MsgBadgesHeader       Key = "badges_header"
+	MsgBadgeFirstQuiz     Key = "badge_first_quiz"
+	MsgBadgeStreak7       Key = "badge_streak_7"
+	MsgBadgeTopicMastered Key = "badge_topic_mastered"
+	MsgBadgeProblems100   Key = "badge_problems_100"
+
+	MsgSubscribeFree        Key = "subscribe_free"
+	MsgSubscribePremium     Key = "subscribe_premium"
+	MsgSubscribePremiumEnds Key = "subscribe_premium_ends"
+	MsgSubscribeUsage       Key = "subscribe_usage"
+	MsgSubscribeCheckout    Key = "subscribe_checkout"
+	MsgSubscribeManage      Key = "subscribe_manage"
+	MsgDailyLimitReached    Key = "daily_limit_reached"
+
+	MsgInviteLink         Key = "invite_link"
+	MsgInviteStats        Key = "invite_stats"
+	MsgInviteUnavailable  Key = "invite_unavailable"
+	MsgReferralWelcome    Key = "referral_welcome"
+	MsgReferralFriendDone Key = "referral_friend_done"
+
+	MsgStartTopicLinked Key = "start_topic_linked"
+
+	MsgTokenBudgetReached Key = "token_budget_reached"
+
+	MsgCheckIn      Key = "check_in"
+	MsgCheckInTopic Key = "check_in_topic"
+
+	MsgWorkingOnIt Key = "working_on_it"
+
+	MsgDuplicateMessage Key = "duplicate_message"
+
+	MsgMetaFooterHint         Key = "meta_footer_hint"
+	MsgMetaFooterCheck        Key = "meta_footer_check"
+	MsgMetaFooterFullSolution Key = "meta_footer_full_solution"
+	MsgMetaFooterObjective    Key = "meta_footer_objective"
+
+	MsgDailyProblemHeader        Key = "daily_problem_header"
+	MsgDailyProblemPushHint      Key = "daily_problem_push_hint"
+	MsgDailyProblemSolve         Key = "daily_problem_solve"
+	MsgDailyProblemSolved        Key = "daily_problem_solved"
+	MsgDailyProblemAlreadySolved Key = "daily_problem_already_solved"
+	MsgDailyProblemOn            Key = "daily_problem_on"
+	MsgDailyProblemOff           Key = "daily_problem_off"
+	MsgDailyProblemNoForm        Key = "daily_problem_no_form"
+	MsgDailyProblemUnavailable   Key = "daily_problem_unavailable"
+	MsgDailyProblemSubscribeHint Key = "daily_problem_subscribe_hint"
+
+	MsgAgainNothing    Key = "again_nothing"
+	MsgAgainUsage      Key = "again_usage"
+	MsgAgainClearer    Key = "again_clearer"
+	MsgAgainAnotherWay Key = "again_another_way"
+	MsgAgainThanks     Key = "again_thanks"
+
+	MsgFrustrationCheckIn           Key = "frustration_check_in"
+	MsgFrustrationTeacherAlert      Key = "frustration_teacher_alert"
+	MsgFrustrationTeacherAlertTopic Key = "frustration_teacher_alert_topic"
 )
 
 var catalog = map[string]map[Key]string{
 	"ms": {
 		MsgHelpHeader:            "Berikut adalah arahan yang tersedia:",
 		MsgTechnicalIssue:        "Maaf, saya sedang mengalami masalah teknikal. Cuba lagi sebentar.",
+		MsgServerBusy:            "Maaf, terlalu ramai pelajar sedang bertanya sekarang. Sila hantar mesej anda semula sebentar lagi.",
+		MsgInvalidInput:          "Maaf, saya tidak dapat memproses permintaan itu. Semak semula dan cuba lagi, atau hantar /help.",
+		MsgSafetyBlocked:         "Maaf, saya tidak boleh membantu dengan itu. Mari kita kembali kepada pelajaran.",
+		MsgAccessRequested:       "Terima kasih kerana berminat dengan P&AI! Kami sedang menjalankan program rintis tertutup. Permintaan akses anda telah direkodkan dan kami akan maklumkan sebaik sahaja ia diluluskan. Jika anda ada kod jemputan, hantarkan sekarang.",
+		MsgAccessPending:         "Permintaan akses anda masih menunggu kelulusan. Kami akan maklumkan sebaik sahaja anda boleh mula belajar.",
+		MsgAccessGranted:         "Kod jemputan diterima — selamat datang ke P&AI! 🎉",
+		MsgAccessApproved:        "Berita baik! Akses anda ke P&AI telah diluluskan. Hantar /start untuk bermula.",
 		MsgImageProcessingFailed: "Saya terima gambar anda, tapi gagal memproses fail gambar itu. Cuba hantar semula gambar yang lebih jelas.",
 		MsgHistoryCleared:        "Sejarah perbualan telah dikosongkan. Hantar soalan baru untuk mula semula.",
 		MsgUnknownCommand:        "Arahan tidak diketahui: %s\nGuna /start untuk bermula, /clear untuk reset perbualan, atau /language untuk tukar bahasa.",
@@ -117,6 +227,9 @@ Balas dengan: 1, 2, atau 3.`,
 
 Tingkatan berapa anda sekarang?`,
 		MsgOnboardingCompleted:    "Bagus, anda Tingkatan %d. Sekarang hantar topik atau soalan matematik yang anda mahu belajar.",
+		MsgOnboardingFormChoice:   "Tingkatan %d",
+		MsgOnboardingTopicPrompt:  "Bagus, anda Tingkatan %d. Pilih topik pertama untuk bermula:\n%s\n\nBalas dengan nombor topik, atau hantar terus soalan matematik anda.",
+		MsgOnboardingTopicSkip:    "Langkau",
 		MsgLanguageChanged:        "Bahasa telah ditukar ke Bahasa Melayu.",
 		MsgRatingThanks:           "Terima kasih atas rating anda. Jom kita sambung.",
 		MsgProfileReset:           "Profil pembelajaran anda telah direset. Mari tetapkan semula.",
@@ -124,6 +237,33 @@ Tingkatan berapa anda sekarang?`,
 		MsgLearnTopicNotFound:     "Topik tidak dijumpai: %s\nGuna /learn <topik> dengan nama topik yang betul.",
 		MsgLearnTopicSet:          "Topik ditetapkan: %s\nMari kita mula belajar!",
 		MsgTopicUnlocked:          "Tahniah! Anda telah membuka topik baru:\n- %s\n\nGuna /learn untuk mula belajar topik ini.",
+		MsgFeedbackPrompt:         "Apa yang tidak kena? Terangkan dalam satu mesej dan saya akan hantar kepada pasukan kami.",
+		MsgFeedbackUsage:          "Guna: /feedback <mesej>\nContoh: /feedback jawapan tadi salah langkah kedua",
+		MsgFeedbackThanks:         "Terima kasih atas maklum balas anda. Pasukan kami akan menyemaknya.",
+		MsgPlanUsage:              "Guna: /plan <tarikh peperiksaan>\nContoh: /plan 2026-11-20\nTarikh mesti pada masa hadapan.",
+		MsgPlanCleared:            "Pelan ulang kaji anda telah dipadam.",
+		MsgPlanNoTopics:           "Tiada topik lemah untuk dirancang. Anda sudah menguasai semua topik tingkatan anda!",
+		MsgNextContinue:           "Sambung %s — penguasaan anda %d%%.\nHantar /learn %s untuk teruskan.",
+		MsgNextStart:              "Topik seterusnya: %s.\nHantar /learn %s untuk mula.",
+		MsgNextPrerequisite:       "Sebelum %s, mari kukuhkan %s dahulu.\nHantar /learn %s untuk mula.",
+		MsgNextAllDone:            "Anda sudah menguasai semua topik tingkatan anda! Cuba /challenge untuk menguji diri.",
+		MsgNextSessionEnd:         "Lain kali, mari kita cuba %s.",
+		MsgExamCountdown:          "⏳ %s: %d hari lagi",
+		MsgRevisionModeOn:         "Mod ulang kaji aktif: ulangan lebih kerap dan soalan gaya kertas sebenar.",
+		MsgReplyMoreHint:          "✂️ Balas /more untuk sambungannya.",
+		MsgMoreNothing:            "Tiada lagi sambungan untuk dihantar.",
+		MsgSearchUsage:            "Guna: /search <kata kunci>\nContoh: /search pecahan minggu lepas",
+		MsgSearchUnavailable:      "Carian sejarah perbualan belum tersedia di sini.",
+		MsgSearchNoResults:        "Tiada mesej ditemui untuk \"%s\".",
+		MsgSearchResultsHeader:    "🔎 Hasil carian untuk \"%s\":",
+		MsgSearchRoleLearner:      "Anda",
+		MsgSearchRoleTutor:        "Tutor",
+		MsgLinkCode:               "Kod pautan anda: %s\nHantar /link %s dari aplikasi chat anda yang lain dalam masa %d minit untuk sambung perbualan ini di sana.",
+		MsgLinkDone:               "Akaun dipautkan. Perbualan anda kini bersambung di kedua-dua aplikasi.",
+		MsgLinkDoneTopic:          "Akaun dipautkan. Kita sambung %s dari tempat anda berhenti.",
+		MsgLinkInvalid:            "Kod itu tidak sah atau sudah tamat tempoh. Hantar /link di aplikasi asal untuk dapatkan kod baharu.",
+		MsgLinkSameChat:           "Hantar kod itu dari aplikasi chat yang lain, bukan yang ini.",
+		MsgLinkUnavailable:        "Pautan akaun belum tersedia di sini.",
 		MsgMilestoneTopicMastered: "Nice, topik %s sudah makin solid. +%d XP.",
 		MsgMilestoneXP:            "Nice, anda sudah capai %d XP. Keep going.",
 		MsgMilestoneSubjectDone:   "Mantap, semua topik dalam %s sudah dikuasai.",
@@ -137,6 +277,19 @@ Tingkatan berapa anda sekarang?`,
 		MsgGroupNoGroups:          "Anda belum menyertai sebarang kumpulan.\nGuna /join <kod> untuk sertai, atau /create_group <nama> untuk buat baru.",
 		MsgLeaderboardEmpty:       "Belum ada data papan pendahulu untuk *%s*.\nTeruskan belajar dan semak semula minggu depan!",
 		MsgGroupClosed:            "*%s* tidak lagi menerima ahli baru.",
+		MsgQuizLeaderboardHeader:  "🏆 *%s — Papan Pendahulu Kuiz Mingguan*",
+		MsgLeaderboardPrivacyHint: "Guna /leaderboard nickname <nama> untuk nama samaran, atau /leaderboard hide untuk keluar dari papan.",
+		MsgLeaderboardNickSet:     "Papan pendahulu kini memaparkan anda sebagai *%s*.",
+		MsgLeaderboardNickCleared: "Nama samaran dibuang. Papan pendahulu akan memaparkan nama anda semula.",
+		MsgLeaderboardNickInvalid: "Nama samaran mesti 2–20 huruf, nombor atau ruang.\nContoh: /leaderboard nickname Wira Algebra",
+		MsgLeaderboardHidden:      "Anda tidak lagi dipaparkan di papan pendahulu. Guna /leaderboard show untuk kembali.",
+		MsgLeaderboardShown:       "Anda dipaparkan semula di papan pendahulu.",
+		MsgBadgeEarned:            "🏅 Lencana baharu: *%s*!",
+		MsgBadgesHeader:           "🏅 Lencana",
+		MsgBadgeFirstQuiz:         "🧠 Kuiz Pertama",
+		MsgBadgeStreak7:           "🔥 Streak 7 Hari",
+		MsgBadgeTopicMastered:     "⭐ Topik Dikuasai",
+		MsgBadgeProblems100:       "💯 100 Soalan",
 		MsgChallengeComplete:      "🏁 Cabaran selesai!\n\n📊 Skor: %d/%d (%d%%)",
 		MsgChallengeReviewOffer:   "Anda salah %d soalan. Mahu ulang kaji?\n\nBalas *review* untuk mula, atau apa sahaja untuk teruskan.",
 		MsgChallengeReviewDone:    "🎉 Ulang kaji selesai!\nAnda dapat %d/%d betul.\n⭐ +50 XP",
@@ -145,10 +298,68 @@ Tingkatan berapa anda sekarang?`,
 		MsgChallengeCorrect:       "✅ Betul!",
 		MsgChallengeIncorrect:     "❌ Salah\nJawapan: %s",
 		MsgChallengeReviewRetry:   "Belum tepat. Cuba lagi.",
+
+		MsgSubscribeFree:        "Pelan anda: *Percuma*.",
+		MsgSubscribePremium:     "Pelan anda: *Premium* ✨",
+		MsgSubscribePremiumEnds: "Pelan anda: *Premium* ✨ (diperbaharui pada %s)",
+		MsgSubscribeUsage:       "Token hari ini: %d daripada %d.",
+		MsgSubscribeCheckout:    "Naik taraf ke Premium untuk had harian yang lebih tinggi dan model AI yang lebih kuat:\n%s",
+		MsgSubscribeManage:      "Urus langganan anda: %s",
+		MsgDailyLimitReached:    "Anda telah mencapai had pembelajaran harian untuk pelan anda. Cuba lagi esok, atau hantar /subscribe untuk naik taraf.",
+
+		MsgInviteLink:         "Ajak rakan belajar bersama! Kongsi pautan ini:\n%s\n\nAnda dapat %d XP untuk setiap rakan yang menyertai dan selesai mendaftar.",
+		MsgInviteStats:        "Rakan yang menyertai: %d. Selesai mendaftar: %d.",
+		MsgInviteUnavailable:  "Pautan jemputan belum tersedia.",
+		MsgReferralWelcome:    "🎁 Rakan anda menjemput anda ke sini. Selesaikan pendaftaran untuk menerima %d XP bonus!",
+		MsgReferralFriendDone: "🎉 Rakan yang anda jemput telah selesai mendaftar. +%d XP!",
+
+		MsgStartTopicLinked: "📌 Selepas persediaan, kita akan terus ke *%s*.",
+
+		MsgTokenBudgetReached: "Maaf, had pembelajaran harian telah dicapai. Sila cuba lagi esok!",
+
+		MsgCheckIn:      "Hai! Saya datang bertanya khabar. Ada soalan matematik hari ini? Hantar saja, kita sambung bersama.",
+		MsgCheckInTopic: "Hai! Semalam kita belajar %s. Nak sambung hari ini? Hantar /learn %s atau terus tanya soalan.",
+
+		MsgWorkingOnIt: "⏳ Sedang saya usahakan. Jawapannya akan saya hantar sebentar lagi.",
+
+		MsgDuplicateMessage: "Mesej yang sama sampai dua kali, jadi saya jawab sekali sahaja 🙂",
+
+		MsgMetaFooterHint:         "ℹ️ Petunjuk diberi, jawapan tidak didedahkan",
+		MsgMetaFooterCheck:        "ℹ️ Jalan kerja pelajar disemak",
+		MsgMetaFooterFullSolution: "ℹ️ Penyelesaian penuh ditunjukkan",
+		MsgMetaFooterObjective:    "Objektif: %s",
+
+		MsgDailyProblemHeader:        "🧩 *Soalan Hari Ini*",
+		MsgDailyProblemPushHint:      "Tekan butang di bawah untuk menjawab, atau hantar /daily.",
+		MsgDailyProblemSolve:         "Jawab sekarang",
+		MsgDailyProblemSolved:        "🏅 Soalan hari ini selesai! Jumpa esok untuk soalan baharu.",
+		MsgDailyProblemAlreadySolved: "Anda sudah menyelesaikan soalan hari ini. Soalan baharu esok jam 8 pagi!",
+		MsgDailyProblemOn:            "✅ Anda akan menerima Soalan Hari Ini setiap pagi jam 8. Hantar /daily off untuk berhenti.",
+		MsgDailyProblemOff:           "Soalan Hari Ini dihentikan. Hantar /daily on untuk melanggan semula.",
+		MsgDailyProblemNoForm:        "Pilih tingkatan anda dahulu dengan /start, kemudian cuba /daily.",
+		MsgDailyProblemUnavailable:   "Tiada soalan hari ini untuk tingkatan anda lagi. Cuba lagi nanti!",
+		MsgDailyProblemSubscribeHint: "💡 Hantar /daily on untuk menerima soalan ini setiap pagi.",
+
+		MsgAgainNothing:    "Belum ada penerangan untuk diulang. Tanya soalan dahulu, kemudian hantar /again.",
+		MsgAgainUsage:      "Guna /again, atau pilih cara: /again analogy, /again steps, /again language.",
+		MsgAgainClearer:    "✅ Lebih jelas",
+		MsgAgainAnotherWay: "🔁 Cara lain",
+		MsgAgainThanks:     "👍 Bagus! Gembira penerangan itu membantu.",
+
+		MsgFrustrationCheckIn:           "Hai, saya perasan soalan tadi agak mencabar. Tak apa, semua orang pernah rasa begitu. Nak cuba cara lain, rehat sekejap, atau mulakan dengan soalan yang lebih mudah? Balas bila-bila masa.",
+		MsgFrustrationTeacherAlert:      "📉 %s nampak kecewa dalam sesi hari ini. Sepatah kata daripada anda mungkin membantu.",
+		MsgFrustrationTeacherAlertTopic: "📉 %s nampak kecewa semasa belajar %s hari ini. Sepatah kata daripada anda mungkin membantu.",
 	},
 	"en": {
 		MsgHelpHeader:            "Here are the available commands:",
 		MsgTechnicalIssue:        "Sorry, I'm facing a technical issue right now. Please try again shortly.",
+		MsgServerBusy:            "Sorry, lots of students are asking questions right now. Please send your message again in a moment.",
+		MsgInvalidInput:          "Sorry, I couldn't process that request. Please check it and try again, or send /help.",
+		MsgSafetyBlocked:         "Sorry, I can't help with that. Let's get back to learning.",
+		MsgAccessRequested:       "Thanks for your interest in P&AI! We're running a closed pilot right now. Your access request has been recorded and we'll let you know as soon as it's approved. If you have an invite code, send it now.",
+		MsgAccessPending:         "Your access request is still waiting for approval. We'll message you as soon as you can start learning.",
+		MsgAccessGranted:         "Invite code accepted — welcome to P&AI! 🎉",
+		MsgAccessApproved:        "Good news! Your access to P&AI has been approved. Send /start to begin.",
 		MsgImageProcessingFailed: "I received your image, but couldn't process it. Please resend a clearer image.",
 		MsgHistoryCleared:        "Conversation history has been cleared. Send a new question to start again.",
 		MsgUnknownCommand:        "Unknown command: %s\nUse /start to begin, /clear to reset, or /language to change language.",
@@ -200,6 +411,9 @@ Reply with: 1, 2, or 3.`,
 
 Which form are you in now?`,
 		MsgOnboardingCompleted:    "Great, you are Form %d. Send any math topic or question you want to learn now.",
+		MsgOnboardingFormChoice:   "Form %d",
+		MsgOnboardingTopicPrompt:  "Great, you are Form %d. Pick a first topic to start with:\n%s\n\nReply with the topic number, or just send any math question.",
+		MsgOnboardingTopicSkip:    "Skip",
 		MsgLanguageChanged:        "Language updated to English.",
 		MsgRatingThanks:           "Thanks for your rating. Let's continue.",
 		MsgProfileReset:           "Your learner profile has been reset. Let's set it up again.",
@@ -207,6 +421,33 @@ Which form are you in now?`,
 		MsgLearnTopicNotFound:     "Topic not found: %s\nUse /learn <topic> with a valid topic name.",
 		MsgLearnTopicSet:          "Topic set: %s\nLet's start learning!",
 		MsgTopicUnlocked:          "Congratulations! You've unlocked new topics:\n- %s\n\nUse /learn to start studying them.",
+		MsgFeedbackPrompt:         "What went wrong? Describe it in one message and I'll pass it to our team.",
+		MsgFeedbackUsage:          "Usage: /feedback <message>\nExample: /feedback the last answer got step two wrong",
+		MsgFeedbackThanks:         "Thanks for the feedback. Our team will review it.",
+		MsgPlanUsage:              "Usage: /plan <exam date>\nExample: /plan 2026-11-20\nThe date must be in the future.",
+		MsgPlanCleared:            "Your study plan has been removed.",
+		MsgPlanNoTopics:           "There are no weak topics to plan for. You have mastered every topic for your form!",
+		MsgNextContinue:           "Keep going with %s — you are at %d%% mastery.\nSend /learn %s to carry on.",
+		MsgNextStart:              "Next up: %s.\nSend /learn %s to start.",
+		MsgNextPrerequisite:       "Before %s, let's firm up %s.\nSend /learn %s to start.",
+		MsgNextAllDone:            "You have mastered every topic for your form! Try /challenge to test yourself.",
+		MsgNextSessionEnd:         "Next time, let's tackle %s.",
+		MsgExamCountdown:          "⏳ %s: %d days to go",
+		MsgRevisionModeOn:         "Revision mode is on: more frequent reviews and past-paper-style questions.",
+		MsgReplyMoreHint:          "✂️ Reply /more for the rest.",
+		MsgMoreNothing:            "There is nothing more to send.",
+		MsgSearchUsage:            "Usage: /search <keywords>\nExample: /search fractions last week",
+		MsgSearchUnavailable:      "Conversation search is not available here yet.",
+		MsgSearchNoResults:        "No messages found for \"%s\".",
+		MsgSearchResultsHeader:    "🔎 Results for \"%s\":",
+		MsgSearchRoleLearner:      "You",
+		MsgSearchRoleTutor:        "Tutor",
+		MsgLinkCode:               "Your link code: %s\nSend /link %s from your other chat app within %d minutes to carry this conversation over there.",
+		MsgLinkDone:               "Accounts linked. Your conversation now continues in both apps.",
+		MsgLinkDoneTopic:          "Accounts linked. Let's pick up %s where you left off.",
+		MsgLinkInvalid:            "That code is not valid or has expired. Send /link in the first app to get a new one.",
+		MsgLinkSameChat:           "Send that code from your other chat app, not this one.",
+		MsgLinkUnavailable:        "Account linking is not available here yet.",
 		MsgMilestoneTopicMastered: "Nice, %s is getting solid. +%d XP.",
 		MsgMilestoneXP:            "Nice, you hit %d XP. Keep going.",
 		MsgMilestoneSubjectDone:   "Big win, you have covered every topic in %s.",
@@ -220,6 +461,19 @@ Which form are you in now?`,
 		MsgGroupNoGroups:          "You haven't joined any groups yet.\nUse /join <code> to join, or /create_group <name> to create one.",
 		MsgLeaderboardEmpty:       "No leaderboard data yet for *%s*.\nKeep studying and check back next week!",
 		MsgGroupClosed:            "*%s* is no longer accepting new members.",
+		MsgQuizLeaderboardHeader:  "🏆 *%s — Weekly Quiz Leaderboard*",
+		MsgLeaderboardPrivacyHint: "Use /leaderboard nickname <name> to show a nickname, or /leaderboard hide to leave the board.",
+		MsgLeaderboardNickSet:     "Leaderboards now show you as *%s*.",
+		MsgLeaderboardNickCleared: "Nickname removed. Leaderboards will show your name again.",
+		MsgLeaderboardNickInvalid: "Nicknames are 2–20 letters, numbers, or spaces.\nExample: /leaderboard nickname Algebra Ace",
+		MsgLeaderboardHidden:      "You're no longer shown on leaderboards. Use /leaderboard show to come back.",
+		MsgLeaderboardShown:       "You're back on the leaderboards.",
+		MsgBadgeEarned:            "🏅 New badge: *%s*!",
+		MsgBadgesHeader:           "🏅 Badges",
+		MsgBadgeFirstQuiz:         "🧠 First Quiz",
+		MsgBadgeStreak7:           "🔥 7-Day Streak",
+		MsgBadgeTopicMastered:     "⭐ Topic Mastered",
+		MsgBadgeProblems100:       "💯 100 Problems",
 		MsgChallengeComplete:      "🏁 Challenge complete!\n\n📊 Score: %d/%d (%d%%)",
 		MsgChallengeReviewOffer:   "You missed %d question(s). Want to review them?\n\nReply *review* to start, or anything else to continue.",
 		MsgChallengeReviewDone:    "🎉 Review complete!\nYou got %d/%d correct.\n⭐ +50 XP",
@@ -228,10 +482,68 @@ Which form are you in now?`,
 		MsgChallengeCorrect:       "✅ Correct!",
 		MsgChallengeIncorrect:     "❌ Incorrect\nAnswer: %s",
 		MsgChallengeReviewRetry:   "Not quite. Try again.",
+
+		MsgSubscribeFree:        "Your plan: *Free*.",
+		MsgSubscribePremium:     "Your plan: *Premium* ✨",
+		MsgSubscribePremiumEnds: "Your plan: *Premium* ✨ (renews on %s)",
+		MsgSubscribeUsage:       "Tokens today: %d of %d.",
+		MsgSubscribeCheckout:    "Upgrade to Premium for a higher daily limit and stronger AI models:\n%s",
+		MsgSubscribeManage:      "Manage your subscription: %s",
+		MsgDailyLimitReached:    "You have reached today's learning limit for your plan. Try again tomorrow, or send /subscribe to upgrade.",
+
+		MsgInviteLink:         "Invite friends to learn with you! Share this link:\n%s\n\nYou get %d XP for each friend who joins and finishes setting up.",
+		MsgInviteStats:        "Friends joined: %d. Finished setting up: %d.",
+		MsgInviteUnavailable:  "Invite links are not available yet.",
+		MsgReferralWelcome:    "🎁 A friend invited you here. Finish setting up to get %d bonus XP!",
+		MsgReferralFriendDone: "🎉 A friend you invited finished setting up. +%d XP!",
+
+		MsgStartTopicLinked: "📌 Once you're set up, we'll jump straight into *%s*.",
+
+		MsgTokenBudgetReached: "Sorry, the daily learning limit has been reached. Please try again tomorrow!",
+
+		MsgCheckIn:      "Hi! Just checking in. Got a maths question today? Send it over and we'll work on it together.",
+		MsgCheckInTopic: "Hi! Yesterday we worked on %s. Want to pick it up again today? Send /learn %s or just ask a question.",
+
+		MsgWorkingOnIt: "⏳ Working on it. I'll send the answer here as soon as it's ready.",
+
+		MsgDuplicateMessage: "That message came through twice, so I've answered it once 🙂",
+
+		MsgMetaFooterHint:         "ℹ️ Hint given, answer not revealed",
+		MsgMetaFooterCheck:        "ℹ️ Checked the student's working",
+		MsgMetaFooterFullSolution: "ℹ️ Full solution shown",
+		MsgMetaFooterObjective:    "Objective: %s",
+
+		MsgDailyProblemHeader:        "🧩 *Problem of the Day*",
+		MsgDailyProblemPushHint:      "Tap the button below to answer, or send /daily.",
+		MsgDailyProblemSolve:         "Solve it",
+		MsgDailyProblemSolved:        "🏅 Problem of the day solved! A new one arrives tomorrow.",
+		MsgDailyProblemAlreadySolved: "You've already solved today's problem. A new one arrives tomorrow at 8 AM!",
+		MsgDailyProblemOn:            "✅ You'll get the Problem of the Day every morning at 8. Send /daily off to stop.",
+		MsgDailyProblemOff:           "Problem of the Day turned off. Send /daily on to subscribe again.",
+		MsgDailyProblemNoForm:        "Pick your form with /start first, then try /daily.",
+		MsgDailyProblemUnavailable:   "There's no problem for your form today yet. Try again later!",
+		MsgDailyProblemSubscribeHint: "💡 Send /daily on to get this every morning.",
+
+		MsgAgainNothing:    "There's no explanation to redo yet. Ask a question first, then send /again.",
+		MsgAgainUsage:      "Use /again, or pick a way: /again analogy, /again steps, /again language.",
+		MsgAgainClearer:    "✅ This is clearer",
+		MsgAgainAnotherWay: "🔁 Another way",
+		MsgAgainThanks:     "👍 Great! Glad that explanation helped.",
+
+		MsgFrustrationCheckIn:           "Hey, that last bit looked tough. That's completely normal. Want to try a different approach, take a short break, or start with an easier question? Reply whenever you're ready.",
+		MsgFrustrationTeacherAlert:      "📉 %s seems frustrated in today's session. A quick word from you may help.",
+		MsgFrustrationTeacherAlertTopic: "📉 %s seems frustrated working on %s today. A quick word from you may help.",
 	},
 	"zh": {
 		MsgHelpHeader:            "以下是可用的指令：",
 		MsgTechnicalIssue:        "抱歉，我目前遇到技术问题。请稍后再试。",
+		MsgServerBusy:            "抱歉，现在提问的同学太多了。请稍后再发送一次你的消息。",
+		MsgInvalidInput:          "抱歉，我无法处理这个请求。请检查后再试，或发送 /help。",
+		MsgSafetyBlocked:         "抱歉，这个我帮不上忙。我们回到学习上吧。",
+		MsgAccessRequested:       "感谢你对 P&AI 的关注！我们目前正在进行封闭试点。你的访问申请已记录，获批后我们会第一时间通知你。如果你有邀请码，现在就可以发送。",
+		MsgAccessPending:         "你的访问申请仍在等待审批。一旦可以开始学习，我们会立即通知你。",
+		MsgAccessGranted:         "邀请码有效，欢迎来到 P&AI！🎉",
+		MsgAccessApproved:        "好消息！你的 P&AI 访问申请已获批准。发送 /start 开始学习。",
 		MsgImageProcessingFailed: "我收到了你的图片，但暂时无法处理。请重新发送更清晰的图片。",
 		MsgHistoryCleared:        "对话记录已清除。发送新问题即可重新开始。",
 		MsgUnknownCommand:        "未知指令：%s\n使用 /start 开始，/clear 重置，或 /language 切换语言。",
@@ -283,6 +595,9 @@ Which form are you in now?`,
 
 你现在是几年级（中学）？`,
 		MsgOnboardingCompleted:    "好的，你现在是 Form %d。现在发你想学的数学题目或主题。",
+		MsgOnboardingFormChoice:   "Form %d",
+		MsgOnboardingTopicPrompt:  "好的，你现在是 Form %d。选一个主题开始吧：\n%s\n\n回复主题编号，或直接发你的数学题目。",
+		MsgOnboardingTopicSkip:    "跳过",
 		MsgLanguageChanged:        "语言已切换为中文。",
 		MsgRatingThanks:           "谢谢你的评分。我们继续。",
 		MsgProfileReset:           "你的学习档案已重置。我们重新设置一次。",
@@ -290,6 +605,33 @@ Which form are you in now?`,
 		MsgLearnTopicNotFound:     "未找到主题：%s\n请使用 /learn <主题> 并输入正确的主题名称。",
 		MsgLearnTopicSet:          "主题已设置：%s\n我们开始学习吧！",
 		MsgTopicUnlocked:          "恭喜！你已解锁新主题：\n- %s\n\n使用 /learn 开始学习。",
+		MsgFeedbackPrompt:         "哪里有问题？请用一条消息描述，我会转交给我们的团队。",
+		MsgFeedbackUsage:          "用法：/feedback <内容>\n例如：/feedback 刚才的答案第二步错了",
+		MsgFeedbackThanks:         "谢谢你的反馈，我们的团队会查看。",
+		MsgPlanUsage:              "用法：/plan <考试日期>\n例如：/plan 2026-11-20\n日期必须是将来的日期。",
+		MsgPlanCleared:            "你的复习计划已删除。",
+		MsgPlanNoTopics:           "没有需要复习的薄弱课题。你已经掌握了本年级的所有课题！",
+		MsgNextContinue:           "继续学习%s——你的掌握度是 %d%%。\n发送 /learn %s 继续。",
+		MsgNextStart:              "下一个课题：%s。\n发送 /learn %s 开始。",
+		MsgNextPrerequisite:       "学习%s之前，我们先巩固%s。\n发送 /learn %s 开始。",
+		MsgNextAllDone:            "你已经掌握了本年级的所有课题！试试 /challenge 考考自己。",
+		MsgNextSessionEnd:         "下次我们来学习%s吧。",
+		MsgExamCountdown:          "⏳ %s：还有 %d 天",
+		MsgRevisionModeOn:         "复习模式已开启：更频繁的复习和历年试卷风格的题目。",
+		MsgReplyMoreHint:          "✂️ 回复 /more 查看其余内容。",
+		MsgMoreNothing:            "没有更多内容了。",
+		MsgSearchUsage:            "用法：/search <关键词>\n例如：/search 分数 上周",
+		MsgSearchUnavailable:      "这里暂时无法搜索对话记录。",
+		MsgSearchNoResults:        "没有找到与“%s”相关的消息。",
+		MsgSearchResultsHeader:    "🔎 “%s”的搜索结果：",
+		MsgSearchRoleLearner:      "你",
+		MsgSearchRoleTutor:        "导师",
+		MsgLinkCode:               "你的绑定码：%[1]s\n请在 %[3]d 分钟内从另一个聊天应用发送 /link %[2]s，在那里继续这段对话。",
+		MsgLinkDone:               "账号已绑定。你的对话现在可以在两个应用中继续。",
+		MsgLinkDoneTopic:          "账号已绑定。我们从上次停下的地方继续学习%s。",
+		MsgLinkInvalid:            "这个绑定码无效或已过期。请在原来的应用发送 /link 获取新的绑定码。",
+		MsgLinkSameChat:           "请从另一个聊天应用发送这个绑定码，而不是这里。",
+		MsgLinkUnavailable:        "这里暂时无法绑定账号。",
 		MsgMilestoneTopicMastered: "不错，%s 已经更稳了。+%d XP。",
 		MsgMilestoneXP:            "不错，你已经达到 %d XP。继续保持。",
 		MsgMilestoneSubjectDone:   "很棒，你已经完成了 %s 的所有主题。",
@@ -303,6 +645,19 @@ Which form are you in now?`,
 		MsgGroupNoGroups:          "你还没有加入任何小组。\n使用 /join <代码> 加入，或 /create_group <名称> 创建一个。",
 		MsgLeaderboardEmpty:       "*%s* 暂无排行榜数据。\n继续学习，下周再来查看！",
 		MsgGroupClosed:            "*%s* 不再接受新成员。",
+		MsgQuizLeaderboardHeader:  "🏆 *%s — 每周测验排行榜*",
+		MsgLeaderboardPrivacyHint: "使用 /leaderboard nickname <昵称> 显示昵称，或 /leaderboard hide 退出排行榜。",
+		MsgLeaderboardNickSet:     "排行榜现在显示你为 *%s*。",
+		MsgLeaderboardNickCleared: "昵称已移除。排行榜将重新显示你的名字。",
+		MsgLeaderboardNickInvalid: "昵称须为 2–20 个字母、数字或空格。\n例如：/leaderboard nickname 代数达人",
+		MsgLeaderboardHidden:      "你已不再显示在排行榜上。使用 /leaderboard show 重新加入。",
+		MsgLeaderboardShown:       "你已重新显示在排行榜上。",
+		MsgBadgeEarned:            "🏅 新徽章：*%s*！",
+		MsgBadgesHeader:           "🏅 徽章",
+		MsgBadgeFirstQuiz:         "🧠 第一次测验",
+		MsgBadgeStreak7:           "🔥 连续 7 天",
+		MsgBadgeTopicMastered:     "⭐ 掌握主题",
+		MsgBadgeProblems100:       "💯 100 道题",
 		MsgChallengeComplete:      "🏁 挑战完成！\n\n📊 分数：%d/%d (%d%%)",
 		MsgChallengeReviewOffer:   "你答错了 %d 道题。要复习吗？\n\n回复 *review* 开始，或其他内容继续。",
 		MsgChallengeReviewDone:    "🎉 复习完成！\n你答对了 %d/%d 道题。\n⭐ +50 XP",
@@ -311,6 +666,57 @@ Which form are you in now?`,
 		MsgChallengeCorrect:       "✅ 正确！",
 		MsgChallengeIncorrect:     "❌ 不正确\n答案：%s",
 		MsgChallengeReviewRetry:   "还不对。再试一次。",
+
+		MsgSubscribeFree:        "你的方案：*免费版*。",
+		MsgSubscribePremium:     "你的方案：*高级版* ✨",
+		MsgSubscribePremiumEnds: "你的方案：*高级版* ✨（%s 续订）",
+		MsgSubscribeUsage:       "今日已用 token：%d / %d。",
+		MsgSubscribeCheckout:    "升级到高级版，获得更高的每日额度和更强的 AI 模型：\n%s",
+		MsgSubscribeManage:      "管理你的订阅：%s",
+		MsgDailyLimitReached:    "你已达到当前方案的每日学习额度。请明天再试，或发送 /subscribe 升级。",
+
+		MsgInviteLink:         "邀请朋友一起学习！分享这个链接：\n%s\n\n每位加入并完成设置的朋友都能让你获得 %d XP。",
+		MsgInviteStats:        "已加入的朋友：%d。已完成设置：%d。",
+		MsgInviteUnavailable:  "邀请链接暂不可用。",
+		MsgReferralWelcome:    "🎁 你的朋友邀请你来这里。完成设置即可获得 %d 额外 XP！",
+		MsgReferralFriendDone: "🎉 你邀请的朋友已完成设置。+%d XP！",
+
+		MsgStartTopicLinked: "📌 设置完成后，我们将直接开始学习 *%s*。",
+
+		MsgTokenBudgetReached: "抱歉，今日学习额度已用完。请明天再试！",
+
+		MsgCheckIn:      "你好！来问候一下。今天有数学问题吗？发过来，我们一起解决。",
+		MsgCheckInTopic: "你好！昨天我们学习了%s。今天要继续吗？发送 /learn %s 或直接提问。",
+
+		MsgWorkingOnIt: "⏳ 正在处理中，完成后我会马上把答案发给你。",
+
+		MsgDuplicateMessage: "这条消息收到了两次，我只回答一次 🙂",
+
+		MsgMetaFooterHint:         "ℹ️ 已给提示，未透露答案",
+		MsgMetaFooterCheck:        "ℹ️ 已检查学生的解题步骤",
+		MsgMetaFooterFullSolution: "ℹ️ 已给出完整解答",
+		MsgMetaFooterObjective:    "学习目标：%s",
+
+		MsgDailyProblemHeader:        "🧩 *每日一题*",
+		MsgDailyProblemPushHint:      "点击下方按钮作答，或发送 /daily。",
+		MsgDailyProblemSolve:         "立即作答",
+		MsgDailyProblemSolved:        "🏅 今日一题已完成！明天见新题。",
+		MsgDailyProblemAlreadySolved: "你已完成今天的题目。新题明早 8 点发布！",
+		MsgDailyProblemOn:            "✅ 你将在每天早上 8 点收到每日一题。发送 /daily off 取消。",
+		MsgDailyProblemOff:           "已关闭每日一题。发送 /daily on 重新订阅。",
+		MsgDailyProblemNoForm:        "请先用 /start 选择你的年级，再试 /daily。",
+		MsgDailyProblemUnavailable:   "今天暂时没有适合你年级的题目，请稍后再试！",
+		MsgDailyProblemSubscribeHint: "💡 发送 /daily on 每天早上收到题目。",
+
+		MsgAgainNothing:    "还没有可以重新讲解的内容。先提一个问题，再发送 /again。",
+		MsgAgainUsage:      "使用 /again，或选择方式：/again analogy、/again steps、/again language。",
+		MsgAgainClearer:    "✅ 这样更清楚",
+		MsgAgainAnotherWay: "🔁 换个方式",
+		MsgAgainThanks:     "👍 太好了！很高兴这个讲解有帮助。",
+
+		MsgFrustrationCheckIn:           "嗨，刚才的内容好像有点难。这很正常，每个人都会遇到。要换个方法、休息一下，还是先从简单的题目开始？准备好了随时回复我。",
+		MsgFrustrationTeacherAlert:      "📉 %s 在今天的学习中似乎有些沮丧。您的一句鼓励可能会有帮助。",
+		MsgFrustrationTeacherAlertTopic: "📉 %s 今天在学习%s时似乎有些沮丧。您的一句鼓励可能会有帮助。",
 	},
 }
 
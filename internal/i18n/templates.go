@@ -0,0 +1,139 @@
+// Copyright 2026 the P&AI authors. All rights reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package i18n
+
+import (
+	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+	"sync"
+)
+
+// MsgReviewReminder is the scheduled review reminder. It has no catalog
+// entry: without an override the scheduler builds the reminder itself.
+const MsgReviewReminder Key = "review_reminder"
+
+// templateVars lists the outbound messages a tenant may override and the
+// variables each one can use. For catalog messages the order matches the
+// message's printf arguments.
+var templateVars = map[Key][]string{
+	MsgStartOnboardingForm:       {"name"},
+	MsgStartOnboardingLang:       {"name"},
+	MsgStartOnboardingAutoDetect: {"name", "language"},
+	MsgReviewReminder:            {"topic", "mastery", "days_overdue", "streak"},
+}
+
+var templateVarRE = regexp.MustCompile(`\{\{\s*([a-z_]+)\s*\}\}`)
+
+// TemplateKeys returns the messages a tenant may override, sorted.
+func TemplateKeys() []Key {
+	keys := make([]Key, 0, len(templateVars))
+	for key := range templateVars {
+		keys = append(keys, key)
+	}
+	slices.Sort(keys)
+	return keys
+}
+
+// TemplateVars returns the variables key's template may use, and whether key
+// can be overridden at all.
+func TemplateVars(key Key) ([]string, bool) {
+	vars, ok := templateVars[key]
+	return slices.Clone(vars), ok
+}
+
+// ValidateTemplate checks that body is a usable override for key in locale:
+// the key is overridable, the locale is supported, and every {{variable}} is
+// one the message provides.
+func ValidateTemplate(key Key, locale, body string) error {
+	vars, ok := templateVars[key]
+	if !ok {
+		return fmt.Errorf("message %q cannot be overridden", key)
+	}
+	if _, ok := catalog[locale]; !ok {
+		return fmt.Errorf("unsupported locale %q: use en, ms, or zh", locale)
+	}
+	if strings.TrimSpace(body) == "" {
+		return fmt.Errorf("template body is required")
+	}
+	for _, match := range templateVarRE.FindAllStringSubmatch(body, -1) {
+		if !slices.Contains(vars, match[1]) {
+			return fmt.Errorf("unknown variable {{%s}}: %s can use %s", match[1], key, strings.Join(vars, ", "))
+		}
+	}
+	if strings.Contains(templateVarRE.ReplaceAllString(body, ""), "{{") {
+		return fmt.Errorf("malformed variable: write variables as {{name}}")
+	}
+	return nil
+}
+
+// Fill replaces each {{variable}} in body with its value in vars. Variables
+// without a value render empty.
+func Fill(body string, vars map[string]string) string {
+	return templateVarRE.ReplaceAllStringFunc(body, func(match string) string {
+		return vars[templateVarRE.FindStringSubmatch(match)[1]]
+	})
+}
+
+// Overrides holds one tenant's replacements for overridable messages, keyed
+// by message and locale. A nil *Overrides renders the built-in catalog.
+type Overrides struct {
+	mu     sync.RWMutex
+	bodies map[Key]map[string]string
+}
+
+// NewOverrides returns an empty override set.
+func NewOverrides() *Overrides {
+	return &Overrides{bodies: make(map[Key]map[string]string)}
+}
+
+// Set makes body the override for key in locale.
+func (o *Overrides) Set(key Key, locale, body string) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	locale = NormalizeLocale(locale)
+	if o.bodies[key] == nil {
+		o.bodies[key] = make(map[string]string)
+	}
+	o.bodies[key][locale] = body
+}
+
+// Delete restores the built-in message for key in locale.
+func (o *Overrides) Delete(key Key, locale string) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	delete(o.bodies[key], NormalizeLocale(locale))
+}
+
+// Lookup returns the override for key in locale. An override for one locale
+// never stands in for another.
+func (o *Overrides) Lookup(locale string, key Key) (string, bool) {
+	if o == nil {
+		return "", false
+	}
+	loc := NormalizeLocale(locale)
+	if loc == "" {
+		loc = DefaultLocale
+	}
+	o.mu.RLock()
+	defer o.mu.RUnlock()
+	body, ok := o.bodies[key][loc]
+	return body, ok
+}
+
+// S renders key like the package-level S, using the tenant's override when
+// one is set. vars supplies the template variables; for the catalog message
+// they become printf arguments in TemplateVars order.
+func (o *Overrides) S(locale string, key Key, vars map[string]string) string {
+	if body, ok := o.Lookup(locale, key); ok {
+		return Fill(body, vars)
+	}
+	names := templateVars[key]
+	args := make([]any, len(names))
+	for i, name := range names {
+		args[i] = vars[name]
+	}
+	return S(locale, key, args...)
+}
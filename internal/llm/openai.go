@@ -59,7 +59,11 @@ func StreamOpenAICompletions(ctx context.Context, model Model, c Context, opts *
 			req.Header.Set(k, v)
 		}
 
-		resp, err := openAIHTTPClient.Do(req)
+		client := openAIHTTPClient
+		if opts.HTTPClient != nil {
+			client = opts.HTTPClient
+		}
+		resp, err := client.Do(req)
 		if err != nil {
 			fail(err)
 			return
@@ -92,7 +92,11 @@ func StreamOpenRouterChat(ctx context.Context, model Model, c Context, opts *Str
 			httpReq.Header.Set(name, value)
 		}
 
-		resp, err := openRouterHTTPClient.Do(httpReq)
+		client := openRouterHTTPClient
+		if opts.HTTPClient != nil {
+			client = opts.HTTPClient
+		}
+		resp, err := client.Do(httpReq)
 		if err != nil {
 			fail(err)
 			return
@@ -212,6 +216,12 @@ func buildOpenRouterRequest(model Model, c Context, opts *StreamOptions) (compon
 	if opts.SessionID != "" {
 		req.SessionID = openrouter.Pointer(opts.SessionID)
 	}
+	if opts.User != "" {
+		req.User = openrouter.Pointer(opts.User)
+	}
+	if len(opts.Metadata) > 0 {
+		req.Metadata = opts.Metadata
+	}
 	if openRouterSupportsCacheControl(model) {
 		switch opts.CacheRetention {
 		case CacheRetentionShort:
@@ -311,6 +311,29 @@ func TestOpenRouterSendsShortCacheTTL(t *testing.T) {
 	}
 }
 
+func TestOpenRouterSendsUserAndMetadata(t *testing.T) {
+	srv, captured := sseServer(t, []string{
+		openRouterChunk(`{"id":"or-meta","model":"openai/gpt-test","object":"chat.completion.chunk","created":1,"choices":[{"index":0,"delta":{"content":"ok"},"finish_reason":"stop"}]}`),
+		"data: [DONE]",
+	})
+	_, err := llm.StreamOpenRouterChat(
+		context.Background(),
+		openRouterModel(srv.URL),
+		llm.Context{Messages: []llm.Message{llm.UserText("hi")}},
+		&llm.StreamOptions{APIKey: "sk-or-test", User: "hash-1", Metadata: map[string]string{"tenant": "tenant-1", "turn_id": "turn-1"}},
+	).Result()
+	if err != nil {
+		t.Fatalf("Result: %v", err)
+	}
+	if captured.body["user"] != "hash-1" {
+		t.Fatalf("user = %v, want hash-1", captured.body["user"])
+	}
+	metadata, _ := captured.body["metadata"].(map[string]any)
+	if metadata["tenant"] != "tenant-1" || metadata["turn_id"] != "turn-1" {
+		t.Fatalf("metadata = %+v", metadata)
+	}
+}
+
 func TestOpenRouterCoalescesMixedParallelToolDeltas(t *testing.T) {
 	srv, _ := sseServer(t, []string{
 		openRouterChunk(`{"id":"or-tools","model":"openai/gpt-test","object":"chat.completion.chunk","created":1,"choices":[{"index":0,"delta":{"content":"answer","reasoning":"think","tool_calls":[{"index":0,"id":"read-first","type":"function","function":{"name":"read","arguments":"{\"path\":\"README"}},{"index":1,"id":"grep-first","type":"function","function":{"name":"grep","arguments":"{\"pattern\":\"TODO"}}]},"finish_reason":null}]}`),
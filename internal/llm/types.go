@@ -3,6 +3,7 @@ package llm
 import (
 	"encoding/json"
 	"fmt"
+	"net/http"
 	"strings"
 	"time"
 )
@@ -197,6 +198,11 @@ type StreamOptions struct {
 	ReasoningEffort  ReasoningEffort
 	Headers          map[string]string
 	StructuredOutput *StructuredOutputSpec
+	// User and Metadata attribute the request for providers that accept them.
+	User     string
+	Metadata map[string]string
+	// HTTPClient sends the request; nil uses the package default.
+	HTTPClient *http.Client
 }
 
 type Model struct {
@@ -4,14 +4,22 @@
 package airouter
 
 import (
+	"context"
+	"errors"
+	"fmt"
 	"log/slog"
+	"net/http"
+	"slices"
+	"strconv"
 	"strings"
+	"sync"
+	"time"
 
 	"github.com/p-n-ai/pai-bot/internal/ai"
 	"github.com/p-n-ai/pai-bot/internal/platform/config"
 )
 
-var defaultProviderOrder = []string{"openai", "anthropic", "deepseek", "google", "ollama", "openrouter"}
+var defaultProviderOrder = []string{"openai", "anthropic", "deepseek", "groq", "google", "ollama", "openrouter"}
 
 // ProviderNames returns every provider name Apply can register.
 func ProviderNames() []string {
@@ -34,10 +42,516 @@ func Apply(router *ai.Router, cfg config.AIConfig) {
 		if !ok {
 			continue
 		}
+		taskModels, err := parseTaskModels(taskModelsFor(name, cfg))
+		if err != nil {
+			slog.Warn("ignoring invalid AI task models", "provider", name, "error", err)
+		}
+		reg.TaskModels = taskModels
+		reg.Provider = ai.WithFaults(reg.Provider, faultConfig(cfg.Fault))
 		regs = append(regs, reg)
-		slog.Info("AI provider registered", "provider", name, "model", strings.TrimSpace(reg.DefaultModel))
+		slog.Info("AI provider registered", "provider", name, "model", strings.TrimSpace(reg.DefaultModel), "task_models", len(taskModels))
+	}
+	if faults := faultConfig(cfg.Fault); faults.Enabled() {
+		slog.Warn("AI fault injection enabled",
+			"error_rate", faults.ErrorRate,
+			"slow_rate", faults.SlowRate,
+			"malformed_rate", faults.MalformedRate,
+		)
 	}
 	router.ReplaceProviders(regs)
+	// Hosted clients retry transient failures in the transport, honoring
+	// Retry-After; the router falls back instead of retrying on top.
+	router.SetTransportRetry(cfg.HTTP.Retry.MaxRetries > 0)
+
+	routes, err := parseTaskRoutes(cfg.TaskRoutes)
+	if err != nil {
+		slog.Warn("ignoring invalid AI task routes", "error", err)
+	}
+	router.SetTaskRoutes(routes)
+
+	for tier, raw := range tierTaskRoutes(cfg) {
+		routes, err := parseTaskRoutes(raw)
+		if err != nil {
+			slog.Warn("ignoring invalid AI tier task routes", "tier", tier, "error", err)
+		}
+		router.SetTierTaskRoutes(tier, routes)
+	}
+
+	prices, err := parseModelPrices(cfg.ModelPrices)
+	if err != nil {
+		slog.Warn("ignoring invalid AI model prices", "error", err)
+	}
+	router.SetModelPrices(prices)
+
+	policies, err := parseTenantModelPolicies(cfg.TenantModelAllow, cfg.TenantModelDeny)
+	if err != nil {
+		slog.Warn("ignoring invalid AI tenant model policies", "error", err)
+	}
+	router.SetTenantModelPolicies(policies)
+
+	limits, err := parseRateLimits(cfg.RateLimits)
+	if err != nil {
+		slog.Warn("ignoring invalid AI rate limits", "error", err)
+	}
+	router.SetRateLimits(limits)
+
+	taskDefaults, tenantDefaults, err := parseGenerationDefaults(cfg.TaskDefaults, cfg.TenantTaskDefaults)
+	if err != nil {
+		slog.Warn("ignoring invalid AI task generation defaults", "error", err)
+	}
+	router.SetGenerationDefaults(taskDefaults, tenantDefaults)
+	router.SetLatencyBudget(time.Duration(cfg.LatencyBudgetMS) * time.Millisecond)
+}
+
+// tierTaskRoutes maps each subscription tier to its configured routes.
+func tierTaskRoutes(cfg config.AIConfig) map[string]string {
+	return map[string]string{
+		"free":    cfg.FreeTaskRoutes,
+		"premium": cfg.PremiumTaskRoutes,
+	}
+}
+
+// Validate checks each registrable provider's per-task models and the task
+// routes, including each tier's: task and provider names must be known, and
+// models must be in the provider catalog or match its configured default
+// model. Routes to providers that would not register are allowed; they are
+// skipped at request time.
+func Validate(cfg config.AIConfig) error {
+	errs := validateTaskRoutes("task routes", cfg.TaskRoutes, cfg)
+	for tier, raw := range tierTaskRoutes(cfg) {
+		errs = append(errs, validateTaskRoutes(tier+" task routes", raw, cfg)...)
+	}
+	if _, err := parseModelPrices(cfg.ModelPrices); err != nil {
+		errs = append(errs, fmt.Errorf("model prices: %w", err))
+	}
+	if _, err := parseTenantModelPolicies(cfg.TenantModelAllow, cfg.TenantModelDeny); err != nil {
+		errs = append(errs, fmt.Errorf("tenant model policies: %w", err))
+	}
+	if _, err := parseRateLimits(cfg.RateLimits); err != nil {
+		errs = append(errs, fmt.Errorf("rate limits: %w", err))
+	}
+	if _, _, err := parseGenerationDefaults(cfg.TaskDefaults, cfg.TenantTaskDefaults); err != nil {
+		errs = append(errs, fmt.Errorf("task generation defaults: %w", err))
+	}
+	for _, name := range defaultProviderOrder {
+		raw := taskModelsFor(name, cfg)
+		if strings.TrimSpace(raw) == "" {
+			continue
+		}
+		reg, ok := buildProvider(name, cfg)
+		if !ok {
+			continue
+		}
+		taskModels, err := parseTaskModels(raw)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("%s task models: %w", name, err))
+			continue
+		}
+		for task, model := range taskModels {
+			if !knownModel(reg, model) {
+				errs = append(errs, fmt.Errorf("%s task models: %s model %q is not in the provider catalog", name, task, model))
+			}
+		}
+	}
+	return errors.Join(errs...)
+}
+
+func validateTaskRoutes(label, raw string, cfg config.AIConfig) []error {
+	var errs []error
+	routes, err := parseTaskRoutes(raw)
+	if err != nil {
+		errs = append(errs, fmt.Errorf("%s: %w", label, err))
+	}
+	for task, chain := range routes {
+		for _, route := range chain {
+			if route.Model == "" {
+				continue
+			}
+			reg, ok := buildProvider(route.Provider, cfg)
+			if !ok {
+				continue
+			}
+			if !knownModel(reg, route.Model) {
+				errs = append(errs, fmt.Errorf("%s: %s model %q is not in the %s catalog", label, task, route.Model, route.Provider))
+			}
+		}
+	}
+	return errs
+}
+
+// catalogRefreshTimeout bounds fetching a live model catalog during
+// validation.
+const catalogRefreshTimeout = 10 * time.Second
+
+// knownModel reports whether model is in reg's provider catalog or is its
+// configured default model. A provider with a live catalog, such as
+// OpenRouter, is refreshed first when the model is missing; if the catalog
+// cannot be fetched the model is accepted unchecked rather than blocking
+// startup on the provider's availability.
+func knownModel(reg ai.ProviderRegistration, model string) bool {
+	if model == strings.TrimSpace(reg.DefaultModel) || inCatalog(reg.Provider, model) {
+		return true
+	}
+	refresher, ok := reg.Provider.(ai.ModelRefresher)
+	if !ok {
+		return false
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), catalogRefreshTimeout)
+	defer cancel()
+	if err := refresher.RefreshModels(ctx); err != nil {
+		slog.Warn("model catalog unavailable; accepting model unchecked", "provider", reg.Name, "model", model, "error", err)
+		return true
+	}
+	return inCatalog(reg.Provider, model)
+}
+
+func inCatalog(provider ai.Provider, model string) bool {
+	for _, info := range provider.Models() {
+		if info.ID == model {
+			return true
+		}
+	}
+	return false
+}
+
+// parseTaskRoutes parses "task=provider[:model]|provider[:model]" entries
+// separated by commas, e.g. "grading=deepseek:deepseek-chat|openai". The
+// model is everything after the first colon, so Ollama tags such as
+// "ollama:llama3:8b" parse whole. Valid entries are kept even when others
+// fail.
+func parseTaskRoutes(raw string) (map[ai.TaskType][]ai.TaskRoute, error) {
+	var (
+		out  map[ai.TaskType][]ai.TaskRoute
+		errs []error
+	)
+	for _, entry := range strings.Split(raw, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+		taskName, chain, ok := strings.Cut(entry, "=")
+		if !ok || strings.TrimSpace(chain) == "" {
+			errs = append(errs, fmt.Errorf("invalid entry %q: want task=provider[:model]|...", entry))
+			continue
+		}
+		task, ok := ai.ParseTaskType(taskName)
+		if !ok {
+			errs = append(errs, fmt.Errorf("unknown task %q", strings.TrimSpace(taskName)))
+			continue
+		}
+		var routes []ai.TaskRoute
+		for _, hop := range strings.Split(chain, "|") {
+			provider, model, _ := strings.Cut(strings.TrimSpace(hop), ":")
+			provider = strings.ToLower(strings.TrimSpace(provider))
+			if !slices.Contains(ProviderNames(), provider) {
+				errs = append(errs, fmt.Errorf("%s: unknown provider %q", task, provider))
+				continue
+			}
+			routes = append(routes, ai.TaskRoute{Provider: provider, Model: strings.TrimSpace(model)})
+		}
+		if len(routes) == 0 {
+			continue
+		}
+		if out == nil {
+			out = make(map[ai.TaskType][]ai.TaskRoute)
+		}
+		out[task] = routes
+	}
+	return out, errors.Join(errs...)
+}
+
+// parseTaskModels parses "task=model" pairs separated by commas, e.g.
+// "nudge=gpt-5.4-mini,teaching=gpt-5.4". Valid pairs are kept even when
+// others fail.
+func parseTaskModels(raw string) (map[ai.TaskType]string, error) {
+	var (
+		out  map[ai.TaskType]string
+		errs []error
+	)
+	for _, pair := range strings.Split(raw, ",") {
+		pair = strings.TrimSpace(pair)
+		if pair == "" {
+			continue
+		}
+		taskName, model, ok := strings.Cut(pair, "=")
+		model = strings.TrimSpace(model)
+		if !ok || model == "" {
+			errs = append(errs, fmt.Errorf("invalid entry %q: want task=model", pair))
+			continue
+		}
+		task, ok := ai.ParseTaskType(taskName)
+		if !ok {
+			errs = append(errs, fmt.Errorf("unknown task %q", strings.TrimSpace(taskName)))
+			continue
+		}
+		if out == nil {
+			out = make(map[ai.TaskType]string)
+		}
+		out[task] = model
+	}
+	return out, errors.Join(errs...)
+}
+
+// parseModelPrices parses "model=input:output" entries separated by commas,
+// with prices in USD per million tokens, e.g. "gpt-5.4=1.25:10". Valid
+// entries are kept even when others fail.
+func parseModelPrices(raw string) (map[string]ai.ModelPrice, error) {
+	var (
+		out  map[string]ai.ModelPrice
+		errs []error
+	)
+	for _, entry := range strings.Split(raw, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+		model, price, ok := strings.Cut(entry, "=")
+		model = strings.ToLower(strings.TrimSpace(model))
+		input, output, hasOutput := strings.Cut(price, ":")
+		if !ok || model == "" || !hasOutput {
+			errs = append(errs, fmt.Errorf("invalid entry %q: want model=input:output", entry))
+			continue
+		}
+		in, inErr := strconv.ParseFloat(strings.TrimSpace(input), 64)
+		outPrice, outErr := strconv.ParseFloat(strings.TrimSpace(output), 64)
+		if inErr != nil || outErr != nil || in < 0 || outPrice < 0 {
+			errs = append(errs, fmt.Errorf("%s: prices must be non-negative numbers, got %q", model, price))
+			continue
+		}
+		if out == nil {
+			out = make(map[string]ai.ModelPrice)
+		}
+		out[model] = ai.ModelPrice{InputPerMTok: in, OutputPerMTok: outPrice}
+	}
+	return out, errors.Join(errs...)
+}
+
+// parseTenantModelPolicies builds each tenant's policy from the allow and
+// deny lists, both "tenant=pattern|pattern" entries separated by commas, e.g.
+// "school-a=claude-*|gpt-5.4-mini". Valid entries are kept even when others
+// fail.
+func parseTenantModelPolicies(allow, deny string) (map[string]ai.ModelPolicy, error) {
+	out := make(map[string]ai.ModelPolicy)
+	allowed, allowErr := parseTenantModelPatterns(allow)
+	for tenant, patterns := range allowed {
+		policy := out[tenant]
+		policy.Allow = patterns
+		out[tenant] = policy
+	}
+	denied, denyErr := parseTenantModelPatterns(deny)
+	for tenant, patterns := range denied {
+		policy := out[tenant]
+		policy.Deny = patterns
+		out[tenant] = policy
+	}
+	var errs []error
+	if allowErr != nil {
+		errs = append(errs, fmt.Errorf("allow: %w", allowErr))
+	}
+	if denyErr != nil {
+		errs = append(errs, fmt.Errorf("deny: %w", denyErr))
+	}
+	return out, errors.Join(errs...)
+}
+
+func parseTenantModelPatterns(raw string) (map[string][]string, error) {
+	var (
+		out  map[string][]string
+		errs []error
+	)
+	for _, entry := range strings.Split(raw, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+		tenant, list, ok := strings.Cut(entry, "=")
+		tenant = strings.TrimSpace(tenant)
+		var patterns []string
+		for _, pattern := range strings.Split(list, "|") {
+			if pattern = strings.TrimSpace(pattern); pattern != "" {
+				patterns = append(patterns, pattern)
+			}
+		}
+		if !ok || tenant == "" || len(patterns) == 0 {
+			errs = append(errs, fmt.Errorf("invalid entry %q: want tenant=pattern|...", entry))
+			continue
+		}
+		if out == nil {
+			out = make(map[string][]string)
+		}
+		out[tenant] = append(out[tenant], patterns...)
+	}
+	return out, errors.Join(errs...)
+}
+
+// parseRateLimits parses "provider=rps:N|rpm:N|tpm:N" entries separated by
+// commas, e.g. "openai=rpm:500|tpm:200000,groq=rps:2". Each entry sets any
+// of requests per second, requests per minute and tokens per minute. Valid
+// entries are kept even when others fail.
+func parseRateLimits(raw string) (map[string]ai.RateLimit, error) {
+	var (
+		out  map[string]ai.RateLimit
+		errs []error
+	)
+	for _, entry := range strings.Split(raw, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+		name, list, ok := strings.Cut(entry, "=")
+		name = strings.ToLower(strings.TrimSpace(name))
+		if !ok || name == "" || strings.TrimSpace(list) == "" {
+			errs = append(errs, fmt.Errorf("invalid entry %q: want provider=rpm:N|tpm:N", entry))
+			continue
+		}
+		if !slices.Contains(ProviderNames(), name) {
+			errs = append(errs, fmt.Errorf("%s: unknown provider", name))
+			continue
+		}
+		limit, err := parseRateLimit(list)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", name, err))
+			continue
+		}
+		if out == nil {
+			out = make(map[string]ai.RateLimit)
+		}
+		out[name] = limit
+	}
+	return out, errors.Join(errs...)
+}
+
+func parseRateLimit(raw string) (ai.RateLimit, error) {
+	var limit ai.RateLimit
+	for _, part := range strings.Split(raw, "|") {
+		kind, value, _ := strings.Cut(strings.TrimSpace(part), ":")
+		n, err := strconv.ParseFloat(strings.TrimSpace(value), 64)
+		if err != nil || n <= 0 {
+			return ai.RateLimit{}, fmt.Errorf("invalid limit %q: want rps, rpm or tpm with a positive number", part)
+		}
+		switch strings.ToLower(strings.TrimSpace(kind)) {
+		case "rps":
+			limit.RequestsPerSecond = n
+		case "rpm":
+			limit.RequestsPerMinute = int(n)
+		case "tpm":
+			limit.TokensPerMinute = int(n)
+		default:
+			return ai.RateLimit{}, fmt.Errorf("invalid limit %q: want rps, rpm or tpm with a positive number", part)
+		}
+	}
+	return limit, nil
+}
+
+// parseGenerationDefaults parses the task defaults, "task=max_tokens:N|temperature:T"
+// entries separated by commas, and the tenant overrides, the same with
+// "tenant/task" keys. Valid entries are kept even when others fail.
+func parseGenerationDefaults(tasks, tenants string) (map[ai.TaskType]ai.GenerationDefaults, map[string]map[ai.TaskType]ai.GenerationDefaults, error) {
+	var (
+		taskOut   map[ai.TaskType]ai.GenerationDefaults
+		tenantOut map[string]map[ai.TaskType]ai.GenerationDefaults
+		errs      []error
+	)
+	for _, entry := range strings.Split(tasks, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+		taskName, list, _ := strings.Cut(entry, "=")
+		task, defaults, err := parseGenerationDefaultsEntry(taskName, list)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("invalid entry %q: %w", entry, err))
+			continue
+		}
+		if taskOut == nil {
+			taskOut = make(map[ai.TaskType]ai.GenerationDefaults)
+		}
+		taskOut[task] = defaults
+	}
+	for _, entry := range strings.Split(tenants, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+		key, list, _ := strings.Cut(entry, "=")
+		tenant, taskName, ok := strings.Cut(key, "/")
+		tenant = strings.TrimSpace(tenant)
+		if !ok || tenant == "" {
+			errs = append(errs, fmt.Errorf("invalid tenant entry %q: want tenant/task=max_tokens:N|temperature:T", entry))
+			continue
+		}
+		task, defaults, err := parseGenerationDefaultsEntry(taskName, list)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("invalid tenant entry %q: %w", entry, err))
+			continue
+		}
+		if tenantOut == nil {
+			tenantOut = make(map[string]map[ai.TaskType]ai.GenerationDefaults)
+		}
+		if tenantOut[tenant] == nil {
+			tenantOut[tenant] = make(map[ai.TaskType]ai.GenerationDefaults)
+		}
+		tenantOut[tenant][task] = defaults
+	}
+	return taskOut, tenantOut, errors.Join(errs...)
+}
+
+func parseGenerationDefaultsEntry(taskName, raw string) (ai.TaskType, ai.GenerationDefaults, error) {
+	task, ok := ai.ParseTaskType(taskName)
+	if !ok {
+		return 0, ai.GenerationDefaults{}, fmt.Errorf("unknown task %q", strings.TrimSpace(taskName))
+	}
+	var defaults ai.GenerationDefaults
+	for _, part := range strings.Split(raw, "|") {
+		key, value, _ := strings.Cut(strings.TrimSpace(part), ":")
+		value = strings.TrimSpace(value)
+		switch strings.ToLower(strings.TrimSpace(key)) {
+		case "max_tokens":
+			n, err := strconv.Atoi(value)
+			if err != nil || n <= 0 {
+				return 0, ai.GenerationDefaults{}, fmt.Errorf("max_tokens must be a positive integer, got %q", value)
+			}
+			defaults.MaxTokens = n
+		case "temperature":
+			t, err := strconv.ParseFloat(value, 64)
+			if err != nil || t <= 0 || t > 2 {
+				return 0, ai.GenerationDefaults{}, fmt.Errorf("temperature must be above 0 and at most 2, got %q", value)
+			}
+			defaults.Temperature = t
+		default:
+			return 0, ai.GenerationDefaults{}, fmt.Errorf("unknown setting %q: want max_tokens or temperature", strings.TrimSpace(key))
+		}
+	}
+	return task, defaults, nil
+}
+
+func taskModelsFor(name string, cfg config.AIConfig) string {
+	switch name {
+	case "openai":
+		return cfg.OpenAI.TaskModels
+	case "anthropic":
+		return cfg.Anthropic.TaskModels
+	case "deepseek":
+		return cfg.DeepSeek.TaskModels
+	case "groq":
+		return cfg.Groq.TaskModels
+	case "google":
+		return cfg.Google.TaskModels
+	case "ollama":
+		return cfg.Ollama.TaskModels
+	case "openrouter":
+		return cfg.OpenRouter.TaskModels
+	}
+	return ""
+}
+
+func faultConfig(cfg config.AIFaultConfig) ai.FaultConfig {
+	return ai.FaultConfig{
+		ErrorRate:     cfg.ErrorRate,
+		SlowRate:      cfg.SlowRate,
+		SlowDelay:     time.Duration(cfg.SlowDelayMS) * time.Millisecond,
+		MalformedRate: cfg.MalformedRate,
+	}
 }
 
 // WouldRegister reports whether Apply would register provider name under cfg.
@@ -46,7 +560,49 @@ func WouldRegister(name string, cfg config.AIConfig) bool {
 	return ok
 }
 
+// shared holds the HTTP clients providers are built with. Apply rebuilds
+// every provider, so the clients are kept across calls while their config is
+// unchanged and open connections are reused.
+var shared struct {
+	mu     sync.Mutex
+	cfg    config.AIHTTPConfig
+	client *http.Client
+	// local serves self-hosted providers. It never retries: a local model
+	// server is not rate limited, and its errors are rarely transient.
+	local *http.Client
+}
+
+// httpClients returns the client for hosted providers, which retries
+// transient errors, and the client for self-hosted ones.
+func httpClients(cfg config.AIHTTPConfig) (hosted, local *http.Client) {
+	shared.mu.Lock()
+	defer shared.mu.Unlock()
+	if shared.client != nil && shared.cfg == cfg {
+		return shared.client, shared.local
+	}
+	clientCfg := ai.HTTPClientConfig{
+		Timeout:               time.Duration(cfg.TimeoutSeconds) * time.Second,
+		ResponseHeaderTimeout: time.Duration(cfg.ResponseHeaderTimeoutSeconds) * time.Second,
+		MaxIdleConnsPerHost:   cfg.MaxIdleConnsPerHost,
+		ProxyURL:              strings.TrimSpace(cfg.ProxyURL),
+	}
+	if _, err := ai.NewHTTPClient(clientCfg); err != nil {
+		slog.Warn("ignoring AI HTTP proxy", "error", err)
+		clientCfg.ProxyURL = ""
+	}
+	local, _ = ai.NewHTTPClient(clientCfg)
+	clientCfg.Retry = ai.RetryConfig{
+		MaxRetries: cfg.Retry.MaxRetries,
+		BaseDelay:  time.Duration(cfg.Retry.BaseDelayMS) * time.Millisecond,
+		MaxDelay:   time.Duration(cfg.Retry.MaxDelayMS) * time.Millisecond,
+	}
+	hosted, _ = ai.NewHTTPClient(clientCfg)
+	shared.cfg, shared.client, shared.local = cfg, hosted, local
+	return hosted, local
+}
+
 func buildProvider(name string, cfg config.AIConfig) (ai.ProviderRegistration, bool) {
+	client, localClient := httpClients(cfg.HTTP)
 	switch name {
 	case "mock":
 		if cfg.Mock.Response == "" {
@@ -57,41 +613,93 @@ func buildProvider(name string, cfg config.AIConfig) (ai.ProviderRegistration, b
 		if cfg.OpenAI.APIKey == "" {
 			return ai.ProviderRegistration{}, false
 		}
-		return ai.ProviderRegistration{Name: name, Provider: ai.NewOpenAIProvider(cfg.OpenAI.APIKey), DefaultModel: cfg.OpenAI.Model}, true
+		return ai.ProviderRegistration{Name: name, Provider: endpoints(name, cfg.OpenAI.BaseURLs, func(url string) ai.Provider {
+			return ai.NewOpenAIProvider(cfg.OpenAI.APIKey, ai.WithHTTPClient(client), ai.WithBaseURL(url))
+		}, ai.NewOpenAIProvider(cfg.OpenAI.APIKey, ai.WithHTTPClient(client))), DefaultModel: cfg.OpenAI.Model}, true
 	case "anthropic":
 		if cfg.Anthropic.APIKey == "" {
 			return ai.ProviderRegistration{}, false
 		}
-		provider, err := ai.NewAnthropicProvider(cfg.Anthropic.APIKey)
+		provider, err := ai.NewAnthropicProvider(cfg.Anthropic.APIKey, ai.WithAnthropicHTTPClient(client))
 		if err != nil {
 			slog.Warn("failed to create Anthropic provider", "error", err)
 			return ai.ProviderRegistration{}, false
 		}
+		var mirrors []ai.Endpoint
+		for _, url := range parseBaseURLs(cfg.Anthropic.BaseURLs) {
+			mirror, err := ai.NewAnthropicProvider(cfg.Anthropic.APIKey, ai.WithAnthropicHTTPClient(client), ai.WithAnthropicBaseURL(url))
+			if err != nil {
+				slog.Warn("failed to create Anthropic provider", "base_url", url, "error", err)
+				return ai.ProviderRegistration{}, false
+			}
+			mirrors = append(mirrors, ai.Endpoint{URL: url, Provider: mirror})
+		}
+		if len(mirrors) > 0 {
+			return ai.ProviderRegistration{Name: name, Provider: ai.WithEndpoints(name, mirrors), DefaultModel: cfg.Anthropic.Model}, true
+		}
 		return ai.ProviderRegistration{Name: name, Provider: provider, DefaultModel: cfg.Anthropic.Model}, true
 	case "deepseek":
 		if cfg.DeepSeek.APIKey == "" {
 			return ai.ProviderRegistration{}, false
 		}
-		return ai.ProviderRegistration{Name: name, Provider: ai.NewDeepSeekProvider(cfg.DeepSeek.APIKey), DefaultModel: cfg.DeepSeek.Model}, true
+		return ai.ProviderRegistration{Name: name, Provider: endpoints(name, cfg.DeepSeek.BaseURLs, func(url string) ai.Provider {
+			return ai.NewDeepSeekProvider(cfg.DeepSeek.APIKey, ai.WithHTTPClient(client), ai.WithBaseURL(url))
+		}, ai.NewDeepSeekProvider(cfg.DeepSeek.APIKey, ai.WithHTTPClient(client))), DefaultModel: cfg.DeepSeek.Model}, true
+	case "groq":
+		if cfg.Groq.APIKey == "" {
+			return ai.ProviderRegistration{}, false
+		}
+		return ai.ProviderRegistration{Name: name, Provider: endpoints(name, cfg.Groq.BaseURLs, func(url string) ai.Provider {
+			return ai.NewGroqProvider(cfg.Groq.APIKey, ai.WithHTTPClient(client), ai.WithBaseURL(url))
+		}, ai.NewGroqProvider(cfg.Groq.APIKey, ai.WithHTTPClient(client))), DefaultModel: cfg.Groq.Model}, true
 	case "google":
 		if cfg.Google.APIKey == "" {
 			return ai.ProviderRegistration{}, false
 		}
-		return ai.ProviderRegistration{Name: name, Provider: ai.NewGoogleProvider(cfg.Google.APIKey), DefaultModel: cfg.Google.Model}, true
+		return ai.ProviderRegistration{Name: name, Provider: endpoints(name, cfg.Google.BaseURLs, func(url string) ai.Provider {
+			return ai.NewGoogleProvider(cfg.Google.APIKey, ai.WithGoogleHTTPClient(client), ai.WithGoogleBaseURL(url))
+		}, ai.NewGoogleProvider(cfg.Google.APIKey, ai.WithGoogleHTTPClient(client))), DefaultModel: cfg.Google.Model}, true
 	case "ollama":
 		if !cfg.Ollama.Enabled {
 			return ai.ProviderRegistration{}, false
 		}
-		return ai.ProviderRegistration{Name: name, Provider: ai.NewOllamaProvider(cfg.Ollama.URL), DefaultModel: cfg.Ollama.Model}, true
+		return ai.ProviderRegistration{Name: name, Provider: endpoints(name, cfg.Ollama.URLs, func(url string) ai.Provider {
+			return ai.NewOllamaProvider(url, ai.WithOllamaHTTPClient(localClient))
+		}, ai.NewOllamaProvider(cfg.Ollama.URL, ai.WithOllamaHTTPClient(localClient))), DefaultModel: cfg.Ollama.Model}, true
 	case "openrouter":
 		if cfg.OpenRouter.APIKey == "" {
 			return ai.ProviderRegistration{}, false
 		}
-		return ai.ProviderRegistration{Name: name, Provider: ai.NewOpenRouterLLMAdapter(cfg.OpenRouter.APIKey), DefaultModel: cfg.OpenRouter.Model}, true
+		return ai.ProviderRegistration{Name: name, Provider: ai.NewOpenRouterLLMAdapter(cfg.OpenRouter.APIKey, ai.WithOpenRouterHTTPClient(client)), DefaultModel: cfg.OpenRouter.Model}, true
 	}
 	return ai.ProviderRegistration{}, false
 }
 
+// parseBaseURLs splits a comma-separated base URL list, dropping blanks.
+func parseBaseURLs(raw string) []string {
+	var urls []string
+	for _, url := range strings.Split(raw, ",") {
+		if url = strings.TrimSpace(url); url != "" {
+			urls = append(urls, url)
+		}
+	}
+	return urls
+}
+
+// endpoints builds one provider per configured base URL and joins them with
+// health-based failover, or returns fallback when none are configured.
+func endpoints(name, raw string, build func(url string) ai.Provider, fallback ai.Provider) ai.Provider {
+	urls := parseBaseURLs(raw)
+	if len(urls) == 0 {
+		return fallback
+	}
+	list := make([]ai.Endpoint, 0, len(urls))
+	for _, url := range urls {
+		list = append(list, ai.Endpoint{URL: url, Provider: build(url)})
+	}
+	return ai.WithEndpoints(name, list)
+}
+
 func providerOrder(preferred string) []string {
 	preferred = strings.ToLower(strings.TrimSpace(preferred))
 	if preferred == "" {
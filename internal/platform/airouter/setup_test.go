@@ -5,9 +5,14 @@ package airouter
 
 import (
 	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
 	"reflect"
+	"slices"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/p-n-ai/pai-bot/internal/ai"
 	"github.com/p-n-ai/pai-bot/internal/platform/config"
@@ -33,6 +38,25 @@ func TestBuildProviderUsesOpenRouterLLMAdapter(t *testing.T) {
 	}
 }
 
+func TestBuildProviderRegistersGroq(t *testing.T) {
+	cfg := config.AIConfig{}
+	if _, ok := buildProvider("groq", cfg); ok {
+		t.Fatal("buildProvider(groq) registered without an API key")
+	}
+	cfg.Groq.APIKey = "test-groq-key"
+	reg, ok := buildProvider("groq", cfg)
+	if !ok {
+		t.Fatal("buildProvider(groq) = not registered with key set")
+	}
+	if !knownModel(reg, "llama-3.1-8b-instant") {
+		t.Fatal("Groq catalog is missing llama-3.1-8b-instant")
+	}
+	routes, err := parseTaskRoutes("nudge=groq:llama-3.1-8b-instant|openai")
+	if err != nil || routes[ai.TaskNudge][0].Provider != "groq" {
+		t.Fatalf("parseTaskRoutes(nudge=groq) = %+v, %v", routes, err)
+	}
+}
+
 func TestProviderOrderSkipsMockByDefault(t *testing.T) {
 	for _, provider := range providerOrder("") {
 		if provider == "mock" {
@@ -104,3 +128,344 @@ func TestApplyUnregistersProviderWhenKeyCleared(t *testing.T) {
 		t.Fatalf("Complete() error = %v, want no-providers failure without touching the stale provider", err)
 	}
 }
+
+func TestApplyWrapsProvidersWhenFaultsEnabled(t *testing.T) {
+	cfg := config.AIConfig{DefaultProvider: "mock"}
+	cfg.Mock.Response = "ok"
+	cfg.Fault.ErrorRate = 1
+
+	router := ai.NewRouterWithConfig(ai.RouterConfig{RetryBackoff: []time.Duration{time.Millisecond}})
+	Apply(router, cfg)
+	_, err := router.Complete(context.Background(), ai.CompletionRequest{
+		Messages: []ai.Message{{Role: "user", Content: "hi"}},
+	})
+	if err == nil || !strings.Contains(err.Error(), ai.ErrInjectedFault.Error()) {
+		t.Fatalf("Complete() error = %v, want injected fault", err)
+	}
+}
+
+func TestApplyRegistersTaskModels(t *testing.T) {
+	cfg := config.AIConfig{}
+	cfg.OpenAI.APIKey = "test-openai-key"
+	cfg.OpenAI.TaskModels = "nudge=gpt-5.4-mini, teaching=gpt-5.4"
+
+	reg, ok := buildProvider("openai", cfg)
+	if !ok {
+		t.Fatal("buildProvider(openai) = not registered with key set")
+	}
+	taskModels, err := parseTaskModels(taskModelsFor(reg.Name, cfg))
+	if err != nil {
+		t.Fatalf("parseTaskModels() error = %v", err)
+	}
+	want := map[ai.TaskType]string{ai.TaskNudge: "gpt-5.4-mini", ai.TaskTeaching: "gpt-5.4"}
+	if !reflect.DeepEqual(taskModels, want) {
+		t.Fatalf("task models = %v, want %v", taskModels, want)
+	}
+	if err := Validate(cfg); err != nil {
+		t.Fatalf("Validate() error = %v", err)
+	}
+}
+
+func TestValidateRejectsUnknownTaskOrModel(t *testing.T) {
+	cfg := config.AIConfig{}
+	cfg.OpenAI.APIKey = "test-openai-key"
+	cfg.OpenAI.TaskModels = "nudge=gpt-unknown"
+	if err := Validate(cfg); err == nil || !strings.Contains(err.Error(), "gpt-unknown") {
+		t.Fatalf("Validate() error = %v, want unknown model", err)
+	}
+
+	cfg.OpenAI.TaskModels = "chitchat=gpt-5.4"
+	if err := Validate(cfg); err == nil || !strings.Contains(err.Error(), "chitchat") {
+		t.Fatalf("Validate() error = %v, want unknown task", err)
+	}
+
+	// The configured default model counts as known even if the catalog lags.
+	cfg.OpenAI.Model = "gpt-custom"
+	cfg.OpenAI.TaskModels = "grading=gpt-custom"
+	if err := Validate(cfg); err != nil {
+		t.Fatalf("Validate() error = %v, want configured model accepted", err)
+	}
+
+	// Providers that would not register are not validated.
+	cfg.OpenAI.APIKey = ""
+	cfg.OpenAI.TaskModels = "nudge=gpt-unknown"
+	if err := Validate(cfg); err != nil {
+		t.Fatalf("Validate() error = %v, want nil for unregistered provider", err)
+	}
+}
+
+func TestParseTaskRoutes(t *testing.T) {
+	routes, err := parseTaskRoutes("grading=deepseek:deepseek-chat|openai, teaching=Anthropic, nudge=ollama:llama3:8b")
+	if err != nil {
+		t.Fatalf("parseTaskRoutes() error = %v", err)
+	}
+	want := map[ai.TaskType][]ai.TaskRoute{
+		ai.TaskGrading:  {{Provider: "deepseek", Model: "deepseek-chat"}, {Provider: "openai"}},
+		ai.TaskTeaching: {{Provider: "anthropic"}},
+		ai.TaskNudge:    {{Provider: "ollama", Model: "llama3:8b"}},
+	}
+	if !reflect.DeepEqual(routes, want) {
+		t.Fatalf("routes = %v, want %v", routes, want)
+	}
+
+	routes, err = parseTaskRoutes("grading=nowhere|openai,chitchat=openai,analysis")
+	for _, want := range []string{"nowhere", "chitchat", "analysis"} {
+		if err == nil || !strings.Contains(err.Error(), want) {
+			t.Fatalf("parseTaskRoutes() error = %v, want %q reported", err, want)
+		}
+	}
+	if got := routes[ai.TaskGrading]; len(got) != 1 || got[0].Provider != "openai" {
+		t.Fatalf("grading routes = %v, want the valid hop kept", got)
+	}
+}
+
+func TestApplyConfiguresTaskRoutes(t *testing.T) {
+	cfg := config.AIConfig{TaskRoutes: "grading=deepseek:deepseek-chat"}
+	cfg.OpenAI.APIKey = "test-openai-key"
+	cfg.DeepSeek.APIKey = "test-deepseek-key"
+
+	router := Setup(cfg)
+	provider, model, ok := router.SelectModel(ai.TaskGrading, "")
+	if !ok || provider != "deepseek" || model != "deepseek-chat" {
+		t.Fatalf("SelectModel(grading) = %q %q %v, want deepseek deepseek-chat", provider, model, ok)
+	}
+	if provider, _, _ := router.SelectModel(ai.TaskTeaching, ""); provider != "openai" {
+		t.Fatalf("SelectModel(teaching) provider = %q, want openai from the default order", provider)
+	}
+}
+
+func TestValidateRejectsUnknownTaskRouteModel(t *testing.T) {
+	cfg := config.AIConfig{TaskRoutes: "grading=openai:gpt-unknown"}
+	cfg.OpenAI.APIKey = "test-openai-key"
+	if err := Validate(cfg); err == nil || !strings.Contains(err.Error(), "gpt-unknown") {
+		t.Fatalf("Validate() error = %v, want unknown route model", err)
+	}
+
+	cfg.TaskRoutes = "grading=openai:gpt-5.4-mini|anthropic:claude-anything"
+	if err := Validate(cfg); err != nil {
+		t.Fatalf("Validate() error = %v, want nil for a catalog model and an unregistered provider", err)
+	}
+}
+
+// liveCatalogProvider is a provider whose catalog loads on refresh.
+type liveCatalogProvider struct {
+	*ai.MockProvider
+	catalog    []ai.ModelInfo
+	refreshErr error
+	refreshed  int
+}
+
+func (p *liveCatalogProvider) RefreshModels(context.Context) error {
+	p.refreshed++
+	return p.refreshErr
+}
+
+func (p *liveCatalogProvider) Models() []ai.ModelInfo {
+	if p.refreshed == 0 {
+		return nil
+	}
+	return p.catalog
+}
+
+func TestKnownModelRefreshesLiveCatalog(t *testing.T) {
+	provider := &liveCatalogProvider{
+		MockProvider: ai.NewMockProvider("ok"),
+		catalog:      []ai.ModelInfo{{ID: "anthropic/claude-sonnet-4.5"}},
+	}
+	reg := ai.ProviderRegistration{Name: "openrouter", Provider: provider}
+	if !knownModel(reg, "anthropic/claude-sonnet-4.5") {
+		t.Fatal("knownModel() = false for a model in the live catalog")
+	}
+	if knownModel(reg, "vendor/no-such-model") {
+		t.Fatal("knownModel() = true for a model missing from the live catalog")
+	}
+
+	provider.refreshErr = errors.New("openrouter models returned status 503")
+	if !knownModel(reg, "vendor/no-such-model") {
+		t.Fatal("knownModel() = false while the catalog is unavailable; want it accepted unchecked")
+	}
+}
+
+func TestValidateTaskModelsAcceptsPulledOllamaModels(t *testing.T) {
+	host := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`{"models":[{"name":"llama3:8b"}]}`))
+	}))
+	defer host.Close()
+
+	cfg := config.AIConfig{}
+	cfg.Ollama.Enabled = true
+	cfg.Ollama.URL = host.URL
+	cfg.Ollama.Model = "qwen3"
+	if errs := validateTaskRoutes("LEARN_AI_TASK_ROUTES", "nudge=ollama:llama3:8b", cfg); len(errs) != 0 {
+		t.Fatalf("validateTaskRoutes() = %v, want the pulled model accepted", errs)
+	}
+	if errs := validateTaskRoutes("LEARN_AI_TASK_ROUTES", "nudge=ollama:mistral:7b", cfg); len(errs) != 1 {
+		t.Fatalf("validateTaskRoutes() = %v, want the missing model rejected", errs)
+	}
+}
+
+func TestApplyConfiguresTierTaskRoutes(t *testing.T) {
+	cfg := config.AIConfig{FreeTaskRoutes: "teaching=deepseek:deepseek-chat"}
+	cfg.OpenAI.APIKey = "test-openai-key"
+	cfg.DeepSeek.APIKey = "test-deepseek-key"
+
+	router := Setup(cfg)
+	want := []ai.TaskRoute{{Provider: "deepseek", Model: "deepseek-chat"}}
+	if got := router.TierTaskRoutes("free", ai.TaskTeaching); !slices.Equal(got, want) {
+		t.Fatalf("TierTaskRoutes(free, teaching) = %v, want %v", got, want)
+	}
+	if got := router.TierTaskRoutes("premium", ai.TaskTeaching); got != nil {
+		t.Fatalf("TierTaskRoutes(premium, teaching) = %v, want none", got)
+	}
+
+	cfg.FreeTaskRoutes = "teaching=openai:gpt-unknown"
+	if err := Validate(cfg); err == nil || !strings.Contains(err.Error(), "free task routes") {
+		t.Fatalf("Validate() error = %v, want the invalid free route named", err)
+	}
+}
+
+func TestApplyConfiguresModelPrices(t *testing.T) {
+	cfg := config.AIConfig{ModelPrices: "gpt-5.4=1.25:10, bad-entry"}
+	cfg.OpenAI.APIKey = "test-openai-key"
+
+	router := Setup(cfg)
+	if got, ok := router.ModelPrice("gpt-5.4"); !ok || got != (ai.ModelPrice{InputPerMTok: 1.25, OutputPerMTok: 10}) {
+		t.Fatalf("ModelPrice(gpt-5.4) = %+v, %v; want the configured price", got, ok)
+	}
+	if _, ok := router.ModelPrice("gpt-4o"); !ok {
+		t.Fatal("ModelPrice(gpt-4o) missing; overrides dropped the defaults")
+	}
+	if err := Validate(cfg); err == nil || !strings.Contains(err.Error(), "model prices") {
+		t.Fatalf("Validate() error = %v, want the invalid price entry named", err)
+	}
+	cfg.ModelPrices = "gpt-5.4=-1:10"
+	if err := Validate(cfg); err == nil {
+		t.Fatal("Validate() accepted a negative price")
+	}
+}
+
+func TestApplyConfiguresTenantModelPolicies(t *testing.T) {
+	cfg := config.AIConfig{
+		TenantModelAllow: "school-a=deepseek-*|gpt-5.4-mini",
+		TenantModelDeny:  "school-a=deepseek-reasoner, school-b=gpt-5.4",
+	}
+	cfg.OpenAI.APIKey = "test-openai-key"
+
+	router := Setup(cfg)
+	want := ai.ModelPolicy{Allow: []string{"deepseek-*", "gpt-5.4-mini"}, Deny: []string{"deepseek-reasoner"}}
+	if got, ok := router.TenantModelPolicy("school-a"); !ok || !reflect.DeepEqual(got, want) {
+		t.Fatalf("TenantModelPolicy(school-a) = %+v, %v; want %+v", got, ok, want)
+	}
+	if got, ok := router.TenantModelPolicy("school-b"); !ok || len(got.Allow) != 0 || !slices.Equal(got.Deny, []string{"gpt-5.4"}) {
+		t.Fatalf("TenantModelPolicy(school-b) = %+v, %v; want a deny-only policy", got, ok)
+	}
+
+	cfg.TenantModelDeny = "school-c"
+	if err := Validate(cfg); err == nil || !strings.Contains(err.Error(), "tenant model policies") {
+		t.Fatalf("Validate() error = %v, want the invalid policy entry named", err)
+	}
+}
+
+func TestApplyConfiguresRateLimits(t *testing.T) {
+	cfg := config.AIConfig{RateLimits: "openai=rpm:500|tpm:200000, groq=rps:0.5"}
+	cfg.OpenAI.APIKey = "test-openai-key"
+
+	router := Setup(cfg)
+	if got, ok := router.RateLimit("openai"); !ok || got != (ai.RateLimit{RequestsPerMinute: 500, TokensPerMinute: 200000}) {
+		t.Fatalf("RateLimit(openai) = %+v, %v", got, ok)
+	}
+	if got, ok := router.RateLimit("groq"); !ok || got.RequestsPerSecond != 0.5 {
+		t.Fatalf("RateLimit(groq) = %+v, %v", got, ok)
+	}
+
+	for _, raw := range []string{"openai", "openai=rpm:0", "openai=rph:10", "nope=rpm:10"} {
+		cfg.RateLimits = raw
+		if err := Validate(cfg); err == nil || !strings.Contains(err.Error(), "rate limits") {
+			t.Fatalf("Validate(%q) error = %v, want the invalid rate limit named", raw, err)
+		}
+	}
+}
+
+func TestApplyConfiguresGenerationDefaults(t *testing.T) {
+	cfg := config.AIConfig{
+		TaskDefaults:       "teaching=max_tokens:800|temperature:0.7, nudge=temperature:0.9",
+		TenantTaskDefaults: "school-a/teaching=temperature:0.3",
+	}
+	cfg.OpenAI.APIKey = "test-openai-key"
+
+	router := Setup(cfg)
+	if got := router.GenerationDefaults("", ai.TaskTeaching); got != (ai.GenerationDefaults{MaxTokens: 800, Temperature: 0.7}) {
+		t.Fatalf("GenerationDefaults(teaching) = %+v", got)
+	}
+	if got := router.GenerationDefaults("school-a", ai.TaskTeaching); got != (ai.GenerationDefaults{MaxTokens: 800, Temperature: 0.3}) {
+		t.Fatalf("GenerationDefaults(school-a, teaching) = %+v, want the tenant temperature over the task cap", got)
+	}
+	if got := router.GenerationDefaults("school-a", ai.TaskAnalysis); got != (ai.GenerationDefaults{MaxTokens: 256}) {
+		t.Fatalf("GenerationDefaults(school-a, analysis) = %+v, want the built-in cap", got)
+	}
+
+	for _, raw := range []string{"teaching", "coding=max_tokens:10", "teaching=max_tokens:0", "teaching=temperature:3", "teaching=top_p:0.9"} {
+		cfg.TaskDefaults = raw
+		if err := Validate(cfg); err == nil || !strings.Contains(err.Error(), "task generation defaults") {
+			t.Fatalf("Validate(%q) error = %v, want the invalid default named", raw, err)
+		}
+	}
+	cfg.TaskDefaults = ""
+	cfg.TenantTaskDefaults = "teaching=max_tokens:10"
+	if err := Validate(cfg); err == nil {
+		t.Fatal("Validate() error = nil for a tenant entry without a tenant")
+	}
+}
+
+func TestHTTPClientsRetryOnlyForHostedProviders(t *testing.T) {
+	hosted, local := httpClients(config.AIHTTPConfig{Retry: config.AIRetryConfig{MaxRetries: 2}})
+	if _, plain := hosted.Transport.(*http.Transport); plain {
+		t.Fatal("hosted client transport is not wrapped for retries")
+	}
+	if _, plain := local.Transport.(*http.Transport); !plain {
+		t.Fatalf("local client transport = %T, want *http.Transport without retries", local.Transport)
+	}
+}
+
+func TestHTTPClientReusedUntilConfigChanges(t *testing.T) {
+	cfg := config.AIHTTPConfig{TimeoutSeconds: 45, MaxIdleConnsPerHost: 8}
+	first, _ := httpClients(cfg)
+	if first.Timeout != 45*time.Second {
+		t.Fatalf("Timeout = %v, want 45s", first.Timeout)
+	}
+	if again, _ := httpClients(cfg); again != first {
+		t.Fatal("httpClients() rebuilt the client for an unchanged config")
+	}
+
+	cfg.ProxyURL = "not a proxy"
+	changed, _ := httpClients(cfg)
+	if changed == first {
+		t.Fatal("httpClients() kept the client after the config changed")
+	}
+	if changed == nil {
+		t.Fatal("httpClients() with an invalid proxy = nil, want a client without the proxy")
+	}
+}
+
+func TestBuildProviderJoinsConfiguredBaseURLs(t *testing.T) {
+	cfg := config.AIConfig{}
+	cfg.OpenAI.APIKey = "test-openai-key"
+	cfg.OpenAI.BaseURLs = " https://api.openai.com/v1, ,https://mirror.example/openai/v1 "
+
+	reg, ok := buildProvider("openai", cfg)
+	if !ok {
+		t.Fatal("buildProvider(openai) = not registered with key set")
+	}
+	if _, ok := reg.Provider.(ai.NativeProvider); !ok {
+		t.Fatalf("OpenAI provider with mirrors = %T, want native tool calls kept", reg.Provider)
+	}
+	if got, single := reflect.TypeOf(reg.Provider), reflect.TypeOf(ai.NewOpenAIProvider("")); got == single {
+		t.Fatalf("OpenAI provider with two base URLs = %v, want the multi-endpoint wrapper", got)
+	}
+
+	cfg.OpenAI.BaseURLs = "https://mirror.example/openai/v1"
+	reg, _ = buildProvider("openai", cfg)
+	if got, want := reflect.TypeOf(reg.Provider), reflect.TypeOf(ai.NewOpenAIProvider("")); got != want {
+		t.Fatalf("OpenAI provider with one base URL = %v, want %v", got, want)
+	}
+}
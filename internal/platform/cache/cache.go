@@ -6,6 +6,9 @@ package cache
 
 import (
 	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
 	"fmt"
 	"time"
 
@@ -55,6 +58,60 @@ func (c *Cache) Close() error {
 	return c.Client.Close()
 }
 
+// Get returns the value at key. It returns nil, nil when the key is unset.
+func (c *Cache) Get(ctx context.Context, key string) ([]byte, error) {
+	value, err := c.Client.Get(ctx, key).Bytes()
+	if errors.Is(err, redis.Nil) {
+		return nil, nil
+	}
+	return value, err
+}
+
+// GetDel returns and deletes the value at key. It returns nil, nil when the
+// key is unset.
+func (c *Cache) GetDel(ctx context.Context, key string) ([]byte, error) {
+	value, err := c.Client.GetDel(ctx, key).Bytes()
+	if errors.Is(err, redis.Nil) {
+		return nil, nil
+	}
+	return value, err
+}
+
+// Set stores value at key for ttl.
+func (c *Cache) Set(ctx context.Context, key string, value []byte, ttl time.Duration) error {
+	return c.Client.Set(ctx, key, value, ttl).Err()
+}
+
+// releaseLock deletes a lock only while it still holds the caller's token, so
+// a holder whose lock expired cannot release the next holder's.
+var releaseLock = redis.NewScript(`
+if redis.call("GET", KEYS[1]) == ARGV[1] then
+	return redis.call("DEL", KEYS[1])
+end
+return 0`)
+
+// TryLock takes the lock at key for ttl if nobody holds it. ok is false when
+// another holder has it. The lock expires after ttl even if unlock is never
+// called, so ttl should outlast the work it guards.
+func (c *Cache) TryLock(ctx context.Context, key string, ttl time.Duration) (unlock func(), ok bool, err error) {
+	var raw [16]byte
+	if _, err := rand.Read(raw[:]); err != nil {
+		return nil, false, fmt.Errorf("generating lock token: %w", err)
+	}
+	token := hex.EncodeToString(raw[:])
+	ok, err = c.Client.SetNX(ctx, key, token, ttl).Result()
+	if err != nil || !ok {
+		return nil, false, err
+	}
+	unlock = func() {
+		// The caller's context may be done by now; release regardless.
+		ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+		defer cancel()
+		_ = releaseLock.Run(ctx, c.Client, []string{key}, token).Err()
+	}
+	return unlock, true, nil
+}
+
 // HealthCheck verifies the cache connection is alive.
 func (c *Cache) HealthCheck(ctx context.Context) error {
 	return c.Client.Ping(ctx).Err()
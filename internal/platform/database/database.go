@@ -7,11 +7,17 @@ package database
 import (
 	"context"
 	"fmt"
+	"log/slog"
 	"time"
 
+	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgxpool"
 )
 
+// DefaultStatementCacheCapacity is how many statements each connection keeps
+// prepared, matching pgx's own default.
+const DefaultStatementCacheCapacity = 512
+
 // DB wraps a pgx connection pool.
 type DB struct {
 	Pool *pgxpool.Pool
@@ -29,8 +35,32 @@ func ParseURL(url string) (*pgxpool.Config, error) {
 	return cfg, nil
 }
 
+// Option adjusts how New sets up the pool.
+type Option func(*options)
+
+type options struct {
+	statementCache int
+	prepare        []string
+}
+
+// WithStatementCache sets how many statements each connection keeps
+// prepared. Zero turns server-side statements off, which transaction-mode
+// poolers such as older PgBouncer releases need; every query is then parsed
+// by the server each time it runs.
+func WithStatementCache(capacity int) Option {
+	return func(o *options) { o.statementCache = capacity }
+}
+
+// WithPreparedStatements prepares sqls on every new connection, named by
+// their own text so pgx uses them whenever the same SQL is queried. A
+// statement that fails to prepare, e.g. before migrations have run, is
+// logged and left to the statement cache.
+func WithPreparedStatements(sqls ...string) Option {
+	return func(o *options) { o.prepare = append(o.prepare, sqls...) }
+}
+
 // New creates a new database connection pool.
-func New(ctx context.Context, url string, maxConns, minConns int) (*DB, error) {
+func New(ctx context.Context, url string, maxConns, minConns int, opts ...Option) (*DB, error) {
 	cfg, err := ParseURL(url)
 	if err != nil {
 		return nil, err
@@ -40,6 +70,7 @@ func New(ctx context.Context, url string, maxConns, minConns int) (*DB, error) {
 	cfg.MinConns = int32(minConns)
 	cfg.MaxConnLifetime = 30 * time.Minute
 	cfg.MaxConnIdleTime = 5 * time.Minute
+	applyOptions(cfg, opts)
 
 	pool, err := pgxpool.NewWithConfig(ctx, cfg)
 	if err != nil {
@@ -54,6 +85,34 @@ func New(ctx context.Context, url string, maxConns, minConns int) (*DB, error) {
 	return &DB{Pool: pool}, nil
 }
 
+// applyOptions sets the statement exec mode and cache. Per-turn queries
+// run on every message, so by default they are prepared once per connection
+// rather than parsed on each call.
+func applyOptions(cfg *pgxpool.Config, opts []Option) {
+	o := options{statementCache: DefaultStatementCacheCapacity}
+	for _, opt := range opts {
+		opt(&o)
+	}
+	if o.statementCache <= 0 {
+		cfg.ConnConfig.DefaultQueryExecMode = pgx.QueryExecModeExec
+		cfg.ConnConfig.StatementCacheCapacity = 0
+		return
+	}
+	cfg.ConnConfig.DefaultQueryExecMode = pgx.QueryExecModeCacheStatement
+	cfg.ConnConfig.StatementCacheCapacity = o.statementCache
+	if len(o.prepare) == 0 {
+		return
+	}
+	cfg.AfterConnect = func(ctx context.Context, conn *pgx.Conn) error {
+		for _, sql := range o.prepare {
+			if _, err := conn.Prepare(ctx, sql, sql); err != nil {
+				slog.Warn("prepare statement failed", "error", err)
+			}
+		}
+		return nil
+	}
+}
+
 // Close shuts down the connection pool.
 func (db *DB) Close() {
 	db.Pool.Close()
@@ -26,6 +26,21 @@ const (
 	TurnHooks Feature = "turn_hooks"
 	// AgentCore enables native sequential tool continuation for teaching turns.
 	AgentCore Feature = "agent_core"
+	// RevisionMode enables exam countdowns and intensified revision before
+	// configured exam sittings.
+	RevisionMode Feature = "revision_mode"
+	// LearnerMemory enables long-term learner highlights extracted at
+	// compaction and recalled by similarity on each teaching turn.
+	LearnerMemory Feature = "learner_memory"
+	// CheckInFollowUps queues a next-day check-in after each teaching turn,
+	// dropped if the learner comes back first.
+	CheckInFollowUps Feature = "check_in_follow_ups"
+	// BackgroundTurns acknowledges heavy requests (image analysis, worksheet
+	// generation) at once and sends the answer as a follow-up message.
+	BackgroundTurns Feature = "background_turns"
+	// StreamedTeaching sends text teaching turns through the router's
+	// streaming fallback chain instead of a single buffered completion.
+	StreamedTeaching Feature = "streamed_teaching"
 )
 
 // Spec describes a known feature flag.
@@ -51,6 +66,31 @@ var registry = map[Feature]Spec{
 		Status:         UnderDevelopment,
 		DefaultEnabled: false,
 	},
+	RevisionMode: {
+		Feature:        RevisionMode,
+		Status:         UnderDevelopment,
+		DefaultEnabled: false,
+	},
+	LearnerMemory: {
+		Feature:        LearnerMemory,
+		Status:         UnderDevelopment,
+		DefaultEnabled: false,
+	},
+	CheckInFollowUps: {
+		Feature:        CheckInFollowUps,
+		Status:         UnderDevelopment,
+		DefaultEnabled: false,
+	},
+	BackgroundTurns: {
+		Feature:        BackgroundTurns,
+		Status:         UnderDevelopment,
+		DefaultEnabled: false,
+	},
+	StreamedTeaching: {
+		Feature:        StreamedTeaching,
+		Status:         UnderDevelopment,
+		DefaultEnabled: false,
+	},
 }
 
 // Parse builds an effective feature set from comma-separated overrides.
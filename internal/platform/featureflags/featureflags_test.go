@@ -111,4 +111,19 @@ func TestDefaults(t *testing.T) {
 	if enabled, ok := defaults["agent_core"]; !ok || enabled {
 		t.Fatalf("Defaults()[agent_core] = %v, %v; want false, present", enabled, ok)
 	}
+	if enabled, ok := defaults["revision_mode"]; !ok || enabled {
+		t.Fatalf("Defaults()[revision_mode] = %v, %v; want false, present", enabled, ok)
+	}
+	if enabled, ok := defaults["learner_memory"]; !ok || enabled {
+		t.Fatalf("Defaults()[learner_memory] = %v, %v; want false, present", enabled, ok)
+	}
+	if enabled, ok := defaults["check_in_follow_ups"]; !ok || enabled {
+		t.Fatalf("Defaults()[check_in_follow_ups] = %v, %v; want false, present", enabled, ok)
+	}
+	if enabled, ok := defaults["background_turns"]; !ok || enabled {
+		t.Fatalf("Defaults()[background_turns] = %v, %v; want false, present", enabled, ok)
+	}
+	if enabled, ok := defaults["streamed_teaching"]; !ok || enabled {
+		t.Fatalf("Defaults()[streamed_teaching] = %v, %v; want false, present", enabled, ok)
+	}
 }
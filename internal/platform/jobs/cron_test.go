@@ -0,0 +1,80 @@
+// Copyright 2026 the P&AI authors. All rights reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package jobs
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParseSchedule_Next(t *testing.T) {
+	myt := time.FixedZone("MYT", 8*60*60)
+	// Saturday 17 October 2026, 10:07:30.
+	from := time.Date(2026, 10, 17, 10, 7, 30, 0, myt)
+
+	tests := []struct {
+		spec string
+		want time.Time
+	}{
+		{"* * * * *", time.Date(2026, 10, 17, 10, 8, 0, 0, myt)},
+		{"*/15 * * * *", time.Date(2026, 10, 17, 10, 15, 0, 0, myt)},
+		{"5,50 9-11 * * *", time.Date(2026, 10, 17, 10, 50, 0, 0, myt)},
+		{"0 20 * * 0", time.Date(2026, 10, 18, 20, 0, 0, 0, myt)},
+		{"0 20 * * 7", time.Date(2026, 10, 18, 20, 0, 0, 0, myt)},
+		{"30 2 1 * *", time.Date(2026, 11, 1, 2, 30, 0, 0, myt)},
+		{"0 0 29 2 *", time.Date(2028, 2, 29, 0, 0, 0, 0, myt)},
+		// Both day fields set: the 1st or any Monday, whichever comes first.
+		{"0 9 1 * 1", time.Date(2026, 10, 19, 9, 0, 0, 0, myt)},
+		{"@hourly", time.Date(2026, 10, 17, 11, 0, 0, 0, myt)},
+		{"@daily", time.Date(2026, 10, 18, 0, 0, 0, 0, myt)},
+		{"@weekly", time.Date(2026, 10, 18, 0, 0, 0, 0, myt)},
+		{"@monthly", time.Date(2026, 11, 1, 0, 0, 0, 0, myt)},
+		{"@yearly", time.Date(2027, 1, 1, 0, 0, 0, 0, myt)},
+		{"@every 5m", time.Date(2026, 10, 17, 10, 10, 0, 0, myt)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.spec, func(t *testing.T) {
+			schedule, err := ParseSchedule(tt.spec)
+			if err != nil {
+				t.Fatalf("ParseSchedule() error = %v", err)
+			}
+			if got := schedule.Next(from); !got.Equal(tt.want) {
+				t.Fatalf("Next() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseSchedule_NeverDueReturnsZero(t *testing.T) {
+	schedule, err := ParseSchedule("0 0 30 2 *")
+	if err != nil {
+		t.Fatalf("ParseSchedule() error = %v", err)
+	}
+	if got := schedule.Next(time.Now()); !got.IsZero() {
+		t.Fatalf("Next() = %v, want zero for 30 February", got)
+	}
+}
+
+func TestParseSchedule_RejectsInvalid(t *testing.T) {
+	for _, spec := range []string{
+		"",
+		"* * * *",
+		"60 * * * *",
+		"* 24 * * *",
+		"* * 0 * *",
+		"* * * 13 *",
+		"* * * * 8",
+		"*/0 * * * *",
+		"5-1 * * * *",
+		"a * * * *",
+		"@every",
+		"@every soon",
+		"@every -1m",
+		"@fortnightly",
+	} {
+		if _, err := ParseSchedule(spec); err == nil {
+			t.Errorf("ParseSchedule(%q) error = nil, want error", spec)
+		}
+	}
+}
@@ -0,0 +1,233 @@
+// Copyright 2026 the P&AI authors. All rights reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+// Package jobs runs background work on cron schedules inside the server
+// process. Jobs marked Singleton run on one replica at a time, guarded by a
+// lock in the shared cache.
+package jobs
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"math/rand/v2"
+	"regexp"
+	"sync"
+	"time"
+)
+
+const (
+	// DefaultTimeout bounds a run when the job sets none.
+	DefaultTimeout = 10 * time.Minute
+	// lockKeyPrefix namespaces singleton locks in the cache.
+	lockKeyPrefix = "pai:jobs:lock:"
+)
+
+var jobNamePattern = regexp.MustCompile(`^[a-z0-9][a-z0-9_-]*$`)
+
+// Job is one piece of scheduled work.
+type Job struct {
+	// Name identifies the job in logs and lock keys: lowercase letters,
+	// digits, "-" and "_".
+	Name     string
+	Schedule Schedule
+	// Jitter delays each run by a random amount up to Jitter, so replicas
+	// and jobs sharing a schedule do not all start on the same second.
+	Jitter time.Duration
+	// Singleton runs the job on one replica per scheduled slot: replicas
+	// that find the slot's lock taken skip the run.
+	Singleton bool
+	// Timeout bounds one run; zero means DefaultTimeout. A singleton's slot
+	// lock is held this long.
+	Timeout time.Duration
+	Run     func(ctx context.Context) error
+}
+
+// Locker takes cluster-wide locks for singleton jobs. cache.Cache satisfies
+// it.
+type Locker interface {
+	// TryLock takes key for ttl if it is free. ok is false when another
+	// holder has it.
+	TryLock(ctx context.Context, key string, ttl time.Duration) (unlock func(), ok bool, err error)
+}
+
+// Scheduler runs registered jobs until its context ends.
+type Scheduler struct {
+	locker Locker
+	loc    *time.Location
+	logger *slog.Logger
+	now    func() time.Time
+	// sleep waits for d or until ctx ends, returning false in the latter
+	// case. Tests replace it to run without real delays.
+	sleep  func(ctx context.Context, d time.Duration) bool
+	jitter func(max time.Duration) time.Duration
+
+	mu      sync.Mutex
+	jobs    []Job
+	started bool
+}
+
+// New returns a scheduler that evaluates schedules in loc (UTC when nil).
+// A nil locker runs singleton jobs unguarded, which is only safe with a
+// single replica.
+func New(locker Locker, loc *time.Location, logger *slog.Logger) *Scheduler {
+	if loc == nil {
+		loc = time.UTC
+	}
+	if logger == nil {
+		logger = slog.Default()
+	}
+	return &Scheduler{
+		locker: locker,
+		loc:    loc,
+		logger: logger,
+		now:    time.Now,
+		sleep:  sleepContext,
+		jitter: randomJitter,
+	}
+}
+
+// Register adds job. It fails on an invalid or duplicate job, or once Run
+// has started.
+func (s *Scheduler) Register(job Job) error {
+	if !jobNamePattern.MatchString(job.Name) {
+		return fmt.Errorf("invalid job name %q", job.Name)
+	}
+	if job.Schedule == nil {
+		return fmt.Errorf("job %s: schedule is required", job.Name)
+	}
+	if job.Run == nil {
+		return fmt.Errorf("job %s: run func is required", job.Name)
+	}
+	if job.Jitter < 0 || job.Timeout < 0 {
+		return fmt.Errorf("job %s: jitter and timeout must not be negative", job.Name)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.started {
+		return fmt.Errorf("job %s: scheduler already running", job.Name)
+	}
+	for _, existing := range s.jobs {
+		if existing.Name == job.Name {
+			return fmt.Errorf("job %s: already registered", job.Name)
+		}
+	}
+	s.jobs = append(s.jobs, job)
+	return nil
+}
+
+// Jobs returns the names of the registered jobs in registration order.
+func (s *Scheduler) Jobs() []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	names := make([]string, len(s.jobs))
+	for i, job := range s.jobs {
+		names[i] = job.Name
+	}
+	return names
+}
+
+// Run runs every registered job on its schedule and returns once ctx ends
+// and in-flight runs have returned.
+func (s *Scheduler) Run(ctx context.Context) {
+	s.mu.Lock()
+	s.started = true
+	jobs := append([]Job(nil), s.jobs...)
+	s.mu.Unlock()
+
+	var wg sync.WaitGroup
+	for _, job := range jobs {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			s.loop(ctx, job)
+		}()
+	}
+	wg.Wait()
+}
+
+func (s *Scheduler) loop(ctx context.Context, job Job) {
+	for {
+		now := s.now().In(s.loc)
+		slot := job.Schedule.Next(now)
+		if slot.IsZero() {
+			s.logger.Warn("job schedule has no next run", "job", job.Name)
+			return
+		}
+		start := slot
+		if job.Jitter > 0 {
+			start = start.Add(s.jitter(job.Jitter))
+		}
+		if !s.sleep(ctx, start.Sub(now)) {
+			return
+		}
+		s.runOnce(ctx, job, slot)
+	}
+}
+
+// runOnce runs job for the scheduled time slot, taking the slot's lock first
+// when the job is a singleton.
+func (s *Scheduler) runOnce(ctx context.Context, job Job, slot time.Time) {
+	timeout := job.Timeout
+	if timeout == 0 {
+		timeout = DefaultTimeout
+	}
+	if job.Singleton && s.locker != nil {
+		// The lock is left to expire rather than released, so a replica
+		// whose clock lags cannot run the same slot after this run ends.
+		key := fmt.Sprintf("%s%s:%d", lockKeyPrefix, job.Name, slot.Unix())
+		_, ok, err := s.locker.TryLock(ctx, key, timeout)
+		if err != nil {
+			s.logger.Warn("job lock failed", "job", job.Name, "error", err)
+			return
+		}
+		if !ok {
+			s.logger.Debug("job skipped; another replica holds the lock", "job", job.Name)
+			return
+		}
+	}
+
+	runCtx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+	started := s.now()
+	err := runJob(runCtx, job)
+	elapsed := s.now().Sub(started)
+	switch {
+	case err != nil && ctx.Err() != nil:
+		// Shutting down; the run was cut short on purpose.
+	case err != nil:
+		s.logger.Warn("job failed", "job", job.Name, "duration", elapsed, "error", err)
+	default:
+		s.logger.Info("job completed", "job", job.Name, "duration", elapsed)
+	}
+}
+
+// runJob calls job.Run, turning a panic into an error so one bad run does
+// not take down the process or stop later runs.
+func runJob(ctx context.Context, job Job) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("panic: %v", r)
+		}
+	}()
+	return job.Run(ctx)
+}
+
+func sleepContext(ctx context.Context, d time.Duration) bool {
+	if d <= 0 {
+		return ctx.Err() == nil
+	}
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return false
+	case <-timer.C:
+		return true
+	}
+}
+
+func randomJitter(max time.Duration) time.Duration {
+	return rand.N(max)
+}
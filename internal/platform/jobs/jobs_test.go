@@ -0,0 +1,192 @@
+// Copyright 2026 the P&AI authors. All rights reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package jobs
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+)
+
+// fakeClock advances to whatever the scheduler sleeps until, so jobs run
+// without real delays.
+type fakeClock struct {
+	mu  sync.Mutex
+	now time.Time
+}
+
+func (c *fakeClock) Now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.now
+}
+
+func (c *fakeClock) Sleep(ctx context.Context, d time.Duration) bool {
+	if ctx.Err() != nil {
+		return false
+	}
+	c.mu.Lock()
+	c.now = c.now.Add(d)
+	c.mu.Unlock()
+	return true
+}
+
+type memoryLocker struct {
+	mu   sync.Mutex
+	held map[string]bool
+	keys []string
+}
+
+func (l *memoryLocker) TryLock(_ context.Context, key string, _ time.Duration) (func(), bool, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.keys = append(l.keys, key)
+	if l.held[key] {
+		return nil, false, nil
+	}
+	l.held[key] = true
+	return func() {}, true, nil
+}
+
+func newTestScheduler(locker Locker, start time.Time) (*Scheduler, *fakeClock) {
+	clock := &fakeClock{now: start}
+	s := New(locker, time.UTC, nil)
+	s.now = clock.Now
+	s.sleep = clock.Sleep
+	return s, clock
+}
+
+func mustSchedule(t *testing.T, spec string) Schedule {
+	t.Helper()
+	schedule, err := ParseSchedule(spec)
+	if err != nil {
+		t.Fatalf("ParseSchedule(%q) error = %v", spec, err)
+	}
+	return schedule
+}
+
+func TestScheduler_RunsJobsOnSchedule(t *testing.T) {
+	start := time.Date(2026, 10, 17, 10, 7, 30, 0, time.UTC)
+	s, _ := newTestScheduler(nil, start)
+	s.jitter = func(max time.Duration) time.Duration { return max / 2 }
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	var ran []time.Time
+	err := s.Register(Job{
+		Name:     "report",
+		Schedule: mustSchedule(t, "*/15 * * * *"),
+		Jitter:   time.Minute,
+		Run: func(context.Context) error {
+			ran = append(ran, s.now())
+			if len(ran) == 3 {
+				cancel()
+			}
+			return nil
+		},
+	})
+	if err != nil {
+		t.Fatalf("Register() error = %v", err)
+	}
+	s.Run(ctx)
+
+	want := []time.Time{
+		time.Date(2026, 10, 17, 10, 15, 30, 0, time.UTC),
+		time.Date(2026, 10, 17, 10, 30, 30, 0, time.UTC),
+		time.Date(2026, 10, 17, 10, 45, 30, 0, time.UTC),
+	}
+	if len(ran) != len(want) {
+		t.Fatalf("ran %d times, want %d", len(ran), len(want))
+	}
+	for i := range want {
+		if !ran[i].Equal(want[i]) {
+			t.Fatalf("run %d at %v, want %v with 30s jitter", i+1, ran[i], want[i])
+		}
+	}
+}
+
+func TestScheduler_SingletonRunsOncePerSlotAcrossReplicas(t *testing.T) {
+	locker := &memoryLocker{held: map[string]bool{}}
+	start := time.Date(2026, 10, 17, 19, 59, 0, 0, time.UTC)
+	var mu sync.Mutex
+	runs := 0
+	for range 2 {
+		s, _ := newTestScheduler(locker, start)
+		err := s.Register(Job{
+			Name:      "weekly-report",
+			Schedule:  mustSchedule(t, "0 20 * * *"),
+			Singleton: true,
+			Run: func(context.Context) error {
+				mu.Lock()
+				runs++
+				mu.Unlock()
+				return nil
+			},
+		})
+		if err != nil {
+			t.Fatalf("Register() error = %v", err)
+		}
+		s.runOnce(context.Background(), s.jobs[0], time.Date(2026, 10, 17, 20, 0, 0, 0, time.UTC))
+	}
+	if runs != 1 {
+		t.Fatalf("runs = %d, want 1 across two replicas", runs)
+	}
+	if want := "pai:jobs:lock:weekly-report:1792267200"; len(locker.keys) != 2 || locker.keys[0] != want || locker.keys[1] != want {
+		t.Fatalf("lock keys = %v, want %q twice", locker.keys, want)
+	}
+}
+
+func TestScheduler_FailuresAndPanicsDoNotStopTheJob(t *testing.T) {
+	s, _ := newTestScheduler(nil, time.Date(2026, 10, 17, 0, 0, 0, 0, time.UTC))
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	calls := 0
+	err := s.Register(Job{
+		Name:     "flaky",
+		Schedule: mustSchedule(t, "@every 1m"),
+		Run: func(context.Context) error {
+			calls++
+			switch calls {
+			case 1:
+				return errors.New("database unavailable")
+			case 2:
+				panic("nil map")
+			}
+			cancel()
+			return nil
+		},
+	})
+	if err != nil {
+		t.Fatalf("Register() error = %v", err)
+	}
+	s.Run(ctx)
+	if calls != 3 {
+		t.Fatalf("calls = %d, want 3", calls)
+	}
+}
+
+func TestScheduler_RegisterRejectsInvalidJobs(t *testing.T) {
+	s := New(nil, nil, nil)
+	run := func(context.Context) error { return nil }
+	every := mustSchedule(t, "@hourly")
+	if err := s.Register(Job{Name: "cleanup", Schedule: every, Run: run}); err != nil {
+		t.Fatalf("Register() error = %v", err)
+	}
+	for name, job := range map[string]Job{
+		"duplicate":   {Name: "cleanup", Schedule: every, Run: run},
+		"bad name":    {Name: "Clean Up", Schedule: every, Run: run},
+		"no schedule": {Name: "a", Run: run},
+		"no run":      {Name: "b", Schedule: every},
+		"neg jitter":  {Name: "c", Schedule: every, Run: run, Jitter: -time.Second},
+	} {
+		if err := s.Register(job); err == nil {
+			t.Errorf("Register(%s) error = nil, want error", name)
+		}
+	}
+	if got := s.Jobs(); len(got) != 1 || got[0] != "cleanup" {
+		t.Fatalf("Jobs() = %v, want [cleanup]", got)
+	}
+}
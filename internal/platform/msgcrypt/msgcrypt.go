@@ -0,0 +1,141 @@
+// Copyright 2026 the P&AI authors. All rights reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+// Package msgcrypt encrypts learner message content at rest with AES-256-GCM.
+// Each sealed value is stored next to the ID of the key that sealed it, so
+// keys can be rotated without rewriting old rows, and is bound to the row it
+// was sealed for, so it cannot be copied into another.
+package msgcrypt
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// KeySize is the AES-256 key length in bytes.
+const KeySize = 32
+
+// Keyring holds every key that may have sealed a stored value and the key
+// new values are sealed with. A nil Keyring leaves values in plaintext.
+type Keyring struct {
+	active string
+	aeads  map[string]cipher.AEAD
+}
+
+// Parse reads "key_id=base64key" pairs separated by commas, each key being
+// 32 random bytes in standard base64. active names the key that seals new
+// values; it may be empty when there is only one key. An empty raw returns a
+// nil Keyring, which disables encryption.
+func Parse(raw, active string) (*Keyring, error) {
+	k := &Keyring{aeads: make(map[string]cipher.AEAD)}
+	var only string
+	for _, entry := range strings.Split(raw, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+		id, encoded, ok := strings.Cut(entry, "=")
+		id = strings.TrimSpace(id)
+		if !ok || id == "" {
+			return nil, fmt.Errorf("invalid key entry: want key_id=base64key")
+		}
+		if _, dup := k.aeads[id]; dup {
+			return nil, fmt.Errorf("duplicate key id %q", id)
+		}
+		key, err := base64.StdEncoding.DecodeString(strings.TrimSpace(encoded))
+		if err != nil || len(key) != KeySize {
+			// The key itself is never echoed back.
+			return nil, fmt.Errorf("key %q: want %d bytes in standard base64", id, KeySize)
+		}
+		block, err := aes.NewCipher(key)
+		if err != nil {
+			return nil, fmt.Errorf("key %q: %w", id, err)
+		}
+		gcm, err := cipher.NewGCM(block)
+		if err != nil {
+			return nil, fmt.Errorf("key %q: %w", id, err)
+		}
+		k.aeads[id] = gcm
+		only = id
+	}
+	active = strings.TrimSpace(active)
+	switch {
+	case len(k.aeads) == 0:
+		if active != "" {
+			return nil, fmt.Errorf("active key %q set without any keys", active)
+		}
+		return nil, nil
+	case active == "" && len(k.aeads) == 1:
+		active = only
+	case active == "":
+		return nil, fmt.Errorf("several keys configured: name the active one")
+	}
+	if _, ok := k.aeads[active]; !ok {
+		return nil, fmt.Errorf("active key %q is not configured", active)
+	}
+	k.active = active
+	return k, nil
+}
+
+// ActiveKeyID returns the ID new values are sealed with, or "" when k is nil.
+func (k *Keyring) ActiveKeyID() string {
+	if k == nil {
+		return ""
+	}
+	return k.active
+}
+
+// Binding names the row a value is sealed for: its tenant and its own ID. It
+// is authenticated with the value, so a value copied into another message,
+// conversation or tenant fails to open.
+func Binding(tenantID, rowID string) []byte {
+	return []byte(tenantID + "\x00" + rowID)
+}
+
+// Seal encrypts plaintext with the active key, bound to binding, and returns
+// base64(nonce||ciphertext) with that key's ID. A nil Keyring returns
+// plaintext unchanged and an empty key ID.
+func (k *Keyring) Seal(plaintext string, binding []byte) (string, string, error) {
+	if k == nil {
+		return plaintext, "", nil
+	}
+	gcm := k.aeads[k.active]
+	nonce := make([]byte, gcm.NonceSize())
+	if _, err := io.ReadFull(rand.Reader, nonce); err != nil {
+		return "", "", fmt.Errorf("read nonce: %w", err)
+	}
+	sealed := gcm.Seal(nonce, nonce, []byte(plaintext), binding)
+	return base64.StdEncoding.EncodeToString(sealed), k.active, nil
+}
+
+// Open decrypts a value sealed under keyID for binding. An empty keyID marks
+// a value stored before encryption was turned on, which is returned as is.
+func (k *Keyring) Open(keyID, encoded string, binding []byte) (string, error) {
+	if keyID == "" {
+		return encoded, nil
+	}
+	if k == nil {
+		return "", fmt.Errorf("value sealed with key %q but message encryption is not configured", keyID)
+	}
+	gcm, ok := k.aeads[keyID]
+	if !ok {
+		return "", fmt.Errorf("value sealed with unknown key %q", keyID)
+	}
+	raw, err := base64.StdEncoding.DecodeString(encoded)
+	if err != nil {
+		return "", fmt.Errorf("decode sealed value: %w", err)
+	}
+	if len(raw) < gcm.NonceSize() {
+		return "", fmt.Errorf("sealed value too short")
+	}
+	plaintext, err := gcm.Open(nil, raw[:gcm.NonceSize()], raw[gcm.NonceSize():], binding)
+	if err != nil {
+		return "", fmt.Errorf("decrypt sealed value with key %q: %w", keyID, err)
+	}
+	return string(plaintext), nil
+}
@@ -0,0 +1,98 @@
+// Copyright 2026 the P&AI authors. All rights reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package msgcrypt
+
+import (
+	"bytes"
+	"encoding/base64"
+	"strings"
+	"testing"
+)
+
+func testKey(b byte) string {
+	return base64.StdEncoding.EncodeToString(bytes.Repeat([]byte{b}, KeySize))
+}
+
+func TestParseEmptyDisablesEncryption(t *testing.T) {
+	k, err := Parse(" , ", "")
+	if err != nil || k != nil {
+		t.Fatalf("Parse(empty) = %v, %v; want nil, nil", k, err)
+	}
+	sealed, keyID, err := k.Seal("hello", nil)
+	if err != nil || sealed != "hello" || keyID != "" {
+		t.Fatalf("nil Seal() = %q, %q, %v; want plaintext", sealed, keyID, err)
+	}
+}
+
+func TestSealOpenRoundTripsAcrossRotation(t *testing.T) {
+	old, err := Parse("2026a="+testKey(1), "")
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+	row := Binding("tenant-1", "message-1")
+	sealed, keyID, err := old.Seal("x = 4, cikgu", row)
+	if err != nil {
+		t.Fatalf("Seal() error = %v", err)
+	}
+	if keyID != "2026a" || strings.Contains(sealed, "cikgu") {
+		t.Fatalf("Seal() = %q under %q, want ciphertext under 2026a", sealed, keyID)
+	}
+
+	rotated, err := Parse("2026a="+testKey(1)+", 2026b="+testKey(2), "2026b")
+	if err != nil {
+		t.Fatalf("Parse(rotated) error = %v", err)
+	}
+	if got, err := rotated.Open(keyID, sealed, row); err != nil || got != "x = 4, cikgu" {
+		t.Fatalf("Open(old key) = %q, %v", got, err)
+	}
+	if _, newID, _ := rotated.Seal("hi", row); newID != "2026b" {
+		t.Fatalf("rotated Seal() key = %q, want 2026b", newID)
+	}
+	if got, err := rotated.Open("", "stored before encryption", row); err != nil || got != "stored before encryption" {
+		t.Fatalf("Open(plaintext row) = %q, %v", got, err)
+	}
+	if _, err := rotated.Open("retired", sealed, row); err == nil {
+		t.Fatal("Open(unknown key) error = nil")
+	}
+}
+
+func TestOpenRejectsValueMovedToAnotherRow(t *testing.T) {
+	k, err := Parse("k1="+testKey(1), "")
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+	sealed, keyID, err := k.Seal("x = 4", Binding("tenant-1", "message-1"))
+	if err != nil {
+		t.Fatalf("Seal() error = %v", err)
+	}
+	for _, row := range [][]byte{
+		Binding("tenant-1", "message-2"),
+		Binding("tenant-2", "message-1"),
+		nil,
+	} {
+		if got, err := k.Open(keyID, sealed, row); err == nil {
+			t.Errorf("Open(%q) = %q, want an error for a value from another row", row, got)
+		}
+	}
+}
+
+func TestParseRejectsBadKeys(t *testing.T) {
+	for _, tc := range []struct{ raw, active string }{
+		{"k1", ""},
+		{"k1=" + base64.StdEncoding.EncodeToString([]byte("short")), ""},
+		{"k1=" + testKey(1) + ",k1=" + testKey(2), ""},
+		{"k1=" + testKey(1) + ",k2=" + testKey(2), ""},
+		{"k1=" + testKey(1), "k2"},
+		{"", "k1"},
+	} {
+		_, err := Parse(tc.raw, tc.active)
+		if err == nil {
+			t.Errorf("Parse(%q, %q) error = nil", tc.raw, tc.active)
+			continue
+		}
+		if strings.Contains(err.Error(), testKey(1)) {
+			t.Errorf("Parse error leaks the key: %v", err)
+		}
+	}
+}
@@ -0,0 +1,254 @@
+// Copyright 2026 the P&AI authors. All rights reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+// Package secrets re-reads credentials while the bot runs, so AI provider
+// keys and the auth secret can be rotated without a restart. Each secret is
+// looked up by its env var name in Vault, then in the file named by
+// <NAME>_FILE, then in the environment.
+package secrets
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"maps"
+	"net/http"
+	"os"
+	"os/signal"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/p-n-ai/pai-bot/internal/platform/config"
+)
+
+// AuthSecret is the env name of the JWT signing secret.
+const AuthSecret = "PAI_AUTH_SECRET"
+
+// aiKeys maps each rotatable provider API key to its config field.
+var aiKeys = map[string]func(*config.AIConfig) *string{
+	"LEARN_AI_OPENAI_API_KEY":     func(c *config.AIConfig) *string { return &c.OpenAI.APIKey },
+	"LEARN_AI_ANTHROPIC_API_KEY":  func(c *config.AIConfig) *string { return &c.Anthropic.APIKey },
+	"LEARN_AI_DEEPSEEK_API_KEY":   func(c *config.AIConfig) *string { return &c.DeepSeek.APIKey },
+	"LEARN_AI_GROQ_API_KEY":       func(c *config.AIConfig) *string { return &c.Groq.APIKey },
+	"LEARN_AI_GOOGLE_API_KEY":     func(c *config.AIConfig) *string { return &c.Google.APIKey },
+	"LEARN_AI_OPENROUTER_API_KEY": func(c *config.AIConfig) *string { return &c.OpenRouter.APIKey },
+}
+
+// Names lists every secret the Manager reloads.
+func Names() []string {
+	names := []string{AuthSecret}
+	for name := range aiKeys {
+		names = append(names, name)
+	}
+	return names
+}
+
+// Values holds the secrets found by the last reload, keyed by env name. A
+// name no source has is absent, not empty.
+type Values map[string]string
+
+// ApplyAI overwrites cfg's provider API keys with the ones in v.
+func (v Values) ApplyAI(cfg *config.AIConfig) {
+	for name, field := range aiKeys {
+		if value, ok := v[name]; ok {
+			*field(cfg) = value
+		}
+	}
+}
+
+// Source looks secrets up by env name.
+type Source interface {
+	Lookup(ctx context.Context, names []string) (Values, error)
+}
+
+// EnvSource reads secrets from the process environment.
+type EnvSource struct{}
+
+func (EnvSource) Lookup(_ context.Context, names []string) (Values, error) {
+	found := Values{}
+	for _, name := range names {
+		if value, ok := os.LookupEnv(name); ok && strings.TrimSpace(value) != "" {
+			found[name] = strings.TrimSpace(value)
+		}
+	}
+	return found, nil
+}
+
+// FileSource reads each secret from the file named by <NAME>_FILE, the
+// convention for Docker and Kubernetes mounted secrets. Files are read on
+// every lookup, so replacing the file rotates the secret.
+type FileSource struct{}
+
+func (FileSource) Lookup(_ context.Context, names []string) (Values, error) {
+	found := Values{}
+	var errs []error
+	for _, name := range names {
+		path := strings.TrimSpace(os.Getenv(name + "_FILE"))
+		if path == "" {
+			continue
+		}
+		raw, err := os.ReadFile(path)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("%s_FILE: %w", name, err))
+			continue
+		}
+		if value := strings.TrimSpace(string(raw)); value != "" {
+			found[name] = value
+		}
+	}
+	return found, errors.Join(errs...)
+}
+
+// VaultSource reads secrets from one HashiCorp Vault KV v2 secret whose keys
+// are the env names.
+type VaultSource struct {
+	// Addr is the Vault server, e.g. https://vault.internal:8200.
+	Addr string
+	// Path is the secret's API path under /v1, e.g. secret/data/pai-bot.
+	Path string
+	// Token authenticates the read. TokenFile, when set, is read on every
+	// lookup instead so the token itself can be rotated.
+	Token     string
+	TokenFile string
+	Client    *http.Client
+}
+
+func (s VaultSource) Lookup(ctx context.Context, names []string) (Values, error) {
+	token := s.Token
+	if s.TokenFile != "" {
+		raw, err := os.ReadFile(s.TokenFile)
+		if err != nil {
+			return nil, fmt.Errorf("read vault token: %w", err)
+		}
+		token = strings.TrimSpace(string(raw))
+	}
+	url := strings.TrimRight(s.Addr, "/") + "/v1/" + strings.TrimLeft(s.Path, "/")
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, fmt.Errorf("vault request: %w", err)
+	}
+	req.Header.Set("X-Vault-Token", token)
+	client := s.Client
+	if client == nil {
+		client = &http.Client{Timeout: 10 * time.Second}
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("vault read: %w", err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("vault read %s: status %d", s.Path, resp.StatusCode)
+	}
+	var body struct {
+		Data struct {
+			Data map[string]any `json:"data"`
+		} `json:"data"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+		return nil, fmt.Errorf("decode vault secret: %w", err)
+	}
+	found := Values{}
+	for _, name := range names {
+		if value, ok := body.Data.Data[name].(string); ok && strings.TrimSpace(value) != "" {
+			found[name] = strings.TrimSpace(value)
+		}
+	}
+	return found, nil
+}
+
+// Manager holds the current secrets and reloads them from its sources.
+type Manager struct {
+	sources []Source
+	names   []string
+
+	mu     sync.Mutex
+	values Values
+}
+
+// NewManager returns a Manager over sources, highest precedence first.
+func NewManager(sources ...Source) *Manager {
+	return &Manager{sources: sources, names: Names(), values: Values{}}
+}
+
+// FromConfig returns a Manager reading Vault when cfg configures it, then
+// secret files, then the environment.
+func FromConfig(cfg config.SecretsConfig) *Manager {
+	var sources []Source
+	if cfg.VaultAddr != "" {
+		sources = append(sources, VaultSource{
+			Addr:      cfg.VaultAddr,
+			Path:      cfg.VaultPath,
+			Token:     cfg.VaultToken,
+			TokenFile: cfg.VaultTokenFile,
+		})
+	}
+	return NewManager(append(sources, FileSource{}, EnvSource{})...)
+}
+
+// Current returns the secrets from the last successful reload.
+func (m *Manager) Current() Values {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return maps.Clone(m.values)
+}
+
+// Reload reads every source and reports whether any secret changed. A source
+// that fails leaves the secrets as they were, so a Vault outage can't blank a
+// working key.
+func (m *Manager) Reload(ctx context.Context) (Values, bool, error) {
+	next := Values{}
+	for i := len(m.sources) - 1; i >= 0; i-- {
+		found, err := m.sources[i].Lookup(ctx, m.names)
+		if err != nil {
+			return m.Current(), false, fmt.Errorf("reload secrets: %w", err)
+		}
+		maps.Copy(next, found)
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	changed := !maps.Equal(next, m.values)
+	m.values = next
+	return maps.Clone(next), changed, nil
+}
+
+// Watch reloads on SIGHUP and, when interval is positive, on every tick, and
+// calls apply with the new secrets whenever one changed. It returns when ctx
+// is done.
+func (m *Manager) Watch(ctx context.Context, interval time.Duration, apply func(Values)) {
+	hup := make(chan os.Signal, 1)
+	signal.Notify(hup, syscall.SIGHUP)
+	defer signal.Stop(hup)
+	var ticks <-chan time.Time
+	if interval > 0 {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		ticks = ticker.C
+	}
+	m.watch(ctx, hup, ticks, apply)
+}
+
+func (m *Manager) watch(ctx context.Context, hup <-chan os.Signal, ticks <-chan time.Time, apply func(Values)) {
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-hup:
+			slog.Info("SIGHUP received, reloading secrets")
+		case <-ticks:
+		}
+		values, changed, err := m.Reload(ctx)
+		if err != nil {
+			slog.Warn("secrets reload failed; keeping current secrets", "error", err)
+			continue
+		}
+		if changed {
+			slog.Info("secrets changed, applying")
+			apply(values)
+		}
+	}
+}
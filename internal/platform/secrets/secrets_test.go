@@ -0,0 +1,130 @@
+// Copyright 2026 the P&AI authors. All rights reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package secrets
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/p-n-ai/pai-bot/internal/platform/config"
+)
+
+func fakeVault(t *testing.T, data *string) *httptest.Server {
+	t.Helper()
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/v1/secret/data/pai-bot" || r.Header.Get("X-Vault-Token") != "vault-token" {
+			http.Error(w, "permission denied", http.StatusForbidden)
+			return
+		}
+		if *data == "" {
+			http.Error(w, "sealed", http.StatusServiceUnavailable)
+			return
+		}
+		_, _ = w.Write([]byte(`{"data":{"data":` + *data + `}}`))
+	}))
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+func TestReloadPrefersVaultThenFileThenEnv(t *testing.T) {
+	keyFile := filepath.Join(t.TempDir(), "anthropic")
+	if err := os.WriteFile(keyFile, []byte("sk-ant-file\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("LEARN_AI_OPENAI_API_KEY", "sk-env")
+	t.Setenv("LEARN_AI_ANTHROPIC_API_KEY", "sk-ant-env")
+	t.Setenv("LEARN_AI_ANTHROPIC_API_KEY_FILE", keyFile)
+	t.Setenv("LEARN_AI_GROQ_API_KEY", "gsk-env")
+	data := `{"LEARN_AI_GROQ_API_KEY":"gsk-vault","PAI_AUTH_SECRET":"jwt-vault"}`
+	vault := fakeVault(t, &data)
+
+	m := FromConfig(config.SecretsConfig{VaultAddr: vault.URL, VaultPath: "secret/data/pai-bot", VaultToken: "vault-token"})
+	values, changed, err := m.Reload(context.Background())
+	if err != nil || !changed {
+		t.Fatalf("Reload() = changed %v, error %v", changed, err)
+	}
+	for name, want := range map[string]string{
+		"LEARN_AI_OPENAI_API_KEY":    "sk-env",
+		"LEARN_AI_ANTHROPIC_API_KEY": "sk-ant-file",
+		"LEARN_AI_GROQ_API_KEY":      "gsk-vault",
+		AuthSecret:                   "jwt-vault",
+	} {
+		if values[name] != want {
+			t.Errorf("%s = %q, want %q", name, values[name], want)
+		}
+	}
+
+	var ai config.AIConfig
+	ai.Google.APIKey = "AIza-boot"
+	values.ApplyAI(&ai)
+	if ai.Groq.APIKey != "gsk-vault" || ai.Anthropic.APIKey != "sk-ant-file" || ai.Google.APIKey != "AIza-boot" {
+		t.Fatalf("ApplyAI() = %+v", ai)
+	}
+
+	if _, changed, _ := m.Reload(context.Background()); changed {
+		t.Fatal("Reload() with nothing rotated reported a change")
+	}
+}
+
+func TestReloadKeepsSecretsWhenASourceFails(t *testing.T) {
+	t.Setenv("LEARN_AI_OPENAI_API_KEY", "")
+	data := `{"LEARN_AI_OPENAI_API_KEY":"sk-one"}`
+	vault := fakeVault(t, &data)
+	m := FromConfig(config.SecretsConfig{VaultAddr: vault.URL, VaultPath: "secret/data/pai-bot", VaultToken: "vault-token"})
+	if _, _, err := m.Reload(context.Background()); err != nil {
+		t.Fatalf("Reload() error = %v", err)
+	}
+
+	data = ""
+	values, changed, err := m.Reload(context.Background())
+	if err == nil || changed {
+		t.Fatalf("Reload() with vault down = changed %v, error %v; want an error and no change", changed, err)
+	}
+	if values["LEARN_AI_OPENAI_API_KEY"] != "sk-one" {
+		t.Fatalf("key after failed reload = %q, want sk-one", values["LEARN_AI_OPENAI_API_KEY"])
+	}
+}
+
+func TestWatchAppliesRotatedSecrets(t *testing.T) {
+	keyFile := filepath.Join(t.TempDir(), "openai")
+	if err := os.WriteFile(keyFile, []byte("sk-old"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("LEARN_AI_OPENAI_API_KEY_FILE", keyFile)
+	m := NewManager(FileSource{})
+	if _, _, err := m.Reload(context.Background()); err != nil {
+		t.Fatalf("Reload() error = %v", err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	hup := make(chan os.Signal, 1)
+	applied := make(chan Values, 1)
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		m.watch(ctx, hup, nil, func(v Values) { applied <- v })
+	}()
+
+	if err := os.WriteFile(keyFile, []byte("sk-new"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	hup <- syscall.SIGHUP
+	select {
+	case v := <-applied:
+		if v["LEARN_AI_OPENAI_API_KEY"] != "sk-new" {
+			t.Fatalf("applied key = %q, want sk-new", v["LEARN_AI_OPENAI_API_KEY"])
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("rotated key was not applied after SIGHUP")
+	}
+	cancel()
+	<-done
+}
@@ -0,0 +1,43 @@
+// Copyright 2026 the P&AI authors. All rights reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+// Package textsearch tokenizes message history search queries the same way
+// for the learner /search command and the admin search API, both as a
+// PostgreSQL tsquery and as a plain substring match over decrypted content.
+package textsearch
+
+import (
+	"strings"
+	"unicode"
+)
+
+// Words lowercases query and splits it into letter/digit runs. Everything
+// else is a separator, which also strips tsquery operators a user might type.
+func Words(query string) []string {
+	return strings.FieldsFunc(strings.ToLower(query), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+}
+
+// PrefixTSQuery builds a to_tsquery expression requiring every word in query
+// as a prefix match, e.g. "pecahan:* & setara:*". It returns "" when query
+// has no words, so user input cannot produce a malformed query.
+func PrefixTSQuery(query string) string {
+	words := Words(query)
+	for i, w := range words {
+		words[i] = w + ":*"
+	}
+	return strings.Join(words, " & ")
+}
+
+// ContainsAll reports whether text contains every word, ignoring case. Words
+// must already be lowercase, as returned by Words.
+func ContainsAll(text string, words []string) bool {
+	lower := strings.ToLower(text)
+	for _, w := range words {
+		if !strings.Contains(lower, w) {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,35 @@
+// Copyright 2026 the P&AI authors. All rights reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package textsearch
+
+import "testing"
+
+func TestPrefixTSQueryStripsOperators(t *testing.T) {
+	for _, tc := range []struct {
+		query string
+		want  string
+	}{
+		{"pecahan", "pecahan:*"},
+		{"Pecahan  setara", "pecahan:* & setara:*"},
+		{"x & y | !z", "x:* & y:* & z:*"},
+		{"x & !y | (z):*", "x:* & y:* & z:*"},
+		{"Persamaan Linear 2x", "persamaan:* & linear:* & 2x:*"},
+		{"分数", "分数:*"},
+		{" !&| ", ""},
+	} {
+		if got := PrefixTSQuery(tc.query); got != tc.want {
+			t.Errorf("PrefixTSQuery(%q) = %q, want %q", tc.query, got, tc.want)
+		}
+	}
+}
+
+func TestContainsAllIgnoresCase(t *testing.T) {
+	words := Words("pecahan SETARA")
+	if !ContainsAll("Pecahan setara ialah 1/2 = 2/4", words) {
+		t.Fatal("ContainsAll() = false, want true for content with every word")
+	}
+	if ContainsAll("pecahan sahaja", words) {
+		t.Fatal("ContainsAll() = true, want false when a word is missing")
+	}
+}
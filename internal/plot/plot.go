@@ -0,0 +1,289 @@
+// Copyright 2026 the P&AI authors. All rights reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+// Package plot renders school graphs of y = f(x) to PNG for chat replies.
+package plot
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"image"
+	"image/color"
+	"image/png"
+	"math"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/p-n-ai/pai-bot/internal/algebra"
+)
+
+const (
+	width   = 600
+	height  = 450
+	margin  = 36
+	samples = width * 2
+
+	// DefaultXMin and DefaultXMax frame a graph when the caller gives no range.
+	DefaultXMin = -5.0
+	DefaultXMax = 5.0
+	// maxXSpan keeps tick spacing and sampling meaningful.
+	maxXSpan = 200.0
+)
+
+// ErrInvalidGraph is returned for a function or range the plotter cannot draw.
+var ErrInvalidGraph = errors.New("invalid graph")
+
+var (
+	background = color.RGBA{255, 255, 255, 255}
+	gridColor  = color.RGBA{226, 232, 240, 255}
+	axisColor  = color.RGBA{30, 41, 59, 255}
+	curveColor = color.RGBA{37, 99, 235, 255}
+	pointColor = color.RGBA{220, 38, 38, 255}
+	labelColor = color.RGBA{71, 85, 105, 255}
+)
+
+// functionPrefix strips "y =" or "f(x) =" from the function to plot.
+var functionPrefix = regexp.MustCompile(`(?i)^\s*(?:y|f\s*\(\s*x\s*\))\s*=`)
+
+// Graph is a rendered plot of one function.
+type Graph struct {
+	Function string
+	XMin     float64
+	XMax     float64
+	PNG      []byte
+}
+
+// Render draws y = function over [xMin, xMax], with axes, a grid, and the
+// x- and y-intercepts marked. function is a polynomial in x such as
+// "2x + 1" or "y = x^2 - 4x + 3".
+func Render(function string, xMin, xMax float64) (Graph, error) {
+	expr := strings.TrimSpace(functionPrefix.ReplaceAllString(function, ""))
+	p, err := algebra.ParseExpression(expr)
+	if err != nil {
+		return Graph{}, fmt.Errorf("%w: %v", ErrInvalidGraph, err)
+	}
+	for _, name := range p.Variables() {
+		if name != "x" {
+			return Graph{}, fmt.Errorf("%w: only x may vary, found %s", ErrInvalidGraph, name)
+		}
+	}
+	if xMin == 0 && xMax == 0 {
+		xMin, xMax = DefaultXMin, DefaultXMax
+	}
+	if math.IsNaN(xMin) || math.IsNaN(xMax) || xMax <= xMin || xMax-xMin > maxXSpan {
+		return Graph{}, fmt.Errorf("%w: x range must be increasing and at most %g wide", ErrInvalidGraph, maxXSpan)
+	}
+
+	f := func(x float64) float64 { return p.Eval(map[string]float64{"x": x}) }
+	xs := make([]float64, samples+1)
+	ys := make([]float64, samples+1)
+	for i := range xs {
+		xs[i] = xMin + (xMax-xMin)*float64(i)/samples
+		ys[i] = f(xs[i])
+		if math.IsInf(ys[i], 0) || math.IsNaN(ys[i]) {
+			return Graph{}, fmt.Errorf("%w: y is too large to draw at x = %g", ErrInvalidGraph, xs[i])
+		}
+	}
+	yMin, yMax := yRange(ys)
+
+	c := newCanvas(xMin, xMax, yMin, yMax)
+	c.drawGrid()
+	c.drawAxes()
+	for i := 1; i < len(xs); i++ {
+		c.line(xs[i-1], ys[i-1], xs[i], ys[i], curveColor, 2)
+	}
+	for _, x := range xIntercepts(xs, ys) {
+		c.dot(x, 0, pointColor)
+	}
+	if xMin <= 0 && 0 <= xMax {
+		c.dot(0, f(0), pointColor)
+	}
+
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, c.img); err != nil {
+		return Graph{}, fmt.Errorf("encode graph: %w", err)
+	}
+	return Graph{Function: expr, XMin: xMin, XMax: xMax, PNG: buf.Bytes()}, nil
+}
+
+// yRange frames the sampled values with some headroom and always shows the
+// x-axis when it is nearby, so intercepts stay in view.
+func yRange(ys []float64) (float64, float64) {
+	lo, hi := math.Inf(1), math.Inf(-1)
+	for _, y := range ys {
+		lo, hi = math.Min(lo, y), math.Max(hi, y)
+	}
+	if lo > 0 && lo < hi {
+		lo = 0
+	}
+	if hi < 0 && lo < hi {
+		hi = 0
+	}
+	if hi-lo < 1e-9 {
+		lo, hi = lo-1, hi+1
+	}
+	pad := (hi - lo) * 0.08
+	return lo - pad, hi + pad
+}
+
+// xIntercepts finds where the sampled curve touches or crosses y = 0.
+func xIntercepts(xs, ys []float64) []float64 {
+	var roots []float64
+	for i := 1; i < len(xs); i++ {
+		switch {
+		case ys[i-1] == 0:
+			roots = append(roots, xs[i-1])
+		case ys[i-1]*ys[i] < 0:
+			t := ys[i-1] / (ys[i-1] - ys[i])
+			roots = append(roots, xs[i-1]+t*(xs[i]-xs[i-1]))
+		}
+	}
+	if ys[len(ys)-1] == 0 {
+		roots = append(roots, xs[len(xs)-1])
+	}
+	return roots
+}
+
+// tickStep picks a 1, 2 or 5 times power-of-ten spacing giving about eight
+// grid lines across span.
+func tickStep(span float64) float64 {
+	raw := span / 8
+	magnitude := math.Pow(10, math.Floor(math.Log10(raw)))
+	for _, m := range []float64{1, 2, 5} {
+		if m*magnitude >= raw {
+			return m * magnitude
+		}
+	}
+	return 10 * magnitude
+}
+
+type canvas struct {
+	img                    *image.RGBA
+	xMin, xMax, yMin, yMax float64
+}
+
+func newCanvas(xMin, xMax, yMin, yMax float64) *canvas {
+	img := image.NewRGBA(image.Rect(0, 0, width, height))
+	for i := 0; i < len(img.Pix); i += 4 {
+		img.Pix[i], img.Pix[i+1], img.Pix[i+2], img.Pix[i+3] = background.R, background.G, background.B, background.A
+	}
+	return &canvas{img: img, xMin: xMin, xMax: xMax, yMin: yMin, yMax: yMax}
+}
+
+func (c *canvas) px(x, y float64) (float64, float64) {
+	return margin + (x-c.xMin)/(c.xMax-c.xMin)*(width-2*margin),
+		height - margin - (y-c.yMin)/(c.yMax-c.yMin)*(height-2*margin)
+}
+
+func (c *canvas) drawGrid() {
+	xStep := tickStep(c.xMax - c.xMin)
+	for x := math.Ceil(c.xMin/xStep) * xStep; x <= c.xMax; x += xStep {
+		c.line(x, c.yMin, x, c.yMax, gridColor, 1)
+		if math.Abs(x) > xStep/2 {
+			px, py := c.px(x, clamp(0, c.yMin, c.yMax))
+			c.text(formatTick(x), int(px), int(py)+6, true)
+		}
+	}
+	yStep := tickStep(c.yMax - c.yMin)
+	for y := math.Ceil(c.yMin/yStep) * yStep; y <= c.yMax; y += yStep {
+		c.line(c.xMin, y, c.xMax, y, gridColor, 1)
+		if math.Abs(y) > yStep/2 {
+			px, py := c.px(clamp(0, c.xMin, c.xMax), y)
+			c.text(formatTick(y), int(px)+6, int(py)-5, false)
+		}
+	}
+}
+
+func (c *canvas) drawAxes() {
+	y0 := clamp(0, c.yMin, c.yMax)
+	x0 := clamp(0, c.xMin, c.xMax)
+	c.line(c.xMin, y0, c.xMax, y0, axisColor, 1)
+	c.line(x0, c.yMin, x0, c.yMax, axisColor, 1)
+	px, py := c.px(c.xMax, y0)
+	c.text("x", int(px)-8, int(py)-16, false)
+	px, py = c.px(x0, c.yMax)
+	c.text("y", int(px)+8, int(py), false)
+}
+
+// line draws a segment in graph coordinates, clipped to the plot area.
+func (c *canvas) line(x1, y1, x2, y2 float64, col color.RGBA, thickness int) {
+	ax, ay := c.px(x1, y1)
+	bx, by := c.px(x2, y2)
+	steps := int(math.Max(math.Abs(bx-ax), math.Abs(by-ay))) + 1
+	if steps > 4*(width+height) {
+		// A near-vertical jump far off the canvas; draw only what is visible.
+		steps = 4 * (width + height)
+	}
+	for i := 0; i <= steps; i++ {
+		t := float64(i) / float64(steps)
+		c.fill(int(math.Round(ax+t*(bx-ax))), int(math.Round(ay+t*(by-ay))), thickness, col)
+	}
+}
+
+func (c *canvas) dot(x, y float64, col color.RGBA) {
+	px, py := c.px(x, y)
+	for dy := -4; dy <= 4; dy++ {
+		for dx := -4; dx <= 4; dx++ {
+			if dx*dx+dy*dy <= 16 {
+				c.set(int(px)+dx, int(py)+dy, col)
+			}
+		}
+	}
+}
+
+func (c *canvas) fill(px, py, thickness int, col color.RGBA) {
+	for dy := 0; dy < thickness; dy++ {
+		for dx := 0; dx < thickness; dx++ {
+			c.set(px+dx, py+dy, col)
+		}
+	}
+}
+
+func (c *canvas) set(px, py int, col color.RGBA) {
+	if px < margin/2 || px >= width-margin/2 || py < margin/2 || py >= height-margin/2 {
+		return
+	}
+	c.img.SetRGBA(px, py, col)
+}
+
+// text draws s with the built-in 3x5 font at twice size, centred on px when
+// centre is set.
+func (c *canvas) text(s string, px, py int, centre bool) {
+	const scale, advance = 2, 8
+	if centre {
+		px -= len(s) * advance / 2
+	}
+	for i, r := range s {
+		rows, ok := glyphs[r]
+		if !ok {
+			continue
+		}
+		for row, bits := range rows {
+			for col := 0; col < 3; col++ {
+				if bits&(4>>col) != 0 {
+					c.fill(px+i*advance+col*scale, py+row*scale, scale, labelColor)
+				}
+			}
+		}
+	}
+}
+
+// glyphs is a 3x5 pixel font for tick labels; each row's low three bits
+// are its pixels, left to right.
+var glyphs = map[rune][5]uint8{
+	'0': {7, 5, 5, 5, 7}, '1': {2, 6, 2, 2, 7}, '2': {7, 1, 7, 4, 7},
+	'3': {7, 1, 7, 1, 7}, '4': {5, 5, 7, 1, 1}, '5': {7, 4, 7, 1, 7},
+	'6': {7, 4, 7, 5, 7}, '7': {7, 1, 2, 2, 2}, '8': {7, 5, 7, 5, 7},
+	'9': {7, 5, 7, 1, 7}, '-': {0, 0, 7, 0, 0}, '.': {0, 0, 0, 0, 2},
+	'x': {0, 5, 2, 5, 0}, 'y': {5, 5, 2, 2, 2},
+}
+
+func formatTick(v float64) string {
+	return strconv.FormatFloat(math.Round(v*1000)/1000, 'f', -1, 64)
+}
+
+func clamp(v, lo, hi float64) float64 {
+	return math.Max(lo, math.Min(hi, v))
+}
@@ -0,0 +1,64 @@
+// Copyright 2026 the P&AI authors. All rights reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package plot
+
+import (
+	"bytes"
+	"errors"
+	"image/png"
+	"testing"
+)
+
+func TestRenderMarksInterceptsOnAPNG(t *testing.T) {
+	graph, err := Render("y = x^2 - 4", 0, 0)
+	if err != nil {
+		t.Fatalf("Render() error = %v", err)
+	}
+	if graph.Function != "x^2 - 4" || graph.XMin != DefaultXMin || graph.XMax != DefaultXMax {
+		t.Fatalf("Render() = %q over [%g, %g], want the default range", graph.Function, graph.XMin, graph.XMax)
+	}
+	img, err := png.Decode(bytes.NewReader(graph.PNG))
+	if err != nil {
+		t.Fatalf("decode PNG: %v", err)
+	}
+	if b := img.Bounds(); b.Dx() != width || b.Dy() != height {
+		t.Fatalf("bounds = %v, want %dx%d", b, width, height)
+	}
+
+	c := newCanvas(graph.XMin, graph.XMax, -4-0.08*25, 21+0.08*25)
+	for _, x := range []float64{-2, 2} {
+		px, py := c.px(x, 0)
+		if r, g, b, _ := img.At(int(px), int(py)).RGBA(); r>>8 != uint32(pointColor.R) || g>>8 != uint32(pointColor.G) || b>>8 != uint32(pointColor.B) {
+			t.Errorf("pixel at x-intercept %g = (%d, %d, %d), want the intercept marker", x, r>>8, g>>8, b>>8)
+		}
+	}
+}
+
+func TestRenderRejectsUnsupportedGraphs(t *testing.T) {
+	tests := []struct {
+		name       string
+		function   string
+		xMin, xMax float64
+	}{
+		{name: "second variable", function: "y = 2x + k"},
+		{name: "reciprocal", function: "y = 1/x"},
+		{name: "backwards range", function: "x", xMin: 3, xMax: -3},
+		{name: "range too wide", function: "x", xMin: -500, xMax: 500},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := Render(tt.function, tt.xMin, tt.xMax); !errors.Is(err, ErrInvalidGraph) {
+				t.Fatalf("Render() error = %v, want ErrInvalidGraph", err)
+			}
+		})
+	}
+}
+
+func TestTickStep(t *testing.T) {
+	for span, want := range map[float64]float64{10: 2, 200: 50, 4: 0.5, 50: 10} {
+		if got := tickStep(span); got != want {
+			t.Errorf("tickStep(%g) = %g, want %g", span, got, want)
+		}
+	}
+}
@@ -146,7 +146,8 @@ func (m *MemoryTracker) GetDueReviews(userID string) ([]ProgressItem, error) {
 	return result, nil
 }
 
-// SetMastery directly sets a topic's mastery score (dev/testing only).
+// SetMastery directly sets a topic's mastery score, for dev commands,
+// tests and quiz re-grading.
 func (m *MemoryTracker) SetMastery(userID, syllabusID, topicID string, score float64) error {
 	m.mu.Lock()
 	defer m.mu.Unlock()
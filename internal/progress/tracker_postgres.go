@@ -95,7 +95,8 @@ func (p *PostgresTracker) UpdateMastery(userID, syllabusID, topicID string, delt
 	return err
 }
 
-// SetMastery directly sets a topic's mastery score (dev/testing only).
+// SetMastery directly sets a topic's mastery score, for dev commands
+// and quiz re-grading.
 func (p *PostgresTracker) SetMastery(userID, syllabusID, topicID string, score float64) error {
 	ctx, cancel := context.WithTimeout(context.Background(), dbTimeout)
 	defer cancel()
@@ -18,6 +18,7 @@ const (
 	XPSourceStreak    XPSource = "streak"
 	XPSourceChallenge XPSource = "challenge"
 	XPSourceReview    XPSource = "review"
+	XPSourceReferral  XPSource = "referral"
 )
 
 // XP award amounts.
@@ -28,6 +29,8 @@ const (
 	XPStreakMilestone = 100 // on streak milestones (3, 7, 14, 30, etc.)
 	XPChallengeWin    = 30  // winning a peer challenge
 	XPReviewCompleted = 50  // completing post-challenge review
+	XPReferral        = 100 // when an invited friend finishes onboarding
+	XPReferralWelcome = 50  // the invited friend, on finishing onboarding
 )
 
 // XPEntry represents a single XP award.
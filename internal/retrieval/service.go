@@ -11,6 +11,8 @@ import (
 	"strings"
 	"sync"
 	"time"
+
+	"github.com/p-n-ai/pai-bot/internal/apperr"
 )
 
 const (
@@ -19,8 +21,8 @@ const (
 )
 
 var (
-	ErrNotFound        = errors.New("retrieval item not found")
-	ErrInvalidArgument = errors.New("retrieval invalid argument")
+	ErrNotFound        = apperr.UserInput(errors.New("retrieval item not found"))
+	ErrInvalidArgument = apperr.UserInput(errors.New("retrieval invalid argument"))
 	searchFieldWeights = map[string]float64{
 		"title":      3.2,
 		"tags":       2.4,
@@ -0,0 +1,69 @@
+// Copyright 2026 the P&AI authors. All rights reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package server
+
+import (
+	"net/http"
+
+	"github.com/p-n-ai/pai-bot/internal/auth"
+)
+
+type createAPITokenRequest struct {
+	Name string `json:"name"`
+}
+
+func handleAdminListAPITokens(adminProvider adminDataSourceProvider) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		admin, ok := resolveAdminDataSource(w, r, adminProvider)
+		if !ok {
+			return
+		}
+		tokens, err := admin.ListAPITokens()
+		if err != nil {
+			writeAdminError(w, err)
+			return
+		}
+		writeJSON(w, http.StatusOK, tokens)
+	}
+}
+
+// handleAdminCreateAPIToken returns the new token's secret. It is the only
+// response that ever contains it.
+func handleAdminCreateAPIToken(adminProvider adminDataSourceProvider) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		admin, ok := resolveAdminDataSource(w, r, adminProvider)
+		if !ok {
+			return
+		}
+		var body createAPITokenRequest
+		if err := decodeJSONBody(r, &body); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		createdBy := ""
+		if claims, ok := auth.ClaimsFromContext(r.Context()); ok {
+			createdBy = claims.Subject
+		}
+		token, err := admin.CreateAPIToken(body.Name, createdBy)
+		if err != nil {
+			writeAdminError(w, err)
+			return
+		}
+		writeJSON(w, http.StatusCreated, token)
+	}
+}
+
+func handleAdminRevokeAPIToken(adminProvider adminDataSourceProvider) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		admin, ok := resolveAdminDataSource(w, r, adminProvider)
+		if !ok {
+			return
+		}
+		if err := admin.RevokeAPIToken(r.PathValue("id")); err != nil {
+			writeAdminError(w, err)
+			return
+		}
+		w.WriteHeader(http.StatusNoContent)
+	}
+}
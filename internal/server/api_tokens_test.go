@@ -0,0 +1,88 @@
+// Copyright 2026 the P&AI authors. All rights reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package server
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestAdminAPITokenEndpoints(t *testing.T) {
+	tests := []struct {
+		name     string
+		method   string
+		path     string
+		body     string
+		token    func(*testing.T) string
+		wantCode int
+		wantBody string
+	}{
+		{
+			name:     "admin lists tokens without secrets",
+			method:   http.MethodGet,
+			path:     "/api/admin/api-tokens",
+			token:    mustIssueAdminToken,
+			wantCode: http.StatusOK,
+			wantBody: `"prefix":"pai_abc123"`,
+		},
+		{
+			name:     "admin creates a token and sees its secret once",
+			method:   http.MethodPost,
+			path:     "/api/admin/api-tokens",
+			body:     `{"name":"District dashboard"}`,
+			token:    mustIssueAdminToken,
+			wantCode: http.StatusCreated,
+			wantBody: `"token":"pai_def456secret"`,
+		},
+		{
+			name:     "blank name",
+			method:   http.MethodPost,
+			path:     "/api/admin/api-tokens",
+			body:     `{"name":" "}`,
+			token:    mustIssueAdminToken,
+			wantCode: http.StatusBadRequest,
+		},
+		{
+			name:     "admin revokes a token",
+			method:   http.MethodDelete,
+			path:     "/api/admin/api-tokens/token-1",
+			token:    mustIssueAdminToken,
+			wantCode: http.StatusNoContent,
+		},
+		{
+			name:     "unknown token",
+			method:   http.MethodDelete,
+			path:     "/api/admin/api-tokens/token-9",
+			token:    mustIssueAdminToken,
+			wantCode: http.StatusNotFound,
+		},
+		{
+			name:     "teachers cannot issue tokens",
+			method:   http.MethodPost,
+			path:     "/api/admin/api-tokens",
+			body:     `{"name":"Mine"}`,
+			token:    mustIssueTeacherToken,
+			wantCode: http.StatusForbidden,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			handler := newHandler(stubAdminAPI{}, &chatGatewayStub{})
+
+			req := httptest.NewRequest(tt.method, tt.path, strings.NewReader(tt.body))
+			req.Header.Set("Authorization", "Bearer "+tt.token(t))
+			rec := httptest.NewRecorder()
+			handler.ServeHTTP(rec, req)
+
+			if rec.Code != tt.wantCode {
+				t.Fatalf("status = %d, want %d (body %q)", rec.Code, tt.wantCode, rec.Body.String())
+			}
+			if tt.wantBody != "" && !strings.Contains(rec.Body.String(), tt.wantBody) {
+				t.Fatalf("body = %q, want it to contain %q", rec.Body.String(), tt.wantBody)
+			}
+		})
+	}
+}
@@ -0,0 +1,79 @@
+// Copyright 2026 the P&AI authors. All rights reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package server
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"time"
+)
+
+const (
+	ConversationArchiveInterval = time.Hour
+	// conversationArchiveBatch bounds one pass so a large backlog is worked
+	// off over several ticks instead of one long-running sweep.
+	conversationArchiveBatch = 200
+)
+
+type conversationArchiver interface {
+	ArchiveEndedConversations(ctx context.Context, cutoff time.Time, limit int) (int, error)
+}
+
+// ConversationArchiveWorker moves conversations that ended more than maxAge
+// ago into cold storage, keeping the hot messages table small.
+type ConversationArchiveWorker struct {
+	archiver conversationArchiver
+	maxAge   time.Duration
+	logger   *slog.Logger
+	now      func() time.Time
+}
+
+func NewConversationArchiveWorker(archiver conversationArchiver, maxAge time.Duration, logger *slog.Logger) (*ConversationArchiveWorker, error) {
+	if archiver == nil {
+		return nil, fmt.Errorf("conversation archiver is required")
+	}
+	if maxAge <= 0 {
+		return nil, fmt.Errorf("conversation archive age must be positive")
+	}
+	if logger == nil {
+		logger = slog.Default()
+	}
+	return &ConversationArchiveWorker{archiver: archiver, maxAge: maxAge, logger: logger, now: time.Now}, nil
+}
+
+func (w *ConversationArchiveWorker) Run(ctx context.Context) {
+	ticker := time.NewTicker(ConversationArchiveInterval)
+	defer ticker.Stop()
+	w.run(ctx, ticker.C)
+}
+
+func (w *ConversationArchiveWorker) run(ctx context.Context, ticks <-chan time.Time) {
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticks:
+			err := w.RunOnce(ctx)
+			if ctx.Err() != nil {
+				return
+			}
+			if err != nil {
+				w.logger.Warn("conversation archive failed", "error", err)
+			}
+		}
+	}
+}
+
+// RunOnce archives one batch of conversations that ended before the cutoff.
+func (w *ConversationArchiveWorker) RunOnce(ctx context.Context) error {
+	archived, err := w.archiver.ArchiveEndedConversations(ctx, w.now().UTC().Add(-w.maxAge), conversationArchiveBatch)
+	if err != nil {
+		return fmt.Errorf("archiving conversations (archived %d): %w", archived, err)
+	}
+	if archived > 0 {
+		w.logger.Info("conversation archive completed", "archived", archived)
+	}
+	return nil
+}
@@ -0,0 +1,68 @@
+// Copyright 2026 the P&AI authors. All rights reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package server
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestConversationArchiveWorkerUsesAgeCutoff(t *testing.T) {
+	archiver := &recordingConversationArchiver{called: make(chan struct{}, 1)}
+	worker, err := NewConversationArchiveWorker(archiver, 90*24*time.Hour, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	now := time.Date(2026, 10, 17, 8, 0, 0, 0, time.UTC)
+	worker.now = func() time.Time { return now }
+
+	ticks := make(chan time.Time, 1)
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		worker.run(ctx, ticks)
+		close(done)
+	}()
+
+	ticks <- now
+	select {
+	case <-archiver.called:
+	case <-time.After(time.Second):
+		t.Fatal("archive did not run")
+	}
+	cancel()
+	<-done
+
+	archiver.mu.Lock()
+	defer archiver.mu.Unlock()
+	if want := now.Add(-90 * 24 * time.Hour); !archiver.cutoff.Equal(want) {
+		t.Fatalf("cutoff = %v, want %v", archiver.cutoff, want)
+	}
+	if archiver.limit != conversationArchiveBatch {
+		t.Fatalf("limit = %d, want %d", archiver.limit, conversationArchiveBatch)
+	}
+}
+
+func TestNewConversationArchiveWorkerRejectsNonPositiveAge(t *testing.T) {
+	if _, err := NewConversationArchiveWorker(&recordingConversationArchiver{}, 0, nil); err == nil {
+		t.Fatal("NewConversationArchiveWorker() error = nil, want age error")
+	}
+}
+
+type recordingConversationArchiver struct {
+	mu     sync.Mutex
+	cutoff time.Time
+	limit  int
+	called chan struct{}
+}
+
+func (a *recordingConversationArchiver) ArchiveEndedConversations(_ context.Context, cutoff time.Time, limit int) (int, error) {
+	a.mu.Lock()
+	a.cutoff, a.limit = cutoff, limit
+	a.mu.Unlock()
+	a.called <- struct{}{}
+	return 1, nil
+}
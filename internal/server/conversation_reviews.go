@@ -0,0 +1,95 @@
+// Copyright 2026 the P&AI authors. All rights reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package server
+
+import (
+	"log/slog"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/p-n-ai/pai-bot/internal/auth"
+)
+
+func handleAdminListConversationReviews(adminProvider adminDataSourceProvider) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		admin, ok := resolveAdminDataSource(w, r, adminProvider)
+		if !ok {
+			return
+		}
+		query := r.URL.Query()
+		limit := 0
+		if raw := strings.TrimSpace(query.Get("limit")); raw != "" {
+			parsed, err := strconv.Atoi(raw)
+			if err != nil || parsed < 1 {
+				http.Error(w, "limit must be a positive integer", http.StatusBadRequest)
+				return
+			}
+			limit = parsed
+		}
+		reviews, err := admin.ListConversationReviews(query.Get("status"), limit)
+		if err != nil {
+			writeAdminError(w, err)
+			return
+		}
+		writeJSON(w, http.StatusOK, reviews)
+	}
+}
+
+type rateConversationReviewRequest struct {
+	Rating  int    `json:"rating"`
+	Comment string `json:"comment"`
+}
+
+func handleAdminRateConversationReview(adminProvider adminDataSourceProvider) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		admin, ok := resolveAdminDataSource(w, r, adminProvider)
+		if !ok {
+			return
+		}
+		var body rateConversationReviewRequest
+		if err := decodeJSONBody(r, &body); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		reviewer := ""
+		if claims, ok := auth.ClaimsFromContext(r.Context()); ok {
+			reviewer = claims.Subject
+		}
+		review, err := admin.RateConversationReview(r.PathValue("id"), body.Rating, body.Comment, reviewer)
+		if err != nil {
+			writeAdminError(w, err)
+			return
+		}
+		writeJSON(w, http.StatusOK, review)
+	}
+}
+
+// handleAdminExportReviewEvals downloads rated reviews as a conversation
+// harness fixture, ready to run with cmd/conversation-harness -fixture.
+func handleAdminExportReviewEvals(adminProvider adminDataSourceProvider) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		admin, ok := resolveAdminDataSource(w, r, adminProvider)
+		if !ok {
+			return
+		}
+		fixture, err := admin.ExportReviewEvalFixture()
+		if err != nil {
+			writeAdminError(w, err)
+			return
+		}
+		body, err := yaml.Marshal(fixture)
+		if err != nil {
+			slog.Error("failed to encode review eval fixture", "error", err)
+			http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+			return
+		}
+		w.Header().Set("Content-Type", "application/yaml")
+		w.Header().Set("Content-Disposition", `attachment; filename="review-conversations.yaml"`)
+		w.WriteHeader(http.StatusOK)
+		_, _ = w.Write(body)
+	}
+}
@@ -0,0 +1,100 @@
+// Copyright 2026 the P&AI authors. All rights reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package server
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/p-n-ai/pai-bot/internal/auth"
+	"github.com/p-n-ai/pai-bot/internal/retrieval"
+)
+
+func TestAdminConversationReviewEndpoints(t *testing.T) {
+	adminToken := func(t *testing.T) string { return mustIssueToken(t, auth.RoleAdmin) }
+	tests := []struct {
+		name     string
+		method   string
+		path     string
+		body     string
+		token    func(*testing.T) string
+		wantCode int
+		wantBody string
+	}{
+		{
+			name:     "admin lists the pending queue",
+			method:   http.MethodGet,
+			path:     "/api/admin/reviews?status=pending&limit=10",
+			token:    adminToken,
+			wantCode: http.StatusOK,
+			wantBody: `"reason":"safety"`,
+		},
+		{
+			name:     "unknown status",
+			method:   http.MethodGet,
+			path:     "/api/admin/reviews?status=flagged",
+			token:    adminToken,
+			wantCode: http.StatusBadRequest,
+		},
+		{
+			name:     "bad limit",
+			method:   http.MethodGet,
+			path:     "/api/admin/reviews?limit=0",
+			token:    adminToken,
+			wantCode: http.StatusBadRequest,
+		},
+		{
+			name:     "admin rates a review",
+			method:   http.MethodPost,
+			path:     "/api/admin/reviews/review-1",
+			body:     `{"rating":4,"comment":"Good scaffolding"}`,
+			token:    adminToken,
+			wantCode: http.StatusOK,
+			wantBody: `"rating":4`,
+		},
+		{
+			name:     "rating out of range",
+			method:   http.MethodPost,
+			path:     "/api/admin/reviews/review-1",
+			body:     `{"rating":9}`,
+			token:    adminToken,
+			wantCode: http.StatusBadRequest,
+		},
+		{
+			name:     "admin exports the eval fixture",
+			method:   http.MethodGet,
+			path:     "/api/admin/reviews/export",
+			token:    adminToken,
+			wantCode: http.StatusOK,
+			wantBody: "- user: Solve 2x = 10",
+		},
+		{
+			name:     "teachers cannot review",
+			method:   http.MethodGet,
+			path:     "/api/admin/reviews",
+			token:    mustIssueTeacherToken,
+			wantCode: http.StatusForbidden,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			handler := newHandlerWithAdminProvider(fixedAdminDataSourceProvider{source: stubAdminAPI{}}, nil, &chatGatewayStub{}, retrieval.NewMemoryService(), &stubAuthService{}, auth.NewSigningSecret("change-me-in-production"), time.Hour, "", nil, nil, false, nil, nil, nil)
+
+			req := httptest.NewRequest(tt.method, tt.path, strings.NewReader(tt.body))
+			req.Header.Set("Authorization", "Bearer "+tt.token(t))
+			rec := httptest.NewRecorder()
+			handler.ServeHTTP(rec, req)
+
+			if rec.Code != tt.wantCode {
+				t.Fatalf("status = %d, want %d (body %q)", rec.Code, tt.wantCode, rec.Body.String())
+			}
+			if tt.wantBody != "" && !strings.Contains(rec.Body.String(), tt.wantBody) {
+				t.Fatalf("body = %q, want it to contain %q", rec.Body.String(), tt.wantBody)
+			}
+		})
+	}
+}
@@ -0,0 +1,52 @@
+// Copyright 2026 the P&AI authors. All rights reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package server
+
+import (
+	"net/http"
+
+	"github.com/p-n-ai/pai-bot/internal/adminapi"
+)
+
+func handleAdminListDailyProblems(adminProvider adminDataSourceProvider) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		admin, ok := resolveAdminDataSource(w, r, adminProvider)
+		if !ok {
+			return
+		}
+		problems, err := admin.ListDailyProblems()
+		if err != nil {
+			writeAdminError(w, err)
+			return
+		}
+		writeJSON(w, http.StatusOK, problems)
+	}
+}
+
+type saveDailyProblemRequest struct {
+	TopicID  string                        `json:"topic_id"`
+	Question adminapi.DailyProblemQuestion `json:"question"`
+}
+
+// handleAdminSaveDailyProblem curates a form's problem of the day. The bot
+// reads it when the day comes, so no reload is needed.
+func handleAdminSaveDailyProblem(adminProvider adminDataSourceProvider) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		admin, ok := resolveAdminDataSource(w, r, adminProvider)
+		if !ok {
+			return
+		}
+		var body saveDailyProblemRequest
+		if err := decodeJSONBody(r, &body); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		problem, err := admin.SaveDailyProblem(r.PathValue("form"), r.PathValue("day"), body.TopicID, body.Question)
+		if err != nil {
+			writeAdminError(w, err)
+			return
+		}
+		writeJSON(w, http.StatusOK, problem)
+	}
+}
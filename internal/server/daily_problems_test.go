@@ -0,0 +1,78 @@
+// Copyright 2026 the P&AI authors. All rights reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package server
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/p-n-ai/pai-bot/internal/auth"
+	"github.com/p-n-ai/pai-bot/internal/retrieval"
+)
+
+func TestAdminDailyProblemEndpoints(t *testing.T) {
+	tests := []struct {
+		name     string
+		method   string
+		path     string
+		body     string
+		token    func(*testing.T) string
+		wantCode int
+		wantBody string
+	}{
+		{
+			name:     "teacher lists problems",
+			method:   http.MethodGet,
+			path:     "/api/admin/daily-problems",
+			token:    mustIssueTeacherToken,
+			wantCode: http.StatusOK,
+			wantBody: "[]",
+		},
+		{
+			name:     "teacher curates a problem",
+			method:   http.MethodPut,
+			path:     "/api/admin/daily-problems/1/2026-10-20",
+			body:     `{"topic_id":"F1-02","question":{"text":"Solve 2x = 10.","answer":"5"}}`,
+			token:    mustIssueTeacherToken,
+			wantCode: http.StatusOK,
+			wantBody: `"source":"curated"`,
+		},
+		{
+			name:     "missing answer",
+			method:   http.MethodPut,
+			path:     "/api/admin/daily-problems/1/2026-10-20",
+			body:     `{"topic_id":"F1-02","question":{"text":"Solve 2x = 10."}}`,
+			token:    mustIssueTeacherToken,
+			wantCode: http.StatusBadRequest,
+		},
+		{
+			name:     "students cannot curate",
+			method:   http.MethodPut,
+			path:     "/api/admin/daily-problems/1/2026-10-20",
+			body:     `{"topic_id":"F1-02","question":{"text":"Solve 2x = 10.","answer":"5"}}`,
+			token:    mustIssueStudentToken,
+			wantCode: http.StatusForbidden,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			handler := newHandlerWithAdminProvider(fixedAdminDataSourceProvider{source: stubAdminAPI{}}, nil, &chatGatewayStub{}, retrieval.NewMemoryService(), &stubAuthService{}, auth.NewSigningSecret("change-me-in-production"), time.Hour, "", nil, nil, false, nil, nil, nil)
+
+			req := httptest.NewRequest(tt.method, tt.path, strings.NewReader(tt.body))
+			req.Header.Set("Authorization", "Bearer "+tt.token(t))
+			rec := httptest.NewRecorder()
+			handler.ServeHTTP(rec, req)
+
+			if rec.Code != tt.wantCode {
+				t.Fatalf("status = %d, want %d (body %q)", rec.Code, tt.wantCode, rec.Body.String())
+			}
+			if tt.wantBody != "" && !strings.Contains(rec.Body.String(), tt.wantBody) {
+				t.Fatalf("body = %q, want it to contain %q", rec.Body.String(), tt.wantBody)
+			}
+		})
+	}
+}
@@ -44,15 +44,25 @@ func (w *FocusedPageCleanupWorker) run(ctx context.Context, ticks <-chan time.Ti
 		case <-ctx.Done():
 			return
 		case <-ticks:
-			deleted, err := w.cleaner.CleanupExpired(ctx, w.now().UTC())
+			err := w.RunOnce(ctx)
 			if ctx.Err() != nil {
 				return
 			}
 			if err != nil {
 				w.logger.Warn("focused page cleanup failed", "deleted", 0, "failed", true)
-				continue
 			}
-			w.logger.Info("focused page cleanup completed", "deleted", deleted, "failed", false)
 		}
 	}
 }
+
+// RunOnce deletes the pages that have expired by now. The error is not
+// logged here: it can carry page content, so callers log only that the
+// sweep failed.
+func (w *FocusedPageCleanupWorker) RunOnce(ctx context.Context) error {
+	deleted, err := w.cleaner.CleanupExpired(ctx, w.now().UTC())
+	if err != nil {
+		return err
+	}
+	w.logger.Info("focused page cleanup completed", "deleted", deleted, "failed", false)
+	return nil
+}
@@ -5,6 +5,7 @@ package server
 
 import (
 	"context"
+	"strconv"
 	"testing"
 	"time"
 
@@ -108,3 +109,39 @@ func TestFocusedPageOutboxLeavesPlainTurnsRemindersAndNotificationsOnDirectGatew
 		}
 	}
 }
+
+type receiptChannel struct {
+	chat.MockChannel
+	nextID int
+}
+
+func (c *receiptChannel) SendMessageWithReceipt(ctx context.Context, userID string, msg chat.OutboundMessage) (chat.SendReceipt, error) {
+	if err := c.SendMessage(ctx, userID, msg); err != nil {
+		return chat.SendReceipt{}, err
+	}
+	c.nextID++
+	return chat.SendReceipt{MessageIDs: []string{strconv.Itoa(c.nextID)}}, nil
+}
+
+func TestGatewayTurnDelivererLinksAnswerToSentMessage(t *testing.T) {
+	ctx := context.Background()
+	store := agent.NewMemoryStore()
+	convID, _ := store.CreateConversation(agent.Conversation{UserID: "learner-1", State: "teaching"})
+	answerID, _ := store.AddMessage(convID, agent.StoredMessage{Role: "assistant", Content: "Try dividing both sides."})
+
+	gateway := chat.NewGateway()
+	channel := &receiptChannel{nextID: 900}
+	gateway.Register("telegram", channel)
+
+	err := NewGatewayTurnDeliverer(gateway, store, nil).DeliverTurn(ctx,
+		chat.InboundMessage{Channel: "telegram", UserID: "learner-1"},
+		agent.TurnResult{Text: "Try dividing both sides.", ConversationID: convID, AssistantMessageID: answerID},
+	)
+	if err != nil {
+		t.Fatalf("DeliverTurn() error = %v", err)
+	}
+	conv, _ := store.GetConversation(convID)
+	if got := conv.Messages[0].ExternalID; got != "901" {
+		t.Fatalf("answer ExternalID = %q, want 901", got)
+	}
+}
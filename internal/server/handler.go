@@ -14,11 +14,13 @@ import (
 	"net/http"
 	"net/url"
 	"slices"
+	"strconv"
 	"strings"
 	"time"
 
 	"github.com/p-n-ai/pai-bot/internal/adminapi"
 	"github.com/p-n-ai/pai-bot/internal/agent"
+	"github.com/p-n-ai/pai-bot/internal/ai"
 	"github.com/p-n-ai/pai-bot/internal/apidocs"
 	"github.com/p-n-ai/pai-bot/internal/auth"
 	"github.com/p-n-ai/pai-bot/internal/chat"
@@ -40,6 +42,9 @@ type TenantAdminDataSourceProvider = tenantAdminDataSourceProvider
 type GatewayNotifier = gatewayNotifier
 type GatewayTurnDeliverer = gatewayTurnDeliverer
 type RuntimeSettingsStore = runtimeSettingsStore
+type ConversationAdmin = conversationAdmin
+type APITokenResolver = apiTokenResolver
+type UsageStatsSource = usageStatsSource
 
 func NewGatewaySender(gw *chat.Gateway) messageSender { return gatewaySender{gw: gw} }
 func NewGatewayNotifier(gw *chat.Gateway, channels userChannelLookup) GatewayNotifier {
@@ -57,8 +62,8 @@ func NewWeeklyParentReportSource(admin *adminapi.Service) weeklyParentReportSour
 func NewBootstrapRetrievalService(loader *curriculum.Loader) *retrieval.Service {
 	return newBootstrapRetrievalService(loader)
 }
-func NewHandlerWithAdminProvider(adminProvider AdminDataSourceProvider, joinSource JoinClassSource, sender MessageSender, retrievalService *retrieval.Service, authSvc AuthService, jwtSecret string, accessTokenTTL time.Duration, inviteBaseURL string, settingsStore RuntimeSettingsStore, applySettings func(settings.Settings), multiTenant bool) http.Handler {
-	return newHandlerWithAdminProvider(adminProvider, joinSource, sender, retrievalService, authSvc, jwtSecret, accessTokenTTL, inviteBaseURL, settingsStore, applySettings, multiTenant)
+func NewHandlerWithAdminProvider(adminProvider AdminDataSourceProvider, joinSource JoinClassSource, sender MessageSender, retrievalService *retrieval.Service, authSvc AuthService, jwtSecret *auth.SigningSecret, accessTokenTTL time.Duration, inviteBaseURL string, settingsStore RuntimeSettingsStore, applySettings func(settings.Settings), multiTenant bool, conversations ConversationAdmin, teachingNotes TeachingNoteCurriculum, templates MessageTemplateOverrides) http.Handler {
+	return newHandlerWithAdminProvider(adminProvider, joinSource, sender, retrievalService, authSvc, jwtSecret, accessTokenTTL, inviteBaseURL, settingsStore, applySettings, multiTenant, conversations, teachingNotes, templates)
 }
 func NewTenantAdminDataSourceProvider(newForTenant func(string) AdminDataSource, newForPlatform func() AdminDataSource, defaultTenantID func(context.Context) (string, error)) TenantAdminDataSourceProvider {
 	return tenantAdminDataSourceProvider{newForTenant: newForTenant, newForPlatform: newForPlatform, defaultTenantID: defaultTenantID}
@@ -75,9 +80,28 @@ type TopMuxOptions struct {
 	WAMeowChannel      *chat.WhatsAppMeowChannel
 	InboundHandler     func(chat.InboundMessage)
 	AuthService        AuthService
-	JWTSecret          string
+	JWTSecret          *auth.SigningSecret
 	AccessTokenTTL     time.Duration
 	FocusedPageHandler http.Handler
+	InboundPool        *chat.InboundPool
+	// TelegramWebAppHandler serves the Telegram Mini App API. Nil leaves it
+	// unmounted.
+	TelegramWebAppHandler http.Handler
+	// StripeWebhookHandler applies Stripe subscription events. Nil leaves it
+	// unmounted.
+	StripeWebhookHandler http.Handler
+	// PublicUsageHandler serves tenant usage statistics to API token
+	// holders. Nil leaves it unmounted.
+	PublicUsageHandler http.Handler
+	// LatencySLO serves per-channel response time compliance to admins. Nil
+	// leaves it unmounted.
+	LatencySLO *agent.LatencySLOMonitor
+	// AIRouter serves per-provider AI latency and circuit breaker state to
+	// admins. Nil leaves them unmounted.
+	AIRouter *ai.Router
+	// Gateway adds per-channel health to /readyz and serves the full report
+	// to admins. Nil leaves /readyz static.
+	Gateway *chat.Gateway
 }
 
 func NewTopMux(opts TopMuxOptions) http.Handler {
@@ -90,10 +114,24 @@ func NewTopMux(opts TopMuxOptions) http.Handler {
 	if opts.FocusedPageHandler != nil {
 		topMux.Handle("/a/{publicID}", opts.FocusedPageHandler)
 	}
+	if opts.TelegramWebAppHandler != nil {
+		topMux.Handle("/api/telegram/webapp/", opts.TelegramWebAppHandler)
+	}
+	if opts.StripeWebhookHandler != nil {
+		topMux.Handle("POST /webhook/stripe", opts.StripeWebhookHandler)
+	}
+	if opts.PublicUsageHandler != nil {
+		// Token holders get the API's headers and rate limit but none of its
+		// session auth.
+		limiter := newFixedWindowLimiter(defaultAPIRateLimitPerMinute, time.Minute)
+		usageHandler := withSecurityHeaders(withCORS(withAPIRateLimit(opts.PublicUsageHandler, time.Now, limiter, limiter)))
+		topMux.Handle("GET /api/public/v1/usage", usageHandler)
+		topMux.Handle("OPTIONS /api/public/v1/usage", usageHandler)
+	}
 	if opts.WACloudChannel != nil {
 		topMux.Handle("/webhook/whatsapp", opts.WACloudChannel.WebhookHandler(opts.InboundHandler))
 	}
-	manager := auth.NewTokenManager(opts.JWTSecret, opts.AccessTokenTTL)
+	manager := auth.NewRotatingTokenManager(opts.JWTSecret, opts.AccessTokenTTL)
 	waAuth := chain(
 		authenticateRequests(opts.AuthService, manager, time.Now),
 		auth.RequireRoles(auth.RoleAdmin, auth.RolePlatformAdmin),
@@ -110,6 +148,35 @@ func NewTopMux(opts TopMuxOptions) http.Handler {
 		topMux.Handle("GET /api/admin/whatsapp/status", waStatusHandler)
 		topMux.Handle("OPTIONS /api/admin/whatsapp/status", waStatusHandler)
 	}
+	if opts.InboundPool != nil {
+		inboundStatsHandler := withCORS(waAuth(opts.InboundPool.StatsHandler()))
+		topMux.Handle("GET /api/admin/inbound/stats", inboundStatsHandler)
+		topMux.Handle("OPTIONS /api/admin/inbound/stats", inboundStatsHandler)
+	}
+	if opts.LatencySLO != nil {
+		sloHandler := withCORS(waAuth(opts.LatencySLO.StatsHandler()))
+		topMux.Handle("GET /api/admin/slo/latency", sloHandler)
+		topMux.Handle("OPTIONS /api/admin/slo/latency", sloHandler)
+	}
+	if opts.AIRouter != nil {
+		aiLatencyHandler := withCORS(waAuth(opts.AIRouter.LatencyStatsHandler()))
+		topMux.Handle("GET /api/admin/ai/latency", aiLatencyHandler)
+		topMux.Handle("OPTIONS /api/admin/ai/latency", aiLatencyHandler)
+		aiBreakersHandler := withCORS(waAuth(opts.AIRouter.BreakerStatsHandler()))
+		topMux.Handle("GET /api/admin/ai/breakers", aiBreakersHandler)
+		topMux.Handle("OPTIONS /api/admin/ai/breakers", aiBreakersHandler)
+	}
+	if opts.Gateway != nil {
+		topMux.Handle("GET /readyz", handleReadyzWithChannels(opts.Gateway))
+		channelHealthHandler := withCORS(waAuth(opts.Gateway.ChannelHealthHandler()))
+		topMux.Handle("GET /api/admin/channels/health", channelHealthHandler)
+		topMux.Handle("OPTIONS /api/admin/channels/health", channelHealthHandler)
+	}
+	if opts.WSChannel != nil {
+		presenceHandler := withCORS(waAuth(opts.WSChannel.PresenceHandler()))
+		topMux.Handle("GET /api/admin/presence", presenceHandler)
+		topMux.Handle("OPTIONS /api/admin/presence", presenceHandler)
+	}
 	topMux.Handle("/", opts.APIHandler)
 	return topMux
 }
@@ -127,6 +194,9 @@ type adminDataSource interface {
 	GetClassProgress(classID string) (adminapi.ClassProgress, error)
 	GetStudentDetail(studentID string) (adminapi.StudentDetail, error)
 	GetStudentConversations(studentID string) ([]adminapi.StudentConversation, error)
+	GetConversationTranscript(conversationID string) (adminapi.ConversationTranscript, error)
+	SearchMessages(query, studentID string, limit int) ([]adminapi.MessageSearchHit, error)
+	GetDeliveryReport(status string, limit int) (adminapi.DeliveryReport, error)
 	GetParentSummary(parentID string) (adminapi.ParentSummary, error)
 	GetAIUsage() (adminapi.AIUsageSummary, error)
 	UpsertTenantTokenBudgetWindow(req adminapi.UpsertTokenBudgetWindowRequest) (adminapi.AIUsageSummary, error)
@@ -146,6 +216,30 @@ type adminDataSource interface {
 	AddGroupMember(groupID, userID, role string) error
 	RemoveGroupMember(groupID, userID string) error
 	GetGroupLeaderboard(id string) ([]adminapi.AdminLeaderboardEntry, error)
+	ListTeachingNotes() ([]adminapi.TeachingNote, error)
+	GetTeachingNoteHistory(topicID string) ([]adminapi.TeachingNote, error)
+	SaveTeachingNote(topicID string, overlay curriculum.TeachingNoteOverlay, createdByUserID string) (adminapi.TeachingNote, error)
+	ListMessageTemplates() (adminapi.MessageTemplateView, error)
+	SaveMessageTemplate(key, locale, body, updatedByUserID string) (adminapi.MessageTemplate, error)
+	DeleteMessageTemplate(key, locale string) (adminapi.MessageTemplate, error)
+	ListDailyProblems() ([]adminapi.DailyProblem, error)
+	SaveDailyProblem(form, day, topicID string, question adminapi.DailyProblemQuestion) (adminapi.DailyProblem, error)
+	ListConversationReviews(status string, limit int) ([]adminapi.ConversationReview, error)
+	RateConversationReview(reviewID string, rating int, comment, reviewerUserID string) (adminapi.ConversationReview, error)
+	ExportReviewEvalFixture() (adminapi.EvalFixture, error)
+	ExportLearningState(studentID string) (adminapi.LearningState, error)
+	ImportLearningState(state adminapi.LearningState) (adminapi.LearningStateImportResult, error)
+	GetTenantSettings() (adminapi.TenantSettings, error)
+	UpdateTenantSettings(settings adminapi.TenantSettings) (adminapi.TenantSettings, error)
+	ListAPITokens() ([]adminapi.APIToken, error)
+	CreateAPIToken(name, createdByUserID string) (adminapi.CreatedAPIToken, error)
+	RevokeAPIToken(id string) error
+}
+
+// conversationAdmin runs engine-side maintenance on a conversation the caller
+// has already been authorized to see through adminDataSource.
+type conversationAdmin interface {
+	Resummarize(ctx context.Context, conversationID string) (string, error)
 }
 
 type joinClassSource interface {
@@ -301,10 +395,26 @@ func (d gatewayTurnDeliverer) DeliverTurn(ctx context.Context, inbound chat.Inbo
 		})
 	}
 	out, ok := chat.RenderTurn(inbound, result.Text, "", telegramInlineKeyboardContext(d.store, inbound.UserID))
+	if result.Graph != nil && chat.SupportsPhotos(inbound.Channel) {
+		out.Photo = result.Graph.PNG
+		ok = true
+	}
 	if !ok {
 		return nil
 	}
-	return d.gw.Send(ctx, out)
+	receipt, err := d.gw.SendWithReceipt(ctx, out)
+	if err != nil {
+		return err
+	}
+	// Link the stored answer to its last delivered part so reactions on it
+	// can be attributed; long answers split across messages keep only the last.
+	if result.AssistantMessageID != "" && len(receipt.MessageIDs) > 0 && d.store != nil {
+		externalID := receipt.MessageIDs[len(receipt.MessageIDs)-1]
+		if err := d.store.SetMessageExternalID(result.ConversationID, result.AssistantMessageID, externalID); err != nil {
+			slog.Warn("failed to link delivered answer", "conversation_id", result.ConversationID, "message_id", result.AssistantMessageID, "error", err)
+		}
+	}
+	return nil
 }
 
 type gatewayFocusedPageSender struct {
@@ -384,17 +494,18 @@ func newHandlerWithServices(admin adminDataSource, sender messageSender, authSvc
 
 func newHandlerWithRetrievalService(admin adminDataSource, sender messageSender, retrievalService *retrieval.Service, authSvc authService, jwtSecret string, accessTokenTTL time.Duration) http.Handler {
 	joinSource, _ := admin.(joinClassSource)
-	return newHandlerWithAdminProvider(fixedAdminDataSourceProvider{source: admin}, joinSource, sender, retrievalService, authSvc, jwtSecret, accessTokenTTL, "", nil, nil, false)
+	return newHandlerWithAdminProvider(fixedAdminDataSourceProvider{source: admin}, joinSource, sender, retrievalService, authSvc, auth.NewSigningSecret(jwtSecret), accessTokenTTL, "", nil, nil, false, nil, nil, nil)
 }
 
 // settingsStore and applySettings back the admin runtime-settings endpoints:
 // handlers save through the store, which runs applySettings to rewire the
 // live AI router. A nil settingsStore leaves the /api/admin/ai/settings routes
 // unregistered (tests, unwired deployments). multiTenant restricts those
-// routes to platform admins: the settings row is platform-global.
-func newHandlerWithAdminProvider(adminProvider adminDataSourceProvider, joinSource joinClassSource, sender messageSender, retrievalService *retrieval.Service, authSvc authService, jwtSecret string, accessTokenTTL time.Duration, inviteBaseURL string, settingsStore runtimeSettingsStore, applySettings func(settings.Settings), multiTenant bool) http.Handler {
+// routes to platform admins: the settings row is platform-global. A nil
+// conversations leaves engine-backed conversation maintenance routes unregistered.
+func newHandlerWithAdminProvider(adminProvider adminDataSourceProvider, joinSource joinClassSource, sender messageSender, retrievalService *retrieval.Service, authSvc authService, jwtSecret *auth.SigningSecret, accessTokenTTL time.Duration, inviteBaseURL string, settingsStore runtimeSettingsStore, applySettings func(settings.Settings), multiTenant bool, conversations conversationAdmin, teachingNotes teachingNoteCurriculum, templates messageTemplateOverrides) http.Handler {
 	mux := newMux(nil, sender)
-	manager := auth.NewTokenManager(jwtSecret, accessTokenTTL)
+	manager := auth.NewRotatingTokenManager(jwtSecret, accessTokenTTL)
 	authenticated := authenticateRequests(authSvc, manager, time.Now)
 	retrievalService = ensureRetrievalService(retrievalService)
 
@@ -444,6 +555,19 @@ func newHandlerWithAdminProvider(adminProvider adminDataSourceProvider, joinSour
 	mux.Handle("GET /api/admin/classes/{id}/progress", teacherOrAbove(handleAdminClassProgress(adminProvider)))
 	mux.Handle("GET /api/admin/students/{id}", teacherOrAbove(handleAdminStudentDetail(adminProvider)))
 	mux.Handle("GET /api/admin/students/{id}/conversations", teacherOrAbove(handleAdminStudentConversations(adminProvider)))
+	mux.Handle("GET /api/admin/conversations/{id}/transcript", teacherOrAbove(handleAdminConversationTranscript(adminProvider)))
+	mux.Handle("GET /api/admin/messages/search", teacherOrAbove(handleAdminMessageSearch(adminProvider)))
+	mux.Handle("GET /api/admin/deliveries", teacherOrAbove(handleAdminDeliveries(adminProvider)))
+	if conversations != nil {
+		mux.Handle("POST /api/admin/conversations/{id}/resummarize", adminOrAbove(handleAdminConversationResummarize(adminProvider, conversations)))
+	}
+	// Sandbox learners live in the engine's tenant, so like AI settings they
+	// are platform-admin only when there are several tenants.
+	if sandbox, ok := conversations.(sandboxAdmin); ok {
+		sandboxAdmins := chain(authenticated, auth.RequireRoles(settingsRoles...))
+		mux.Handle("POST /api/admin/sandbox", sandboxAdmins(handleAdminStartSandbox(sandbox)))
+		mux.Handle("POST /api/admin/sandbox/{id}/messages", sandboxAdmins(handleAdminSandboxMessage(sandbox)))
+	}
 	mux.Handle("POST /api/admin/students/{id}/nudge", teacherOrAbove(handleAdminStudentNudge(adminProvider, sender)))
 	mux.Handle("GET /api/admin/metrics", teacherOrAbove(handleAdminMetrics(adminProvider)))
 	mux.Handle("GET /api/admin/ai/usage", teacherOrAbove(handleAdminAIUsage(adminProvider)))
@@ -457,6 +581,11 @@ func newHandlerWithAdminProvider(adminProvider adminDataSourceProvider, joinSour
 	mux.Handle("GET /api/admin/export/students", adminOrAbove(handleAdminExportStudents(adminProvider)))
 	mux.Handle("GET /api/admin/export/conversations", adminOrAbove(handleAdminExportConversations(adminProvider)))
 	mux.Handle("GET /api/admin/export/progress", adminOrAbove(handleAdminExportProgress(adminProvider)))
+	mux.Handle("GET /api/admin/students/{id}/learning-state", adminOrAbove(handleAdminExportLearningState(adminProvider)))
+	mux.Handle("POST /api/admin/learning-state/import", adminOrAbove(handleAdminImportLearningState(adminProvider)))
+	settingsApplier, _ := conversations.(tenantSettingsApplier)
+	mux.Handle("GET /api/admin/tenant-settings", adminOrAbove(handleAdminGetTenantSettings(adminProvider)))
+	mux.Handle("PUT /api/admin/tenant-settings", adminOrAbove(handleAdminUpdateTenantSettings(adminProvider, settingsApplier)))
 	mux.Handle("GET /api/admin/parents/{id}", parentOrAbove(handleAdminParentSummary(adminProvider)))
 	// Group CRUD
 	mux.Handle("GET /api/admin/groups", teacherOrAbove(handleAdminListGroups(adminProvider)))
@@ -467,6 +596,28 @@ func newHandlerWithAdminProvider(adminProvider adminDataSourceProvider, joinSour
 	mux.Handle("POST /api/admin/groups/{id}/members", adminOrAbove(handleAdminAddGroupMember(adminProvider)))
 	mux.Handle("DELETE /api/admin/groups/{id}/members/{uid}", adminOrAbove(handleAdminRemoveGroupMember(adminProvider)))
 	mux.Handle("GET /api/admin/groups/{id}/leaderboard", teacherOrAbove(handleAdminGroupLeaderboard(adminProvider)))
+	// School teaching notes layered over the OSS curriculum
+	mux.Handle("GET /api/admin/teaching-notes", teacherOrAbove(handleAdminListTeachingNotes(adminProvider)))
+	mux.Handle("GET /api/admin/teaching-notes/{topicID}", teacherOrAbove(handleAdminTeachingNoteHistory(adminProvider)))
+	if teachingNotes != nil {
+		mux.Handle("PUT /api/admin/teaching-notes/{topicID}", teacherOrAbove(handleAdminSaveTeachingNote(adminProvider, teachingNotes)))
+	}
+	// Tenant copy for recurring outbound messages
+	mux.Handle("GET /api/admin/message-templates", adminOrAbove(handleAdminListMessageTemplates(adminProvider)))
+	if templates != nil {
+		mux.Handle("PUT /api/admin/message-templates/{key}/{locale}", adminOrAbove(handleAdminSaveMessageTemplate(adminProvider, templates)))
+		mux.Handle("DELETE /api/admin/message-templates/{key}/{locale}", adminOrAbove(handleAdminDeleteMessageTemplate(adminProvider, templates)))
+	}
+	// Problem of the day, curated per form
+	mux.Handle("GET /api/admin/daily-problems", teacherOrAbove(handleAdminListDailyProblems(adminProvider)))
+	mux.Handle("PUT /api/admin/daily-problems/{form}/{day}", teacherOrAbove(handleAdminSaveDailyProblem(adminProvider)))
+	mux.Handle("GET /api/admin/reviews", adminOrAbove(handleAdminListConversationReviews(adminProvider)))
+	mux.Handle("GET /api/admin/reviews/export", adminOrAbove(handleAdminExportReviewEvals(adminProvider)))
+	mux.Handle("POST /api/admin/reviews/{id}", adminOrAbove(handleAdminRateConversationReview(adminProvider)))
+	// Read-only tokens for the public usage API
+	mux.Handle("GET /api/admin/api-tokens", adminOrAbove(handleAdminListAPITokens(adminProvider)))
+	mux.Handle("POST /api/admin/api-tokens", adminOrAbove(handleAdminCreateAPIToken(adminProvider)))
+	mux.Handle("DELETE /api/admin/api-tokens/{id}", adminOrAbove(handleAdminRevokeAPIToken(adminProvider)))
 	registerRetrievalRoutes(mux, retrievalService, teacherOrAbove, adminOrAbove)
 
 	apiLimiter := newFixedWindowLimiter(defaultAPIRateLimitPerMinute, time.Minute)
@@ -603,6 +754,40 @@ func handleReadyz(w http.ResponseWriter, r *http.Request) {
 	_, _ = w.Write([]byte(`{"status":"ready"}`))
 }
 
+// readyzChannel is the public slice of chat.ChannelHealth; error text stays
+// on the admin endpoint.
+type readyzChannel struct {
+	Channel         string     `json:"channel"`
+	Healthy         bool       `json:"healthy"`
+	LastReceivedAt  *time.Time `json:"last_received_at,omitempty"`
+	LastSentAt      *time.Time `json:"last_sent_at,omitempty"`
+	WebhookVerified *bool      `json:"webhook_verified,omitempty"`
+	LoopFailures    int        `json:"loop_failures"`
+	Restarts        int        `json:"restarts"`
+}
+
+// handleReadyzWithChannels stays 200 while a channel is unhealthy: the
+// supervisor restarts channels in place, and pulling the instance from the
+// load balancer would only cut off the healthy ones.
+func handleReadyzWithChannels(gw *chat.Gateway) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		health := gw.ChannelHealth()
+		channels := make([]readyzChannel, 0, len(health))
+		for _, h := range health {
+			channels = append(channels, readyzChannel{
+				Channel:         h.Channel,
+				Healthy:         h.Healthy,
+				LastReceivedAt:  h.LastReceivedAt,
+				LastSentAt:      h.LastSentAt,
+				WebhookVerified: h.WebhookVerified,
+				LoopFailures:    h.LoopFailures,
+				Restarts:        h.Restarts,
+			})
+		}
+		writeJSON(w, http.StatusOK, map[string]any{"status": "ready", "channels": channels})
+	})
+}
+
 func handleOpenAPI(w http.ResponseWriter, r *http.Request) {
 	data, err := apidocs.JSON()
 	if err != nil {
@@ -678,6 +863,103 @@ func handleAdminStudentConversations(adminProvider adminDataSourceProvider) http
 	}
 }
 
+func handleAdminConversationTranscript(adminProvider adminDataSourceProvider) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		admin, ok := resolveAdminDataSource(w, r, adminProvider)
+		if !ok {
+			return
+		}
+
+		payload, err := admin.GetConversationTranscript(r.PathValue("id"))
+		if err != nil {
+			writeAdminError(w, err)
+			return
+		}
+		writeJSON(w, http.StatusOK, payload)
+	}
+}
+
+func handleAdminMessageSearch(adminProvider adminDataSourceProvider) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		admin, ok := resolveAdminDataSource(w, r, adminProvider)
+		if !ok {
+			return
+		}
+
+		query := r.URL.Query()
+		limit := 0
+		if raw := strings.TrimSpace(query.Get("limit")); raw != "" {
+			parsed, err := strconv.Atoi(raw)
+			if err != nil || parsed < 1 {
+				http.Error(w, "limit must be a positive integer", http.StatusBadRequest)
+				return
+			}
+			limit = parsed
+		}
+		payload, err := admin.SearchMessages(query.Get("q"), query.Get("student_id"), limit)
+		if err != nil {
+			writeAdminError(w, err)
+			return
+		}
+		writeJSON(w, http.StatusOK, payload)
+	}
+}
+
+func handleAdminDeliveries(adminProvider adminDataSourceProvider) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		admin, ok := resolveAdminDataSource(w, r, adminProvider)
+		if !ok {
+			return
+		}
+
+		query := r.URL.Query()
+		limit := 0
+		if raw := strings.TrimSpace(query.Get("limit")); raw != "" {
+			parsed, err := strconv.Atoi(raw)
+			if err != nil || parsed < 1 {
+				http.Error(w, "limit must be a positive integer", http.StatusBadRequest)
+				return
+			}
+			limit = parsed
+		}
+		payload, err := admin.GetDeliveryReport(query.Get("status"), limit)
+		if err != nil {
+			writeAdminError(w, err)
+			return
+		}
+		writeJSON(w, http.StatusOK, payload)
+	}
+}
+
+func handleAdminConversationResummarize(adminProvider adminDataSourceProvider, conversations conversationAdmin) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		admin, ok := resolveAdminDataSource(w, r, adminProvider)
+		if !ok {
+			return
+		}
+
+		conversationID := r.PathValue("id")
+		if _, err := admin.GetConversationTranscript(conversationID); err != nil {
+			writeAdminError(w, err)
+			return
+		}
+		summary, err := conversations.Resummarize(r.Context(), conversationID)
+		if err != nil {
+			if errors.Is(err, agent.ErrNothingToSummarize) {
+				http.Error(w, err.Error(), http.StatusConflict)
+				return
+			}
+			slog.Warn("admin resummarize failed", "conversation_id", conversationID, "error", err)
+			http.Error(w, "summary could not be regenerated", http.StatusBadGateway)
+			return
+		}
+		writeJSON(w, http.StatusOK, map[string]string{
+			"conversation_id": conversationID,
+			"summary":         summary,
+		})
+	}
+}
+
 func handleAdminParentSummary(adminProvider adminDataSourceProvider) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
 		claims, ok := auth.ClaimsFromContext(r.Context())
@@ -7,6 +7,7 @@ import (
 	"context"
 	"encoding/json"
 	"errors"
+	"fmt"
 	"net/http"
 	"net/http/httptest"
 	"strings"
@@ -16,6 +17,9 @@ import (
 	"github.com/p-n-ai/pai-bot/internal/adminapi"
 	"github.com/p-n-ai/pai-bot/internal/agent"
 	"github.com/p-n-ai/pai-bot/internal/auth"
+	"github.com/p-n-ai/pai-bot/internal/chat"
+	"github.com/p-n-ai/pai-bot/internal/curriculum"
+	"github.com/p-n-ai/pai-bot/internal/i18n"
 	"github.com/p-n-ai/pai-bot/internal/retrieval"
 )
 
@@ -59,6 +63,35 @@ func TestHealthEndpoints(t *testing.T) {
 	}
 }
 
+func TestTopMuxReadyzReportsChannelHealthWithoutErrors(t *testing.T) {
+	gw := chat.NewGateway()
+	gw.Register("telegram", &chat.MockChannel{})
+	_ = gw.Send(context.Background(), chat.OutboundMessage{Channel: "telegram", UserID: "42", Text: "hi"})
+	handler := NewTopMux(TopMuxOptions{APIHandler: http.NotFoundHandler(), Gateway: gw})
+
+	recorder := httptest.NewRecorder()
+	handler.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/readyz", nil))
+	if recorder.Code != http.StatusOK {
+		t.Fatalf("status = %d", recorder.Code)
+	}
+	var body struct {
+		Status   string           `json:"status"`
+		Channels []map[string]any `json:"channels"`
+	}
+	if err := json.Unmarshal(recorder.Body.Bytes(), &body); err != nil {
+		t.Fatal(err)
+	}
+	if body.Status != "ready" || len(body.Channels) != 1 || body.Channels[0]["channel"] != "telegram" || body.Channels[0]["healthy"] != true {
+		t.Fatalf("readyz = %s", recorder.Body.String())
+	}
+	if _, ok := body.Channels[0]["last_sent_at"]; !ok {
+		t.Fatalf("readyz = %s, want last_sent_at", recorder.Body.String())
+	}
+	if _, ok := body.Channels[0]["last_send_error"]; ok {
+		t.Fatalf("readyz = %s, want error text kept to the admin endpoint", recorder.Body.String())
+	}
+}
+
 func TestAPIDocumentationEndpoints(t *testing.T) {
 	mux := newMux(stubAdminAPI{}, &chatGatewayStub{})
 
@@ -215,6 +248,157 @@ func TestAdminStudentConversationsEndpoint(t *testing.T) {
 	}
 }
 
+func TestAdminConversationTranscriptEndpoint(t *testing.T) {
+	req := httptest.NewRequest(http.MethodGet, "/api/admin/conversations/conv-1/transcript", nil)
+	req.Header.Set("Authorization", "Bearer "+mustIssueAdminToken(t))
+	rec := httptest.NewRecorder()
+
+	newHandler(stubAdminAPI{}, &chatGatewayStub{}).ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status = %d, want %d", rec.Code, http.StatusOK)
+	}
+	var payload struct {
+		ConversationID string `json:"conversation_id"`
+		Entries        []struct {
+			Kind      string         `json:"kind"`
+			EventType string         `json:"event_type"`
+			Data      map[string]any `json:"data"`
+		} `json:"entries"`
+	}
+	if err := json.Unmarshal(rec.Body.Bytes(), &payload); err != nil {
+		t.Fatalf("json.Unmarshal() error = %v", err)
+	}
+	if payload.ConversationID != "conv-1" {
+		t.Fatalf("conversation_id = %q, want conv-1", payload.ConversationID)
+	}
+	if len(payload.Entries) != 2 || payload.Entries[1].Kind != "event" || payload.Entries[1].Data["topic_id"] != "F1-02" {
+		t.Fatalf("entries = %#v, want message then annotated event", payload.Entries)
+	}
+}
+
+func TestAdminConversationTranscriptEndpointNotFound(t *testing.T) {
+	req := httptest.NewRequest(http.MethodGet, "/api/admin/conversations/missing/transcript", nil)
+	req.Header.Set("Authorization", "Bearer "+mustIssueAdminToken(t))
+	rec := httptest.NewRecorder()
+
+	newHandler(stubAdminAPI{}, &chatGatewayStub{}).ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusNotFound {
+		t.Fatalf("status = %d, want %d", rec.Code, http.StatusNotFound)
+	}
+}
+
+func TestAdminMessageSearchEndpoint(t *testing.T) {
+	req := httptest.NewRequest(http.MethodGet, "/api/admin/messages/search?q=pecahan&student_id=stu_2&limit=5", nil)
+	req.Header.Set("Authorization", "Bearer "+mustIssueAdminToken(t))
+	rec := httptest.NewRecorder()
+
+	newHandler(stubAdminAPI{}, &chatGatewayStub{}).ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status = %d, want %d", rec.Code, http.StatusOK)
+	}
+	var hits []adminapi.MessageSearchHit
+	if err := json.Unmarshal(rec.Body.Bytes(), &hits); err != nil {
+		t.Fatalf("json.Unmarshal() error = %v", err)
+	}
+	if len(hits) != 1 || hits[0].StudentID != "stu_2" || hits[0].Text != "pecahan (limit 5)" {
+		t.Fatalf("hits = %#v, want stubbed hit with forwarded filters", hits)
+	}
+}
+
+func TestAdminMessageSearchEndpointRejectsBadInput(t *testing.T) {
+	for _, path := range []string{
+		"/api/admin/messages/search?q=",
+		"/api/admin/messages/search?q=pecahan&limit=zero",
+	} {
+		req := httptest.NewRequest(http.MethodGet, path, nil)
+		req.Header.Set("Authorization", "Bearer "+mustIssueAdminToken(t))
+		rec := httptest.NewRecorder()
+
+		newHandler(stubAdminAPI{}, &chatGatewayStub{}).ServeHTTP(rec, req)
+
+		if rec.Code != http.StatusBadRequest {
+			t.Fatalf("%s status = %d, want %d", path, rec.Code, http.StatusBadRequest)
+		}
+	}
+}
+
+func TestAdminDeliveriesEndpoint(t *testing.T) {
+	req := httptest.NewRequest(http.MethodGet, "/api/admin/deliveries?status=blocked&limit=5", nil)
+	req.Header.Set("Authorization", "Bearer "+mustIssueAdminToken(t))
+	rec := httptest.NewRecorder()
+
+	newHandler(stubAdminAPI{}, &chatGatewayStub{}).ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status = %d, want %d", rec.Code, http.StatusOK)
+	}
+	var report adminapi.DeliveryReport
+	if err := json.Unmarshal(rec.Body.Bytes(), &report); err != nil {
+		t.Fatalf("json.Unmarshal() error = %v", err)
+	}
+	if report.BlockedUsers != 2 || len(report.Recent) != 1 || report.Recent[0].Error != "limit 5" {
+		t.Fatalf("report = %#v, want stubbed report with forwarded limit", report)
+	}
+
+	req = httptest.NewRequest(http.MethodGet, "/api/admin/deliveries?status=bounced", nil)
+	req.Header.Set("Authorization", "Bearer "+mustIssueAdminToken(t))
+	rec = httptest.NewRecorder()
+	newHandler(stubAdminAPI{}, &chatGatewayStub{}).ServeHTTP(rec, req)
+	if rec.Code != http.StatusBadRequest {
+		t.Fatalf("unknown status = %d, want %d", rec.Code, http.StatusBadRequest)
+	}
+}
+
+type stubConversationAdmin struct {
+	summary string
+	err     error
+	calls   []string
+}
+
+func (s *stubConversationAdmin) Resummarize(_ context.Context, conversationID string) (string, error) {
+	s.calls = append(s.calls, conversationID)
+	return s.summary, s.err
+}
+
+func TestAdminConversationResummarizeEndpoint(t *testing.T) {
+	tests := []struct {
+		name      string
+		path      string
+		err       error
+		wantCode  int
+		wantCalls int
+	}{
+		{name: "regenerates summary", path: "/api/admin/conversations/conv-1/resummarize", wantCode: http.StatusOK, wantCalls: 1},
+		{name: "unknown conversation", path: "/api/admin/conversations/missing/resummarize", wantCode: http.StatusNotFound},
+		{name: "nothing to summarize", path: "/api/admin/conversations/conv-1/resummarize", err: agent.ErrNothingToSummarize, wantCode: http.StatusConflict, wantCalls: 1},
+		{name: "summary rejected", path: "/api/admin/conversations/conv-1/resummarize", err: errors.New("summary failed validation"), wantCode: http.StatusBadGateway, wantCalls: 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			conversations := &stubConversationAdmin{summary: "The student worked through linear equations.", err: tt.err}
+			handler := newHandlerWithAdminProvider(fixedAdminDataSourceProvider{source: stubAdminAPI{}}, nil, &chatGatewayStub{}, retrieval.NewMemoryService(), &stubAuthService{}, auth.NewSigningSecret("change-me-in-production"), time.Hour, "", nil, nil, false, conversations, nil, nil)
+
+			req := httptest.NewRequest(http.MethodPost, tt.path, nil)
+			req.Header.Set("Authorization", "Bearer "+mustIssueAdminToken(t))
+			rec := httptest.NewRecorder()
+			handler.ServeHTTP(rec, req)
+
+			if rec.Code != tt.wantCode {
+				t.Fatalf("status = %d, want %d (body %q)", rec.Code, tt.wantCode, rec.Body.String())
+			}
+			if len(conversations.calls) != tt.wantCalls {
+				t.Fatalf("Resummarize calls = %v, want %d", conversations.calls, tt.wantCalls)
+			}
+			if tt.wantCode == http.StatusOK && !strings.Contains(rec.Body.String(), "linear equations") {
+				t.Fatalf("body = %q, want regenerated summary", rec.Body.String())
+			}
+		})
+	}
+}
+
 func TestAdminParentSummaryEndpoint(t *testing.T) {
 	req := httptest.NewRequest(http.MethodGet, "/api/admin/parents/parent-1", nil)
 	req.Header.Set("Authorization", "Bearer "+mustIssueParentToken(t))
@@ -591,6 +775,10 @@ func TestAdminAnalyticsReportEndpoint(t *testing.T) {
 			AverageDAU          float64 `json:"average_dau"`
 			LatestDAU           int     `json:"latest_dau"`
 		} `json:"overview"`
+		Churn struct {
+			ChurnedUsers int `json:"churned_users"`
+			BlockedUsers int `json:"blocked_users"`
+		} `json:"churn"`
 	}
 	if err := json.Unmarshal(rec.Body.Bytes(), &payload); err != nil {
 		t.Fatalf("json.Unmarshal() error = %v", err)
@@ -607,6 +795,9 @@ func TestAdminAnalyticsReportEndpoint(t *testing.T) {
 	if payload.Overview.AverageDAU <= 0 {
 		t.Fatalf("average_dau = %v, want positive value", payload.Overview.AverageDAU)
 	}
+	if payload.Churn.ChurnedUsers != 3 || payload.Churn.BlockedUsers != 2 {
+		t.Fatalf("churn = %+v, want 3 churned and 2 blocked", payload.Churn)
+	}
 }
 
 func TestAdminAnalyticsReportEndpointRejectsTeacherRole(t *testing.T) {
@@ -841,7 +1032,7 @@ func TestAuthSessionPayloadAISettingsCapability(t *testing.T) {
 				ExpiresAt: time.Date(2026, 3, 23, 10, 0, 0, 0, time.UTC),
 				User:      auth.UserSession{UserID: "user-1", TenantID: "tenant-abc", Role: tc.role},
 			}}
-			handler := newHandlerWithAdminProvider(fixedAdminDataSourceProvider{source: stubAdminAPI{}}, nil, &chatGatewayStub{}, retrieval.NewMemoryService(), authSvc, "change-me-in-production", time.Hour, "", &memorySettingsStore{}, nil, tc.multiTenant)
+			handler := newHandlerWithAdminProvider(fixedAdminDataSourceProvider{source: stubAdminAPI{}}, nil, &chatGatewayStub{}, retrieval.NewMemoryService(), authSvc, auth.NewSigningSecret("change-me-in-production"), time.Hour, "", &memorySettingsStore{}, nil, tc.multiTenant, nil, nil, nil)
 
 			req := httptest.NewRequest(http.MethodGet, "/api/auth/session", nil)
 			req.AddCookie(&http.Cookie{Name: auth.SessionCookieName, Value: "session-old"})
@@ -1602,6 +1793,58 @@ func (stubAdminAPI) GetStudentConversations(studentID string) ([]adminapi.Studen
 	}, nil
 }
 
+func (stubAdminAPI) SearchMessages(query, studentID string, limit int) ([]adminapi.MessageSearchHit, error) {
+	if strings.TrimSpace(query) == "" {
+		return nil, fmt.Errorf("%w: search query is required", adminapi.ErrInvalidArgument)
+	}
+	return []adminapi.MessageSearchHit{{
+		ConversationID: "conv-1",
+		MessageID:      "msg_1",
+		StudentID:      studentID,
+		Role:           "student",
+		Text:           fmt.Sprintf("%s (limit %d)", query, limit),
+		Timestamp:      time.Date(2026, 3, 9, 11, 20, 0, 0, time.UTC),
+	}}, nil
+}
+
+func (stubAdminAPI) GetDeliveryReport(status string, limit int) (adminapi.DeliveryReport, error) {
+	if status == "bounced" {
+		return adminapi.DeliveryReport{}, fmt.Errorf("%w: unknown delivery status", adminapi.ErrInvalidArgument)
+	}
+	return adminapi.DeliveryReport{
+		WindowDays:   7,
+		Counts:       map[string]int{"sent": 40, "blocked": 2},
+		BlockedUsers: 2,
+		Recent: []adminapi.DeliveryAttempt{{
+			Channel:   "telegram",
+			StudentID: "stu_2",
+			Status:    "blocked",
+			Error:     fmt.Sprintf("limit %d", limit),
+			Timestamp: time.Date(2026, 3, 9, 11, 20, 0, 0, time.UTC),
+		}},
+	}, nil
+}
+
+func (stubAdminAPI) GetConversationTranscript(conversationID string) (adminapi.ConversationTranscript, error) {
+	if conversationID == "missing" {
+		return adminapi.ConversationTranscript{}, adminapi.ErrNotFound
+	}
+	startedAt := time.Date(2026, 3, 9, 11, 20, 0, 0, time.UTC)
+	return adminapi.ConversationTranscript{
+		ConversationID: conversationID,
+		StudentID:      "stu_2",
+		StudentName:    "Aisyah",
+		Channel:        "telegram",
+		TopicID:        "F1-02",
+		State:          "teaching",
+		StartedAt:      startedAt,
+		Entries: []adminapi.TranscriptEntry{
+			{Kind: adminapi.TranscriptEntryMessage, ID: "msg_1", Timestamp: startedAt, Role: "student", Text: "Question"},
+			{Kind: adminapi.TranscriptEntryEvent, ID: "evt_1", Timestamp: startedAt, EventType: "agent_turn_completed", Data: map[string]any{"topic_id": "F1-02"}},
+		},
+	}, nil
+}
+
 func (stubAdminAPI) GetParentSummary(parentID string) (adminapi.ParentSummary, error) {
 	if parentID == "missing" {
 		return adminapi.ParentSummary{}, adminapi.ErrNotFound
@@ -1724,6 +1967,11 @@ func (stubAdminAPI) GetAnalyticsReport() (adminapi.AnalyticsReport, error) {
 			ResponsesWithin24Hours: 18,
 			ResponseRate:           0.36,
 		},
+		Churn: adminapi.ChurnSummary{
+			ChurnedUsers:     3,
+			ReactivatedUsers: 1,
+			BlockedUsers:     2,
+		},
 		AIUsage: adminapi.AIUsageSummary{
 			TotalMessages:     1820,
 			TotalInputTokens:  128000,
@@ -1909,6 +2157,115 @@ func (stubAdminAPI) GetGroupLeaderboard(_ string) ([]adminapi.AdminLeaderboardEn
 	return []adminapi.AdminLeaderboardEntry{}, nil
 }
 
+func (stubAdminAPI) ListTeachingNotes() ([]adminapi.TeachingNote, error) {
+	return []adminapi.TeachingNote{}, nil
+}
+
+func (stubAdminAPI) GetTeachingNoteHistory(_ string) ([]adminapi.TeachingNote, error) {
+	return nil, adminapi.ErrNotFound
+}
+
+func (stubAdminAPI) ListMessageTemplates() (adminapi.MessageTemplateView, error) {
+	return adminapi.MessageTemplateView{Keys: []adminapi.MessageTemplateKey{}, Templates: []adminapi.MessageTemplate{}}, nil
+}
+
+func (stubAdminAPI) SaveMessageTemplate(key, locale, body, updatedByUserID string) (adminapi.MessageTemplate, error) {
+	if err := i18n.ValidateTemplate(i18n.Key(key), locale, body); err != nil {
+		return adminapi.MessageTemplate{}, fmt.Errorf("%w: %v", adminapi.ErrInvalidArgument, err)
+	}
+	return adminapi.MessageTemplate{TenantID: "tenant-abc", Key: key, Locale: locale, Body: body, UpdatedBy: updatedByUserID}, nil
+}
+
+func (stubAdminAPI) DeleteMessageTemplate(key, locale string) (adminapi.MessageTemplate, error) {
+	return adminapi.MessageTemplate{TenantID: "tenant-abc", Key: key, Locale: locale}, nil
+}
+
+func (stubAdminAPI) ListDailyProblems() ([]adminapi.DailyProblem, error) {
+	return []adminapi.DailyProblem{}, nil
+}
+
+func (stubAdminAPI) SaveDailyProblem(form, day, topicID string, question adminapi.DailyProblemQuestion) (adminapi.DailyProblem, error) {
+	if question.Text == "" || question.Answer == "" {
+		return adminapi.DailyProblem{}, fmt.Errorf("%w: question text and answer are required", adminapi.ErrInvalidArgument)
+	}
+	return adminapi.DailyProblem{TenantID: "tenant-abc", Form: form, Day: day, TopicID: topicID, Question: question, Source: "curated"}, nil
+}
+
+func (stubAdminAPI) SaveTeachingNote(topicID string, overlay curriculum.TeachingNoteOverlay, createdByUserID string) (adminapi.TeachingNote, error) {
+	return adminapi.TeachingNote{TenantID: "tenant-abc", TopicID: topicID, Version: 1, Notes: overlay.Notes, Examples: overlay.Examples, CreatedBy: createdByUserID}, nil
+}
+
+func (stubAdminAPI) ListConversationReviews(status string, _ int) ([]adminapi.ConversationReview, error) {
+	if status != "" && status != adminapi.ReviewStatusPending {
+		return nil, fmt.Errorf("%w: status must be pending, reviewed or all", adminapi.ErrInvalidArgument)
+	}
+	return []adminapi.ConversationReview{{ID: "review-1", ConversationID: "conv-1", Reason: "safety"}}, nil
+}
+
+func (stubAdminAPI) RateConversationReview(reviewID string, rating int, comment, reviewerUserID string) (adminapi.ConversationReview, error) {
+	if rating < 1 || rating > 5 {
+		return adminapi.ConversationReview{}, fmt.Errorf("%w: rating must be between 1 and 5", adminapi.ErrInvalidArgument)
+	}
+	return adminapi.ConversationReview{ID: reviewID, Reason: "sampled", Rating: &rating, Comment: comment, ReviewedBy: reviewerUserID}, nil
+}
+
+func (stubAdminAPI) ExportReviewEvalFixture() (adminapi.EvalFixture, error) {
+	return adminapi.EvalFixture{Version: 1, Conversations: []adminapi.EvalConversation{{
+		ID:    "review-1",
+		Title: "Gave away the answer",
+		Tags:  []string{"review", "safety", "rating-2"},
+		Turns: []adminapi.EvalTurn{{User: "Solve 2x = 10"}},
+	}}}, nil
+}
+
+func (stubAdminAPI) ExportLearningState(studentID string) (adminapi.LearningState, error) {
+	if studentID != "student-1" {
+		return adminapi.LearningState{}, adminapi.ErrNotFound
+	}
+	return adminapi.LearningState{
+		Version:  adminapi.LearningStateVersion,
+		Learner:  adminapi.LearningStateLearner{Name: "Aina", ExternalID: studentID, Channel: "telegram"},
+		Progress: []adminapi.LearningStateProgress{{SyllabusID: "kssm-f1", TopicID: "F1-02", MasteryScore: 0.7}},
+	}, nil
+}
+
+func (stubAdminAPI) ImportLearningState(state adminapi.LearningState) (adminapi.LearningStateImportResult, error) {
+	if state.Version != adminapi.LearningStateVersion {
+		return adminapi.LearningStateImportResult{}, fmt.Errorf("%w: unsupported learning state version %d", adminapi.ErrInvalidArgument, state.Version)
+	}
+	return adminapi.LearningStateImportResult{StudentID: state.Learner.ExternalID, Created: true, Topics: len(state.Progress)}, nil
+}
+
+func (stubAdminAPI) GetTenantSettings() (adminapi.TenantSettings, error) {
+	return adminapi.TenantSettings{TenantID: "tenant-1"}, nil
+}
+
+func (stubAdminAPI) UpdateTenantSettings(settings adminapi.TenantSettings) (adminapi.TenantSettings, error) {
+	settings.TenantID = "tenant-1"
+	return settings, nil
+}
+
+func (stubAdminAPI) ListAPITokens() ([]adminapi.APIToken, error) {
+	return []adminapi.APIToken{{ID: "token-1", Name: "District dashboard", Prefix: "pai_abc123"}}, nil
+}
+
+func (stubAdminAPI) CreateAPIToken(name, createdByUserID string) (adminapi.CreatedAPIToken, error) {
+	if strings.TrimSpace(name) == "" {
+		return adminapi.CreatedAPIToken{}, fmt.Errorf("%w: name is required", adminapi.ErrInvalidArgument)
+	}
+	return adminapi.CreatedAPIToken{
+		APIToken: adminapi.APIToken{ID: "token-2", Name: name, Prefix: "pai_def456", CreatedBy: createdByUserID},
+		Token:    "pai_def456secret",
+	}, nil
+}
+
+func (stubAdminAPI) RevokeAPIToken(id string) error {
+	if id != "token-1" {
+		return adminapi.ErrNotFound
+	}
+	return nil
+}
+
 var _ adminDataSource = stubAdminAPI{}
 
 type recordingAdminProvider struct {
@@ -2165,7 +2522,7 @@ func TestAdminEndpointsUseTenantFromClaims(t *testing.T) {
 	req.Header.Set("Authorization", "Bearer "+mustIssueTokenWithTenant(t, auth.RoleTeacher, "teacher-1", "tenant-second"))
 	rec := httptest.NewRecorder()
 
-	newHandlerWithAdminProvider(provider, stubAdminAPI{}, &chatGatewayStub{}, retrieval.NewMemoryService(), &stubAuthService{}, "change-me-in-production", time.Hour, "", nil, nil, false).ServeHTTP(rec, req)
+	newHandlerWithAdminProvider(provider, stubAdminAPI{}, &chatGatewayStub{}, retrieval.NewMemoryService(), &stubAuthService{}, auth.NewSigningSecret("change-me-in-production"), time.Hour, "", nil, nil, false, nil, nil, nil).ServeHTTP(rec, req)
 
 	if rec.Code != http.StatusOK {
 		t.Fatalf("status = %d, want %d", rec.Code, http.StatusOK)
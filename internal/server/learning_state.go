@@ -0,0 +1,55 @@
+// Copyright 2026 the P&AI authors. All rights reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package server
+
+import (
+	"net/http"
+
+	"github.com/p-n-ai/pai-bot/internal/adminapi"
+)
+
+// maxLearningStateBytes caps an import body; years of XP awards fit well
+// inside it.
+const maxLearningStateBytes = 8 << 20
+
+// handleAdminExportLearningState downloads a student's learning state for
+// ImportLearningState in another tenant or deployment.
+func handleAdminExportLearningState(adminProvider adminDataSourceProvider) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		admin, ok := resolveAdminDataSource(w, r, adminProvider)
+		if !ok {
+			return
+		}
+		state, err := admin.ExportLearningState(r.PathValue("id"))
+		if err != nil {
+			writeAdminError(w, err)
+			return
+		}
+		w.Header().Set("Content-Disposition", `attachment; filename="learning-state.json"`)
+		writeJSON(w, http.StatusOK, state)
+	}
+}
+
+// handleAdminImportLearningState writes an exported learning state to the
+// learner with the same channel and external ID in the caller's tenant.
+func handleAdminImportLearningState(adminProvider adminDataSourceProvider) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		admin, ok := resolveAdminDataSource(w, r, adminProvider)
+		if !ok {
+			return
+		}
+		r.Body = http.MaxBytesReader(w, r.Body, maxLearningStateBytes)
+		var state adminapi.LearningState
+		if err := decodeJSONBody(r, &state); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		result, err := admin.ImportLearningState(state)
+		if err != nil {
+			writeAdminError(w, err)
+			return
+		}
+		writeJSON(w, http.StatusOK, result)
+	}
+}
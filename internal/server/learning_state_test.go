@@ -0,0 +1,92 @@
+// Copyright 2026 the P&AI authors. All rights reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package server
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/p-n-ai/pai-bot/internal/auth"
+	"github.com/p-n-ai/pai-bot/internal/retrieval"
+)
+
+func TestAdminLearningStateEndpoints(t *testing.T) {
+	tests := []struct {
+		name     string
+		method   string
+		path     string
+		body     string
+		token    func(*testing.T) string
+		wantCode int
+		wantBody string
+	}{
+		{
+			name:     "admin exports a student",
+			method:   http.MethodGet,
+			path:     "/api/admin/students/student-1/learning-state",
+			token:    mustIssueAdminToken,
+			wantCode: http.StatusOK,
+			wantBody: `"topic_id":"F1-02"`,
+		},
+		{
+			name:     "unknown student",
+			method:   http.MethodGet,
+			path:     "/api/admin/students/missing/learning-state",
+			token:    mustIssueAdminToken,
+			wantCode: http.StatusNotFound,
+		},
+		{
+			name:     "teachers cannot export",
+			method:   http.MethodGet,
+			path:     "/api/admin/students/student-1/learning-state",
+			token:    mustIssueTeacherToken,
+			wantCode: http.StatusForbidden,
+		},
+		{
+			name:     "admin imports a state",
+			method:   http.MethodPost,
+			path:     "/api/admin/learning-state/import",
+			body:     `{"version":1,"learner":{"external_id":"12345","channel":"telegram"},"progress":[{"syllabus_id":"kssm-f1","topic_id":"F1-02","mastery_score":0.7}]}`,
+			token:    mustIssueAdminToken,
+			wantCode: http.StatusOK,
+			wantBody: `"topics":1`,
+		},
+		{
+			name:     "unsupported version",
+			method:   http.MethodPost,
+			path:     "/api/admin/learning-state/import",
+			body:     `{"version":9,"learner":{"external_id":"12345","channel":"telegram"}}`,
+			token:    mustIssueAdminToken,
+			wantCode: http.StatusBadRequest,
+		},
+		{
+			name:     "invalid json",
+			method:   http.MethodPost,
+			path:     "/api/admin/learning-state/import",
+			body:     `{"version":`,
+			token:    mustIssueAdminToken,
+			wantCode: http.StatusBadRequest,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			handler := newHandlerWithAdminProvider(fixedAdminDataSourceProvider{source: stubAdminAPI{}}, nil, &chatGatewayStub{}, retrieval.NewMemoryService(), &stubAuthService{}, auth.NewSigningSecret("change-me-in-production"), time.Hour, "", nil, nil, false, nil, nil, nil)
+
+			req := httptest.NewRequest(tt.method, tt.path, strings.NewReader(tt.body))
+			req.Header.Set("Authorization", "Bearer "+tt.token(t))
+			rec := httptest.NewRecorder()
+			handler.ServeHTTP(rec, req)
+
+			if rec.Code != tt.wantCode {
+				t.Fatalf("status = %d, want %d (body %q)", rec.Code, tt.wantCode, rec.Body.String())
+			}
+			if tt.wantBody != "" && !strings.Contains(rec.Body.String(), tt.wantBody) {
+				t.Fatalf("body = %q, want it to contain %q", rec.Body.String(), tt.wantBody)
+			}
+		})
+	}
+}
@@ -0,0 +1,119 @@
+// Copyright 2026 the P&AI authors. All rights reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package server
+
+import (
+	"net/http"
+
+	"github.com/p-n-ai/pai-bot/internal/adminapi"
+	"github.com/p-n-ai/pai-bot/internal/auth"
+	"github.com/p-n-ai/pai-bot/internal/i18n"
+)
+
+// MessageTemplateOverrides is the exported seam cmd/server wires to the
+// handler; see messageTemplateOverrides.
+type MessageTemplateOverrides = messageTemplateOverrides
+
+// messageTemplateOverrides hands saved and removed message templates to the
+// running bot.
+type messageTemplateOverrides interface {
+	ApplyMessageTemplate(tmpl adminapi.MessageTemplate)
+	RemoveMessageTemplate(tmpl adminapi.MessageTemplate)
+}
+
+// tenantMessageTemplates keeps the engine's overrides in step with the
+// tenant's saved templates. The engine serves one tenant, so templates saved
+// for any other tenant are stored but not applied.
+type tenantMessageTemplates struct {
+	overrides *i18n.Overrides
+	tenantID  string
+}
+
+// NewTenantMessageTemplates applies the saved templates for tenantID to
+// overrides and keeps them current as admins edit.
+func NewTenantMessageTemplates(overrides *i18n.Overrides, tenantID string, saved []adminapi.MessageTemplate) MessageTemplateOverrides {
+	templates := tenantMessageTemplates{overrides: overrides, tenantID: tenantID}
+	for _, tmpl := range saved {
+		templates.ApplyMessageTemplate(tmpl)
+	}
+	return templates
+}
+
+func (t tenantMessageTemplates) ApplyMessageTemplate(tmpl adminapi.MessageTemplate) {
+	if tmpl.TenantID != t.tenantID {
+		return
+	}
+	t.overrides.Set(i18n.Key(tmpl.Key), tmpl.Locale, tmpl.Body)
+}
+
+func (t tenantMessageTemplates) RemoveMessageTemplate(tmpl adminapi.MessageTemplate) {
+	if tmpl.TenantID != t.tenantID {
+		return
+	}
+	t.overrides.Delete(i18n.Key(tmpl.Key), tmpl.Locale)
+}
+
+func handleAdminListMessageTemplates(adminProvider adminDataSourceProvider) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		admin, ok := resolveAdminDataSource(w, r, adminProvider)
+		if !ok {
+			return
+		}
+		view, err := admin.ListMessageTemplates()
+		if err != nil {
+			writeAdminError(w, err)
+			return
+		}
+		writeJSON(w, http.StatusOK, view)
+	}
+}
+
+type saveMessageTemplateRequest struct {
+	Body string `json:"body"`
+}
+
+// handleAdminSaveMessageTemplate stores the tenant's copy of a message in one
+// locale and makes it live without a redeploy.
+func handleAdminSaveMessageTemplate(adminProvider adminDataSourceProvider, templates messageTemplateOverrides) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		admin, ok := resolveAdminDataSource(w, r, adminProvider)
+		if !ok {
+			return
+		}
+		var body saveMessageTemplateRequest
+		if err := decodeJSONBody(r, &body); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		updatedBy := ""
+		if claims, ok := auth.ClaimsFromContext(r.Context()); ok {
+			updatedBy = claims.Subject
+		}
+		tmpl, err := admin.SaveMessageTemplate(r.PathValue("key"), r.PathValue("locale"), body.Body, updatedBy)
+		if err != nil {
+			writeAdminError(w, err)
+			return
+		}
+		templates.ApplyMessageTemplate(tmpl)
+		writeJSON(w, http.StatusOK, tmpl)
+	}
+}
+
+// handleAdminDeleteMessageTemplate restores the built-in copy of a message
+// in one locale.
+func handleAdminDeleteMessageTemplate(adminProvider adminDataSourceProvider, templates messageTemplateOverrides) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		admin, ok := resolveAdminDataSource(w, r, adminProvider)
+		if !ok {
+			return
+		}
+		tmpl, err := admin.DeleteMessageTemplate(r.PathValue("key"), r.PathValue("locale"))
+		if err != nil {
+			writeAdminError(w, err)
+			return
+		}
+		templates.RemoveMessageTemplate(tmpl)
+		w.WriteHeader(http.StatusNoContent)
+	}
+}
@@ -0,0 +1,103 @@
+// Copyright 2026 the P&AI authors. All rights reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package server
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/p-n-ai/pai-bot/internal/adminapi"
+	"github.com/p-n-ai/pai-bot/internal/auth"
+	"github.com/p-n-ai/pai-bot/internal/i18n"
+	"github.com/p-n-ai/pai-bot/internal/retrieval"
+)
+
+func TestNewTenantMessageTemplatesAppliesOwnTenantOnly(t *testing.T) {
+	overrides := i18n.NewOverrides()
+	NewTenantMessageTemplates(overrides, "tenant-abc", []adminapi.MessageTemplate{
+		{TenantID: "tenant-abc", Key: "start_onboarding_form", Locale: "en", Body: "Welcome to SMK Damansara, {{name}}!"},
+		{TenantID: "tenant-other", Key: "start_onboarding_form", Locale: "ms", Body: "Selamat datang, {{name}}!"},
+	})
+
+	if got := overrides.S("en", i18n.MsgStartOnboardingForm, map[string]string{"name": "Aina"}); got != "Welcome to SMK Damansara, Aina!" {
+		t.Fatalf("S(en) = %q, want the tenant override", got)
+	}
+	if _, ok := overrides.Lookup("ms", i18n.MsgStartOnboardingForm); ok {
+		t.Fatal("Lookup(ms) found another tenant's override")
+	}
+}
+
+func TestAdminMessageTemplateEndpoints(t *testing.T) {
+	tests := []struct {
+		name     string
+		method   string
+		path     string
+		body     string
+		token    func(*testing.T) string
+		wantCode int
+		wantLive string
+	}{
+		{
+			name:     "admin saves and the bot uses it",
+			method:   http.MethodPut,
+			path:     "/api/admin/message-templates/start_onboarding_form/en",
+			body:     `{"body":"Welcome to SMK Damansara, {{name}}!"}`,
+			token:    mustIssueAdminToken,
+			wantCode: http.StatusOK,
+			wantLive: "Welcome to SMK Damansara, Aina!",
+		},
+		{
+			name:     "unknown variable",
+			method:   http.MethodPut,
+			path:     "/api/admin/message-templates/start_onboarding_form/en",
+			body:     `{"body":"Welcome, {{nickname}}!"}`,
+			token:    mustIssueAdminToken,
+			wantCode: http.StatusBadRequest,
+		},
+		{
+			name:     "teachers cannot edit copy",
+			method:   http.MethodPut,
+			path:     "/api/admin/message-templates/start_onboarding_form/en",
+			body:     `{"body":"Welcome, {{name}}!"}`,
+			token:    mustIssueTeacherToken,
+			wantCode: http.StatusForbidden,
+		},
+		{
+			name:     "delete restores the built-in copy",
+			method:   http.MethodDelete,
+			path:     "/api/admin/message-templates/start_onboarding_form/en",
+			token:    mustIssueAdminToken,
+			wantCode: http.StatusNoContent,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			overrides := i18n.NewOverrides()
+			overrides.Set(i18n.MsgStartOnboardingForm, "en", "Hello {{name}}.")
+			templates := NewTenantMessageTemplates(overrides, "tenant-abc", nil)
+			handler := newHandlerWithAdminProvider(fixedAdminDataSourceProvider{source: stubAdminAPI{}}, nil, &chatGatewayStub{}, retrieval.NewMemoryService(), &stubAuthService{}, auth.NewSigningSecret("change-me-in-production"), time.Hour, "", nil, nil, false, nil, nil, templates)
+
+			req := httptest.NewRequest(tt.method, tt.path, strings.NewReader(tt.body))
+			req.Header.Set("Authorization", "Bearer "+tt.token(t))
+			rec := httptest.NewRecorder()
+			handler.ServeHTTP(rec, req)
+
+			if rec.Code != tt.wantCode {
+				t.Fatalf("status = %d, want %d (body %q)", rec.Code, tt.wantCode, rec.Body.String())
+			}
+			got := overrides.S("en", i18n.MsgStartOnboardingForm, map[string]string{"name": "Aina"})
+			switch {
+			case tt.wantLive != "" && got != tt.wantLive:
+				t.Fatalf("S(en) = %q, want %q live", got, tt.wantLive)
+			case tt.method == http.MethodDelete && !strings.HasPrefix(got, "Hi Aina!"):
+				t.Fatalf("S(en) = %q, want the built-in welcome", got)
+			case tt.wantLive == "" && tt.method != http.MethodDelete && got != "Hello Aina.":
+				t.Fatalf("S(en) = %q, want the earlier override unchanged", got)
+			}
+		})
+	}
+}
@@ -0,0 +1,93 @@
+// Copyright 2026 the P&AI authors. All rights reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package server
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"time"
+
+	"github.com/p-n-ai/pai-bot/internal/adminapi"
+)
+
+// apiTokenResolver maps a tenant API token to its tenant.
+type apiTokenResolver interface {
+	ResolveAPIToken(ctx context.Context, token string) (string, error)
+}
+
+type usageStatsSource interface {
+	GetUsageStats(from, to time.Time) (adminapi.UsageStats, error)
+}
+
+// PublicUsageHandler serves a tenant's aggregate usage to holders of one of
+// its API tokens. The token picks the tenant, so a partner school only ever
+// sees its own numbers and needs no admin account.
+type PublicUsageHandler struct {
+	tokens    apiTokenResolver
+	forTenant func(tenantID string) usageStatsSource
+}
+
+func NewPublicUsageHandler(tokens APITokenResolver, forTenant func(tenantID string) UsageStatsSource) (*PublicUsageHandler, error) {
+	if tokens == nil {
+		return nil, fmt.Errorf("api token resolver is required")
+	}
+	if forTenant == nil {
+		return nil, fmt.Errorf("usage stats source is required")
+	}
+	return &PublicUsageHandler{tokens: tokens, forTenant: forTenant}, nil
+}
+
+func (h *PublicUsageHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	token, err := bearerToken(r.Header.Get("Authorization"))
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusUnauthorized)
+		return
+	}
+	tenantID, err := h.tokens.ResolveAPIToken(r.Context(), token)
+	if err != nil {
+		if errors.Is(err, adminapi.ErrNotFound) {
+			http.Error(w, "invalid api token", http.StatusUnauthorized)
+			return
+		}
+		slog.Error("resolve api token failed", "error", err)
+		http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+		return
+	}
+
+	from, err := parseUsageTime(r.URL.Query().Get("from"))
+	if err != nil {
+		http.Error(w, "from: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+	to, err := parseUsageTime(r.URL.Query().Get("to"))
+	if err != nil {
+		http.Error(w, "to: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+	stats, err := h.forTenant(tenantID).GetUsageStats(from, to)
+	if err != nil {
+		writeAdminError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, stats)
+}
+
+// parseUsageTime accepts an RFC 3339 timestamp or a UTC date. Empty means
+// the default bound.
+func parseUsageTime(raw string) (time.Time, error) {
+	if raw == "" {
+		return time.Time{}, nil
+	}
+	if t, err := time.Parse(time.DateOnly, raw); err == nil {
+		return t, nil
+	}
+	t, err := time.Parse(time.RFC3339, raw)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("want YYYY-MM-DD or an RFC 3339 timestamp")
+	}
+	return t, nil
+}
@@ -0,0 +1,106 @@
+// Copyright 2026 the P&AI authors. All rights reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/p-n-ai/pai-bot/internal/adminapi"
+)
+
+type stubTokenResolver map[string]string
+
+func (s stubTokenResolver) ResolveAPIToken(_ context.Context, token string) (string, error) {
+	tenantID, ok := s[token]
+	if !ok {
+		return "", adminapi.ErrNotFound
+	}
+	return tenantID, nil
+}
+
+type stubUsageStats struct {
+	tenantID string
+	from, to time.Time
+}
+
+func (s *stubUsageStats) GetUsageStats(from, to time.Time) (adminapi.UsageStats, error) {
+	s.from, s.to = from, to
+	return adminapi.UsageStats{
+		TenantID:       s.tenantID,
+		ActiveStudents: 12,
+		Messages:       340,
+		TotalTokens:    5000,
+		TopTopics:      []adminapi.TopicUsage{{TopicID: "F1-02", Messages: 120, Students: 8}},
+	}, nil
+}
+
+func newPublicUsageTestMux(t *testing.T) (http.Handler, *stubUsageStats) {
+	t.Helper()
+	stats := &stubUsageStats{}
+	handler, err := NewPublicUsageHandler(stubTokenResolver{"pai_school": "tenant-1"}, func(tenantID string) UsageStatsSource {
+		stats.tenantID = tenantID
+		return stats
+	})
+	if err != nil {
+		t.Fatalf("NewPublicUsageHandler() error = %v", err)
+	}
+	return NewTopMux(TopMuxOptions{APIHandler: http.NotFoundHandler(), PublicUsageHandler: handler}), stats
+}
+
+func getPublicUsage(h http.Handler, query, token string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(http.MethodGet, "/api/public/v1/usage"+query, nil)
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, req)
+	return rec
+}
+
+func TestPublicUsageReturnsTheTokensTenant(t *testing.T) {
+	h, stats := newPublicUsageTestMux(t)
+
+	rec := getPublicUsage(h, "?from=2026-09-01&to=2026-10-01T00:00:00Z", "pai_school")
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status = %d, body %q", rec.Code, rec.Body.String())
+	}
+	var got adminapi.UsageStats
+	if err := json.Unmarshal(rec.Body.Bytes(), &got); err != nil {
+		t.Fatalf("decode response: %v", err)
+	}
+	if got.TenantID != "tenant-1" || got.ActiveStudents != 12 || len(got.TopTopics) != 1 {
+		t.Fatalf("stats = %+v", got)
+	}
+	if !stats.from.Equal(time.Date(2026, 9, 1, 0, 0, 0, 0, time.UTC)) || !stats.to.Equal(time.Date(2026, 10, 1, 0, 0, 0, 0, time.UTC)) {
+		t.Fatalf("window = %v..%v", stats.from, stats.to)
+	}
+	if rec.Header().Get("Cache-Control") == "" {
+		t.Fatal("usage response should not be cached")
+	}
+}
+
+func TestPublicUsageRejectsBadRequests(t *testing.T) {
+	h, _ := newPublicUsageTestMux(t)
+
+	for name, tc := range map[string]struct {
+		query, token string
+		want         int
+	}{
+		"missing token": {"", "", http.StatusUnauthorized},
+		"unknown token": {"", "pai_other", http.StatusUnauthorized},
+		"bad date":      {"?from=last-week", "pai_school", http.StatusBadRequest},
+	} {
+		if rec := getPublicUsage(h, tc.query, tc.token); rec.Code != tc.want {
+			t.Errorf("%s: status = %d, want %d", name, rec.Code, tc.want)
+		}
+	}
+	if rec := getPublicUsage(h, "", "pai_school"); rec.Code != http.StatusOK {
+		t.Fatalf("valid request status = %d", rec.Code)
+	}
+}
@@ -0,0 +1,86 @@
+// Copyright 2026 the P&AI authors. All rights reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package server
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"net/http"
+	"strings"
+
+	"github.com/p-n-ai/pai-bot/internal/agent"
+	"github.com/p-n-ai/pai-bot/internal/auth"
+)
+
+// sandboxAdmin lets staff chat as a throwaway learner through the REST API.
+// The engine implements it alongside conversationAdmin.
+type sandboxAdmin interface {
+	StartSandbox(ctx context.Context, name, form string) (agent.SandboxSession, error)
+	SandboxTurn(ctx context.Context, userID, text string) (string, error)
+}
+
+type sandboxStartRequest struct {
+	Name string `json:"name"`
+	Form string `json:"form"`
+}
+
+type sandboxMessageRequest struct {
+	Text string `json:"text"`
+}
+
+func handleAdminStartSandbox(sandbox sandboxAdmin) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		var req sandboxStartRequest
+		if err := decodeOptionalJSONBody(r, &req); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		session, err := sandbox.StartSandbox(r.Context(), req.Name, req.Form)
+		if errors.Is(err, agent.ErrInvalidSandboxForm) {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		if err != nil {
+			slog.Error("failed to start sandbox", "error", err)
+			http.Error(w, "sandbox could not be started", http.StatusInternalServerError)
+			return
+		}
+		staff := ""
+		if claims, ok := auth.ClaimsFromContext(r.Context()); ok {
+			staff = claims.Subject
+		}
+		slog.Info("sandbox started", "staff_user_id", staff, "sandbox_user_id", session.UserID)
+		writeJSON(w, http.StatusCreated, session)
+	}
+}
+
+func handleAdminSandboxMessage(sandbox sandboxAdmin) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		var req sandboxMessageRequest
+		if err := decodeJSONBody(r, &req); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		if strings.TrimSpace(req.Text) == "" {
+			http.Error(w, "text is required", http.StatusBadRequest)
+			return
+		}
+		userID := r.PathValue("id")
+		reply, err := sandbox.SandboxTurn(r.Context(), userID, req.Text)
+		if errors.Is(err, agent.ErrNotSandboxUser) {
+			http.Error(w, err.Error(), http.StatusNotFound)
+			return
+		}
+		if err != nil {
+			slog.Warn("sandbox turn failed", "sandbox_user_id", userID, "error", err)
+			http.Error(w, "sandbox turn failed", http.StatusBadGateway)
+			return
+		}
+		writeJSON(w, http.StatusOK, map[string]string{
+			"user_id": userID,
+			"reply":   reply,
+		})
+	}
+}
@@ -0,0 +1,116 @@
+// Copyright 2026 the P&AI authors. All rights reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/p-n-ai/pai-bot/internal/agent"
+	"github.com/p-n-ai/pai-bot/internal/auth"
+	"github.com/p-n-ai/pai-bot/internal/retrieval"
+)
+
+type stubSandboxEngine struct {
+	stubConversationAdmin
+}
+
+func (s *stubSandboxEngine) StartSandbox(_ context.Context, _, form string) (agent.SandboxSession, error) {
+	if form == "9" {
+		return agent.SandboxSession{}, agent.ErrInvalidSandboxForm
+	}
+	return agent.SandboxSession{UserID: agent.SandboxUserPrefix + "abc", ConversationID: "conv-1"}, nil
+}
+
+func (s *stubSandboxEngine) SandboxTurn(_ context.Context, userID, _ string) (string, error) {
+	if !agent.IsSandboxUser(userID) {
+		return "", agent.ErrNotSandboxUser
+	}
+	return "Jawapan tutor.", nil
+}
+
+func TestAdminSandboxRoutes(t *testing.T) {
+	tests := []struct {
+		name        string
+		path        string
+		body        string
+		token       func(*testing.T) string
+		multiTenant bool
+		wantCode    int
+		wantBody    string
+	}{
+		{
+			name:     "admin starts a sandbox",
+			path:     "/api/admin/sandbox",
+			body:     `{"form":"2"}`,
+			token:    func(t *testing.T) string { return mustIssueToken(t, auth.RoleAdmin) },
+			wantCode: http.StatusCreated,
+			wantBody: `"user_id":"sandbox-abc"`,
+		},
+		{
+			name:     "bad form",
+			path:     "/api/admin/sandbox",
+			body:     `{"form":"9"}`,
+			token:    func(t *testing.T) string { return mustIssueToken(t, auth.RoleAdmin) },
+			wantCode: http.StatusBadRequest,
+		},
+		{
+			name:     "admin chats as the sandbox learner",
+			path:     "/api/admin/sandbox/sandbox-abc/messages",
+			body:     `{"text":"What is a fraction?"}`,
+			token:    func(t *testing.T) string { return mustIssueToken(t, auth.RoleAdmin) },
+			wantCode: http.StatusOK,
+			wantBody: `"reply":"Jawapan tutor."`,
+		},
+		{
+			name:     "real learners cannot be impersonated",
+			path:     "/api/admin/sandbox/42/messages",
+			body:     `{"text":"hi"}`,
+			token:    func(t *testing.T) string { return mustIssueToken(t, auth.RoleAdmin) },
+			wantCode: http.StatusNotFound,
+		},
+		{
+			name:     "teachers cannot open sandboxes",
+			path:     "/api/admin/sandbox",
+			token:    mustIssueTeacherToken,
+			wantCode: http.StatusForbidden,
+		},
+		{
+			name:        "tenant admins cannot open sandboxes across tenants",
+			path:        "/api/admin/sandbox",
+			token:       func(t *testing.T) string { return mustIssueToken(t, auth.RoleAdmin) },
+			multiTenant: true,
+			wantCode:    http.StatusForbidden,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			engine := &stubSandboxEngine{}
+			handler := newHandlerWithAdminProvider(fixedAdminDataSourceProvider{source: stubAdminAPI{}}, nil, &chatGatewayStub{}, retrieval.NewMemoryService(), &stubAuthService{}, auth.NewSigningSecret("change-me-in-production"), time.Hour, "", nil, nil, tt.multiTenant, engine, nil, nil)
+
+			req := httptest.NewRequest(http.MethodPost, tt.path, strings.NewReader(tt.body))
+			req.Header.Set("Authorization", "Bearer "+tt.token(t))
+			rec := httptest.NewRecorder()
+			handler.ServeHTTP(rec, req)
+
+			if rec.Code != tt.wantCode {
+				t.Fatalf("status = %d, want %d (body %q)", rec.Code, tt.wantCode, rec.Body.String())
+			}
+			if tt.wantBody != "" && !strings.Contains(rec.Body.String(), tt.wantBody) {
+				t.Fatalf("body = %q, want %s", rec.Body.String(), tt.wantBody)
+			}
+			if rec.Code == http.StatusOK {
+				var payload map[string]string
+				if err := json.Unmarshal(rec.Body.Bytes(), &payload); err != nil || payload["user_id"] != "sandbox-abc" {
+					t.Fatalf("payload = %v, %v", payload, err)
+				}
+			}
+		})
+	}
+}
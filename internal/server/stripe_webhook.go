@@ -0,0 +1,255 @@
+// Copyright 2026 the P&AI authors. All rights reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package server
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/p-n-ai/pai-bot/internal/agent"
+)
+
+const (
+	stripeProvider = "stripe"
+	// stripeSignatureTolerance matches Stripe's own libraries: older
+	// signatures are treated as replays.
+	stripeSignatureTolerance = 5 * time.Minute
+	stripeMaxBodyBytes       = 1 << 20
+)
+
+var errStripeSignature = errors.New("invalid stripe signature")
+
+// StripeWebhookHandler applies Stripe Checkout and subscription events to the
+// subscription store. A checkout names the learner in client_reference_id,
+// which /subscribe adds to the payment link; metadata "scope=tenant" makes
+// the plan tenant-wide and metadata "tier" picks the tier (premium when
+// unset). Later subscription events are matched by the Stripe subscription
+// ID.
+type StripeWebhookHandler struct {
+	store  agent.SubscriptionStore
+	secret string
+	now    func() time.Time
+}
+
+func NewStripeWebhookHandler(store agent.SubscriptionStore, secret string) (*StripeWebhookHandler, error) {
+	if store == nil {
+		return nil, fmt.Errorf("subscription store is required")
+	}
+	if strings.TrimSpace(secret) == "" {
+		return nil, fmt.Errorf("stripe webhook secret is required")
+	}
+	return &StripeWebhookHandler{store: store, secret: secret, now: time.Now}, nil
+}
+
+type stripeEvent struct {
+	ID   string `json:"id"`
+	Type string `json:"type"`
+	Data struct {
+		Object json.RawMessage `json:"object"`
+	} `json:"data"`
+}
+
+type stripeCheckoutSession struct {
+	Mode              string            `json:"mode"`
+	ClientReferenceID string            `json:"client_reference_id"`
+	Customer          string            `json:"customer"`
+	Subscription      string            `json:"subscription"`
+	Metadata          map[string]string `json:"metadata"`
+}
+
+type stripeSubscription struct {
+	ID               string            `json:"id"`
+	Customer         string            `json:"customer"`
+	Status           string            `json:"status"`
+	CurrentPeriodEnd int64             `json:"current_period_end"`
+	Metadata         map[string]string `json:"metadata"`
+	// Newer API versions report the period on each item instead.
+	Items struct {
+		Data []struct {
+			CurrentPeriodEnd int64 `json:"current_period_end"`
+		} `json:"data"`
+	} `json:"items"`
+}
+
+func (s stripeSubscription) periodEnd() *time.Time {
+	end := s.CurrentPeriodEnd
+	for _, item := range s.Items.Data {
+		end = max(end, item.CurrentPeriodEnd)
+	}
+	if end == 0 {
+		return nil
+	}
+	t := time.Unix(end, 0).UTC()
+	return &t
+}
+
+func (h *StripeWebhookHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	payload, err := io.ReadAll(http.MaxBytesReader(w, r.Body, stripeMaxBodyBytes))
+	if err != nil {
+		http.Error(w, "request body too large", http.StatusRequestEntityTooLarge)
+		return
+	}
+	if err := verifyStripeSignature(payload, r.Header.Get("Stripe-Signature"), h.secret, h.now()); err != nil {
+		slog.Warn("rejected stripe webhook", "error", err)
+		http.Error(w, "invalid signature", http.StatusBadRequest)
+		return
+	}
+	var event stripeEvent
+	if err := json.Unmarshal(payload, &event); err != nil {
+		http.Error(w, "invalid event", http.StatusBadRequest)
+		return
+	}
+
+	switch event.Type {
+	case "checkout.session.completed":
+		err = h.applyCheckout(event.Data.Object)
+	case "customer.subscription.created", "customer.subscription.updated", "customer.subscription.deleted":
+		err = h.applySubscription(event.Type, event.Data.Object)
+	default:
+		// Stripe sends whatever the endpoint subscribes to; acknowledge the rest.
+	}
+	if err != nil {
+		// A 5xx makes Stripe retry the event later.
+		slog.Error("failed to apply stripe event", "event_id", event.ID, "type", event.Type, "error", err)
+		http.Error(w, "failed to apply event", http.StatusInternalServerError)
+		return
+	}
+	w.WriteHeader(http.StatusOK)
+}
+
+func (h *StripeWebhookHandler) applyCheckout(raw json.RawMessage) error {
+	var session stripeCheckoutSession
+	if err := json.Unmarshal(raw, &session); err != nil {
+		return fmt.Errorf("decode checkout session: %w", err)
+	}
+	if session.Mode != "subscription" {
+		return nil
+	}
+	userID := strings.TrimSpace(session.ClientReferenceID)
+	if session.Metadata["scope"] == "tenant" {
+		userID = ""
+	} else if userID == "" {
+		slog.Warn("stripe checkout without a learner reference", "subscription_id", session.Subscription)
+		return nil
+	}
+	sub := agent.Subscription{
+		UserID:         userID,
+		Tier:           stripeTier(session.Metadata),
+		Status:         agent.SubscriptionActive,
+		Provider:       stripeProvider,
+		CustomerID:     session.Customer,
+		SubscriptionID: session.Subscription,
+	}
+	// The subscription event may have arrived first with the billing period.
+	if existing, found, err := h.store.FindSubscription(stripeProvider, session.Subscription); err != nil {
+		return err
+	} else if found {
+		sub.Status = existing.Status
+		sub.PeriodEnd = existing.PeriodEnd
+	}
+	if err := h.store.UpsertSubscription(sub); err != nil {
+		return err
+	}
+	slog.Info("subscription started", "user_id", userID, "tier", sub.Tier, "subscription_id", sub.SubscriptionID)
+	return nil
+}
+
+func (h *StripeWebhookHandler) applySubscription(eventType string, raw json.RawMessage) error {
+	var stripeSub stripeSubscription
+	if err := json.Unmarshal(raw, &stripeSub); err != nil {
+		return fmt.Errorf("decode subscription: %w", err)
+	}
+	sub, found, err := h.store.FindSubscription(stripeProvider, stripeSub.ID)
+	if err != nil {
+		return err
+	}
+	if !found {
+		userID := strings.TrimSpace(stripeSub.Metadata["user_id"])
+		if userID == "" && stripeSub.Metadata["scope"] != "tenant" {
+			// Checkout has not linked it to a learner yet; the checkout event
+			// will.
+			return nil
+		}
+		sub = &agent.Subscription{
+			UserID:         userID,
+			Tier:           stripeTier(stripeSub.Metadata),
+			Provider:       stripeProvider,
+			SubscriptionID: stripeSub.ID,
+		}
+	}
+	if _, ok := stripeSub.Metadata["tier"]; ok {
+		sub.Tier = stripeTier(stripeSub.Metadata)
+	}
+	sub.CustomerID = stripeSub.Customer
+	sub.Status = stripeSub.Status
+	if eventType == "customer.subscription.deleted" {
+		sub.Status = agent.SubscriptionCanceled
+	}
+	sub.PeriodEnd = stripeSub.periodEnd()
+	if err := h.store.UpsertSubscription(*sub); err != nil {
+		return err
+	}
+	slog.Info("subscription updated", "user_id", sub.UserID, "tier", sub.Tier, "status", sub.Status, "subscription_id", sub.SubscriptionID)
+	return nil
+}
+
+// stripeTier reads the tier from Stripe metadata; paid plans default to
+// premium.
+func stripeTier(metadata map[string]string) agent.SubscriptionTier {
+	if tier, ok := agent.ParseSubscriptionTier(metadata["tier"]); ok {
+		return tier
+	}
+	return agent.TierPremium
+}
+
+// verifyStripeSignature checks a Stripe-Signature header of the form
+// "t=<unix>,v1=<hex hmac>[,v1=...]" against payload.
+func verifyStripeSignature(payload []byte, header, secret string, now time.Time) error {
+	var timestamp string
+	var signatures []string
+	for _, part := range strings.Split(header, ",") {
+		key, value, ok := strings.Cut(strings.TrimSpace(part), "=")
+		if !ok {
+			continue
+		}
+		switch key {
+		case "t":
+			timestamp = value
+		case "v1":
+			signatures = append(signatures, value)
+		}
+	}
+	unix, err := strconv.ParseInt(timestamp, 10, 64)
+	if err != nil || len(signatures) == 0 {
+		return errStripeSignature
+	}
+	if age := now.Sub(time.Unix(unix, 0)); age > stripeSignatureTolerance || age < -stripeSignatureTolerance {
+		return fmt.Errorf("%w: timestamp outside tolerance", errStripeSignature)
+	}
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(timestamp + "."))
+	mac.Write(payload)
+	expected := mac.Sum(nil)
+	for _, sig := range signatures {
+		got, err := hex.DecodeString(sig)
+		if err == nil && hmac.Equal(got, expected) {
+			return nil
+		}
+	}
+	return errStripeSignature
+}
@@ -0,0 +1,116 @@
+// Copyright 2026 the P&AI authors. All rights reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package server
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/p-n-ai/pai-bot/internal/agent"
+)
+
+const testStripeSecret = "whsec_test"
+
+func signStripePayload(payload string, at time.Time) string {
+	timestamp := fmt.Sprint(at.Unix())
+	mac := hmac.New(sha256.New, []byte(testStripeSecret))
+	mac.Write([]byte(timestamp + "." + payload))
+	return "t=" + timestamp + ",v1=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+func postStripeEvent(t *testing.T, h http.Handler, payload, signature string) int {
+	t.Helper()
+	req := httptest.NewRequest(http.MethodPost, "/webhook/stripe", strings.NewReader(payload))
+	req.Header.Set("Stripe-Signature", signature)
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, req)
+	return rec.Code
+}
+
+func TestStripeWebhookRejectsBadSignatures(t *testing.T) {
+	store := agent.NewMemorySubscriptionStore()
+	h, err := NewStripeWebhookHandler(store, testStripeSecret)
+	if err != nil {
+		t.Fatalf("NewStripeWebhookHandler() error = %v", err)
+	}
+	now := time.Now()
+	h.now = func() time.Time { return now }
+	payload := `{"type":"checkout.session.completed","data":{"object":{"mode":"subscription","client_reference_id":"42","subscription":"sub_1"}}}`
+
+	for name, signature := range map[string]string{
+		"missing":  "",
+		"tampered": signStripePayload(payload+" ", now),
+		"stale":    signStripePayload(payload, now.Add(-10*time.Minute)),
+	} {
+		if code := postStripeEvent(t, h, payload, signature); code != http.StatusBadRequest {
+			t.Errorf("%s signature status = %d, want 400", name, code)
+		}
+	}
+	if _, found, _ := store.GetSubscription("42"); found {
+		t.Fatal("unsigned event created a subscription")
+	}
+}
+
+func TestStripeWebhookAppliesCheckoutAndSubscriptionEvents(t *testing.T) {
+	store := agent.NewMemorySubscriptionStore()
+	h, err := NewStripeWebhookHandler(store, testStripeSecret)
+	if err != nil {
+		t.Fatalf("NewStripeWebhookHandler() error = %v", err)
+	}
+	now := time.Now()
+	send := func(payload string) {
+		t.Helper()
+		if code := postStripeEvent(t, h, payload, signStripePayload(payload, now)); code != http.StatusOK {
+			t.Fatalf("status = %d, want 200 for %s", code, payload)
+		}
+	}
+
+	send(`{"id":"evt_1","type":"checkout.session.completed","data":{"object":{"mode":"subscription","client_reference_id":"42","customer":"cus_1","subscription":"sub_1"}}}`)
+	sub, found, _ := store.GetSubscription("42")
+	if !found || sub.Tier != agent.TierPremium || !sub.Active(now) || sub.SubscriptionID != "sub_1" || sub.CustomerID != "cus_1" {
+		t.Fatalf("subscription after checkout = %+v, %v; want active premium", sub, found)
+	}
+
+	periodEnd := now.Add(30 * 24 * time.Hour).Unix()
+	send(fmt.Sprintf(`{"id":"evt_2","type":"customer.subscription.updated","data":{"object":{"id":"sub_1","customer":"cus_1","status":"past_due","items":{"data":[{"current_period_end":%d}]}}}}`, periodEnd))
+	sub, _, _ = store.GetSubscription("42")
+	if sub.Status != agent.SubscriptionPastDue || sub.PeriodEnd == nil || sub.PeriodEnd.Unix() != periodEnd {
+		t.Fatalf("subscription after update = %+v, want past_due with the item period end", sub)
+	}
+
+	send(`{"id":"evt_3","type":"customer.subscription.deleted","data":{"object":{"id":"sub_1","customer":"cus_1","status":"canceled"}}}`)
+	sub, _, _ = store.GetSubscription("42")
+	if sub.Status != agent.SubscriptionCanceled || sub.Active(now) {
+		t.Fatalf("subscription after delete = %+v, want canceled", sub)
+	}
+
+	// Unrelated events and unknown subscriptions are acknowledged and ignored.
+	send(`{"id":"evt_4","type":"invoice.paid","data":{"object":{}}}`)
+	send(`{"id":"evt_5","type":"customer.subscription.updated","data":{"object":{"id":"sub_other","status":"active"}}}`)
+	if _, found, _ := store.FindSubscription("stripe", "sub_other"); found {
+		t.Fatal("unlinked subscription event created a subscription")
+	}
+}
+
+func TestStripeWebhookTenantWideCheckout(t *testing.T) {
+	store := agent.NewMemorySubscriptionStore()
+	h, _ := NewStripeWebhookHandler(store, testStripeSecret)
+	payload := `{"type":"checkout.session.completed","data":{"object":{"mode":"subscription","client_reference_id":"admin-7","subscription":"sub_school","metadata":{"scope":"tenant"}}}}`
+	if code := postStripeEvent(t, h, payload, signStripePayload(payload, time.Now())); code != http.StatusOK {
+		t.Fatalf("status = %d, want 200", code)
+	}
+	if sub, found, _ := store.GetSubscription(""); !found || sub.Tier != agent.TierPremium {
+		t.Fatalf("tenant subscription = %+v, %v; want premium", sub, found)
+	}
+	if _, found, _ := store.GetSubscription("admin-7"); found {
+		t.Fatal("tenant checkout also created a learner subscription")
+	}
+}
@@ -0,0 +1,115 @@
+// Copyright 2026 the P&AI authors. All rights reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package server
+
+import (
+	"net/http"
+
+	"github.com/p-n-ai/pai-bot/internal/adminapi"
+	"github.com/p-n-ai/pai-bot/internal/auth"
+	"github.com/p-n-ai/pai-bot/internal/curriculum"
+)
+
+// TeachingNoteCurriculum is the exported seam cmd/server wires to the
+// handler; see teachingNoteCurriculum.
+type TeachingNoteCurriculum = teachingNoteCurriculum
+
+// teachingNoteCurriculum checks teacher-authored notes against the loaded
+// curriculum and hands saved ones to the running tutor.
+type teachingNoteCurriculum interface {
+	ValidateTeachingNoteOverlay(topicID string, overlay curriculum.TeachingNoteOverlay) error
+	ApplyTeachingNote(note adminapi.TeachingNote)
+}
+
+// loaderTeachingNotes applies saved notes to the tutor's curriculum loader.
+// The engine serves one tenant, so notes saved for any other tenant are
+// stored but not layered in.
+type loaderTeachingNotes struct {
+	loader   *curriculum.Loader
+	tenantID string
+}
+
+// NewLoaderTeachingNotes layers the saved notes for tenantID onto loader and
+// keeps it current as teachers edit.
+func NewLoaderTeachingNotes(loader *curriculum.Loader, tenantID string, saved []adminapi.TeachingNote) TeachingNoteCurriculum {
+	notes := loaderTeachingNotes{loader: loader, tenantID: tenantID}
+	for _, note := range saved {
+		notes.ApplyTeachingNote(note)
+	}
+	return notes
+}
+
+func (n loaderTeachingNotes) ValidateTeachingNoteOverlay(topicID string, overlay curriculum.TeachingNoteOverlay) error {
+	return n.loader.ValidateTeachingNoteOverlay(topicID, overlay)
+}
+
+func (n loaderTeachingNotes) ApplyTeachingNote(note adminapi.TeachingNote) {
+	if note.TenantID != n.tenantID {
+		return
+	}
+	n.loader.SetTeachingNoteOverlay(note.TopicID, note.Overlay())
+}
+
+func handleAdminListTeachingNotes(adminProvider adminDataSourceProvider) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		admin, ok := resolveAdminDataSource(w, r, adminProvider)
+		if !ok {
+			return
+		}
+		notes, err := admin.ListTeachingNotes()
+		if err != nil {
+			writeAdminError(w, err)
+			return
+		}
+		writeJSON(w, http.StatusOK, map[string]any{"notes": notes})
+	}
+}
+
+func handleAdminTeachingNoteHistory(adminProvider adminDataSourceProvider) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		admin, ok := resolveAdminDataSource(w, r, adminProvider)
+		if !ok {
+			return
+		}
+		versions, err := admin.GetTeachingNoteHistory(r.PathValue("topicID"))
+		if err != nil {
+			writeAdminError(w, err)
+			return
+		}
+		writeJSON(w, http.StatusOK, map[string]any{"versions": versions})
+	}
+}
+
+// handleAdminSaveTeachingNote validates the body against the curriculum,
+// stores it as the topic's next version, and makes it live for the tutor
+// without a restart.
+func handleAdminSaveTeachingNote(adminProvider adminDataSourceProvider, teachingNotes teachingNoteCurriculum) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		admin, ok := resolveAdminDataSource(w, r, adminProvider)
+		if !ok {
+			return
+		}
+		var overlay curriculum.TeachingNoteOverlay
+		if err := decodeJSONBody(r, &overlay); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		topicID := r.PathValue("topicID")
+		if err := teachingNotes.ValidateTeachingNoteOverlay(topicID, overlay); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		createdBy := ""
+		if claims, ok := auth.ClaimsFromContext(r.Context()); ok {
+			createdBy = claims.Subject
+		}
+		note, err := admin.SaveTeachingNote(topicID, overlay, createdBy)
+		if err != nil {
+			writeAdminError(w, err)
+			return
+		}
+		teachingNotes.ApplyTeachingNote(note)
+		writeJSON(w, http.StatusOK, note)
+	}
+}
@@ -0,0 +1,123 @@
+// Copyright 2026 the P&AI authors. All rights reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package server
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/p-n-ai/pai-bot/internal/adminapi"
+	"github.com/p-n-ai/pai-bot/internal/auth"
+	"github.com/p-n-ai/pai-bot/internal/curriculum"
+	"github.com/p-n-ai/pai-bot/internal/retrieval"
+)
+
+func newTeachingNotesLoader(t *testing.T) *curriculum.Loader {
+	t.Helper()
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "F1-03.yaml"), []byte("id: F1-03\nname: Linear Equations\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "F1-03.teaching.md"), []byte("# Linear Equations\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	loader, err := curriculum.NewLoader(dir)
+	if err != nil {
+		t.Fatalf("NewLoader() error = %v", err)
+	}
+	return loader
+}
+
+func TestNewLoaderTeachingNotesAppliesOwnTenantOnly(t *testing.T) {
+	loader := newTeachingNotesLoader(t)
+	NewLoaderTeachingNotes(loader, "tenant-abc", []adminapi.TeachingNote{
+		{TenantID: "tenant-abc", TopicID: "F1-03", Version: 2, Notes: "Use bus fares in word problems."},
+		{TenantID: "tenant-other", TopicID: "F1-03", Version: 5, Notes: "Use ringgit coins."},
+	})
+
+	notes, _ := loader.GetTeachingNotes("F1-03")
+	if !strings.Contains(notes, "bus fares") || strings.Contains(notes, "ringgit coins") {
+		t.Fatalf("GetTeachingNotes() = %q, want only this tenant's overlay", notes)
+	}
+}
+
+func TestAdminSaveTeachingNoteEndpoint(t *testing.T) {
+	tests := []struct {
+		name      string
+		path      string
+		body      string
+		token     func(*testing.T) string
+		wantCode  int
+		wantNotes string
+	}{
+		{
+			name:      "teacher saves and tutor sees it",
+			path:      "/api/admin/teaching-notes/F1-03",
+			body:      `{"notes":"Use bus fares in word problems.","examples":[{"problem":"x + 2 = 5","steps":["Subtract 2 from both sides."],"answer":"x = 3"}]}`,
+			token:     mustIssueTeacherToken,
+			wantCode:  http.StatusOK,
+			wantNotes: "**Answer:** x = 3",
+		},
+		{
+			name:     "unknown topic",
+			path:     "/api/admin/teaching-notes/F9-99",
+			body:     `{"notes":"Anything."}`,
+			token:    mustIssueTeacherToken,
+			wantCode: http.StatusBadRequest,
+		},
+		{
+			name:     "example without answer",
+			path:     "/api/admin/teaching-notes/F1-03",
+			body:     `{"examples":[{"problem":"x + 2 = 5"}]}`,
+			token:    mustIssueTeacherToken,
+			wantCode: http.StatusBadRequest,
+		},
+		{
+			name:     "students cannot author",
+			path:     "/api/admin/teaching-notes/F1-03",
+			body:     `{"notes":"Anything."}`,
+			token:    mustIssueStudentToken,
+			wantCode: http.StatusForbidden,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			loader := newTeachingNotesLoader(t)
+			handler := newHandlerWithAdminProvider(fixedAdminDataSourceProvider{source: stubAdminAPI{}}, nil, &chatGatewayStub{}, retrieval.NewMemoryService(), &stubAuthService{}, auth.NewSigningSecret("change-me-in-production"), time.Hour, "", nil, nil, false, nil, NewLoaderTeachingNotes(loader, "tenant-abc", nil), nil)
+
+			req := httptest.NewRequest(http.MethodPut, tt.path, strings.NewReader(tt.body))
+			req.Header.Set("Authorization", "Bearer "+tt.token(t))
+			rec := httptest.NewRecorder()
+			handler.ServeHTTP(rec, req)
+
+			if rec.Code != tt.wantCode {
+				t.Fatalf("status = %d, want %d (body %q)", rec.Code, tt.wantCode, rec.Body.String())
+			}
+			notes, _ := loader.GetTeachingNotes("F1-03")
+			if tt.wantNotes != "" && !strings.Contains(notes, tt.wantNotes) {
+				t.Fatalf("GetTeachingNotes() = %q, want the saved overlay live", notes)
+			}
+			if tt.wantNotes == "" && notes != "# Linear Equations\n" {
+				t.Fatalf("GetTeachingNotes() = %q, want the OSS notes unchanged", notes)
+			}
+		})
+	}
+}
+
+func TestAdminTeachingNoteHistoryUnknownTopic(t *testing.T) {
+	req := httptest.NewRequest(http.MethodGet, "/api/admin/teaching-notes/F1-03", nil)
+	req.Header.Set("Authorization", "Bearer "+mustIssueTeacherToken(t))
+	rec := httptest.NewRecorder()
+
+	newHandler(stubAdminAPI{}, &chatGatewayStub{}).ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusNotFound {
+		t.Fatalf("status = %d, want %d", rec.Code, http.StatusNotFound)
+	}
+}
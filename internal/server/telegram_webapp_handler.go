@@ -0,0 +1,156 @@
+// Copyright 2026 the P&AI authors. All rights reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/p-n-ai/pai-bot/internal/agent"
+	"github.com/p-n-ai/pai-bot/internal/chat"
+)
+
+// TelegramWebAppBackend serves the learner data behind the Telegram Mini App.
+type TelegramWebAppBackend interface {
+	WebAppProgress(userID string) (agent.WebAppProgress, error)
+	WebAppQuiz(userID string) agent.WebAppQuiz
+	ProcessMessage(ctx context.Context, msg chat.InboundMessage) (string, error)
+}
+
+// TelegramWebAppHandler serves the Mini App API under /api/telegram/webapp/.
+// Requests authenticate with the Mini App's signed initData in an
+// "Authorization: tma <initData>" header, which identifies the bot user
+// without a separate login.
+type TelegramWebAppHandler struct {
+	backend  TelegramWebAppBackend
+	botToken string
+	origin   string
+	maxAge   time.Duration
+	now      func() time.Time
+	mux      *http.ServeMux
+}
+
+type telegramWebAppUserKey struct{}
+
+func NewTelegramWebAppHandler(backend TelegramWebAppBackend, botToken, appURL string, maxAge time.Duration) (*TelegramWebAppHandler, error) {
+	if backend == nil {
+		return nil, fmt.Errorf("telegram web app backend is required")
+	}
+	if strings.TrimSpace(botToken) == "" {
+		return nil, fmt.Errorf("telegram bot token is required to verify web app init data")
+	}
+	parsed, err := url.Parse(strings.TrimSpace(appURL))
+	if err != nil || parsed.Scheme != "https" || parsed.Host == "" {
+		return nil, fmt.Errorf("telegram web app URL must be absolute HTTPS")
+	}
+	h := &TelegramWebAppHandler{
+		backend:  backend,
+		botToken: botToken,
+		origin:   parsed.Scheme + "://" + parsed.Host,
+		maxAge:   maxAge,
+		now:      time.Now,
+		mux:      http.NewServeMux(),
+	}
+	h.mux.HandleFunc("GET /api/telegram/webapp/progress", h.progress)
+	h.mux.HandleFunc("GET /api/telegram/webapp/quiz", h.quiz)
+	h.mux.HandleFunc("POST /api/telegram/webapp/quiz/answer", h.answer)
+	return h, nil
+}
+
+func (h *TelegramWebAppHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	setPrivateNoStoreHeaders(w)
+	w.Header().Set("X-Content-Type-Options", "nosniff")
+	if r.Header.Get("Origin") == h.origin {
+		w.Header().Set("Access-Control-Allow-Origin", h.origin)
+		w.Header().Add("Vary", "Origin")
+		w.Header().Set("Access-Control-Allow-Methods", "GET, POST, OPTIONS")
+		w.Header().Set("Access-Control-Allow-Headers", "Authorization, Content-Type")
+	}
+	if r.Method == http.MethodOptions {
+		w.WriteHeader(http.StatusNoContent)
+		return
+	}
+
+	scheme, initData, _ := strings.Cut(r.Header.Get("Authorization"), " ")
+	if !strings.EqualFold(scheme, "tma") || strings.TrimSpace(initData) == "" {
+		http.Error(w, "missing web app init data", http.StatusUnauthorized)
+		return
+	}
+	user, err := chat.ValidateTelegramWebAppInitData(strings.TrimSpace(initData), h.botToken, h.maxAge, h.now())
+	if err != nil {
+		message := "invalid web app init data"
+		if errors.Is(err, chat.ErrExpiredWebAppInitData) {
+			message = "expired web app init data"
+		}
+		http.Error(w, message, http.StatusUnauthorized)
+		return
+	}
+	h.mux.ServeHTTP(w, r.WithContext(context.WithValue(r.Context(), telegramWebAppUserKey{}, user)))
+}
+
+func (h *TelegramWebAppHandler) progress(w http.ResponseWriter, r *http.Request) {
+	user := telegramWebAppUser(r)
+	report, err := h.backend.WebAppProgress(user.BotUserID())
+	if err != nil {
+		slog.Error("telegram web app: failed to load progress", "user_id", user.BotUserID(), "error", err)
+		http.Error(w, "progress unavailable", http.StatusInternalServerError)
+		return
+	}
+	writeJSON(w, http.StatusOK, report)
+}
+
+func (h *TelegramWebAppHandler) quiz(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, http.StatusOK, h.backend.WebAppQuiz(telegramWebAppUser(r).BotUserID()))
+}
+
+// answer submits a quiz answer through the engine, exactly as if the learner
+// had typed it in the chat, and returns the reply with the next question.
+func (h *TelegramWebAppHandler) answer(w http.ResponseWriter, r *http.Request) {
+	user := telegramWebAppUser(r)
+	var input struct {
+		Answer string `json:"answer"`
+	}
+	decoder := json.NewDecoder(http.MaxBytesReader(w, r.Body, 2048))
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(&input); err != nil || strings.TrimSpace(input.Answer) == "" {
+		http.Error(w, "answer is required", http.StatusBadRequest)
+		return
+	}
+	if !h.backend.WebAppQuiz(user.BotUserID()).Active {
+		http.Error(w, "no active quiz", http.StatusConflict)
+		return
+	}
+
+	reply, err := h.backend.ProcessMessage(r.Context(), chat.InboundMessage{
+		Channel:    "telegram",
+		UserID:     user.BotUserID(),
+		ExternalID: user.BotUserID(),
+		Username:   user.Username,
+		FirstName:  user.FirstName,
+		LastName:   user.LastName,
+		Language:   user.LanguageCode,
+		Text:       strings.TrimSpace(input.Answer),
+	})
+	if err != nil {
+		slog.Error("telegram web app: failed to process answer", "user_id", user.BotUserID(), "error", err)
+		http.Error(w, "answer could not be checked", http.StatusInternalServerError)
+		return
+	}
+	writeJSON(w, http.StatusOK, map[string]any{
+		"reply": reply,
+		"quiz":  h.backend.WebAppQuiz(user.BotUserID()),
+	})
+}
+
+func telegramWebAppUser(r *http.Request) chat.TelegramWebAppUser {
+	user, _ := r.Context().Value(telegramWebAppUserKey{}).(chat.TelegramWebAppUser)
+	return user
+}
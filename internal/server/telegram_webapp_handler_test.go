@@ -0,0 +1,177 @@
+// Copyright 2026 the P&AI authors. All rights reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package server
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/p-n-ai/pai-bot/internal/agent"
+	"github.com/p-n-ai/pai-bot/internal/chat"
+)
+
+const telegramWebAppTestToken = "123456:test-token"
+
+type fakeTelegramWebAppBackend struct {
+	quiz     agent.WebAppQuiz
+	progress map[string]agent.WebAppProgress
+	messages []chat.InboundMessage
+}
+
+func (b *fakeTelegramWebAppBackend) WebAppProgress(userID string) (agent.WebAppProgress, error) {
+	return b.progress[userID], nil
+}
+
+func (b *fakeTelegramWebAppBackend) WebAppQuiz(string) agent.WebAppQuiz {
+	return b.quiz
+}
+
+func (b *fakeTelegramWebAppBackend) ProcessMessage(_ context.Context, msg chat.InboundMessage) (string, error) {
+	b.messages = append(b.messages, msg)
+	b.quiz.QuestionNumber++
+	return "Correct!", nil
+}
+
+func telegramWebAppInitData(t *testing.T, userID int64, authDate time.Time) string {
+	t.Helper()
+	fields := map[string]string{
+		"auth_date": strconv.FormatInt(authDate.Unix(), 10),
+		"user":      `{"id":` + strconv.FormatInt(userID, 10) + `,"first_name":"Aina","language_code":"en"}`,
+	}
+	lines := make([]string, 0, len(fields))
+	values := url.Values{}
+	for key, value := range fields {
+		lines = append(lines, key+"="+value)
+		values.Set(key, value)
+	}
+	sort.Strings(lines)
+	secret := hmac.New(sha256.New, []byte("WebAppData"))
+	secret.Write([]byte(telegramWebAppTestToken))
+	mac := hmac.New(sha256.New, secret.Sum(nil))
+	mac.Write([]byte(strings.Join(lines, "\n")))
+	values.Set("hash", hex.EncodeToString(mac.Sum(nil)))
+	return values.Encode()
+}
+
+func newTestTelegramWebAppHandler(t *testing.T, backend TelegramWebAppBackend) http.Handler {
+	t.Helper()
+	handler, err := NewTelegramWebAppHandler(backend, telegramWebAppTestToken, "https://app.example/mini", time.Hour)
+	if err != nil {
+		t.Fatalf("NewTelegramWebAppHandler() error = %v", err)
+	}
+	return NewTopMux(TopMuxOptions{APIHandler: http.NotFoundHandler(), TelegramWebAppHandler: handler})
+}
+
+func TestTelegramWebAppHandler_RejectsMissingAndForgedInitData(t *testing.T) {
+	handler := newTestTelegramWebAppHandler(t, &fakeTelegramWebAppBackend{})
+	forged := strings.Replace(telegramWebAppInitData(t, 42, time.Now()), "Aina", "Eve", 1)
+	stale := telegramWebAppInitData(t, 42, time.Now().Add(-2*time.Hour))
+
+	for name, authorization := range map[string]string{
+		"missing": "",
+		"bearer":  "Bearer abc",
+		"forged":  "tma " + forged,
+		"stale":   "tma " + stale,
+	} {
+		req := httptest.NewRequest(http.MethodGet, "/api/telegram/webapp/progress", nil)
+		if authorization != "" {
+			req.Header.Set("Authorization", authorization)
+		}
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, req)
+		if rec.Code != http.StatusUnauthorized {
+			t.Errorf("%s: status = %d, want 401", name, rec.Code)
+		}
+	}
+}
+
+func TestTelegramWebAppHandler_ProgressUsesTheSignedUser(t *testing.T) {
+	backend := &fakeTelegramWebAppBackend{progress: map[string]agent.WebAppProgress{
+		"42": {XP: 120, CurrentStreak: 3},
+	}}
+	handler := newTestTelegramWebAppHandler(t, backend)
+
+	req := httptest.NewRequest(http.MethodGet, "/api/telegram/webapp/progress", nil)
+	req.Header.Set("Authorization", "tma "+telegramWebAppInitData(t, 42, time.Now()))
+	req.Header.Set("Origin", "https://app.example")
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status = %d, want 200: %s", rec.Code, rec.Body.String())
+	}
+	var got agent.WebAppProgress
+	if err := json.NewDecoder(rec.Body).Decode(&got); err != nil {
+		t.Fatalf("decode progress: %v", err)
+	}
+	if got.XP != 120 || got.CurrentStreak != 3 {
+		t.Fatalf("progress = %+v, want user 42's progress", got)
+	}
+	if origin := rec.Header().Get("Access-Control-Allow-Origin"); origin != "https://app.example" {
+		t.Fatalf("Access-Control-Allow-Origin = %q, want the web app origin", origin)
+	}
+	if cache := rec.Header().Get("Cache-Control"); !strings.Contains(cache, "no-store") {
+		t.Fatalf("Cache-Control = %q, want no-store", cache)
+	}
+}
+
+func TestTelegramWebAppHandler_AnswerRoutesThroughEngine(t *testing.T) {
+	backend := &fakeTelegramWebAppBackend{}
+	handler := newTestTelegramWebAppHandler(t, backend)
+	answer := func() *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodPost, "/api/telegram/webapp/quiz/answer", strings.NewReader(`{"answer":" x = 4 "}`))
+		req.Header.Set("Authorization", "tma "+telegramWebAppInitData(t, 42, time.Now()))
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, req)
+		return rec
+	}
+
+	if rec := answer(); rec.Code != http.StatusConflict {
+		t.Fatalf("status without a quiz = %d, want 409", rec.Code)
+	}
+
+	backend.quiz = agent.WebAppQuiz{Active: true, QuestionNumber: 1, TotalQuestions: 3}
+	rec := answer()
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status = %d, want 200: %s", rec.Code, rec.Body.String())
+	}
+	if len(backend.messages) != 1 {
+		t.Fatalf("engine messages = %d, want 1", len(backend.messages))
+	}
+	msg := backend.messages[0]
+	if msg.Channel != "telegram" || msg.UserID != "42" || msg.Text != "x = 4" || msg.Language != "en" {
+		t.Fatalf("engine message = %+v, want the trimmed answer from telegram user 42", msg)
+	}
+	var body struct {
+		Reply string           `json:"reply"`
+		Quiz  agent.WebAppQuiz `json:"quiz"`
+	}
+	if err := json.NewDecoder(rec.Body).Decode(&body); err != nil {
+		t.Fatalf("decode answer: %v", err)
+	}
+	if body.Reply != "Correct!" || body.Quiz.QuestionNumber != 2 {
+		t.Fatalf("answer response = %+v, want the reply and the next question", body)
+	}
+}
+
+func TestNewTelegramWebAppHandler_RequiresHTTPSAndToken(t *testing.T) {
+	backend := &fakeTelegramWebAppBackend{}
+	if _, err := NewTelegramWebAppHandler(backend, "", "https://app.example", time.Hour); err == nil {
+		t.Fatal("NewTelegramWebAppHandler() without a bot token succeeded")
+	}
+	if _, err := NewTelegramWebAppHandler(backend, telegramWebAppTestToken, "http://app.example", time.Hour); err == nil {
+		t.Fatal("NewTelegramWebAppHandler() with an http URL succeeded")
+	}
+}
@@ -0,0 +1,66 @@
+// Copyright 2026 the P&AI authors. All rights reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package server
+
+import (
+	"log/slog"
+	"net/http"
+
+	"github.com/p-n-ai/pai-bot/internal/adminapi"
+	"github.com/p-n-ai/pai-bot/internal/agent"
+)
+
+// tenantSettingsApplier is implemented by the engine. It serves one tenant
+// and ignores changes for any other.
+type tenantSettingsApplier interface {
+	SetTenantMetaFooter(tenantID string, enabled bool)
+	SetTenantNotation(tenantID, rules string) error
+}
+
+func handleAdminGetTenantSettings(adminProvider adminDataSourceProvider) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		admin, ok := resolveAdminDataSource(w, r, adminProvider)
+		if !ok {
+			return
+		}
+		settings, err := admin.GetTenantSettings()
+		if err != nil {
+			writeAdminError(w, err)
+			return
+		}
+		writeJSON(w, http.StatusOK, settings)
+	}
+}
+
+// handleAdminUpdateTenantSettings saves the tenant's settings and, when
+// applier is set, applies them to the running bot without a redeploy.
+func handleAdminUpdateTenantSettings(adminProvider adminDataSourceProvider, applier tenantSettingsApplier) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		admin, ok := resolveAdminDataSource(w, r, adminProvider)
+		if !ok {
+			return
+		}
+		var body adminapi.TenantSettings
+		if err := decodeJSONBody(r, &body); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		if _, err := agent.ParseNotationConfig(body.NotationRules); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		settings, err := admin.UpdateTenantSettings(body)
+		if err != nil {
+			writeAdminError(w, err)
+			return
+		}
+		if applier != nil {
+			applier.SetTenantMetaFooter(settings.TenantID, settings.MetaFooter)
+			if err := applier.SetTenantNotation(settings.TenantID, settings.NotationRules); err != nil {
+				slog.Warn("tenant notation rules not applied", "tenant_id", settings.TenantID, "error", err)
+			}
+		}
+		writeJSON(w, http.StatusOK, settings)
+	}
+}
@@ -0,0 +1,122 @@
+// Copyright 2026 the P&AI authors. All rights reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package server
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/p-n-ai/pai-bot/internal/auth"
+	"github.com/p-n-ai/pai-bot/internal/retrieval"
+)
+
+// footerConversationAdmin records meta footer and notation changes applied
+// to the engine.
+type footerConversationAdmin struct {
+	stubConversationAdmin
+	applied  []string
+	notation []string
+}
+
+func (f *footerConversationAdmin) SetTenantMetaFooter(tenantID string, enabled bool) {
+	state := "off"
+	if enabled {
+		state = "on"
+	}
+	f.applied = append(f.applied, tenantID+":"+state)
+}
+
+func (f *footerConversationAdmin) SetTenantNotation(tenantID, rules string) error {
+	f.notation = append(f.notation, tenantID+":"+rules)
+	return nil
+}
+
+func TestAdminTenantSettingsEndpoints(t *testing.T) {
+	tests := []struct {
+		name         string
+		method       string
+		body         string
+		token        func(*testing.T) string
+		wantCode     int
+		wantBody     string
+		wantApplied  []string
+		wantNotation []string
+	}{
+		{
+			name:     "admin reads settings",
+			method:   http.MethodGet,
+			token:    mustIssueAdminToken,
+			wantCode: http.StatusOK,
+			wantBody: `"meta_footer":false`,
+		},
+		{
+			name:         "admin turns the meta footer on",
+			method:       http.MethodPut,
+			body:         `{"meta_footer":true}`,
+			token:        mustIssueAdminToken,
+			wantCode:     http.StatusOK,
+			wantBody:     `"meta_footer":true`,
+			wantApplied:  []string{"tenant-1:on"},
+			wantNotation: []string{"tenant-1:"},
+		},
+		{
+			name:         "admin sets the tenant's notation rules",
+			method:       http.MethodPut,
+			body:         `{"notation_rules":"currency,terms"}`,
+			token:        mustIssueAdminToken,
+			wantCode:     http.StatusOK,
+			wantBody:     `"notation_rules":"currency,terms"`,
+			wantApplied:  []string{"tenant-1:off"},
+			wantNotation: []string{"tenant-1:currency,terms"},
+		},
+		{
+			name:     "unknown notation rule",
+			method:   http.MethodPut,
+			body:     `{"notation_rules":"fahrenheit"}`,
+			token:    mustIssueAdminToken,
+			wantCode: http.StatusBadRequest,
+		},
+		{
+			name:     "teachers cannot change settings",
+			method:   http.MethodPut,
+			body:     `{"meta_footer":true}`,
+			token:    mustIssueTeacherToken,
+			wantCode: http.StatusForbidden,
+		},
+		{
+			name:     "invalid json",
+			method:   http.MethodPut,
+			body:     `{"meta_footer":`,
+			token:    mustIssueAdminToken,
+			wantCode: http.StatusBadRequest,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			engine := &footerConversationAdmin{}
+			handler := newHandlerWithAdminProvider(fixedAdminDataSourceProvider{source: stubAdminAPI{}}, nil, &chatGatewayStub{}, retrieval.NewMemoryService(), &stubAuthService{}, auth.NewSigningSecret("change-me-in-production"), time.Hour, "", nil, nil, false, engine, nil, nil)
+
+			req := httptest.NewRequest(tt.method, "/api/admin/tenant-settings", strings.NewReader(tt.body))
+			req.Header.Set("Authorization", "Bearer "+tt.token(t))
+			rec := httptest.NewRecorder()
+			handler.ServeHTTP(rec, req)
+
+			if rec.Code != tt.wantCode {
+				t.Fatalf("status = %d, want %d (body %q)", rec.Code, tt.wantCode, rec.Body.String())
+			}
+			if tt.wantBody != "" && !strings.Contains(rec.Body.String(), tt.wantBody) {
+				t.Fatalf("body = %q, want it to contain %q", rec.Body.String(), tt.wantBody)
+			}
+			if strings.Join(engine.applied, ",") != strings.Join(tt.wantApplied, ",") {
+				t.Fatalf("applied = %v, want %v", engine.applied, tt.wantApplied)
+			}
+			if strings.Join(engine.notation, ",") != strings.Join(tt.wantNotation, ",") {
+				t.Fatalf("notation = %v, want %v", engine.notation, tt.wantNotation)
+			}
+		})
+	}
+}
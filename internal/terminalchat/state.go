@@ -6,10 +6,12 @@ package terminalchat
 import (
 	"context"
 	"fmt"
+	"time"
 
 	"github.com/p-n-ai/pai-bot/internal/agent"
 	"github.com/p-n-ai/pai-bot/internal/platform/config"
 	"github.com/p-n-ai/pai-bot/internal/platform/database"
+	"github.com/p-n-ai/pai-bot/internal/platform/msgcrypt"
 	"github.com/p-n-ai/pai-bot/internal/progress"
 )
 
@@ -17,8 +19,16 @@ import (
 type StateOptions struct {
 	Memory  bool
 	Channel string
+	// SnapshotPath, with Memory, loads in-memory conversations from this JSON
+	// file on start and saves them back every SnapshotInterval and on cleanup.
+	SnapshotPath     string
+	SnapshotInterval time.Duration
 }
 
+// defaultSnapshotInterval is how often in-memory state is saved when
+// SnapshotPath is set without an interval.
+const defaultSnapshotInterval = 30 * time.Second
+
 // State bundles the dependencies needed by the engine for session state.
 type State struct {
 	Store       agent.ConversationStore
@@ -40,18 +50,28 @@ type StateDeps struct {
 // Persistent PostgreSQL-backed state is the default; in-memory mode must be explicit.
 func BuildState(ctx context.Context, dbCfg config.DatabaseConfig, opts StateOptions, deps StateDeps) (State, func(), error) {
 	if opts.Memory {
+		store := agent.NewMemoryStore()
+		cleanup := func() {}
+		if opts.SnapshotPath != "" {
+			if err := store.LoadSnapshot(opts.SnapshotPath); err != nil {
+				return State{}, nil, err
+			}
+			cleanup = startSnapshots(store, opts.SnapshotPath, opts.SnapshotInterval)
+		}
 		return State{
-			Store:       agent.NewMemoryStore(),
+			Store:       store,
 			Tracker:     progress.NewMemoryTracker(),
 			EventLogger: agent.NewMemoryEventLogger(),
 			DB:          nil,
 			TenantID:    "",
-		}, func() {}, nil
+		}, cleanup, nil
 	}
 
 	openDB := deps.OpenDB
 	if openDB == nil {
-		openDB = database.New
+		openDB = func(ctx context.Context, url string, maxConns, minConns int) (*database.DB, error) {
+			return database.New(ctx, url, maxConns, minConns)
+		}
 	}
 
 	newPostgresStore := deps.NewPostgresStore
@@ -61,6 +81,11 @@ func BuildState(ctx context.Context, dbCfg config.DatabaseConfig, opts StateOpti
 			if err != nil {
 				return nil, "", err
 			}
+			keys, err := msgcrypt.Parse(dbCfg.MessageKeys, dbCfg.MessageActiveKey)
+			if err != nil {
+				return nil, "", fmt.Errorf("message encryption keys: %w", err)
+			}
+			store.SetMessageKeyring(keys)
 			return store, store.TenantID(), nil
 		}
 	}
@@ -104,3 +129,21 @@ func BuildState(ctx context.Context, dbCfg config.DatabaseConfig, opts StateOpti
 		TenantID:    tenantID,
 	}, cleanup, nil
 }
+
+// startSnapshots saves store periodically in the background. The returned
+// cleanup stops the loop after a final save.
+func startSnapshots(store *agent.MemoryStore, path string, interval time.Duration) func() {
+	if interval <= 0 {
+		interval = defaultSnapshotInterval
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		store.RunSnapshots(ctx, path, interval)
+	}()
+	return func() {
+		cancel()
+		<-done
+	}
+}
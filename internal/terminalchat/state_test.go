@@ -5,7 +5,9 @@ package terminalchat
 
 import (
 	"context"
+	"path/filepath"
 	"testing"
+	"time"
 
 	"github.com/p-n-ai/pai-bot/internal/agent"
 	"github.com/p-n-ai/pai-bot/internal/platform/config"
@@ -84,3 +86,34 @@ func TestBuildState_MemoryModeSkipsDatabase(t *testing.T) {
 		t.Fatalf("memory mode DB/TenantID = %#v / %q, want nil and empty", state.DB, state.TenantID)
 	}
 }
+
+func TestBuildState_MemorySnapshotSurvivesRestart(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "state.json")
+	opts := StateOptions{Memory: true, SnapshotPath: path, SnapshotInterval: time.Hour}
+
+	state, cleanup, err := BuildState(context.Background(), config.DatabaseConfig{}, opts, StateDeps{})
+	if err != nil {
+		t.Fatalf("BuildState() error = %v", err)
+	}
+	convID, err := state.Store.CreateConversation(agent.Conversation{UserID: "u1", State: "teaching"})
+	if err != nil {
+		t.Fatalf("CreateConversation() error = %v", err)
+	}
+	if _, err := state.Store.AddMessage(convID, agent.StoredMessage{Role: "user", Content: "hello"}); err != nil {
+		t.Fatalf("AddMessage() error = %v", err)
+	}
+	cleanup()
+
+	restored, cleanup, err := BuildState(context.Background(), config.DatabaseConfig{}, opts, StateDeps{})
+	if err != nil {
+		t.Fatalf("BuildState() after restart error = %v", err)
+	}
+	defer cleanup()
+	conv, found := restored.Store.GetActiveConversation("u1")
+	if !found || conv.ID != convID {
+		t.Fatalf("GetActiveConversation() = %+v, %v; want restored %s", conv, found, convID)
+	}
+	if len(conv.Messages) != 1 || conv.Messages[0].Content != "hello" {
+		t.Fatalf("restored messages = %+v, want the saved message", conv.Messages)
+	}
+}
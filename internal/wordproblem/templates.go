@@ -0,0 +1,474 @@
+// Copyright 2026 the P&AI authors. All rights reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package wordproblem
+
+import (
+	"fmt"
+	"math/rand/v2"
+	"strings"
+
+	"github.com/p-n-ai/pai-bot/internal/algebra"
+)
+
+type foodItem struct {
+	ms, en, zh string
+	// loSen and hiSen bound a believable kopitiam or canteen price.
+	loSen, hiSen int
+}
+
+var foodItems = []foodItem{
+	{"nasi lemak", "nasi lemak", "椰浆饭", 250, 450},
+	{"teh tarik", "teh tarik", "拉茶", 180, 300},
+	{"roti canai", "roti canai", "印度煎饼", 120, 200},
+	{"karipap", "curry puff", "咖喱角", 60, 120},
+	{"Milo ais", "iced Milo", "冰美禄", 250, 400},
+	{"kuih lapis", "kuih lapis", "千层糕", 50, 100},
+}
+
+func (f foodItem) name(lang string) string { return pick(lang, f.ms, f.en, f.zh) }
+
+type purchase struct {
+	item     foodItem
+	qty, sen int
+}
+
+// drawShoppingChange: buy a few canteen items, pay with one note, find the
+// change.
+func drawShoppingChange(r *rand.Rand, level int, lang string) (Problem, bool) {
+	kinds, step, maxQty := 2, 50, 3
+	switch level {
+	case 1:
+		step, maxQty = 10, 4
+	case 2:
+		kinds, step, maxQty = 3, 10, 5
+	}
+	var basket []purchase
+	total := 0
+	for _, i := range r.Perm(len(foodItems))[:kinds] {
+		item := foodItems[i]
+		p := purchase{item: item, qty: 1 + r.IntN(maxQty), sen: between(r, item.loSen, item.hiSen, step)}
+		basket = append(basket, p)
+		total += p.qty * p.sen
+	}
+	note := 0
+	for _, n := range []int{1000, 2000, 5000, 10000} {
+		if n > total {
+			note = n
+			break
+		}
+	}
+	change := note - total
+	// Check the story: the note covers the basket and the change makes it up.
+	sum := 0
+	for _, p := range basket {
+		sum += p.qty * p.sen
+	}
+	if note == 0 || change <= 0 || sum+change != note {
+		return Problem{}, false
+	}
+
+	name := names[r.IntN(len(names))]
+	var lines, terms []string
+	for _, p := range basket {
+		lines = append(lines, pick(lang,
+			fmt.Sprintf("%d %s pada harga RM%s setiap satu", p.qty, p.item.name(lang), ringgit(p.sen)),
+			fmt.Sprintf("%d %s at RM%s each", p.qty, p.item.name(lang), ringgit(p.sen)),
+			fmt.Sprintf("%d 份%s（每份 RM%s）", p.qty, p.item.name(lang), ringgit(p.sen))))
+		terms = append(terms, fmt.Sprintf("%d × RM%s", p.qty, ringgit(p.sen)))
+	}
+	bought := joinList(lang, lines)
+	return Problem{
+		Text: pick(lang,
+			fmt.Sprintf("Di kantin sekolah, %s membeli %s. Dia membayar dengan sekeping wang kertas RM%d. Berapakah baki wang yang diterimanya, dalam RM?", name, bought, note/100),
+			fmt.Sprintf("At the school canteen, %s buys %s. They pay with a RM%d note. How much change do they get, in RM?", name, bought, note/100),
+			fmt.Sprintf("在学校食堂，%s 买了 %s。%s 用一张 RM%d 钞票付款。应找回多少令吉？", name, bought, name, note/100)),
+		Answer: ringgit(change),
+		Unit:   "RM",
+		Working: fmt.Sprintf("%s = %s = RM%s\n%s = RM%s − RM%s = RM%s",
+			pick(lang, "Jumlah", "Total", "总额"), strings.Join(terms, " + "), ringgit(total),
+			pick(lang, "Baki", "Change", "找回"), ringgit(note), ringgit(total), ringgit(change)),
+		Hints: [2]string{
+			pick(lang,
+				"Cari jumlah harga dahulu: darabkan setiap harga dengan bilangan yang dibeli.",
+				"Find the total cost first: multiply each price by how many were bought.",
+				"先算总额：每样东西的价钱乘以购买的数量。"),
+			pick(lang,
+				fmt.Sprintf("Jumlahnya RM%s. Tolakkan daripada RM%d.", ringgit(total), note/100),
+				fmt.Sprintf("The total is RM%s. Subtract it from RM%d.", ringgit(total), note/100),
+				fmt.Sprintf("总额是 RM%s。用 RM%d 减去它。", ringgit(total), note/100)),
+		},
+	}, true
+}
+
+// drawKopitiamSplit: a group shares a kopitiam bill, with service tax and a
+// service charge at higher levels.
+func drawKopitiamSplit(r *rand.Rand, level int, lang string) (Problem, bool) {
+	people := 2 + r.IntN(5)
+	subtotal := between(r, 2000, 8000, 100)
+	taxRate, chargeRate := 0, 0
+	switch level {
+	case 1:
+		subtotal = between(r, 2000, 8000, 50)
+		taxRate = 6
+	case 2:
+		subtotal = between(r, 2000, 8000, 50)
+		taxRate, chargeRate = 6, 10
+	}
+	// Charges must come to whole sen and the bill must split evenly.
+	if subtotal*taxRate%100 != 0 || subtotal*chargeRate%100 != 0 {
+		return Problem{}, false
+	}
+	tax := subtotal * taxRate / 100
+	charge := subtotal * chargeRate / 100
+	total := subtotal + tax + charge
+	if total%people != 0 {
+		return Problem{}, false
+	}
+	each := total / people
+	if each*people != total {
+		return Problem{}, false
+	}
+
+	name := names[r.IntN(len(names))]
+	extra := ""
+	var working []string
+	switch level {
+	case 1:
+		extra = pick(lang,
+			" Cukai perkhidmatan 6% dikenakan ke atas harga makanan.",
+			" A 6% service tax is charged on the food.",
+			" 食物价钱另加 6% 服务税。")
+	case 2:
+		extra = pick(lang,
+			" Caj perkhidmatan 10% dan cukai perkhidmatan 6% dikenakan ke atas harga makanan.",
+			" A 10% service charge and a 6% service tax are both charged on the food.",
+			" 食物价钱另加 10% 服务费和 6% 服务税。")
+	}
+	if chargeRate > 0 {
+		working = append(working, fmt.Sprintf("%s = 10%% × RM%s = RM%s", pick(lang, "Caj perkhidmatan", "Service charge", "服务费"), ringgit(subtotal), ringgit(charge)))
+	}
+	if taxRate > 0 {
+		working = append(working, fmt.Sprintf("%s = 6%% × RM%s = RM%s", pick(lang, "Cukai perkhidmatan", "Service tax", "服务税"), ringgit(subtotal), ringgit(tax)))
+		working = append(working, fmt.Sprintf("%s = RM%s", pick(lang, "Jumlah bil", "Total bill", "账单总额"), ringgit(total)))
+	}
+	working = append(working, fmt.Sprintf("%s = RM%s ÷ %d = RM%s", pick(lang, "Seorang", "Each pays", "每人"), ringgit(total), people, ringgit(each)))
+
+	return Problem{
+		Text: pick(lang,
+			fmt.Sprintf("%s dan %d orang kawan makan di sebuah kopitiam. Harga makanan ialah RM%s.%s Mereka berkongsi bil sama rata. Berapakah bayaran seorang, dalam RM?", name, people-1, ringgit(subtotal), extra),
+			fmt.Sprintf("%s and %d friends eat at a kopitiam. The food costs RM%s.%s They split the bill equally. How much does each person pay, in RM?", name, people-1, ringgit(subtotal), extra),
+			fmt.Sprintf("%s 和 %d 位朋友在咖啡店吃饭，食物共 RM%s。%s他们平分账单。每人要付多少令吉？", name, people-1, ringgit(subtotal), strings.TrimSpace(extra))),
+		Answer:  ringgit(each),
+		Unit:    "RM",
+		Working: strings.Join(working, "\n"),
+		Hints: [2]string{
+			pick(lang,
+				fmt.Sprintf("Ada %d orang semuanya, termasuk %s.", people, name),
+				fmt.Sprintf("There are %d people altogether, including %s.", people, name),
+				fmt.Sprintf("连 %s 在内一共 %d 人。", name, people)),
+			pick(lang,
+				fmt.Sprintf("Jumlah bil ialah RM%s. Bahagikan dengan %d.", ringgit(total), people),
+				fmt.Sprintf("The whole bill is RM%s. Divide it by %d.", ringgit(total), people),
+				fmt.Sprintf("账单总额是 RM%s，再除以 %d。", ringgit(total), people)),
+		},
+	}, true
+}
+
+type route struct {
+	from, to string
+	// km is the rounded road distance quoted in the problem.
+	km int
+}
+
+var routes = []route{
+	{"Kuala Lumpur", "Ipoh", 200},
+	{"Kuala Lumpur", "Melaka", 150},
+	{"Kuala Lumpur", "Kuantan", 250},
+	{"Kuala Lumpur", "Johor Bahru", 330},
+	{"Ipoh", "George Town", 160},
+	{"Kota Bharu", "Kuala Terengganu", 165},
+	{"Kota Kinabalu", "Sandakan", 330},
+	{"Kuching", "Sibu", 400},
+}
+
+// drawCityDistance: a bus between two Malaysian cities; find the journey
+// time, or at the hard level the average speed from a timetable.
+func drawCityDistance(r *rand.Rand, level int, lang string) (Problem, bool) {
+	rt := routes[r.IntN(len(routes))]
+	if level == 2 {
+		return drawCitySpeed(r, rt, lang)
+	}
+	speed := between(r, 40, 110, 5)
+	// minutes is exact only when speed divides the distance in minutes.
+	if rt.km*60%speed != 0 {
+		return Problem{}, false
+	}
+	minutes := rt.km * 60 / speed
+	granularity := 60
+	if level == 1 {
+		granularity = 15
+	}
+	if minutes%granularity != 0 || minutes < 60 || speed*minutes != rt.km*60 {
+		return Problem{}, false
+	}
+	hours := decimal(minutes * 100 / 60)
+	return Problem{
+		Text: pick(lang,
+			fmt.Sprintf("Jarak jalan raya dari %s ke %s ialah kira-kira %d km. Sebuah bas bergerak dengan laju purata %d km/j. Berapa jamkah perjalanan itu?", rt.from, rt.to, rt.km, speed),
+			fmt.Sprintf("The road distance from %s to %s is about %d km. A bus travels at an average speed of %d km/h. How many hours does the journey take?", rt.from, rt.to, rt.km, speed),
+			fmt.Sprintf("从 %s 到 %s 的公路距离约 %d 公里。一辆巴士以平均时速 %d 公里行驶。全程需要多少小时？", rt.from, rt.to, rt.km, speed)),
+		Answer:  hours,
+		Unit:    pick(lang, "jam", "h", "小时"),
+		Working: fmt.Sprintf("%s = %d km ÷ %d km/h = %s h", pick(lang, "Masa", "Time", "时间"), rt.km, speed, hours),
+		Hints: [2]string{
+			pick(lang, "Masa = jarak ÷ laju.", "Time = distance ÷ speed.", "时间 = 距离 ÷ 速度。"),
+			pick(lang,
+				fmt.Sprintf("Bahagikan %d dengan %d.", rt.km, speed),
+				fmt.Sprintf("Divide %d by %d.", rt.km, speed),
+				fmt.Sprintf("用 %d 除以 %d。", rt.km, speed)),
+		},
+	}, true
+}
+
+func drawCitySpeed(r *rand.Rand, rt route, lang string) (Problem, bool) {
+	depart := between(r, 7*60, 10*60, 15)
+	minutes := between(r, 90, 6*60, 15)
+	if rt.km*60%minutes != 0 {
+		return Problem{}, false
+	}
+	speed := rt.km * 60 / minutes
+	if speed < 40 || speed > 110 || speed*minutes != rt.km*60 {
+		return Problem{}, false
+	}
+	arrive := depart + minutes
+	hours := decimal(minutes * 100 / 60)
+	return Problem{
+		Text: pick(lang,
+			fmt.Sprintf("Sebuah bas bertolak dari %s pada %s dan tiba di %s pada %s. Jarak perjalanan ialah kira-kira %d km. Hitung laju purata bas itu, dalam km/j.", rt.from, clock(depart, lang), rt.to, clock(arrive, lang), rt.km),
+			fmt.Sprintf("A bus leaves %s at %s and arrives in %s at %s. The journey is about %d km. Find the bus's average speed, in km/h.", rt.from, clock(depart, lang), rt.to, clock(arrive, lang), rt.km),
+			fmt.Sprintf("一辆巴士在%s从 %s 出发，%s抵达 %s，全程约 %d 公里。求巴士的平均速度（公里/小时）。", clock(depart, lang), rt.from, clock(arrive, lang), rt.to, rt.km)),
+		Answer: fmt.Sprintf("%d", speed),
+		Unit:   "km/h",
+		Working: fmt.Sprintf("%s = %s − %s = %s h\n%s = %d km ÷ %s h = %d km/h",
+			pick(lang, "Masa", "Time", "时间"), clock(arrive, lang), clock(depart, lang), hours,
+			pick(lang, "Laju purata", "Average speed", "平均速度"), rt.km, hours, speed),
+		Hints: [2]string{
+			pick(lang,
+				"Cari masa perjalanan dalam jam dahulu. 30 minit = 0.5 jam.",
+				"Find the journey time in hours first. 30 minutes = 0.5 h.",
+				"先求行程时间（小时）。30 分钟 = 0.5 小时。"),
+			pick(lang,
+				fmt.Sprintf("Perjalanan mengambil %s jam. Laju = jarak ÷ masa.", hours),
+				fmt.Sprintf("The journey takes %s h. Speed = distance ÷ time.", hours),
+				fmt.Sprintf("行程需要 %s 小时。速度 = 距离 ÷ 时间。", hours)),
+		},
+	}, true
+}
+
+// clock formats minutes after midnight as a 12-hour time.
+func clock(minutes int, lang string) string {
+	h, m := minutes/60, minutes%60
+	h12 := h % 12
+	if h12 == 0 {
+		h12 = 12
+	}
+	switch lang {
+	case "en":
+		return fmt.Sprintf("%d:%02d %s", h12, m, map[bool]string{true: "am", false: "pm"}[h < 12])
+	case "zh":
+		return fmt.Sprintf("%s%d:%02d", map[bool]string{true: "上午", false: "下午"}[h < 12], h12, m)
+	default:
+		period := "pagi"
+		switch {
+		case h >= 19:
+			period = "malam"
+		case h >= 14:
+			period = "petang"
+		case h >= 12:
+			period = "tengah hari"
+		}
+		return fmt.Sprintf("%d.%02d %s", h12, m, period)
+	}
+}
+
+// drawLinearPrice: form and solve a linear equation for an unknown kopitiam
+// price. The worked solution is checked line by line with the algebra
+// package.
+func drawLinearPrice(r *rand.Rand, level int, lang string) (Problem, bool) {
+	perm := r.Perm(len(foodItems))
+	unknown, known := foodItems[perm[0]], foodItems[perm[1]]
+	a := 2 + r.IntN(4)
+	x := between(r, unknown.loSen, unknown.hiSen, 10)
+	b, p, note := 0, 0, 0
+	if level >= 1 {
+		b = 1 + r.IntN(3)
+		p = between(r, known.loSen, known.hiSen, 10)
+	}
+	total := a*x + b*p
+	if level == 2 {
+		for _, n := range []int{2000, 5000, 10000} {
+			if n > total {
+				note = n
+				break
+			}
+		}
+		if note == 0 {
+			return Problem{}, false
+		}
+	}
+
+	name := names[r.IntN(len(names))]
+	var text string
+	var steps []string
+	equation := fmt.Sprintf("%dx = %s", a, ringgit(total))
+	if b > 0 {
+		equation = fmt.Sprintf("%dx + %d(%s) = %s", a, b, ringgit(p), ringgit(total))
+	}
+	switch level {
+	case 0:
+		text = pick(lang,
+			fmt.Sprintf("%d %s berharga RM%s. Bentukkan satu persamaan linear dan cari harga sebiji %s, dalam RM.", a, unknown.ms, ringgit(total), unknown.ms),
+			fmt.Sprintf("%d %s cost RM%s altogether. Form a linear equation and find the price of one %s, in RM.", a, unknown.en, ringgit(total), unknown.en),
+			fmt.Sprintf("%d 份%s共 RM%s。列出一个线性方程，求一份%s的价钱（令吉）。", a, unknown.zh, ringgit(total), unknown.zh))
+		steps = []string{equation}
+	case 1:
+		text = pick(lang,
+			fmt.Sprintf("Di sebuah kopitiam, %d %s dan %d %s berharga RM%s. Sebiji %s berharga RM%s. Bentukkan satu persamaan linear dan cari harga sebiji %s, dalam RM.", a, unknown.ms, b, known.ms, ringgit(total), known.ms, ringgit(p), unknown.ms),
+			fmt.Sprintf("At a kopitiam, %d %s and %d %s cost RM%s. One %s costs RM%s. Form a linear equation and find the price of one %s, in RM.", a, unknown.en, b, known.en, ringgit(total), known.en, ringgit(p), unknown.en),
+			fmt.Sprintf("在咖啡店，%d 份%s和 %d 份%s共 RM%s。一份%s RM%s。列出一个线性方程，求一份%s的价钱（令吉）。", a, unknown.zh, b, known.zh, ringgit(total), known.zh, ringgit(p), unknown.zh))
+		steps = []string{equation, fmt.Sprintf("%dx = %s", a, ringgit(a*x))}
+	default:
+		text = pick(lang,
+			fmt.Sprintf("%s membeli %d %s dan %d %s. Sebiji %s berharga RM%s. %s membayar dengan wang kertas RM%d dan menerima baki RM%s. Bentukkan satu persamaan linear dan cari harga sebiji %s, dalam RM.", name, a, unknown.ms, b, known.ms, known.ms, ringgit(p), name, note/100, ringgit(note-total), unknown.ms),
+			fmt.Sprintf("%s buys %d %s and %d %s. One %s costs RM%s. %s pays with a RM%d note and gets RM%s change. Form a linear equation and find the price of one %s, in RM.", name, a, unknown.en, b, known.en, known.en, ringgit(p), name, note/100, ringgit(note-total), unknown.en),
+			fmt.Sprintf("%s 买了 %d 份%s和 %d 份%s。一份%s RM%s。%s 用 RM%d 付款，找回 RM%s。列出一个线性方程，求一份%s的价钱（令吉）。", name, a, unknown.zh, b, known.zh, known.zh, ringgit(p), name, note/100, ringgit(note-total), unknown.zh))
+		equation = fmt.Sprintf("%dx + %d(%s) = %s − %s", a, b, ringgit(p), ringgit(note), ringgit(note-total))
+		steps = []string{equation, fmt.Sprintf("%dx + %s = %s", a, ringgit(b*p), ringgit(total)), fmt.Sprintf("%dx = %s", a, ringgit(a*x))}
+	}
+	steps = append(steps, fmt.Sprintf("x = %s", ringgit(x)))
+
+	// Check the worked solution the way a student's would be checked.
+	report := algebra.CheckSteps("", algebraSteps(steps))
+	if report.FirstIncorrect != 0 || len(report.Steps) != len(steps) {
+		return Problem{}, false
+	}
+	for _, step := range report.Steps {
+		if step.Status != algebra.StepOK {
+			return Problem{}, false
+		}
+	}
+
+	return Problem{
+		Text:   text,
+		Answer: ringgit(x),
+		Unit:   "RM",
+		Working: pick(lang,
+			fmt.Sprintf("Katakan x = harga sebiji %s (RM).\n", unknown.ms),
+			fmt.Sprintf("Let x = the price of one %s (RM).\n", unknown.en),
+			fmt.Sprintf("设 x = 一份%s的价钱（令吉）。\n", unknown.zh)) + strings.Join(steps, "\n"),
+		Hints: [2]string{
+			pick(lang,
+				fmt.Sprintf("Katakan x ialah harga sebiji %s, kemudian tulis jumlah harga dalam sebutan x.", unknown.ms),
+				fmt.Sprintf("Let x be the price of one %s, then write the total cost in terms of x.", unknown.en),
+				fmt.Sprintf("设 x 为一份%s的价钱，再用 x 表示总价。", unknown.zh)),
+			pick(lang,
+				fmt.Sprintf("Persamaannya ialah %s.", equation),
+				fmt.Sprintf("The equation is %s.", equation),
+				fmt.Sprintf("方程是 %s。", equation)),
+		},
+	}, true
+}
+
+// algebraSteps rewrites display working for the algebra parser.
+func algebraSteps(steps []string) []string {
+	out := make([]string, len(steps))
+	for i, step := range steps {
+		out[i] = strings.ReplaceAll(step, "−", "-")
+	}
+	return out
+}
+
+type recipe struct {
+	dish, ingredient [3]string // ms, en, zh
+	// grams per servings, for the quantity the recipe is written for.
+	grams, servings int
+}
+
+var recipes = []recipe{
+	{[3]string{"kuih seri muka", "kuih seri muka", "九层糕"}, [3]string{"beras pulut", "glutinous rice", "糯米"}, 300, 4},
+	{[3]string{"nasi lemak", "nasi lemak", "椰浆饭"}, [3]string{"beras", "rice", "白米"}, 500, 5},
+	{[3]string{"rendang daging", "beef rendang", "仁当牛肉"}, [3]string{"daging lembu", "beef", "牛肉"}, 600, 6},
+	{[3]string{"bubur kacang hijau", "green bean porridge", "绿豆糖水"}, [3]string{"kacang hijau", "green beans", "绿豆"}, 250, 8},
+	{[3]string{"cucur udang", "prawn fritters", "虾饼"}, [3]string{"tepung gandum", "wheat flour", "面粉"}, 400, 10},
+}
+
+// drawRecipeScale: scale a recipe ingredient to a new number of servings,
+// in kilograms at the hard level.
+func drawRecipeScale(r *rand.Rand, level int, lang string) (Problem, bool) {
+	rc := recipes[r.IntN(len(recipes))]
+	target := between(r, 2, 40, 1)
+	if target == rc.servings {
+		return Problem{}, false
+	}
+	if level == 0 && target%rc.servings != 0 {
+		return Problem{}, false
+	}
+	if rc.grams*target%rc.servings != 0 {
+		return Problem{}, false
+	}
+	grams := rc.grams * target / rc.servings
+	// Cross-multiply to check the proportion holds.
+	if grams*rc.servings != rc.grams*target {
+		return Problem{}, false
+	}
+	idx := map[string]int{"ms": 0, "en": 1, "zh": 2}[lang]
+	dish, ingredient := rc.dish[idx], rc.ingredient[idx]
+
+	answer, unit := fmt.Sprintf("%d", grams), "g"
+	question := pick(lang,
+		fmt.Sprintf("Berapa gram %s diperlukan untuk %d hidangan?", ingredient, target),
+		fmt.Sprintf("How many grams of %s are needed for %d servings?", ingredient, target),
+		fmt.Sprintf("做 %d 份需要多少克%s？", target, ingredient))
+	working := fmt.Sprintf("%d g ÷ %d × %d = %d g", rc.grams, rc.servings, target, grams)
+	if level == 2 {
+		if grams%10 != 0 {
+			return Problem{}, false
+		}
+		answer, unit = decimal(grams/10), "kg"
+		question = pick(lang,
+			fmt.Sprintf("Berapa kilogram %s diperlukan untuk %d hidangan?", ingredient, target),
+			fmt.Sprintf("How many kilograms of %s are needed for %d servings?", ingredient, target),
+			fmt.Sprintf("做 %d 份需要多少公斤%s？", target, ingredient))
+		working += fmt.Sprintf("\n%d g ÷ 1000 = %s kg", grams, answer)
+	}
+	return Problem{
+		Text: pick(lang,
+			fmt.Sprintf("Satu resipi %s untuk %d hidangan menggunakan %d g %s. %s", dish, rc.servings, rc.grams, ingredient, question),
+			fmt.Sprintf("A recipe for %d servings of %s uses %d g of %s. %s", rc.servings, dish, rc.grams, ingredient, question),
+			fmt.Sprintf("一份 %d 人份的%s食谱需要 %d 克%s。%s", rc.servings, dish, rc.grams, ingredient, question)),
+		Answer:  answer,
+		Unit:    unit,
+		Working: working,
+		Hints: [2]string{
+			pick(lang,
+				fmt.Sprintf("Cari berapa gram untuk satu hidangan dahulu: %d g ÷ %d.", rc.grams, rc.servings),
+				fmt.Sprintf("Find the amount for one serving first: %d g ÷ %d.", rc.grams, rc.servings),
+				fmt.Sprintf("先求一份的分量：%d 克 ÷ %d。", rc.grams, rc.servings)),
+			pick(lang,
+				fmt.Sprintf("Darabkan jumlah untuk satu hidangan dengan %d.", target),
+				fmt.Sprintf("Multiply the amount for one serving by %d.", target),
+				fmt.Sprintf("把一份的分量乘以 %d。", target)),
+		},
+	}, true
+}
+
+// joinList joins phrases as "a, b and c" in lang.
+func joinList(lang string, items []string) string {
+	if len(items) <= 1 {
+		return strings.Join(items, "")
+	}
+	sep := pick(lang, ", ", ", ", "、")
+	and := pick(lang, " dan ", " and ", "和")
+	return strings.Join(items[:len(items)-1], sep) + and + items[len(items)-1]
+}
@@ -0,0 +1,177 @@
+// Copyright 2026 the P&AI authors. All rights reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+// Package wordproblem generates money, rate and unit word problems set in
+// Malaysian daily life. Numbers come from templates, not a language model,
+// and every problem is checked against its own story before it is returned.
+package wordproblem
+
+import (
+	"fmt"
+	"math/rand/v2"
+	"regexp"
+	"strings"
+)
+
+// Problem is one generated word problem with its worked answer.
+type Problem struct {
+	// Template names the story the problem came from, e.g. "shopping_change".
+	Template string
+	Text     string
+	// Answer is the bare number, e.g. "12.50", so it grades numerically.
+	Answer string
+	// Unit is what Answer is measured in, e.g. "RM" or "km/h".
+	Unit    string
+	Working string
+	// Hints are two progressive hints, the second closer to the answer.
+	Hints [2]string
+}
+
+// maxAttempts bounds how many random draws a template gets to produce
+// numbers that pass its check.
+const maxAttempts = 50
+
+type template struct {
+	name string
+	// topics matches curriculum topic names, in English and Malay.
+	topics *regexp.Regexp
+	// draw builds a candidate problem; ok is false when the numbers fail the
+	// story's check and the caller should draw again.
+	draw func(r *rand.Rand, level int, lang string) (p Problem, ok bool)
+}
+
+var templates = []template{
+	{
+		name:   "shopping_change",
+		topics: topicWords("rational", "nombor nisbah", "decimal", "perpuluhan", "money", "wang", "consumer", "pengguna"),
+		draw:   drawShoppingChange,
+	},
+	{
+		name:   "kopitiam_split",
+		topics: topicWords("percent", "percentage", "peratus", "peratusan", "consumer", "pengguna", "tax", "cukai"),
+		draw:   drawKopitiamSplit,
+	},
+	{
+		name:   "city_distance",
+		topics: topicWords("rate", "kadar", "speed", "laju", "distance", "jarak"),
+		draw:   drawCityDistance,
+	},
+	{
+		name:   "linear_price",
+		topics: topicWords("linear equation", "persamaan linear"),
+		draw:   drawLinearPrice,
+	},
+	{
+		name:   "recipe_scale",
+		topics: topicWords("ratio", "proportion", "kadaran", "measurement", "sukatan", "unit"),
+		draw:   drawRecipeScale,
+	},
+}
+
+// Templates returns the names of the templates that suit topicName, the
+// curriculum topic's display name in English or Malay.
+func Templates(topicName string) []string {
+	var names []string
+	for _, t := range matching(topicName) {
+		names = append(names, t.name)
+	}
+	return names
+}
+
+// Generate returns n problems for topicName at difficulty ("easy",
+// "medium" or "hard"; anything else is medium) in lang ("ms", "en" or
+// "zh"; anything else is Malay). It returns fewer than n, possibly none,
+// when no template suits the topic.
+func Generate(r *rand.Rand, topicName, difficulty, lang string, n int) []Problem {
+	candidates := matching(topicName)
+	if len(candidates) == 0 {
+		return nil
+	}
+	level := levelOf(difficulty)
+	lang = normalizeLang(lang)
+	var problems []Problem
+	offset := r.IntN(len(candidates))
+	for i := 0; len(problems) < n && i < n*len(candidates); i++ {
+		t := candidates[(offset+i)%len(candidates)]
+		for attempt := 0; attempt < maxAttempts; attempt++ {
+			if p, ok := t.draw(r, level, lang); ok {
+				p.Template = t.name
+				problems = append(problems, p)
+				break
+			}
+		}
+	}
+	return problems
+}
+
+func matching(topicName string) []template {
+	var out []template
+	for _, t := range templates {
+		if t.topics.MatchString(topicName) {
+			out = append(out, t)
+		}
+	}
+	return out
+}
+
+// topicWords matches any of words as whole words, allowing an English
+// plural, so "ratio" matches "Ratios" but not "Rational".
+func topicWords(words ...string) *regexp.Regexp {
+	quoted := make([]string, len(words))
+	for i, w := range words {
+		quoted[i] = regexp.QuoteMeta(w)
+	}
+	return regexp.MustCompile(`(?i)\b(?:` + strings.Join(quoted, "|") + `)(?:s|es)?\b`)
+}
+
+// levelOf maps a quiz difficulty to 0 (easy), 1 (medium) or 2 (hard).
+func levelOf(difficulty string) int {
+	switch strings.ToLower(strings.TrimSpace(difficulty)) {
+	case "easy":
+		return 0
+	case "hard":
+		return 2
+	default:
+		return 1
+	}
+}
+
+func normalizeLang(lang string) string {
+	switch lang {
+	case "en", "zh":
+		return lang
+	default:
+		return "ms"
+	}
+}
+
+// pick returns the entry for lang from a ms/en/zh triple.
+func pick(lang string, ms, en, zh string) string {
+	switch lang {
+	case "en":
+		return en
+	case "zh":
+		return zh
+	default:
+		return ms
+	}
+}
+
+// ringgit formats an amount in sen as ringgit with two decimals.
+func ringgit(sen int) string {
+	return fmt.Sprintf("%d.%02d", sen/100, sen%100)
+}
+
+// decimal formats a count of hundredths without trailing zeros.
+func decimal(hundredths int) string {
+	s := ringgit(hundredths)
+	s = strings.TrimRight(s, "0")
+	return strings.TrimSuffix(s, ".")
+}
+
+// between returns a random multiple of step in [lo, hi].
+func between(r *rand.Rand, lo, hi, step int) int {
+	return lo + r.IntN((hi-lo)/step+1)*step
+}
+
+var names = []string{"Aina", "Hafiz", "Mei Ling", "Arjun", "Siti", "Wei Jie", "Kavitha", "Danial"}
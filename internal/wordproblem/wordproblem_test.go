@@ -0,0 +1,105 @@
+// Copyright 2026 the P&AI authors. All rights reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package wordproblem
+
+import (
+	"math/rand/v2"
+	"reflect"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+func TestTemplatesMatchTopicNames(t *testing.T) {
+	tests := []struct {
+		topic string
+		want  []string
+	}{
+		{"Linear Equations", []string{"linear_price"}},
+		{"Persamaan Linear", []string{"linear_price"}},
+		{"Ratios, Rates and Proportions", []string{"city_distance", "recipe_scale"}},
+		{"Nombor Nisbah", []string{"shopping_change"}},
+		{"Rational Numbers", []string{"shopping_change"}},
+		{"Nisbah, Kadar dan Kadaran", []string{"city_distance", "recipe_scale"}},
+		{"Lines and Angles", nil},
+	}
+	for _, tt := range tests {
+		if got := Templates(tt.topic); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("Templates(%q) = %v, want %v", tt.topic, got, tt.want)
+		}
+	}
+}
+
+func TestGenerateReturnsNothingForUnmatchedTopic(t *testing.T) {
+	r := rand.New(rand.NewPCG(1, 2))
+	if got := Generate(r, "Lines and Angles", "easy", "en", 3); len(got) != 0 {
+		t.Fatalf("Generate() = %+v, want none", got)
+	}
+}
+
+func TestGenerateProducesConsistentProblems(t *testing.T) {
+	r := rand.New(rand.NewPCG(7, 11))
+	for _, tmpl := range templates {
+		for _, difficulty := range []string{"easy", "medium", "hard"} {
+			for _, lang := range []string{"ms", "en", "zh"} {
+				for i := 0; i < 20; i++ {
+					var p Problem
+					var ok bool
+					for attempt := 0; attempt < maxAttempts && !ok; attempt++ {
+						p, ok = tmpl.draw(r, levelOf(difficulty), lang)
+					}
+					if !ok {
+						t.Fatalf("%s/%s/%s: no problem after %d attempts", tmpl.name, difficulty, lang, maxAttempts)
+					}
+					if _, err := strconv.ParseFloat(p.Answer, 64); err != nil {
+						t.Fatalf("%s: answer %q is not a number", tmpl.name, p.Answer)
+					}
+					lines := strings.Split(p.Working, "\n")
+					if last := lines[len(lines)-1]; !strings.Contains(last, p.Answer) {
+						t.Fatalf("%s: working %q does not end at answer %q", tmpl.name, p.Working, p.Answer)
+					}
+					if p.Text == "" || p.Hints[0] == "" || p.Hints[1] == "" {
+						t.Fatalf("%s: incomplete problem %+v", tmpl.name, p)
+					}
+				}
+			}
+		}
+	}
+}
+
+func TestGenerateShoppingChangeAddsUp(t *testing.T) {
+	r := rand.New(rand.NewPCG(3, 5))
+	for _, p := range Generate(r, "Rational Numbers", "hard", "en", 10) {
+		if p.Template != "shopping_change" {
+			continue
+		}
+		// "Change = RM20.00 − RM17.10 = RM2.90"
+		fields := strings.Fields(strings.Split(p.Working, "\n")[1])
+		paid, _ := strconv.ParseFloat(strings.TrimPrefix(fields[2], "RM"), 64)
+		total, _ := strconv.ParseFloat(strings.TrimPrefix(fields[4], "RM"), 64)
+		change, _ := strconv.ParseFloat(p.Answer, 64)
+		if diff := paid - total - change; diff > 0.001 || diff < -0.001 || change <= 0 {
+			t.Fatalf("change %v does not make up RM%v from RM%v", change, total, paid)
+		}
+	}
+}
+
+func TestClockFormatsByLanguage(t *testing.T) {
+	tests := []struct {
+		minutes int
+		lang    string
+		want    string
+	}{
+		{9*60 + 15, "en", "9:15 am"},
+		{13*60 + 45, "en", "1:45 pm"},
+		{13*60 + 45, "ms", "1.45 tengah hari"},
+		{15 * 60, "ms", "3.00 petang"},
+		{8*60 + 5, "zh", "上午8:05"},
+	}
+	for _, tt := range tests {
+		if got := clock(tt.minutes, tt.lang); got != tt.want {
+			t.Errorf("clock(%d, %s) = %q, want %q", tt.minutes, tt.lang, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,10 @@
+-- +goose Up
+-- Link events to the conversation that fired them so admin transcripts can
+-- interleave turn events with messages. Older rows stay NULL.
+ALTER TABLE events ADD COLUMN conversation_id UUID REFERENCES conversations(id);
+
+CREATE INDEX idx_events_conversation_id ON events(conversation_id, created_at) WHERE conversation_id IS NOT NULL;
+
+-- +goose Down
+DROP INDEX IF EXISTS idx_events_conversation_id;
+ALTER TABLE events DROP COLUMN IF EXISTS conversation_id;
@@ -0,0 +1,13 @@
+-- +goose Up
+-- Rich message content: a kind for what the message carries and attachment
+-- references (type, reference, caption) so images, documents, and quiz
+-- payloads survive in history and exports. Existing rows are plain text.
+ALTER TABLE messages
+    ADD COLUMN content_kind TEXT NOT NULL DEFAULT 'text'
+        CHECK (content_kind IN ('text', 'image', 'document', 'quiz')),
+    ADD COLUMN attachments JSONB;
+
+-- +goose Down
+ALTER TABLE messages
+    DROP COLUMN IF EXISTS attachments,
+    DROP COLUMN IF EXISTS content_kind;
@@ -0,0 +1,17 @@
+-- +goose Up
+-- Channel edits and deletes. external_id is the channel-native message ID so
+-- an edit or delete update can find its row; original_content keeps the first
+-- version of an edited message; deleted_at soft-deletes for moderation and
+-- user deletes without shifting compaction offsets.
+ALTER TABLE messages
+    ADD COLUMN external_id TEXT,
+    ADD COLUMN original_content TEXT,
+    ADD COLUMN edited_at TIMESTAMPTZ,
+    ADD COLUMN deleted_at TIMESTAMPTZ;
+
+-- +goose Down
+ALTER TABLE messages
+    DROP COLUMN IF EXISTS deleted_at,
+    DROP COLUMN IF EXISTS edited_at,
+    DROP COLUMN IF EXISTS original_content,
+    DROP COLUMN IF EXISTS external_id;
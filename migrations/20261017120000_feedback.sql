@@ -0,0 +1,18 @@
+-- +goose Up
+-- Learner /feedback reports. context keeps the recent visible turns so a
+-- reviewer can see what the bot said without the conversation still existing.
+CREATE TABLE feedback (
+    id              UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    tenant_id       UUID NOT NULL REFERENCES tenants(id),
+    user_id         UUID REFERENCES users(id),
+    conversation_id UUID REFERENCES conversations(id) ON DELETE SET NULL,
+    message_id      UUID REFERENCES messages(id) ON DELETE SET NULL,
+    text            TEXT NOT NULL,
+    context         JSONB NOT NULL DEFAULT '[]',
+    created_at      TIMESTAMPTZ DEFAULT NOW()
+);
+
+CREATE INDEX idx_feedback_tenant_created ON feedback(tenant_id, created_at DESC);
+
+-- +goose Down
+DROP TABLE IF EXISTS feedback;
@@ -0,0 +1,17 @@
+-- +goose Up
+-- Week-by-week /plan study plans, one active plan per learner. weeks holds
+-- the generated schedule including the per-week nudge pace.
+CREATE TABLE study_plans (
+    id         UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    user_id    UUID NOT NULL UNIQUE REFERENCES users(id),
+    tenant_id  UUID NOT NULL REFERENCES tenants(id),
+    exam_date  DATE NOT NULL,
+    form       TEXT NOT NULL DEFAULT '',
+    weeks      JSONB NOT NULL DEFAULT '[]',
+    created_at TIMESTAMPTZ DEFAULT NOW()
+);
+
+CREATE INDEX idx_study_plans_tenant ON study_plans(tenant_id);
+
+-- +goose Down
+DROP TABLE IF EXISTS study_plans;
@@ -0,0 +1,22 @@
+-- +goose Up
+-- Cold storage for ended conversations. The archive worker moves a
+-- conversation's messages and summary into one gzip-compressed JSON payload
+-- and deletes the hot message rows; the conversations row stays so events,
+-- feedback, and focused pages keep their references.
+ALTER TABLE conversations ADD COLUMN archived_at TIMESTAMPTZ;
+
+CREATE TABLE conversation_archives (
+    conversation_id UUID PRIMARY KEY REFERENCES conversations(id),
+    tenant_id       UUID NOT NULL REFERENCES tenants(id),
+    message_count   INTEGER NOT NULL,
+    payload         BYTEA NOT NULL,
+    archived_at     TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX idx_conversation_archives_tenant ON conversation_archives(tenant_id);
+CREATE INDEX idx_conversations_archivable ON conversations(ended_at) WHERE archived_at IS NULL AND ended_at IS NOT NULL;
+
+-- +goose Down
+DROP INDEX IF EXISTS idx_conversations_archivable;
+DROP TABLE IF EXISTS conversation_archives;
+ALTER TABLE conversations DROP COLUMN IF EXISTS archived_at;
@@ -0,0 +1,13 @@
+-- +goose Up
+-- Full-text search over message history for /search and the admin search
+-- API. The 'simple' configuration skips stemming because learners mix Bahasa
+-- Melayu, English, and Chinese in one conversation; no single dictionary fits.
+ALTER TABLE messages
+    ADD COLUMN search_vector tsvector
+        GENERATED ALWAYS AS (to_tsvector('simple', content)) STORED;
+
+CREATE INDEX idx_messages_search_vector ON messages USING GIN (search_vector);
+
+-- +goose Down
+DROP INDEX IF EXISTS idx_messages_search_vector;
+ALTER TABLE messages DROP COLUMN IF EXISTS search_vector;
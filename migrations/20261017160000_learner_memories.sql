@@ -0,0 +1,23 @@
+-- +goose Up
+-- Long-term learner memory: short highlights (goals, persistent struggles,
+-- preferences) extracted when a conversation is compacted. Embeddings are
+-- plain REAL[] so no vector extension is needed; each learner keeps a small
+-- capped set that is ranked in the application.
+CREATE TABLE learner_memories (
+    id              UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    user_id         UUID NOT NULL REFERENCES users(id),
+    tenant_id       UUID NOT NULL REFERENCES tenants(id),
+    conversation_id UUID REFERENCES conversations(id) ON DELETE SET NULL,
+    kind            TEXT NOT NULL CHECK (kind IN ('goal', 'struggle', 'preference')),
+    source          TEXT NOT NULL CHECK (source IN ('learner_stated', 'tutor_observed')),
+    content         TEXT NOT NULL,
+    embedding_model TEXT NOT NULL DEFAULT '',
+    embedding       REAL[] NOT NULL DEFAULT '{}',
+    created_at      TIMESTAMPTZ DEFAULT NOW()
+);
+
+CREATE INDEX idx_learner_memories_user ON learner_memories(user_id, created_at DESC);
+CREATE INDEX idx_learner_memories_tenant ON learner_memories(tenant_id);
+
+-- +goose Down
+DROP TABLE IF EXISTS learner_memories;
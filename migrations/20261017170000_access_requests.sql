@@ -0,0 +1,22 @@
+-- +goose Up
+-- Access gate for closed pilots: one row per learner who asked to use a gated
+-- channel. Rows exist before the learner has a users record, so they are keyed
+-- by channel and external ID rather than users.id.
+CREATE TABLE access_requests (
+    id           UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    tenant_id    UUID NOT NULL REFERENCES tenants(id),
+    channel      TEXT NOT NULL,
+    external_id  TEXT NOT NULL,
+    display_name TEXT NOT NULL DEFAULT '',
+    status       TEXT NOT NULL DEFAULT 'pending' CHECK (status IN ('pending', 'approved', 'revoked')),
+    granted_via  TEXT NOT NULL DEFAULT '' CHECK (granted_via IN ('', 'operator', 'invite_code')),
+    decided_by   TEXT NOT NULL DEFAULT '',
+    requested_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    decided_at   TIMESTAMPTZ,
+    UNIQUE (tenant_id, channel, external_id)
+);
+
+CREATE INDEX idx_access_requests_pending ON access_requests(tenant_id, requested_at) WHERE status = 'pending';
+
+-- +goose Down
+DROP TABLE IF EXISTS access_requests;
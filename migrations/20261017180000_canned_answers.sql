@@ -0,0 +1,19 @@
+-- +goose Up
+-- Operator-written replies for common admin questions ("is this free", "how do
+-- I reset"). A learner message containing one of the phrases gets the stored
+-- answer in their language instead of a model turn.
+CREATE TABLE canned_answers (
+    id         UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    tenant_id  UUID NOT NULL REFERENCES tenants(id),
+    key        TEXT NOT NULL,
+    phrases    TEXT[] NOT NULL DEFAULT '{}',
+    -- Locale code to answer text, e.g. {"ms": "...", "en": "..."}.
+    answers    JSONB NOT NULL DEFAULT '{}',
+    updated_by TEXT NOT NULL DEFAULT '',
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    UNIQUE (tenant_id, key)
+);
+
+-- +goose Down
+DROP TABLE IF EXISTS canned_answers;
@@ -0,0 +1,8 @@
+-- +goose Up
+-- Per-turn safety trace on the assistant message: every safety check that ran
+-- for the turn and what it did (content filter hits, regenerations, prompt
+-- injection flags), so a flagged session can be audited message by message.
+ALTER TABLE messages ADD COLUMN safety JSONB;
+
+-- +goose Down
+ALTER TABLE messages DROP COLUMN IF EXISTS safety;
@@ -0,0 +1,19 @@
+-- +goose Up
+-- School-specific teaching notes and worked examples that teachers layer on
+-- top of the OSS curriculum. Every save is a new version; the highest version
+-- per topic is the one the tutor sees.
+CREATE TABLE teaching_note_versions (
+    id         UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    tenant_id  UUID NOT NULL REFERENCES tenants(id),
+    topic_id   TEXT NOT NULL,
+    version    INT NOT NULL,
+    notes      TEXT NOT NULL DEFAULT '',
+    -- Worked examples: [{"problem": "...", "steps": ["..."], "answer": "..."}].
+    examples   JSONB NOT NULL DEFAULT '[]',
+    created_by UUID REFERENCES users(id),
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    UNIQUE (tenant_id, topic_id, version)
+);
+
+-- +goose Down
+DROP TABLE IF EXISTS teaching_note_versions;
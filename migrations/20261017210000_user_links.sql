@@ -0,0 +1,25 @@
+-- +goose Up
+-- Chat identities a learner has linked with /link. Every member of a group,
+-- the canonical user included, has a row pointing at the canonical user, so
+-- the active conversation resolves the same from any linked channel.
+CREATE TABLE user_links (
+    user_id           UUID PRIMARY KEY REFERENCES users(id) ON DELETE CASCADE,
+    canonical_user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    tenant_id         UUID NOT NULL REFERENCES tenants(id),
+    linked_at         TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX idx_user_links_canonical ON user_links (canonical_user_id);
+
+-- Short-lived single-use codes issued by /link and redeemed from the other chat.
+CREATE TABLE user_link_codes (
+    code       TEXT PRIMARY KEY,
+    tenant_id  UUID NOT NULL REFERENCES tenants(id),
+    user_id    UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    expires_at TIMESTAMPTZ NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+-- +goose Down
+DROP TABLE IF EXISTS user_link_codes;
+DROP TABLE IF EXISTS user_links;
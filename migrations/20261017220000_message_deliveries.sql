@@ -0,0 +1,18 @@
+-- +goose Up
+-- One row per outbound send with its outcome, so admins can see failed and
+-- blocked deliveries and the scheduler can stop nudging blocked users.
+CREATE TABLE message_deliveries (
+    id          BIGSERIAL PRIMARY KEY,
+    tenant_id   UUID NOT NULL REFERENCES tenants(id),
+    channel     TEXT NOT NULL,
+    external_id TEXT NOT NULL,
+    status      TEXT NOT NULL CHECK (status IN ('sent', 'failed', 'rate_limited', 'blocked')),
+    error       TEXT,
+    created_at  TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX idx_message_deliveries_user ON message_deliveries (tenant_id, channel, external_id, created_at DESC);
+CREATE INDEX idx_message_deliveries_status ON message_deliveries (tenant_id, status, created_at DESC);
+
+-- +goose Down
+DROP TABLE IF EXISTS message_deliveries;
@@ -0,0 +1,16 @@
+-- +goose Up
+-- Lifecycle state derived from outbound delivery: 'blocked' when the learner
+-- blocked the bot, 'inactive' when their chat account was deactivated. The
+-- scheduler only nudges 'active' learners; a later successful send resets it.
+ALTER TABLE users
+    ADD COLUMN lifecycle_state TEXT NOT NULL DEFAULT 'active'
+        CHECK (lifecycle_state IN ('active', 'inactive', 'blocked')),
+    ADD COLUMN lifecycle_changed_at TIMESTAMPTZ;
+
+CREATE INDEX idx_users_lifecycle_state ON users (tenant_id, lifecycle_state) WHERE lifecycle_state <> 'active';
+
+-- +goose Down
+DROP INDEX IF EXISTS idx_users_lifecycle_state;
+ALTER TABLE users
+    DROP COLUMN IF EXISTS lifecycle_changed_at,
+    DROP COLUMN IF EXISTS lifecycle_state;
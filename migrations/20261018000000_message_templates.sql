@@ -0,0 +1,16 @@
+-- +goose Up
+-- Tenant copy for recurring outbound messages (welcome, review reminders).
+-- Bodies use {{variable}} placeholders; a message with no row for the
+-- learner's locale falls back to the built-in copy.
+CREATE TABLE message_templates (
+    tenant_id   UUID NOT NULL REFERENCES tenants(id),
+    key         TEXT NOT NULL,
+    locale      TEXT NOT NULL CHECK (locale IN ('en', 'ms', 'zh')),
+    body        TEXT NOT NULL,
+    updated_by  UUID REFERENCES users(id),
+    updated_at  TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (tenant_id, key, locale)
+);
+
+-- +goose Down
+DROP TABLE IF EXISTS message_templates;
@@ -0,0 +1,20 @@
+-- +goose Up
+-- Weekly quiz leaderboards are opt-in per class. Learners control how they
+-- appear on any leaderboard: a nickname instead of their name, or not at all.
+ALTER TABLE groups ADD COLUMN quiz_leaderboard BOOLEAN NOT NULL DEFAULT false;
+
+CREATE TABLE leaderboard_preferences (
+    user_id     UUID PRIMARY KEY REFERENCES users(id) ON DELETE CASCADE,
+    tenant_id   UUID NOT NULL REFERENCES tenants(id),
+    nickname    TEXT NOT NULL DEFAULT '',
+    opted_out   BOOLEAN NOT NULL DEFAULT false,
+    updated_at  TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX idx_events_quiz_completed_user ON events(user_id, created_at)
+    WHERE event_type = 'quiz_completed';
+
+-- +goose Down
+DROP INDEX IF EXISTS idx_events_quiz_completed_user;
+DROP TABLE IF EXISTS leaderboard_preferences;
+ALTER TABLE groups DROP COLUMN IF EXISTS quiz_leaderboard;
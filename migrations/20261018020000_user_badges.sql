@@ -0,0 +1,32 @@
+-- +goose Up
+-- Achievement badges a learner has earned, and the running counts that
+-- count-based badges (such as 100 problems solved) are evaluated against.
+CREATE TABLE user_badges (
+    user_id     UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    tenant_id   UUID NOT NULL REFERENCES tenants(id),
+    badge_id    TEXT NOT NULL,
+    earned_at   TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (user_id, badge_id)
+);
+
+CREATE TABLE user_badge_counters (
+    user_id     UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    tenant_id   UUID NOT NULL REFERENCES tenants(id),
+    counter     TEXT NOT NULL,
+    value       INT NOT NULL DEFAULT 0,
+    updated_at  TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (user_id, counter)
+);
+
+-- Learners who already finished a quiz hold the first-quiz badge from the
+-- time of their first one.
+INSERT INTO user_badges (user_id, tenant_id, badge_id, earned_at)
+SELECT user_id, tenant_id, 'first_quiz', MIN(created_at)
+FROM events
+WHERE event_type = 'quiz_completed' AND user_id IS NOT NULL
+GROUP BY user_id, tenant_id
+ON CONFLICT (user_id, badge_id) DO NOTHING;
+
+-- +goose Down
+DROP TABLE IF EXISTS user_badge_counters;
+DROP TABLE IF EXISTS user_badges;
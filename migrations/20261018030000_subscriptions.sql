@@ -0,0 +1,34 @@
+-- +goose Up
+-- Paid plans, written by the billing webhook. Rows are keyed by external ID
+-- because checkout can finish before the learner has a users record; an
+-- empty external_id is a tenant-wide plan that covers every learner.
+CREATE TABLE subscriptions (
+    id              UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    tenant_id       UUID NOT NULL REFERENCES tenants(id),
+    external_id     TEXT NOT NULL DEFAULT '',
+    tier            TEXT NOT NULL CHECK (tier IN ('free', 'premium')),
+    status          TEXT NOT NULL,
+    provider        TEXT NOT NULL DEFAULT '',
+    customer_id     TEXT NOT NULL DEFAULT '',
+    subscription_id TEXT NOT NULL DEFAULT '',
+    period_end      TIMESTAMPTZ,
+    created_at      TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at      TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    UNIQUE (tenant_id, external_id)
+);
+
+CREATE INDEX idx_subscriptions_provider_id ON subscriptions(tenant_id, provider, subscription_id) WHERE subscription_id <> '';
+
+-- Tokens each learner's teaching turns used per MYT day, checked against the
+-- daily budget of their tier.
+CREATE TABLE daily_token_usage (
+    tenant_id   UUID NOT NULL REFERENCES tenants(id),
+    external_id TEXT NOT NULL,
+    day         DATE NOT NULL,
+    tokens      BIGINT NOT NULL DEFAULT 0 CHECK (tokens >= 0),
+    PRIMARY KEY (tenant_id, external_id, day)
+);
+
+-- +goose Down
+DROP TABLE IF EXISTS daily_token_usage;
+DROP TABLE IF EXISTS subscriptions;
@@ -0,0 +1,34 @@
+-- +goose Up
+-- Each learner's invite code, carried in t.me/<bot>?start=ref_<code> links.
+CREATE TABLE referral_codes (
+    user_id     UUID PRIMARY KEY REFERENCES users(id) ON DELETE CASCADE,
+    tenant_id   UUID NOT NULL REFERENCES tenants(id),
+    code        TEXT NOT NULL,
+    created_at  TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    UNIQUE (tenant_id, code)
+);
+
+-- Who invited whom. A learner has at most one referrer; activated_at is set
+-- when the invited learner finishes onboarding and both earn XP.
+CREATE TABLE referrals (
+    referred_user_id UUID PRIMARY KEY REFERENCES users(id) ON DELETE CASCADE,
+    referrer_user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    tenant_id        UUID NOT NULL REFERENCES tenants(id),
+    created_at       TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    activated_at     TIMESTAMPTZ
+);
+
+CREATE INDEX idx_referrals_referrer_user_id ON referrals(referrer_user_id);
+
+ALTER TABLE xp_ledger DROP CONSTRAINT IF EXISTS xp_ledger_source_check;
+ALTER TABLE xp_ledger ADD CONSTRAINT xp_ledger_source_check
+    CHECK (source IN ('session', 'quiz', 'mastery', 'streak', 'challenge', 'review', 'referral'));
+
+-- +goose Down
+DELETE FROM xp_ledger WHERE source = 'referral';
+ALTER TABLE xp_ledger DROP CONSTRAINT IF EXISTS xp_ledger_source_check;
+ALTER TABLE xp_ledger ADD CONSTRAINT xp_ledger_source_check
+    CHECK (source IN ('session', 'quiz', 'mastery', 'streak', 'challenge', 'review'));
+
+DROP TABLE IF EXISTS referrals;
+DROP TABLE IF EXISTS referral_codes;
@@ -0,0 +1,38 @@
+-- +goose Up
+-- Problem of the day, one per form per MYT day. Operators curate problems
+-- ahead of time; days with none get one generated from the curriculum.
+CREATE TABLE daily_problems (
+    id          UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    tenant_id   UUID NOT NULL REFERENCES tenants(id),
+    form        TEXT NOT NULL,
+    publish_on  DATE NOT NULL,
+    topic_id    TEXT NOT NULL,
+    question    JSONB NOT NULL,
+    source      TEXT NOT NULL CHECK (source IN ('curated', 'generated')),
+    created_at  TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at  TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    UNIQUE (tenant_id, form, publish_on)
+);
+
+-- Learners who opted in with /daily on, and the channel to push on.
+CREATE TABLE daily_problem_subscriptions (
+    user_id     UUID PRIMARY KEY REFERENCES users(id) ON DELETE CASCADE,
+    tenant_id   UUID NOT NULL REFERENCES tenants(id),
+    channel     TEXT NOT NULL,
+    created_at  TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+-- Who was sent each problem and their first answer.
+CREATE TABLE daily_problem_attempts (
+    problem_id  UUID NOT NULL REFERENCES daily_problems(id) ON DELETE CASCADE,
+    user_id     UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    sent_at     TIMESTAMPTZ,
+    answered_at TIMESTAMPTZ,
+    correct     BOOLEAN,
+    PRIMARY KEY (problem_id, user_id)
+);
+
+-- +goose Down
+DROP TABLE IF EXISTS daily_problem_attempts;
+DROP TABLE IF EXISTS daily_problem_subscriptions;
+DROP TABLE IF EXISTS daily_problems;
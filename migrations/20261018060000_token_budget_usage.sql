@@ -0,0 +1,16 @@
+-- +goose Up
+-- P&AI Bot - Daily token usage per budget window, flushed from the in-process
+-- budget cache for billing and reporting.
+
+CREATE TABLE token_budget_usage (
+    budget_id   UUID NOT NULL REFERENCES token_budgets(id) ON DELETE CASCADE,
+    usage_date  DATE NOT NULL,
+    tokens      BIGINT NOT NULL DEFAULT 0 CHECK (tokens >= 0),
+    updated_at  TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (budget_id, usage_date)
+);
+
+CREATE INDEX idx_token_budget_usage_date ON token_budget_usage(usage_date);
+
+-- +goose Down
+DROP TABLE IF EXISTS token_budget_usage;
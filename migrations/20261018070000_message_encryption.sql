@@ -0,0 +1,12 @@
+-- +goose Up
+-- P&AI Bot - Optional at-rest encryption of message content. A non-null key
+-- ID marks content, original_content and attachments as AES-GCM sealed with
+-- that key (attachments then hold a sealed JSON string); NULL rows are
+-- plaintext. Archive payloads carry their own key ID the same way.
+
+ALTER TABLE messages ADD COLUMN content_key_id TEXT;
+ALTER TABLE conversation_archives ADD COLUMN payload_key_id TEXT;
+
+-- +goose Down
+ALTER TABLE conversation_archives DROP COLUMN IF EXISTS payload_key_id;
+ALTER TABLE messages DROP COLUMN IF EXISTS content_key_id;
@@ -0,0 +1,10 @@
+-- +goose Up
+-- Staff "test as student" learners. Their conversations work like any other,
+-- but analytics and token budgets skip them.
+
+ALTER TABLE users ADD COLUMN sandbox BOOLEAN NOT NULL DEFAULT false;
+CREATE INDEX idx_users_sandbox ON users(id) WHERE sandbox;
+
+-- +goose Down
+DROP INDEX IF EXISTS idx_users_sandbox;
+ALTER TABLE users DROP COLUMN IF EXISTS sandbox;
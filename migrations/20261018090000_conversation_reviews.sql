@@ -0,0 +1,22 @@
+-- +goose Up
+-- Conversations queued for human quality review: a random sample of
+-- sessions plus every conversation with a safety-flagged turn. Reviewers
+-- rate and comment on them; rated transcripts feed the eval fixtures.
+CREATE TABLE conversation_reviews (
+    id              UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    tenant_id       UUID NOT NULL REFERENCES tenants(id),
+    conversation_id UUID NOT NULL UNIQUE REFERENCES conversations(id) ON DELETE CASCADE,
+    reason          TEXT NOT NULL CHECK (reason IN ('sampled', 'safety')),
+    rating          SMALLINT CHECK (rating BETWEEN 1 AND 5),
+    comment         TEXT NOT NULL DEFAULT '',
+    reviewed_by     UUID REFERENCES users(id) ON DELETE SET NULL,
+    reviewed_at     TIMESTAMPTZ,
+    created_at      TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX idx_conversation_reviews_pending
+    ON conversation_reviews (tenant_id, created_at)
+    WHERE reviewed_at IS NULL;
+
+-- +goose Down
+DROP TABLE IF EXISTS conversation_reviews;
@@ -0,0 +1,19 @@
+-- +goose Up
+-- Outbound messages queued by Gateway.SendAt until they are due: morning
+-- review nudges deferred past quiet hours and next-day check-ins. A learner
+-- who messages first has their queued messages dropped.
+CREATE TABLE scheduled_messages (
+    id          UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    tenant_id   UUID NOT NULL REFERENCES tenants(id),
+    channel     TEXT NOT NULL,
+    external_id TEXT NOT NULL,
+    message     JSONB NOT NULL,
+    send_at     TIMESTAMPTZ NOT NULL,
+    created_at  TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX idx_scheduled_messages_due ON scheduled_messages (tenant_id, send_at);
+CREATE INDEX idx_scheduled_messages_user ON scheduled_messages (tenant_id, channel, external_id);
+
+-- +goose Down
+DROP TABLE IF EXISTS scheduled_messages;
@@ -0,0 +1,25 @@
+-- +goose Up
+-- One row per completed quiz with every question's answers and score, so
+-- reports and mastery updates read a fixed schema instead of event JSON.
+CREATE TABLE quiz_results (
+    id               UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    tenant_id        UUID NOT NULL REFERENCES tenants(id),
+    user_id          UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    conversation_id  UUID REFERENCES conversations(id) ON DELETE SET NULL,
+    topic_id         TEXT NOT NULL,
+    intensity        TEXT NOT NULL DEFAULT '',
+    difficulty       TEXT NOT NULL DEFAULT '',
+    daily_problem_id TEXT NOT NULL DEFAULT '',
+    correct_answers  INTEGER NOT NULL,
+    total_questions  INTEGER NOT NULL,
+    score            DOUBLE PRECISION NOT NULL,
+    objective_ids    TEXT[] NOT NULL DEFAULT '{}',
+    questions        JSONB NOT NULL,
+    completed_at     TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX idx_quiz_results_user ON quiz_results (user_id, completed_at DESC);
+CREATE INDEX idx_quiz_results_topic ON quiz_results (tenant_id, topic_id, completed_at DESC);
+
+-- +goose Down
+DROP TABLE IF EXISTS quiz_results;
@@ -0,0 +1,20 @@
+-- +goose Up
+-- Read-only tokens a school hands to its own dashboards to pull the tenant's
+-- usage statistics. Only a hash of the token is kept; the prefix lets admins
+-- tell tokens apart.
+CREATE TABLE api_tokens (
+    id           UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    tenant_id    UUID NOT NULL REFERENCES tenants(id),
+    name         TEXT NOT NULL,
+    token_hash   TEXT NOT NULL UNIQUE,
+    token_prefix TEXT NOT NULL,
+    created_by   UUID REFERENCES users(id) ON DELETE SET NULL,
+    created_at   TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    last_used_at TIMESTAMPTZ,
+    revoked_at   TIMESTAMPTZ
+);
+
+CREATE INDEX idx_api_tokens_tenant ON api_tokens (tenant_id, created_at DESC);
+
+-- +goose Down
+DROP TABLE IF EXISTS api_tokens;
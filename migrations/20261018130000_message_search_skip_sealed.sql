@@ -0,0 +1,24 @@
+-- +goose Up
+-- Stop indexing sealed messages. Their content column holds AES-GCM
+-- ciphertext, so the tsvector only filled the GIN index with noise; sealed
+-- stores search by decrypting recent rows instead.
+DROP INDEX IF EXISTS idx_messages_search_vector;
+ALTER TABLE messages DROP COLUMN IF EXISTS search_vector;
+
+ALTER TABLE messages
+    ADD COLUMN search_vector tsvector
+        GENERATED ALWAYS AS (
+            CASE WHEN content_key_id IS NULL THEN to_tsvector('simple', content) END
+        ) STORED;
+
+CREATE INDEX idx_messages_search_vector ON messages USING GIN (search_vector);
+
+-- +goose Down
+DROP INDEX IF EXISTS idx_messages_search_vector;
+ALTER TABLE messages DROP COLUMN IF EXISTS search_vector;
+
+ALTER TABLE messages
+    ADD COLUMN search_vector tsvector
+        GENERATED ALWAYS AS (to_tsvector('simple', content)) STORED;
+
+CREATE INDEX idx_messages_search_vector ON messages USING GIN (search_vector);